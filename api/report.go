@@ -0,0 +1,161 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+// Report summarizes a replay.
+type Report struct {
+	Blocks       int           // number of completed blocks
+	Transactions int           // number of completed transactions, pseudo transactions are not counted
+	Failures     []Failure     // failures tolerated by --continue-on-failure and the failure stopping the replay
+	Elapsed      time.Duration // duration of the replay
+}
+
+// Failure is an error of a transaction of the replay.
+type Failure struct {
+	Block       int
+	Transaction int
+	Err         error
+}
+
+func (f Failure) Error() string {
+	return fmt.Sprintf("block %d transaction %d: %v", f.Block, f.Transaction, f.Err)
+}
+
+func (f Failure) Unwrap() error {
+	return f.Err
+}
+
+func makeReportCollector[T any]() *reportCollector[T] {
+	return &reportCollector[T]{}
+}
+
+// reportCollector records the progress and the failures of a replay. Errors tolerated by
+// --continue-on-failure are intercepted on their way to the error logger and attributed to
+// the transaction being executed, which is unambiguous as the replay uses a single worker.
+type reportCollector[T any] struct {
+	extension.NilExtension[T]
+
+	lock       sync.Mutex
+	start      time.Time
+	rep        Report
+	block, tx  int
+	started    bool       // whether any transaction was started
+	errorInput chan error // error input of the error logger
+	wg         sync.WaitGroup
+}
+
+// PreRun intercepts the error input created by the error logger.
+func (c *reportCollector[T]) PreRun(_ executor.State[T], ctx *executor.Context) error {
+	c.start = time.Now()
+	if ctx.ErrorInput == nil {
+		return nil
+	}
+	c.errorInput = ctx.ErrorInput
+	input := make(chan error)
+	ctx.ErrorInput = input
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for err := range input {
+			c.lock.Lock()
+			c.rep.Failures = append(c.rep.Failures, Failure{Block: c.block, Transaction: c.tx, Err: err})
+			c.lock.Unlock()
+			c.errorInput <- err
+		}
+	}()
+	return nil
+}
+
+func (c *reportCollector[T]) PreTransaction(state executor.State[T], _ *executor.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.block, c.tx, c.started = state.Block, state.Transaction, true
+	return nil
+}
+
+func (c *reportCollector[T]) PostTransaction(state executor.State[T], _ *executor.Context) error {
+	if state.Transaction >= utils.PseudoTx {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.rep.Transactions++
+	return nil
+}
+
+func (c *reportCollector[T]) PostBlock(executor.State[T], *executor.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.rep.Blocks++
+	return nil
+}
+
+// PostRun records the error stopping the replay and hands the error input back to the error logger.
+func (c *reportCollector[T]) PostRun(_ executor.State[T], ctx *executor.Context, err error) error {
+	if c.errorInput != nil {
+		close(ctx.ErrorInput)
+		c.wg.Wait()
+		ctx.ErrorInput = c.errorInput
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.start.IsZero() {
+		c.rep.Elapsed = time.Since(c.start)
+	}
+	canceled := errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+	if err != nil && c.started && !canceled {
+		c.rep.Failures = append(c.rep.Failures, Failure{Block: c.block, Transaction: c.tx, Err: err})
+	}
+	return nil
+}
+
+func (c *reportCollector[T]) report() Report {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.rep
+}
+
+func makeCancellation[T any](ctx context.Context) executor.Extension[T] {
+	return &cancellation[T]{ctx: ctx}
+}
+
+// cancellation stops the replay before the next block once its context is canceled.
+type cancellation[T any] struct {
+	extension.NilExtension[T]
+	ctx context.Context
+}
+
+func (c *cancellation[T]) PreBlock(state executor.State[T], _ *executor.Context) error {
+	if err := c.ctx.Err(); err != nil {
+		return fmt.Errorf("replay canceled before block %d; %w", state.Block, err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportCollector_CountsCompletedBlocksAndTransactions(t *testing.T) {
+	c := makeReportCollector[any]()
+	ctx := &executor.Context{}
+
+	require.NoError(t, c.PreRun(executor.State[any]{}, ctx))
+	for _, tx := range []int{0, 1, utils.PseudoTx} {
+		s := executor.State[any]{Block: 5, Transaction: tx}
+		require.NoError(t, c.PreTransaction(s, ctx))
+		require.NoError(t, c.PostTransaction(s, ctx))
+	}
+	require.NoError(t, c.PostBlock(executor.State[any]{Block: 5}, ctx))
+	require.NoError(t, c.PostRun(executor.State[any]{}, ctx, nil))
+
+	report := c.report()
+	assert.Equal(t, 1, report.Blocks)
+	assert.Equal(t, 2, report.Transactions)
+	assert.Empty(t, report.Failures)
+	assert.Positive(t, report.Elapsed)
+}
+
+func TestReportCollector_AttributesToleratedErrorsToTheirTransaction(t *testing.T) {
+	c := makeReportCollector[any]()
+	errorInput := make(chan error, 10)
+	ctx := &executor.Context{ErrorInput: errorInput}
+
+	require.NoError(t, c.PreRun(executor.State[any]{}, ctx))
+	require.NotEqual(t, errorInput, ctx.ErrorInput)
+
+	injected := errors.New("injected")
+	require.NoError(t, c.PreTransaction(executor.State[any]{Block: 7, Transaction: 3}, ctx))
+	ctx.ErrorInput <- injected
+	require.NoError(t, c.PostRun(executor.State[any]{}, ctx, nil))
+
+	// the error is forwarded and the error input is handed back to the error logger
+	assert.Equal(t, errorInput, ctx.ErrorInput)
+	assert.Equal(t, injected, <-errorInput)
+
+	report := c.report()
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, Failure{Block: 7, Transaction: 3, Err: injected}, report.Failures[0])
+	assert.ErrorIs(t, report.Failures[0], injected)
+	assert.EqualError(t, report.Failures[0], "block 7 transaction 3: injected")
+}
+
+func TestReportCollector_RecordsErrorStoppingTheRun(t *testing.T) {
+	c := makeReportCollector[any]()
+	ctx := &executor.Context{}
+
+	injected := errors.New("injected")
+	require.NoError(t, c.PreRun(executor.State[any]{}, ctx))
+	require.NoError(t, c.PreTransaction(executor.State[any]{Block: 7, Transaction: 3}, ctx))
+	require.NoError(t, c.PostRun(executor.State[any]{}, ctx, injected))
+
+	assert.Equal(t, []Failure{{Block: 7, Transaction: 3, Err: injected}}, c.report().Failures)
+}
+
+func TestReportCollector_ErrorsOutsideOfTransactionsAndCancellationsAreNoFailures(t *testing.T) {
+	tests := map[string]struct {
+		started bool
+		err     error
+	}{
+		"before_first_transaction": {err: errors.New("injected")},
+		"canceled":                 {started: true, err: context.Canceled},
+		"deadline_exceeded":        {started: true, err: context.DeadlineExceeded},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := makeReportCollector[any]()
+			ctx := &executor.Context{}
+
+			require.NoError(t, c.PreRun(executor.State[any]{}, ctx))
+			if test.started {
+				require.NoError(t, c.PreTransaction(executor.State[any]{Block: 7, Transaction: 3}, ctx))
+			}
+			require.NoError(t, c.PostRun(executor.State[any]{}, ctx, test.err))
+
+			assert.Empty(t, c.report().Failures)
+		})
+	}
+}
+
+func TestCancellation_StopsBeforeNextBlockOnceCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ext := makeCancellation[any](ctx)
+
+	require.NoError(t, ext.PreBlock(executor.State[any]{Block: 1}, nil))
+	cancel()
+	err := ext.PreBlock(executor.State[any]{Block: 2}, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "replay canceled before block 2")
+}
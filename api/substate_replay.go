@@ -0,0 +1,352 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+// Package api provides entry points for running Aida's replays from Go code,
+// so that projects embedding Aida do not need to invoke its command line tools.
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension/logger"
+	"github.com/0xsoniclabs/aida/executor/extension/primer"
+	"github.com/0xsoniclabs/aida/executor/extension/profiler"
+	"github.com/0xsoniclabs/aida/executor/extension/register"
+	"github.com/0xsoniclabs/aida/executor/extension/statedb"
+	"github.com/0xsoniclabs/aida/executor/extension/tracker"
+	"github.com/0xsoniclabs/aida/executor/extension/validator"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	substateDefaultProgressReportFrequency = 100_000
+)
+
+// Option customizes a replay started by RunSubstateReplay.
+type Option func(*options)
+
+type options struct {
+	cli        *cli.Context
+	aidaDb     db.BaseDB
+	stateDb    state.StateDB
+	provider   executor.Provider[txcontext.TxContext]
+	processor  executor.Processor[txcontext.TxContext]
+	extensions []executor.Extension[txcontext.TxContext]
+	validators []executor.Extension[txcontext.TxContext]
+}
+
+// WithCliContext passes the command line context to the provider of the replay.
+func WithCliContext(ctx *cli.Context) Option {
+	return func(o *options) {
+		o.cli = ctx
+	}
+}
+
+// WithAidaDb replays substates of an already opened aida-db instead of opening cfg.AidaDb.
+// The aida-db stays open after the replay.
+func WithAidaDb(aidaDb db.BaseDB) Option {
+	return func(o *options) {
+		o.aidaDb = aidaDb
+	}
+}
+
+// WithStateDb executes the replay on the given StateDb. The StateDb is neither created,
+// primed for a resumed or continued run, nor closed by the replay.
+func WithStateDb(stateDb state.StateDB) Option {
+	return func(o *options) {
+		o.stateDb = stateDb
+	}
+}
+
+// WithProvider replaces the substate provider. No aida-db is opened for a replaced
+// provider unless it is given by WithAidaDb.
+func WithProvider(provider executor.Provider[txcontext.TxContext]) Option {
+	return func(o *options) {
+		o.provider = provider
+	}
+}
+
+// WithProcessor replaces the live-db processor executing the transactions.
+func WithProcessor(processor executor.Processor[txcontext.TxContext]) Option {
+	return func(o *options) {
+		o.processor = processor
+	}
+}
+
+// WithExtensions adds extensions to the default extensions of the replay. They are
+// run after the StateDb is created and before the progress is registered.
+func WithExtensions(extensions ...executor.Extension[txcontext.TxContext]) Option {
+	return func(o *options) {
+		o.extensions = append(o.extensions, extensions...)
+	}
+}
+
+// withValidators adds extensions next to the transaction validator, so that they observe
+// the StateDb holding the post-state of each transaction.
+func withValidators(validators ...executor.Extension[txcontext.TxContext]) Option {
+	return func(o *options) {
+		o.validators = append(o.validators, validators...)
+	}
+}
+
+// RunSubstateReplay replays the substates of the block range given by cfg on a StateDb
+// the same way as the substate command of aida-vm-sdb. Canceling ctx stops the replay
+// before the next block. The returned report is filled in even if the replay fails.
+func RunSubstateReplay(ctx context.Context, cfg *utils.Config, opts ...Option) (report Report, err error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.stateDb == nil {
+		if cfg.Resume {
+			if err = utils.PrepareResume(cfg); errors.Is(err, utils.ErrNothingToResume) {
+				return report, nil
+			} else if err != nil {
+				return report, err
+			}
+		} else if cfg.StateDbSrc != "" {
+			if err = prepareContinuation(cfg); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	cfg.StateValidationMode = utils.SubsetCheck
+
+	aidaDb := o.aidaDb
+	if aidaDb == nil && o.provider == nil {
+		aidaDb, err = utils.OpenAidaDb(cfg)
+		if err != nil {
+			return report, fmt.Errorf("cannot open aida-db; %w", err)
+		}
+		defer func() {
+			err = errors.Join(err, aidaDb.Close())
+		}()
+	}
+
+	provider := o.provider
+	if provider == nil {
+		provider, err = executor.TxProviders.OpenSelected(cfg, executor.SubstateProviderName, executor.ProviderEnvironment{Cli: o.cli, AidaDb: aidaDb, StateDb: o.stateDb})
+		if err != nil {
+			return report, err
+		}
+		defer provider.Close()
+	}
+
+	processor := o.processor
+	if processor == nil {
+		processor, err = executor.MakeLiveDbTxProcessor(cfg)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	validators := append(o.validators, validator.MakeDivergenceBisector(cfg, func(first, last uint64, recorder executor.Extension[txcontext.TxContext]) error {
+		_, err := RunSubstateReplay(ctx, makeBisectionConfig(cfg, first, last),
+			WithCliContext(o.cli),
+			WithAidaDb(aidaDb),
+			WithProvider(provider),
+			WithProcessor(processor),
+			withValidators(recorder),
+		)
+		return err
+	}))
+
+	collector := makeReportCollector[txcontext.TxContext]()
+	err = runSubstates(ctx, cfg, executor.MakeTxLimitProvider(provider, cfg), o.stateDb, processor, o.extensions, validators, aidaDb, collector)
+	return collector.report(), err
+}
+
+// makeBisectionConfig derives the configuration re-running the blocks [first, last] of a failed run
+// on a new StateDb primed with the state of block first-1 and with transaction validation enabled.
+func makeBisectionConfig(cfg *utils.Config, first, last uint64) *utils.Config {
+	c := *cfg
+	c.First, c.Last = first, last
+	c.Bisect = false
+	c.ValidateTxState = true
+	c.ContinueOnFailure = false
+	c.MaxNumTransactions = 0
+	c.StateDbSrc = ""
+	c.Resume = false
+	c.SkipPriming = false
+	c.KeepDb = false
+	c.RegisterRun = ""
+	c.RunBundle = ""
+	return &c
+}
+
+// prepareContinuation disables priming if the run continues the StateDb given by --db-src
+// right after its last block, so that neither substates nor update-sets are read for it.
+func prepareContinuation(cfg *utils.Config) error {
+	path := cfg.StateDbSrc
+	if cfg.ShadowDb {
+		path = filepath.Join(path, utils.PathToPrimaryStateDb)
+	}
+	info, err := utils.ReadStateDbInfo(path)
+	if err != nil {
+		return fmt.Errorf("cannot read state-db info; %w", err)
+	}
+	continues, err := isContinuation(info, cfg.First, cfg.StateDbSrcDirectAccess)
+	if err != nil {
+		return err
+	}
+	if continues {
+		cfg.SkipPriming = true
+	}
+	return nil
+}
+
+// isContinuation decides whether a run starting at the first block continues a StateDb whose
+// last block is recorded in info. Blocks missing in between are reported instead of being
+// primed. Starting at or before the last block is left to the block checkers unless the
+// source is modified in place, since replaying blocks it already contains would corrupt it.
+func isContinuation(info utils.StateDbInfo, first uint64, overwrite bool) (bool, error) {
+	switch {
+	case first == info.Block+1:
+		return true, nil
+	case first > info.Block+1:
+		return false, fmt.Errorf("state-db ends at block %d but the first block is %d; blocks %d-%d are missing", info.Block, first, info.Block+1, first-1)
+	case overwrite && info.HasFinished:
+		return false, fmt.Errorf("state-db ends at block %d but the first block is %d; blocks already contained in a state-db modified in place cannot be replayed", info.Block, first)
+	default:
+		return false, nil
+	}
+}
+
+func runSubstates(ctx context.Context, cfg *utils.Config, provider executor.Provider[txcontext.TxContext], stateDb state.StateDB, processor executor.Processor[txcontext.TxContext], extra []executor.Extension[txcontext.TxContext], validators []executor.Extension[txcontext.TxContext], aidaDb db.BaseDB, collector *reportCollector[txcontext.TxContext]) error {
+	// order of extensionList has to be maintained
+	var extensionList = []executor.Extension[txcontext.TxContext]{
+		// run bundle writer has to be first so that it collects reports of all other extensions
+		profiler.MakeRunBundleWriter[txcontext.TxContext](cfg),
+		// otel tracer has to be next so that its run span covers all other extensions
+		profiler.MakeOtelTracer[txcontext.TxContext](cfg),
+		profiler.MakeCpuProfiler[txcontext.TxContext](cfg),
+		profiler.MakeDiagnosticServer[txcontext.TxContext](cfg),
+		// inputs have to be fingerprinted before the StateDb manager opens them
+		register.MakeRunManifestWriter[txcontext.TxContext](cfg),
+	}
+
+	if stateDb == nil {
+		extensionList = append(
+			extensionList,
+			statedb.MakeStateDbManager[txcontext.TxContext](cfg, ""),
+			statedb.MakeAddressRemapper[txcontext.TxContext](cfg),
+			statedb.MakeGenesisExporter[txcontext.TxContext](cfg),
+			statedb.MakeLiveDbBlockChecker[txcontext.TxContext](cfg),
+			validator.MakeShadowDbValidator(cfg),
+			logger.MakeDbLogger[txcontext.TxContext](cfg),
+		)
+	}
+
+	archiveInquirer, err := statedb.MakeArchiveInquirer(cfg)
+	if err != nil {
+		return err
+	}
+
+	operationProfiler := profiler.MakeOperationProfiler[txcontext.TxContext](cfg)
+	var latencies []tracker.LatencyReporter
+	if reporter, ok := operationProfiler.(tracker.LatencyReporter); ok {
+		latencies = append(latencies, reporter)
+	}
+	stallWatchdog, err := tracker.MakeStallWatchdog[txcontext.TxContext](cfg, latencies...)
+	if err != nil {
+		return err
+	}
+
+	extensionList = append(extensionList, logger.MakeDeltaLogger[txcontext.TxContext](cfg))
+	extensionList = append(extensionList, extra...)
+
+	extensionList = append(extensionList, []executor.Extension[txcontext.TxContext]{
+		register.MakeRegisterProgress(cfg,
+			substateDefaultProgressReportFrequency,
+			register.OnPreBlock,
+		),
+		// RegisterProgress should be the as top-most as possible on the list
+		// In this case, after StateDb is created.
+		// Any error that happen in extension above it will not be correctly recorded.
+		profiler.MakeThreadLocker[txcontext.TxContext](),
+		profiler.MakeVirtualMachineStatisticsPrinter[txcontext.TxContext](cfg),
+		// failure inspector has to be before all extensions running in the background,
+		// so that they are stopped by the time the run is paused
+		statedb.MakeFailureInspector[txcontext.TxContext](cfg),
+		logger.MakeProgressLogger[txcontext.TxContext](cfg, 15*time.Second),
+		logger.MakeErrorLogger[txcontext.TxContext](cfg),
+		// the report collector has to be after the error logger to observe the errors sent to it
+		collector,
+		makeCancellation[txcontext.TxContext](ctx),
+		tracker.MakeBlockProgressTracker(cfg, cfg.TrackerGranularity),
+		tracker.MakeDiskUsageTracker[txcontext.TxContext](cfg, cfg.TrackerGranularity),
+		primer.MakeStateDbPrimer[txcontext.TxContext](cfg),
+		profiler.MakeMemoryUsagePrinter[txcontext.TxContext](cfg),
+		profiler.MakeMemoryProfiler[txcontext.TxContext](cfg),
+		statedb.MakeStateDbPrepper(),
+		archiveInquirer,
+		validator.MakeStateHashValidator[txcontext.TxContext](cfg),
+		// checkpoint trigger has to be before the block event emitter, so that checkpoints are created after EndBlock
+		statedb.MakeCheckpointTrigger[txcontext.TxContext](cfg),
+		statedb.MakeBlockEventEmitter[txcontext.TxContext](),
+		statedb.NewParentBlockHashProcessor(cfg),
+		statedb.MakeTransactionEventEmitter[txcontext.TxContext](),
+		validator.MakeEthereumDbPreTransactionUpdater(cfg),
+		statedb.MakeStateDbCorrector(cfg),
+		validator.MakeLiveDbValidator(cfg, validator.ValidateTxTarget{WorldState: true, Receipt: true}),
+	}...)
+	// extensions observing the post-state of transactions, e.g. the divergence bisector
+	extensionList = append(extensionList, validators...)
+	extensionList = append(extensionList, []executor.Extension[txcontext.TxContext]{
+		validator.MakeBalanceAccountingValidator(cfg),
+		validator.MakeWitnessValidator(cfg),
+		validator.MakeDeletionValidator(cfg),
+		validator.MakeSanityValidator(cfg),
+		validator.MakeEthereumDbPostTransactionUpdater(cfg),
+		profiler.MakeAccessListCollector(cfg),
+		profiler.MakeContractGasProfiler(cfg),
+		operationProfiler,
+		stallWatchdog,
+		// measures the transaction execution, so it is placed as close to the processor as the block profiler allows
+		profiler.MakeTxTimingProfiler(cfg),
+
+		// block profile extension should be always last because:
+		// 1) Pre-Func are called forwards so this is called last and
+		// 2) Post-Func are called backwards so this is called first
+		// that means the gap between time measurements will be as small as possible
+		profiler.MakeBlockRuntimeAndGasCollector(cfg),
+	}...,
+	)
+
+	return executor.NewExecutor(provider, cfg.LogLevel).Run(
+		executor.Params{
+			From:                   int(cfg.First),
+			To:                     int(cfg.Last) + 1,
+			NumWorkers:             1, // vm-sdb can run only with one worker
+			State:                  stateDb,
+			ParallelismGranularity: executor.BlockLevel,
+		},
+		processor,
+		extensionList,
+		aidaDb,
+	)
+}
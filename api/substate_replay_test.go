@@ -0,0 +1,205 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/0xsoniclabs/substate/substate"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// newTestAidaDbConfig creates a test aida-db holding a single substate and parses
+// the configuration of a replay of it the same way as the command line tools do.
+func newTestAidaDbConfig(t *testing.T, flags ...string) (*utils.Config, *substate.Substate) {
+	ss, path := utils.CreateTestSubstateDb(t, db.ProtobufEncodingSchema)
+
+	var cfg *utils.Config
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{
+		&utils.AidaDbFlag,
+		&utils.SubstateEncodingFlag,
+		&utils.ContinueOnFailureFlag,
+	}
+	app.Action = func(ctx *cli.Context) (err error) {
+		cfg, err = utils.NewConfig(ctx, utils.BlockRangeArgs)
+		return err
+	}
+
+	args := append([]string{"replay", "--aida-db", path, "--substate-encoding", "pb"}, flags...)
+	require.NoError(t, app.Run(append(args, "first", "last")))
+	return cfg, ss
+}
+
+func TestRunSubstateReplay_ReportsFailureStoppingTheReplay(t *testing.T) {
+	cfg, ss := newTestAidaDbConfig(t)
+
+	report, err := RunSubstateReplay(context.Background(), cfg)
+	require.ErrorContains(t, err, "nonce too high")
+
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, ss.Block, uint64(report.Failures[0].Block))
+	assert.Equal(t, ss.Transaction, report.Failures[0].Transaction)
+	assert.ErrorContains(t, report.Failures[0], "nonce too high")
+	assert.Zero(t, report.Transactions)
+	assert.Zero(t, report.Blocks)
+	assert.Positive(t, report.Elapsed)
+}
+
+func TestRunSubstateReplay_ReportsFailuresToleratedByContinueOnFailure(t *testing.T) {
+	cfg, ss := newTestAidaDbConfig(t, "--continue-on-failure")
+
+	report, err := RunSubstateReplay(context.Background(), cfg)
+	require.ErrorContains(t, err, "run failed")
+
+	// the failing transaction may be reported by both the sanity checks and the processor
+	require.NotEmpty(t, report.Failures)
+	for _, failure := range report.Failures {
+		assert.Equal(t, ss.Block, uint64(failure.Block))
+		assert.Equal(t, ss.Transaction, failure.Transaction)
+	}
+	assert.ErrorContains(t, report.Failures[0], "nonce too high")
+	assert.Equal(t, 1, report.Transactions)
+	assert.Equal(t, 1, report.Blocks)
+}
+
+func TestRunSubstateReplay_CanceledContextStopsTheReplay(t *testing.T) {
+	cfg, _ := newTestAidaDbConfig(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report, err := RunSubstateReplay(ctx, cfg)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, report.Failures)
+	assert.Zero(t, report.Blocks)
+}
+
+func TestRunSubstateReplay_FailsWithoutAidaDb(t *testing.T) {
+	cfg := &utils.Config{AidaDb: filepath.Join(t.TempDir(), "missing")}
+
+	_, err := RunSubstateReplay(context.Background(), cfg)
+	require.ErrorContains(t, err, "cannot open aida-db")
+}
+
+func TestSubstateReplay_IsContinuation(t *testing.T) {
+	tests := map[string]struct {
+		stored      uint64
+		hasFinished bool
+		first       uint64
+		overwrite   bool
+		continues   bool
+		wantErr     string
+	}{
+		"contiguous":                           {stored: 10, hasFinished: true, first: 11, continues: true},
+		"contiguous overwrite":                 {stored: 10, hasFinished: true, first: 11, overwrite: true, continues: true},
+		"contiguous unfinished":                {stored: 10, first: 11, continues: true},
+		"contiguous from genesis":              {stored: 0, hasFinished: true, first: 1, continues: true},
+		"gap":                                  {stored: 10, hasFinished: true, first: 20, wantErr: "state-db ends at block 10 but the first block is 20; blocks 11-19 are missing"},
+		"gap overwrite":                        {stored: 10, hasFinished: true, first: 12, overwrite: true, wantErr: "blocks 11-11 are missing"},
+		"overlap":                              {stored: 10, hasFinished: true, first: 5},
+		"overlap unfinished":                   {stored: 10, first: 10},
+		"overlap overwrite":                    {stored: 10, hasFinished: true, first: 10, overwrite: true, wantErr: "state-db ends at block 10 but the first block is 10; blocks already contained"},
+		"overlap overwrite unfinished":         {stored: 10, first: 5, overwrite: true},
+		"overlap overwrite at genesis":         {stored: 0, hasFinished: true, first: 0, overwrite: true, wantErr: "blocks already contained"},
+		"overlap unfinished without overwrite": {stored: 0, first: 0},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			info := utils.StateDbInfo{Block: test.stored, HasFinished: test.hasFinished}
+			continues, err := isContinuation(info, test.first, test.overwrite)
+			if test.wantErr != "" {
+				require.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.continues, continues)
+		})
+	}
+}
+
+func TestSubstateReplay_PrepareContinuationSkipsPrimingOfContiguousRange(t *testing.T) {
+	cfg := &utils.Config{StateDbSrc: t.TempDir(), First: 11}
+	require.NoError(t, utils.WriteStateDbInfo(cfg.StateDbSrc, cfg, 10, common.Hash{}, true))
+
+	require.NoError(t, prepareContinuation(cfg))
+	assert.True(t, cfg.SkipPriming)
+}
+
+func TestSubstateReplay_PrepareContinuationReportsGap(t *testing.T) {
+	cfg := &utils.Config{StateDbSrc: t.TempDir(), First: 15}
+	require.NoError(t, utils.WriteStateDbInfo(cfg.StateDbSrc, cfg, 10, common.Hash{}, true))
+
+	err := prepareContinuation(cfg)
+	require.ErrorContains(t, err, "state-db ends at block 10 but the first block is 15")
+	assert.False(t, cfg.SkipPriming)
+}
+
+func TestSubstateReplay_PrepareContinuationReadsInfoOfPrimaryShadowDb(t *testing.T) {
+	cfg := &utils.Config{StateDbSrc: t.TempDir(), First: 11, ShadowDb: true}
+	primary := filepath.Join(cfg.StateDbSrc, utils.PathToPrimaryStateDb)
+	require.NoError(t, os.MkdirAll(primary, 0755))
+	require.NoError(t, utils.WriteStateDbInfo(primary, cfg, 10, common.Hash{}, true))
+
+	require.NoError(t, prepareContinuation(cfg))
+	assert.True(t, cfg.SkipPriming)
+}
+
+func TestSubstateReplay_PrepareContinuationFailsWithoutInfo(t *testing.T) {
+	cfg := &utils.Config{StateDbSrc: t.TempDir(), First: 11}
+	require.ErrorContains(t, prepareContinuation(cfg), "cannot read state-db info")
+}
+
+func TestSubstateReplay_MakeBisectionConfigReplaysRangeOnPrimedStateDb(t *testing.T) {
+	cfg := &utils.Config{
+		First:       1,
+		Last:        100,
+		Bisect:      true,
+		StateDbSrc:  "/path/to/state-db",
+		Resume:      true,
+		SkipPriming: true,
+		KeepDb:      true,
+		RegisterRun: "connection",
+		RunBundle:   "bundle.tar.gz",
+		DbImpl:      "carmen",
+	}
+
+	got := makeBisectionConfig(cfg, 41, 48)
+	assert.Equal(t, uint64(41), got.First)
+	assert.Equal(t, uint64(48), got.Last)
+	assert.False(t, got.Bisect)
+	assert.True(t, got.ValidateTxState)
+	assert.Empty(t, got.StateDbSrc)
+	assert.False(t, got.Resume)
+	assert.False(t, got.SkipPriming)
+	assert.False(t, got.KeepDb)
+	assert.Empty(t, got.RegisterRun)
+	assert.Empty(t, got.RunBundle)
+	assert.Equal(t, "carmen", got.DbImpl)
+
+	// the configuration of the failed run is not modified
+	assert.True(t, cfg.Bisect)
+	assert.Equal(t, uint64(1), cfg.First)
+}
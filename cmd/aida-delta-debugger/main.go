@@ -34,10 +34,15 @@ func main() {
 		Flags: []cli.Flag{
 			&utils.DeltaTraceFileFlag,
 			&utils.DeltaOutputFlag,
+			&utils.DeltaOutputDirFlag,
 			&utils.AddressSampleRunsFlag,
 			&utils.DeltaTimeoutFlag,
 			&utils.RandomSeedFlag,
 			&utils.MaxFactorFlag,
+			&utils.DeltaStrategyFlag,
+			&utils.CutPointFlag,
+			&utils.StrictReplayFlag,
+			&utils.MaxReplayMismatchesFlag,
 			&utils.StateDbImplementationFlag,
 			&utils.StateDbVariantFlag,
 			&utils.CarmenSchemaFlag,
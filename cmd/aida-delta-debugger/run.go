@@ -19,8 +19,10 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
@@ -35,10 +37,15 @@ import (
 func run(c *cli.Context) error {
 	traceFiles := c.StringSlice(utils.DeltaTraceFileFlag.Name)
 	outputPath := c.String(utils.DeltaOutputFlag.Name)
+	outputDir := c.Path(utils.DeltaOutputDirFlag.Name)
 	timeout := c.Duration(utils.DeltaTimeoutFlag.Name)
 	addressRuns := c.Int(utils.AddressSampleRunsFlag.Name)
 	seed := c.Int64(utils.RandomSeedFlag.Name)
 	maxFactor := c.Int(utils.MaxFactorFlag.Name)
+	cutPointArg := c.String(utils.CutPointFlag.Name)
+	strategyArg := c.String(utils.DeltaStrategyFlag.Name)
+	strict := c.Bool(utils.StrictReplayFlag.Name)
+	maxMismatches := c.Int(utils.MaxReplayMismatchesFlag.Name)
 
 	dbImpl := c.String(utils.StateDbImplementationFlag.Name)
 	dbVariant := c.String(utils.StateDbVariantFlag.Name)
@@ -52,18 +59,32 @@ func run(c *cli.Context) error {
 	if len(traceFiles) == 0 {
 		return cli.Exit("provide --trace-file pointing to the logger output", 1)
 	}
-	if len(traceFiles) > 1 {
-		return cli.Exit("provide exactly one --trace-file when using logger traces", 1)
+	batch := strings.TrimSpace(outputDir) != ""
+	if batch && strings.TrimSpace(outputPath) != "" {
+		return cli.Exit("use either --output or --output-dir", 1)
 	}
-	if strings.TrimSpace(outputPath) == "" {
+	if !batch && len(traceFiles) > 1 {
+		return cli.Exit("provide exactly one --trace-file when using logger traces, or --output-dir to minimize each of them", 1)
+	}
+	if !batch && strings.TrimSpace(outputPath) == "" {
 		return cli.Exit("specify --output to store the minimized trace", 1)
 	}
+	if maxMismatches < 0 {
+		return cli.Exit("--max-replay-mismatches must not be negative", 1)
+	}
 
-	files := traceFiles
+	var cutPoint *delta.CutPoint
+	if strings.TrimSpace(cutPointArg) != "" {
+		cut, err := delta.ParseCutPoint(cutPointArg)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		cutPoint = &cut
+	}
 
-	ops, err := delta.LoadOperations(files, 0, 0)
+	strategies, err := delta.ParseStrategies(strategyArg)
 	if err != nil {
-		return err
+		return cli.Exit(err.Error(), 1)
 	}
 
 	loggerFn := func(string, ...any) {}
@@ -73,40 +94,75 @@ func run(c *cli.Context) error {
 		}
 	}
 
-	minimizer := delta.NewMinimizer(delta.MinimizerConfig{
+	minimizerCfg := delta.MinimizerConfig{
 		AddressSampleRuns: addressRuns,
 		RandSeed:          seed,
 		MaxFactor:         maxFactor,
+		CutPoint:          cutPoint,
+		Strategies:        strategies,
 		Logger:            loggerFn,
-	})
+	}
+
+	if batch {
+		// all traces share a single working directory for their temporary StateDbs
+		tmpDir, err = os.MkdirTemp(tmpDir, "delta-batch-")
+		if err != nil {
+			return fmt.Errorf("cannot create working directory; %w", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(tmpDir); err != nil {
+				log.Warningf("cannot remove working directory %v; %v", tmpDir, err)
+			}
+		}()
+	}
 
 	tester, err := delta.NewStateTester(delta.StateTesterConfig{
-		DbImpl:       dbImpl,
-		Variant:      dbVariant,
-		TmpDir:       tmpDir,
-		CarmenSchema: carmenSchema,
-		LogLevel:     logLevel,
-		ChainID:      chainID,
+		DbImpl:        dbImpl,
+		Variant:       dbVariant,
+		TmpDir:        tmpDir,
+		CarmenSchema:  carmenSchema,
+		LogLevel:      logLevel,
+		ChainID:       chainID,
+		Strict:        strict,
+		MaxMismatches: maxMismatches,
 	})
 	if err != nil {
 		return err
 	}
 
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if batch {
+		summaries, err := delta.MinimizeBatch(ctx, delta.BatchConfig{
+			TraceFiles: traceFiles,
+			OutputDir:  outputDir,
+			Minimizer:  minimizerCfg,
+			Timeout:    timeout,
+		}, tester)
+		return reportBatch(log, outputDir, summaries, err)
+	}
+
+	ops, err := delta.LoadOperations(traceFiles, 0, 0)
+	if err != nil {
+		return err
+	}
+
 	if timeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
-	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
-	defer stop()
 
 	start := time.Now()
-	minimized, err := minimizer.Minimize(ctx, ops, tester)
+	minimized, err := delta.NewMinimizer(minimizerCfg).Minimize(ctx, ops, tester)
 	if err != nil {
 		if errors.Is(err, delta.ErrInputDoesNotFail) {
 			return cli.Exit("delta-debugger: command succeeds on the original trace", 1)
 		}
+		if errors.Is(err, delta.ErrMinimizedDoesNotFail) {
+			return cli.Exit("delta-debugger: minimized trace no longer reproduces the failure; the failure may be non-deterministic", 1)
+		}
 		if errors.Is(err, context.Canceled) {
 			return cli.Exit("delta-debugger: operation cancelled", 1)
 		}
@@ -130,3 +186,26 @@ func run(c *cli.Context) error {
 
 	return nil
 }
+
+// reportBatch logs the outcome of each trace of a batch and fails if any of them was not minimized.
+func reportBatch(log logger.Logger, outputDir string, summaries []delta.BatchSummary, err error) error {
+	failed := 0
+	for _, summary := range summaries {
+		if summary.Error != "" {
+			failed++
+			log.Errorf("%s: %s", summary.TraceFile, summary.Error)
+			continue
+		}
+		log.Noticef("%s: reduced operations %d -> %d (%.1f%%) in %.2fs",
+			summary.TraceFile, summary.OriginalOps, summary.MinimizedOps, 100*summary.ReductionRatio, summary.RuntimeSeconds)
+	}
+	if err != nil {
+		return err
+	}
+	log.Noticef("summaries written to %s", filepath.Join(outputDir, delta.BatchIndexFile))
+
+	if failed > 0 {
+		return cli.Exit(fmt.Sprintf("delta-debugger: %d of %d traces could not be minimized", failed, len(summaries)), 1)
+	}
+	return nil
+}
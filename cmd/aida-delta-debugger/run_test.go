@@ -18,9 +18,11 @@ package main
 
 import (
 	"flag"
+	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/0xsoniclabs/aida/delta"
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/stretchr/testify/require"
@@ -34,10 +36,13 @@ func newRunContext(t *testing.T, traceFiles []string, output string) *cli.Contex
 	for _, fl := range []cli.Flag{
 		&utils.DeltaTraceFileFlag,
 		&utils.DeltaOutputFlag,
+		&utils.DeltaOutputDirFlag,
 		&utils.DeltaTimeoutFlag,
 		&utils.AddressSampleRunsFlag,
 		&utils.RandomSeedFlag,
 		&utils.MaxFactorFlag,
+		&utils.DeltaStrategyFlag,
+		&utils.CutPointFlag,
 		&utils.StateDbImplementationFlag,
 		&utils.StateDbVariantFlag,
 		&utils.DbTmpFlag,
@@ -78,6 +83,40 @@ func TestRun_MultipleTraceFiles(t *testing.T) {
 	require.Contains(t, exitErr.Error(), "provide exactly one --trace-file")
 }
 
+func TestRun_OutputAndOutputDirAreExclusive(t *testing.T) {
+	ctx := newRunContext(t, []string{"a", "b"}, "out.trace")
+	require.NoError(t, ctx.Set(utils.DeltaOutputDirFlag.Name, t.TempDir()))
+
+	err := run(ctx)
+	require.Error(t, err)
+	exitErr, ok := err.(cli.ExitCoder)
+	require.True(t, ok)
+	require.Contains(t, exitErr.Error(), "use either --output or --output-dir")
+}
+
+func TestRun_BatchRecordsTracesWhichCannotBeLoaded(t *testing.T) {
+	dir := t.TempDir()
+	outputDir := filepath.Join(dir, "out")
+	ctx := newRunContext(t, []string{filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log")}, "")
+	require.NoError(t, ctx.Set(utils.DeltaOutputDirFlag.Name, outputDir))
+	require.NoError(t, ctx.Set(utils.DbTmpFlag.Name, dir))
+
+	err := run(ctx)
+	require.Error(t, err)
+	exitErr, ok := err.(cli.ExitCoder)
+	require.True(t, ok)
+	require.Contains(t, exitErr.Error(), "2 of 2 traces could not be minimized")
+
+	require.FileExists(t, filepath.Join(outputDir, delta.BatchIndexFile))
+	require.FileExists(t, filepath.Join(outputDir, "0-a.log.summary.json"))
+	require.FileExists(t, filepath.Join(outputDir, "1-b.log.summary.json"))
+
+	// the working directory of the StateDbs is removed
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
 func TestRun_MissingOutput(t *testing.T) {
 	ctx := newRunContext(t, []string{"trace.txt"}, "")
 
@@ -95,3 +134,25 @@ func TestRun_LoadOperationsError(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "open trace")
 }
+
+func TestRun_InvalidCutPoint(t *testing.T) {
+	ctx := newRunContext(t, []string{"trace.txt"}, "out.trace")
+	require.NoError(t, ctx.Set(utils.CutPointFlag.Name, "12:x:3"))
+
+	err := run(ctx)
+	require.Error(t, err)
+	exitErr, ok := err.(cli.ExitCoder)
+	require.True(t, ok)
+	require.Contains(t, exitErr.Error(), "invalid cut point transaction")
+}
+
+func TestRun_InvalidStrategy(t *testing.T) {
+	ctx := newRunContext(t, []string{"trace.txt"}, "out.trace")
+	require.NoError(t, ctx.Set(utils.DeltaStrategyFlag.Name, "addresses,blocks"))
+
+	err := run(ctx)
+	require.Error(t, err)
+	exitErr, ok := err.(cli.ExitCoder)
+	require.True(t, ok)
+	require.Contains(t, exitErr.Error(), "unknown strategy")
+}
@@ -37,6 +37,8 @@ func main() {
 			&profile.GetAddressStatsCommand,
 			&profile.GetKeyStatsCommand,
 			&profile.GetLocationStatsCommand,
+			&profile.DiffBundlesCommand,
+			&profile.VerifyManifestCommand,
 		},
 	}
 	if err := app.Run(os.Args); err != nil {
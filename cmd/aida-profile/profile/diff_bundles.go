@@ -0,0 +1,86 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profile
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/aida/utils/bundle"
+	"github.com/urfave/cli/v2"
+)
+
+// DiffBundlesCommand compares metrics of two run bundles.
+var DiffBundlesCommand = cli.Command{
+	Action:    diffBundlesAction,
+	Name:      "diff-bundles",
+	Usage:     "compares metrics of two run bundles produced with --run-bundle",
+	ArgsUsage: "<bundleA> <bundleB>",
+	Flags: []cli.Flag{
+		&utils.OutputFlag,
+	},
+	Description: `
+The aida-profile diff-bundles command requires two arguments:
+<bundleA> <bundleB>
+
+<bundleA> and <bundleB> are run bundles (tar.zst) of runs over the same
+block range and data inputs. A textual report of all common metrics is
+printed; if --output is set, the metrics are written into the given CSV file.`,
+}
+
+func diffBundlesAction(ctx *cli.Context) error {
+	if ctx.Args().Len() != 2 {
+		return fmt.Errorf("diff-bundles command requires exactly 2 arguments")
+	}
+
+	a, err := bundle.Read(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	b, err := bundle.Read(ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	if err = bundle.CheckComparable(a.Manifest, b.Manifest); err != nil {
+		return fmt.Errorf("bundles are not comparable; %w", err)
+	}
+
+	comparison, err := bundle.Compare(a, b)
+	if err != nil {
+		return err
+	}
+
+	if err = comparison.WriteText(os.Stdout); err != nil {
+		return err
+	}
+
+	output := ctx.String(utils.OutputFlag.Name)
+	if output == "" {
+		return nil
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("cannot create %v; %w", output, err)
+	}
+	if err = comparison.WriteCsv(file); err != nil {
+		file.Close()
+		return fmt.Errorf("cannot write %v; %w", output, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/aida/utils/bundle"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	verifyAidaDbFlag = cli.PathFlag{
+		Name:  "aida-db",
+		Usage: "verifies the aida-db at given path instead of the one recorded in the manifest",
+	}
+	verifyStateDbSrcFlag = cli.PathFlag{
+		Name:  "db-src",
+		Usage: "verifies the StateDb at given path instead of the one recorded in the manifest",
+	}
+)
+
+// VerifyManifestCommand recomputes the input fingerprints of a run and reports drift.
+var VerifyManifestCommand = cli.Command{
+	Action:    verifyManifestAction,
+	Name:      "verify-manifest",
+	Usage:     "recomputes the input fingerprints recorded by a run and reports inputs which changed since",
+	ArgsUsage: "<runDir|bundle>",
+	Flags: []cli.Flag{
+		&verifyAidaDbFlag,
+		&verifyStateDbSrcFlag,
+		&logger.LogLevelFlag,
+	},
+	Description: `
+The aida-profile verify-manifest command requires one argument:
+<runDir|bundle>
+
+<runDir|bundle> is either the directory of a run scoped by --output-dir or a
+run bundle (tar.zst) produced with --run-bundle. The fingerprints of the run
+manifest are recomputed using the recorded schemes and sampling parameters;
+the command fails if any of the inputs drifted.`,
+}
+
+func verifyManifestAction(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return fmt.Errorf("verify-manifest command requires exactly 1 argument")
+	}
+	log := logger.NewLogger(ctx.String(logger.LogLevelFlag.Name), "Verify-Manifest")
+
+	manifest, err := readRunManifest(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	paths := make(map[string]string)
+	if aidaDb := ctx.Path(verifyAidaDbFlag.Name); aidaDb != "" {
+		paths[utils.AidaDbMetadataInput] = aidaDb
+		paths[utils.AidaDbContentInput] = aidaDb
+	}
+	if dbSrc := ctx.Path(verifyStateDbSrcFlag.Name); dbSrc != "" {
+		paths[utils.StateDbInfoInput] = filepath.Join(dbSrc, utils.PathToDbInfo)
+	}
+
+	drifts, err := utils.VerifyRunManifest(manifest, paths, ctx.String(logger.LogLevelFlag.Name))
+	if err != nil {
+		return err
+	}
+	if manifest.Build.GitCommit != utils.GitCommit {
+		log.Warningf("Run was executed by a binary built from commit %v, this binary is built from %v", manifest.Build.GitCommit, utils.GitCommit)
+	}
+	if len(drifts) == 0 {
+		log.Noticef("All %d input fingerprints match", len(manifest.Inputs))
+		return nil
+	}
+	errs := make([]error, 0, len(drifts))
+	for _, drift := range drifts {
+		errs = append(errs, errors.New(drift.String()))
+	}
+	return fmt.Errorf("%d of %d inputs drifted;\n%w", len(drifts), len(manifest.Inputs), errors.Join(errs...))
+}
+
+// readRunManifest reads the manifest from the summary of a run directory or from the
+// configuration stored in a run bundle.
+func readRunManifest(path string) (*utils.RunManifest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest *utils.RunManifest
+	if info.IsDir() {
+		summary, err := utils.ReadRunSummary(path)
+		if err != nil {
+			return nil, err
+		}
+		manifest = summary.Manifest
+	} else {
+		b, err := bundle.Read(path)
+		if err != nil {
+			return nil, err
+		}
+		var cfg struct{ RunManifest *utils.RunManifest }
+		if err = json.Unmarshal(b.Files[bundle.ConfigName], &cfg); err != nil {
+			return nil, fmt.Errorf("cannot decode configuration of bundle %v; %w", path, err)
+		}
+		manifest = cfg.RunManifest
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("run %v has no manifest", path)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/aida/utils/bundle"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestCmd_RunVerifyManifestCommand_DetectsDrift(t *testing.T) {
+	// given
+	runDir := t.TempDir()
+	skipList := filepath.Join(t.TempDir(), "skip-list")
+	require.NoError(t, os.WriteFile(skipList, []byte("1:2\n"), 0644))
+	manifest, err := utils.MakeRunManifest(&utils.Config{SkipList: skipList}, nil)
+	require.NoError(t, err)
+	require.NoError(t, utils.WriteRunSummary(utils.RunSummary{RunDir: runDir, Manifest: manifest}))
+
+	app := cli.NewApp()
+	app.Commands = []*cli.Command{&VerifyManifestCommand}
+	args := utils.NewArgs("test").Arg(VerifyManifestCommand.Name).Arg(runDir).Build()
+
+	// when, then
+	require.NoError(t, app.Run(args))
+
+	require.NoError(t, os.WriteFile(skipList, []byte("1:3\n"), 0644))
+	err = app.Run(args)
+	require.ErrorContains(t, err, "1 of 1 inputs drifted")
+	require.ErrorContains(t, err, utils.SkipListInput)
+}
+
+func TestCmd_RunVerifyManifestCommand_ReadsManifestFromBundle(t *testing.T) {
+	// given
+	skipList := filepath.Join(t.TempDir(), "skip-list")
+	require.NoError(t, os.WriteFile(skipList, []byte("1:2\n"), 0644))
+	cfg := &utils.Config{SkipList: skipList}
+	manifest, err := utils.MakeRunManifest(cfg, nil)
+	require.NoError(t, err)
+	cfg.RunManifest = manifest
+
+	path := filepath.Join(t.TempDir(), "run.tar.zst")
+	b := bundle.NewBundle("test", 1, 2, nil)
+	require.NoError(t, b.AddJson(bundle.ConfigName, cfg))
+	require.NoError(t, b.Write(path))
+
+	app := cli.NewApp()
+	app.Commands = []*cli.Command{&VerifyManifestCommand}
+
+	// when, then
+	require.NoError(t, app.Run(utils.NewArgs("test").Arg(VerifyManifestCommand.Name).Arg(path).Build()))
+}
+
+func TestCmd_RunVerifyManifestCommand_FailsWithoutManifest(t *testing.T) {
+	runDir := t.TempDir()
+	require.NoError(t, utils.WriteRunSummary(utils.RunSummary{RunDir: runDir}))
+
+	app := cli.NewApp()
+	app.Commands = []*cli.Command{&VerifyManifestCommand}
+
+	err := app.Run(utils.NewArgs("test").Arg(VerifyManifestCommand.Name).Arg(runDir).Build())
+	require.ErrorContains(t, err, "has no manifest")
+}
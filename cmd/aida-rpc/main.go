@@ -20,6 +20,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/urfave/cli/v2"
@@ -33,30 +34,53 @@ var rpcApp = &cli.App{
 	Copyright: "(c) 2025 Sonic Labs",
 	Flags: []cli.Flag{
 		&utils.RpcRecordingFileFlag,
+		&utils.ProviderFlag,
+		&utils.AidaDbFlag,
 		&utils.WorkersFlag,
 
+		// Fuzzing
+		&utils.RpcFuzzFlag,
+		&utils.RpcFuzzVariantsFlag,
+		&utils.RandomSeedFlag,
+
+		// Latency
+		&utils.RpcCompareLatencyFlag,
+		&utils.RpcLatencyReportFlag,
+
 		// VM
 		&utils.VmImplementation,
 
 		// Config
 		&logger.LogLevelFlag,
+		&logger.LogLevelOverrideFlag,
 		&utils.ChainIDFlag,
+		&utils.ChainConfigFileFlag,
 		&utils.ContinueOnFailureFlag,
 		&utils.ValidateFlag,
 		&utils.NoHeartbeatLoggingFlag,
+		&utils.HeartbeatFieldsFlag,
 		&utils.ErrorLoggingFlag,
+		&utils.ErrorLoggingFormatFlag,
+		&utils.LogQueueSizeFlag,
+		&utils.LogOverflowFlag,
 		&utils.TrackProgressFlag,
 
 		// Register
 		&utils.RegisterRunFlag,
 		&utils.OverwriteRunIdFlag,
+		&utils.OutputDirFlag,
 
 		// ShadowDB
 		&utils.ShadowDb,
 
+		// ArchiveDb
+		&utils.ArchiveCacheSizeFlag,
+
 		// StateDB
 		&utils.StateDbSrcFlag,
 		&utils.StateDbLoggingFlag,
+		&utils.StateDbLoggingFormatFlag,
+		&utils.StateDbLoggingFilterFlag,
 
 		// Trace
 		&utils.TraceFlag,
@@ -73,6 +97,7 @@ var rpcApp = &cli.App{
 
 func main() {
 	if err := rpcApp.Run(os.Args); err != nil {
-		log.Fatal(err)
+		log.Println(err)
+		os.Exit(executor.ExitCode(err))
 	}
 }
@@ -19,6 +19,7 @@ package main
 import (
 	"compress/gzip"
 	"encoding/json"
+	"errors"
 
 	"os"
 	"strings"
@@ -135,7 +136,7 @@ func TestRpc_AllDbEventsAreIssuedInOrder_Sequential(t *testing.T) {
 		archiveFour.EXPECT().Release(),
 	)
 
-	if err := run(cfg, provider, db, rpcProcessor{cfg}, nil); err != nil {
+	if err := run(cfg, provider, db, rpcProcessor{cfg: cfg}, nil); err != nil {
 		t.Errorf("run failed: %v", err)
 	}
 }
@@ -193,7 +194,7 @@ func TestRpc_AllDbEventsAreIssuedInOrder_Parallel(t *testing.T) {
 		archiveThree.EXPECT().Release(),
 	)
 
-	if err := run(cfg, provider, db, rpcProcessor{cfg}, nil); err != nil {
+	if err := run(cfg, provider, db, rpcProcessor{cfg: cfg}, nil); err != nil {
 		t.Errorf("run failed: %v", err)
 	}
 }
@@ -397,7 +398,7 @@ func TestRpc_ValidationDoesNotFailOnValidTransaction_Sequential(t *testing.T) {
 	)
 
 	// run fails but not on validation
-	err = run(cfg, provider, db, rpcProcessor{cfg}, nil)
+	err = run(cfg, provider, db, rpcProcessor{cfg: cfg}, nil)
 	if err != nil {
 		t.Errorf("run must not fail")
 	}
@@ -432,7 +433,7 @@ func TestRpc_ValidationDoesNotFailOnValidTransaction_Parallel(t *testing.T) {
 	)
 
 	// run fails but not on validation
-	err = run(cfg, provider, db, rpcProcessor{cfg}, nil)
+	err = run(cfg, provider, db, rpcProcessor{cfg: cfg}, nil)
 	if err != nil {
 		t.Errorf("run must not fail")
 	}
@@ -466,7 +467,7 @@ func TestRpc_ValidationFailsOnValidTransaction_Sequential(t *testing.T) {
 	)
 
 	// run fails but not on validation
-	err = run(cfg, provider, db, rpcProcessor{cfg}, nil)
+	err = run(cfg, provider, db, rpcProcessor{cfg: cfg}, nil)
 	if err == nil {
 		t.Errorf("run must fail")
 	}
@@ -505,7 +506,7 @@ func TestRpc_ValidationFailsOnValidTransaction_Parallel(t *testing.T) {
 	)
 
 	// run fails but not on validation
-	err = run(cfg, provider, db, rpcProcessor{cfg}, nil)
+	err = run(cfg, provider, db, rpcProcessor{cfg: cfg}, nil)
 	if err == nil {
 		t.Errorf("run must fail")
 	}
@@ -515,6 +516,109 @@ func TestRpc_ValidationFailsOnValidTransaction_Parallel(t *testing.T) {
 	}
 }
 
+func TestRpc_FuzzModeExecutesMutatedRequestsWithoutComparingResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := executor.NewMockProvider[*rpc.RequestAndResults](ctrl)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+
+	cfg := utils.NewTestConfig(t, utils.OperaMainnetChainID, 2, 4, true, "")
+	cfg.RpcFuzz = true
+	cfg.RpcFuzzVariants = 5
+
+	provider.EXPECT().
+		Run(2, 5, gomock.Any()).
+		DoAndReturn(func(_ int, _ int, consumer executor.Consumer[*rpc.RequestAndResults]) error {
+			return consumer(executor.TransactionInfo[*rpc.RequestAndResults]{Block: 2, Transaction: 1, Data: reqBlockTwo})
+		})
+
+	db.EXPECT().GetArchiveState(gomock.Any()).Return(archive, nil).MinTimes(1)
+	db.EXPECT().Error().Return(nil).Times(cfg.RpcFuzzVariants)
+	archive.EXPECT().BeginTransaction(gomock.Any()).AnyTimes()
+	archive.EXPECT().GetBalance(gomock.Any()).Return(new(uint256.Int).SetUint64(42)).AnyTimes()
+	archive.EXPECT().EndTransaction().AnyTimes()
+	archive.EXPECT().Release().AnyTimes()
+
+	err := run(cfg, provider, db, makeRpcFuzzProcessor(cfg), nil)
+	require.NoError(t, err)
+}
+
+func TestRpcFuzzProcessor_ReportsPanic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+
+	cfg := &utils.Config{RpcFuzzVariants: 1}
+	mutant := rpc.Mutant{
+		Mutation: rpc.AddressMutation,
+		Request: &rpc.RequestAndResults{
+			RequestedBlock: 2,
+			// a non-string address makes the execution panic
+			Query: &rpc.Body{Method: "eth_getBalance", MethodBase: "getBalance", Params: []interface{}{42}},
+		},
+	}
+	st := executor.State[*rpc.RequestAndResults]{Block: 2, Data: mutant.Request}
+
+	err := makeRpcFuzzProcessor(cfg).processMutant(st, &executor.Context{Archive: archive}, mutant)
+	require.ErrorContains(t, err, "execution panicked")
+}
+
+func TestRpcFuzzProcessor_ReportsStateDbError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+
+	cfg := &utils.Config{RpcFuzzVariants: 1}
+	mutant := rpc.Mutant{Mutation: rpc.AddressMutation, Request: reqBlockTwo}
+	st := executor.State[*rpc.RequestAndResults]{Block: 2, Data: reqBlockTwo}
+
+	archive.EXPECT().GetBalance(common.HexToAddress(testingAddress)).Return(new(uint256.Int))
+	db.EXPECT().Error().Return(errors.New("injected error"))
+
+	err := makeRpcFuzzProcessor(cfg).processMutant(st, &executor.Context{State: db, Archive: archive}, mutant)
+	require.ErrorContains(t, err, "injected error")
+}
+
+func TestRpcFuzzProcessor_ShiftedBlockUsesItsOwnArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+	shifted := state.NewMockNonCommittableStateDB(ctrl)
+
+	cfg := &utils.Config{RpcFuzzVariants: 1}
+	req := *reqBlockTwo
+	req.RequestedBlock = 3
+	mutant := rpc.Mutant{Mutation: rpc.BlockNumberMutation, Request: &req}
+	st := executor.State[*rpc.RequestAndResults]{Block: 2, Transaction: 1, Data: reqBlockTwo}
+
+	gomock.InOrder(
+		db.EXPECT().GetArchiveState(uint64(3)).Return(shifted, nil),
+		shifted.EXPECT().BeginTransaction(uint32(1)),
+		shifted.EXPECT().GetBalance(common.HexToAddress(testingAddress)).Return(new(uint256.Int)),
+		db.EXPECT().Error(),
+		shifted.EXPECT().EndTransaction(),
+		shifted.EXPECT().Release(),
+	)
+
+	err := makeRpcFuzzProcessor(cfg).processMutant(st, &executor.Context{State: db, Archive: archive}, mutant)
+	require.NoError(t, err)
+}
+
+func TestRpcFuzzProcessor_MissingArchiveBlockIsNotAFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+
+	cfg := &utils.Config{RpcFuzzVariants: 1}
+	req := *reqBlockTwo
+	req.RequestedBlock = 3
+	mutant := rpc.Mutant{Mutation: rpc.BlockNumberMutation, Request: &req}
+	st := executor.State[*rpc.RequestAndResults]{Block: 2, Data: reqBlockTwo}
+
+	db.EXPECT().GetArchiveState(uint64(3)).Return(nil, errors.New("block 3 is not present in the archive"))
+
+	err := makeRpcFuzzProcessor(cfg).processMutant(st, &executor.Context{State: db}, mutant)
+	require.NoError(t, err)
+}
+
 var reqBlockTwo = &rpc.RequestAndResults{
 	RequestedBlock: 2,
 	Query: &rpc.Body{
@@ -17,6 +17,8 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/0xsoniclabs/aida/executor"
@@ -26,9 +28,11 @@ import (
 	"github.com/0xsoniclabs/aida/executor/extension/statedb"
 	"github.com/0xsoniclabs/aida/executor/extension/tracker"
 	"github.com/0xsoniclabs/aida/executor/extension/validator"
+	log "github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/rpc"
 	"github.com/0xsoniclabs/aida/state"
 	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
 	"github.com/urfave/cli/v2"
 )
 
@@ -36,7 +40,7 @@ const (
 	rpcDefaultProgressReportFrequency = 100_000
 )
 
-func RunRpc(ctx *cli.Context) error {
+func RunRpc(ctx *cli.Context) (err error) {
 	cfg, err := utils.NewConfig(ctx, utils.BlockRangeArgs)
 	if err != nil {
 		return err
@@ -44,27 +48,54 @@ func RunRpc(ctx *cli.Context) error {
 
 	cfg.SetStateDbSrcReadOnly()
 
-	rpcSource, err := executor.OpenRpcRecording(cfg, ctx)
+	rpcSource, err := executor.RpcProviders.OpenSelected(cfg, executor.RpcProviderName, executor.ProviderEnvironment{Cli: ctx})
 	if err != nil {
 		return err
 	}
 
 	defer rpcSource.Close()
 
-	return run(cfg, rpcSource, nil, makeRpcProcessor(cfg), nil)
+	if cfg.RpcFuzz {
+		if cfg.RpcFuzzVariants < 1 {
+			return fmt.Errorf("--%v must be positive, got %d", utils.RpcFuzzVariantsFlag.Name, cfg.RpcFuzzVariants)
+		}
+		log.NewLogger(cfg.LogLevel, "Rpc-Fuzz").Noticef("Fuzzing %d variants per request with random seed %d", cfg.RpcFuzzVariants, cfg.RandomSeed)
+		return run(cfg, rpcSource, nil, makeRpcFuzzProcessor(cfg), nil)
+	}
+
+	// receipts are reconstructed from the substates of the AidaDb, without it they are unverifiable
+	var substates db.SubstateDB
+	if cfg.AidaDb != "" {
+		substates, err = db.NewReadOnlySubstateDB(cfg.AidaDb)
+		if err != nil {
+			return fmt.Errorf("cannot open aida-db; %w", err)
+		}
+		defer func() {
+			err = errors.Join(err, substates.Close())
+		}()
+	}
+
+	return run(cfg, rpcSource, nil, makeRpcProcessor(cfg, substates), nil)
 }
 
-func makeRpcProcessor(cfg *utils.Config) rpcProcessor {
+func makeRpcProcessor(cfg *utils.Config, substates rpc.SubstateReader) rpcProcessor {
 	return rpcProcessor{
-		cfg: cfg,
+		cfg:       cfg,
+		substates: substates,
 	}
 }
 
 type rpcProcessor struct {
-	cfg *utils.Config
+	cfg       *utils.Config
+	substates rpc.SubstateReader // nil if no AidaDb is available
 }
 
 func (p rpcProcessor) Process(state executor.State[*rpc.RequestAndResults], ctx *executor.Context) error {
+	if state.Data.Query.MethodBase == "getTransactionReceipt" {
+		ctx.ExecutionResult = rpc.ExecuteGetTransactionReceipt(state.Data, p.substates, p.cfg.First, p.cfg.Last)
+		return nil
+	}
+
 	var err error
 	ctx.ExecutionResult, err = rpc.Execute(uint64(state.Block), state.Data, ctx.Archive, p.cfg)
 	if err != nil {
@@ -73,6 +104,61 @@ func (p rpcProcessor) Process(state executor.State[*rpc.RequestAndResults], ctx
 	return nil
 }
 
+func makeRpcFuzzProcessor(cfg *utils.Config) rpcFuzzProcessor {
+	return rpcFuzzProcessor{
+		cfg:     cfg,
+		mutator: rpc.NewMutator(cfg.RandomSeed, cfg.RpcFuzzVariants),
+	}
+}
+
+// rpcFuzzProcessor executes mutated variants of the recorded requests. Their results are
+// not compared, instead the run fails if the execution panics or the StateDB reports an error.
+type rpcFuzzProcessor struct {
+	cfg     *utils.Config
+	mutator *rpc.Mutator
+}
+
+func (p rpcFuzzProcessor) Process(state executor.State[*rpc.RequestAndResults], ctx *executor.Context) error {
+	for _, mutant := range p.mutator.Mutate(state.Data) {
+		if err := p.processMutant(state, ctx, mutant); err != nil {
+			return fmt.Errorf("block %v: %v with %v mutation failed; %w", state.Block, state.Data.Query.Method, mutant.Mutation, err)
+		}
+	}
+	return nil
+}
+
+func (p rpcFuzzProcessor) processMutant(state executor.State[*rpc.RequestAndResults], ctx *executor.Context, mutant rpc.Mutant) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("execution panicked; %v", r)
+		}
+	}()
+
+	block := state.Block
+	archive := ctx.Archive
+	if shift := mutant.Request.RequestedBlock - state.Data.RequestedBlock; shift != 0 {
+		block += shift
+		archive, err = ctx.State.GetArchiveState(uint64(mutant.Request.RequestedBlock))
+		if err != nil {
+			// blocks missing in the archive are rightfully rejected
+			return nil
+		}
+		defer func() {
+			err = errors.Join(err, archive.Release())
+		}()
+		if err = archive.BeginTransaction(uint32(state.Transaction)); err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.Join(err, archive.EndTransaction())
+		}()
+	}
+
+	// malformed requests are rightfully rejected, only errors of the StateDB itself are failures
+	_, _ = rpc.Execute(uint64(block), mutant.Request, archive, p.cfg)
+	return ctx.State.Error()
+}
+
 func run(
 	cfg *utils.Config,
 	provider executor.Provider[*rpc.RequestAndResults],
@@ -94,12 +180,10 @@ func run(
 		logger.MakeProgressLogger[*rpc.RequestAndResults](cfg, 15*time.Second),
 		logger.MakeErrorLogger[*rpc.RequestAndResults](cfg),
 		tracker.MakeRequestProgressTracker(cfg, 100_000),
-		statedb.MakeTemporaryArchivePrepper(),
-		validator.MakeRpcComparator(cfg),
 	}
 
-	// this is for testing purposes so mock statedb and mock extension can be used
-	extensionList = append(extensionList, extra...)
+	// StateDb manager precedes the archive prepper, so that cached archives
+	// are released before the StateDb is closed
 	if stateDb == nil {
 		extensionList = append(
 			extensionList,
@@ -107,9 +191,22 @@ func run(
 			statedb.MakeArchiveBlockChecker[*rpc.RequestAndResults](cfg),
 			logger.MakeDbLogger[*rpc.RequestAndResults](cfg),
 		)
+	}
+
+	extensionList = append(extensionList, statedb.MakeTemporaryArchivePrepper(cfg))
 
+	// results of mutated requests cannot be compared to the recorded ones
+	if !cfg.RpcFuzz {
+		extensionList = append(extensionList, validator.MakeRpcComparator(cfg))
 	}
 
+	// the latency profiler is the last one to receive PreTransaction and the first one
+	// to receive PostTransaction, so that it measures the execution of the request only
+	extensionList = append(extensionList, profiler.MakeRpcLatencyProfiler(cfg))
+
+	// this is for testing purposes so mock statedb and mock extension can be used
+	extensionList = append(extensionList, extra...)
+
 	return executor.NewExecutor(provider, cfg.LogLevel).Run(
 		executor.Params{
 			From:                   int(cfg.First),
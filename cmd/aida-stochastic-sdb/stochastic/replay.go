@@ -50,11 +50,13 @@ var StochasticReplayCommand = cli.Command{
 		&utils.MemoryBreakdownFlag,
 		&utils.NonceRangeFlag,
 		&utils.RandomSeedFlag,
+		&utils.ReplayWorkersFlag,
 		&utils.StateDbImplementationFlag,
 		&utils.StateDbVariantFlag,
 		&utils.DbTmpFlag,
 		&utils.StateDbLoggingFlag,
 		&utils.DeltaLoggingFlag,
+		&utils.DeltaLoggingResultsFlag,
 		&utils.TraceFileFlag,
 		&utils.TraceDebugFlag,
 		&utils.TraceFlag,
@@ -62,6 +64,7 @@ var StochasticReplayCommand = cli.Command{
 		&utils.ShadowDbVariantFlag,
 		&utils.ValidateStateHashesFlag,
 		&logger.LogLevelFlag,
+		&logger.LogLevelOverrideFlag,
 	},
 	Description: `
 The stochastic replay command requires two argument:
@@ -137,6 +140,9 @@ func stochasticReplayAction(ctx *cli.Context) error {
 		}
 		writer := bufio.NewWriter(loggerFile)
 		deltaSink = proxy.NewDeltaLogSink(log, writer, loggerFile)
+		if ctx.Bool(utils.DeltaLoggingResultsFlag.Name) {
+			deltaSink.RecordResults()
+		}
 		db = proxy.NewDeltaLoggerProxy(db, deltaSink)
 		log.Noticef("Delta logging enabled: %s", deltaLoggingPath)
 	} else if dbLoggingPath != "" {
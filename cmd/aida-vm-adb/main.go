@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/urfave/cli/v2"
@@ -37,28 +38,46 @@ var RunArchiveApp = cli.App{
 	Flags: []cli.Flag{
 		// substate
 		&utils.WorkersFlag,
+		&utils.WorkerPartitioningFlag,
 
 		// utils
 		&utils.CpuProfileFlag,
 		&utils.ChainIDFlag,
+		&utils.ForceChainIDFlag,
+		&utils.ChainConfigFileFlag,
 		&logger.LogLevelFlag,
+		&logger.LogLevelOverrideFlag,
 		&utils.StateDbLoggingFlag,
+		&utils.StateDbLoggingFormatFlag,
+		&utils.StateDbLoggingFilterFlag,
 		&utils.TrackProgressFlag,
 		&utils.NoHeartbeatLoggingFlag,
+		&utils.HeartbeatFieldsFlag,
 		&utils.ErrorLoggingFlag,
+		&utils.ErrorLoggingFormatFlag,
+		&utils.LogQueueSizeFlag,
+		&utils.LogOverflowFlag,
 
 		// StateDb
 		&utils.AidaDbFlag,
+		&utils.ProviderFlag,
 		&utils.StateDbSrcFlag,
 		&utils.ValidateTxStateFlag,
+		&utils.TxStateValidationFlag,
+		&utils.CoinbaseToleranceFlag,
 		&utils.ValidateFlag,
 
+		// Archive queries
+		&utils.QueryWorkloadFileFlag,
+		&utils.QueryWorkloadSpeedupFlag,
+
 		// ShadowDb
 		&utils.ShadowDb,
 
 		// VM
 		&utils.VmImplementation,
 		&utils.EvmImplementation,
+		&utils.ListVmsFlag,
 
 		// Encoding
 		&utils.SubstateEncodingFlag,
@@ -69,8 +88,7 @@ var RunArchiveApp = cli.App{
 // main implements vm-sdb cli.
 func main() {
 	if err := RunArchiveApp.Run(os.Args); err != nil {
-		code := 1
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(code)
+		os.Exit(executor.ExitCode(err))
 	}
 }
@@ -34,6 +34,10 @@ import (
 
 // RunVmAdb performs block processing on an ArchiveDb
 func RunVmAdb(ctx *cli.Context) error {
+	if ctx.Bool(utils.ListVmsFlag.Name) {
+		return utils.PrintVmImplementations(ctx.App.Writer)
+	}
+
 	cfg, err := utils.NewConfig(ctx, utils.BlockRangeArgs)
 	if err != nil {
 		return err
@@ -49,7 +53,7 @@ func RunVmAdb(ctx *cli.Context) error {
 		cfg.First = 1
 	}
 
-	aidaDb, err := db.NewReadOnlySubstateDB(cfg.AidaDb)
+	aidaDb, err := utils.OpenAidaDb(cfg)
 	if err != nil {
 		return fmt.Errorf("cannot open aida-db; %w", err)
 	}
@@ -57,7 +61,7 @@ func RunVmAdb(ctx *cli.Context) error {
 		err = errors.Join(err, aidaDb.Close())
 	}(aidaDb)
 
-	substateIterator, err := executor.OpenSubstateProvider(cfg, ctx, aidaDb)
+	substateIterator, err := executor.TxProviders.OpenSelected(cfg, executor.SubstateProviderName, executor.ProviderEnvironment{Cli: ctx, AidaDb: aidaDb})
 	if err != nil {
 		return err
 	}
@@ -95,6 +99,12 @@ func run(
 			logger.MakeDbLogger[txcontext.TxContext](cfg),
 		)
 	}
+	extensionList = append(extensionList, statedb.MakeArchiveWorkloadReplayer(cfg))
+
+	partitioning, err := executor.ParseBlockPartitioning(cfg.WorkerPartitioning)
+	if err != nil {
+		return err
+	}
 
 	extensionList = append(extensionList, extra...)
 	return executor.NewExecutor(provider, cfg.LogLevel).Run(
@@ -104,6 +114,7 @@ func run(
 			State:                  stateDb,
 			NumWorkers:             cfg.Workers,
 			ParallelismGranularity: executor.BlockLevel,
+			Partitioning:           partitioning,
 		},
 		processor,
 		extensionList,
@@ -646,3 +646,66 @@ var testTx = &substate.Substate{
 		GasUsed: 1,
 	},
 }
+
+func TestCmd_RunVmAdb_EmptyBlockRangeFailsArgumentValidation(t *testing.T) {
+	_, path := utils.CreateTestSubstateDb(t, db.ProtobufEncodingSchema)
+	app := cli.NewApp()
+	app.Action = RunVmAdb
+	app.Flags = []cli.Flag{
+		&utils.AidaDbFlag,
+		&utils.SubstateEncodingFlag,
+	}
+
+	err := app.Run([]string{RunArchiveApp.Name, "--aida-db", path, "--substate-encoding", "pb", "5", "4"})
+	require.ErrorIs(t, err, utils.ErrEmptyBlockRange)
+}
+
+func TestVmAdb_SingleBlockRangeExecutesThatBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := executor.NewMockProvider[txcontext.TxContext](ctrl)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+	ext := executor.NewMockExtension[txcontext.TxContext](ctrl)
+	processor := executor.NewMockProcessor[txcontext.TxContext](ctrl)
+
+	cfg := utils.NewTestConfig(t, utils.OperaMainnetChainID, 3, 3, false, "")
+	provider.EXPECT().
+		Run(3, 4, gomock.Any()).
+		DoAndReturn(func(_ int, _ int, consumer executor.Consumer[txcontext.TxContext]) error {
+			return consumer(executor.TransactionInfo[txcontext.TxContext]{Block: 3, Transaction: 1, Data: substatecontext.NewTxContext(emptyTx)})
+		})
+
+	gomock.InOrder(
+		ext.EXPECT().PreRun(executor.AtBlock[txcontext.TxContext](3), gomock.Any()),
+		db.EXPECT().GetArchiveState(uint64(2)).Return(archive, nil),
+		ext.EXPECT().PreBlock(executor.AtBlock[txcontext.TxContext](3), gomock.Any()),
+		archive.EXPECT().BeginTransaction(uint32(1)),
+		ext.EXPECT().PreTransaction(executor.AtTransaction[txcontext.TxContext](3, 1), gomock.Any()),
+		processor.EXPECT().Process(executor.AtTransaction[txcontext.TxContext](3, 1), gomock.Any()),
+		ext.EXPECT().PostTransaction(executor.AtTransaction[txcontext.TxContext](3, 1), gomock.Any()),
+		archive.EXPECT().EndTransaction(),
+		ext.EXPECT().PostBlock(executor.AtTransaction[txcontext.TxContext](3, 1), gomock.Any()),
+		archive.EXPECT().Release(),
+		ext.EXPECT().PostRun(executor.AtBlock[txcontext.TxContext](4), gomock.Any(), nil),
+	)
+
+	require.NoError(t, run(cfg, provider, db, processor, []executor.Extension[txcontext.TxContext]{ext}))
+}
+
+func TestVmAdb_RangeWithoutSubstatesSucceeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := executor.NewMockProvider[txcontext.TxContext](ctrl)
+	db := state.NewMockStateDB(ctrl)
+	ext := executor.NewMockExtension[txcontext.TxContext](ctrl)
+	processor := executor.NewMockProcessor[txcontext.TxContext](ctrl)
+
+	cfg := utils.NewTestConfig(t, utils.OperaMainnetChainID, 2, 4, false, "")
+	provider.EXPECT().Run(2, 5, gomock.Any()).Return(nil)
+
+	gomock.InOrder(
+		ext.EXPECT().PreRun(executor.AtBlock[txcontext.TxContext](2), gomock.Any()),
+		ext.EXPECT().PostRun(executor.AtBlock[txcontext.TxContext](5), gomock.Any(), nil),
+	)
+
+	require.NoError(t, run(cfg, provider, db, processor, []executor.Extension[txcontext.TxContext]{ext}))
+}
@@ -19,7 +19,9 @@ package main
 import (
 	"fmt"
 	"os"
+	"slices"
 
+	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/urfave/cli/v2"
@@ -34,6 +36,8 @@ var RunVMApp = cli.App{
 		&RunSubstateCmd,
 		&RunEthTestsCmd,
 		&RunTxGeneratorCmd,
+		&RunBisectCmd,
+		&RunKillResumeCmd,
 	},
 	Description: `
 The aida-vm-sdb command requires two arguments: <blockNumFirst> <blockNumLast>
@@ -50,39 +54,59 @@ var RunSubstateCmd = cli.Command{
 	Flags: []cli.Flag{
 		// AidaDb
 		&utils.AidaDbFlag,
+		&utils.ProviderFlag,
+		&utils.PipelineDepthFlag,
+		&utils.LazySubstateDecodingFlag,
 
 		// StateDb
 		&utils.CarmenCheckpointInterval,
 		&utils.CarmenCheckpointPeriod,
+		&utils.CarmenCheckpointTriggerFlag,
 		&utils.CarmenSchemaFlag,
 		&utils.StateDbImplementationFlag,
 		&utils.StateDbVariantFlag,
 		&utils.StateDbSrcFlag,
 		&utils.StateDbSrcOverwriteFlag,
+		&utils.ResumeFlag,
 		&utils.DbTmpFlag,
+		&utils.ExportGenesisFlag,
 		&utils.StateDbLoggingFlag,
+		&utils.StateDbLoggingFormatFlag,
+		&utils.StateDbLoggingFilterFlag,
 		&utils.DeltaLoggingFlag,
+		&utils.DeltaLoggingResultsFlag,
 		&utils.ValidateStateHashesFlag,
+		&utils.ValidateStateHashesSkipFirstFlag,
+		&utils.ValidateStateHashesIntervalFlag,
 
 		// ArchiveDb
 		&utils.ArchiveModeFlag,
 		&utils.ArchiveQueryRateFlag,
+		&utils.ArchiveQueryBurstFlag,
+		&utils.ArchiveQueryWarmUpFlag,
 		&utils.ArchiveMaxQueryAgeFlag,
 		&utils.ArchiveVariantFlag,
+		&utils.ArchiveCacheSizeFlag,
 
 		// ShadowDb
 		&utils.ShadowDb,
 		&utils.ShadowDbImplementationFlag,
 		&utils.ShadowDbVariantFlag,
+		&utils.ShadowRetryFlag,
+		&utils.CompareSchemasFlag,
+		&utils.CompareSchemasReportFlag,
 
 		// VM
 		&utils.EvmImplementation,
 		&utils.VmImplementation,
+		&utils.ListVmsFlag,
 
 		// Profiling
 		&utils.CpuProfileFlag,
 		&utils.CpuProfilePerIntervalFlag,
 		&utils.DiagnosticServerFlag,
+		&utils.OtlpEndpointFlag,
+		&utils.OtlpBlockSamplingFlag,
 		&utils.MemoryBreakdownFlag,
 		&utils.MemoryProfileFlag,
 		&utils.RandomSeedFlag,
@@ -94,32 +118,73 @@ var RunSubstateCmd = cli.Command{
 		&utils.ProfileIntervalFlag,
 		&utils.ProfileDBFlag,
 		&utils.ProfileBlocksFlag,
+		&utils.ProfileContractsFlag,
+		&utils.ProfileContractsTopFlag,
+		&utils.ExcludePrecompileCallsFlag,
+		&utils.ProfileTxTimingFlag,
+		&utils.RunBundleFlag,
+		&utils.AccessListStatsFlag,
 
 		// RegisterRun
 		&utils.RegisterRunFlag,
+		&utils.RegisterExportCsvFlag,
+		&utils.RegisterGasBucketsFlag,
+		&utils.RegisterTxBucketsFlag,
 		&utils.OverwriteRunIdFlag,
+		&utils.OutputDirFlag,
 
 		// Priming
 		&utils.RandomizePrimingFlag,
+		&utils.PrimeShuffleWindowFlag,
 		&utils.SkipPrimingFlag,
 		&utils.UpdateBufferSizeFlag,
+		&utils.UpdateBufferStrictFlag,
+		&utils.UpdateDbFlag,
+		&utils.UpdateCacheDirFlag,
+		&utils.UpdateCacheSizeFlag,
 
 		// Utils
 		&utils.WorkersFlag,
 		&utils.ChainIDFlag,
+		&utils.ForceChainIDFlag,
+		&utils.ChainConfigFileFlag,
 		&utils.ContinueOnFailureFlag,
+		&utils.SkipFailedTxFlag,
+		&utils.SkipListFlag,
 		&utils.SyncPeriodLengthFlag,
 		&utils.KeepDbFlag,
 		&utils.CustomDbNameFlag,
-		//&utils.MaxNumTransactionsFlag,
+		&utils.MaxNumTransactionsFlag,
 		&utils.ValidateTxStateFlag,
+		&utils.TxStateValidationFlag,
+		&utils.CoinbaseToleranceFlag,
+		&utils.DeepOutputCompareFlag,
+		&utils.ValidateBalanceAccountingFlag,
+		&utils.ValidateWitnessFlag,
+		&utils.ValidateDeletionsFlag,
+		&utils.SkipSanityChecksFlag,
+		&utils.RemapKeyFlag,
+		&utils.RemapStorageKeysFlag,
 		&utils.ValidateFlag,
+		&utils.BisectFlag,
+		&utils.BisectReportFlag,
 		&utils.OverwritePreWorldStateFlag,
 		&logger.LogLevelFlag,
+		&logger.LogLevelOverrideFlag,
 		&utils.NoHeartbeatLoggingFlag,
+		&utils.HeartbeatFieldsFlag,
 		&utils.TrackProgressFlag,
+		&utils.TrackIoFlag,
 		&utils.ErrorLoggingFlag,
+		&utils.ErrorLoggingFormatFlag,
+		&utils.LogQueueSizeFlag,
+		&utils.LogOverflowFlag,
+		&utils.PauseOnFailureFlag,
 		&utils.TrackerGranularityFlag,
+		&utils.TrackerEtaWindowFlag,
+		&utils.MinFreeDiskGibFlag,
+		&utils.StallTimeoutFlag,
+		&utils.StallActionFlag,
 		&utils.SubstateEncodingFlag,
 	},
 	Description: `
@@ -136,6 +201,10 @@ var RunTxGeneratorCmd = cli.Command{
 	Flags: []cli.Flag{
 		// TxGenerator specific flags
 		&utils.TxGeneratorTypeFlag,
+		&utils.TxGeneratorAccountsFlag,
+		&utils.TxGeneratorCreateRateFlag,
+		&utils.RandomSeedFlag,
+		&utils.ProviderFlag,
 
 		// StateDb
 		&utils.CarmenSchemaFlag,
@@ -145,7 +214,12 @@ var RunTxGeneratorCmd = cli.Command{
 		&utils.StateDbSrcOverwriteFlag,
 		&utils.DbTmpFlag,
 		&utils.StateDbLoggingFlag,
+		&utils.StateDbLoggingFormatFlag,
+		&utils.StateDbLoggingFilterFlag,
 		&utils.DeltaLoggingFlag,
+		&utils.DeltaLoggingResultsFlag,
+		&utils.LogQueueSizeFlag,
+		&utils.LogOverflowFlag,
 		&utils.ValidateStateHashesFlag,
 
 		// ShadowDb
@@ -155,7 +229,11 @@ var RunTxGeneratorCmd = cli.Command{
 
 		// RegisterRun
 		&utils.RegisterRunFlag,
+		&utils.RegisterExportCsvFlag,
+		&utils.RegisterGasBucketsFlag,
+		&utils.RegisterTxBucketsFlag,
 		&utils.OverwriteRunIdFlag,
+		&utils.OutputDirFlag,
 
 		// VM
 		&utils.EvmImplementation,
@@ -175,9 +253,12 @@ var RunTxGeneratorCmd = cli.Command{
 		&utils.KeepDbFlag,
 		&utils.ValidateFlag,
 		&logger.LogLevelFlag,
+		&logger.LogLevelOverrideFlag,
 		&utils.NoHeartbeatLoggingFlag,
+		&utils.HeartbeatFieldsFlag,
 		&utils.BlockLengthFlag,
 		&utils.TrackerGranularityFlag,
+		&utils.TrackerEtaWindowFlag,
 		&utils.ForkFlag,
 	},
 	Description: `
@@ -187,10 +268,55 @@ The aida-vm-sdb tx-generator command requires two arguments: <blockNumFirst> <bl
 the inclusive range of blocks.`,
 }
 
+var RunBisectCmd = cli.Command{
+	Action: RunBisect,
+	Name:   "bisect",
+	Usage:  "Finds the first block in which two configurations produce different state hashes",
+	// all flags of the substate command are shared by both configurations
+	Flags: append(slices.Clone(RunSubstateCmd.Flags),
+		&utils.BisectAFlag,
+		&utils.BisectBFlag,
+		&utils.BisectReportFlag,
+	),
+	Description: `
+The aida-vm-sdb bisect command requires two arguments: <blockNumFirst> <blockNumLast>
+
+<blockNumFirst> and <blockNumLast> are the first and last block of
+the inclusive range of blocks. The StateDb given by --db-src must contain
+the state of the block preceding <blockNumFirst>. Both configurations
+replay binary-searched sub-ranges starting from the last state they agreed
+on until the first divergent block is found.`,
+}
+
+var RunKillResumeCmd = cli.Command{
+	Action:    RunKillResume,
+	Name:      "kill-resume",
+	Usage:     "Tests that a substate run killed at random points resumes to the same final state",
+	ArgsUsage: "<blockNumFirst> <blockNumLast>",
+	Flags: []cli.Flag{
+		&utils.KillResumeArgsFlag,
+		&utils.KillCountFlag,
+		&utils.KillReportFlag,
+		&utils.CarmenCheckpointInterval,
+		&utils.RandomSeedFlag,
+		&utils.DbTmpFlag,
+		&logger.LogLevelFlag,
+		&logger.LogLevelOverrideFlag,
+	},
+	Description: `
+The aida-vm-sdb kill-resume command requires two arguments: <blockNumFirst> <blockNumLast>
+
+<blockNumFirst> and <blockNumLast> are the first and last block of
+the inclusive range of blocks. The substate run configured by --kill-resume-args
+is executed once as a reference. Then it is repeatedly killed by SIGKILL and
+restarted with --resume, until it finishes. The command fails if a restarted run
+cannot resume or if the final state hash differs from the reference run.`,
+}
+
 // main implements vm-sdb cli.
 func main() {
 	if err := RunVMApp.Run(os.Args); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(executor.ExitCode(err))
 	}
 }
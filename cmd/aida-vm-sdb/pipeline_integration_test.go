@@ -0,0 +1,79 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build integration
+
+package main
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/cmd/util-db/primer"
+	"github.com/0xsoniclabs/aida/cmd/util-db/synthetic"
+	"github.com/0xsoniclabs/aida/cmd/util-db/validate"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// TestIntegration_ReplayPipeline runs the whole replay pipeline on a generated
+// aida-db: the db is primed to the middle of the generated range, the rest of
+// the range is replayed on the primed StateDb with transaction and state-root
+// validation, and finally the aida-db itself is validated.
+func TestIntegration_ReplayPipeline(t *testing.T) {
+	const (
+		lastBlock    = 300
+		primingBlock = 151
+		stateDbName  = "primed-state-db"
+	)
+	aidaDb := filepath.Join(t.TempDir(), "aida-db")
+	dbTmp := t.TempDir()
+
+	run := func(cmd *cli.Command, args []string) {
+		app := cli.NewApp()
+		app.Commands = []*cli.Command{cmd}
+		require.NoError(t, app.Run(append([]string{"aida", cmd.Name}, args...)), "%v failed", cmd.Name)
+	}
+
+	run(&synthetic.Command, []string{
+		"--" + utils.TargetDbFlag.Name, aidaDb,
+		"--" + utils.DbTmpFlag.Name, dbTmp,
+		strconv.Itoa(lastBlock),
+	})
+
+	run(&primer.RunPrimerCmd, []string{
+		"--" + utils.AidaDbFlag.Name, aidaDb,
+		"--" + utils.DbTmpFlag.Name, dbTmp,
+		"--" + utils.CustomDbNameFlag.Name, stateDbName,
+		strconv.Itoa(primingBlock),
+	})
+
+	run(&RunSubstateCmd, []string{
+		"--" + utils.AidaDbFlag.Name, aidaDb,
+		"--" + utils.StateDbSrcFlag.Name, filepath.Join(dbTmp, stateDbName),
+		"--" + utils.DbTmpFlag.Name, dbTmp,
+		"--" + utils.ValidateTxStateFlag.Name,
+		"--" + utils.ValidateStateHashesFlag.Name,
+		strconv.Itoa(primingBlock),
+		strconv.Itoa(lastBlock),
+	})
+
+	run(&validate.Command, []string{
+		"--" + utils.AidaDbFlag.Name, aidaDb,
+	})
+}
@@ -0,0 +1,450 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+)
+
+// RunBisect searches the first block of the given range after which two configurations
+// produce different state hashes. Both configurations start from the snapshot given by
+// --db-src, which has to contain the verified state of the block preceding the range.
+func RunBisect(ctx *cli.Context) error {
+	cfg, err := utils.NewConfig(ctx, utils.BlockRangeArgs)
+	if err != nil {
+		return err
+	}
+	if cfg.StateDbSrc == "" {
+		return fmt.Errorf("bisection requires --%v with the state preceding the range", utils.StateDbSrcFlag.Name)
+	}
+	if cfg.BisectA == "" || cfg.BisectB == "" {
+		return fmt.Errorf("both --%v and --%v must be set", utils.BisectAFlag.Name, utils.BisectBFlag.Name)
+	}
+	info, err := utils.ReadStateDbInfo(cfg.StateDbSrc)
+	if err != nil {
+		return fmt.Errorf("cannot read snapshot info; %w", err)
+	}
+	if info.Block+1 != cfg.First {
+		return fmt.Errorf("snapshot contains block %d, the range must start at block %d", info.Block, info.Block+1)
+	}
+
+	var configs [2]*utils.Config
+	for i, overrides := range []string{cfg.BisectA, cfg.BisectB} {
+		configs[i], err = makeBisectConfig(ctx, overrides)
+		if err != nil {
+			return fmt.Errorf("invalid configuration %q; %w", overrides, err)
+		}
+	}
+
+	aidaDb, err := utils.OpenAidaDb(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot open aida-db; %w", err)
+	}
+	defer func(aidaDb db.BaseDB) {
+		err = errors.Join(err, aidaDb.Close())
+	}(aidaDb)
+
+	log := logger.NewLogger(cfg.LogLevel, "Bisect")
+	b := makeBisector([2]string{cfg.BisectA, cfg.BisectB}, func(side int, snapshot string, from, to uint64, extra []executor.Extension[txcontext.TxContext]) (replayResult, error) {
+		return replaySubstates(configs[side], aidaDb, snapshot, from, to, extra)
+	}, log)
+
+	report, err := b.run(cfg.StateDbSrc, cfg.First, cfg.Last)
+	if err != nil || report == nil {
+		return err
+	}
+	report.log(log)
+	if cfg.BisectReport == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal bisection report; %w", err)
+	}
+	if err = os.WriteFile(cfg.BisectReport, data, 0644); err != nil {
+		return fmt.Errorf("cannot write bisection report; %w", err)
+	}
+	log.Noticef("Bisection report: %v", cfg.BisectReport)
+	return nil
+}
+
+// makeBisectConfig creates the configuration of the bisect command with the given flags applied on top.
+func makeBisectConfig(ctx *cli.Context, overrides string) (*utils.Config, error) {
+	args := strings.Fields(overrides)
+	set := flag.NewFlagSet(ctx.Command.Name, flag.ContinueOnError)
+	// only overridden flags are defined, values of other flags are looked up in the parent context
+	names := overriddenFlagNames(args)
+	for _, f := range ctx.Command.Flags {
+		if !slices.ContainsFunc(f.Names(), func(name string) bool { return slices.Contains(names, name) }) {
+			continue
+		}
+		if err := f.Apply(set); err != nil {
+			return nil, err
+		}
+	}
+	if err := set.Parse(append(args, ctx.Args().Slice()...)); err != nil {
+		return nil, err
+	}
+
+	child := cli.NewContext(ctx.App, set, ctx)
+	child.Command = ctx.Command
+	return utils.NewConfig(child, utils.BlockRangeArgs)
+}
+
+// overriddenFlagNames returns names of flags present in the given arguments.
+func overriddenFlagNames(args []string) []string {
+	var names []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		names = append(names, name)
+	}
+	return names
+}
+
+// replayResult is the outcome of replaying a block range from a snapshot.
+type replayResult struct {
+	hash   common.Hash // state hash after the last block
+	dbPath string      // path of the resulting StateDb, owned by the caller
+	err    error       // error of the replay, the hash is not valid if set
+}
+
+// replayFunc replays the range [from, to] with the configuration of given side starting from the snapshot.
+type replayFunc func(side int, snapshot string, from, to uint64, extra []executor.Extension[txcontext.TxContext]) (replayResult, error)
+
+// replaySubstates replays the range on a copy of the snapshot, which is kept so that it can become the next snapshot.
+func replaySubstates(base *utils.Config, aidaDb db.BaseDB, snapshot string, from, to uint64, extra []executor.Extension[txcontext.TxContext]) (replayResult, error) {
+	cfg := *base
+	cfg.StateDbSrc = snapshot
+	cfg.StateDbSrcDirectAccess = false
+	cfg.First, cfg.Last = from, to
+
+	stateDb, dbPath, err := utils.PrepareStateDB(&cfg)
+	if err != nil {
+		return replayResult{}, err
+	}
+	res := replayResult{dbPath: dbPath}
+
+	provider, err := executor.TxProviders.OpenSelected(&cfg, executor.SubstateProviderName, executor.ProviderEnvironment{AidaDb: aidaDb, StateDb: stateDb})
+	if err != nil {
+		return res, errors.Join(err, stateDb.Close(), os.RemoveAll(dbPath))
+	}
+	defer provider.Close()
+
+	processor, err := executor.MakeLiveDbTxProcessor(&cfg)
+	if err != nil {
+		return res, errors.Join(err, stateDb.Close(), os.RemoveAll(dbPath))
+	}
+
+	res.err = runSubstates(&cfg, provider, stateDb, processor, extra, aidaDb)
+	if res.err == nil {
+		res.hash, res.err = stateDb.GetHash()
+	}
+	if err = stateDb.Close(); err != nil {
+		return res, errors.Join(fmt.Errorf("cannot close state-db; %w", err), os.RemoveAll(dbPath))
+	}
+	if res.err != nil {
+		return res, nil
+	}
+	if err = utils.WriteStateDbInfo(dbPath, &cfg, to, res.hash, true); err != nil {
+		return res, errors.Join(fmt.Errorf("cannot write state-db info; %w", err), os.RemoveAll(dbPath))
+	}
+	return res, nil
+}
+
+func makeBisector(names [2]string, replay replayFunc, log logger.Logger) *bisector {
+	return &bisector{
+		names:  names,
+		replay: replay,
+		log:    log,
+	}
+}
+
+// bisector narrows a range in which two configurations diverge down to its first divergent block.
+// Each step replays the first half of the remaining range from the last snapshot both configurations
+// agreed on. If they agree on the half, its resulting state becomes the next snapshot, so that each
+// block is replayed at most a logarithmic number of times.
+type bisector struct {
+	names  [2]string
+	replay replayFunc
+	log    logger.Logger
+}
+
+// run returns the report of the first divergent block of [first, last], or nil if
+// both configurations produce the same state hash at the end of the range.
+func (b *bisector) run(snapshot string, first, last uint64) (report *bisectReport, err error) {
+	b.log.Noticef("Verifying divergence of %q and %q on range [%d, %d]", b.names[0], b.names[1], first, last)
+	diverged, _, err := b.step(snapshot, first, last, false)
+	if err != nil || !diverged {
+		if err == nil {
+			b.log.Noticef("Configurations agree on range [%d, %d]", first, last)
+		}
+		return nil, err
+	}
+
+	// the snapshot holds the state after block lo-1 both configurations agree on
+	lo, hi := first, last
+	owned := false
+	defer func() {
+		if owned {
+			err = errors.Join(err, os.RemoveAll(snapshot))
+		}
+	}()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		b.log.Noticef("Bisecting range [%d, %d]; replaying blocks [%d, %d]", lo, hi, lo, mid)
+		diverged, agreed, err := b.step(snapshot, lo, mid, true)
+		if err != nil {
+			return nil, err
+		}
+		if diverged {
+			hi = mid
+			continue
+		}
+		if owned {
+			if err = os.RemoveAll(snapshot); err != nil {
+				return nil, err
+			}
+		}
+		snapshot, owned, lo = agreed, true, mid+1
+	}
+
+	b.log.Noticef("First divergent block: %d", lo)
+	return b.diffBlock(snapshot, lo)
+}
+
+// step replays [from, to] with both configurations and reports whether they diverged.
+// If they agree and keep is set, the path of the resulting state is returned.
+func (b *bisector) step(snapshot string, from, to uint64, keep bool) (bool, string, error) {
+	var results [2]replayResult
+	var err error
+	for side := range results {
+		results[side], err = b.replay(side, snapshot, from, to, nil)
+		if err != nil {
+			return false, "", errors.Join(err, removeReplays(results[:side]...))
+		}
+		if results[side].err != nil {
+			b.log.Warningf("Configuration %q failed in range [%d, %d]; %v", b.names[side], from, to, results[side].err)
+		}
+	}
+
+	diverged := results[0].err != nil || results[1].err != nil || results[0].hash != results[1].hash
+	if !diverged && keep {
+		// both states are equal, the first one is kept as the next snapshot
+		return false, results[0].dbPath, removeReplays(results[1])
+	}
+	if results[0].err == nil && results[1].err == nil {
+		b.log.Infof("State hashes after block %d; %q: %v, %q: %v", to, b.names[0], results[0].hash, b.names[1], results[1].hash)
+	}
+	return diverged, "", removeReplays(results[:]...)
+}
+
+// diffBlock replays the divergent block with both configurations and compares
+// the state of accounts accessed by each transaction.
+func (b *bisector) diffBlock(snapshot string, block uint64) (*bisectReport, error) {
+	report := &bisectReport{
+		Block:          block,
+		Configurations: b.names,
+	}
+	var recorders [2]*txStateRecorder
+	for side := range recorders {
+		recorders[side] = newTxStateRecorder()
+		res, err := b.replay(side, snapshot, block, block, []executor.Extension[txcontext.TxContext]{recorders[side]})
+		if err != nil {
+			return nil, err
+		}
+		report.Hashes[side] = res.hash
+		if res.err != nil {
+			report.Errors[side] = res.err.Error()
+		}
+		if err = removeReplays(res); err != nil {
+			return nil, err
+		}
+	}
+	report.Transactions = diffTransactions(recorders[0].states, recorders[1].states)
+	return report, nil
+}
+
+func removeReplays(results ...replayResult) error {
+	var errs []error
+	for _, res := range results {
+		if res.dbPath != "" {
+			errs = append(errs, os.RemoveAll(res.dbPath))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// bisectReport describes the first divergent block of two configurations.
+type bisectReport struct {
+	Block          uint64
+	Configurations [2]string
+	Hashes         [2]common.Hash
+	Errors         [2]string
+	Transactions   []txStateDiff
+}
+
+// txStateDiff lists the differences of the state of both configurations after a transaction.
+type txStateDiff struct {
+	Transaction int
+	Differences []string
+}
+
+func (r *bisectReport) log(log logger.Logger) {
+	log.Noticef("Block %d; %q: %v, %q: %v", r.Block, r.Configurations[0], r.Hashes[0], r.Configurations[1], r.Hashes[1])
+	for side, err := range r.Errors {
+		if err != "" {
+			log.Warningf("Configuration %q failed; %v", r.Configurations[side], err)
+		}
+	}
+	for _, tx := range r.Transactions {
+		for _, diff := range tx.Differences {
+			log.Noticef("Transaction %d/%d: %v", r.Block, tx.Transaction, diff)
+		}
+	}
+}
+
+// diffTransactions compares recorded states transaction by transaction. Transactions
+// executed by one configuration only are reported as well.
+func diffTransactions(a, b map[int]txcontext.WorldState) []txStateDiff {
+	var txs []int
+	for tx := range a {
+		txs = append(txs, tx)
+	}
+	for tx := range b {
+		if _, found := a[tx]; !found {
+			txs = append(txs, tx)
+		}
+	}
+	slices.Sort(txs)
+
+	var res []txStateDiff
+	for _, tx := range txs {
+		var differences []string
+		wsA, foundA := a[tx]
+		wsB, foundB := b[tx]
+		switch {
+		case !foundA:
+			differences = []string{"transaction was not executed by the first configuration"}
+		case !foundB:
+			differences = []string{"transaction was not executed by the second configuration"}
+		default:
+			differences = diffWorldStates(wsA, wsB)
+		}
+		if len(differences) > 0 {
+			res = append(res, txStateDiff{Transaction: tx, Differences: differences})
+		}
+	}
+	return res
+}
+
+// diffWorldStates lists all differences of accounts and storage slots recorded in both world states.
+func diffWorldStates(a, b txcontext.WorldState) []string {
+	var res []string
+	var addresses []common.Address
+	a.ForEachAccount(func(addr common.Address, _ txcontext.Account) {
+		addresses = append(addresses, addr)
+	})
+	slices.SortFunc(addresses, func(x, y common.Address) int { return bytes.Compare(x[:], y[:]) })
+
+	for _, addr := range addresses {
+		accA, accB := a.Get(addr), b.Get(addr)
+		if accB == nil {
+			continue
+		}
+		if accA.GetBalance().Cmp(accB.GetBalance()) != 0 {
+			res = append(res, fmt.Sprintf("balance of %v: %v vs %v", addr.Hex(), accA.GetBalance(), accB.GetBalance()))
+		}
+		if accA.GetNonce() != accB.GetNonce() {
+			res = append(res, fmt.Sprintf("nonce of %v: %v vs %v", addr.Hex(), accA.GetNonce(), accB.GetNonce()))
+		}
+		if !bytes.Equal(accA.GetCode(), accB.GetCode()) {
+			res = append(res, fmt.Sprintf("code of %v: %d bytes vs %d bytes", addr.Hex(), len(accA.GetCode()), len(accB.GetCode())))
+		}
+		var keys []common.Hash
+		accA.ForEachStorage(func(key common.Hash, _ common.Hash) {
+			keys = append(keys, key)
+		})
+		slices.SortFunc(keys, func(x, y common.Hash) int { return bytes.Compare(x[:], y[:]) })
+		for _, key := range keys {
+			if valueA, valueB := accA.GetStorageAt(key), accB.GetStorageAt(key); valueA != valueB {
+				res = append(res, fmt.Sprintf("storage of %v at %v: %v vs %v", addr.Hex(), key.Hex(), valueA.Hex(), valueB.Hex()))
+			}
+		}
+	}
+	return res
+}
+
+func newTxStateRecorder() *txStateRecorder {
+	return &txStateRecorder{states: make(map[int]txcontext.WorldState)}
+}
+
+// txStateRecorder records the state of all accounts and storage slots accessed
+// by each transaction, as listed in its recorded input and output alloc.
+type txStateRecorder struct {
+	extension.NilExtension[txcontext.TxContext]
+	states map[int]txcontext.WorldState
+}
+
+func (r *txStateRecorder) PostTransaction(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	keys := make(map[common.Address]map[common.Hash]struct{})
+	collect := func(addr common.Address, acc txcontext.Account) {
+		slots, found := keys[addr]
+		if !found {
+			slots = make(map[common.Hash]struct{})
+			keys[addr] = slots
+		}
+		acc.ForEachStorage(func(key common.Hash, _ common.Hash) {
+			slots[key] = struct{}{}
+		})
+	}
+	state.Data.GetInputState().ForEachAccount(collect)
+	state.Data.GetOutputState().ForEachAccount(collect)
+
+	// the transaction has already been ended, values are read within a pseudo transaction
+	if err := ctx.State.BeginTransaction(utils.PseudoTx); err != nil {
+		return fmt.Errorf("cannot begin transaction; %w", err)
+	}
+	accounts := make(map[common.Address]txcontext.Account, len(keys))
+	for addr, slots := range keys {
+		storage := make(map[common.Hash]common.Hash, len(slots))
+		for key := range slots {
+			storage[key] = ctx.State.GetState(addr, key)
+		}
+		accounts[addr] = txcontext.NewAccount(ctx.State.GetCode(addr), storage, ctx.State.GetBalance(addr).ToBig(), ctx.State.GetNonce(addr))
+	}
+	r.states[state.Transaction] = txcontext.NewWorldState(accounts)
+	return ctx.State.EndTransaction()
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReplay simulates two configurations diverging in the given block. Each replay
+// creates a directory standing for the resulting StateDb.
+type fakeReplay struct {
+	t         *testing.T
+	divergent uint64
+	failing   bool // the second configuration fails instead of producing a different hash
+	snapshots map[string]uint64
+	created   []string
+}
+
+func (f *fakeReplay) replay(side int, snapshot string, from, to uint64, extra []executor.Extension[txcontext.TxContext]) (replayResult, error) {
+	// replays must always start from the state of the preceding block
+	require.Equal(f.t, from-1, f.snapshots[snapshot], "replay of [%d, %d] started from snapshot of block %d", from, to, f.snapshots[snapshot])
+
+	dir := f.t.TempDir() + "/db"
+	require.NoError(f.t, os.Mkdir(dir, 0755))
+	f.snapshots[dir] = to
+	f.created = append(f.created, dir)
+
+	res := replayResult{dbPath: dir}
+	if side == 1 && to >= f.divergent {
+		if f.failing {
+			res.err = errors.New("injected failure")
+		} else {
+			res.hash = common.Hash{1}
+		}
+	}
+	for _, ext := range extra {
+		balance := int64(1)
+		if side == 1 {
+			balance = 2
+		}
+		ext.(*txStateRecorder).states[3] = txcontext.NewWorldState(map[common.Address]txcontext.Account{
+			{1}: txcontext.NewAccount(nil, map[common.Hash]common.Hash{}, big.NewInt(balance), 1),
+		})
+	}
+	return res, nil
+}
+
+func newFakeReplay(t *testing.T, divergent uint64) (*fakeReplay, string) {
+	snapshot := t.TempDir()
+	return &fakeReplay{
+		t:         t,
+		divergent: divergent,
+		snapshots: map[string]uint64{snapshot: 99},
+	}, snapshot
+}
+
+func TestBisector_FindsFirstDivergentBlock(t *testing.T) {
+	for _, divergent := range []uint64{100, 101, 137, 199, 200} {
+		fake, snapshot := newFakeReplay(t, divergent)
+		b := makeBisector([2]string{"a", "b"}, fake.replay, logger.NewLogger("critical", "test"))
+
+		report, err := b.run(snapshot, 100, 200)
+		require.NoError(t, err)
+		require.NotNil(t, report)
+		assert.Equal(t, divergent, report.Block)
+		assert.Equal(t, common.Hash{1}, report.Hashes[1])
+		require.Len(t, report.Transactions, 1)
+		assert.Equal(t, 3, report.Transactions[0].Transaction)
+		assert.Equal(t, []string{"balance of " + common.Address{1}.Hex() + ": 1 vs 2"}, report.Transactions[0].Differences)
+
+		// all intermediate states are removed, the original snapshot is kept
+		for _, dir := range fake.created {
+			assert.NoDirExists(t, dir)
+		}
+		assert.DirExists(t, snapshot)
+	}
+}
+
+func TestBisector_FailingReplayIsDivergence(t *testing.T) {
+	fake, snapshot := newFakeReplay(t, 150)
+	fake.failing = true
+	b := makeBisector([2]string{"a", "b"}, fake.replay, logger.NewLogger("critical", "test"))
+
+	report, err := b.run(snapshot, 100, 200)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, uint64(150), report.Block)
+	assert.Equal(t, "injected failure", report.Errors[1])
+	assert.Empty(t, report.Errors[0])
+}
+
+func TestBisector_AgreeingConfigurationsProduceNoReport(t *testing.T) {
+	fake, snapshot := newFakeReplay(t, 1000)
+	b := makeBisector([2]string{"a", "b"}, fake.replay, logger.NewLogger("critical", "test"))
+
+	report, err := b.run(snapshot, 100, 200)
+	require.NoError(t, err)
+	assert.Nil(t, report)
+	assert.Len(t, fake.created, 2)
+}
+
+func TestBisector_ReplayErrorAbortsBisection(t *testing.T) {
+	injectedErr := errors.New("injected error")
+	b := makeBisector([2]string{"a", "b"}, func(int, string, uint64, uint64, []executor.Extension[txcontext.TxContext]) (replayResult, error) {
+		return replayResult{}, injectedErr
+	}, logger.NewLogger("critical", "test"))
+
+	_, err := b.run(t.TempDir(), 100, 200)
+	require.ErrorIs(t, err, injectedErr)
+}
+
+func TestDiffWorldStates_ListsAllDifferences(t *testing.T) {
+	addr := common.Address{1}
+	a := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		addr: txcontext.NewAccount([]byte{1}, map[common.Hash]common.Hash{{1}: {1}, {2}: {2}}, big.NewInt(1), 1),
+	})
+	b := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		addr: txcontext.NewAccount([]byte{1, 2}, map[common.Hash]common.Hash{{1}: {1}, {2}: {3}}, big.NewInt(2), 2),
+	})
+
+	diffs := diffWorldStates(a, b)
+	require.Len(t, diffs, 4)
+	assert.Contains(t, diffs[0], "balance")
+	assert.Contains(t, diffs[1], "nonce")
+	assert.Contains(t, diffs[2], "code")
+	assert.Contains(t, diffs[3], common.Hash{2}.Hex())
+	assert.Empty(t, diffWorldStates(a, a))
+}
+
+func TestDiffTransactions_ReportsTransactionsOfSingleConfiguration(t *testing.T) {
+	ws := txcontext.NewWorldState(map[common.Address]txcontext.Account{})
+	diffs := diffTransactions(map[int]txcontext.WorldState{0: ws, 1: ws}, map[int]txcontext.WorldState{0: ws, 2: ws})
+	require.Len(t, diffs, 2)
+	assert.Equal(t, 1, diffs[0].Transaction)
+	assert.Contains(t, diffs[0].Differences[0], "second configuration")
+	assert.Equal(t, 2, diffs[1].Transaction)
+	assert.Contains(t, diffs[1].Differences[0], "first configuration")
+}
+
+func TestOverriddenFlagNames(t *testing.T) {
+	names := overriddenFlagNames([]string{"--evm-impl", "opera", "-vm-impl=lfvm", "--validate"})
+	assert.Equal(t, []string{"evm-impl", "vm-impl", "validate"}, names)
+}
@@ -19,6 +19,7 @@ package main
 import (
 	"fmt"
 
+	"github.com/0xsoniclabs/aida/ethtest"
 	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/executor/extension/logger"
 	"github.com/0xsoniclabs/aida/executor/extension/primer"
@@ -39,13 +40,19 @@ var RunEthTestsCmd = cli.Command{
 	ArgsUsage: "<blockNumFirst> <blockNumLast>",
 	Aliases:   []string{"ethtest"},
 	Flags: []cli.Flag{
+		&utils.ProviderFlag,
+
 		// StateDb
 		&utils.CarmenSchemaFlag,
 		&utils.StateDbImplementationFlag,
 		&utils.StateDbVariantFlag,
 		&utils.DbTmpFlag,
+		&utils.OutputDirFlag,
 		&utils.StateDbLoggingFlag,
+		&utils.StateDbLoggingFormatFlag,
+		&utils.StateDbLoggingFilterFlag,
 		&utils.DeltaLoggingFlag,
+		&utils.DeltaLoggingResultsFlag,
 
 		//// ShadowDb
 		&utils.ShadowDb,
@@ -72,12 +79,18 @@ var RunEthTestsCmd = cli.Command{
 		&utils.ValidateFlag,
 		&utils.ValidateStateHashesFlag,
 		&log.LogLevelFlag,
+		&log.LogLevelOverrideFlag,
 		&utils.ErrorLoggingFlag,
+		&utils.ErrorLoggingFormatFlag,
+		&utils.LogQueueSizeFlag,
+		&utils.LogOverflowFlag,
 		&utils.MaxNumErrorsFlag,
 
 		// Ethereum execution tests
 		&utils.EthTestTypeFlag,
 		&utils.ForkFlag,
+		&utils.ForksFlag,
+		&utils.ReportFileFlag,
 	},
 	Description: `
 The aida-vm-sdb geth-state-tests command requires one argument: <pathToJsonTest or pathToDirWithJsonTests>`,
@@ -93,7 +106,7 @@ func RunEthereumTest(ctx *cli.Context) error {
 	cfg.StateValidationMode = utils.SubsetCheck
 	cfg.ValidateTxState = true
 
-	processor, err := executor.MakeEthTestProcessor(cfg)
+	processor, err := makeEthTestProcessor(cfg)
 	if err != nil {
 		return err
 	}
@@ -101,7 +114,23 @@ func RunEthereumTest(ctx *cli.Context) error {
 		return fmt.Errorf("please specify chain ID using --%s flag (1337 for most cases for this tool)", utils.ChainIDFlag.Name)
 	}
 
-	return runEth(cfg, executor.NewEthStateTestProvider(cfg), nil, processor, nil)
+	results := ethtest.NewResultMatrix()
+	provider, err := executor.TxProviders.OpenSelected(cfg, executor.EthTestProviderName, executor.ProviderEnvironment{Cli: ctx, EthTestResults: results})
+	if err != nil {
+		return err
+	}
+
+	return runEth(cfg, provider, nil, processor, []executor.Extension[txcontext.TxContext]{
+		logger.MakeEthTestResultReporter(cfg, results),
+	})
+}
+
+// makeEthTestProcessor creates a processor for the type of ethereum tests selected by the user.
+func makeEthTestProcessor(cfg *utils.Config) (executor.Processor[txcontext.TxContext], error) {
+	if cfg.EthTestType == utils.BlockTests {
+		return executor.MakeEthBlockchainTestProcessor(cfg)
+	}
+	return executor.MakeEthTestProcessor(cfg)
 }
 
 func runEth(
@@ -134,11 +163,24 @@ func runEth(
 		logger.MakeEthStateTestLogger(cfg, 0),
 		validator.MakeShadowDbValidator(cfg),
 		validator.MakeEthStateTestStateHashValidator(cfg),
-		statedb.MakeEthStateScopeTestEventEmitter(),
-		validator.MakeEthStateTestErrorValidator(cfg),
-		validator.MakeEthStateTestLogHashValidator(cfg),
 	)
 
+	if cfg.EthTestType == utils.BlockTests {
+		// blockchain tests consist of multiple blocks, hence scopes are opened by the processor
+		extensionList = append(
+			extensionList,
+			validator.MakeEthStateTestErrorValidator(cfg),
+			validator.MakeEthBlockchainTestPostStateValidator(cfg),
+		)
+	} else {
+		extensionList = append(
+			extensionList,
+			statedb.MakeEthStateScopeTestEventEmitter(),
+			validator.MakeEthStateTestErrorValidator(cfg),
+			validator.MakeEthStateTestLogHashValidator(cfg),
+		)
+	}
+
 	extensionList = append(extensionList, extra...)
 
 	return executor.NewExecutor(provider, cfg.LogLevel).Run(
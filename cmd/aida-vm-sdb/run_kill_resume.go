@@ -0,0 +1,451 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+)
+
+// trackReportPrefix starts the progress report the block progress tracker logs after each finished block.
+const trackReportPrefix = "Track: block "
+
+// RunKillResume measures whether a run survives crashes by resuming from the checkpoints of its StateDb.
+// A reference run processes the whole range in a single pass. The same run is then repeatedly killed
+// by SIGKILL at random points and resumed until it is allowed to finish. The test fails if a resumed
+// run fails or does not continue where the previous one left off, or if its final state hash differs
+// from the reference one.
+func RunKillResume(ctx *cli.Context) error {
+	cfg, err := utils.NewConfig(ctx, utils.BlockRangeArgs)
+	if err != nil {
+		return err
+	}
+	if cfg.KillResumeArgs == "" {
+		return fmt.Errorf("--%v must be set", utils.KillResumeArgsFlag.Name)
+	}
+	if cfg.KillCount < 1 {
+		return fmt.Errorf("--%v must be positive", utils.KillCountFlag.Name)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot find executable; %w", err)
+	}
+	workDir, err := os.MkdirTemp(cfg.DbTmp, "kill_resume_*")
+	if err != nil {
+		return fmt.Errorf("cannot create working directory; %w", err)
+	}
+
+	log := logger.NewLogger(cfg.LogLevel, "Kill-Resume")
+	h := makeKillResumeHarness(cfg, startProcess(exe), workDir, log)
+	report, err := h.run()
+	if err != nil {
+		return fmt.Errorf("%w; logs are kept in %v", err, workDir)
+	}
+	report.log(log)
+	if cfg.KillReport != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("cannot marshal kill and resume report; %w", err)
+		}
+		if err = os.WriteFile(cfg.KillReport, data, 0644); err != nil {
+			return fmt.Errorf("cannot write kill and resume report; %w", err)
+		}
+	}
+	if !report.Passed {
+		return fmt.Errorf("kill and resume test failed with %d failure(s); logs and StateDbs are kept in %v", len(report.Failures), workDir)
+	}
+	return os.RemoveAll(workDir)
+}
+
+// child is a started run of the tested command.
+type child interface {
+	Kill() error
+	Wait() error
+}
+
+// startFunc starts the tested command with the given arguments. Its output is written
+// to the log and passed line by line to onLine.
+type startFunc func(args []string, log io.Writer, onLine func(string)) (child, error)
+
+// startProcess starts the tested command as a child process of the given executable.
+func startProcess(exe string) startFunc {
+	return func(args []string, log io.Writer, onLine func(string)) (child, error) {
+		reader, writer := io.Pipe()
+		cmd := exec.Command(exe, args...)
+		cmd.Stdout = io.MultiWriter(log, writer)
+		cmd.Stderr = cmd.Stdout
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			scanner := bufio.NewScanner(reader)
+			for scanner.Scan() {
+				onLine(scanner.Text())
+			}
+			// drain the rest of the output if a line exceeded the buffer
+			_, _ = io.Copy(io.Discard, reader)
+		}()
+		return &process{cmd: cmd, writer: writer, done: done}, nil
+	}
+}
+
+type process struct {
+	cmd    *exec.Cmd
+	writer *io.PipeWriter
+	done   chan struct{}
+}
+
+// Kill sends SIGKILL to the process.
+func (p *process) Kill() error {
+	return p.cmd.Process.Kill()
+}
+
+// Wait waits until the process exits and all its output is consumed.
+func (p *process) Wait() error {
+	err := p.cmd.Wait()
+	_ = p.writer.Close()
+	<-p.done
+	return err
+}
+
+// killTrigger says when a run is killed.
+type killTrigger string
+
+const (
+	triggerNone       killTrigger = "none"       // the run is allowed to finish
+	triggerEndBlock   killTrigger = "end-block"  // killed right after the end of a block
+	triggerCheckpoint killTrigger = "checkpoint" // killed while a block at a checkpoint interval is processed
+	triggerTimer      killTrigger = "timer"      // killed after a random delay
+)
+
+// killPlan describes when a run is killed.
+type killPlan struct {
+	trigger killTrigger
+	block   uint64        // block whose end (end-block) or processing (checkpoint) triggers the kill
+	delay   time.Duration // delay of the kill after the trigger
+}
+
+// killResumeRun is a single run of the tested command.
+type killResumeRun struct {
+	Name      string      `json:"name"`
+	Trigger   killTrigger `json:"trigger"`
+	ResumedAt int64       `json:"resumedAt"` // block at which the run continued, -1 if not reported
+	LastBlock int64       `json:"lastBlock"` // last block reported as finished, -1 if none
+	Killed    bool        `json:"killed"`
+	Err       string      `json:"error,omitempty"`
+	Log       string      `json:"log"`
+}
+
+// killResumeReport is the outcome of the kill and resume test.
+type killResumeReport struct {
+	Seed          int64           `json:"seed"`
+	Kills         int             `json:"kills"`
+	ReferenceHash common.Hash     `json:"referenceHash"`
+	FinalHash     common.Hash     `json:"finalHash"`
+	Runs          []killResumeRun `json:"runs"`
+	Failures      []string        `json:"failures"`
+	Passed        bool            `json:"passed"`
+}
+
+func (r *killResumeReport) fail(format string, args ...any) {
+	r.Failures = append(r.Failures, fmt.Sprintf(format, args...))
+}
+
+func (r *killResumeReport) log(log logger.Logger) {
+	log.Noticef("Runs: %d, kills: %d, seed: %d", len(r.Runs), r.Kills, r.Seed)
+	log.Noticef("Reference hash: %v, final hash: %v", r.ReferenceHash, r.FinalHash)
+	for _, failure := range r.Failures {
+		log.Errorf("Failure: %v", failure)
+	}
+	for _, run := range r.Runs {
+		if run.Err != "" {
+			log.Errorf("Log of failed %v: %v", run.Name, run.Log)
+		}
+	}
+	if r.Passed {
+		log.Noticef("Kill and resume test passed")
+	}
+}
+
+func makeKillResumeHarness(cfg *utils.Config, start startFunc, workDir string, log logger.Logger) *killResumeHarness {
+	h := &killResumeHarness{
+		start:    start,
+		args:     strings.Fields(cfg.KillResumeArgs),
+		first:    cfg.First,
+		last:     cfg.Last,
+		kills:    cfg.KillCount,
+		interval: uint64(max(cfg.CarmenCheckpointInterval, 0)),
+		seed:     cfg.RandomSeed,
+		rand:     rand.New(rand.NewSource(cfg.RandomSeed)),
+		workDir:  workDir,
+		log:      log,
+	}
+	h.plan = h.planKill
+	return h
+}
+
+// killResumeHarness repeatedly kills and resumes the substate command of this executable.
+type killResumeHarness struct {
+	start    startFunc
+	args     []string // flags of the tested substate run
+	first    uint64
+	last     uint64
+	kills    int
+	interval uint64 // Carmen checkpoint interval in blocks, 0 if not set
+	seed     int64
+	rand     *rand.Rand
+	workDir  string
+	log      logger.Logger
+
+	plan      func(next uint64, killsLeft int) killPlan
+	runTime   time.Duration // duration of the reference run
+	blockTime time.Duration // average duration of a block in the reference run
+}
+
+func (h *killResumeHarness) run() (*killResumeReport, error) {
+	report := &killResumeReport{Seed: h.seed}
+
+	refPath := filepath.Join(h.workDir, "reference")
+	h.log.Noticef("Reference run of blocks %d-%d", h.first, h.last)
+	start := time.Now()
+	ref, err := h.runOnce("reference", refPath, killPlan{trigger: triggerNone})
+	if err != nil {
+		return nil, err
+	}
+	if ref.Err != "" {
+		return nil, fmt.Errorf("reference run failed; %v", ref.Err)
+	}
+	h.runTime = time.Since(start)
+	h.blockTime = h.runTime / time.Duration(h.last-h.first+1)
+	refInfo, err := utils.ReadStateDbInfo(refPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read result of reference run; %w", err)
+	}
+	report.ReferenceHash = refInfo.RootHash
+	if err = os.RemoveAll(refPath); err != nil {
+		return nil, err
+	}
+
+	dbPath := filepath.Join(h.workDir, "resumed")
+	next := h.first        // first block not reported as finished yet
+	resumedAt := int64(-1) // block at which the previous run continued
+	for i := 0; ; i++ {
+		plan := killPlan{trigger: triggerNone}
+		if report.Kills < h.kills && next <= h.last {
+			plan = h.plan(next, h.kills-report.Kills)
+		}
+		run, err := h.runOnce(fmt.Sprintf("run-%d", i), dbPath, plan)
+		if err != nil {
+			return nil, err
+		}
+		report.Runs = append(report.Runs, run)
+		h.log.Infof("%v (%v) resumed at block %d, finished block %d, killed: %v", run.Name, run.Trigger, run.ResumedAt, run.LastBlock, run.Killed)
+
+		if run.Err != "" {
+			if run.LastBlock < 0 {
+				report.fail("%v failed to resume; %v", run.Name, run.Err)
+			} else {
+				report.fail("%v failed after block %d; %v", run.Name, run.LastBlock, run.Err)
+			}
+			break
+		}
+		if run.ResumedAt >= 0 {
+			// a block is durable at the latest at the end of the next one
+			if run.ResumedAt < resumedAt {
+				report.fail("%v resumed at block %d, before block %d at which the previous run was resumed", run.Name, run.ResumedAt, resumedAt)
+			}
+			if uint64(run.ResumedAt) > next+1 {
+				report.fail("%v resumed at block %d, although only blocks before %d were finished", run.Name, run.ResumedAt, next)
+			}
+			resumedAt = run.ResumedAt
+		}
+		if run.LastBlock >= 0 {
+			next = max(next, uint64(run.LastBlock)+1)
+		}
+		if !run.Killed {
+			break
+		}
+		report.Kills++
+	}
+	if report.Kills < h.kills {
+		h.log.Warningf("Run was killed only %d times instead of %d", report.Kills, h.kills)
+	}
+
+	if len(report.Failures) == 0 {
+		info, err := utils.ReadStateDbInfo(dbPath)
+		switch {
+		case err != nil:
+			report.fail("cannot read result of resumed run; %v", err)
+		case !info.HasFinished || info.Block != h.last:
+			report.fail("resumed run did not finish; last block %d, finished: %v", info.Block, info.HasFinished)
+		default:
+			report.FinalHash = info.RootHash
+			if report.FinalHash != report.ReferenceHash {
+				report.fail("final state hash %v differs from reference hash %v", report.FinalHash, report.ReferenceHash)
+			}
+		}
+	}
+	report.Passed = len(report.Failures) == 0
+	return report, nil
+}
+
+// planKill chooses when the next run is killed. Kills are spread over the remaining blocks
+// and weighted towards the ends of blocks and the blocks at which Carmen creates checkpoints.
+func (h *killResumeHarness) planKill(next uint64, killsLeft int) killPlan {
+	remaining := h.last - next + 1
+	offset := uint64(h.rand.Int63n(int64(max(2*remaining/uint64(killsLeft+1), 1))))
+	block := next + offset
+
+	r := h.rand.Float64()
+	switch {
+	case r < 0.4:
+		return killPlan{trigger: triggerEndBlock, block: block}
+	case r < 0.8 && h.interval > 0:
+		checkpoint := (block/h.interval + 1) * h.interval
+		if checkpoint > h.last {
+			return killPlan{trigger: triggerEndBlock, block: block}
+		}
+		// the kill hits the checkpoint block while it is processed or committed
+		return killPlan{trigger: triggerCheckpoint, block: checkpoint, delay: h.randomDelay(h.blockTime)}
+	default:
+		return killPlan{trigger: triggerTimer, delay: h.randomDelay(2 * h.runTime / time.Duration(killsLeft+1))}
+	}
+}
+
+func (h *killResumeHarness) randomDelay(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return time.Duration(h.rand.Int63n(int64(limit)))
+}
+
+// runOnce runs the tested command on the StateDb in the given directory and kills it according to the plan.
+func (h *killResumeHarness) runOnce(name, dbPath string, plan killPlan) (killResumeRun, error) {
+	run := killResumeRun{
+		Name:      name,
+		Trigger:   plan.trigger,
+		ResumedAt: -1,
+		LastBlock: -1,
+		Log:       filepath.Join(h.workDir, name+".log"),
+	}
+	file, err := os.Create(run.Log)
+	if err != nil {
+		return run, fmt.Errorf("cannot create log file; %w", err)
+	}
+	defer file.Close()
+
+	var (
+		lock   sync.Mutex
+		c      child
+		killed bool
+		armed  bool // whether the kill of a checkpoint plan is scheduled
+	)
+	kill := func() {
+		lock.Lock()
+		defer lock.Unlock()
+		if !killed && c.Kill() == nil {
+			killed = true
+		}
+	}
+	onLine := func(line string) {
+		if block, found := parseReportedBlock(line, strings.TrimSuffix(utils.ResumeReportFormat, "%d")); found {
+			run.ResumedAt = int64(block)
+		}
+		block, found := parseReportedBlock(line, trackReportPrefix)
+		if !found {
+			return
+		}
+		run.LastBlock = int64(block)
+		switch {
+		case plan.trigger == triggerEndBlock && block >= plan.block:
+			kill()
+		case plan.trigger == triggerCheckpoint && block+1 >= plan.block && !armed:
+			armed = true
+			time.AfterFunc(plan.delay, kill)
+		}
+	}
+
+	lock.Lock()
+	c, err = h.start(h.childArgs(dbPath), file, onLine)
+	lock.Unlock()
+	if err != nil {
+		return run, fmt.Errorf("cannot start %v; %w", name, err)
+	}
+	if plan.trigger == triggerTimer {
+		timer := time.AfterFunc(plan.delay, kill)
+		defer timer.Stop()
+	}
+
+	err = c.Wait()
+	lock.Lock()
+	defer lock.Unlock()
+	// a kill may come too late if the run has just finished
+	run.Killed = killed && err != nil
+	if err != nil && !run.Killed {
+		run.Err = err.Error()
+	}
+	// prevent delayed kills from hitting a finished run
+	killed = true
+	return run, nil
+}
+
+// childArgs returns the arguments of the substate command resuming the run on the given StateDb.
+func (h *killResumeHarness) childArgs(dbPath string) []string {
+	args := append([]string{RunSubstateCmd.Name}, h.args...)
+	args = append(args,
+		"--"+utils.ResumeFlag.Name,
+		"--"+utils.StateDbSrcFlag.Name, dbPath,
+		"--"+utils.DbTmpFlag.Name, h.workDir,
+		"--"+utils.TrackProgressFlag.Name,
+		fmt.Sprintf("--%v=1", utils.TrackerGranularityFlag.Name),
+	)
+	if h.interval > 0 {
+		args = append(args, fmt.Sprintf("--%v=%d", utils.CarmenCheckpointInterval.Name, h.interval))
+	}
+	return append(args, strconv.FormatUint(h.first, 10), strconv.FormatUint(h.last, 10))
+}
+
+// parseReportedBlock returns the block number following the prefix in a log line.
+func parseReportedBlock(line, prefix string) (uint64, bool) {
+	_, rest, found := strings.Cut(line, prefix)
+	if !found {
+		return 0, false
+	}
+	var block uint64
+	if _, err := fmt.Sscanf(rest, "%d", &block); err != nil {
+		return 0, false
+	}
+	return block, true
+}
@@ -0,0 +1,222 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRuns simulates runs of the substate command resuming from checkpoints created
+// at multiples of the checkpoint interval.
+type fakeRuns struct {
+	t           *testing.T
+	first, last uint64
+	interval    uint64
+	durable     map[string]uint64 // last durable block per StateDb
+	hashes      map[string]common.Hash
+	failing     int // number of the started run, counted from 1, which fails to resume; 0 if none
+	started     int
+}
+
+func newFakeRuns(t *testing.T, first, last, interval uint64) *fakeRuns {
+	return &fakeRuns{
+		t:        t,
+		first:    first,
+		last:     last,
+		interval: interval,
+		durable:  make(map[string]uint64),
+		hashes:   make(map[string]common.Hash),
+	}
+}
+
+type fakeChild struct {
+	once   sync.Once
+	killed chan struct{}
+	done   chan error
+}
+
+func (c *fakeChild) Kill() error {
+	c.once.Do(func() { close(c.killed) })
+	return nil
+}
+
+func (c *fakeChild) Wait() error {
+	return <-c.done
+}
+
+func (f *fakeRuns) start(args []string, log io.Writer, onLine func(string)) (child, error) {
+	require.Equal(f.t, RunSubstateCmd.Name, args[0])
+	require.Contains(f.t, args, "--"+utils.ResumeFlag.Name)
+	dbPath := args[slices.Index(args, "--"+utils.StateDbSrcFlag.Name)+1]
+
+	f.started++
+	failing := f.started == f.failing
+	c := &fakeChild{killed: make(chan struct{}), done: make(chan error, 1)}
+	go func() {
+		if failing {
+			_, _ = fmt.Fprintln(log, "cannot restore StateDb")
+			c.done <- errors.New("exit status 1")
+			return
+		}
+		next := f.first
+		if durable, found := f.durable[dbPath]; found {
+			next = durable + 1
+		}
+		onLine("NOTICE Resume " + fmt.Sprintf(utils.ResumeReportFormat, next))
+		for block := next; block <= f.last; block++ {
+			select {
+			case <-c.killed:
+				c.done <- errors.New("signal: killed")
+				return
+			default:
+			}
+			if block%f.interval == 0 {
+				f.durable[dbPath] = block
+			}
+			onLine(fmt.Sprintf("NOTICE ProgressTracker %v%d, memory 0", trackReportPrefix, block))
+		}
+		assert.NoError(f.t, os.MkdirAll(dbPath, 0755))
+		assert.NoError(f.t, utils.WriteStateDbInfo(dbPath, &utils.Config{}, f.last, f.hashes[filepath.Base(dbPath)], true))
+		c.done <- nil
+	}()
+	return c, nil
+}
+
+func makeTestKillResumeHarness(t *testing.T, runs *fakeRuns, kills int) *killResumeHarness {
+	cfg := &utils.Config{
+		First:                    runs.first,
+		Last:                     runs.last,
+		KillCount:                kills,
+		KillResumeArgs:           "--aida-db aida-db --archive",
+		CarmenCheckpointInterval: int(runs.interval),
+		RandomSeed:               7,
+	}
+	h := makeKillResumeHarness(cfg, runs.start, t.TempDir(), logger.NewLogger("critical", "test"))
+	// each run is killed two blocks after the last finished block
+	h.plan = func(next uint64, _ int) killPlan {
+		return killPlan{trigger: triggerEndBlock, block: next + 2}
+	}
+	return h
+}
+
+func TestKillResume_PassesIfResumedRunReachesReferenceHash(t *testing.T) {
+	runs := newFakeRuns(t, 1, 20, 5)
+	runs.hashes["reference"] = common.Hash{1}
+	runs.hashes["resumed"] = common.Hash{1}
+
+	report, err := makeTestKillResumeHarness(t, runs, 3).run()
+	require.NoError(t, err)
+	assert.True(t, report.Passed, "failures: %v", report.Failures)
+	assert.Equal(t, 3, report.Kills)
+	require.Len(t, report.Runs, 4)
+
+	// run-0 is killed at block 3 before any checkpoint, run-1 at block 6 after the checkpoint of block 5
+	assert.Equal(t, []int64{1, 1, 6, 6}, []int64{report.Runs[0].ResumedAt, report.Runs[1].ResumedAt, report.Runs[2].ResumedAt, report.Runs[3].ResumedAt})
+	assert.Equal(t, []int64{3, 6, 9, 20}, []int64{report.Runs[0].LastBlock, report.Runs[1].LastBlock, report.Runs[2].LastBlock, report.Runs[3].LastBlock})
+	assert.False(t, report.Runs[3].Killed)
+	assert.Equal(t, common.Hash{1}, report.FinalHash)
+}
+
+func TestKillResume_DetectsDifferentFinalHash(t *testing.T) {
+	runs := newFakeRuns(t, 1, 20, 5)
+	runs.hashes["reference"] = common.Hash{1}
+	runs.hashes["resumed"] = common.Hash{2}
+
+	report, err := makeTestKillResumeHarness(t, runs, 2).run()
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+	require.Len(t, report.Failures, 1)
+	assert.Contains(t, report.Failures[0], "differs from reference hash")
+}
+
+func TestKillResume_DetectsFailedResume(t *testing.T) {
+	runs := newFakeRuns(t, 1, 20, 5)
+	runs.failing = 3 // reference, run-0, run-1
+
+	report, err := makeTestKillResumeHarness(t, runs, 3).run()
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+	require.Len(t, report.Failures, 1)
+	assert.Contains(t, report.Failures[0], "run-1 failed to resume")
+	require.Len(t, report.Runs, 2)
+	log, err := os.ReadFile(report.Runs[1].Log)
+	require.NoError(t, err)
+	assert.Contains(t, string(log), "cannot restore StateDb")
+}
+
+func TestKillResume_FailsIfReferenceRunFails(t *testing.T) {
+	runs := newFakeRuns(t, 1, 20, 5)
+	runs.failing = 1
+
+	_, err := makeTestKillResumeHarness(t, runs, 3).run()
+	require.ErrorContains(t, err, "reference run failed")
+}
+
+func TestKillResume_PlanKillStaysWithinRange(t *testing.T) {
+	for _, interval := range []int{0, 7} {
+		cfg := &utils.Config{First: 10, Last: 100, KillCount: 5, CarmenCheckpointInterval: interval, RandomSeed: 1}
+		h := makeKillResumeHarness(cfg, nil, t.TempDir(), logger.NewLogger("critical", "test"))
+		for i := 0; i < 1000; i++ {
+			plan := h.planKill(40, 3)
+			switch plan.trigger {
+			case triggerEndBlock:
+				assert.True(t, plan.block >= 40 && plan.block <= 100, "block %d out of range", plan.block)
+			case triggerCheckpoint:
+				require.NotZero(t, interval)
+				assert.Zero(t, plan.block%7)
+				assert.True(t, plan.block > 40 && plan.block <= 100, "block %d out of range", plan.block)
+			case triggerTimer:
+			default:
+				t.Fatalf("unexpected trigger %v", plan.trigger)
+			}
+		}
+	}
+}
+
+func TestKillResume_ChildArgsResumeOnGivenStateDb(t *testing.T) {
+	cfg := &utils.Config{First: 10, Last: 100, KillResumeArgs: "--aida-db path --archive", CarmenCheckpointInterval: 50}
+	h := makeKillResumeHarness(cfg, nil, "work", logger.NewLogger("critical", "test"))
+
+	args := h.childArgs("db")
+	assert.Equal(t, []string{
+		"substate", "--aida-db", "path", "--archive",
+		"--resume", "--db-src", "db", "--db-tmp", "work", "--track-progress", "--tracker-granularity=1",
+		"--carmen-checkpoint-interval=50", "10", "100",
+	}, args)
+}
+
+func TestParseReportedBlock(t *testing.T) {
+	block, found := parseReportedBlock("12:00 NOTICE ProgressTracker Track: block 42, memory 1", trackReportPrefix)
+	assert.True(t, found)
+	assert.Equal(t, uint64(42), block)
+
+	_, found = parseReportedBlock("12:00 NOTICE Resume other line", trackReportPrefix)
+	assert.False(t, found)
+}
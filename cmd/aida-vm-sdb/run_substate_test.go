@@ -373,3 +373,68 @@ var testTx = &substate.Substate{
 		GasUsed: 118900,
 	},
 }
+
+func TestCmd_RunSubstate_EmptyBlockRangeFailsArgumentValidation(t *testing.T) {
+	_, path := utils.CreateTestSubstateDb(t, db.ProtobufEncodingSchema)
+	app := cli.NewApp()
+	app.Action = RunSubstate
+	app.Flags = []cli.Flag{
+		&utils.AidaDbFlag,
+		&utils.SubstateEncodingFlag,
+	}
+
+	err := app.Run([]string{RunSubstateCmd.Name, "--aida-db", path, "--substate-encoding", "pb", "5", "4"})
+	require.ErrorIs(t, err, utils.ErrEmptyBlockRange)
+}
+
+func TestVmSdb_Substate_SingleBlockRangeExecutesThatBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := executor.NewMockProvider[txcontext.TxContext](ctrl)
+	db := state.NewMockStateDB(ctrl)
+	ext := executor.NewMockExtension[txcontext.TxContext](ctrl)
+	processor := executor.NewMockProcessor[txcontext.TxContext](ctrl)
+
+	cfg := utils.NewTestConfig(t, utils.OperaMainnetChainID, 3, 3, false, "")
+	provider.EXPECT().
+		Run(3, 4, gomock.Any()).
+		DoAndReturn(func(_ int, _ int, consumer executor.Consumer[txcontext.TxContext]) error {
+			return consumer(executor.TransactionInfo[txcontext.TxContext]{Block: 3, Transaction: 1, Data: substatecontext.NewTxContext(emptyTx)})
+		})
+
+	gomock.InOrder(
+		ext.EXPECT().PreRun(executor.AtBlock[txcontext.TxContext](3), gomock.Any()),
+		ext.EXPECT().PreBlock(executor.AtBlock[txcontext.TxContext](3), gomock.Any()),
+		db.EXPECT().BeginBlock(uint64(3)),
+		ext.EXPECT().PreTransaction(executor.AtTransaction[txcontext.TxContext](3, 1), gomock.Any()),
+		db.EXPECT().PrepareSubstate(gomock.Any(), uint64(3)),
+		db.EXPECT().BeginTransaction(uint32(1)),
+		processor.EXPECT().Process(executor.AtTransaction[txcontext.TxContext](3, 1), gomock.Any()),
+		db.EXPECT().EndTransaction(),
+		ext.EXPECT().PostTransaction(executor.AtTransaction[txcontext.TxContext](3, 1), gomock.Any()),
+		db.EXPECT().EndBlock(),
+		ext.EXPECT().PostBlock(executor.AtTransaction[txcontext.TxContext](3, 1), gomock.Any()),
+		ext.EXPECT().PostRun(executor.AtBlock[txcontext.TxContext](4), gomock.Any(), nil),
+	)
+
+	require.NoError(t, runSubstates(cfg, provider, db, processor, []executor.Extension[txcontext.TxContext]{ext}, nil))
+}
+
+func TestVmSdb_Substate_RangeWithoutSubstatesSucceeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := executor.NewMockProvider[txcontext.TxContext](ctrl)
+	db := state.NewMockStateDB(ctrl)
+	ext := executor.NewMockExtension[txcontext.TxContext](ctrl)
+	processor := executor.NewMockProcessor[txcontext.TxContext](ctrl)
+
+	cfg := utils.NewTestConfig(t, utils.OperaMainnetChainID, 2, 4, false, "")
+	cfg.TrackProgress = true
+	provider.EXPECT().Run(2, 5, gomock.Any()).Return(nil)
+
+	gomock.InOrder(
+		ext.EXPECT().PreRun(executor.AtBlock[txcontext.TxContext](2), gomock.Any()),
+		ext.EXPECT().PostRun(executor.AtBlock[txcontext.TxContext](5), gomock.Any(), nil),
+	)
+	db.EXPECT().GetMemoryUsage().AnyTimes()
+
+	require.NoError(t, runSubstates(cfg, provider, db, processor, []executor.Extension[txcontext.TxContext]{ext}, nil))
+}
@@ -53,7 +53,10 @@ func RunTxGenerator(ctx *cli.Context) error {
 		return err
 	}
 
-	provider := executor.NewNormaTxProvider(cfg, db)
+	provider, err := executor.TxProviders.OpenSelected(cfg, executor.TxGeneratorProviderName, executor.ProviderEnvironment{Cli: ctx, StateDb: db})
+	if err != nil {
+		return err
+	}
 
 	processor, err := executor.MakeLiveDbTxProcessor(cfg)
 	if err != nil {
@@ -80,6 +83,7 @@ func runTransactions(
 	// order of extensionList has to be maintained
 	var extensionList = []executor.Extension[txcontext.TxContext]{
 		profiler.MakeVirtualMachineStatisticsPrinter[txcontext.TxContext](cfg),
+		register.MakeRunManifestWriter[txcontext.TxContext](cfg),
 		statedb.MakeStateDbManager[txcontext.TxContext](cfg, stateDbPath),
 		register.MakeRegisterProgress(cfg,
 			progressReportFrequency,
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/urfave/cli/v2"
@@ -38,32 +39,55 @@ var runVmApp = &cli.App{
 		//&substate.SkipCallTxsFlag,
 		//&substate.SkipCreateTxsFlag,
 		&utils.ChainIDFlag,
+		&utils.ForceChainIDFlag,
+		&utils.ChainConfigFileFlag,
 		//&utils.ProfileEVMCallFlag,
-		//&utils.MicroProfilingFlag,
+		&utils.MicroProfilingFlag,
 		//&utils.BasicBlockProfilingFlag,
-		//&utils.ProfilingDbNameFlag,
+		&utils.ProfilingDbNameFlag,
 		&utils.ChannelBufferSizeFlag,
+		&utils.LazySubstateDecodingFlag,
 		&utils.EvmImplementation,
 		&utils.VmImplementation,
+		&utils.ListVmsFlag,
+		&utils.CrossCheckEvmFlag,
+		&utils.CrossCheckDirFlag,
+		&utils.VmTraceFlag,
 		&utils.ValidateTxStateFlag,
+		&utils.TxStateValidationFlag,
+		&utils.CoinbaseToleranceFlag,
+		&utils.DeepOutputCompareFlag,
 		&utils.ValidateFlag,
 		//&utils.OnlySuccessfulFlag,
 		&utils.CpuProfileFlag,
 		&utils.DiagnosticServerFlag,
+		&utils.OtlpEndpointFlag,
+		&utils.OtlpBlockSamplingFlag,
 		&utils.AidaDbFlag,
+		&utils.ProviderFlag,
 		&logger.LogLevelFlag,
+		&logger.LogLevelOverrideFlag,
 		&utils.ErrorLoggingFlag,
+		&utils.ErrorLoggingFormatFlag,
+		&utils.LogQueueSizeFlag,
+		&utils.LogOverflowFlag,
 		&utils.StateDbImplementationFlag,
 		&utils.StateDbLoggingFlag,
+		&utils.StateDbLoggingFormatFlag,
+		&utils.StateDbLoggingFilterFlag,
 		&utils.DeltaLoggingFlag,
+		&utils.DeltaLoggingResultsFlag,
 		&utils.CacheFlag,
 		&utils.SubstateEncodingFlag,
+		&utils.SkipSanityChecksFlag,
+		&utils.TxFilterFlag,
+		&utils.MaxNumTransactionsFlag,
 	},
 }
 
 func main() {
 	if err := runVmApp.Run(os.Args); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(executor.ExitCode(err))
 	}
 }
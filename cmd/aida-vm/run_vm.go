@@ -35,12 +35,16 @@ import (
 
 // RunVm runs a range of transactions on an EVM in parallel.
 func RunVm(ctx *cli.Context) error {
+	if ctx.Bool(utils.ListVmsFlag.Name) {
+		return utils.PrintVmImplementations(ctx.App.Writer)
+	}
+
 	cfg, err := utils.NewConfig(ctx, utils.BlockRangeArgs)
 	if err != nil {
 		return err
 	}
 
-	aidaDb, err := db.NewReadOnlySubstateDB(cfg.AidaDb)
+	aidaDb, err := utils.OpenAidaDb(cfg)
 	if err != nil {
 		return fmt.Errorf("cannot open aida-db; %w", err)
 	}
@@ -48,13 +52,19 @@ func RunVm(ctx *cli.Context) error {
 		err = errors.Join(err, aidaDb.Close())
 	}(aidaDb)
 
-	substateIterator, err := executor.OpenSubstateProvider(cfg, ctx, aidaDb)
+	substateIterator, err := executor.TxProviders.OpenSelected(cfg, executor.SubstateProviderName, executor.ProviderEnvironment{Cli: ctx, AidaDb: aidaDb})
 	if err != nil {
 		return err
 	}
 	defer substateIterator.Close()
 
-	processor, err := executor.MakeLiveDbTxProcessor(cfg)
+	substateIterator, err = executor.MakeTxFilterProvider(substateIterator, cfg)
+	if err != nil {
+		return err
+	}
+	substateIterator = executor.MakeTxLimitProvider(substateIterator, cfg)
+
+	processor, err := executor.MakeEvmCrossCheckProcessor(cfg)
 	if err != nil {
 		return err
 	}
@@ -76,9 +86,11 @@ func run(
 	extra []executor.Extension[txcontext.TxContext],
 ) error {
 	extensions := []executor.Extension[txcontext.TxContext]{
+		profiler.MakeOtelTracer[txcontext.TxContext](cfg),
 		profiler.MakeCpuProfiler[txcontext.TxContext](cfg),
 		profiler.MakeDiagnosticServer[txcontext.TxContext](cfg),
 		profiler.MakeVirtualMachineStatisticsPrinter[txcontext.TxContext](cfg),
+		profiler.MakeOpcodeProfilePrinter[txcontext.TxContext](cfg, processor),
 	}
 
 	if stateDb == nil {
@@ -95,6 +107,7 @@ func run(
 		logger.MakeErrorLogger[txcontext.TxContext](cfg),
 		logger.MakeProgressLogger[txcontext.TxContext](cfg, 15*time.Second),
 		validator.MakeLiveDbValidator(cfg, validator.ValidateTxTarget{WorldState: true, Receipt: true}),
+		validator.MakeSanityValidator(cfg),
 		validator.MakeEthereumDbPostTransactionUpdater(cfg),
 		statedb.MakeTransactionEventEmitter[txcontext.TxContext](),
 	)
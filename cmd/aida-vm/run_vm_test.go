@@ -53,7 +53,7 @@ func TestCmd_RunVm(t *testing.T) {
 	}
 
 	err := app.Run([]string{runVmApp.Name, "--aida-db", path, "--substate-encoding", "pb", "first", "last"})
-	require.ErrorContains(t, err, "intrinsic gas too low")
+	require.ErrorContains(t, err, "insufficient funds for gas * price + value")
 }
 
 func TestCmd_RunVmApp(t *testing.T) {
@@ -75,6 +75,20 @@ func TestCmd_RunVmApp(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCmd_RunVmApp_ListVmsPrintsImplementationsWithoutRunning(t *testing.T) {
+	var out strings.Builder
+	app := *runVmApp
+	app.Writer = &out
+
+	// no aida-db and no block range is needed to list the implementations
+	err := app.Run(utils.NewArgs(runVmApp.Name).Flag(utils.ListVmsFlag.Name, true).Build())
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "--evm-impl")
+	assert.Contains(t, out.String(), "opera")
+	assert.Contains(t, out.String(), "--vm-impl")
+	assert.Contains(t, out.String(), "lfvm")
+}
+
 func TestVm_AllDbEventsAreIssuedInOrder_Sequential(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	provider := executor.NewMockProvider[txcontext.TxContext](ctrl)
@@ -510,3 +524,60 @@ var testTx = &substate.Substate{
 		GasUsed: 1,
 	},
 }
+
+func TestCmd_RunVm_EmptyBlockRangeFailsArgumentValidation(t *testing.T) {
+	_, path := utils.CreateTestSubstateDb(t, db.ProtobufEncodingSchema)
+	app := cli.NewApp()
+	app.Action = RunVm
+	app.Flags = []cli.Flag{
+		&utils.AidaDbFlag,
+		&utils.SubstateEncodingFlag,
+	}
+
+	err := app.Run([]string{runVmApp.Name, "--aida-db", path, "--substate-encoding", "pb", "5", "4"})
+	require.ErrorIs(t, err, utils.ErrEmptyBlockRange)
+}
+
+func TestVm_SingleBlockRangeExecutesThatBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := executor.NewMockProvider[txcontext.TxContext](ctrl)
+	processor := executor.NewMockProcessor[txcontext.TxContext](ctrl)
+	ext := executor.NewMockExtension[txcontext.TxContext](ctrl)
+
+	cfg := utils.NewTestConfig(t, utils.OperaMainnetChainID, 3, 3, false, "")
+	provider.EXPECT().
+		Run(3, 4, gomock.Any()).
+		DoAndReturn(func(_ int, _ int, consumer executor.Consumer[txcontext.TxContext]) error {
+			return consumer(executor.TransactionInfo[txcontext.TxContext]{Block: 3, Transaction: 1, Data: substatecontext.NewTxContext(emptyTx)})
+		})
+
+	gomock.InOrder(
+		ext.EXPECT().PreRun(executor.AtBlock[txcontext.TxContext](3), gomock.Any()),
+		ext.EXPECT().PreBlock(executor.AtBlock[txcontext.TxContext](3), gomock.Any()),
+		ext.EXPECT().PreTransaction(executor.AtTransaction[txcontext.TxContext](3, 1), gomock.Any()),
+		processor.EXPECT().Process(executor.AtTransaction[txcontext.TxContext](3, 1), gomock.Any()),
+		ext.EXPECT().PostTransaction(executor.AtTransaction[txcontext.TxContext](3, 1), gomock.Any()),
+		ext.EXPECT().PostBlock(executor.AtBlock[txcontext.TxContext](3), gomock.Any()),
+		ext.EXPECT().PostRun(executor.AtBlock[txcontext.TxContext](4), gomock.Any(), nil),
+	)
+
+	require.NoError(t, run(cfg, provider, nil, processor, []executor.Extension[txcontext.TxContext]{ext}))
+}
+
+func TestVm_RangeWithoutSubstatesSucceeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := executor.NewMockProvider[txcontext.TxContext](ctrl)
+	processor := executor.NewMockProcessor[txcontext.TxContext](ctrl)
+	ext := executor.NewMockExtension[txcontext.TxContext](ctrl)
+
+	cfg := utils.NewTestConfig(t, utils.OperaMainnetChainID, 2, 4, false, "")
+	cfg.Workers = 4
+	provider.EXPECT().Run(2, 5, gomock.Any()).Return(nil)
+
+	gomock.InOrder(
+		ext.EXPECT().PreRun(executor.AtBlock[txcontext.TxContext](2), gomock.Any()),
+		ext.EXPECT().PostRun(executor.AtBlock[txcontext.TxContext](5), gomock.Any(), nil),
+	)
+
+	require.NoError(t, run(cfg, provider, nil, processor, []executor.Extension[txcontext.TxContext]{ext}))
+}
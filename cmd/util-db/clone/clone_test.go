@@ -18,6 +18,7 @@ package clone
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 	"os"
 	"strconv"
@@ -30,6 +31,7 @@ import (
 	"github.com/0xsoniclabs/substate/substate"
 	"github.com/0xsoniclabs/substate/types"
 	"github.com/0xsoniclabs/substate/types/hash"
+	"github.com/0xsoniclabs/substate/updateset"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -782,3 +784,86 @@ func TestCloneCodes_PutsDataHashAsCode(t *testing.T) {
 	err = ss.Equal(gotSs)
 	require.NoError(t, err)
 }
+
+func TestClone_ParallelWorkersCopyExactlyRequestedRange(t *testing.T) {
+	srcDb, err := db.NewDefaultSubstateDB(t.TempDir() + "/source")
+	require.NoError(t, err)
+	require.NoError(t, srcDb.SetSubstateEncoding(db.ProtobufEncodingSchema))
+	md := utils.NewAidaDbMetadata(srcDb, "ERROR")
+	// ethereum chain id avoids looking up epochs via rpc when writing metadata
+	require.NoError(t, md.SetChainID(utils.EthereumChainID))
+
+	udb, err := db.MakeDefaultUpdateDBFromBaseDB(srcDb)
+	require.NoError(t, err)
+	for block := uint64(1); block <= 40; block++ {
+		ss := createTestSubstate(t, 1, []byte{1}, []byte{2})
+		ss.Block = block
+		require.NoError(t, srcDb.PutSubstate(ss))
+		require.NoError(t, udb.PutUpdateSet(&updateset.UpdateSet{WorldState: substate.WorldState{}, Block: block}, []types.Address{}))
+		require.NoError(t, srcDb.Put(db.EncodeDestroyedAccountKey(block, 1), []byte{1}))
+	}
+
+	prefixes := []string{db.SubstateDBPrefix, db.UpdateDBPrefix, db.DestroyedAccountPrefix}
+	collect := func(database db.BaseDB) map[string]string {
+		res := make(map[string]string)
+		for _, prefix := range prefixes {
+			iter := database.NewIterator([]byte(prefix), nil)
+			for iter.Next() {
+				res[string(iter.Key())] = string(iter.Value())
+			}
+			iter.Release()
+		}
+		return res
+	}
+
+	expected := make(map[string]struct{})
+	for block := uint64(10); block <= 29; block++ {
+		expected[string(db.SubstateDBKey(block, 1))] = struct{}{}
+		expected[string(db.UpdateDBKey(block))] = struct{}{}
+		expected[string(db.EncodeDestroyedAccountKey(block, 1))] = struct{}{}
+	}
+
+	clones := make(map[int]map[string]string)
+	for _, workers := range []int{1, 4} {
+		targetDb, err := db.NewDefaultSubstateDB(t.TempDir() + "/target")
+		require.NoError(t, err)
+
+		cfg := &utils.Config{First: 10, Last: 29, Workers: 1, CloneWorkers: workers, Validate: true}
+		require.NoError(t, clone(cfg, srcDb, targetDb, utils.PatchType))
+
+		clones[workers] = collect(targetDb)
+		got := make(map[string]struct{})
+		for key := range clones[workers] {
+			got[key] = struct{}{}
+		}
+		assert.Equal(t, expected, got, "unexpected keys cloned with %d workers", workers)
+
+		targetMd := utils.NewAidaDbMetadata(targetDb, "ERROR")
+		assert.Equal(t, uint64(10), targetMd.GetFirstBlock())
+		assert.Equal(t, uint64(29), targetMd.GetLastBlock())
+		assert.Equal(t, utils.EthereumChainID, targetMd.GetChainID())
+		require.NoError(t, targetDb.Close())
+	}
+	assert.Equal(t, clones[1], clones[4])
+}
+
+func TestSplitBlockRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		first, last uint64
+		n           int
+		want        [][2]uint64
+	}{
+		{"Single", 5, 10, 1, [][2]uint64{{5, 10}}},
+		{"Even", 0, 7, 4, [][2]uint64{{0, 1}, {2, 3}, {4, 5}, {6, 7}}},
+		{"Uneven", 1, 10, 4, [][2]uint64{{1, 3}, {4, 6}, {7, 9}, {10, 10}}},
+		{"MoreWorkersThanBlocks", 3, 4, 4, [][2]uint64{{3, 3}, {4, 4}}},
+		{"MaxBlock", 0, math.MaxUint64, 2, [][2]uint64{{0, math.MaxUint64 / 2}, {math.MaxUint64/2 + 1, math.MaxUint64}}},
+		{"EmptyRange", 10, 5, 4, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitBlockRange(tt.first, tt.last, tt.n))
+		})
+	}
+}
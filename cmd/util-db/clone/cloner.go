@@ -20,6 +20,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/0xsoniclabs/substate/types/hash"
@@ -60,6 +62,12 @@ func clone(cfg *utils.Config, aidaDb, cloneDb db.SubstateDB, cloneType utils.Aid
 	var err error
 	log := logger.NewLogger(cfg.LogLevel, "AidaDb clone")
 
+	stopScanCachePolicy, err := utildb.StartScanCachePolicy(cfg.ScanCachePolicy, cfg.AidaDb, log)
+	if err != nil {
+		return err
+	}
+	defer stopScanCachePolicy()
+
 	var dbComponent dbcomponent.DbComponent
 
 	if cloneType == utils.CustomType {
@@ -77,7 +85,7 @@ func clone(cfg *utils.Config, aidaDb, cloneDb db.SubstateDB, cloneType utils.Aid
 		log:            log,
 		typ:            cloneType,
 		cloneComponent: dbComponent,
-		writeCh:        make(chan rawEntry, cloneWriteChanSize),
+		writeCh:        make(chan rawEntry, max(1, cfg.CloneWorkers)*cloneWriteChanSize),
 		errCh:          make(chan error, 1),
 		stopCh:         make(chan any),
 	}
@@ -282,11 +290,18 @@ func (c *cloner) readUpdateSet() uint64 {
 
 	switch c.typ {
 	case utils.CloneType:
+		if c.cfg.CloneWorkers > 1 {
+			return c.readUpdateSetParallel(0)
+		}
 		c.read([]byte(db.UpdateDBPrefix), 0, endCond)
 		// if there is no updateset before interval (first 1M blocks) then 0 is returned
 		return lastUpdateBeforeRange
 	case utils.PatchType, utils.CustomType:
 		wantedBlock := c.cfg.First
+		if c.cfg.CloneWorkers > 1 {
+			c.readUpdateSetParallel(wantedBlock)
+			return 0
+		}
 		c.read([]byte(db.UpdateDBPrefix), wantedBlock, endCond)
 		return 0
 	default:
@@ -308,6 +323,14 @@ func (c *cloner) readSubstate() {
 		return false, nil
 	}
 
+	if c.cfg.CloneWorkers > 1 {
+		c.readParallel([]byte(db.SubstateDBPrefix), c.cfg.First, c.cfg.Last, func(key []byte) (uint64, error) {
+			block, _, err := db.DecodeSubstateDBKey(key)
+			return block, err
+		})
+		return
+	}
+
 	c.read([]byte(db.SubstateDBPrefix), c.cfg.First, endCond)
 }
 
@@ -407,9 +430,118 @@ func (c *cloner) readDeletions(firstDeletionBlock uint64) {
 		return false, nil
 	}
 
+	if c.cfg.CloneWorkers > 1 {
+		c.readParallel([]byte(db.DestroyedAccountPrefix), firstDeletionBlock, c.cfg.Last, func(key []byte) (uint64, error) {
+			block, _, err := db.DecodeDestroyedAccountKey(key)
+			return block, err
+		})
+		return
+	}
+
 	c.read([]byte(db.DestroyedAccountPrefix), firstDeletionBlock, endCond)
 }
 
+// readUpdateSetParallel copies update-sets from given block until cfg.Last using
+// cfg.CloneWorkers workers and returns the last update-set preceding cfg.First.
+func (c *cloner) readUpdateSetParallel(first uint64) uint64 {
+	var (
+		mu                    sync.Mutex
+		lastUpdateBeforeRange uint64
+	)
+	c.readParallel([]byte(db.UpdateDBPrefix), first, c.cfg.Last, func(key []byte) (uint64, error) {
+		block, err := db.DecodeUpdateSetKey(key)
+		if err != nil {
+			return 0, err
+		}
+		if block < c.cfg.First {
+			mu.Lock()
+			lastUpdateBeforeRange = max(lastUpdateBeforeRange, block)
+			mu.Unlock()
+		}
+		return block, nil
+	})
+	return lastUpdateBeforeRange
+}
+
+// readParallel copies data with given prefix within block range [first, last]. The range is
+// split into cfg.CloneWorkers disjoint sub-ranges, each copied by a worker with its own
+// iterator. All workers feed the same writer, hence the order of writes is not deterministic.
+func (c *cloner) readParallel(prefix []byte, first, last uint64, decodeBlock func(key []byte) (uint64, error)) {
+	c.log.Noticef("Copying data with prefix %v using %v workers", string(prefix), c.cfg.CloneWorkers)
+
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		abort   = make(chan any)
+		failure error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			failure = err
+			close(abort)
+		})
+	}
+
+	for _, r := range splitBlockRange(first, last, c.cfg.CloneWorkers) {
+		wg.Add(1)
+		go func(from, to uint64) {
+			defer wg.Done()
+			iter := c.sourceDb.NewIterator(prefix, db.BlockToBytes(from))
+			defer iter.Release()
+
+			for iter.Next() {
+				select {
+				case <-abort:
+					return
+				default:
+				}
+
+				block, err := decodeBlock(iter.Key())
+				if err != nil {
+					fail(err)
+					return
+				}
+				if block > to {
+					return
+				}
+
+				atomic.AddUint64(&c.count, 1)
+				if !c.sendToWriteChan(iter.Key(), iter.Value()) {
+					return
+				}
+			}
+		}(r[0], r[1])
+	}
+	wg.Wait()
+
+	if failure != nil {
+		c.errCh <- fmt.Errorf("condition emit error; %v", failure)
+		return
+	}
+	c.log.Noticef("Prefix %v done", string(prefix))
+}
+
+// splitBlockRange splits block range [first, last] into at most n disjoint contiguous sub-ranges.
+func splitBlockRange(first, last uint64, n int) [][2]uint64 {
+	if n < 1 || first > last {
+		return nil
+	}
+	step := (last-first)/uint64(n) + 1
+	var ranges [][2]uint64
+	for from := first; ; {
+		to := from + step - 1
+		// guard against overflow when the range ends at the maximal block
+		if to < from || to > last {
+			to = last
+		}
+		ranges = append(ranges, [2]uint64{from, to})
+		if to == last {
+			return ranges
+		}
+		from = to + 1
+	}
+}
+
 // validateDbSize compares size of database and expectedWritten
 func (c *cloner) validateDbSize() error {
 	actualWritten := utildb.GetDbSize(c.cloneDb)
@@ -512,6 +644,10 @@ func openCloningDbs(aidaDbPath, targetDbPath string, substateEncoding db.Substat
 		return nil, nil, fmt.Errorf("targetDb %v; %v", targetDbPath, err)
 	}
 
+	// keep the encoding of the source unless a different one is requested
+	if substateEncoding == "" {
+		substateEncoding = aidaDb.GetSubstateEncoding()
+	}
 	err = cloneDb.SetSubstateEncoding(substateEncoding)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot set substate encoding; %v", err)
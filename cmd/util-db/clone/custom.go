@@ -37,6 +37,8 @@ var cloneCustomCommand = cli.Command{
 		&utils.ValidateFlag,
 		&logger.LogLevelFlag,
 		&utils.SubstateEncodingFlag,
+		&utils.ScanCachePolicyFlag,
+		&utils.CloneWorkersFlag,
 	},
 	Description: `
 clone custom is a specialized clone tool which copies specific components in aida-db from 
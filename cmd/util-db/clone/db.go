@@ -36,6 +36,8 @@ var cloneDbCommand = cli.Command{
 		&utils.ValidateFlag,
 		&logger.LogLevelFlag,
 		&utils.SubstateEncodingFlag,
+		&utils.ScanCachePolicyFlag,
+		&utils.CloneWorkersFlag,
 	},
 	Description: `
 Creates clone db is used to create subset of aida-db to have more compact database, but still fully usable for desired block range.
@@ -39,6 +39,8 @@ var clonePatchCommand = cli.Command{
 		&utils.ValidateFlag,
 		&logger.LogLevelFlag,
 		&utils.SubstateEncodingFlag,
+		&utils.ScanCachePolicyFlag,
+		&utils.CloneWorkersFlag,
 	},
 	Description: `
 Creates patch of aida-db for desired block range
@@ -17,11 +17,14 @@
 package compact
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/0xsoniclabs/aida/cmd/util-db/flags"
 	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utildb"
 	"github.com/0xsoniclabs/aida/utils"
-	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/0xsoniclabs/substate/db"
 	"github.com/urfave/cli/v2"
 )
 
@@ -32,34 +35,36 @@ var Command = cli.Command{
 	Usage:  "compact target db",
 	Flags: []cli.Flag{
 		&utils.TargetDbFlag,
+		&flags.CompactRanges,
 	},
 	Description: `
-Compacts target database.
+Compacts target database. The key ranges of the components of the database are
+compacted one after another, reporting the progress and the change of the
+on-disk size after each of them. Use --ranges to compact selected components only.
 `,
 }
 
 // compactAction compacts database
-func compactAction(ctx *cli.Context) error {
+func compactAction(ctx *cli.Context) (finalErr error) {
 	cfg, err := utils.NewConfig(ctx, utils.NoArgs)
 	if err != nil {
 		return err
 	}
 
-	log := logger.NewLogger(cfg.LogLevel, "aida-db-compact")
-
-	targetDb, err := leveldb.New(cfg.TargetDb, 1024, 100, "profiling", false)
+	components, err := utildb.ParseCompactionComponents(ctx.String(flags.CompactRanges.Name))
 	if err != nil {
-		return fmt.Errorf("cannot open db; %v", err)
+		return err
 	}
 
-	log.Notice("Starting compaction")
+	log := logger.NewLogger(cfg.LogLevel, "aida-db-compact")
 
-	err = targetDb.Compact(nil, nil)
+	targetDb, err := db.NewDefaultSubstateDB(cfg.TargetDb)
 	if err != nil {
-		return err
+		return fmt.Errorf("cannot open db; %v", err)
 	}
+	defer func() {
+		finalErr = errors.Join(finalErr, targetDb.Close())
+	}()
 
-	log.Notice("Compaction finished")
-
-	return nil
+	return utildb.CompactDb(targetDb, components, log)
 }
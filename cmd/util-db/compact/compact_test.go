@@ -21,6 +21,7 @@ import (
 
 	"github.com/0xsoniclabs/substate/db"
 
+	"github.com/0xsoniclabs/aida/cmd/util-db/flags"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v2"
@@ -32,8 +33,35 @@ func TestCmd_Compact(t *testing.T) {
 	app.Action = compactAction
 	app.Flags = []cli.Flag{
 		&utils.TargetDbFlag,
+		&flags.CompactRanges,
 	}
 
 	err := app.Run([]string{Command.Name, "--target-db", path})
 	require.NoError(t, err)
 }
+
+func TestCmd_CompactSelectedRanges(t *testing.T) {
+	_, path := utils.CreateTestSubstateDb(t, db.ProtobufEncodingSchema)
+	app := cli.NewApp()
+	app.Action = compactAction
+	app.Flags = []cli.Flag{
+		&utils.TargetDbFlag,
+		&flags.CompactRanges,
+	}
+
+	err := app.Run([]string{Command.Name, "--target-db", path, "--ranges", "substate,code"})
+	require.NoError(t, err)
+}
+
+func TestCmd_CompactRejectsUnknownRange(t *testing.T) {
+	_, path := utils.CreateTestSubstateDb(t, db.ProtobufEncodingSchema)
+	app := cli.NewApp()
+	app.Action = compactAction
+	app.Flags = []cli.Flag{
+		&utils.TargetDbFlag,
+		&flags.CompactRanges,
+	}
+
+	err := app.Run([]string{Command.Name, "--target-db", path, "--ranges", "hashes"})
+	require.ErrorContains(t, err, `unknown component "hashes"`)
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package convert
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xsoniclabs/aida/cmd/util-db/flags"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utildb"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/urfave/cli/v2"
+)
+
+// Command re-encodes substates of an AidaDb.
+var Command = cli.Command{
+	Action:    convertAction,
+	Name:      "convert-encoding",
+	Usage:     "re-encodes substates of given block range into --target-encoding",
+	ArgsUsage: "<blockNumFirst> <blockNumLast>",
+	Flags: []cli.Flag{
+		&utils.AidaDbFlag,
+		&utils.TargetDbFlag,
+		&utils.SubstateEncodingFlag,
+		&flags.TargetEncoding,
+		&flags.InPlace,
+		&utils.WorkersFlag,
+		&logger.LogLevelFlag,
+	},
+	Description: `
+Reads substates of given block range from the aida-db with --substate-encoding, or
+the encoding detected from the stored substates, and writes them re-encoded with
+--target-encoding into --target-db. All other keys of the aida-db are copied as well
+and the target encoding is recorded in the metadata.
+
+With --in-place, the substates are converted into a staging database next to the
+aida-db and copied over the original substates once all are converted; the block
+range has to cover all substates of the aida-db.
+
+An interrupted conversion continues where it stopped once the command is run
+again with the same arguments. Note that rlp cannot represent access lists, set
+code authorizations and the random value of blocks, which are dropped when
+converting to rlp.`,
+}
+
+func convertAction(ctx *cli.Context) (finalErr error) {
+	cfg, err := utils.NewConfig(ctx, utils.BlockRangeArgs)
+	if err != nil {
+		return err
+	}
+	if !ctx.IsSet(flags.TargetEncoding.Name) {
+		return fmt.Errorf("--%v must be set", flags.TargetEncoding.Name)
+	}
+	// the source encoding is only taken from the flag since a resumed in-place
+	// conversion may have replaced part of the substates already
+	from := db.SubstateEncodingSchema(ctx.String(utils.SubstateEncodingFlag.Name))
+	to := db.SubstateEncodingSchema(ctx.String(flags.TargetEncoding.Name))
+
+	if ctx.Bool(flags.InPlace.Name) {
+		return utildb.ConvertSubstateEncodingInPlace(cfg.AidaDb, from, to, cfg.First, cfg.Last, cfg.Workers, cfg.LogLevel)
+	}
+	if cfg.TargetDb == "" {
+		return fmt.Errorf("either --%v or --%v must be set", utils.TargetDbFlag.Name, flags.InPlace.Name)
+	}
+
+	aidaDb, err := db.NewReadOnlySubstateDB(cfg.AidaDb)
+	if err != nil {
+		return fmt.Errorf("cannot open aida-db; %w", err)
+	}
+	defer func() {
+		finalErr = errors.Join(finalErr, aidaDb.Close())
+	}()
+	targetDb, err := db.NewDefaultSubstateDB(cfg.TargetDb)
+	if err != nil {
+		return fmt.Errorf("cannot open target-db; %w", err)
+	}
+	defer func() {
+		finalErr = errors.Join(finalErr, targetDb.Close())
+	}()
+
+	return utildb.ConvertSubstateEncoding(aidaDb, targetDb, from, to, cfg.First, cfg.Last, cfg.Workers, true, cfg.LogLevel)
+}
@@ -0,0 +1,283 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/0xsoniclabs/substate/substate"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	jsonlFormat = "jsonl"
+	csvFormat   = "csv"
+)
+
+// record is the exported representation of a single transaction.
+type record struct {
+	Block       uint64       `json:"block"`
+	Transaction int          `json:"transaction"`
+	Message     message      `json:"message"`
+	Env         env          `json:"env"`
+	InputAlloc  allocSummary `json:"inputAlloc"`
+	OutputAlloc allocSummary `json:"outputAlloc"`
+	Status      uint64       `json:"status"`
+	GasUsed     uint64       `json:"gasUsed"`
+
+	// full allocs are only exported on request
+	InputAllocFull  map[common.Address]account `json:"inputAllocFull,omitempty"`
+	OutputAllocFull map[common.Address]account `json:"outputAllocFull,omitempty"`
+}
+
+type message struct {
+	From      common.Address  `json:"from"`
+	To        *common.Address `json:"to"` // nil for contract creations
+	Nonce     uint64          `json:"nonce"`
+	Value     string          `json:"value"`
+	Gas       uint64          `json:"gas"`
+	GasPrice  string          `json:"gasPrice"`
+	GasFeeCap string          `json:"gasFeeCap"`
+	GasTipCap string          `json:"gasTipCap"`
+	Data      hexutil.Bytes   `json:"data"`
+}
+
+type env struct {
+	Coinbase    common.Address `json:"coinbase"`
+	Number      uint64         `json:"number"`
+	Timestamp   uint64         `json:"timestamp"`
+	GasLimit    uint64         `json:"gasLimit"`
+	BaseFee     string         `json:"baseFee"`
+	BlobBaseFee string         `json:"blobBaseFee"`
+	Difficulty  string         `json:"difficulty"`
+}
+
+type allocSummary struct {
+	Accounts     int `json:"accounts"`
+	StorageSlots int `json:"storageSlots"`
+}
+
+type account struct {
+	Nonce   uint64                      `json:"nonce"`
+	Balance string                      `json:"balance"`
+	Code    hexutil.Bytes               `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+// newRecord converts given substate into its exported representation.
+func newRecord(ss *substate.Substate, includeAlloc bool) *record {
+	r := &record{
+		Block:       ss.Block,
+		Transaction: ss.Transaction,
+		InputAlloc:  summarizeAlloc(ss.InputSubstate),
+		OutputAlloc: summarizeAlloc(ss.OutputSubstate),
+	}
+	if msg := ss.Message; msg != nil {
+		r.Message = message{
+			From:      common.Address(msg.From),
+			Nonce:     msg.Nonce,
+			Value:     bigToString(msg.Value),
+			Gas:       msg.Gas,
+			GasPrice:  bigToString(msg.GasPrice),
+			GasFeeCap: bigToString(msg.GasFeeCap),
+			GasTipCap: bigToString(msg.GasTipCap),
+			Data:      msg.Data,
+		}
+		if msg.To != nil {
+			to := common.Address(*msg.To)
+			r.Message.To = &to
+		}
+	}
+	if e := ss.Env; e != nil {
+		r.Env = env{
+			Coinbase:    common.Address(e.Coinbase),
+			Number:      e.Number,
+			Timestamp:   e.Timestamp,
+			GasLimit:    e.GasLimit,
+			BaseFee:     bigToString(e.BaseFee),
+			BlobBaseFee: bigToString(e.BlobBaseFee),
+			Difficulty:  bigToString(e.Difficulty),
+		}
+	}
+	if res := ss.Result; res != nil {
+		r.Status = res.Status
+		r.GasUsed = res.GasUsed
+	}
+	if includeAlloc {
+		r.InputAllocFull = convertAlloc(ss.InputSubstate)
+		r.OutputAllocFull = convertAlloc(ss.OutputSubstate)
+	}
+	return r
+}
+
+func summarizeAlloc(alloc substate.WorldState) allocSummary {
+	summary := allocSummary{Accounts: len(alloc)}
+	for _, acc := range alloc {
+		summary.StorageSlots += len(acc.Storage)
+	}
+	return summary
+}
+
+func convertAlloc(alloc substate.WorldState) map[common.Address]account {
+	res := make(map[common.Address]account, len(alloc))
+	for addr, acc := range alloc {
+		storage := make(map[common.Hash]common.Hash, len(acc.Storage))
+		for key, value := range acc.Storage {
+			storage[common.Hash(key)] = common.Hash(value)
+		}
+		balance := ""
+		if acc.Balance != nil {
+			balance = acc.Balance.Dec()
+		}
+		res[common.Address(addr)] = account{
+			Nonce:   acc.Nonce,
+			Balance: balance,
+			Code:    acc.Code,
+			Storage: storage,
+		}
+	}
+	return res
+}
+
+// bigToString returns the decimal representation of given number or an empty string if it is nil.
+func bigToString(value *big.Int) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
+
+// recordWriter writes exported records in a specific format.
+type recordWriter interface {
+	Write(*record) error
+	Flush() error
+}
+
+// checkExportFormat makes sure given format is supported and can be combined with full allocs if requested.
+func checkExportFormat(format string, includeAlloc bool) error {
+	switch format {
+	case jsonlFormat:
+		return nil
+	case csvFormat:
+		if includeAlloc {
+			return fmt.Errorf("--include-alloc is only supported with --format=%v", jsonlFormat)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown export format %q; supported formats are %v and %v", format, jsonlFormat, csvFormat)
+	}
+}
+
+func newRecordWriter(format string, out io.Writer) (recordWriter, error) {
+	switch format {
+	case jsonlFormat:
+		return &jsonlWriter{encoder: json.NewEncoder(out)}, nil
+	case csvFormat:
+		return &csvWriter{writer: csv.NewWriter(out)}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// jsonlWriter writes each record as a single JSON object on its own line.
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *jsonlWriter) Write(r *record) error {
+	return w.encoder.Encode(r)
+}
+
+func (w *jsonlWriter) Flush() error {
+	return nil
+}
+
+// csvHeader lists the columns of the csv format. Full allocs cannot be represented in csv.
+var csvHeader = []string{
+	"block", "transaction",
+	"from", "to", "nonce", "value", "gas", "gas_price", "gas_fee_cap", "gas_tip_cap", "data_size",
+	"coinbase", "number", "timestamp", "gas_limit", "base_fee", "blob_base_fee", "difficulty",
+	"input_accounts", "input_storage_slots", "output_accounts", "output_storage_slots",
+	"status", "gas_used",
+}
+
+// csvWriter writes each record as a single row; the header is written before the first row.
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvWriter) Write(r *record) error {
+	if !w.headerWritten {
+		if err := w.writer.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+	to := ""
+	if r.Message.To != nil {
+		to = r.Message.To.Hex()
+	}
+	u := func(v uint64) string { return strconv.FormatUint(v, 10) }
+	return w.writer.Write([]string{
+		u(r.Block), strconv.Itoa(r.Transaction),
+		r.Message.From.Hex(), to, u(r.Message.Nonce), r.Message.Value, u(r.Message.Gas),
+		r.Message.GasPrice, r.Message.GasFeeCap, r.Message.GasTipCap, strconv.Itoa(len(r.Message.Data)),
+		r.Env.Coinbase.Hex(), u(r.Env.Number), u(r.Env.Timestamp), u(r.Env.GasLimit),
+		r.Env.BaseFee, r.Env.BlobBaseFee, r.Env.Difficulty,
+		strconv.Itoa(r.InputAlloc.Accounts), strconv.Itoa(r.InputAlloc.StorageSlots),
+		strconv.Itoa(r.OutputAlloc.Accounts), strconv.Itoa(r.OutputAlloc.StorageSlots),
+		u(r.Status), u(r.GasUsed),
+	})
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// exportSubstates streams all substates of the inclusive block range [first, last] into
+// given writer and returns the number of exported substates. Substates are processed one
+// at a time, so the memory usage is independent of the size of the range.
+func exportSubstates(sdb db.SubstateDB, first, last uint64, workers int, includeAlloc bool, writer recordWriter) (int, error) {
+	count := 0
+	iter := sdb.NewSubstateIterator(int(first), workers)
+	for iter.Next() {
+		ss := iter.Value()
+		if ss.Block > last {
+			break
+		}
+		if err := writer.Write(newRecord(ss, includeAlloc)); err != nil {
+			iter.Release()
+			return count, fmt.Errorf("cannot export substate %d_%d; %w", ss.Block, ss.Transaction, err)
+		}
+		count++
+	}
+	// Release cannot be deferred, otherwise iteration errors might not be reported
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return count, err
+	}
+	return count, writer.Flush()
+}
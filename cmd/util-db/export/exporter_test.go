@@ -0,0 +1,229 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/cmd/util-db/flags"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/0xsoniclabs/substate/substate"
+	"github.com/0xsoniclabs/substate/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// createExportTestDb creates a substate db containing two synthetic substates of blocks 10 and 12.
+func createExportTestDb(t *testing.T) (db.SubstateDB, []*substate.Substate) {
+	sdb, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, sdb.SetSubstateEncoding(db.ProtobufEncodingSchema))
+	t.Cleanup(func() { require.NoError(t, sdb.Close()) })
+
+	first := utils.GetTestSubstate(string(db.ProtobufEncodingSchema))
+	first.Block, first.Transaction = 10, 3
+	first.InputSubstate[types.Address{1}].Storage[types.Hash{1}] = types.Hash{2}
+
+	second := utils.GetTestSubstate(string(db.ProtobufEncodingSchema))
+	second.Block, second.Transaction = 12, 0
+	second.Message.To = nil
+	second.Result.Status = 0
+
+	for _, ss := range []*substate.Substate{first, second} {
+		require.NoError(t, sdb.PutSubstate(ss))
+	}
+	return sdb, []*substate.Substate{first, second}
+}
+
+// decodeJsonl decodes each line of given output into a record and checks that
+// encoding the record again yields the very same line.
+func decodeJsonl(t *testing.T, output []byte) []record {
+	var records []record
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		var r record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		encoded, err := json.Marshal(r)
+		require.NoError(t, err)
+		require.JSONEq(t, scanner.Text(), string(encoded))
+		records = append(records, r)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func TestExport_JsonlRoundTrips(t *testing.T) {
+	sdb, substates := createExportTestDb(t)
+
+	var out bytes.Buffer
+	writer, err := newRecordWriter(jsonlFormat, &out)
+	require.NoError(t, err)
+	count, err := exportSubstates(sdb, 0, 100, 1, false, writer)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	records := decodeJsonl(t, out.Bytes())
+	require.Len(t, records, 2)
+	for i, ss := range substates {
+		r := records[i]
+		assert.Equal(t, ss.Block, r.Block)
+		assert.Equal(t, ss.Transaction, r.Transaction)
+		assert.Equal(t, common.Address(ss.Message.From), r.Message.From)
+		assert.Equal(t, ss.Message.Value.String(), r.Message.Value)
+		assert.Equal(t, ss.Message.Gas, r.Message.Gas)
+		assert.Equal(t, ss.Message.Data, []byte(r.Message.Data))
+		assert.Equal(t, common.Address(ss.Env.Coinbase), r.Env.Coinbase)
+		assert.Equal(t, ss.Env.Number, r.Env.Number)
+		assert.Equal(t, ss.Env.BaseFee.String(), r.Env.BaseFee)
+		assert.Equal(t, ss.Result.Status, r.Status)
+		assert.Equal(t, ss.Result.GasUsed, r.GasUsed)
+		assert.Nil(t, r.InputAllocFull)
+		assert.Nil(t, r.OutputAllocFull)
+	}
+	assert.Equal(t, allocSummary{Accounts: 1, StorageSlots: 1}, records[0].InputAlloc)
+	assert.Equal(t, allocSummary{Accounts: 1, StorageSlots: 0}, records[0].OutputAlloc)
+	assert.NotNil(t, records[0].Message.To)
+	assert.Nil(t, records[1].Message.To)
+}
+
+func TestExport_JsonlIncludesFullAllocOnRequest(t *testing.T) {
+	sdb, substates := createExportTestDb(t)
+
+	var out bytes.Buffer
+	writer, err := newRecordWriter(jsonlFormat, &out)
+	require.NoError(t, err)
+	_, err = exportSubstates(sdb, 10, 10, 1, true, writer)
+	require.NoError(t, err)
+
+	records := decodeJsonl(t, out.Bytes())
+	require.Len(t, records, 1)
+	want := substates[0].InputSubstate[types.Address{1}]
+	got, ok := records[0].InputAllocFull[common.Address{1}]
+	require.True(t, ok)
+	assert.Equal(t, want.Nonce, got.Nonce)
+	assert.Equal(t, want.Balance.Dec(), got.Balance)
+	assert.Equal(t, map[common.Hash]common.Hash{{1}: {2}}, got.Storage)
+	assert.Contains(t, records[0].OutputAllocFull, common.Address{2})
+}
+
+func TestExport_RespectsBlockRange(t *testing.T) {
+	sdb, _ := createExportTestDb(t)
+
+	var out bytes.Buffer
+	writer, err := newRecordWriter(jsonlFormat, &out)
+	require.NoError(t, err)
+	count, err := exportSubstates(sdb, 11, 20, 1, false, writer)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	records := decodeJsonl(t, out.Bytes())
+	require.Len(t, records, 1)
+	assert.Equal(t, uint64(12), records[0].Block)
+}
+
+func TestExport_Csv(t *testing.T) {
+	sdb, substates := createExportTestDb(t)
+
+	var out bytes.Buffer
+	writer, err := newRecordWriter(csvFormat, &out)
+	require.NoError(t, err)
+	_, err = exportSubstates(sdb, 0, 100, 1, false, writer)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, csvHeader, rows[0])
+	column := func(name string) int {
+		for i, c := range csvHeader {
+			if c == name {
+				return i
+			}
+		}
+		t.Fatalf("unknown column %v", name)
+		return -1
+	}
+	assert.Equal(t, "10", rows[1][column("block")])
+	assert.Equal(t, "3", rows[1][column("transaction")])
+	assert.Equal(t, common.Address(substates[0].Message.From).Hex(), rows[1][column("from")])
+	assert.Equal(t, "1", rows[1][column("input_storage_slots")])
+	assert.Equal(t, "", rows[2][column("to")])
+	assert.Equal(t, "0", rows[2][column("status")])
+}
+
+func TestExport_CheckExportFormat(t *testing.T) {
+	tests := map[string]struct {
+		format       string
+		includeAlloc bool
+		wantErr      string
+	}{
+		"jsonl":            {format: jsonlFormat},
+		"jsonlWithAlloc":   {format: jsonlFormat, includeAlloc: true},
+		"csv":              {format: csvFormat},
+		"csvWithAlloc":     {format: csvFormat, includeAlloc: true, wantErr: "--include-alloc is only supported"},
+		"unknownFormat":    {format: "xml", wantErr: "unknown export format"},
+		"emptyFormatAlloc": {format: "", includeAlloc: true, wantErr: "unknown export format"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkExportFormat(test.format, test.includeAlloc)
+			if test.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCmd_Export(t *testing.T) {
+	ss, path := utils.CreateTestSubstateDb(t, db.ProtobufEncodingSchema)
+	output := filepath.Join(t.TempDir(), "export.jsonl")
+
+	app := cli.NewApp()
+	app.Action = exportAction
+	app.Flags = Command.Flags
+
+	args := utils.NewArgs(Command.Name).
+		Flag(utils.AidaDbFlag.Name, path).
+		Flag(utils.SubstateEncodingFlag.Name, string(db.ProtobufEncodingSchema)).
+		Flag(utils.OutputFlag.Name, output).
+		Flag(flags.ExportFormat.Name, jsonlFormat).
+		Flag(flags.IncludeAlloc.Name, true).
+		Flag(logger.LogLevelFlag.Name, "critical").
+		Arg(int(ss.Block)).
+		Arg(int(ss.Block)).
+		Build()
+	require.NoError(t, app.Run(args))
+
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	records := decodeJsonl(t, content)
+	require.Len(t, records, 1)
+	assert.Equal(t, ss.Block, records[0].Block)
+	assert.Len(t, records[0].InputAllocFull, len(ss.InputSubstate))
+}
@@ -28,8 +28,72 @@ var (
 		Name:  "skip-metadata",
 		Usage: "Skips metadata inserting and getting. Useful especially when working with old AidaDb that does not have Metadata yet",
 	}
+	DryRun = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Validates compatibility of source databases and prints the merge plan without writing the target database",
+	}
 	ForceFlag = cli.BoolFlag{
 		Name:  "force",
 		Usage: "Forces generation even when dbHash is found.",
 	}
+	RestartValidation = cli.BoolFlag{
+		Name:  "force",
+		Usage: "Ignores the checkpoint of an interrupted validation and hashes the whole AidaDb again",
+	}
+	ExportFormat = cli.StringFlag{
+		Name:  "format",
+		Usage: "Format of exported substates; one of jsonl or csv",
+		Value: "jsonl",
+	}
+	SizeFormat = cli.StringFlag{
+		Name:  "format",
+		Usage: "Format of the printed sizes; one of table or json",
+		Value: "table",
+	}
+	SampleRate = cli.Float64Flag{
+		Name:  "sample",
+		Usage: "Estimates sizes by scanning only given fraction of block and code keys; 0 scans all keys",
+	}
+	KeepFrom = cli.Uint64Flag{
+		Name:  "keep-from",
+		Usage: "First block retained by pruning; all older blocks are removed",
+	}
+	PruneCompact = cli.BoolFlag{
+		Name:  "compact",
+		Usage: "Compacts the pruned components once the pruning is finished",
+	}
+	ForcePrune = cli.BoolFlag{
+		Name:  "force",
+		Usage: "Prunes even if --keep-from is in the middle of an update-set interval",
+	}
+	TargetEncoding = cli.StringFlag{
+		Name:  "target-encoding",
+		Usage: "Encoding of converted substates; rlp or protobuf",
+	}
+	InPlace = cli.BoolFlag{
+		Name:  "in-place",
+		Usage: "Converts substates of the aida-db in place using a staging database next to it instead of writing --target-db",
+	}
+	CompactRanges = cli.StringFlag{
+		Name:  "ranges",
+		Usage: "Comma-separated list of components to compact, e.g. substate,code; all components are compacted if empty",
+	}
+	ScrapeSource = cli.StringFlag{
+		Name:  "source",
+		Usage: "Source of scraped data; node stores state and block hashes of an Opera/Sonic node, rpc records substates from an eth JSON-RPC node",
+		Value: "node",
+	}
+	RpcEndpoint = cli.StringFlag{
+		Name:  "rpc-endpoint",
+		Usage: "Eth JSON-RPC endpoint providing debug_traceBlockByNumber and eth_getBlockReceipts; required by --source rpc",
+	}
+	RpcBatchSize = cli.IntFlag{
+		Name:  "rpc-batch-size",
+		Usage: "Number of blocks requested by a single JSON-RPC batch request when scraping state hashes; single requests are used if the node rejects batches",
+		Value: 64,
+	}
+	IncludeAlloc = cli.BoolFlag{
+		Name:  "include-alloc",
+		Usage: "Includes full input and output allocs in exported substates (jsonl only)",
+	}
 )
@@ -32,6 +32,7 @@ var generateDbHashCommand = cli.Command{
 	Usage:  "Generates new db-hash. Note that this will overwrite the current AidaDb hash.",
 	Flags: []cli.Flag{
 		&utils.AidaDbFlag,
+		&utils.ScanCachePolicyFlag,
 	},
 }
 
@@ -53,6 +54,12 @@ func generateDbHashAction(ctx *cli.Context) error {
 
 	md := utils.NewAidaDbMetadata(aidaDb, "INFO")
 
+	stopScanCachePolicy, err := utildb.StartScanCachePolicy(cfg.ScanCachePolicy, cfg.AidaDb, log)
+	if err != nil {
+		return err
+	}
+	defer stopScanCachePolicy()
+
 	log.Noticef("Starting DbHash generation for %v; this may take several hours...", cfg.AidaDb)
 	hash, err := utildb.GenerateDbHash(aidaDb, "INFO")
 	if err != nil {
@@ -0,0 +1,88 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package generate
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	updateDbSubdir   = "update" // subdirectory of aida-db holding the update-db unless --update-db is set
+	deletionDbSubdir = "delete" // subdirectory of aida-db holding the deletion-db unless --deletion-db is set
+)
+
+// optionalAidaDbFlag is the --aida-db flag of commands which may work with component databases only.
+var optionalAidaDbFlag = func() cli.PathFlag {
+	flag := utils.AidaDbFlag
+	flag.Required = false
+	return flag
+}()
+
+// dbComponent is a component database of aida-db which may be stored at a path of its own.
+type dbComponent struct {
+	flag   string // name of the flag setting the path of the component
+	path   string // path given by the flag, empty if the flag is not set
+	subdir string // subdirectory of aida-db used if the flag is not set
+}
+
+// componentFromFlag creates a dbComponent from the value of given flag.
+func componentFromFlag(ctx *cli.Context, flag cli.PathFlag, subdir string) dbComponent {
+	return dbComponent{flag: flag.Name, path: ctx.Path(flag.Name), subdir: subdir}
+}
+
+// resolveComponentPaths returns the paths of given component databases in the same order.
+// Explicitly set paths are used as-is. Components without a path are placed into their own
+// subdirectory of aida-db, so that each database is opened by a distinct leveldb instance.
+// An error is returned if two of the databases, including aida-db itself, share a path.
+func resolveComponentPaths(aidaDb string, components ...dbComponent) ([]string, error) {
+	owners := make(map[string]string)
+	if aidaDb != "" {
+		owners[filepath.Clean(aidaDb)] = utils.AidaDbFlag.Name
+	}
+
+	paths := make([]string, len(components))
+	for i, component := range components {
+		switch {
+		case component.path != "":
+			paths[i] = component.path
+		case aidaDb != "":
+			paths[i] = filepath.Join(aidaDb, component.subdir)
+		default:
+			return nil, fmt.Errorf("neither --%v nor --%v is set; set --%v or let --%v derive it as <aida-db>/%v",
+				component.flag, utils.AidaDbFlag.Name, component.flag, utils.AidaDbFlag.Name, component.subdir)
+		}
+
+		path := filepath.Clean(paths[i])
+		owner, found := owners[path]
+		switch {
+		case !found:
+			owners[path] = component.flag
+		case owner == utils.AidaDbFlag.Name:
+			return nil, fmt.Errorf("--%v and --%v cannot both point to %v since the database would be opened twice; "+
+				"omit --%v to store it in %v", utils.AidaDbFlag.Name, component.flag, paths[i],
+				component.flag, filepath.Join(aidaDb, component.subdir))
+		default:
+			return nil, fmt.Errorf("--%v and --%v cannot both point to %v since the database would be opened twice",
+				owner, component.flag, paths[i])
+		}
+	}
+	return paths, nil
+}
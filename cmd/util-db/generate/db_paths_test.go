@@ -0,0 +1,92 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package generate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveComponentPaths_OnlyAidaDbDerivesSubdirectories(t *testing.T) {
+	paths, err := resolveComponentPaths("/data/aida-db",
+		dbComponent{flag: "update-db", subdir: updateDbSubdir},
+		dbComponent{flag: "deletion-db", subdir: deletionDbSubdir},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("/data/aida-db", "update"), filepath.Join("/data/aida-db", "delete")}, paths)
+}
+
+func TestResolveComponentPaths_OnlyComponentPathsAreUsedAsIs(t *testing.T) {
+	paths, err := resolveComponentPaths("",
+		dbComponent{flag: "update-db", path: "/data/update", subdir: updateDbSubdir},
+		dbComponent{flag: "deletion-db", path: "/data/delete", subdir: deletionDbSubdir},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/data/update", "/data/delete"}, paths)
+}
+
+func TestResolveComponentPaths_BothSetWithoutConflictUsesComponentPaths(t *testing.T) {
+	paths, err := resolveComponentPaths("/data/aida-db",
+		dbComponent{flag: "update-db", path: "/data/update", subdir: updateDbSubdir},
+		dbComponent{flag: "deletion-db", subdir: deletionDbSubdir},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/data/update", filepath.Join("/data/aida-db", "delete")}, paths)
+}
+
+func TestResolveComponentPaths_ReportsInvalidCombinations(t *testing.T) {
+	tests := map[string]struct {
+		aidaDb     string
+		components []dbComponent
+		wantErr    string
+	}{
+		"component equals aida-db": {
+			aidaDb:     "/data/aida-db",
+			components: []dbComponent{{flag: "deletion-db", path: "/data/aida-db/", subdir: deletionDbSubdir}},
+			wantErr:    "--aida-db and --deletion-db cannot both point to /data/aida-db/ since the database would be opened twice; omit --deletion-db to store it in /data/aida-db/delete",
+		},
+		"components share a path": {
+			aidaDb: "/data/aida-db",
+			components: []dbComponent{
+				{flag: "update-db", path: "/data/shared", subdir: updateDbSubdir},
+				{flag: "deletion-db", path: "/data/shared", subdir: deletionDbSubdir},
+			},
+			wantErr: "--update-db and --deletion-db cannot both point to /data/shared",
+		},
+		"component collides with derived path": {
+			aidaDb: "/data/aida-db",
+			components: []dbComponent{
+				{flag: "update-db", subdir: updateDbSubdir},
+				{flag: "deletion-db", path: "/data/aida-db/update", subdir: deletionDbSubdir},
+			},
+			wantErr: "--update-db and --deletion-db cannot both point to /data/aida-db/update",
+		},
+		"nothing set": {
+			components: []dbComponent{{flag: "update-db", subdir: updateDbSubdir}},
+			wantErr:    "neither --update-db nor --aida-db is set",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := resolveComponentPaths(test.aidaDb, test.components...)
+			require.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
@@ -64,9 +64,11 @@ func generateDeletedAccountsAction(ctx *cli.Context) (finalErr error) {
 		return err
 	}
 
-	if !ctx.IsSet(utils.DeletionDbFlag.Name) {
-		return fmt.Errorf("you need to specify where you want deletion-db to save (--deletion-db)")
+	paths, err := resolveComponentPaths(ctx.Path(utils.AidaDbFlag.Name), componentFromFlag(ctx, utils.DeletionDbFlag, deletionDbSubdir))
+	if err != nil {
+		return err
 	}
+	cfg.DeletionDb = paths[0]
 
 	sdb, err := db.NewReadOnlySubstateDB(cfg.AidaDb)
 	if err != nil {
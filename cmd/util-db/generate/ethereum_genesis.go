@@ -42,6 +42,7 @@ var generateEthereumGenesisCommand = cli.Command{
 	Usage:  "Extracts WorldState from json into first updateset",
 	Flags: []cli.Flag{
 		&utils.ChainIDFlag,
+		&optionalAidaDbFlag,
 		&utils.UpdateDbFlag,
 		&logger.LogLevelFlag,
 	},
@@ -58,6 +59,12 @@ func generateEthereumGenesisAction(ctx *cli.Context) (finalErr error) {
 	if argErr != nil {
 		return argErr
 	}
+	paths, err := resolveComponentPaths(ctx.Path(utils.AidaDbFlag.Name), componentFromFlag(ctx, utils.UpdateDbFlag, updateDbSubdir))
+	if err != nil {
+		return err
+	}
+	cfg.UpdateDb = paths[0]
+
 	log := logger.NewLogger(cfg.LogLevel, "Ethereum Update")
 
 	log.Notice("Load Ethereum initial world state")
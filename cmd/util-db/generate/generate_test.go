@@ -54,12 +54,24 @@ func TestGenerate_GenerateDeletedAccountsCommand(t *testing.T) {
 				Arg(int(ss.Block + 1)),
 		},
 		{
-			name:    "NoDeletionDb",
-			wantErr: "you need to specify where you want deletion-db to save (--deletion-db)",
+			name: "DeletionDbDerivedFromAidaDb",
+			// Transaction fail
+			wantErr: "intrinsic gas too low",
+			argsBuilder: utils.NewArgs("test").
+				Arg(Command.Name).
+				Arg(generateDeletedAccountsCommand.Name).
+				Flag(utils.AidaDbFlag.Name, sdbPath).
+				Arg(strconv.FormatUint(ss.Block-1, 10)).
+				Arg(strconv.FormatUint(ss.Block+1, 10)),
+		},
+		{
+			name:    "DeletionDbIsAidaDb",
+			wantErr: "--aida-db and --deletion-db cannot both point to",
 			argsBuilder: utils.NewArgs("test").
 				Arg(Command.Name).
 				Arg(generateDeletedAccountsCommand.Name).
 				Flag(utils.AidaDbFlag.Name, sdbPath).
+				Flag(utils.DeletionDbFlag.Name, sdbPath).
 				Arg(strconv.FormatUint(ss.Block-1, 10)).
 				Arg(strconv.FormatUint(ss.Block+1, 10)),
 		},
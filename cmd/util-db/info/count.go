@@ -62,6 +62,11 @@ func printCountAction(ctx *cli.Context) error {
 		}
 	}()
 
+	cfg.SubstateEncoding, err = utils.ApplySubstateEncoding(base, cfg.SubstateEncoding)
+	if err != nil {
+		return err
+	}
+
 	return printCount(cfg, base, log)
 }
 
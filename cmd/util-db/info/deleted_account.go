@@ -17,8 +17,12 @@
 package info
 
 import (
+	"encoding/binary"
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/0xsoniclabs/aida/cmd/util-db/flags"
 	"github.com/0xsoniclabs/aida/logger"
@@ -39,6 +43,22 @@ var printDeletedAccountsCommand = cli.Command{
 	},
 }
 
+var printDeletionsCommand = cli.Command{
+	Action:    printDeletionsAction,
+	Name:      "deleted-accounts",
+	Usage:     "Prints accounts destroyed and resurrected within a block range in AidaDb.",
+	ArgsUsage: "<firstBlockNum> <lastBlockNum>",
+	Flags: []cli.Flag{
+		&utils.AidaDbFlag,
+		&logger.LogLevelFlag,
+	},
+	Description: `
+Lists every account recorded in the deletion db as destroyed or resurrected
+by a transaction between the given blocks (inclusive), ordered by block and
+transaction.
+`,
+}
+
 // printDeletedAccountsAction for given deleted account in AidaDb
 func printDeletedAccountsAction(ctx *cli.Context) error {
 	cfg, argErr := utils.NewConfig(ctx, utils.BlockRangeArgs)
@@ -78,3 +98,67 @@ func printDeletedAccountsAction(ctx *cli.Context) error {
 
 	return nil
 }
+
+// printDeletionsAction prints destroyed and resurrected accounts of given block range in AidaDb
+func printDeletionsAction(ctx *cli.Context) error {
+	cfg, argErr := utils.NewConfig(ctx, utils.BlockRangeArgs)
+	if argErr != nil {
+		return argErr
+	}
+
+	log := logger.NewLogger(cfg.LogLevel, "AidaDb-Deleted-Accounts")
+
+	ddb, err := db.NewReadOnlyDestroyedAccountDB(cfg.DeletionDb)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := ddb.Close(); err != nil {
+			log.Warningf("Error closing aida db: %v", err)
+		}
+	}()
+
+	return printDeletions(os.Stdout, ddb, cfg.First, cfg.Last)
+}
+
+// printDeletions writes a table of accounts destroyed and resurrected between first and last block.
+func printDeletions(out io.Writer, ddb db.DestroyedAccountDB, first, last uint64) error {
+	start := make([]byte, 8)
+	binary.BigEndian.PutUint64(start, first)
+
+	iter := ddb.NewIterator([]byte(db.DestroyedAccountPrefix), start)
+	defer iter.Release()
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "BLOCK\tTX\tSTATUS\tACCOUNT"); err != nil {
+		return err
+	}
+	for iter.Next() {
+		block, tx, err := db.DecodeDestroyedAccountKey(iter.Key())
+		if err != nil {
+			return err
+		}
+		if block > last {
+			break
+		}
+		list, err := ddb.Decode(iter.Value())
+		if err != nil {
+			return fmt.Errorf("cannot decode deleted accounts of block %v tx %v; %w", block, tx, err)
+		}
+		for _, addr := range list.DestroyedAccounts {
+			if _, err = fmt.Fprintf(w, "%d\t%d\tdestroyed\t%v\n", block, tx, addr); err != nil {
+				return err
+			}
+		}
+		for _, addr := range list.ResurrectedAccounts {
+			if _, err = fmt.Fprintf(w, "%d\t%d\tresurrected\t%v\n", block, tx, addr); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
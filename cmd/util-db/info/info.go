@@ -25,6 +25,7 @@ var Command = cli.Command{
 	Usage: "Prints information about AidaDb",
 	Subcommands: []*cli.Command{
 		&printDeletedAccountsCommand,
+		&printDeletionsCommand,
 		&printCountCommand,
 		&printRangeCommand,
 		&printStateHashCommand,
@@ -34,5 +35,6 @@ var Command = cli.Command{
 		&printPrefixHashCommand,
 		&printDbHashCommand,
 		&dumpSubstateCommand,
+		&printSizeCommand,
 	},
 }
@@ -17,6 +17,7 @@
 package info
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -24,6 +25,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/0xsoniclabs/aida/utildb"
 	"github.com/0xsoniclabs/aida/utildb/dbcomponent"
 	"github.com/stretchr/testify/require"
 
@@ -1205,6 +1207,19 @@ func TestCommands(t *testing.T) {
 				require.NoError(t, err)
 			},
 		},
+		{
+			cmd: printDeletionsCommand,
+			args: []string{
+				printDeletionsCommand.Name,
+				"--aida-db",
+				dbPath,
+				strconv.FormatUint(ss.Block-1, 10),
+				strconv.FormatUint(ss.Block+1, 10),
+			},
+			setup: func() {
+				// deletions are set up by the del-acc command
+			},
+		},
 		{
 			cmd: dumpSubstateCommand,
 			args: []string{
@@ -1312,6 +1327,23 @@ func TestCommands(t *testing.T) {
 				// ignored
 			},
 		},
+		{
+			cmd: printSizeCommand,
+			args: []string{
+				printSizeCommand.Name,
+				"--aida-db",
+				dbPath,
+				"--format",
+				"json",
+				"--sample",
+				"0.5",
+				"-l",
+				"CRITICAL",
+			},
+			setup: func() {
+				// ignored
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.cmd.Name, func(t *testing.T) {
@@ -1326,3 +1358,61 @@ func TestCommands(t *testing.T) {
 	}
 
 }
+
+func TestInfo_PrintSizes(t *testing.T) {
+	sizes := []utildb.DbComponentSize{
+		{Component: "substate", Keys: 2, KeyBytes: 36, ValueBytes: 200},
+		{Component: "code", Keys: 1000, KeyBytes: 34000, ValueBytes: 5000, Estimated: true},
+		{Component: "total", Keys: 1002, KeyBytes: 34036, ValueBytes: 5200, Estimated: true},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var out strings.Builder
+		require.NoError(t, printSizes(&out, sizes, "table"))
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 4)
+		assert.Equal(t, []string{"COMPONENT", "KEYS", "KEY", "BYTES", "VALUE", "BYTES"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"substate", "2", "36", "200"}, strings.Fields(lines[1]))
+		assert.Equal(t, []string{"code", "(est.)", "1000", "34000", "5000"}, strings.Fields(lines[2]))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out strings.Builder
+		require.NoError(t, printSizes(&out, sizes, "json"))
+		var decoded []utildb.DbComponentSize
+		require.NoError(t, json.Unmarshal([]byte(out.String()), &decoded))
+		assert.Equal(t, sizes, decoded)
+	})
+}
+
+func TestInfo_PrintSize_RejectsUnknownFormat(t *testing.T) {
+	app := cli.NewApp()
+	app.Action = printSizeCommand.Action
+	app.Flags = printSizeCommand.Flags
+
+	err := app.Run([]string{printSizeCommand.Name, "--aida-db", t.TempDir(), "--format", "xml"})
+	require.ErrorContains(t, err, "unknown format \"xml\"")
+}
+
+func TestInfo_PrintDeletions(t *testing.T) {
+	ddb, err := db.NewDefaultDestroyedAccountDB(t.TempDir())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, ddb.Close())
+	}()
+
+	a, b := types.Address{0xa}, types.Address{0xb}
+	require.NoError(t, ddb.SetDestroyedAccounts(9, 0, []types.Address{a}, nil))
+	require.NoError(t, ddb.SetDestroyedAccounts(10, 1, []types.Address{a}, nil))
+	require.NoError(t, ddb.SetDestroyedAccounts(10, 3, []types.Address{b}, []types.Address{a}))
+	require.NoError(t, ddb.SetDestroyedAccounts(11, 0, []types.Address{b}, nil))
+
+	var out strings.Builder
+	require.NoError(t, printDeletions(&out, ddb, 10, 10))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, []string{"BLOCK", "TX", "STATUS", "ACCOUNT"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"10", "1", "destroyed", a.String()}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"10", "3", "destroyed", b.String()}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"10", "3", "resurrected", a.String()}, strings.Fields(lines[3]))
+}
@@ -68,9 +68,9 @@ func printRange(cfg *utils.Config, log logger.Logger) error {
 		if err != nil {
 			return err
 		}
-		err = sdb.SetSubstateEncoding(cfg.SubstateEncoding)
+		_, err = utils.ApplySubstateEncoding(sdb, cfg.SubstateEncoding)
 		if err != nil {
-			return fmt.Errorf("cannot set substate encoding; %w", err)
+			return err
 		}
 
 		firstBlock, lastBlock, ok := utils.FindBlockRangeInSubstate(sdb)
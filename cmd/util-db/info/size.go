@@ -0,0 +1,117 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package info
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"text/tabwriter"
+
+	"github.com/0xsoniclabs/aida/cmd/util-db/flags"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utildb"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/urfave/cli/v2"
+)
+
+var printSizeCommand = cli.Command{
+	Action:    printSizeAction,
+	Name:      "size",
+	Usage:     "Prints number of keys and occupied bytes of each AidaDb component.",
+	ArgsUsage: "[<firstBlockNum> <lastBlockNum>]",
+	Flags: []cli.Flag{
+		&utils.AidaDbFlag,
+		&flags.SampleRate,
+		&flags.SizeFormat,
+		&logger.LogLevelFlag,
+	},
+	Description: `
+Iterates keys of substates, update-sets, deleted accounts, state hashes,
+block hashes, exceptions, codes and metadata and prints the number of keys,
+key bytes and value bytes of each component together with the total.
+
+If a block range is given, block related components are only measured
+within the range. Codes and metadata are always measured in full.
+
+Since a full iteration of a large AidaDb is expensive, --sample=<fraction>
+scans only the given fraction of block and code keys and extrapolates the
+result. Estimated sizes are marked in the output.
+`,
+}
+
+// printSizeAction prints sizes of all components of given AidaDb
+func printSizeAction(ctx *cli.Context) error {
+	format := ctx.String(flags.SizeFormat.Name)
+	if format != "table" && format != "json" {
+		return fmt.Errorf("unknown format %q; supported formats are table and json", format)
+	}
+
+	mode := utils.NoArgs
+	if ctx.Args().Len() > 0 {
+		mode = utils.BlockRangeArgs
+	}
+	cfg, err := utils.NewConfig(ctx, mode)
+	if err != nil {
+		return err
+	}
+	if mode == utils.NoArgs {
+		cfg.First, cfg.Last = 0, math.MaxUint64
+	}
+
+	log := logger.NewLogger(cfg.LogLevel, "AidaDb-Size")
+
+	base, err := db.NewReadOnlySubstateDB(cfg.AidaDb)
+	if err != nil {
+		return err
+	}
+	defer utildb.MustCloseDB(base)
+
+	log.Noticef("Measuring database between blocks %v-%v", cfg.First, cfg.Last)
+	sizes, err := utildb.GetDbSize(base, cfg.First, cfg.Last, ctx.Float64(flags.SampleRate.Name))
+	if err != nil {
+		return err
+	}
+	return printSizes(os.Stdout, sizes, format)
+}
+
+// printSizes writes given sizes in requested format.
+func printSizes(out io.Writer, sizes []utildb.DbComponentSize, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(sizes)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	if _, err := fmt.Fprintln(w, "COMPONENT\tKEYS\tKEY BYTES\tVALUE BYTES\t"); err != nil {
+		return err
+	}
+	for _, s := range sizes {
+		name := s.Component
+		if s.Estimated {
+			name += " (est.)"
+		}
+		if _, err := fmt.Fprintf(w, "%v\t%d\t%d\t%d\t\n", name, s.Keys, s.KeyBytes, s.ValueBytes); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -22,13 +22,18 @@ import (
 
 	"github.com/0xsoniclabs/aida/cmd/util-db/clone"
 	"github.com/0xsoniclabs/aida/cmd/util-db/compact"
+	"github.com/0xsoniclabs/aida/cmd/util-db/convert"
 	"github.com/0xsoniclabs/aida/cmd/util-db/db"
+	"github.com/0xsoniclabs/aida/cmd/util-db/export"
 	"github.com/0xsoniclabs/aida/cmd/util-db/generate"
 	"github.com/0xsoniclabs/aida/cmd/util-db/info"
 	"github.com/0xsoniclabs/aida/cmd/util-db/merge"
 	"github.com/0xsoniclabs/aida/cmd/util-db/metadata"
 	"github.com/0xsoniclabs/aida/cmd/util-db/primer"
+	"github.com/0xsoniclabs/aida/cmd/util-db/prune"
 	"github.com/0xsoniclabs/aida/cmd/util-db/scrape"
+	"github.com/0xsoniclabs/aida/cmd/util-db/shrink"
+	"github.com/0xsoniclabs/aida/cmd/util-db/synthetic"
 	"github.com/0xsoniclabs/aida/cmd/util-db/validate"
 	"github.com/urfave/cli/v2"
 )
@@ -42,13 +47,18 @@ var UtilDbApp = cli.App{
 	Commands: []*cli.Command{
 		&clone.Command,
 		&compact.Command,
+		&prune.Command,
+		&convert.Command,
 		&merge.Command,
 		&info.Command,
 		&validate.Command,
 		&metadata.Command,
 		&generate.Command,
 		&db.UpdateCommand,
+		&export.Command,
 		&scrape.Command,
+		&shrink.Command,
+		&synthetic.Command,
 
 		//Priming only
 		&primer.RunPrimerCmd,
@@ -38,11 +38,15 @@ var Command = cli.Command{
 		&logger.LogLevelFlag,
 		&utils.CompactDbFlag,
 		&flags.SkipMetadata,
+		&flags.DryRun,
 		&utils.SubstateEncodingFlag,
 	},
 	Description: `
 Creates target aida-db by merging source databases from arguments:
 <db1> [<db2> <db3> ...]
+
+With --dry-run, only the source databases are opened and the merge plan
+including conflicts between the sources is printed.
 `,
 }
 
@@ -58,6 +62,12 @@ func mergeAction(ctx *cli.Context) error {
 		sourcePaths[i] = ctx.Args().Get(i)
 	}
 
+	if cfg.DryRun {
+		return dryRunMerge(cfg, sourcePaths)
+	}
+
+	log := logger.NewLogger(cfg.LogLevel, "Merge")
+
 	targetDb, err := db.NewDefaultSubstateDB(cfg.AidaDb)
 	if err != nil {
 		return fmt.Errorf("cannot open db; %v", err)
@@ -73,6 +83,13 @@ func mergeAction(ctx *cli.Context) error {
 		if err != nil {
 			return fmt.Errorf("cannot open source databases: %w", err)
 		}
+		plan, err := utildb.PlanMerge(cfg, dbs, sourcePaths)
+		if err != nil {
+			return err
+		}
+		for _, conflict := range plan.Conflicts {
+			log.Warningf("Conflict - %v", conflict)
+		}
 		md, err = utils.ProcessMergeMetadata(cfg, targetDb, dbs, sourcePaths)
 		if err != nil {
 			return err
@@ -95,3 +112,29 @@ func mergeAction(ctx *cli.Context) error {
 	m.CloseSourceDbs()
 	return m.FinishMerge()
 }
+
+// dryRunMerge prints the merge plan of given source databases without touching the target database.
+func dryRunMerge(cfg *utils.Config, sourcePaths []string) error {
+	log := logger.NewLogger(cfg.LogLevel, "Merge-Dry-Run")
+
+	dbs, err := utildb.OpenSourceDatabases(sourcePaths)
+	if err != nil {
+		return fmt.Errorf("cannot open source databases: %w", err)
+	}
+	defer func() {
+		for _, database := range dbs {
+			utildb.MustCloseDB(database)
+		}
+	}()
+
+	plan, err := utildb.PlanMerge(cfg, dbs, sourcePaths)
+	if err != nil {
+		return err
+	}
+	if err = plan.EstimateSizes(dbs); err != nil {
+		return err
+	}
+
+	plan.Print(log)
+	return plan.Err()
+}
@@ -165,6 +165,42 @@ func TestMerge_Command_Errors(t *testing.T) {
 	}
 }
 
+func TestMerge_DryRunReportsConflictsWithoutWritingTarget(t *testing.T) {
+	createSource := func(path string, first, last uint64) {
+		sdb, err := db.NewDefaultSubstateDB(path)
+		require.NoError(t, err)
+		md := utils.NewAidaDbMetadata(sdb, "CRITICAL")
+		require.NoError(t, md.SetChainID(utils.OperaMainnetChainID))
+		require.NoError(t, md.SetFirstBlock(first))
+		require.NoError(t, md.SetLastBlock(last))
+		require.NoError(t, sdb.Close())
+	}
+	path1 := t.TempDir() + "/sdb1"
+	path2 := t.TempDir() + "/sdb2"
+	createSource(path1, 10, 30)
+	createSource(path2, 20, 40)
+	targetPath := t.TempDir() + "/target"
+
+	app := cli.NewApp()
+	app.Action = mergeAction
+	app.Flags = Command.Flags
+
+	err := app.Run([]string{
+		Command.Name,
+		"--aida-db",
+		targetPath,
+		"-l",
+		"CRITICAL",
+		"--dry-run",
+		path1,
+		path2,
+	})
+	require.ErrorContains(t, err, fmt.Sprintf("blocks 20-30 are contained in both %v and %v", path1, path2))
+
+	_, err = os.Stat(targetPath)
+	require.True(t, os.IsNotExist(err), "dry-run must not create the target db")
+}
+
 // TestCmd_RunMergeCommand tests the MergeCommand
 func TestCmd_RunMergeCommand(t *testing.T) {
 	tempDir := t.TempDir()
@@ -39,10 +39,14 @@ var RunPrimerCmd = cli.Command{
 		&utils.StateDbSrcFlag,
 		&utils.DbTmpFlag,
 		&utils.StateDbLoggingFlag,
+		&utils.StateDbLoggingFormatFlag,
+		&utils.StateDbLoggingFilterFlag,
 
 		// ArchiveDb
 		&utils.ArchiveModeFlag,
 		&utils.ArchiveQueryRateFlag,
+		&utils.ArchiveQueryBurstFlag,
+		&utils.ArchiveQueryWarmUpFlag,
 		&utils.ArchiveMaxQueryAgeFlag,
 		&utils.ArchiveVariantFlag,
 
@@ -57,13 +61,19 @@ var RunPrimerCmd = cli.Command{
 
 		// Priming
 		&utils.RandomizePrimingFlag,
+		&utils.PrimeShuffleWindowFlag,
 		&utils.UpdateBufferSizeFlag,
+		&utils.UpdateBufferStrictFlag,
+		&utils.UpdateDbFlag,
+		&utils.UpdateCacheDirFlag,
+		&utils.UpdateCacheSizeFlag,
 
 		// Utils
 		&utils.CustomDbNameFlag,
 		&logger.LogLevelFlag,
 		&utils.TrackProgressFlag,
 		&utils.ErrorLoggingFlag,
+		&utils.ErrorLoggingFormatFlag,
 	},
 	Description: `
 The util-primer priming command requires one argument: <blockNum>
@@ -0,0 +1,77 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package prune
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xsoniclabs/aida/cmd/util-db/flags"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utildb"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/urfave/cli/v2"
+)
+
+// Command removes old blocks from an AidaDb in place.
+var Command = cli.Command{
+	Action: pruneAction,
+	Name:   "prune",
+	Usage:  "removes all blocks below --keep-from from the aida-db in place",
+	Flags: []cli.Flag{
+		&utils.AidaDbFlag,
+		&flags.KeepFrom,
+		&flags.PruneCompact,
+		&flags.ForcePrune,
+		&logger.LogLevelFlag,
+	},
+	Description: `
+Removes substates, update sets, destroyed accounts and state hashes of all blocks
+below --keep-from from the aida-db and moves the first block of its metadata to
+--keep-from. The pruning is refused if --keep-from does not directly follow an
+update set, since the retained update set would span pruned blocks; use --force
+to prune anyway. An interrupted pruning is completed by running the command again
+with the same --keep-from. With --compact, the pruned components are compacted
+afterwards to release the disk space.`,
+}
+
+func pruneAction(ctx *cli.Context) (finalErr error) {
+	if !ctx.IsSet(flags.KeepFrom.Name) {
+		return fmt.Errorf("--%v must be set", flags.KeepFrom.Name)
+	}
+	keepFrom := ctx.Uint64(flags.KeepFrom.Name)
+	logLevel := ctx.String(logger.LogLevelFlag.Name)
+	log := logger.NewLogger(logLevel, "aida-db-prune")
+
+	aidaDb, err := db.NewDefaultSubstateDB(ctx.String(utils.AidaDbFlag.Name))
+	if err != nil {
+		return fmt.Errorf("cannot open aida-db; %w", err)
+	}
+	defer func() {
+		finalErr = errors.Join(finalErr, aidaDb.Close())
+	}()
+
+	md := utils.NewAidaDbMetadata(aidaDb, logLevel)
+	if err = utildb.PruneDb(md, keepFrom, ctx.Bool(flags.ForcePrune.Name), log); err != nil {
+		return err
+	}
+	if !ctx.Bool(flags.PruneCompact.Name) {
+		return nil
+	}
+	return utildb.CompactDb(aidaDb, utildb.PrunedComponents, log)
+}
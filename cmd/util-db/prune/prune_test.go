@@ -0,0 +1,95 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package prune
+
+import (
+	"testing"
+
+	"github.com/0xsoniclabs/aida/cmd/util-db/flags"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func newPruneApp() *cli.App {
+	app := cli.NewApp()
+	app.Action = pruneAction
+	app.Flags = []cli.Flag{
+		&utils.AidaDbFlag,
+		&flags.KeepFrom,
+		&flags.PruneCompact,
+		&flags.ForcePrune,
+		&logger.LogLevelFlag,
+	}
+	return app
+}
+
+// createAidaDb creates an AidaDb of blocks 1-10 with a substate per block and update sets at blocks 4 and 9.
+func createAidaDb(t *testing.T) string {
+	path := t.TempDir()
+	database, err := db.NewDefaultSubstateDB(path)
+	require.NoError(t, err)
+	for block := uint64(1); block <= 10; block++ {
+		require.NoError(t, database.Put(db.SubstateDBKey(block, 0), []byte{1}))
+	}
+	require.NoError(t, database.Put(db.UpdateDBKey(4), []byte{1}))
+	require.NoError(t, database.Put(db.UpdateDBKey(9), []byte{1}))
+	md := utils.NewAidaDbMetadata(database, "ERROR")
+	require.NoError(t, md.SetFirstBlock(1))
+	require.NoError(t, md.SetLastBlock(10))
+	require.NoError(t, database.Close())
+	return path
+}
+
+func TestCmd_PruneAndCompact(t *testing.T) {
+	path := createAidaDb(t)
+	err := newPruneApp().Run([]string{Command.Name, "--aida-db", path, "--keep-from", "5", "--compact"})
+	require.NoError(t, err)
+
+	database, err := db.NewDefaultSubstateDB(path)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, database.Close())
+	}()
+	for block := uint64(1); block <= 10; block++ {
+		has, err := database.Has(db.SubstateDBKey(block, 0))
+		require.NoError(t, err)
+		assert.Equal(t, block >= 5, has, "substate of block %d", block)
+	}
+	has, err := database.Has(db.UpdateDBKey(4))
+	require.NoError(t, err)
+	assert.False(t, has)
+	assert.Equal(t, uint64(5), utils.NewAidaDbMetadata(database, "ERROR").GetFirstBlock())
+}
+
+func TestCmd_PruneRequiresForceInUpdateSetInterval(t *testing.T) {
+	path := createAidaDb(t)
+	err := newPruneApp().Run([]string{Command.Name, "--aida-db", path, "--keep-from", "7"})
+	require.ErrorContains(t, err, "use --force")
+
+	err = newPruneApp().Run([]string{Command.Name, "--aida-db", path, "--keep-from", "7", "--force"})
+	require.NoError(t, err)
+}
+
+func TestCmd_PruneRequiresKeepFrom(t *testing.T) {
+	path := createAidaDb(t)
+	err := newPruneApp().Run([]string{Command.Name, "--aida-db", path})
+	require.ErrorContains(t, err, "--keep-from must be set")
+}
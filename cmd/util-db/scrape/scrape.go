@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/0xsoniclabs/aida/cmd/util-db/flags"
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/0xsoniclabs/substate/db"
@@ -32,14 +33,24 @@ import (
 var Command = cli.Command{
 	Action:    scrapeAction,
 	Name:      "scrape",
-	Usage:     "Stores state hashes into TargetDb for given range",
+	Usage:     "Stores state hashes, or substates with --source rpc, into TargetDb for given range",
 	ArgsUsage: "<blockNumFirst> <blockNumLast>",
 	Flags: []cli.Flag{
 		&utils.TargetDbFlag,
 		&utils.ChainIDFlag,
 		&utils.ClientDbFlag,
+		&flags.ScrapeSource,
+		&flags.RpcEndpoint,
+		&flags.RpcBatchSize,
+		&utils.SubstateEncodingFlag,
 		&logger.LogLevelFlag,
 	},
+	Description: `
+The scrape command stores state and block hashes of an Opera/Sonic node into TargetDb.
+With --source rpc, substates are reconstructed from the prestate traces of a standard
+eth JSON-RPC node given by --rpc-endpoint. State hashes are requested by batches of
+--rpc-batch-size blocks. An interrupted scrape resumes at the first block without a
+stored state hash.`,
 }
 
 // scrapeAction stores state hashes into Target for given range
@@ -49,6 +60,18 @@ func scrapeAction(ctx *cli.Context) (err error) {
 		return argErr
 	}
 
+	source := ctx.String(flags.ScrapeSource.Name)
+	endpoint := ctx.String(flags.RpcEndpoint.Name)
+	switch source {
+	case "node":
+	case "rpc":
+		if endpoint == "" {
+			return fmt.Errorf("--%v is required by --%v rpc", flags.RpcEndpoint.Name, flags.ScrapeSource.Name)
+		}
+	default:
+		return fmt.Errorf("unknown --%v %q; must be one of node or rpc", flags.ScrapeSource.Name, source)
+	}
+
 	log := logger.NewLogger(cfg.LogLevel, "UtilDb-Scrape")
 	log.Infof("Scraping for range %d-%d", cfg.First, cfg.Last)
 
@@ -60,7 +83,11 @@ func scrapeAction(ctx *cli.Context) (err error) {
 		err = errors.Join(err, database.Close())
 	}(database)
 
-	err = StateAndBlockHashScraper(ctx.Context, cfg.ChainID, cfg.ClientDb, database, cfg.First, cfg.Last, log)
+	if source == "rpc" {
+		err = SubstateScraper(ctx.Context, cfg, endpoint, database, log)
+	} else {
+		err = StateAndBlockHashScraper(ctx.Context, cfg.ChainID, cfg.ClientDb, database, cfg.First, cfg.Last, ctx.Int(flags.RpcBatchSize.Name), log)
+	}
 	if err != nil {
 		return err
 	}
@@ -69,65 +96,18 @@ func scrapeAction(ctx *cli.Context) (err error) {
 	return nil
 }
 
-// StateAndBlockHashScraper scrapes state and block hashes from a node and saves them to a leveldb database
-func StateAndBlockHashScraper(ctx context.Context, chainId utils.ChainID, clientDb string, bdb db.BaseDB, firstBlock, lastBlock uint64, log logger.Logger) error {
+// StateAndBlockHashScraper scrapes state and block hashes from a node and saves them to a leveldb database.
+// The blocks are requested by JSON-RPC batch requests of batchSize blocks, or one by one if the node
+// rejects batch requests. The scraped range is recorded in the database, hence an interrupted scrape
+// resumes after the last block of the range.
+func StateAndBlockHashScraper(ctx context.Context, chainId utils.ChainID, clientDb string, bdb db.BaseDB, firstBlock, lastBlock uint64, batchSize int, log logger.Logger) error {
 	client, err := getClient(ctx, chainId, clientDb, log)
 	if err != nil {
 		return err
 	}
 	defer client.Close()
 
-	var i = firstBlock
-
-	// If firstBlock is 0, we need to get the state root for block 1 and save it as the state root for block 0
-	// this is because the correct state root for block 0 is not available from the rpc node (at least in fantom mainnet and testnet)
-	if firstBlock == 0 {
-		block, err := db.GetBlockByNumber(client, "0x1")
-		if err != nil {
-			return err
-		}
-
-		if block == nil {
-			return fmt.Errorf("block 1 not found")
-		}
-
-		err = db.SaveStateRoot(bdb, "0x0", block["stateRoot"].(string))
-		if err != nil {
-			return err
-		}
-		err = db.SaveBlockHash(bdb, "0x1", block["hash"].(string))
-		if err != nil {
-			return err
-		}
-		i++
-	}
-
-	for ; i <= lastBlock; i++ {
-		blockNumber := fmt.Sprintf("0x%x", i)
-		block, err := db.GetBlockByNumber(client, blockNumber)
-		if err != nil {
-			return err
-		}
-
-		if block == nil {
-			return fmt.Errorf("block %d not found", i)
-		}
-
-		err = db.SaveStateRoot(bdb, blockNumber, block["stateRoot"].(string))
-		if err != nil {
-			return err
-		}
-		err = db.SaveBlockHash(bdb, blockNumber, block["hash"].(string))
-		if err != nil {
-			return err
-		}
-
-		if i%10000 == 0 {
-			log.Infof("Scraping block %d done!\n", i)
-		}
-	}
-
-	return nil
+	return newStateHashScraper(client, bdb, batchSize, log).scrape(ctx, firstBlock, lastBlock)
 }
 
 // getClient returns a rpc/ipc client
@@ -66,7 +66,7 @@ func TestStateHash_ZeroHasSameStateHashAsOne(t *testing.T) {
 	}
 	log := logger.NewLogger("info", "Test state hash")
 
-	err = StateAndBlockHashScraper(context.TODO(), utils.OperaTestnetChainID, "", database, 0, 1, log)
+	err = StateAndBlockHashScraper(context.TODO(), utils.OperaTestnetChainID, "", database, 0, 1, 64, log)
 	if err != nil {
 		t.Fatalf("error scraping state hashes: %v", err)
 	}
@@ -115,7 +115,7 @@ func TestStateHash_Log(t *testing.T) {
 	log.EXPECT().Infof("Connected to RPC at %s", utils.RPCTestnet)
 	log.EXPECT().Infof("Scraping block %d done!\n", uint64(10000))
 
-	err = StateAndBlockHashScraper(context.TODO(), utils.OperaTestnetChainID, "", database, 9990, 10100, log)
+	err = StateAndBlockHashScraper(context.TODO(), utils.OperaTestnetChainID, "", database, 9990, 10100, 64, log)
 	if err != nil {
 		t.Fatalf("error scraping state hashes: %v", err)
 	}
@@ -131,7 +131,7 @@ func TestStateHash_ZeroHasDifferentStateHashAfterHundredBlocks(t *testing.T) {
 	}
 	log := logger.NewLogger("info", "Test state hash")
 
-	err = StateAndBlockHashScraper(context.TODO(), utils.OperaTestnetChainID, "", database, 0, 100, log)
+	err = StateAndBlockHashScraper(context.TODO(), utils.OperaTestnetChainID, "", database, 0, 100, 64, log)
 	if err != nil {
 		t.Fatalf("error scraping state hashes: %v", err)
 	}
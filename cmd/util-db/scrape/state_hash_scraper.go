@@ -0,0 +1,269 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package scrape
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const (
+	// maxBlockRetries is the number of times a failed block is requested again before the scrape fails.
+	maxBlockRetries = 8
+	blockRetryDelay = 500 * time.Millisecond
+)
+
+// stateHashProgressKey records the progress of state hash scraping in the target database. Its value
+// is the first scraped block followed by the highest block such that all blocks in between are stored.
+var stateHashProgressKey = []byte(db.MetadataPrefix + "sp")
+
+// rpcBatchCaller is the part of rpc.Client used by the state hash scraper.
+type rpcBatchCaller interface {
+	rpcCaller
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// blockHashes are the fields of an eth_getBlockByNumber response stored by the state hash scraper.
+type blockHashes struct {
+	Hash      common.Hash `json:"hash"`
+	StateRoot common.Hash `json:"stateRoot"`
+}
+
+type stateHashScraper struct {
+	client     rpcBatchCaller
+	database   db.BaseDB
+	log        logger.Logger
+	batchSize  int
+	maxRetries int
+	retryDelay time.Duration
+	noBatches  bool // set once the server rejected a batch request
+
+	progressFrom uint64 // first block of the contiguous range of stored blocks
+	done         uint64 // last block of the contiguous range of stored blocks
+	hasProgress  bool
+}
+
+func newStateHashScraper(client rpcBatchCaller, database db.BaseDB, batchSize int, log logger.Logger) *stateHashScraper {
+	return &stateHashScraper{
+		client:     client,
+		database:   database,
+		log:        log,
+		batchSize:  max(batchSize, 1),
+		maxRetries: maxBlockRetries,
+		retryDelay: blockRetryDelay,
+	}
+}
+
+func (s *stateHashScraper) scrape(ctx context.Context, first, last uint64) (err error) {
+	start, err := s.resume(first, last)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, s.writeProgress())
+	}()
+
+	// If first is 0, the state root of block 1 is stored as the state root of block 0, because the
+	// correct state root of block 0 is not available from the rpc node (at least in fantom mainnet and testnet)
+	if start == 0 {
+		block, err := s.fetchBlock(ctx, 1)
+		if err != nil {
+			return fmt.Errorf("cannot scrape block 1; %w", err)
+		}
+		if err = db.SaveStateRoot(s.database, "0x0", block.StateRoot.Hex()); err != nil {
+			return err
+		}
+		if err = db.SaveBlockHash(s.database, "0x1", block.Hash.Hex()); err != nil {
+			return err
+		}
+		s.completed(0)
+		start++
+	}
+
+	for from := start; from <= last; from += uint64(s.batchSize) {
+		if err = s.scrapeBatch(ctx, from, min(from+uint64(s.batchSize)-1, last)); err != nil {
+			return err
+		}
+		if err = s.writeProgress(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resume returns the first block to scrape. Blocks stored by an earlier scrape are skipped if
+// its contiguous range of stored blocks covers the first block.
+func (s *stateHashScraper) resume(first, last uint64) (uint64, error) {
+	s.progressFrom = first
+	value, err := s.database.Get(stateHashProgressKey)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return first, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot read scrape progress; %w", err)
+	}
+	if len(value) != 16 {
+		return 0, fmt.Errorf("invalid scrape progress %#x", value)
+	}
+
+	from, done := bigendian.BytesToUint64(value[:8]), bigendian.BytesToUint64(value[8:])
+	if from > first || done < first {
+		return first, nil
+	}
+	s.progressFrom, s.done, s.hasProgress = from, done, true
+	s.log.Noticef("Blocks %d-%d are already scraped, resuming at block %d", first, min(done, last), done+1)
+	return done + 1, nil
+}
+
+// completed extends the contiguous range of stored blocks by given block.
+func (s *stateHashScraper) completed(number uint64) {
+	s.done, s.hasProgress = number, true
+	if number > 0 && number%10000 == 0 {
+		s.log.Infof("Scraping block %d done!\n", number)
+	}
+}
+
+// writeProgress records the contiguous range of stored blocks.
+func (s *stateHashScraper) writeProgress() error {
+	if !s.hasProgress {
+		return nil
+	}
+	value := append(bigendian.Uint64ToBytes(s.progressFrom), bigendian.Uint64ToBytes(s.done)...)
+	if err := s.database.Put(stateHashProgressKey, value); err != nil {
+		return fmt.Errorf("cannot write scrape progress; %w", err)
+	}
+	return nil
+}
+
+// scrapeBatch stores the hashes of blocks from-to in ascending order. The blocks are requested by
+// a single batch request first, blocks missing in its response are requested one by one.
+func (s *stateHashScraper) scrapeBatch(ctx context.Context, from, to uint64) error {
+	blocks := make([]blockHashes, to-from+1)
+	received := make([]bool, len(blocks))
+	if len(blocks) > 1 && !s.noBatches {
+		s.requestBatch(ctx, from, blocks, received)
+	}
+
+	for i := range blocks {
+		number := from + uint64(i)
+		if !received[i] {
+			block, err := s.fetchBlock(ctx, number)
+			if err != nil {
+				return fmt.Errorf("cannot scrape block %d; %w", number, err)
+			}
+			blocks[i] = block
+		}
+
+		blockNumber := hexutil.EncodeUint64(number)
+		if err := db.SaveStateRoot(s.database, blockNumber, blocks[i].StateRoot.Hex()); err != nil {
+			return err
+		}
+		if err := db.SaveBlockHash(s.database, blockNumber, blocks[i].Hash.Hex()); err != nil {
+			return err
+		}
+		s.completed(number)
+	}
+	return nil
+}
+
+// requestBatch requests the blocks starting at from by a single batch request and marks the received ones.
+func (s *stateHashScraper) requestBatch(ctx context.Context, from uint64, blocks []blockHashes, received []bool) {
+	batch := make([]rpc.BatchElem, len(blocks))
+	for i := range batch {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []any{hexutil.EncodeUint64(from + uint64(i)), false},
+			Result: &blocks[i],
+		}
+	}
+
+	if err := s.client.BatchCallContext(ctx, batch); err != nil {
+		if isBatchRejected(err) {
+			s.noBatches = true
+			s.log.Warningf("Batch requests are rejected by the node, falling back to single requests; %v", err)
+		} else {
+			s.log.Warningf("Batch request of blocks %d-%d failed, requesting them one by one; %v", from, from+uint64(len(blocks))-1, err)
+		}
+		return
+	}
+	for i, elem := range batch {
+		received[i] = elem.Error == nil && blocks[i].Hash != (common.Hash{})
+	}
+}
+
+// fetchBlock requests given block. Failed requests are retried with an exponential backoff
+// until the retry budget of the block is exhausted.
+func (s *stateHashScraper) fetchBlock(ctx context.Context, number uint64) (blockHashes, error) {
+	var block blockHashes
+	for retry := 0; ; retry++ {
+		err := s.client.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false)
+		if err == nil {
+			if block.Hash == (common.Hash{}) {
+				return block, errors.New("block not found")
+			}
+			return block, nil
+		}
+		if retry >= s.maxRetries || ctx.Err() != nil {
+			return block, fmt.Errorf("eth_getBlockByNumber failed; %w", err)
+		}
+
+		delay := s.backoff(retry)
+		s.log.Warningf("Request of block %d failed, retrying in %v; %v", number, delay, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return block, ctx.Err()
+		}
+	}
+}
+
+// backoff returns the delay before the given retry. The delay doubles with each retry and is
+// randomized by up to a half, so that blocks failing together are not requested together again.
+func (s *stateHashScraper) backoff(retry int) time.Duration {
+	delay := s.retryDelay << retry
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// isBatchRejected reports whether the node does not support batch requests.
+// Such nodes either answer with a single error object or with a client error.
+func isBatchRejected(err error) bool {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return true
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 400 && httpErr.StatusCode < 500 && httpErr.StatusCode != http.StatusTooManyRequests
+	}
+	return false
+}
@@ -0,0 +1,275 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package scrape
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/substate/db"
+	substatetypes "github.com/0xsoniclabs/substate/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hashNode is a JSON-RPC server answering eth_getBlockByNumber for blocks up to last,
+// served by single and batch requests.
+type hashNode struct {
+	mu            sync.Mutex
+	last          uint64
+	rejectBatches bool           // batch requests are answered by a single error object
+	failures      map[uint64]int // number of failing requests of a block before it is served
+	batches       int            // number of received batch requests
+	requested     []uint64       // blocks requested by single requests
+}
+
+type hashNodeRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func (n *hashNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		var req hashNodeRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		n.requested = append(n.requested, n.number(req))
+		_, _ = w.Write(n.respond(req))
+		return
+	}
+
+	n.batches++
+	if n.rejectBatches {
+		_, _ = fmt.Fprint(w, `{"jsonrpc": "2.0", "id": null, "error": {"code": -32600, "message": "batch requests are not supported"}}`)
+		return
+	}
+	var reqs []hashNodeRequest
+	if err = json.Unmarshal(body, &reqs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	responses := make([]json.RawMessage, len(reqs))
+	for i, req := range reqs {
+		responses[i] = n.respond(req)
+	}
+	_ = json.NewEncoder(w).Encode(responses)
+}
+
+func (n *hashNode) batchCount() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.batches
+}
+
+func (n *hashNode) requestedBlocks() []uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.requested
+}
+
+func (n *hashNode) number(req hashNodeRequest) uint64 {
+	var number hexutil.Uint64
+	_ = json.Unmarshal(req.Params[0], &number)
+	return uint64(number)
+}
+
+func (n *hashNode) respond(req hashNodeRequest) []byte {
+	number := n.number(req)
+	if n.failures[number] > 0 {
+		n.failures[number]--
+		return fmt.Appendf(nil, `{"jsonrpc": "2.0", "id": %s, "error": {"code": -32000, "message": "internal error"}}`, req.ID)
+	}
+	if number > n.last {
+		return fmt.Appendf(nil, `{"jsonrpc": "2.0", "id": %s, "result": null}`, req.ID)
+	}
+	return fmt.Appendf(nil, `{"jsonrpc": "2.0", "id": %s, "result": {"number": "%s", "hash": "%s", "stateRoot": "%s"}}`,
+		req.ID, hexutil.EncodeUint64(number), testBlockHash(number).Hex(), testStateRoot(number).Hex())
+}
+
+func testBlockHash(number uint64) common.Hash {
+	return common.Hash{0xbb, byte(number)}
+}
+
+func testStateRoot(number uint64) common.Hash {
+	return common.Hash{0x55, byte(number)}
+}
+
+func newTestStateHashScraper(t *testing.T, node *hashNode, database db.BaseDB, batchSize int) *stateHashScraper {
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	client, err := rpc.DialContext(context.Background(), server.URL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	s := newStateHashScraper(client, database, batchSize, logger.NewLogger("critical", "Test-Scrape"))
+	s.maxRetries = 3
+	s.retryDelay = 0
+	return s
+}
+
+func newTestHashDb(t *testing.T) db.BaseDB {
+	database, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, database.Close())
+	})
+	return database
+}
+
+// requireScrapedBlocks checks that the hashes of blocks first-last are stored.
+func requireScrapedBlocks(t *testing.T, database db.BaseDB, first, last uint64) {
+	t.Helper()
+	provider := db.MakeHashProvider(database)
+	for number := first; number <= last; number++ {
+		root, err := provider.GetStateRootHash(int(number))
+		require.NoError(t, err, "block %d", number)
+		require.Equal(t, substatetypes.Hash(testStateRoot(number)), root, "block %d", number)
+		hash, err := provider.GetBlockHash(int(number))
+		require.NoError(t, err, "block %d", number)
+		require.Equal(t, substatetypes.Hash(testBlockHash(number)), hash, "block %d", number)
+	}
+}
+
+func TestStateHashScraper_RequestsBlocksByBatches(t *testing.T) {
+	database := newTestHashDb(t)
+	node := &hashNode{last: 20}
+	s := newTestStateHashScraper(t, node, database, 4)
+
+	require.NoError(t, s.scrape(context.Background(), 1, 10))
+	requireScrapedBlocks(t, database, 1, 10)
+	assert.Equal(t, 3, node.batchCount())
+	assert.Empty(t, node.requestedBlocks())
+}
+
+func TestStateHashScraper_FallsBackToSingleRequestsIfBatchesAreRejected(t *testing.T) {
+	database := newTestHashDb(t)
+	node := &hashNode{last: 20, rejectBatches: true}
+	s := newTestStateHashScraper(t, node, database, 4)
+
+	require.NoError(t, s.scrape(context.Background(), 1, 10))
+	requireScrapedBlocks(t, database, 1, 10)
+	assert.Equal(t, 1, node.batchCount())
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, node.requestedBlocks())
+}
+
+func TestStateHashScraper_RetriesIntermittentlyFailingBlocks(t *testing.T) {
+	database := newTestHashDb(t)
+	node := &hashNode{last: 20, failures: map[uint64]int{3: 2, 6: 1, 10: 3}}
+	s := newTestStateHashScraper(t, node, database, 4)
+
+	require.NoError(t, s.scrape(context.Background(), 1, 10))
+	requireScrapedBlocks(t, database, 1, 10)
+	// failed elements of a batch are requested one by one
+	assert.Equal(t, []uint64{3, 3, 6, 10, 10, 10}, node.requestedBlocks())
+}
+
+func TestStateHashScraper_FailsIfRetryBudgetIsExhausted(t *testing.T) {
+	database := newTestHashDb(t)
+	node := &hashNode{last: 20, failures: map[uint64]int{6: 5}}
+	s := newTestStateHashScraper(t, node, database, 4)
+
+	err := s.scrape(context.Background(), 1, 10)
+	require.ErrorContains(t, err, "cannot scrape block 6")
+	require.ErrorContains(t, err, "internal error")
+	requireScrapedBlocks(t, database, 1, 5)
+
+	// blocks following the failed one are not stored, so the recorded progress leaves no hole
+	found, err := database.Has([]byte(db.StateRootHashPrefix + hexutil.EncodeUint64(7)))
+	require.NoError(t, err)
+	assert.False(t, found)
+	progress, err := database.Get(stateHashProgressKey)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 5}, progress)
+}
+
+func TestStateHashScraper_FailsOnMissingBlock(t *testing.T) {
+	database := newTestHashDb(t)
+	s := newTestStateHashScraper(t, &hashNode{last: 5}, database, 4)
+
+	err := s.scrape(context.Background(), 1, 10)
+	require.ErrorContains(t, err, "cannot scrape block 6; block not found")
+	requireScrapedBlocks(t, database, 1, 5)
+}
+
+func TestStateHashScraper_ResumesAfterLastContiguousBlock(t *testing.T) {
+	database := newTestHashDb(t)
+	node := &hashNode{last: 20, failures: map[uint64]int{6: 5}}
+	require.Error(t, newTestStateHashScraper(t, node, database, 1).scrape(context.Background(), 1, 10))
+
+	node = &hashNode{last: 20}
+	require.NoError(t, newTestStateHashScraper(t, node, database, 1).scrape(context.Background(), 1, 10))
+	requireScrapedBlocks(t, database, 1, 10)
+	assert.Equal(t, []uint64{6, 7, 8, 9, 10}, node.requestedBlocks())
+
+	// a range within the scraped one is not requested again
+	node = &hashNode{last: 20}
+	require.NoError(t, newTestStateHashScraper(t, node, database, 1).scrape(context.Background(), 3, 8))
+	assert.Empty(t, node.requestedBlocks())
+}
+
+func TestStateHashScraper_ProgressOfDisjointRangeIsIgnored(t *testing.T) {
+	database := newTestHashDb(t)
+	require.NoError(t, newTestStateHashScraper(t, &hashNode{last: 20}, database, 1).scrape(context.Background(), 10, 12))
+
+	node := &hashNode{last: 20}
+	require.NoError(t, newTestStateHashScraper(t, node, database, 1).scrape(context.Background(), 1, 3))
+	assert.Equal(t, []uint64{1, 2, 3}, node.requestedBlocks())
+}
+
+func TestStateHashScraper_StateRootOfBlockZeroIsTakenFromBlockOne(t *testing.T) {
+	database := newTestHashDb(t)
+	s := newTestStateHashScraper(t, &hashNode{last: 20}, database, 4)
+
+	require.NoError(t, s.scrape(context.Background(), 0, 2))
+	root, err := db.MakeHashProvider(database).GetStateRootHash(0)
+	require.NoError(t, err)
+	assert.Equal(t, substatetypes.Hash(testStateRoot(1)), root)
+	requireScrapedBlocks(t, database, 1, 2)
+}
+
+func TestStateHashScraper_BackoffDoublesWithJitter(t *testing.T) {
+	s := &stateHashScraper{retryDelay: 100 * time.Millisecond}
+	for retry, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		for range 10 {
+			delay := s.backoff(retry)
+			assert.GreaterOrEqual(t, delay, want/2)
+			assert.LessOrEqual(t, delay, want)
+		}
+	}
+}
@@ -0,0 +1,492 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package scrape
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/0xsoniclabs/substate/substate"
+	substatetypes "github.com/0xsoniclabs/substate/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/holiman/uint256"
+)
+
+const (
+	// limitExceededErrorCode is the JSON-RPC error code of rate limited requests, see EIP-1474.
+	limitExceededErrorCode = -32005
+	maxRateLimitRetries    = 8
+	rateLimitRetryDelay    = time.Second
+)
+
+// rpcCaller is the part of rpc.Client used by the substate scraper.
+type rpcCaller interface {
+	CallContext(ctx context.Context, result any, method string, args ...any) error
+}
+
+// SubstateScraper records substates of blocks first to last from an eth JSON-RPC node into given
+// database. The substates are reconstructed from the prestate traces of the blocks, hence the node
+// has to provide debug_traceBlockByNumber and eth_getBlockReceipts. Blocks whose state root is
+// already stored are skipped, so an interrupted scrape resumes at the first incomplete block.
+func SubstateScraper(ctx context.Context, cfg *utils.Config, endpoint string, database db.SubstateDB, log logger.Logger) error {
+	chainCfg, err := cfg.GetChainConfig("")
+	if err != nil {
+		return err
+	}
+	encoding, err := utils.ApplySubstateEncoding(database, cfg.SubstateEncoding)
+	if err != nil {
+		return err
+	}
+
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the RPC client at %s: %v", endpoint, err)
+	}
+	defer client.Close()
+	log.Infof("Connected to RPC at %s, recording %v encoded substates", endpoint, encoding)
+
+	s := &substateScraper{
+		client:     client,
+		database:   database,
+		chainCfg:   chainCfg,
+		log:        log,
+		maxRetries: maxRateLimitRetries,
+		retryDelay: rateLimitRetryDelay,
+	}
+	return s.scrape(ctx, cfg.First, cfg.Last)
+}
+
+type substateScraper struct {
+	client     rpcCaller
+	database   db.SubstateDB
+	chainCfg   *params.ChainConfig
+	log        logger.Logger
+	maxRetries int
+	retryDelay time.Duration
+}
+
+func (s *substateScraper) scrape(ctx context.Context, first, last uint64) error {
+	start, err := s.findFirstIncompleteBlock(first, last)
+	if err != nil {
+		return err
+	}
+	if start > first {
+		s.log.Noticef("Blocks %d-%d are already scraped, resuming at block %d", first, start-1, start)
+	}
+	for number := start; number <= last; number++ {
+		if err = s.scrapeBlock(ctx, number); err != nil {
+			return fmt.Errorf("cannot scrape block %d; %w", number, err)
+		}
+		if number%10000 == 0 {
+			s.log.Infof("Scraping block %d done!\n", number)
+		}
+	}
+	return nil
+}
+
+// findFirstIncompleteBlock returns the first block of the range without a stored state root.
+// The state root is the last record written for a block, hence it marks completed blocks.
+func (s *substateScraper) findFirstIncompleteBlock(first, last uint64) (uint64, error) {
+	number := first
+	for ; number <= last; number++ {
+		found, err := s.database.Has([]byte(db.StateRootHashPrefix + hexutil.EncodeUint64(number)))
+		if err != nil {
+			return 0, fmt.Errorf("cannot look up state root of block %d; %w", number, err)
+		}
+		if !found {
+			break
+		}
+	}
+	return number, nil
+}
+
+func (s *substateScraper) scrapeBlock(ctx context.Context, number uint64) error {
+	blockNumber := hexutil.EncodeUint64(number)
+
+	var block rpcBlock
+	if err := s.call(ctx, &block, "eth_getBlockByNumber", blockNumber, true); err != nil {
+		return err
+	}
+	if block.Hash == (common.Hash{}) {
+		return errors.New("block not found")
+	}
+
+	if len(block.Transactions) > 0 {
+		var prestates []prestateTrace
+		if err := s.call(ctx, &prestates, "debug_traceBlockByNumber", blockNumber, map[string]any{
+			"tracer": "prestateTracer",
+		}); err != nil {
+			return err
+		}
+		var diffs []prestateDiffTrace
+		if err := s.call(ctx, &diffs, "debug_traceBlockByNumber", blockNumber, map[string]any{
+			"tracer":       "prestateTracer",
+			"tracerConfig": map[string]any{"diffMode": true},
+		}); err != nil {
+			return err
+		}
+		var receipts []rpcReceipt
+		if err := s.call(ctx, &receipts, "eth_getBlockReceipts", blockNumber); err != nil {
+			return err
+		}
+		if len(prestates) != len(block.Transactions) || len(diffs) != len(block.Transactions) || len(receipts) != len(block.Transactions) {
+			return fmt.Errorf("node returned %d prestate traces, %d diff traces and %d receipts for %d transactions",
+				len(prestates), len(diffs), len(receipts), len(block.Transactions))
+		}
+
+		env := s.makeEnv(&block)
+		for i, tx := range block.Transactions {
+			if prestates[i].Error != "" || diffs[i].Error != "" {
+				return fmt.Errorf("cannot trace transaction %v; %v%v", tx.Hash, prestates[i].Error, diffs[i].Error)
+			}
+			result, err := receipts[i].toSubstate()
+			if err != nil {
+				return fmt.Errorf("invalid receipt of transaction %v; %w", tx.Hash, err)
+			}
+			input := prestates[i].Result.toSubstate()
+			ss := substate.NewSubstate(
+				input,
+				applyPrestateDiff(input, diffs[i].Result.Pre, diffs[i].Result.Post),
+				env,
+				tx.toSubstate(),
+				result,
+				number,
+				i,
+			)
+			if err = s.database.PutSubstate(ss); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := db.SaveBlockHash(s.database, blockNumber, block.Hash.Hex()); err != nil {
+		return err
+	}
+	return db.SaveStateRoot(s.database, blockNumber, block.StateRoot.Hex())
+}
+
+// call performs given RPC call. Rate limited calls are retried with an exponential backoff.
+func (s *substateScraper) call(ctx context.Context, result any, method string, args ...any) error {
+	delay := s.retryDelay
+	for attempt := 0; ; attempt++ {
+		err := s.client.CallContext(ctx, result, method, args...)
+		if err == nil {
+			return nil
+		}
+		if !isRateLimited(err) || attempt >= s.maxRetries {
+			return fmt.Errorf("%v failed; %w", method, err)
+		}
+		s.log.Warningf("%v is rate limited, retrying in %v", method, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+func isRateLimited(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == limitExceededErrorCode
+	}
+	return false
+}
+
+// makeEnv creates the block environment shared by all substates of given block.
+// Block hashes are not part of the environment, the BLOCKHASH opcode is served
+// by the scraped block hashes instead.
+func (s *substateScraper) makeEnv(block *rpcBlock) *substate.Env {
+	var random *substatetypes.Hash
+	difficulty := block.Difficulty.ToInt()
+	if difficulty == nil || difficulty.Sign() == 0 {
+		// after the merge, the mix hash holds the RANDAO value
+		mixHash := substatetypes.Hash(block.MixHash)
+		random = &mixHash
+		difficulty = new(big.Int)
+	}
+	var blobBaseFee *big.Int
+	if block.ExcessBlobGas != nil && s.chainCfg.IsCancun(new(big.Int).SetUint64(uint64(block.Number)), uint64(block.Timestamp)) {
+		excessBlobGas := uint64(*block.ExcessBlobGas)
+		blobBaseFee = eip4844.CalcBlobFee(s.chainCfg, &types.Header{
+			Number:        new(big.Int).SetUint64(uint64(block.Number)),
+			Time:          uint64(block.Timestamp),
+			ExcessBlobGas: &excessBlobGas,
+		})
+	}
+	return substate.NewEnv(
+		substatetypes.Address(block.Miner),
+		difficulty,
+		uint64(block.GasLimit),
+		uint64(block.Number),
+		uint64(block.Timestamp),
+		block.BaseFee.ToInt(),
+		blobBaseFee,
+		nil,
+		random,
+	)
+}
+
+// applyPrestateDiff derives the output alloc of a transaction from its input alloc and
+// the pre and post state of the modified accounts. Accounts missing in the post state
+// have been deleted, storage slots missing in the post state have been cleared.
+func applyPrestateDiff(input substate.WorldState, pre, post rpcAlloc) substate.WorldState {
+	output := substate.NewWorldState()
+	for addr, acc := range input {
+		output[addr] = copyAccount(acc)
+	}
+	for addr := range pre {
+		if _, found := post[addr]; !found {
+			delete(output, substatetypes.Address(addr))
+		}
+	}
+	for addr, acc := range post {
+		out, found := output[substatetypes.Address(addr)]
+		if !found {
+			out = substate.NewAccount(0, new(uint256.Int), nil)
+			output[substatetypes.Address(addr)] = out
+		}
+		if acc.Balance != nil {
+			out.Balance = uint256.MustFromBig(acc.Balance.ToInt())
+		}
+		if acc.Nonce != 0 {
+			out.Nonce = acc.Nonce
+		}
+		if acc.Code != nil {
+			out.Code = acc.Code
+		}
+		if before, found := pre[addr]; found {
+			for key := range before.Storage {
+				if _, written := acc.Storage[key]; !written {
+					out.Storage[substatetypes.Hash(key)] = substatetypes.Hash{}
+				}
+			}
+		}
+		for key, value := range acc.Storage {
+			out.Storage[substatetypes.Hash(key)] = substatetypes.Hash(value)
+		}
+	}
+	return output
+}
+
+func copyAccount(acc *substate.Account) *substate.Account {
+	res := substate.NewAccount(acc.Nonce, acc.Balance.Clone(), acc.Code)
+	for key, value := range acc.Storage {
+		res.Storage[key] = value
+	}
+	return res
+}
+
+// ----------------------------------------------------------------------------
+//                               RPC Responses
+// ----------------------------------------------------------------------------
+
+type rpcBlock struct {
+	Number        hexutil.Uint64   `json:"number"`
+	Hash          common.Hash      `json:"hash"`
+	StateRoot     common.Hash      `json:"stateRoot"`
+	Miner         common.Address   `json:"miner"`
+	Difficulty    *hexutil.Big     `json:"difficulty"`
+	MixHash       common.Hash      `json:"mixHash"`
+	GasLimit      hexutil.Uint64   `json:"gasLimit"`
+	Timestamp     hexutil.Uint64   `json:"timestamp"`
+	BaseFee       *hexutil.Big     `json:"baseFeePerGas"`
+	ExcessBlobGas *hexutil.Uint64  `json:"excessBlobGas"`
+	Transactions  []rpcTransaction `json:"transactions"`
+}
+
+type rpcTransaction struct {
+	Hash                 common.Hash                  `json:"hash"`
+	Type                 hexutil.Uint64               `json:"type"`
+	From                 common.Address               `json:"from"`
+	To                   *common.Address              `json:"to"`
+	Nonce                hexutil.Uint64               `json:"nonce"`
+	Gas                  hexutil.Uint64               `json:"gas"`
+	GasPrice             *hexutil.Big                 `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big                 `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big                 `json:"maxPriorityFeePerGas"`
+	MaxFeePerBlobGas     *hexutil.Big                 `json:"maxFeePerBlobGas"`
+	Value                *hexutil.Big                 `json:"value"`
+	Input                hexutil.Bytes                `json:"input"`
+	AccessList           types.AccessList             `json:"accessList"`
+	BlobVersionedHashes  []common.Hash                `json:"blobVersionedHashes"`
+	AuthorizationList    []types.SetCodeAuthorization `json:"authorizationList"`
+}
+
+func (tx *rpcTransaction) toSubstate() *substate.Message {
+	// mined transactions report their effective gas price
+	gasPrice := tx.GasPrice.ToInt()
+	gasFeeCap, gasTipCap := gasPrice, gasPrice
+	if tx.MaxFeePerGas != nil {
+		gasFeeCap = tx.MaxFeePerGas.ToInt()
+	}
+	if tx.MaxPriorityFeePerGas != nil {
+		gasTipCap = tx.MaxPriorityFeePerGas.ToInt()
+	}
+
+	var accessList substatetypes.AccessList
+	if tx.Type != types.LegacyTxType {
+		accessList = make(substatetypes.AccessList, 0, len(tx.AccessList))
+		for _, tuple := range tx.AccessList {
+			keys := make([]substatetypes.Hash, 0, len(tuple.StorageKeys))
+			for _, key := range tuple.StorageKeys {
+				keys = append(keys, substatetypes.Hash(key))
+			}
+			accessList = append(accessList, substatetypes.AccessTuple{Address: substatetypes.Address(tuple.Address), StorageKeys: keys})
+		}
+	}
+
+	var blobHashes []substatetypes.Hash
+	for _, hash := range tx.BlobVersionedHashes {
+		blobHashes = append(blobHashes, substatetypes.Hash(hash))
+	}
+
+	var authorizations []substatetypes.SetCodeAuthorization
+	for _, a := range tx.AuthorizationList {
+		authorizations = append(authorizations, substatetypes.SetCodeAuthorization{
+			ChainID: a.ChainID,
+			Address: substatetypes.Address(a.Address),
+			Nonce:   a.Nonce,
+			V:       a.V,
+			R:       a.R,
+			S:       a.S,
+		})
+	}
+
+	var to *substatetypes.Address
+	if tx.To != nil {
+		addr := substatetypes.Address(*tx.To)
+		to = &addr
+	}
+	txType := int32(tx.Type)
+	return substate.NewMessage(
+		uint64(tx.Nonce),
+		true,
+		gasPrice,
+		uint64(tx.Gas),
+		substatetypes.Address(tx.From),
+		to,
+		tx.Value.ToInt(),
+		tx.Input,
+		nil,
+		&txType,
+		accessList,
+		gasFeeCap,
+		gasTipCap,
+		tx.MaxFeePerBlobGas.ToInt(),
+		blobHashes,
+		authorizations,
+	)
+}
+
+type rpcReceipt struct {
+	Status          *hexutil.Uint64 `json:"status"`
+	GasUsed         hexutil.Uint64  `json:"gasUsed"`
+	ContractAddress *common.Address `json:"contractAddress"`
+	LogsBloom       types.Bloom     `json:"logsBloom"`
+	Logs            []rpcLog        `json:"logs"`
+}
+
+type rpcLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+func (r *rpcReceipt) toSubstate() (*substate.Result, error) {
+	if r.Status == nil {
+		return nil, errors.New("receipt has no status; receipts of pre-Byzantium blocks are not supported")
+	}
+	logs := make([]*substatetypes.Log, 0, len(r.Logs))
+	for _, log := range r.Logs {
+		topics := make([]substatetypes.Hash, 0, len(log.Topics))
+		for _, topic := range log.Topics {
+			topics = append(topics, substatetypes.Hash(topic))
+		}
+		logs = append(logs, &substatetypes.Log{
+			Address: substatetypes.Address(log.Address),
+			Topics:  topics,
+			Data:    log.Data,
+		})
+	}
+	var contractAddress substatetypes.Address
+	if r.ContractAddress != nil {
+		contractAddress = substatetypes.Address(*r.ContractAddress)
+	}
+	return substate.NewResult(uint64(*r.Status), substatetypes.Bloom(r.LogsBloom), logs, contractAddress, uint64(r.GasUsed)), nil
+}
+
+// prestateTrace is the result of the prestateTracer for a single transaction.
+type prestateTrace struct {
+	TxHash common.Hash `json:"txHash"`
+	Result rpcAlloc    `json:"result"`
+	Error  string      `json:"error"`
+}
+
+// prestateDiffTrace is the result of the prestateTracer in diff mode for a single transaction.
+type prestateDiffTrace struct {
+	TxHash common.Hash `json:"txHash"`
+	Result struct {
+		Pre  rpcAlloc `json:"pre"`
+		Post rpcAlloc `json:"post"`
+	} `json:"result"`
+	Error string `json:"error"`
+}
+
+type rpcAlloc map[common.Address]*rpcAccount
+
+// rpcAccount is an account reported by the prestateTracer, which omits fields not
+// accessed or, in diff mode, not modified by the transaction.
+type rpcAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Nonce   uint64                      `json:"nonce"`
+	Code    hexutil.Bytes               `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+func (a rpcAlloc) toSubstate() substate.WorldState {
+	res := substate.NewWorldState()
+	for addr, acc := range a {
+		balance := new(uint256.Int)
+		if acc.Balance != nil {
+			balance = uint256.MustFromBig(acc.Balance.ToInt())
+		}
+		account := substate.NewAccount(acc.Nonce, balance, acc.Code)
+		for key, value := range acc.Storage {
+			account.Storage[substatetypes.Hash(key)] = substatetypes.Hash(value)
+		}
+		res[substatetypes.Address(addr)] = account
+	}
+	return res
+}
@@ -0,0 +1,385 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package scrape
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/cmd/util-db/flags"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/0xsoniclabs/substate/substate"
+	substatetypes "github.com/0xsoniclabs/substate/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	senderAddr   = substatetypes.HexToAddress("0x00000000000000000000000000000000000000aa")
+	receiverAddr = substatetypes.HexToAddress("0x00000000000000000000000000000000000000bb")
+	minerAddr    = substatetypes.HexToAddress("0x00000000000000000000000000000000000000cc")
+	contractAddr = substatetypes.HexToAddress("0x00000000000000000000000000000000000000dd")
+	slotOne      = substatetypes.Hash(common.HexToHash("0x01"))
+	slotTwo      = substatetypes.Hash(common.HexToHash("0x02"))
+)
+
+// cannedNodeResponses holds the results of a node for blocks 1 and 2, each containing a single transaction.
+// Block 1 is a pre-merge block with a legacy value transfer, block 2 is a post-merge block with a dynamic
+// fee transaction writing slot one and clearing slot two of a contract.
+var cannedNodeResponses = map[string]string{
+	"eth_getBlockByNumber/0x1": `{
+		"number": "0x1", "hash": "0x1111111111111111111111111111111111111111111111111111111111111111",
+		"stateRoot": "0x1000000000000000000000000000000000000000000000000000000000000001",
+		"miner": "0x00000000000000000000000000000000000000cc", "difficulty": "0x20000",
+		"mixHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"gasLimit": "0x1c9c380", "timestamp": "0x64",
+		"transactions": [{
+			"hash": "0xaaaa000000000000000000000000000000000000000000000000000000000001", "type": "0x0",
+			"from": "0x00000000000000000000000000000000000000aa", "to": "0x00000000000000000000000000000000000000bb",
+			"nonce": "0x0", "gas": "0x5208", "gasPrice": "0x3b9aca00", "value": "0x3e8", "input": "0x"
+		}]
+	}`,
+	"debug_traceBlockByNumber/0x1": `[{"txHash": "0xaaaa000000000000000000000000000000000000000000000000000000000001", "result": {
+		"0x00000000000000000000000000000000000000aa": {"balance": "0xde0b6b3a7640000"},
+		"0x00000000000000000000000000000000000000bb": {"balance": "0x0"},
+		"0x00000000000000000000000000000000000000cc": {"balance": "0x0"}
+	}}]`,
+	"debug_traceBlockByNumber/0x1/diff": `[{"txHash": "0xaaaa000000000000000000000000000000000000000000000000000000000001", "result": {
+		"pre": {
+			"0x00000000000000000000000000000000000000aa": {"balance": "0xde0b6b3a7640000"},
+			"0x00000000000000000000000000000000000000cc": {"balance": "0x0"}
+		},
+		"post": {
+			"0x00000000000000000000000000000000000000aa": {"balance": "0xde0a55f8f3aab18", "nonce": 1},
+			"0x00000000000000000000000000000000000000bb": {"balance": "0x3e8"},
+			"0x00000000000000000000000000000000000000cc": {"balance": "0x1319718a5000"}
+		}
+	}}]`,
+	"eth_getBlockReceipts/0x1": `[{
+		"status": "0x1", "gasUsed": "0x5208", "contractAddress": null, "logs": [],
+		"logsBloom": "0x` + strings.Repeat("0", 512) + `"
+	}]`,
+	"eth_getBlockByNumber/0x2": `{
+		"number": "0x2", "hash": "0x2222222222222222222222222222222222222222222222222222222222222222",
+		"stateRoot": "0x2000000000000000000000000000000000000000000000000000000000000002",
+		"miner": "0x00000000000000000000000000000000000000cc", "difficulty": "0x0",
+		"mixHash": "0x0303030303030303030303030303030303030303030303030303030303030303",
+		"gasLimit": "0x1c9c380", "timestamp": "0x70", "baseFeePerGas": "0x7",
+		"transactions": [{
+			"hash": "0xaaaa000000000000000000000000000000000000000000000000000000000002", "type": "0x2",
+			"from": "0x00000000000000000000000000000000000000aa", "to": "0x00000000000000000000000000000000000000dd",
+			"nonce": "0x1", "gas": "0x186a0", "gasPrice": "0x9", "maxFeePerGas": "0xa", "maxPriorityFeePerGas": "0x2",
+			"value": "0x0", "input": "0x0102", "accessList": [{
+				"address": "0x00000000000000000000000000000000000000dd",
+				"storageKeys": ["0x0000000000000000000000000000000000000000000000000000000000000001"]
+			}]
+		}]
+	}`,
+	"debug_traceBlockByNumber/0x2": `[{"txHash": "0xaaaa000000000000000000000000000000000000000000000000000000000002", "result": {
+		"0x00000000000000000000000000000000000000aa": {"balance": "0xde0a55f8f3aab18", "nonce": 1},
+		"0x00000000000000000000000000000000000000dd": {"balance": "0x0", "nonce": 1, "code": "0x6001", "storage": {
+			"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000000",
+			"0x0000000000000000000000000000000000000000000000000000000000000002": "0x0000000000000000000000000000000000000000000000000000000000000005"
+		}}
+	}}]`,
+	"debug_traceBlockByNumber/0x2/diff": `[{"txHash": "0xaaaa000000000000000000000000000000000000000000000000000000000002", "result": {
+		"pre": {
+			"0x00000000000000000000000000000000000000aa": {"balance": "0xde0a55f8f3aab18", "nonce": 1},
+			"0x00000000000000000000000000000000000000dd": {"balance": "0x0", "nonce": 1, "code": "0x6001", "storage": {
+				"0x0000000000000000000000000000000000000000000000000000000000000002": "0x0000000000000000000000000000000000000000000000000000000000000005"
+			}}
+		},
+		"post": {
+			"0x00000000000000000000000000000000000000aa": {"balance": "0xde0a55f8f39e7e8", "nonce": 2},
+			"0x00000000000000000000000000000000000000dd": {"storage": {
+				"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"
+			}}
+		}
+	}}]`,
+	"eth_getBlockReceipts/0x2": `[{
+		"status": "0x1", "gasUsed": "0x5a3c", "contractAddress": null,
+		"logs": [{"address": "0x00000000000000000000000000000000000000dd",
+			"topics": ["0x0000000000000000000000000000000000000000000000000000000000000001"], "data": "0x02"}],
+		"logsBloom": "0x` + strings.Repeat("0", 512) + `"
+	}]`,
+}
+
+// cannedNode is a JSON-RPC server answering requests with cannedNodeResponses.
+type cannedNode struct {
+	mu          sync.Mutex
+	rateLimited int      // number of requests rejected with 429 before requests are served
+	calls       []string // served requests
+}
+
+func (n *cannedNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.rateLimited > 0 {
+		n.rateLimited--
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+		return
+	}
+
+	var req struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var block string
+	if err := json.Unmarshal(req.Params[0], &block); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	key := req.Method + "/" + block
+	if len(req.Params) > 1 && strings.Contains(string(req.Params[1]), "diffMode") {
+		key += "/diff"
+	}
+	n.calls = append(n.calls, key)
+
+	w.Header().Set("Content-Type", "application/json")
+	if result, found := cannedNodeResponses[key]; found {
+		_, _ = fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %s, "result": %s}`, req.ID, result)
+	} else {
+		_, _ = fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %s, "result": null}`, req.ID)
+	}
+}
+
+func newTestSubstateScraper(t *testing.T, node *cannedNode, database db.SubstateDB) *substateScraper {
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	client, err := rpc.DialContext(context.Background(), server.URL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return &substateScraper{
+		client:     client,
+		database:   database,
+		chainCfg:   params.MainnetChainConfig,
+		log:        logger.NewLogger("critical", "Test-Scrape"),
+		maxRetries: 3,
+		retryDelay: 0,
+	}
+}
+
+func TestSubstateScraper_RecordsSubstatesOfTwoBlocks(t *testing.T) {
+	database, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	defer database.Close()
+
+	s := newTestSubstateScraper(t, &cannedNode{}, database)
+	require.NoError(t, s.scrape(context.Background(), 1, 2))
+
+	// block 1: pre-merge legacy value transfer
+	ss, err := database.GetSubstate(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), ss.Env.Number)
+	assert.Equal(t, minerAddr, ss.Env.Coinbase)
+	assert.Equal(t, big.NewInt(0x20000), ss.Env.Difficulty)
+	assert.Nil(t, ss.Env.Random)
+	assert.Nil(t, ss.Env.BaseFee)
+	assert.Equal(t, senderAddr, ss.Message.From)
+	assert.Equal(t, receiverAddr, *ss.Message.To)
+	assert.Equal(t, big.NewInt(1000), ss.Message.Value)
+	assert.Equal(t, uint64(21000), ss.Message.Gas)
+	assert.Equal(t, big.NewInt(1_000_000_000), ss.Message.GasPrice)
+	assert.Equal(t, big.NewInt(1_000_000_000), ss.Message.GasFeeCap)
+	assert.Equal(t, uint64(1), ss.Result.Status)
+	assert.Equal(t, uint64(21000), ss.Result.GasUsed)
+
+	wantInput := substate.NewWorldState().
+		Add(senderAddr, 0, uint256.MustFromHex("0xde0b6b3a7640000"), nil).
+		Add(receiverAddr, 0, new(uint256.Int), nil).
+		Add(minerAddr, 0, new(uint256.Int), nil)
+	assert.True(t, wantInput.Equal(ss.InputSubstate), "unexpected input alloc %v", ss.InputSubstate)
+	wantOutput := substate.NewWorldState().
+		Add(senderAddr, 1, uint256.MustFromHex("0xde0a55f8f3aab18"), nil).
+		Add(receiverAddr, 0, uint256.NewInt(1000), nil).
+		Add(minerAddr, 0, uint256.MustFromHex("0x1319718a5000"), nil)
+	assert.True(t, wantOutput.Equal(ss.OutputSubstate), "unexpected output alloc %v", ss.OutputSubstate)
+
+	// block 2: post-merge dynamic fee transaction modifying storage
+	ss, err = database.GetSubstate(2, 0)
+	require.NoError(t, err)
+	require.NotNil(t, ss.Env.Random)
+	assert.Equal(t, substatetypes.Hash(common.HexToHash("0x0303030303030303030303030303030303030303030303030303030303030303")), *ss.Env.Random)
+	assert.Equal(t, big.NewInt(7), ss.Env.BaseFee)
+	assert.Equal(t, big.NewInt(9), ss.Message.GasPrice)
+	assert.Equal(t, big.NewInt(10), ss.Message.GasFeeCap)
+	assert.Equal(t, big.NewInt(2), ss.Message.GasTipCap)
+	assert.Equal(t, []byte{1, 2}, ss.Message.Data)
+	assert.Equal(t, substatetypes.AccessList{{Address: contractAddr, StorageKeys: []substatetypes.Hash{slotOne}}}, ss.Message.AccessList)
+	require.Len(t, ss.Result.Logs, 1)
+	assert.Equal(t, contractAddr, ss.Result.Logs[0].Address)
+	assert.Equal(t, []byte{2}, ss.Result.Logs[0].Data)
+
+	contract := ss.OutputSubstate[contractAddr]
+	require.NotNil(t, contract)
+	assert.Equal(t, []byte{0x60, 0x01}, contract.Code)
+	assert.Equal(t, uint64(1), contract.Nonce)
+	assert.Equal(t, substatetypes.Hash(common.HexToHash("0x02")), contract.Storage[slotOne])
+	assert.Equal(t, substatetypes.Hash{}, contract.Storage[slotTwo])
+	assert.Equal(t, substatetypes.Hash(common.HexToHash("0x05")), ss.InputSubstate[contractAddr].Storage[slotTwo])
+	assert.Equal(t, uint64(2), ss.OutputSubstate[senderAddr].Nonce)
+
+	hashes := db.MakeHashProvider(database)
+	for number, want := range map[int]string{
+		1: "0x1000000000000000000000000000000000000000000000000000000000000001",
+		2: "0x2000000000000000000000000000000000000000000000000000000000000002",
+	} {
+		got, err := hashes.GetStateRootHash(number)
+		require.NoError(t, err)
+		assert.Equal(t, substatetypes.Hash(common.HexToHash(want)), got)
+	}
+	blockHash, err := hashes.GetBlockHash(2)
+	require.NoError(t, err)
+	assert.Equal(t, substatetypes.Hash(common.HexToHash("0x2222222222222222222222222222222222222222222222222222222222222222")), blockHash)
+}
+
+func TestSubstateScraper_ResumesAfterLastCompletedBlock(t *testing.T) {
+	database, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	defer database.Close()
+	require.NoError(t, db.SaveStateRoot(database, "0x1", "0x1000000000000000000000000000000000000000000000000000000000000001"))
+
+	node := &cannedNode{}
+	s := newTestSubstateScraper(t, node, database)
+	require.NoError(t, s.scrape(context.Background(), 1, 2))
+
+	for _, call := range node.calls {
+		assert.True(t, strings.HasSuffix(call, "/0x2") || strings.HasSuffix(call, "/0x2/diff"), "completed block 1 was requested again: %v", call)
+	}
+	found, err := database.HasSubstate(1, 0)
+	require.NoError(t, err)
+	assert.False(t, found)
+	found, err = database.HasSubstate(2, 0)
+	require.NoError(t, err)
+	assert.True(t, found)
+}
+
+func TestSubstateScraper_RetriesRateLimitedCalls(t *testing.T) {
+	database, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	defer database.Close()
+
+	s := newTestSubstateScraper(t, &cannedNode{rateLimited: 3}, database)
+	require.NoError(t, s.scrape(context.Background(), 1, 1))
+
+	found, err := database.HasSubstate(1, 0)
+	require.NoError(t, err)
+	assert.True(t, found)
+}
+
+func TestSubstateScraper_FailsIfRateLimitPersists(t *testing.T) {
+	database, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	defer database.Close()
+
+	s := newTestSubstateScraper(t, &cannedNode{rateLimited: 4}, database)
+	err = s.scrape(context.Background(), 1, 1)
+	assert.ErrorContains(t, err, "eth_getBlockByNumber failed")
+	assert.ErrorContains(t, err, "429")
+}
+
+func TestSubstateScraper_FailsOnMissingBlock(t *testing.T) {
+	database, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	defer database.Close()
+
+	s := newTestSubstateScraper(t, &cannedNode{}, database)
+	err = s.scrape(context.Background(), 2, 3)
+	assert.ErrorContains(t, err, "cannot scrape block 3; block not found")
+}
+
+func TestSubstateScraper_ApplyPrestateDiffRemovesDeletedAccounts(t *testing.T) {
+	input := substate.NewWorldState().Add(receiverAddr, 1, uint256.NewInt(5), []byte{1})
+	pre := rpcAlloc{common.Address(receiverAddr): {}}
+
+	output := applyPrestateDiff(input, pre, rpcAlloc{})
+	assert.Empty(t, output)
+	assert.Len(t, input, 1, "input alloc must not be modified")
+}
+
+func TestCmd_ScrapeCommandRecordsSubstatesFromRpcNode(t *testing.T) {
+	server := httptest.NewServer(&cannedNode{})
+	defer server.Close()
+	targetDbPath := filepath.Join(t.TempDir(), "target-db")
+
+	app := cli.NewApp()
+	app.Commands = []*cli.Command{&Command}
+	args := utils.NewArgs("test").
+		Arg(Command.Name).
+		Flag(utils.TargetDbFlag.Name, targetDbPath).
+		Flag(utils.ChainIDFlag.Name, int(utils.EthereumChainID)).
+		Flag(flags.ScrapeSource.Name, "rpc").
+		Flag(flags.RpcEndpoint.Name, server.URL).
+		Arg("1").
+		Arg("2").
+		Build()
+	require.NoError(t, app.Run(args))
+
+	database, err := db.NewReadOnlySubstateDB(targetDbPath)
+	require.NoError(t, err)
+	defer database.Close()
+	for _, block := range []uint64{1, 2} {
+		found, err := database.HasSubstate(block, 0)
+		require.NoError(t, err)
+		assert.True(t, found, "substate of block %d is missing", block)
+	}
+}
+
+func TestCmd_ScrapeCommandRejectsInvalidSource(t *testing.T) {
+	tests := map[string]struct {
+		source  string
+		wantErr string
+	}{
+		"unknown source":   {source: "carrier-pigeon", wantErr: `unknown --source "carrier-pigeon"`},
+		"missing endpoint": {source: "rpc", wantErr: "--rpc-endpoint is required by --source rpc"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			app := cli.NewApp()
+			app.Commands = []*cli.Command{&Command}
+			args := utils.NewArgs("test").
+				Arg(Command.Name).
+				Flag(utils.TargetDbFlag.Name, filepath.Join(t.TempDir(), "target-db")).
+				Flag(utils.ChainIDFlag.Name, int(utils.EthereumChainID)).
+				Flag(flags.ScrapeSource.Name, test.source).
+				Arg("1").
+				Arg("2").
+				Build()
+			assert.ErrorContains(t, app.Run(args), test.wantErr)
+		})
+	}
+}
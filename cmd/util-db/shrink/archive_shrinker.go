@@ -0,0 +1,274 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package shrink
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/substate/db"
+	substatetypes "github.com/0xsoniclabs/substate/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/holiman/uint256"
+)
+
+// progressInterval is the number of blocks between progress reports.
+const progressInterval = 100_000
+
+// archiveShrinker copies the history of a source archive into a target StateDb
+// primed to the block before the retention boundary.
+type archiveShrinker struct {
+	log logger.Logger
+	src state.StateDB
+	dst state.StateDB
+	sdb db.SubstateDB
+	ddb db.DestroyedAccountDB
+}
+
+// blockChanges lists the accounts and storage slots touched by a block.
+type blockChanges struct {
+	accounts  map[common.Address]map[common.Hash]struct{}
+	destroyed []common.Address
+}
+
+func (c *blockChanges) add(addr common.Address, storage map[substatetypes.Hash]substatetypes.Hash) {
+	slots, ok := c.accounts[addr]
+	if !ok {
+		slots = make(map[common.Hash]struct{})
+		c.accounts[addr] = slots
+	}
+	for key := range storage {
+		slots[common.Hash(key)] = struct{}{}
+	}
+}
+
+// shrink copies the blocks from boundary to head into the target and verifies its
+// content against the source. It returns the state root of the target.
+func (s *archiveShrinker) shrink(boundary, head uint64) (common.Hash, error) {
+	height, empty, err := s.dst.GetArchiveBlockHeight()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("cannot get archive height of target StateDb; %w", err)
+	}
+	if !empty && height >= boundary {
+		return common.Hash{}, fmt.Errorf("priming of target StateDb reached archive block %v, cannot retain blocks from %v", height, boundary)
+	}
+
+	for block := boundary; block <= head; block++ {
+		changes, err := s.collectChanges(block)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		// the head block is always written, so that both archives have the same height
+		if changes == nil && block != head {
+			continue
+		}
+		if err = s.copyBlock(block, changes); err != nil {
+			return common.Hash{}, err
+		}
+		if (block-boundary+1)%progressInterval == 0 {
+			s.log.Infof("Copied archive blocks %v-%v", boundary, block)
+		}
+	}
+
+	return s.verify(boundary, head)
+}
+
+// collectChanges gathers the accounts and slots touched by the transactions of given
+// block. A nil result is returned for blocks without transactions.
+func (s *archiveShrinker) collectChanges(block uint64) (*blockChanges, error) {
+	substates, err := s.sdb.GetBlockSubstates(block)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get substates of block %v; %w", block, err)
+	}
+	if len(substates) == 0 {
+		return nil, nil
+	}
+
+	changes := &blockChanges{accounts: make(map[common.Address]map[common.Hash]struct{})}
+	for _, tx := range slices.Sorted(maps.Keys(substates)) {
+		ss := substates[tx]
+		for addr, acc := range ss.InputSubstate {
+			changes.add(common.Address(addr), acc.Storage)
+		}
+		for addr, acc := range ss.OutputSubstate {
+			changes.add(common.Address(addr), acc.Storage)
+		}
+		destroyed, resurrected, err := s.ddb.GetDestroyedAccounts(block, tx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get destroyed accounts of tx %v/%v; %w", block, tx, err)
+		}
+		for _, addr := range append(destroyed, resurrected...) {
+			changes.destroyed = append(changes.destroyed, common.Address(addr))
+			changes.add(common.Address(addr), nil)
+		}
+	}
+	return changes, nil
+}
+
+// copyBlock writes the values of all touched accounts and slots at the end of given
+// block, as recorded by the source archive, into the target. Destroyed accounts are
+// deleted first, so that their untouched storage is cleared as well.
+func (s *archiveShrinker) copyBlock(block uint64, changes *blockChanges) (err error) {
+	view, err := s.src.GetArchiveState(block)
+	if err != nil {
+		return fmt.Errorf("cannot get source archive state of block %v; %w", block, err)
+	}
+	defer func() {
+		if releaseErr := view.Release(); releaseErr != nil && err == nil {
+			err = fmt.Errorf("cannot release source archive state of block %v; %w", block, releaseErr)
+		}
+	}()
+	if err = view.BeginTransaction(0); err != nil {
+		return err
+	}
+
+	if err = s.dst.BeginBlock(block); err != nil {
+		return fmt.Errorf("cannot begin block %v; %w", block, err)
+	}
+	if changes != nil {
+		if err = s.dst.BeginTransaction(0); err != nil {
+			return err
+		}
+		for _, addr := range changes.destroyed {
+			s.dst.SelfDestruct(addr)
+		}
+		if err = s.dst.EndTransaction(); err != nil {
+			return err
+		}
+
+		if err = s.dst.BeginTransaction(1); err != nil {
+			return err
+		}
+		for _, addr := range sortedAddresses(changes.accounts) {
+			s.copyAccount(view, addr, changes.accounts[addr])
+		}
+		if err = s.dst.EndTransaction(); err != nil {
+			return err
+		}
+	}
+	if err = s.dst.EndBlock(); err != nil {
+		return fmt.Errorf("cannot end block %v; %w", block, err)
+	}
+	return view.EndTransaction()
+}
+
+// copyAccount updates the target account to match the given source view.
+func (s *archiveShrinker) copyAccount(view state.VmStateDB, addr common.Address, slots map[common.Hash]struct{}) {
+	if !view.Exist(addr) {
+		if s.dst.Exist(addr) {
+			s.dst.SelfDestruct(addr)
+		}
+		return
+	}
+	if !s.dst.Exist(addr) {
+		s.dst.CreateAccount(addr)
+	}
+
+	want, have := view.GetBalance(addr), s.dst.GetBalance(addr)
+	switch want.Cmp(have) {
+	case 1:
+		s.dst.AddBalance(addr, new(uint256.Int).Sub(want, have), tracing.BalanceChangeUnspecified)
+	case -1:
+		s.dst.SubBalance(addr, new(uint256.Int).Sub(have, want), tracing.BalanceChangeUnspecified)
+	}
+	if nonce := view.GetNonce(addr); nonce != s.dst.GetNonce(addr) {
+		s.dst.SetNonce(addr, nonce, tracing.NonceChangeUnspecified)
+	}
+	if code := view.GetCode(addr); !bytes.Equal(code, s.dst.GetCode(addr)) {
+		s.dst.SetCode(addr, code, tracing.CodeChangeUnspecified)
+	}
+	for _, key := range sortedKeys(slots) {
+		if value := view.GetState(addr, key); value != s.dst.GetState(addr, key) {
+			s.dst.SetState(addr, key, value)
+		}
+	}
+}
+
+// verify checks that the target reproduces the live state and the first retained
+// archive block of the source. It returns the state root of the target.
+func (s *archiveShrinker) verify(boundary, head uint64) (common.Hash, error) {
+	height, _, err := s.dst.GetArchiveBlockHeight()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("cannot get archive height of target StateDb; %w", err)
+	}
+	if height != head {
+		return common.Hash{}, fmt.Errorf("unexpected archive height of target StateDb; want: %v, have: %v", head, height)
+	}
+
+	want, err := s.src.GetHash()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("cannot get state root of source StateDb; %w", err)
+	}
+	have, err := s.dst.GetHash()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("cannot get state root of target StateDb; %w", err)
+	}
+	if want != have {
+		return common.Hash{}, fmt.Errorf("state root of target StateDb differs; want: %v, have: %v", want, have)
+	}
+
+	wantFirst, err := archiveHash(s.src, boundary)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	haveFirst, err := archiveHash(s.dst, boundary)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if wantFirst != haveFirst {
+		return common.Hash{}, fmt.Errorf("state root of archive block %v differs; want: %v, have: %v", boundary, wantFirst, haveFirst)
+	}
+
+	s.log.Noticef("Target StateDb verified; archive blocks %v-%v, state root %v", boundary, head, have)
+	return have, nil
+}
+
+// archiveHash returns the state root of given archive block.
+func archiveHash(db state.StateDB, block uint64) (common.Hash, error) {
+	view, err := db.GetArchiveState(block)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("cannot get archive state of block %v; %w", block, err)
+	}
+	hash, err := view.GetHash()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("cannot get state root of archive block %v; %w", block, err)
+	}
+	return hash, view.Release()
+}
+
+func sortedAddresses(accounts map[common.Address]map[common.Hash]struct{}) []common.Address {
+	res := make([]common.Address, 0, len(accounts))
+	for addr := range accounts {
+		res = append(res, addr)
+	}
+	slices.SortFunc(res, func(a, b common.Address) int { return bytes.Compare(a[:], b[:]) })
+	return res
+}
+
+func sortedKeys(slots map[common.Hash]struct{}) []common.Hash {
+	res := make([]common.Hash, 0, len(slots))
+	for key := range slots {
+		res = append(res, key)
+	}
+	slices.SortFunc(res, func(a, b common.Hash) int { return bytes.Compare(a[:], b[:]) })
+	return res
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package shrink
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/prime"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/urfave/cli/v2"
+)
+
+// Command rebuilds an archive StateDb retaining only the history from given block.
+var Command = cli.Command{
+	Action:    shrinkAction,
+	Name:      "shrink-archive",
+	Usage:     "rebuilds an archive StateDb retaining only the history starting at given block",
+	ArgsUsage: "<retentionBlock>",
+	Flags: []cli.Flag{
+		&utils.AidaDbFlag,
+		&utils.SubstateEncodingFlag,
+		&utils.StateDbSrcFlag,
+		&utils.TargetDbFlag,
+		&utils.UpdateBufferSizeFlag,
+		&utils.UpdateBufferStrictFlag,
+		&logger.LogLevelFlag,
+	},
+	Description: `
+The shrink-archive command requires one argument: <retentionBlock>
+
+Carmen does not support dropping archive history in place, hence a new StateDb is
+written into --target-db. Its state is primed from the aida-db to <retentionBlock> - 1,
+the blocks from <retentionBlock> up to the head of the --db-src StateDb are copied from
+its archive. The live state of both StateDbs is verified to be equal, archive queries
+of blocks before <retentionBlock> are rejected afterwards.`,
+}
+
+func shrinkAction(ctx *cli.Context) error {
+	cfg, err := utils.NewConfig(ctx, utils.LastBlockArg)
+	if err != nil {
+		return err
+	}
+	log := logger.NewLogger(cfg.LogLevel, "Shrink-Archive")
+	boundary := cfg.Last
+
+	info, err := utils.ReadStateDbInfo(cfg.StateDbSrc)
+	if err != nil {
+		return fmt.Errorf("cannot read info of --%v; %w", utils.StateDbSrcFlag.Name, err)
+	}
+	if err = checkRetentionBoundary(info, boundary); err != nil {
+		return err
+	}
+	if cfg.TargetDb == "" {
+		return fmt.Errorf("--%v must be set", utils.TargetDbFlag.Name)
+	}
+	if _, err = os.Stat(cfg.TargetDb); !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("target StateDb %v already exists", cfg.TargetDb)
+	}
+
+	aidaDb, err := utils.OpenAidaDb(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot open aida-db; %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, aidaDb.Close())
+	}()
+	ddb, err := db.MakeDefaultDestroyedAccountDBFromBaseDB(aidaDb)
+	if err != nil {
+		return err
+	}
+
+	// the source is only read, hence it is opened in place
+	cfg.StateDbSrcDirectAccess = true
+	cfg.StateDbSrcReadOnly = true
+	src, _, err := utils.PrepareStateDB(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot open source StateDb; %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, src.Close())
+	}()
+
+	// the target is created next to its final location and renamed once it is complete
+	targetCfg := *cfg
+	targetCfg.StateDbSrc = ""
+	targetCfg.IsExistingStateDb = false
+	targetCfg.DbTmp = filepath.Dir(cfg.TargetDb)
+	targetCfg.ArchiveMode = true
+	targetCfg.ArchiveVariant = info.ArchiveVariant
+	targetCfg.ArchiveFirstBlock = boundary
+	targetCfg.First = boundary
+	dst, dstPath, err := utils.PrepareStateDB(&targetCfg)
+	if err != nil {
+		return fmt.Errorf("cannot create target StateDb; %w", err)
+	}
+
+	log.Noticef("Priming target StateDb to block %v", boundary-1)
+	primer, err := prime.NewPrimer(&targetCfg, dst, aidaDb, log)
+	if err == nil {
+		err = primer.Prime()
+	}
+	if err != nil {
+		return errors.Join(fmt.Errorf("cannot prime target StateDb; %w", err), dst.Close(), os.RemoveAll(dstPath))
+	}
+
+	s := &archiveShrinker{log: log, src: src, dst: dst, sdb: aidaDb, ddb: ddb}
+	root, err := s.shrink(boundary, info.Block)
+	if err != nil {
+		return errors.Join(err, dst.Close(), os.RemoveAll(dstPath))
+	}
+	if err = dst.Close(); err != nil {
+		return fmt.Errorf("cannot close target StateDb; %w", err)
+	}
+	if err = utils.WriteStateDbInfo(dstPath, &targetCfg, info.Block, root, true); err != nil {
+		return err
+	}
+	if err = os.Rename(dstPath, cfg.TargetDb); err != nil {
+		return fmt.Errorf("cannot move target StateDb to %v; %w", cfg.TargetDb, err)
+	}
+
+	log.Noticef("Archive of %v retaining blocks %v-%v written to %v", cfg.StateDbSrc, boundary, info.Block, cfg.TargetDb)
+	return nil
+}
+
+// checkRetentionBoundary checks whether the archive of the StateDb described by
+// info can be shrunk to retain the blocks starting at boundary.
+func checkRetentionBoundary(info utils.StateDbInfo, boundary uint64) error {
+	if info.Impl != "carmen" {
+		return fmt.Errorf("only carmen StateDbs can be shrunk, source StateDb is %v", info.Impl)
+	}
+	if !info.ArchiveMode {
+		return errors.New("source StateDb has no archive")
+	}
+	first := max(info.ArchiveFirst, 1)
+	if boundary < first || boundary > info.Block {
+		return fmt.Errorf("retention block %v is outside of the archive block range %v-%v", boundary, first, info.Block)
+	}
+	return nil
+}
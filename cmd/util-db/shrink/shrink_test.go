@@ -0,0 +1,244 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package shrink
+
+import (
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/0xsoniclabs/substate/substate"
+	substatetypes "github.com/0xsoniclabs/substate/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestShrink_CheckRetentionBoundary(t *testing.T) {
+	archive := utils.StateDbInfo{Impl: "carmen", ArchiveMode: true, Block: 100}
+	tests := map[string]struct {
+		info     utils.StateDbInfo
+		boundary uint64
+		wantErr  string
+	}{
+		"first block":       {archive, 1, ""},
+		"head block":        {archive, 100, ""},
+		"not carmen":        {utils.StateDbInfo{Impl: "geth", ArchiveMode: true, Block: 100}, 50, "only carmen StateDbs"},
+		"no archive":        {utils.StateDbInfo{Impl: "carmen", Block: 100}, 50, "has no archive"},
+		"genesis":           {archive, 0, "outside of the archive block range 1-100"},
+		"beyond head":       {archive, 101, "outside of the archive block range 1-100"},
+		"already pruned":    {utils.StateDbInfo{Impl: "carmen", ArchiveMode: true, ArchiveFirst: 60, Block: 100}, 50, "range 60-100"},
+		"within old window": {utils.StateDbInfo{Impl: "carmen", ArchiveMode: true, ArchiveFirst: 60, Block: 100}, 70, ""},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkRetentionBoundary(test.info, test.boundary)
+			if test.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestArchiveShrinker_CollectChangesMergesTransactionsOfBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	sdb := db.NewMockSubstateDB(ctrl)
+	ddb := db.NewMockDestroyedAccountDB(ctrl)
+	s := &archiveShrinker{sdb: sdb, ddb: ddb}
+
+	acc := func(keys ...substatetypes.Hash) *substate.Account {
+		res := substate.NewAccount(0, uint256.NewInt(0), nil)
+		for _, key := range keys {
+			res.Storage[key] = substatetypes.Hash{1}
+		}
+		return res
+	}
+	gomock.InOrder(
+		sdb.EXPECT().GetBlockSubstates(uint64(5)).Return(map[int]*substate.Substate{
+			0: {InputSubstate: substate.WorldState{{1}: acc(substatetypes.Hash{1})}, OutputSubstate: substate.WorldState{{1}: acc(substatetypes.Hash{2})}},
+			1: {InputSubstate: substate.WorldState{{2}: acc()}, OutputSubstate: substate.WorldState{}},
+		}, nil),
+		ddb.EXPECT().GetDestroyedAccounts(uint64(5), 0).Return(nil, nil, nil),
+		ddb.EXPECT().GetDestroyedAccounts(uint64(5), 1).Return([]substatetypes.Address{{3}}, nil, nil),
+		sdb.EXPECT().GetBlockSubstates(uint64(6)).Return(map[int]*substate.Substate{}, nil),
+	)
+
+	changes, err := s.collectChanges(5)
+	require.NoError(t, err)
+	assert.Equal(t, map[common.Address]map[common.Hash]struct{}{
+		{1}: {{1}: {}, {2}: {}},
+		{2}: {},
+		{3}: {},
+	}, changes.accounts)
+	assert.Equal(t, []common.Address{{3}}, changes.destroyed)
+
+	changes, err = s.collectChanges(6)
+	require.NoError(t, err)
+	assert.Nil(t, changes)
+}
+
+func TestArchiveShrinker_CopyBlockWritesSourceValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	src := state.NewMockStateDB(ctrl)
+	dst := state.NewMockStateDB(ctrl)
+	view := state.NewMockNonCommittableStateDB(ctrl)
+	s := &archiveShrinker{src: src, dst: dst}
+
+	created, updated, deleted, destroyed := common.Address{1}, common.Address{2}, common.Address{3}, common.Address{4}
+	changes := &blockChanges{
+		accounts: map[common.Address]map[common.Hash]struct{}{
+			created:   {{1}: {}},
+			updated:   {{1}: {}, {2}: {}},
+			deleted:   {},
+			destroyed: {},
+		},
+		destroyed: []common.Address{destroyed},
+	}
+
+	gomock.InOrder(
+		src.EXPECT().GetArchiveState(uint64(7)).Return(view, nil),
+		view.EXPECT().BeginTransaction(uint32(0)),
+		dst.EXPECT().BeginBlock(uint64(7)),
+		dst.EXPECT().BeginTransaction(uint32(0)),
+		dst.EXPECT().SelfDestruct(destroyed),
+		dst.EXPECT().EndTransaction(),
+		dst.EXPECT().BeginTransaction(uint32(1)),
+
+		// a newly created account
+		view.EXPECT().Exist(created).Return(true),
+		dst.EXPECT().Exist(created).Return(false),
+		dst.EXPECT().CreateAccount(created),
+		view.EXPECT().GetBalance(created).Return(uint256.NewInt(10)),
+		dst.EXPECT().GetBalance(created).Return(uint256.NewInt(0)),
+		dst.EXPECT().AddBalance(created, uint256.NewInt(10), tracing.BalanceChangeUnspecified),
+		view.EXPECT().GetNonce(created).Return(uint64(1)),
+		dst.EXPECT().GetNonce(created).Return(uint64(0)),
+		dst.EXPECT().SetNonce(created, uint64(1), tracing.NonceChangeUnspecified),
+		view.EXPECT().GetCode(created).Return([]byte{1}),
+		dst.EXPECT().GetCode(created).Return(nil),
+		dst.EXPECT().SetCode(created, []byte{1}, tracing.CodeChangeUnspecified),
+		view.EXPECT().GetState(created, common.Hash{1}).Return(common.Hash{5}),
+		dst.EXPECT().GetState(created, common.Hash{1}).Return(common.Hash{}),
+		dst.EXPECT().SetState(created, common.Hash{1}, common.Hash{5}),
+
+		// an updated account, only differing values are written
+		view.EXPECT().Exist(updated).Return(true),
+		dst.EXPECT().Exist(updated).Return(true),
+		view.EXPECT().GetBalance(updated).Return(uint256.NewInt(3)),
+		dst.EXPECT().GetBalance(updated).Return(uint256.NewInt(5)),
+		dst.EXPECT().SubBalance(updated, uint256.NewInt(2), tracing.BalanceChangeUnspecified),
+		view.EXPECT().GetNonce(updated).Return(uint64(2)),
+		dst.EXPECT().GetNonce(updated).Return(uint64(2)),
+		view.EXPECT().GetCode(updated).Return(nil),
+		dst.EXPECT().GetCode(updated).Return(nil),
+		view.EXPECT().GetState(updated, common.Hash{1}).Return(common.Hash{1}),
+		dst.EXPECT().GetState(updated, common.Hash{1}).Return(common.Hash{1}),
+		view.EXPECT().GetState(updated, common.Hash{2}).Return(common.Hash{}),
+		dst.EXPECT().GetState(updated, common.Hash{2}).Return(common.Hash{2}),
+		dst.EXPECT().SetState(updated, common.Hash{2}, common.Hash{}),
+
+		// an account deleted in the source
+		view.EXPECT().Exist(deleted).Return(false),
+		dst.EXPECT().Exist(deleted).Return(true),
+		dst.EXPECT().SelfDestruct(deleted),
+
+		// an account destroyed in the block
+		view.EXPECT().Exist(destroyed).Return(false),
+		dst.EXPECT().Exist(destroyed).Return(false),
+
+		dst.EXPECT().EndTransaction(),
+		dst.EXPECT().EndBlock(),
+		view.EXPECT().EndTransaction(),
+		view.EXPECT().Release(),
+	)
+
+	require.NoError(t, s.copyBlock(7, changes))
+}
+
+func TestArchiveShrinker_ShrinkCopiesBlocksAndVerifiesTarget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	src := state.NewMockStateDB(ctrl)
+	dst := state.NewMockStateDB(ctrl)
+	srcView := state.NewMockNonCommittableStateDB(ctrl)
+	dstView := state.NewMockNonCommittableStateDB(ctrl)
+	sdb := db.NewMockSubstateDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+	s := &archiveShrinker{log: log, src: src, dst: dst, sdb: sdb}
+
+	root := common.Hash{9}
+	gomock.InOrder(
+		dst.EXPECT().GetArchiveBlockHeight().Return(uint64(2), false, nil),
+		// blocks without substates are skipped, except for the head
+		sdb.EXPECT().GetBlockSubstates(uint64(10)).Return(nil, nil),
+		sdb.EXPECT().GetBlockSubstates(uint64(11)).Return(nil, nil),
+		src.EXPECT().GetArchiveState(uint64(11)).Return(srcView, nil),
+		srcView.EXPECT().BeginTransaction(uint32(0)),
+		dst.EXPECT().BeginBlock(uint64(11)),
+		dst.EXPECT().EndBlock(),
+		srcView.EXPECT().EndTransaction(),
+		srcView.EXPECT().Release(),
+
+		dst.EXPECT().GetArchiveBlockHeight().Return(uint64(11), false, nil),
+		src.EXPECT().GetHash().Return(root, nil),
+		dst.EXPECT().GetHash().Return(root, nil),
+		src.EXPECT().GetArchiveState(uint64(10)).Return(srcView, nil),
+		srcView.EXPECT().GetHash().Return(common.Hash{1}, nil),
+		srcView.EXPECT().Release(),
+		dst.EXPECT().GetArchiveState(uint64(10)).Return(dstView, nil),
+		dstView.EXPECT().GetHash().Return(common.Hash{1}, nil),
+		dstView.EXPECT().Release(),
+		log.EXPECT().Noticef(gomock.Any(), uint64(10), uint64(11), root),
+	)
+
+	got, err := s.shrink(10, 11)
+	require.NoError(t, err)
+	assert.Equal(t, root, got)
+}
+
+func TestArchiveShrinker_ShrinkFailsIfPrimingReachedBoundary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dst := state.NewMockStateDB(ctrl)
+	s := &archiveShrinker{dst: dst}
+
+	dst.EXPECT().GetArchiveBlockHeight().Return(uint64(10), false, nil)
+
+	_, err := s.shrink(10, 11)
+	require.ErrorContains(t, err, "cannot retain blocks from 10")
+}
+
+func TestArchiveShrinker_VerifyDetectsDifferentStateRoot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	src := state.NewMockStateDB(ctrl)
+	dst := state.NewMockStateDB(ctrl)
+	s := &archiveShrinker{src: src, dst: dst}
+
+	gomock.InOrder(
+		dst.EXPECT().GetArchiveBlockHeight().Return(uint64(11), false, nil),
+		src.EXPECT().GetHash().Return(common.Hash{1}, nil),
+		dst.EXPECT().GetHash().Return(common.Hash{2}, nil),
+	)
+
+	_, err := s.verify(10, 11)
+	require.ErrorContains(t, err, "state root of target StateDb differs")
+}
@@ -0,0 +1,360 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package synthetic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	substatecontext "github.com/0xsoniclabs/aida/txcontext/substate"
+	"github.com/0xsoniclabs/aida/utildb"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/0xsoniclabs/substate/substate"
+	substatetypes "github.com/0xsoniclabs/substate/types"
+	"github.com/0xsoniclabs/substate/updateset"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+const (
+	numAccounts        = 32
+	numContracts       = 4
+	numContractSlots   = 16 // storage slots written by the generated contract calls
+	maxTxsPerBlock     = 4
+	updateSetInterval  = 100
+	blockGasLimit      = 100_000_000
+	firstBlockTime     = 1_700_000_000
+	transferGas        = 21_000
+	storageWriteGas    = 100_000
+	outOfGasStorageGas = 30_000 // covers the intrinsic gas but not the SSTORE of a new slot
+)
+
+var (
+	// storageWriterCode stores the second calldata word into the slot given by the
+	// first calldata word and emits a log with the slot as topic and the value as data.
+	storageWriterCode = hexutil.MustDecode("0x6020356000355560203560005260003560206000a100")
+	genesisBalance    = new(uint256.Int).Exp(uint256.NewInt(10), uint256.NewInt(24))
+	baseFee           = big.NewInt(1_000_000_000)
+	gasPrice          = big.NewInt(2_000_000_000)
+	blobBaseFee       = big.NewInt(1)
+)
+
+// generator produces a deterministic chain of synthetic transactions and records
+// them into an aida-db. Each transaction is executed by the same processor used
+// for replaying, hence the recorded substates, update-sets and state hashes are
+// consistent with each other.
+type generator struct {
+	cfg       *utils.Config
+	log       logger.Logger
+	rand      *rand.Rand
+	processor *executor.TxProcessor
+	state     state.StateDB
+	aidaDb    db.SubstateDB
+	updateDb  db.UpdateDB
+
+	coinbase  common.Address
+	accounts  []common.Address
+	contracts []common.Address
+	nonces    map[common.Address]uint64
+	update    substate.WorldState // changes since the last written update-set
+}
+
+// Generate writes a synthetic aida-db with a genesis update-set and blocks 1 to
+// cfg.Last into given path. The same seed always yields the same content.
+func Generate(cfg *utils.Config, path string, seed int64) (err error) {
+	if cfg.Last < 1 {
+		return errors.New("at least one block has to be generated")
+	}
+	log := logger.NewLogger(cfg.LogLevel, "Make-Testdata")
+
+	processor, err := executor.MakeTxProcessor(cfg)
+	if err != nil {
+		return err
+	}
+
+	aidaDb, err := db.NewDefaultSubstateDB(path)
+	if err != nil {
+		return fmt.Errorf("cannot create aida-db; %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, aidaDb.Close())
+	}()
+	if err = aidaDb.SetSubstateEncoding(db.ProtobufEncodingSchema); err != nil {
+		return err
+	}
+	updateDb, err := db.MakeDefaultUpdateDBFromBaseDB(aidaDb)
+	if err != nil {
+		return err
+	}
+
+	stateDb, stateDbPath, err := utils.PrepareStateDB(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot create state-db; %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, stateDb.Close(), os.RemoveAll(stateDbPath))
+	}()
+
+	g := &generator{
+		cfg:       cfg,
+		log:       log,
+		rand:      rand.New(rand.NewSource(seed)),
+		processor: processor,
+		state:     stateDb,
+		aidaDb:    aidaDb,
+		updateDb:  updateDb,
+		nonces:    make(map[common.Address]uint64),
+		update:    substate.NewWorldState(),
+	}
+	log.Noticef("Generating blocks 1-%v with seed %v", cfg.Last, seed)
+	if err = g.run(); err != nil {
+		return err
+	}
+
+	log.Notice("Generating DbHash")
+	dbHash, err := utildb.GenerateDbHash(aidaDb, cfg.LogLevel)
+	if err != nil {
+		return err
+	}
+	return utils.ProcessGenLikeMetadata(aidaDb, 1, cfg.Last, 0, 0, cfg.ChainID, cfg.LogLevel, dbHash)
+}
+
+func (g *generator) run() error {
+	if err := g.updateDb.PutMetadata(updateSetInterval, g.cfg.UpdateBufferSize); err != nil {
+		return err
+	}
+
+	g.state.BeginSyncPeriod(0)
+	if err := g.genesis(); err != nil {
+		return fmt.Errorf("cannot generate genesis; %w", err)
+	}
+	for block := uint64(1); block <= g.cfg.Last; block++ {
+		if err := g.block(block); err != nil {
+			return fmt.Errorf("cannot generate block %v; %w", block, err)
+		}
+		if block%updateSetInterval == updateSetInterval-1 || block == g.cfg.Last {
+			if err := g.flushUpdateSet(block); err != nil {
+				return err
+			}
+		}
+	}
+	g.state.EndSyncPeriod()
+	return nil
+}
+
+// genesis funds the accounts and deploys the contracts used by the generated
+// transactions. The genesis allocation is recorded as update-set of block 0.
+func (g *generator) genesis() error {
+	g.coinbase = g.randomAddress()
+	alloc := substate.NewWorldState()
+	for range numAccounts {
+		addr := g.randomAddress()
+		g.accounts = append(g.accounts, addr)
+		alloc[substatetypes.Address(addr)] = substate.NewAccount(0, genesisBalance.Clone(), nil)
+	}
+	for range numContracts {
+		addr := g.randomAddress()
+		g.contracts = append(g.contracts, addr)
+		acc := substate.NewAccount(1, new(uint256.Int), storageWriterCode)
+		for slot := range numContractSlots / 2 {
+			acc.Storage[substatetypes.Hash(common.BigToHash(big.NewInt(int64(slot))))] = substatetypes.Hash(g.randomHash())
+		}
+		alloc[substatetypes.Address(addr)] = acc
+	}
+
+	if err := g.state.BeginBlock(0); err != nil {
+		return err
+	}
+	if err := g.state.BeginTransaction(0); err != nil {
+		return err
+	}
+	for addr, acc := range alloc {
+		address := common.Address(addr)
+		g.state.CreateAccount(address)
+		g.state.AddBalance(address, acc.Balance, tracing.BalanceChangeUnspecified)
+		g.state.SetNonce(address, acc.Nonce, tracing.NonceChangeUnspecified)
+		g.state.SetCode(address, acc.Code, tracing.CodeChangeUnspecified)
+		for key, value := range acc.Storage {
+			g.state.SetState(address, common.Hash(key), common.Hash(value))
+		}
+	}
+	if err := g.state.EndTransaction(); err != nil {
+		return err
+	}
+	if err := g.state.EndBlock(); err != nil {
+		return err
+	}
+	if err := g.updateDb.PutUpdateSet(&updateset.UpdateSet{WorldState: alloc, Block: 0}, nil); err != nil {
+		return fmt.Errorf("cannot put genesis update-set; %w", err)
+	}
+	return g.writeHashes(0)
+}
+
+// block executes a random number of transactions in given block and records their substates.
+func (g *generator) block(number uint64) error {
+	random := substatetypes.Hash(g.randomHash())
+	env := substate.NewEnv(substatetypes.Address(g.coinbase), big.NewInt(0), blockGasLimit, number,
+		firstBlockTime+number, baseFee, blobBaseFee, nil, &random)
+
+	if err := g.state.BeginBlock(number); err != nil {
+		return err
+	}
+	numTxs := 1 + g.rand.Intn(maxTxsPerBlock)
+	for tx := range numTxs {
+		ss := &substate.Substate{
+			Env:         env,
+			Message:     g.randomMessage(),
+			Block:       number,
+			Transaction: tx,
+		}
+		if err := g.execute(ss); err != nil {
+			return fmt.Errorf("transaction %v; %w", tx, err)
+		}
+		if err := g.aidaDb.PutSubstate(ss); err != nil {
+			return err
+		}
+		g.update.Merge(ss.OutputSubstate)
+	}
+	if err := g.state.EndBlock(); err != nil {
+		return err
+	}
+	return g.writeHashes(number)
+}
+
+// execute runs the transaction of given substate and fills in its input and
+// output world states and the result.
+func (g *generator) execute(ss *substate.Substate) error {
+	if err := g.state.BeginTransaction(uint32(ss.Transaction)); err != nil {
+		return err
+	}
+	recorder := newAccessRecorder(g.state)
+	res, err := g.processor.ProcessTransaction(recorder, int(ss.Block), ss.Transaction, substatecontext.NewTxContext(ss))
+	if err != nil {
+		return err
+	}
+	ss.InputSubstate = recorder.inputAlloc()
+	ss.OutputSubstate = recorder.outputAlloc()
+	ss.Result = toSubstateResult(res)
+	return g.state.EndTransaction()
+}
+
+// writeHashes records the state root and a block hash derived from it for given block.
+func (g *generator) writeHashes(block uint64) error {
+	root, err := g.state.GetHash()
+	if err != nil {
+		return fmt.Errorf("cannot get state hash; %w", err)
+	}
+	key := hexutil.EncodeUint64(block)
+	if err = db.SaveStateRoot(g.aidaDb, key, root.Hex()); err != nil {
+		return err
+	}
+	number := make([]byte, 8)
+	binary.BigEndian.PutUint64(number, block)
+	return db.SaveBlockHash(g.aidaDb, key, crypto.Keccak256Hash(number, root.Bytes()).Hex())
+}
+
+// flushUpdateSet writes changes accumulated since the last update-set as update-set of given block.
+func (g *generator) flushUpdateSet(block uint64) error {
+	if err := g.updateDb.PutUpdateSet(&updateset.UpdateSet{WorldState: g.update, Block: block}, nil); err != nil {
+		return fmt.Errorf("cannot put update-set of block %v; %w", block, err)
+	}
+	g.log.Infof("Written update-set of block %v with %v accounts", block, len(g.update))
+	g.update = substate.NewWorldState()
+	return nil
+}
+
+// randomMessage creates either a value transfer, a storage write or a storage
+// write running out of gas, sent by one of the genesis accounts.
+func (g *generator) randomMessage() *substate.Message {
+	var (
+		from  = g.accounts[g.rand.Intn(len(g.accounts))]
+		value = new(big.Int)
+		gas   uint64
+		to    common.Address
+		data  []byte
+	)
+	switch kind := g.rand.Intn(10); {
+	case kind < 5:
+		gas = transferGas
+		value.SetInt64(1 + g.rand.Int63n(1_000_000_000_000_000_000))
+		if g.rand.Intn(5) == 0 {
+			to = g.randomAddress() // creates a new account
+		} else {
+			to = g.accounts[g.rand.Intn(len(g.accounts))]
+		}
+	default:
+		gas = storageWriteGas
+		if kind == 9 {
+			gas = outOfGasStorageGas
+		}
+		to = g.contracts[g.rand.Intn(len(g.contracts))]
+		slot := common.BigToHash(big.NewInt(int64(g.rand.Intn(numContractSlots))))
+		var word common.Hash
+		if g.rand.Intn(8) != 0 { // some writes clear the slot
+			word = g.randomHash()
+		}
+		data = append(slot.Bytes(), word.Bytes()...)
+	}
+
+	nonce := g.nonces[from]
+	g.nonces[from]++
+	txType := int32(substate.LegacyTxType)
+	recipient := substatetypes.Address(to)
+	return substate.NewMessage(nonce, true, gasPrice, gas, substatetypes.Address(from), &recipient, value, data,
+		nil, &txType, substatetypes.AccessList{}, gasPrice, gasPrice, nil, nil, nil)
+}
+
+func (g *generator) randomAddress() (addr common.Address) {
+	g.rand.Read(addr[:])
+	return addr
+}
+
+func (g *generator) randomHash() (hash common.Hash) {
+	g.rand.Read(hash[:])
+	return hash
+}
+
+// toSubstateResult converts the receipt of an executed transaction into a substate result.
+func toSubstateResult(res txcontext.Result) *substate.Result {
+	receipt := res.GetReceipt()
+	logs := make([]*substatetypes.Log, 0, len(receipt.GetLogs()))
+	for _, log := range receipt.GetLogs() {
+		topics := make([]substatetypes.Hash, 0, len(log.Topics))
+		for _, topic := range log.Topics {
+			topics = append(topics, substatetypes.Hash(topic))
+		}
+		logs = append(logs, &substatetypes.Log{
+			Address: substatetypes.Address(log.Address),
+			Topics:  topics,
+			Data:    log.Data,
+		})
+	}
+	return substate.NewResult(receipt.GetStatus(), substatetypes.Bloom(receipt.GetBloom()), logs,
+		substatetypes.Address(receipt.GetContractAddress()), receipt.GetGasUsed())
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package synthetic
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/utildb"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func newTestConfig(t *testing.T, last uint64) *utils.Config {
+	cfg := utils.NewTestConfig(t, utils.SonicMainnetChainID, 1, last, false, "")
+	cfg.DbImpl = "geth"
+	cfg.DbTmp = t.TempDir()
+	return cfg
+}
+
+func TestGenerate_WritesConsistentAidaDb(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aida-db")
+	require.NoError(t, Generate(newTestConfig(t, 120), path, 1))
+
+	aidaDb, err := db.NewReadOnlySubstateDB(path)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, aidaDb.Close())
+	}()
+	require.NoError(t, aidaDb.SetSubstateEncoding(db.ProtobufEncodingSchema))
+
+	md := utils.NewAidaDbMetadata(aidaDb, "CRITICAL")
+	assert.Equal(t, uint64(1), md.GetFirstBlock())
+	assert.Equal(t, uint64(120), md.GetLastBlock())
+	assert.Equal(t, utils.SonicMainnetChainID, md.GetChainID())
+	assert.Equal(t, utils.GenType, md.GetDbType())
+	dbHash, err := utildb.GenerateDbHash(aidaDb, "CRITICAL")
+	require.NoError(t, err)
+	assert.Equal(t, dbHash, md.GetDbHash())
+
+	hashes := db.MakeHashProvider(aidaDb)
+	for block := uint64(1); block <= 120; block++ {
+		substates, err := aidaDb.GetBlockSubstates(block)
+		require.NoError(t, err)
+		require.NotEmpty(t, substates, "block %v", block)
+		for _, ss := range substates {
+			assert.NotEmpty(t, ss.InputSubstate, "block %v tx %v", block, ss.Transaction)
+			assert.NotEmpty(t, ss.OutputSubstate, "block %v tx %v", block, ss.Transaction)
+			require.NotNil(t, ss.Result)
+		}
+		_, err = hashes.GetStateRootHash(int(block))
+		require.NoError(t, err, "block %v", block)
+	}
+
+	updateDb, err := db.MakeDefaultUpdateDBFromBaseDB(aidaDb)
+	require.NoError(t, err)
+	for _, block := range []uint64{0, 99, 120} {
+		has, err := updateDb.HasUpdateSet(block)
+		require.NoError(t, err)
+		assert.True(t, has, "missing update-set of block %v", block)
+	}
+	genesis, err := updateDb.GetUpdateSet(0)
+	require.NoError(t, err)
+	assert.Len(t, genesis.WorldState, numAccounts+numContracts)
+}
+
+func TestGenerate_IsDeterministic(t *testing.T) {
+	generate := func(seed int64) []byte {
+		path := filepath.Join(t.TempDir(), "aida-db")
+		require.NoError(t, Generate(newTestConfig(t, 10), path, seed))
+		aidaDb, err := db.NewReadOnlySubstateDB(path)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, aidaDb.Close())
+		}()
+		return utils.NewAidaDbMetadata(aidaDb, "CRITICAL").GetDbHash()
+	}
+
+	first := generate(1)
+	assert.Equal(t, first, generate(1))
+	assert.NotEqual(t, first, generate(2))
+}
+
+func TestGenerate_RequiresAtLeastOneBlock(t *testing.T) {
+	err := Generate(newTestConfig(t, 0), filepath.Join(t.TempDir(), "aida-db"), 1)
+	require.ErrorContains(t, err, "at least one block")
+}
+
+func TestMakeTestData_Command(t *testing.T) {
+	existing := t.TempDir()
+	tests := map[string]struct {
+		args    []string
+		wantErr string
+	}{
+		"missing block": {
+			args:    utils.NewArgs(Command.Name).Flag(utils.TargetDbFlag.Name, filepath.Join(existing, "new")).Build(),
+			wantErr: "requires exactly 1 argument",
+		},
+		"missing target": {
+			args:    utils.NewArgs(Command.Name).Arg("10").Build(),
+			wantErr: "--target-db must be set",
+		},
+		"existing target": {
+			args:    utils.NewArgs(Command.Name).Flag(utils.TargetDbFlag.Name, existing).Arg("10").Build(),
+			wantErr: "already exists",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			app := cli.NewApp()
+			app.Action = Command.Action
+			app.Flags = Command.Flags
+			require.ErrorContains(t, app.Run(test.args), test.wantErr)
+		})
+	}
+}
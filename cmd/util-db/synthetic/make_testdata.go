@@ -0,0 +1,80 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package synthetic
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/urfave/cli/v2"
+)
+
+// defaultSeed is used if no --random-seed is given, so that the generated aida-db is reproducible.
+const defaultSeed = 42
+
+// Command generates a small synthetic aida-db for testing the replay pipeline.
+var Command = cli.Command{
+	Action:    makeTestDataAction,
+	Name:      "make-testdata",
+	Usage:     "generates a small deterministic aida-db from synthetic transactions",
+	ArgsUsage: "<lastBlock>",
+	Flags: []cli.Flag{
+		&utils.TargetDbFlag,
+		&utils.ChainIDFlag,
+		&utils.RandomSeedFlag,
+		&utils.StateDbImplementationFlag,
+		&utils.StateDbVariantFlag,
+		&utils.CarmenSchemaFlag,
+		&utils.DbTmpFlag,
+		&logger.LogLevelFlag,
+	},
+	Description: `
+The make-testdata command requires one argument: <lastBlock>
+
+A new aida-db is written into --target-db. It contains a genesis update-set funding
+a set of accounts and deploying storage writing contracts, followed by blocks 1 to
+<lastBlock> of value transfers, storage writes and out-of-gas calls. For each block
+the substates, the state root and a block hash are recorded, update-sets are written
+every 100 blocks, and the metadata includes the DbHash so that the result passes
+util-db validate. Apart from the creation timestamp in the metadata, the content only
+depends on <lastBlock>, --random-seed, --chainid and the StateDb computing the state roots.`,
+}
+
+func makeTestDataAction(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return fmt.Errorf("make-testdata command requires exactly 1 argument")
+	}
+	cfg, err := utils.NewConfig(ctx, utils.LastBlockArg)
+	if err != nil {
+		return err
+	}
+	if cfg.TargetDb == "" {
+		return fmt.Errorf("--%v must be set", utils.TargetDbFlag.Name)
+	}
+	if _, err = os.Stat(cfg.TargetDb); !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("target aida-db %v already exists", cfg.TargetDb)
+	}
+
+	seed := int64(defaultSeed)
+	if ctx.IsSet(utils.RandomSeedFlag.Name) {
+		seed = cfg.RandomSeed
+	}
+	return Generate(cfg, cfg.TargetDb, seed)
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package synthetic
+
+import (
+	"bytes"
+
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/substate/substate"
+	substatetypes "github.com/0xsoniclabs/substate/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/holiman/uint256"
+)
+
+// accessRecorder is a VmStateDB wrapper capturing the accounts and storage slots
+// accessed by a single transaction. The values observed on first access form the
+// input substate, the values present after the execution form the output substate.
+type accessRecorder struct {
+	state.VmStateDB
+	accounts map[common.Address]*substate.Account // nil if the account did not exist before the transaction
+	slots    map[common.Address]map[common.Hash]common.Hash
+}
+
+func newAccessRecorder(db state.VmStateDB) *accessRecorder {
+	return &accessRecorder{
+		VmStateDB: db,
+		accounts:  make(map[common.Address]*substate.Account),
+		slots:     make(map[common.Address]map[common.Hash]common.Hash),
+	}
+}
+
+// touchAccount records the state of given account before it is accessed for the first time.
+func (r *accessRecorder) touchAccount(addr common.Address) {
+	if _, ok := r.accounts[addr]; ok {
+		return
+	}
+	var acc *substate.Account
+	if r.VmStateDB.Exist(addr) {
+		acc = substate.NewAccount(
+			r.VmStateDB.GetNonce(addr),
+			new(uint256.Int).Set(r.VmStateDB.GetBalance(addr)),
+			bytes.Clone(r.VmStateDB.GetCode(addr)),
+		)
+	}
+	r.accounts[addr] = acc
+	r.slots[addr] = make(map[common.Hash]common.Hash)
+}
+
+// touchSlot records the value of given storage slot before it is accessed for the first time.
+func (r *accessRecorder) touchSlot(addr common.Address, key common.Hash) {
+	r.touchAccount(addr)
+	if _, ok := r.slots[addr][key]; !ok {
+		r.slots[addr][key] = r.VmStateDB.GetState(addr, key)
+	}
+}
+
+// inputAlloc returns the pre-transaction state of all accessed accounts which existed before the transaction.
+func (r *accessRecorder) inputAlloc() substate.WorldState {
+	ws := substate.NewWorldState()
+	for addr, acc := range r.accounts {
+		if acc == nil {
+			continue
+		}
+		in := acc.Copy()
+		for key, value := range r.slots[addr] {
+			in.Storage[substatetypes.Hash(key)] = substatetypes.Hash(value)
+		}
+		ws[substatetypes.Address(addr)] = in
+	}
+	return ws
+}
+
+// outputAlloc returns the post-transaction state of all accessed accounts. Accounts
+// which are empty after the transaction are omitted, since they are removed at the
+// end of the transaction.
+func (r *accessRecorder) outputAlloc() substate.WorldState {
+	ws := substate.NewWorldState()
+	for addr := range r.accounts {
+		if !r.VmStateDB.Exist(addr) || r.VmStateDB.Empty(addr) {
+			continue
+		}
+		out := substate.NewAccount(
+			r.VmStateDB.GetNonce(addr),
+			new(uint256.Int).Set(r.VmStateDB.GetBalance(addr)),
+			bytes.Clone(r.VmStateDB.GetCode(addr)),
+		)
+		for key := range r.slots[addr] {
+			out.Storage[substatetypes.Hash(key)] = substatetypes.Hash(r.VmStateDB.GetState(addr, key))
+		}
+		ws[substatetypes.Address(addr)] = out
+	}
+	return ws
+}
+
+func (r *accessRecorder) CreateAccount(addr common.Address) {
+	r.touchAccount(addr)
+	r.VmStateDB.CreateAccount(addr)
+}
+
+func (r *accessRecorder) CreateContract(addr common.Address) {
+	r.touchAccount(addr)
+	r.VmStateDB.CreateContract(addr)
+}
+
+func (r *accessRecorder) Exist(addr common.Address) bool {
+	r.touchAccount(addr)
+	return r.VmStateDB.Exist(addr)
+}
+
+func (r *accessRecorder) Empty(addr common.Address) bool {
+	r.touchAccount(addr)
+	return r.VmStateDB.Empty(addr)
+}
+
+func (r *accessRecorder) SelfDestruct(addr common.Address) {
+	r.touchAccount(addr)
+	r.VmStateDB.SelfDestruct(addr)
+}
+
+func (r *accessRecorder) GetBalance(addr common.Address) *uint256.Int {
+	r.touchAccount(addr)
+	return r.VmStateDB.GetBalance(addr)
+}
+
+func (r *accessRecorder) AddBalance(addr common.Address, value *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	r.touchAccount(addr)
+	return r.VmStateDB.AddBalance(addr, value, reason)
+}
+
+func (r *accessRecorder) SubBalance(addr common.Address, value *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	r.touchAccount(addr)
+	return r.VmStateDB.SubBalance(addr, value, reason)
+}
+
+func (r *accessRecorder) GetNonce(addr common.Address) uint64 {
+	r.touchAccount(addr)
+	return r.VmStateDB.GetNonce(addr)
+}
+
+func (r *accessRecorder) SetNonce(addr common.Address, nonce uint64, reason tracing.NonceChangeReason) {
+	r.touchAccount(addr)
+	r.VmStateDB.SetNonce(addr, nonce, reason)
+}
+
+func (r *accessRecorder) GetCommittedState(addr common.Address, key common.Hash) common.Hash {
+	r.touchSlot(addr, key)
+	return r.VmStateDB.GetCommittedState(addr, key)
+}
+
+func (r *accessRecorder) GetState(addr common.Address, key common.Hash) common.Hash {
+	r.touchSlot(addr, key)
+	return r.VmStateDB.GetState(addr, key)
+}
+
+func (r *accessRecorder) SetState(addr common.Address, key common.Hash, value common.Hash) common.Hash {
+	r.touchSlot(addr, key)
+	return r.VmStateDB.SetState(addr, key, value)
+}
+
+func (r *accessRecorder) GetStateAndCommittedState(addr common.Address, key common.Hash) (common.Hash, common.Hash) {
+	r.touchSlot(addr, key)
+	return r.VmStateDB.GetStateAndCommittedState(addr, key)
+}
+
+func (r *accessRecorder) GetStorageRoot(addr common.Address) common.Hash {
+	r.touchAccount(addr)
+	return r.VmStateDB.GetStorageRoot(addr)
+}
+
+func (r *accessRecorder) GetCodeHash(addr common.Address) common.Hash {
+	r.touchAccount(addr)
+	return r.VmStateDB.GetCodeHash(addr)
+}
+
+func (r *accessRecorder) GetCode(addr common.Address) []byte {
+	r.touchAccount(addr)
+	return r.VmStateDB.GetCode(addr)
+}
+
+func (r *accessRecorder) SetCode(addr common.Address, code []byte, reason tracing.CodeChangeReason) []byte {
+	r.touchAccount(addr)
+	return r.VmStateDB.SetCode(addr, code, reason)
+}
+
+func (r *accessRecorder) GetCodeSize(addr common.Address) int {
+	r.touchAccount(addr)
+	return r.VmStateDB.GetCodeSize(addr)
+}
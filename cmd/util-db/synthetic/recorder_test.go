@@ -0,0 +1,73 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package synthetic
+
+import (
+	"testing"
+
+	"github.com/0xsoniclabs/aida/state"
+	substatecontext "github.com/0xsoniclabs/aida/txcontext/substate"
+	"github.com/0xsoniclabs/substate/substate"
+	substatetypes "github.com/0xsoniclabs/substate/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessRecorder_RecordsPreAndPostStateOfAccessedAccounts(t *testing.T) {
+	existing, created, missing := common.Address{1}, common.Address{2}, common.Address{3}
+	key := common.Hash{4}
+	pre := substate.NewAccount(1, uint256.NewInt(10), []byte{5})
+	pre.Storage[substatetypes.Hash(key)] = substatetypes.Hash{6}
+	db := state.MakeInMemoryStateDB(substatecontext.NewWorldState(substate.WorldState{substatetypes.Address(existing): pre}), 1)
+
+	recorder := newAccessRecorder(db)
+	recorder.GetBalance(existing)
+	recorder.SetState(existing, key, common.Hash{7})
+	recorder.AddBalance(created, uint256.NewInt(5), tracing.BalanceChangeUnspecified)
+	assert.False(t, recorder.Exist(missing))
+
+	in := recorder.inputAlloc()
+	require.Len(t, in, 1)
+	assert.True(t, pre.Equal(in[substatetypes.Address(existing)]))
+
+	out := recorder.outputAlloc()
+	require.Len(t, out, 2)
+	want := pre.Copy()
+	want.Storage[substatetypes.Hash(key)] = substatetypes.Hash{7}
+	assert.True(t, want.Equal(out[substatetypes.Address(existing)]))
+	assert.True(t, substate.NewAccount(0, uint256.NewInt(5), []byte{}).Equal(out[substatetypes.Address(created)]))
+}
+
+func TestAccessRecorder_InputKeepsFirstObservedSlotValue(t *testing.T) {
+	addr, key := common.Address{1}, common.Hash{2}
+	pre := substate.NewAccount(1, uint256.NewInt(1), nil)
+	pre.Storage[substatetypes.Hash(key)] = substatetypes.Hash{3}
+	db := state.MakeInMemoryStateDB(substatecontext.NewWorldState(substate.WorldState{substatetypes.Address(addr): pre}), 1)
+
+	recorder := newAccessRecorder(db)
+	recorder.SetState(addr, key, common.Hash{4})
+	recorder.SetState(addr, key, common.Hash{5})
+	assert.Equal(t, common.Hash{5}, recorder.GetState(addr, key))
+
+	in := recorder.inputAlloc()
+	assert.Equal(t, substatetypes.Hash{3}, in[substatetypes.Address(addr)].Storage[substatetypes.Hash(key)])
+	out := recorder.outputAlloc()
+	assert.Equal(t, substatetypes.Hash{5}, out[substatetypes.Address(addr)].Storage[substatetypes.Hash(key)])
+}
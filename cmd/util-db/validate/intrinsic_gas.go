@@ -0,0 +1,94 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validate
+
+import (
+	"fmt"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utildb"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/urfave/cli/v2"
+)
+
+var intrinsicGasCommand = cli.Command{
+	Action:    intrinsicGasAction,
+	Name:      "intrinsic-gas",
+	Usage:     "Flags substates whose message gas limit is below the intrinsic gas of their fork.",
+	ArgsUsage: "<blockNumFirst> <blockNumLast>",
+	Flags: []cli.Flag{
+		&utils.AidaDbFlag,
+		&utils.ChainIDFlag,
+		&utils.SubstateEncodingFlag,
+		&logger.LogLevelFlag,
+	},
+}
+
+func intrinsicGasAction(ctx *cli.Context) error {
+	cfg, err := utils.NewConfig(ctx, utils.BlockRangeArgs)
+	if err != nil {
+		return fmt.Errorf("cannot parse config; %v", err)
+	}
+
+	aidaDb, err := db.NewReadOnlySubstateDB(cfg.AidaDb)
+	if err != nil {
+		return fmt.Errorf("cannot open db; %v", err)
+	}
+	defer utildb.MustCloseDB(aidaDb)
+
+	provider, err := executor.OpenSubstateProvider(cfg, ctx, aidaDb)
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+
+	return checkIntrinsicGas(cfg, provider, logger.NewLogger(cfg.LogLevel, "Validate-IntrinsicGas"))
+}
+
+// checkIntrinsicGas reports every substate of the configured range whose gas limit does not cover
+// the intrinsic gas of its message. Such substates can only be produced by broken recordings.
+func checkIntrinsicGas(cfg *utils.Config, provider executor.Provider[txcontext.TxContext], log logger.Logger) error {
+	var checked, flagged int
+	err := provider.Run(int(cfg.First), int(cfg.Last)+1, func(info executor.TransactionInfo[txcontext.TxContext]) error {
+		if info.Transaction >= utils.PseudoTx {
+			return nil
+		}
+		env := info.Data.GetBlockEnvironment()
+		chainCfg, err := cfg.GetChainConfig(env.GetFork())
+		if err != nil {
+			return fmt.Errorf("cannot get chain config of block %d; %w", info.Block, err)
+		}
+		checked++
+		if err = utils.CheckIntrinsicGas(info.Data.GetMessage(), utils.GetRules(chainCfg, env)); err != nil {
+			flagged++
+			log.Warningf("Block %d, transaction %d: %v", info.Block, info.Transaction, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Noticef("Checked intrinsic gas of %d substates", checked)
+	if flagged > 0 {
+		return fmt.Errorf("%d substates have a gas limit below their intrinsic gas", flagged)
+	}
+	return nil
+}
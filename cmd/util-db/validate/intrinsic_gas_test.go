@@ -0,0 +1,87 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validate
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckIntrinsicGas_FlagsSubstatesBelowIntrinsicGas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := executor.NewMockProvider[txcontext.TxContext](ctrl)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{ChainID: utils.SonicMainnetChainID, First: 10, Last: 11}
+
+	env := txcontext.NewMockBlockEnvironment(ctrl)
+	env.EXPECT().GetFork().Return("cancun").AnyTimes()
+	env.EXPECT().GetNumber().Return(uint64(10)).AnyTimes()
+	env.EXPECT().GetRandom().Return(&common.Hash{}).AnyTimes()
+	env.EXPECT().GetTimestamp().Return(uint64(1700000000)).AnyTimes()
+
+	makeTx := func(gasLimit uint64) txcontext.TxContext {
+		tx := txcontext.NewMockTxContext(ctrl)
+		tx.EXPECT().GetBlockEnvironment().Return(env).AnyTimes()
+		tx.EXPECT().GetMessage().Return(&core.Message{
+			To:       &common.Address{1},
+			Value:    big.NewInt(0),
+			GasLimit: gasLimit,
+		}).AnyTimes()
+		return tx
+	}
+	// pseudo transactions carry no message and must be skipped
+	pseudoTx := txcontext.NewMockTxContext(ctrl)
+
+	provider.EXPECT().Run(10, 12, gomock.Any()).DoAndReturn(func(_, _ int, consumer executor.Consumer[txcontext.TxContext]) error {
+		for _, info := range []executor.TransactionInfo[txcontext.TxContext]{
+			{Block: 10, Transaction: 0, Data: makeTx(21_000)},
+			{Block: 10, Transaction: 1, Data: makeTx(20_999)},
+			{Block: 11, Transaction: utils.PseudoTx, Data: pseudoTx},
+		} {
+			if err := consumer(info); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	log.EXPECT().Warningf("Block %d, transaction %d: %v", 10, 1, gomock.Any())
+	log.EXPECT().Noticef("Checked intrinsic gas of %d substates", 2)
+
+	err := checkIntrinsicGas(cfg, provider, log)
+	require.ErrorContains(t, err, "1 substates have a gas limit below their intrinsic gas")
+}
+
+func TestCheckIntrinsicGas_PassesValidSubstates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := executor.NewMockProvider[txcontext.TxContext](ctrl)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{ChainID: utils.SonicMainnetChainID, First: 10, Last: 10}
+
+	provider.EXPECT().Run(10, 11, gomock.Any()).Return(nil)
+	log.EXPECT().Noticef("Checked intrinsic gas of %d substates", 0)
+
+	require.NoError(t, checkIntrinsicGas(cfg, provider, log))
+}
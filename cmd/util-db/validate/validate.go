@@ -20,7 +20,9 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"path/filepath"
 
+	"github.com/0xsoniclabs/aida/cmd/util-db/flags"
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/utildb"
 	"github.com/0xsoniclabs/aida/utils"
@@ -35,6 +37,12 @@ var Command = cli.Command{
 	Flags: []cli.Flag{
 		&utils.AidaDbFlag,
 		&utils.ChainIDFlag,
+		&utils.ScanCachePolicyFlag,
+		&utils.WorkersFlag,
+		&flags.RestartValidation,
+	},
+	Subcommands: []*cli.Command{
+		&intrinsicGasCommand,
 	},
 }
 
@@ -82,8 +90,27 @@ func validateAction(ctx *cli.Context) error {
 
 	log.Noticef("Found DbHash for your Db: %v", hex.EncodeToString(expectedHash))
 
-	log.Noticef("Starting DbHash calculation for %v; this may take several hours...", cfg.AidaDb)
-	trueHash, err := utildb.GenerateDbHash(aidaDb, "INFO")
+	stopScanCachePolicy, err := utildb.StartScanCachePolicy(cfg.ScanCachePolicy, cfg.AidaDb, log)
+	if err != nil {
+		return err
+	}
+	defer stopScanCachePolicy()
+
+	lastBlock := md.GetLastBlock()
+	if lastBlock == 0 {
+		if substateDb, err := db.MakeDefaultSubstateDBFromBaseDB(aidaDb); err == nil {
+			_, lastBlock, _ = utils.FindBlockRangeInSubstate(substateDb)
+		}
+	}
+
+	log.Noticef("Starting DbHash calculation for %v using %v workers; this may take several hours...", cfg.AidaDb, cfg.Workers)
+	trueHash, err := utildb.GenerateDbHashInChunks(ctx.Context, aidaDb, utildb.ChunkedDbHashConfig{
+		LastBlock:  lastBlock,
+		ChunkSize:  utildb.DefaultDbHashChunkSize,
+		Workers:    cfg.Workers,
+		Checkpoint: checkpointPath(cfg.AidaDb),
+		Force:      ctx.Bool(flags.RestartValidation.Name),
+	}, "INFO")
 	if err != nil {
 		return err
 	}
@@ -103,3 +130,8 @@ func validateAction(ctx *cli.Context) error {
 
 	return nil
 }
+
+// checkpointPath returns the path of the file next to the AidaDb recording the progress of an interrupted validation.
+func checkpointPath(aidaDb string) string {
+	return filepath.Clean(aidaDb) + ".validate-checkpoint"
+}
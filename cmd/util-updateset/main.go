@@ -35,6 +35,7 @@ var GenUpdateSetApp = cli.App{
 	Commands: []*cli.Command{
 		&updateset.GenUpdateSetCommand,
 		&updateset.UpdateSetStatsCommand,
+		&updateset.ExportUpdateSetCommand,
 	},
 }
 
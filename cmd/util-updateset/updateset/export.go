@@ -0,0 +1,67 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package updateset
+
+import (
+	"errors"
+
+	"github.com/0xsoniclabs/aida/prime"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/urfave/cli/v2"
+)
+
+var ExportUpdateSetCommand = cli.Command{
+	Action:    exportUpdateSet,
+	Name:      "export",
+	Usage:     "export update-set into files which can be served over http(s)",
+	ArgsUsage: "<blockNumFirst> <blockNumLast>",
+	Flags: []cli.Flag{
+		&utils.UpdateDbFlag,
+		&utils.OutputFlag,
+	},
+	Description: `
+The export command requires two arguments: <blockNumFirst> <blockNumLast>
+
+<blockNumFirst> and <blockNumLast> are the first and last block of the inclusive range of exported update sets.
+
+Each update set is written into its own file within the --output directory next to an index listing them.
+Served over http(s), the directory can be passed as --update-db to priming commands.`,
+}
+
+// exportUpdateSet exports update sets of the update-set database into a directory.
+func exportUpdateSet(ctx *cli.Context) (err error) {
+	cfg, argErr := utils.NewConfig(ctx, utils.BlockRangeArgs)
+	if argErr != nil {
+		return argErr
+	}
+	if cfg.Output == "" {
+		return errors.New("export requires --output directory")
+	}
+	udb, err := db.NewReadOnlyUpdateDB(cfg.UpdateDb)
+	if err != nil {
+		return err
+	}
+	defer func(udb db.UpdateDB) {
+		err = errors.Join(err, udb.Close())
+	}(udb)
+
+	iter := prime.NewFileUpdateSetSource(udb).NewUpdateSetIterator(cfg.First, cfg.Last)
+	defer iter.Release()
+
+	return prime.WriteUpdateSetFiles(iter, cfg.Output)
+}
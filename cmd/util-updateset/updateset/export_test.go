@@ -0,0 +1,77 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package updateset
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/prime"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestCmd_RunExportUpdateSetCommand(t *testing.T) {
+	// given
+	tempDir := t.TempDir()
+	aidaDbPath := filepath.Join(tempDir, "aida-db")
+	outputDir := filepath.Join(tempDir, "export")
+	require.NoError(t, utils.CopyDir(path.Join(testDataDir, "sample-rlp-db"), aidaDbPath))
+	app := cli.NewApp()
+	app.Commands = []*cli.Command{&ExportUpdateSetCommand}
+
+	args := utils.NewArgs("test").
+		Arg(ExportUpdateSetCommand.Name).
+		Flag(utils.UpdateDbFlag.Name, aidaDbPath).
+		Flag(utils.OutputFlag.Name, outputDir).
+		Arg("first").
+		Arg("last").
+		Build()
+
+	// when
+	err := app.Run(args)
+
+	// then
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(outputDir, prime.UpdateSetIndexName))
+	require.NoError(t, err)
+	var index prime.UpdateSetIndex
+	require.NoError(t, json.Unmarshal(data, &index))
+	for _, file := range index.UpdateSets {
+		assert.FileExists(t, filepath.Join(outputDir, file.File))
+	}
+}
+
+func TestCmd_ExportUpdateSetCommandRequiresOutput(t *testing.T) {
+	app := cli.NewApp()
+	app.Commands = []*cli.Command{&ExportUpdateSetCommand}
+
+	args := utils.NewArgs("test").
+		Arg(ExportUpdateSetCommand.Name).
+		Flag(utils.UpdateDbFlag.Name, t.TempDir()).
+		Arg("0").
+		Arg("100").
+		Build()
+
+	err := app.Run(args)
+	assert.ErrorContains(t, err, "requires --output")
+}
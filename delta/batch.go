@@ -0,0 +1,133 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package delta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BatchIndexFile is the name of the index listing summaries of all traces of a batch.
+const BatchIndexFile = "index.json"
+
+// BatchConfig describes a minimization of multiple traces.
+type BatchConfig struct {
+	TraceFiles []string        // traces minimized independently of each other
+	OutputDir  string          // directory receiving minimized traces, their summaries and the index
+	Minimizer  MinimizerConfig // configuration of the minimizer created for each trace
+	Timeout    time.Duration   // optional timeout of the minimization of a single trace
+}
+
+// BatchSummary describes the minimization of a single trace of a batch.
+type BatchSummary struct {
+	TraceFile      string  `json:"traceFile"`
+	Output         string  `json:"output,omitempty"`
+	OriginalOps    int     `json:"originalOps"`
+	MinimizedOps   int     `json:"minimizedOps"`
+	RuntimeSeconds float64 `json:"runtimeSeconds"`
+	ReductionRatio float64 `json:"reductionRatio"` // fraction of operations removed from the original trace
+	Error          string  `json:"error,omitempty"`
+}
+
+// MinimizeBatch minimizes each trace of the batch independently using given test function and
+// writes the minimized trace and a summary of each trace into the output directory, named after
+// the position and the base name of the trace. A trace which cannot be minimized is recorded with
+// its error and does not stop the others. An index of all summaries is written once the batch
+// finishes or the context is cancelled.
+func MinimizeBatch(ctx context.Context, cfg BatchConfig, test testFunc) ([]BatchSummary, error) {
+	if len(cfg.TraceFiles) == 0 {
+		return nil, fmt.Errorf("delta: no trace files provided")
+	}
+	if err := os.MkdirAll(cfg.OutputDir, 0o755); err != nil {
+		return nil, fmt.Errorf("delta: ensure output directory: %w", err)
+	}
+
+	var (
+		summaries []BatchSummary
+		err       error
+	)
+	for i, traceFile := range cfg.TraceFiles {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		name := fmt.Sprintf("%d-%s", i, filepath.Base(traceFile))
+		summary := minimizeBatchTrace(ctx, cfg, traceFile, filepath.Join(cfg.OutputDir, name), test)
+		summaries = append(summaries, summary)
+		if writeErr := writeJson(filepath.Join(cfg.OutputDir, name+".summary.json"), summary); writeErr != nil {
+			err = writeErr
+			break
+		}
+	}
+
+	if indexErr := writeJson(filepath.Join(cfg.OutputDir, BatchIndexFile), summaries); indexErr != nil {
+		err = errors.Join(err, indexErr)
+	}
+	return summaries, err
+}
+
+// minimizeBatchTrace minimizes a single trace of a batch and writes the result to given output path.
+func minimizeBatchTrace(ctx context.Context, cfg BatchConfig, traceFile string, output string, test testFunc) (summary BatchSummary) {
+	summary.TraceFile = traceFile
+	start := time.Now()
+	defer func() {
+		summary.RuntimeSeconds = time.Since(start).Seconds()
+	}()
+
+	ops, err := LoadOperations([]string{traceFile}, 0, 0)
+	if err != nil {
+		summary.Error = err.Error()
+		return summary
+	}
+	summary.OriginalOps = len(ops)
+
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	minimized, err := NewMinimizer(cfg.Minimizer).Minimize(ctx, ops, test)
+	if err == nil {
+		err = WriteTrace(output, minimized)
+	}
+	if err != nil {
+		summary.Error = err.Error()
+		return summary
+	}
+
+	summary.Output = output
+	summary.MinimizedOps = len(minimized)
+	summary.ReductionRatio = 1 - float64(len(minimized))/float64(len(ops))
+	return summary
+}
+
+// writeJson writes given value as indented json into the file at given path.
+func writeJson(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("delta: encode %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("delta: write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
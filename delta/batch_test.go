@@ -0,0 +1,165 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package delta
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var batchFailingContract = common.HexToAddress("0x3")
+
+// writeBatchTrace writes a trace of a single transaction setting a slot of each given contract.
+func writeBatchTrace(t *testing.T, dir string, name string, contracts ...common.Address) string {
+	content := "BeginBlock, 1\nBeginTransaction, 0\n"
+	for _, contract := range contracts {
+		content += "SetState, " + contract.Hex() + ", 0x0, 0x0, 0x0\n"
+	}
+	content += "EndTransaction\nEndBlock\n"
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// failsOnContract is a test function failing for candidates setting a slot of batchFailingContract.
+func failsOnContract(_ context.Context, candidate []TraceOp) (outcome, error) {
+	for _, op := range candidate {
+		if op.Kind == "SetState" && op.Contract == batchFailingContract {
+			return outcomeFail, nil
+		}
+	}
+	return outcomePass, nil
+}
+
+// readBatchSummaries reads the index of given output directory.
+func readBatchSummaries(t *testing.T, dir string) []BatchSummary {
+	data, err := os.ReadFile(filepath.Join(dir, BatchIndexFile))
+	require.NoError(t, err)
+	var summaries []BatchSummary
+	require.NoError(t, json.Unmarshal(data, &summaries))
+	return summaries
+}
+
+func TestMinimizeBatch_EachTraceIsMinimizedIndependently(t *testing.T) {
+	dir := t.TempDir()
+	outputDir := filepath.Join(dir, "out")
+	first := writeBatchTrace(t, dir, "first.log", common.HexToAddress("0x1"), batchFailingContract, common.HexToAddress("0x2"))
+	second := writeBatchTrace(t, dir, "second.log", batchFailingContract, common.HexToAddress("0x4"))
+
+	summaries, err := MinimizeBatch(context.Background(), BatchConfig{
+		TraceFiles: []string{first, second},
+		OutputDir:  outputDir,
+		Minimizer:  MinimizerConfig{AddressSampleRuns: 5, RandSeed: 1},
+	}, failsOnContract)
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+
+	for i, want := range []struct {
+		trace       string
+		output      string
+		originalOps int
+	}{
+		{first, filepath.Join(outputDir, "0-first.log"), 7},
+		{second, filepath.Join(outputDir, "1-second.log"), 6},
+	} {
+		summary := summaries[i]
+		require.Empty(t, summary.Error)
+		require.Equal(t, want.trace, summary.TraceFile)
+		require.Equal(t, want.output, summary.Output)
+		require.Equal(t, want.originalOps, summary.OriginalOps)
+		require.Equal(t, 5, summary.MinimizedOps)
+		require.InDelta(t, 1-5/float64(want.originalOps), summary.ReductionRatio, 1e-9)
+		require.GreaterOrEqual(t, summary.RuntimeSeconds, 0.0)
+
+		ops, err := LoadOperations([]string{summary.Output}, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, ops, summary.MinimizedOps)
+		require.Len(t, UniqueContracts(ops), 1)
+
+		data, err := os.ReadFile(summary.Output + ".summary.json")
+		require.NoError(t, err)
+		var written BatchSummary
+		require.NoError(t, json.Unmarshal(data, &written))
+		require.Equal(t, summary, written)
+	}
+	require.Equal(t, summaries, readBatchSummaries(t, outputDir))
+}
+
+func TestMinimizeBatch_FailureOfOneTraceDoesNotAbortOthers(t *testing.T) {
+	dir := t.TempDir()
+	outputDir := filepath.Join(dir, "out")
+	passing := writeBatchTrace(t, dir, "passing.log", common.HexToAddress("0x1"))
+	missing := filepath.Join(dir, "missing.log")
+	failing := writeBatchTrace(t, dir, "failing.log", batchFailingContract, common.HexToAddress("0x2"))
+
+	summaries, err := MinimizeBatch(context.Background(), BatchConfig{
+		TraceFiles: []string{passing, missing, failing},
+		OutputDir:  outputDir,
+		Minimizer:  MinimizerConfig{AddressSampleRuns: 5, RandSeed: 1},
+	}, failsOnContract)
+	require.NoError(t, err)
+	require.Len(t, summaries, 3)
+
+	require.Contains(t, summaries[0].Error, ErrInputDoesNotFail.Error())
+	require.Equal(t, 5, summaries[0].OriginalOps)
+	require.Empty(t, summaries[0].Output)
+	require.NoFileExists(t, filepath.Join(outputDir, "0-passing.log"))
+	require.FileExists(t, filepath.Join(outputDir, "0-passing.log.summary.json"))
+
+	require.Contains(t, summaries[1].Error, "open trace")
+	require.Zero(t, summaries[1].OriginalOps)
+
+	require.Empty(t, summaries[2].Error)
+	require.Equal(t, 5, summaries[2].MinimizedOps)
+	require.FileExists(t, summaries[2].Output)
+
+	require.Equal(t, summaries, readBatchSummaries(t, outputDir))
+}
+
+func TestMinimizeBatch_CancellationStopsBatch(t *testing.T) {
+	dir := t.TempDir()
+	outputDir := filepath.Join(dir, "out")
+	first := writeBatchTrace(t, dir, "first.log", batchFailingContract)
+	second := writeBatchTrace(t, dir, "second.log", batchFailingContract)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	test := func(ctx context.Context, candidate []TraceOp) (outcome, error) {
+		cancel()
+		return outcomeUnresolved, ctx.Err()
+	}
+
+	summaries, err := MinimizeBatch(ctx, BatchConfig{
+		TraceFiles: []string{first, second},
+		OutputDir:  outputDir,
+	}, test)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, summaries, 1)
+	require.Contains(t, summaries[0].Error, context.Canceled.Error())
+	require.Equal(t, summaries, readBatchSummaries(t, outputDir))
+}
+
+func TestMinimizeBatch_NoTraceFiles(t *testing.T) {
+	_, err := MinimizeBatch(context.Background(), BatchConfig{OutputDir: t.TempDir()}, failsOnContract)
+	require.ErrorContains(t, err, "no trace files provided")
+}
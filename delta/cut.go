@@ -0,0 +1,215 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package delta
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// noTransaction marks a cut point located at the block level, outside any transaction.
+const noTransaction = -1
+
+// CutPoint designates the operation at which a trace prefix ends. Op is the offset
+// of the operation relative to the BeginTransaction of transaction Tx of block Block,
+// or relative to the BeginBlock if the cut lies outside any transaction.
+type CutPoint struct {
+	Block uint64
+	Tx    int
+	Op    int
+}
+
+// ParseCutPoint parses a cut point in the form <block>:<tx>:<op>. The transaction
+// may be given as '-' to designate an operation outside any transaction.
+func ParseCutPoint(s string) (CutPoint, error) {
+	parts := splitAndTrim(s, ":")
+	if len(parts) != 3 {
+		return CutPoint{}, fmt.Errorf("delta: invalid cut point %q, expected <block>:<tx>:<op>", s)
+	}
+	block, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return CutPoint{}, fmt.Errorf("delta: invalid cut point block %q: %w", parts[0], err)
+	}
+	tx := noTransaction
+	if parts[1] != "-" {
+		v, err := parseUint32(parts[1])
+		if err != nil {
+			return CutPoint{}, fmt.Errorf("delta: invalid cut point transaction %q: %w", parts[1], err)
+		}
+		tx = int(v)
+	}
+	op, err := strconv.Atoi(parts[2])
+	if err != nil || op < 0 {
+		return CutPoint{}, fmt.Errorf("delta: invalid cut point operation %q", parts[2])
+	}
+	return CutPoint{Block: block, Tx: tx, Op: op}, nil
+}
+
+func (c CutPoint) String() string {
+	tx := "-"
+	if c.Tx != noTransaction {
+		tx = strconv.Itoa(c.Tx)
+	}
+	return fmt.Sprintf("%d:%s:%d", c.Block, tx, c.Op)
+}
+
+// locateCut returns the index of the operation designated by the cut point.
+func locateCut(ops []TraceOp, cut CutPoint) (int, error) {
+	for idx, op := range ops {
+		if op.Kind != "BeginBlock" || !op.HasBlock || op.Block != cut.Block {
+			continue
+		}
+		begin := idx
+		if cut.Tx != noTransaction {
+			begin = findTransaction(ops, idx, cut.Tx)
+			if begin < 0 {
+				return 0, fmt.Errorf("delta: block %d has no transaction %d", cut.Block, cut.Tx)
+			}
+		}
+		target := begin + cut.Op
+		if end := scopeEnd(ops, begin); target > end {
+			return 0, fmt.Errorf("delta: cut point %v lies beyond the end of its scope", cut)
+		}
+		return target, nil
+	}
+	return 0, fmt.Errorf("delta: trace does not contain block %d", cut.Block)
+}
+
+// cutPointOf returns the cut point designating the operation at the given index.
+func cutPointOf(ops []TraceOp, idx int) CutPoint {
+	var block uint64
+	blockIdx, txIdx, tx := 0, -1, noTransaction
+	for i := 0; i <= idx && i < len(ops); i++ {
+		switch ops[i].Kind {
+		case "BeginBlock":
+			block, blockIdx, txIdx, tx = ops[i].Block, i, -1, noTransaction
+		case "BeginTransaction":
+			if id, ok := transactionID(ops[i]); ok {
+				txIdx, tx = i, id
+			}
+		case "EndTransaction":
+			if i < idx {
+				txIdx, tx = -1, noTransaction
+			}
+		}
+	}
+	if txIdx >= 0 {
+		return CutPoint{Block: block, Tx: tx, Op: idx - txIdx}
+	}
+	return CutPoint{Block: block, Tx: noTransaction, Op: idx - blockIdx}
+}
+
+// cutGuards disables all operations following the operation at index cut, except
+// the end operations closing the scopes still open at the cut. Hence, the resulting
+// trace stays balanced and the open transaction and block are finished right after
+// the cut. Snapshots taken within the open transaction are not reverted; they are
+// dropped together with the transaction once it ends.
+func cutGuards(ops []TraceOp, guards []bool, cut int) []bool {
+	candidate := copyGuards(guards)
+
+	open := make([]string, 0, len(scopeBeginToEnd))
+	for idx := 0; idx <= cut && idx < len(ops); idx++ {
+		if !guards[idx] {
+			continue
+		}
+		open = pushOrPopScope(open, ops[idx].Kind)
+	}
+
+	dropped := make([]string, 0)
+	for idx := cut + 1; idx < len(ops); idx++ {
+		if !guards[idx] {
+			continue
+		}
+		candidate[idx] = false
+		kind := ops[idx].Kind
+		if _, ok := scopeBeginToEnd[kind]; ok {
+			dropped = append(dropped, kind)
+			continue
+		}
+		beginKind, isEnd := scopeEndToBegin[kind]
+		if !isEnd {
+			continue
+		}
+		if len(dropped) > 0 {
+			dropped = dropped[:len(dropped)-1]
+			continue
+		}
+		if len(open) > 0 && open[len(open)-1] == beginKind {
+			open = open[:len(open)-1]
+			candidate[idx] = true
+		}
+	}
+	return candidate
+}
+
+// pushOrPopScope updates the stack of open scopes by the given operation kind.
+func pushOrPopScope(open []string, kind string) []string {
+	if _, ok := scopeBeginToEnd[kind]; ok {
+		return append(open, kind)
+	}
+	if beginKind, ok := scopeEndToBegin[kind]; ok && len(open) > 0 && open[len(open)-1] == beginKind {
+		return open[:len(open)-1]
+	}
+	return open
+}
+
+// findTransaction returns the index of the BeginTransaction with the given id
+// within the block starting at index begin, or -1 if there is none.
+func findTransaction(ops []TraceOp, begin int, tx int) int {
+	for idx := begin + 1; idx < len(ops); idx++ {
+		switch ops[idx].Kind {
+		case "EndBlock":
+			return -1
+		case "BeginTransaction":
+			if id, ok := transactionID(ops[idx]); ok && id == tx {
+				return idx
+			}
+		}
+	}
+	return -1
+}
+
+// scopeEnd returns the index of the operation closing the scope opened at index
+// begin, or the last index of the trace if the scope is never closed.
+func scopeEnd(ops []TraceOp, begin int) int {
+	endKind := scopeBeginToEnd[ops[begin].Kind]
+	depth := 0
+	for idx := begin + 1; idx < len(ops); idx++ {
+		switch ops[idx].Kind {
+		case ops[begin].Kind:
+			depth++
+		case endKind:
+			if depth == 0 {
+				return idx
+			}
+			depth--
+		}
+	}
+	return len(ops) - 1
+}
+
+func transactionID(op TraceOp) (int, bool) {
+	s, err := getArg(op.Args, 0)
+	if err != nil {
+		return 0, false
+	}
+	id, err := parseUint32(s)
+	if err != nil {
+		return 0, false
+	}
+	return int(id), true
+}
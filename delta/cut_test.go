@@ -0,0 +1,194 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package delta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func cutTestTrace() []TraceOp {
+	return []TraceOp{
+		{Kind: "BeginSyncPeriod", Args: []string{"1"}},
+		{Kind: "BeginBlock", Args: []string{"7"}, HasBlock: true, Block: 7},
+		{Kind: "BeginTransaction", Args: []string{"0"}, HasBlock: true, Block: 7},
+		{Kind: "Snapshot", HasBlock: true, Block: 7},
+		{Kind: "SetNonce", Args: []string{common.HexToAddress("0x1").Hex(), "1", "Unspecified"}, HasBlock: true, Block: 7},
+		{Kind: "RevertToSnapshot", Args: []string{"0"}, HasBlock: true, Block: 7},
+		{Kind: "EndTransaction", HasBlock: true, Block: 7},
+		{Kind: "BeginTransaction", Args: []string{"1"}, HasBlock: true, Block: 7},
+		{Kind: "GetNonce", Args: []string{common.HexToAddress("0x1").Hex()}, HasBlock: true, Block: 7},
+		{Kind: "EndTransaction", HasBlock: true, Block: 7},
+		{Kind: "EndBlock", HasBlock: true, Block: 7},
+		{Kind: "EndSyncPeriod", HasBlock: true, Block: 7},
+	}
+}
+
+func TestParseCutPoint(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  CutPoint
+		err   bool
+	}{
+		"transaction":  {input: "12:3:4", want: CutPoint{Block: 12, Tx: 3, Op: 4}},
+		"block level":  {input: "12:-:0", want: CutPoint{Block: 12, Tx: noTransaction, Op: 0}},
+		"spaces":       {input: " 12 : 3 : 4 ", want: CutPoint{Block: 12, Tx: 3, Op: 4}},
+		"missing part": {input: "12:3", err: true},
+		"bad block":    {input: "x:3:4", err: true},
+		"bad tx":       {input: "12:-1:4", err: true},
+		"negative op":  {input: "12:3:-4", err: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseCutPoint(test.input)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestCutPoint_StringRoundTrip(t *testing.T) {
+	for _, cut := range []CutPoint{{Block: 1, Tx: 2, Op: 3}, {Block: 4, Tx: noTransaction, Op: 5}} {
+		parsed, err := ParseCutPoint(cut.String())
+		require.NoError(t, err)
+		require.Equal(t, cut, parsed)
+	}
+}
+
+func TestLocateCut(t *testing.T) {
+	ops := cutTestTrace()
+
+	idx, err := locateCut(ops, CutPoint{Block: 7, Tx: 1, Op: 1})
+	require.NoError(t, err)
+	require.Equal(t, 8, idx)
+
+	idx, err = locateCut(ops, CutPoint{Block: 7, Tx: noTransaction, Op: 0})
+	require.NoError(t, err)
+	require.Equal(t, 1, idx)
+
+	_, err = locateCut(ops, CutPoint{Block: 8, Tx: 0, Op: 0})
+	require.ErrorContains(t, err, "does not contain block 8")
+
+	_, err = locateCut(ops, CutPoint{Block: 7, Tx: 5, Op: 0})
+	require.ErrorContains(t, err, "has no transaction 5")
+
+	_, err = locateCut(ops, CutPoint{Block: 7, Tx: 0, Op: 5})
+	require.ErrorContains(t, err, "beyond the end of its scope")
+}
+
+func TestCutPointOf_InvertsLocateCut(t *testing.T) {
+	ops := cutTestTrace()
+	for idx := 1; idx < len(ops)-1; idx++ {
+		got, err := locateCut(ops, cutPointOf(ops, idx))
+		require.NoError(t, err)
+		require.Equal(t, idx, got, "cut point %v", cutPointOf(ops, idx))
+	}
+	require.Equal(t, CutPoint{Block: 7, Tx: 0, Op: 4}, cutPointOf(ops, 6))
+	require.Equal(t, CutPoint{Block: 7, Tx: noTransaction, Op: 9}, cutPointOf(ops, 10))
+}
+
+func TestCutGuards_KeepsEndOperationsOfOpenScopes(t *testing.T) {
+	ops := cutTestTrace()
+
+	// Cut right after the snapshot of the first transaction; the revert is dropped.
+	result := operationsForGuards(ops, cutGuards(ops, newGuardVector(len(ops)), 3))
+	kinds := make([]string, 0, len(result))
+	for _, op := range result {
+		kinds = append(kinds, op.Kind)
+	}
+	require.Equal(t, []string{
+		"BeginSyncPeriod", "BeginBlock", "BeginTransaction", "Snapshot",
+		"EndTransaction", "EndBlock", "EndSyncPeriod",
+	}, kinds)
+}
+
+func TestCutGuards_RespectsDisabledOperations(t *testing.T) {
+	ops := cutTestTrace()
+	guards := newGuardVector(len(ops))
+	guards[6] = false // EndTransaction of first transaction
+	guards[7] = false // BeginTransaction of second transaction
+
+	result := cutGuards(ops, guards, 4)
+	require.True(t, isSubset(result, guards))
+	require.False(t, result[6])
+	require.False(t, result[8])
+	require.True(t, result[9], "EndTransaction of the second transaction closes the open transaction")
+	require.True(t, result[10])
+	require.True(t, result[11])
+}
+
+func TestMinimize_CutPointSearchRunsOnPrefixes(t *testing.T) {
+	ops := cutTestTrace()
+	var lengths []int
+	test := func(_ context.Context, candidate []TraceOp) (outcome, error) {
+		lengths = append(lengths, len(candidate))
+		for _, op := range candidate {
+			if op.Kind == "SetNonce" {
+				return outcomeFail, nil
+			}
+		}
+		return outcomePass, nil
+	}
+
+	var logs []string
+	m := NewMinimizer(MinimizerConfig{RandSeed: 1, Logger: func(format string, _ ...any) {
+		logs = append(logs, format)
+	}})
+	result, err := m.Minimize(context.Background(), ops, test)
+	require.NoError(t, err)
+
+	for _, op := range result {
+		require.NotEqual(t, "GetNonce", op.Kind, "operations after the cut must be removed")
+		require.NotEqual(t, "RevertToSnapshot", op.Kind, "operations after the cut must be removed")
+	}
+	require.Contains(t, logs, "cut point search accepted: cut=%v removed=%d")
+	for _, length := range lengths[1:] {
+		require.Less(t, length, len(ops))
+	}
+}
+
+func TestMinimize_DesignatedCutPoint(t *testing.T) {
+	ops := cutTestTrace()
+	test := func(_ context.Context, candidate []TraceOp) (outcome, error) {
+		for _, op := range candidate {
+			if op.Kind == "SetNonce" {
+				return outcomeFail, nil
+			}
+		}
+		return outcomePass, nil
+	}
+
+	m := NewMinimizer(MinimizerConfig{RandSeed: 1, CutPoint: &CutPoint{Block: 7, Tx: 0, Op: 2}})
+	result, err := m.Minimize(context.Background(), ops, test)
+	require.NoError(t, err)
+	require.NotEmpty(t, result)
+
+	m = NewMinimizer(MinimizerConfig{RandSeed: 1, CutPoint: &CutPoint{Block: 7, Tx: 0, Op: 1}})
+	_, err = m.Minimize(context.Background(), ops, test)
+	require.ErrorContains(t, err, "does not fail when cut at 7:0:1")
+
+	m = NewMinimizer(MinimizerConfig{RandSeed: 1, CutPoint: &CutPoint{Block: 9, Tx: 0, Op: 1}})
+	_, err = m.Minimize(context.Background(), ops, test)
+	require.ErrorContains(t, err, "does not contain block 9")
+}
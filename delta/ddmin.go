@@ -0,0 +1,165 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package delta
+
+import (
+	"context"
+	"fmt"
+)
+
+// transactionUnit holds the indices of the active operations of one transaction,
+// including its BeginTransaction and EndTransaction.
+type transactionUnit []int
+
+// transactionElimination applies ddmin to the sequence of transactions. Each
+// transaction is kept or dropped as a whole while operations outside of any
+// transaction are considered setup and always retained. This converges on
+// failures caused by a specific interleaving of transactions, for which sampling
+// contract addresses performs poorly.
+func (m *Minimizer) transactionElimination(
+	ctx context.Context,
+	ops []TraceOp,
+	scopeForest []*scopeNode,
+	guards []bool,
+	test testFunc,
+) ([]bool, error) {
+	current := copyGuards(guards)
+	units := activeTransactionUnits(scopeForest, current)
+	granularity := 2
+
+	for len(units) >= 2 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		chunks := splitUnits(units, granularity)
+		reduced := false
+
+		// Try to reduce to a single chunk first, then to the complement of one.
+		// For two chunks, the complements are the chunks themselves.
+		candidates := append([][]transactionUnit{}, chunks...)
+		if granularity > 2 {
+			for idx := range chunks {
+				candidates = append(candidates, complementUnits(chunks, idx))
+			}
+		}
+		for idx, kept := range candidates {
+			candidate := keepUnits(current, units, kept)
+			if !isSubset(candidate, current) {
+				return nil, fmt.Errorf("delta: transaction elimination produced a non-subset candidate")
+			}
+
+			fails, err := m.reproducesFailure(ctx, ops, candidate, test)
+			if err != nil {
+				return nil, err
+			}
+			if !fails {
+				continue
+			}
+
+			m.log(
+				"transaction elimination accepted: transactions=%d removed=%d",
+				len(kept),
+				countOnes(current)-countOnes(candidate),
+			)
+			current = candidate
+			units = kept
+			if idx < len(chunks) {
+				granularity = 2
+			} else {
+				granularity = max(granularity-1, 2)
+			}
+			reduced = true
+			break
+		}
+
+		if reduced {
+			continue
+		}
+		if granularity >= len(units) {
+			break
+		}
+		granularity = min(2*granularity, len(units))
+	}
+
+	return current, nil
+}
+
+// activeTransactionUnits returns the transactions with active operations in
+// trace order.
+func activeTransactionUnits(scopeForest []*scopeNode, guards []bool) []transactionUnit {
+	units := make([]transactionUnit, 0)
+	var visit func(node *scopeNode)
+	visit = func(node *scopeNode) {
+		if node.kind != "BeginTransaction" {
+			for _, child := range node.children {
+				visit(child)
+			}
+			return
+		}
+		unit := make(transactionUnit, 0)
+		for idx := node.start; idx <= node.end && idx < len(guards); idx++ {
+			if guards[idx] {
+				unit = append(unit, idx)
+			}
+		}
+		if len(unit) > 0 {
+			units = append(units, unit)
+		}
+	}
+	for _, root := range scopeForest {
+		visit(root)
+	}
+	return units
+}
+
+// splitUnits partitions the units into n contiguous chunks of nearly equal size.
+func splitUnits(units []transactionUnit, n int) [][]transactionUnit {
+	n = min(n, len(units))
+	chunks := make([][]transactionUnit, 0, n)
+	for i := 0; i < n; i++ {
+		chunks = append(chunks, units[i*len(units)/n:(i+1)*len(units)/n])
+	}
+	return chunks
+}
+
+// complementUnits returns the units of all chunks except the one at index skip.
+func complementUnits(chunks [][]transactionUnit, skip int) []transactionUnit {
+	result := make([]transactionUnit, 0)
+	for idx, chunk := range chunks {
+		if idx != skip {
+			result = append(result, chunk...)
+		}
+	}
+	return result
+}
+
+// keepUnits disables the operations of all given units except the kept ones.
+func keepUnits(guards []bool, units []transactionUnit, kept []transactionUnit) []bool {
+	candidate := copyGuards(guards)
+	for _, unit := range units {
+		for _, idx := range unit {
+			candidate[idx] = false
+		}
+	}
+	for _, unit := range kept {
+		for _, idx := range unit {
+			candidate[idx] = true
+		}
+	}
+	return candidate
+}
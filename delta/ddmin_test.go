@@ -0,0 +1,232 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package delta
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// transactionTestTrace builds a single block trace with the given number of
+// transactions, each touching its own account.
+func transactionTestTrace(transactions int) []TraceOp {
+	ops := []TraceOp{
+		{Kind: "BeginSyncPeriod", Args: []string{"1"}},
+		{Kind: "BeginBlock", Args: []string{"5"}, HasBlock: true, Block: 5},
+	}
+	for tx := 0; tx < transactions; tx++ {
+		addr := common.BytesToAddress([]byte{byte(tx + 1)})
+		ops = append(ops,
+			TraceOp{Kind: "BeginTransaction", Args: []string{strconv.Itoa(tx)}, HasBlock: true, Block: 5},
+			TraceOp{Kind: "CreateAccount", Args: []string{addr.Hex()}, HasContract: true, Contract: addr, HasBlock: true, Block: 5},
+			TraceOp{Kind: "SetNonce", Args: []string{addr.Hex(), "1", "Unspecified"}, HasContract: true, Contract: addr, HasBlock: true, Block: 5},
+			TraceOp{Kind: "EndTransaction", HasBlock: true, Block: 5},
+		)
+	}
+	return append(ops,
+		TraceOp{Kind: "EndBlock", HasBlock: true, Block: 5},
+		TraceOp{Kind: "EndSyncPeriod", HasBlock: true, Block: 5},
+	)
+}
+
+// withTransactions returns the operations of the trace outside of transactions
+// and of the listed transactions.
+func withTransactions(ops []TraceOp, txs ...int) []TraceOp {
+	keep := make(map[int]bool, len(txs))
+	for _, tx := range txs {
+		keep[tx] = true
+	}
+	result := make([]TraceOp, 0)
+	current := noTransaction
+	for _, op := range ops {
+		if op.Kind == "BeginTransaction" {
+			current, _ = transactionID(op)
+		}
+		if current == noTransaction || keep[current] {
+			result = append(result, op)
+		}
+		if op.Kind == "EndTransaction" {
+			current = noTransaction
+		}
+	}
+	return result
+}
+
+// failsWithTransactions returns a stub test runner failing only if the candidate
+// contains all operations of the given transactions in the given order.
+func failsWithTransactions(txs ...int) (testFunc, *int) {
+	calls := 0
+	return func(_ context.Context, ops []TraceOp) (outcome, error) {
+		calls++
+		complete := make([]int, 0)
+		current, seen := noTransaction, 0
+		for _, op := range ops {
+			switch op.Kind {
+			case "BeginTransaction":
+				current, _ = transactionID(op)
+				seen = 0
+			case "EndTransaction":
+				if current != noTransaction && seen == 2 {
+					complete = append(complete, current)
+				}
+				current = noTransaction
+			default:
+				if current != noTransaction {
+					seen++
+				}
+			}
+		}
+		next := 0
+		for _, tx := range complete {
+			if next < len(txs) && tx == txs[next] {
+				next++
+			}
+		}
+		if next == len(txs) {
+			return outcomeFail, nil
+		}
+		return outcomePass, nil
+	}, &calls
+}
+
+func TestTransactionElimination_KeepsOnlyMarkedTransaction(t *testing.T) {
+	ops := transactionTestTrace(16)
+	test, _ := failsWithTransactions(11)
+
+	m := NewMinimizer(MinimizerConfig{RandSeed: 1})
+	guards, err := m.transactionElimination(context.Background(), ops, buildScopeForest(ops), newGuardVector(len(ops)), test)
+	require.NoError(t, err)
+	require.Equal(t, withTransactions(ops, 11), operationsForGuards(ops, guards))
+}
+
+func TestTransactionElimination_KeepsRequiredSetup(t *testing.T) {
+	ops := transactionTestTrace(16)
+	test, _ := failsWithTransactions(3, 11)
+
+	m := NewMinimizer(MinimizerConfig{RandSeed: 1})
+	guards, err := m.transactionElimination(context.Background(), ops, buildScopeForest(ops), newGuardVector(len(ops)), test)
+	require.NoError(t, err)
+	require.Equal(t, withTransactions(ops, 3, 11), operationsForGuards(ops, guards))
+}
+
+func TestTransactionElimination_IgnoresDisabledTransactions(t *testing.T) {
+	ops := transactionTestTrace(4)
+	guards := newGuardVector(len(ops))
+	// Disable transaction 3 up front; it must not be revived.
+	for idx := 2 + 3*4; idx < 2+4*4; idx++ {
+		guards[idx] = false
+	}
+	test, _ := failsWithTransactions(1)
+
+	m := NewMinimizer(MinimizerConfig{RandSeed: 1})
+	result, err := m.transactionElimination(context.Background(), ops, buildScopeForest(ops), guards, test)
+	require.NoError(t, err)
+	require.Equal(t, withTransactions(ops, 1), operationsForGuards(ops, result))
+}
+
+func TestTransactionElimination_SingleTransactionIsKept(t *testing.T) {
+	ops := transactionTestTrace(1)
+	test, calls := failsWithTransactions(0)
+
+	m := NewMinimizer(MinimizerConfig{RandSeed: 1})
+	guards, err := m.transactionElimination(context.Background(), ops, buildScopeForest(ops), newGuardVector(len(ops)), test)
+	require.NoError(t, err)
+	require.Equal(t, ops, operationsForGuards(ops, guards))
+	require.Zero(t, *calls)
+}
+
+func TestTransactionElimination_PropagatesTestError(t *testing.T) {
+	ops := transactionTestTrace(4)
+	injected := errors.New("injected")
+	test := func(context.Context, []TraceOp) (outcome, error) {
+		return outcomeUnresolved, injected
+	}
+
+	m := NewMinimizer(MinimizerConfig{RandSeed: 1})
+	_, err := m.transactionElimination(context.Background(), ops, buildScopeForest(ops), newGuardVector(len(ops)), test)
+	require.ErrorIs(t, err, injected)
+}
+
+func TestTransactionElimination_ContextCancellation(t *testing.T) {
+	ops := transactionTestTrace(4)
+	test, _ := failsWithTransactions(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	m := NewMinimizer(MinimizerConfig{RandSeed: 1})
+	_, err := m.transactionElimination(ctx, ops, buildScopeForest(ops), newGuardVector(len(ops)), test)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestMinimize_TransactionStrategy(t *testing.T) {
+	ops := transactionTestTrace(12)
+	test, _ := failsWithTransactions(2, 9)
+
+	m := NewMinimizer(MinimizerConfig{
+		RandSeed:   1,
+		Strategies: []Strategy{StrategyTransactions},
+	})
+	result, err := m.Minimize(context.Background(), ops, test)
+	require.NoError(t, err)
+	require.Equal(t, withTransactions(ops, 2, 9), result)
+}
+
+func TestMinimize_AllStrategiesKeepMarkedTransactions(t *testing.T) {
+	ops := transactionTestTrace(12)
+	test, _ := failsWithTransactions(2, 9)
+
+	for _, strategies := range [][]Strategy{
+		{StrategyAddresses, StrategyTransactions},
+		{StrategyTransactions, StrategyAddresses},
+	} {
+		m := NewMinimizer(MinimizerConfig{RandSeed: 1, Strategies: strategies})
+		result, err := m.Minimize(context.Background(), ops, test)
+		require.NoError(t, err)
+		require.Equal(t, withTransactions(ops, 2, 9), result, "strategies %v", strategies)
+	}
+}
+
+func TestMinimize_UnknownStrategy(t *testing.T) {
+	ops := transactionTestTrace(2)
+	test, _ := failsWithTransactions(1)
+
+	m := NewMinimizer(MinimizerConfig{RandSeed: 1, Strategies: []Strategy{"prefix"}})
+	_, err := m.Minimize(context.Background(), ops, test)
+	require.ErrorContains(t, err, "unknown strategy")
+}
+
+func TestMinimize_VerifiesFinalTrace(t *testing.T) {
+	ops := transactionTestTrace(4)
+	calls := 0
+	// The failure is only observed on the very first run.
+	test := func(context.Context, []TraceOp) (outcome, error) {
+		calls++
+		if calls == 1 {
+			return outcomeFail, nil
+		}
+		return outcomePass, nil
+	}
+
+	m := NewMinimizer(MinimizerConfig{RandSeed: 1})
+	_, err := m.Minimize(context.Background(), ops, test)
+	require.ErrorIs(t, err, ErrMinimizedDoesNotFail)
+}
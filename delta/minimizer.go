@@ -67,6 +67,8 @@ type MinimizerConfig struct {
 	RandSeed          int64 // RNG seed (<=0 uses time-based seed)
 	MaxFactor         int   // optional upper bound for sampled address-set size
 	MandatoryKinds    map[string]struct{}
+	CutPoint          *CutPoint  // optional operation after which the failure is known to occur
+	Strategies        []Strategy // content reduction phases in order of application (empty uses DefaultStrategies)
 	Logger            func(format string, args ...any)
 }
 
@@ -117,6 +119,20 @@ func (m *Minimizer) Minimize(ctx context.Context, ops []TraceOp, test testFunc)
 		return nil, ErrInputDoesNotFail
 	}
 
+	if m.cfg.CutPoint != nil {
+		guards, err = m.applyCutPoint(ctx, ops, guards, *m.cfg.CutPoint, test)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Shortening the trace first makes every following content reduction
+	// iteration cheaper whenever the failure occurs early in the trace.
+	guards, err = m.cutPointSearch(ctx, ops, guards, test)
+	if err != nil {
+		return nil, err
+	}
+
 	scopeForest := buildScopeForest(ops)
 
 	for {
@@ -131,9 +147,11 @@ func (m *Minimizer) Minimize(ctx context.Context, ops []TraceOp, test testFunc)
 			return nil, err
 		}
 
-		guards, err = m.addressElimination(ctx, ops, guards, test)
-		if err != nil {
-			return nil, err
+		for _, strategy := range m.cfg.Strategies {
+			guards, err = m.applyStrategy(ctx, strategy, ops, scopeForest, guards, test)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		guards, err = m.emptyStructureElimination(ctx, ops, scopeForest, guards, test)
@@ -146,9 +164,112 @@ func (m *Minimizer) Minimize(ctx context.Context, ops []TraceOp, test testFunc)
 		}
 	}
 
+	// Each accepted candidate failed once; make sure the final trace still does
+	// before handing it out, since the outcome of a run may be non-deterministic.
+	fails, err = m.reproducesFailure(ctx, ops, guards, test)
+	if err != nil {
+		return nil, err
+	}
+	if !fails {
+		return nil, ErrMinimizedDoesNotFail
+	}
+
 	return operationsForGuards(ops, guards), nil
 }
 
+// applyStrategy runs the content reduction phase named by the strategy.
+func (m *Minimizer) applyStrategy(
+	ctx context.Context,
+	strategy Strategy,
+	ops []TraceOp,
+	scopeForest []*scopeNode,
+	guards []bool,
+	test testFunc,
+) ([]bool, error) {
+	switch strategy {
+	case StrategyAddresses:
+		return m.addressElimination(ctx, ops, guards, test)
+	case StrategyTransactions:
+		return m.transactionElimination(ctx, ops, scopeForest, guards, test)
+	default:
+		return nil, fmt.Errorf("delta: unknown strategy %q", strategy)
+	}
+}
+
+// applyCutPoint restricts the trace to the prefix ending at the designated cut point.
+func (m *Minimizer) applyCutPoint(
+	ctx context.Context,
+	ops []TraceOp,
+	guards []bool,
+	cut CutPoint,
+	test testFunc,
+) ([]bool, error) {
+	idx, err := locateCut(ops, cut)
+	if err != nil {
+		return nil, err
+	}
+	candidate := cutGuards(ops, guards, idx)
+	fails, err := m.reproducesFailure(ctx, ops, candidate, test)
+	if err != nil {
+		return nil, err
+	}
+	if !fails {
+		return nil, fmt.Errorf("delta: trace does not fail when cut at %v", cut)
+	}
+	m.log("cut point %v accepted: removed=%d", cut, countOnes(guards)-countOnes(candidate))
+	return candidate, nil
+}
+
+// cutPointSearch binary-searches the shortest failing trace prefix. The failure is
+// assumed to persist once it occurred, hence every prefix longer than a failing
+// one fails as well.
+func (m *Minimizer) cutPointSearch(
+	ctx context.Context,
+	ops []TraceOp,
+	guards []bool,
+	test testFunc,
+) ([]bool, error) {
+	active := make([]int, 0, len(guards))
+	for idx, enabled := range guards {
+		if enabled {
+			active = append(active, idx)
+		}
+	}
+	if len(active) == 0 {
+		return guards, nil
+	}
+
+	// The current trace is known to fail, so does the prefix cut at its last operation.
+	lo := -1
+	hi := len(active) - 1
+	for hi-lo > 1 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		mid := (lo + hi) / 2
+		candidate := cutGuards(ops, guards, active[mid])
+		fails, err := m.reproducesFailure(ctx, ops, candidate, test)
+		if err != nil {
+			return nil, err
+		}
+		if fails {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	next := cutGuards(ops, guards, active[hi])
+	if !isSubset(next, guards) {
+		return nil, fmt.Errorf("delta: cut point search produced a non-subset candidate")
+	}
+	if removed := countOnes(guards) - countOnes(next); removed > 0 {
+		m.log("cut point search accepted: cut=%v removed=%d", cutPointOf(ops, active[hi]), removed)
+	}
+	return next, nil
+}
+
 func (m *Minimizer) structuralHalvening(
 	ctx context.Context,
 	ops []TraceOp,
@@ -834,6 +955,9 @@ func NewMinimizer(cfg MinimizerConfig) *Minimizer {
 	if cfg.MandatoryKinds == nil {
 		cfg.MandatoryKinds = defaultMandatoryKinds()
 	}
+	if len(cfg.Strategies) == 0 {
+		cfg.Strategies = DefaultStrategies
+	}
 	seed := cfg.RandSeed
 	if seed == 0 {
 		seed = time.Now().UnixNano()
@@ -872,6 +996,10 @@ var inheritContractKinds = map[string]struct{}{
 // ErrInputDoesNotFail indicates the original trace did not reproduce the failure.
 var ErrInputDoesNotFail = fmt.Errorf("delta: original trace does not reproduce the failure")
 
+// ErrMinimizedDoesNotFail indicates the minimized trace no longer reproduced the failure
+// when it was verified at the end of the minimisation.
+var ErrMinimizedDoesNotFail = fmt.Errorf("delta: minimized trace does not reproduce the failure")
+
 // UniqueContracts returns the sorted set of contract addresses referenced by operations.
 func UniqueContracts(ops []TraceOp) []common.Address {
 	meta := collectMetadata(ops, defaultMandatoryKinds())
@@ -34,11 +34,44 @@ import (
 type stateReplayer struct {
 	backend      state.StateDB
 	currentBlock uint64
+	currentTx    int      // transaction being replayed, noTransaction outside any transaction
+	openScopes   []string // kinds of begin operations whose scope is not closed yet
+	position     int      // index of the operation being replayed
+
+	strict        bool        // values returned by read operations are verified against Result lines
+	maxMismatches int         // number of mismatching results tolerated before the replay fails
+	mismatches    int         // number of mismatching results observed so far
+	firstMismatch error       // description of the first mismatching result, nil if none
+	lastRead      *readResult // value returned by the preceding operation if it was a verified read
+}
+
+// readResult is the value returned by a read operation during a strict replay.
+type readResult struct {
+	op       TraceOp
+	position int
+	block    uint64
+	tx       int
+	value    string
 }
 
 // newStateReplayer constructs a replayer for the provided StateDB.
 func newStateReplayer(backend state.StateDB) *stateReplayer {
-	return &stateReplayer{backend: backend}
+	return &stateReplayer{backend: backend, currentTx: noTransaction}
+}
+
+// withStrictMode makes the replayer verify the values returned by read operations
+// against the values recorded in Result lines. The replay fails once more than
+// maxMismatches results differ. Traces without Result lines replay as usual.
+func (r *stateReplayer) withStrictMode(maxMismatches int) *stateReplayer {
+	r.strict = true
+	r.maxMismatches = maxMismatches
+	return r
+}
+
+// Mismatches returns the number of mismatching results observed so far together with
+// a description of the first one, or nil if all verified results matched.
+func (r *stateReplayer) Mismatches() (int, error) {
+	return r.mismatches, r.firstMismatch
 }
 
 // Execute runs all trace operations until completion or failure.
@@ -47,6 +80,7 @@ func (r *stateReplayer) Execute(ctx context.Context, ops []TraceOp) error {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
+		r.position = i
 		if err := r.execute(op); err != nil {
 			return fmt.Errorf("operation %d (%s): %w", i, op.Kind, err)
 		}
@@ -54,7 +88,134 @@ func (r *stateReplayer) Execute(ctx context.Context, ops []TraceOp) error {
 	return nil
 }
 
+// ExecutePrefix runs the trace operations like Execute and afterwards closes all
+// scopes left open by the operations, so that a trace cut in the middle of a
+// transaction or block can be evaluated as if it completed at the cut. Open
+// snapshots are not reverted; they are discarded when the transaction ends.
+func (r *stateReplayer) ExecutePrefix(ctx context.Context, ops []TraceOp) error {
+	if err := r.Execute(ctx, ops); err != nil {
+		return err
+	}
+	return r.closeOpenScopes()
+}
+
+// closeOpenScopes ends open transactions, blocks and sync periods, innermost first.
+func (r *stateReplayer) closeOpenScopes() error {
+	for len(r.openScopes) > 0 {
+		kind := r.openScopes[len(r.openScopes)-1]
+		r.openScopes = r.openScopes[:len(r.openScopes)-1]
+		switch kind {
+		case "BeginTransaction":
+			r.currentTx = noTransaction
+			if err := r.backend.EndTransaction(); err != nil {
+				return fmt.Errorf("close open transaction: %w", err)
+			}
+		case "BeginBlock":
+			if err := r.backend.EndBlock(); err != nil {
+				return fmt.Errorf("close open block %d: %w", r.currentBlock, err)
+			}
+		case "BeginSyncPeriod":
+			r.backend.EndSyncPeriod()
+		}
+	}
+	return nil
+}
+
 func (r *stateReplayer) execute(op TraceOp) error {
+	read := r.lastRead
+	r.lastRead = nil
+	if op.Kind == "Result" {
+		return r.verifyResult(read, op)
+	}
+	if err := r.apply(op); err != nil {
+		return err
+	}
+	r.openScopes = pushOrPopScope(r.openScopes, op.Kind)
+	return nil
+}
+
+// observe remembers the value returned by a read operation, so that it can be
+// verified by a directly following Result line.
+func (r *stateReplayer) observe(op TraceOp, value string) {
+	r.lastRead = &readResult{
+		op:       op,
+		position: r.position,
+		block:    r.currentBlock,
+		tx:       r.currentTx,
+		value:    value,
+	}
+}
+
+// verifyResult compares the value recorded in a Result line with the value returned
+// by the read operation preceding it. Results are ignored outside of the strict mode
+// and if they do not belong to the preceding operation, which happens once the read
+// has been removed from a reduced trace.
+func (r *stateReplayer) verifyResult(read *readResult, result TraceOp) error {
+	if !r.strict || read == nil || !read.matches(result) {
+		return nil
+	}
+	recorded, err := normalizeResult(result.SubKind, result.Args[len(result.Args)-1])
+	if err != nil {
+		return fmt.Errorf("invalid %s result: %w", result.SubKind, err)
+	}
+	if recorded == read.value {
+		return nil
+	}
+
+	r.mismatches++
+	if r.firstMismatch == nil {
+		tx := "-"
+		if read.tx != noTransaction {
+			tx = strconv.Itoa(read.tx)
+		}
+		r.firstMismatch = fmt.Errorf("operation %d (%s) in block %d, tx %s returned %s, recorded %s",
+			read.position, read.op.Kind, read.block, tx, read.value, recorded)
+	}
+	if r.mismatches > r.maxMismatches {
+		return fmt.Errorf("strict replay: %d read results mismatched, first at %w", r.mismatches, r.firstMismatch)
+	}
+	return nil
+}
+
+// matches reports whether the Result line records the value of the read operation,
+// i.e. whether it repeats the kind and the arguments of the operation.
+func (rr *readResult) matches(result TraceOp) bool {
+	if result.SubKind != rr.op.Kind || len(result.Args) != len(rr.op.Args)+2 {
+		return false
+	}
+	for i, arg := range rr.op.Args {
+		if !strings.EqualFold(arg, result.Args[i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeResult converts a recorded result into the form produced by the replayer.
+func normalizeResult(kind string, raw string) (string, error) {
+	args := []string{raw}
+	switch kind {
+	case "Exist":
+		v, err := parseBool(args, 0)
+		return strconv.FormatBool(v), err
+	case "GetBalance":
+		v, err := parseUint256(args, 0)
+		if err != nil {
+			return "", err
+		}
+		return v.String(), nil
+	case "GetNonce":
+		v, err := parseUint64(args, 0)
+		return strconv.FormatUint(v, 10), err
+	case "GetState", "GetCodeHash":
+		v, err := parseHash(args, 0)
+		return v.Hex(), err
+	default:
+		return "", fmt.Errorf("results of %s are not recorded", kind)
+	}
+}
+
+func (r *stateReplayer) apply(op TraceOp) error {
 	if op.Kind == "Bulk" {
 		return fmt.Errorf("bulk operations are not supported in logger traces")
 	}
@@ -96,7 +257,10 @@ func (r *stateReplayer) execute(op TraceOp) error {
 		if err != nil {
 			return err
 		}
-		r.backend.Exist(addr)
+		exists := r.backend.Exist(addr)
+		if r.strict {
+			r.observe(op, strconv.FormatBool(exists))
+		}
 	case "Empty":
 		addr, err := parseAddress(op.Args, 0)
 		if err != nil {
@@ -120,7 +284,13 @@ func (r *stateReplayer) execute(op TraceOp) error {
 		if err != nil {
 			return err
 		}
-		r.backend.GetBalance(addr)
+		balance := r.backend.GetBalance(addr)
+		if r.strict {
+			if balance == nil {
+				balance = new(uint256.Int)
+			}
+			r.observe(op, balance.String())
+		}
 	case "AddBalance":
 		addr, err := parseAddress(op.Args, 0)
 		if err != nil {
@@ -154,7 +324,10 @@ func (r *stateReplayer) execute(op TraceOp) error {
 		if err != nil {
 			return err
 		}
-		r.backend.GetNonce(addr)
+		nonce := r.backend.GetNonce(addr)
+		if r.strict {
+			r.observe(op, strconv.FormatUint(nonce, 10))
+		}
 	case "SetNonce":
 		addr, err := parseAddress(op.Args, 0)
 		if err != nil {
@@ -198,7 +371,10 @@ func (r *stateReplayer) execute(op TraceOp) error {
 		if err != nil {
 			return err
 		}
-		r.backend.GetState(addr, key)
+		value := r.backend.GetState(addr, key)
+		if r.strict {
+			r.observe(op, value.Hex())
+		}
 	case "SetState":
 		addr, err := parseAddress(op.Args, 0)
 		if err != nil {
@@ -254,7 +430,10 @@ func (r *stateReplayer) execute(op TraceOp) error {
 		if err != nil {
 			return err
 		}
-		r.backend.GetCodeHash(addr)
+		hash := r.backend.GetCodeHash(addr)
+		if r.strict {
+			r.observe(op, hash.Hex())
+		}
 	case "SetCode":
 		addr, err := parseAddress(op.Args, 0)
 		if err != nil {
@@ -282,8 +461,12 @@ func (r *stateReplayer) execute(op TraceOp) error {
 		if err != nil {
 			return err
 		}
-		return r.backend.BeginTransaction(txID)
+		if err := r.backend.BeginTransaction(txID); err != nil {
+			return err
+		}
+		r.currentTx = int(txID)
 	case "EndTransaction":
+		r.currentTx = noTransaction
 		return r.backend.EndTransaction()
 	case "Finalise":
 		flag, err := parseBool(op.Args, 0)
@@ -17,15 +17,21 @@
 package delta
 
 import (
+	"bufio"
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/state/proxy"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestStateReplayer_CreateAccount(t *testing.T) {
@@ -336,6 +342,51 @@ func TestStateReplayer_EndBlock(t *testing.T) {
 	require.NoError(t, replayer.Execute(context.Background(), ops))
 }
 
+func TestStateReplayer_ExecutePrefixClosesOpenScopes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	replayer := newStateReplayer(db)
+
+	gomock.InOrder(
+		db.EXPECT().BeginSyncPeriod(uint64(1)),
+		db.EXPECT().BeginBlock(uint64(2)).Return(nil),
+		db.EXPECT().BeginTransaction(uint32(0)).Return(nil),
+		db.EXPECT().EndTransaction().Return(nil),
+		db.EXPECT().BeginTransaction(uint32(1)).Return(nil),
+		db.EXPECT().Snapshot().Return(0),
+		// the open snapshot is not reverted, the transaction is ended right away
+		db.EXPECT().EndTransaction().Return(nil),
+		db.EXPECT().EndBlock().Return(nil),
+		db.EXPECT().EndSyncPeriod(),
+	)
+
+	ops := []TraceOp{
+		{Kind: "BeginSyncPeriod", Args: []string{"1"}},
+		{Kind: "BeginBlock", Args: []string{"2"}},
+		{Kind: "BeginTransaction", Args: []string{"0"}},
+		{Kind: "EndTransaction"},
+		{Kind: "BeginTransaction", Args: []string{"1"}},
+		{Kind: "Snapshot"},
+	}
+
+	require.NoError(t, replayer.ExecutePrefix(context.Background(), ops))
+	require.Empty(t, replayer.openScopes)
+}
+
+func TestStateReplayer_ExecutePrefixReportsCloseFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	replayer := newStateReplayer(db)
+
+	db.EXPECT().BeginBlock(uint64(2)).Return(nil)
+	db.EXPECT().EndBlock().Return(fmt.Errorf("injected"))
+
+	ops := []TraceOp{{Kind: "BeginBlock", Args: []string{"2"}}}
+
+	err := replayer.ExecutePrefix(context.Background(), ops)
+	require.ErrorContains(t, err, "close open block 2: injected")
+}
+
 func TestStateReplayer_IntermediateRoot(t *testing.T) {
 	db := newTrackingStateDB(t)
 	replayer := newStateReplayer(db)
@@ -469,6 +520,129 @@ func TestStateReplayer_SnapshotRevert(t *testing.T) {
 	require.NoError(t, replayer.Execute(context.Background(), ops))
 }
 
+func TestStateReplayer_StrictReplayOfRecordedTraceMatches(t *testing.T) {
+	ops := recordTraceWithResults(t)
+	require.Equal(t, 5, countResults(ops))
+
+	replayer := newStateReplayer(newTrackingStateDB(t)).withStrictMode(0)
+	require.NoError(t, replayer.Execute(context.Background(), ops))
+
+	count, first := replayer.Mismatches()
+	require.Zero(t, count)
+	require.NoError(t, first)
+}
+
+func TestStateReplayer_StrictReplayReportsInjectedDivergence(t *testing.T) {
+	ops := recordTraceWithResults(t)
+	position := -1
+	for i, op := range ops {
+		if op.Kind == "Result" && op.SubKind == "GetBalance" {
+			op.Args = append(append([]string{}, op.Args[:len(op.Args)-1]...), "43")
+			ops[i] = op
+			position = i - 1
+		}
+	}
+	require.GreaterOrEqual(t, position, 0, "trace does not record a balance")
+	want := fmt.Sprintf("operation %d (GetBalance) in block 1, tx 0 returned 42, recorded 43", position)
+
+	replayer := newStateReplayer(newTrackingStateDB(t)).withStrictMode(0)
+	err := replayer.Execute(context.Background(), ops)
+	require.ErrorContains(t, err, "strict replay: 1 read results mismatched")
+	require.ErrorContains(t, err, want)
+
+	// the divergence is tolerated if the number of mismatches does not exceed the maximum
+	replayer = newStateReplayer(newTrackingStateDB(t)).withStrictMode(1)
+	require.NoError(t, replayer.Execute(context.Background(), ops))
+	count, first := replayer.Mismatches()
+	require.Equal(t, 1, count)
+	require.EqualError(t, first, want)
+
+	// results are not verified outside of the strict mode
+	replayer = newStateReplayer(newTrackingStateDB(t))
+	require.NoError(t, replayer.Execute(context.Background(), ops))
+	count, _ = replayer.Mismatches()
+	require.Zero(t, count)
+}
+
+func TestStateReplayer_StrictReplayIgnoresResultsNotFollowingTheirRead(t *testing.T) {
+	addr := common.HexToAddress("0x1")
+	replayer := newStateReplayer(newTrackingStateDB(t)).withStrictMode(0)
+
+	ops := []TraceOp{
+		{Kind: "BeginBlock", Args: []string{"1"}},
+		{Kind: "Result", SubKind: "GetNonce", Args: []string{"GetNonce", addr.Hex(), "5"}},
+		{Kind: "GetBalance", Args: []string{addr.Hex()}},
+		{Kind: "Result", SubKind: "GetNonce", Args: []string{"GetNonce", addr.Hex(), "5"}},
+		{Kind: "GetNonce", Args: []string{addr.Hex()}},
+		{Kind: "Exist", Args: []string{addr.Hex()}},
+		{Kind: "Result", SubKind: "GetNonce", Args: []string{"GetNonce", addr.Hex(), "5"}},
+		{Kind: "EndBlock"},
+	}
+
+	require.NoError(t, replayer.Execute(context.Background(), ops))
+	count, _ := replayer.Mismatches()
+	require.Zero(t, count)
+}
+
+func TestStateReplayer_StrictReplayRejectsInvalidResult(t *testing.T) {
+	addr := common.HexToAddress("0x1")
+	replayer := newStateReplayer(newTrackingStateDB(t)).withStrictMode(0)
+
+	ops := []TraceOp{
+		{Kind: "BeginBlock", Args: []string{"1"}},
+		{Kind: "GetNonce", Args: []string{addr.Hex()}},
+		{Kind: "Result", SubKind: "GetNonce", Args: []string{"GetNonce", addr.Hex(), "seven"}},
+	}
+
+	require.ErrorContains(t, replayer.Execute(context.Background(), ops), "invalid GetNonce result")
+}
+
+// recordTraceWithResults runs a transaction through the delta logger recording read
+// results and loads the resulting trace.
+func recordTraceWithResults(t *testing.T) []TraceOp {
+	t.Helper()
+	addr := common.HexToAddress("0x1")
+	key := common.HexToHash("0x2")
+	value := common.HexToHash("0x3")
+
+	path := filepath.Join(t.TempDir(), "trace.log")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	sink := proxy.NewDeltaLogSink(nil, bufio.NewWriter(file), file)
+	sink.RecordResults()
+	db := proxy.NewDeltaLoggerProxy(newTrackingStateDB(t), sink)
+
+	require.NoError(t, db.BeginBlock(1))
+	require.NoError(t, db.BeginTransaction(0))
+	db.CreateAccount(addr)
+	db.AddBalance(addr, uint256.NewInt(42), tracing.BalanceChangeTransfer)
+	db.SetNonce(addr, 7, tracing.NonceChangeUnspecified)
+	db.SetState(addr, key, value)
+	db.SetCode(addr, []byte{0x60, 0x00}, tracing.CodeChangeUnspecified)
+	require.True(t, db.Exist(addr))
+	require.Equal(t, uint64(42), db.GetBalance(addr).Uint64())
+	require.Equal(t, uint64(7), db.GetNonce(addr))
+	require.Equal(t, value, db.GetState(addr, key))
+	require.NotEqual(t, common.Hash{}, db.GetCodeHash(addr))
+	require.NoError(t, db.EndTransaction())
+	require.NoError(t, db.EndBlock())
+	require.NoError(t, sink.Close())
+
+	ops, err := LoadOperations([]string{path}, 0, 0)
+	require.NoError(t, err)
+	return ops
+}
+
+func countResults(ops []TraceOp) int {
+	count := 0
+	for _, op := range ops {
+		if op.Kind == "Result" {
+			count++
+		}
+	}
+	return count
+}
+
 func TestParseInt(t *testing.T) {
 	db := newTrackingStateDB(t)
 	replayer := newStateReplayer(db)
@@ -0,0 +1,59 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package delta
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Strategy names a content reduction phase of the minimizer.
+type Strategy string
+
+const (
+	// StrategyAddresses removes all operations of sampled contract addresses.
+	StrategyAddresses Strategy = "addresses"
+	// StrategyTransactions runs ddmin over the transactions of the trace.
+	StrategyTransactions Strategy = "transactions"
+)
+
+// DefaultStrategies is the phase order used when no strategies are configured.
+var DefaultStrategies = []Strategy{StrategyAddresses, StrategyTransactions}
+
+// ParseStrategies parses a comma-separated list of strategies, e.g.
+// "addresses,transactions". The order of the list is the order in which the
+// phases are applied in each minimization round.
+func ParseStrategies(s string) ([]Strategy, error) {
+	strategies := make([]Strategy, 0, len(DefaultStrategies))
+	seen := make(map[Strategy]struct{})
+	for _, part := range strings.Split(s, ",") {
+		strategy := Strategy(strings.ToLower(strings.TrimSpace(part)))
+		switch strategy {
+		case StrategyAddresses, StrategyTransactions:
+		case "":
+			return nil, fmt.Errorf("delta: empty strategy in %q", s)
+		default:
+			return nil, fmt.Errorf("delta: unknown strategy %q; supported: %s, %s", strategy, StrategyAddresses, StrategyTransactions)
+		}
+		if _, ok := seen[strategy]; ok {
+			return nil, fmt.Errorf("delta: strategy %q listed more than once", strategy)
+		}
+		seen[strategy] = struct{}{}
+		strategies = append(strategies, strategy)
+	}
+	return strategies, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package delta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStrategies(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  []Strategy
+		err   string
+	}{
+		"default order":  {input: "addresses,transactions", want: []Strategy{StrategyAddresses, StrategyTransactions}},
+		"reversed order": {input: "transactions,addresses", want: []Strategy{StrategyTransactions, StrategyAddresses}},
+		"single":         {input: "transactions", want: []Strategy{StrategyTransactions}},
+		"spaces and case": {
+			input: " Addresses , TRANSACTIONS ",
+			want:  []Strategy{StrategyAddresses, StrategyTransactions},
+		},
+		"empty":      {input: "", err: "empty strategy"},
+		"empty item": {input: "addresses,", err: "empty strategy"},
+		"unknown":    {input: "addresses,blocks", err: "unknown strategy"},
+		"duplicate":  {input: "addresses,addresses", err: "more than once"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseStrategies(test.input)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestNewMinimizer_DefaultStrategies(t *testing.T) {
+	m := NewMinimizer(MinimizerConfig{})
+	require.Equal(t, DefaultStrategies, m.cfg.Strategies)
+}
@@ -34,6 +34,10 @@ type StateTesterConfig struct {
 	CarmenSchema int
 	LogLevel     string
 	ChainID      int
+	// Strict makes the replay verify read results recorded in the trace, failing
+	// once more than MaxMismatches of them differ.
+	Strict        bool
+	MaxMismatches int
 }
 
 // NewStateTester prepares a testFunc that replays operations against a StateDB backend.
@@ -80,6 +84,9 @@ func NewStateTester(cfg StateTesterConfig) (testFunc, error) {
 		}
 
 		replayer := newStateReplayer(db)
+		if cfg.Strict {
+			replayer.withStrictMode(cfg.MaxMismatches)
+		}
 		var (
 			panicValue any
 			replayErr  error
@@ -98,7 +105,7 @@ func NewStateTester(cfg StateTesterConfig) (testFunc, error) {
 					panicValue = r
 				}
 			}()
-			replayErr = replayer.Execute(ctx, ops)
+			replayErr = replayer.ExecutePrefix(ctx, ops)
 		}()
 
 		logFailure := func(err error) (outcome, error) {
@@ -130,6 +137,10 @@ func NewStateTester(cfg StateTesterConfig) (testFunc, error) {
 			return logFailure(cleanupErr)
 		}
 
+		if count, first := replayer.Mismatches(); count > 0 {
+			fmt.Fprintf(os.Stderr, "aida-delta-debugger: tolerated %d mismatching read results, first: %v\n", count, first)
+		}
+
 		return outcomePass, nil
 	}, nil
 }
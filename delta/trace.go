@@ -25,6 +25,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/0xsoniclabs/aida/utils/tracefile"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -88,8 +89,9 @@ func estimateTotalOperations(files []string) int {
 
 // readTraceFileAppend reads a trace file and appends operations directly to the provided slice.
 // This avoids intermediate allocations and copies, making it memory-efficient for large files.
+// Compressed trace files are decompressed on the fly.
 func readTraceFileAppend(path string, ops *[]TraceOp) error {
-	f, err := os.Open(path)
+	f, err := tracefile.Open(path)
 	if err != nil {
 		return fmt.Errorf("delta: open trace %s: %w", path, err)
 	}
@@ -161,7 +163,7 @@ func parseTraceLine(line string) (TraceOp, error) {
 	}
 
 	switch kind {
-	case "Bulk":
+	case "Bulk", "Result":
 		if len(args) > 0 {
 			op.SubKind = args[0]
 		}
@@ -189,7 +191,7 @@ func extractAddress(kind string, args []string) (common.Address, bool) {
 	candidate := ""
 
 	switch kind {
-	case "Bulk":
+	case "Bulk", "Result":
 		if len(args) > 1 {
 			candidate = args[1]
 		}
@@ -222,7 +224,7 @@ func splitAndTrim(input, sep string) []string {
 	return raw
 }
 
-// WriteTrace writes operations to the specified destination file.
+// WriteTrace writes operations to the specified destination file, which is compressed if its path ends in .zst.
 func WriteTrace(path string, ops []TraceOp) error {
 	if len(ops) == 0 {
 		return fmt.Errorf("delta: cannot write empty trace")
@@ -235,7 +237,7 @@ func WriteTrace(path string, ops []TraceOp) error {
 		}
 	}
 
-	file, err := os.Create(path)
+	file, err := tracefile.Create(path)
 	if err != nil {
 		return fmt.Errorf("delta: create trace: %w", err)
 	}
@@ -255,6 +257,10 @@ func WriteTrace(path string, ops []TraceOp) error {
 	if err := writer.Flush(); err != nil {
 		return fmt.Errorf("delta: flush trace: %w", err)
 	}
+	// closing completes the compressed stream, hence its error must not be ignored
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("delta: close trace: %w", err)
+	}
 
 	return nil
 }
@@ -77,6 +77,37 @@ func TestWriteTrace(t *testing.T) {
 	require.Contains(t, string(content), "EndBlock")
 }
 
+func TestLoadOperations_CompressedFileMatchesUncompressedOne(t *testing.T) {
+	dir := t.TempDir()
+	ops := []TraceOp{
+		{Raw: "BeginBlock, 1000", Kind: "BeginBlock"},
+		{Raw: "CreateAccount, 0x1234567890123456789012345678901234567890", Kind: "CreateAccount"},
+		{Raw: "EndBlock", Kind: "EndBlock"},
+	}
+	plain := filepath.Join(dir, "trace.txt")
+	compressed := filepath.Join(dir, "trace.txt.zst")
+	require.NoError(t, WriteTrace(plain, ops))
+	require.NoError(t, WriteTrace(compressed, ops))
+
+	want, err := LoadOperations([]string{plain}, 0, 0)
+	require.NoError(t, err)
+	got, err := LoadOperations([]string{compressed}, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestLoadOperations_TruncatedCompressedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.txt.zst")
+	require.NoError(t, WriteTrace(path, []TraceOp{{Raw: "BeginBlock, 1000"}, {Raw: "EndBlock"}}))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, raw[:len(raw)-2], 0644))
+
+	_, err = LoadOperations([]string{path}, 0, 0)
+	require.ErrorContains(t, err, "is truncated")
+}
+
 func TestWriteTrace_EmptyOps(t *testing.T) {
 	dir := t.TempDir()
 	outputPath := filepath.Join(dir, "empty.txt")
@@ -126,6 +157,17 @@ func TestParseTraceLine_WithAddress(t *testing.T) {
 	require.Equal(t, "0x1234567890123456789012345678901234567890", op.Contract.Hex())
 }
 
+func TestParseTraceLine_Result(t *testing.T) {
+	line := "Result, GetState, 0x1234567890123456789012345678901234567890, 0x02, 0x03"
+	op, err := parseTraceLine(line)
+	require.NoError(t, err)
+	require.Equal(t, "Result", op.Kind)
+	require.Equal(t, "GetState", op.SubKind)
+	require.True(t, op.HasContract)
+	require.Equal(t, "0x1234567890123456789012345678901234567890", op.Contract.Hex())
+	require.Equal(t, []string{"GetState", "0x1234567890123456789012345678901234567890", "0x02", "0x03"}, op.Args)
+}
+
 func TestParseTraceLine_InvalidBlockNumber(t *testing.T) {
 	line := "BeginBlock, invalid"
 	_, err := parseTraceLine(line)
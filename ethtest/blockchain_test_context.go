@@ -0,0 +1,214 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package ethtest
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// BlockchainTestBlock is a single block of a blockchain test.
+type BlockchainTestBlock struct {
+	Block           *types.Block // nil if the block rlp cannot be decoded
+	DecodeErr       error        // error returned when decoding the block rlp
+	ExpectException string       // non-empty if the block is expected to be rejected
+}
+
+func newBlockchainTestContext(btJson *btJSON, genesis *types.Block, chainCfg *params.ChainConfig, fork string) *BlockchainTestContext {
+	blocks := make([]BlockchainTestBlock, len(btJson.Blocks))
+	for i, b := range btJson.Blocks {
+		block, err := b.decode()
+		blocks[i] = BlockchainTestBlock{
+			Block:           block,
+			DecodeErr:       err,
+			ExpectException: b.ExpectException,
+		}
+	}
+	return &BlockchainTestContext{
+		path:          btJson.path,
+		testLabel:     btJson.testLabel,
+		fork:          fork,
+		env:           NewBlockchainTestEnvironment(genesis.Header(), chainCfg, fork, nil),
+		genesis:       genesis.Header(),
+		blocks:        blocks,
+		inputState:    btJson.Pre,
+		postState:     btJson.Post,
+		lastBlockHash: common.Hash(btJson.BestBlock),
+	}
+}
+
+// BlockchainTestContext holds a whole blockchain test. Unlike state tests, the test
+// is not split into transactions since its blocks have to be executed in sequence.
+type BlockchainTestContext struct {
+	txcontext.NilTxContext
+	path          string // path to file from which is the test
+	testLabel     string // the test label within one JSON file (key to the JSON)
+	fork          string // which fork is the test running
+	env           txcontext.BlockEnvironment
+	genesis       *types.Header
+	blocks        []BlockchainTestBlock
+	inputState    types.GenesisAlloc
+	postState     types.GenesisAlloc
+	lastBlockHash common.Hash // expected head of the chain
+}
+
+// GetGenesis returns header of the genesis block.
+func (s *BlockchainTestContext) GetGenesis() *types.Header {
+	return s.genesis
+}
+
+// GetBlocks returns all blocks of the test in order of execution.
+func (s *BlockchainTestContext) GetBlocks() []BlockchainTestBlock {
+	return s.blocks
+}
+
+// GetLastBlockHash returns hash of the expected head of the chain.
+func (s *BlockchainTestContext) GetLastBlockHash() common.Hash {
+	return s.lastBlockHash
+}
+
+// GetStateHash returns state root of the expected head of the chain.
+func (s *BlockchainTestContext) GetStateHash() common.Hash {
+	if s.lastBlockHash == s.genesis.Hash() {
+		return s.genesis.Root
+	}
+	for _, b := range s.blocks {
+		if b.Block != nil && b.Block.Hash() == s.lastBlockHash {
+			return b.Block.Root()
+		}
+	}
+	return common.Hash{}
+}
+
+// GetLogsHash returns an empty hash as blockchain tests do not provide an expected logs hash.
+func (s *BlockchainTestContext) GetLogsHash() common.Hash {
+	return common.Hash{}
+}
+
+func (s *BlockchainTestContext) GetInputState() txcontext.WorldState {
+	return NewWorldState(s.inputState)
+}
+
+func (s *BlockchainTestContext) GetOutputState() txcontext.WorldState {
+	if s.postState == nil {
+		return nil
+	}
+	return NewWorldState(s.postState)
+}
+
+// GetFork returns the fork the test is run on.
+func (s *BlockchainTestContext) GetFork() string {
+	return s.fork
+}
+
+// GetSuite returns the suite the test belongs to.
+func (s *BlockchainTestContext) GetSuite() string {
+	return testSuite(s.path)
+}
+
+func (s *BlockchainTestContext) GetBlockEnvironment() txcontext.BlockEnvironment {
+	return s.env
+}
+
+func (s *BlockchainTestContext) GetResult() txcontext.Result {
+	// rejection of invalid blocks is checked by the processor,
+	// hence the test as a whole is never expected to fail
+	return stateTestResult{}
+}
+
+func (s *BlockchainTestContext) String() string {
+	return fmt.Sprintf(
+		"Test path: %v\n"+
+			"Test label: %v\n"+
+			"Blocks: %v\n"+
+			"Fork: %v\n", s.path, s.testLabel, len(s.blocks), s.fork)
+}
+
+// NewBlockchainTestEnvironment creates a block environment of given block header. Hashes
+// of ancestors are resolved using given map.
+func NewBlockchainTestEnvironment(header *types.Header, chainCfg *params.ChainConfig, fork string, ancestors map[uint64]common.Hash) txcontext.BlockEnvironment {
+	return &btBlockEnvironment{
+		header:    header,
+		chainCfg:  chainCfg,
+		fork:      fork,
+		ancestors: ancestors,
+	}
+}
+
+type btBlockEnvironment struct {
+	header    *types.Header
+	chainCfg  *params.ChainConfig
+	fork      string
+	ancestors map[uint64]common.Hash
+}
+
+func (e *btBlockEnvironment) GetCoinbase() common.Address {
+	return e.header.Coinbase
+}
+
+func (e *btBlockEnvironment) GetDifficulty() *big.Int {
+	return e.header.Difficulty
+}
+
+func (e *btBlockEnvironment) GetGasLimit() uint64 {
+	return e.header.GasLimit
+}
+
+func (e *btBlockEnvironment) GetNumber() uint64 {
+	return e.header.Number.Uint64()
+}
+
+func (e *btBlockEnvironment) GetTimestamp() uint64 {
+	return e.header.Time
+}
+
+func (e *btBlockEnvironment) GetBlockHash(blockNum uint64) (common.Hash, error) {
+	hash, ok := e.ancestors[blockNum]
+	if !ok {
+		return common.Hash{}, fmt.Errorf("hash of block %v is not known", blockNum)
+	}
+	return hash, nil
+}
+
+func (e *btBlockEnvironment) GetBaseFee() *big.Int {
+	return e.header.BaseFee
+}
+
+func (e *btBlockEnvironment) GetBlobBaseFee() *big.Int {
+	if e.header.ExcessBlobGas == nil {
+		return nil
+	}
+	return eip4844.CalcBlobFee(e.chainCfg, e.header)
+}
+
+func (e *btBlockEnvironment) GetRandom() *common.Hash {
+	if e.header.Difficulty != nil && e.header.Difficulty.Sign() != 0 {
+		return nil
+	}
+	random := e.header.MixDigest
+	return &random
+}
+
+func (e *btBlockEnvironment) GetFork() string {
+	return e.fork
+}
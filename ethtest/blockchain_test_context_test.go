@@ -0,0 +1,91 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package ethtest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockchainTestContext_GetStateHashReturnsRootOfExpectedHead(t *testing.T) {
+	genesis := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(0), Root: common.Hash{1}}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0), Root: common.Hash{2}})
+	blocks := []BlockchainTestBlock{{Block: block}, {ExpectException: "invalid"}}
+
+	assert.Equal(t, common.Hash{1}, CreateTestBlockchainTest(t, genesis, blocks, genesis.Hash()).GetStateHash())
+	assert.Equal(t, common.Hash{2}, CreateTestBlockchainTest(t, genesis, blocks, block.Hash()).GetStateHash())
+	assert.Equal(t, common.Hash{}, CreateTestBlockchainTest(t, genesis, blocks, common.Hash{3}).GetStateHash())
+}
+
+func TestBlockchainTestContext_GetOutputStateIsNilWithoutPostState(t *testing.T) {
+	test := &BlockchainTestContext{}
+	assert.Nil(t, test.GetOutputState())
+
+	test.postState = types.GenesisAlloc{common.Address{1}: {Balance: big.NewInt(1)}}
+	require.NotNil(t, test.GetOutputState())
+	assert.True(t, test.GetOutputState().Has(common.Address{1}))
+}
+
+func TestBlockchainTestContext_ImplementsTxContext(t *testing.T) {
+	var test txcontext.TxContext = &BlockchainTestContext{}
+	assert.Equal(t, common.Hash{}, test.GetLogsHash())
+}
+
+func TestBlockchainTestContext_GetForkAndSuite(t *testing.T) {
+	test := &BlockchainTestContext{path: "BlockchainTests/ValidBlocks/bcExample/basic.json", fork: "Prague"}
+	assert.Equal(t, "Prague", test.GetFork())
+	assert.Equal(t, "bcExample", test.GetSuite())
+}
+
+func TestBlockchainTestEnvironment_ReadsHeader(t *testing.T) {
+	chainCfg, _, err := tests.GetChainConfig("Cancun")
+	require.NoError(t, err)
+	excessBlobGas := uint64(0)
+	header := &types.Header{
+		Coinbase:      common.Address{1},
+		Number:        big.NewInt(2),
+		Difficulty:    big.NewInt(0),
+		GasLimit:      100,
+		Time:          10,
+		BaseFee:       big.NewInt(7),
+		MixDigest:     common.Hash{5},
+		ExcessBlobGas: &excessBlobGas,
+	}
+	env := NewBlockchainTestEnvironment(header, chainCfg, "Cancun", map[uint64]common.Hash{1: {9}})
+
+	assert.Equal(t, common.Address{1}, env.GetCoinbase())
+	assert.Equal(t, uint64(2), env.GetNumber())
+	assert.Equal(t, uint64(100), env.GetGasLimit())
+	assert.Equal(t, uint64(10), env.GetTimestamp())
+	assert.Equal(t, big.NewInt(7), env.GetBaseFee())
+	assert.Equal(t, big.NewInt(1), env.GetBlobBaseFee())
+	assert.Equal(t, &common.Hash{5}, env.GetRandom())
+	assert.Equal(t, "Cancun", env.GetFork())
+
+	hash, err := env.GetBlockHash(1)
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{9}, hash)
+	_, err = env.GetBlockHash(0)
+	assert.Error(t, err)
+}
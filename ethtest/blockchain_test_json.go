@@ -0,0 +1,76 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package ethtest
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// btJSON serves as a 'middleman' into which are blockchain tests unmarshalled from geth test files.
+type btJSON struct {
+	path       string
+	testLabel  string
+	Blocks     []btBlock             `json:"blocks"`
+	GenesisRlp hexutil.Bytes         `json:"genesisRLP"`
+	Pre        types.GenesisAlloc    `json:"pre"`
+	Post       types.GenesisAlloc    `json:"postState"`
+	BestBlock  common.UnprefixedHash `json:"lastblockhash"`
+	Network    string                `json:"network"`
+	SealEngine string                `json:"sealEngine"`
+}
+
+func (s *btJSON) setPath(path string) {
+	s.path = path
+}
+
+func (s *btJSON) setTestLabel(testLabel string) {
+	s.testLabel = testLabel
+}
+
+// decodeGenesis decodes the genesis block of the test.
+func (s *btJSON) decodeGenesis() (*types.Block, error) {
+	if len(s.GenesisRlp) == 0 {
+		return nil, fmt.Errorf("missing genesis rlp")
+	}
+	var genesis types.Block
+	if err := rlp.DecodeBytes(s.GenesisRlp, &genesis); err != nil {
+		return nil, fmt.Errorf("cannot decode genesis rlp; %w", err)
+	}
+	return &genesis, nil
+}
+
+// btBlock indicates data for each block of a blockchain test.
+type btBlock struct {
+	// Rlp holds the encoded block, it may be malformed for blocks expected to be invalid.
+	Rlp string `json:"rlp"`
+	// ExpectException is set if the block is expected to be rejected.
+	ExpectException string `json:"expectException"`
+}
+
+// decode decodes the block rlp.
+func (b *btBlock) decode() (*types.Block, error) {
+	var block types.Block
+	if err := rlp.DecodeBytes(common.FromHex(b.Rlp), &block); err != nil {
+		return nil, fmt.Errorf("cannot decode block rlp; %w", err)
+	}
+	return &block, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package ethtest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBtJSON_DecodeGenesis(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(0), Root: common.Hash{1}}
+	encoded, err := rlp.EncodeToBytes(types.NewBlockWithHeader(header))
+	require.NoError(t, err)
+
+	genesis, err := (&btJSON{GenesisRlp: encoded}).decodeGenesis()
+	require.NoError(t, err)
+	assert.Equal(t, header.Hash(), genesis.Hash())
+
+	_, err = (&btJSON{}).decodeGenesis()
+	assert.ErrorContains(t, err, "missing genesis rlp")
+
+	_, err = (&btJSON{GenesisRlp: hexutil.Bytes{1, 2, 3}}).decodeGenesis()
+	assert.ErrorContains(t, err, "cannot decode genesis rlp")
+}
+
+func TestBtBlock_Decode(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0)}
+	encoded, err := rlp.EncodeToBytes(types.NewBlockWithHeader(header))
+	require.NoError(t, err)
+
+	block, err := (&btBlock{Rlp: hexutil.Encode(encoded)}).decode()
+	require.NoError(t, err)
+	assert.Equal(t, header.Hash(), block.Hash())
+
+	_, err = (&btBlock{Rlp: "0xdeadbeef"}).decode()
+	assert.ErrorContains(t, err, "cannot decode block rlp")
+}
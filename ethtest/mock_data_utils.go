@@ -207,3 +207,17 @@ func CreateTestTransactionWithUnknownFork(*testing.T) txcontext.TxContext {
 		fork: "unknown",
 	}
 }
+
+func CreateTestBlockchainTest(t *testing.T, genesis *types.Header, blocks []BlockchainTestBlock, lastBlockHash common.Hash) *BlockchainTestContext {
+	chainCfg, _, err := tests.GetChainConfig("Cancun")
+	if err != nil {
+		t.Fatalf("cannot get chain config: %v", err)
+	}
+	return &BlockchainTestContext{
+		fork:          "Cancun",
+		env:           NewBlockchainTestEnvironment(genesis, chainCfg, "Cancun", nil),
+		genesis:       genesis,
+		blocks:        blocks,
+		lastBlockHash: lastBlockHash,
+	}
+}
@@ -28,7 +28,7 @@ import (
 )
 
 type ethTest interface {
-	*stJSON
+	*stJSON | *btJSON
 	setPath(path string)
 	setTestLabel(testLabel string)
 }
@@ -73,7 +73,19 @@ func getTestsWithinPath[T ethTest](cfg *utils.Config, testType utils.EthTestType
 			dirPaths = []string{path}
 		}
 	case utils.BlockTests:
-		return nil, errors.New("blockchain test-type not yet implemented")
+		// If all dir with all tests is passed, only BlockchainTests are extracted
+		for _, dir := range []string{"BlockchainTests", "blockchain_tests"} {
+			bt := filepath.Join(path, dir)
+			_, err = os.Stat(bt)
+			if err == nil {
+				dirPaths = append(dirPaths, bt)
+			}
+		}
+
+		// Otherwise exact directory with tests is passed
+		if len(dirPaths) == 0 {
+			dirPaths = []string{path}
+		}
 	default:
 		return nil, errors.New("please chose which testType do you want to read")
 	}
@@ -90,14 +90,19 @@ func TestEthTest_getTestWithinPath(t *testing.T) {
 	})
 
 	t.Run("with block test", func(t *testing.T) {
-		// create dir name testdata
-		createConfigFile(t, tmp+"/GeneralStateTests/testdata.json")
+		// only tests within BlockchainTests are read if the dir exists
+		err := os.Mkdir(tmp+"/BlockchainTests", 0755)
+		if err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		createConfigFile(t, tmp+"/BlockchainTests/testdata.json")
+		createConfigFile(t, tmp+"/BlockchainTests/testdata2.json")
 		cfg := &utils.Config{
 			ArgPath: tmp,
 		}
-		tests, err := getTestsWithinPath[*stJSON](cfg, utils.BlockTests)
-		assert.Error(t, err)
-		assert.Nil(t, tests)
+		tests, err := getTestsWithinPath[*btJSON](cfg, utils.BlockTests)
+		assert.NoError(t, err)
+		assert.Len(t, tests, 2)
 	})
 
 	t.Run("with other test", func(t *testing.T) {
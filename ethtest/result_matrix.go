@@ -0,0 +1,143 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package ethtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// TestCase is implemented by the contexts of all ethereum tests and
+// identifies the fork and the suite each test belongs to.
+type TestCase interface {
+	GetFork() string
+	GetSuite() string
+}
+
+// ResultRow holds the numbers of passed, failed and skipped tests of one suite run on one fork.
+type ResultRow struct {
+	Fork    string `json:"fork"`
+	Suite   string `json:"suite"`
+	Passed  int    `json:"passed"`
+	Failed  int    `json:"failed"`
+	Skipped int    `json:"skipped"`
+}
+
+// testSuite returns the suite of the test stored at given path, that is
+// the name of the directory containing the test file.
+func testSuite(path string) string {
+	return filepath.Base(filepath.Dir(path))
+}
+
+type resultKey struct {
+	fork, suite string
+}
+
+// ResultMatrix counts the results of ethereum tests per fork and suite.
+// It is safe for concurrent use.
+type ResultMatrix struct {
+	mu   sync.Mutex
+	rows map[resultKey]*ResultRow
+}
+
+func NewResultMatrix() *ResultMatrix {
+	return &ResultMatrix{rows: make(map[resultKey]*ResultRow)}
+}
+
+// AddPassed records a passed test.
+func (m *ResultMatrix) AddPassed(fork, suite string) {
+	m.update(fork, suite, func(r *ResultRow) { r.Passed++ })
+}
+
+// AddFailed records a failed test.
+func (m *ResultMatrix) AddFailed(fork, suite string) {
+	m.update(fork, suite, func(r *ResultRow) { r.Failed++ })
+}
+
+// AddSkipped records a test which cannot be run, e.g. because its fork is not supported.
+func (m *ResultMatrix) AddSkipped(fork, suite string) {
+	m.update(fork, suite, func(r *ResultRow) { r.Skipped++ })
+}
+
+func (m *ResultMatrix) update(fork, suite string, apply func(*ResultRow)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := resultKey{fork, suite}
+	row, ok := m.rows[key]
+	if !ok {
+		row = &ResultRow{Fork: fork, Suite: suite}
+		m.rows[key] = row
+	}
+	apply(row)
+}
+
+// Rows returns the rows of the matrix sorted by fork and suite.
+func (m *ResultMatrix) Rows() []ResultRow {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rows := make([]ResultRow, 0, len(m.rows))
+	for _, row := range m.rows {
+		rows = append(rows, *row)
+	}
+	slices.SortFunc(rows, func(a, b ResultRow) int {
+		if c := strings.Compare(a.Fork, b.Fork); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Suite, b.Suite)
+	})
+	return rows
+}
+
+// Total returns the sums of all rows.
+func (m *ResultMatrix) Total() ResultRow {
+	total := ResultRow{Fork: "Total"}
+	for _, row := range m.Rows() {
+		total.Passed += row.Passed
+		total.Failed += row.Failed
+		total.Skipped += row.Skipped
+	}
+	return total
+}
+
+// WriteTable prints the matrix followed by the totals as a table.
+func (m *ResultMatrix) WriteTable(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Fork\tSuite\tPassed\tFailed\tSkipped\t")
+	for _, row := range append(m.Rows(), m.Total()) {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t\n", row.Fork, row.Suite, row.Passed, row.Failed, row.Skipped)
+	}
+	return w.Flush()
+}
+
+// WriteJson writes the rows of the matrix to a JSON file at given path.
+func (m *ResultMatrix) WriteJson(path string) error {
+	data, err := json.MarshalIndent(m.Rows(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode results; %w", err)
+	}
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("cannot write results to %v; %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package ethtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultMatrix_CountsResultsPerForkAndSuite(t *testing.T) {
+	m := NewResultMatrix()
+	m.AddPassed("Prague", "stExample")
+	m.AddPassed("Cancun", "stExample")
+	m.AddPassed("Cancun", "stExample")
+	m.AddFailed("Cancun", "stExample")
+	m.AddPassed("Cancun", "stCreate")
+	m.AddSkipped("Frontier", "stExample")
+
+	assert.Equal(t, []ResultRow{
+		{Fork: "Cancun", Suite: "stCreate", Passed: 1},
+		{Fork: "Cancun", Suite: "stExample", Passed: 2, Failed: 1},
+		{Fork: "Frontier", Suite: "stExample", Skipped: 1},
+		{Fork: "Prague", Suite: "stExample", Passed: 1},
+	}, m.Rows())
+	assert.Equal(t, ResultRow{Fork: "Total", Passed: 4, Failed: 1, Skipped: 1}, m.Total())
+}
+
+func TestResultMatrix_IsSafeForConcurrentUse(t *testing.T) {
+	m := NewResultMatrix()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.AddPassed("Cancun", "stExample")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1000, m.Total().Passed)
+}
+
+func TestResultMatrix_WriteTable(t *testing.T) {
+	m := NewResultMatrix()
+	m.AddPassed("Cancun", "stExample")
+	m.AddSkipped("Frontier", "stExample")
+
+	var out bytes.Buffer
+	require.NoError(t, m.WriteTable(&out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, []string{"Fork", "Suite", "Passed", "Failed", "Skipped"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"Cancun", "stExample", "1", "0", "0"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"Frontier", "stExample", "0", "0", "1"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"Total", "1", "0", "1"}, strings.Fields(lines[3]))
+}
+
+func TestResultMatrix_WriteJson(t *testing.T) {
+	m := NewResultMatrix()
+	m.AddPassed("Cancun", "stExample")
+	m.AddFailed("Cancun", "stExample")
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, m.WriteJson(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var rows []ResultRow
+	require.NoError(t, json.Unmarshal(data, &rows))
+	assert.Equal(t, m.Rows(), rows)
+	assert.Contains(t, string(data), `"skipped": 0`)
+}
+
+func TestResultMatrix_WriteJson_FailsOnInvalidPath(t *testing.T) {
+	m := NewResultMatrix()
+	err := m.WriteJson(filepath.Join(t.TempDir(), "missing", "report.json"))
+	require.ErrorContains(t, err, "cannot write results")
+}
+
+func TestResultMatrix_MiniatureStateTest(t *testing.T) {
+	cfg := &utils.Config{
+		ArgPath:     filepath.Join("testdata", "stMiniature", "miniature.json"),
+		EthTestType: utils.StateTests,
+		Forks:       []string{"cancun", "prague"},
+		LogLevel:    "critical",
+	}
+	splitter, err := NewTestCaseSplitter(cfg)
+	require.NoError(t, err)
+	results := NewResultMatrix()
+	splitter.SetResults(results)
+
+	tests, err := splitter.SplitStateTests()
+	require.NoError(t, err)
+	require.Len(t, tests, 3)
+
+	// the first Cancun test fails, all others pass
+	failed := false
+	for _, test := range tests {
+		tc, ok := test.Ctx.(TestCase)
+		require.True(t, ok, "test context does not expose its fork")
+		require.Equal(t, test.Fork, tc.GetFork())
+		if tc.GetFork() == "Cancun" && !failed {
+			results.AddFailed(tc.GetFork(), tc.GetSuite())
+			failed = true
+		} else {
+			results.AddPassed(tc.GetFork(), tc.GetSuite())
+		}
+	}
+
+	// London is not selected, Frontier is not supported
+	assert.Equal(t, []ResultRow{
+		{Fork: "Cancun", Suite: "stMiniature", Passed: 1, Failed: 1},
+		{Fork: "Frontier", Suite: "stMiniature", Skipped: 2},
+		{Fork: "Prague", Suite: "stMiniature", Passed: 1},
+	}, results.Rows())
+}
@@ -95,6 +95,16 @@ func (s *StateTestContext) GetInputState() txcontext.WorldState {
 	return NewWorldState(s.inputState)
 }
 
+// GetFork returns the fork the test is run on.
+func (s *StateTestContext) GetFork() string {
+	return s.fork
+}
+
+// GetSuite returns the suite the test belongs to.
+func (s *StateTestContext) GetSuite() string {
+	return testSuite(s.path)
+}
+
 func (s *StateTestContext) GetBlockEnvironment() txcontext.BlockEnvironment {
 	return s.env
 }
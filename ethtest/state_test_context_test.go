@@ -116,6 +116,12 @@ func TestStateTestContext_GetBlockEnvironment(t *testing.T) {
 	assert.NotNil(t, stCtx.GetBlockEnvironment())
 }
 
+func TestStateTestContext_GetForkAndSuite(t *testing.T) {
+	stCtx := &StateTestContext{path: "GeneralStateTests/stExample/add11.json", fork: "Cancun"}
+	assert.Equal(t, "Cancun", stCtx.GetFork())
+	assert.Equal(t, "stExample", stCtx.GetSuite())
+}
+
 func TestStateTestContext_GetMessage(t *testing.T) {
 	stCtx := newTestStateTestContext()
 	assert.NotNil(t, stCtx.GetMessage())
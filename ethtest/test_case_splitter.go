@@ -19,6 +19,8 @@ package ethtest
 import (
 	"fmt"
 	"math/big"
+	"slices"
+	"strings"
 
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/txcontext"
@@ -50,41 +52,84 @@ var usableForks = map[string]struct{}{
 	"TestNetwork":  {},
 }
 
+// mergedForks are forks for which blockchain tests can be run. Pre-merge
+// forks would require block and ommer rewards, which are not supported.
+var mergedForks = map[string]struct{}{
+	"Osaka":    {},
+	"Prague":   {},
+	"Cancun":   {},
+	"Shanghai": {},
+	"Paris":    {},
+}
+
 // NewTestCaseSplitter opens all JSON tests within path
 func NewTestCaseSplitter(cfg *utils.Config) (*TestCaseSplitter, error) {
-	tests, err := getTestsWithinPath(cfg, utils.StateTests)
-	if err != nil {
-		return nil, err
-	}
 	log := logger.NewLogger(cfg.LogLevel, "eth-test-decoder")
-
-	return &TestCaseSplitter{
-		enabledForks: sortForks(log, cfg.Fork),
+	forks := cfg.Forks
+	if len(forks) == 0 {
+		forks = []string{cfg.Fork}
+	}
+	s := &TestCaseSplitter{
+		enabledForks: sortForks(log, forks),
 		log:          log,
-		jsons:        tests,
 		chainConfigs: make(map[string]*params.ChainConfig),
-	}, nil
-}
+	}
 
-func sortForks(log logger.Logger, cfgFork string) (forks []string) {
-	cfgFork = utils.ToTitleCase(cfgFork)
-	if cfgFork == "All" {
-		forks = maps.Keys(usableForks)
+	var err error
+	if cfg.EthTestType == utils.BlockTests {
+		s.blockchainJsons, err = getTestsWithinPath[*btJSON](cfg, utils.BlockTests)
 	} else {
+		s.jsons, err = getTestsWithinPath[*stJSON](cfg, utils.StateTests)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// sortForks returns the usable forks selected by given fork names; "all" selects every usable fork.
+func sortForks(log logger.Logger, cfgForks []string) (forks []string) {
+	for _, cfgFork := range cfgForks {
+		cfgFork = utils.ToTitleCase(strings.TrimSpace(cfgFork))
+		if cfgFork == "All" {
+			return maps.Keys(usableForks)
+		}
 		if _, ok := usableForks[cfgFork]; !ok {
 			log.Warningf("Unknown name fork name %v, removing", cfgFork)
-		} else {
+		} else if !slices.Contains(forks, cfgFork) {
 			forks = append(forks, cfgFork)
 		}
 	}
 	return forks
 }
 
+// runFork returns the name of the fork under which tests of given fork are run and reported.
+func runFork(fork string) string {
+	if fork == "Paris" {
+		return "Merge"
+	}
+	return fork
+}
+
 type TestCaseSplitter struct {
-	enabledForks []string  // Which forks are enabled by user (default is all)
-	jsons        []*stJSON // Decoded json fil
-	log          logger.Logger
-	chainConfigs map[string]*params.ChainConfig
+	enabledForks    []string  // Which forks are enabled by user (default is all)
+	jsons           []*stJSON // Decoded json fil
+	blockchainJsons []*btJSON // Decoded blockchain test json files
+	log             logger.Logger
+	chainConfigs    map[string]*params.ChainConfig
+	results         *ResultMatrix // if set, tests which cannot be run are recorded as skipped
+}
+
+// SetResults sets the matrix to which tests which cannot be run are recorded as skipped.
+func (s *TestCaseSplitter) SetResults(results *ResultMatrix) {
+	s.results = results
+}
+
+// skip records a test which cannot be run.
+func (s *TestCaseSplitter) skip(fork, path string) {
+	if s.results != nil {
+		s.results.AddSkipped(runFork(fork), testSuite(path))
+	}
 }
 
 // SplitStateTests iterates unmarshalled Geth-State test-files and divides them by 1) fork and
@@ -102,6 +147,15 @@ func (s *TestCaseSplitter) SplitStateTests() (dividedTests []Transaction, err er
 			baseFee = &BigInt{*big.NewInt(0x0a)}
 		}
 
+		// Tests of forks which are not supported are skipped
+		for fork, posts := range stJson.Post {
+			if _, ok := usableForks[fork]; !ok {
+				for range posts {
+					s.skip(fork, stJson.path)
+				}
+			}
+		}
+
 		// Iterate all usable forks within one JSON file
 		for _, fork := range s.enabledForks {
 			posts, ok := stJson.Post[fork]
@@ -118,15 +172,13 @@ func (s *TestCaseSplitter) SplitStateTests() (dividedTests []Transaction, err er
 				if err != nil {
 					s.log.Warningf("Path: %v, fork: %v, test postNumber: %v\n"+
 						"cannot decode tx to message: %v", stJson.path, fork, postNumber, err)
+					s.skip(fork, stJson.path)
 					continue
 				}
 
-				if fork == "Paris" {
-					fork = "Merge"
-				}
-				txCtx := newStateTestTxContext(stJson, msg, post, chainCfg, stJson.testLabel, fork, postNumber)
+				txCtx := newStateTestTxContext(stJson, msg, post, chainCfg, stJson.testLabel, runFork(fork), postNumber)
 				dividedTests = append(dividedTests, Transaction{
-					fork,
+					runFork(fork),
 					txCtx,
 				})
 				overall++
@@ -139,6 +191,73 @@ func (s *TestCaseSplitter) SplitStateTests() (dividedTests []Transaction, err er
 	return dividedTests, err
 }
 
+// SplitBlockchainTests iterates unmarshalled Geth-Blockchain test-files. Each test runs on a single
+// network and is executed as a whole, hence each test becomes exactly one Transaction. Tests which
+// cannot be run are skipped and the number of skipped tests is reported for each reason.
+func (s *TestCaseSplitter) SplitBlockchainTests() (dividedTests []Transaction, err error) {
+	skipped := make(map[string]int)
+
+	for _, btJson := range s.blockchainJsons {
+		fork, reason := s.getBlockchainTestFork(btJson)
+		if reason != "" {
+			skipped[reason]++
+			if reason != forkNotEnabled {
+				s.skip(btJson.Network, btJson.path)
+			}
+			continue
+		}
+
+		genesis, err := btJson.decodeGenesis()
+		if err != nil {
+			s.log.Warningf("Path: %v, test: %v\n%v", btJson.path, btJson.testLabel, err)
+			skipped["undecodable genesis"]++
+			s.skip(fork, btJson.path)
+			continue
+		}
+
+		chainCfg, err := s.getChainConfig(fork)
+		if err != nil {
+			return nil, err
+		}
+
+		fork = runFork(fork)
+		dividedTests = append(dividedTests, Transaction{
+			fork,
+			newBlockchainTestContext(btJson, genesis, chainCfg, fork),
+		})
+	}
+
+	s.log.Noticef("Found %v runnable blockchain tests...", len(dividedTests))
+	reasons := maps.Keys(skipped)
+	slices.Sort(reasons)
+	for _, reason := range reasons {
+		s.log.Noticef("Skipped %v blockchain tests; %v", skipped[reason], reason)
+	}
+
+	return dividedTests, nil
+}
+
+// forkNotEnabled is the reason of not running tests of forks deselected by the user,
+// such tests are not reported as skipped.
+const forkNotEnabled = "fork not enabled"
+
+// getBlockchainTestFork returns fork of given blockchain test or the reason why it cannot be run.
+func (s *TestCaseSplitter) getBlockchainTestFork(btJson *btJSON) (fork string, reason string) {
+	if btJson.SealEngine != "" && btJson.SealEngine != "NoProof" {
+		return "", fmt.Sprintf("unsupported seal engine %v", btJson.SealEngine)
+	}
+	if _, ok := usableForks[btJson.Network]; !ok {
+		return "", "unsupported network (e.g. fork transition)"
+	}
+	if !slices.Contains(s.enabledForks, btJson.Network) {
+		return "", forkNotEnabled
+	}
+	if _, ok := mergedForks[btJson.Network]; !ok {
+		return "", "pre-merge network"
+	}
+	return btJson.Network, ""
+}
+
 func (s *TestCaseSplitter) getChainConfig(fork string) (*params.ChainConfig, error) {
 	if cfg, ok := s.chainConfigs[fork]; ok {
 		return cfg, nil
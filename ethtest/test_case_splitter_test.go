@@ -17,14 +17,18 @@
 package ethtest
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTransaction_Fields(t *testing.T) {
@@ -35,7 +39,7 @@ func TestTransaction_Fields(t *testing.T) {
 
 func TestSortForks_All(t *testing.T) {
 	log := logger.NewLogger("info", "test-sort-forks")
-	forks := sortForks(log, "all")
+	forks := sortForks(log, []string{"all"})
 	assert.ElementsMatch(t, forks, []string{
 		"Osaka", "Prague", "Cancun", "Shanghai", "Paris", "Bellatrix", "GrayGlacier", "ArrowGlacier", "Altair", "London", "Berlin", "Istanbul", "MuirGlacier", "TestNetwork",
 	})
@@ -43,16 +47,28 @@ func TestSortForks_All(t *testing.T) {
 
 func TestSortForks_Unknown(t *testing.T) {
 	log := logger.NewLogger("info", "test-sort-forks")
-	forks := sortForks(log, "unknownFork")
+	forks := sortForks(log, []string{"unknownFork"})
 	assert.Empty(t, forks)
 }
 
 func TestSortForks_Single(t *testing.T) {
 	log := logger.NewLogger("info", "test-sort-forks")
-	forks := sortForks(log, "London")
+	forks := sortForks(log, []string{"London"})
 	assert.Equal(t, []string{"London"}, forks)
 }
 
+func TestSortForks_List(t *testing.T) {
+	log := logger.NewLogger("critical", "test-sort-forks")
+	forks := sortForks(log, []string{"cancun", " Prague", "unknownFork", "Cancun"})
+	assert.Equal(t, []string{"Cancun", "Prague"}, forks)
+}
+
+func TestSortForks_ListWithAll(t *testing.T) {
+	log := logger.NewLogger("critical", "test-sort-forks")
+	forks := sortForks(log, []string{"cancun", "all"})
+	assert.Len(t, forks, len(usableForks))
+}
+
 func TestTestCaseSplitter_getChainConfig(t *testing.T) {
 	ts := &TestCaseSplitter{chainConfigs: make(map[string]*params.ChainConfig)}
 	cfg, err := ts.getChainConfig("London")
@@ -151,3 +167,77 @@ func TestTestCaseSplitter_SplitStateTests(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, dt, len(ts.enabledForks))
 }
+
+func TestTestCaseSplitter_SplitBlockchainTests_SkipsUnsupportedTests(t *testing.T) {
+	genesisRlp, err := rlp.EncodeToBytes(types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(0)}))
+	require.NoError(t, err)
+
+	ts := &TestCaseSplitter{
+		blockchainJsons: []*btJSON{
+			{Network: "Cancun", SealEngine: "NoProof", GenesisRlp: genesisRlp},
+			{Network: "Cancun", SealEngine: "Ethash", GenesisRlp: genesisRlp},
+			{Network: "ShanghaiToCancunAtTime15k", GenesisRlp: genesisRlp},
+			{Network: "London", GenesisRlp: genesisRlp},
+			{Network: "Prague", GenesisRlp: genesisRlp},
+			{Network: "Cancun"},
+		},
+		enabledForks: []string{"Cancun", "London"},
+		chainConfigs: make(map[string]*params.ChainConfig),
+		log:          logger.NewLogger("critical", "splitter"),
+	}
+	dt, err := ts.SplitBlockchainTests()
+	require.NoError(t, err)
+	require.Len(t, dt, 1)
+	assert.Equal(t, "Cancun", dt[0].Fork)
+	assert.IsType(t, &BlockchainTestContext{}, dt[0].Ctx)
+}
+
+func TestTestCaseSplitter_SplitBlockchainTests_RecordsSkippedTests(t *testing.T) {
+	genesisRlp, err := rlp.EncodeToBytes(types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(0)}))
+	require.NoError(t, err)
+
+	results := NewResultMatrix()
+	ts := &TestCaseSplitter{
+		blockchainJsons: []*btJSON{
+			{path: "bt/suite/a.json", Network: "Cancun", GenesisRlp: genesisRlp},
+			{path: "bt/suite/b.json", Network: "ShanghaiToCancunAtTime15k", GenesisRlp: genesisRlp},
+			{path: "bt/suite/c.json", Network: "London", GenesisRlp: genesisRlp},
+			{path: "bt/suite/d.json", Network: "Prague", GenesisRlp: genesisRlp},
+		},
+		enabledForks: []string{"Cancun", "London"},
+		chainConfigs: make(map[string]*params.ChainConfig),
+		log:          logger.NewLogger("critical", "splitter"),
+	}
+	ts.SetResults(results)
+	dt, err := ts.SplitBlockchainTests()
+	require.NoError(t, err)
+	require.Len(t, dt, 1)
+
+	// tests of forks not enabled are not reported
+	assert.Equal(t, []ResultRow{
+		{Fork: "London", Suite: "suite", Skipped: 1},
+		{Fork: "ShanghaiToCancunAtTime15k", Suite: "suite", Skipped: 1},
+	}, results.Rows())
+}
+
+func TestTestCaseSplitter_getBlockchainTestFork(t *testing.T) {
+	ts := &TestCaseSplitter{enabledForks: []string{"Cancun", "London"}}
+	tests := map[string]struct {
+		json   *btJSON
+		fork   string
+		reason string
+	}{
+		"runnable":   {&btJSON{Network: "Cancun"}, "Cancun", ""},
+		"seal":       {&btJSON{Network: "Cancun", SealEngine: "Ethash"}, "", "unsupported seal engine Ethash"},
+		"transition": {&btJSON{Network: "ParisToShanghaiAtTime15k"}, "", "unsupported network (e.g. fork transition)"},
+		"disabled":   {&btJSON{Network: "Prague"}, "", "fork not enabled"},
+		"pre-merge":  {&btJSON{Network: "London"}, "", "pre-merge network"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fork, reason := ts.getBlockchainTestFork(test.json)
+			assert.Equal(t, test.fork, fork)
+			assert.Equal(t, test.reason, reason)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+// Package provider is an example of a transaction provider supplied by a project
+// embedding Aida. The provider generates value transfers and is plugged into the
+// executor by registering it in executor.TxProviders; run commands select it by
+// --provider example-transfers without any modification of Aida's source.
+package provider
+
+import (
+	"math/big"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/txcontext/txgenerator"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Name is the name under which the provider is registered.
+const Name = "example-transfers"
+
+// Sender is the account sending all generated transfers; it has to be funded by the embedding project.
+var Sender = common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+// Register adds the provider to the registry of transaction providers. It is
+// typically called from main of the embedding project before the run command.
+func Register() error {
+	return executor.TxProviders.Register(Name, newTransferProvider)
+}
+
+func newTransferProvider(cfg *utils.Config, _ executor.ProviderEnvironment) (executor.Provider[txcontext.TxContext], error) {
+	txsPerBlock := cfg.BlockLength
+	if txsPerBlock == 0 {
+		txsPerBlock = 1
+	}
+	return &transferProvider{
+		txsPerBlock: txsPerBlock,
+		fork:        cfg.Fork,
+	}, nil
+}
+
+// transferProvider generates a fixed number of transfers of 1 wei per block,
+// each sent to a different recipient.
+type transferProvider struct {
+	txsPerBlock uint64
+	fork        string
+}
+
+func (p *transferProvider) Run(from int, to int, consumer executor.Consumer[txcontext.TxContext]) error {
+	nonce := uint64(0)
+	for block := from; block < to; block++ {
+		env := blockEnvironment{number: uint64(block), fork: p.fork}
+		for tx := 0; tx < int(p.txsPerBlock); tx++ {
+			recipient := common.BigToAddress(big.NewInt(int64(nonce + 1)))
+			msg := &core.Message{
+				From:      Sender,
+				To:        &recipient,
+				Nonce:     nonce,
+				Value:     big.NewInt(1),
+				GasLimit:  params.TxGas,
+				GasPrice:  big.NewInt(0),
+				GasFeeCap: big.NewInt(0),
+				GasTipCap: big.NewInt(0),
+			}
+			nonce++
+			if err := consumer(executor.TransactionInfo[txcontext.TxContext]{
+				Block:       block,
+				Transaction: tx,
+				Data:        txgenerator.NewTxContext(env, msg),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *transferProvider) Close() {
+	// nothing to release
+}
+
+// blockEnvironment describes a synthetic block without base fee.
+type blockEnvironment struct {
+	number uint64
+	fork   string
+}
+
+func (e blockEnvironment) GetCoinbase() common.Address {
+	return common.Address{}
+}
+
+func (e blockEnvironment) GetDifficulty() *big.Int {
+	return big.NewInt(0)
+}
+
+func (e blockEnvironment) GetGasLimit() uint64 {
+	return 1_000_000_000
+}
+
+func (e blockEnvironment) GetNumber() uint64 {
+	return e.number
+}
+
+func (e blockEnvironment) GetTimestamp() uint64 {
+	return e.number
+}
+
+func (e blockEnvironment) GetBlockHash(number uint64) (common.Hash, error) {
+	return common.BigToHash(new(big.Int).SetUint64(number)), nil
+}
+
+func (e blockEnvironment) GetBaseFee() *big.Int {
+	return big.NewInt(0)
+}
+
+func (e blockEnvironment) GetBlobBaseFee() *big.Int {
+	return big.NewInt(0)
+}
+
+func (e blockEnvironment) GetRandom() *common.Hash {
+	return nil
+}
+
+func (e blockEnvironment) GetFork() string {
+	return e.fork
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package provider
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var registerOnce sync.Once
+
+func register(t *testing.T) {
+	registerOnce.Do(func() {
+		require.NoError(t, Register())
+	})
+}
+
+// countingProcessor records the messages passed by the executor.
+type countingProcessor struct {
+	perBlock map[int]int
+	nonces   []uint64
+}
+
+func (p *countingProcessor) Process(state executor.State[txcontext.TxContext], _ *executor.Context) error {
+	p.perBlock[state.Block]++
+	p.nonces = append(p.nonces, state.Data.GetMessage().Nonce)
+	return nil
+}
+
+func TestProvider_DrivesExecutorWhenSelected(t *testing.T) {
+	register(t)
+	cfg := &utils.Config{Provider: Name, BlockLength: 3}
+
+	provider, err := executor.TxProviders.OpenSelected(cfg, executor.SubstateProviderName, executor.ProviderEnvironment{})
+	require.NoError(t, err)
+	defer provider.Close()
+
+	processor := &countingProcessor{perBlock: make(map[int]int)}
+	err = executor.NewExecutor(provider, "critical").Run(executor.Params{From: 10, To: 12}, processor, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[int]int{10: 3, 11: 3}, processor.perBlock)
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4, 5}, processor.nonces)
+}
+
+func TestProvider_GeneratesTransfersOfSender(t *testing.T) {
+	register(t)
+	provider, err := executor.TxProviders.Open(Name, &utils.Config{}, executor.ProviderEnvironment{})
+	require.NoError(t, err)
+
+	var infos []executor.TransactionInfo[txcontext.TxContext]
+	require.NoError(t, provider.Run(5, 7, func(info executor.TransactionInfo[txcontext.TxContext]) error {
+		infos = append(infos, info)
+		return nil
+	}))
+
+	require.Len(t, infos, 2)
+	for i, info := range infos {
+		assert.Equal(t, 5+i, info.Block)
+		assert.Equal(t, 0, info.Transaction)
+		assert.Equal(t, uint64(5+i), info.Data.GetBlockEnvironment().GetNumber())
+		msg := info.Data.GetMessage()
+		assert.Equal(t, Sender, msg.From)
+		assert.NotNil(t, msg.To)
+		assert.Equal(t, int64(1), msg.Value.Int64())
+	}
+}
+
+func TestRegister_NameCanOnlyBeRegisteredOnce(t *testing.T) {
+	register(t)
+	assert.Contains(t, executor.TxProviders.Names(), Name)
+	require.ErrorContains(t, Register(), "already registered")
+}
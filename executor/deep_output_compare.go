@@ -0,0 +1,193 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// callFrame describes the call frame active while a storage write was issued.
+type callFrame struct {
+	depth int
+	typ   vm.OpCode
+	from  common.Address
+	to    common.Address
+}
+
+func (f callFrame) String() string {
+	return fmt.Sprintf("%v frame at depth %d (%v -> %v)", f.typ, f.depth, f.from.Hex(), f.to.Hex())
+}
+
+// storageWrite is the last write of a storage slot observed during a transaction.
+type storageWrite struct {
+	value    common.Hash
+	frame    *callFrame // nil if no call tracer hooks were invoked
+	pc       uint64
+	bySstore bool // true if the write was issued by an SSTORE instruction
+}
+
+func (w storageWrite) String() string {
+	if w.frame == nil {
+		return fmt.Sprintf("last written with value %v outside of any traced call frame", w.value.Hex())
+	}
+	if !w.bySstore {
+		return fmt.Sprintf("last written with value %v in %v", w.value.Hex(), w.frame)
+	}
+	return fmt.Sprintf("last written with value %v by SSTORE at pc %d in %v", w.value.Hex(), w.pc, w.frame)
+}
+
+// writeJournal is a VmStateDB wrapper recording every storage write of a single
+// transaction together with the call frame that issued it. The journal is kept
+// alive until the deep output compare is done, so that a divergent slot can be
+// attributed to the code which wrote it.
+type writeJournal struct {
+	state.VmStateDB
+	frames []callFrame
+	lastOp vm.OpCode
+	lastPc uint64
+	writes map[common.Address]map[common.Hash]storageWrite
+}
+
+func newWriteJournal(db state.VmStateDB) *writeJournal {
+	return &writeJournal{
+		VmStateDB: db,
+		writes:    make(map[common.Address]map[common.Hash]storageWrite),
+	}
+}
+
+// hooks returns the call tracer hooks maintaining the call frame stack of the journal.
+func (j *writeJournal) hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnEnter:  j.onEnter,
+		OnExit:   j.onExit,
+		OnOpcode: j.onOpcode,
+	}
+}
+
+func (j *writeJournal) onEnter(depth int, typ byte, from common.Address, to common.Address, _ []byte, _ uint64, _ *big.Int) {
+	j.frames = append(j.frames, callFrame{depth: depth, typ: vm.OpCode(typ), from: from, to: to})
+}
+
+func (j *writeJournal) onExit(int, []byte, uint64, error, bool) {
+	if len(j.frames) > 0 {
+		j.frames = j.frames[:len(j.frames)-1]
+	}
+}
+
+func (j *writeJournal) onOpcode(pc uint64, op byte, _, _ uint64, _ tracing.OpContext, _ []byte, _ int, _ error) {
+	j.lastPc = pc
+	j.lastOp = vm.OpCode(op)
+}
+
+func (j *writeJournal) SetState(addr common.Address, key common.Hash, value common.Hash) common.Hash {
+	write := storageWrite{value: value}
+	if len(j.frames) > 0 {
+		frame := j.frames[len(j.frames)-1]
+		write.frame = &frame
+		write.bySstore = j.lastOp == vm.SSTORE
+		write.pc = j.lastPc
+	}
+	slots, ok := j.writes[addr]
+	if !ok {
+		slots = make(map[common.Hash]storageWrite)
+		j.writes[addr] = slots
+	}
+	slots[key] = write
+	return j.VmStateDB.SetState(addr, key, value)
+}
+
+// describeWrite returns the recorded write context of the given slot.
+func (j *writeJournal) describeWrite(addr common.Address, key common.Hash) string {
+	if write, ok := j.writes[addr][key]; ok {
+		return write.String()
+	}
+	return "not written during the transaction"
+}
+
+// compare checks whether the world state produced by the execution matches the
+// expected one and reports the first divergence. Accounts and storage slots are
+// visited in sorted order, hence the reported divergence is deterministic.
+func (j *writeJournal) compare(want, have txcontext.WorldState) error {
+	for _, addr := range sortedAddresses(want, have) {
+		wantAcc, haveAcc := want.Get(addr), have.Get(addr)
+		if wantAcc == nil {
+			return fmt.Errorf("unexpected account %v in post-alloc", addr.Hex())
+		}
+		if haveAcc == nil {
+			return fmt.Errorf("account %v is missing in post-alloc", addr.Hex())
+		}
+		if wantAcc.GetNonce() != haveAcc.GetNonce() {
+			return fmt.Errorf("different nonce of account %v; want: %v, have: %v", addr.Hex(), wantAcc.GetNonce(), haveAcc.GetNonce())
+		}
+		if wantAcc.GetBalance().Cmp(haveAcc.GetBalance()) != 0 {
+			return fmt.Errorf("different balance of account %v; want: %v, have: %v", addr.Hex(), wantAcc.GetBalance(), haveAcc.GetBalance())
+		}
+		if !bytes.Equal(wantAcc.GetCode(), haveAcc.GetCode()) {
+			return fmt.Errorf("different code of account %v; want len: %v, have len: %v", addr.Hex(), len(wantAcc.GetCode()), len(haveAcc.GetCode()))
+		}
+		for _, key := range sortedStorageKeys(wantAcc, haveAcc) {
+			wantValue, haveValue := wantAcc.GetStorageAt(key), haveAcc.GetStorageAt(key)
+			if wantValue == haveValue && wantAcc.HasStorageAt(key) == haveAcc.HasStorageAt(key) {
+				continue
+			}
+			return fmt.Errorf("different storage of account %v at slot %v; want: %v, have: %v; %v",
+				addr.Hex(), key.Hex(), wantValue.Hex(), haveValue.Hex(), j.describeWrite(addr, key))
+		}
+	}
+	return nil
+}
+
+// sortedAddresses returns the sorted union of accounts of given world states.
+func sortedAddresses(worldStates ...txcontext.WorldState) []common.Address {
+	seen := make(map[common.Address]struct{})
+	for _, ws := range worldStates {
+		ws.ForEachAccount(func(addr common.Address, _ txcontext.Account) {
+			seen[addr] = struct{}{}
+		})
+	}
+	res := make([]common.Address, 0, len(seen))
+	for addr := range seen {
+		res = append(res, addr)
+	}
+	slices.SortFunc(res, func(a, b common.Address) int { return bytes.Compare(a[:], b[:]) })
+	return res
+}
+
+// sortedStorageKeys returns the sorted union of storage keys of given accounts.
+func sortedStorageKeys(accounts ...txcontext.Account) []common.Hash {
+	seen := make(map[common.Hash]struct{})
+	for _, acc := range accounts {
+		acc.ForEachStorage(func(key common.Hash, _ common.Hash) {
+			seen[key] = struct{}{}
+		})
+	}
+	res := make([]common.Hash, 0, len(seen))
+	for key := range seen {
+		res = append(res, key)
+	}
+	slices.SortFunc(res, func(a, b common.Hash) int { return bytes.Compare(a[:], b[:]) })
+	return res
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWriteJournal_SetStateRecordsWritingFrame(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockVmStateDB(ctrl)
+
+	addr := common.Address{1}
+	key := common.Hash{2}
+	value := common.Hash{3}
+	db.EXPECT().SetState(addr, key, value).Return(common.Hash{})
+
+	journal := newWriteJournal(db)
+	hooks := journal.hooks()
+	hooks.OnEnter(0, byte(vm.CALL), common.Address{4}, addr, nil, 0, big.NewInt(0))
+	hooks.OnOpcode(7, byte(vm.SSTORE), 0, 0, nil, nil, 0, nil)
+	journal.SetState(addr, key, value)
+	hooks.OnExit(0, nil, 0, nil, false)
+
+	write, ok := journal.writes[addr][key]
+	require.True(t, ok)
+	assert.Equal(t, value, write.value)
+	assert.True(t, write.bySstore)
+	assert.Equal(t, uint64(7), write.pc)
+	require.NotNil(t, write.frame)
+	assert.Equal(t, vm.CALL, write.frame.typ)
+	assert.Empty(t, journal.frames)
+}
+
+func TestWriteJournal_CompareReportsFirstDivergentSlot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockVmStateDB(ctrl)
+
+	addr := common.Address{1}
+	db.EXPECT().SetState(addr, common.Hash{2}, common.Hash{4}).Return(common.Hash{})
+
+	journal := newWriteJournal(db)
+	hooks := journal.hooks()
+	hooks.OnEnter(1, byte(vm.DELEGATECALL), common.Address{5}, addr, nil, 0, big.NewInt(0))
+	hooks.OnOpcode(42, byte(vm.SSTORE), 0, 0, nil, nil, 1, nil)
+	journal.SetState(addr, common.Hash{2}, common.Hash{4})
+
+	want := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		addr: txcontext.NewAccount(nil, map[common.Hash]common.Hash{{1}: {1}, {2}: {3}, {3}: {3}}, big.NewInt(1), 1),
+	})
+	have := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		addr: txcontext.NewAccount(nil, map[common.Hash]common.Hash{{1}: {1}, {2}: {4}, {3}: {5}}, big.NewInt(1), 1),
+	})
+
+	err := journal.compare(want, have)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), common.Hash{2}.Hex())
+	assert.Contains(t, err.Error(), "SSTORE at pc 42")
+	assert.Contains(t, err.Error(), "DELEGATECALL frame at depth 1")
+}
+
+func TestWriteJournal_CompareReportsUnwrittenSlot(t *testing.T) {
+	journal := newWriteJournal(nil)
+	addr := common.Address{1}
+	want := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		addr: txcontext.NewAccount(nil, map[common.Hash]common.Hash{{1}: {1}}, big.NewInt(1), 1),
+	})
+	have := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		addr: txcontext.NewAccount(nil, map[common.Hash]common.Hash{}, big.NewInt(1), 1),
+	})
+
+	err := journal.compare(want, have)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not written during the transaction")
+}
+
+func TestWriteJournal_CompareDetectsAccountDivergences(t *testing.T) {
+	acc := func(balance int64, nonce uint64, code []byte) txcontext.Account {
+		return txcontext.NewAccount(code, map[common.Hash]common.Hash{}, big.NewInt(balance), nonce)
+	}
+	addr := common.Address{1}
+	tests := map[string]struct {
+		want, have txcontext.Account
+		expected   string
+	}{
+		"equal":    {acc(1, 1, nil), acc(1, 1, nil), ""},
+		"balance":  {acc(1, 1, nil), acc(2, 1, nil), "different balance"},
+		"nonce":    {acc(1, 1, nil), acc(1, 2, nil), "different nonce"},
+		"code":     {acc(1, 1, nil), acc(1, 1, []byte{1}), "different code"},
+		"missing":  {acc(1, 1, nil), nil, "is missing"},
+		"unwanted": {nil, acc(1, 1, nil), "unexpected account"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			toWorldState := func(acc txcontext.Account) txcontext.WorldState {
+				if acc == nil {
+					return txcontext.NewWorldState(map[common.Address]txcontext.Account{})
+				}
+				return txcontext.NewWorldState(map[common.Address]txcontext.Account{addr: acc})
+			}
+			err := newWriteJournal(nil).compare(toWorldState(test.want), toWorldState(test.have))
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestAidaProcessor_DeepOutputCompareSkipsDbWithoutPostAlloc(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockVmStateDB(ctrl)
+	db.EXPECT().GetSubstatePostAlloc().Return(nil).Times(2)
+
+	p := &aidaProcessor{
+		cfg: &utils.Config{DeepOutputCompare: true},
+		log: logger.NewLogger("critical", "test"),
+	}
+	want := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		{1}: txcontext.NewAccount(nil, nil, big.NewInt(1), 1),
+	})
+	require.NoError(t, p.deepOutputCompare(newWriteJournal(db), want))
+	require.NoError(t, p.deepOutputCompare(newWriteJournal(db), want))
+}
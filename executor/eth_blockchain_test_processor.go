@@ -0,0 +1,337 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xsoniclabs/aida/ethtest"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
+)
+
+// MakeEthBlockchainTestProcessor creates an executor.Processor which processes blockchain tests created from ethereum test package.
+func MakeEthBlockchainTestProcessor(cfg *utils.Config) (*ethBlockchainTestProcessor, error) {
+	processor, err := MakeTxProcessor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ethBlockchainTestProcessor{processor}, nil
+}
+
+type ethBlockchainTestProcessor struct {
+	*TxProcessor
+}
+
+// testChain tracks the head and block hashes of the chain built by a blockchain test.
+type testChain struct {
+	head   *types.Header
+	hashes map[uint64]common.Hash
+}
+
+func newTestChain(genesis *types.Header) *testChain {
+	return &testChain{
+		head:   genesis,
+		hashes: map[uint64]common.Hash{genesis.Number.Uint64(): genesis.Hash()},
+	}
+}
+
+func (c *testChain) append(header *types.Header) {
+	c.head = header
+	c.hashes[header.Number.Uint64()] = header.Hash()
+}
+
+// Process executes all blocks of a blockchain test inside given LIVE StateDb. Blocks expected
+// to be invalid must be rejected without modifying the state, and the head of the resulting
+// chain must match the head expected by the test. Any failure is reported through the execution
+// result so that it is evaluated by the same validators as state tests.
+func (p *ethBlockchainTestProcessor) Process(state State[txcontext.TxContext], ctx *Context) error {
+	test, ok := state.Data.(*ethtest.BlockchainTestContext)
+	if !ok {
+		return fmt.Errorf("unexpected test type %T", state.Data)
+	}
+	fork := test.GetBlockEnvironment().GetFork()
+	chainCfg, err := p.cfg.GetChainConfig(fork)
+	if err != nil {
+		return err
+	}
+
+	chain := newTestChain(test.GetGenesis())
+	var failure error
+	for i, block := range test.GetBlocks() {
+		err = p.processBlock(ctx.State, chainCfg, fork, chain, block)
+		if block.ExpectException == "" && err != nil {
+			failure = fmt.Errorf("valid block %d was rejected; %w", i, err)
+			break
+		}
+		if block.ExpectException != "" && err == nil {
+			failure = fmt.Errorf("invalid block %d was accepted; expected exception: %v", i, block.ExpectException)
+			break
+		}
+	}
+	if failure == nil && chain.head.Hash() != test.GetLastBlockHash() {
+		failure = fmt.Errorf("unexpected head of chain, got: %s, want: %s", chain.head.Hash(), test.GetLastBlockHash())
+	}
+
+	ctx.ExecutionResult = newTransactionResult(nil, &core.Message{}, nil, failure, common.Address{})
+	return nil
+}
+
+// processBlock validates and executes a single block. Blocks expected to be invalid are executed
+// within a single snapshot which is always reverted, hence a rejected block never changes the state.
+func (p *ethBlockchainTestProcessor) processBlock(db state.StateDB, chainCfg *params.ChainConfig, fork string, chain *testChain, b ethtest.BlockchainTestBlock) error {
+	if b.Block == nil {
+		return b.DecodeErr
+	}
+	header := b.Block.Header()
+	if err := validateBlockHeader(chainCfg, chain.head, header); err != nil {
+		return err
+	}
+	if err := validateBlockBody(b.Block); err != nil {
+		return err
+	}
+
+	env := ethtest.NewBlockchainTestEnvironment(header, chainCfg, fork, chain.hashes)
+	if err := db.BeginBlock(header.Number.Uint64()); err != nil {
+		return err
+	}
+	var err error
+	if b.ExpectException != "" {
+		err = p.applyRejectedBlock(db, chainCfg, env, b.Block)
+	} else {
+		err = p.applyBlock(db, chainCfg, env, b.Block)
+	}
+	if err = errors.Join(err, db.EndBlock()); err != nil {
+		return err
+	}
+
+	if p.cfg.Validate && b.ExpectException == "" {
+		root, err := db.GetHash()
+		if err != nil {
+			return err
+		}
+		if root != header.Root {
+			return fmt.Errorf("unexpected state root, got: %s, want: %s", root, header.Root)
+		}
+	}
+	chain.append(header)
+	return nil
+}
+
+// applyBlock executes system calls, transactions and withdrawals of the block,
+// each in its own transaction scope.
+func (p *ethBlockchainTestProcessor) applyBlock(db state.StateDB, chainCfg *params.ChainConfig, env txcontext.BlockEnvironment, block *types.Block) error {
+	header := block.Header()
+	var hashError error
+	evm := vm.NewEVM(*utils.PrepareBlockCtx(env, &hashError), db, chainCfg, p.cfg.VmCfg)
+
+	if err := db.BeginTransaction(utils.PseudoTx); err != nil {
+		return err
+	}
+	if header.ParentBeaconRoot != nil {
+		core.ProcessBeaconBlockRoot(*header.ParentBeaconRoot, evm)
+	}
+	if chainCfg.IsPrague(header.Number, header.Time) {
+		core.ProcessParentBlockHash(header.ParentHash, evm)
+	}
+	if err := db.EndTransaction(); err != nil {
+		return err
+	}
+
+	var gasUsed uint64
+	for i, tx := range block.Transactions() {
+		if err := db.BeginTransaction(uint32(i)); err != nil {
+			return err
+		}
+		used, err := p.applyTransaction(db, chainCfg, env, header, i, tx)
+		if err != nil {
+			return err
+		}
+		gasUsed += used
+		if err = db.EndTransaction(); err != nil {
+			return err
+		}
+	}
+	if err := checkGasUsage(chainCfg, block, gasUsed); err != nil {
+		return err
+	}
+
+	if err := db.BeginTransaction(utils.PseudoTx); err != nil {
+		return err
+	}
+	applyWithdrawals(db, block.Withdrawals())
+	if chainCfg.IsPrague(header.Number, header.Time) {
+		// requests are not compared with the header, only state changes made by the system calls are applied
+		var requests [][]byte
+		if err := core.ProcessWithdrawalQueue(&requests, evm); err != nil {
+			return err
+		}
+		if err := core.ProcessConsolidationQueue(&requests, evm); err != nil {
+			return err
+		}
+	}
+	if err := db.EndTransaction(); err != nil {
+		return err
+	}
+	return hashError
+}
+
+// applyRejectedBlock executes transactions of a block which is expected to be rejected. All of them
+// run within one transaction scope and the state is reverted afterward regardless of the outcome.
+func (p *ethBlockchainTestProcessor) applyRejectedBlock(db state.StateDB, chainCfg *params.ChainConfig, env txcontext.BlockEnvironment, block *types.Block) error {
+	if err := db.BeginTransaction(0); err != nil {
+		return err
+	}
+	snapshot := db.Snapshot()
+	err := func() error {
+		var gasUsed uint64
+		for i, tx := range block.Transactions() {
+			used, err := p.applyTransaction(db, chainCfg, env, block.Header(), i, tx)
+			if err != nil {
+				return err
+			}
+			gasUsed += used
+		}
+		return checkGasUsage(chainCfg, block, gasUsed)
+	}()
+	db.RevertToSnapshot(snapshot)
+	return errors.Join(err, db.EndTransaction())
+}
+
+// applyTransaction executes a single transaction of the block and returns gas used by it.
+// An invalid transaction invalidates the whole block.
+func (p *ethBlockchainTestProcessor) applyTransaction(db state.StateDB, chainCfg *params.ChainConfig, env txcontext.BlockEnvironment, header *types.Header, i int, tx *types.Transaction) (uint64, error) {
+	signer := types.MakeSigner(chainCfg, header.Number, header.Time)
+	msg, err := core.TransactionToMessage(tx, signer, header.BaseFee)
+	if err != nil {
+		return 0, fmt.Errorf("invalid transaction %d; %w", i, err)
+	}
+	res, err := p.ProcessTransaction(db, int(header.Number.Uint64()), i, ethtest.NewStateTestContext(msg, env, nil))
+	if err != nil {
+		return 0, fmt.Errorf("invalid transaction %d; %w", i, err)
+	}
+	return res.GetGasUsed(), nil
+}
+
+// applyWithdrawals credits withdrawn amounts, which are denominated in Gwei.
+func applyWithdrawals(db state.VmStateDB, withdrawals types.Withdrawals) {
+	for _, w := range withdrawals {
+		amount := new(uint256.Int).Mul(uint256.NewInt(w.Amount), uint256.NewInt(params.GWei))
+		db.AddBalance(w.Address, amount, tracing.BalanceIncreaseWithdrawal)
+	}
+}
+
+// checkGasUsage compares gas and blob gas used by transactions with values declared by the block header.
+func checkGasUsage(chainCfg *params.ChainConfig, block *types.Block, gasUsed uint64) error {
+	if gasUsed != block.GasUsed() {
+		return fmt.Errorf("invalid gas used, got: %v, header: %v", gasUsed, block.GasUsed())
+	}
+	if !chainCfg.IsCancun(block.Number(), block.Time()) {
+		return nil
+	}
+	var blobGasUsed uint64
+	for _, tx := range block.Transactions() {
+		blobGasUsed += tx.BlobGas()
+	}
+	if limit := eip4844.MaxBlobGasPerBlock(chainCfg, block.Time()); blobGasUsed > limit {
+		return fmt.Errorf("blob gas used %v exceeds maximum %v", blobGasUsed, limit)
+	}
+	if header := block.Header(); header.BlobGasUsed == nil || *header.BlobGasUsed != blobGasUsed {
+		return fmt.Errorf("invalid blob gas used, got: %v, header: %v", blobGasUsed, header.BlobGasUsed)
+	}
+	return nil
+}
+
+// validateBlockHeader checks the header against its parent according to post-merge consensus rules.
+func validateBlockHeader(chainCfg *params.ChainConfig, parent *types.Header, header *types.Header) error {
+	if header.ParentHash != parent.Hash() {
+		return fmt.Errorf("unknown parent %s", header.ParentHash)
+	}
+	if header.Number.Uint64() != parent.Number.Uint64()+1 {
+		return fmt.Errorf("invalid block number %v, parent: %v", header.Number, parent.Number)
+	}
+	if header.Time <= parent.Time {
+		return fmt.Errorf("timestamp %v is not newer than parent %v", header.Time, parent.Time)
+	}
+	if len(header.Extra) > int(params.MaximumExtraDataSize) {
+		return fmt.Errorf("extra-data too long: %v", len(header.Extra))
+	}
+	if header.GasUsed > header.GasLimit {
+		return fmt.Errorf("gas used %v exceeds gas limit %v", header.GasUsed, header.GasLimit)
+	}
+	if header.Difficulty == nil || header.Difficulty.Sign() != 0 {
+		return errors.New("non-zero difficulty after the merge")
+	}
+	if header.UncleHash != types.EmptyUncleHash {
+		return errors.New("ommers are not allowed after the merge")
+	}
+	if chainCfg.IsLondon(header.Number) {
+		if err := eip1559.VerifyEIP1559Header(chainCfg, parent, header); err != nil {
+			return err
+		}
+	} else if err := misc.VerifyGaslimit(parent.GasLimit, header.GasLimit); err != nil {
+		return err
+	}
+	if chainCfg.IsShanghai(header.Number, header.Time) != (header.WithdrawalsHash != nil) {
+		return errors.New("withdrawals hash presence does not match the fork")
+	}
+	if chainCfg.IsCancun(header.Number, header.Time) {
+		if header.ParentBeaconRoot == nil {
+			return errors.New("missing parent beacon root")
+		}
+		return eip4844.VerifyEIP4844Header(chainCfg, parent, header)
+	}
+	if header.ExcessBlobGas != nil || header.BlobGasUsed != nil || header.ParentBeaconRoot != nil {
+		return errors.New("unexpected cancun header fields")
+	}
+	return nil
+}
+
+// validateBlockBody checks that transactions, ommers and withdrawals match the block header.
+func validateBlockBody(block *types.Block) error {
+	header := block.Header()
+	if len(block.Uncles()) > 0 {
+		return errors.New("ommers are not allowed after the merge")
+	}
+	if hash := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); hash != header.TxHash {
+		return fmt.Errorf("transaction root mismatch, got: %s, header: %s", hash, header.TxHash)
+	}
+	if header.WithdrawalsHash != nil {
+		if block.Withdrawals() == nil {
+			return errors.New("missing withdrawals in block body")
+		}
+		if hash := types.DeriveSha(block.Withdrawals(), trie.NewStackTrie(nil)); hash != *header.WithdrawalsHash {
+			return fmt.Errorf("withdrawals root mismatch, got: %s, header: %s", hash, *header.WithdrawalsHash)
+		}
+	} else if block.Withdrawals() != nil {
+		return errors.New("unexpected withdrawals in block body")
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/ethtest"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/tests"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthBlockchainTestProcessor_UndecodableBlocks(t *testing.T) {
+	genesis := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(0)}
+	decodeErr := errors.New("cannot decode block rlp")
+	tests := map[string]struct {
+		block   ethtest.BlockchainTestBlock
+		wantErr string
+	}{
+		"rejected invalid block": {
+			block: ethtest.BlockchainTestBlock{DecodeErr: decodeErr, ExpectException: "BlockException.RLP_STRUCTURES_ENCODING"},
+		},
+		"rejected valid block": {
+			block:   ethtest.BlockchainTestBlock{DecodeErr: decodeErr},
+			wantErr: "valid block 0 was rejected",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := MakeEthBlockchainTestProcessor(&utils.Config{ChainID: utils.EthTestsChainID})
+			require.NoError(t, err)
+			data := ethtest.CreateTestBlockchainTest(t, genesis, []ethtest.BlockchainTestBlock{test.block}, genesis.Hash())
+
+			ctx := &Context{}
+			require.NoError(t, p.Process(State[txcontext.TxContext]{Block: 2, Data: data}, ctx))
+			_, err = ctx.ExecutionResult.GetRawResult()
+			if test.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestEthBlockchainTestProcessor_ReportsUnexpectedHead(t *testing.T) {
+	p, err := MakeEthBlockchainTestProcessor(&utils.Config{ChainID: utils.EthTestsChainID})
+	require.NoError(t, err)
+	genesis := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(0)}
+	data := ethtest.CreateTestBlockchainTest(t, genesis, nil, common.Hash{1})
+
+	ctx := &Context{}
+	require.NoError(t, p.Process(State[txcontext.TxContext]{Block: 2, Data: data}, ctx))
+	_, err = ctx.ExecutionResult.GetRawResult()
+	require.ErrorContains(t, err, "unexpected head of chain")
+}
+
+func TestEthBlockchainTestProcessor_RejectsUnknownTestType(t *testing.T) {
+	p, err := MakeEthBlockchainTestProcessor(&utils.Config{ChainID: utils.EthTestsChainID})
+	require.NoError(t, err)
+	err = p.Process(State[txcontext.TxContext]{Data: ethtest.CreateTestTransaction(t)}, &Context{})
+	require.ErrorContains(t, err, "unexpected test type")
+}
+
+func TestValidateBlockHeader(t *testing.T) {
+	chainCfg, _, err := tests.GetChainConfig("Shanghai")
+	require.NoError(t, err)
+	parent := &types.Header{
+		Number:     big.NewInt(0),
+		Difficulty: big.NewInt(0),
+		GasLimit:   params.GenesisGasLimit,
+		BaseFee:    big.NewInt(params.InitialBaseFee),
+		UncleHash:  types.EmptyUncleHash,
+		Time:       10,
+	}
+	valid := func() *types.Header {
+		return &types.Header{
+			ParentHash:      parent.Hash(),
+			Number:          big.NewInt(1),
+			Difficulty:      big.NewInt(0),
+			GasLimit:        params.GenesisGasLimit,
+			BaseFee:         big.NewInt(params.InitialBaseFee * 7 / 8), // empty parent decreases base fee
+			UncleHash:       types.EmptyUncleHash,
+			WithdrawalsHash: &types.EmptyWithdrawalsHash,
+			Time:            20,
+		}
+	}
+	require.NoError(t, validateBlockHeader(chainCfg, parent, valid()))
+
+	tests := map[string]struct {
+		modify  func(*types.Header)
+		wantErr string
+	}{
+		"unknown parent":   {func(h *types.Header) { h.ParentHash = common.Hash{1} }, "unknown parent"},
+		"wrong number":     {func(h *types.Header) { h.Number = big.NewInt(2) }, "invalid block number"},
+		"old timestamp":    {func(h *types.Header) { h.Time = 10 }, "is not newer than parent"},
+		"difficulty":       {func(h *types.Header) { h.Difficulty = big.NewInt(1) }, "non-zero difficulty"},
+		"ommers":           {func(h *types.Header) { h.UncleHash = common.Hash{1} }, "ommers are not allowed"},
+		"gas used":         {func(h *types.Header) { h.GasUsed = h.GasLimit + 1 }, "exceeds gas limit"},
+		"no withdrawals":   {func(h *types.Header) { h.WithdrawalsHash = nil }, "withdrawals hash presence"},
+		"cancun fields":    {func(h *types.Header) { h.ParentBeaconRoot = &common.Hash{} }, "unexpected cancun header fields"},
+		"invalid base fee": {func(h *types.Header) { h.BaseFee = big.NewInt(1) }, "invalid baseFee"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			header := valid()
+			test.modify(header)
+			require.ErrorContains(t, validateBlockHeader(chainCfg, parent, header), test.wantErr)
+		})
+	}
+}
+
+func TestValidateBlockBody(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0)}
+	block := types.NewBlock(header, &types.Body{Withdrawals: []*types.Withdrawal{}}, nil, nil)
+	require.NoError(t, validateBlockBody(block))
+
+	ommers := types.NewBlock(header, &types.Body{Uncles: []*types.Header{header}}, nil, nil)
+	require.ErrorContains(t, validateBlockBody(ommers), "ommers are not allowed")
+
+	mismatch := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), TxHash: common.Hash{1}})
+	require.ErrorContains(t, validateBlockBody(mismatch), "transaction root mismatch")
+}
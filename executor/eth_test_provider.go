@@ -25,11 +25,18 @@ import (
 )
 
 func NewEthStateTestProvider(cfg *utils.Config) Provider[txcontext.TxContext] {
-	return ethTestProvider{cfg}
+	return ethTestProvider{cfg: cfg}
+}
+
+// NewEthStateTestProviderWithResults creates a provider of ethereum tests which records
+// tests that cannot be run as skipped to the given result matrix.
+func NewEthStateTestProviderWithResults(cfg *utils.Config, results *statetest.ResultMatrix) Provider[txcontext.TxContext] {
+	return ethTestProvider{cfg: cfg, results: results}
 }
 
 type ethTestProvider struct {
-	cfg *utils.Config
+	cfg     *utils.Config
+	results *statetest.ResultMatrix // if set, tests which cannot be run are recorded as skipped
 }
 
 func (e ethTestProvider) Run(_ int, _ int, consumer Consumer[txcontext.TxContext]) error {
@@ -37,8 +44,16 @@ func (e ethTestProvider) Run(_ int, _ int, consumer Consumer[txcontext.TxContext
 	if err != nil {
 		return err
 	}
+	if e.results != nil {
+		splitter.SetResults(e.results)
+	}
 
-	tests, err := splitter.SplitStateTests()
+	var tests []statetest.Transaction
+	if e.cfg.EthTestType == utils.BlockTests {
+		tests, err = splitter.SplitBlockchainTests()
+	} else {
+		tests, err = splitter.SplitStateTests()
+	}
 	if err != nil {
 		return err
 	}
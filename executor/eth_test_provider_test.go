@@ -20,12 +20,18 @@ import (
 	_ "embed"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/0xsoniclabs/aida/ethtest"
+	"github.com/0xsoniclabs/aida/txcontext"
 	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -79,6 +85,30 @@ func createTestDataFile(t *testing.T) string {
 	return pathFile
 }
 
+func Test_ethTestProvider_Run_RecordsSkippedTests(t *testing.T) {
+	stData := ethtest.CreateTestStJson(t)
+	stData.Post["Frontier"] = stData.Post["London"]
+	jsonData, err := json.Marshal(map[string]any{"test": stData})
+	require.NoError(t, err)
+	pathFile := filepath.Join(t.TempDir(), "stExample", "test.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(pathFile), 0755))
+	require.NoError(t, os.WriteFile(pathFile, jsonData, 0644))
+
+	cfg := &utils.Config{
+		ArgPath: pathFile,
+		Forks:   []string{"cancun"},
+	}
+	results := ethtest.NewResultMatrix()
+	provider := NewEthStateTestProviderWithResults(cfg, results)
+
+	ctrl := gomock.NewController(t)
+	consumer := NewMockTxConsumer(ctrl)
+	consumer.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+
+	require.NoError(t, provider.Run(0, 0, toSubstateConsumer(consumer)))
+	assert.Equal(t, []ethtest.ResultRow{{Fork: "Frontier", Suite: "stExample", Skipped: 2}}, results.Rows())
+}
+
 func TestExecutor_NewEthStateTestProvider(t *testing.T) {
 	cfg := &utils.Config{ArgPath: "somepath"}
 	provider := NewEthStateTestProvider(cfg)
@@ -151,3 +181,32 @@ func TestEthTestProvider_Run_NoTestsFromSplitter(t *testing.T) {
 	runErr := provider.Run(0, 0, toSubstateConsumer(mockConsumer))
 	assert.NoError(t, runErr)
 }
+
+func TestEthTestProvider_Run_BlockchainTests(t *testing.T) {
+	genesis, err := rlp.EncodeToBytes(types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(0)}))
+	require.NoError(t, err)
+	pathFile := filepath.Join(t.TempDir(), "test.json")
+	jsonStr := fmt.Sprintf(`{
+		"runnable": {"network": "Cancun", "sealEngine": "NoProof", "genesisRLP": "%v", "blocks": [{"rlp": "0xbad", "expectException": "err"}]},
+		"pre-merge": {"network": "London", "sealEngine": "NoProof", "genesisRLP": "%v"}
+	}`, hexutil.Encode(genesis), hexutil.Encode(genesis))
+	require.NoError(t, os.WriteFile(pathFile, []byte(jsonStr), 0644))
+
+	cfg := &utils.Config{
+		ArgPath:     pathFile,
+		Fork:        "all",
+		EthTestType: utils.BlockTests,
+	}
+
+	ctrl := gomock.NewController(t)
+	consumer := NewMockTxConsumer(ctrl)
+	consumer.EXPECT().Consume(2, 0, gomock.Any()).DoAndReturn(func(_ int, _ int, data txcontext.TxContext) error {
+		test, ok := data.(*ethtest.BlockchainTestContext)
+		require.True(t, ok)
+		require.Len(t, test.GetBlocks(), 1)
+		assert.Error(t, test.GetBlocks()[0].DecodeErr)
+		return nil
+	})
+
+	require.NoError(t, NewEthStateTestProvider(cfg).Run(0, 0, toSubstateConsumer(consumer)))
+}
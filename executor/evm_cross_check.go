@@ -0,0 +1,302 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	gethlogger "github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/holiman/uint256"
+	"golang.org/x/exp/maps"
+)
+
+// MakeEvmCrossCheckProcessor creates an executor.Processor which processes transactions into the LIVE StateDb
+// like the one of MakeLiveDbTxProcessor. If --cross-check-evm is set, each regular transaction is additionally
+// executed by both --evm-impl and --cross-check-evm on isolated in-memory copies of its input alloc. Diverging
+// gas usage, return data, logs or balances of touched accounts are reported as processing errors and the
+// execution summaries of both runs are written to --cross-check-dir.
+func MakeEvmCrossCheckProcessor(cfg *utils.Config) (Processor[txcontext.TxContext], error) {
+	live, err := MakeLiveDbTxProcessor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.CrossCheckEvm == "" {
+		return live, nil
+	}
+
+	evmImpl, vmImpl, _ := strings.Cut(cfg.CrossCheckEvm, ":")
+	if vmImpl == "" {
+		vmImpl = cfg.VmImpl
+	}
+
+	p := &evmCrossCheckProcessor{
+		LiveDbTxProcessor: live,
+		cfg:               cfg,
+		log:               logger.NewLogger(cfg.LogLevel, "EvmCrossCheck"),
+	}
+	for i, impl := range [2][2]string{{cfg.EvmImpl, cfg.VmImpl}, {evmImpl, vmImpl}} {
+		c := *cfg
+		c.EvmImpl, c.VmImpl = impl[0], impl[1]
+		// the isolated runs must neither be compared with the recording nor be profiled
+		c.DeepOutputCompare = false
+		c.MicroProfiling = false
+
+		processor, err := MakeTxProcessor(&c)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create processor for --%v; %w", crossCheckSides[i], err)
+		}
+		p.runs[i] = crossCheckRun{
+			name:      impl[0] + ":" + impl[1],
+			cfg:       &c,
+			processor: processor,
+		}
+	}
+	return p, nil
+}
+
+// crossCheckSides names the compared executions in reports and file names.
+var crossCheckSides = [2]string{utils.EvmImplementation.Name, utils.CrossCheckEvmFlag.Name}
+
+type evmCrossCheckProcessor struct {
+	*LiveDbTxProcessor
+	cfg  *utils.Config
+	log  logger.Logger
+	runs [2]crossCheckRun
+}
+
+// crossCheckRun is one of the compared EVM implementations.
+type crossCheckRun struct {
+	name      string // <evm-impl>:<vm-impl>
+	cfg       *utils.Config
+	processor *TxProcessor
+}
+
+// Process cross-checks the transaction and processes it into the LIVE StateDb afterward.
+func (p *evmCrossCheckProcessor) Process(state State[txcontext.TxContext], ctx *Context) error {
+	if state.Transaction < utils.PseudoTx && !IsSkippedByPolicy(state.Data) {
+		if err := p.crossCheck(state.Block, state.Transaction, state.Data); err != nil {
+			if p.isErrFatal() {
+				return err
+			}
+			ctx.ErrorInput <- NewTxError(ProcessorComponent, state.Block, state.Transaction, ErrEvmDivergence, err)
+		}
+	}
+	return p.LiveDbTxProcessor.Process(state, ctx)
+}
+
+// crossCheck executes the transaction by both implementations and compares the outcomes.
+func (p *evmCrossCheckProcessor) crossCheck(block int, tx int, st txcontext.TxContext) error {
+	accounts := touchedAccounts(st)
+
+	var summaries [2]*executionSummary
+	for i, run := range p.runs {
+		summaries[i] = run.execute(run.processor, block, tx, st, accounts)
+	}
+
+	diffs := summaries[0].diff(summaries[1], accounts)
+	if len(diffs) == 0 {
+		return nil
+	}
+
+	err := fmt.Errorf("block: %v transaction: %v; %v and %v diverge; %v", block, tx, p.runs[0].name, p.runs[1].name, strings.Join(diffs, "; "))
+	if dumpErr := p.dump(block, tx, st, summaries); dumpErr != nil {
+		return errors.Join(err, fmt.Errorf("cannot write cross-check results; %w", dumpErr))
+	}
+	return err
+}
+
+// dump writes the summaries and, with --vm-trace, the structured logs of both executions into --cross-check-dir.
+func (p *evmCrossCheckProcessor) dump(block int, tx int, st txcontext.TxContext, summaries [2]*executionSummary) error {
+	if err := os.MkdirAll(p.cfg.CrossCheckDir, 0755); err != nil {
+		return err
+	}
+	for i, side := range crossCheckSides {
+		prefix := filepath.Join(p.cfg.CrossCheckDir, fmt.Sprintf("%d_%d_%s", block, tx, side))
+		data, err := json.MarshalIndent(summaries[i], "", "  ")
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(prefix+".json", data, 0644); err != nil {
+			return err
+		}
+		if p.cfg.VmTrace {
+			if err = p.trace(p.runs[i], prefix+".trace.jsonl", block, tx, st); err != nil {
+				return err
+			}
+		}
+	}
+	p.log.Warningf("Block %v transaction %v diverges; execution summaries are written to %v", block, tx, p.cfg.CrossCheckDir)
+	return nil
+}
+
+// trace re-executes the transaction with a structured logger writing to the given file. Only the processors
+// of Aida support the tracer of the vm config, the interpreters of Tosca are not traced.
+func (p *evmCrossCheckProcessor) trace(run crossCheckRun, path string, block int, tx int, st txcontext.TxContext) (err error) {
+	if !isAidaEvmImpl(run.cfg.EvmImpl) {
+		p.log.Warningf("Structured logs are not supported by %v; %v is not written", run.name, path)
+		return nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, file.Close())
+	}()
+	output := bufio.NewWriter(file)
+
+	c := *run.cfg
+	c.VmCfg.Tracer = gethlogger.NewJSONLogger(nil, output)
+	processor, err := MakeTxProcessor(&c)
+	if err != nil {
+		return err
+	}
+	// the outcome is part of the summary already
+	run.execute(processor, block, tx, st, nil)
+	return output.Flush()
+}
+
+// isAidaEvmImpl reports whether the given EVM implementation is executed by the processor of Aida.
+func isAidaEvmImpl(evmImpl string) bool {
+	switch strings.ToLower(evmImpl) {
+	case "", "opera", "ethereum":
+		return true
+	default:
+		return false
+	}
+}
+
+// execute processes the transaction on an in-memory copy of its input alloc and summarizes the outcome.
+func (r crossCheckRun) execute(processor *TxProcessor, block int, tx int, st txcontext.TxContext, accounts []common.Address) *executionSummary {
+	db := state.MakeInMemoryStateDB(st.GetInputState(), uint64(block))
+	res, err := processor.ProcessTransaction(db, block, tx, st)
+
+	s := &executionSummary{
+		Implementation: r.name,
+		Balances:       make(map[common.Address]*uint256.Int, len(accounts)),
+	}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	if res != nil {
+		s.GasUsed = res.GetGasUsed()
+		s.ReturnData, _ = res.GetRawResult()
+		if receipt := res.GetReceipt(); receipt != nil {
+			s.Status = receipt.GetStatus()
+			s.Logs = receipt.GetLogs()
+			if reporter, ok := receipt.(txcontext.VmErrorReporter); ok && reporter.GetVmError() != nil {
+				s.VmError = reporter.GetVmError().Error()
+			}
+		}
+	}
+	for _, addr := range accounts {
+		s.Balances[addr] = db.GetBalance(addr)
+	}
+	return s
+}
+
+// touchedAccounts returns the sorted accounts of the input and output alloc, the sender, the recipient and the coinbase.
+func touchedAccounts(st txcontext.TxContext) []common.Address {
+	touched := make(map[common.Address]struct{})
+	collect := func(addr common.Address, _ txcontext.Account) {
+		touched[addr] = struct{}{}
+	}
+	st.GetInputState().ForEachAccount(collect)
+	st.GetOutputState().ForEachAccount(collect)
+
+	msg := st.GetMessage()
+	touched[msg.From] = struct{}{}
+	if msg.To != nil {
+		touched[*msg.To] = struct{}{}
+	}
+	touched[st.GetBlockEnvironment().GetCoinbase()] = struct{}{}
+
+	accounts := maps.Keys(touched)
+	slices.SortFunc(accounts, func(a, b common.Address) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return accounts
+}
+
+// executionSummary is the outcome of a transaction executed by one of the cross-checked implementations.
+type executionSummary struct {
+	Implementation string                          `json:"implementation"`
+	Error          string                          `json:"error,omitempty"`
+	Status         uint64                          `json:"status"`
+	VmError        string                          `json:"vmError,omitempty"`
+	GasUsed        uint64                          `json:"gasUsed"`
+	ReturnData     hexutil.Bytes                   `json:"returnData"`
+	Logs           []*types.Log                    `json:"logs"`
+	Balances       map[common.Address]*uint256.Int `json:"balances"`
+}
+
+// diff lists the differences of two summaries of the same transaction.
+func (s *executionSummary) diff(o *executionSummary, accounts []common.Address) []string {
+	var diffs []string
+	if s.Error != o.Error {
+		diffs = append(diffs, fmt.Sprintf("error %q vs %q", s.Error, o.Error))
+	}
+	if s.Status != o.Status {
+		diffs = append(diffs, fmt.Sprintf("status %d vs %d", s.Status, o.Status))
+	}
+	if s.VmError != o.VmError {
+		diffs = append(diffs, fmt.Sprintf("vm error %q vs %q", s.VmError, o.VmError))
+	}
+	if s.GasUsed != o.GasUsed {
+		diffs = append(diffs, fmt.Sprintf("gas used %d vs %d", s.GasUsed, o.GasUsed))
+	}
+	if !bytes.Equal(s.ReturnData, o.ReturnData) {
+		diffs = append(diffs, fmt.Sprintf("return data %v vs %v", s.ReturnData, o.ReturnData))
+	}
+	if len(s.Logs) != len(o.Logs) {
+		diffs = append(diffs, fmt.Sprintf("%d logs vs %d logs", len(s.Logs), len(o.Logs)))
+	} else {
+		for i := range s.Logs {
+			if !logsEqual(s.Logs[i], o.Logs[i]) {
+				diffs = append(diffs, fmt.Sprintf("log %d differs", i))
+				break
+			}
+		}
+	}
+	for _, addr := range accounts {
+		if s.Balances[addr].Cmp(o.Balances[addr]) != 0 {
+			diffs = append(diffs, fmt.Sprintf("balance of %v %v vs %v", addr, s.Balances[addr], o.Balances[addr]))
+		}
+	}
+	return diffs
+}
+
+// logsEqual compares the parts of the logs produced by the execution.
+func logsEqual(a, b *types.Log) bool {
+	return a.Address == b.Address && slices.Equal(a.Topics, b.Topics) && bytes.Equal(a.Data, b.Data)
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	substatecontext "github.com/0xsoniclabs/aida/txcontext/substate"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMakeEvmCrossCheckProcessor_ReturnsLiveDbProcessorWithoutCrossCheck(t *testing.T) {
+	p, err := MakeEvmCrossCheckProcessor(&utils.Config{EvmImpl: "opera"})
+	require.NoError(t, err)
+	assert.IsType(t, &LiveDbTxProcessor{}, p)
+}
+
+func TestMakeEvmCrossCheckProcessor_SelectsBothImplementations(t *testing.T) {
+	p, err := MakeEvmCrossCheckProcessor(&utils.Config{EvmImpl: "opera", VmImpl: "geth", CrossCheckEvm: "ethereum"})
+	require.NoError(t, err)
+	require.IsType(t, &evmCrossCheckProcessor{}, p)
+	runs := p.(*evmCrossCheckProcessor).runs
+	assert.Equal(t, "opera:geth", runs[0].name)
+	assert.Equal(t, "ethereum:geth", runs[1].name)
+	assert.Equal(t, "ethereum", runs[1].cfg.EvmImpl)
+}
+
+func TestMakeEvmCrossCheckProcessor_InvalidImplementationCausesError(t *testing.T) {
+	_, err := MakeEvmCrossCheckProcessor(&utils.Config{EvmImpl: "opera", CrossCheckEvm: "floria:invalid"})
+	require.ErrorContains(t, err, "cannot create processor for --cross-check-evm")
+}
+
+// makeTestCrossCheckProcessor creates a cross-check processor whose processors are mocked.
+func makeTestCrossCheckProcessor(ctrl *gomock.Controller, cfg *utils.Config) (*evmCrossCheckProcessor, [3]*Mockprocessor) {
+	var mocks [3]*Mockprocessor
+	var processors [3]*TxProcessor
+	for i := range mocks {
+		mocks[i] = NewMockprocessor(ctrl)
+		processors[i] = &TxProcessor{
+			cfg:       cfg,
+			numErrors: new(atomic.Int32),
+			processor: mocks[i],
+			log:       logger.NewLogger("critical", "test"),
+		}
+	}
+	return &evmCrossCheckProcessor{
+		LiveDbTxProcessor: &LiveDbTxProcessor{processors[2]},
+		cfg:               cfg,
+		log:               logger.NewLogger("critical", "test"),
+		runs: [2]crossCheckRun{
+			{name: "opera:geth", cfg: cfg, processor: processors[0]},
+			{name: "floria:lfvm", cfg: cfg, processor: processors[1]},
+		},
+	}, mocks
+}
+
+func TestEvmCrossCheckProcessor_EqualResultsAreAccepted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	cfg := &utils.Config{CrossCheckDir: t.TempDir()}
+	p, mocks := makeTestCrossCheckProcessor(ctrl, cfg)
+	st := substatecontext.NewTxContext(utils.GetTestSubstate("default"))
+
+	res := transactionResult{gasUsed: 21000, status: types.ReceiptStatusSuccessful}
+	mocks[0].EXPECT().processRegularTx(gomock.Not(db), 1, 2, st).Return(res, nil)
+	mocks[1].EXPECT().processRegularTx(gomock.Not(db), 1, 2, st).Return(res, nil)
+	mocks[2].EXPECT().processRegularTx(db, 1, 2, st).Return(res, nil)
+
+	ctx := &Context{State: db}
+	require.NoError(t, p.Process(State[txcontext.TxContext]{Block: 1, Transaction: 2, Data: st}, ctx))
+	assert.Equal(t, res, ctx.ExecutionResult)
+
+	files, err := os.ReadDir(cfg.CrossCheckDir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestEvmCrossCheckProcessor_DivergentResultsAreReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	cfg := &utils.Config{CrossCheckDir: filepath.Join(t.TempDir(), "out")}
+	p, mocks := makeTestCrossCheckProcessor(ctrl, cfg)
+	st := substatecontext.NewTxContext(utils.GetTestSubstate("default"))
+
+	log := &types.Log{Address: common.Address{1}, Data: []byte{1}}
+	mocks[0].EXPECT().processRegularTx(gomock.Any(), 1, 2, st).Return(transactionResult{gasUsed: 21000, result: []byte{1}, logs: []*types.Log{log}}, nil)
+	mocks[1].EXPECT().processRegularTx(gomock.Any(), 1, 2, st).Return(transactionResult{gasUsed: 22000, result: []byte{2}, logs: []*types.Log{{Address: common.Address{2}}}}, nil)
+
+	err := p.Process(State[txcontext.TxContext]{Block: 1, Transaction: 2, Data: st}, &Context{State: db})
+	require.ErrorContains(t, err, "opera:geth and floria:lfvm diverge")
+	require.ErrorContains(t, err, "gas used 21000 vs 22000")
+	require.ErrorContains(t, err, "return data 0x01 vs 0x02")
+	require.ErrorContains(t, err, "log 0 differs")
+
+	for _, side := range []struct {
+		file    string
+		name    string
+		gasUsed uint64
+	}{
+		{"1_2_evm-impl.json", "opera:geth", 21000},
+		{"1_2_cross-check-evm.json", "floria:lfvm", 22000},
+	} {
+		data, err := os.ReadFile(filepath.Join(cfg.CrossCheckDir, side.file))
+		require.NoError(t, err)
+		// logs are not decoded since their JSON decoding requires fields not set by the test
+		var summary struct {
+			Implementation string
+			GasUsed        uint64
+			Balances       map[common.Address]string
+		}
+		require.NoError(t, json.Unmarshal(data, &summary))
+		assert.Equal(t, side.name, summary.Implementation)
+		assert.Equal(t, side.gasUsed, summary.GasUsed)
+		assert.Contains(t, summary.Balances, common.Address{1})
+	}
+}
+
+func TestEvmCrossCheckProcessor_DivergenceIsForwardedWithContinueOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	cfg := &utils.Config{CrossCheckDir: t.TempDir(), ContinueOnFailure: true}
+	p, mocks := makeTestCrossCheckProcessor(ctrl, cfg)
+	st := substatecontext.NewTxContext(utils.GetTestSubstate("default"))
+
+	mocks[0].EXPECT().processRegularTx(gomock.Any(), 1, 2, st).Return(transactionResult{status: types.ReceiptStatusSuccessful}, nil)
+	mocks[1].EXPECT().processRegularTx(gomock.Any(), 1, 2, st).Return(transactionResult{status: types.ReceiptStatusFailed}, nil)
+	mocks[2].EXPECT().processRegularTx(db, 1, 2, st).Return(transactionResult{}, nil)
+
+	ctx := &Context{State: db, ErrorInput: make(chan error, 1)}
+	require.NoError(t, p.Process(State[txcontext.TxContext]{Block: 1, Transaction: 2, Data: st}, ctx))
+
+	err := <-ctx.ErrorInput
+	require.ErrorIs(t, err, ErrEvmDivergence)
+	require.ErrorContains(t, err, "status 1 vs 0")
+	var txErr *TxError
+	require.True(t, errors.As(err, &txErr))
+	assert.Equal(t, ProcessorComponent, txErr.Component)
+}
+
+func TestEvmCrossCheckProcessor_PseudoTransactionsAreNotCrossChecked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	p, _ := makeTestCrossCheckProcessor(ctrl, &utils.Config{})
+	st := txcontext.NewMockTxContext(ctrl)
+	ws := txcontext.NewMockWorldState(ctrl)
+
+	st.EXPECT().GetOutputState().Return(ws)
+	ws.EXPECT().ForEachAccount(gomock.Any())
+
+	require.NoError(t, p.Process(State[txcontext.TxContext]{Block: 1, Transaction: utils.PseudoTx, Data: st}, &Context{State: db}))
+}
+
+func TestEvmCrossCheckProcessor_VmTraceWritesStructuredLogsOfAidaProcessors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	cfg := &utils.Config{CrossCheckDir: t.TempDir(), VmTrace: true, ChainID: utils.SonicMainnetChainID, EvmImpl: "opera"}
+	p, mocks := makeTestCrossCheckProcessor(ctrl, cfg)
+	p.runs[1].cfg = &utils.Config{ChainID: utils.SonicMainnetChainID, EvmImpl: "floria", VmImpl: "lfvm"}
+	ss := utils.GetTestSubstate("default")
+	st := substatecontext.NewTxContext(ss)
+
+	mocks[0].EXPECT().processRegularTx(gomock.Any(), int(ss.Block), 1, st).Return(transactionResult{gasUsed: 1}, nil)
+	mocks[1].EXPECT().processRegularTx(gomock.Any(), int(ss.Block), 1, st).Return(transactionResult{gasUsed: 2}, nil)
+
+	err := p.Process(State[txcontext.TxContext]{Block: int(ss.Block), Transaction: 1, Data: st}, &Context{State: db})
+	require.ErrorContains(t, err, "gas used 1 vs 2")
+
+	assert.FileExists(t, filepath.Join(cfg.CrossCheckDir, "37534834_1_evm-impl.trace.jsonl"))
+	// the tracer is not supported by the processors of tosca
+	assert.NoFileExists(t, filepath.Join(cfg.CrossCheckDir, "37534834_1_cross-check-evm.trace.jsonl"))
+}
@@ -19,6 +19,7 @@ package executor
 //go:generate mockgen -source executor.go -destination executor_mock.go -package executor
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"runtime/debug"
@@ -94,6 +95,9 @@ type Executor[T any] interface {
 	// PostXXX events are delivered in reverse order. If any of the extensions
 	// reports an error during processing of an event, the same event is still
 	// delivered to the remaining extensions before processing is aborted.
+	// If the run is interrupted, see Params.Interrupt, no further blocks are
+	// started, blocks in progress are finished and PostRun is delivered with
+	// ErrInterrupted, which is also the result of the run.
 	Run(params Params, processor Processor[T], extensions []Extension[T], aidaDb db.BaseDB) error
 }
 
@@ -117,6 +121,26 @@ const (
 	BlockLevel
 )
 
+// BlockPartitioning determines how blocks are distributed among workers running on block granularity.
+type BlockPartitioning string
+
+const (
+	InterleavedPartitioning BlockPartitioning = "interleaved" // each block is handed to the next idle worker
+	ContiguousPartitioning  BlockPartitioning = "contiguous"  // each worker processes its own contiguous sub-range of blocks
+)
+
+// ParseBlockPartitioning converts given name into a BlockPartitioning.
+func ParseBlockPartitioning(name string) (BlockPartitioning, error) {
+	switch partitioning := BlockPartitioning(name); partitioning {
+	case InterleavedPartitioning, ContiguousPartitioning:
+		return partitioning, nil
+	case "":
+		return InterleavedPartitioning, nil
+	default:
+		return "", fmt.Errorf("unknown worker partitioning %q; options: %q, %q", name, InterleavedPartitioning, ContiguousPartitioning)
+	}
+}
+
 // Params summarizes input parameters for a run of the executor.
 type Params struct {
 	// From is the beginning of the range of blocks to be processed (inclusive).
@@ -134,6 +158,13 @@ type Params struct {
 	NumWorkers int
 	// ParallelismGranularity determines whether parallelism is done on block or transaction level
 	ParallelismGranularity ParallelismGranularity
+	// Partitioning determines how blocks are distributed among workers if parallelism is
+	// done on block level. The default value is equal to InterleavedPartitioning.
+	Partitioning BlockPartitioning
+	// Interrupt is an optional context interrupting the run once it is done. If it
+	// is nil, the run is interrupted by SIGINT or SIGTERM, and a second signal
+	// terminates the process immediately.
+	Interrupt context.Context
 }
 
 // Processor is an interface for the entity to which an executor is feeding
@@ -165,7 +196,9 @@ type Extension[T any] interface {
 	// have been produced. In case of a successful execution, the provided
 	// state lists the first non-executiond block, while in an error case
 	// it references the last transaction attempted to be processed. Also,
-	// the second parameter contains the error causing the abort.
+	// the second parameter contains the error causing the abort. An
+	// interrupted execution ends with ErrInterrupted and the state lists
+	// the first block which was not processed.
 	PostRun(State[T], *Context, error) error
 
 	// PreBlock is called once before the begin of processing a block with
@@ -250,6 +283,15 @@ func (e *executor[T]) Run(params Params, processor Processor[T], extensions []Ex
 	state := State[T]{}
 	ctx := Context{State: params.State, AidaDb: aidaDb}
 
+	// the signals are handled until PostRun is finished, so that a second signal can terminate it
+	interrupt := params.Interrupt
+	if interrupt == nil {
+		var release func()
+		interrupt, release = notifyInterrupt(e.log)
+		defer release()
+	}
+	stop := newInterruption(interrupt)
+
 	defer func() {
 		// Skip PostRun actions if a panic occurred. In such a case there is no guarantee
 		// on the state of anything, and PostRun operations may deadlock or cause damage.
@@ -272,14 +314,29 @@ func (e *executor[T]) Run(params Params, processor Processor[T], extensions []Ex
 		params.NumWorkers = 1
 	}
 
+	var executed atomic.Uint64
 	switch params.ParallelismGranularity {
 	case TransactionLevel:
-		return e.runTransactions(params, processor, extensions, &state, &ctx)
+		err = e.runTransactions(params, processor, extensions, &state, &ctx, &executed, stop)
 	case BlockLevel:
-		return e.runBlocks(params, processor, extensions, &state, &ctx)
+		if params.Partitioning == ContiguousPartitioning {
+			err = e.runPartitionedBlocks(params, processor, extensions, &state, &ctx, &executed, stop)
+		} else {
+			err = e.runBlocks(params, processor, extensions, &state, &ctx, &executed, stop)
+		}
 	default:
 		return fmt.Errorf("incorrect parallelism type: %v", params.ParallelismGranularity)
 	}
+	if block, interrupted := stop.firstUnprocessedBlock(); err == nil && interrupted {
+		state.Block = block
+		e.log.Warningf("Run interrupted; %v transactions executed in blocks %v-%v", executed.Load(), params.From, block-1)
+		return ErrInterrupted
+	}
+	if err == nil {
+		// a range without any transactions is a valid run, the summary makes it explicit
+		e.log.Noticef("%v transactions executed in blocks %v-%v", executed.Load(), params.From, params.To-1)
+	}
+	return err
 }
 
 // runBlock runs transaction execution in a block
@@ -293,6 +350,8 @@ func runBlock[T any](
 	extensions []Extension[T],
 	ctx *Context,
 	cachedPanic *atomic.Value,
+	executed *atomic.Uint64,
+	admit func(block int) bool,
 ) {
 
 	// channel panics back to the main thread.
@@ -312,11 +371,21 @@ func runBlock[T any](
 			if len(blockTransactions) == 0 {
 				return // reached an end without abort
 			}
+			// blocks queued before an interruption are not started
+			if !admit(blockTransactions[0].Block) {
+				return
+			}
 
 			localState.Block = blockTransactions[0].Block
 			localState.Data = blockTransactions[0].Data
 			localCtx := *ctx
 
+			if err := decodePayload(localState.Block, blockTransactions[0].Transaction, localState.Data); err != nil {
+				workerErrs[workerNumber] = err
+				abort.Signal()
+				return
+			}
+
 			if err := signalPreBlock(localState, &localCtx, extensions); err != nil {
 				workerErrs[workerNumber] = err
 				abort.Signal()
@@ -332,6 +401,7 @@ func runBlock[T any](
 					abort.Signal()
 					return
 				}
+				executed.Add(1)
 
 				// listen for possible abort between the transactions
 				select {
@@ -354,7 +424,8 @@ func runBlock[T any](
 }
 
 // forwardBlocks is a worker that unites transactions by block and forwards them to execution.
-func (e *executor[T]) forwardBlocks(params Params, abort utils.Event) (chan []*TransactionInfo[T], *atomic.Pointer[error]) {
+// Once the run is interrupted, no further blocks are forwarded.
+func (e *executor[T]) forwardBlocks(params Params, abort utils.Event, stop *interruption) (chan []*TransactionInfo[T], *atomic.Pointer[error]) {
 	blocks := make(chan []*TransactionInfo[T], 10*params.NumWorkers)
 	forwardErr := new(atomic.Pointer[error])
 
@@ -366,6 +437,29 @@ func (e *executor[T]) forwardBlocks(params Params, abort utils.Event) (chan []*T
 		first := true
 
 		block := make([]*TransactionInfo[T], 0)
+
+		// forward sends the collected block to the workers unless the run is aborted or interrupted
+		forward := func() error {
+			var interrupted <-chan struct{}
+			if len(block) > 0 {
+				if stop.stopAt(block[0].Block) {
+					return abortErr
+				}
+				interrupted = stop.done
+			}
+			select {
+			case blocks <- block:
+				// clean block for reuse
+				block = make([]*TransactionInfo[T], 0)
+				return nil
+			case <-abort.Wait():
+				return abortErr
+			case <-interrupted:
+				stop.stopAt(block[0].Block)
+				return abortErr
+			}
+		}
+
 		err := e.provider.Run(params.From, params.To, func(tx TransactionInfo[T]) error {
 			if first {
 				previousBlock = tx.Block
@@ -374,12 +468,8 @@ func (e *executor[T]) forwardBlocks(params Params, abort utils.Event) (chan []*T
 
 			if tx.Block != previousBlock {
 				previousBlock = tx.Block
-				select {
-				case blocks <- block:
-					// clean block for reuse
-					block = make([]*TransactionInfo[T], 0)
-				case <-abort.Wait():
-					return abortErr
+				if err := forward(); err != nil {
+					return err
 				}
 			}
 
@@ -390,11 +480,7 @@ func (e *executor[T]) forwardBlocks(params Params, abort utils.Event) (chan []*T
 
 		// send last block to the queue
 		if err == nil {
-			select {
-			case blocks <- block:
-			case <-abort.Wait():
-				err = abortErr
-			}
+			err = forward()
 		}
 
 		if err != abortErr {
@@ -405,7 +491,7 @@ func (e *executor[T]) forwardBlocks(params Params, abort utils.Event) (chan []*T
 	return blocks, forwardErr
 }
 
-func (e *executor[T]) runTransactions(params Params, processor Processor[T], extensions []Extension[T], state *State[T], ctx *Context) error {
+func (e *executor[T]) runTransactions(params Params, processor Processor[T], extensions []Extension[T], state *State[T], ctx *Context, executed *atomic.Uint64, stop *interruption) error {
 	numWorkers := params.NumWorkers
 
 	// An event for signaling an abort of the execution.
@@ -422,7 +508,15 @@ func (e *executor[T]) runTransactions(params Params, processor Processor[T], ext
 			wg.Done()
 		}()
 		abortErr := errors.New("aborted")
+		previousBlock := -1
 		err := e.provider.Run(params.From, params.To, func(tx TransactionInfo[T]) error {
+			// once the run is interrupted, transactions of the current block are still forwarded
+			if tx.Block != previousBlock {
+				if stop.stopAt(tx.Block) {
+					return abortErr
+				}
+				previousBlock = tx.Block
+			}
 			select {
 			case transactions <- &tx:
 				return nil
@@ -458,6 +552,10 @@ func (e *executor[T]) runTransactions(params Params, processor Processor[T], ext
 					if tx == nil {
 						return // reached an end without abort
 					}
+					// transactions queued before an interruption are skipped unless their block is in progress
+					if !stop.admit(tx.Block) {
+						continue
+					}
 					localState := *state
 					localState.Block = tx.Block
 					localState.Transaction = tx.Transaction
@@ -467,6 +565,7 @@ func (e *executor[T]) runTransactions(params Params, processor Processor[T], ext
 						abort.Signal()
 						return
 					}
+					executed.Add(1)
 				case <-abort.Wait():
 					return
 				}
@@ -490,7 +589,20 @@ func (e *executor[T]) runTransactions(params Params, processor Processor[T], ext
 	return err
 }
 
+// decodePayload decodes payloads provided in encoded form, see LazyPayload.
+func decodePayload[T any](block int, tx int, data T) error {
+	if lazy, ok := any(data).(LazyPayload); ok {
+		if err := lazy.Decode(); err != nil {
+			return fmt.Errorf("cannot decode payload of block %v tx %v; %w", block, tx, err)
+		}
+	}
+	return nil
+}
+
 func runTransaction[T any](state State[T], ctx *Context, data T, processor Processor[T], extensions []Extension[T]) error {
+	if err := decodePayload(state.Block, state.Transaction, data); err != nil {
+		return err
+	}
 	state.Data = data
 	if err := signalPreTransaction(state, ctx, extensions); err != nil {
 		return err
@@ -503,14 +615,14 @@ func runTransaction[T any](state State[T], ctx *Context, data T, processor Proce
 	}
 	return nil
 }
-func (e *executor[T]) runBlocks(params Params, processor Processor[T], extensions []Extension[T], state *State[T], ctx *Context) error {
+func (e *executor[T]) runBlocks(params Params, processor Processor[T], extensions []Extension[T], state *State[T], ctx *Context, executed *atomic.Uint64, stop *interruption) error {
 	numWorkers := params.NumWorkers
 
 	// An event for signaling an abort of the execution.
 	abort := utils.MakeEvent()
 
 	// Start one go-routine forwarding blocks from the provider to a local channel.
-	blocks, forwardErr := e.forwardBlocks(params, abort)
+	blocks, forwardErr := e.forwardBlocks(params, abort, stop)
 
 	// Start numWorkers go-routines processing blocks in parallel.
 	wg := new(sync.WaitGroup)
@@ -521,7 +633,7 @@ func (e *executor[T]) runBlocks(params Params, processor Processor[T], extension
 	wg.Add(numWorkers)
 	e.log.Debugf("Starting %v workers run on Block granularity...", numWorkers)
 	for i := 0; i < numWorkers; i++ {
-		go runBlock(i, blocks, wg, abort, workerErrs, processor, extensions, ctx, cachedPanic)
+		go runBlock(i, blocks, wg, abort, workerErrs, processor, extensions, ctx, cachedPanic, executed, stop.admit)
 	}
 
 	wg.Wait()
@@ -542,6 +654,83 @@ func (e *executor[T]) runBlocks(params Params, processor Processor[T], extension
 	return err
 }
 
+// runPartitionedBlocks splits the block range into one contiguous partition per worker. Each
+// worker reads and processes the blocks of its own partition only, hence workers never
+// access the same blocks at the same time.
+func (e *executor[T]) runPartitionedBlocks(params Params, processor Processor[T], extensions []Extension[T], state *State[T], ctx *Context, executed *atomic.Uint64, stop *interruption) error {
+	partitions := partitionBlockRange(params.From, params.To, params.NumWorkers)
+
+	// An event for signaling an abort of the execution.
+	abort := utils.MakeEvent()
+
+	wg := new(sync.WaitGroup)
+	workerErrs := make([]error, len(partitions))
+	forwardErrs := make([]*atomic.Pointer[error], len(partitions))
+
+	cachedPanic := new(atomic.Value)
+
+	wg.Add(len(partitions))
+	e.log.Debugf("Starting %v workers run on contiguous Block partitions...", len(partitions))
+	for i, partition := range partitions {
+		partitionParams := params
+		partitionParams.From, partitionParams.To = partition.from, partition.to
+
+		var blocks chan []*TransactionInfo[T]
+		blocks, forwardErrs[i] = e.forwardBlocks(partitionParams, abort, stop)
+		// each partition is processed in order by a single worker, so the progress of other partitions is irrelevant
+		admit := func(block int) bool {
+			return !stop.stopAt(block)
+		}
+		go runBlock(i, blocks, wg, abort, workerErrs, processor, extensions, ctx, cachedPanic, executed, admit)
+	}
+
+	wg.Wait()
+
+	if r := cachedPanic.Load(); r != nil {
+		panic(r)
+	}
+
+	err := errors.Join(workerErrs...)
+	// append errors from blocks forwarding if there are any
+	for _, forwardErr := range forwardErrs {
+		if errPtr := forwardErr.Load(); errPtr != nil {
+			err = errors.Join(err, *errPtr)
+		}
+	}
+
+	if err == nil {
+		state.Block = params.To
+	}
+	return err
+}
+
+// blockPartition is a range of blocks [from,to) processed by a single worker.
+type blockPartition struct {
+	from, to int
+}
+
+// partitionBlockRange splits the block range [from,to) into n contiguous partitions
+// whose sizes differ by at most one block.
+func partitionBlockRange(from, to, n int) []blockPartition {
+	if n < 1 {
+		n = 1
+	}
+	if to < from {
+		to = from
+	}
+	size, rest := (to-from)/n, (to-from)%n
+	partitions := make([]blockPartition, n)
+	for i := range partitions {
+		partitions[i].from = from
+		from += size
+		if i < rest {
+			from++
+		}
+		partitions[i].to = from
+	}
+	return partitions
+}
+
 func RunUtilPrimer[T any](params Params, extensions []Extension[T], aidaDb db.BaseDB) (err error) {
 	state := State[T]{}
 	ctx := Context{State: params.State, AidaDb: aidaDb}
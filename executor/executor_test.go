@@ -17,10 +17,12 @@
 package executor
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/0xsoniclabs/aida/logger"
@@ -1791,6 +1793,7 @@ func TestProcessor_PanicCaughtInPostRunIsReturned_TransactionLevelParallelism(t
 		extension.EXPECT().PreTransaction(gomock.Any(), gomock.Any()),
 		processor.EXPECT().Process(gomock.Any(), gomock.Any()),
 		extension.EXPECT().PostTransaction(gomock.Any(), gomock.Any()),
+		log.EXPECT().Noticef("%v transactions executed in blocks %v-%v", uint64(1), 1, 1),
 		extension.EXPECT().PostRun(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(any, any, any) {
 			panic("stop")
 		}),
@@ -1837,6 +1840,7 @@ func TestProcessor_PanicCaughtInPostRunIsReturned_BlockLevelParallelism(t *testi
 		processor.EXPECT().Process(gomock.Any(), gomock.Any()),
 		extension.EXPECT().PostTransaction(gomock.Any(), gomock.Any()),
 		extension.EXPECT().PostBlock(gomock.Any(), gomock.Any()),
+		log.EXPECT().Noticef("%v transactions executed in blocks %v-%v", uint64(1), 1, 1),
 		extension.EXPECT().PostRun(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(any, any, any) {
 			panic("stop")
 		}),
@@ -1883,3 +1887,370 @@ func TestExecutor_RecoverPanicStack(t *testing.T) {
 	err := signalPreRun(State[any]{}, nil, []Extension[any]{extension})
 	assert.NoError(t, err)
 }
+
+func TestProcessor_EmptyRangeReportsZeroExecutedTransactions(t *testing.T) {
+	for _, granularity := range []ParallelismGranularity{TransactionLevel, BlockLevel} {
+		ctrl := gomock.NewController(t)
+		provider := NewMockProvider[any](ctrl)
+		processor := NewMockProcessor[any](ctrl)
+		extension := NewMockExtension[any](ctrl)
+		log := logger.NewMockLogger(ctrl)
+
+		provider.EXPECT().Run(10, 12, gomock.Any()).Return(nil)
+		gomock.InOrder(
+			extension.EXPECT().PreRun(AtBlock[any](10), gomock.Any()),
+			log.EXPECT().Debugf(gomock.Any(), gomock.Any()),
+			log.EXPECT().Noticef("%v transactions executed in blocks %v-%v", uint64(0), 10, 11),
+			extension.EXPECT().PostRun(AtBlock[any](12), gomock.Any(), nil),
+		)
+
+		err := newExecutor[any](provider, log).Run(
+			Params{From: 10, To: 12, NumWorkers: 2, ParallelismGranularity: granularity},
+			processor,
+			[]Extension[any]{extension},
+			nil,
+		)
+		assert.NoError(t, err)
+	}
+}
+
+func TestProcessor_ContiguousPartitioningRunsEachWorkerOnItsOwnBlockRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[any](ctrl)
+	processor := NewMockProcessor[any](ctrl)
+
+	var (
+		mutex     sync.Mutex
+		processed = map[int]int{}
+	)
+	for _, partition := range []blockPartition{{10, 13}, {13, 16}, {16, 18}} {
+		provider.EXPECT().
+			Run(partition.from, partition.to, gomock.Any()).
+			DoAndReturn(func(from int, to int, consume Consumer[any]) error {
+				for i := from; i < to; i++ {
+					if err := consume(TransactionInfo[any]{i, 0, nil}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+	}
+	processor.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(state State[any], _ *Context) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		processed[state.Block]++
+		return nil
+	}).Times(8)
+
+	executor := NewExecutor[any](provider, "DEBUG")
+	params := Params{From: 10, To: 18, NumWorkers: 3, ParallelismGranularity: BlockLevel, Partitioning: ContiguousPartitioning}
+	if err := executor.Run(params, processor, nil, nil); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	for block := 10; block < 18; block++ {
+		assert.Equal(t, 1, processed[block], "block %v", block)
+	}
+}
+
+func TestProcessor_ContiguousPartitioningStopsAllWorkersOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[any](ctrl)
+	processor := NewMockProcessor[any](ctrl)
+
+	provider.EXPECT().
+		Run(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(from int, to int, consume Consumer[any]) error {
+			for i := from; i < to; i++ {
+				if err := consume(TransactionInfo[any]{i, 0, nil}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).AnyTimes()
+
+	stop := fmt.Errorf("stop!")
+	processor.EXPECT().Process(gomock.Any(), gomock.Any()).Return(stop).MinTimes(1).MaxTimes(2)
+
+	executor := NewExecutor[any](provider, "DEBUG")
+	params := Params{From: 10, To: 20, NumWorkers: 2, ParallelismGranularity: BlockLevel, Partitioning: ContiguousPartitioning}
+	if got, want := executor.Run(params, processor, nil, nil), stop; !errors.Is(got, want) {
+		t.Errorf("execution did not produce expected error, wanted %v, got %v", want, got)
+	}
+}
+
+func TestPartitionBlockRange(t *testing.T) {
+	tests := map[string]struct {
+		from, to, n int
+		want        []blockPartition
+	}{
+		"even":         {0, 6, 3, []blockPartition{{0, 2}, {2, 4}, {4, 6}}},
+		"uneven":       {10, 18, 3, []blockPartition{{10, 13}, {13, 16}, {16, 18}}},
+		"single":       {5, 9, 1, []blockPartition{{5, 9}}},
+		"more workers": {5, 7, 3, []blockPartition{{5, 6}, {6, 7}, {7, 7}}},
+		"empty":        {5, 5, 2, []blockPartition{{5, 5}, {5, 5}}},
+		"no workers":   {5, 9, 0, []blockPartition{{5, 9}}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, partitionBlockRange(test.from, test.to, test.n))
+		})
+	}
+}
+
+func TestParseBlockPartitioning(t *testing.T) {
+	tests := map[string]struct {
+		want    BlockPartitioning
+		wantErr bool
+	}{
+		"":            {want: InterleavedPartitioning},
+		"interleaved": {want: InterleavedPartitioning},
+		"contiguous":  {want: ContiguousPartitioning},
+		"random":      {wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseBlockPartitioning(name)
+			if test.wantErr {
+				assert.ErrorContains(t, err, "unknown worker partitioning")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+// testLazyPayload is a LazyPayload recording whether it has been decoded.
+type testLazyPayload struct {
+	decoded atomic.Bool
+	err     error
+}
+
+func (p *testLazyPayload) Decode() error {
+	p.decoded.Store(true)
+	return p.err
+}
+
+func TestProcessor_LazyPayloadIsDecodedBeforeProcessing(t *testing.T) {
+	for _, granularity := range []ParallelismGranularity{TransactionLevel, BlockLevel} {
+		t.Run(fmt.Sprintf("granularity_%v", granularity), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			provider := NewMockProvider[*testLazyPayload](ctrl)
+			processor := NewMockProcessor[*testLazyPayload](ctrl)
+
+			payload := &testLazyPayload{}
+			provider.EXPECT().
+				Run(10, 11, gomock.Any()).
+				DoAndReturn(func(from int, to int, consume Consumer[*testLazyPayload]) error {
+					return consume(TransactionInfo[*testLazyPayload]{from, 7, payload})
+				})
+			processor.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(state State[*testLazyPayload], ctx *Context) error {
+				assert.True(t, state.Data.decoded.Load(), "payload was not decoded before processing")
+				return nil
+			})
+
+			err := NewExecutor[*testLazyPayload](provider, "DEBUG").Run(
+				Params{From: 10, To: 11, NumWorkers: 2, ParallelismGranularity: granularity},
+				processor,
+				nil,
+				nil,
+			)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProcessor_LazyPayloadDecodingErrorAbortsProcessing(t *testing.T) {
+	for _, granularity := range []ParallelismGranularity{TransactionLevel, BlockLevel} {
+		t.Run(fmt.Sprintf("granularity_%v", granularity), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			provider := NewMockProvider[*testLazyPayload](ctrl)
+			processor := NewMockProcessor[*testLazyPayload](ctrl)
+
+			injectedErr := errors.New("corrupted payload")
+			provider.EXPECT().
+				Run(10, 11, gomock.Any()).
+				DoAndReturn(func(from int, to int, consume Consumer[*testLazyPayload]) error {
+					return consume(TransactionInfo[*testLazyPayload]{from, 7, &testLazyPayload{err: injectedErr}})
+				})
+
+			err := NewExecutor[*testLazyPayload](provider, "DEBUG").Run(
+				Params{From: 10, To: 11, NumWorkers: 2, ParallelismGranularity: granularity},
+				processor,
+				nil,
+				nil,
+			)
+			assert.ErrorIs(t, err, injectedErr)
+			assert.ErrorContains(t, err, "cannot decode payload of block 10 tx 7")
+		})
+	}
+}
+
+// provideBlocks returns a provider run producing the given number of transactions per block.
+func provideBlocks(transactions int) func(int, int, Consumer[any]) error {
+	return func(from int, to int, consume Consumer[any]) error {
+		for block := from; block < to; block++ {
+			for tx := 0; tx < transactions; tx++ {
+				if err := consume(TransactionInfo[any]{block, tx, nil}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// runInterrupted runs blocks 10-29 and interrupts the run during the first transaction of block 12.
+// It returns the number of processed transactions per block, the state reported to PostRun and
+// the result of the run, which is also checked to be reported to PostRun.
+func runInterrupted(t *testing.T, params Params) (map[int]int, State[any], error) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[any](ctrl)
+	processor := NewMockProcessor[any](ctrl)
+	extension := NewMockExtension[any](ctrl)
+
+	interrupt, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	params.From, params.To, params.Interrupt = 10, 30, interrupt
+
+	var (
+		mutex     sync.Mutex
+		processed = map[int]int{}
+		last      State[any]
+	)
+	provider.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(provideBlocks(3)).AnyTimes()
+	processor.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(state State[any], _ *Context) error {
+		mutex.Lock()
+		processed[state.Block]++
+		mutex.Unlock()
+		if state.Block == 12 && state.Transaction == 0 {
+			cancel()
+		}
+		return nil
+	}).AnyTimes()
+	extension.EXPECT().PreRun(AtBlock[any](10), gomock.Any())
+	extension.EXPECT().PreBlock(gomock.Any(), gomock.Any()).AnyTimes()
+	extension.EXPECT().PostBlock(gomock.Any(), gomock.Any()).AnyTimes()
+	extension.EXPECT().PreTransaction(gomock.Any(), gomock.Any()).AnyTimes()
+	extension.EXPECT().PostTransaction(gomock.Any(), gomock.Any()).AnyTimes()
+	var postRunErr error
+	extension.EXPECT().PostRun(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(state State[any], _ *Context, err error) {
+		last, postRunErr = state, err
+	})
+
+	err := NewExecutor[any](provider, "critical").Run(params, processor, []Extension[any]{extension}, nil)
+	if postRunErr == nil {
+		assert.NoError(t, err)
+	} else {
+		assert.ErrorIs(t, err, postRunErr)
+	}
+	return processed, last, err
+}
+
+func TestProcessor_InterruptFinishesBlockInProgress(t *testing.T) {
+	tests := map[string]Params{
+		"transaction level":     {NumWorkers: 1, ParallelismGranularity: TransactionLevel},
+		"block level":           {NumWorkers: 1, ParallelismGranularity: BlockLevel},
+		"contiguous partitions": {NumWorkers: 1, ParallelismGranularity: BlockLevel, Partitioning: ContiguousPartitioning},
+	}
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			processed, last, err := runInterrupted(t, params)
+			assert.ErrorIs(t, err, ErrInterrupted)
+			assert.Equal(t, map[int]int{10: 3, 11: 3, 12: 3}, processed)
+			assert.Equal(t, 13, last.Block)
+		})
+	}
+}
+
+func TestProcessor_InterruptOfParallelRunFinishesStartedBlocks(t *testing.T) {
+	tests := map[string]Params{
+		"transaction level":     {NumWorkers: 4, ParallelismGranularity: TransactionLevel},
+		"block level":           {NumWorkers: 4, ParallelismGranularity: BlockLevel},
+		"contiguous partitions": {NumWorkers: 4, ParallelismGranularity: BlockLevel, Partitioning: ContiguousPartitioning},
+	}
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			processed, last, err := runInterrupted(t, params)
+			// other workers may have started all remaining blocks before the interruption
+			if err == nil {
+				assert.Equal(t, 30, last.Block)
+			} else {
+				assert.ErrorIs(t, err, ErrInterrupted)
+			}
+			assert.Greater(t, last.Block, 12)
+			for block, transactions := range processed {
+				assert.Equal(t, 3, transactions, "block %d is incomplete", block)
+			}
+			if params.Partitioning != ContiguousPartitioning {
+				// blocks are started in order, hence all blocks before the reported one are processed
+				for block := 10; block < last.Block; block++ {
+					assert.Equal(t, 3, processed[block], "block %d", block)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessor_InterruptBeforeRunProcessesNoTransaction(t *testing.T) {
+	for _, granularity := range []ParallelismGranularity{TransactionLevel, BlockLevel} {
+		t.Run(fmt.Sprintf("granularity_%v", granularity), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			provider := NewMockProvider[any](ctrl)
+			processor := NewMockProcessor[any](ctrl)
+			extension := NewMockExtension[any](ctrl)
+
+			interrupt, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			provider.EXPECT().Run(10, 20, gomock.Any()).DoAndReturn(provideBlocks(2))
+			gomock.InOrder(
+				extension.EXPECT().PreRun(AtBlock[any](10), gomock.Any()),
+				extension.EXPECT().PostRun(AtBlock[any](10), gomock.Any(), ErrInterrupted),
+			)
+
+			err := NewExecutor[any](provider, "critical").Run(
+				Params{From: 10, To: 20, NumWorkers: 2, ParallelismGranularity: granularity, Interrupt: interrupt},
+				processor,
+				[]Extension[any]{extension},
+				nil,
+			)
+			assert.ErrorIs(t, err, ErrInterrupted)
+		})
+	}
+}
+
+func TestProcessor_InterruptAfterLastBlockCompletesRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[any](ctrl)
+	processor := NewMockProcessor[any](ctrl)
+	extension := NewMockExtension[any](ctrl)
+
+	interrupt, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider.EXPECT().Run(10, 12, gomock.Any()).DoAndReturn(provideBlocks(1))
+	processor.EXPECT().Process(gomock.Any(), gomock.Any()).Times(2)
+	gomock.InOrder(
+		extension.EXPECT().PreRun(AtBlock[any](10), gomock.Any()),
+		extension.EXPECT().PreBlock(AtBlock[any](10), gomock.Any()),
+		extension.EXPECT().PreTransaction(AtTransaction[any](10, 0), gomock.Any()),
+		extension.EXPECT().PostTransaction(AtTransaction[any](10, 0), gomock.Any()),
+		extension.EXPECT().PostBlock(AtBlock[any](10), gomock.Any()),
+		extension.EXPECT().PreBlock(AtBlock[any](11), gomock.Any()),
+		extension.EXPECT().PreTransaction(AtTransaction[any](11, 0), gomock.Any()),
+		extension.EXPECT().PostTransaction(AtTransaction[any](11, 0), gomock.Any()),
+		extension.EXPECT().PostBlock(AtBlock[any](11), gomock.Any()).Do(func(State[any], *Context) {
+			cancel()
+		}),
+		extension.EXPECT().PostRun(AtBlock[any](12), gomock.Any(), nil),
+	)
+
+	err := NewExecutor[any](provider, "critical").Run(
+		Params{From: 10, To: 12, ParallelismGranularity: BlockLevel, Interrupt: interrupt},
+		processor,
+		[]Extension[any]{extension},
+		nil,
+	)
+	assert.NoError(t, err)
+}
@@ -19,7 +19,7 @@ package logger
 import (
 	"bufio"
 	"fmt"
-	"os"
+	"io"
 	"sync"
 
 	"github.com/0xsoniclabs/aida/executor"
@@ -27,6 +27,7 @@ import (
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/state/proxy"
 	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/aida/utils/tracefile"
 )
 
 const inputSize = 100
@@ -35,10 +36,11 @@ type dbLogger[T any] struct {
 	extension.NilExtension[T]
 	cfg    *utils.Config
 	log    logger.Logger
-	file   *os.File
+	file   io.WriteCloser
 	writer *bufio.Writer
 	input  chan string
 	wg     *sync.WaitGroup
+	opts   []proxy.LoggerOption
 }
 
 // MakeDbLogger creates an extensions which logs any Db transaction into a file and log level DEBUG
@@ -59,10 +61,18 @@ func makeDbLogger[T any](cfg *utils.Config, log logger.Logger) *dbLogger[T] {
 	}
 }
 
-// PreRun creates a logging file
+// PreRun creates a logging file, which is compressed if its path ends in .zst
 func (l *dbLogger[T]) PreRun(_ executor.State[T], ctx *executor.Context) error {
-	var err error
-	l.file, err = os.Create(l.cfg.DbLogging)
+	format, err := proxy.ParseLoggingFormat(l.cfg.DbLoggingFormat)
+	if err != nil {
+		return err
+	}
+	l.opts = []proxy.LoggerOption{
+		proxy.WithLoggingFormat(format),
+		proxy.WithOperationFilter(proxy.ParseOperationFilter(l.cfg.DbLoggingFilter)...),
+	}
+
+	l.file, err = tracefile.Create(l.cfg.DbLogging)
 	if err != nil {
 		return fmt.Errorf("cannot create db-logging file; %v", err)
 	}
@@ -74,7 +84,7 @@ func (l *dbLogger[T]) PreRun(_ executor.State[T], ctx *executor.Context) error {
 
 	// in some cases, StateDb does not have to be initialized yet
 	if ctx.State != nil {
-		ctx.State = proxy.NewLoggerProxy(ctx.State, l.log, l.input, l.wg)
+		ctx.State = proxy.NewLoggerProxy(ctx.State, l.log, l.input, l.wg, l.opts...)
 	}
 
 	return nil
@@ -88,7 +98,7 @@ func (l *dbLogger[T]) PreTransaction(_ executor.State[T], ctx *executor.Context)
 		return nil
 	}
 
-	ctx.State = proxy.NewLoggerProxy(ctx.State, l.log, l.input, l.wg)
+	ctx.State = proxy.NewLoggerProxy(ctx.State, l.log, l.input, l.wg, l.opts...)
 	return nil
 }
 
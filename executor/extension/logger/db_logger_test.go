@@ -17,13 +17,16 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/0xsoniclabs/aida/delta"
 	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/executor/extension"
 	"github.com/0xsoniclabs/aida/logger"
@@ -33,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -96,17 +100,18 @@ func TestDbLoggerExtension_LoggingHappens(t *testing.T) {
 	endTransaction := "EndTransaction"
 	endBlock := "EndBlock"
 
+	// operations are logged once they are finished to report their duration
 	gomock.InOrder(
-		log.EXPECT().Debug(beginBlock),
 		db.EXPECT().BeginBlock(uint64(1)),
-		log.EXPECT().Debug(beginTransaction),
+		log.EXPECT().Debug(beginBlock),
 		db.EXPECT().BeginTransaction(uint32(0)),
+		log.EXPECT().Debug(beginTransaction),
 		db.EXPECT().GetBalance(testAddr).Return(balance),
 		log.EXPECT().Debug(getBalance),
-		log.EXPECT().Debug(endTransaction),
 		db.EXPECT().EndTransaction(),
-		log.EXPECT().Debug(endBlock),
+		log.EXPECT().Debug(endTransaction),
 		db.EXPECT().EndBlock(),
+		log.EXPECT().Debug(endBlock),
 	)
 
 	err = ctx.State.BeginBlock(1)
@@ -146,6 +151,51 @@ func TestDbLoggerExtension_LoggingHappens(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestDbLoggerExtension_WritesFilteredOperationsAsJson(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+
+	cfg := &utils.Config{
+		DbLogging:       t.TempDir() + "test-log",
+		DbLoggingFormat: "json",
+		DbLoggingFilter: "GetBalance",
+	}
+	ext := makeDbLogger[any](cfg, logger.NewLogger("critical", "test"))
+	ctx := &executor.Context{State: db}
+	require.NoError(t, ext.PreRun(executor.State[any]{}, ctx))
+
+	db.EXPECT().BeginBlock(uint64(1))
+	db.EXPECT().GetBalance(testAddr).Return(uint256.NewInt(10))
+	db.EXPECT().EndBlock()
+	require.NoError(t, ctx.State.BeginBlock(1))
+	ctx.State.GetBalance(testAddr)
+	require.NoError(t, ctx.State.EndBlock())
+
+	// signal and await the close
+	close(ext.input)
+	ext.wg.Wait()
+
+	content, err := os.ReadFile(cfg.DbLogging)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 1)
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "GetBalance", entry["op"])
+	assert.Equal(t, "10", entry["value"])
+	assert.Equal(t, float64(1), entry["block"])
+}
+
+func TestDbLoggerExtension_UnknownFormatFailsPreRun(t *testing.T) {
+	cfg := &utils.Config{
+		DbLogging:       t.TempDir() + "test-log",
+		DbLoggingFormat: "xml",
+	}
+	ext := makeDbLogger[any](cfg, logger.NewLogger("critical", "test"))
+	err := ext.PreRun(executor.State[any]{}, &executor.Context{})
+	require.ErrorContains(t, err, "unknown db-logging format")
+}
+
 func TestDbLoggerExtension_PreTransactionCreatesNewLoggerProxy(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	db := state.NewMockStateDB(ctrl)
@@ -270,3 +320,47 @@ func TestDbLoggerExtension_StateDbCloseIsWrittenInTheFile(t *testing.T) {
 
 	assert.Contains(t, string(fileContent), want)
 }
+
+func TestDbLoggerExtension_CompressedRecordingReplaysLikeUncompressedOne(t *testing.T) {
+	record := func(path string) {
+		ctrl := gomock.NewController(t)
+		db := state.NewMockStateDB(ctrl)
+		ext := makeDbLogger[any](&utils.Config{DbLogging: path}, logger.NewLogger("critical", "test"))
+		ctx := &executor.Context{State: db}
+		require.NoError(t, ext.PreRun(executor.State[any]{}, ctx))
+
+		db.EXPECT().BeginBlock(uint64(1))
+		db.EXPECT().BeginTransaction(uint32(0))
+		db.EXPECT().GetBalance(testAddr).Return(uint256.NewInt(10))
+		db.EXPECT().SetState(testAddr, common.Hash{1}, common.Hash{2})
+		db.EXPECT().EndTransaction()
+		db.EXPECT().EndBlock()
+		require.NoError(t, ctx.State.BeginBlock(1))
+		require.NoError(t, ctx.State.BeginTransaction(0))
+		ctx.State.GetBalance(testAddr)
+		ctx.State.SetState(testAddr, common.Hash{1}, common.Hash{2})
+		require.NoError(t, ctx.State.EndTransaction())
+		require.NoError(t, ctx.State.EndBlock())
+
+		// signal and await the close
+		close(ext.input)
+		ext.wg.Wait()
+	}
+
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "trace.log")
+	compressed := filepath.Join(dir, "trace.log.zst")
+	record(plain)
+	record(compressed)
+
+	raw, err := os.ReadFile(compressed)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "BeginBlock", "trace was not compressed")
+
+	want, err := delta.LoadOperations([]string{plain}, 0, 0)
+	require.NoError(t, err)
+	got, err := delta.LoadOperations([]string{compressed}, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, got, 6)
+	assert.Equal(t, want, got)
+}
@@ -50,13 +50,20 @@ func makeDeltaLogger[T any](cfg *utils.Config, log logger.Logger) *deltaLogger[T
 
 // PreRun prepares the sink and wraps an already initialized StateDB.
 func (l *deltaLogger[T]) PreRun(_ executor.State[T], ctx *executor.Context) error {
+	policy, err := logger.ParseOverflowPolicy(l.cfg.LogOverflow)
+	if err != nil {
+		return err
+	}
+
 	file, err := os.Create(l.cfg.DeltaLogging)
 	if err != nil {
 		return fmt.Errorf("cannot create delta-log file; %w", err)
 	}
 
-	l.sink = proxy.NewDeltaLogSink(l.log, bufio.NewWriter(file), file)
-
+	l.sink = proxy.NewAsyncDeltaLogSink(l.log, bufio.NewWriter(file), file, l.cfg.LogQueueSize, policy)
+	if l.cfg.DeltaLoggingResults {
+		l.sink.RecordResults()
+	}
 	if ctx.State != nil {
 		ctx.State = proxy.NewDeltaLoggerProxy(ctx.State, l.sink)
 	}
@@ -82,5 +89,9 @@ func (l *deltaLogger[T]) PostRun(_ executor.State[T], _ *executor.Context, _ err
 	if l.sink == nil {
 		return nil
 	}
-	return l.sink.Close()
+	err := l.sink.Close()
+	if dropped := l.sink.Dropped(); dropped > 0 {
+		l.log.Warningf("Delta-log is incomplete, %v records were dropped because the log queue was full", dropped)
+	}
+	return err
 }
@@ -17,24 +17,72 @@
 package logger
 
 import (
+	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/executor/extension"
 	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state/proxy"
 	"github.com/0xsoniclabs/aida/utils"
 )
 
+const (
+	plainErrorLogFormat = "plain"
+	jsonlErrorLogFormat = "jsonl"
+)
+
+// errorCategories maps sentinel errors to the categories of the JSON error log; the first match is used.
+var errorCategories = []struct {
+	sentinel error
+	category string
+}{
+	{executor.ErrTxProcessing, "tx-processing"},
+	{executor.ErrStateMismatch, "state-mismatch"},
+	{executor.ErrReceiptMismatch, "receipt-mismatch"},
+	{executor.ErrEvmDivergence, "evm-divergence"},
+	{proxy.ErrArchiveBlockPruned, "archive-block-pruned"},
+	{executor.ErrArchiveQuery, "archive-query"},
+}
+
+// otherErrorCategory is the category of errors not matching any of the errorCategories.
+const otherErrorCategory = "other"
+
 type errorLogger[T any] struct {
 	extension.NilExtension[T]
 	cfg    *utils.Config
 	file   *os.File
+	output *bufio.Writer
+	jsonl  bool
 	log    logger.Logger
 	wg     *sync.WaitGroup
 	errors []error
+	writer *logger.WriteBehind[errorRecord]
+}
+
+// errorRecord is an error queued for being logged together with its position among all errors
+// and the time it was reported.
+type errorRecord struct {
+	err    error
+	number int
+	time   time.Time
+}
+
+// errorEntry is a line of the JSON error log. Block and transaction are null if the error
+// is not related to a transaction.
+type errorEntry struct {
+	Timestamp   time.Time               `json:"timestamp"`
+	Block       *int                    `json:"block"`
+	Transaction *int                    `json:"transaction"`
+	Component   executor.ErrorComponent `json:"component"`
+	Error       string                  `json:"error"`
+	Category    string                  `json:"category"`
 }
 
 func MakeErrorLogger[T any](cfg *utils.Config) executor.Extension[T] {
@@ -49,32 +97,51 @@ func makeErrorLogger[T any](cfg *utils.Config, log logger.Logger) *errorLogger[T
 	}
 }
 
+// PreRun creates the error channel and the log-file. Errors are collected by a dedicated thread,
+// which hands them over to a write-behind queue, so that slow logging does not block the workers.
 func (l *errorLogger[T]) PreRun(_ executor.State[T], ctx *executor.Context) error {
-	ctx.ErrorInput = make(chan error, l.cfg.Workers*10)
+	policy, err := logger.ParseOverflowPolicy(l.cfg.LogOverflow)
+	if err != nil {
+		return err
+	}
 
-	l.wg.Add(1)
-	go l.doLogging(ctx.ErrorInput)
+	if l.cfg.ErrorLogging != "" {
+		l.jsonl, err = isJsonlErrorLog(l.cfg.ErrorLogging, l.cfg.ErrorLoggingFormat)
+		if err != nil {
+			return err
+		}
+		l.log.Noticef("Creating log-file %v in which any processing error will be recorded.", l.cfg.ErrorLogging)
 
-	if l.cfg.ErrorLogging == "" {
-		return nil
+		l.file, err = os.Create(l.cfg.ErrorLogging)
+		if err != nil {
+			return fmt.Errorf("cannot create log file %v; %v", l.cfg.ErrorLogging, err)
+		}
+		l.output = bufio.NewWriter(l.file)
 	}
 
-	l.log.Noticef("Creating log-file %v in which any processing error will be recorded.", l.cfg.ErrorLogging)
+	l.writer = logger.NewWriteBehind(l.cfg.LogQueueSize, policy, l.write, l.flush)
 
-	var err error
-	l.file, err = os.Create(l.cfg.ErrorLogging)
-	if err != nil {
-		return fmt.Errorf("cannot create log file %v; %v", l.cfg.ErrorLogging, err)
-	}
+	ctx.ErrorInput = make(chan error, l.cfg.Workers*10)
+
+	l.wg.Add(1)
+	go l.doLogging(ctx.ErrorInput)
 
 	return nil
 }
 
-// PostRun closes the file and logging thread.
+// PostRun closes the logging thread, flushes the pending records and closes the file.
 func (l *errorLogger[T]) PostRun(_ executor.State[T], ctx *executor.Context, _ error) error {
+	if ctx.ErrorInput == nil {
+		// PreRun failed
+		return nil
+	}
 	close(ctx.ErrorInput)
 	l.wg.Wait()
 
+	if err := l.writer.Close(); err != nil {
+		l.log.Errorf("cannot flush log-file; %v", err)
+	}
+
 	if l.file != nil {
 		err := l.file.Close()
 		if err != nil {
@@ -82,6 +149,10 @@ func (l *errorLogger[T]) PostRun(_ executor.State[T], ctx *executor.Context, _ e
 		}
 	}
 
+	if dropped := l.writer.Dropped(); dropped > 0 {
+		l.log.Warningf("%v of %v errors were not logged when they occurred because the log queue was full", dropped, len(l.errors))
+	}
+
 	for i, e := range l.errors {
 		l.log.Errorf("#%v: %v", i+1, e)
 	}
@@ -93,24 +164,83 @@ func (l *errorLogger[T]) PostRun(_ executor.State[T], ctx *executor.Context, _ e
 	return nil
 }
 
+// doLogging collects all errors; collected errors are never dropped so that the summary is complete.
 func (l *errorLogger[T]) doLogging(input chan error) {
 	defer l.wg.Done()
 
-	var numberOfErrors int
 	for {
 		in := <-input
 		if in == nil {
 			return
 		}
-		numberOfErrors++
-		l.log.Errorf("New error: \n\t%v", in)
-		l.log.Warningf("Total number of errors %v", numberOfErrors)
-		if l.file != nil {
-			_, err := l.file.WriteString(in.Error())
-			if err != nil {
-				l.log.Errorf("cannot write into log-file; %v", err)
-			}
-		}
 		l.errors = append(l.errors, in)
+		l.writer.Push(errorRecord{err: in, number: len(l.errors), time: time.Now()})
+	}
+}
+
+// write is called by the writer thread of the write-behind queue.
+func (l *errorLogger[T]) write(record errorRecord) error {
+	l.log.Errorf("New error: \n\t%v", record.err)
+	l.log.Warningf("Total number of errors %v", record.number)
+	if l.output == nil {
+		return nil
+	}
+	line := record.err.Error()
+	if l.jsonl {
+		entry, err := json.Marshal(makeErrorEntry(record))
+		if err != nil {
+			l.log.Errorf("cannot encode error; %v", err)
+			return nil
+		}
+		// records are written by a single thread, each with a single write, hence lines never interleave
+		line = string(entry) + "\n"
+	}
+	if _, err := l.output.WriteString(line); err != nil {
+		l.log.Errorf("cannot write into log-file; %v", err)
+	}
+	return nil
+}
+
+// makeErrorEntry extracts the context of the error of given record.
+func makeErrorEntry(record errorRecord) errorEntry {
+	entry := errorEntry{
+		Timestamp: record.time,
+		Error:     record.err.Error(),
+		Category:  otherErrorCategory,
+	}
+	var txErr *executor.TxError
+	if errors.As(record.err, &txErr) {
+		entry.Block = &txErr.Block
+		entry.Transaction = &txErr.Transaction
+		entry.Component = txErr.Component
+	}
+	for _, c := range errorCategories {
+		if errors.Is(record.err, c.sentinel) {
+			entry.Category = c.category
+			break
+		}
+	}
+	return entry
+}
+
+// isJsonlErrorLog decides whether the error log is written as JSON lines. Unless the format
+// is given, it is selected by the extension of the file.
+func isJsonlErrorLog(path string, format string) (bool, error) {
+	switch format {
+	case "":
+		return filepath.Ext(path) == ".jsonl", nil
+	case plainErrorLogFormat:
+		return false, nil
+	case jsonlErrorLogFormat:
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown error log format %q; use %q or %q", format, plainErrorLogFormat, jsonlErrorLogFormat)
+	}
+}
+
+func (l *errorLogger[T]) flush() error {
+	if l.output == nil {
+		return nil
 	}
+	return l.output.Flush()
 }
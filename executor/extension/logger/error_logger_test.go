@@ -17,16 +17,23 @@
 package logger
 
 import (
+	"bufio"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state/proxy"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -113,3 +120,177 @@ func TestErrorLogger_LoggingHappens(t *testing.T) {
 	}
 
 }
+
+func TestErrorLogger_DroppedRecordsAreCountedInSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+
+	cfg := &utils.Config{LogQueueSize: 1, LogOverflow: string(logger.DropOnOverflow)}
+	ext := makeErrorLogger[any](cfg, log)
+
+	e1, e2, e3 := errors.New("first"), errors.New("second"), errors.New("third")
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	log.EXPECT().Errorf("New error: \n\t%v", e1).Do(func(string, ...any) {
+		close(started)
+		<-release
+	})
+	log.EXPECT().Errorf("New error: \n\t%v", e2)
+	log.EXPECT().Warningf("Total number of errors %v", gomock.Any()).Times(2)
+	gomock.InOrder(
+		log.EXPECT().Warningf("%v of %v errors were not logged when they occurred because the log queue was full", uint64(1), 3),
+		log.EXPECT().Errorf("#%v: %v", 1, e1),
+		log.EXPECT().Errorf("#%v: %v", 2, e2),
+		log.EXPECT().Errorf("#%v: %v", 3, e3),
+	)
+
+	ctx := new(executor.Context)
+	assert.NoError(t, ext.PreRun(executor.State[any]{}, ctx))
+
+	ctx.ErrorInput <- e1
+	<-started // the writer is busy with the first error
+	ctx.ErrorInput <- e2
+	ctx.ErrorInput <- e3
+
+	// the third error is dropped by the collecting thread once it is processed;
+	// the writer must not be released before that happens
+	assert.Eventually(t, func() bool { return ext.writer.Dropped() == 1 }, time.Second, time.Millisecond)
+	close(release)
+
+	assert.ErrorContains(t, ext.PostRun(executor.State[any]{}, ctx, nil), "run failed")
+}
+
+func TestErrorLogger_InvalidOverflowPolicyIsRejected(t *testing.T) {
+	cfg := &utils.Config{LogOverflow: "spill"}
+	ext := makeErrorLogger[any](cfg, logger.NewLogger("critical", "Test"))
+	ctx := new(executor.Context)
+	assert.ErrorContains(t, ext.PreRun(executor.State[any]{}, ctx), "unknown overflow policy")
+	assert.NoError(t, ext.PostRun(executor.State[any]{}, ctx, nil))
+}
+
+// runErrorLogger reports given errors through an error logger writing into given file.
+func runErrorLogger(t *testing.T, cfg *utils.Config, errs ...error) {
+	ext := makeErrorLogger[any](cfg, logger.NewLogger("critical", "Test"))
+	ctx := new(executor.Context)
+	require.NoError(t, ext.PreRun(executor.State[any]{}, ctx))
+	for _, err := range errs {
+		ctx.ErrorInput <- err
+	}
+	require.ErrorContains(t, ext.PostRun(executor.State[any]{}, ctx, nil), "run failed")
+}
+
+// readErrorEntries parses every line of given JSON error log.
+func readErrorEntries(t *testing.T, fileName string) []errorEntry {
+	file, err := os.Open(fileName)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, file.Close())
+	}()
+
+	var entries []errorEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var entry errorEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry), "line %q", scanner.Text())
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+func TestErrorLogger_WritesJsonLinesWithContext(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "errors.jsonl")
+	cfg := &utils.Config{ErrorLogging: fileName}
+
+	start := time.Now()
+	runErrorLogger(t, cfg,
+		executor.NewTxError(executor.ProcessorComponent, 10, 1, executor.ErrTxProcessing, errors.New("out of gas")),
+		executor.NewTxError(executor.ValidatorComponent, 11, 2, executor.ErrReceiptMismatch, errors.New("receipt differs")),
+		executor.NewTxError(executor.StateDbComponent, 12, 3, executor.ErrArchiveQuery, fmt.Errorf("no archive; %w", proxy.ErrArchiveBlockPruned)),
+		errors.New("stall detected"),
+	)
+
+	entries := readErrorEntries(t, fileName)
+	require.Len(t, entries, 4)
+
+	intPtr := func(v int) *int { return &v }
+	want := []errorEntry{
+		{Block: intPtr(10), Transaction: intPtr(1), Component: executor.ProcessorComponent, Error: "out of gas", Category: "tx-processing"},
+		{Block: intPtr(11), Transaction: intPtr(2), Component: executor.ValidatorComponent, Error: "receipt differs", Category: "receipt-mismatch"},
+		{Block: intPtr(12), Transaction: intPtr(3), Component: executor.StateDbComponent, Error: "no archive; archive block not found", Category: "archive-block-pruned"},
+		{Error: "stall detected", Category: otherErrorCategory},
+	}
+	for i, entry := range entries {
+		assert.False(t, entry.Timestamp.Before(start.Truncate(time.Second)), "timestamp of entry %d", i)
+		entry.Timestamp = time.Time{}
+		assert.Equal(t, want[i], entry)
+	}
+}
+
+func TestErrorLogger_ConcurrentErrorsAreWrittenAsWholeLines(t *testing.T) {
+	const workers, errorsPerWorker = 8, 50
+
+	fileName := filepath.Join(t.TempDir(), "errors.log")
+	cfg := &utils.Config{ErrorLogging: fileName, ErrorLoggingFormat: jsonlErrorLogFormat, Workers: workers}
+	ext := makeErrorLogger[any](cfg, logger.NewLogger("critical", "Test"))
+	ctx := new(executor.Context)
+	require.NoError(t, ext.PreRun(executor.State[any]{}, ctx))
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < errorsPerWorker; i++ {
+				err := fmt.Errorf("worker %d error %d; %s", w, i, strings.Repeat("x", 4096))
+				ctx.ErrorInput <- executor.NewTxError(executor.ValidatorComponent, w, i, executor.ErrStateMismatch, err)
+			}
+		}()
+	}
+	wg.Wait()
+	require.ErrorContains(t, ext.PostRun(executor.State[any]{}, ctx, nil), "run failed")
+
+	entries := readErrorEntries(t, fileName)
+	require.Len(t, entries, workers*errorsPerWorker)
+	for _, entry := range entries {
+		require.NotNil(t, entry.Block)
+		require.NotNil(t, entry.Transaction)
+		assert.True(t, strings.HasPrefix(entry.Error, fmt.Sprintf("worker %d error %d;", *entry.Block, *entry.Transaction)))
+		assert.Equal(t, "state-mismatch", entry.Category)
+	}
+}
+
+func TestErrorLogger_PlainFormatIsKept(t *testing.T) {
+	tests := map[string]struct {
+		fileName string
+		format   string
+	}{
+		"default":                    {fileName: "errors.log"},
+		"format-overrides-extension": {fileName: "errors.jsonl", format: plainErrorLogFormat},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), test.fileName)
+			cfg := &utils.Config{ErrorLogging: fileName, ErrorLoggingFormat: test.format}
+
+			runErrorLogger(t, cfg,
+				executor.NewTxError(executor.ProcessorComponent, 10, 1, executor.ErrTxProcessing, errors.New("first")),
+				errors.New("second"),
+			)
+
+			content, err := os.ReadFile(fileName)
+			require.NoError(t, err)
+			assert.Equal(t, "firstsecond", string(content))
+		})
+	}
+}
+
+func TestErrorLogger_UnknownFormatIsRejected(t *testing.T) {
+	cfg := &utils.Config{ErrorLogging: filepath.Join(t.TempDir(), "errors.log"), ErrorLoggingFormat: "xml"}
+	ext := makeErrorLogger[any](cfg, logger.NewLogger("critical", "Test"))
+	ctx := new(executor.Context)
+	assert.ErrorContains(t, ext.PreRun(executor.State[any]{}, ctx), "unknown error log format")
+	assert.NoError(t, ext.PostRun(executor.State[any]{}, ctx, nil))
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/0xsoniclabs/aida/ethtest"
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+// MakeEthTestResultReporter creates an extension recording the result of each ethereum test to
+// the given matrix. The matrix is printed at the end of the run and written to --report-file if set.
+func MakeEthTestResultReporter(cfg *utils.Config, results *ethtest.ResultMatrix) executor.Extension[txcontext.TxContext] {
+	return makeEthTestResultReporter(cfg, results, logger.NewLogger(cfg.LogLevel, "EthTestResultReporter"))
+}
+
+func makeEthTestResultReporter(cfg *utils.Config, results *ethtest.ResultMatrix, log logger.Logger) *ethTestResultReporter {
+	return &ethTestResultReporter{
+		cfg:     cfg,
+		results: results,
+		log:     log,
+	}
+}
+
+// ethTestResultReporter attributes the errors tolerated by --continue-on-failure to the test
+// being run by intercepting them on their way to the error logger. A test is finished once
+// the next one starts, as its errors may be reported by any post hook of the other extensions.
+type ethTestResultReporter struct {
+	extension.NilExtension[txcontext.TxContext]
+	cfg     *utils.Config
+	results *ethtest.ResultMatrix
+	log     logger.Logger
+
+	lock       sync.Mutex
+	current    ethtest.TestCase // test being run, nil if none
+	failed     bool             // whether the current test failed
+	errorInput chan error       // error input of the error logger
+	input      chan error       // intercepted error input
+	wg         sync.WaitGroup
+}
+
+// flushRequest is sent through the intercepted error input and closed once all errors
+// sent before it were attributed.
+type flushRequest chan struct{}
+
+func (flushRequest) Error() string {
+	return "flush request"
+}
+
+// PreRun intercepts the error input created by the error logger.
+func (r *ethTestResultReporter) PreRun(_ executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	if ctx.ErrorInput == nil {
+		return nil
+	}
+	r.errorInput = ctx.ErrorInput
+	r.input = make(chan error)
+	ctx.ErrorInput = r.input
+
+	r.wg.Add(1)
+	go r.intercept()
+	return nil
+}
+
+func (r *ethTestResultReporter) intercept() {
+	defer r.wg.Done()
+	for err := range r.input {
+		if flush, ok := err.(flushRequest); ok {
+			close(flush)
+			continue
+		}
+		r.lock.Lock()
+		r.failed = true
+		r.lock.Unlock()
+		r.errorInput <- err
+	}
+}
+
+// flush waits until all errors reported so far are attributed.
+func (r *ethTestResultReporter) flush() {
+	if r.input == nil {
+		return
+	}
+	done := make(flushRequest)
+	r.input <- done
+	<-done
+}
+
+// PreBlock finishes the previous test and starts the one about to be run.
+func (r *ethTestResultReporter) PreBlock(state executor.State[txcontext.TxContext], _ *executor.Context) error {
+	r.flush()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.finish(false)
+	r.current, _ = state.Data.(ethtest.TestCase)
+	return nil
+}
+
+// finish records the result of the current test; the lock must be held.
+func (r *ethTestResultReporter) finish(failed bool) {
+	if r.current == nil {
+		return
+	}
+	if r.failed || failed {
+		r.results.AddFailed(r.current.GetFork(), r.current.GetSuite())
+	} else {
+		r.results.AddPassed(r.current.GetFork(), r.current.GetSuite())
+	}
+	r.current, r.failed = nil, false
+}
+
+// PostRun finishes the last test, which failed if the run was aborted, hands the error input back
+// to the error logger and reports the results.
+func (r *ethTestResultReporter) PostRun(_ executor.State[txcontext.TxContext], ctx *executor.Context, err error) error {
+	r.flush()
+	if r.input != nil {
+		close(r.input)
+		r.wg.Wait()
+		ctx.ErrorInput = r.errorInput
+	}
+
+	r.lock.Lock()
+	r.finish(err != nil)
+	r.lock.Unlock()
+
+	var table bytes.Buffer
+	if err := r.results.WriteTable(&table); err != nil {
+		return err
+	}
+	r.log.Noticef("Results of ethereum tests:\n%s", table.String())
+
+	if r.cfg.ReportFile != "" {
+		if err := r.results.WriteJson(r.cfg.ReportFile); err != nil {
+			return err
+		}
+		r.log.Noticef("Results written to %v", r.cfg.ReportFile)
+	}
+	return nil
+}
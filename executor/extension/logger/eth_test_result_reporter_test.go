@@ -0,0 +1,126 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/ethtest"
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// testCase is an ethereum test identified by its fork and suite.
+type testCase struct {
+	txcontext.TxContext
+	fork, suite string
+}
+
+func (c testCase) GetFork() string {
+	return c.fork
+}
+
+func (c testCase) GetSuite() string {
+	return c.suite
+}
+
+func TestEthTestResultReporter_AttributesErrorsToTests(t *testing.T) {
+	results := ethtest.NewResultMatrix()
+	ext := makeEthTestResultReporter(&utils.Config{}, results, logger.NewLogger("critical", "test"))
+
+	errorInput := make(chan error, 10)
+	ctx := &executor.Context{ErrorInput: errorInput}
+	require.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+
+	tests := []testCase{
+		{fork: "Cancun", suite: "stA"},
+		{fork: "Cancun", suite: "stA"},
+		{fork: "Prague", suite: "stB"},
+	}
+	for i, test := range tests {
+		require.NoError(t, ext.PreBlock(executor.State[txcontext.TxContext]{Block: 2 + i, Data: test}, ctx))
+		if i == 0 {
+			ctx.ErrorInput <- errors.New("first")
+			ctx.ErrorInput <- errors.New("second")
+		}
+	}
+	require.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, nil))
+
+	assert.Equal(t, []ethtest.ResultRow{
+		{Fork: "Cancun", Suite: "stA", Passed: 1, Failed: 1},
+		{Fork: "Prague", Suite: "stB", Passed: 1},
+	}, results.Rows())
+	assert.Equal(t, errorInput, ctx.ErrorInput, "error input must be handed back")
+	assert.Len(t, errorInput, 2, "errors must be forwarded to the error logger")
+}
+
+func TestEthTestResultReporter_AbortedTestFails(t *testing.T) {
+	results := ethtest.NewResultMatrix()
+	ext := makeEthTestResultReporter(&utils.Config{}, results, logger.NewLogger("critical", "test"))
+	ctx := &executor.Context{}
+
+	require.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+	require.NoError(t, ext.PreBlock(executor.State[txcontext.TxContext]{Block: 2, Data: testCase{fork: "Cancun", suite: "stA"}}, ctx))
+	require.NoError(t, ext.PreBlock(executor.State[txcontext.TxContext]{Block: 3, Data: testCase{fork: "Cancun", suite: "stA"}}, ctx))
+	require.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, errors.New("fatal")))
+
+	assert.Equal(t, []ethtest.ResultRow{
+		{Fork: "Cancun", Suite: "stA", Passed: 1, Failed: 1},
+	}, results.Rows())
+}
+
+func TestEthTestResultReporter_IgnoresOtherPayloads(t *testing.T) {
+	results := ethtest.NewResultMatrix()
+	ext := makeEthTestResultReporter(&utils.Config{}, results, logger.NewLogger("critical", "test"))
+	ctx := &executor.Context{}
+
+	require.NoError(t, ext.PreBlock(executor.State[txcontext.TxContext]{Block: 2, Data: txcontext.NewMockTxContext(gomock.NewController(t))}, ctx))
+	require.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, nil))
+	assert.Empty(t, results.Rows())
+}
+
+func TestEthTestResultReporter_WritesReportFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	results := ethtest.NewResultMatrix()
+	results.AddSkipped("Frontier", "stA")
+	ext := MakeEthTestResultReporter(&utils.Config{ReportFile: path}, results)
+	ctx := &executor.Context{}
+
+	require.NoError(t, ext.PreBlock(executor.State[txcontext.TxContext]{Block: 2, Data: testCase{fork: "Cancun", suite: "stA"}}, ctx))
+	require.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, nil))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"fork": "Cancun"`)
+	assert.Contains(t, string(data), `"fork": "Frontier"`)
+}
+
+func TestEthTestResultReporter_FailsOnInvalidReportFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "report.json")
+	ext := MakeEthTestResultReporter(&utils.Config{ReportFile: path}, ethtest.NewResultMatrix())
+
+	err := ext.PostRun(executor.State[txcontext.TxContext]{}, &executor.Context{}, nil)
+	require.ErrorContains(t, err, "cannot write results")
+}
@@ -17,12 +17,19 @@
 package logger
 
 import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/executor/extension"
 	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
 	"github.com/0xsoniclabs/aida/utils"
 )
 
@@ -32,6 +39,39 @@ const (
 	finalSummaryProgressReportFormat     = "Total elapsed time: %v; last block %d; total transaction rate ~%.2f Tx/s, ~%.2f MGas/s"
 )
 
+// Fields which can be appended to the heartbeat as key=value pairs.
+const (
+	heartbeatStateDbBytes     = "statedb_bytes"
+	heartbeatStateDbBreakdown = "statedb_breakdown"
+	heartbeatGoroutines       = "goroutines"
+	heartbeatHeapAlloc        = "heap_alloc"
+	heartbeatNumGC            = "num_gc"
+)
+
+var heartbeatFields = []string{
+	heartbeatStateDbBytes,
+	heartbeatStateDbBreakdown,
+	heartbeatGoroutines,
+	heartbeatHeapAlloc,
+	heartbeatNumGC,
+}
+
+// parseHeartbeatFields parses a comma-separated list of heartbeat fields.
+func parseHeartbeatFields(list string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !slices.Contains(heartbeatFields, field) {
+			return nil, fmt.Errorf("unknown heartbeat field %q; supported fields: %v", field, strings.Join(heartbeatFields, ","))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
 // MakeProgressLogger creates progress logger. It logs progress about processor depending on reportFrequency.
 // If reportFrequency is 0, it is set to ProgressLoggerDefaultReportFrequency.
 func MakeProgressLogger[T any](cfg *utils.Config, reportFrequency time.Duration) executor.Extension[T] {
@@ -71,10 +111,23 @@ type progressLogger[T any] struct {
 	inputCh         chan txProgressInfo
 	wg              *sync.WaitGroup
 	reportFrequency time.Duration
+	fields          []string
+
+	// the StateDb may only be accessed by the executor, hence the report
+	// goroutine requests a sample which is taken by the next PostBlock
+	sampleRequested atomic.Bool
+	memory          atomic.Pointer[state.MemoryUsage]
 }
 
 // PreRun starts the report goroutine
 func (l *progressLogger[T]) PreRun(_ executor.State[T], ctx *executor.Context) error {
+	fields, err := parseHeartbeatFields(l.cfg.HeartbeatFields)
+	if err != nil {
+		return err
+	}
+	l.fields = fields
+	l.sampleRequested.Store(l.needsMemorySample())
+
 	l.wg.Add(1)
 
 	// pass the value for thread safety
@@ -103,6 +156,66 @@ func (l *progressLogger[T]) PostTransaction(state executor.State[T], ctx *execut
 	return nil
 }
 
+// PostBlock samples the memory usage of the StateDb if requested by the report goroutine.
+func (l *progressLogger[T]) PostBlock(_ executor.State[T], ctx *executor.Context) error {
+	if ctx.State == nil || !l.sampleRequested.CompareAndSwap(true, false) {
+		return nil
+	}
+	if usage := ctx.State.GetMemoryUsage(); usage != nil {
+		l.memory.Store(usage)
+	}
+	return nil
+}
+
+// needsMemorySample returns true if any of the configured fields reports the StateDb memory.
+func (l *progressLogger[T]) needsMemorySample() bool {
+	return slices.Contains(l.fields, heartbeatStateDbBytes) ||
+		(l.cfg.MemoryBreakdown && slices.Contains(l.fields, heartbeatStateDbBreakdown))
+}
+
+// formatHeartbeatFields returns the configured fields as space-separated key=value pairs.
+// StateDb fields are reported from the latest sample and skipped until the first one is taken.
+func (l *progressLogger[T]) formatHeartbeatFields() string {
+	var memStats *runtime.MemStats
+	usage := l.memory.Load()
+
+	var pairs []string
+	for _, field := range l.fields {
+		var value string
+		switch field {
+		case heartbeatStateDbBytes:
+			if usage == nil {
+				continue
+			}
+			value = strconv.FormatUint(usage.UsedBytes, 10)
+		case heartbeatStateDbBreakdown:
+			if !l.cfg.MemoryBreakdown || usage == nil || usage.Breakdown == nil {
+				continue
+			}
+			// quoting keeps the multi-line breakdown on a single line
+			value = strconv.Quote(usage.Breakdown.String())
+		case heartbeatGoroutines:
+			value = strconv.Itoa(runtime.NumGoroutine())
+		case heartbeatHeapAlloc, heartbeatNumGC:
+			if memStats == nil {
+				memStats = new(runtime.MemStats)
+				runtime.ReadMemStats(memStats)
+			}
+			if field == heartbeatHeapAlloc {
+				value = strconv.FormatUint(memStats.HeapAlloc, 10)
+			} else {
+				value = strconv.FormatUint(uint64(memStats.NumGC), 10)
+			}
+		}
+		pairs = append(pairs, field+"="+value)
+	}
+
+	if l.needsMemorySample() {
+		l.sampleRequested.Store(true)
+	}
+	return strings.Join(pairs, " ")
+}
+
 // startReport runs in own goroutine. It accepts data from Executor from PostBock func.
 // It reports current progress every time we hit the ticker with defaultReportFrequencyInSeconds.
 func (l *progressLogger[T]) startReport(reportFrequency time.Duration, stateDbPath string) {
@@ -120,8 +233,8 @@ func (l *progressLogger[T]) startReport(reportFrequency time.Duration, stateDbPa
 
 	defer func() {
 		elapsed := time.Since(start)
-		txRate := float64(totalTx) / elapsed.Seconds()
-		gasRate := float64(totalGas) / elapsed.Seconds()
+		txRate := utils.Rate(float64(totalTx), elapsed)
+		gasRate := utils.Rate(float64(totalGas), elapsed)
 
 		l.log.Noticef(finalSummaryProgressReportFormat, elapsed.Round(time.Second), currentBlock, txRate, gasRate/1e6)
 	}()
@@ -153,9 +266,11 @@ func (l *progressLogger[T]) startReport(reportFrequency time.Duration, stateDbPa
 				continue
 			}
 			elapsed := now.Sub(start)
-			txRate := float64(currentIntervalTx) / now.Sub(lastReport).Seconds()
-			gasRate := float64(currentIntervalGas) / now.Sub(lastReport).Seconds()
+			txRate := utils.Rate(float64(currentIntervalTx), now.Sub(lastReport))
+			gasRate := utils.Rate(float64(currentIntervalGas), now.Sub(lastReport))
 
+			format := progressLoggerReportFormat
+			args := []any{elapsed.Round(1 * time.Second), currentBlock, txRate, gasRate / 1e6}
 			if stateDbPath != "" {
 				used, err := utils.GetDirectorySize(stateDbPath)
 				if err != nil {
@@ -169,15 +284,14 @@ func (l *progressLogger[T]) startReport(reportFrequency time.Duration, stateDbPa
 				}
 
 				GiB := float64(1 << 30)
-				l.log.Infof(progressLoggerReportFormat+"; disk usage %.2f GiB, free space %.2f GiB",
-					elapsed.Round(1*time.Second), currentBlock, txRate, gasRate/1e6,
-					float64(used)/GiB, float64(free)/GiB,
-				)
-			} else {
-				l.log.Infof(progressLoggerReportFormat,
-					elapsed.Round(1*time.Second), currentBlock, txRate, gasRate/1e6,
-				)
+				format += "; disk usage %.2f GiB, free space %.2f GiB"
+				args = append(args, float64(used)/GiB, float64(free)/GiB)
+			}
+			if fields := l.formatHeartbeatFields(); fields != "" {
+				format += "; %s"
+				args = append(args, fields)
 			}
+			l.log.Infof(format, args...)
 
 			lastReport = now
 
@@ -17,16 +17,19 @@
 package logger
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/executor/extension"
 	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
 	substatecontext "github.com/0xsoniclabs/aida/txcontext/substate"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/0xsoniclabs/substate/substate"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -151,3 +154,124 @@ func TestProgressLoggerExtension_MakeProgressLogger(t *testing.T) {
 	_, ok := ext.(*progressLogger[any])
 	assert.True(t, ok)
 }
+
+type testBreakdown string
+
+func (b testBreakdown) String() string {
+	return string(b)
+}
+
+// runHeartbeat runs the progress logger against given StateDb until the first heartbeat is emitted.
+func runHeartbeat(t *testing.T, cfg *utils.Config, db state.StateDB) string {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+
+	lines := make(chan string, 1)
+	log.EXPECT().Infof(progressLoggerReportFormat+"; %s", gomock.Any()).DoAndReturn(func(format string, args ...any) {
+		select {
+		case lines <- fmt.Sprintf(format, args...):
+		default:
+		}
+	}).MinTimes(1)
+	log.EXPECT().Noticef(finalSummaryProgressReportFormat, gomock.Any())
+
+	ext := makeProgressLogger[any](cfg, testProgressReportFrequency, log)
+	require.NoError(t, ext.PreRun(executor.State[any]{}, nil))
+
+	ctx := &executor.Context{
+		State:           db,
+		ExecutionResult: substatecontext.NewReceipt(&substate.Result{GasUsed: 21_000}),
+	}
+	require.NoError(t, ext.PostTransaction(executor.State[any]{Block: 1}, ctx))
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 1}, ctx))
+
+	var line string
+	select {
+	case line = <-lines:
+	case <-time.After(3 * testProgressReportFrequency):
+		t.Fatal("heartbeat was not logged")
+	}
+	require.NoError(t, ext.PostRun(executor.State[any]{}, nil, nil))
+	return line
+}
+
+func TestProgressLoggerExtension_HeartbeatContainsStateDbAndRuntimeStatistics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	db.EXPECT().GetMemoryUsage().Return(&state.MemoryUsage{
+		UsedBytes: 1234,
+		Breakdown: testBreakdown("live: 1000\narchive: 234"),
+	})
+
+	cfg := &utils.Config{
+		HeartbeatFields: utils.HeartbeatFieldsFlag.Value,
+		MemoryBreakdown: true,
+	}
+	line := runHeartbeat(t, cfg, db)
+
+	assert.NotContains(t, line, "\n")
+	assert.Contains(t, line, "statedb_bytes=1234")
+	assert.Contains(t, line, `statedb_breakdown="live: 1000\narchive: 234"`)
+	assert.Regexp(t, `goroutines=\d+`, line)
+	assert.Regexp(t, `heap_alloc=\d+`, line)
+	assert.Regexp(t, `num_gc=\d+`, line)
+}
+
+func TestProgressLoggerExtension_HeartbeatContainsOnlyConfiguredFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	// the StateDb must not be sampled if no StateDb field is configured
+	db := state.NewMockStateDB(ctrl)
+
+	cfg := &utils.Config{
+		HeartbeatFields: "goroutines, num_gc",
+		MemoryBreakdown: true,
+	}
+	line := runHeartbeat(t, cfg, db)
+
+	assert.Regexp(t, `; goroutines=\d+ num_gc=\d+$`, line)
+	assert.NotContains(t, line, "statedb_")
+	assert.NotContains(t, line, "heap_alloc")
+}
+
+func TestProgressLoggerExtension_HeartbeatSkipsBreakdownWithoutMemoryBreakdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	db.EXPECT().GetMemoryUsage().Return(&state.MemoryUsage{
+		UsedBytes: 1234,
+		Breakdown: testBreakdown("live: 1234"),
+	})
+
+	cfg := &utils.Config{HeartbeatFields: "statedb_bytes,statedb_breakdown"}
+	line := runHeartbeat(t, cfg, db)
+
+	assert.Contains(t, line, "; statedb_bytes=1234")
+	assert.NotContains(t, line, "statedb_breakdown")
+}
+
+func TestProgressLoggerExtension_PreRunRejectsUnknownHeartbeatField(t *testing.T) {
+	cfg := &utils.Config{HeartbeatFields: "goroutines,heap"}
+	ext := makeProgressLogger[any](cfg, testProgressReportFrequency, logger.NewLogger("info", "test"))
+
+	err := ext.PreRun(executor.State[any]{}, nil)
+	require.ErrorContains(t, err, `unknown heartbeat field "heap"`)
+}
+
+func TestProgressLogger_parseHeartbeatFields(t *testing.T) {
+	tests := []struct {
+		name string
+		list string
+		want []string
+	}{
+		{name: "empty", list: "", want: nil},
+		{name: "single", list: "goroutines", want: []string{"goroutines"}},
+		{name: "spaces_and_empty_items", list: " heap_alloc, ,num_gc ", want: []string{"heap_alloc", "num_gc"}},
+		{name: "default", list: utils.HeartbeatFieldsFlag.Value, want: heartbeatFields},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseHeartbeatFields(test.list)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
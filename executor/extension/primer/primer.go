@@ -55,7 +55,7 @@ func (p *stateDbPrimer[T]) PreRun(_ executor.State[T], ctx *executor.Context) (e
 	}
 
 	if p.cfg.PrimeRandom {
-		p.log.Infof("Randomized Priming enabled; Seed: %v, threshold: %v", p.cfg.RandomSeed, p.cfg.PrimeThreshold)
+		p.log.Infof("Randomized Priming enabled; Seed: %v, threshold: %v, shuffle window: %v", p.cfg.RandomSeed, p.cfg.PrimeThreshold, p.cfg.PrimeShuffleWindow)
 	}
 
 	p.log.Infof("Update buffer size: %v bytes", p.cfg.UpdateBufferSize)
@@ -165,12 +165,13 @@ func TestStateDbPrimerExtension_UserIsInformedAboutRandomPriming(t *testing.T) {
 	cfg.PrimeRandom = true
 	cfg.RandomSeed = 111
 	cfg.PrimeThreshold = 10
+	cfg.PrimeShuffleWindow = 100
 	cfg.UpdateBufferSize = 1024
 
 	ext := makeStateDbPrimer[any](cfg, log)
 
 	gomock.InOrder(
-		log.EXPECT().Infof("Randomized Priming enabled; Seed: %v, threshold: %v", int64(111), 10),
+		log.EXPECT().Infof("Randomized Priming enabled; Seed: %v, threshold: %v, shuffle window: %v", int64(111), 10, 100),
 		log.EXPECT().Infof("Update buffer size: %v bytes", uint64(1024)),
 		log.EXPECT().Warning("cannot get first substate; substate db is empty"),
 		log.EXPECT().Noticef("Priming from block %v...", uint64(0)),
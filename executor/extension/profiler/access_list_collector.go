@@ -0,0 +1,227 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// accessListStatsHeader describes columns of the per-transaction access-list statistics.
+var accessListStatsHeader = []string{"block", "tx", "declared", "touched", "coverage", "wasted"}
+
+// MakeAccessListCollector creates an extension comparing access lists declared by
+// transactions against addresses and slots actually queried during their execution.
+func MakeAccessListCollector(cfg *utils.Config) executor.Extension[txcontext.TxContext] {
+	if cfg.AccessListStats == "" {
+		return extension.NilExtension[txcontext.TxContext]{}
+	}
+	return makeAccessListCollector(cfg, logger.NewLogger(cfg.LogLevel, "Access-List-Stats"))
+}
+
+func makeAccessListCollector(cfg *utils.Config, log logger.Logger) *accessListCollector {
+	interval := cfg.ProfileInterval
+	if interval == 0 {
+		interval = 100_000
+	}
+	return &accessListCollector{
+		cfg:      cfg,
+		log:      log,
+		interval: utils.NewInterval(cfg.First, cfg.Last, interval),
+	}
+}
+
+// accessListCollector writes one csv row per transaction declaring an access list
+// and logs the aggregated effectiveness at the end of each profiling interval.
+type accessListCollector struct {
+	extension.NilExtension[txcontext.TxContext]
+	cfg      *utils.Config
+	log      logger.Logger
+	file     *os.File
+	writer   *csv.Writer
+	tracker  *accessListTracker
+	interval *utils.Interval
+	stats    accessListStats
+}
+
+// accessListStats aggregates access-list effectiveness over multiple transactions.
+type accessListStats struct {
+	transactions uint64 // number of transactions declaring an access list
+	declared     uint64 // number of declared addresses and slots
+	touched      uint64 // number of declared addresses and slots queried during execution
+}
+
+func (s accessListStats) coverage() float64 {
+	if s.declared == 0 {
+		return 0
+	}
+	return float64(s.touched) / float64(s.declared) * 100
+}
+
+func (s accessListStats) wasted() uint64 {
+	return s.declared - s.touched
+}
+
+// PreRun creates the csv file and wraps the StateDb to track access-list queries.
+func (c *accessListCollector) PreRun(_ executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	var err error
+	c.file, err = os.Create(c.cfg.AccessListStats)
+	if err != nil {
+		return fmt.Errorf("cannot create access-list stats file; %w", err)
+	}
+	c.writer = csv.NewWriter(c.file)
+	if err = c.writer.Write(accessListStatsHeader); err != nil {
+		return fmt.Errorf("cannot write access-list stats header; %w", err)
+	}
+
+	c.tracker = newAccessListTracker(ctx.State)
+	ctx.State = c.tracker
+	return nil
+}
+
+// PreBlock reports statistics of the previous interval once the block is beyond it.
+func (c *accessListCollector) PreBlock(state executor.State[txcontext.TxContext], _ *executor.Context) error {
+	if uint64(state.Block) > c.interval.End() {
+		c.report()
+		c.interval.Next()
+		c.stats = accessListStats{}
+	}
+	return nil
+}
+
+// PreTransaction forgets accesses of the previous transaction.
+func (c *accessListCollector) PreTransaction(executor.State[txcontext.TxContext], *executor.Context) error {
+	c.tracker.reset()
+	return nil
+}
+
+// PostTransaction compares the declared access list with the accessed entries.
+func (c *accessListCollector) PostTransaction(state executor.State[txcontext.TxContext], _ *executor.Context) error {
+	if state.Data == nil {
+		return nil
+	}
+	msg := state.Data.GetMessage()
+	if msg == nil || len(msg.AccessList) == 0 {
+		return nil
+	}
+
+	tx := c.tracker.evaluate(msg.AccessList)
+	c.stats.transactions++
+	c.stats.declared += tx.declared
+	c.stats.touched += tx.touched
+
+	err := c.writer.Write([]string{
+		strconv.Itoa(state.Block),
+		strconv.Itoa(state.Transaction),
+		strconv.FormatUint(tx.declared, 10),
+		strconv.FormatUint(tx.touched, 10),
+		strconv.FormatFloat(tx.coverage(), 'f', 2, 64),
+		strconv.FormatUint(tx.wasted(), 10),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot write access-list stats of tx %v/%v; %w", state.Block, state.Transaction, err)
+	}
+	return nil
+}
+
+// PostRun reports statistics of the last interval and closes the csv file.
+func (c *accessListCollector) PostRun(executor.State[txcontext.TxContext], *executor.Context, error) error {
+	if c.file == nil {
+		return nil
+	}
+	c.report()
+	c.writer.Flush()
+	return errors.Join(c.writer.Error(), c.file.Close())
+}
+
+func (c *accessListCollector) report() {
+	if c.stats.transactions == 0 {
+		return
+	}
+	c.log.Noticef("Blocks %v-%v: %v transactions declared %v access-list entries; coverage %.2f%%, %v (%.2f%%) never touched",
+		c.interval.Start(), c.interval.End(), c.stats.transactions, c.stats.declared,
+		c.stats.coverage(), c.stats.wasted(), 100-c.stats.coverage())
+}
+
+// accessListTracker is a thin StateDb wrapper recording which addresses and slots
+// were queried through the access-list methods. Queries are forwarded unchanged,
+// hence the warm/cold semantics of the EVM are not affected.
+type accessListTracker struct {
+	state.StateDB
+	addresses map[common.Address]struct{}
+	slots     map[common.Address]map[common.Hash]struct{}
+}
+
+func newAccessListTracker(db state.StateDB) *accessListTracker {
+	t := &accessListTracker{StateDB: db}
+	t.reset()
+	return t
+}
+
+func (t *accessListTracker) reset() {
+	t.addresses = make(map[common.Address]struct{})
+	t.slots = make(map[common.Address]map[common.Hash]struct{})
+}
+
+func (t *accessListTracker) AddressInAccessList(addr common.Address) bool {
+	t.addresses[addr] = struct{}{}
+	return t.StateDB.AddressInAccessList(addr)
+}
+
+func (t *accessListTracker) SlotInAccessList(addr common.Address, slot common.Hash) (bool, bool) {
+	slots, ok := t.slots[addr]
+	if !ok {
+		slots = make(map[common.Hash]struct{})
+		t.slots[addr] = slots
+	}
+	slots[slot] = struct{}{}
+	return t.StateDB.SlotInAccessList(addr, slot)
+}
+
+// evaluate counts declared entries of the access list and how many of them were
+// queried since the last reset. A declared address counts as touched if the address
+// itself or any of its slots was queried.
+func (t *accessListTracker) evaluate(declared types.AccessList) accessListStats {
+	res := accessListStats{transactions: 1}
+	for _, tuple := range declared {
+		res.declared++
+		_, addrTouched := t.addresses[tuple.Address]
+		slots, slotsTouched := t.slots[tuple.Address]
+		if addrTouched || slotsTouched {
+			res.touched++
+		}
+		for _, key := range tuple.StorageKeys {
+			res.declared++
+			if _, ok := slots[key]; ok {
+				res.touched++
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccessListCollector_NoCollectorIsCreatedIfDisabled(t *testing.T) {
+	ext := MakeAccessListCollector(&utils.Config{})
+	_, ok := ext.(extension.NilExtension[txcontext.TxContext])
+	assert.True(t, ok)
+}
+
+func TestAccessListTracker_ForwardsQueriesUnchanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	addr, slot := common.Address{1}, common.Hash{2}
+	db.EXPECT().AddressInAccessList(addr).Return(true)
+	db.EXPECT().SlotInAccessList(addr, slot).Return(true, false)
+
+	tracker := newAccessListTracker(db)
+	assert.True(t, tracker.AddressInAccessList(addr))
+	addrOk, slotOk := tracker.SlotInAccessList(addr, slot)
+	assert.True(t, addrOk)
+	assert.False(t, slotOk)
+}
+
+func TestAccessListTracker_EvaluateCountsTouchedAndWastedEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	db.EXPECT().SlotInAccessList(gomock.Any(), gomock.Any()).AnyTimes()
+	db.EXPECT().AddressInAccessList(gomock.Any()).AnyTimes()
+
+	tracker := newAccessListTracker(db)
+	tracker.SlotInAccessList(common.Address{1}, common.Hash{1})
+	tracker.AddressInAccessList(common.Address{3})
+
+	stats := tracker.evaluate(types.AccessList{
+		{Address: common.Address{1}, StorageKeys: []common.Hash{{1}, {2}}},
+		{Address: common.Address{2}, StorageKeys: []common.Hash{{1}}},
+		{Address: common.Address{3}},
+	})
+	assert.Equal(t, uint64(6), stats.declared)
+	assert.Equal(t, uint64(3), stats.touched)
+	assert.Equal(t, uint64(3), stats.wasted())
+	assert.Equal(t, 50.0, stats.coverage())
+
+	tracker.reset()
+	stats = tracker.evaluate(types.AccessList{{Address: common.Address{1}}})
+	assert.Equal(t, uint64(0), stats.touched)
+}
+
+func TestAccessListCollector_WritesRowPerTransactionWithAccessList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	db.EXPECT().SlotInAccessList(common.Address{1}, common.Hash{1}).Return(true, true)
+	log := logger.NewMockLogger(ctrl)
+	log.EXPECT().Noticef(gomock.Any(), uint64(10), uint64(19), uint64(1), uint64(3), gomock.Any(), uint64(1), gomock.Any())
+
+	withList := txcontext.NewMockTxContext(ctrl)
+	withList.EXPECT().GetMessage().Return(&core.Message{AccessList: types.AccessList{
+		{Address: common.Address{1}, StorageKeys: []common.Hash{{1}, {2}}},
+	}})
+	withoutList := txcontext.NewMockTxContext(ctrl)
+	withoutList.EXPECT().GetMessage().Return(&core.Message{})
+
+	cfg := &utils.Config{
+		First:           10,
+		Last:            20,
+		ProfileInterval: 10,
+		AccessListStats: filepath.Join(t.TempDir(), "access_list.csv"),
+	}
+	ext := makeAccessListCollector(cfg, log)
+	ctx := &executor.Context{State: db}
+	require.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+
+	require.NoError(t, ext.PreBlock(executor.State[txcontext.TxContext]{Block: 10}, ctx))
+	require.NoError(t, ext.PreTransaction(executor.State[txcontext.TxContext]{Block: 10, Transaction: 1}, ctx))
+	ctx.State.SlotInAccessList(common.Address{1}, common.Hash{1})
+	require.NoError(t, ext.PostTransaction(executor.State[txcontext.TxContext]{Block: 10, Transaction: 1, Data: withList}, ctx))
+	require.NoError(t, ext.PreTransaction(executor.State[txcontext.TxContext]{Block: 10, Transaction: 2}, ctx))
+	require.NoError(t, ext.PostTransaction(executor.State[txcontext.TxContext]{Block: 10, Transaction: 2, Data: withoutList}, ctx))
+	require.NoError(t, ext.PreBlock(executor.State[txcontext.TxContext]{Block: 20}, ctx))
+	require.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, nil))
+
+	content, err := os.ReadFile(cfg.AccessListStats)
+	require.NoError(t, err)
+	assert.Equal(t, "block,tx,declared,touched,coverage,wasted\n10,1,3,2,66.67,1\n", string(content))
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+const (
+	// contractCreation is the recipient reported for transactions deploying a contract.
+	contractCreation = "create"
+	// precompiles is the recipient reported for transactions sent to precompiled contracts if they are aggregated.
+	precompiles = "precompiles"
+)
+
+// Handling of transactions sent to precompiled contracts selected by --exclude-precompile-calls.
+const (
+	includePrecompileCalls   = "include"
+	aggregatePrecompileCalls = "aggregate"
+	excludePrecompileCalls   = "exclude"
+)
+
+const (
+	sqlite3_ContractGas_CreateTableIfNotExist = `
+		CREATE TABLE IF NOT EXISTS contract_gas (
+			first INTEGER NOT NULL,
+			last INTEGER NOT NULL,
+			rank INTEGER NOT NULL,
+			contract STRING NOT NULL,
+			calls INTEGER,
+			gas INTEGER,
+			avgGas FLOAT,
+			PRIMARY KEY (first, last, contract)
+		)
+	`
+	sqlite3_ContractGas_InsertOrReplace = `
+		INSERT or REPLACE INTO contract_gas (
+			first, last, rank, contract, calls, gas, avgGas
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?
+		)
+	`
+)
+
+// MakeContractGasProfiler creates an extension attributing the gas used by each transaction
+// to its recipient and reporting the contracts with the highest gas consumption at the end
+// of the run.
+func MakeContractGasProfiler(cfg *utils.Config) executor.Extension[txcontext.TxContext] {
+	if !cfg.ProfileContracts {
+		return extension.NilExtension[txcontext.TxContext]{}
+	}
+	return makeContractGasProfiler(cfg, logger.NewLogger(cfg.LogLevel, "Contract-Gas-Profiler"))
+}
+
+func makeContractGasProfiler(cfg *utils.Config, log logger.Logger) *contractGasProfiler {
+	top := cfg.ProfileContractsTop
+	if top <= 0 {
+		top = utils.ProfileContractsTopFlag.Value
+	}
+	precompileCalls := cfg.ExcludePrecompileCalls
+	if precompileCalls == "" {
+		precompileCalls = includePrecompileCalls
+	}
+	return &contractGasProfiler{
+		cfg:             cfg,
+		log:             log,
+		top:             top,
+		precompileCalls: precompileCalls,
+		contracts:       make(map[string]*contractGas),
+	}
+}
+
+type contractGasProfiler struct {
+	extension.NilExtension[txcontext.TxContext]
+	cfg             *utils.Config
+	log             logger.Logger
+	top             int
+	precompileCalls string
+	chainCfg        *params.ChainConfig // defines the precompiled contracts of each fork
+	lock            sync.Mutex
+	contracts       map[string]*contractGas
+}
+
+// contractGas is the gas consumption of transactions sent to a single contract.
+type contractGas struct {
+	contract string
+	calls    uint64
+	gas      uint64
+}
+
+func (c *contractGas) average() float64 {
+	if c.calls == 0 {
+		return 0
+	}
+	return float64(c.gas) / float64(c.calls)
+}
+
+// PreRun checks the handling of precompile calls and resolves the chain config
+// needed to classify recipients as precompiled contracts.
+func (p *contractGasProfiler) PreRun(executor.State[txcontext.TxContext], *executor.Context) error {
+	switch p.precompileCalls {
+	case includePrecompileCalls:
+		return nil
+	case aggregatePrecompileCalls, excludePrecompileCalls:
+	default:
+		return fmt.Errorf("unknown --%v %q; must be one of include, aggregate or exclude", utils.ExcludePrecompileCallsFlag.Name, p.precompileCalls)
+	}
+
+	chainCfg, err := p.cfg.GetChainConfig("")
+	if err != nil {
+		return fmt.Errorf("cannot get chain config; %w", err)
+	}
+	p.chainCfg = chainCfg
+	return nil
+}
+
+// PostTransaction attributes the gas used by the transaction to its recipient.
+func (p *contractGasProfiler) PostTransaction(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	if state.Data == nil || ctx.ExecutionResult == nil {
+		return nil
+	}
+	msg := state.Data.GetMessage()
+	if msg == nil {
+		return nil
+	}
+	contract := contractCreation
+	if msg.To != nil {
+		contract = msg.To.Hex()
+		if p.precompileCalls != includePrecompileCalls && p.isPrecompile(*msg.To, state.Data.GetBlockEnvironment()) {
+			if p.precompileCalls == excludePrecompileCalls {
+				return nil
+			}
+			contract = precompiles
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	stats, ok := p.contracts[contract]
+	if !ok {
+		stats = &contractGas{contract: contract}
+		p.contracts[contract] = stats
+	}
+	stats.calls++
+	stats.gas += ctx.ExecutionResult.GetGasUsed()
+	return nil
+}
+
+// isPrecompile reports whether the address is a precompiled contract of the fork active in the block environment.
+func (p *contractGasProfiler) isPrecompile(address common.Address, env txcontext.BlockEnvironment) bool {
+	return slices.Contains(vm.ActivePrecompiles(utils.GetRules(p.chainCfg, env)), address)
+}
+
+// PostRun logs the contracts with the highest gas consumption and stores them
+// into the sqlite3 profile DB if requested.
+func (p *contractGasProfiler) PostRun(executor.State[txcontext.TxContext], *executor.Context, error) error {
+	ranking := p.ranking()
+	if len(ranking) == 0 {
+		p.log.Notice("No transactions were profiled")
+		return nil
+	}
+	p.log.Noticef("Top %d contracts by gas used in blocks %d-%d\n%v", len(ranking), p.cfg.First, p.cfg.Last, p.prettyTable(ranking).Render())
+
+	if p.cfg.ProfileSqlite3 == "" {
+		return nil
+	}
+	printer, err := utils.NewPrinterToSqlite3(p.cfg.ProfileSqlite3, sqlite3_ContractGas_CreateTableIfNotExist, sqlite3_ContractGas_InsertOrReplace, func() [][]any {
+		values := make([][]any, 0, len(ranking))
+		for i, c := range ranking {
+			values = append(values, []any{p.cfg.First, p.cfg.Last, i + 1, c.contract, c.calls, c.gas, c.average()})
+		}
+		return values
+	})
+	if err != nil {
+		return fmt.Errorf("cannot open contract gas profile db; %w", err)
+	}
+	return errors.Join(printer.Print(), printer.Close())
+}
+
+// ranking returns the top contracts ordered by gas used; ties are ordered by address.
+func (p *contractGasProfiler) ranking() []contractGas {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	ranking := make([]contractGas, 0, len(p.contracts))
+	for _, c := range p.contracts {
+		ranking = append(ranking, *c)
+	}
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].gas != ranking[j].gas {
+			return ranking[i].gas > ranking[j].gas
+		}
+		return ranking[i].contract < ranking[j].contract
+	})
+	if len(ranking) > p.top {
+		ranking = ranking[:p.top]
+	}
+	return ranking
+}
+
+func (p *contractGasProfiler) prettyTable(ranking []contractGas) table.Writer {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"rank", "contract", "calls", "gas", "avg gas"})
+	for i, c := range ranking {
+		t.AppendRow(table.Row{i + 1, c.contract, c.calls, c.gas, fmt.Sprintf("%.2f", c.average())})
+	}
+	return t
+}
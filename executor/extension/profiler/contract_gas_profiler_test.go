@@ -0,0 +1,293 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestContractGasProfiler_NoProfilerIsCreatedIfDisabled(t *testing.T) {
+	cfg := &utils.Config{}
+	ext := MakeContractGasProfiler(cfg)
+	if _, ok := ext.(extension.NilExtension[txcontext.TxContext]); !ok {
+		t.Errorf("contract gas profiler is enabled although not set in configuration")
+	}
+}
+
+// runContractGasProfiler feeds given transactions, each a recipient (nil for a creation)
+// and the gas it used, through the profiler.
+func runContractGasProfiler(t *testing.T, ctrl *gomock.Controller, p *contractGasProfiler, txs []struct {
+	to  *common.Address
+	gas uint64
+}) {
+	for i, tx := range txs {
+		data := txcontext.NewMockTxContext(ctrl)
+		data.EXPECT().GetMessage().Return(&core.Message{To: tx.to})
+		result := txcontext.NewMockResult(ctrl)
+		result.EXPECT().GetGasUsed().Return(tx.gas)
+
+		state := executor.State[txcontext.TxContext]{Block: 1, Transaction: i, Data: data}
+		require.NoError(t, p.PostTransaction(state, &executor.Context{ExecutionResult: result}))
+	}
+}
+
+func TestContractGasProfiler_AggregatesGasPerRecipient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	a, b := common.Address{0xa}, common.Address{0xb}
+
+	p := makeContractGasProfiler(&utils.Config{ProfileContracts: true}, logger.NewMockLogger(ctrl))
+	runContractGasProfiler(t, ctrl, p, []struct {
+		to  *common.Address
+		gas uint64
+	}{
+		{&a, 100},
+		{&b, 1000},
+		{nil, 500},
+		{&a, 300},
+		{nil, 700},
+	})
+
+	assert.Equal(t, []contractGas{
+		{contract: contractCreation, calls: 2, gas: 1200},
+		{contract: b.Hex(), calls: 1, gas: 1000},
+		{contract: a.Hex(), calls: 2, gas: 400},
+	}, p.ranking())
+	assert.Equal(t, 200.0, p.contracts[a.Hex()].average())
+}
+
+func TestContractGasProfiler_RankingIsLimitedToTopContracts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	a, b, c := common.Address{1}, common.Address{2}, common.Address{3}
+
+	p := makeContractGasProfiler(&utils.Config{ProfileContracts: true, ProfileContractsTop: 2}, logger.NewMockLogger(ctrl))
+	runContractGasProfiler(t, ctrl, p, []struct {
+		to  *common.Address
+		gas uint64
+	}{
+		{&a, 100},
+		{&b, 100},
+		{&c, 300},
+	})
+
+	// ties are ordered by address
+	assert.Equal(t, []contractGas{
+		{contract: c.Hex(), calls: 1, gas: 300},
+		{contract: a.Hex(), calls: 1, gas: 100},
+	}, p.ranking())
+}
+
+func TestContractGasProfiler_TransactionsWithoutResultAreIgnored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	p := makeContractGasProfiler(&utils.Config{ProfileContracts: true}, logger.NewMockLogger(ctrl))
+
+	state := executor.State[txcontext.TxContext]{Block: 1, Data: txcontext.NewMockTxContext(ctrl)}
+	require.NoError(t, p.PostTransaction(state, &executor.Context{}))
+	require.NoError(t, p.PostTransaction(executor.State[txcontext.TxContext]{Block: 1}, &executor.Context{}))
+	assert.Empty(t, p.ranking())
+}
+
+func TestContractGasProfiler_PostRunLogsAndStoresTopContracts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	a := common.Address{0xa}
+
+	cfg := &utils.Config{
+		ProfileContracts: true,
+		ProfileSqlite3:   filepath.Join(t.TempDir(), "profile.db"),
+		First:            1,
+		Last:             10,
+	}
+	p := makeContractGasProfiler(cfg, log)
+	runContractGasProfiler(t, ctrl, p, []struct {
+		to  *common.Address
+		gas uint64
+	}{
+		{&a, 100},
+		{&a, 200},
+		{nil, 50},
+	})
+
+	log.EXPECT().Noticef("Top %d contracts by gas used in blocks %d-%d\n%v", 2, uint64(1), uint64(10), gomock.Any())
+	require.NoError(t, p.PostRun(executor.State[txcontext.TxContext]{}, &executor.Context{}, nil))
+
+	db, err := sql.Open("sqlite3", cfg.ProfileSqlite3)
+	require.NoError(t, err)
+	defer db.Close()
+	rows, err := db.Query("SELECT rank, contract, calls, gas, avgGas FROM contract_gas ORDER BY rank")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	type row struct {
+		rank, calls, gas int
+		contract         string
+		avgGas           float64
+	}
+	var got []row
+	for rows.Next() {
+		var r row
+		require.NoError(t, rows.Scan(&r.rank, &r.contract, &r.calls, &r.gas, &r.avgGas))
+		got = append(got, r)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []row{
+		{rank: 1, contract: a.Hex(), calls: 2, gas: 300, avgGas: 150},
+		{rank: 2, contract: contractCreation, calls: 1, gas: 50, avgGas: 50},
+	}, got)
+}
+
+func TestContractGasProfiler_PostRunWithoutTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	log.EXPECT().Notice("No transactions were profiled")
+
+	p := makeContractGasProfiler(&utils.Config{ProfileContracts: true}, log)
+	require.NoError(t, p.PostRun(executor.State[txcontext.TxContext]{}, &executor.Context{}, nil))
+}
+
+// newBlockEnvironment mocks a post-merge block environment of Ethereum mainnet at given time.
+func newBlockEnvironment(ctrl *gomock.Controller, time uint64) *txcontext.MockBlockEnvironment {
+	env := txcontext.NewMockBlockEnvironment(ctrl)
+	env.EXPECT().GetNumber().Return(uint64(19_000_000)).AnyTimes()
+	env.EXPECT().GetRandom().Return(&common.Hash{1}).AnyTimes()
+	env.EXPECT().GetTimestamp().Return(time).AnyTimes()
+	return env
+}
+
+func TestContractGasProfiler_PrecompilesAreDerivedFromActiveFork(t *testing.T) {
+	cancun := *params.MainnetChainConfig.CancunTime
+	tests := map[string]struct {
+		time    uint64
+		address common.Address
+		want    bool
+	}{
+		"ecrecover pre-Cancun":           {cancun - 1, common.BytesToAddress([]byte{0x01}), true},
+		"blake2f pre-Cancun":             {cancun - 1, common.BytesToAddress([]byte{0x09}), true},
+		"point evaluation pre-Cancun":    {cancun - 1, common.BytesToAddress([]byte{0x0a}), false},
+		"ecrecover post-Cancun":          {cancun, common.BytesToAddress([]byte{0x01}), true},
+		"point evaluation post-Cancun":   {cancun, common.BytesToAddress([]byte{0x0a}), true},
+		"bls12-381 g1 add post-Cancun":   {cancun, common.BytesToAddress([]byte{0x0b}), false},
+		"regular contract post-Cancun":   {cancun, common.Address{0xa}, false},
+		"regular contract pre-Cancun":    {cancun - 1, common.Address{0xa}, false},
+		"precompile-like suffix address": {cancun, common.Address{0xa, 0x01}, false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			cfg := &utils.Config{ProfileContracts: true, ChainID: utils.EthereumChainID, ExcludePrecompileCalls: aggregatePrecompileCalls}
+			p := makeContractGasProfiler(cfg, logger.NewMockLogger(ctrl))
+			require.NoError(t, p.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{}))
+
+			assert.Equal(t, test.want, p.isPrecompile(test.address, newBlockEnvironment(ctrl, test.time)))
+		})
+	}
+}
+
+// runPrecompileCalls feeds transactions to ecrecover, the point evaluation precompile and a regular
+// contract of a block at given time through a profiler handling precompile calls by given mode.
+func runPrecompileCalls(t *testing.T, mode string, time uint64) []contractGas {
+	ctrl := gomock.NewController(t)
+	cfg := &utils.Config{ProfileContracts: true, ChainID: utils.EthereumChainID, ExcludePrecompileCalls: mode}
+	p := makeContractGasProfiler(cfg, logger.NewMockLogger(ctrl))
+	require.NoError(t, p.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{}))
+
+	env := newBlockEnvironment(ctrl, time)
+	for i, tx := range []struct {
+		to  common.Address
+		gas uint64
+	}{
+		{common.BytesToAddress([]byte{0x01}), 100},
+		{common.BytesToAddress([]byte{0x0a}), 200},
+		{common.Address{0xa}, 50},
+	} {
+		data := txcontext.NewMockTxContext(ctrl)
+		data.EXPECT().GetMessage().Return(&core.Message{To: &tx.to})
+		data.EXPECT().GetBlockEnvironment().Return(env).AnyTimes()
+		result := txcontext.NewMockResult(ctrl)
+		result.EXPECT().GetGasUsed().Return(tx.gas).AnyTimes()
+
+		state := executor.State[txcontext.TxContext]{Block: 1, Transaction: i, Data: data}
+		require.NoError(t, p.PostTransaction(state, &executor.Context{ExecutionResult: result}))
+	}
+	return p.ranking()
+}
+
+func TestContractGasProfiler_PrecompileCallsAreAggregated(t *testing.T) {
+	cancun := *params.MainnetChainConfig.CancunTime
+	a := common.Address{0xa}
+	pointEvaluation := common.BytesToAddress([]byte{0x0a})
+
+	assert.Equal(t, []contractGas{
+		{contract: precompiles, calls: 2, gas: 300},
+		{contract: a.Hex(), calls: 1, gas: 50},
+	}, runPrecompileCalls(t, aggregatePrecompileCalls, cancun))
+
+	assert.Equal(t, []contractGas{
+		{contract: pointEvaluation.Hex(), calls: 1, gas: 200},
+		{contract: precompiles, calls: 1, gas: 100},
+		{contract: a.Hex(), calls: 1, gas: 50},
+	}, runPrecompileCalls(t, aggregatePrecompileCalls, cancun-1))
+}
+
+func TestContractGasProfiler_PrecompileCallsAreExcluded(t *testing.T) {
+	cancun := *params.MainnetChainConfig.CancunTime
+	a := common.Address{0xa}
+	pointEvaluation := common.BytesToAddress([]byte{0x0a})
+
+	assert.Equal(t, []contractGas{
+		{contract: a.Hex(), calls: 1, gas: 50},
+	}, runPrecompileCalls(t, excludePrecompileCalls, cancun))
+
+	assert.Equal(t, []contractGas{
+		{contract: pointEvaluation.Hex(), calls: 1, gas: 200},
+		{contract: a.Hex(), calls: 1, gas: 50},
+	}, runPrecompileCalls(t, excludePrecompileCalls, cancun-1))
+}
+
+func TestContractGasProfiler_PrecompileCallsAreIncludedByDefault(t *testing.T) {
+	a := common.Address{0xa}
+	ecrecover := common.BytesToAddress([]byte{0x01})
+	pointEvaluation := common.BytesToAddress([]byte{0x0a})
+
+	assert.Equal(t, []contractGas{
+		{contract: pointEvaluation.Hex(), calls: 1, gas: 200},
+		{contract: ecrecover.Hex(), calls: 1, gas: 100},
+		{contract: a.Hex(), calls: 1, gas: 50},
+	}, runPrecompileCalls(t, "", *params.MainnetChainConfig.CancunTime))
+}
+
+func TestContractGasProfiler_PreRunFailsOnUnknownPrecompileHandling(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfg := &utils.Config{ProfileContracts: true, ExcludePrecompileCalls: "ignore"}
+	p := makeContractGasProfiler(cfg, logger.NewMockLogger(ctrl))
+
+	err := p.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{})
+	require.ErrorContains(t, err, `unknown --exclude-precompile-calls "ignore"`)
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/profile/opcodeprofile"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+// opcodeProfiled is implemented by processors collecting per-opcode statistics.
+type opcodeProfiled interface {
+	OpcodeProfile() *opcodeprofile.Profile
+}
+
+// MakeOpcodeProfilePrinter creates an extension reporting the per-opcode statistics
+// collected by the processor at the end of a run. The statistics are written to the
+// profiling db if --profiling-db-name is set, otherwise they are printed as a table.
+func MakeOpcodeProfilePrinter[T any](cfg *utils.Config, processor executor.Processor[T]) executor.Extension[T] {
+	if !cfg.MicroProfiling {
+		return extension.NilExtension[T]{}
+	}
+	p, ok := processor.(opcodeProfiled)
+	if !ok || p.OpcodeProfile() == nil {
+		return extension.NilExtension[T]{}
+	}
+
+	log := logger.NewLogger(cfg.LogLevel, "Opcode-Profile-Printer")
+	return makeOpcodeProfilePrinter[T](cfg, p.OpcodeProfile(), os.Stdout, log)
+}
+
+func makeOpcodeProfilePrinter[T any](cfg *utils.Config, profile *opcodeprofile.Profile, out io.Writer, log logger.Logger) executor.Extension[T] {
+	return &opcodeProfilePrinter[T]{
+		cfg:     cfg,
+		profile: profile,
+		out:     out,
+		log:     log,
+	}
+}
+
+type opcodeProfilePrinter[T any] struct {
+	extension.NilExtension[T]
+	cfg     *utils.Config
+	profile *opcodeprofile.Profile
+	out     io.Writer
+	log     logger.Logger
+}
+
+func (p *opcodeProfilePrinter[T]) PostRun(executor.State[T], *executor.Context, error) error {
+	if p.profile.IsEmpty() {
+		p.log.Warningf("No opcodes were profiled; the %v interpreter may not support tracing", p.cfg.VmImpl)
+	}
+
+	if p.cfg.ProfilingDbName != "" {
+		if err := p.profile.WriteToDb(p.cfg.ProfilingDbName, p.cfg.VmImpl); err != nil {
+			return fmt.Errorf("cannot write opcode profile; %w", err)
+		}
+		p.log.Noticef("Opcode profile was written to %v", p.cfg.ProfilingDbName)
+		return nil
+	}
+
+	if err := p.profile.WriteTable(p.out); err != nil {
+		return fmt.Errorf("cannot print opcode profile; %w", err)
+	}
+	return nil
+}
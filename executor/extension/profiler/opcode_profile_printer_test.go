@@ -0,0 +1,117 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"bytes"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/profile/opcodeprofile"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type opcodeProfiledProcessor struct {
+	executor.Processor[any]
+	profile *opcodeprofile.Profile
+}
+
+func (p opcodeProfiledProcessor) OpcodeProfile() *opcodeprofile.Profile {
+	return p.profile
+}
+
+// makeOpcodeProfile returns a profile with a single recorded SSTORE.
+func makeOpcodeProfile() *opcodeprofile.Profile {
+	profile := opcodeprofile.NewProfile()
+	collector := profile.NewCollector()
+	collector.Hooks(nil).OnOpcode(0, byte(vm.SSTORE), 0, 20_000, nil, nil, 0, nil)
+	collector.Flush()
+	return profile
+}
+
+func TestOpcodeProfilePrinter_NoPrinterIsCreatedIfDisabled(t *testing.T) {
+	ext := MakeOpcodeProfilePrinter[any](&utils.Config{}, opcodeProfiledProcessor{profile: opcodeprofile.NewProfile()})
+	_, ok := ext.(extension.NilExtension[any])
+	assert.True(t, ok)
+}
+
+func TestOpcodeProfilePrinter_NoPrinterIsCreatedForProcessorWithoutProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfg := &utils.Config{MicroProfiling: true}
+
+	ext := MakeOpcodeProfilePrinter[any](cfg, executor.NewMockProcessor[any](ctrl))
+	_, ok := ext.(extension.NilExtension[any])
+	assert.True(t, ok)
+
+	ext = MakeOpcodeProfilePrinter[any](cfg, opcodeProfiledProcessor{})
+	_, ok = ext.(extension.NilExtension[any])
+	assert.True(t, ok)
+}
+
+func TestOpcodeProfilePrinter_PrintsTableIfNoDbIsSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{MicroProfiling: true}
+
+	var out bytes.Buffer
+	ext := makeOpcodeProfilePrinter[any](cfg, makeOpcodeProfile(), &out, log)
+	require.NoError(t, ext.PostRun(executor.State[any]{}, nil, nil))
+	assert.Contains(t, out.String(), "SSTORE")
+	assert.Contains(t, out.String(), "20000")
+}
+
+func TestOpcodeProfilePrinter_WritesProfileToDb(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{
+		MicroProfiling:  true,
+		ProfilingDbName: filepath.Join(t.TempDir(), "profiling.db"),
+		VmImpl:          "geth",
+	}
+	log.EXPECT().Noticef("Opcode profile was written to %v", cfg.ProfilingDbName)
+
+	var out bytes.Buffer
+	ext := makeOpcodeProfilePrinter[any](cfg, makeOpcodeProfile(), &out, log)
+	require.NoError(t, ext.PostRun(executor.State[any]{}, nil, nil))
+	assert.Empty(t, out.String())
+
+	db, err := sql.Open("sqlite3", cfg.ProfilingDbName)
+	require.NoError(t, err)
+	defer db.Close()
+	var count uint64
+	require.NoError(t, db.QueryRow("SELECT count FROM opcodeProfile WHERE vm = 'geth' AND opcode = 'SSTORE'").Scan(&count))
+	assert.Equal(t, uint64(1), count)
+}
+
+func TestOpcodeProfilePrinter_WarnsIfNoOpcodeWasProfiled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{MicroProfiling: true, VmImpl: "lfvm"}
+	log.EXPECT().Warningf(gomock.Any(), "lfvm")
+
+	var out bytes.Buffer
+	ext := makeOpcodeProfilePrinter[any](cfg, opcodeprofile.NewProfile(), &out, log)
+	require.NoError(t, ext.PostRun(executor.State[any]{}, nil, nil))
+}
@@ -17,6 +17,8 @@
 package profiler
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/0xsoniclabs/aida/executor"
@@ -253,6 +255,24 @@ func (p *operationProfiler[T]) PostRun(executor.State[T], *executor.Context, err
 	return nil
 }
 
+// ReportLatencies describes latencies of StateDb operations recorded in the current interval.
+// It is meant for diagnostics of a stalled run, hence the analytics are read without synchronization.
+func (p *operationProfiler[T]) ReportLatencies() string {
+	var sb strings.Builder
+	for opId, stat := range p.anlts[IntervalLevel].Iterate() {
+		if stat.GetCount() == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "%v: n %d, mean %v, max %v\n",
+			p.ops[byte(opId)],
+			stat.GetCount(),
+			time.Duration(stat.GetMean()),
+			time.Duration(stat.GetMax()),
+		)
+	}
+	return sb.String()
+}
+
 //
 // Printer-related
 //
@@ -0,0 +1,241 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// otelShutdownTimeout limits how long PostRun waits for the remaining spans to be exported.
+const otelShutdownTimeout = 10 * time.Second
+
+// MakeOtelTracer creates an extension exporting the run as an OpenTelemetry trace to
+// the OTLP/HTTP endpoint given by --otlp-endpoint. The trace consists of a root span
+// of the run with child spans for the priming, execution and post-validation phase,
+// and a span for every n-th block as configured by --otlp-block-sampling. Export
+// failures are only logged, hence an unreachable endpoint does not affect the run.
+// The extension has to be registered first so that its PreRun is called before and
+// its PostRun after all other extensions.
+func MakeOtelTracer[T any](cfg *utils.Config) executor.Extension[T] {
+	if cfg.OtlpEndpoint == "" {
+		return extension.NilExtension[T]{}
+	}
+	return makeOtelTracer[T](cfg, logger.NewLogger(cfg.LogLevel, "Otel-Tracer"), newOtlpExporter)
+}
+
+func makeOtelTracer[T any](cfg *utils.Config, log logger.Logger, newExporter func(context.Context, string) (sdktrace.SpanExporter, error)) *otelTracer[T] {
+	return &otelTracer[T]{
+		cfg:         cfg,
+		log:         log,
+		newExporter: newExporter,
+		blocks:      make(map[int]*blockSpan),
+	}
+}
+
+// newOtlpExporter creates an exporter sending spans to given endpoint, which is
+// either a URL or a plain host:port served without TLS.
+func newOtlpExporter(ctx context.Context, endpoint string) (sdktrace.SpanExporter, error) {
+	if strings.Contains(endpoint, "://") {
+		return otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	return otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(endpoint), otlptracehttp.WithInsecure())
+}
+
+// blockSpan is the span of a sampled block together with its statistics.
+type blockSpan struct {
+	span    trace.Span
+	txCount int
+	gasUsed uint64
+}
+
+type otelTracer[T any] struct {
+	extension.NilExtension[T]
+	cfg         *utils.Config
+	log         logger.Logger
+	newExporter func(context.Context, string) (sdktrace.SpanExporter, error)
+
+	provider  *sdktrace.TracerProvider
+	tracer    trace.Tracer
+	ctx       context.Context // context of the root span
+	root      trace.Span
+	priming   trace.Span
+	execution trace.Span
+
+	mu           sync.Mutex
+	blocks       map[int]*blockSpan // sampled blocks in progress
+	lastBlockEnd time.Time          // end of the last processed block
+}
+
+// PreRun starts the root span of the run and the span of the priming phase.
+func (t *otelTracer[T]) PreRun(executor.State[T], *executor.Context) error {
+	exporter, err := t.newExporter(context.Background(), t.cfg.OtlpEndpoint)
+	if err != nil {
+		t.log.Warningf("Cannot create OTLP exporter for %v, tracing is disabled; %v", t.cfg.OtlpEndpoint, err)
+		return nil
+	}
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		t.log.Warningf("Cannot export traces to %v; %v", t.cfg.OtlpEndpoint, err)
+	}))
+
+	t.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "aida"))),
+	)
+	t.tracer = t.provider.Tracer("github.com/0xsoniclabs/aida")
+	t.ctx, t.root = t.tracer.Start(context.Background(), "run", trace.WithAttributes(
+		attribute.Int64("aida.chain_id", int64(t.cfg.ChainID)),
+		attribute.Int64("aida.first_block", int64(t.cfg.First)),
+		attribute.Int64("aida.last_block", int64(t.cfg.Last)),
+		attribute.String("aida.db_impl", t.cfg.DbImpl),
+		attribute.String("aida.db_variant", t.cfg.DbVariant),
+		attribute.String("aida.vm_impl", t.cfg.VmImpl),
+		attribute.Int("aida.workers", t.cfg.Workers),
+	))
+	_, t.priming = t.tracer.Start(t.ctx, "priming")
+	t.log.Noticef("Exporting traces to %v", t.cfg.OtlpEndpoint)
+	return nil
+}
+
+// PreBlock ends the priming phase once the first block starts and opens a span for sampled blocks.
+func (t *otelTracer[T]) PreBlock(state executor.State[T], _ *executor.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.execution == nil {
+		t.priming.End()
+		_, t.execution = t.tracer.Start(t.ctx, "execution")
+	}
+	if t.isSampled(state.Block) {
+		_, span := t.tracer.Start(trace.ContextWithSpan(t.ctx, t.execution), "block",
+			trace.WithAttributes(attribute.Int("aida.block", state.Block)))
+		t.blocks[state.Block] = &blockSpan{span: span}
+	}
+	return nil
+}
+
+// PostTransaction collects the statistics of sampled blocks.
+func (t *otelTracer[T]) PostTransaction(state executor.State[T], ctx *executor.Context) error {
+	if t.provider == nil || !t.isSampled(state.Block) {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if b, found := t.blocks[state.Block]; found {
+		b.txCount++
+		if ctx.ExecutionResult != nil {
+			b.gasUsed += ctx.ExecutionResult.GetGasUsed()
+		}
+	}
+	return nil
+}
+
+// PostBlock ends the span of a sampled block. It is only reached if no other
+// extension reported a failure of the block, hence the block passed validation.
+func (t *otelTracer[T]) PostBlock(state executor.State[T], _ *executor.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastBlockEnd = time.Now()
+	if b, found := t.blocks[state.Block]; found {
+		b.end("passed")
+		delete(t.blocks, state.Block)
+	}
+	return nil
+}
+
+// PostRun ends all remaining spans and flushes them to the exporter.
+func (t *otelTracer[T]) PostRun(_ executor.State[T], _ *executor.Context, err error) error {
+	if t.provider == nil {
+		return nil
+	}
+
+	status := "passed"
+	if err != nil {
+		status = "failed"
+	}
+
+	t.mu.Lock()
+	for _, b := range t.blocks {
+		b.end("failed")
+	}
+	t.blocks = make(map[int]*blockSpan)
+	if t.execution == nil {
+		t.priming.End()
+	} else {
+		// PostRun of all other extensions has been called since the last block,
+		// so the time in between is spent in the post-validation phase
+		executionEnd := t.lastBlockEnd
+		if executionEnd.IsZero() {
+			executionEnd = time.Now()
+		}
+		t.execution.End(trace.WithTimestamp(executionEnd))
+		_, validation := t.tracer.Start(t.ctx, "post-validation", trace.WithTimestamp(executionEnd))
+		validation.SetAttributes(attribute.String("aida.validation_status", status))
+		validation.End()
+	}
+	t.mu.Unlock()
+
+	t.root.SetAttributes(attribute.String("aida.validation_status", status))
+	if err != nil {
+		t.root.RecordError(err)
+		t.root.SetStatus(codes.Error, err.Error())
+	}
+	t.root.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), otelShutdownTimeout)
+	defer cancel()
+	if err := t.provider.Shutdown(ctx); err != nil {
+		t.log.Warningf("Cannot flush traces to %v; %v", t.cfg.OtlpEndpoint, err)
+	}
+	return nil
+}
+
+// isSampled returns true if a span should be recorded for given block.
+func (t *otelTracer[T]) isSampled(block int) bool {
+	return t.cfg.OtlpBlockSampling > 0 && block%t.cfg.OtlpBlockSampling == 0
+}
+
+func (b *blockSpan) end(status string) {
+	b.span.SetAttributes(
+		attribute.Int("aida.tx_count", b.txCount),
+		attribute.Int64("aida.gas_used", int64(b.gasUsed)),
+		attribute.String("aida.validation_status", status),
+	)
+	if status != "passed" {
+		b.span.SetStatus(codes.Error, "block did not finish")
+	}
+	b.span.End()
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/mock/gomock"
+)
+
+// keptSpansExporter keeps the exported spans on shutdown so that they can be inspected.
+type keptSpansExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (keptSpansExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func newTestOtelTracer(t *testing.T, cfg *utils.Config) (*otelTracer[any], *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	newExporter := func(_ context.Context, endpoint string) (sdktrace.SpanExporter, error) {
+		assert.Equal(t, cfg.OtlpEndpoint, endpoint)
+		return keptSpansExporter{exporter}, nil
+	}
+	return makeOtelTracer[any](cfg, logger.NewLogger("critical", "test"), newExporter), exporter
+}
+
+// spansByName returns the exported spans indexed by their name; blocks are indexed by their number.
+func spansByName(exporter *tracetest.InMemoryExporter) map[string]tracetest.SpanStub {
+	res := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		name := span.Name
+		for _, attr := range span.Attributes {
+			if attr.Key == "aida.block" {
+				name = attr.Value.Emit()
+			}
+		}
+		res[name] = span
+	}
+	return res
+}
+
+func attributeOf(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestOtelTracer_NoTracerIsCreatedIfEndpointIsNotSet(t *testing.T) {
+	ext := MakeOtelTracer[any](&utils.Config{})
+	_, ok := ext.(extension.NilExtension[any])
+	assert.True(t, ok)
+}
+
+func TestOtelTracer_ExportsRunPhasesAndSampledBlocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	result := txcontext.NewMockResult(ctrl)
+	result.EXPECT().GetGasUsed().Return(uint64(21_000)).Times(2)
+
+	cfg := &utils.Config{OtlpEndpoint: "localhost:4318", OtlpBlockSampling: 10, First: 10, Last: 11}
+	ext, exporter := newTestOtelTracer(t, cfg)
+	ctx := &executor.Context{ExecutionResult: result}
+
+	require.NoError(t, ext.PreRun(executor.State[any]{}, ctx))
+	for _, block := range []int{10, 11} {
+		require.NoError(t, ext.PreBlock(executor.State[any]{Block: block}, ctx))
+		for tx := 0; tx < 2; tx++ {
+			require.NoError(t, ext.PostTransaction(executor.State[any]{Block: block, Transaction: tx}, ctx))
+		}
+		require.NoError(t, ext.PostBlock(executor.State[any]{Block: block}, ctx))
+	}
+	require.NoError(t, ext.PostRun(executor.State[any]{}, ctx, nil))
+
+	spans := spansByName(exporter)
+	require.Len(t, spans, 5)
+	root := spans["run"]
+	assert.Equal(t, int64(10), attributeOf(root, "aida.first_block").AsInt64())
+	assert.Equal(t, "passed", attributeOf(root, "aida.validation_status").AsString())
+	for _, phase := range []string{"priming", "execution", "post-validation"} {
+		require.Contains(t, spans, phase)
+		assert.Equal(t, root.SpanContext.SpanID(), spans[phase].Parent.SpanID(), phase)
+	}
+
+	block := spans["10"]
+	assert.Equal(t, spans["execution"].SpanContext.SpanID(), block.Parent.SpanID())
+	assert.Equal(t, int64(2), attributeOf(block, "aida.tx_count").AsInt64())
+	assert.Equal(t, int64(42_000), attributeOf(block, "aida.gas_used").AsInt64())
+	assert.Equal(t, "passed", attributeOf(block, "aida.validation_status").AsString())
+	assert.NotContains(t, spans, "11")
+}
+
+func TestOtelTracer_MarksUnfinishedBlocksAndRunAsFailed(t *testing.T) {
+	cfg := &utils.Config{OtlpEndpoint: "localhost:4318", OtlpBlockSampling: 1}
+	ext, exporter := newTestOtelTracer(t, cfg)
+
+	require.NoError(t, ext.PreRun(executor.State[any]{}, nil))
+	require.NoError(t, ext.PreBlock(executor.State[any]{Block: 5}, &executor.Context{}))
+	require.NoError(t, ext.PostRun(executor.State[any]{}, nil, errors.New("validation failed")))
+
+	spans := spansByName(exporter)
+	assert.Equal(t, "failed", attributeOf(spans["5"], "aida.validation_status").AsString())
+	assert.Equal(t, codes.Error, spans["5"].Status.Code)
+	assert.Equal(t, "failed", attributeOf(spans["run"], "aida.validation_status").AsString())
+	assert.Equal(t, codes.Error, spans["run"].Status.Code)
+	assert.Contains(t, spans, "post-validation")
+}
+
+func TestOtelTracer_EndsPrimingIfNoBlockWasProcessed(t *testing.T) {
+	cfg := &utils.Config{OtlpEndpoint: "localhost:4318"}
+	ext, exporter := newTestOtelTracer(t, cfg)
+
+	require.NoError(t, ext.PreRun(executor.State[any]{}, nil))
+	require.NoError(t, ext.PostRun(executor.State[any]{}, nil, nil))
+
+	spans := spansByName(exporter)
+	assert.Len(t, spans, 2)
+	assert.Contains(t, spans, "priming")
+	assert.Contains(t, spans, "run")
+}
+
+func TestOtelTracer_IsNoOpIfExporterCannotBeCreated(t *testing.T) {
+	cfg := &utils.Config{OtlpEndpoint: "localhost:4318", OtlpBlockSampling: 1}
+	ext := makeOtelTracer[any](cfg, logger.NewLogger("critical", "test"), func(context.Context, string) (sdktrace.SpanExporter, error) {
+		return nil, errors.New("no exporter")
+	})
+
+	require.NoError(t, ext.PreRun(executor.State[any]{}, nil))
+	require.NoError(t, ext.PreBlock(executor.State[any]{Block: 1}, nil))
+	require.NoError(t, ext.PostTransaction(executor.State[any]{Block: 1}, nil))
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 1}, nil))
+	require.NoError(t, ext.PostRun(executor.State[any]{}, nil, nil))
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/rpc"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// unknownRpcMethod is the method reported for requests without a decoded query.
+const unknownRpcMethod = "unknown"
+
+// MakeRpcLatencyProfiler creates an extension comparing the upstream latency recorded with
+// each request to the time needed to execute the request locally. Per-method statistics are
+// logged at the end of the run and written into the JSON file set by --latency-report.
+func MakeRpcLatencyProfiler(cfg *utils.Config) executor.Extension[*rpc.RequestAndResults] {
+	if !cfg.RpcCompareLatency {
+		return extension.NilExtension[*rpc.RequestAndResults]{}
+	}
+	return makeRpcLatencyProfiler(cfg, logger.NewLogger(cfg.LogLevel, "Rpc-Latency-Profiler"))
+}
+
+func makeRpcLatencyProfiler(cfg *utils.Config, log logger.Logger) *rpcLatencyProfiler {
+	return &rpcLatencyProfiler{
+		cfg:     cfg,
+		log:     log,
+		starts:  make(map[*rpc.RequestAndResults]time.Time),
+		methods: make(map[string]*methodLatency),
+	}
+}
+
+// rpcLatencyProfiler measures the time between PreTransaction and PostTransaction of each
+// request. It should be registered as the last extension, so that the measured time covers
+// the execution of the request rather than the work of other extensions.
+type rpcLatencyProfiler struct {
+	extension.NilExtension[*rpc.RequestAndResults]
+	cfg     *utils.Config
+	log     logger.Logger
+	lock    sync.Mutex
+	starts  map[*rpc.RequestAndResults]time.Time
+	methods map[string]*methodLatency
+}
+
+// methodLatency collects the latencies of all requests of a single method. Only requests
+// with a recorded latency contribute to the percentiles.
+type methodLatency struct {
+	count    uint64
+	recorded []time.Duration
+	replayed []time.Duration
+}
+
+func (m *methodLatency) add(recorded, replayed time.Duration) {
+	m.count++
+	if recorded <= 0 {
+		return
+	}
+	m.recorded = append(m.recorded, recorded)
+	m.replayed = append(m.replayed, replayed)
+}
+
+// rpcLatencyStats is the latency comparison of a single method.
+type rpcLatencyStats struct {
+	Method   string         `json:"method"`
+	Count    uint64         `json:"count"`
+	Timed    uint64         `json:"timed"`
+	Recorded latencySummary `json:"recorded"`
+	Replayed latencySummary `json:"replayed"`
+	Speedup  float64        `json:"speedup"` // total recorded latency divided by total replay time
+}
+
+// latencySummary describes a set of latencies; all values are in nanoseconds.
+type latencySummary struct {
+	P50 time.Duration `json:"p50"`
+	P95 time.Duration `json:"p95"`
+	Max time.Duration `json:"max"`
+}
+
+// PreTransaction starts the timer of the request.
+func (p *rpcLatencyProfiler) PreTransaction(state executor.State[*rpc.RequestAndResults], _ *executor.Context) error {
+	start := time.Now()
+	p.lock.Lock()
+	p.starts[state.Data] = start
+	p.lock.Unlock()
+	return nil
+}
+
+// PostTransaction stops the timer of the request and adds it to the statistics of its method.
+func (p *rpcLatencyProfiler) PostTransaction(state executor.State[*rpc.RequestAndResults], _ *executor.Context) error {
+	end := time.Now()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	start, ok := p.starts[state.Data]
+	if !ok || state.Data == nil {
+		return nil
+	}
+	delete(p.starts, state.Data)
+	p.add(state.Data, end.Sub(start))
+	return nil
+}
+
+// add records the replay time of the request; must be called with the lock held.
+func (p *rpcLatencyProfiler) add(req *rpc.RequestAndResults, replayed time.Duration) {
+	method := unknownRpcMethod
+	if req.Query != nil && req.Query.Method != "" {
+		method = req.Query.Method
+	}
+	m, ok := p.methods[method]
+	if !ok {
+		m = &methodLatency{}
+		p.methods[method] = m
+	}
+	m.add(req.RecordedLatency, replayed)
+}
+
+// PostRun logs the per-method statistics and writes them into the latency report if requested.
+func (p *rpcLatencyProfiler) PostRun(executor.State[*rpc.RequestAndResults], *executor.Context, error) error {
+	stats := p.stats()
+	if len(stats) == 0 {
+		p.log.Notice("No requests were profiled")
+		return nil
+	}
+	p.log.Noticef("Recorded latency compared to replay time\n%v", p.prettyTable(stats).Render())
+
+	if p.cfg.RpcLatencyReport == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode latency report; %w", err)
+	}
+	if err = os.WriteFile(p.cfg.RpcLatencyReport, data, 0644); err != nil {
+		return fmt.Errorf("cannot write latency report; %w", err)
+	}
+	return nil
+}
+
+// stats returns the statistics of all methods ordered by name.
+func (p *rpcLatencyProfiler) stats() []rpcLatencyStats {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	stats := make([]rpcLatencyStats, 0, len(p.methods))
+	for method, m := range p.methods {
+		stats = append(stats, rpcLatencyStats{
+			Method:   method,
+			Count:    m.count,
+			Timed:    uint64(len(m.recorded)),
+			Recorded: summarize(m.recorded),
+			Replayed: summarize(m.replayed),
+			Speedup:  speedup(m.recorded, m.replayed),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Method < stats[j].Method
+	})
+	return stats
+}
+
+func (p *rpcLatencyProfiler) prettyTable(stats []rpcLatencyStats) table.Writer {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"method", "count", "timed", "recorded p50", "recorded p95", "recorded max", "replay p50", "replay p95", "replay max", "speedup"})
+	for _, s := range stats {
+		t.AppendRow(table.Row{
+			s.Method, s.Count, s.Timed,
+			s.Recorded.P50, s.Recorded.P95, s.Recorded.Max,
+			s.Replayed.P50, s.Replayed.P95, s.Replayed.Max,
+			fmt.Sprintf("%.2f", s.Speedup),
+		})
+	}
+	return t
+}
+
+// summarize computes the percentiles of the given latencies using the nearest-rank method.
+func summarize(latencies []time.Duration) latencySummary {
+	if len(latencies) == 0 {
+		return latencySummary{}
+	}
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+	return latencySummary{
+		P50: percentile(sorted, 50),
+		P95: percentile(sorted, 95),
+		Max: sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the p-th percentile (0 < p <= 100) of the given sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = max(1, min(rank, len(sorted)))
+	return sorted[rank-1]
+}
+
+// speedup returns how many times faster the replay was than the upstream node,
+// or zero if no request was timed.
+func speedup(recorded, replayed []time.Duration) float64 {
+	var recordedTotal, replayedTotal time.Duration
+	for i := range recorded {
+		recordedTotal += recorded[i]
+		replayedTotal += replayed[i]
+	}
+	if recordedTotal == 0 || replayedTotal == 0 {
+		return 0
+	}
+	return float64(recordedTotal) / float64(replayedTotal)
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/rpc"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRpcLatencyProfiler_NoProfilerIsCreatedIfDisabled(t *testing.T) {
+	cfg := &utils.Config{}
+	ext := MakeRpcLatencyProfiler(cfg)
+	if _, ok := ext.(extension.NilExtension[*rpc.RequestAndResults]); !ok {
+		t.Errorf("rpc latency profiler is enabled although not set in configuration")
+	}
+}
+
+func newLatencyRequest(method string, recorded time.Duration) *rpc.RequestAndResults {
+	return &rpc.RequestAndResults{
+		Query:           &rpc.Body{Method: method},
+		RecordedLatency: recorded,
+	}
+}
+
+func TestRpcLatencyProfiler_AggregatesPerMethod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	p := makeRpcLatencyProfiler(&utils.Config{RpcCompareLatency: true}, logger.NewMockLogger(ctrl))
+
+	// 20 timed calls recorded with 1ms..20ms and replayed in a quarter of the time
+	for i := 1; i <= 20; i++ {
+		recorded := time.Duration(i) * time.Millisecond
+		p.add(newLatencyRequest("eth_call", recorded), recorded/4)
+	}
+	// requests without recorded latency are counted only
+	p.add(newLatencyRequest("eth_call", 0), time.Hour)
+	p.add(newLatencyRequest("eth_getBalance", 0), time.Millisecond)
+	p.add(&rpc.RequestAndResults{}, time.Millisecond)
+
+	assert.Equal(t, []rpcLatencyStats{
+		{
+			Method: "eth_call",
+			Count:  21,
+			Timed:  20,
+			Recorded: latencySummary{
+				P50: 10 * time.Millisecond,
+				P95: 19 * time.Millisecond,
+				Max: 20 * time.Millisecond,
+			},
+			Replayed: latencySummary{
+				P50: 2500 * time.Microsecond,
+				P95: 4750 * time.Microsecond,
+				Max: 5 * time.Millisecond,
+			},
+			Speedup: 4,
+		},
+		{Method: "eth_getBalance", Count: 1},
+		{Method: unknownRpcMethod, Count: 1},
+	}, p.stats())
+}
+
+func TestRpcLatencyProfiler_MeasuresTimeBetweenPreAndPostTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	p := makeRpcLatencyProfiler(&utils.Config{RpcCompareLatency: true}, logger.NewMockLogger(ctrl))
+
+	req := newLatencyRequest("eth_call", time.Second)
+	state := executor.State[*rpc.RequestAndResults]{Block: 1, Data: req}
+	require.NoError(t, p.PreTransaction(state, &executor.Context{}))
+	time.Sleep(time.Millisecond)
+	require.NoError(t, p.PostTransaction(state, &executor.Context{}))
+
+	// requests without PreTransaction are ignored
+	other := executor.State[*rpc.RequestAndResults]{Block: 1, Data: newLatencyRequest("eth_call", time.Second)}
+	require.NoError(t, p.PostTransaction(other, &executor.Context{}))
+
+	stats := p.stats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint64(1), stats[0].Count)
+	assert.Equal(t, uint64(1), stats[0].Timed)
+	assert.GreaterOrEqual(t, stats[0].Replayed.Max, time.Millisecond)
+	assert.Empty(t, p.starts)
+}
+
+func TestRpcLatencyProfiler_PostRunWritesReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	report := filepath.Join(t.TempDir(), "latency.json")
+	p := makeRpcLatencyProfiler(&utils.Config{RpcCompareLatency: true, RpcLatencyReport: report}, log)
+
+	p.add(newLatencyRequest("eth_call", 4*time.Millisecond), 2*time.Millisecond)
+
+	log.EXPECT().Noticef(gomock.Any(), gomock.Any())
+	require.NoError(t, p.PostRun(executor.State[*rpc.RequestAndResults]{}, &executor.Context{}, nil))
+
+	data, err := os.ReadFile(report)
+	require.NoError(t, err)
+	var stats []rpcLatencyStats
+	require.NoError(t, json.Unmarshal(data, &stats))
+	assert.Equal(t, []rpcLatencyStats{{
+		Method:   "eth_call",
+		Count:    1,
+		Timed:    1,
+		Recorded: latencySummary{P50: 4 * time.Millisecond, P95: 4 * time.Millisecond, Max: 4 * time.Millisecond},
+		Replayed: latencySummary{P50: 2 * time.Millisecond, P95: 2 * time.Millisecond, Max: 2 * time.Millisecond},
+		Speedup:  2,
+	}}, stats)
+}
+
+func TestRpcLatencyProfiler_PostRunWithoutRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	report := filepath.Join(t.TempDir(), "latency.json")
+	p := makeRpcLatencyProfiler(&utils.Config{RpcCompareLatency: true, RpcLatencyReport: report}, log)
+
+	log.EXPECT().Notice("No requests were profiled")
+	require.NoError(t, p.PostRun(executor.State[*rpc.RequestAndResults]{}, &executor.Context{}, nil))
+	assert.NoFileExists(t, report)
+}
+
+func TestRpcLatencyProfiler_PostRunFailsOnUnwritableReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	report := filepath.Join(t.TempDir(), "missing", "latency.json")
+	p := makeRpcLatencyProfiler(&utils.Config{RpcCompareLatency: true, RpcLatencyReport: report}, log)
+	p.add(newLatencyRequest("eth_call", time.Millisecond), time.Millisecond)
+
+	log.EXPECT().Noticef(gomock.Any(), gomock.Any())
+	err := p.PostRun(executor.State[*rpc.RequestAndResults]{}, &executor.Context{}, nil)
+	assert.ErrorContains(t, err, "cannot write latency report")
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/aida/utils/bundle"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// MakeRunBundleWriter creates an extension which packs the summary, the effective
+// configuration and all produced reports of the run into a single bundle written at
+// the end of the run. It should be the first extension of the list, so that its
+// PostRun is called after all other extensions finished writing their reports.
+func MakeRunBundleWriter[T any](cfg *utils.Config) executor.Extension[T] {
+	if cfg.RunBundle == "" {
+		return extension.NilExtension[T]{}
+	}
+	return makeRunBundleWriter[T](cfg, logger.NewLogger(cfg.LogLevel, "Run-Bundle-Writer"))
+}
+
+func makeRunBundleWriter[T any](cfg *utils.Config, log logger.Logger) *runBundleWriter[T] {
+	return &runBundleWriter[T]{
+		cfg: cfg,
+		log: log,
+	}
+}
+
+type runBundleWriter[T any] struct {
+	extension.NilExtension[T]
+	cfg *utils.Config
+	log logger.Logger
+
+	lock            sync.Mutex
+	start           time.Time
+	numBlocks       uint64
+	numTransactions uint64
+	gas             uint64
+	io              *utils.IoCounters // i/o counters at the start of the run, nil if not available
+}
+
+func (w *runBundleWriter[T]) PreRun(executor.State[T], *executor.Context) error {
+	w.start = time.Now()
+	if utils.IoStatsSupported {
+		io, err := utils.ReadIoCounters("")
+		if err != nil {
+			w.log.Warningf("I/O counters are not included in the summary; %v", err)
+			return nil
+		}
+		w.io = &io
+	}
+	return nil
+}
+
+func (w *runBundleWriter[T]) PostTransaction(_ executor.State[T], ctx *executor.Context) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.numTransactions++
+	if ctx.ExecutionResult != nil {
+		w.gas += ctx.ExecutionResult.GetGasUsed()
+	}
+	return nil
+}
+
+func (w *runBundleWriter[T]) PostBlock(executor.State[T], *executor.Context) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.numBlocks++
+	return nil
+}
+
+// PostRun collects all artifacts of the run and writes the bundle.
+func (w *runBundleWriter[T]) PostRun(_ executor.State[T], _ *executor.Context, runErr error) error {
+	inputs := map[string]string{
+		"aida-db":           w.cfg.AidaDb,
+		"chain-id":          fmt.Sprint(w.cfg.ChainID),
+		"state-db-src":      w.cfg.StateDbSrc,
+		"substate-encoding": fmt.Sprint(w.cfg.SubstateEncoding),
+	}
+	// fingerprints make sure compared runs used identical data, not only the same paths
+	if w.cfg.RunManifest != nil {
+		for _, input := range w.cfg.RunManifest.Inputs {
+			inputs["fingerprint:"+input.Name] = input.Scheme + ":" + input.Hash
+		}
+	}
+	b := bundle.NewBundle(strings.TrimSpace(w.cfg.AppName+" "+w.cfg.CommandName), w.cfg.First, w.cfg.Last, inputs)
+
+	summary, err := w.summary(runErr)
+	if err != nil {
+		return err
+	}
+	if err = b.AddJson(bundle.SummaryName, summary); err != nil {
+		return err
+	}
+
+	// the vm config contains tracer hooks which cannot be encoded
+	cfg := *w.cfg
+	cfg.VmCfg = vm.Config{}
+	if err := b.AddJson(bundle.ConfigName, cfg); err != nil {
+		return err
+	}
+
+	reports := []string{w.cfg.ProfileFile, w.cfg.CPUProfile, w.cfg.MemoryProfile, w.cfg.ErrorLogging}
+	if w.cfg.OutputDir != "" {
+		reports = append(reports, filepath.Join(w.cfg.OutputDir, w.cfg.RunId, utils.PathToRunSummary))
+	}
+	for _, report := range reports {
+		if report == "" {
+			continue
+		}
+		added, err := b.AddFile(report)
+		if err != nil {
+			return err
+		}
+		if !added {
+			w.log.Warningf("Report %v was not found and is not included in the run bundle", report)
+		}
+	}
+
+	if err := b.Write(w.cfg.RunBundle); err != nil {
+		return err
+	}
+	w.log.Noticef("Run bundle written to %v", w.cfg.RunBundle)
+	return nil
+}
+
+// summary returns the metrics of the run which are compared between bundles.
+func (w *runBundleWriter[T]) summary(runErr error) (map[string]any, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	elapsed := time.Since(w.start).Seconds()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	summary := map[string]any{
+		"failed":            runErr != nil,
+		"elapsed_seconds":   elapsed,
+		"blocks":            w.numBlocks,
+		"transactions":      w.numTransactions,
+		"gas":               w.gas,
+		"tx_rate":           float64(w.numTransactions) / elapsed,
+		"gas_rate":          float64(w.gas) / elapsed,
+		"total_alloc_bytes": m.TotalAlloc,
+		"heap_sys_bytes":    m.HeapSys,
+		"num_gc":            m.NumGC,
+		"gc_pause_seconds":  time.Duration(m.PauseTotalNs).Seconds(),
+	}
+
+	if w.io != nil {
+		io, err := utils.ReadIoCounters("")
+		if err != nil {
+			return nil, fmt.Errorf("cannot read i/o counters; %w", err)
+		}
+		io = io.Sub(*w.io)
+		summary["io_read_bytes"] = io.ReadBytes
+		summary["io_written_bytes"] = io.WrittenBytes
+	}
+	return summary, nil
+}
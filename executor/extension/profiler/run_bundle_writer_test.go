@@ -0,0 +1,80 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/aida/utils/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRunBundleWriter_NoBundleIsWrittenIfDisabled(t *testing.T) {
+	ext := MakeRunBundleWriter[any](&utils.Config{})
+	_, ok := ext.(extension.NilExtension[any])
+	assert.True(t, ok)
+}
+
+func TestRunBundleWriter_WritesSummaryConfigAndReports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dir := t.TempDir()
+	profileFile := filepath.Join(dir, "profile.csv")
+	require.NoError(t, os.WriteFile(profileFile, []byte("op,n\nGetState,1\n"), 0644))
+
+	cfg := &utils.Config{
+		First:       1,
+		Last:        2,
+		AidaDb:      "/aida-db",
+		RunBundle:   filepath.Join(dir, "run.tar.zst"),
+		ProfileFile: profileFile,
+		CPUProfile:  filepath.Join(dir, "missing.prof"),
+	}
+	log := logger.NewMockLogger(ctrl)
+	log.EXPECT().Warningf(gomock.Any(), cfg.CPUProfile)
+	log.EXPECT().Noticef(gomock.Any(), cfg.RunBundle)
+
+	result := txcontext.NewMockResult(ctrl)
+	result.EXPECT().GetGasUsed().Return(uint64(21_000))
+
+	ext := makeRunBundleWriter[any](cfg, log)
+	ctx := &executor.Context{ExecutionResult: result}
+	require.NoError(t, ext.PreRun(executor.State[any]{}, ctx))
+	require.NoError(t, ext.PostTransaction(executor.State[any]{}, ctx))
+	require.NoError(t, ext.PostBlock(executor.State[any]{}, ctx))
+	require.NoError(t, ext.PostRun(executor.State[any]{}, ctx, nil))
+
+	b, err := bundle.Read(cfg.RunBundle)
+	require.NoError(t, err)
+	assert.Equal(t, "/aida-db", b.Manifest.Inputs["aida-db"])
+	assert.Contains(t, b.Files, bundle.ConfigName)
+	assert.Contains(t, b.Files, "profile.csv")
+
+	metrics, err := bundle.Metrics(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, metrics["summary:transactions"])
+	assert.Equal(t, 21_000.0, metrics["summary:gas"])
+	assert.Equal(t, 1.0, metrics["profile.csv:GetState:n"])
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+const (
+	sqlite3_TxTiming_CreateTableIfNotExist = `
+		CREATE TABLE IF NOT EXISTS tx_timing (
+			block INTEGER NOT NULL,
+			tx INTEGER NOT NULL,
+			recipient STRING,
+			gas INTEGER,
+			duration INTEGER,
+			valid INTEGER,
+			PRIMARY KEY (block, tx)
+		);
+		CREATE INDEX IF NOT EXISTS tx_timing_block ON tx_timing (block);
+	`
+	sqlite3_TxTiming_InsertOrReplace = `
+		INSERT or REPLACE INTO tx_timing (
+			block, tx, recipient, gas, duration, valid
+		) VALUES (
+			?, ?, ?, ?, ?, ?
+		)
+	`
+)
+
+const (
+	txTimingBatchSize     = 1000             // number of rows inserted within a single db transaction
+	txTimingFlushInterval = 10 * time.Second // maximum time rows are kept in memory
+	txTimingQueueSize     = 16               // number of batches waiting to be inserted
+)
+
+// MakeTxTimingProfiler creates an extension recording the wall-clock execution time
+// of each transaction into table tx_timing of the sqlite3 profile DB.
+func MakeTxTimingProfiler(cfg *utils.Config) executor.Extension[txcontext.TxContext] {
+	if !cfg.ProfileTxTiming {
+		return extension.NilExtension[txcontext.TxContext]{}
+	}
+	return makeTxTimingProfiler(cfg, logger.NewLogger(cfg.LogLevel, "Tx-Timing-Profiler"))
+}
+
+func makeTxTimingProfiler(cfg *utils.Config, log logger.Logger) *txTimingProfiler {
+	return &txTimingProfiler{
+		cfg:    cfg,
+		log:    log,
+		starts: make(map[txTimingKey]time.Time),
+	}
+}
+
+// txTimingProfiler measures the time between PreTransaction and PostTransaction of
+// each transaction. Rows are collected into batches which are inserted into the DB
+// by a background writer, so that the execution does not wait for the disk.
+type txTimingProfiler struct {
+	extension.NilExtension[txcontext.TxContext]
+	cfg       *utils.Config
+	log       logger.Logger
+	printer   *utils.PrinterToDb
+	writer    *logger.WriteBehind[[]txTiming]
+	pending   []txTiming // batch currently being inserted by the writer
+	lock      sync.Mutex
+	starts    map[txTimingKey]time.Time
+	batch     []txTiming
+	lastFlush time.Time
+}
+
+type txTimingKey struct {
+	block, tx int
+}
+
+// txTiming is a single row of table tx_timing.
+type txTiming struct {
+	block     int
+	tx        int
+	recipient string
+	gas       uint64
+	duration  time.Duration
+	valid     *bool // nil if the recorded result is not available
+}
+
+// PreRun opens the sqlite3 DB and starts the background writer.
+func (p *txTimingProfiler) PreRun(executor.State[txcontext.TxContext], *executor.Context) error {
+	if p.cfg.ProfileSqlite3 == "" {
+		return fmt.Errorf("transaction timing profiler requires --%v", utils.ProfileSqlite3Flag.Name)
+	}
+	printer, err := utils.NewPrinterToSqlite3(p.cfg.ProfileSqlite3, sqlite3_TxTiming_CreateTableIfNotExist, sqlite3_TxTiming_InsertOrReplace, p.values)
+	if err != nil {
+		return fmt.Errorf("cannot open transaction timing profile db; %w", err)
+	}
+	p.printer = printer
+	p.writer = logger.NewWriteBehind(txTimingQueueSize, logger.BlockOnOverflow, p.write, func() error { return nil })
+	p.batch = make([]txTiming, 0, txTimingBatchSize)
+	p.lastFlush = time.Now()
+	return nil
+}
+
+// PreTransaction starts the timer of the transaction.
+func (p *txTimingProfiler) PreTransaction(state executor.State[txcontext.TxContext], _ *executor.Context) error {
+	start := time.Now()
+	p.lock.Lock()
+	p.starts[txTimingKey{state.Block, state.Transaction}] = start
+	p.lock.Unlock()
+	return nil
+}
+
+// PostTransaction stops the timer of the transaction and adds its row to the current batch.
+func (p *txTimingProfiler) PostTransaction(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	end := time.Now()
+	key := txTimingKey{state.Block, state.Transaction}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	start, ok := p.starts[key]
+	if !ok {
+		return nil
+	}
+	delete(p.starts, key)
+	if state.Transaction >= utils.PseudoTx || state.Data == nil {
+		return nil
+	}
+
+	row := txTiming{
+		block:     state.Block,
+		tx:        state.Transaction,
+		recipient: contractCreation,
+		duration:  end.Sub(start),
+	}
+	if msg := state.Data.GetMessage(); msg != nil && msg.To != nil {
+		row.recipient = msg.To.Hex()
+	}
+	if ctx.ExecutionResult != nil {
+		row.gas = ctx.ExecutionResult.GetGasUsed()
+		if expected := state.Data.GetResult(); expected != nil && expected.GetReceipt() != nil && ctx.ExecutionResult.GetReceipt() != nil {
+			valid := ctx.ExecutionResult.GetReceipt().Equal(expected.GetReceipt())
+			row.valid = &valid
+		}
+	}
+
+	p.batch = append(p.batch, row)
+	if len(p.batch) >= txTimingBatchSize || end.Sub(p.lastFlush) >= txTimingFlushInterval {
+		p.pushBatch(end)
+	}
+	return nil
+}
+
+// PostRun inserts the remaining rows and closes the DB.
+func (p *txTimingProfiler) PostRun(executor.State[txcontext.TxContext], *executor.Context, error) error {
+	if p.writer == nil {
+		return nil
+	}
+	p.lock.Lock()
+	p.pushBatch(time.Now())
+	p.lock.Unlock()
+
+	err := errors.Join(p.writer.Close(), p.printer.Close())
+	if err != nil {
+		return fmt.Errorf("cannot write transaction timing profile; %w", err)
+	}
+	return nil
+}
+
+// pushBatch hands the current batch over to the writer; must be called with the lock held.
+func (p *txTimingProfiler) pushBatch(now time.Time) {
+	p.lastFlush = now
+	if len(p.batch) == 0 {
+		return
+	}
+	p.writer.Push(p.batch)
+	p.batch = make([]txTiming, 0, txTimingBatchSize)
+}
+
+// write inserts a batch within a single db transaction; it is only called by the writer goroutine.
+func (p *txTimingProfiler) write(batch []txTiming) error {
+	p.pending = batch
+	defer func() { p.pending = nil }()
+	return p.printer.Print()
+}
+
+// values converts the pending batch into rows of table tx_timing.
+func (p *txTimingProfiler) values() [][]any {
+	values := make([][]any, 0, len(p.pending))
+	for _, r := range p.pending {
+		var valid any
+		if r.valid != nil {
+			valid = *r.valid
+		}
+		values = append(values, []any{r.block, r.tx, r.recipient, r.gas, r.duration.Nanoseconds(), valid})
+	}
+	return values
+}
@@ -0,0 +1,209 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package profiler
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTxTimingProfiler_NoProfilerIsCreatedIfDisabled(t *testing.T) {
+	cfg := &utils.Config{}
+	ext := MakeTxTimingProfiler(cfg)
+	if _, ok := ext.(extension.NilExtension[txcontext.TxContext]); !ok {
+		t.Errorf("transaction timing profiler is enabled although not set in configuration")
+	}
+}
+
+func TestTxTimingProfiler_PreRunFailsWithoutSqlite3Db(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	p := makeTxTimingProfiler(&utils.Config{ProfileTxTiming: true}, logger.NewMockLogger(ctrl))
+
+	err := p.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{})
+	require.ErrorContains(t, err, "--profile-sqlite3")
+	require.NoError(t, p.PostRun(executor.State[txcontext.TxContext]{}, &executor.Context{}, nil))
+}
+
+// txTimingRow is a row of table tx_timing as read back from the DB.
+type txTimingRow struct {
+	block, tx int
+	recipient string
+	gas       uint64
+	duration  int64
+	valid     sql.NullBool
+}
+
+func readTxTimingRows(t *testing.T, path string) []txTimingRow {
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows, err := db.Query("SELECT block, tx, recipient, gas, duration, valid FROM tx_timing ORDER BY block, tx")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var res []txTimingRow
+	for rows.Next() {
+		var r txTimingRow
+		require.NoError(t, rows.Scan(&r.block, &r.tx, &r.recipient, &r.gas, &r.duration, &r.valid))
+		res = append(res, r)
+	}
+	require.NoError(t, rows.Err())
+	return res
+}
+
+func TestTxTimingProfiler_RecordsEachTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	a := common.Address{0xa}
+	cfg := &utils.Config{
+		ProfileTxTiming: true,
+		ProfileSqlite3:  filepath.Join(t.TempDir(), "profile.db"),
+	}
+	p := makeTxTimingProfiler(cfg, logger.NewMockLogger(ctrl))
+	require.NoError(t, p.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{}))
+
+	recorded := txcontext.NewMockReceipt(ctrl)
+	expected := txcontext.NewMockResult(ctrl)
+	expected.EXPECT().GetReceipt().Return(recorded).AnyTimes()
+
+	txs := []struct {
+		block, tx int
+		to        *common.Address
+		gas       uint64
+		valid     bool
+	}{
+		{block: 10, tx: 0, to: &a, gas: 21_000, valid: true},
+		{block: 10, tx: 1, to: nil, gas: 100_000, valid: false},
+		{block: 11, tx: 0, to: &a, gas: 30_000, valid: true},
+	}
+	for _, tx := range txs {
+		data := txcontext.NewMockTxContext(ctrl)
+		data.EXPECT().GetMessage().Return(&core.Message{To: tx.to})
+		data.EXPECT().GetResult().Return(expected)
+		receipt := txcontext.NewMockReceipt(ctrl)
+		receipt.EXPECT().Equal(recorded).Return(tx.valid)
+		result := txcontext.NewMockResult(ctrl)
+		result.EXPECT().GetGasUsed().Return(tx.gas)
+		result.EXPECT().GetReceipt().Return(receipt).AnyTimes()
+
+		state := executor.State[txcontext.TxContext]{Block: tx.block, Transaction: tx.tx, Data: data}
+		ctx := &executor.Context{}
+		require.NoError(t, p.PreTransaction(state, ctx))
+		time.Sleep(time.Millisecond)
+		ctx.ExecutionResult = result
+		require.NoError(t, p.PostTransaction(state, ctx))
+	}
+
+	// pseudo transactions are not recorded
+	pseudo := executor.State[txcontext.TxContext]{Block: 11, Transaction: utils.PseudoTx, Data: txcontext.NewMockTxContext(ctrl)}
+	require.NoError(t, p.PreTransaction(pseudo, &executor.Context{}))
+	require.NoError(t, p.PostTransaction(pseudo, &executor.Context{}))
+
+	require.NoError(t, p.PostRun(executor.State[txcontext.TxContext]{}, &executor.Context{}, nil))
+
+	rows := readTxTimingRows(t, cfg.ProfileSqlite3)
+	require.Len(t, rows, len(txs))
+	for i, tx := range txs {
+		want := txTimingRow{block: tx.block, tx: tx.tx, recipient: contractCreation, gas: tx.gas, valid: sql.NullBool{Bool: tx.valid, Valid: true}}
+		if tx.to != nil {
+			want.recipient = tx.to.Hex()
+		}
+		assert.GreaterOrEqual(t, rows[i].duration, time.Millisecond.Nanoseconds())
+		want.duration = rows[i].duration
+		assert.Equal(t, want, rows[i])
+	}
+}
+
+func TestTxTimingProfiler_ValidityIsUnknownWithoutRecordedResult(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfg := &utils.Config{
+		ProfileTxTiming: true,
+		ProfileSqlite3:  filepath.Join(t.TempDir(), "profile.db"),
+	}
+	p := makeTxTimingProfiler(cfg, logger.NewMockLogger(ctrl))
+	require.NoError(t, p.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{}))
+
+	data := txcontext.NewMockTxContext(ctrl)
+	data.EXPECT().GetMessage().Return(&core.Message{})
+	data.EXPECT().GetResult().Return(nil)
+	result := txcontext.NewMockResult(ctrl)
+	result.EXPECT().GetGasUsed().Return(uint64(5))
+
+	state := executor.State[txcontext.TxContext]{Block: 1, Data: data}
+	require.NoError(t, p.PreTransaction(state, &executor.Context{}))
+	require.NoError(t, p.PostTransaction(state, &executor.Context{ExecutionResult: result}))
+	require.NoError(t, p.PostRun(executor.State[txcontext.TxContext]{}, &executor.Context{}, nil))
+
+	rows := readTxTimingRows(t, cfg.ProfileSqlite3)
+	require.Len(t, rows, 1)
+	assert.False(t, rows[0].valid.Valid)
+}
+
+func TestTxTimingProfiler_FullBatchesAreWrittenDuringTheRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfg := &utils.Config{
+		ProfileTxTiming: true,
+		ProfileSqlite3:  filepath.Join(t.TempDir(), "profile.db"),
+	}
+	p := makeTxTimingProfiler(cfg, logger.NewMockLogger(ctrl))
+	require.NoError(t, p.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{}))
+
+	for i := 0; i < txTimingBatchSize+1; i++ {
+		data := txcontext.NewMockTxContext(ctrl)
+		data.EXPECT().GetMessage().Return(&core.Message{})
+		state := executor.State[txcontext.TxContext]{Block: 1, Transaction: i, Data: data}
+		require.NoError(t, p.PreTransaction(state, &executor.Context{}))
+		require.NoError(t, p.PostTransaction(state, &executor.Context{}))
+	}
+	require.NoError(t, p.writer.Flush())
+	assert.Len(t, readTxTimingRows(t, cfg.ProfileSqlite3), txTimingBatchSize)
+	assert.Len(t, p.batch, 1)
+
+	require.NoError(t, p.PostRun(executor.State[txcontext.TxContext]{}, &executor.Context{}, nil))
+	assert.Len(t, readTxTimingRows(t, cfg.ProfileSqlite3), txTimingBatchSize+1)
+}
+
+func TestTxTimingProfiler_TableIsIndexedByBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfg := &utils.Config{
+		ProfileTxTiming: true,
+		ProfileSqlite3:  filepath.Join(t.TempDir(), "profile.db"),
+	}
+	p := makeTxTimingProfiler(cfg, logger.NewMockLogger(ctrl))
+	require.NoError(t, p.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{}))
+	require.NoError(t, p.PostRun(executor.State[txcontext.TxContext]{}, &executor.Context{}, nil))
+
+	db, err := sql.Open("sqlite3", cfg.ProfileSqlite3)
+	require.NoError(t, err)
+	defer db.Close()
+	var table string
+	require.NoError(t, db.QueryRow("SELECT tbl_name FROM sqlite_master WHERE type = 'index' AND name = 'tx_timing_block'").Scan(&table))
+	assert.Equal(t, "tx_timing", table)
+}
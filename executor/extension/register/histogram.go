@@ -0,0 +1,147 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package register
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultGasPerBlockBuckets are the upper bounds of the gas-per-block histogram
+// used unless overridden by --register-gas-buckets.
+var defaultGasPerBlockBuckets = []uint64{
+	100_000, 500_000, 1_000_000, 2_500_000, 5_000_000, 10_000_000,
+	20_000_000, 30_000_000, 50_000_000, 100_000_000, 250_000_000,
+}
+
+// defaultTxPerBlockBuckets are the upper bounds of the transactions-per-block
+// histogram used unless overridden by --register-tx-buckets.
+var defaultTxPerBlockBuckets = []uint64{
+	1, 2, 5, 10, 20, 50, 100, 200, 500, 1_000,
+}
+
+// histogram counts values in fixed buckets. Bucket i holds values v with
+// bounds[i-1] < v <= bounds[i]; values above the last bound fall into an
+// additional overflow bucket.
+type histogram struct {
+	bounds []uint64
+	counts []uint64
+	total  uint64
+	min    uint64
+	max    uint64
+}
+
+// newHistogram creates an empty histogram with the given ascending bucket upper bounds.
+func newHistogram(bounds []uint64) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+// add records the value in its bucket.
+func (h *histogram) add(v uint64) {
+	h.counts[h.bucket(v)]++
+	if h.total == 0 || v < h.min {
+		h.min = v
+	}
+	if v > h.max {
+		h.max = v
+	}
+	h.total++
+}
+
+// bucket returns the index of the bucket the value belongs to.
+func (h *histogram) bucket(v uint64) int {
+	return sort.Search(len(h.bounds), func(i int) bool { return v <= h.bounds[i] })
+}
+
+// percentile estimates the p-th percentile (0 < p <= 100) of the recorded values
+// by locating the bucket holding the nearest rank and interpolating linearly within
+// it. Bucket edges are clamped to the observed minimum and maximum, so the overflow
+// bucket and sparsely populated buckets do not yield values that were never seen.
+func (h *histogram) percentile(p float64) uint64 {
+	if h.total == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(p / 100 * float64(h.total)))
+	rank = max(1, min(rank, h.total))
+
+	var seen uint64
+	for i, count := range h.counts {
+		if seen+count < rank {
+			seen += count
+			continue
+		}
+		lower := h.min
+		if i > 0 {
+			lower = max(lower, h.bounds[i-1])
+		}
+		upper := h.max
+		if i < len(h.bounds) {
+			upper = min(upper, h.bounds[i])
+		}
+		fraction := float64(rank-seen) / float64(count)
+		return lower + uint64(math.Round(fraction*float64(upper-lower)))
+	}
+	return h.max
+}
+
+// reset removes all recorded values while keeping the buckets.
+func (h *histogram) reset() {
+	clear(h.counts)
+	h.total = 0
+	h.min = 0
+	h.max = 0
+}
+
+// rows returns one row per bucket as (metric, upper bound, count); the upper bound
+// of the overflow bucket is nil.
+func (h *histogram) rows(metric string) [][]any {
+	rows := make([][]any, 0, len(h.counts))
+	for i, count := range h.counts {
+		var upper any
+		if i < len(h.bounds) {
+			upper = h.bounds[i]
+		}
+		rows = append(rows, []any{metric, upper, count})
+	}
+	return rows
+}
+
+// parseHistogramBuckets parses a comma-separated list of strictly increasing bucket
+// upper bounds. An empty list yields the given defaults.
+func parseHistogramBuckets(list string, defaults []uint64) ([]uint64, error) {
+	if strings.TrimSpace(list) == "" {
+		return defaults, nil
+	}
+	var bounds []uint64
+	for _, field := range strings.Split(list, ",") {
+		bound, err := strconv.ParseUint(strings.TrimSpace(field), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid histogram bucket %q; %w", field, err)
+		}
+		if len(bounds) > 0 && bound <= bounds[len(bounds)-1] {
+			return nil, fmt.Errorf("histogram buckets must be strictly increasing, got %d after %d", bound, bounds[len(bounds)-1])
+		}
+		bounds = append(bounds, bound)
+	}
+	return bounds, nil
+}
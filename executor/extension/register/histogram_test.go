@@ -0,0 +1,167 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package register
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram_AddAssignsValuesToBuckets(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  uint64
+		bucket int
+	}{
+		{name: "zero", value: 0, bucket: 0},
+		{name: "first-bound", value: 10, bucket: 0},
+		{name: "above-first-bound", value: 11, bucket: 1},
+		{name: "last-bound", value: 100, bucket: 2},
+		{name: "overflow", value: 101, bucket: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newHistogram([]uint64{10, 50, 100})
+			h.add(test.value)
+
+			want := make([]uint64, 4)
+			want[test.bucket] = 1
+			assert.Equal(t, want, h.counts)
+			assert.Equal(t, uint64(1), h.total)
+			assert.Equal(t, test.value, h.min)
+			assert.Equal(t, test.value, h.max)
+		})
+	}
+}
+
+func TestHistogram_PercentileOfSyntheticSequences(t *testing.T) {
+	sequence := func(from, to uint64) []uint64 {
+		var values []uint64
+		for v := from; v <= to; v++ {
+			values = append(values, v)
+		}
+		return values
+	}
+	tests := []struct {
+		name   string
+		bounds []uint64
+		values []uint64
+		want   map[float64]uint64
+	}{
+		{
+			name:   "uniform",
+			bounds: []uint64{10, 20, 30, 40, 50, 60, 70, 80, 90},
+			values: sequence(1, 100),
+			want:   map[float64]uint64{10: 10, 50: 50, 90: 90, 99: 99, 100: 100},
+		},
+		{
+			name:   "constant",
+			bounds: []uint64{20_000_000, 30_000_000},
+			values: []uint64{21_000_000, 21_000_000, 21_000_000},
+			want:   map[float64]uint64{50: 21_000_000, 90: 21_000_000, 99: 21_000_000},
+		},
+		{
+			name:   "interpolated-within-bucket",
+			bounds: []uint64{100, 200},
+			values: []uint64{150, 150, 150, 200},
+			want:   map[float64]uint64{25: 163, 50: 175, 100: 200},
+		},
+		{
+			name:   "outliers-in-overflow-bucket",
+			bounds: []uint64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100},
+			values: append(sequence(1, 98), 1_000, 5_000),
+			want:   map[float64]uint64{50: 50, 99: 2_550, 100: 5_000},
+		},
+		{
+			name:   "empty",
+			bounds: []uint64{10},
+			want:   map[float64]uint64{50: 0, 99: 0},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newHistogram(test.bounds)
+			for _, v := range test.values {
+				h.add(v)
+			}
+			for p, want := range test.want {
+				assert.Equal(t, want, h.percentile(p), "p%v", p)
+			}
+		})
+	}
+}
+
+func TestHistogram_ResetKeepsBuckets(t *testing.T) {
+	h := newHistogram([]uint64{10, 20})
+	h.add(5)
+	h.add(25)
+
+	h.reset()
+
+	assert.Equal(t, []uint64{0, 0, 0}, h.counts)
+	assert.Zero(t, h.total)
+	assert.Zero(t, h.percentile(50))
+	h.add(15)
+	assert.Equal(t, []uint64{0, 1, 0}, h.counts)
+	assert.Equal(t, uint64(15), h.min)
+}
+
+func TestHistogram_RowsListBucketsWithOverflow(t *testing.T) {
+	h := newHistogram([]uint64{10, 20})
+	h.add(5)
+	h.add(25)
+	h.add(30)
+
+	want := [][]any{
+		{"metric", uint64(10), uint64(1)},
+		{"metric", uint64(20), uint64(0)},
+		{"metric", nil, uint64(2)},
+	}
+	assert.Equal(t, want, h.rows("metric"))
+}
+
+func TestParseHistogramBuckets(t *testing.T) {
+	defaults := []uint64{1, 2}
+	tests := []struct {
+		name    string
+		list    string
+		want    []uint64
+		wantErr string
+	}{
+		{name: "empty", list: "", want: defaults},
+		{name: "blank", list: "  ", want: defaults},
+		{name: "single", list: "5", want: []uint64{5}},
+		{name: "spaces", list: "10, 20 ,30", want: []uint64{10, 20, 30}},
+		{name: "not-a-number", list: "10,abc", wantErr: "invalid histogram bucket \"abc\""},
+		{name: "negative", list: "-1", wantErr: "invalid histogram bucket"},
+		{name: "decreasing", list: "20,10", wantErr: "strictly increasing, got 10 after 20"},
+		{name: "duplicate", list: "10,10", wantErr: "strictly increasing, got 10 after 10"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseHistogramBuckets(test.list, defaults)
+			if test.wantErr != "" {
+				require.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -17,7 +17,10 @@
 package register
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -45,6 +48,10 @@ const (
 	archiveDbDirectoryName        = "archive"
 	defaultReportFrequency uint64 = 100_000
 
+	// names of files written into the csv export directory
+	runMetadataCsvName = "run_metadata.csv"
+	runMetricsCsvName  = "run_metrics.csv"
+
 	registerProgressCreateTableIfNotExist = `
 		CREATE TABLE IF NOT EXISTS stats (
   			start INTEGER NOT NULL,
@@ -69,11 +76,48 @@ const (
 			?, ?, ?, ?
 		)
 	`
+
+	registerHistogramCreateTableIfNotExist = `
+		CREATE TABLE IF NOT EXISTS histograms (
+			start INTEGER NOT NULL,
+			end INTEGER NOT NULL,
+			metric TEXT NOT NULL,
+			upper_bound INTEGER,
+			count INTEGER NOT NULL
+		)
+	`
+	registerHistogramInsert = `
+		INSERT INTO histograms (
+			start, end, metric, upper_bound, count
+		) VALUES (
+			?, ?, ?, ?, ?
+		)
+	`
+
+	// names of the histograms recorded into the histograms table
+	gasPerBlockMetric = "gas_per_block"
+	txPerBlockMetric  = "tx_per_block"
 )
 
+// registryMigrations upgrade the schema of a registry written by an older version,
+// e.g. one reused by --overwrite-run-id. The PRAGMA user_version of a registry counts
+// the migrations applied to it, so new migrations must only ever be appended.
+var registryMigrations = []string{
+	registerProgressCreateTableIfNotExist,
+	registerHistogramCreateTableIfNotExist,
+}
+
+// registerProgressCsvHeader names the columns of the exported metrics, which match the stats table.
+var registerProgressCsvHeader = []string{
+	"start", "end",
+	"memory", "live_disk", "archive_disk",
+	"tx_rate", "gas_rate", "overall_tx_rate", "overall_gas_rate",
+}
+
 // MakeRegisterProgress creates an extention that
 //  1. Track Progress e.g. ProgressTracker
 //  2. Register the intermediate results to an external service (sqlite3 db)
+//  3. Optionally, export the metadata and the intermediate results as csv files
 func MakeRegisterProgress(cfg *utils.Config, reportFrequency int, when whenToPrint) executor.Extension[txcontext.TxContext] {
 	if cfg.RegisterRun == "" {
 		return extension.NilExtension[txcontext.TxContext]{}
@@ -119,11 +163,25 @@ type registerProgress struct {
 	pathToStateDb   string
 	pathToArchiveDb string
 	memory          *state.MemoryUsage
+	stats           []any // statistics of the last printed interval
+
+	// Per-block histograms
+	blocks           map[int]*blockTotals // totals of blocks still being processed
+	gasPerBlock      *histogram           // gas used per block in the current interval
+	txPerBlock       *histogram           // transactions per block in the current interval
+	totalGasPerBlock *histogram           // gas used per block in the whole run
+	histograms       [][]any              // histogram rows of the last printed interval
 
 	id   *rr.RunIdentity
 	meta *rr.RunMetadata
 }
 
+// blockTotals accumulates the transactions of a single block.
+type blockTotals struct {
+	gas uint64
+	txs uint64
+}
+
 // PreRun checks the following items:
 // 1. if directory does not exists -> fatal, throw error
 // 2. if database could not be created -> fatal, throw error
@@ -136,17 +194,50 @@ func (rp *registerProgress) PreRun(_ executor.State[txcontext.TxContext], ctx *e
 	connection := filepath.Join(rp.cfg.RegisterRun, fmt.Sprintf("%s.db", id))
 	rp.log.Noticef("Registering to: %s", connection)
 
+	gasBuckets, err := parseHistogramBuckets(rp.cfg.RegisterGasBuckets, defaultGasPerBlockBuckets)
+	if err != nil {
+		return fmt.Errorf("cannot parse gas-per-block buckets; %w", err)
+	}
+	txBuckets, err := parseHistogramBuckets(rp.cfg.RegisterTxBuckets, defaultTxPerBlockBuckets)
+	if err != nil {
+		return fmt.Errorf("cannot parse tx-per-block buckets; %w", err)
+	}
+	rp.blocks = make(map[int]*blockTotals)
+	rp.gasPerBlock = newHistogram(gasBuckets)
+	rp.txPerBlock = newHistogram(txBuckets)
+	rp.totalGasPerBlock = newHistogram(gasBuckets)
+
 	// 1. if directory does not exists -> fatal, throw error
 	if _, err := os.Stat(rp.cfg.RegisterRun); err != nil {
 		return err
 	}
 
-	// 2. if database could be created -> fatal, throw error
+	// 2. if database could not be created or migrated -> fatal, throw error
+	if err = migrateRegistry(connection); err != nil {
+		return err
+	}
 	p2db, err := utils.NewPrinterToSqlite3(rp.sqlite3(connection))
 	if err != nil {
 		return err
 	}
 	rp.ps.AddPrinter(p2db)
+	p2hist, err := utils.NewPrinterToSqlite3(connection, registerHistogramCreateTableIfNotExist, registerHistogramInsert, rp.printedHistograms)
+	if err != nil {
+		return err
+	}
+	rp.ps.AddPrinter(p2hist)
+
+	// 2a. if csv export is enabled and the metrics file could not be created -> fatal, throw error
+	if rp.cfg.RegisterExportCsv != "" {
+		if err = os.MkdirAll(rp.cfg.RegisterExportCsv, 0755); err != nil {
+			return fmt.Errorf("cannot create csv export directory; %w", err)
+		}
+		p2csv, err := utils.NewPrinterToCsv(filepath.Join(rp.cfg.RegisterExportCsv, runMetricsCsvName), registerProgressCsvHeader, rp.printedStats)
+		if err != nil {
+			return err
+		}
+		rp.ps.AddPrinter(p2csv)
+	}
 
 	// 3. if metadata could be fetched -> continue without the failed metadata
 	rm, err := rr.MakeRunMetadata(connection, rp.id, rr.FetchUnixInfo)
@@ -158,6 +249,9 @@ func (rp *registerProgress) PreRun(_ executor.State[txcontext.TxContext], ctx *e
 	if err != nil {
 		rp.log.Errorf("Metadata warnings: %s.", err)
 	}
+	if rp.cfg.RunManifest != nil {
+		maps.Copy(rm.Meta, rp.cfg.RunManifest.Metadata())
+	}
 	rp.meta = rm
 	err = rm.Print()
 	if err != nil {
@@ -219,8 +313,7 @@ func (rp *registerProgress) PreTransaction(state executor.State[txcontext.TxCont
 
 // printAndReset sends the state to the report goroutine and reset current-interval tracker.
 func (rp *registerProgress) printAndReset(ctx *executor.Context) error {
-	rp.memory = ctx.State.GetMemoryUsage()
-	err := rp.ps.Print()
+	err := rp.print(ctx)
 	if err != nil {
 		return err
 	}
@@ -244,13 +337,37 @@ func (rp *registerProgress) PostTransaction(state executor.State[txcontext.TxCon
 	rp.totalGas += res.GetGasUsed()
 	rp.gas += res.GetGasUsed()
 
+	block, ok := rp.blocks[state.Block]
+	if !ok {
+		block = new(blockTotals)
+		rp.blocks[state.Block] = block
+	}
+	block.txs++
+	block.gas += res.GetGasUsed()
+
+	return nil
+}
+
+// PostBlock records the gas used and the number of transactions of the finished block into the histograms.
+func (rp *registerProgress) PostBlock(state executor.State[txcontext.TxContext], _ *executor.Context) error {
+	rp.lock.Lock()
+	defer rp.lock.Unlock()
+
+	var totals blockTotals
+	if block, ok := rp.blocks[state.Block]; ok {
+		totals = *block
+		delete(rp.blocks, state.Block)
+	}
+	rp.gasPerBlock.add(totals.gas)
+	rp.txPerBlock.add(totals.txs)
+	rp.totalGasPerBlock.add(totals.gas)
+
 	return nil
 }
 
 // PostRun prints the remaining statistics and terminates any printer resources.
 func (rp *registerProgress) PostRun(_ executor.State[txcontext.TxContext], ctx *executor.Context, inputErr error) error {
-	rp.memory = ctx.State.GetMemoryUsage()
-	err := rp.ps.Print()
+	err := rp.print(ctx)
 	if err != nil {
 		return err
 	}
@@ -261,12 +378,22 @@ func (rp *registerProgress) PostRun(_ executor.State[txcontext.TxContext], ctx *
 	}
 
 	rp.meta.Meta["Runtime"] = strconv.Itoa(int(time.Since(rp.startOfRun).Seconds()))
+	rp.meta.Meta["EndTimestamp"] = strconv.Itoa(int(time.Now().Unix()))
 	if inputErr != nil {
 		rp.meta.Meta["RunSucceed"] = strconv.FormatBool(false)
 		rp.meta.Meta["RunError"] = fmt.Sprintf("%v", inputErr)
 	} else {
 		rp.meta.Meta["RunSucceed"] = strconv.FormatBool(true)
 	}
+	if rp.totalGasPerBlock.total > 0 {
+		p50 := rp.totalGasPerBlock.percentile(50)
+		p90 := rp.totalGasPerBlock.percentile(90)
+		p99 := rp.totalGasPerBlock.percentile(99)
+		rp.log.Noticef("Gas per block: p50 %d, p90 %d, p99 %d", p50, p90, p99)
+		rp.meta.Meta["GasPerBlockP50"] = strconv.FormatUint(p50, 10)
+		rp.meta.Meta["GasPerBlockP90"] = strconv.FormatUint(p90, 10)
+		rp.meta.Meta["GasPerBlockP99"] = strconv.FormatUint(p99, 10)
+	}
 
 	err = rp.meta.Print()
 	if err != nil {
@@ -277,14 +404,40 @@ func (rp *registerProgress) PostRun(_ executor.State[txcontext.TxContext], ctx *
 		return err
 	}
 
+	if rp.cfg.RegisterExportCsv != "" {
+		return rp.meta.WriteCsv(filepath.Join(rp.cfg.RegisterExportCsv, runMetadataCsvName))
+	}
 	return nil
 }
 
+// print collects the statistics of the current interval and prints them to all printers.
+func (rp *registerProgress) print(ctx *executor.Context) error {
+	rp.memory = ctx.State.GetMemoryUsage()
+	rp.stats = rp.collectStats()
+	rp.histograms = rp.collectHistograms()
+	return rp.ps.Print()
+}
+
+// printedStats returns the statistics collected by the last print.
+func (rp *registerProgress) printedStats() [][]any {
+	return [][]any{rp.stats}
+}
+
+// printedHistograms returns the histogram rows collected by the last print.
+func (rp *registerProgress) printedHistograms() [][]any {
+	return rp.histograms
+}
+
 // Reset set local interval trackers to initial state for the next interval.
 func (rp *registerProgress) Reset() {
+	rp.lock.Lock()
+	defer rp.lock.Unlock()
+
 	rp.lastUpdate = time.Now()
 	rp.txCount = 0
 	rp.gas = 0
+	rp.gasPerBlock.reset()
+	rp.txPerBlock.reset()
 }
 
 // GetId returns a unique id based on the run metadata.
@@ -296,60 +449,104 @@ func (rp *registerProgress) sqlite3(conn string) (string, string, string, func()
 	return conn,
 		registerProgressCreateTableIfNotExist,
 		registerProgressInsertOrReplace,
-		func() [][]any {
-			values := [][]any{}
-
-			var (
-				txCount      uint64
-				gas          uint64
-				totalTxCount uint64
-				totalGas     uint64
-				lDisk        int64
-				aDisk        int64
-			)
-
-			rp.lock.Lock()
-			txCount = rp.txCount
-			gas = rp.gas
-			totalTxCount = rp.totalTxCount
-			totalGas = rp.totalGas
-			rp.lock.Unlock()
-
-			lDisk, err := utils.GetDirectorySize(rp.pathToStateDb)
-			if err != nil {
-				// silent defaults to 0 if anything happens to path at runtime
-				lDisk = 0
-			}
-
-			if rp.cfg.ArchiveMode {
-				aDisk, err = utils.GetDirectorySize(rp.pathToArchiveDb)
-				if err != nil {
-					// silent defaults to 0 if anything happens to path at runtime
-					aDisk = 0
-				} else {
-					lDisk -= aDisk
-				}
-			}
-
-			mem := rp.memory.UsedBytes
-
-			txRate := float64(txCount) / time.Since(rp.lastUpdate).Seconds()
-			gasRate := float64(gas) / time.Since(rp.lastUpdate).Seconds()
-			overallTxRate := float64(totalTxCount) / time.Since(rp.startOfRun).Seconds()
-			overallGasRate := float64(totalGas) / time.Since(rp.startOfRun).Seconds()
-
-			values = append(values, []any{
-				rp.interval.Start(),
-				rp.interval.End(),
-				mem,
-				lDisk,
-				aDisk,
-				txRate,
-				gasRate,
-				overallTxRate,
-				overallGasRate,
-			})
-
-			return values
+		rp.printedStats
+}
+
+// collectStats returns the statistics of the current interval in order of the stats table columns.
+func (rp *registerProgress) collectStats() []any {
+	var (
+		txCount      uint64
+		gas          uint64
+		totalTxCount uint64
+		totalGas     uint64
+		lDisk        int64
+		aDisk        int64
+	)
+
+	rp.lock.Lock()
+	txCount = rp.txCount
+	gas = rp.gas
+	totalTxCount = rp.totalTxCount
+	totalGas = rp.totalGas
+	rp.lock.Unlock()
+
+	lDisk, err := utils.GetDirectorySize(rp.pathToStateDb)
+	if err != nil {
+		// silent defaults to 0 if anything happens to path at runtime
+		lDisk = 0
+	}
+
+	if rp.cfg.ArchiveMode {
+		aDisk, err = utils.GetDirectorySize(rp.pathToArchiveDb)
+		if err != nil {
+			// silent defaults to 0 if anything happens to path at runtime
+			aDisk = 0
+		} else {
+			lDisk -= aDisk
 		}
+	}
+
+	// memory usage is not reported by all StateDb implementations
+	var mem uint64
+	if rp.memory != nil {
+		mem = rp.memory.UsedBytes
+	}
+
+	txRate := utils.Rate(float64(txCount), time.Since(rp.lastUpdate))
+	gasRate := utils.Rate(float64(gas), time.Since(rp.lastUpdate))
+	overallTxRate := utils.Rate(float64(totalTxCount), time.Since(rp.startOfRun))
+	overallGasRate := utils.Rate(float64(totalGas), time.Since(rp.startOfRun))
+
+	return []any{
+		rp.interval.Start(),
+		rp.interval.End(),
+		mem,
+		lDisk,
+		aDisk,
+		txRate,
+		gasRate,
+		overallTxRate,
+		overallGasRate,
+	}
+}
+
+// collectHistograms returns the per-block histograms of the current interval in order of the histograms table columns.
+func (rp *registerProgress) collectHistograms() [][]any {
+	rp.lock.Lock()
+	defer rp.lock.Unlock()
+
+	var rows [][]any
+	for _, row := range append(rp.gasPerBlock.rows(gasPerBlockMetric), rp.txPerBlock.rows(txPerBlockMetric)...) {
+		rows = append(rows, append([]any{rp.interval.Start(), rp.interval.End()}, row...))
+	}
+	return rows
+}
+
+// migrateRegistry applies all registryMigrations the registry at conn is missing.
+func migrateRegistry(conn string) (err error) {
+	db, err := sql.Open("sqlite3", conn)
+	if err != nil {
+		return fmt.Errorf("failed to open connection to sqlite3 %s; %w", conn, err)
+	}
+	defer func() {
+		err = errors.Join(err, db.Close())
+	}()
+
+	var version int
+	if err = db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("cannot read schema version of %s; %w", conn, err)
+	}
+	if version > len(registryMigrations) {
+		return fmt.Errorf("registry %s has schema version %d, newer than the supported version %d", conn, version, len(registryMigrations))
+	}
+	for i := version; i < len(registryMigrations); i++ {
+		if _, err = db.Exec(registryMigrations[i]); err != nil {
+			return fmt.Errorf("cannot migrate %s to schema version %d; %w", conn, i+1, err)
+		}
+	}
+	// PRAGMA statements do not accept bound parameters
+	if _, err = db.Exec(fmt.Sprintf("PRAGMA user_version = %d", len(registryMigrations))); err != nil {
+		return fmt.Errorf("cannot update schema version of %s; %w", conn, err)
+	}
+	return nil
 }
@@ -17,6 +17,8 @@
 package register
 
 import (
+	"database/sql"
+	"encoding/csv"
 	"errors"
 	"fmt"
 	"math"
@@ -300,6 +302,82 @@ func TestRegisterProgress_InsertToDbIfEnabled(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRegisterProgress_ExportsCsvIfEnabled(t *testing.T) {
+	var (
+		tmpDir           = t.TempDir()
+		dummyStateDbPath = filepath.Join(tmpDir, "dummy.txt")
+		csvDir           = filepath.Join(tmpDir, "csv")
+	)
+	if err := os.WriteFile(dummyStateDbPath, []byte("hello world"), 0x600); err != nil {
+		t.Fatalf("Failed to prepare disk content for %s.", dummyStateDbPath)
+	}
+
+	ctrl := gomock.NewController(t)
+	stateDb := state.NewMockStateDB(ctrl)
+
+	cfg := &utils.Config{}
+	cfg.RegisterRun = tmpDir
+	cfg.RegisterExportCsv = csvDir
+	cfg.OverwriteRunId = "tmp"
+	cfg.First = 5
+	cfg.Last = 25
+	interval := 10
+	// expects [5-9]P[10-19]P[20-24]P, where P is print
+
+	gomock.InOrder(
+		stateDb.EXPECT().GetMemoryUsage().Return(&state.MemoryUsage{UsedBytes: 1234}),
+		stateDb.EXPECT().GetMemoryUsage().Return(&state.MemoryUsage{UsedBytes: 4321}),
+		stateDb.EXPECT().GetMemoryUsage().Return(nil),
+	)
+
+	ext := MakeRegisterProgress(cfg, interval, OnPreBlock)
+	ctx := &executor.Context{
+		State:           stateDb,
+		StateDbPath:     dummyStateDbPath,
+		ExecutionResult: substatecontext.NewReceipt(&substate.Result{GasUsed: 100}),
+	}
+	sub := substatecontext.NewTxContext(&substate.Substate{Result: &substate.Result{GasUsed: 100}})
+
+	require.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+	for b := int(cfg.First); b < int(cfg.Last); b++ {
+		require.NoError(t, ext.PreBlock(executor.State[txcontext.TxContext]{Block: b, Data: sub}, ctx))
+		require.NoError(t, ext.PreTransaction(executor.State[txcontext.TxContext]{Data: sub}, ctx))
+		require.NoError(t, ext.PostTransaction(executor.State[txcontext.TxContext]{Data: sub}, ctx))
+		require.NoError(t, ext.PostBlock(executor.State[txcontext.TxContext]{Block: b, Data: sub}, ctx))
+	}
+	require.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, nil))
+
+	readCsv := func(name string) [][]string {
+		f, err := os.Open(filepath.Join(csvDir, name))
+		require.NoError(t, err)
+		defer f.Close()
+		records, err := csv.NewReader(f).ReadAll()
+		require.NoError(t, err)
+		return records
+	}
+
+	metrics := readCsv(runMetricsCsvName)
+	require.Len(t, metrics, 4)
+	assert.Equal(t, registerProgressCsvHeader, metrics[0])
+	assert.Equal(t, []string{"5", "9", "1234"}, metrics[1][:3])
+	assert.Equal(t, []string{"10", "19", "4321"}, metrics[2][:3])
+	assert.Equal(t, []string{"20", "24", "0"}, metrics[3][:3])
+
+	metadata := readCsv(runMetadataCsvName)
+	require.Len(t, metadata, 2)
+	row := make(map[string]string)
+	for i, column := range metadata[0] {
+		row[column] = metadata[1][i]
+	}
+	assert.Equal(t, "tmp", row["RunId"])
+	assert.Equal(t, "5", row["First"])
+	assert.Equal(t, "25", row["Last"])
+	assert.Equal(t, "true", row["RunSucceed"])
+	assert.NotEmpty(t, row["Timestamp"])
+	assert.NotEmpty(t, row["EndTimestamp"])
+	assert.Contains(t, row, "AidaGitHash")
+}
+
 func TestRegisterProgress_IfErrorRecordIntoMetadata(t *testing.T) {
 	var (
 		tmpDir           = t.TempDir()
@@ -482,3 +560,163 @@ func TestRegisterProgress_GetId(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, id, 38)
 }
+
+func TestRegisterProgress_RecordsHistogramsPerBlock(t *testing.T) {
+	var (
+		tmpDir           = t.TempDir()
+		dummyStateDbPath = filepath.Join(tmpDir, "dummy.txt")
+		connection       = filepath.Join(tmpDir, "tmp.db")
+	)
+	require.NoError(t, os.WriteFile(dummyStateDbPath, []byte("hello world"), 0x600))
+
+	ctrl := gomock.NewController(t)
+	stateDb := state.NewMockStateDB(ctrl)
+	stateDb.EXPECT().GetMemoryUsage().Return(nil).Times(3)
+
+	cfg := &utils.Config{}
+	cfg.RegisterRun = tmpDir
+	cfg.OverwriteRunId = "tmp"
+	cfg.RegisterGasBuckets = "150,250"
+	cfg.RegisterTxBuckets = "1,2"
+	cfg.First = 5
+	cfg.Last = 25
+	interval := 10
+	// expects [5-9]P[10-19]P[20-24]P, where P is print
+
+	ext := MakeRegisterProgress(cfg, interval, OnPreBlock)
+	ctx := &executor.Context{
+		State:           stateDb,
+		StateDbPath:     dummyStateDbPath,
+		ExecutionResult: substatecontext.NewReceipt(&substate.Result{GasUsed: 100}),
+	}
+	sub := substatecontext.NewTxContext(&substate.Substate{Result: &substate.Result{GasUsed: 100}})
+
+	// block b holds b%3+1 transactions using 100 gas each
+	require.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+	for b := int(cfg.First); b < int(cfg.Last); b++ {
+		require.NoError(t, ext.PreBlock(executor.State[txcontext.TxContext]{Block: b, Data: sub}, ctx))
+		for tx := 0; tx <= b%3; tx++ {
+			st := executor.State[txcontext.TxContext]{Block: b, Transaction: tx, Data: sub}
+			require.NoError(t, ext.PreTransaction(st, ctx))
+			require.NoError(t, ext.PostTransaction(st, ctx))
+		}
+		require.NoError(t, ext.PostBlock(executor.State[txcontext.TxContext]{Block: b, Data: sub}, ctx))
+	}
+	require.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, nil))
+
+	sDb, err := sqlx.Open("sqlite3", connection)
+	require.NoError(t, err)
+	defer sDb.Close()
+
+	type bucket struct {
+		Start      int           `db:"start"`
+		End        int           `db:"end"`
+		Metric     string        `db:"metric"`
+		UpperBound sql.NullInt64 `db:"upper_bound"`
+		Count      int           `db:"count"`
+	}
+	var buckets []bucket
+	require.NoError(t, sDb.Select(&buckets, "select start, end, metric, upper_bound, count from histograms order by start, metric, rowid"))
+	require.Len(t, buckets, 3*6)
+
+	// blocks 5-9 use 300, 100, 200, 300 and 100 gas
+	bound := func(v int64) sql.NullInt64 { return sql.NullInt64{Int64: v, Valid: true} }
+	assert.Equal(t, []bucket{
+		{5, 9, gasPerBlockMetric, bound(150), 2},
+		{5, 9, gasPerBlockMetric, bound(250), 1},
+		{5, 9, gasPerBlockMetric, sql.NullInt64{}, 2},
+		{5, 9, txPerBlockMetric, bound(1), 2},
+		{5, 9, txPerBlockMetric, bound(2), 1},
+		{5, 9, txPerBlockMetric, sql.NullInt64{}, 2},
+	}, buckets[:6])
+
+	// every interval only counts its own blocks
+	for _, start := range []int{5, 10, 20} {
+		var total int
+		require.NoError(t, sDb.Get(&total, "select sum(count) from histograms where start = ? and metric = ?", start, gasPerBlockMetric))
+		assert.Equal(t, map[int]int{5: 5, 10: 10, 20: 5}[start], total, "interval starting at %d", start)
+	}
+
+	// the run uses 100 gas in 7 blocks, 200 gas in 6 blocks and 300 gas in 7 blocks
+	meta := map[string]string{}
+	rows, err := sDb.Query("select key, value from metadata where key like 'GasPerBlockP%'")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var key, value string
+		require.NoError(t, rows.Scan(&key, &value))
+		meta[key] = value
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, map[string]string{
+		"GasPerBlockP50": "200",
+		"GasPerBlockP90": "286",
+		"GasPerBlockP99": "300",
+	}, meta)
+}
+
+func TestRegisterProgress_FailsOnInvalidHistogramBuckets(t *testing.T) {
+	cfg := &utils.Config{}
+	cfg.RegisterRun = t.TempDir()
+	cfg.OverwriteRunId = "tmp"
+	cfg.RegisterGasBuckets = "100,50"
+
+	ext := MakeRegisterProgress(cfg, 10, OnPreBlock)
+	err := ext.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{})
+	require.ErrorContains(t, err, "cannot parse gas-per-block buckets")
+}
+
+func TestRegisterProgress_MigratesOlderRegistry(t *testing.T) {
+	var (
+		tmpDir           = t.TempDir()
+		dummyStateDbPath = filepath.Join(tmpDir, "dummy.txt")
+		connection       = filepath.Join(tmpDir, "tmp.db")
+	)
+	require.NoError(t, os.WriteFile(dummyStateDbPath, []byte("hello world"), 0x600))
+
+	// a registry written before histograms were recorded
+	sDb, err := sqlx.Open("sqlite3", connection)
+	require.NoError(t, err)
+	defer sDb.Close()
+	_, err = sDb.Exec(registerProgressCreateTableIfNotExist)
+	require.NoError(t, err)
+	_, err = sDb.Exec(registerProgressInsertOrReplace, 0, 4, 1, 2, 3, 4.0, 5.0, 6.0, 7.0)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	stateDb := state.NewMockStateDB(ctrl)
+	stateDb.EXPECT().GetMemoryUsage().Return(nil)
+
+	cfg := &utils.Config{}
+	cfg.RegisterRun = tmpDir
+	cfg.OverwriteRunId = "tmp"
+	cfg.First = 5
+	cfg.Last = 9
+
+	ext := MakeRegisterProgress(cfg, 10, OnPreBlock)
+	ctx := &executor.Context{State: stateDb, StateDbPath: dummyStateDbPath}
+	require.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+	require.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, nil))
+
+	var version int
+	require.NoError(t, sDb.Get(&version, "PRAGMA user_version"))
+	assert.Equal(t, len(registryMigrations), version)
+
+	var statsRows, histogramRows int
+	require.NoError(t, sDb.Get(&statsRows, "select count(*) from stats"))
+	assert.Equal(t, 2, statsRows, "existing stats must be kept")
+	require.NoError(t, sDb.Get(&histogramRows, "select count(*) from histograms"))
+	assert.Equal(t, len(defaultGasPerBlockBuckets)+len(defaultTxPerBlockBuckets)+2, histogramRows)
+}
+
+func TestMigrateRegistry_RejectsNewerSchema(t *testing.T) {
+	connection := filepath.Join(t.TempDir(), "tmp.db")
+	sDb, err := sqlx.Open("sqlite3", connection)
+	require.NoError(t, err)
+	defer sDb.Close()
+	_, err = sDb.Exec(fmt.Sprintf("PRAGMA user_version = %d", len(registryMigrations)+1))
+	require.NoError(t, err)
+
+	err = migrateRegistry(connection)
+	require.ErrorContains(t, err, "newer than the supported version")
+}
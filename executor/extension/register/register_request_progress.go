@@ -192,11 +192,11 @@ func (rp *registerRequestProgress) PostTransaction(state executor.State[*rpc.Req
 	overallGas := overallInfo.gas
 	intervalGas := rp.lastIntervalInfo.gas
 
-	rp.intervalReqRate = float64(rp.reportFrequency) / sinceLastUpdate.Seconds()
-	rp.intervalGasRate = float64(overallGas-intervalGas) / sinceLastUpdate.Seconds()
+	rp.intervalReqRate = utils.Rate(float64(rp.reportFrequency), sinceLastUpdate)
+	rp.intervalGasRate = utils.Rate(float64(overallGas-intervalGas), sinceLastUpdate)
 
-	rp.overallReqRate = float64(overallCount) / sinceStartOfRun.Seconds()
-	rp.overallGasRate = float64(overallGas) / sinceStartOfRun.Seconds()
+	rp.overallReqRate = utils.Rate(float64(overallCount), sinceStartOfRun)
+	rp.overallGasRate = utils.Rate(float64(overallGas), sinceStartOfRun)
 
 	err := rp.ps.Print()
 	if err != nil {
@@ -0,0 +1,73 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package register
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+// MakeRunManifestWriter creates an extension which fingerprints all inputs of the run
+// at its start. The manifest is added to the run summary and, through the configuration,
+// made available to the run registration and the run bundle. It has to be placed before
+// the extensions which open or modify the inputs, such as the StateDb manager.
+func MakeRunManifestWriter[T any](cfg *utils.Config) executor.Extension[T] {
+	if cfg.OutputDir == "" && cfg.RegisterRun == "" && cfg.RunBundle == "" {
+		return extension.NilExtension[T]{}
+	}
+	return makeRunManifestWriter[T](cfg, logger.NewLogger(cfg.LogLevel, "Run-Manifest-Writer"))
+}
+
+func makeRunManifestWriter[T any](cfg *utils.Config, log logger.Logger) *runManifestWriter[T] {
+	return &runManifestWriter[T]{
+		cfg: cfg,
+		log: log,
+	}
+}
+
+type runManifestWriter[T any] struct {
+	extension.NilExtension[T]
+	cfg *utils.Config
+	log logger.Logger
+}
+
+func (w *runManifestWriter[T]) PreRun(_ executor.State[T], ctx *executor.Context) error {
+	start := time.Now()
+	manifest, err := utils.MakeRunManifest(w.cfg, ctx.AidaDb)
+	if err != nil {
+		return fmt.Errorf("cannot create run manifest; %w", err)
+	}
+	w.cfg.RunManifest = manifest
+	w.log.Noticef("Fingerprinted %d inputs of the run in %v", len(manifest.Inputs), time.Since(start).Round(time.Millisecond))
+
+	if w.cfg.OutputDir == "" {
+		return nil
+	}
+	runDir := filepath.Join(w.cfg.OutputDir, w.cfg.RunId)
+	summary, err := utils.ReadRunSummary(runDir)
+	if err != nil {
+		return err
+	}
+	summary.Manifest = manifest
+	return utils.WriteRunSummary(summary)
+}
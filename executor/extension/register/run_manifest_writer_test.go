@@ -0,0 +1,73 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package register
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRunManifestWriter_NoManifestIsCreatedIfNotRecorded(t *testing.T) {
+	ext := MakeRunManifestWriter[txcontext.TxContext](&utils.Config{})
+	if _, ok := ext.(extension.NilExtension[txcontext.TxContext]); !ok {
+		t.Errorf("manifest writer is enabled although the run is not recorded")
+	}
+}
+
+func TestRunManifestWriter_PreRunAddsManifestToConfigAndSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	log.EXPECT().Noticef(gomock.Any(), 1, gomock.Any())
+
+	skipList := filepath.Join(t.TempDir(), "skip-list")
+	require.NoError(t, os.WriteFile(skipList, []byte("1:2\n"), 0644))
+	cfg := &utils.Config{OutputDir: t.TempDir(), RunId: "run", SkipList: skipList}
+	runDir := filepath.Join(cfg.OutputDir, cfg.RunId)
+	require.NoError(t, os.MkdirAll(runDir, 0755))
+	require.NoError(t, utils.WriteRunSummary(utils.RunSummary{RunId: cfg.RunId, RunDir: runDir}))
+
+	ext := makeRunManifestWriter[txcontext.TxContext](cfg, log)
+	require.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{}))
+
+	require.NotNil(t, cfg.RunManifest)
+	want, err := utils.FingerprintFile(utils.SkipListInput, skipList)
+	require.NoError(t, err)
+	assert.Equal(t, []utils.InputFingerprint{want}, cfg.RunManifest.Inputs)
+
+	summary, err := utils.ReadRunSummary(runDir)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.RunManifest, summary.Manifest)
+}
+
+func TestRunManifestWriter_PreRunFailsOnUnreadableInput(t *testing.T) {
+	cfg := &utils.Config{RunBundle: "bundle.tar.zst", SkipList: filepath.Join(t.TempDir(), "missing")}
+	ext := makeRunManifestWriter[txcontext.TxContext](cfg, logger.NewLogger("critical", "test"))
+
+	err := ext.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{})
+	require.ErrorContains(t, err, "cannot create run manifest")
+	assert.Nil(t, cfg.RunManifest)
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state/proxy"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+// MakeAddressRemapper creates an extension wrapping the StateDb into a proxy which
+// remaps all addresses, and with --remap-storage-keys also all storage keys, by a
+// permutation derived from --remap-key. The execution is not affected, but the StateDb
+// stores the state in a uniformly distributed address space. It has to be registered
+// right after the StateDb is created so that priming and all other extensions
+// access the StateDb through the proxy.
+func MakeAddressRemapper[T any](cfg *utils.Config) executor.Extension[T] {
+	if cfg.RemapKey == "" {
+		return extension.NilExtension[T]{}
+	}
+	return makeAddressRemapper[T](cfg, logger.NewLogger(cfg.LogLevel, "Address-Remapper"))
+}
+
+func makeAddressRemapper[T any](cfg *utils.Config, log logger.Logger) *addressRemapper[T] {
+	return &addressRemapper[T]{
+		cfg:      cfg,
+		log:      log,
+		remapper: proxy.NewRemapper(cfg.RemapKey, cfg.RemapStorageKeys),
+	}
+}
+
+type addressRemapper[T any] struct {
+	extension.NilExtension[T]
+	cfg      *utils.Config
+	log      logger.Logger
+	remapper *proxy.Remapper
+}
+
+// PreRun wraps the StateDb into the remapping proxy.
+func (r *addressRemapper[T]) PreRun(_ executor.State[T], ctx *executor.Context) error {
+	r.log.Warningf("Addresses (storage keys: %v) are remapped; state roots of the StateDb differ from the recorded ones", r.cfg.RemapStorageKeys)
+	ctx.State = proxy.NewRemapProxy(ctx.State, r.remapper)
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/state/proxy"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAddressRemapper_NoRemapperIsCreatedIfKeyIsNotSet(t *testing.T) {
+	ext := MakeAddressRemapper[any](&utils.Config{})
+	_, ok := ext.(extension.NilExtension[any])
+	assert.True(t, ok)
+}
+
+func TestAddressRemapper_PreRunWrapsStateDb(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{RemapKey: "key"}
+
+	addr := common.Address{1}
+	log.EXPECT().Warningf(gomock.Any(), false)
+	db.EXPECT().Exist(proxy.NewRemapper("key", false).Address(addr)).Return(true)
+
+	ext := makeAddressRemapper[any](cfg, log)
+	ctx := &executor.Context{State: db}
+	require.NoError(t, ext.PreRun(executor.State[any]{}, ctx))
+
+	_, ok := ctx.State.(*proxy.RemapProxy)
+	require.True(t, ok)
+	assert.True(t, ctx.State.Exist(addr))
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+)
+
+// archiveCache keeps the most recently used archive states open, so that repeated
+// queries of the same block do not need to set up a new historic context each time.
+// Archive states are not documented to be thread safe, hence a cached state is used
+// by at most one goroutine at a time. An archive state is released once it is both
+// evicted from the cache and no longer in use.
+type archiveCache struct {
+	capacity int
+	mutex    sync.Mutex
+	entries  map[uint64]*list.Element // block -> element of lru holding *cachedArchive
+	lru      *list.List               // most recently used archive at front
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// cachedArchive is an archive state owned by the cache.
+type cachedArchive struct {
+	db    state.NonCommittableStateDB
+	block uint64
+	inUse sync.Mutex // held by the goroutine currently using the archive
+
+	// guarded by the mutex of the cache
+	refs    int  // number of goroutines using or waiting for the archive
+	evicted bool // true if the archive was removed from the cache
+}
+
+// archiveLease grants exclusive access to a cached archive until it is released.
+type archiveLease struct {
+	state.NonCommittableStateDB
+	cache   *archiveCache
+	archive *cachedArchive
+}
+
+func newArchiveCache(capacity int) *archiveCache {
+	return &archiveCache{
+		capacity: capacity,
+		entries:  make(map[uint64]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// get returns the archive state of given block, which is opened on db if it is not cached.
+// The returned archive must be released once it is no longer needed.
+func (c *archiveCache) get(db state.StateDB, block uint64) (state.NonCommittableStateDB, error) {
+	if archive := c.acquire(block); archive != nil {
+		c.hits.Add(1)
+		return c.lease(archive), nil
+	}
+
+	c.misses.Add(1)
+	// setting up the archive is the expensive part, hence it is done outside the lock
+	archiveDb, err := db.GetArchiveState(block)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := c.insert(block, archiveDb)
+	if archive == nil {
+		// the block was cached by another goroutine meanwhile, the archive stays private
+		return archiveDb, err
+	}
+	lease := c.lease(archive)
+	if err != nil {
+		return nil, errors.Join(err, lease.Release())
+	}
+	return lease, nil
+}
+
+// acquire returns the cached archive of given block or nil if the block is not cached.
+func (c *archiveCache) acquire(block uint64) *cachedArchive {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	elem, found := c.entries[block]
+	if !found {
+		return nil
+	}
+	c.lru.MoveToFront(elem)
+	archive := elem.Value.(*cachedArchive)
+	archive.refs++
+	return archive
+}
+
+// insert adds an archive of given block to the cache and evicts the least recently used
+// archives exceeding the capacity. Nil is returned if the block is already cached.
+func (c *archiveCache) insert(block uint64, db state.NonCommittableStateDB) (*cachedArchive, error) {
+	var idle []*cachedArchive
+	c.mutex.Lock()
+	if _, found := c.entries[block]; found {
+		c.mutex.Unlock()
+		return nil, nil
+	}
+	archive := &cachedArchive{db: db, block: block, refs: 1}
+	c.entries[block] = c.lru.PushFront(archive)
+	for c.lru.Len() > c.capacity {
+		if evicted := c.evict(c.lru.Back()); evicted != nil {
+			idle = append(idle, evicted)
+		}
+	}
+	c.mutex.Unlock()
+	return archive, releaseArchives(idle)
+}
+
+// evict removes the archive from the cache and returns it if it is not in use.
+// The cache mutex must be held by the caller.
+func (c *archiveCache) evict(elem *list.Element) *cachedArchive {
+	archive := c.lru.Remove(elem).(*cachedArchive)
+	delete(c.entries, archive.block)
+	archive.evicted = true
+	if archive.refs > 0 {
+		return nil
+	}
+	return archive
+}
+
+// lease waits until the archive is not used by any other goroutine.
+func (c *archiveCache) lease(archive *cachedArchive) *archiveLease {
+	archive.inUse.Lock()
+	return &archiveLease{NonCommittableStateDB: archive.db, cache: c, archive: archive}
+}
+
+// Release returns the archive to the cache, the underlying archive is kept open unless it was evicted.
+func (l *archiveLease) Release() error {
+	archive := l.archive
+	if archive == nil {
+		return fmt.Errorf("archive was already released")
+	}
+	l.archive = nil
+	archive.inUse.Unlock()
+
+	l.cache.mutex.Lock()
+	archive.refs--
+	idle := archive.evicted && archive.refs == 0
+	l.cache.mutex.Unlock()
+	if idle {
+		return archive.db.Release()
+	}
+	return nil
+}
+
+// close evicts all archives. Archives still in use are released by their last user.
+func (c *archiveCache) close() error {
+	var idle []*cachedArchive
+	c.mutex.Lock()
+	for c.lru.Len() > 0 {
+		if evicted := c.evict(c.lru.Back()); evicted != nil {
+			idle = append(idle, evicted)
+		}
+	}
+	c.mutex.Unlock()
+	return releaseArchives(idle)
+}
+
+// report logs out the hit rate of the cache.
+func (c *archiveCache) report(log logger.Logger) {
+	hits, misses := c.hits.Load(), c.misses.Load()
+	if hits+misses == 0 {
+		return
+	}
+	log.Noticef("Archive cache hit rate: %.2f%% (%d hits, %d misses)", 100*float64(hits)/float64(hits+misses), hits, misses)
+}
+
+func releaseArchives(archives []*cachedArchive) error {
+	var errs []error
+	for _, archive := range archives {
+		if err := archive.db.Release(); err != nil {
+			errs = append(errs, fmt.Errorf("cannot release archive of block %d; %w", archive.block, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestArchiveCache_HitReusesOpenArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+	cache := newArchiveCache(1)
+
+	db.EXPECT().GetArchiveState(uint64(5)).Return(archive, nil).Times(1)
+
+	for i := 0; i < 3; i++ {
+		got, err := cache.get(db, 5)
+		require.NoError(t, err)
+		require.NoError(t, got.Release())
+	}
+	assert.Equal(t, uint64(2), cache.hits.Load())
+	assert.Equal(t, uint64(1), cache.misses.Load())
+
+	archive.EXPECT().Release()
+	require.NoError(t, cache.close())
+}
+
+func TestArchiveCache_EvictsLeastRecentlyUsedArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	archives := map[uint64]*state.MockNonCommittableStateDB{}
+	for _, block := range []uint64{1, 2, 3} {
+		archives[block] = state.NewMockNonCommittableStateDB(ctrl)
+		db.EXPECT().GetArchiveState(block).Return(archives[block], nil)
+	}
+	cache := newArchiveCache(2)
+
+	use := func(block uint64) {
+		got, err := cache.get(db, block)
+		require.NoError(t, err)
+		require.NoError(t, got.Release())
+	}
+	use(1)
+	use(2)
+	use(1)
+
+	// block 2 is the least recently used one
+	archives[2].EXPECT().Release()
+	use(3)
+
+	archives[1].EXPECT().Release()
+	archives[3].EXPECT().Release()
+	require.NoError(t, cache.close())
+}
+
+func TestArchiveCache_EvictedArchiveIsReleasedByLastUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	first := state.NewMockNonCommittableStateDB(ctrl)
+	second := state.NewMockNonCommittableStateDB(ctrl)
+	db.EXPECT().GetArchiveState(uint64(1)).Return(first, nil)
+	db.EXPECT().GetArchiveState(uint64(2)).Return(second, nil)
+	cache := newArchiveCache(1)
+
+	inUse, err := cache.get(db, 1)
+	require.NoError(t, err)
+
+	// evicting block 1 must not release the archive which is still in use
+	other, err := cache.get(db, 2)
+	require.NoError(t, err)
+	require.NoError(t, other.Release())
+
+	first.EXPECT().Release()
+	require.NoError(t, inUse.Release())
+
+	second.EXPECT().Release()
+	require.NoError(t, cache.close())
+}
+
+func TestArchiveCache_LeaseCannotBeReleasedTwice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+	db.EXPECT().GetArchiveState(uint64(1)).Return(archive, nil)
+	cache := newArchiveCache(1)
+
+	got, err := cache.get(db, 1)
+	require.NoError(t, err)
+	require.NoError(t, got.Release())
+	require.ErrorContains(t, got.Release(), "already released")
+}
+
+func TestArchiveCache_ErrorIsNotCached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	mockErr := errors.New("mock error")
+	db.EXPECT().GetArchiveState(uint64(1)).Return(nil, mockErr).Times(2)
+	cache := newArchiveCache(1)
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.get(db, 1)
+		require.ErrorIs(t, err, mockErr)
+	}
+	require.NoError(t, cache.close())
+}
+
+func TestArchiveCache_ArchiveIsUsedByOneGoroutineAtATime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+	db.EXPECT().GetArchiveState(uint64(1)).Return(archive, nil).MinTimes(1)
+	archive.EXPECT().Release().AnyTimes()
+	cache := newArchiveCache(1)
+
+	var (
+		active, maxActive int
+		mutex             sync.Mutex
+		wg                sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				got, err := cache.get(db, 1)
+				if !assert.NoError(t, err) {
+					return
+				}
+				if _, cached := got.(*archiveLease); cached {
+					mutex.Lock()
+					active++
+					maxActive = max(maxActive, active)
+					mutex.Unlock()
+					runtime.Gosched()
+					mutex.Lock()
+					active--
+					mutex.Unlock()
+				}
+				assert.NoError(t, got.Release())
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, maxActive)
+	require.NoError(t, cache.close())
+}
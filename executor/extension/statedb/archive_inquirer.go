@@ -55,14 +55,19 @@ func makeArchiveInquirer(cfg *utils.Config, log logger.Logger, duration *time.Du
 	if tickerDuration <= 0 {
 		return nil, fmt.Errorf("duration must greater than 0")
 	}
+	var cache *archiveCache
+	if cfg.ArchiveCacheSize > 0 {
+		cache = newArchiveCache(cfg.ArchiveCacheSize)
+	}
 	return &archiveInquirer{
 		ArchiveDbTxProcessor: processor,
 		cfg:                  cfg,
 		log:                  log,
 		tickerDuration:       tickerDuration,
-		throttler:            newThrottler(cfg.ArchiveQueryRate),
+		throttler:            newThrottler(cfg.ArchiveQueryRate, cfg.ArchiveQueryBurst, cfg.ArchiveQueryWarmUp),
 		finished:             utils.MakeEvent(),
 		history:              newBuffer[historicTransaction](cfg.ArchiveMaxQueryAge),
+		cache:                cache,
 		validator:            validator.MakeArchiveDbValidator(cfg, validator.ValidateTxTarget{WorldState: true, Receipt: true}),
 	}, nil
 }
@@ -75,6 +80,7 @@ type archiveInquirer struct {
 	log            logger.Logger
 	state          state.StateDB
 	tickerDuration time.Duration
+	cache          *archiveCache // nil if archives are not cached
 
 	// Buffer for historic queries to sample from
 	history      *circularBuffer[historicTransaction]
@@ -132,7 +138,11 @@ func (i *archiveInquirer) PostTransaction(state executor.State[txcontext.TxConte
 func (i *archiveInquirer) PostRun(executor.State[txcontext.TxContext], *executor.Context, error) error {
 	i.finished.Signal()
 	i.done.Wait()
-	return nil
+	if i.cache == nil {
+		return nil
+	}
+	i.cache.report(i.log)
+	return i.cache.close()
 }
 
 func (i *archiveInquirer) getRandomTransaction(rnd *rand.Rand) (historicTransaction, bool) {
@@ -187,18 +197,18 @@ func (i *archiveInquirer) doInquiry(rnd *rand.Rand, errCh chan error) {
 	archive, err := i.getArchive(uint64(tx.block), uint32(tx.number))
 	if err != nil {
 		// ArchiveInquirer should not end the app, hence we just send the error to the errorLogger
-		errCh <- err
+		errCh <- executor.NewTxError(executor.StateDbComponent, tx.block, tx.number, executor.ErrArchiveQuery, err)
 		return
 	}
 
 	defer func() {
 		err = archive.EndTransaction()
 		if err != nil {
-			errCh <- fmt.Errorf("cannot end archive inquirer transaction; %w", err)
+			errCh <- executor.NewTxError(executor.StateDbComponent, tx.block, tx.number, executor.ErrArchiveQuery, fmt.Errorf("cannot end archive inquirer transaction; %w", err))
 		}
 		err = archive.Release()
 		if err != nil {
-			errCh <- fmt.Errorf("cannot release archive inside archive inquirer; %w", err)
+			errCh <- executor.NewTxError(executor.StateDbComponent, tx.block, tx.number, executor.ErrArchiveQuery, fmt.Errorf("cannot release archive inside archive inquirer; %w", err))
 		}
 
 	}()
@@ -278,7 +288,15 @@ func (i *archiveInquirer) runProgressReport() {
 }
 
 func (i *archiveInquirer) getArchive(blk uint64, tx uint32) (state.NonCommittableStateDB, error) {
-	archive, err := i.state.GetArchiveState(blk)
+	var (
+		archive state.NonCommittableStateDB
+		err     error
+	)
+	if i.cache != nil {
+		archive, err = i.cache.get(i.state, blk)
+	} else {
+		archive, err = i.state.GetArchiveState(blk)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to obtain access to archive blk %d, tx %d: %w", blk, tx, err)
 	}
@@ -328,33 +346,62 @@ func (b *circularBuffer[T]) Get(pos int) T {
 	return b.data[pos]
 }
 
+// throttler is a token bucket shared by all inquiry workers. Tokens are added at
+// the target rate, which optionally ramps up linearly from zero during a warm-up
+// period starting with the first request. Without a burst size, tokens of idle
+// periods accumulate without limit.
 type throttler struct {
 	transactionsPerSecond int
+	burst                 int           // maximum number of tokens held, unbounded if <= 0
+	warmUp                time.Duration // period of the linear ramp up to the target rate
+	now                   func() time.Time
+	start                 time.Time
 	lastUpdate            time.Time
 	pending               float64
 	mutex                 sync.Mutex
 }
 
-func newThrottler(rate int) *throttler {
+func newThrottler(rate int, burst int, warmUp time.Duration) *throttler {
 	return &throttler{
 		transactionsPerSecond: rate,
-		lastUpdate:            time.Now(),
+		burst:                 burst,
+		warmUp:                max(warmUp, 0),
+		now:                   time.Now,
 	}
 }
 
 func (t *throttler) shouldRunNow() bool {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
-	if t.pending < 1 {
-		// Replenish pending transactions.
-		now := time.Now()
-		delta := now.Sub(t.lastUpdate)
+
+	// Replenish pending transactions.
+	now := t.now()
+	if t.start.IsZero() {
+		t.start = now
 		t.lastUpdate = now
-		t.pending += float64(t.transactionsPerSecond) * delta.Seconds()
 	}
+	t.pending += t.allowance(now.Sub(t.start)) - t.allowance(t.lastUpdate.Sub(t.start))
+	t.lastUpdate = now
+	if t.burst > 0 && t.pending > float64(t.burst) {
+		t.pending = float64(t.burst)
+	}
+
 	if t.pending >= 1 {
 		t.pending -= 1
 		return true
 	}
 	return false
 }
+
+// allowance returns the number of tokens added in total during the given time
+// since the start, i.e. the integral of the rate over the elapsed time.
+func (t *throttler) allowance(elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	rate := float64(t.transactionsPerSecond)
+	if elapsed >= t.warmUp {
+		return rate * (elapsed - t.warmUp/2).Seconds()
+	}
+	return rate * elapsed.Seconds() * elapsed.Seconds() / (2 * t.warmUp.Seconds())
+}
@@ -20,6 +20,8 @@ import (
 	"math"
 	"math/big"
 	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -78,6 +80,22 @@ func TestArchiveInquirer_makeArchiveInquirer(t *testing.T) {
 		assert.Nil(t, ext)
 	})
 
+	t.Run("archive cache", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		log := logger.NewMockLogger(ctrl)
+		cfg := utils.Config{}
+		cfg.ChainID = utils.OperaMainnetChainID
+		cfg.ArchiveQueryRate = 100
+		cfg.ArchiveCacheSize = 10
+		ext, err := makeArchiveInquirer(&cfg, log, nil)
+		assert.NoError(t, err)
+		out, ok := ext.(*archiveInquirer)
+		assert.True(t, ok)
+		if assert.NotNil(t, out.cache) {
+			assert.Equal(t, 10, out.cache.capacity)
+		}
+	})
+
 }
 
 func TestArchiveInquirer_DisabledIfNoQueryRateIsGiven(t *testing.T) {
@@ -287,7 +305,7 @@ func TestCircularBuffer_CyclesThroughContent(t *testing.T) {
 func TestThrottler_ProducesEventsInExpectedRate(t *testing.T) {
 	const testPeriod = 500 * time.Millisecond
 	for _, rate := range []int{5, 10, 100, 1000} {
-		throttler := *newThrottler(rate)
+		throttler := newThrottler(rate, 0, 0)
 
 		count := 0
 		start := time.Now()
@@ -305,6 +323,148 @@ func TestThrottler_ProducesEventsInExpectedRate(t *testing.T) {
 	}
 }
 
+// fakeClock is a manually advanced clock for throttler tests.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// admissionTimes polls the throttler every step until the end and returns the
+// offsets of the admitted queries relative to the first poll.
+func admissionTimes(throttler *throttler, clock *fakeClock, step, end time.Duration) []time.Duration {
+	start := clock.now
+	var admitted []time.Duration
+	for offset := time.Duration(0); offset < end; offset += step {
+		clock.now = start.Add(offset)
+		if throttler.shouldRunNow() {
+			admitted = append(admitted, offset)
+		}
+	}
+	return admitted
+}
+
+func countBetween(times []time.Duration, from, to time.Duration) int {
+	count := 0
+	for _, t := range times {
+		if t >= from && t < to {
+			count++
+		}
+	}
+	return count
+}
+
+func TestThrottler_FlatRateIsDefault(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	throttler := newThrottler(10, 0, 0)
+	throttler.now = clock.Now
+
+	admitted := admissionTimes(throttler, clock, time.Millisecond, 10*time.Second)
+	assert.InDelta(t, 100, len(admitted), 1)
+	for i := 1; i < len(admitted); i++ {
+		assert.InDelta(t, float64(100*time.Millisecond), float64(admitted[i]-admitted[i-1]), float64(time.Millisecond))
+	}
+}
+
+func TestThrottler_BurstLimitsQueriesAfterIdlePeriod(t *testing.T) {
+	tests := map[string]struct {
+		burst int
+		want  int
+	}{
+		"unbounded": {burst: 0, want: 100},
+		"burst":     {burst: 5, want: 5},
+		"one":       {burst: 1, want: 1},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Unix(1000, 0)}
+			throttler := newThrottler(10, test.burst, 0)
+			throttler.now = clock.Now
+			assert.False(t, throttler.shouldRunNow())
+
+			// After 10 idle seconds, all saved up queries are admitted at once.
+			clock.now = clock.now.Add(10 * time.Second)
+			count := 0
+			for throttler.shouldRunNow() {
+				count++
+			}
+			assert.Equal(t, test.want, count)
+
+			// Afterwards, the flat rate applies again.
+			admitted := admissionTimes(throttler, clock, time.Millisecond, 2*time.Second)
+			assert.InDelta(t, 20, len(admitted), 1)
+		})
+	}
+}
+
+func TestThrottler_WarmUpRampsUpRateLinearly(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	throttler := newThrottler(100, 0, 10*time.Second)
+	throttler.now = clock.Now
+
+	admitted := admissionTimes(throttler, clock, time.Millisecond, 20*time.Second)
+
+	// The rate ramps from 0 to 100/s within 10s, hence the number of queries
+	// admitted up to time t < 10s is 100 * t^2 / 20.
+	assert.InDelta(t, 125, countBetween(admitted, 0, 5*time.Second), 1)
+	assert.InDelta(t, 375, countBetween(admitted, 5*time.Second, 10*time.Second), 1)
+	assert.InDelta(t, 1000, countBetween(admitted, 10*time.Second, 20*time.Second), 1)
+
+	// The first query is admitted once a full token was accumulated at sqrt(0.2)s.
+	if assert.NotEmpty(t, admitted) {
+		assert.InDelta(t, float64(447*time.Millisecond), float64(admitted[0]), float64(2*time.Millisecond))
+	}
+}
+
+func TestThrottler_WarmUpWithBurst(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	throttler := newThrottler(100, 3, 4*time.Second)
+	throttler.now = clock.Now
+	assert.False(t, throttler.shouldRunNow())
+
+	// 200 queries accumulate during the warm-up, but only 3 are admitted at once.
+	clock.now = clock.now.Add(4 * time.Second)
+	count := 0
+	for throttler.shouldRunNow() {
+		count++
+	}
+	assert.Equal(t, 3, count)
+}
+
+func TestThrottler_IsAccurateWithConcurrentWorkers(t *testing.T) {
+	const (
+		workers = 8
+		calls   = 1000
+	)
+	// Every reading of the clock advances it by 1ms, for a total of 8s.
+	start := time.Unix(1000, 0)
+	var ticks atomic.Int64
+	throttler := newThrottler(50, 0, 2*time.Second)
+	throttler.now = func() time.Time {
+		return start.Add(time.Duration(ticks.Add(1)-1) * time.Millisecond)
+	}
+
+	var admitted atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < calls; j++ {
+				if throttler.shouldRunNow() {
+					admitted.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 50/s over 8s minus half of the 2s warm-up.
+	assert.InDelta(t, 350, admitted.Load(), 1)
+}
+
 func TestArchiveInquirer_RunProgressReport(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
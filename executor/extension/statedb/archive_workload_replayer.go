@@ -0,0 +1,350 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MakeArchiveWorkloadReplayer creates an extension replaying the archive queries recorded in
+// the workload file of the configuration, each at its recorded offset from the start of the run.
+func MakeArchiveWorkloadReplayer(cfg *utils.Config) executor.Extension[txcontext.TxContext] {
+	if cfg.QueryWorkloadFile == "" {
+		return extension.NilExtension[txcontext.TxContext]{}
+	}
+	return makeArchiveWorkloadReplayer(cfg, logger.NewLogger(cfg.LogLevel, "Archive Workload"), time.Now, waitOrStop)
+}
+
+func makeArchiveWorkloadReplayer(
+	cfg *utils.Config,
+	log logger.Logger,
+	now func() time.Time,
+	wait func(time.Duration, <-chan struct{}) bool,
+) *archiveWorkloadReplayer {
+	return &archiveWorkloadReplayer{
+		cfg:     cfg,
+		log:     log,
+		now:     now,
+		wait:    wait,
+		stopped: utils.MakeEvent(),
+	}
+}
+
+// archiveWorkloadReplayer schedules the queries of a workload file against the archive of the
+// StateDb and measures the drift between the intended and the actual start of each query.
+type archiveWorkloadReplayer struct {
+	extension.NilExtension[txcontext.TxContext]
+	cfg  *utils.Config
+	log  logger.Logger
+	now  func() time.Time
+	wait func(time.Duration, <-chan struct{}) bool // waits for given duration; false if stopped before
+
+	stopped utils.Event
+	done    sync.WaitGroup
+
+	mutex   sync.Mutex
+	drifts  []time.Duration
+	failed  int
+	skipped int // malformed lines of the workload file
+}
+
+// workloadQuery is a query of the workload file.
+type workloadQuery struct {
+	line    int
+	offset  time.Duration
+	block   uint64
+	account common.Address
+	key     *common.Hash // nil if the balance is queried
+}
+
+// workloadRecord is a line of a JSONL workload file.
+type workloadRecord struct {
+	OffsetMs *float64 `json:"offset_ms"`
+	Block    *uint64  `json:"block"`
+	Account  string   `json:"account"`
+	Key      string   `json:"key,omitempty"`
+}
+
+// PreRun reads the workload file and starts the replay of its queries.
+func (r *archiveWorkloadReplayer) PreRun(_ executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	if r.cfg.QueryWorkloadSpeedup <= 0 {
+		return fmt.Errorf("--%v must be greater than 0", utils.QueryWorkloadSpeedupFlag.Name)
+	}
+	if ctx.State == nil {
+		return errors.New("cannot replay archive queries without a StateDb")
+	}
+	queries, err := r.readWorkload(r.cfg.QueryWorkloadFile)
+	if err != nil {
+		return err
+	}
+	r.log.Noticef("Replaying %d archive queries of %v at speedup %v", len(queries), r.cfg.QueryWorkloadFile, r.cfg.QueryWorkloadSpeedup)
+
+	r.done.Add(1)
+	go r.replay(queries, ctx.State, ctx.ErrorInput)
+	return nil
+}
+
+// PostRun waits for the remaining queries of the workload and reports the schedule drift.
+// The remaining queries are abandoned if the run failed.
+func (r *archiveWorkloadReplayer) PostRun(_ executor.State[txcontext.TxContext], _ *executor.Context, err error) error {
+	if err != nil {
+		r.stopped.Signal()
+	} else {
+		r.log.Notice("Waiting for the remaining archive queries of the workload")
+	}
+	r.done.Wait()
+	r.stopped.Signal()
+	r.report()
+	return nil
+}
+
+// replay runs the queries at their intended time, i.e. their offset scaled by the speedup.
+// Queries running late are started right away, so the drift of late queries accumulates.
+func (r *archiveWorkloadReplayer) replay(queries []workloadQuery, db state.StateDB, errCh chan error) {
+	defer r.done.Done()
+	start := r.now()
+	for _, q := range queries {
+		intended := time.Duration(float64(q.offset) / r.cfg.QueryWorkloadSpeedup)
+		if delay := intended - r.now().Sub(start); delay > 0 && !r.wait(delay, r.stopped.Wait()) {
+			return
+		}
+		if r.stopped.HasHappened() {
+			return
+		}
+		drift := r.now().Sub(start) - intended
+		err := runWorkloadQuery(db, q)
+
+		r.mutex.Lock()
+		r.drifts = append(r.drifts, drift)
+		if err != nil {
+			r.failed++
+		}
+		r.mutex.Unlock()
+
+		if err != nil {
+			err = fmt.Errorf("archive query of workload line %d failed; %w", q.line, err)
+			r.log.Warning(err)
+			if errCh != nil {
+				errCh <- err
+			}
+		}
+	}
+}
+
+// report logs the number of queries and the drift of their actual start from the intended one.
+func (r *archiveWorkloadReplayer) report() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.skipped > 0 {
+		r.log.Warningf("Skipped %d malformed lines of the workload file", r.skipped)
+	}
+	if len(r.drifts) == 0 {
+		r.log.Notice("No archive queries of the workload were executed")
+		return
+	}
+	stats := computeDriftStats(r.drifts)
+	r.log.Noticef("Executed %d archive queries of the workload, %d failed; schedule drift mean %v, p50 %v, p95 %v, max %v",
+		len(r.drifts), r.failed, stats.mean, stats.p50, stats.p95, stats.max)
+}
+
+// driftStats summarizes the drifts of all executed queries.
+type driftStats struct {
+	mean, p50, p95, max time.Duration
+}
+
+func computeDriftStats(drifts []time.Duration) driftStats {
+	sorted := slices.Clone(drifts)
+	slices.Sort(sorted)
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	percentile := func(p int) time.Duration {
+		// nearest-rank percentile
+		rank := (p*len(sorted) + 99) / 100
+		return sorted[max(rank, 1)-1]
+	}
+	return driftStats{
+		mean: total / time.Duration(len(sorted)),
+		p50:  percentile(50),
+		p95:  percentile(95),
+		max:  sorted[len(sorted)-1],
+	}
+}
+
+// runWorkloadQuery reads the balance, or the storage slot if given, of the queried account in the archive.
+func runWorkloadQuery(db state.StateDB, q workloadQuery) (err error) {
+	archive, err := db.GetArchiveState(q.block)
+	if err != nil {
+		return fmt.Errorf("cannot get archive of block %d; %w", q.block, err)
+	}
+	defer func() {
+		err = errors.Join(err, archive.Release())
+	}()
+	if err = archive.BeginTransaction(0); err != nil {
+		return fmt.Errorf("cannot begin transaction; %w", err)
+	}
+	if q.key != nil {
+		archive.GetState(q.account, *q.key)
+	} else {
+		archive.GetBalance(q.account)
+	}
+	return archive.EndTransaction()
+}
+
+// readWorkload reads the queries of given workload file ordered by their offset. Files with
+// the extension .jsonl contain a JSON record per line, any other file is read as CSV with the
+// columns offset_ms, block, account and an optional storage key. Malformed lines are skipped.
+func (r *archiveWorkloadReplayer) readWorkload(path string) ([]workloadQuery, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open workload file; %w", err)
+	}
+	defer file.Close()
+
+	parse := parseCsvWorkloadLine
+	if filepath.Ext(path) == ".jsonl" {
+		parse = parseJsonWorkloadLine
+	}
+
+	var queries []workloadQuery
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || (line == 1 && strings.HasPrefix(text, "offset_ms")) {
+			continue
+		}
+		q, err := parse(text)
+		if err != nil {
+			r.log.Warningf("Skipping line %d of workload file; %v", line, err)
+			r.skipped++
+			continue
+		}
+		q.line = line
+		queries = append(queries, q)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read workload file; %w", err)
+	}
+	slices.SortStableFunc(queries, func(a, b workloadQuery) int {
+		return cmp.Compare(a.offset, b.offset)
+	})
+	return queries, nil
+}
+
+func parseCsvWorkloadLine(text string) (workloadQuery, error) {
+	fields, err := csv.NewReader(strings.NewReader(text)).Read()
+	if err != nil {
+		return workloadQuery{}, err
+	}
+	if len(fields) != 3 && len(fields) != 4 {
+		return workloadQuery{}, fmt.Errorf("expected 3 or 4 fields, got %d", len(fields))
+	}
+	offset, err := strconv.ParseFloat(strings.TrimSpace(fields[0]), 64)
+	if err != nil {
+		return workloadQuery{}, fmt.Errorf("invalid offset %q", fields[0])
+	}
+	block, err := strconv.ParseUint(strings.TrimSpace(fields[1]), 10, 64)
+	if err != nil {
+		return workloadQuery{}, fmt.Errorf("invalid block %q", fields[1])
+	}
+	key := ""
+	if len(fields) == 4 {
+		key = fields[3]
+	}
+	return makeWorkloadQuery(offset, block, fields[2], key)
+}
+
+func parseJsonWorkloadLine(text string) (workloadQuery, error) {
+	var record workloadRecord
+	if err := json.Unmarshal([]byte(text), &record); err != nil {
+		return workloadQuery{}, err
+	}
+	if record.OffsetMs == nil || record.Block == nil {
+		return workloadQuery{}, errors.New("offset_ms and block are required")
+	}
+	return makeWorkloadQuery(*record.OffsetMs, *record.Block, record.Account, record.Key)
+}
+
+func makeWorkloadQuery(offsetMs float64, block uint64, account string, key string) (workloadQuery, error) {
+	if offsetMs < 0 {
+		return workloadQuery{}, fmt.Errorf("negative offset %v", offsetMs)
+	}
+	account = strings.TrimSpace(account)
+	if !common.IsHexAddress(account) {
+		return workloadQuery{}, fmt.Errorf("invalid account %q", account)
+	}
+	q := workloadQuery{
+		offset:  time.Duration(offsetMs * float64(time.Millisecond)),
+		block:   block,
+		account: common.HexToAddress(account),
+	}
+	if key = strings.TrimSpace(key); key != "" {
+		if !isHexHash(key) {
+			return workloadQuery{}, fmt.Errorf("invalid storage key %q", key)
+		}
+		hash := common.HexToHash(key)
+		q.key = &hash
+	}
+	return q, nil
+}
+
+// isHexHash checks whether given string is a hex encoded hash with an optional 0x prefix.
+func isHexHash(s string) bool {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s) == 0 || len(s) > 2*common.HashLength {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// waitOrStop waits for given duration unless stop is closed before; it reports whether the time elapsed.
+func waitOrStop(d time.Duration, stop <-chan struct{}) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
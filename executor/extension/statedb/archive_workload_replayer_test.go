@@ -0,0 +1,212 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeWorkloadClock is a clock advanced only by waiting and by the queries of a test.
+type fakeWorkloadClock struct {
+	mutex   sync.Mutex
+	current time.Time
+	waits   []time.Duration
+}
+
+func (c *fakeWorkloadClock) now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.current
+}
+
+func (c *fakeWorkloadClock) advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.current = c.current.Add(d)
+}
+
+func (c *fakeWorkloadClock) wait(d time.Duration, _ <-chan struct{}) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.waits = append(c.waits, d)
+	c.current = c.current.Add(d)
+	return true
+}
+
+func writeWorkloadFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestArchiveWorkloadReplayer_NoFileDisablesExtension(t *testing.T) {
+	ext := MakeArchiveWorkloadReplayer(&utils.Config{})
+	_, ok := ext.(extension.NilExtension[txcontext.TxContext])
+	assert.True(t, ok)
+}
+
+func TestArchiveWorkloadReplayer_SchedulesQueriesAndComputesDrift(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+
+	path := writeWorkloadFile(t, "workload.csv", `offset_ms,block,account,key
+0,10,0x00000000000000000000000000000000000000aa,
+200,30,0x00000000000000000000000000000000000000bb,0x01
+not-a-number,20,0x00000000000000000000000000000000000000cc
+# queries need not be ordered by offset
+100,20,0x00000000000000000000000000000000000000cc
+400,40,0xinvalid
+500,50,0x00000000000000000000000000000000000000dd
+`)
+	cfg := &utils.Config{QueryWorkloadFile: path, QueryWorkloadSpeedup: 2}
+	clock := &fakeWorkloadClock{current: time.Unix(1000, 0)}
+	ext := makeArchiveWorkloadReplayer(cfg, log, clock.now, clock.wait)
+
+	// each query takes 60ms
+	query := func(block uint64) {
+		db.EXPECT().GetArchiveState(block).Return(archive, nil)
+		archive.EXPECT().BeginTransaction(uint32(0))
+		archive.EXPECT().EndTransaction().Do(func() { clock.advance(60 * time.Millisecond) })
+		archive.EXPECT().Release()
+	}
+	gomock.InOrder(
+		log.EXPECT().Warningf("Skipping line %d of workload file; %v", 4, gomock.Any()),
+		log.EXPECT().Warningf("Skipping line %d of workload file; %v", 7, gomock.Any()),
+		log.EXPECT().Noticef("Replaying %d archive queries of %v at speedup %v", 4, path, 2.0),
+	)
+	balance := archive.EXPECT().GetBalance(common.HexToAddress("0xaa")).Return(uint256.NewInt(1))
+	storage := archive.EXPECT().GetState(common.HexToAddress("0xbb"), common.HexToHash("0x01")).Return(common.Hash{})
+	gomock.InOrder(balance, archive.EXPECT().GetBalance(common.HexToAddress("0xcc")).Return(uint256.NewInt(1)), storage,
+		archive.EXPECT().GetBalance(common.HexToAddress("0xdd")).Return(uint256.NewInt(1)))
+	for _, block := range []uint64{10, 20, 30, 50} {
+		query(block)
+	}
+	log.EXPECT().Notice(gomock.Any())
+	log.EXPECT().Warningf("Skipped %d malformed lines of the workload file", 2)
+	log.EXPECT().Noticef(gomock.Any(), 4, 0, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+
+	ctx := &executor.Context{State: db}
+	require.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+	require.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, nil))
+
+	// intended starts are 0, 50, 100 and 250ms; the first query delays the second and the third one
+	assert.Equal(t, []time.Duration{0, 10 * time.Millisecond, 20 * time.Millisecond, 0}, ext.drifts)
+	assert.Equal(t, []time.Duration{70 * time.Millisecond}, clock.waits)
+	assert.Equal(t, 2, ext.skipped)
+}
+
+func TestArchiveWorkloadReplayer_ReadsJsonLines(t *testing.T) {
+	path := writeWorkloadFile(t, "workload.jsonl", `{"offset_ms":1.5,"block":7,"account":"0x00000000000000000000000000000000000000aa","key":"0x02"}
+{"offset_ms":0,"block":5,"account":"0x00000000000000000000000000000000000000bb"}
+{"block":6,"account":"0x00000000000000000000000000000000000000bb"}
+{"offset_ms":1,
+`)
+	ext := makeArchiveWorkloadReplayer(&utils.Config{}, logger.NewLogger("critical", "Test"), time.Now, waitOrStop)
+	queries, err := ext.readWorkload(path)
+	require.NoError(t, err)
+
+	key := common.HexToHash("0x02")
+	assert.Equal(t, []workloadQuery{
+		{line: 2, offset: 0, block: 5, account: common.HexToAddress("0xbb")},
+		{line: 1, offset: 1500 * time.Microsecond, block: 7, account: common.HexToAddress("0xaa"), key: &key},
+	}, queries)
+	assert.Equal(t, 2, ext.skipped)
+}
+
+func TestArchiveWorkloadReplayer_FailedQueriesAreReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+
+	path := writeWorkloadFile(t, "workload.csv", "0,10,0x00000000000000000000000000000000000000aa\n")
+	cfg := &utils.Config{QueryWorkloadFile: path, QueryWorkloadSpeedup: 1}
+	clock := &fakeWorkloadClock{}
+	ext := makeArchiveWorkloadReplayer(cfg, logger.NewLogger("critical", "Test"), clock.now, clock.wait)
+
+	db.EXPECT().GetArchiveState(uint64(10)).Return(nil, errors.New("pruned"))
+
+	ctx := &executor.Context{State: db, ErrorInput: make(chan error, 1)}
+	require.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+	require.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, nil))
+
+	assert.Equal(t, 1, ext.failed)
+	require.Len(t, ctx.ErrorInput, 1)
+	assert.ErrorContains(t, <-ctx.ErrorInput, "archive query of workload line 1 failed; cannot get archive of block 10; pruned")
+}
+
+func TestArchiveWorkloadReplayer_FailedRunStopsWaitingQueries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+
+	path := writeWorkloadFile(t, "workload.csv", "3600000,10,0x00000000000000000000000000000000000000aa\n")
+	cfg := &utils.Config{QueryWorkloadFile: path, QueryWorkloadSpeedup: 1}
+	ext := makeArchiveWorkloadReplayer(cfg, logger.NewLogger("critical", "Test"), time.Now, waitOrStop)
+
+	ctx := &executor.Context{State: db}
+	require.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, errors.New("failed")))
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("PostRun waits for the workload of a failed run")
+	}
+	assert.Empty(t, ext.drifts)
+}
+
+func TestArchiveWorkloadReplayer_InvalidSpeedupIsRejected(t *testing.T) {
+	cfg := &utils.Config{QueryWorkloadFile: "workload.csv", QueryWorkloadSpeedup: 0}
+	ext := makeArchiveWorkloadReplayer(cfg, logger.NewLogger("critical", "Test"), time.Now, waitOrStop)
+	err := ext.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{})
+	assert.ErrorContains(t, err, "--speedup must be greater than 0")
+}
+
+func TestArchiveWorkloadReplayer_ComputeDriftStats(t *testing.T) {
+	var drifts []time.Duration
+	for i := 100; i > 0; i-- {
+		drifts = append(drifts, time.Duration(i)*time.Millisecond)
+	}
+	stats := computeDriftStats(drifts)
+	assert.Equal(t, driftStats{
+		mean: 50500 * time.Microsecond,
+		p50:  50 * time.Millisecond,
+		p95:  95 * time.Millisecond,
+		max:  100 * time.Millisecond,
+	}, stats)
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+// MakeCheckpointTrigger creates an executor.Extension making the StateDB create a checkpoint
+// after the block during which the file at cfg.CarmenCheckpointTrigger was created. It
+// complements the checkpoints Carmen creates in regular intervals, e.g. to secure the
+// progress of a long run before a planned reboot.
+func MakeCheckpointTrigger[T any](cfg *utils.Config) executor.Extension[T] {
+	if cfg.CarmenCheckpointTrigger == "" {
+		return extension.NilExtension[T]{}
+	}
+	return makeCheckpointTrigger[T](cfg.CarmenCheckpointTrigger, logger.NewLogger(cfg.LogLevel, "Checkpoint-Trigger"))
+}
+
+func makeCheckpointTrigger[T any](path string, log logger.Logger) *checkpointTrigger[T] {
+	return &checkpointTrigger[T]{path: path, log: log}
+}
+
+type checkpointTrigger[T any] struct {
+	extension.NilExtension[T]
+	path string
+	log  logger.Logger
+	mu   sync.Mutex // held while a checkpoint is created
+}
+
+// PreRun removes a trigger file left over from a previous run.
+func (c *checkpointTrigger[T]) PreRun(executor.State[T], *executor.Context) error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("cannot remove checkpoint trigger %v; %w", c.path, err)
+	}
+	c.log.Noticef("Create %v to make the StateDB create a checkpoint", c.path)
+	return nil
+}
+
+// PostBlock creates a checkpoint if the trigger file exists. The file is removed once the
+// checkpoint is created, hence all triggers arriving before are coalesced into a single
+// checkpoint. A failed checkpoint is reported, but does not stop the run.
+func (c *checkpointTrigger[T]) PostBlock(state executor.State[T], ctx *executor.Context) error {
+	if !c.mu.TryLock() {
+		// a checkpoint is being created by a concurrent block, which covers the trigger
+		return nil
+	}
+	defer c.mu.Unlock()
+
+	if _, err := os.Stat(c.path); err != nil {
+		return nil
+	}
+
+	start := time.Now()
+	err := ctx.State.CreateCheckpoint()
+	elapsed := time.Since(start)
+	if rmErr := os.Remove(c.path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+		return fmt.Errorf("cannot remove checkpoint trigger %v; %w", c.path, rmErr)
+	}
+
+	if err != nil {
+		c.log.Errorf("Cannot create checkpoint after block %v; %v", state.Block, err)
+		return nil
+	}
+	c.log.Noticef("Checkpoint created after block %v; took %v", state.Block, elapsed.Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckpointTrigger_NoTriggerPathYieldsNilExtension(t *testing.T) {
+	cfg := &utils.Config{}
+	ext := MakeCheckpointTrigger[any](cfg)
+	if _, ok := ext.(extension.NilExtension[any]); !ok {
+		t.Errorf("checkpoint trigger is enabled although no trigger path is set")
+	}
+}
+
+func TestCheckpointTrigger_PreRunRemovesStaleTrigger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+
+	log.EXPECT().Noticef(gomock.Any(), path)
+
+	ext := makeCheckpointTrigger[any](path, log)
+	require.NoError(t, ext.PreRun(executor.State[any]{}, &executor.Context{}))
+	require.NoFileExists(t, path)
+}
+
+func TestCheckpointTrigger_CreatesCheckpointOnlyWhenTriggered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+	path := filepath.Join(t.TempDir(), "checkpoint")
+
+	gomock.InOrder(
+		db.EXPECT().CreateCheckpoint(),
+		log.EXPECT().Noticef("Checkpoint created after block %v; took %v", 2, gomock.Any()),
+	)
+
+	ext := makeCheckpointTrigger[any](path, log)
+	ctx := &executor.Context{State: db}
+
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 1}, ctx))
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 2}, ctx))
+	require.NoFileExists(t, path)
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 3}, ctx))
+}
+
+func TestCheckpointTrigger_CoalescesConcurrentTriggers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+	path := filepath.Join(t.TempDir(), "checkpoint")
+
+	ext := makeCheckpointTrigger[any](path, log)
+	ctx := &executor.Context{State: db}
+
+	// triggers arriving while a checkpoint is created, as well as blocks finished meanwhile,
+	// are covered by the running checkpoint
+	db.EXPECT().CreateCheckpoint().DoAndReturn(func() error {
+		require.NoError(t, os.WriteFile(path, []byte("again"), 0644))
+		require.NoError(t, ext.PostBlock(executor.State[any]{Block: 6}, ctx))
+		return nil
+	})
+	log.EXPECT().Noticef(gomock.Any(), 5, gomock.Any())
+
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 5}, ctx))
+	require.NoFileExists(t, path)
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 7}, ctx))
+}
+
+func TestCheckpointTrigger_FailedCheckpointDoesNotStopRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	injectedErr := errors.New("injected error")
+
+	gomock.InOrder(
+		db.EXPECT().CreateCheckpoint().Return(injectedErr),
+		log.EXPECT().Errorf("Cannot create checkpoint after block %v; %v", 4, injectedErr),
+	)
+
+	ext := makeCheckpointTrigger[any](path, log)
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 4}, &executor.Context{State: db}))
+	require.NoFileExists(t, path)
+}
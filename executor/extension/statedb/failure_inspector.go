@@ -0,0 +1,291 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const failureInspectorHelp = `Commands:
+  balance <addr>                          balance of the account in the failing block
+  nonce <addr>                            nonce of the account in the failing block
+  code <addr>                             code hash and size of the account in the failing block
+  storage <addr> <key>                    storage slot of the account in the failing block
+  at <block> balance|nonce|code <addr>    same as above, queried on the archive state of given block
+  at <block> storage <addr> <key>         same as above, queried on the archive state of given block
+  diff <blockA> <blockB> <addr> [<key>..] changes of the account (and given slots) between two blocks
+  help                                    prints this message
+  exit                                    terminates the run`
+
+// MakeFailureInspector creates an extension pausing a failed run with an interactive console,
+// which allows inspecting the StateDb of the failing block and, if enabled, its archive.
+func MakeFailureInspector[T any](cfg *utils.Config) executor.Extension[T] {
+	if !cfg.PauseOnFailure {
+		return extension.NilExtension[T]{}
+	}
+	return makeFailureInspector[T](cfg, os.Stdin, os.Stdout, logger.NewLogger(cfg.LogLevel, "Failure-Inspector"))
+}
+
+func makeFailureInspector[T any](cfg *utils.Config, in io.Reader, out io.Writer, log logger.Logger) *failureInspector[T] {
+	return &failureInspector[T]{
+		cfg: cfg,
+		in:  in,
+		out: out,
+		log: log,
+	}
+}
+
+type failureInspector[T any] struct {
+	extension.NilExtension[T]
+	cfg *utils.Config
+	in  io.Reader
+	out io.Writer
+	log logger.Logger
+}
+
+// PostRun opens the inspection console if the run failed. The StateDb is still open at this
+// point since the inspector is registered after the StateDb manager.
+func (f *failureInspector[T]) PostRun(st executor.State[T], ctx *executor.Context, err error) error {
+	if err == nil || ctx.State == nil {
+		return nil
+	}
+	f.log.Errorf("Run failed at block %d; %v", st.Block, err)
+	f.log.Notice("Pausing run for inspection, type 'help' for available commands")
+
+	session := newInspectionSession(f.cfg, ctx.State, f.out)
+	scanner := bufio.NewScanner(f.in)
+	for {
+		fmt.Fprint(f.out, "> ")
+		if !scanner.Scan() {
+			break
+		}
+		if !session.execute(scanner.Text()) {
+			break
+		}
+	}
+	return errors.Join(scanner.Err(), session.close())
+}
+
+// inspectionSession executes the commands of the console. Archive states opened during
+// the session are kept for repeated queries and released once the session is closed.
+type inspectionSession struct {
+	cfg      *utils.Config
+	db       state.StateDB
+	out      io.Writer
+	archives map[uint64]state.NonCommittableStateDB
+}
+
+func newInspectionSession(cfg *utils.Config, db state.StateDB, out io.Writer) *inspectionSession {
+	return &inspectionSession{
+		cfg:      cfg,
+		db:       db,
+		out:      out,
+		archives: make(map[uint64]state.NonCommittableStateDB),
+	}
+}
+
+// execute runs a single command line and returns false if the session should end.
+func (s *inspectionSession) execute(line string) bool {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return true
+	}
+	var err error
+	switch args[0] {
+	case "exit", "quit":
+		return false
+	case "help":
+		fmt.Fprintln(s.out, failureInspectorHelp)
+	case "at":
+		err = s.at(args[1:])
+	case "diff":
+		err = s.diff(args[1:])
+	default:
+		err = s.query(s.db, args)
+	}
+	if err != nil {
+		fmt.Fprintf(s.out, "error: %v\n", err)
+	}
+	return true
+}
+
+// query prints a single property of an account of given state.
+func (s *inspectionSession) query(db state.VmStateDB, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("unknown or incomplete command %q, type 'help' for available commands", strings.Join(args, " "))
+	}
+	addr, err := parseAddress(args[1])
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "balance":
+		fmt.Fprintln(s.out, db.GetBalance(addr))
+	case "nonce":
+		fmt.Fprintln(s.out, db.GetNonce(addr))
+	case "code":
+		fmt.Fprintf(s.out, "hash: %v, size: %d\n", db.GetCodeHash(addr).Hex(), len(db.GetCode(addr)))
+	case "storage":
+		if len(args) != 3 {
+			return errors.New("usage: storage <addr> <key>")
+		}
+		key, err := parseHash(args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(s.out, db.GetState(addr, key).Hex())
+	default:
+		return fmt.Errorf("unknown command %q, type 'help' for available commands", args[0])
+	}
+	return nil
+}
+
+// at runs a query on the archive state of given block.
+func (s *inspectionSession) at(args []string) error {
+	if len(args) < 2 {
+		return errors.New("usage: at <block> <query>")
+	}
+	archive, err := s.getArchive(args[0])
+	if err != nil {
+		return err
+	}
+	return s.query(archive, args[1:])
+}
+
+// diff prints changes of an account, and optionally given storage slots, between two blocks.
+func (s *inspectionSession) diff(args []string) error {
+	if len(args) < 3 {
+		return errors.New("usage: diff <blockA> <blockB> <addr> [<key>...]")
+	}
+	a, err := s.getArchive(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := s.getArchive(args[1])
+	if err != nil {
+		return err
+	}
+	addr, err := parseAddress(args[2])
+	if err != nil {
+		return err
+	}
+	keys := make([]common.Hash, 0, len(args)-3)
+	for _, arg := range args[3:] {
+		key, err := parseHash(arg)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	changes := 0
+	report := func(what string, before, after any) {
+		if before != after {
+			fmt.Fprintf(s.out, "%v: %v -> %v\n", what, before, after)
+			changes++
+		}
+	}
+	report("exists", a.Exist(addr), b.Exist(addr))
+	report("balance", a.GetBalance(addr).String(), b.GetBalance(addr).String())
+	report("nonce", a.GetNonce(addr), b.GetNonce(addr))
+	report("code hash", a.GetCodeHash(addr).Hex(), b.GetCodeHash(addr).Hex())
+	for _, key := range keys {
+		report("storage "+key.Hex(), a.GetState(addr, key).Hex(), b.GetState(addr, key).Hex())
+	}
+	if changes == 0 {
+		fmt.Fprintf(s.out, "no changes between block %v and %v\n", args[0], args[1])
+	}
+	return nil
+}
+
+// getArchive returns the archive state of given block, which is opened if it is not yet in use.
+func (s *inspectionSession) getArchive(arg string) (state.NonCommittableStateDB, error) {
+	block, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block number %q", arg)
+	}
+	if archive, found := s.archives[block]; found {
+		return archive, nil
+	}
+	if !s.cfg.ArchiveMode {
+		return nil, errors.New("archive is not enabled, re-run with --archive to query historic blocks")
+	}
+	height, empty, err := s.db.GetArchiveBlockHeight()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get archive block height; %w", err)
+	}
+	if empty {
+		return nil, errors.New("archive does not contain any block yet")
+	}
+	if block > height {
+		return nil, fmt.Errorf("archive does not cover block %d, its last block is %d", block, height)
+	}
+	archive, err := s.db.GetArchiveState(block)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get archive state of block %d; %w", block, err)
+	}
+	s.archives[block] = archive
+	return archive, nil
+}
+
+// close releases all archive states opened during the session.
+func (s *inspectionSession) close() error {
+	var errs []error
+	for block, archive := range s.archives {
+		if err := archive.Release(); err != nil {
+			errs = append(errs, fmt.Errorf("cannot release archive state of block %d; %w", block, err))
+		}
+		delete(s.archives, block)
+	}
+	return errors.Join(errs...)
+}
+
+func parseAddress(arg string) (common.Address, error) {
+	if !common.IsHexAddress(arg) {
+		return common.Address{}, fmt.Errorf("invalid address %q", arg)
+	}
+	return common.HexToAddress(arg), nil
+}
+
+func parseHash(arg string) (common.Hash, error) {
+	digits := strings.TrimPrefix(arg, "0x")
+	if len(digits) > 2*common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid key %q", arg)
+	}
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	bytes, err := hex.DecodeString(digits)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("invalid key %q", arg)
+	}
+	return common.BytesToHash(bytes), nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFailureInspector_DisabledByDefault(t *testing.T) {
+	ext := MakeFailureInspector[any](&utils.Config{})
+	assert.IsType(t, extension.NilExtension[any]{}, ext)
+}
+
+func TestFailureInspector_DoesNotPauseSuccessfulRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	var out bytes.Buffer
+	ext := makeFailureInspector[any](&utils.Config{}, strings.NewReader("help\n"), &out, logger.NewMockLogger(ctrl))
+	require.NoError(t, ext.PostRun(executor.State[any]{}, &executor.Context{State: state.NewMockStateDB(ctrl)}, nil))
+	assert.Empty(t, out.String())
+}
+
+func TestFailureInspector_QueriesStateOfFailingBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	addr := common.Address{1}
+
+	db.EXPECT().GetBalance(addr).Return(uint256.NewInt(42))
+	db.EXPECT().GetNonce(addr).Return(uint64(7))
+	db.EXPECT().GetState(addr, common.Hash{0x12}).Return(common.Hash{3})
+
+	out := runInspector(t, &utils.Config{}, db,
+		"balance "+addr.Hex(),
+		"nonce "+addr.Hex(),
+		"storage "+addr.Hex()+" 0x12",
+	)
+	assert.Contains(t, out, "42\n")
+	assert.Contains(t, out, "7\n")
+	assert.Contains(t, out, common.Hash{3}.Hex())
+}
+
+func TestFailureInspector_QueriesArchiveStatesAndReleasesThem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+	addr := common.Address{1}
+
+	db.EXPECT().GetArchiveBlockHeight().Return(uint64(10), false, nil)
+	// the archive of block 5 is opened only once and reused for the second query
+	db.EXPECT().GetArchiveState(uint64(5)).Return(archive, nil)
+	archive.EXPECT().GetBalance(addr).Return(uint256.NewInt(1))
+	archive.EXPECT().GetState(addr, common.Hash{1}).Return(common.Hash{2})
+	archive.EXPECT().Release()
+
+	out := runInspector(t, &utils.Config{ArchiveMode: true}, db,
+		"at 5 balance "+addr.Hex(),
+		"at 5 storage "+addr.Hex()+" 0x01",
+	)
+	assert.Contains(t, out, "1\n")
+	assert.Contains(t, out, common.Hash{2}.Hex())
+}
+
+func TestFailureInspector_ReportsBlocksNotCoveredByArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	addr := common.Address{1}.Hex()
+
+	tests := map[string]struct {
+		cfg   *utils.Config
+		setup func(db *state.MockStateDB)
+		want  string
+	}{
+		"archive disabled": {
+			cfg:   &utils.Config{},
+			setup: func(*state.MockStateDB) {},
+			want:  "archive is not enabled",
+		},
+		"empty archive": {
+			cfg: &utils.Config{ArchiveMode: true},
+			setup: func(db *state.MockStateDB) {
+				db.EXPECT().GetArchiveBlockHeight().Return(uint64(0), true, nil)
+			},
+			want: "archive does not contain any block yet",
+		},
+		"block beyond archive": {
+			cfg: &utils.Config{ArchiveMode: true},
+			setup: func(db *state.MockStateDB) {
+				db.EXPECT().GetArchiveBlockHeight().Return(uint64(4), false, nil)
+			},
+			want: "archive does not cover block 5, its last block is 4",
+		},
+		"archive failure": {
+			cfg: &utils.Config{ArchiveMode: true},
+			setup: func(db *state.MockStateDB) {
+				db.EXPECT().GetArchiveBlockHeight().Return(uint64(0), false, errors.New("broken"))
+			},
+			want: "cannot get archive block height; broken",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			db := state.NewMockStateDB(ctrl)
+			test.setup(db)
+			out := runInspector(t, test.cfg, db, "at 5 balance "+addr)
+			assert.Contains(t, out, "error: "+test.want)
+		})
+	}
+}
+
+func TestFailureInspector_DiffShowsChangesBetweenBlocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	a := state.NewMockNonCommittableStateDB(ctrl)
+	b := state.NewMockNonCommittableStateDB(ctrl)
+	addr := common.Address{1}
+	key := common.Hash{1}
+
+	db.EXPECT().GetArchiveBlockHeight().Return(uint64(10), false, nil).Times(2)
+	db.EXPECT().GetArchiveState(uint64(3)).Return(a, nil)
+	db.EXPECT().GetArchiveState(uint64(6)).Return(b, nil)
+	for _, archive := range []*state.MockNonCommittableStateDB{a, b} {
+		archive.EXPECT().Exist(addr).Return(true)
+		archive.EXPECT().GetNonce(addr).Return(uint64(1))
+		archive.EXPECT().GetCodeHash(addr).Return(common.Hash{})
+		archive.EXPECT().Release()
+	}
+	a.EXPECT().GetBalance(addr).Return(uint256.NewInt(10))
+	b.EXPECT().GetBalance(addr).Return(uint256.NewInt(20))
+	a.EXPECT().GetState(addr, key).Return(common.Hash{})
+	b.EXPECT().GetState(addr, key).Return(common.Hash{5})
+
+	out := runInspector(t, &utils.Config{ArchiveMode: true}, db, "diff 3 6 "+addr.Hex()+" "+key.Hex())
+	assert.Contains(t, out, "balance: 10 -> 20\n")
+	assert.Contains(t, out, "storage "+key.Hex()+": "+common.Hash{}.Hex()+" -> "+common.Hash{5}.Hex())
+	assert.NotContains(t, out, "nonce")
+	assert.NotContains(t, out, "code hash")
+}
+
+func TestFailureInspector_ReportsInvalidCommands(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	out := runInspector(t, &utils.Config{}, state.NewMockStateDB(ctrl),
+		"foo",
+		"balance 0x12",
+		"storage "+common.Address{1}.Hex()+" xyz",
+		"at x balance "+common.Address{1}.Hex(),
+		"diff 1",
+	)
+	assert.Contains(t, out, `unknown or incomplete command "foo"`)
+	assert.Contains(t, out, `invalid address "0x12"`)
+	assert.Contains(t, out, `invalid key "xyz"`)
+	assert.Contains(t, out, `invalid block number "x"`)
+	assert.Contains(t, out, "usage: diff")
+}
+
+func TestFailureInspector_ReportsReleaseFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+
+	db.EXPECT().GetArchiveBlockHeight().Return(uint64(10), false, nil)
+	db.EXPECT().GetArchiveState(uint64(1)).Return(archive, nil)
+	archive.EXPECT().GetNonce(common.Address{1}).Return(uint64(0))
+	archive.EXPECT().Release().Return(errors.New("boom"))
+	log.EXPECT().Errorf(gomock.Any(), gomock.Any(), gomock.Any())
+	log.EXPECT().Notice(gomock.Any())
+
+	var out bytes.Buffer
+	ext := makeFailureInspector[any](&utils.Config{ArchiveMode: true}, strings.NewReader("at 1 nonce "+common.Address{1}.Hex()+"\nexit\n"), &out, log)
+	err := ext.PostRun(executor.State[any]{Block: 2}, &executor.Context{State: db}, errors.New("failure"))
+	require.ErrorContains(t, err, "cannot release archive state of block 1; boom")
+}
+
+// runInspector runs the inspector of a failed run with given console input and returns its output.
+func runInspector(t *testing.T, cfg *utils.Config, db state.StateDB, lines ...string) string {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	log.EXPECT().Errorf("Run failed at block %d; %v", 2, gomock.Any())
+	log.EXPECT().Notice(gomock.Any())
+
+	var out bytes.Buffer
+	ext := makeFailureInspector[any](cfg, strings.NewReader(strings.Join(lines, "\n")+"\n"), &out, log)
+	require.NoError(t, ext.PostRun(executor.State[any]{Block: 2}, &executor.Context{State: db}, errors.New("failure")))
+	return out.String()
+}
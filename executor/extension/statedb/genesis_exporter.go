@@ -0,0 +1,306 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/sonic/opera"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/holiman/uint256"
+)
+
+// genesisProgressInterval is the number of exported accounts between progress reports.
+const genesisProgressInterval = 100_000
+
+// MakeGenesisExporter creates an extension exporting the final state of the run into a genesis
+// json file accepted by the Sonic client. StateDb implementations do not provide a way to iterate
+// their content, hence all accounts and storage slots written during the run, including priming,
+// are recorded and their final values are read from the StateDb at the end of the run.
+func MakeGenesisExporter[T any](cfg *utils.Config) executor.Extension[T] {
+	if cfg.ExportGenesis == "" {
+		return extension.NilExtension[T]{}
+	}
+	return makeGenesisExporter[T](cfg, logger.NewLogger(cfg.LogLevel, "Genesis-Exporter"))
+}
+
+func makeGenesisExporter[T any](cfg *utils.Config, log logger.Logger) executor.Extension[T] {
+	return &genesisExporter[T]{
+		cfg: cfg,
+		log: log,
+	}
+}
+
+type genesisExporter[T any] struct {
+	extension.NilExtension[T]
+	cfg      *utils.Config
+	log      logger.Logger
+	recorder *writeRecorder
+}
+
+// PreRun starts recording of written keys. The StateDb must be created by the run,
+// otherwise keys written before the run would be missing in the genesis.
+func (e *genesisExporter[T]) PreRun(_ executor.State[T], ctx *executor.Context) error {
+	if e.cfg.StateDbSrc != "" {
+		return fmt.Errorf("--%v cannot be used with an existing StateDb, its content cannot be enumerated", utils.ExportGenesisFlag.Name)
+	}
+	e.recorder = newWriteRecorder(ctx.State)
+	ctx.State = e.recorder
+	return nil
+}
+
+// PostRun writes the final value of all recorded keys into the genesis file.
+func (e *genesisExporter[T]) PostRun(state executor.State[T], ctx *executor.Context, err error) error {
+	// the genesis is not exported if the run did not finish
+	if err != nil || ctx.State == nil {
+		return nil
+	}
+
+	root, err := ctx.State.GetHash()
+	if err != nil {
+		return fmt.Errorf("cannot get state root; %w", err)
+	}
+
+	file, err := os.Create(e.cfg.ExportGenesis)
+	if err != nil {
+		return fmt.Errorf("cannot create genesis file; %w", err)
+	}
+	writer := bufio.NewWriter(file)
+
+	// values can only be read within a transaction, the read-only block does not modify the state
+	block := uint64(state.Block)
+	if err = ctx.State.BeginBlock(block); err != nil {
+		return errors.Join(fmt.Errorf("cannot begin block %d; %w", block, err), file.Close())
+	}
+	if err = ctx.State.BeginTransaction(0); err != nil {
+		return errors.Join(fmt.Errorf("cannot begin transaction; %w", err), file.Close())
+	}
+
+	numAccounts, err := e.writeGenesis(writer, ctx.State, root, block-1)
+	err = errors.Join(
+		err,
+		ctx.State.EndTransaction(),
+		ctx.State.EndBlock(),
+		writer.Flush(),
+		file.Close(),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot export genesis; %w", err)
+	}
+
+	e.log.Noticef("Exported %d accounts of block %d with state root %v into %v", numAccounts, block-1, root.Hex(), e.cfg.ExportGenesis)
+	return nil
+}
+
+// sonicGenesisAccount mirrors an account of the json genesis accepted by the Sonic client.
+type sonicGenesisAccount struct {
+	Name    string
+	Address common.Address
+	Balance *big.Int                    `json:",omitempty"`
+	Code    hexutil.Bytes               `json:",omitempty"`
+	Nonce   uint64                      `json:",omitempty"`
+	Storage map[common.Hash]common.Hash `json:",omitempty"`
+}
+
+// writeGenesis streams the genesis account by account, so that only a single account is held in memory.
+// The state root and block are recorded alongside the genesis, so the node can verify the imported state.
+func (e *genesisExporter[T]) writeGenesis(w io.Writer, db state.VmStateDB, root common.Hash, block uint64) (int, error) {
+	header := []struct {
+		key   string
+		value any
+	}{
+		{"Rules", opera.FakeNetRules(opera.GetSonicUpgrades())},
+		{"BlockZeroTime", time.Now().UTC().Truncate(time.Second)},
+		{"StateRoot", root},
+		{"Block", block},
+	}
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return 0, err
+	}
+	for _, field := range header {
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return 0, fmt.Errorf("cannot marshal %v; %w", field.key, err)
+		}
+		if _, err = fmt.Fprintf(w, "%q:%s,", field.key, value); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := io.WriteString(w, `"Accounts":[`); err != nil {
+		return 0, err
+	}
+
+	numAccounts := 0
+	addresses := e.recorder.getAddresses()
+	for i, addr := range addresses {
+		if i%genesisProgressInterval == 0 && i > 0 {
+			e.log.Infof("Exported %d/%d accounts", i, len(addresses))
+		}
+		if !db.Exist(addr) {
+			continue
+		}
+		account := sonicGenesisAccount{
+			Address: addr,
+			Balance: db.GetBalance(addr).ToBig(),
+			Code:    db.GetCode(addr),
+			Nonce:   db.GetNonce(addr),
+			Storage: make(map[common.Hash]common.Hash),
+		}
+		for key := range e.recorder.keys[addr] {
+			if value := db.GetState(addr, key); value != (common.Hash{}) {
+				account.Storage[key] = value
+			}
+		}
+
+		data, err := json.Marshal(account)
+		if err != nil {
+			return numAccounts, fmt.Errorf("cannot marshal account %v; %w", addr, err)
+		}
+		if numAccounts > 0 {
+			data = append([]byte{','}, data...)
+		}
+		if _, err = w.Write(data); err != nil {
+			return numAccounts, err
+		}
+		numAccounts++
+	}
+
+	_, err := io.WriteString(w, "]}")
+	return numAccounts, err
+}
+
+// writeRecorder is a StateDB proxy recording all accounts and storage slots written
+// through the VmStateDB interface or by bulk loads.
+type writeRecorder struct {
+	state.StateDB
+	keys map[common.Address]map[common.Hash]struct{}
+}
+
+func newWriteRecorder(db state.StateDB) *writeRecorder {
+	return &writeRecorder{
+		StateDB: db,
+		keys:    make(map[common.Address]map[common.Hash]struct{}),
+	}
+}
+
+func (r *writeRecorder) recordAccount(addr common.Address) map[common.Hash]struct{} {
+	slots, found := r.keys[addr]
+	if !found {
+		slots = make(map[common.Hash]struct{})
+		r.keys[addr] = slots
+	}
+	return slots
+}
+
+func (r *writeRecorder) recordSlot(addr common.Address, key common.Hash) {
+	r.recordAccount(addr)[key] = struct{}{}
+}
+
+// getAddresses returns all recorded accounts in sorted order.
+func (r *writeRecorder) getAddresses() []common.Address {
+	addresses := make([]common.Address, 0, len(r.keys))
+	for addr := range r.keys {
+		addresses = append(addresses, addr)
+	}
+	slices.SortFunc(addresses, func(a, b common.Address) int { return bytes.Compare(a[:], b[:]) })
+	return addresses
+}
+
+func (r *writeRecorder) CreateAccount(addr common.Address) {
+	r.recordAccount(addr)
+	r.StateDB.CreateAccount(addr)
+}
+
+func (r *writeRecorder) CreateContract(addr common.Address) {
+	r.recordAccount(addr)
+	r.StateDB.CreateContract(addr)
+}
+
+func (r *writeRecorder) AddBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	r.recordAccount(addr)
+	return r.StateDB.AddBalance(addr, amount, reason)
+}
+
+func (r *writeRecorder) SetNonce(addr common.Address, nonce uint64, reason tracing.NonceChangeReason) {
+	r.recordAccount(addr)
+	r.StateDB.SetNonce(addr, nonce, reason)
+}
+
+func (r *writeRecorder) SetCode(addr common.Address, code []byte, reason tracing.CodeChangeReason) []byte {
+	r.recordAccount(addr)
+	return r.StateDB.SetCode(addr, code, reason)
+}
+
+func (r *writeRecorder) SetState(addr common.Address, key common.Hash, value common.Hash) common.Hash {
+	r.recordSlot(addr, key)
+	return r.StateDB.SetState(addr, key, value)
+}
+
+func (r *writeRecorder) StartBulkLoad(block uint64) (state.BulkLoad, error) {
+	load, err := r.StateDB.StartBulkLoad(block)
+	if err != nil {
+		return nil, err
+	}
+	return &bulkLoadRecorder{BulkLoad: load, recorder: r}, nil
+}
+
+// bulkLoadRecorder records accounts and storage slots primed by a bulk load.
+type bulkLoadRecorder struct {
+	state.BulkLoad
+	recorder *writeRecorder
+}
+
+func (l *bulkLoadRecorder) CreateAccount(addr common.Address) {
+	l.recorder.recordAccount(addr)
+	l.BulkLoad.CreateAccount(addr)
+}
+
+func (l *bulkLoadRecorder) SetBalance(addr common.Address, value *uint256.Int) {
+	l.recorder.recordAccount(addr)
+	l.BulkLoad.SetBalance(addr, value)
+}
+
+func (l *bulkLoadRecorder) SetNonce(addr common.Address, nonce uint64) {
+	l.recorder.recordAccount(addr)
+	l.BulkLoad.SetNonce(addr, nonce)
+}
+
+func (l *bulkLoadRecorder) SetState(addr common.Address, key common.Hash, value common.Hash) {
+	l.recorder.recordSlot(addr, key)
+	l.BulkLoad.SetState(addr, key, value)
+}
+
+func (l *bulkLoadRecorder) SetCode(addr common.Address, code []byte) {
+	l.recorder.recordAccount(addr)
+	l.BulkLoad.SetCode(addr, code)
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGenesisExporter_DisabledWithoutPath(t *testing.T) {
+	ext := MakeGenesisExporter[any](&utils.Config{})
+	assert.IsType(t, extension.NilExtension[any]{}, ext)
+}
+
+func TestGenesisExporter_RejectsExistingStateDb(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfg := &utils.Config{ExportGenesis: filepath.Join(t.TempDir(), "genesis.json"), StateDbSrc: t.TempDir()}
+	ext := makeGenesisExporter[any](cfg, logger.NewMockLogger(ctrl))
+	err := ext.PreRun(executor.State[any]{}, &executor.Context{State: state.NewMockStateDB(ctrl)})
+	require.ErrorContains(t, err, "cannot be used with an existing StateDb")
+}
+
+func TestGenesisExporter_ExportsFinalValuesOfWrittenKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	load := state.NewMockBulkLoad(ctrl)
+	log := logger.NewMockLogger(ctrl)
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	ext := makeGenesisExporter[any](&utils.Config{ExportGenesis: path}, log)
+
+	primed, written, deleted := common.Address{1}, common.Address{2}, common.Address{3}
+	root := common.Hash{0xaa}
+
+	ctx := &executor.Context{State: db}
+	require.NoError(t, ext.PreRun(executor.State[any]{}, ctx))
+
+	// priming
+	db.EXPECT().StartBulkLoad(uint64(0)).Return(load, nil)
+	load.EXPECT().CreateAccount(primed)
+	load.EXPECT().SetState(primed, common.Hash{1}, common.Hash{1})
+	bulk, err := ctx.State.StartBulkLoad(0)
+	require.NoError(t, err)
+	bulk.CreateAccount(primed)
+	bulk.SetState(primed, common.Hash{1}, common.Hash{1})
+
+	// execution
+	db.EXPECT().AddBalance(written, uint256.NewInt(5), tracing.BalanceChangeUnspecified)
+	db.EXPECT().SetState(written, common.Hash{2}, common.Hash{2})
+	db.EXPECT().SetState(written, common.Hash{3}, common.Hash{3})
+	db.EXPECT().CreateAccount(deleted)
+	ctx.State.AddBalance(written, uint256.NewInt(5), tracing.BalanceChangeUnspecified)
+	ctx.State.SetState(written, common.Hash{2}, common.Hash{2})
+	ctx.State.SetState(written, common.Hash{3}, common.Hash{3})
+	ctx.State.CreateAccount(deleted)
+
+	gomock.InOrder(
+		db.EXPECT().GetHash().Return(root, nil),
+		db.EXPECT().BeginBlock(uint64(11)),
+		db.EXPECT().BeginTransaction(uint32(0)),
+	)
+	db.EXPECT().Exist(primed).Return(true)
+	db.EXPECT().GetBalance(primed).Return(uint256.NewInt(1))
+	db.EXPECT().GetCode(primed).Return([]byte{0x60})
+	db.EXPECT().GetNonce(primed).Return(uint64(1))
+	db.EXPECT().GetState(primed, common.Hash{1}).Return(common.Hash{1})
+	db.EXPECT().Exist(written).Return(true)
+	db.EXPECT().GetBalance(written).Return(uint256.NewInt(5))
+	db.EXPECT().GetCode(written).Return(nil)
+	db.EXPECT().GetNonce(written).Return(uint64(0))
+	db.EXPECT().GetState(written, common.Hash{2}).Return(common.Hash{2})
+	db.EXPECT().GetState(written, common.Hash{3}).Return(common.Hash{})
+	db.EXPECT().Exist(deleted).Return(false)
+	db.EXPECT().EndTransaction()
+	db.EXPECT().EndBlock()
+	log.EXPECT().Noticef(gomock.Any(), 2, uint64(10), root.Hex(), path)
+
+	require.NoError(t, ext.PostRun(executor.State[any]{Block: 11}, ctx, nil))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var genesis struct {
+		StateRoot common.Hash
+		Block     uint64
+		Accounts  []sonicGenesisAccount
+	}
+	require.NoError(t, json.Unmarshal(data, &genesis))
+	assert.Equal(t, root, genesis.StateRoot)
+	assert.Equal(t, uint64(10), genesis.Block)
+	require.Len(t, genesis.Accounts, 2)
+	assert.Equal(t, primed, genesis.Accounts[0].Address)
+	assert.Equal(t, int64(1), genesis.Accounts[0].Balance.Int64())
+	assert.Equal(t, []byte{0x60}, []byte(genesis.Accounts[0].Code))
+	assert.Equal(t, uint64(1), genesis.Accounts[0].Nonce)
+	assert.Equal(t, map[common.Hash]common.Hash{{1}: {1}}, genesis.Accounts[0].Storage)
+	assert.Equal(t, written, genesis.Accounts[1].Address)
+	assert.Equal(t, map[common.Hash]common.Hash{{2}: {2}}, genesis.Accounts[1].Storage)
+}
+
+func TestGenesisExporter_FailedRunIsNotExported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	ext := makeGenesisExporter[any](&utils.Config{ExportGenesis: path}, logger.NewMockLogger(ctrl))
+
+	ctx := &executor.Context{State: state.NewMockStateDB(ctrl)}
+	require.NoError(t, ext.PreRun(executor.State[any]{}, ctx))
+	require.NoError(t, ext.PostRun(executor.State[any]{Block: 5}, ctx, errors.New("failure")))
+	assert.NoFileExists(t, path)
+}
@@ -43,11 +43,18 @@ type stateDbManager[T any] struct {
 	cfg    *utils.Config
 	log    logger.Logger
 	dbPath string // state db path if the  db is created out side of this extension
+
+	comparison *utils.SchemaComparison // set if two Carmen schemas are compared
 }
 
 func (m *stateDbManager[T]) PreRun(_ executor.State[T], ctx *executor.Context) error {
 	var err error
-	if ctx.State == nil {
+	if ctx.State == nil && m.cfg.CompareSchemas != "" {
+		ctx.State, ctx.StateDbPath, m.comparison, err = utils.PrepareSchemaComparison(m.cfg)
+		if err != nil {
+			return err
+		}
+	} else if ctx.State == nil {
 		ctx.State, ctx.StateDbPath, err = utils.PrepareStateDB(m.cfg)
 		if err != nil {
 			return err
@@ -56,7 +63,9 @@ func (m *stateDbManager[T]) PreRun(_ executor.State[T], ctx *executor.Context) e
 		ctx.StateDbPath = m.dbPath
 	}
 
-	if !m.cfg.ShadowDb {
+	if m.comparison != nil {
+		m.log.Noticef("Comparing Carmen schemas; Variant: %v, Prime Schema: %d, Shadow Schema: %d", m.cfg.DbVariant, m.comparison.PrimeSchema, m.comparison.ShadowSchema)
+	} else if !m.cfg.ShadowDb {
 		m.logDbMode("Db Implementation", m.cfg.DbImpl, m.cfg.DbVariant)
 	} else {
 		m.logDbMode("Prime Db Implementation", m.cfg.DbImpl, m.cfg.DbVariant)
@@ -113,7 +122,7 @@ func (m *stateDbManager[T]) PreRun(_ executor.State[T], ctx *executor.Context) e
 	return nil
 }
 
-func (m *stateDbManager[T]) PostRun(state executor.State[T], ctx *executor.Context, _ error) error {
+func (m *stateDbManager[T]) PostRun(state executor.State[T], ctx *executor.Context, runErr error) error {
 	//  if state was not correctly initialized remove the stateDbPath and abort
 	if ctx.State == nil {
 		var err = fmt.Errorf("state-db is nil")
@@ -124,9 +133,9 @@ func (m *stateDbManager[T]) PostRun(state executor.State[T], ctx *executor.Conte
 	}
 
 	// get root hash before closing db
-	rootHash, err := ctx.State.GetHash()
+	rootHash, err := m.getRootHash(ctx, runErr)
 	if err != nil {
-		return fmt.Errorf("cannot get state hash; %w", err)
+		return err
 	}
 
 	start := time.Now()
@@ -135,6 +144,12 @@ func (m *stateDbManager[T]) PostRun(state executor.State[T], ctx *executor.Conte
 	}
 	m.log.Noticef("DB close time: %v seconds", time.Since(start).Round(time.Second))
 
+	if m.comparison != nil {
+		if err = m.reportSchemaComparison(); err != nil {
+			return err
+		}
+	}
+
 	// db was not modified, then close db without chnaging state-db info and keep db folder as-is.
 	if m.cfg.StateDbSrcReadOnly {
 		m.log.Noticef("State-db directory was read-only %v. No updates to state-db info", ctx.StateDbPath)
@@ -167,6 +182,47 @@ func (m *stateDbManager[T]) PostRun(state executor.State[T], ctx *executor.Conte
 	return nil
 }
 
+// getRootHash returns the state hash of the db. Divergences of compared schemas, including
+// the one which terminated the run, are recorded for the report and the hash of the prime
+// db is used instead.
+func (m *stateDbManager[T]) getRootHash(ctx *executor.Context, runErr error) (gc.Hash, error) {
+	if m.comparison == nil {
+		rootHash, err := ctx.State.GetHash()
+		if err != nil {
+			return gc.Hash{}, fmt.Errorf("cannot get state hash; %w", err)
+		}
+		return rootHash, nil
+	}
+
+	m.comparison.RecordDivergence(runErr)
+	m.comparison.RecordDivergence(ctx.State.Error())
+	if _, err := ctx.State.GetHash(); err != nil {
+		m.comparison.RecordDivergence(err)
+	}
+	rootHash, err := m.comparison.GetPrimeHash()
+	if err != nil {
+		return gc.Hash{}, fmt.Errorf("cannot get state hash; %w", err)
+	}
+	return rootHash, nil
+}
+
+// reportSchemaComparison logs the comparison of closed dbs and writes it to --compare-schemas-report if set.
+func (m *stateDbManager[T]) reportSchemaComparison() error {
+	report, err := m.comparison.Report()
+	if err != nil {
+		return err
+	}
+	report.Log(m.log)
+	if m.cfg.CompareSchemasReport == "" {
+		return nil
+	}
+	if err = utils.WriteSchemaComparisonReport(m.cfg.CompareSchemasReport, report); err != nil {
+		return err
+	}
+	m.log.Noticef("Schema comparison report: %v", m.cfg.CompareSchemasReport)
+	return nil
+}
+
 func (m *stateDbManager[T]) logDbMode(prefix, impl, variant string) {
 	if m.cfg.DbImpl == "carmen" {
 		m.log.Noticef("%s: %v; Variant: %v, Carmen Schema: %d", prefix, impl, variant, m.cfg.CarmenSchema)
@@ -21,23 +21,37 @@ import (
 
 	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/rpc"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/utils"
 )
 
 // MakeTemporaryArchivePrepper creates an extension for retrieving temporary archive before every txcontext.
-// Archive is assigned to context.Archive. Archive is released after transaction.
-func MakeTemporaryArchivePrepper() executor.Extension[*rpc.RequestAndResults] {
-	return &temporaryArchivePrepper{}
+// Archive is assigned to context.Archive. Archive is released after transaction. If --archive-cache-size
+// is set, recently used archives are kept open and reused by requests for the same block.
+func MakeTemporaryArchivePrepper(cfg *utils.Config) executor.Extension[*rpc.RequestAndResults] {
+	return makeTemporaryArchivePrepper(cfg, logger.NewLogger(cfg.LogLevel, "Archive Prepper"))
+}
+
+func makeTemporaryArchivePrepper(cfg *utils.Config, log logger.Logger) executor.Extension[*rpc.RequestAndResults] {
+	p := &temporaryArchivePrepper{log: log}
+	if cfg.ArchiveCacheSize > 0 {
+		p.cache = newArchiveCache(cfg.ArchiveCacheSize)
+	}
+	return p
 }
 
 type temporaryArchivePrepper struct {
 	extension.NilExtension[*rpc.RequestAndResults]
+	log   logger.Logger
+	cache *archiveCache // nil if archives are not cached
 }
 
 // PreTransaction creates temporary archive that is released after transaction is executed.
 func (r *temporaryArchivePrepper) PreTransaction(state executor.State[*rpc.RequestAndResults], ctx *executor.Context) error {
 	var err error
-	ctx.Archive, err = ctx.State.GetArchiveState(uint64(state.Data.RequestedBlock))
+	ctx.Archive, err = r.getArchive(ctx.State, uint64(state.Data.RequestedBlock))
 	if err != nil {
 		return err
 	}
@@ -52,3 +66,19 @@ func (r *temporaryArchivePrepper) PostTransaction(_ executor.State[*rpc.RequestA
 	}
 	return ctx.Archive.Release()
 }
+
+// PostRun releases all cached archives and reports the hit rate of the cache.
+func (r *temporaryArchivePrepper) PostRun(executor.State[*rpc.RequestAndResults], *executor.Context, error) error {
+	if r.cache == nil {
+		return nil
+	}
+	r.cache.report(r.log)
+	return r.cache.close()
+}
+
+func (r *temporaryArchivePrepper) getArchive(db state.StateDB, block uint64) (state.NonCommittableStateDB, error) {
+	if r.cache == nil {
+		return db.GetArchiveState(block)
+	}
+	return r.cache.get(db, block)
+}
@@ -22,9 +22,12 @@ import (
 	"testing"
 
 	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/rpc"
 	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -32,7 +35,7 @@ func TestTemporaryArchivePrepper_PreTransactionGetsArchiveForRequestedBlock(t *t
 	ctrl := gomock.NewController(t)
 	db := state.NewMockStateDB(ctrl)
 	archive := state.NewMockNonCommittableStateDB(ctrl)
-	ext := MakeTemporaryArchivePrepper()
+	ext := MakeTemporaryArchivePrepper(&utils.Config{})
 
 	gomock.InOrder(
 		db.EXPECT().GetArchiveState(uint64(10)).Return(archive, nil),
@@ -50,7 +53,7 @@ func TestTemporaryArchivePrepper_PreTransactionGetsArchiveForRequestedBlock(t *t
 func TestTemporaryArchivePrepper_PostTransactionReleasesAllocations(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	archive := state.NewMockNonCommittableStateDB(ctrl)
-	ext := MakeTemporaryArchivePrepper()
+	ext := MakeTemporaryArchivePrepper(&utils.Config{})
 
 	gomock.InOrder(
 		archive.EXPECT().EndTransaction(),
@@ -82,7 +85,7 @@ var data = &rpc.RequestAndResults{
 func TestTemporaryArchivePrepper_PreTransactionError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	db := state.NewMockStateDB(ctrl)
-	ext := MakeTemporaryArchivePrepper()
+	ext := MakeTemporaryArchivePrepper(&utils.Config{})
 	mockErr := errors.New("mock error")
 	gomock.InOrder(
 		db.EXPECT().GetArchiveState(uint64(10)).Return(nil, mockErr),
@@ -93,3 +96,29 @@ func TestTemporaryArchivePrepper_PreTransactionError(t *testing.T) {
 	err := ext.PreTransaction(st, ctx)
 	assert.Equal(t, mockErr, err)
 }
+
+func TestTemporaryArchivePrepper_CachedArchiveIsReusedForSameBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+	ext := makeTemporaryArchivePrepper(&utils.Config{ArchiveCacheSize: 2}, log)
+
+	gomock.InOrder(
+		db.EXPECT().GetArchiveState(uint64(10)).Return(archive, nil),
+		archive.EXPECT().BeginTransaction(uint32(0)),
+		archive.EXPECT().EndTransaction(),
+		archive.EXPECT().BeginTransaction(uint32(1)),
+		archive.EXPECT().EndTransaction(),
+		log.EXPECT().Noticef("Archive cache hit rate: %.2f%% (%d hits, %d misses)", 50.0, uint64(1), uint64(1)),
+		archive.EXPECT().Release(),
+	)
+
+	ctx := &executor.Context{State: db}
+	for i := 0; i < 2; i++ {
+		st := executor.State[*rpc.RequestAndResults]{Block: 10, Transaction: i, Data: data}
+		require.NoError(t, ext.PreTransaction(st, ctx))
+		require.NoError(t, ext.PostTransaction(st, ctx))
+	}
+	require.NoError(t, ext.PostRun(executor.State[*rpc.RequestAndResults]{}, ctx, nil))
+}
@@ -27,7 +27,7 @@ import (
 	"github.com/0xsoniclabs/aida/utils"
 )
 
-const substateProgressTrackerReportFormat = "Track: block %d, memory %d, disk %d, interval_blk_rate %.2f, interval_tx_rate %.2f, interval_gas_rate %.2f, overall_blk_rate %.2f, overall_tx_rate %.2f, overall_gas_rate %.2f"
+const substateProgressTrackerReportFormat = "Track: block %d, memory %d, disk %d, interval_blk_rate %.2f, interval_tx_rate %.2f, interval_gas_rate %.2f, overall_blk_rate %.2f, overall_tx_rate %.2f, overall_gas_rate %.2f, recent_blk_rate %.2f, recent_tx_rate %.2f, recent_gas_rate %.2f, eta %v"
 
 // MakeBlockProgressTracker creates a blockProgressTracker that depends on the
 // PostBlock event and is only useful as part of a sequential evaluation.
@@ -40,13 +40,27 @@ func MakeBlockProgressTracker(cfg *utils.Config, reportFrequency int) executor.E
 		reportFrequency = ProgressTrackerDefaultReportFrequency
 	}
 
-	return makeBlockProgressTracker(cfg, reportFrequency, logger.NewLogger(cfg.LogLevel, "ProgressTracker"))
+	log := logger.NewLogger(cfg.LogLevel, "ProgressTracker")
+	t := makeBlockProgressTracker(cfg, reportFrequency, log)
+	if cfg.TrackIo {
+		if utils.IoStatsSupported {
+			t.io = newIoTracker(log)
+		} else {
+			log.Warningf("--%v is not supported on this platform", utils.TrackIoFlag.Name)
+		}
+	}
+	return t
 }
 
 func makeBlockProgressTracker(cfg *utils.Config, reportFrequency int, log logger.Logger) *blockProgressTracker {
+	etaWindow := cfg.TrackerEtaWindow
+	if etaWindow <= 0 {
+		etaWindow = ProgressTrackerDefaultEtaWindow
+	}
 	return &blockProgressTracker{
 		progressTracker:   newProgressTracker[txcontext.TxContext](cfg, reportFrequency, log),
 		lastReportedBlock: int(cfg.First) - (int(cfg.First) % reportFrequency),
+		recent:            progressWindow{size: etaWindow},
 	}
 }
 
@@ -57,6 +71,8 @@ type blockProgressTracker struct {
 	overallInfo       substateProcessInfo
 	lastIntervalInfo  substateProcessInfo
 	lastReportedBlock int
+	recent            progressWindow
+	io                *ioTracker // nil if i/o statistics are not tracked
 }
 
 type substateProcessInfo struct {
@@ -64,6 +80,65 @@ type substateProcessInfo struct {
 	gas             uint64
 }
 
+// progressSample is the progress achieved within a single reporting interval.
+type progressSample struct {
+	blocks   uint64
+	info     substateProcessInfo
+	duration time.Duration
+}
+
+// progressWindow keeps the samples of the last size reporting intervals. Rates computed
+// over the window reflect the recent throughput, unlike the overall rates which are
+// skewed by slow phases at the beginning of the run, e.g. priming.
+type progressWindow struct {
+	samples []progressSample
+	next    int // position of the oldest sample once the window is full
+	size    int
+}
+
+func (w *progressWindow) add(sample progressSample) {
+	if len(w.samples) < w.size {
+		w.samples = append(w.samples, sample)
+		return
+	}
+	w.samples[w.next] = sample
+	w.next = (w.next + 1) % w.size
+}
+
+// rates returns block, transaction and gas rates over all samples of the window.
+func (w *progressWindow) rates() (float64, float64, float64) {
+	var total progressSample
+	for _, sample := range w.samples {
+		total.blocks += sample.blocks
+		total.info.numTransactions += sample.info.numTransactions
+		total.info.gas += sample.info.gas
+		total.duration += sample.duration
+	}
+	return utils.Rate(float64(total.blocks), total.duration),
+		utils.Rate(float64(total.info.numTransactions), total.duration),
+		utils.Rate(float64(total.info.gas), total.duration)
+}
+
+// estimateRemainingTime returns the time needed to process blocks after given block up to
+// the last block of the run with given block rate; 0 if the rate is not known yet.
+func estimateRemainingTime(block int, last uint64, blkRate float64) time.Duration {
+	if blkRate <= 0 || block < 0 || uint64(block) >= last {
+		return 0
+	}
+	seconds := float64(last-uint64(block)) / blkRate
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second)
+}
+
+// PreRun starts the clock and records the initial i/o counters.
+func (t *blockProgressTracker) PreRun(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	if t.io != nil {
+		if err := t.io.begin(ctx.StateDbPath); err != nil {
+			return fmt.Errorf("cannot read i/o counters; %w", err)
+		}
+	}
+	return t.progressTracker.PreRun(state, ctx)
+}
+
 // PostTransaction increments number of transactions and saves gas used in last substate.
 func (t *blockProgressTracker) PostTransaction(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
 	t.lock.Lock()
@@ -85,7 +160,7 @@ func (t *blockProgressTracker) PostBlock(state executor.State[txcontext.TxContex
 		return nil
 	}
 
-	now := time.Now()
+	now := t.now()
 	overall := now.Sub(t.startOfRun)
 	interval := now.Sub(t.startOfLastInterval)
 
@@ -105,21 +180,38 @@ func (t *blockProgressTracker) PostBlock(state executor.State[txcontext.TxContex
 		memory = m.UsedBytes
 	}
 
-	intervalBlkRate := float64(t.reportFrequency) / interval.Seconds()
-	intervalTxRate := float64(info.numTransactions-t.lastIntervalInfo.numTransactions) / interval.Seconds()
-	intervalGasRate := float64(info.gas-t.lastIntervalInfo.gas) / interval.Seconds()
+	intervalBlkRate := utils.Rate(float64(t.reportFrequency), interval)
+	intervalTxRate := utils.Rate(float64(info.numTransactions-t.lastIntervalInfo.numTransactions), interval)
+	intervalGasRate := utils.Rate(float64(info.gas-t.lastIntervalInfo.gas), interval)
+
+	t.recent.add(progressSample{
+		blocks: uint64(boundary - t.lastReportedBlock),
+		info: substateProcessInfo{
+			numTransactions: info.numTransactions - t.lastIntervalInfo.numTransactions,
+			gas:             info.gas - t.lastIntervalInfo.gas,
+		},
+		duration: interval,
+	})
+	recentBlkRate, recentTxRate, recentGasRate := t.recent.rates()
+	eta := estimateRemainingTime(boundary, t.cfg.Last, recentBlkRate)
 	t.lastIntervalInfo = info
 
-	overallBlkRate := float64(state.Block-int(t.cfg.First)) / overall.Seconds()
-	overallTxRate := float64(info.numTransactions) / overall.Seconds()
-	overallGasRate := float64(info.gas) / overall.Seconds()
+	overallBlkRate := utils.Rate(float64(state.Block-int(t.cfg.First)), overall)
+	overallTxRate := utils.Rate(float64(info.numTransactions), overall)
+	overallGasRate := utils.Rate(float64(info.gas), overall)
 
 	t.log.Noticef(
 		substateProgressTrackerReportFormat,
 		boundary, memory, disk,
 		intervalBlkRate, intervalTxRate, intervalGasRate,
 		overallBlkRate, overallTxRate, overallGasRate,
+		recentBlkRate, recentTxRate, recentGasRate, eta,
 	)
+	if t.io != nil {
+		if err = t.io.report(boundary, ctx.StateDbPath, interval); err != nil {
+			return fmt.Errorf("cannot read i/o counters; %w", err)
+		}
+	}
 
 	t.lastReportedBlock = boundary
 	t.startOfLastInterval = now
@@ -84,6 +84,10 @@ func TestSubstateProgressTrackerExtension_LoggingHappens(t *testing.T) {
 			executor.MatchRate(gomock.All(executor.Gt(1), executor.Lt(6)), "blkRate"),
 			executor.MatchRate(gomock.All(executor.Gt(1), executor.Lt(9)), "txRate"),
 			executor.MatchRate(gomock.All(executor.Gt(100), executor.Lt(1000)), "gasRate"),
+			executor.MatchRate(gomock.All(executor.Gt(1), executor.Lt(6)), "blkRate"),
+			gomock.Any(),
+			executor.MatchRate(gomock.All(executor.Gt(100), executor.Lt(1000)), "gasRate"),
+			time.Duration(0),
 		),
 		db.EXPECT().GetMemoryUsage().Return(&state.MemoryUsage{UsedBytes: 4321}),
 		log.EXPECT().Noticef(substateProgressTrackerReportFormat,
@@ -94,6 +98,10 @@ func TestSubstateProgressTrackerExtension_LoggingHappens(t *testing.T) {
 			executor.MatchRate(gomock.All(executor.Gt(1), executor.Lt(6)), "blkRate"),
 			executor.MatchRate(gomock.All(executor.Gt(1), executor.Lt(6)), "txRate"),
 			executor.MatchRate(gomock.All(executor.Gt(100), executor.Lt(1000)), "gasRate"),
+			executor.MatchRate(gomock.All(executor.Gt(1), executor.Lt(6)), "blkRate"),
+			gomock.Any(),
+			executor.MatchRate(gomock.All(executor.Gt(100), executor.Lt(1000)), "gasRate"),
+			time.Duration(0),
 		),
 	)
 
@@ -179,10 +187,87 @@ func TestSubstateProgressTrackerExtension_FirstLoggingIsIgnored(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSubstateProgressTrackerExtension_EtaIsBasedOnRecentThroughput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	db := state.NewMockStateDB(ctrl)
+
+	cfg := &utils.Config{First: 0, Last: 1000, TrackerEtaWindow: 2}
+	ext := makeBlockProgressTracker(cfg, 100, log)
+
+	clock := time.Unix(0, 0)
+	ext.now = func() time.Time { return clock }
+
+	var etas []time.Duration
+	var overallBlkRates, recentBlkRates []float64
+	db.EXPECT().GetMemoryUsage().Return(nil).AnyTimes()
+	log.EXPECT().Noticef(substateProgressTrackerReportFormat, gomock.Any()).Do(func(_ string, args ...any) {
+		overallBlkRates = append(overallBlkRates, args[6].(float64))
+		recentBlkRates = append(recentBlkRates, args[9].(float64))
+		etas = append(etas, args[12].(time.Duration))
+	}).Times(4)
+
+	ctx := &executor.Context{
+		State:           db,
+		StateDbPath:     t.TempDir(),
+		ExecutionResult: substatecontext.NewReceipt(&substate.Result{GasUsed: 100}),
+	}
+	assert.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+
+	// the first interval is slowed down by priming, the following ones run at 10 blocks per second
+	for i, duration := range []time.Duration{1000 * time.Second, 10 * time.Second, 10 * time.Second, 10 * time.Second} {
+		clock = clock.Add(duration)
+		assert.NoError(t, ext.PostTransaction(executor.State[txcontext.TxContext]{}, ctx))
+		assert.NoError(t, ext.PostBlock(executor.State[txcontext.TxContext]{Block: 100 * (i + 1)}, ctx))
+	}
+
+	want := []time.Duration{9000 * time.Second, 4040 * time.Second, 70 * time.Second, 60 * time.Second}
+	if assert.Len(t, etas, len(want)) {
+		for i := range want {
+			assert.InDelta(t, want[i].Seconds(), etas[i].Seconds(), 1, "eta of report %d", i)
+		}
+	}
+	assert.InDelta(t, 10, recentBlkRates[3], 0.01)
+	assert.InDelta(t, 400.0/1030, overallBlkRates[3], 0.01)
+}
+
+func TestEstimateRemainingTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		block   int
+		last    uint64
+		blkRate float64
+		want    time.Duration
+	}{
+		{"Remaining", 100, 200, 10, 10 * time.Second},
+		{"Rounded", 100, 201, 3, 34 * time.Second},
+		{"UnknownRate", 100, 200, 0, 0},
+		{"Finished", 200, 200, 10, 0},
+		{"BeyondLast", 300, 200, 10, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, estimateRemainingTime(test.block, test.last, test.blkRate))
+		})
+	}
+}
+
+func TestProgressWindow_KeepsOnlyRecentSamples(t *testing.T) {
+	w := progressWindow{size: 2}
+	w.add(progressSample{blocks: 100, info: substateProcessInfo{numTransactions: 1000, gas: 10_000}, duration: 100 * time.Second})
+	w.add(progressSample{blocks: 10, info: substateProcessInfo{numTransactions: 10, gas: 100}, duration: time.Second})
+	w.add(progressSample{blocks: 30, info: substateProcessInfo{numTransactions: 50, gas: 300}, duration: time.Second})
+
+	blkRate, txRate, gasRate := w.rates()
+	assert.Equal(t, 20.0, blkRate)
+	assert.Equal(t, 30.0, txRate)
+	assert.Equal(t, 200.0, gasRate)
+}
+
 func Test_LoggingFormatMatchesRubyScript(t *testing.T) {
 	// NOTE: keep this in sync with the pattern used by scripts/run_throughput_eval.rb
-	pattern := `Track: block \d+, memory \d+, disk \d+, interval_blk_rate \d+.\d*, interval_tx_rate \d+.\d*, interval_gas_rate \d+.\d*, overall_blk_rate \d+.\d*, overall_tx_rate \d+.\d*, overall_gas_rate \d+.\d*`
-	example := fmt.Sprintf(substateProgressTrackerReportFormat, 1, 2, 3, 4.5, 6.7, 8.9, 0.1, 2.3, 4.5)
+	pattern := `Track: block \d+, memory \d+, disk \d+, interval_blk_rate \d+.\d*, interval_tx_rate \d+.\d*, interval_gas_rate \d+.\d*, overall_blk_rate \d+.\d*, overall_tx_rate \d+.\d*, overall_gas_rate \d+.\d*, recent_blk_rate \d+.\d*, recent_tx_rate \d+.\d*, recent_gas_rate \d+.\d*, eta \w+`
+	example := fmt.Sprintf(substateProgressTrackerReportFormat, 1, 2, 3, 4.5, 6.7, 8.9, 0.1, 2.3, 4.5, 6.7, 8.9, 0.1, 90*time.Minute)
 	if match, err := regexp.Match(pattern, []byte(example)); !match || err != nil {
 		t.Errorf("Logging format '%v' does not match required format '%v'; err %v", example, pattern, err)
 	}
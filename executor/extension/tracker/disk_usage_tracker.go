@@ -0,0 +1,101 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package tracker
+
+import (
+	"fmt"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+const diskUsageTrackerReportFormat = "Track-Disk: block %d, state_db_size %.2f GiB, tmp_free %.2f GiB"
+
+const bytesPerGib = 1 << 30
+
+// MakeDiskUsageTracker creates an extension logging the size of the StateDb directory and the
+// free space of the temporary directory every reportFrequency blocks. If cfg.MinFreeDiskGib is
+// set, the run is aborted with an error once the free space drops below it. The error passes
+// through the executor like any other, hence the StateDb is closed and cleaned up as usual
+// instead of Carmen failing on a full disk much later.
+func MakeDiskUsageTracker[T any](cfg *utils.Config, reportFrequency int) executor.Extension[T] {
+	if !cfg.TrackProgress && cfg.MinFreeDiskGib <= 0 {
+		return extension.NilExtension[T]{}
+	}
+
+	if reportFrequency == 0 {
+		reportFrequency = ProgressTrackerDefaultReportFrequency
+	}
+
+	return makeDiskUsageTracker[T](cfg, reportFrequency, logger.NewLogger(cfg.LogLevel, "Disk-Usage-Tracker"))
+}
+
+func makeDiskUsageTracker[T any](cfg *utils.Config, reportFrequency int, log logger.Logger) *diskUsageTracker[T] {
+	return &diskUsageTracker[T]{
+		cfg:               cfg,
+		log:               log,
+		reportFrequency:   reportFrequency,
+		lastReportedBlock: int(cfg.First) - (int(cfg.First) % reportFrequency),
+		directorySize:     utils.GetDirectorySize,
+		freeSpace:         utils.GetFreeSpace,
+	}
+}
+
+// diskUsageTracker samples the disk usage at the same block boundaries as the blockProgressTracker.
+type diskUsageTracker[T any] struct {
+	extension.NilExtension[T]
+	cfg               *utils.Config
+	log               logger.Logger
+	reportFrequency   int
+	lastReportedBlock int
+	directorySize     func(path string) (int64, error)
+	freeSpace         func(path string) (int64, error)
+}
+
+// PostBlock samples and logs the disk usage once a report boundary is crossed and aborts
+// the run if the free space of the temporary directory falls below the threshold.
+func (t *diskUsageTracker[T]) PostBlock(state executor.State[T], ctx *executor.Context) error {
+	if state.Block-t.lastReportedBlock < t.reportFrequency {
+		return nil
+	}
+	boundary := state.Block - (state.Block % t.reportFrequency)
+	t.lastReportedBlock = boundary
+
+	size, err := t.directorySize(ctx.StateDbPath)
+	if err != nil {
+		return fmt.Errorf("cannot get size of state-db (%v); %w", ctx.StateDbPath, err)
+	}
+	free, err := t.freeSpace(t.cfg.DbTmp)
+	if err != nil {
+		return fmt.Errorf("cannot get free space of %v; %w", t.cfg.DbTmp, err)
+	}
+
+	freeGib := toGib(free)
+	t.log.Noticef(diskUsageTrackerReportFormat, boundary, toGib(size), freeGib)
+
+	if t.cfg.MinFreeDiskGib > 0 && freeGib < t.cfg.MinFreeDiskGib {
+		return fmt.Errorf("free space of %v dropped to %.2f GiB at block %d, which is below --%v=%v; aborting run",
+			t.cfg.DbTmp, freeGib, state.Block, utils.MinFreeDiskGibFlag.Name, t.cfg.MinFreeDiskGib)
+	}
+	return nil
+}
+
+func toGib(bytes int64) float64 {
+	return float64(bytes) / bytesPerGib
+}
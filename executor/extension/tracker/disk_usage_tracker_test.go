@@ -0,0 +1,136 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package tracker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDiskUsageTracker_NoTrackerIsCreatedIfDisabled(t *testing.T) {
+	cfg := &utils.Config{}
+	ext := MakeDiskUsageTracker[txcontext.TxContext](cfg, 10)
+	if _, ok := ext.(extension.NilExtension[txcontext.TxContext]); !ok {
+		t.Errorf("disk usage tracker is enabled even though not requested")
+	}
+}
+
+func TestDiskUsageTracker_IsCreatedByThresholdOrTrackProgress(t *testing.T) {
+	for name, cfg := range map[string]*utils.Config{
+		"threshold":     {MinFreeDiskGib: 1},
+		"trackProgress": {TrackProgress: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ext := MakeDiskUsageTracker[txcontext.TxContext](cfg, 0)
+			tracker, ok := ext.(*diskUsageTracker[txcontext.TxContext])
+			require.True(t, ok, "disk usage tracker is not created")
+			assert.Equal(t, ProgressTrackerDefaultReportFrequency, tracker.reportFrequency)
+		})
+	}
+}
+
+func TestDiskUsageTracker_ReportsOncePerInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+
+	cfg := &utils.Config{First: 5, DbTmp: "/tmp-dir", TrackProgress: true}
+	tracker := makeDiskUsageTracker[txcontext.TxContext](cfg, 10, log)
+	sizes := 0
+	tracker.directorySize = func(path string) (int64, error) {
+		assert.Equal(t, "/db", path)
+		sizes++
+		return int64(sizes) * bytesPerGib, nil
+	}
+	tracker.freeSpace = func(path string) (int64, error) {
+		assert.Equal(t, "/tmp-dir", path)
+		return 100 * bytesPerGib, nil
+	}
+
+	gomock.InOrder(
+		log.EXPECT().Noticef(diskUsageTrackerReportFormat, 10, 1.0, 100.0),
+		log.EXPECT().Noticef(diskUsageTrackerReportFormat, 20, 2.0, 100.0),
+		log.EXPECT().Noticef(diskUsageTrackerReportFormat, 40, 3.0, 100.0),
+	)
+
+	ctx := &executor.Context{StateDbPath: "/db"}
+	for _, block := range []int{5, 9, 10, 11, 19, 23, 29, 41, 45} {
+		require.NoError(t, tracker.PostBlock(executor.State[txcontext.TxContext]{Block: block}, ctx))
+	}
+	assert.Equal(t, 3, sizes)
+}
+
+func TestDiskUsageTracker_AbortsOnceFreeSpaceIsBelowThreshold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+
+	cfg := &utils.Config{DbTmp: "/tmp-dir", MinFreeDiskGib: 10}
+	tracker := makeDiskUsageTracker[txcontext.TxContext](cfg, 10, log)
+	free := []int64{12 * bytesPerGib, 10 * bytesPerGib, 9 * bytesPerGib}
+	tracker.directorySize = func(string) (int64, error) {
+		return 0, nil
+	}
+	tracker.freeSpace = func(string) (int64, error) {
+		res := free[0]
+		free = free[1:]
+		return res, nil
+	}
+
+	gomock.InOrder(
+		log.EXPECT().Noticef(diskUsageTrackerReportFormat, 10, 0.0, 12.0),
+		log.EXPECT().Noticef(diskUsageTrackerReportFormat, 20, 0.0, 10.0),
+		log.EXPECT().Noticef(diskUsageTrackerReportFormat, 30, 0.0, 9.0),
+	)
+
+	ctx := &executor.Context{StateDbPath: "/db"}
+	require.NoError(t, tracker.PostBlock(executor.State[txcontext.TxContext]{Block: 10}, ctx))
+	require.NoError(t, tracker.PostBlock(executor.State[txcontext.TxContext]{Block: 20}, ctx))
+	err := tracker.PostBlock(executor.State[txcontext.TxContext]{Block: 30}, ctx)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "free space of /tmp-dir dropped to 9.00 GiB at block 30")
+	assert.ErrorContains(t, err, utils.MinFreeDiskGibFlag.Name)
+}
+
+func TestDiskUsageTracker_ProbeErrorsAreReturned(t *testing.T) {
+	injectedErr := errors.New("injected error")
+	ctx := &executor.Context{StateDbPath: "/db"}
+	state := executor.State[txcontext.TxContext]{Block: 10}
+	cfg := &utils.Config{MinFreeDiskGib: 1}
+
+	tracker := makeDiskUsageTracker[txcontext.TxContext](cfg, 10, logger.NewMockLogger(gomock.NewController(t)))
+	tracker.directorySize = func(string) (int64, error) {
+		return 0, injectedErr
+	}
+	require.ErrorIs(t, tracker.PostBlock(state, ctx), injectedErr)
+
+	tracker = makeDiskUsageTracker[txcontext.TxContext](cfg, 10, logger.NewMockLogger(gomock.NewController(t)))
+	tracker.directorySize = func(string) (int64, error) {
+		return 0, nil
+	}
+	tracker.freeSpace = func(string) (int64, error) {
+		return 0, injectedErr
+	}
+	require.ErrorIs(t, tracker.PostBlock(state, ctx), injectedErr)
+}
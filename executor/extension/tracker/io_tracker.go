@@ -0,0 +1,103 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package tracker
+
+import (
+	"expvar"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+const ioTrackerReportFormat = "Track-IO: block %d, read_rate %.2f MB/s, write_rate %.2f MB/s, device_read_iops %.2f, device_write_iops %.2f, total_read %.2f MB, total_written %.2f MB"
+
+// ioMetrics publishes the last I/O report on /debug/vars of the diagnostic server.
+var ioMetrics = expvar.NewMap("io")
+
+// ioTracker samples I/O counters of the process and of the device holding the state DB
+// at each progress report, so that throughput changes can be correlated with disk activity.
+type ioTracker struct {
+	log           logger.Logger
+	readCounters  func(dir string) (utils.IoCounters, error)
+	start         utils.IoCounters
+	last          utils.IoCounters
+	noDeviceTrack bool // device unavailability has been reported
+}
+
+func newIoTracker(log logger.Logger) *ioTracker {
+	return &ioTracker{
+		log:          log,
+		readCounters: utils.ReadIoCounters,
+	}
+}
+
+// begin records the counters at the start of the run.
+func (t *ioTracker) begin(dir string) error {
+	counters, err := t.readCounters(dir)
+	if err != nil {
+		return err
+	}
+	t.start = counters
+	t.last = counters
+	return nil
+}
+
+// report logs and publishes the I/O rates of the last interval and the totals of the run.
+func (t *ioTracker) report(block int, dir string, interval time.Duration) error {
+	counters, err := t.readCounters(dir)
+	if err != nil {
+		return err
+	}
+	delta := counters.Sub(t.last)
+	total := counters.Sub(t.start)
+	t.last = counters
+
+	if !delta.HasDevice && !t.noDeviceTrack {
+		t.noDeviceTrack = true
+		t.log.Warningf("Device of %v does not provide i/o counters; IOPS are not reported", dir)
+	}
+
+	values := []struct {
+		name  string
+		value float64
+	}{
+		{"read_rate_mb", utils.Rate(toMB(delta.ReadBytes), interval)},
+		{"write_rate_mb", utils.Rate(toMB(delta.WrittenBytes), interval)},
+		{"device_read_iops", utils.Rate(float64(delta.DeviceReads), interval)},
+		{"device_write_iops", utils.Rate(float64(delta.DeviceWrites), interval)},
+		{"total_read_mb", toMB(total.ReadBytes)},
+		{"total_written_mb", toMB(total.WrittenBytes)},
+	}
+	args := []any{block}
+	for _, v := range values {
+		metric := new(expvar.Float)
+		metric.Set(v.value)
+		ioMetrics.Set(v.name, metric)
+		args = append(args, v.value)
+	}
+	blockMetric := new(expvar.Int)
+	blockMetric.Set(int64(block))
+	ioMetrics.Set("block", blockMetric)
+
+	t.log.Noticef(ioTrackerReportFormat, args...)
+	return nil
+}
+
+func toMB(bytes uint64) float64 {
+	return float64(bytes) / 1_000_000
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package tracker
+
+import (
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIoTracker_ReportsIntervalRatesAndTotals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+
+	samples := []utils.IoCounters{
+		{ReadBytes: 1_000_000, WrittenBytes: 2_000_000, HasDevice: true, DeviceReads: 10, DeviceWrites: 20},
+		{ReadBytes: 3_000_000, WrittenBytes: 10_000_000, HasDevice: true, DeviceReads: 30, DeviceWrites: 60},
+		{ReadBytes: 4_000_000, WrittenBytes: 12_000_000, HasDevice: true, DeviceReads: 40, DeviceWrites: 80},
+	}
+	tracker := newIoTracker(log)
+	tracker.readCounters = func(dir string) (utils.IoCounters, error) {
+		assert.Equal(t, "/db", dir)
+		sample := samples[0]
+		samples = samples[1:]
+		return sample, nil
+	}
+
+	gomock.InOrder(
+		log.EXPECT().Noticef(ioTrackerReportFormat, 100, 1.0, 4.0, 10.0, 20.0, 2.0, 8.0),
+		log.EXPECT().Noticef(ioTrackerReportFormat, 200, 0.5, 1.0, 5.0, 10.0, 3.0, 10.0),
+	)
+
+	require.NoError(t, tracker.begin("/db"))
+	require.NoError(t, tracker.report(100, "/db", 2*time.Second))
+	require.NoError(t, tracker.report(200, "/db", 2*time.Second))
+
+	assert.Equal(t, "200", ioMetrics.Get("block").String())
+	assert.Equal(t, "10", ioMetrics.Get("total_written_mb").String())
+	assert.NotNil(t, expvar.Get("io"))
+}
+
+func TestIoTracker_WarnsOnceAboutMissingDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+
+	tracker := newIoTracker(log)
+	tracker.readCounters = func(string) (utils.IoCounters, error) {
+		return utils.IoCounters{}, nil
+	}
+
+	log.EXPECT().Warningf(gomock.Any(), "/db")
+	log.EXPECT().Noticef(ioTrackerReportFormat, gomock.Any()).Times(2)
+
+	require.NoError(t, tracker.begin("/db"))
+	require.NoError(t, tracker.report(100, "/db", time.Second))
+	require.NoError(t, tracker.report(200, "/db", time.Second))
+}
+
+func TestIoTracker_ReadErrorIsReturned(t *testing.T) {
+	injectedErr := errors.New("injected error")
+	tracker := newIoTracker(logger.NewMockLogger(gomock.NewController(t)))
+	tracker.readCounters = func(string) (utils.IoCounters, error) {
+		return utils.IoCounters{}, injectedErr
+	}
+	require.ErrorIs(t, tracker.begin("/db"), injectedErr)
+	require.ErrorIs(t, tracker.report(100, "/db", time.Second), injectedErr)
+}
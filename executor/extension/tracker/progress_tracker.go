@@ -28,6 +28,7 @@ import (
 
 const (
 	ProgressTrackerDefaultReportFrequency = 100_000 // in blocks
+	ProgressTrackerDefaultEtaWindow       = 10      // in reports
 )
 
 func newProgressTracker[T any](cfg *utils.Config, reportFrequency int, log logger.Logger) *progressTracker[T] {
@@ -35,6 +36,7 @@ func newProgressTracker[T any](cfg *utils.Config, reportFrequency int, log logge
 		cfg:             cfg,
 		log:             log,
 		reportFrequency: reportFrequency,
+		now:             time.Now,
 	}
 }
 
@@ -45,12 +47,13 @@ type progressTracker[T any] struct {
 	reportFrequency     int
 	startOfRun          time.Time
 	startOfLastInterval time.Time
+	now                 func() time.Time
 
 	lock sync.Mutex
 }
 
 func (t *progressTracker[T]) PreRun(executor.State[T], *executor.Context) error {
-	now := time.Now()
+	now := t.now()
 	t.startOfRun = now
 	t.startOfLastInterval = now
 	return nil
@@ -17,8 +17,6 @@
 package tracker
 
 import (
-	"time"
-
 	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/executor/extension"
 	"github.com/0xsoniclabs/aida/logger"
@@ -80,18 +78,18 @@ func (t *requestProgressTracker) PostTransaction(state executor.State[*rpc.Reque
 
 	boundary := overallCount - (overallCount % uint64(t.reportFrequency))
 
-	now := time.Now()
+	now := t.now()
 	overall := now.Sub(t.startOfRun)
 	interval := now.Sub(t.startOfLastInterval)
 
 	overallGas := overallInfo.gas
 	intervalGas := t.lastIntervalInfo.gas
 
-	intervalReqRate := float64(t.reportFrequency) / interval.Seconds()
-	intervalGasRate := float64(overallGas-intervalGas) / interval.Seconds()
+	intervalReqRate := utils.Rate(float64(t.reportFrequency), interval)
+	intervalGasRate := utils.Rate(float64(overallGas-intervalGas), interval)
 
-	overallReqRate := float64(overallCount) / overall.Seconds()
-	overallGasRate := float64(overallGas) / overall.Seconds()
+	overallReqRate := utils.Rate(float64(overallCount), overall)
+	overallGasRate := utils.Rate(float64(overallGas), overall)
 
 	t.log.Noticef(
 		rpcProgressTrackerReportFormat, boundary,
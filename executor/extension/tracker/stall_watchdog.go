@@ -0,0 +1,226 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package tracker
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+const (
+	StallActionLog   = "log"   // dump diagnostics and continue watching
+	StallActionAbort = "abort" // dump diagnostics and terminate the process
+)
+
+// LatencyReporter is implemented by extensions able to describe recent StateDb operation latencies.
+type LatencyReporter interface {
+	ReportLatencies() string
+}
+
+// MakeStallWatchdog creates an extension dumping diagnostics if no transaction completes
+// within cfg.StallTimeout. The watchdog is only armed once the first block starts, hence
+// long setup phases like priming are not reported. It should be registered close to the end
+// of the extension list so that it is disarmed before other extensions start cleaning up.
+func MakeStallWatchdog[T any](cfg *utils.Config, latencies ...LatencyReporter) (executor.Extension[T], error) {
+	if cfg.StallTimeout <= 0 {
+		return extension.NilExtension[T]{}, nil
+	}
+	switch cfg.StallAction {
+	case StallActionLog, StallActionAbort:
+	default:
+		return nil, fmt.Errorf("unknown stall action %q; supported actions are %q and %q", cfg.StallAction, StallActionLog, StallActionAbort)
+	}
+	return makeStallWatchdog[T](cfg, logger.NewLogger(cfg.LogLevel, "Stall-Watchdog"), os.Exit, latencies...), nil
+}
+
+func makeStallWatchdog[T any](cfg *utils.Config, log logger.Logger, exit func(int), latencies ...LatencyReporter) *stallWatchdog[T] {
+	return &stallWatchdog[T]{
+		cfg:       cfg,
+		log:       log,
+		exit:      exit,
+		latencies: latencies,
+		inFlight:  make(map[txPosition]time.Time),
+		stop:      make(chan struct{}),
+	}
+}
+
+// txPosition identifies a transaction currently being processed by one of the workers.
+type txPosition struct {
+	block, tx int
+}
+
+type stallWatchdog[T any] struct {
+	extension.NilExtension[T]
+	cfg       *utils.Config
+	log       logger.Logger
+	exit      func(int)
+	latencies []LatencyReporter
+
+	lock         sync.Mutex
+	armed        bool
+	stopped      bool
+	lastProgress time.Time
+	inFlight     map[txPosition]time.Time
+	errorInput   chan error
+	stop         chan struct{}
+	wg           sync.WaitGroup
+}
+
+func (w *stallWatchdog[T]) PreRun(_ executor.State[T], ctx *executor.Context) error {
+	w.errorInput = ctx.ErrorInput
+	w.wg.Add(1)
+	go w.watch()
+	return nil
+}
+
+// PreBlock arms the watchdog on the first block, all setup phases are finished by then.
+func (w *stallWatchdog[T]) PreBlock(executor.State[T], *executor.Context) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if !w.armed {
+		w.armed = true
+		w.lastProgress = time.Now()
+	}
+	return nil
+}
+
+func (w *stallWatchdog[T]) PreTransaction(state executor.State[T], _ *executor.Context) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.inFlight[txPosition{state.Block, state.Transaction}] = time.Now()
+	return nil
+}
+
+func (w *stallWatchdog[T]) PostTransaction(state executor.State[T], _ *executor.Context) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	delete(w.inFlight, txPosition{state.Block, state.Transaction})
+	w.lastProgress = time.Now()
+	return nil
+}
+
+// PostBlock counts as progress so that blocks without transactions are not reported.
+func (w *stallWatchdog[T]) PostBlock(executor.State[T], *executor.Context) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.lastProgress = time.Now()
+	return nil
+}
+
+// PostRun disarms the watchdog, closing the StateDb may legitimately take long.
+func (w *stallWatchdog[T]) PostRun(executor.State[T], *executor.Context, error) error {
+	w.lock.Lock()
+	w.stopped = true
+	w.lock.Unlock()
+	close(w.stop)
+	w.wg.Wait()
+	return nil
+}
+
+func (w *stallWatchdog[T]) watch() {
+	defer w.wg.Done()
+
+	// check more often than the timeout so that a stall is reported close to its deadline
+	ticker := time.NewTicker(w.cfg.StallTimeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case now := <-ticker.C:
+			w.check(now)
+		}
+	}
+}
+
+// check dumps diagnostics if the run did not make any progress within the timeout.
+func (w *stallWatchdog[T]) check(now time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if !w.armed || w.stopped {
+		return
+	}
+	stalled := now.Sub(w.lastProgress)
+	if stalled < w.cfg.StallTimeout {
+		return
+	}
+
+	dump := w.diagnostics(stalled)
+	if w.errorInput != nil {
+		w.errorInput <- dump
+	} else {
+		w.log.Error(dump)
+	}
+
+	if w.cfg.StallAction == StallActionAbort {
+		w.log.Criticalf("Aborting stalled run after %v without progress", stalled.Round(time.Second))
+		w.exit(1)
+		return
+	}
+	// do not repeat the same dump on every tick
+	w.lastProgress = now
+}
+
+// diagnostics collects the diagnostic bundle of a stalled run. It is called with the lock held.
+func (w *stallWatchdog[T]) diagnostics(stalled time.Duration) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "no transaction completed for %v\n", stalled.Round(time.Second))
+
+	positions := make([]txPosition, 0, len(w.inFlight))
+	for pos := range w.inFlight {
+		positions = append(positions, pos)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].block != positions[j].block {
+			return positions[i].block < positions[j].block
+		}
+		return positions[i].tx < positions[j].tx
+	})
+	fmt.Fprintf(&buf, "\n-- transactions in flight: %d --\n", len(positions))
+	for _, pos := range positions {
+		fmt.Fprintf(&buf, "block %d, tx %d, running for %v\n", pos.block, pos.tx, time.Since(w.inFlight[pos]).Round(time.Second))
+	}
+
+	for _, l := range w.latencies {
+		fmt.Fprintf(&buf, "\n-- recent StateDb operation latencies --\n%v", l.ReportLatencies())
+	}
+
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	fmt.Fprintf(&buf, "\n-- memory --\nalloc %d, heap in use %d, sys %d, num gc %d, goroutines %d\n",
+		m.Alloc, m.HeapInuse, m.Sys, m.NumGC, runtime.NumGoroutine())
+
+	fmt.Fprintf(&buf, "\n-- goroutines --\n")
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		fmt.Fprintf(&buf, "cannot dump goroutines; %v\n", err)
+	}
+
+	return fmt.Errorf("stalled run detected; %v", buf.String())
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package tracker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type testLatencyReporter string
+
+func (r testLatencyReporter) ReportLatencies() string {
+	return string(r)
+}
+
+func TestStallWatchdog_DisabledByDefault(t *testing.T) {
+	ext, err := MakeStallWatchdog[any](&utils.Config{})
+	require.NoError(t, err)
+	_, ok := ext.(extension.NilExtension[any])
+	assert.True(t, ok)
+}
+
+func TestStallWatchdog_UnknownActionIsRejected(t *testing.T) {
+	_, err := MakeStallWatchdog[any](&utils.Config{StallTimeout: time.Minute, StallAction: "panic"})
+	require.ErrorContains(t, err, "unknown stall action")
+}
+
+func TestStallWatchdog_NotArmedBeforeFirstBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{StallTimeout: time.Minute, StallAction: StallActionLog}
+
+	w := makeStallWatchdog[any](cfg, log, func(int) { t.Fatal("unexpected exit") })
+	// priming may take arbitrarily long, no log is expected
+	w.check(time.Now().Add(time.Hour))
+}
+
+func TestStallWatchdog_StallIsDumpedIntoErrorLog(t *testing.T) {
+	cfg := &utils.Config{StallTimeout: time.Minute, StallAction: StallActionLog}
+	errorInput := make(chan error, 1)
+
+	w := makeStallWatchdog[any](cfg, logger.NewLogger("critical", "test"), func(int) { t.Fatal("unexpected exit") }, testLatencyReporter("GetState: n 1"))
+	w.errorInput = errorInput
+	require.NoError(t, w.PreBlock(executor.State[any]{Block: 5}, nil))
+	require.NoError(t, w.PreTransaction(executor.State[any]{Block: 5, Transaction: 2}, nil))
+
+	w.check(time.Now().Add(2 * time.Minute))
+
+	require.Len(t, errorInput, 1)
+	dump := (<-errorInput).Error()
+	assert.Contains(t, dump, "stalled run detected")
+	assert.Contains(t, dump, "block 5, tx 2")
+	assert.Contains(t, dump, "GetState: n 1")
+	assert.Contains(t, dump, "goroutines")
+
+	// the dump is not repeated until another timeout passes
+	w.check(time.Now().Add(2 * time.Minute))
+	assert.Len(t, errorInput, 0)
+}
+
+func TestStallWatchdog_ProgressPreventsDump(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{StallTimeout: time.Minute, StallAction: StallActionLog}
+
+	w := makeStallWatchdog[any](cfg, log, func(int) { t.Fatal("unexpected exit") })
+	require.NoError(t, w.PreBlock(executor.State[any]{Block: 5}, nil))
+	require.NoError(t, w.PreTransaction(executor.State[any]{Block: 5}, nil))
+	require.NoError(t, w.PostTransaction(executor.State[any]{Block: 5}, nil))
+
+	w.check(time.Now().Add(30 * time.Second))
+	assert.Empty(t, w.inFlight)
+}
+
+func TestStallWatchdog_AbortExitsProcess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{StallTimeout: time.Minute, StallAction: StallActionAbort}
+
+	exitCode := -1
+	w := makeStallWatchdog[any](cfg, log, func(code int) { exitCode = code })
+	require.NoError(t, w.PreBlock(executor.State[any]{Block: 5}, nil))
+
+	log.EXPECT().Error(gomock.Any())
+	log.EXPECT().Criticalf(gomock.Any(), gomock.Any())
+	w.check(time.Now().Add(2 * time.Minute))
+	assert.Equal(t, 1, exitCode)
+}
+
+func TestStallWatchdog_PostRunStopsWatching(t *testing.T) {
+	cfg := &utils.Config{StallTimeout: time.Millisecond, StallAction: StallActionLog}
+	errorInput := make(chan error, 100)
+
+	w := makeStallWatchdog[any](cfg, logger.NewLogger("critical", "test"), func(int) { t.Fatal("unexpected exit") })
+	require.NoError(t, w.PreRun(executor.State[any]{}, &executor.Context{ErrorInput: errorInput}))
+	require.NoError(t, w.PostRun(executor.State[any]{}, nil, errors.New("ignored")))
+
+	w.check(time.Now().Add(time.Hour))
+	assert.Len(t, errorInput, 0)
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// MakeBalanceAccountingValidator creates an extension which checks that the net balance
+// change of all accounts touched in a block equals the fees burned by its transactions.
+// Transactions only move funds between accounts, so any other difference means that
+// money was created or destroyed by the execution. It depends on the PostBlock event
+// and is only useful as part of a sequential evaluation.
+func MakeBalanceAccountingValidator(cfg *utils.Config) executor.Extension[txcontext.TxContext] {
+	if !cfg.ValidateAccounting {
+		return extension.NilExtension[txcontext.TxContext]{}
+	}
+
+	log := logger.NewLogger(cfg.LogLevel, "Balance-Accounting-Validator")
+
+	return makeBalanceAccountingValidator(cfg, log)
+}
+
+func makeBalanceAccountingValidator(cfg *utils.Config, log logger.Logger) *balanceAccountingValidator {
+	return &balanceAccountingValidator{
+		stateDbValidator: makeStateDbValidator(cfg, log, ValidateTxTarget{}),
+		delta:            new(big.Int),
+		expected:         new(big.Int),
+	}
+}
+
+type balanceAccountingValidator struct {
+	*stateDbValidator
+	pre           map[common.Address]*big.Int // balances of touched accounts before the current transaction
+	delta         *big.Int                    // net balance change of the current block
+	expected      *big.Int                    // expected net balance change of the current block
+	skippedPseudo bool                        // true if a pseudo transaction was skipped in the run
+}
+
+// PreRun informs the user that the accounting check is enabled.
+func (v *balanceAccountingValidator) PreRun(executor.State[txcontext.TxContext], *executor.Context) error {
+	v.log.Warning("Balance accounting validation is enabled, this may slow down the block processing.")
+	return nil
+}
+
+// PreBlock resets the accounting of the block.
+func (v *balanceAccountingValidator) PreBlock(executor.State[txcontext.TxContext], *executor.Context) error {
+	v.delta.SetUint64(0)
+	v.expected.SetUint64(0)
+	return nil
+}
+
+// PreTransaction records balances of all accounts the transaction may touch.
+func (v *balanceAccountingValidator) PreTransaction(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	v.pre = nil
+	if state.Transaction >= utils.PseudoTx {
+		// pseudo transactions mint rewards which cannot be derived from recorded data
+		if !v.skippedPseudo {
+			v.skippedPseudo = true
+			v.log.Warningf("Block %v contains pseudo transactions; issuance data is not available, their balance changes are not accounted", state.Block)
+		}
+		return nil
+	}
+
+	v.pre = make(map[common.Address]*big.Int)
+	for _, addr := range touchedAccounts(state.Data) {
+		v.pre[addr] = ctx.State.GetBalance(addr).ToBig()
+	}
+	return nil
+}
+
+// PostTransaction adds the balance changes of the transaction and its burned fees to the block accounting.
+func (v *balanceAccountingValidator) PostTransaction(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	if v.pre == nil {
+		return nil
+	}
+
+	for addr, before := range v.pre {
+		v.delta.Add(v.delta, ctx.State.GetBalance(addr).ToBig())
+		v.delta.Sub(v.delta, before)
+	}
+
+	if ctx.ExecutionResult != nil {
+		v.expected.Sub(v.expected, burnedFees(state.Data, ctx.ExecutionResult.GetGasUsed()))
+	}
+	return nil
+}
+
+// PostBlock compares the net balance change of the block with the burned fees.
+func (v *balanceAccountingValidator) PostBlock(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	if v.delta.Cmp(v.expected) == 0 {
+		return nil
+	}
+
+	discrepancy := new(big.Int).Sub(v.delta, v.expected)
+	err := fmt.Errorf("balance accounting error at block %v: net balance change %v, expected %v (burned fees); discrepancy %v",
+		state.Block, v.delta, v.expected, discrepancy)
+	if v.isErrFatal(err, ctx.ErrorInput) {
+		return err
+	}
+	return nil
+}
+
+// touchedAccounts returns all accounts whose balance may be changed by the transaction.
+// Besides the recorded world states these are the sender, the recipient and the coinbase
+// receiving tips.
+func touchedAccounts(data txcontext.TxContext) []common.Address {
+	seen := make(map[common.Address]struct{})
+	var res []common.Address
+	add := func(addr common.Address) {
+		if _, found := seen[addr]; !found {
+			seen[addr] = struct{}{}
+			res = append(res, addr)
+		}
+	}
+
+	for _, ws := range []txcontext.WorldState{data.GetInputState(), data.GetOutputState()} {
+		if ws == nil {
+			continue
+		}
+		ws.ForEachAccount(func(addr common.Address, _ txcontext.Account) {
+			add(addr)
+		})
+	}
+	if env := data.GetBlockEnvironment(); env != nil {
+		add(env.GetCoinbase())
+	}
+	if msg := data.GetMessage(); msg != nil {
+		add(msg.From)
+		if msg.To != nil {
+			add(*msg.To)
+		}
+	}
+	return res
+}
+
+// burnedFees returns the amount of funds burned by the transaction. Only the base fee is
+// burned, tips are transferred to the coinbase and thus do not change the total balance.
+func burnedFees(data txcontext.TxContext, gasUsed uint64) *big.Int {
+	burned := new(big.Int)
+	env := data.GetBlockEnvironment()
+	if env == nil {
+		return burned
+	}
+	if baseFee := env.GetBaseFee(); baseFee != nil {
+		burned.Mul(baseFee, new(big.Int).SetUint64(gasUsed))
+	}
+	msg := data.GetMessage()
+	if blobBaseFee := env.GetBlobBaseFee(); blobBaseFee != nil && msg != nil && len(msg.BlobHashes) > 0 {
+		blobGas := new(big.Int).SetUint64(uint64(len(msg.BlobHashes)) * params.BlobTxBlobGasPerBlob)
+		burned.Add(burned, blobGas.Mul(blobGas, blobBaseFee))
+	}
+	return burned
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBalanceAccountingValidator_NoValidatorIsCreatedIfDisabled(t *testing.T) {
+	ext := MakeBalanceAccountingValidator(&utils.Config{})
+	_, ok := ext.(extension.NilExtension[txcontext.TxContext])
+	assert.True(t, ok)
+}
+
+// prepareAccountingTx prepares a transaction from sender to recipient in a block with given base fee.
+func prepareAccountingTx(ctrl *gomock.Controller, sender, recipient, coinbase common.Address, baseFee int64) txcontext.TxContext {
+	env := txcontext.NewMockBlockEnvironment(ctrl)
+	env.EXPECT().GetCoinbase().Return(coinbase).AnyTimes()
+	env.EXPECT().GetBaseFee().Return(big.NewInt(baseFee)).AnyTimes()
+	env.EXPECT().GetBlobBaseFee().Return(nil).AnyTimes()
+
+	data := txcontext.NewMockTxContext(ctrl)
+	data.EXPECT().GetInputState().Return(txcontext.NewWorldState(map[common.Address]txcontext.Account{})).AnyTimes()
+	data.EXPECT().GetOutputState().Return(nil).AnyTimes()
+	data.EXPECT().GetBlockEnvironment().Return(env).AnyTimes()
+	data.EXPECT().GetMessage().Return(&core.Message{From: sender, To: &recipient}).AnyTimes()
+	return data
+}
+
+func TestBalanceAccountingValidator_TransfersAndBurnedFeesAreAccounted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	sender, recipient, coinbase := common.Address{1}, common.Address{2}, common.Address{3}
+
+	// sender transfers 100, uses 10 gas at base fee 2 and tip 1
+	// balances before the transaction, expectations with equal arguments are matched in declaration order
+	db.EXPECT().GetBalance(sender).Return(uint256.NewInt(1000))
+	db.EXPECT().GetBalance(recipient).Return(uint256.NewInt(0))
+	db.EXPECT().GetBalance(coinbase).Return(uint256.NewInt(0))
+	db.EXPECT().GetBalance(sender).Return(uint256.NewInt(1000 - 100 - 30))
+	db.EXPECT().GetBalance(recipient).Return(uint256.NewInt(100))
+	db.EXPECT().GetBalance(coinbase).Return(uint256.NewInt(10))
+
+	result := txcontext.NewMockResult(ctrl)
+	result.EXPECT().GetGasUsed().Return(uint64(10))
+
+	ctx := &executor.Context{State: db, ExecutionResult: result}
+	st := executor.State[txcontext.TxContext]{Block: 1, Data: prepareAccountingTx(ctrl, sender, recipient, coinbase, 2)}
+
+	ext := makeBalanceAccountingValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	require.NoError(t, ext.PreBlock(st, ctx))
+	require.NoError(t, ext.PreTransaction(st, ctx))
+	require.NoError(t, ext.PostTransaction(st, ctx))
+	require.NoError(t, ext.PostBlock(st, ctx))
+}
+
+func TestBalanceAccountingValidator_CreatedMoneyIsReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	sender, recipient, coinbase := common.Address{1}, common.Address{2}, common.Address{3}
+
+	// balances before the transaction, expectations with equal arguments are matched in declaration order
+	db.EXPECT().GetBalance(sender).Return(uint256.NewInt(1000))
+	db.EXPECT().GetBalance(recipient).Return(uint256.NewInt(0))
+	db.EXPECT().GetBalance(coinbase).Return(uint256.NewInt(0))
+	// recipient receives 5 more than the sender sent
+	db.EXPECT().GetBalance(sender).Return(uint256.NewInt(1000 - 100 - 20))
+	db.EXPECT().GetBalance(recipient).Return(uint256.NewInt(105))
+	db.EXPECT().GetBalance(coinbase).Return(uint256.NewInt(0))
+
+	result := txcontext.NewMockResult(ctrl)
+	result.EXPECT().GetGasUsed().Return(uint64(10))
+
+	ctx := &executor.Context{State: db, ExecutionResult: result}
+	st := executor.State[txcontext.TxContext]{Block: 7, Data: prepareAccountingTx(ctrl, sender, recipient, coinbase, 2)}
+
+	ext := makeBalanceAccountingValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	require.NoError(t, ext.PreBlock(st, ctx))
+	require.NoError(t, ext.PreTransaction(st, ctx))
+	require.NoError(t, ext.PostTransaction(st, ctx))
+	err := ext.PostBlock(st, ctx)
+	require.ErrorContains(t, err, "balance accounting error at block 7")
+	require.ErrorContains(t, err, "discrepancy 5")
+}
+
+func TestBalanceAccountingValidator_PseudoTransactionsAreSkipped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+	log.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(1)
+
+	ctx := &executor.Context{State: db}
+	st := executor.State[txcontext.TxContext]{Block: 1, Transaction: utils.PseudoTx}
+
+	ext := makeBalanceAccountingValidator(&utils.Config{}, log)
+	require.NoError(t, ext.PreBlock(st, ctx))
+	for i := 0; i < 2; i++ {
+		require.NoError(t, ext.PreTransaction(st, ctx))
+		require.NoError(t, ext.PostTransaction(st, ctx))
+	}
+	require.NoError(t, ext.PostBlock(st, ctx))
+}
+
+func TestBalanceAccountingValidator_ErrorIsForwardedWithContinueOnFailure(t *testing.T) {
+	ext := makeBalanceAccountingValidator(&utils.Config{ContinueOnFailure: true}, logger.NewLogger("critical", "test"))
+	ext.delta.SetInt64(1)
+
+	ctx := &executor.Context{ErrorInput: make(chan error, 1)}
+	require.NoError(t, ext.PostBlock(executor.State[txcontext.TxContext]{Block: 3}, ctx))
+	require.Len(t, ctx.ErrorInput, 1)
+}
@@ -0,0 +1,267 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state/proxy"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// deletionChannelSize is the maximum number of account creations and self-destructs of a transaction.
+const deletionChannelSize = 100000
+
+// MakeDeletionValidator creates an extension which checks at the end of each block that
+// the accounts self-destructed and resurrected by the execution match the deleted accounts
+// recorded in the AidaDb. Both sides are reduced to the last action of each account within
+// the block, so an account destroyed and re-created in the same block is resurrected.
+// It depends on the PostBlock event and is only useful as part of a sequential evaluation.
+func MakeDeletionValidator(cfg *utils.Config) executor.Extension[txcontext.TxContext] {
+	if !cfg.ValidateDeletions {
+		return extension.NilExtension[txcontext.TxContext]{}
+	}
+
+	log := logger.NewLogger(cfg.LogLevel, "Deletion-Validator")
+
+	return makeDeletionValidator(cfg, log)
+}
+
+func makeDeletionValidator(cfg *utils.Config, log logger.Logger) *deletionValidator {
+	return &deletionValidator{
+		stateDbValidator: makeStateDbValidator(cfg, log, ValidateTxTarget{}),
+		ch:               make(chan proxy.ContractLiveliness, deletionChannelSize),
+		history:          make(map[common.Address]bool),
+		executed:         make(map[common.Address]accountLiveliness),
+	}
+}
+
+// accountLiveliness is the last action of an account within a block.
+type accountLiveliness byte
+
+const (
+	accountCreated     accountLiveliness = iota // created without a known earlier deletion
+	accountDestroyed                            // self-destructed
+	accountResurrected                          // re-created after a deletion
+)
+
+func (l accountLiveliness) String() string {
+	switch l {
+	case accountCreated:
+		return "created"
+	case accountDestroyed:
+		return "destroyed"
+	case accountResurrected:
+		return "resurrected"
+	default:
+		return "unknown"
+	}
+}
+
+type deletionValidator struct {
+	*stateDbValidator
+	ddb      db.DestroyedAccountDB
+	ch       chan proxy.ContractLiveliness        // creations and self-destructs of the current transaction
+	history  map[common.Address]bool              // true if the account was deleted and not re-created since
+	executed map[common.Address]accountLiveliness // last action of each account in the current block
+}
+
+// PreRun opens the deletion db within the AidaDb and wraps the StateDb into a proxy reporting
+// creations and self-destructs of accounts.
+func (v *deletionValidator) PreRun(_ executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	// ddb is already set in tests
+	if v.ddb == nil {
+		if ctx.AidaDb == nil {
+			return errors.New("deletion validation requires an aida-db")
+		}
+		var err error
+		v.ddb, err = db.MakeDefaultDestroyedAccountDBFromBaseDB(ctx.AidaDb)
+		if err != nil {
+			return fmt.Errorf("cannot open deletion db; %w", err)
+		}
+	}
+	ctx.State = proxy.NewDeletionProxy(ctx.State, v.ch, v.cfg.LogLevel)
+	return nil
+}
+
+// PreBlock resets the actions of the block.
+func (v *deletionValidator) PreBlock(executor.State[txcontext.TxContext], *executor.Context) error {
+	clear(v.executed)
+	return nil
+}
+
+// PreTransaction discards actions left over by a transaction which was not completed.
+func (v *deletionValidator) PreTransaction(executor.State[txcontext.TxContext], *executor.Context) error {
+	v.drain()
+	return nil
+}
+
+// PostTransaction collects the last action of each account created or self-destructed by the
+// transaction. Like the recorder of the deletion db, failed transactions update the deletion history
+// but their actions are not compared.
+func (v *deletionValidator) PostTransaction(state executor.State[txcontext.TxContext], _ *executor.Context) error {
+	actions := v.drain()
+	if len(actions) == 0 || state.Transaction >= utils.PseudoTx {
+		return nil
+	}
+
+	// only the last action of each account within the transaction is kept
+	last := make(map[common.Address]accountLiveliness)
+	for _, action := range actions {
+		addr := action.Addr
+		if action.IsDeleted {
+			v.history[addr] = true
+			last[addr] = accountDestroyed
+			continue
+		}
+		if last[addr] == accountDestroyed {
+			delete(last, addr)
+		}
+		deleted, found := v.history[addr]
+		switch {
+		case deleted:
+			v.history[addr] = false
+			last[addr] = accountResurrected
+		case !found:
+			// the account may have been deleted before the first replayed block
+			last[addr] = accountCreated
+		}
+	}
+
+	result := state.Data.GetResult()
+	if result == nil || result.GetReceipt() == nil || result.GetReceipt().GetStatus() != types.ReceiptStatusSuccessful {
+		return nil
+	}
+	for addr, l := range last {
+		v.executed[addr] = l
+	}
+	return nil
+}
+
+// PostBlock compares the actions of the block with the recorded deleted accounts.
+func (v *deletionValidator) PostBlock(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	recorded, err := recordedLiveliness(v.ddb, uint64(state.Block))
+	if err != nil {
+		return err
+	}
+
+	err = compareLiveliness(v.executed, recorded)
+	if err == nil {
+		return nil
+	}
+
+	err = fmt.Errorf("deletion validation failed at block %v; %w", state.Block, err)
+	if v.isErrFatal(err, ctx.ErrorInput) {
+		return err
+	}
+	return nil
+}
+
+// drain returns all actions reported by the proxy since the last call.
+func (v *deletionValidator) drain() []proxy.ContractLiveliness {
+	var res []proxy.ContractLiveliness
+	for {
+		select {
+		case action := <-v.ch:
+			res = append(res, action)
+		default:
+			return res
+		}
+	}
+}
+
+// recordedLiveliness returns the last recorded action of each account deleted or resurrected in given block.
+func recordedLiveliness(ddb db.DestroyedAccountDB, block uint64) (map[common.Address]accountLiveliness, error) {
+	start := make([]byte, 8)
+	binary.BigEndian.PutUint64(start, block)
+
+	iter := ddb.NewIterator([]byte(db.DestroyedAccountPrefix), start)
+	defer iter.Release()
+
+	res := make(map[common.Address]accountLiveliness)
+	for iter.Next() {
+		b, _, err := db.DecodeDestroyedAccountKey(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		if b > block {
+			break
+		}
+		list, err := ddb.Decode(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode deleted accounts of block %v; %w", block, err)
+		}
+		for _, addr := range list.DestroyedAccounts {
+			res[common.Address(addr)] = accountDestroyed
+		}
+		for _, addr := range list.ResurrectedAccounts {
+			res[common.Address(addr)] = accountResurrected
+		}
+	}
+	return res, iter.Error()
+}
+
+// compareLiveliness reports accounts whose executed action differs from the recorded one.
+// A creation matches a recorded resurrection since the deletion preceding it may not have
+// been replayed.
+func compareLiveliness(executed, recorded map[common.Address]accountLiveliness) error {
+	var addrs []common.Address
+	for addr := range executed {
+		addrs = append(addrs, addr)
+	}
+	for addr := range recorded {
+		if _, found := executed[addr]; !found {
+			addrs = append(addrs, addr)
+		}
+	}
+	slices.SortFunc(addrs, func(a, b common.Address) int { return bytes.Compare(a[:], b[:]) })
+
+	var diffs []string
+	for _, addr := range addrs {
+		have, executedFound := executed[addr]
+		want, recordedFound := recorded[addr]
+		switch {
+		case !recordedFound && have == accountCreated:
+			continue
+		case !recordedFound:
+			diffs = append(diffs, fmt.Sprintf("account %v was %v but is not recorded", addr.Hex(), have))
+		case !executedFound:
+			diffs = append(diffs, fmt.Sprintf("account %v is recorded as %v but was not", addr.Hex(), want))
+		case have == want || (have == accountCreated && want == accountResurrected):
+			continue
+		default:
+			diffs = append(diffs, fmt.Sprintf("account %v was %v but is recorded as %v", addr.Hex(), have, want))
+		}
+	}
+
+	if len(diffs) > 0 {
+		return fmt.Errorf("%d accounts differ: %v", len(diffs), strings.Join(diffs, ", "))
+	}
+	return nil
+}
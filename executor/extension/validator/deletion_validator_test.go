@@ -0,0 +1,194 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/state/proxy"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	substatetypes "github.com/0xsoniclabs/substate/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeletionValidator_NoValidatorIsCreatedIfDisabled(t *testing.T) {
+	ext := MakeDeletionValidator(&utils.Config{})
+	_, ok := ext.(extension.NilExtension[txcontext.TxContext])
+	assert.True(t, ok)
+}
+
+func TestDeletionValidator_PreRunFailsWithoutAidaDb(t *testing.T) {
+	ext := makeDeletionValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	err := ext.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{})
+	require.ErrorContains(t, err, "requires an aida-db")
+}
+
+func TestDeletionValidator_PreRunWrapsStateDbIntoDeletionProxy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ext := makeDeletionValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	ext.ddb = makeTestDeletionDb(t)
+
+	ctx := &executor.Context{State: state.NewMockStateDB(ctrl)}
+	require.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+	_, ok := ctx.State.(*proxy.DeletionProxy)
+	assert.True(t, ok)
+}
+
+func TestDeletionValidator_MatchingBlockIsAccepted(t *testing.T) {
+	a, b := common.Address{0xa}, common.Address{0xb}
+	ddb := makeTestDeletionDb(t)
+	// a is destroyed and re-created within the block, b is destroyed
+	require.NoError(t, ddb.SetDestroyedAccounts(5, 0, []substatetypes.Address{substatetypes.Address(a)}, nil))
+	require.NoError(t, ddb.SetDestroyedAccounts(5, 1, []substatetypes.Address{substatetypes.Address(b)}, []substatetypes.Address{substatetypes.Address(a)}))
+	// deletions of the next block are not considered
+	require.NoError(t, ddb.SetDestroyedAccounts(6, 0, []substatetypes.Address{substatetypes.Address(a)}, nil))
+
+	ext, ctx := prepareDeletionValidator(t, ddb, &utils.Config{})
+	runDeletionTx(t, ext, ctx, 5, 0, types.ReceiptStatusSuccessful, func(db state.StateDB) {
+		db.SelfDestruct(a)
+	})
+	runDeletionTx(t, ext, ctx, 5, 1, types.ReceiptStatusSuccessful, func(db state.StateDB) {
+		db.CreateAccount(a)
+		db.SelfDestruct(b)
+	})
+	require.NoError(t, ext.PostBlock(executor.State[txcontext.TxContext]{Block: 5}, ctx))
+}
+
+func TestDeletionValidator_DifferencesAreReported(t *testing.T) {
+	a, b, c := common.Address{0xa}, common.Address{0xb}, common.Address{0xc}
+	ddb := makeTestDeletionDb(t)
+	require.NoError(t, ddb.SetDestroyedAccounts(5, 0, []substatetypes.Address{substatetypes.Address(a), substatetypes.Address(b)}, nil))
+
+	ext, ctx := prepareDeletionValidator(t, ddb, &utils.Config{})
+	runDeletionTx(t, ext, ctx, 5, 0, types.ReceiptStatusSuccessful, func(db state.StateDB) {
+		// b is re-created by the execution, c is destroyed instead of a
+		db.SelfDestruct(b)
+		db.CreateAccount(b)
+		db.SelfDestruct(c)
+	})
+
+	err := ext.PostBlock(executor.State[txcontext.TxContext]{Block: 5}, ctx)
+	require.ErrorContains(t, err, "deletion validation failed at block 5; 3 accounts differ")
+	require.ErrorContains(t, err, "account "+a.Hex()+" is recorded as destroyed but was not")
+	require.ErrorContains(t, err, "account "+b.Hex()+" was resurrected but is recorded as destroyed")
+	require.ErrorContains(t, err, "account "+c.Hex()+" was destroyed but is not recorded")
+}
+
+func TestDeletionValidator_ActionsOfFailedTransactionsAreIgnored(t *testing.T) {
+	a := common.Address{0xa}
+	ext, ctx := prepareDeletionValidator(t, makeTestDeletionDb(t), &utils.Config{})
+	runDeletionTx(t, ext, ctx, 5, 0, types.ReceiptStatusFailed, func(db state.StateDB) {
+		db.SelfDestruct(a)
+	})
+	require.NoError(t, ext.PostBlock(executor.State[txcontext.TxContext]{Block: 5}, ctx))
+}
+
+func TestDeletionValidator_CreationMatchesResurrectionOfAccountDeletedBeforeRun(t *testing.T) {
+	a := common.Address{0xa}
+	ddb := makeTestDeletionDb(t)
+	require.NoError(t, ddb.SetDestroyedAccounts(5, 0, nil, []substatetypes.Address{substatetypes.Address(a)}))
+
+	ext, ctx := prepareDeletionValidator(t, ddb, &utils.Config{})
+	runDeletionTx(t, ext, ctx, 5, 0, types.ReceiptStatusSuccessful, func(db state.StateDB) {
+		db.CreateAccount(a)
+	})
+	require.NoError(t, ext.PostBlock(executor.State[txcontext.TxContext]{Block: 5}, ctx))
+}
+
+func TestDeletionValidator_ResurrectionIsTrackedAcrossBlocks(t *testing.T) {
+	a := common.Address{0xa}
+	ddb := makeTestDeletionDb(t)
+	require.NoError(t, ddb.SetDestroyedAccounts(5, 0, []substatetypes.Address{substatetypes.Address(a)}, nil))
+
+	ext, ctx := prepareDeletionValidator(t, ddb, &utils.Config{})
+	runDeletionTx(t, ext, ctx, 5, 0, types.ReceiptStatusSuccessful, func(db state.StateDB) {
+		db.SelfDestruct(a)
+	})
+	require.NoError(t, ext.PostBlock(executor.State[txcontext.TxContext]{Block: 5}, ctx))
+
+	// the resurrection in block 6 is missing in the deletion db
+	runDeletionTx(t, ext, ctx, 6, 0, types.ReceiptStatusSuccessful, func(db state.StateDB) {
+		db.CreateAccount(a)
+	})
+	err := ext.PostBlock(executor.State[txcontext.TxContext]{Block: 6}, ctx)
+	require.ErrorContains(t, err, "account "+a.Hex()+" was resurrected but is not recorded")
+}
+
+func TestDeletionValidator_ErrorIsNotFatalWithContinueOnFailure(t *testing.T) {
+	ddb := makeTestDeletionDb(t)
+	require.NoError(t, ddb.SetDestroyedAccounts(5, 0, []substatetypes.Address{{0xa}}, nil))
+
+	ext, ctx := prepareDeletionValidator(t, ddb, &utils.Config{ContinueOnFailure: true})
+	ctx.ErrorInput = make(chan error, 1)
+	require.NoError(t, ext.PreBlock(executor.State[txcontext.TxContext]{Block: 5}, ctx))
+	require.NoError(t, ext.PostBlock(executor.State[txcontext.TxContext]{Block: 5}, ctx))
+
+	err := <-ctx.ErrorInput
+	require.ErrorContains(t, err, "is recorded as destroyed but was not")
+}
+
+// makeTestDeletionDb creates an empty deletion db in a temporary directory.
+func makeTestDeletionDb(t *testing.T) db.DestroyedAccountDB {
+	ddb, err := db.NewDefaultDestroyedAccountDB(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, ddb.Close())
+	})
+	return ddb
+}
+
+// prepareDeletionValidator creates a deletion validator wrapping a mocked StateDb accepting
+// any creation and self-destruct.
+func prepareDeletionValidator(t *testing.T, ddb db.DestroyedAccountDB, cfg *utils.Config) (*deletionValidator, *executor.Context) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	db.EXPECT().CreateAccount(gomock.Any()).AnyTimes()
+	db.EXPECT().SelfDestruct(gomock.Any()).AnyTimes()
+
+	ext := makeDeletionValidator(cfg, logger.NewLogger("critical", "test"))
+	ext.ddb = ddb
+	ctx := &executor.Context{State: db}
+	require.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+	return ext, ctx
+}
+
+// runDeletionTx executes given operations as a transaction with given recorded status.
+func runDeletionTx(t *testing.T, ext *deletionValidator, ctx *executor.Context, block, tx int, status uint64, execute func(state.StateDB)) {
+	ctrl := gomock.NewController(t)
+	result := txcontext.NewMockResult(ctrl)
+	result.EXPECT().GetReceipt().Return(txcontext.NewResult(status, types.Bloom{}, nil, common.Address{}, 0)).AnyTimes()
+	data := txcontext.NewMockTxContext(ctrl)
+	data.EXPECT().GetResult().Return(result).AnyTimes()
+
+	st := executor.State[txcontext.TxContext]{Block: block, Transaction: tx, Data: data}
+	if tx == 0 {
+		require.NoError(t, ext.PreBlock(st, ctx))
+	}
+	require.NoError(t, ext.PreTransaction(st, ctx))
+	execute(ctx.State)
+	require.NoError(t, ext.PostTransaction(st, ctx))
+}
@@ -0,0 +1,235 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// errDivergenceFound stops the re-run of the bisection once the first divergent transaction is found.
+var errDivergenceFound = errors.New("transaction diverged from recorded post-state")
+
+// BisectReplayFunc replays blocks [first, last] on a StateDb primed with the state of block first-1
+// and with transaction validation enabled. The recorder has to receive the transaction events
+// while the StateDb still holds the post-state of the transaction, i.e. next to the transaction validator.
+type BisectReplayFunc func(first, last uint64, recorder executor.Extension[txcontext.TxContext]) error
+
+// MakeDivergenceBisector creates an extension locating the first transaction whose post-state
+// differs from the recorded one once the run fails. The failing blocks are re-run by the replay
+// function starting after the last block known to be good, which is the last block whose state
+// hash was validated, or the block preceding the failing one if state hashes are not validated.
+func MakeDivergenceBisector(cfg *utils.Config, replay BisectReplayFunc) executor.Extension[txcontext.TxContext] {
+	if !cfg.Bisect {
+		return extension.NilExtension[txcontext.TxContext]{}
+	}
+
+	log := logger.NewLogger(cfg.LogLevel, "Divergence-Bisector")
+
+	return makeDivergenceBisector(cfg, replay, log)
+}
+
+func makeDivergenceBisector(cfg *utils.Config, replay BisectReplayFunc, log logger.Logger) *divergenceBisector {
+	return &divergenceBisector{
+		cfg:    cfg,
+		log:    log,
+		replay: replay,
+	}
+}
+
+type divergenceBisector struct {
+	extension.NilExtension[txcontext.TxContext]
+	cfg      *utils.Config
+	log      logger.Logger
+	replay   BisectReplayFunc
+	started  bool // true once the first block was started
+	current  int  // block currently being processed
+	lastGood int  // last block known to be good
+}
+
+// PreRun marks the block preceding the range as good, it is either primed or validated by a previous run.
+func (b *divergenceBisector) PreRun(executor.State[txcontext.TxContext], *executor.Context) error {
+	b.lastGood = int(b.cfg.First) - 1
+	return nil
+}
+
+// PreBlock moves the last known-good block forward once the previous block passed its validation.
+func (b *divergenceBisector) PreBlock(state executor.State[txcontext.TxContext], _ *executor.Context) error {
+	if b.started && b.isValidated(b.current) {
+		b.lastGood = b.current
+	}
+	b.started = true
+	b.current = state.Block
+	return nil
+}
+
+// isValidated returns true if the state after the block is validated as a whole,
+// so that a divergence cannot remain unnoticed after a successfully finished block.
+func (b *divergenceBisector) isValidated(block int) bool {
+	return !b.cfg.ValidateStateHashes || block%max(b.cfg.StateHashInterval, 1) == 0
+}
+
+// PostRun re-runs the failing blocks if the run failed and reports the first divergent transaction.
+func (b *divergenceBisector) PostRun(_ executor.State[txcontext.TxContext], _ *executor.Context, err error) error {
+	if err == nil || !b.started {
+		return nil
+	}
+
+	first, last := uint64(b.lastGood+1), uint64(b.current)
+	b.log.Warningf("Run failed in block %d; re-running blocks %d-%d with transaction validation", last, first, last)
+
+	recorder := newDivergenceRecorder()
+	replayErr := b.replay(first, last, recorder)
+	if recorder.report == nil {
+		if replayErr != nil {
+			return fmt.Errorf("cannot locate divergent transaction in blocks %d-%d; %w", first, last, replayErr)
+		}
+		b.log.Warningf("No transaction of blocks %d-%d diverged from its recorded post-state", first, last)
+		return nil
+	}
+
+	report := recorder.report
+	report.FailedBlock = b.current
+	report.log(b.log)
+	if b.cfg.BisectReport == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal divergence report; %w", err)
+	}
+	if err = os.WriteFile(b.cfg.BisectReport, data, 0644); err != nil {
+		return fmt.Errorf("cannot write divergence report; %w", err)
+	}
+	b.log.Noticef("Divergence report: %v", b.cfg.BisectReport)
+	return nil
+}
+
+// divergenceReport describes the first transaction whose post-state differs from the recorded one.
+type divergenceReport struct {
+	FailedBlock int // block in which the original run failed
+	Block       int
+	Transaction int
+	Divergences []stateDivergence
+}
+
+func (r *divergenceReport) log(log logger.Logger) {
+	log.Noticef("First divergent transaction: %d/%d (run failed in block %d)", r.Block, r.Transaction, r.FailedBlock)
+	for _, d := range r.Divergences {
+		log.Noticef("%v", d)
+	}
+}
+
+// stateDivergence is a single difference between the recorded post-state of an account and the StateDb.
+type stateDivergence struct {
+	Address  common.Address
+	Field    string       // one of exist, balance, nonce, code or storage
+	Key      *common.Hash `json:",omitempty"` // set for storage only
+	Expected string
+	Actual   string
+}
+
+func (d stateDivergence) String() string {
+	if d.Key != nil {
+		return fmt.Sprintf("%v %v %v: expected %v, actual %v", d.Address.Hex(), d.Field, d.Key.Hex(), d.Expected, d.Actual)
+	}
+	return fmt.Sprintf("%v %v: expected %v, actual %v", d.Address.Hex(), d.Field, d.Expected, d.Actual)
+}
+
+func newDivergenceRecorder() *divergenceRecorder {
+	return &divergenceRecorder{}
+}
+
+// divergenceRecorder compares the post-state of each transaction with the recorded one
+// and stops the run at the first transaction with differences.
+type divergenceRecorder struct {
+	extension.NilExtension[txcontext.TxContext]
+	report *divergenceReport
+}
+
+// PostTransaction records the differences of the first divergent transaction.
+func (r *divergenceRecorder) PostTransaction(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	if r.report != nil || state.Data == nil || ctx.State == nil {
+		return nil
+	}
+	expected := state.Data.GetOutputState()
+	if expected == nil {
+		return nil
+	}
+	divergences := findStateDivergences(expected, ctx.State)
+	if len(divergences) == 0 {
+		return nil
+	}
+	r.report = &divergenceReport{
+		Block:       state.Block,
+		Transaction: state.Transaction,
+		Divergences: divergences,
+	}
+	return fmt.Errorf("block %d, transaction %d; %w", state.Block, state.Transaction, errDivergenceFound)
+}
+
+// findStateDivergences lists the differences of the given alloc to the db ordered by address.
+// Like doSubsetValidation, only accounts and slots contained in the alloc are compared.
+func findStateDivergences(alloc txcontext.WorldState, db state.VmStateDB) []stateDivergence {
+	var addresses []common.Address
+	alloc.ForEachAccount(func(addr common.Address, _ txcontext.Account) {
+		addresses = append(addresses, addr)
+	})
+	slices.SortFunc(addresses, func(x, y common.Address) int { return bytes.Compare(x[:], y[:]) })
+
+	var res []stateDivergence
+	for _, addr := range addresses {
+		acc := alloc.Get(addr)
+		if !db.Exist(addr) {
+			res = append(res, stateDivergence{Address: addr, Field: "exist", Expected: "true", Actual: "false"})
+		}
+		if balance := db.GetBalance(addr); acc.GetBalance().Cmp(balance) != 0 {
+			res = append(res, stateDivergence{Address: addr, Field: "balance", Expected: acc.GetBalance().String(), Actual: balance.String()})
+		}
+		if nonce := db.GetNonce(addr); nonce != acc.GetNonce() {
+			res = append(res, stateDivergence{Address: addr, Field: "nonce", Expected: fmt.Sprint(acc.GetNonce()), Actual: fmt.Sprint(nonce)})
+		}
+		if code := db.GetCode(addr); !bytes.Equal(code, acc.GetCode()) {
+			res = append(res, stateDivergence{Address: addr, Field: "code", Expected: fmt.Sprintf("%d bytes", len(acc.GetCode())), Actual: fmt.Sprintf("%d bytes", len(code))})
+		}
+
+		var keys []common.Hash
+		acc.ForEachStorage(func(key common.Hash, _ common.Hash) {
+			keys = append(keys, key)
+		})
+		slices.SortFunc(keys, func(x, y common.Hash) int { return bytes.Compare(x[:], y[:]) })
+		for _, key := range keys {
+			want, have := acc.GetStorageAt(key), db.GetState(addr, key)
+			if want != have {
+				res = append(res, stateDivergence{Address: addr, Field: "storage", Key: &key, Expected: want.Hex(), Actual: have.Hex()})
+			}
+		}
+	}
+	return res
+}
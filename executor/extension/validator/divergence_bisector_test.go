@@ -0,0 +1,240 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// divergentAccount is credited by every transaction of the divergentProvider.
+var divergentAccount = common.Address{0xd}
+
+const divergentTxsPerBlock = 2
+
+// divergentBalance is the recorded balance of the divergentAccount after the given transaction.
+func divergentBalance(block, tx int) int64 {
+	return int64((block-1)*divergentTxsPerBlock + tx + 1)
+}
+
+// divergentProvider provides blocks of transactions each crediting the divergentAccount by one.
+type divergentProvider struct {
+	ctrl *gomock.Controller
+}
+
+func (p divergentProvider) Run(from int, to int, consumer executor.Consumer[txcontext.TxContext]) error {
+	for block := from; block < to; block++ {
+		for tx := 0; tx < divergentTxsPerBlock; tx++ {
+			data := txcontext.NewMockTxContext(p.ctrl)
+			data.EXPECT().GetOutputState().Return(txcontext.NewWorldState(map[common.Address]txcontext.Account{
+				divergentAccount: txcontext.NewAccount(nil, nil, big.NewInt(divergentBalance(block, tx)), 0),
+			})).AnyTimes()
+			if err := consumer(executor.TransactionInfo[txcontext.TxContext]{Block: block, Transaction: tx, Data: data}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p divergentProvider) Close() {}
+
+// divergentProcessor credits the divergentAccount by one, except for the injected transaction crediting it by two.
+type divergentProcessor struct {
+	block, tx int
+}
+
+func (p divergentProcessor) Process(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	amount := uint64(1)
+	if state.Block == p.block && state.Transaction == p.tx {
+		amount = 2
+	}
+	ctx.State.AddBalance(divergentAccount, uint256.NewInt(amount), tracing.BalanceChangeUnspecified)
+	return nil
+}
+
+// intervalHashValidator simulates the state hash validation of blocks divisible by the interval.
+type intervalHashValidator struct {
+	extension.NilExtension[txcontext.TxContext]
+	interval int
+}
+
+func (v intervalHashValidator) PostBlock(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	if state.Block%v.interval != 0 {
+		return nil
+	}
+	if ctx.State.GetBalance(divergentAccount).Uint64() != uint64(divergentBalance(state.Block, divergentTxsPerBlock-1)) {
+		return errors.New("unexpected hash")
+	}
+	return nil
+}
+
+// primedStateDb creates a StateDb holding the recorded state after the given block.
+func primedStateDb(block int) state.StateDB {
+	return state.MakeInMemoryStateDB(txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		divergentAccount: txcontext.NewAccount(nil, nil, big.NewInt(divergentBalance(block, divergentTxsPerBlock-1)), 0),
+	}), uint64(block))
+}
+
+func TestDivergenceBisector_NoBisectorIsCreatedIfDisabled(t *testing.T) {
+	ext := MakeDivergenceBisector(&utils.Config{}, nil)
+	if _, ok := ext.(extension.NilExtension[txcontext.TxContext]); !ok {
+		t.Errorf("divergence bisector is enabled although not set in configuration")
+	}
+}
+
+func TestDivergenceBisector_ReportsFirstDivergentTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	log.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+	log.EXPECT().Noticef(gomock.Any(), gomock.Any()).AnyTimes()
+
+	report := filepath.Join(t.TempDir(), "divergence.json")
+	cfg := &utils.Config{
+		First:               1,
+		Last:                10,
+		Bisect:              true,
+		BisectReport:        report,
+		ValidateStateHashes: true,
+		StateHashInterval:   4,
+	}
+	provider := divergentProvider{ctrl: ctrl}
+	processor := divergentProcessor{block: 6, tx: 1}
+
+	var replayed [2]uint64
+	bisector := makeDivergenceBisector(cfg, func(first, last uint64, recorder executor.Extension[txcontext.TxContext]) error {
+		replayed = [2]uint64{first, last}
+		return executor.NewExecutor[txcontext.TxContext](provider, "CRITICAL").Run(
+			executor.Params{From: int(first), To: int(last) + 1, NumWorkers: 1, ParallelismGranularity: executor.BlockLevel, State: primedStateDb(int(first) - 1)},
+			processor,
+			[]executor.Extension[txcontext.TxContext]{recorder},
+			nil,
+		)
+	}, log)
+
+	err := executor.NewExecutor[txcontext.TxContext](provider, "CRITICAL").Run(
+		executor.Params{From: int(cfg.First), To: int(cfg.Last) + 1, NumWorkers: 1, ParallelismGranularity: executor.BlockLevel, State: primedStateDb(0)},
+		processor,
+		[]executor.Extension[txcontext.TxContext]{bisector, intervalHashValidator{interval: cfg.StateHashInterval}},
+		nil,
+	)
+	require.ErrorContains(t, err, "unexpected hash")
+
+	// the divergence is noticed in block 8, block 4 is the last validated one
+	assert.Equal(t, [2]uint64{5, 8}, replayed)
+
+	data, err := os.ReadFile(report)
+	require.NoError(t, err)
+	var got divergenceReport
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, divergenceReport{
+		FailedBlock: 8,
+		Block:       6,
+		Transaction: 1,
+		Divergences: []stateDivergence{
+			{Address: divergentAccount, Field: "balance", Expected: "12", Actual: "13"},
+		},
+	}, got)
+}
+
+func TestDivergenceBisector_SuccessfulRunIsNotReplayed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfg := &utils.Config{First: 1, Last: 10, Bisect: true}
+	bisector := makeDivergenceBisector(cfg, func(uint64, uint64, executor.Extension[txcontext.TxContext]) error {
+		t.Fatal("successful run must not be replayed")
+		return nil
+	}, logger.NewMockLogger(ctrl))
+
+	err := executor.NewExecutor[txcontext.TxContext](divergentProvider{ctrl: ctrl}, "CRITICAL").Run(
+		executor.Params{From: int(cfg.First), To: int(cfg.Last) + 1, NumWorkers: 1, ParallelismGranularity: executor.BlockLevel, State: primedStateDb(0)},
+		divergentProcessor{},
+		[]executor.Extension[txcontext.TxContext]{bisector},
+		nil,
+	)
+	require.NoError(t, err)
+}
+
+func TestDivergenceBisector_ReplaysOnlyFailingBlockWithoutStateHashValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{First: 3, Last: 10, Bisect: true}
+	bisector := makeDivergenceBisector(cfg, func(first, last uint64, _ executor.Extension[txcontext.TxContext]) error {
+		assert.Equal(t, uint64(5), first)
+		assert.Equal(t, uint64(5), last)
+		return nil
+	}, log)
+
+	log.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(2)
+	require.NoError(t, bisector.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{}))
+	for block := 3; block <= 5; block++ {
+		require.NoError(t, bisector.PreBlock(executor.State[txcontext.TxContext]{Block: block}, &executor.Context{}))
+	}
+	require.NoError(t, bisector.PostRun(executor.State[txcontext.TxContext]{}, &executor.Context{}, errors.New("failure")))
+}
+
+func TestDivergenceBisector_FailingReplayIsReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{First: 1, Last: 10, Bisect: true}
+	injected := errors.New("injected")
+	bisector := makeDivergenceBisector(cfg, func(uint64, uint64, executor.Extension[txcontext.TxContext]) error {
+		return injected
+	}, log)
+
+	log.EXPECT().Warningf(gomock.Any(), gomock.Any())
+	require.NoError(t, bisector.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{}))
+	require.NoError(t, bisector.PreBlock(executor.State[txcontext.TxContext]{Block: 1}, &executor.Context{}))
+	err := bisector.PostRun(executor.State[txcontext.TxContext]{}, &executor.Context{}, errors.New("failure"))
+	require.ErrorIs(t, err, injected)
+	assert.ErrorContains(t, err, "cannot locate divergent transaction in blocks 1-1")
+}
+
+func TestDivergenceBisector_FindStateDivergences(t *testing.T) {
+	a, b := common.Address{0xa}, common.Address{0xb}
+	db := state.MakeInMemoryStateDB(txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		a: txcontext.NewAccount([]byte{1, 2}, map[common.Hash]common.Hash{{1}: {1}, {2}: {2}}, big.NewInt(10), 1),
+	}), 0)
+	alloc := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		b: txcontext.NewAccount(nil, nil, big.NewInt(0), 0),
+		a: txcontext.NewAccount([]byte{1}, map[common.Hash]common.Hash{{1}: {1}, {2}: {3}}, big.NewInt(10), 2),
+	})
+
+	key := common.Hash{2}
+	assert.Equal(t, []stateDivergence{
+		{Address: a, Field: "nonce", Expected: "2", Actual: "1"},
+		{Address: a, Field: "code", Expected: "1 bytes", Actual: "2 bytes"},
+		{Address: a, Field: "storage", Key: &key, Expected: common.Hash{3}.Hex(), Actual: common.Hash{2}.Hex()},
+		{Address: b, Field: "exist", Expected: "true", Actual: "false"},
+	}, findStateDivergences(alloc, db))
+}
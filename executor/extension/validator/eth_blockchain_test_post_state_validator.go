@@ -0,0 +1,68 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"fmt"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+// MakeEthBlockchainTestPostStateValidator creates an extension validating the state
+// after all blocks of a blockchain test were executed against the expected post-state.
+func MakeEthBlockchainTestPostStateValidator(cfg *utils.Config) executor.Extension[txcontext.TxContext] {
+	if !cfg.Validate {
+		return extension.NilExtension[txcontext.TxContext]{}
+	}
+	return makeEthBlockchainTestPostStateValidator(cfg, logger.NewLogger(cfg.LogLevel, "ethBlockchainTestPostStateValidator"))
+}
+
+func makeEthBlockchainTestPostStateValidator(cfg *utils.Config, log logger.Logger) executor.Extension[txcontext.TxContext] {
+	return &ethBlockchainTestPostStateValidator{
+		cfg: cfg,
+		log: log,
+	}
+}
+
+type ethBlockchainTestPostStateValidator struct {
+	extension.NilExtension[txcontext.TxContext]
+	cfg *utils.Config
+	log logger.Logger
+}
+
+// PostBlock validates world state. Tests which do not provide
+// the post-state (e.g. large ones) are validated by state root only.
+func (e *ethBlockchainTestPostStateValidator) PostBlock(s executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	want := s.Data.GetOutputState()
+	if want == nil {
+		return nil
+	}
+	err := validateWorldState(e.cfg, ctx.State, want, e.log)
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("post alloc validation failed; %w\ntest-info:\n%s", err, s.Data)
+	if !e.cfg.ContinueOnFailure {
+		return err
+	}
+	ctx.ErrorInput <- err
+	return nil
+}
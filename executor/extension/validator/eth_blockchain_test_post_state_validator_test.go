@@ -0,0 +1,84 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEthBlockchainTestPostStateValidator_NoValidatorIsCreatedIfDisabled(t *testing.T) {
+	ext := MakeEthBlockchainTestPostStateValidator(&utils.Config{})
+	_, ok := ext.(extension.NilExtension[txcontext.TxContext])
+	assert.True(t, ok)
+}
+
+func TestEthBlockchainTestPostStateValidator_PostBlock(t *testing.T) {
+	addr := common.Address{1}
+	postState := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		addr: txcontext.NewAccount(nil, nil, big.NewInt(1), 1),
+	})
+	tests := map[string]struct {
+		balance *uint256.Int
+		wantErr bool
+	}{
+		"matching":  {uint256.NewInt(1), false},
+		"divergent": {uint256.NewInt(2), true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := state.NewMockStateDB(ctrl)
+			data := txcontext.NewMockTxContext(ctrl)
+			data.EXPECT().GetOutputState().Return(postState)
+			db.EXPECT().Exist(addr).Return(true)
+			db.EXPECT().GetBalance(addr).Return(test.balance)
+			db.EXPECT().GetNonce(addr).Return(uint64(1))
+			db.EXPECT().GetCode(addr).Return(nil)
+
+			cfg := &utils.Config{StateValidationMode: utils.SubsetCheck}
+			ext := makeEthBlockchainTestPostStateValidator(cfg, logger.NewLogger("critical", "test"))
+			err := ext.PostBlock(executor.State[txcontext.TxContext]{Data: data}, &executor.Context{State: db})
+			if !test.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, "post alloc validation failed")
+		})
+	}
+}
+
+func TestEthBlockchainTestPostStateValidator_SkipsTestsWithoutPostState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	data := txcontext.NewMockTxContext(ctrl)
+	data.EXPECT().GetOutputState().Return(nil)
+
+	ext := makeEthBlockchainTestPostStateValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	require.NoError(t, ext.PostBlock(executor.State[txcontext.TxContext]{Data: data}, &executor.Context{}))
+}
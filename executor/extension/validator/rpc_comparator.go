@@ -17,11 +17,16 @@
 package validator
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/executor/extension"
@@ -30,6 +35,7 @@ import (
 	"github.com/0xsoniclabs/aida/txcontext"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/Fantom-foundation/lachesis-base/common/littleendian"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
@@ -109,6 +115,7 @@ type rpcComparator struct {
 	numberOfRetriedRequests int
 	totalNumberOfRequests   int
 	numberOfErrors          int
+	numberOfUnverifiable    atomic.Int64
 }
 
 // PostTransaction compares result with recording. If ContinueOnFailure
@@ -121,6 +128,13 @@ func (c *rpcComparator) PostTransaction(state executor.State[*rpc.RequestAndResu
 		return nil
 	}
 
+	// results which cannot be reconstructed from the replayed data are neither matches nor failures
+	if _, err := ctx.ExecutionResult.GetRawResult(); errors.Is(err, rpc.ErrUnverifiable) {
+		c.numberOfUnverifiable.Add(1)
+		c.log.Debugf("block %v: %v request is unverifiable", state.Block, state.Data.Query.Method)
+		return nil
+	}
+
 	compareErr := compare(ctx.ExecutionResult, state)
 	if compareErr != nil {
 		// request method base 'call' cannot be resent, because we need timestamp of the block that executed
@@ -135,7 +149,8 @@ func (c *rpcComparator) PostTransaction(state executor.State[*rpc.RequestAndResu
 			}
 		}
 		// lot errors are recorded wrongly, for this case we resend the request and compare it again
-		if !state.Data.IsRecovered {
+		// - logs and receipts are not a single value, hence they cannot be compared with a resent result
+		if !state.Data.IsRecovered && state.Data.Query.MethodBase != "getLogs" && state.Data.Query.MethodBase != "getTransactionReceipt" {
 			c.log.Debugf("retrying %v request", state.Data.Query.Method)
 			c.numberOfRetriedRequests++
 			c.log.Debugf("current ration retried against total %v/%v", c.numberOfRetriedRequests, c.totalNumberOfRequests)
@@ -173,6 +188,14 @@ func (c *rpcComparator) PostTransaction(state executor.State[*rpc.RequestAndResu
 	return nil
 }
 
+// PostRun reports the number of requests which could not be verified.
+func (c *rpcComparator) PostRun(executor.State[*rpc.RequestAndResults], *executor.Context, error) error {
+	if n := c.numberOfUnverifiable.Load(); n > 0 {
+		c.log.Noticef("%v requests were unverifiable, their results cannot be reconstructed from the replayed data", n)
+	}
+	return nil
+}
+
 func compare(result txcontext.Result, state executor.State[*rpc.RequestAndResults]) *comparatorError {
 	switch state.Data.Query.MethodBase {
 	case "getBalance":
@@ -188,6 +211,10 @@ func compare(result txcontext.Result, state executor.State[*rpc.RequestAndResult
 		return compareCode(result, state.Data, state.Block)
 	case "getStorageAt":
 		return compareStorageAt(result, state.Data, state.Block)
+	case "getLogs":
+		return compareLogs(result, state.Data, state.Block)
+	case "getTransactionReceipt":
+		return compareTransactionReceipt(result, state.Data, state.Block)
 	}
 
 	return nil
@@ -573,6 +600,269 @@ func compareStorageAt(result txcontext.Result, data *rpc.RequestAndResults, bloc
 	return nil
 }
 
+// compareLogs compares getLogs data recorded on API server with data returned by StateDB.
+// Both lists of logs are normalized before the comparison, hence their order and the
+// notation of hex values do not matter.
+func compareLogs(result txcontext.Result, data *rpc.RequestAndResults, block int) *comparatorError {
+	res, err := result.GetRawResult()
+
+	if data.Error != nil {
+		return checkUnexpectedError(result, data, block, res)
+	}
+
+	if err != nil {
+		return newComparatorError(result, err, string(data.Response.Result), data, block, expectedResultGotError)
+	}
+
+	var recorded, computed []rpcLog
+	if err = json.Unmarshal(data.Response.Result, &recorded); err != nil {
+		return newComparatorError(result, string(res), string(data.Response.Result), data, block, cannotUnmarshalResult)
+	}
+	if err = json.Unmarshal(res, &computed); err != nil {
+		return newComparatorError(result, string(res), string(data.Response.Result), data, block, cannotUnmarshalResult)
+	}
+	if err = normalizeLogs(recorded); err != nil {
+		return newComparatorError(result, string(res), string(data.Response.Result), data, block, cannotUnmarshalResult)
+	}
+	if err = normalizeLogs(computed); err != nil {
+		return newComparatorError(result, string(res), string(data.Response.Result), data, block, cannotUnmarshalResult)
+	}
+
+	if diff := diffLogs(computed, recorded); !diff.empty() {
+		return newComparatorError(result, diff, fmt.Sprintf("%d logs", len(recorded)), data, block, noMatchingResult)
+	}
+
+	return nil
+}
+
+// compareTransactionReceipt compares getTransactionReceipt data recorded on API server with the receipt
+// reconstructed from the substate of the transaction. Log indices of the recorded receipt are counted
+// within the block, hence they are compared relative to the first log of the transaction.
+func compareTransactionReceipt(result txcontext.Result, data *rpc.RequestAndResults, block int) *comparatorError {
+	res, err := result.GetRawResult()
+
+	if data.Error != nil {
+		return checkUnexpectedError(result, data, block, res)
+	}
+
+	if err != nil {
+		return newComparatorError(result, err, string(data.Response.Result), data, block, expectedResultGotError)
+	}
+
+	var recorded, computed rpc.TransactionReceipt
+	if err = json.Unmarshal(data.Response.Result, &recorded); err != nil {
+		return newComparatorError(result, string(res), string(data.Response.Result), data, block, cannotUnmarshalResult)
+	}
+	if err = json.Unmarshal(res, &computed); err != nil {
+		return newComparatorError(result, string(res), string(data.Response.Result), data, block, cannotUnmarshalResult)
+	}
+
+	if diff := diffReceipts(&computed, &recorded); len(diff) > 0 {
+		return newComparatorError(result, strings.Join(diff, "\n\t\t"), fmt.Sprintf("receipt of %v", recorded.TxHash.Hex()), data, block, noMatchingResult)
+	}
+
+	return nil
+}
+
+// diffReceipts lists the fields in which the reconstructed receipt differs from the recorded one.
+func diffReceipts(have, want *rpc.TransactionReceipt) []string {
+	var diff []string
+	report := func(field string, have, want any) {
+		diff = append(diff, fmt.Sprintf("different %v: have %v, want %v", field, have, want))
+	}
+
+	if have.TxHash != want.TxHash {
+		report("transactionHash", have.TxHash.Hex(), want.TxHash.Hex())
+	}
+	if have.BlockNumber != want.BlockNumber {
+		report("blockNumber", have.BlockNumber, want.BlockNumber)
+	}
+	if have.TransactionIndex != want.TransactionIndex {
+		report("transactionIndex", have.TransactionIndex, want.TransactionIndex)
+	}
+	if have.Status != want.Status {
+		report("status", have.Status, want.Status)
+	}
+	if have.GasUsed != want.GasUsed {
+		report("gasUsed", have.GasUsed, want.GasUsed)
+	}
+	if haveAddr, wantAddr := contractAddress(have.ContractAddress), contractAddress(want.ContractAddress); haveAddr != wantAddr {
+		report("contractAddress", haveAddr, wantAddr)
+	}
+	if have.Bloom != want.Bloom {
+		report("logsBloom", hexutil.Encode(have.Bloom[:]), hexutil.Encode(want.Bloom[:]))
+	}
+
+	if len(have.Logs) != len(want.Logs) {
+		report("number of logs", len(have.Logs), len(want.Logs))
+		return diff
+	}
+	for i := range have.Logs {
+		h, w := have.Logs[i], want.Logs[i]
+		if index := w.Index - want.Logs[0].Index; h.Index != index {
+			report(fmt.Sprintf("index of log %d", i), h.Index, index)
+		}
+		if h.TxHash != w.TxHash {
+			report(fmt.Sprintf("transactionHash of log %d", i), h.TxHash.Hex(), w.TxHash.Hex())
+		}
+		if h.Address != w.Address {
+			report(fmt.Sprintf("address of log %d", i), h.Address.Hex(), w.Address.Hex())
+		}
+		if !slices.Equal(h.Topics, w.Topics) {
+			report(fmt.Sprintf("topics of log %d", i), h.Topics, w.Topics)
+		}
+		if !bytes.Equal(h.Data, w.Data) {
+			report(fmt.Sprintf("data of log %d", i), hexutil.Encode(h.Data), hexutil.Encode(w.Data))
+		}
+	}
+	return diff
+}
+
+// contractAddress formats the contract address of a receipt, which is null unless the transaction created a contract.
+func contractAddress(addr *common.Address) string {
+	if addr == nil {
+		return "null"
+	}
+	return addr.Hex()
+}
+
+// rpcLog is a log as returned by the eth_getLogs method. Only fields
+// which are known to the StateDB are compared.
+type rpcLog struct {
+	Address     string   `json:"address"`
+	Topics      []string `json:"topics"`
+	Data        string   `json:"data"`
+	BlockNumber string   `json:"blockNumber"`
+	LogIndex    string   `json:"logIndex"`
+
+	block, index uint64 // decoded block number and log index
+}
+
+func (l rpcLog) String() string {
+	return fmt.Sprintf("{block: %d, index: %d, address: %v, topics: %v, data: %v}", l.block, l.index, l.Address, l.Topics, l.Data)
+}
+
+// before returns true if the log precedes the other one in order of block number and log index.
+func (l rpcLog) before(other rpcLog) bool {
+	if l.block != other.block {
+		return l.block < other.block
+	}
+	return l.index < other.index
+}
+
+// normalizeLogs lowercases and 0x-prefixes all hex values, decodes block numbers
+// and log indices, and orders the logs by them.
+func normalizeLogs(logs []rpcLog) error {
+	var err error
+	for i := range logs {
+		log := &logs[i]
+		log.Address = normalizeHex(log.Address)
+		log.Data = normalizeHex(log.Data)
+		for j := range log.Topics {
+			log.Topics[j] = normalizeHex(log.Topics[j])
+		}
+		if log.block, err = strconv.ParseUint(strings.TrimPrefix(normalizeHex(log.BlockNumber), "0x"), 16, 64); err != nil {
+			return fmt.Errorf("invalid block number %q; %w", log.BlockNumber, err)
+		}
+		if log.index, err = strconv.ParseUint(strings.TrimPrefix(normalizeHex(log.LogIndex), "0x"), 16, 64); err != nil {
+			return fmt.Errorf("invalid log index %q; %w", log.LogIndex, err)
+		}
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].before(logs[j])
+	})
+	return nil
+}
+
+func normalizeHex(value string) string {
+	value = strings.ToLower(value)
+	if !strings.HasPrefix(value, "0x") {
+		value = "0x" + value
+	}
+	return value
+}
+
+// logsDiff lists differences between logs returned by the StateDB and the recorded logs.
+type logsDiff struct {
+	missing    []rpcLog // recorded logs not returned by the StateDB
+	extra      []rpcLog // logs returned by the StateDB which were not recorded
+	mismatches []string // logs returned by both with different content
+}
+
+func (d logsDiff) empty() bool {
+	return len(d.missing) == 0 && len(d.extra) == 0 && len(d.mismatches) == 0
+}
+
+func (d logsDiff) String() string {
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "%d missing, %d extra and %d mismatching logs", len(d.missing), len(d.extra), len(d.mismatches))
+	for _, log := range d.missing {
+		fmt.Fprintf(builder, "\n\t\tmissing log: %v", log)
+	}
+	for _, log := range d.extra {
+		fmt.Fprintf(builder, "\n\t\textra log: %v", log)
+	}
+	for _, mismatch := range d.mismatches {
+		fmt.Fprintf(builder, "\n\t\tmismatching log: %v", mismatch)
+	}
+	return builder.String()
+}
+
+// diffLogs compares normalized logs returned by the StateDB with the recorded ones. Logs are
+// paired by their block number and log index. The recorded response may be a single page of
+// a paginated result, hence logs returned by the StateDB outside the range of the recorded
+// logs belong to other pages and are not reported.
+func diffLogs(computed, recorded []rpcLog) logsDiff {
+	var diff logsDiff
+	if len(recorded) > 0 {
+		first, last := recorded[0], recorded[len(recorded)-1]
+		page := make([]rpcLog, 0, len(computed))
+		for _, log := range computed {
+			if !log.before(first) && !last.before(log) {
+				page = append(page, log)
+			}
+		}
+		computed = page
+	}
+
+	type logKey struct{ block, index uint64 }
+	candidates := make(map[logKey][]int)
+	for i, log := range computed {
+		key := logKey{log.block, log.index}
+		candidates[key] = append(candidates[key], i)
+	}
+
+	paired := make([]bool, len(computed))
+	for _, want := range recorded {
+		key := logKey{want.block, want.index}
+		if len(candidates[key]) == 0 {
+			diff.missing = append(diff.missing, want)
+			continue
+		}
+		i := candidates[key][0]
+		candidates[key] = candidates[key][1:]
+		paired[i] = true
+
+		have := computed[i]
+		if have.Address != want.Address {
+			diff.mismatches = append(diff.mismatches, fmt.Sprintf("log %d of block %d has different address; carmen: %v, recorded: %v", want.index, want.block, have.Address, want.Address))
+		}
+		if strings.Join(have.Topics, ",") != strings.Join(want.Topics, ",") {
+			diff.mismatches = append(diff.mismatches, fmt.Sprintf("log %d of block %d has different topics; carmen: %v, recorded: %v", want.index, want.block, have.Topics, want.Topics))
+		}
+		if have.Data != want.Data {
+			diff.mismatches = append(diff.mismatches, fmt.Sprintf("log %d of block %d has different data; carmen: %v, recorded: %v", want.index, want.block, have.Data, want.Data))
+		}
+	}
+
+	for i, log := range computed {
+		if !paired[i] {
+			diff.extra = append(diff.extra, log)
+		}
+	}
+	return diff
+}
+
 // newComparatorError returns new comparatorError with given StateDB and recorded data based on the typ.
 func newComparatorError(result txcontext.Result, stateDB, expected any, data *rpc.RequestAndResults, block int, typ comparatorErrorType) *comparatorError {
 	switch typ {
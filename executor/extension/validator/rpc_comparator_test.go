@@ -29,7 +29,12 @@ import (
 	"github.com/0xsoniclabs/aida/rpc"
 	"github.com/0xsoniclabs/aida/txcontext"
 	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/substate"
+	substatetypes "github.com/0xsoniclabs/substate/types"
 	"github.com/Fantom-foundation/lachesis-base/common/littleendian"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/status-im/keycard-go/hexutils"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -733,6 +738,7 @@ func TestRpcComparator_Compare(t *testing.T) {
 		"estimateGas",
 		"getCode",
 		"getStorageAt",
+		"getLogs",
 		"unknownMethod",
 	}
 	for _, input := range inputs {
@@ -763,3 +769,291 @@ func TestRpcComparator_Compare(t *testing.T) {
 	}
 
 }
+
+// carmenLogs are logs of block 0x10 as encoded by the StateDB for the getLogs method.
+const carmenLogs = `[
+	{"address":"0x000000000000000000000000000000000000000A","topics":["0x0000000000000000000000000000000000000000000000000000000000000001"],"data":"0x01","blockNumber":"0x10","transactionHash":"0x0000000000000000000000000000000000000000000000000000000000000000","transactionIndex":"0x0","blockHash":"0x0000000000000000000000000000000000000000000000000000000000000000","logIndex":"0x0","removed":false},
+	{"address":"0x000000000000000000000000000000000000000B","topics":[],"data":"0x","blockNumber":"0x10","transactionHash":"0x0000000000000000000000000000000000000000000000000000000000000000","transactionIndex":"0x0","blockHash":"0x0000000000000000000000000000000000000000000000000000000000000000","logIndex":"0x1","removed":false},
+	{"address":"0x000000000000000000000000000000000000000C","topics":["0x0000000000000000000000000000000000000000000000000000000000000002"],"data":"0x03","blockNumber":"0x10","transactionHash":"0x0000000000000000000000000000000000000000000000000000000000000000","transactionIndex":"0x0","blockHash":"0x0000000000000000000000000000000000000000000000000000000000000000","logIndex":"0x2","removed":false}
+]`
+
+func Test_compareLogs(t *testing.T) {
+	tests := map[string]struct {
+		recorded string
+		expected []string // parts of the reported difference, nil if results match
+	}{
+		"identical": {
+			recorded: carmenLogs,
+		},
+		"reordered and differently notated": {
+			recorded: `[
+				{"address":"0x000000000000000000000000000000000000000c","topics":["0000000000000000000000000000000000000000000000000000000000000002"],"data":"0x03","blockNumber":"0x10","logIndex":"0x2"},
+				{"address":"0x000000000000000000000000000000000000000a","topics":["0000000000000000000000000000000000000000000000000000000000000001"],"data":"0x01","blockNumber":"0x10","logIndex":"0x0"},
+				{"address":"0x000000000000000000000000000000000000000b","topics":[],"data":"0x","blockNumber":"0x10","logIndex":"0x1"}
+			]`,
+		},
+		"paginated": {
+			recorded: `[
+				{"address":"0x000000000000000000000000000000000000000b","topics":[],"data":"0x","blockNumber":"0x10","logIndex":"0x1"}
+			]`,
+		},
+		"missing log": {
+			recorded: `[
+				{"address":"0x000000000000000000000000000000000000000a","topics":["0x0000000000000000000000000000000000000000000000000000000000000001"],"data":"0x01","blockNumber":"0x10","logIndex":"0x0"},
+				{"address":"0x000000000000000000000000000000000000000b","topics":[],"data":"0x","blockNumber":"0x10","logIndex":"0x1"},
+				{"address":"0x000000000000000000000000000000000000000c","topics":["0x0000000000000000000000000000000000000000000000000000000000000002"],"data":"0x03","blockNumber":"0x10","logIndex":"0x2"},
+				{"address":"0x000000000000000000000000000000000000000d","topics":[],"data":"0x","blockNumber":"0x10","logIndex":"0x3"}
+			]`,
+			expected: []string{"1 missing, 0 extra and 0 mismatching logs", "missing log: {block: 16, index: 3"},
+		},
+		"extra log": {
+			recorded: `[
+				{"address":"0x000000000000000000000000000000000000000a","topics":["0x0000000000000000000000000000000000000000000000000000000000000001"],"data":"0x01","blockNumber":"0x10","logIndex":"0x0"},
+				{"address":"0x000000000000000000000000000000000000000c","topics":["0x0000000000000000000000000000000000000000000000000000000000000002"],"data":"0x03","blockNumber":"0x10","logIndex":"0x2"}
+			]`,
+			expected: []string{"0 missing, 1 extra and 0 mismatching logs", "extra log: {block: 16, index: 1"},
+		},
+		"different fields": {
+			recorded: `[
+				{"address":"0x000000000000000000000000000000000000000a","topics":["0x0000000000000000000000000000000000000000000000000000000000000003"],"data":"0x01","blockNumber":"0x10","logIndex":"0x0"},
+				{"address":"0x000000000000000000000000000000000000000e","topics":[],"data":"0x","blockNumber":"0x10","logIndex":"0x1"},
+				{"address":"0x000000000000000000000000000000000000000c","topics":["0x0000000000000000000000000000000000000000000000000000000000000002"],"data":"0x04","blockNumber":"0x10","logIndex":"0x2"}
+			]`,
+			expected: []string{
+				"0 missing, 0 extra and 3 mismatching logs",
+				"log 0 of block 16 has different topics",
+				"log 1 of block 16 has different address",
+				"log 2 of block 16 has different data",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := &rpc.RequestAndResults{
+				Query: &rpc.Body{
+					Method:     "eth_getLogs",
+					MethodBase: "getLogs",
+				},
+				Response: &rpc.Response{
+					Result: json.RawMessage(test.recorded),
+				},
+			}
+
+			err := compareLogs(rpc.NewResult([]byte(carmenLogs), nil, 0), data, 16)
+			if test.expected == nil {
+				assert.Nil(t, err)
+				return
+			}
+			if assert.NotNil(t, err) {
+				assert.Equal(t, noMatchingResult, err.typ)
+				for _, part := range test.expected {
+					assert.Contains(t, err.Error(), part)
+				}
+			}
+		})
+	}
+}
+
+func Test_compareLogsRecordedErrorIsUnexpected(t *testing.T) {
+	data := &rpc.RequestAndResults{
+		Query: &rpc.Body{
+			Method:     "eth_getLogs",
+			MethodBase: "getLogs",
+		},
+		Error: &rpc.ErrorResponse{
+			Error: rpc.ErrorMessage{
+				Code:    -32005,
+				Message: "query returned more than 10000 results",
+			},
+		},
+	}
+
+	err := compareLogs(rpc.NewResult([]byte(carmenLogs), nil, 0), data, 16)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, expectedErrorGotResult, err.typ)
+	}
+}
+
+func Test_compareLogsInvalidRecordingCannotBeUnmarshalled(t *testing.T) {
+	data := &rpc.RequestAndResults{
+		Query: &rpc.Body{
+			Method:     "eth_getLogs",
+			MethodBase: "getLogs",
+		},
+		Response: &rpc.Response{
+			Result: json.RawMessage(`[{"blockNumber":"latest","logIndex":"0x0"}]`),
+		},
+	}
+
+	err := compareLogs(rpc.NewResult([]byte(carmenLogs), nil, 0), data, 16)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, cannotUnmarshalResult, err.typ)
+	}
+}
+
+// receiptTestHash is the hash of the transaction recorded by receiptTestSubstate.
+const receiptTestHash = "0x000000000000000000000000000000000000000000000000000000000000abcd"
+
+// receiptTestSubstate is a transfer at block 0x14 with index 0x3 emitting two logs.
+var receiptTestSubstate = &substate.Substate{
+	Block:       20,
+	Transaction: 3,
+	Message:     &substate.Message{To: &substatetypes.Address{0x2}},
+	Result: substate.NewResult(1, substatetypes.Bloom{}, []*substatetypes.Log{
+		{Address: substatetypes.Address{0xa}, Topics: []substatetypes.Hash{{0x1}}, Data: []byte{0x1}},
+		{Address: substatetypes.Address{0xb}, Topics: []substatetypes.Hash{}, Data: []byte{}},
+	}, substatetypes.Address{}, 21500),
+}
+
+// recordedReceipt returns the receipt of receiptTestSubstate as recorded on the API server, where the
+// logs are the 8th and 9th logs of the block; the replacer may alter the recording.
+func recordedReceipt(replacer *strings.Replacer) string {
+	var bloom types.Bloom
+	bloom.Add(common.Address{0xa}.Bytes())
+	bloom.Add(common.Hash{0x1}.Bytes())
+	bloom.Add(common.Address{0xb}.Bytes())
+
+	receipt := replacer.Replace(`{
+		"transactionHash":"` + receiptTestHash + `","blockNumber":"0x14","transactionIndex":"0x3","status":"0x1","gasUsed":"0x53fc",
+		"cumulativeGasUsed":"0x1a2b3c","contractAddress":null,"logsBloom":"BLOOM",
+		"logs":[
+			{"address":"0x0a00000000000000000000000000000000000000","topics":["0x0100000000000000000000000000000000000000000000000000000000000000"],"data":"0x01",
+			 "blockNumber":"0x14","transactionHash":"` + receiptTestHash + `","transactionIndex":"0x3","logIndex":"0x7","removed":false},
+			{"address":"0x0b00000000000000000000000000000000000000","topics":[],"data":"0x",
+			 "blockNumber":"0x14","transactionHash":"` + receiptTestHash + `","transactionIndex":"0x3","logIndex":"0x8","removed":false}
+		]}`)
+	return strings.ReplaceAll(receipt, "BLOOM", hexutil.Encode(bloom[:]))
+}
+
+func Test_compareTransactionReceipt(t *testing.T) {
+	tests := map[string]struct {
+		replacer *strings.Replacer
+		expected []string // parts of the reported difference, nil if receipts match
+	}{
+		"identical": {
+			replacer: strings.NewReplacer(),
+		},
+		"different status": {
+			replacer: strings.NewReplacer(`"status":"0x1"`, `"status":"0x0"`),
+			expected: []string{"different status: have 0x1, want 0x0"},
+		},
+		"different gas used": {
+			replacer: strings.NewReplacer(`"gasUsed":"0x53fc"`, `"gasUsed":"0x5208"`),
+			expected: []string{"different gasUsed: have 0x53fc, want 0x5208"},
+		},
+		"different log data": {
+			replacer: strings.NewReplacer(`"data":"0x01"`, `"data":"0x02"`),
+			expected: []string{"different data of log 0: have 0x01, want 0x02"},
+		},
+		"log indices with gap": {
+			replacer: strings.NewReplacer(`"logIndex":"0x8"`, `"logIndex":"0x9"`),
+			expected: []string{"different index of log 1: have 1, want 2"},
+		},
+		"missing log": {
+			replacer: strings.NewReplacer(`,
+			{"address":"0x0b00000000000000000000000000000000000000","topics":[],"data":"0x",
+			 "blockNumber":"0x14","transactionHash":"`+receiptTestHash+`","transactionIndex":"0x3","logIndex":"0x8","removed":false}`, ``),
+			expected: []string{"different number of logs: have 2, want 1"},
+		},
+		"different bloom": {
+			replacer: strings.NewReplacer("BLOOM", hexutil.Encode(make([]byte, types.BloomByteLength))),
+			expected: []string{"different logsBloom"},
+		},
+		"created contract": {
+			replacer: strings.NewReplacer(`"contractAddress":null`, `"contractAddress":"0x0c00000000000000000000000000000000000000"`),
+			expected: []string{"different contractAddress: have null, want 0x0c00000000000000000000000000000000000000"},
+		},
+		"different transaction": {
+			replacer: strings.NewReplacer(`"transactionIndex":"0x3","status"`, `"transactionIndex":"0x4","status"`),
+			expected: []string{"different transactionIndex: have 0x3, want 0x4"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := &rpc.RequestAndResults{
+				Query: &rpc.Body{
+					Method:     "eth_getTransactionReceipt",
+					MethodBase: "getTransactionReceipt",
+					Params:     []interface{}{receiptTestHash},
+				},
+				Response: &rpc.Response{
+					Result: json.RawMessage(recordedReceipt(test.replacer)),
+				},
+			}
+			// the substate is located by the unaltered recording
+			located := &rpc.RequestAndResults{Query: data.Query, Response: &rpc.Response{Result: json.RawMessage(recordedReceipt(strings.NewReplacer()))}}
+			result := rpc.ExecuteGetTransactionReceipt(located, receiptTestSubstates{}, 0, 100)
+
+			err := compareTransactionReceipt(result, data, 20)
+			if test.expected == nil {
+				assert.Nil(t, err)
+				return
+			}
+			if assert.NotNil(t, err) {
+				assert.Equal(t, noMatchingResult, err.typ)
+				for _, part := range test.expected {
+					assert.Contains(t, err.Error(), part)
+				}
+			}
+		})
+	}
+}
+
+func Test_compareTransactionReceiptRecordedErrorIsUnexpected(t *testing.T) {
+	data := &rpc.RequestAndResults{
+		Query: &rpc.Body{
+			Method:     "eth_getTransactionReceipt",
+			MethodBase: "getTransactionReceipt",
+		},
+		Error: &rpc.ErrorResponse{
+			Error: rpc.ErrorMessage{
+				Code:    -32000,
+				Message: "unexpected failure",
+			},
+		},
+	}
+
+	err := compareTransactionReceipt(rpc.NewResult([]byte(`{}`), nil, 0), data, 20)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, expectedErrorGotResult, err.typ)
+	}
+}
+
+func TestRPCComparator_UnverifiableRequestIsNotAFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+
+	cfg := &utils.Config{}
+	cfg.Validate = true
+
+	data := &rpc.RequestAndResults{
+		Query: &rpc.Body{
+			Method:     "eth_getTransactionReceipt",
+			MethodBase: "getTransactionReceipt",
+			Params:     []interface{}{receiptTestHash},
+		},
+		Response: &rpc.Response{Result: json.RawMessage(`null`)},
+	}
+	s := executor.State[*rpc.RequestAndResults]{Block: 20, Data: data}
+
+	log.EXPECT().Debugf(gomock.Any(), gomock.Any()).AnyTimes()
+	log.EXPECT().Noticef(gomock.Any(), int64(2))
+
+	c := makeRPCComparator(cfg, log)
+	for range 2 {
+		ctx := &executor.Context{ExecutionResult: rpc.ExecuteGetTransactionReceipt(data, receiptTestSubstates{}, 0, 100)}
+		assert.NoError(t, c.PostTransaction(s, ctx))
+	}
+	assert.NoError(t, c.PostRun(s, &executor.Context{}, nil))
+}
+
+// receiptTestSubstates is a SubstateReader serving receiptTestSubstate.
+type receiptTestSubstates struct{}
+
+func (receiptTestSubstates) HasSubstate(block uint64, tx int) (bool, error) {
+	return block == receiptTestSubstate.Block && tx == receiptTestSubstate.Transaction, nil
+}
+
+func (receiptTestSubstates) GetSubstate(uint64, int) (*substate.Substate, error) {
+	return receiptTestSubstate, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+// MakeSanityValidator creates an extension which checks cheap invariants of every
+// transaction: the nonce of the sender has to match the message and increase by
+// exactly one, and the sender has to afford the gas and value deducted upfront.
+// Violations are counted separately from mismatches reported by the full validation.
+// The checks are enabled unless --skip-sanity-checks is set.
+func MakeSanityValidator(cfg *utils.Config) executor.Extension[txcontext.TxContext] {
+	if cfg.SkipSanityChecks {
+		return extension.NilExtension[txcontext.TxContext]{}
+	}
+
+	log := logger.NewLogger(cfg.LogLevel, "Sanity-Validator")
+
+	return makeSanityValidator(cfg, log)
+}
+
+func makeSanityValidator(cfg *utils.Config, log logger.Logger) *sanityValidator {
+	return &sanityValidator{
+		stateDbValidator: makeStateDbValidator(cfg, log, ValidateTxTarget{}),
+	}
+}
+
+// sanityValidator does not keep any per-transaction state, hence it can be
+// used by parallel workers.
+type sanityValidator struct {
+	*stateDbValidator
+}
+
+// PreRun informs the user how to disable the sanity checks.
+func (v *sanityValidator) PreRun(executor.State[txcontext.TxContext], *executor.Context) error {
+	v.log.Infof("Sanity checks of sender nonces and balances are enabled; use --%v to disable them", utils.SkipSanityChecksFlag.Name)
+	return nil
+}
+
+// PreTransaction checks that the sender nonce matches the message and that
+// the sender can pay for the gas and value of the transaction.
+func (v *sanityValidator) PreTransaction(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	msg := state.Data.GetMessage()
+	if state.Transaction >= utils.PseudoTx || msg == nil {
+		return nil
+	}
+
+	if !msg.SkipNonceChecks {
+		if nonce := ctx.State.GetNonce(msg.From); nonce != msg.Nonce {
+			reason := "nonce too low"
+			if msg.Nonce > nonce {
+				reason = "nonce too high"
+			}
+			return v.report(fmt.Errorf("sanity check failed at block %v tx %v: %v; nonce of sender %v is %v, message expects %v",
+				state.Block, state.Transaction, reason, msg.From, nonce, msg.Nonce), ctx)
+		}
+	}
+
+	required := new(big.Int).SetUint64(msg.GasLimit)
+	if msg.GasPrice != nil {
+		required.Mul(required, msg.GasPrice)
+	} else {
+		required.SetUint64(0)
+	}
+	if msg.Value != nil {
+		required.Add(required, msg.Value)
+	}
+	if balance := ctx.State.GetBalance(msg.From).ToBig(); balance.Cmp(required) < 0 {
+		return v.report(fmt.Errorf("sanity check failed at block %v tx %v: insufficient funds for gas * price + value; balance of sender %v is %v, gas limit %v at price %v plus value %v requires %v",
+			state.Block, state.Transaction, msg.From, balance, msg.GasLimit, msg.GasPrice, msg.Value, required), ctx)
+	}
+	return nil
+}
+
+// PostTransaction checks that the sender nonce was increased by exactly one, or
+// additionally by the authorizations the sender signed for itself.
+func (v *sanityValidator) PostTransaction(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	msg := state.Data.GetMessage()
+	if state.Transaction >= utils.PseudoTx || msg == nil || msg.SkipNonceChecks || ctx.ExecutionResult == nil {
+		return nil
+	}
+	// rejected transactions do not change the state and are reported by the processor
+	if _, err := ctx.ExecutionResult.GetRawResult(); err != nil {
+		return nil
+	}
+
+	want := msg.Nonce + 1
+	maxWant := want + uint64(len(msg.SetCodeAuthorizations))
+	if nonce := ctx.State.GetNonce(msg.From); nonce < want || nonce > maxWant {
+		return v.report(fmt.Errorf("sanity check failed at block %v tx %v: nonce of sender %v is %v after the transaction, expected %v",
+			state.Block, state.Transaction, msg.From, nonce, want), ctx)
+	}
+	return nil
+}
+
+// PostRun reports the number of violations found if the run continued on failure.
+func (v *sanityValidator) PostRun(executor.State[txcontext.TxContext], *executor.Context, error) error {
+	if n := v.numberOfErrors.Load(); n > 0 {
+		v.log.Warningf("Sanity checks found %v violations", n)
+	}
+	return nil
+}
+
+// report passes the violation to the error input or returns it if it is fatal.
+func (v *sanityValidator) report(err error, ctx *executor.Context) error {
+	if v.isErrFatal(err, ctx.ErrorInput) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSanityValidator_NoValidatorIsCreatedIfDisabled(t *testing.T) {
+	ext := MakeSanityValidator(&utils.Config{SkipSanityChecks: true})
+	_, ok := ext.(extension.NilExtension[txcontext.TxContext])
+	assert.True(t, ok)
+}
+
+func TestSanityValidator_IsEnabledByDefault(t *testing.T) {
+	ext := MakeSanityValidator(&utils.Config{})
+	_, ok := ext.(*sanityValidator)
+	assert.True(t, ok)
+}
+
+// prepareSanityTx prepares a transaction of sender with given nonce, gas limit 10 at price 2 and value 100.
+func prepareSanityTx(ctrl *gomock.Controller, sender common.Address, nonce uint64) (txcontext.TxContext, *core.Message) {
+	msg := &core.Message{From: sender, Nonce: nonce, GasLimit: 10, GasPrice: big.NewInt(2), Value: big.NewInt(100)}
+	data := txcontext.NewMockTxContext(ctrl)
+	data.EXPECT().GetMessage().Return(msg).AnyTimes()
+	return data, msg
+}
+
+func TestSanityValidator_AcceptsConsistentTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	result := txcontext.NewMockResult(ctrl)
+	sender := common.Address{1}
+
+	data, _ := prepareSanityTx(ctrl, sender, 5)
+	gomock.InOrder(
+		db.EXPECT().GetNonce(sender).Return(uint64(5)),
+		db.EXPECT().GetBalance(sender).Return(uint256.NewInt(120)),
+		result.EXPECT().GetRawResult().Return(nil, nil),
+		db.EXPECT().GetNonce(sender).Return(uint64(6)),
+	)
+
+	ctx := &executor.Context{State: db, ExecutionResult: result}
+	st := executor.State[txcontext.TxContext]{Block: 1, Transaction: 2, Data: data}
+
+	ext := makeSanityValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	require.NoError(t, ext.PreTransaction(st, ctx))
+	require.NoError(t, ext.PostTransaction(st, ctx))
+}
+
+func TestSanityValidator_DetectsNonceGapBeforeTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	sender := common.Address{1}
+
+	data, _ := prepareSanityTx(ctrl, sender, 5)
+	db.EXPECT().GetNonce(sender).Return(uint64(3))
+
+	ctx := &executor.Context{State: db}
+	st := executor.State[txcontext.TxContext]{Block: 1, Transaction: 2, Data: data}
+
+	ext := makeSanityValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	err := ext.PreTransaction(st, ctx)
+	require.ErrorContains(t, err, "sanity check failed at block 1 tx 2: nonce too high")
+	assert.ErrorContains(t, err, sender.String())
+}
+
+func TestSanityValidator_SkipsNonceCheckIfDisabledByMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	sender := common.Address{1}
+
+	data, msg := prepareSanityTx(ctrl, sender, 5)
+	msg.SkipNonceChecks = true
+	db.EXPECT().GetBalance(sender).Return(uint256.NewInt(120))
+
+	ctx := &executor.Context{State: db, ExecutionResult: txcontext.NewMockResult(ctrl)}
+	st := executor.State[txcontext.TxContext]{Block: 1, Transaction: 2, Data: data}
+
+	ext := makeSanityValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	require.NoError(t, ext.PreTransaction(st, ctx))
+	require.NoError(t, ext.PostTransaction(st, ctx))
+}
+
+func TestSanityValidator_DetectsBalanceUnderflow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	sender := common.Address{1}
+
+	data, _ := prepareSanityTx(ctrl, sender, 5)
+	db.EXPECT().GetNonce(sender).Return(uint64(5))
+	db.EXPECT().GetBalance(sender).Return(uint256.NewInt(119))
+
+	ctx := &executor.Context{State: db}
+	st := executor.State[txcontext.TxContext]{Block: 1, Transaction: 2, Data: data}
+
+	ext := makeSanityValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	err := ext.PreTransaction(st, ctx)
+	require.ErrorContains(t, err, "insufficient funds for gas * price + value")
+	assert.ErrorContains(t, err, "requires 120")
+}
+
+func TestSanityValidator_DetectsNonceNotIncreasedByOne(t *testing.T) {
+	tests := map[string]struct {
+		post           uint64
+		authorizations int
+		wantErr        bool
+	}{
+		"unchanged":             {post: 5, wantErr: true},
+		"increased by one":      {post: 6},
+		"increased by two":      {post: 7, wantErr: true},
+		"self authorization":    {post: 7, authorizations: 1},
+		"beyond authorizations": {post: 8, authorizations: 1, wantErr: true},
+		"decreased":             {post: 4, wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := state.NewMockStateDB(ctrl)
+			result := txcontext.NewMockResult(ctrl)
+			sender := common.Address{1}
+
+			data, msg := prepareSanityTx(ctrl, sender, 5)
+			msg.SetCodeAuthorizations = make([]types.SetCodeAuthorization, test.authorizations)
+			result.EXPECT().GetRawResult().Return(nil, nil)
+			db.EXPECT().GetNonce(sender).Return(test.post)
+
+			ctx := &executor.Context{State: db, ExecutionResult: result}
+			st := executor.State[txcontext.TxContext]{Block: 1, Transaction: 2, Data: data}
+
+			ext := makeSanityValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+			err := ext.PostTransaction(st, ctx)
+			if test.wantErr {
+				require.ErrorContains(t, err, "after the transaction, expected 6")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSanityValidator_IgnoresRejectedTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	result := txcontext.NewMockResult(ctrl)
+	data, _ := prepareSanityTx(ctrl, common.Address{1}, 5)
+	result.EXPECT().GetRawResult().Return(nil, errors.New("intrinsic gas too low"))
+
+	ctx := &executor.Context{ExecutionResult: result}
+	st := executor.State[txcontext.TxContext]{Block: 1, Transaction: 2, Data: data}
+
+	ext := makeSanityValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	require.NoError(t, ext.PostTransaction(st, ctx))
+}
+
+func TestSanityValidator_IgnoresPseudoTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	data, _ := prepareSanityTx(ctrl, common.Address{1}, 5)
+
+	ctx := &executor.Context{}
+	st := executor.State[txcontext.TxContext]{Block: 1, Transaction: utils.PseudoTx, Data: data}
+
+	ext := makeSanityValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	require.NoError(t, ext.PreTransaction(st, ctx))
+	require.NoError(t, ext.PostTransaction(st, ctx))
+}
+
+func TestSanityValidator_CountsViolationsOnContinueOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+	sender := common.Address{1}
+
+	data, _ := prepareSanityTx(ctrl, sender, 5)
+	db.EXPECT().GetNonce(sender).Return(uint64(5))
+	db.EXPECT().GetBalance(sender).Return(uint256.NewInt(0))
+	log.EXPECT().Warningf("Sanity checks found %v violations", int32(1))
+
+	errs := make(chan error, 1)
+	ctx := &executor.Context{State: db, ErrorInput: errs}
+	st := executor.State[txcontext.TxContext]{Block: 1, Transaction: 2, Data: data}
+
+	ext := makeSanityValidator(&utils.Config{ContinueOnFailure: true}, log)
+	require.NoError(t, ext.PreTransaction(st, ctx))
+	require.ErrorContains(t, <-errs, "insufficient funds")
+	require.NoError(t, ext.PostRun(st, ctx, nil))
+}
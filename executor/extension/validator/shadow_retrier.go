@@ -0,0 +1,199 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"fmt"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+// makeShadowDbRetrier creates a shadow DB validator which does not give up on the first divergence.
+// A diverged block is rolled back on both DBs by re-priming the accounts it touched with their
+// pre-block values and re-executed. The run fails only if the divergence reproduces in every retry.
+// If processor is nil, a LiveDb transaction processor is created on PreRun.
+func makeShadowDbRetrier(cfg *utils.Config, log logger.Logger, processor executor.Processor[txcontext.TxContext]) executor.Extension[txcontext.TxContext] {
+	return &shadowDbRetrier{
+		shadowDbValidator: shadowDbValidator{cfg: cfg},
+		log:               log,
+		processor:         processor,
+	}
+}
+
+type shadowDbRetrier struct {
+	shadowDbValidator
+	log          logger.Logger
+	processor    executor.Processor[txcontext.TxContext]
+	transactions []executor.State[txcontext.TxContext] // transactions of the current block
+
+	// counters for the summary
+	retriedBlocks     int
+	transientBlocks   int
+	reproducedBlocks  int
+	totalReExecutions int
+}
+
+func (r *shadowDbRetrier) PreRun(executor.State[txcontext.TxContext], *executor.Context) error {
+	if r.cfg.ArchiveMode {
+		return fmt.Errorf("--%v cannot be used in archive mode; archived blocks cannot be re-executed", utils.ShadowRetryFlag.Name)
+	}
+	if r.processor != nil {
+		return nil
+	}
+	processor, err := executor.MakeLiveDbTxProcessor(r.cfg)
+	if err != nil {
+		return fmt.Errorf("cannot create processor for block retries; %w", err)
+	}
+	r.processor = processor
+	return nil
+}
+
+func (r *shadowDbRetrier) PreBlock(executor.State[txcontext.TxContext], *executor.Context) error {
+	r.transactions = r.transactions[:0]
+	return nil
+}
+
+// PreTransaction records the transaction, so that the block can be re-executed.
+func (r *shadowDbRetrier) PreTransaction(state executor.State[txcontext.TxContext], _ *executor.Context) error {
+	r.transactions = append(r.transactions, state)
+	return nil
+}
+
+func (r *shadowDbRetrier) PostBlock(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	err := r.shadowDbValidator.PostBlock(state, ctx)
+	if err == nil {
+		return nil
+	}
+
+	r.retriedBlocks++
+	for attempt := 1; attempt <= r.cfg.ShadowRetry; attempt++ {
+		r.log.Warningf("Block %d: prime and shadow DB diverged, re-executing block (attempt %d/%d); %v", state.Block, attempt, r.cfg.ShadowRetry, err)
+		r.totalReExecutions++
+		if retryErr := r.reExecuteBlock(state.Block, ctx); retryErr != nil {
+			return fmt.Errorf("cannot re-execute block %d; %w", state.Block, retryErr)
+		}
+		err = r.shadowDbValidator.PostBlock(state, ctx)
+		if err == nil {
+			r.transientBlocks++
+			r.log.Noticef("Block %d: divergence did not reproduce in attempt %d, continuing", state.Block, attempt)
+			return nil
+		}
+	}
+
+	r.reproducedBlocks++
+	r.log.Errorf("Block %d: divergence reproduced in all %d attempts", state.Block, r.cfg.ShadowRetry)
+	return err
+}
+
+func (r *shadowDbRetrier) PostRun(executor.State[txcontext.TxContext], *executor.Context, error) error {
+	r.log.Noticef("Shadow retries: %d diverged blocks, %d transient, %d reproduced; %d block re-executions",
+		r.retriedBlocks, r.transientBlocks, r.reproducedBlocks, r.totalReExecutions)
+	return nil
+}
+
+// reExecuteBlock rolls back the block on both DBs and executes its transactions again.
+func (r *shadowDbRetrier) reExecuteBlock(block int, ctx *executor.Context) error {
+	db := ctx.State
+	// divergences of the previous attempt must not be reported again
+	_ = db.Error()
+
+	if err := db.BeginBlock(uint64(block)); err != nil {
+		return fmt.Errorf("cannot begin block; %w", err)
+	}
+	if err := db.BeginTransaction(utils.PseudoTx); err != nil {
+		return fmt.Errorf("cannot begin roll-back transaction; %w", err)
+	}
+	utils.OverwriteStateDB(getPreBlockState(r.transactions), db)
+	if err := db.EndTransaction(); err != nil {
+		return fmt.Errorf("cannot end roll-back transaction; %w", err)
+	}
+
+	for _, tx := range r.transactions {
+		if err := db.BeginTransaction(uint32(tx.Transaction)); err != nil {
+			return fmt.Errorf("cannot begin transaction %d; %w", tx.Transaction, err)
+		}
+		if err := r.processor.Process(tx, &executor.Context{State: db, ErrorInput: ctx.ErrorInput}); err != nil {
+			return fmt.Errorf("cannot process transaction %d; %w", tx.Transaction, err)
+		}
+		if err := db.EndTransaction(); err != nil {
+			return fmt.Errorf("cannot end transaction %d; %w", tx.Transaction, err)
+		}
+	}
+
+	if err := db.EndBlock(); err != nil {
+		return fmt.Errorf("cannot end block; %w", err)
+	}
+	return nil
+}
+
+// preBlockAccount collects the pre-block values of an account touched by a block.
+type preBlockAccount struct {
+	code    []byte
+	balance *uint256.Int
+	nonce   uint64
+	storage map[common.Hash]common.Hash
+}
+
+// getPreBlockState reconstructs the state of all accounts touched by given transactions before
+// their block from the input substates. Each value is taken from the first transaction accessing
+// it, later transactions observe values modified within the block. Accounts and storage slots
+// which do not exist before the block are reset to empty values.
+func getPreBlockState(transactions []executor.State[txcontext.TxContext]) txcontext.WorldState {
+	accounts := make(map[common.Address]*preBlockAccount)
+	for _, tx := range transactions {
+		tx.Data.GetInputState().ForEachAccount(func(addr common.Address, acc txcontext.Account) {
+			pre, found := accounts[addr]
+			if !found {
+				pre = &preBlockAccount{
+					code:    acc.GetCode(),
+					balance: acc.GetBalance(),
+					nonce:   acc.GetNonce(),
+					storage: make(map[common.Hash]common.Hash),
+				}
+				accounts[addr] = pre
+			}
+			acc.ForEachStorage(func(key common.Hash, value common.Hash) {
+				if _, found := pre.storage[key]; !found {
+					pre.storage[key] = value
+				}
+			})
+		})
+		tx.Data.GetOutputState().ForEachAccount(func(addr common.Address, acc txcontext.Account) {
+			pre, found := accounts[addr]
+			if !found {
+				pre = &preBlockAccount{balance: new(uint256.Int), storage: make(map[common.Hash]common.Hash)}
+				accounts[addr] = pre
+			}
+			acc.ForEachStorage(func(key common.Hash, _ common.Hash) {
+				if _, found := pre.storage[key]; !found {
+					pre.storage[key] = common.Hash{}
+				}
+			})
+		})
+	}
+
+	ws := make(map[common.Address]txcontext.Account, len(accounts))
+	for addr, pre := range accounts {
+		ws[addr] = txcontext.NewAccount(pre.code, pre.storage, pre.balance.ToBig(), pre.nonce)
+	}
+	return txcontext.NewWorldState(ws)
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestShadowDbRetrier_MakeShadowDbValidatorCreatesRetrier(t *testing.T) {
+	ext := MakeShadowDbValidator(&utils.Config{ShadowDb: true, ShadowRetry: 2})
+	_, ok := ext.(*shadowDbRetrier)
+	assert.True(t, ok)
+}
+
+func TestShadowDbRetrier_PreRunRejectsArchiveMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ext := makeShadowDbRetrier(&utils.Config{ShadowRetry: 1, ArchiveMode: true}, logger.NewMockLogger(ctrl), executor.NewMockProcessor[txcontext.TxContext](ctrl))
+	err := ext.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{})
+	require.ErrorContains(t, err, "cannot be used in archive mode")
+}
+
+func TestShadowDbRetrier_TransientDivergenceIsRetried(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+	processor := executor.NewMockProcessor[txcontext.TxContext](ctrl)
+	data := newEmptyTxContext(ctrl)
+
+	ext := makeShadowDbRetrier(&utils.Config{ShadowRetry: 2}, log, processor)
+	ctx := &executor.Context{State: db}
+	st := executor.State[txcontext.TxContext]{Block: 5, Transaction: 3, Data: data}
+	mismatch := errors.New("hash mismatch")
+
+	gomock.InOrder(
+		db.EXPECT().GetHash().Return(common.Hash{}, mismatch),
+		log.EXPECT().Warningf(gomock.Any(), 5, 1, 2, mismatch),
+		db.EXPECT().Error().Return(nil),
+		db.EXPECT().BeginBlock(uint64(5)),
+		db.EXPECT().BeginTransaction(uint32(utils.PseudoTx)),
+		db.EXPECT().EndTransaction(),
+		db.EXPECT().BeginTransaction(uint32(3)),
+		processor.EXPECT().Process(st, gomock.Any()),
+		db.EXPECT().EndTransaction(),
+		db.EXPECT().EndBlock(),
+		db.EXPECT().GetHash(),
+		db.EXPECT().Error().Return(nil),
+		log.EXPECT().Noticef(gomock.Any(), 5, 1),
+		log.EXPECT().Noticef(gomock.Any(), 1, 1, 0, 1),
+	)
+
+	require.NoError(t, ext.PreBlock(st, ctx))
+	require.NoError(t, ext.PreTransaction(st, ctx))
+	require.NoError(t, ext.PostBlock(st, ctx))
+	require.NoError(t, ext.PostRun(st, ctx, nil))
+}
+
+func TestShadowDbRetrier_ReproducedDivergenceFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+	processor := executor.NewMockProcessor[txcontext.TxContext](ctrl)
+
+	ext := makeShadowDbRetrier(&utils.Config{ShadowRetry: 2}, log, processor)
+	ctx := &executor.Context{State: db}
+	st := executor.State[txcontext.TxContext]{Block: 5}
+	mismatch := errors.New("hash mismatch")
+
+	db.EXPECT().GetHash().Return(common.Hash{}, mismatch).Times(3)
+	db.EXPECT().Error().Return(nil).Times(2)
+	db.EXPECT().BeginBlock(uint64(5)).Times(2)
+	db.EXPECT().BeginTransaction(uint32(utils.PseudoTx)).Times(2)
+	db.EXPECT().EndTransaction().Times(2)
+	db.EXPECT().EndBlock().Times(2)
+	log.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(2)
+	log.EXPECT().Errorf(gomock.Any(), 5, 2)
+
+	require.NoError(t, ext.PreBlock(st, ctx))
+	require.ErrorIs(t, ext.PostBlock(st, ctx), mismatch)
+}
+
+func TestShadowDbRetrier_PassingBlockIsNotRetried(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	ext := makeShadowDbRetrier(&utils.Config{ShadowRetry: 2}, logger.NewMockLogger(ctrl), executor.NewMockProcessor[txcontext.TxContext](ctrl))
+
+	db.EXPECT().GetHash()
+	db.EXPECT().Error().Return(nil)
+
+	require.NoError(t, ext.PostBlock(executor.State[txcontext.TxContext]{Block: 1}, &executor.Context{State: db}))
+}
+
+func TestShadowDbRetrier_GetPreBlockStateTakesFirstObservedValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	addr, created := common.Address{1}, common.Address{2}
+	first := txcontext.NewMockTxContext(ctrl)
+	first.EXPECT().GetInputState().Return(txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		addr: txcontext.NewAccount([]byte{1}, map[common.Hash]common.Hash{{1}: {1}}, big.NewInt(10), 1),
+	}))
+	first.EXPECT().GetOutputState().Return(txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		addr:    txcontext.NewAccount([]byte{1}, map[common.Hash]common.Hash{{1}: {2}}, big.NewInt(5), 2),
+		created: txcontext.NewAccount(nil, map[common.Hash]common.Hash{{3}: {3}}, big.NewInt(5), 0),
+	}))
+	second := txcontext.NewMockTxContext(ctrl)
+	second.EXPECT().GetInputState().Return(txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		addr:    txcontext.NewAccount([]byte{1}, map[common.Hash]common.Hash{{1}: {2}, {2}: {7}}, big.NewInt(5), 2),
+		created: txcontext.NewAccount(nil, map[common.Hash]common.Hash{{3}: {3}}, big.NewInt(5), 0),
+	}))
+	second.EXPECT().GetOutputState().Return(txcontext.NewWorldState(map[common.Address]txcontext.Account{}))
+
+	ws := getPreBlockState([]executor.State[txcontext.TxContext]{{Data: first}, {Data: second}})
+
+	acc := ws.Get(addr)
+	require.NotNil(t, acc)
+	assert.Equal(t, uint64(10), acc.GetBalance().Uint64())
+	assert.Equal(t, uint64(1), acc.GetNonce())
+	assert.Equal(t, common.Hash{1}, acc.GetStorageAt(common.Hash{1}))
+	assert.Equal(t, common.Hash{7}, acc.GetStorageAt(common.Hash{2}))
+
+	acc = ws.Get(created)
+	require.NotNil(t, acc)
+	assert.True(t, acc.GetBalance().IsZero())
+	assert.Equal(t, common.Hash{}, acc.GetStorageAt(common.Hash{3}))
+	assert.True(t, acc.HasStorageAt(common.Hash{3}))
+}
+
+func newEmptyTxContext(ctrl *gomock.Controller) txcontext.TxContext {
+	data := txcontext.NewMockTxContext(ctrl)
+	data.EXPECT().GetInputState().Return(txcontext.NewWorldState(map[common.Address]txcontext.Account{})).AnyTimes()
+	data.EXPECT().GetOutputState().Return(txcontext.NewWorldState(map[common.Address]txcontext.Account{})).AnyTimes()
+	return data
+}
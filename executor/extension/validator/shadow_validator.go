@@ -19,11 +19,15 @@ package validator
 import (
 	"github.com/0xsoniclabs/aida/executor"
 	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/txcontext"
 	"github.com/0xsoniclabs/aida/utils"
 )
 
 func MakeShadowDbValidator(cfg *utils.Config) executor.Extension[txcontext.TxContext] {
+	if cfg.ShadowDb && cfg.ShadowRetry > 0 {
+		return makeShadowDbRetrier(cfg, logger.NewLogger(cfg.LogLevel, "Shadow-Retrier"), nil)
+	}
 	if cfg.ShadowDb {
 		return makeShadowDbValidator(cfg)
 	}
@@ -19,6 +19,7 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/0xsoniclabs/aida/executor"
@@ -43,7 +44,20 @@ func MakeStateHashValidator[T any](cfg *utils.Config) executor.Extension[T] {
 }
 
 func makeStateHashValidator[T any](cfg *utils.Config, log logger.Logger) *stateHashValidator[T] {
-	return &stateHashValidator[T]{cfg: cfg, log: log, nextArchiveBlockToCheck: int(cfg.First)}
+	v := &stateHashValidator[T]{
+		cfg:                     cfg,
+		log:                     log,
+		nextArchiveBlockToCheck: int(cfg.First),
+		interval:                max(cfg.StateHashInterval, 1),
+		truncatedBlock:          -1,
+	}
+	// Priming may produce a different intermediate representation of the state,
+	// hence the hashes of the first blocks after priming may differ although the
+	// logical state matches.
+	if !cfg.SkipPriming && cfg.First > 0 {
+		v.blocksToSkip = cfg.StateHashSkipFirst
+	}
+	return v
 }
 
 type stateHashValidator[T any] struct {
@@ -52,8 +66,12 @@ type stateHashValidator[T any] struct {
 	log                     logger.Logger
 	nextArchiveBlockToCheck int
 	lastProcessedBlock      int
+	blocksToSkip            int // number of blocks after priming left to skip
+	interval                int // only blocks divisible by the interval are compared
 	hashProvider            db.HashProvider
 	sdb                     db.SubstateDB // substate db pointer
+	txCount                 atomic.Int64  // number of executed transactions, only counted with --max-tx
+	truncatedBlock          int           // block cut off by --max-tx, -1 if none
 }
 
 func (v *stateHashValidator[T]) PreRun(_ executor.State[T], ctx *executor.Context) error {
@@ -88,7 +106,17 @@ func (v *stateHashValidator[T]) PreRun(_ executor.State[T], ctx *executor.Contex
 		}
 	}
 
-	v.hashProvider = db.MakeHashProvider(ctx.AidaDb)
+	// this condition is added for setting hashProvider in testing.
+	if v.hashProvider == nil {
+		v.hashProvider = db.MakeHashProvider(ctx.AidaDb)
+	}
+	return nil
+}
+
+func (v *stateHashValidator[T]) PostTransaction(state executor.State[T], _ *executor.Context) error {
+	if v.cfg.MaxNumTransactions > 0 && state.Transaction < utils.PseudoTx {
+		v.txCount.Add(1)
+	}
 	return nil
 }
 
@@ -97,25 +125,43 @@ func (v *stateHashValidator[T]) PostBlock(state executor.State[T], ctx *executor
 		return nil
 	}
 
-	want, err := v.getStateHash(state.Block)
-	if err != nil {
-		return err
+	// The run stops within the block reaching the transaction limit, hence
+	// its state is likely incomplete and not to be compared.
+	if v.cfg.MaxNumTransactions > 0 && v.truncatedBlock < 0 && v.txCount.Load() >= int64(v.cfg.MaxNumTransactions) {
+		v.truncatedBlock = state.Block
+		v.log.Warningf("Skipping state hash validation of block %d; the block may be incomplete due to the transaction limit (--%v)",
+			state.Block, utils.MaxNumTransactionsFlag.Name)
 	}
 
-	// NOTE: ContinueOnFailure does not make sense here, if hash does not
-	// match every block after this block would have different hash
-	got, err := ctx.State.GetHash()
-	if err != nil {
-		return fmt.Errorf("cannot get state hash; %w", err)
+	if v.blocksToSkip > 0 {
+		v.blocksToSkip--
+		v.log.Warningf("Skipping state hash validation of block %d; the hashes of the first %d block(s) after priming are not compared (--%v)",
+			state.Block, v.cfg.StateHashSkipFirst, utils.ValidateStateHashesSkipFirstFlag.Name)
+		v.nextArchiveBlockToCheck = max(v.nextArchiveBlockToCheck, state.Block+1)
+		return nil
 	}
-	if want != got {
-		return fmt.Errorf("unexpected hash for Live block %d\nwanted %v\n   got %v", state.Block, want, got)
+
+	if v.isCompared(state.Block) {
+		want, err := v.getStateHash(state.Block)
+		if err != nil {
+			return err
+		}
+
+		// NOTE: ContinueOnFailure does not make sense here, if hash does not
+		// match every block after this block would have different hash
+		got, err := ctx.State.GetHash()
+		if err != nil {
+			return fmt.Errorf("cannot get state hash; %w", err)
+		}
+		if want != got {
+			return fmt.Errorf("unexpected hash for Live block %d\nwanted %v\n   got %v", state.Block, want, got)
+		}
 	}
 
 	// Check the ArchiveDB
 	if v.cfg.ArchiveMode {
 		v.lastProcessedBlock = state.Block
-		if err = v.checkArchiveHashes(ctx.State, ctx.AidaDb); err != nil {
+		if err := v.checkArchiveHashes(ctx.State, ctx.AidaDb); err != nil {
 			return err
 		}
 	}
@@ -123,6 +169,11 @@ func (v *stateHashValidator[T]) PostBlock(state executor.State[T], ctx *executor
 	return nil
 }
 
+// isCompared reports whether the hash of the given block is to be compared.
+func (v *stateHashValidator[T]) isCompared(block int) bool {
+	return block%v.interval == 0 && block != v.truncatedBlock
+}
+
 func (v *stateHashValidator[T]) PostRun(_ executor.State[T], ctx *executor.Context, err error) error {
 	// Skip processing if run is aborted due to an error.
 	if err != nil {
@@ -153,6 +204,11 @@ func (v *stateHashValidator[T]) checkArchiveHashes(state state.StateDB, aidaDb d
 
 	cur := uint64(v.nextArchiveBlockToCheck)
 	for !empty && cur <= height {
+		if !v.isCompared(int(cur)) {
+			cur++
+			continue
+		}
+
 		want, err := v.getStateHash(int(cur))
 		if err != nil {
 			return err
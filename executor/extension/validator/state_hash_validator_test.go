@@ -513,3 +513,159 @@ func TestStateHashValidator_CheckArchiveHashesBlocksReturnsError(t *testing.T) {
 		})
 	}
 }
+
+// runStateHashValidation streams blocks 10 to 13 through an executor validating
+// state hashes, where the state hash of the first block differs from the recorded
+// one although the logical state matches, as it may happen after priming.
+func runStateHashValidation(t *testing.T, cfg *utils.Config, log logger.Logger) error {
+	ctrl := gomock.NewController(t)
+	provider := executor.NewMockProvider[any](ctrl)
+	processor := executor.NewMockProcessor[any](ctrl)
+	db := state.NewMockStateDB(ctrl)
+	hashProvider := substateDb.NewMockHashProvider(ctrl)
+
+	provider.EXPECT().
+		Run(10, 14, gomock.Any()).
+		DoAndReturn(func(from int, to int, consume executor.Consumer[any]) error {
+			for i := from; i < to; i++ {
+				if err := consume(executor.TransactionInfo[any]{Block: i, Transaction: 0}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	current := 0
+	processor.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(st executor.State[any], _ *executor.Context) error {
+		current = st.Block
+		return nil
+	}).AnyTimes()
+
+	hashProvider.EXPECT().GetStateRootHash(gomock.Any()).Return(types.Hash(common.HexToHash(exampleHashA)), nil).AnyTimes()
+	db.EXPECT().GetHash().DoAndReturn(func() (common.Hash, error) {
+		if current == 10 {
+			return common.HexToHash(exampleHashB), nil
+		}
+		return common.HexToHash(exampleHashA), nil
+	}).AnyTimes()
+
+	ext := makeStateHashValidator[any](cfg, log)
+	ext.hashProvider = hashProvider
+
+	return executor.NewExecutor[any](provider, "CRITICAL").Run(
+		executor.Params{From: 10, To: 14, State: db},
+		processor,
+		[]executor.Extension[any]{ext},
+		nil,
+	)
+}
+
+func TestStateHashValidator_SkipsFirstBlockAfterPrimingByDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{DbImpl: "geth", First: 10, StateHashSkipFirst: 1}
+
+	log.EXPECT().Warningf(gomock.Any(), 10, 1, utils.ValidateStateHashesSkipFirstFlag.Name)
+	require.NoError(t, runStateHashValidation(t, cfg, log))
+}
+
+func TestStateHashValidator_FailsOnFirstBlockIfSkippingIsDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{DbImpl: "geth", First: 10, StateHashSkipFirst: 0}
+
+	err := runStateHashValidation(t, cfg, log)
+	require.ErrorContains(t, err, "unexpected hash for Live block 10")
+}
+
+func TestStateHashValidator_DoesNotSkipWithoutPriming(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cfg := &utils.Config{DbImpl: "geth", First: 10, StateHashSkipFirst: 1, SkipPriming: true}
+
+	err := runStateHashValidation(t, cfg, log)
+	require.ErrorContains(t, err, "unexpected hash for Live block 10")
+}
+
+func TestStateHashValidator_ComparesOnlyEveryNthBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	db := state.NewMockStateDB(ctrl)
+	hashProvider := substateDb.NewMockHashProvider(ctrl)
+
+	cfg := &utils.Config{DbImpl: "geth", StateHashInterval: 4}
+	ext := makeStateHashValidator[any](cfg, log)
+	ext.hashProvider = hashProvider
+
+	for _, block := range []int{4, 8} {
+		hashProvider.EXPECT().GetStateRootHash(block).Return(types.Hash(common.HexToHash(exampleHashA)), nil)
+	}
+	db.EXPECT().GetHash().Return(common.HexToHash(exampleHashA), nil).Times(2)
+
+	ctx := &executor.Context{State: db}
+	for block := 3; block <= 10; block++ {
+		require.NoError(t, ext.PostBlock(executor.State[any]{Block: block}, ctx))
+	}
+}
+
+func TestStateHashValidator_ComparesOnlyEveryNthArchiveBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+	hashProvider := substateDb.NewMockHashProvider(ctrl)
+
+	cfg := &utils.Config{DbImpl: "geth", ArchiveMode: true, StateHashInterval: 2}
+	ext := makeStateHashValidator[any](cfg, log)
+	ext.hashProvider = hashProvider
+
+	db.EXPECT().GetArchiveBlockHeight().Return(uint64(5), false, nil)
+	for _, block := range []uint64{0, 2, 4} {
+		hashProvider.EXPECT().GetStateRootHash(int(block)).Return(types.Hash(common.HexToHash(exampleHashA)), nil)
+		db.EXPECT().GetArchiveState(block).Return(archive, nil)
+	}
+	archive.EXPECT().GetHash().Return(common.HexToHash(exampleHashA), nil).Times(3)
+	archive.EXPECT().Release().Return(nil).Times(3)
+
+	// block 5 itself is not compared in the live state
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 5}, &executor.Context{State: db}))
+}
+
+func TestStateHashValidator_SkippedBlocksAreNotCheckedInArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	db := state.NewMockStateDB(ctrl)
+
+	cfg := &utils.Config{DbImpl: "geth", ArchiveMode: true, First: 10, StateHashSkipFirst: 2}
+	ext := makeStateHashValidator[any](cfg, log)
+
+	log.EXPECT().Warningf(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+	ctx := &executor.Context{State: db}
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 10}, ctx))
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 11}, ctx))
+	require.Equal(t, 12, ext.nextArchiveBlockToCheck)
+}
+
+func TestStateHashValidator_SkipsBlockTruncatedByTransactionLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	db := state.NewMockStateDB(ctrl)
+	hashProvider := substateDb.NewMockHashProvider(ctrl)
+
+	cfg := &utils.Config{DbImpl: "geth", MaxNumTransactions: 3}
+	ext := makeStateHashValidator[any](cfg, log)
+	ext.hashProvider = hashProvider
+
+	hashProvider.EXPECT().GetStateRootHash(1).Return(types.Hash(common.HexToHash(exampleHashA)), nil)
+	db.EXPECT().GetHash().Return(common.HexToHash(exampleHashA), nil)
+	log.EXPECT().Warningf(gomock.Any(), 2, utils.MaxNumTransactionsFlag.Name)
+
+	ctx := &executor.Context{State: db}
+	require.NoError(t, ext.PostTransaction(executor.State[any]{Block: 1, Transaction: 0}, ctx))
+	require.NoError(t, ext.PostTransaction(executor.State[any]{Block: 1, Transaction: 1}, ctx))
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 1}, ctx))
+	// pseudo transactions are not counted
+	require.NoError(t, ext.PostTransaction(executor.State[any]{Block: 2, Transaction: utils.PseudoTx}, ctx))
+	require.NoError(t, ext.PostTransaction(executor.State[any]{Block: 2, Transaction: 0}, ctx))
+	require.NoError(t, ext.PostBlock(executor.State[any]{Block: 2}, ctx))
+	require.False(t, ext.isCompared(2))
+}
@@ -18,6 +18,9 @@ package validator
 
 import (
 	"fmt"
+	"math/big"
+	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/0xsoniclabs/aida/executor"
@@ -26,6 +29,8 @@ import (
 	"github.com/0xsoniclabs/aida/state"
 	"github.com/0xsoniclabs/aida/txcontext"
 	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
 )
 
 // MakeLiveDbValidator creates an extension which validates LIVE StateDb
@@ -99,6 +104,8 @@ func makeStateDbValidator(cfg *utils.Config, log logger.Logger, target ValidateT
 		log:            log,
 		numberOfErrors: new(atomic.Int32),
 		target:         target,
+		skipped:        make(map[skippedTx]struct{}),
+		coinbases:      make(map[skippedTx]*uint256.Int),
 	}
 }
 
@@ -108,6 +115,20 @@ type stateDbValidator struct {
 	log            logger.Logger
 	numberOfErrors *atomic.Int32
 	target         ValidateTxTarget
+
+	skippedMutex sync.Mutex
+	skipped      map[skippedTx]struct{} // transactions with mismatches skipped due to --skip-failed-tx
+	skippedOrder []skippedTx
+
+	relaxed       bool         // the fee recipient is compared by its balance delta only
+	tolerance     *uint256.Int // tolerated difference of the fee recipient's balance delta
+	coinbaseMutex sync.Mutex
+	coinbases     map[skippedTx]*uint256.Int // balances of fee recipients before their transaction in relaxed mode
+}
+
+// skippedTx identifies a transaction skipped due to a validation mismatch.
+type skippedTx struct {
+	block, tx int
 }
 
 // ValidateTxTarget serves for the validator to determine what type of validation to run
@@ -120,14 +141,51 @@ type ValidateTxTarget struct {
 func (v *stateDbValidator) PreRun(executor.State[txcontext.TxContext], *executor.Context) error {
 	v.log.Warning("Transaction verification is enabled, this may slow down the block processing.")
 
+	switch v.cfg.TxStateValidation {
+	case "", utils.StrictTxStateValidation:
+	case utils.RelaxedTxStateValidation:
+		tolerance, err := uint256.FromDecimal(v.cfg.CoinbaseTolerance)
+		if err != nil {
+			return fmt.Errorf("cannot parse --%v %q; %w", utils.CoinbaseToleranceFlag.Name, v.cfg.CoinbaseTolerance, err)
+		}
+		v.relaxed = true
+		v.tolerance = tolerance
+		v.log.Warningf("Relaxed world-state validation is enabled, the balance delta of fee recipients may differ by up to %v wei.", tolerance)
+	default:
+		return fmt.Errorf("unknown --%v %q; must be one of %v or %v", utils.TxStateValidationFlag.Name, v.cfg.TxStateValidation, utils.StrictTxStateValidation, utils.RelaxedTxStateValidation)
+	}
+
 	if v.cfg.ContinueOnFailure {
 		v.log.Warningf("Continue on Failure for transaction validation is enabled, yet "+
 			"block processing will stop after %v encountered issues. (0 is endless)", v.cfg.MaxNumErrors)
 	}
 
+	if v.cfg.SkipFailedTx {
+		v.log.Warning("Transactions failing the validation are skipped and reported at the end of the run.")
+	}
+
 	return nil
 }
 
+// PostRun reports all transactions skipped due to validation mismatches. The run
+// fails if any transaction was skipped.
+func (v *stateDbValidator) PostRun(executor.State[txcontext.TxContext], *executor.Context, error) error {
+	v.skippedMutex.Lock()
+	defer v.skippedMutex.Unlock()
+
+	if len(v.skippedOrder) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	for _, skipped := range v.skippedOrder {
+		sb.WriteString(fmt.Sprintf("\n\tblock %v tx %v", skipped.block, skipped.tx))
+	}
+	v.log.Errorf("%v transactions were skipped due to validation mismatches:%v", len(v.skippedOrder), sb.String())
+
+	return fmt.Errorf("%v transactions were skipped due to validation mismatches", len(v.skippedOrder))
+}
+
 func (v *stateDbValidator) runPreTxValidation(tool string, db state.VmStateDB, state executor.State[txcontext.TxContext], errOutput chan error) error {
 	if !v.target.WorldState {
 		return nil
@@ -135,27 +193,36 @@ func (v *stateDbValidator) runPreTxValidation(tool string, db state.VmStateDB, s
 
 	if v.cfg.OverwritePreWorldState {
 		utils.OverwriteStateDB(state.Data.GetInputState(), db)
+		if coinbase, ok := v.relaxedCoinbase(state); ok {
+			v.recordCoinbaseBalance(state, db.GetBalance(coinbase))
+		}
 		return nil
 	}
-	err := validateWorldState(v.cfg, db, state.Data.GetInputState(), v.log)
+
+	expected := state.Data.GetInputState()
+	// fee differences of previous transactions accumulate in the balance of the fee recipient
+	if coinbase, ok := v.relaxedCoinbase(state); ok {
+		balance := db.GetBalance(coinbase)
+		v.recordCoinbaseBalance(state, balance)
+		expected = withBalance(expected, coinbase, balance)
+	}
+	err := validateWorldState(v.cfg, db, expected, v.log)
 	if err == nil {
 		return nil
 	}
 
 	err = fmt.Errorf("%v err:\nblock %v tx %v\n world-state input is not contained in the state-db\n %v", tool, state.Block, state.Transaction, err)
+	err = executor.NewTxError(executor.ValidatorComponent, state.Block, state.Transaction, executor.ErrStateMismatch, err)
 
-	if v.isErrFatal(err, errOutput) {
-		return err
-	}
-
-	return nil
+	return v.reportMismatch(state, err, errOutput)
 }
 
 func (v *stateDbValidator) runPostTxValidation(tool string, db state.VmStateDB, state executor.State[txcontext.TxContext], res txcontext.Result, errOutput chan error) error {
 	if v.target.WorldState {
-		if err := validateWorldState(v.cfg, db, state.Data.GetOutputState(), v.log); err != nil {
+		if err := v.validateOutputState(db, state); err != nil {
 			err = fmt.Errorf("%v err:\nworld-state output error at block %v tx %v; %v", tool, state.Block, state.Transaction, err)
-			if v.isErrFatal(err, errOutput) {
+			err = executor.NewTxError(executor.ValidatorComponent, state.Block, state.Transaction, executor.ErrStateMismatch, err)
+			if err = v.reportMismatch(state, err, errOutput); err != nil {
 				return err
 			}
 		}
@@ -169,10 +236,12 @@ func (v *stateDbValidator) runPostTxValidation(tool string, db state.VmStateDB,
 	}
 
 	// TODO remove state.Transaction < 99999 after patch aida-db
-	if v.target.Receipt && state.Transaction < utils.PseudoTx && !skipEthereumException {
+	// receipts of transactions skipped by policy cannot be reproduced since they are not executed
+	if v.target.Receipt && state.Transaction < utils.PseudoTx && !skipEthereumException && !executor.IsSkippedByPolicy(state.Data) {
 		if err := v.validateReceipt(res.GetReceipt(), state.Data.GetResult().GetReceipt()); err != nil {
 			err = fmt.Errorf("%v err:\nvm-result error at block %v tx %v; %v", tool, state.Block, state.Transaction, err)
-			if v.isErrFatal(err, errOutput) {
+			err = executor.NewTxError(executor.ValidatorComponent, state.Block, state.Transaction, executor.ErrReceiptMismatch, err)
+			if err = v.reportMismatch(state, err, errOutput); err != nil {
 				return err
 			}
 		}
@@ -181,6 +250,115 @@ func (v *stateDbValidator) runPostTxValidation(tool string, db state.VmStateDB,
 	return nil
 }
 
+// validateOutputState compares the resulting world-state of given transaction with the db. In relaxed mode,
+// the balance of the fee recipient is excluded from the comparison and only its delta is validated.
+func (v *stateDbValidator) validateOutputState(db state.VmStateDB, state executor.State[txcontext.TxContext]) error {
+	coinbase, ok := v.relaxedCoinbase(state)
+	if !ok {
+		return validateWorldState(v.cfg, db, state.Data.GetOutputState(), v.log)
+	}
+
+	balance := db.GetBalance(coinbase)
+	before, found := v.takeCoinbaseBalance(state)
+	if !found {
+		before = balance
+	}
+
+	var msg string
+	if err := validateWorldState(v.cfg, db, withBalance(state.Data.GetOutputState(), coinbase, balance), v.log); err != nil {
+		msg = err.Error()
+	}
+	if err := validateBalanceDelta(coinbase, state.Data.GetInputState(), state.Data.GetOutputState(), before, balance, v.tolerance); err != nil {
+		msg += err.Error()
+	}
+
+	if len(msg) > 0 {
+		return fmt.Errorf("%s", msg)
+	}
+	return nil
+}
+
+// relaxedCoinbase returns the fee recipient of given transaction if its balance is validated by its delta.
+func (v *stateDbValidator) relaxedCoinbase(state executor.State[txcontext.TxContext]) (common.Address, bool) {
+	if !v.relaxed {
+		return common.Address{}, false
+	}
+	env := state.Data.GetBlockEnvironment()
+	if env == nil {
+		return common.Address{}, false
+	}
+	return env.GetCoinbase(), true
+}
+
+// recordCoinbaseBalance remembers the balance of the fee recipient before given transaction is executed.
+func (v *stateDbValidator) recordCoinbaseBalance(state executor.State[txcontext.TxContext], balance *uint256.Int) {
+	v.coinbaseMutex.Lock()
+	defer v.coinbaseMutex.Unlock()
+	v.coinbases[skippedTx{block: state.Block, tx: state.Transaction}] = balance.Clone()
+}
+
+// takeCoinbaseBalance returns and forgets the balance of the fee recipient recorded before given transaction.
+func (v *stateDbValidator) takeCoinbaseBalance(state executor.State[txcontext.TxContext]) (*uint256.Int, bool) {
+	v.coinbaseMutex.Lock()
+	defer v.coinbaseMutex.Unlock()
+	key := skippedTx{block: state.Block, tx: state.Transaction}
+	balance, found := v.coinbases[key]
+	delete(v.coinbases, key)
+	return balance, found
+}
+
+// validateBalanceDelta checks that the balance change of addr in the db matches the change between
+// the expected input and output world-states within given tolerance. An account missing in the input
+// had no balance, an account missing in the output kept its balance.
+func validateBalanceDelta(addr common.Address, input, output txcontext.WorldState, haveBefore, haveAfter, tolerance *uint256.Int) error {
+	wantBefore := new(big.Int)
+	if acc := input.Get(addr); acc != nil {
+		wantBefore = acc.GetBalance().ToBig()
+	}
+	wantAfter := wantBefore
+	if acc := output.Get(addr); acc != nil {
+		wantAfter = acc.GetBalance().ToBig()
+	}
+
+	want := new(big.Int).Sub(wantAfter, wantBefore)
+	have := new(big.Int).Sub(haveAfter.ToBig(), haveBefore.ToBig())
+	if diff := new(big.Int).Sub(have, want); diff.CmpAbs(tolerance.ToBig()) > 0 {
+		return fmt.Errorf("  Failed to validate balance delta of fee recipient %v\n"+
+			"    have %v\n"+
+			"    want %v\n"+
+			"    tolerance %v\n",
+			addr.Hex(), have, want, tolerance)
+	}
+	return nil
+}
+
+// reportMismatch handles a validation mismatch of given transaction. With --skip-failed-tx
+// the transaction is recorded as skipped and the run continues, otherwise the mismatch is
+// returned if it is fatal.
+func (v *stateDbValidator) reportMismatch(state executor.State[txcontext.TxContext], err error, errOutput chan error) error {
+	if v.cfg.SkipFailedTx {
+		v.skipTx(state.Block, state.Transaction, err)
+		return nil
+	}
+	if v.isErrFatal(err, errOutput) {
+		return err
+	}
+	return nil
+}
+
+// skipTx records a transaction skipped due to given validation mismatch.
+func (v *stateDbValidator) skipTx(block int, tx int, err error) {
+	v.skippedMutex.Lock()
+	defer v.skippedMutex.Unlock()
+
+	key := skippedTx{block: block, tx: tx}
+	if _, found := v.skipped[key]; !found {
+		v.skipped[key] = struct{}{}
+		v.skippedOrder = append(v.skippedOrder, key)
+	}
+	v.log.Warningf("Skipping block %v tx %v due to a validation mismatch; %v", block, tx, err)
+}
+
 // isErrFatal decides whether given error should stop the program or not depending on ContinueOnFailure and MaxNumErrors.
 func (v *stateDbValidator) isErrFatal(err error, ch chan error) bool {
 	// ContinueOnFailure is disabled, return the error and exit the program
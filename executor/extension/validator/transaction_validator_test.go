@@ -376,6 +376,49 @@ func TestLiveTxValidator_TwoErrorsDoReturnErrorOnEventWhenContinueOnFailureIsEna
 	}
 }
 
+func TestLiveTxValidator_SkipFailedTxContinuesAndFailsRunWithSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	ctx := &executor.Context{State: db}
+	ctx.ErrorInput = make(chan error, 10)
+
+	cfg := &utils.Config{}
+	cfg.ValidateTxState = true
+	cfg.SkipFailedTx = true
+
+	ext := MakeLiveDbValidator(cfg, ValidateTxTarget{WorldState: true, Receipt: false})
+
+	db.EXPECT().Exist(common.Address{0}).Return(false).Times(3)
+	db.EXPECT().GetBalance(common.Address{0}).Return(new(uint256.Int)).Times(3)
+	db.EXPECT().GetNonce(common.Address{0}).Return(uint64(0)).Times(3)
+	db.EXPECT().GetCode(common.Address{0}).Return([]byte{0}).Times(3)
+
+	assert.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+
+	// the first transaction fails twice, yet it is reported only once
+	for _, tx := range []int{1, 1, 2} {
+		err := ext.PreTransaction(executor.State[txcontext.TxContext]{
+			Block:       1,
+			Transaction: tx,
+			Data:        getIncorrectTestWorldState(),
+		}, ctx)
+		assert.NoError(t, err)
+	}
+	assert.Empty(t, ctx.ErrorInput, "skipped transactions must not be reported as errors")
+
+	err := ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, nil)
+	assert.ErrorContains(t, err, "2 transactions were skipped due to validation mismatches")
+}
+
+func TestLiveTxValidator_PostRunDoesNotFailWithoutSkippedTransactions(t *testing.T) {
+	cfg := &utils.Config{}
+	cfg.ValidateTxState = true
+	cfg.SkipFailedTx = true
+
+	ext := MakeLiveDbValidator(cfg, ValidateTxTarget{WorldState: true, Receipt: false})
+	assert.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, &executor.Context{}, nil))
+}
+
 func TestLiveTxValidator_PreTransactionDoesNotFailWithIncorrectOutput(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	db := state.NewMockStateDB(ctrl)
@@ -436,6 +479,135 @@ func TestLiveTxValidator_PostTransactionDoesNotFailWithIncorrectInput(t *testing
 	}
 }
 
+func TestLiveTxValidator_RelaxedValidationAcceptsExactMatch(t *testing.T) {
+	balances := map[common.Address]uint64{relaxedSender: 1000, relaxedCoinbase: 100}
+	ext, ctx, run := makeRelaxedTestValidator(t, utils.RelaxedTxStateValidation, "0", balances)
+
+	assert.NoError(t, run(func() {
+		balances[relaxedSender] = 990
+		balances[relaxedCoinbase] = 110
+	}))
+	assert.NoError(t, ext.PostRun(executor.State[txcontext.TxContext]{}, ctx, nil))
+}
+
+func TestLiveTxValidator_RelaxedValidationToleratesCoinbaseDrift(t *testing.T) {
+	// the fee recipient already received different fees from previous transactions
+	balances := map[common.Address]uint64{relaxedSender: 1000, relaxedCoinbase: 150}
+	_, _, run := makeRelaxedTestValidator(t, utils.RelaxedTxStateValidation, "5", balances)
+
+	assert.NoError(t, run(func() {
+		balances[relaxedSender] = 990
+		balances[relaxedCoinbase] = 165 // recorded delta is 10
+	}))
+}
+
+func TestLiveTxValidator_RelaxedValidationReportsCoinbaseDeltaBeyondTolerance(t *testing.T) {
+	balances := map[common.Address]uint64{relaxedSender: 1000, relaxedCoinbase: 150}
+	_, _, run := makeRelaxedTestValidator(t, utils.RelaxedTxStateValidation, "5", balances)
+
+	err := run(func() {
+		balances[relaxedSender] = 990
+		balances[relaxedCoinbase] = 166 // recorded delta is 10
+	})
+	assert.ErrorIs(t, err, executor.ErrStateMismatch)
+	assert.ErrorContains(t, err, "Failed to validate balance delta of fee recipient "+relaxedCoinbase.Hex()+"\n    have 16\n    want 10\n    tolerance 5")
+	assert.NotContains(t, err.Error(), "Failed to validate balance for account")
+}
+
+func TestLiveTxValidator_RelaxedValidationReportsMismatchOfOtherAccounts(t *testing.T) {
+	balances := map[common.Address]uint64{relaxedSender: 1000, relaxedCoinbase: 150}
+	_, _, run := makeRelaxedTestValidator(t, utils.RelaxedTxStateValidation, "5", balances)
+
+	err := run(func() {
+		balances[relaxedSender] = 991
+		balances[relaxedCoinbase] = 160
+	})
+	assert.ErrorIs(t, err, executor.ErrStateMismatch)
+	assert.ErrorContains(t, err, "Failed to validate balance for account "+relaxedSender.Hex()+"\n    have 991\n    want 990")
+	assert.NotContains(t, err.Error(), relaxedCoinbase.Hex())
+}
+
+func TestLiveTxValidator_StrictValidationReportsCoinbaseDrift(t *testing.T) {
+	balances := map[common.Address]uint64{relaxedSender: 1000, relaxedCoinbase: 150}
+	_, _, run := makeRelaxedTestValidator(t, utils.StrictTxStateValidation, "0", balances)
+
+	err := run(func() {})
+	assert.ErrorIs(t, err, executor.ErrStateMismatch)
+	assert.ErrorContains(t, err, "Failed to validate balance for account "+relaxedCoinbase.Hex()+"\n    have 150\n    want 100")
+}
+
+func TestLiveTxValidator_PreRunRejectsInvalidRelaxedConfiguration(t *testing.T) {
+	tests := map[string]struct {
+		mode, tolerance string
+		wantErr         string
+	}{
+		"unknown mode":      {mode: "lenient", tolerance: "0", wantErr: `unknown --validate-tx-state "lenient"`},
+		"invalid tolerance": {mode: utils.RelaxedTxStateValidation, tolerance: "-1", wantErr: `cannot parse --validate-tx-coinbase-tolerance "-1"`},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &utils.Config{ValidateTxState: true, TxStateValidation: test.mode, CoinbaseTolerance: test.tolerance}
+			ext := MakeLiveDbValidator(cfg, ValidateTxTarget{WorldState: true})
+			assert.ErrorContains(t, ext.PreRun(executor.State[txcontext.TxContext]{}, &executor.Context{}), test.wantErr)
+		})
+	}
+}
+
+var (
+	relaxedSender   = common.Address{0x1}
+	relaxedCoinbase = common.Address{0xc}
+)
+
+// makeRelaxedTestValidator creates a live-db validator in given mode backed by a db with given balances. The returned
+// function validates a transaction of relaxedSender paying a fee of 10 to relaxedCoinbase; execute updates the balances.
+func makeRelaxedTestValidator(t *testing.T, mode, tolerance string, balances map[common.Address]uint64) (executor.Extension[txcontext.TxContext], *executor.Context, func(execute func()) error) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	ctx := &executor.Context{State: db, ErrorInput: make(chan error, 10)}
+
+	db.EXPECT().Exist(gomock.Any()).Return(true).AnyTimes()
+	db.EXPECT().GetNonce(gomock.Any()).Return(uint64(0)).AnyTimes()
+	db.EXPECT().GetCode(gomock.Any()).Return(nil).AnyTimes()
+	db.EXPECT().GetBalance(gomock.Any()).DoAndReturn(func(addr common.Address) *uint256.Int {
+		return uint256.NewInt(balances[addr])
+	}).AnyTimes()
+
+	cfg := &utils.Config{
+		ValidateTxState:   true,
+		TxStateValidation: mode,
+		CoinbaseTolerance: tolerance,
+	}
+	ext := MakeLiveDbValidator(cfg, ValidateTxTarget{WorldState: true})
+	assert.NoError(t, ext.PreRun(executor.State[txcontext.TxContext]{}, ctx))
+
+	account := func(balance uint64) *substate.Account {
+		return substate.NewAccount(0, uint256.NewInt(balance), nil)
+	}
+	st := executor.State[txcontext.TxContext]{
+		Block:       1,
+		Transaction: 1,
+		Data: substatecontext.NewTxContext(&substate.Substate{
+			Env: &substate.Env{Coinbase: substatetypes.Address(relaxedCoinbase)},
+			InputSubstate: substate.WorldState{
+				substatetypes.Address(relaxedSender):   account(1000),
+				substatetypes.Address(relaxedCoinbase): account(100),
+			},
+			OutputSubstate: substate.WorldState{
+				substatetypes.Address(relaxedSender):   account(990),
+				substatetypes.Address(relaxedCoinbase): account(110),
+			},
+		}),
+	}
+
+	return ext, ctx, func(execute func()) error {
+		if err := ext.PreTransaction(st, ctx); err != nil {
+			return err
+		}
+		execute()
+		return ext.PostTransaction(st, ctx)
+	}
+}
+
 func TestArchiveTxValidator_NoValidatorIsCreatedIfDisabled(t *testing.T) {
 	cfg := &utils.Config{}
 	cfg.ValidateTxState = false
@@ -53,6 +53,27 @@ func validateWorldState(cfg *utils.Config, db state.VmStateDB, expectedAlloc txc
 	return err
 }
 
+// withBalance returns a copy of given world-state in which the balance of addr is replaced by given
+// balance. The world-state is returned unchanged if it does not contain addr.
+func withBalance(ws txcontext.WorldState, addr common.Address, balance *uint256.Int) txcontext.WorldState {
+	acc := ws.Get(addr)
+	if acc == nil {
+		return ws
+	}
+
+	accounts := make(map[common.Address]txcontext.Account, ws.Len())
+	ws.ForEachAccount(func(addr common.Address, acc txcontext.Account) {
+		accounts[addr] = acc
+	})
+	storage := make(map[common.Hash]common.Hash, acc.GetStorageSize())
+	acc.ForEachStorage(func(keyHash common.Hash, valueHash common.Hash) {
+		storage[keyHash] = valueHash
+	})
+	accounts[addr] = txcontext.NewAccount(acc.GetCode(), storage, balance.ToBig(), acc.GetNonce())
+
+	return txcontext.NewWorldState(accounts)
+}
+
 // printIfDifferent compares two values of any types and reports differences if any.
 func printIfDifferent[T comparable](label string, want, have T, log logger.Logger) bool {
 	if want != have {
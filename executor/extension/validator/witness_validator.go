@@ -0,0 +1,165 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// MakeWitnessValidator creates an extension which checks after each transaction that
+// the state witness of the primary StateDb covers every account and storage slot
+// recorded in the input alloc of the transaction. StateDb implementations which do
+// not collect witnesses are detected on the first transaction and the validation is
+// disabled for the rest of the run.
+func MakeWitnessValidator(cfg *utils.Config) executor.Extension[txcontext.TxContext] {
+	if !cfg.ValidateWitness {
+		return extension.NilExtension[txcontext.TxContext]{}
+	}
+
+	log := logger.NewLogger(cfg.LogLevel, "Witness-Validator")
+
+	return makeWitnessValidator(cfg, log)
+}
+
+func makeWitnessValidator(cfg *utils.Config, log logger.Logger) *witnessValidator {
+	return &witnessValidator{
+		stateDbValidator: makeStateDbValidator(cfg, log, ValidateTxTarget{}),
+	}
+}
+
+type witnessValidator struct {
+	*stateDbValidator
+	disabled atomic.Bool // set once the StateDb is found not to provide witnesses
+}
+
+// PostTransaction verifies that the witness covers the input alloc of the transaction.
+func (v *witnessValidator) PostTransaction(state executor.State[txcontext.TxContext], ctx *executor.Context) error {
+	if v.disabled.Load() {
+		return nil
+	}
+
+	witness := ctx.State.Witness()
+	if witness == nil {
+		if v.disabled.CompareAndSwap(false, true) {
+			v.log.Warning("StateDb does not provide state witnesses, witness validation is disabled")
+		}
+		return nil
+	}
+
+	inputAlloc := state.Data.GetInputState()
+	if inputAlloc == nil {
+		return nil
+	}
+
+	err := verifyWitnessCoverage(witness, inputAlloc)
+	if err == nil {
+		return nil
+	}
+
+	err = fmt.Errorf("witness validation failed at block %v, tx %v; %w", state.Block, state.Transaction, err)
+	if v.isErrFatal(err, ctx.ErrorInput) {
+		return err
+	}
+	return nil
+}
+
+// verifyWitnessCoverage checks that every account and storage slot of the given
+// world state can be resolved using only the trie nodes contained in the witness.
+// Resolving a key requires all nodes on its path from the pre-state root, hence
+// a successful lookup proves the key is covered, whether it exists or not.
+func verifyWitnessCoverage(witness *stateless.Witness, ws txcontext.WorldState) error {
+	if len(witness.Headers) == 0 {
+		return errors.New("witness does not contain the pre-state header")
+	}
+
+	root := witness.Root()
+	db := triedb.NewDatabase(witness.MakeHashDB(), triedb.HashDefaults)
+	defer db.Close()
+
+	accountTrie, err := trie.NewStateTrie(trie.StateTrieID(root), db)
+	if err != nil {
+		return fmt.Errorf("cannot open account trie with root %v; %w", root.Hex(), err)
+	}
+
+	var missing []string
+	for _, addr := range sortedAddresses(ws) {
+		account, err := accountTrie.GetAccount(addr)
+		if err != nil {
+			missing = append(missing, fmt.Sprintf("account %v", addr.Hex()))
+			continue
+		}
+
+		acc := ws.Get(addr)
+		keys := sortedStorageKeys(acc)
+		if len(keys) == 0 || account == nil {
+			// storage of non-existing accounts is empty, the absence proof covers it
+			continue
+		}
+
+		storageTrie, err := trie.NewStateTrie(trie.StorageTrieID(root, crypto.Keccak256Hash(addr.Bytes()), account.Root), db)
+		if err != nil {
+			missing = append(missing, fmt.Sprintf("storage trie of account %v", addr.Hex()))
+			continue
+		}
+		for _, key := range keys {
+			if _, err := storageTrie.GetStorage(addr, key.Bytes()); err != nil {
+				missing = append(missing, fmt.Sprintf("slot %v of account %v", key.Hex(), addr.Hex()))
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("witness does not cover %d entries of the input alloc: %v", len(missing), strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// sortedAddresses returns the sorted accounts of the given world state.
+func sortedAddresses(ws txcontext.WorldState) []common.Address {
+	var res []common.Address
+	ws.ForEachAccount(func(addr common.Address, _ txcontext.Account) {
+		res = append(res, addr)
+	})
+	slices.SortFunc(res, func(a, b common.Address) int { return bytes.Compare(a[:], b[:]) })
+	return res
+}
+
+// sortedStorageKeys returns the sorted storage keys of the given account.
+func sortedStorageKeys(acc txcontext.Account) []common.Hash {
+	var res []common.Hash
+	acc.ForEachStorage(func(key common.Hash, _ common.Hash) {
+		res = append(res, key)
+	})
+	slices.SortFunc(res, func(a, b common.Hash) int { return bytes.Compare(a[:], b[:]) })
+	return res
+}
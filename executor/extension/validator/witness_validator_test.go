@@ -0,0 +1,244 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/executor"
+	"github.com/0xsoniclabs/aida/executor/extension"
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWitnessValidator_NoValidatorIsCreatedIfDisabled(t *testing.T) {
+	ext := MakeWitnessValidator(&utils.Config{})
+	_, ok := ext.(extension.NilExtension[txcontext.TxContext])
+	assert.True(t, ok)
+}
+
+// proofCollector gathers the trie nodes of merkle proofs.
+type proofCollector map[string][]byte
+
+func (c proofCollector) Put(key []byte, value []byte) error {
+	c[string(key)] = value
+	return nil
+}
+
+func (c proofCollector) Delete(key []byte) error {
+	delete(c, string(key))
+	return nil
+}
+
+// makeTestWitness builds a state trie containing given world state and returns a witness
+// consisting of the proofs of all its accounts and storage slots except the omitted ones.
+// An omitted slot is identified by its account and key, an omitted account by its address
+// and a nil key.
+func makeTestWitness(t *testing.T, ws txcontext.WorldState, omit func(addr common.Address, key *common.Hash) bool) *stateless.Witness {
+	db := triedb.NewDatabase(rawdb.NewMemoryDatabase(), triedb.HashDefaults)
+	accountTrie, err := trie.NewStateTrie(trie.StateTrieID(types.EmptyRootHash), db)
+	require.NoError(t, err)
+
+	proofs := make(proofCollector)
+	for _, addr := range sortedAddresses(ws) {
+		acc := ws.Get(addr)
+		addrHash := crypto.Keccak256Hash(addr.Bytes())
+		storageTrie, err := trie.NewStateTrie(trie.StorageTrieID(types.EmptyRootHash, addrHash, types.EmptyRootHash), db)
+		require.NoError(t, err)
+
+		keys := sortedStorageKeys(acc)
+		for _, key := range keys {
+			value := acc.GetStorageAt(key)
+			require.NoError(t, storageTrie.UpdateStorage(addr, key.Bytes(), value.Bytes()))
+		}
+		for _, key := range keys {
+			if !omit(addr, &key) {
+				require.NoError(t, storageTrie.Prove(crypto.Keccak256(key.Bytes()), proofs))
+			}
+		}
+
+		account := &types.StateAccount{
+			Nonce:    acc.GetNonce(),
+			Balance:  acc.GetBalance(),
+			Root:     storageTrie.Hash(),
+			CodeHash: types.EmptyCodeHash.Bytes(),
+		}
+		require.NoError(t, accountTrie.UpdateAccount(addr, account, 0))
+	}
+	for _, addr := range sortedAddresses(ws) {
+		if !omit(addr, nil) {
+			require.NoError(t, accountTrie.Prove(crypto.Keccak256(addr.Bytes()), proofs))
+		}
+	}
+
+	witness, err := stateless.NewWitness(&types.Header{}, nil, false)
+	require.NoError(t, err)
+	witness.Headers = []*types.Header{{Number: big.NewInt(0), Root: accountTrie.Hash()}}
+	witness.AddState(proofs, common.Hash{})
+	return witness
+}
+
+func makeWitnessTestWorldState() txcontext.WorldState {
+	return txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		{1}: txcontext.NewAccount(nil, map[common.Hash]common.Hash{{1}: {1}, {2}: {2}}, big.NewInt(1), 1),
+		{2}: txcontext.NewAccount(nil, map[common.Hash]common.Hash{}, big.NewInt(2), 2),
+		{3}: txcontext.NewAccount(nil, map[common.Hash]common.Hash{{3}: {3}}, big.NewInt(3), 3),
+	})
+}
+
+func TestWitnessValidator_VerifyWitnessCoverage(t *testing.T) {
+	tests := map[string]struct {
+		omit     func(addr common.Address, key *common.Hash) bool
+		expected []string
+	}{
+		"covered": {
+			omit: func(common.Address, *common.Hash) bool { return false },
+		},
+		"missing account": {
+			omit: func(addr common.Address, key *common.Hash) bool {
+				return addr == common.Address{2} && key == nil
+			},
+			expected: []string{"1 entries", "account " + common.Address{2}.Hex()},
+		},
+		"missing slot": {
+			omit: func(addr common.Address, key *common.Hash) bool {
+				return addr == common.Address{1} && key != nil && *key == common.Hash{2}
+			},
+			expected: []string{"1 entries", "slot " + common.Hash{2}.Hex() + " of account " + common.Address{1}.Hex()},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ws := makeWitnessTestWorldState()
+			err := verifyWitnessCoverage(makeTestWitness(t, ws, test.omit), ws)
+			if len(test.expected) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range test.expected {
+				assert.ErrorContains(t, err, expected)
+			}
+		})
+	}
+}
+
+func TestWitnessValidator_NonExistingAccountIsCoveredByAbsenceProof(t *testing.T) {
+	// a trie with a single account consists of a single leaf, hence the proof
+	// of this account also proves the absence of any other account
+	existing := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		{1}: txcontext.NewAccount(nil, map[common.Hash]common.Hash{{1}: {1}}, big.NewInt(1), 1),
+	})
+	witness := makeTestWitness(t, existing, func(common.Address, *common.Hash) bool { return false })
+
+	absent := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		{9}: txcontext.NewAccount(nil, map[common.Hash]common.Hash{{9}: {9}}, big.NewInt(9), 9),
+	})
+	require.NoError(t, verifyWitnessCoverage(witness, absent))
+}
+
+func TestWitnessValidator_WitnessWithoutHeaderIsReported(t *testing.T) {
+	witness, err := stateless.NewWitness(&types.Header{}, nil, false)
+	require.NoError(t, err)
+	err = verifyWitnessCoverage(witness, makeWitnessTestWorldState())
+	require.ErrorContains(t, err, "pre-state header")
+}
+
+func TestWitnessValidator_PostTransactionReportsMissingEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	ws := makeWitnessTestWorldState()
+	db.EXPECT().Witness().Return(makeTestWitness(t, ws, func(addr common.Address, key *common.Hash) bool {
+		return addr == common.Address{3} && key == nil
+	}))
+
+	data := txcontext.NewMockTxContext(ctrl)
+	data.EXPECT().GetInputState().Return(ws)
+
+	ctx := &executor.Context{State: db}
+	st := executor.State[txcontext.TxContext]{Block: 5, Transaction: 2, Data: data}
+
+	ext := makeWitnessValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	err := ext.PostTransaction(st, ctx)
+	require.ErrorContains(t, err, "witness validation failed at block 5, tx 2")
+	require.ErrorContains(t, err, "1 entries of the input alloc: account "+common.Address{3}.Hex())
+}
+
+func TestWitnessValidator_PostTransactionPassesWithCoveredInputAlloc(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	ws := makeWitnessTestWorldState()
+	db.EXPECT().Witness().Return(makeTestWitness(t, ws, func(common.Address, *common.Hash) bool { return false }))
+
+	data := txcontext.NewMockTxContext(ctrl)
+	data.EXPECT().GetInputState().Return(ws)
+
+	ctx := &executor.Context{State: db}
+	st := executor.State[txcontext.TxContext]{Block: 5, Data: data}
+
+	ext := makeWitnessValidator(&utils.Config{}, logger.NewLogger("critical", "test"))
+	require.NoError(t, ext.PostTransaction(st, ctx))
+}
+
+func TestWitnessValidator_ErrorIsForwardedWithContinueOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	ws := makeWitnessTestWorldState()
+	db.EXPECT().Witness().Return(makeTestWitness(t, ws, func(_ common.Address, key *common.Hash) bool {
+		return key == nil
+	}))
+
+	data := txcontext.NewMockTxContext(ctrl)
+	data.EXPECT().GetInputState().Return(ws)
+
+	ctx := &executor.Context{State: db, ErrorInput: make(chan error, 1)}
+	st := executor.State[txcontext.TxContext]{Block: 5, Data: data}
+
+	ext := makeWitnessValidator(&utils.Config{ContinueOnFailure: true}, logger.NewLogger("critical", "test"))
+	require.NoError(t, ext.PostTransaction(st, ctx))
+	require.Len(t, ctx.ErrorInput, 1)
+}
+
+func TestWitnessValidator_DisablesItselfIfStateDbProvidesNoWitness(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+
+	db.EXPECT().Witness().Return(nil).Times(1)
+	log.EXPECT().Warning(gomock.Any()).Times(1)
+
+	ctx := &executor.Context{State: db}
+	st := executor.State[txcontext.TxContext]{Block: 1}
+
+	ext := makeWitnessValidator(&utils.Config{}, log)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, ext.PostTransaction(st, ctx))
+	}
+}
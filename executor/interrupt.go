@@ -0,0 +1,157 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/0xsoniclabs/aida/logger"
+)
+
+// ErrInterrupted is reported by runs stopped by an interrupt or termination signal.
+var ErrInterrupted = errors.New("run interrupted")
+
+// InterruptedExitCode is the exit code of applications whose run was interrupted,
+// following the shell convention of 128 + SIGINT.
+const InterruptedExitCode = 130
+
+// ExitCode returns the exit code of an application failing with the given error.
+func ExitCode(err error) int {
+	if errors.Is(err, ErrInterrupted) {
+		return InterruptedExitCode
+	}
+	return 1
+}
+
+// exit terminates the process, it is replaced in tests.
+var exit = os.Exit
+
+// notifyInterrupt returns a context which is canceled once the process receives SIGINT
+// or SIGTERM, so that the run stops gracefully. A second signal terminates the process
+// immediately. The returned function releases the signal handling.
+func notifyInterrupt(log logger.Logger) (context.Context, func()) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	return handleInterrupt(signals, log, func() {
+		signal.Stop(signals)
+	})
+}
+
+func handleInterrupt(signals <-chan os.Signal, log logger.Logger, release func()) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	quit := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case sig := <-signals:
+			log.Warningf("Received %v, finishing blocks in progress; send it again to exit immediately", sig)
+			cancel()
+		case <-quit:
+			return
+		}
+		select {
+		case sig := <-signals:
+			log.Errorf("Received %v again, exiting immediately", sig)
+			exit(InterruptedExitCode)
+		case <-quit:
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			release()
+			close(quit)
+			<-finished
+			cancel()
+		})
+	}
+}
+
+// interruption stops an interrupted run at block boundaries and records the first
+// block which was not processed.
+type interruption struct {
+	done        <-chan struct{}
+	lock        sync.Mutex
+	started     int // highest block started by workers sharing a queue of blocks or transactions
+	interrupted bool
+	block       int
+}
+
+func newInterruption(ctx context.Context) *interruption {
+	return &interruption{done: ctx.Done(), started: math.MinInt}
+}
+
+func (i *interruption) isInterrupted() bool {
+	select {
+	case <-i.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// stopAt reports whether the run is interrupted, in which case the given block and
+// all following ones are not processed.
+func (i *interruption) stopAt(block int) bool {
+	if !i.isInterrupted() {
+		return false
+	}
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.record(block)
+	return true
+}
+
+// admit reports whether a worker processes the given block or a transaction of it. Once
+// the run is interrupted, only blocks up to the highest block started before are admitted,
+// so that blocks in progress, and blocks which were dequeued earlier, are finished.
+func (i *interruption) admit(block int) bool {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if !i.isInterrupted() {
+		i.started = max(i.started, block)
+		return true
+	}
+	if block <= i.started {
+		return true
+	}
+	i.record(block)
+	return false
+}
+
+func (i *interruption) record(block int) {
+	if !i.interrupted || block < i.block {
+		i.block = block
+	}
+	i.interrupted = true
+}
+
+// firstUnprocessedBlock returns the first block not processed by an interrupted run.
+// The second result is false if the run was not interrupted.
+func (i *interruption) firstUnprocessedBlock() (int, bool) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return i.block, i.interrupted
+}
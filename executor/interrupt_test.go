@@ -0,0 +1,118 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, InterruptedExitCode, ExitCode(ErrInterrupted))
+	assert.Equal(t, InterruptedExitCode, ExitCode(fmt.Errorf("replay failed; %w", errors.Join(errors.New("other"), ErrInterrupted))))
+	assert.Equal(t, 1, ExitCode(errors.New("failure")))
+}
+
+func TestHandleInterrupt_FirstSignalInterruptsRunSecondSignalExits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+
+	exited := make(chan int, 1)
+	exit = func(code int) { exited <- code }
+	t.Cleanup(func() { exit = os.Exit })
+
+	signals := make(chan os.Signal)
+	log.EXPECT().Warningf("Received %v, finishing blocks in progress; send it again to exit immediately", os.Interrupt)
+	log.EXPECT().Errorf("Received %v again, exiting immediately", os.Interrupt)
+	ctx, release := handleInterrupt(signals, log, func() {})
+	defer release()
+
+	assert.NoError(t, ctx.Err())
+	signals <- os.Interrupt
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	signals <- os.Interrupt
+	select {
+	case code := <-exited:
+		assert.Equal(t, InterruptedExitCode, code)
+	case <-time.After(5 * time.Second):
+		t.Fatal("process was not terminated by the second signal")
+	}
+}
+
+func TestHandleInterrupt_ReleaseStopsSignalHandling(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+
+	released := 0
+	signals := make(chan os.Signal, 1)
+	_, release := handleInterrupt(signals, log, func() { released++ })
+	release()
+	release()
+	assert.Equal(t, 1, released)
+
+	// signals received after the release are not handled anymore
+	signals <- os.Interrupt
+}
+
+func TestNotifyInterrupt_TerminationSignalInterruptsRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	log.EXPECT().Warningf(gomock.Any(), syscall.SIGTERM)
+
+	ctx, release := notifyInterrupt(log)
+	defer release()
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("run was not interrupted by SIGTERM")
+	}
+}
+
+func TestInterruption_RecordsFirstUnprocessedBlock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := newInterruption(ctx)
+
+	assert.False(t, stop.stopAt(10))
+	assert.True(t, stop.admit(12))
+	_, interrupted := stop.firstUnprocessedBlock()
+	assert.False(t, interrupted)
+
+	cancel()
+	assert.True(t, stop.admit(11), "blocks before the started one are finished")
+	assert.True(t, stop.admit(12), "started blocks are finished")
+	assert.False(t, stop.admit(14))
+	assert.True(t, stop.stopAt(15))
+	assert.False(t, stop.admit(13))
+
+	block, interrupted := stop.firstUnprocessedBlock()
+	assert.True(t, interrupted)
+	assert.Equal(t, 13, block)
+}
@@ -21,6 +21,7 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/0xsoniclabs/aida/state"
 	"github.com/0xsoniclabs/aida/txcontext"
@@ -99,17 +100,36 @@ func (p normaTxProvider) Run(from int, to int, consumer Consumer[txcontext.TxCon
 	// initialize the list of app types
 	appTypes := p.cfg.TxGeneratorType
 	if len(appTypes) == 1 && appTypes[0] == "all" {
-		appTypes = []string{"erc20", "counter", "store", "uniswap"}
+		appTypes = []string{txgenerator.Erc20GeneratorType, txgenerator.CreateGeneratorType, "counter", "store", "uniswap"}
 	}
 
+	// all generators implemented by Aida share a single account registry, so each
+	// account receives consecutive nonces even if used by multiple generators
+	registry := txgenerator.NewAccountRegistry(
+		func(addr common.Address) (uint64, error) {
+			return fakeRpc.NonceAt(context.Background(), addr, nil)
+		},
+		func(addr common.Address) (*big.Int, error) {
+			return fakeRpc.BalanceAt(context.Background(), addr, nil)
+		},
+	)
+
 	// create users for each app type
 	users := make([]app.User, 0)
 	for ix, appType := range appTypes {
+		user, err := p.newAidaTxGenerator(appType, ix, registry)
+		if err != nil {
+			return err
+		}
+		if user != nil {
+			users = append(users, user)
+			continue
+		}
 		application, err := app.NewApplication(appType, fakeRpc, primaryAccount, 1, uint32(ix), uint32(ix))
 		if err != nil {
 			return err
 		}
-		user, err := application.CreateUser(fakeRpc)
+		user, err = application.CreateUser(fakeRpc)
 		if err != nil {
 			return err
 		}
@@ -151,9 +171,34 @@ func (p normaTxProvider) Close() {
 	// nothing to do
 }
 
+// newAidaTxGenerator creates a generator of the given type implemented by Aida.
+// Such generators do not need any setup and deploy their contracts by their own
+// transactions. It returns nil if the type is provided by Norma.
+func (p normaTxProvider) newAidaTxGenerator(appType string, ix int, registry *txgenerator.AccountRegistry) (app.User, error) {
+	// each generator gets its own seed, so that a generator produces the same
+	// transactions regardless of the other generators in use
+	seed := p.cfg.RandomSeed + int64(ix)
+	switch strings.ToLower(appType) {
+	case txgenerator.Erc20GeneratorType:
+		generator, err := txgenerator.NewErc20Generator(p.cfg.TxGeneratorAccounts, seed, registry)
+		if err != nil {
+			return nil, err
+		}
+		return generator, nil
+	case txgenerator.CreateGeneratorType:
+		generator, err := txgenerator.NewCreateGenerator(p.cfg.TxGeneratorAccounts, p.cfg.TxGeneratorCreateRate, seed, registry)
+		if err != nil {
+			return nil, err
+		}
+		return generator, nil
+	}
+	return nil, nil
+}
+
 // initializeTreasureAccount initializes the treasure account.
 // The treasure account is an account with a lot of ether that is used to fund
-// the accounts and deploy the contract.
+// the accounts and deploy the contract. The funder of accounts used by
+// generators implemented by Aida is funded alongside.
 func (p normaTxProvider) initializeTreasureAccount(blkNumber int) (*app.Account, error) {
 	// extract the address from the treasure account private key
 	privateKey, err := crypto.HexToECDSA(treasureAccountPrivateKey)
@@ -181,6 +226,9 @@ func (p normaTxProvider) initializeTreasureAccount(blkNumber int) (*app.Account,
 	}
 	p.stateDb.CreateAccount(fromAddress)
 	p.stateDb.AddBalance(fromAddress, amount, 0)
+	// the funder of accounts used by generators implemented by Aida
+	p.stateDb.CreateAccount(txgenerator.FunderAddress)
+	p.stateDb.AddBalance(txgenerator.FunderAddress, amount, 0)
 	err = p.stateDb.EndTransaction()
 	if err != nil {
 		return nil, fmt.Errorf("cannot end transaction; %w", err)
@@ -23,9 +23,12 @@ import (
 	"testing"
 
 	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/txcontext/txgenerator"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
@@ -53,6 +56,8 @@ func TestNormaTxProvider_Run(t *testing.T) {
 		dbMock.EXPECT().BeginTransaction(gomock.Any()),
 		dbMock.EXPECT().CreateAccount(gomock.Any()),
 		dbMock.EXPECT().AddBalance(gomock.Any(), gomock.Any(), gomock.Any()),
+		dbMock.EXPECT().CreateAccount(txgenerator.FunderAddress),
+		dbMock.EXPECT().AddBalance(txgenerator.FunderAddress, gomock.Any(), gomock.Any()),
 		dbMock.EXPECT().EndTransaction(),
 		dbMock.EXPECT().EndBlock(),
 
@@ -108,7 +113,7 @@ func TestNormaTxProvider_RunAll(t *testing.T) {
 
 	cfg := &utils.Config{
 		BlockLength:     uint64(5),
-		TxGeneratorType: []string{"erc20", "counter", "store"},
+		TxGeneratorType: []string{"counter", "store"},
 		ChainID:         297,
 	}
 	provider := NewNormaTxProvider(cfg, dbMock)
@@ -122,48 +127,27 @@ func TestNormaTxProvider_RunAll(t *testing.T) {
 		dbMock.EXPECT().BeginTransaction(gomock.Any()),
 		dbMock.EXPECT().CreateAccount(gomock.Any()),
 		dbMock.EXPECT().AddBalance(gomock.Any(), gomock.Any(), gomock.Any()),
+		dbMock.EXPECT().CreateAccount(txgenerator.FunderAddress),
+		dbMock.EXPECT().AddBalance(txgenerator.FunderAddress, gomock.Any(), gomock.Any()),
 		dbMock.EXPECT().EndTransaction(),
 		dbMock.EXPECT().EndBlock(),
 
-		// contract deployment in order: erc20 -> counter -> store
+		// contract deployment in order: counter -> store
 
 		// expected on block 2, because block 1 is treasure account initialization
 		// and we are starting from block 1
 
-		// ERC 20
+		// COUNTER
 		consumer.EXPECT().Consume(2, 0, gomock.Any()).Return(nil),
 		dbMock.EXPECT().BeginTransaction(gomock.Any()),
-		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(0)),
-		dbMock.EXPECT().EndTransaction(),
-		dbMock.EXPECT().BeginTransaction(gomock.Any()),
-		dbMock.EXPECT().GetBalance(gomock.Any()).Return(balance),
-		dbMock.EXPECT().EndTransaction(),
-		dbMock.EXPECT().BeginTransaction(gomock.Any()),
 		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(1)),
 		dbMock.EXPECT().EndTransaction(),
-		// funding accounts
-		dbMock.EXPECT().BeginTransaction(gomock.Any()),
-		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(0)),
-		dbMock.EXPECT().EndTransaction(),
 		dbMock.EXPECT().BeginTransaction(gomock.Any()),
 		dbMock.EXPECT().GetBalance(gomock.Any()).Return(balance),
 		dbMock.EXPECT().EndTransaction(),
-		// mint nf tokens
-		consumer.EXPECT().Consume(2, 1, gomock.Any()).Return(nil),
-		dbMock.EXPECT().BeginTransaction(gomock.Any()),
-		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(1)),
-		dbMock.EXPECT().EndTransaction(),
-		// COUNTER
-		consumer.EXPECT().Consume(2, 2, gomock.Any()).Return(nil),
 		dbMock.EXPECT().BeginTransaction(gomock.Any()),
 		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(1)),
 		dbMock.EXPECT().EndTransaction(),
-		dbMock.EXPECT().BeginTransaction(gomock.Any()),
-		dbMock.EXPECT().GetBalance(gomock.Any()).Return(balance),
-		dbMock.EXPECT().EndTransaction(),
-		dbMock.EXPECT().BeginTransaction(gomock.Any()),
-		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(2)),
-		dbMock.EXPECT().EndTransaction(),
 		// funding accounts
 		dbMock.EXPECT().BeginTransaction(gomock.Any()),
 		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(0)),
@@ -175,7 +159,7 @@ func TestNormaTxProvider_RunAll(t *testing.T) {
 		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(1)),
 		dbMock.EXPECT().EndTransaction(),
 		// STORE
-		consumer.EXPECT().Consume(2, 3, gomock.Any()).Return(nil),
+		consumer.EXPECT().Consume(2, 1, gomock.Any()).Return(nil),
 		dbMock.EXPECT().BeginTransaction(gomock.Any()),
 		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(2)),
 		dbMock.EXPECT().EndTransaction(),
@@ -183,7 +167,7 @@ func TestNormaTxProvider_RunAll(t *testing.T) {
 		dbMock.EXPECT().GetBalance(gomock.Any()).Return(balance),
 		dbMock.EXPECT().EndTransaction(),
 		dbMock.EXPECT().BeginTransaction(gomock.Any()),
-		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(3)),
+		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(2)),
 		dbMock.EXPECT().EndTransaction(),
 		// funding accounts
 		dbMock.EXPECT().BeginTransaction(gomock.Any()),
@@ -196,6 +180,8 @@ func TestNormaTxProvider_RunAll(t *testing.T) {
 		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(2)),
 		dbMock.EXPECT().EndTransaction(),
 		// generating transactions
+		consumer.EXPECT().Consume(2, 2, gomock.Any()).Return(nil),
+		consumer.EXPECT().Consume(2, 3, gomock.Any()).Return(nil),
 		consumer.EXPECT().Consume(2, 4, gomock.Any()).Return(nil),
 		consumer.EXPECT().Consume(3, 0, gomock.Any()).Return(nil),
 		consumer.EXPECT().Consume(3, 1, gomock.Any()).Return(nil),
@@ -210,6 +196,136 @@ func TestNormaTxProvider_RunAll(t *testing.T) {
 	}
 }
 
+func TestNormaTxProvider_RunErc20Generator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := state.NewMockStateDB(ctrl)
+
+	cfg := &utils.Config{
+		BlockLength:         uint64(3),
+		TxGeneratorType:     []string{"erc20"},
+		TxGeneratorAccounts: 1,
+		RandomSeed:          1,
+		ChainID:             297,
+	}
+	provider := NewNormaTxProvider(cfg, dbMock)
+	consumer := NewMockTxConsumer(ctrl)
+
+	var messages []*core.Message
+	record := func(_ int, _ int, data txcontext.TxContext) error {
+		messages = append(messages, data.GetMessage())
+		return nil
+	}
+
+	gomock.InOrder(
+		// treasure account initialization
+		dbMock.EXPECT().BeginBlock(gomock.Any()),
+		dbMock.EXPECT().BeginTransaction(gomock.Any()),
+		dbMock.EXPECT().CreateAccount(gomock.Any()),
+		dbMock.EXPECT().AddBalance(gomock.Any(), gomock.Any(), gomock.Any()),
+		dbMock.EXPECT().CreateAccount(txgenerator.FunderAddress),
+		dbMock.EXPECT().AddBalance(txgenerator.FunderAddress, gomock.Any(), gomock.Any()),
+		dbMock.EXPECT().EndTransaction(),
+		dbMock.EXPECT().EndBlock(),
+
+		// the nonce of the only account is read once on its first use
+		dbMock.EXPECT().BeginTransaction(gomock.Any()),
+		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(3)),
+		dbMock.EXPECT().EndTransaction(),
+
+		// token deployment, minting and calls, all within the configured block length
+		consumer.EXPECT().Consume(2, 0, gomock.Any()).DoAndReturn(record),
+		consumer.EXPECT().Consume(2, 1, gomock.Any()).DoAndReturn(record),
+		consumer.EXPECT().Consume(2, 2, gomock.Any()).DoAndReturn(record),
+		consumer.EXPECT().Consume(3, 0, gomock.Any()).DoAndReturn(record),
+		consumer.EXPECT().Consume(3, 1, gomock.Any()).DoAndReturn(record),
+		consumer.EXPECT().Consume(3, 2, gomock.Any()).DoAndReturn(record),
+	)
+
+	require.NoError(t, provider.Run(1, 3, toSubstateConsumer(consumer)))
+	require.Len(t, messages, 6)
+	assert.Nil(t, messages[0].To, "first transaction must deploy the token")
+	token := crypto.CreateAddress(messages[0].From, 3)
+	for i, msg := range messages {
+		assert.Equal(t, uint64(3+i), msg.Nonce)
+		assert.Equal(t, messages[0].From, msg.From)
+		if i > 0 {
+			assert.Equal(t, &token, msg.To)
+		}
+	}
+}
+
+func TestNormaTxProvider_RunCreateGenerator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := state.NewMockStateDB(ctrl)
+
+	cfg := &utils.Config{
+		BlockLength:           uint64(2),
+		TxGeneratorType:       []string{"create"},
+		TxGeneratorAccounts:   1,
+		TxGeneratorCreateRate: 1,
+		ChainID:               297,
+	}
+	provider := NewNormaTxProvider(cfg, dbMock)
+	consumer := NewMockTxConsumer(ctrl)
+
+	codes := make(map[string]struct{})
+	record := func(_ int, _ int, data txcontext.TxContext) error {
+		assert.Nil(t, data.GetMessage().To)
+		codes[string(data.GetMessage().Data)] = struct{}{}
+		return nil
+	}
+
+	gomock.InOrder(
+		// treasure account initialization
+		dbMock.EXPECT().BeginBlock(gomock.Any()),
+		dbMock.EXPECT().BeginTransaction(gomock.Any()),
+		dbMock.EXPECT().CreateAccount(gomock.Any()),
+		dbMock.EXPECT().AddBalance(gomock.Any(), gomock.Any(), gomock.Any()),
+		dbMock.EXPECT().CreateAccount(txgenerator.FunderAddress),
+		dbMock.EXPECT().AddBalance(txgenerator.FunderAddress, gomock.Any(), gomock.Any()),
+		dbMock.EXPECT().EndTransaction(),
+		dbMock.EXPECT().EndBlock(),
+
+		dbMock.EXPECT().BeginTransaction(gomock.Any()),
+		dbMock.EXPECT().GetNonce(gomock.Any()).Return(uint64(0)),
+		dbMock.EXPECT().EndTransaction(),
+
+		consumer.EXPECT().Consume(2, 0, gomock.Any()).DoAndReturn(record),
+		consumer.EXPECT().Consume(2, 1, gomock.Any()).DoAndReturn(record),
+		consumer.EXPECT().Consume(3, 0, gomock.Any()).DoAndReturn(record),
+		consumer.EXPECT().Consume(3, 1, gomock.Any()).DoAndReturn(record),
+		consumer.EXPECT().Consume(4, 0, gomock.Any()).DoAndReturn(record),
+		consumer.EXPECT().Consume(4, 1, gomock.Any()).DoAndReturn(record),
+	)
+
+	require.NoError(t, provider.Run(1, 4, toSubstateConsumer(consumer)))
+	assert.Len(t, codes, 6, "each deployed contract must be unique")
+}
+
+func TestNormaTxProvider_RunFailsOnInvalidCreateRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := state.NewMockStateDB(ctrl)
+
+	cfg := &utils.Config{
+		BlockLength:           uint64(2),
+		TxGeneratorType:       []string{"create"},
+		TxGeneratorAccounts:   1,
+		TxGeneratorCreateRate: 2,
+		ChainID:               297,
+	}
+	provider := NewNormaTxProvider(cfg, dbMock)
+
+	dbMock.EXPECT().BeginBlock(gomock.Any())
+	dbMock.EXPECT().BeginTransaction(gomock.Any())
+	dbMock.EXPECT().CreateAccount(gomock.Any()).Times(2)
+	dbMock.EXPECT().AddBalance(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+	dbMock.EXPECT().EndTransaction()
+	dbMock.EXPECT().EndBlock()
+
+	err := provider.Run(1, 2, toSubstateConsumer(NewMockTxConsumer(ctrl)))
+	require.ErrorContains(t, err, "create rate")
+}
+
 func TestFakeRpcClient_CodeAt(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,206 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/core"
+)
+
+// MakePipelinedProvider wraps the given provider so that up to --pipeline-depth blocks following
+// the currently executed block are fetched and prepared on worker goroutines. Transactions are
+// still forwarded to the consumer strictly in order. If pipelining is disabled, the provider is
+// returned unchanged.
+func MakePipelinedProvider(provider Provider[txcontext.TxContext], cfg *utils.Config) (Provider[txcontext.TxContext], error) {
+	if cfg.PipelineDepth < 0 {
+		return nil, fmt.Errorf("invalid pipeline depth %d; must not be negative", cfg.PipelineDepth)
+	}
+	if cfg.PipelineDepth == 0 {
+		return provider, nil
+	}
+	return makePipelinedProvider(provider, cfg.PipelineDepth, prefetchTxContext), nil
+}
+
+// PrefetchFunc prepares the payload of a transaction before it is forwarded to the consumer.
+// It is called concurrently for transactions of different blocks, hence it has to be thread safe.
+type PrefetchFunc[T any] func(TransactionInfo[T]) (T, error)
+
+func makePipelinedProvider[T any](provider Provider[T], depth int, prefetch PrefetchFunc[T]) *pipelinedProvider[T] {
+	return &pipelinedProvider[T]{
+		Provider: provider,
+		depth:    depth,
+		prefetch: prefetch,
+	}
+}
+
+type pipelinedProvider[T any] struct {
+	Provider[T]
+	depth    int // maximum number of blocks prepared ahead of the consumer
+	prefetch PrefetchFunc[T]
+}
+
+// pipelinedBlock is a block of transactions prepared ahead of its execution.
+type pipelinedBlock[T any] struct {
+	txs   []TransactionInfo[T]
+	err   error         // reported to the consumer after all transactions of the block were forwarded
+	ready chan struct{} // closed once the block is prepared
+}
+
+func (p *pipelinedProvider[T]) Run(from int, to int, consumer Consumer[T]) error {
+	// Each block holds a slot from the begin of its preparation until it is consumed. Since the
+	// queue can hold as many blocks as there are slots, sending a block never blocks.
+	slots := make(chan struct{}, p.depth)
+	blocks := make(chan *pipelinedBlock[T], p.depth)
+	abort := utils.MakeEvent()
+	abortErr := errors.New("aborted")
+
+	var wg sync.WaitGroup
+	submit := func(block *pipelinedBlock[T]) error {
+		select {
+		case slots <- struct{}{}:
+		case <-abort.Wait():
+			return abortErr
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				close(block.ready)
+				wg.Done()
+			}()
+			if block.err == nil {
+				block.err = p.prepare(block)
+			}
+		}()
+		blocks <- block
+		return nil
+	}
+
+	// Start one go-routine forwarding blocks from the provider to the preparation.
+	wg.Add(1)
+	go func() {
+		defer func() {
+			close(blocks)
+			wg.Done()
+		}()
+		var current *pipelinedBlock[T]
+		err := p.Provider.Run(from, to, func(tx TransactionInfo[T]) error {
+			if current != nil && current.txs[0].Block != tx.Block {
+				if err := submit(current); err != nil {
+					return err
+				}
+				current = nil
+			}
+			if current == nil {
+				current = &pipelinedBlock[T]{ready: make(chan struct{})}
+			}
+			current.txs = append(current.txs, tx)
+			return nil
+		})
+		if err == abortErr {
+			return
+		}
+		if current != nil {
+			if submit(current) != nil {
+				return
+			}
+		}
+		// a provider error is reported after all transactions fetched before it were consumed
+		if err != nil {
+			submit(&pipelinedBlock[T]{err: err, ready: make(chan struct{})})
+		}
+	}()
+
+	var err error
+	for block := range blocks {
+		<-block.ready
+		for _, tx := range block.txs {
+			if err = consumer(tx); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = block.err
+		}
+		<-slots
+		if err != nil {
+			break
+		}
+	}
+
+	// stop the forwarding and wait for all preparations to finish
+	abort.Signal()
+	wg.Wait()
+	return err
+}
+
+// prepare applies the prefetch function to all transactions of the block. If it fails, the
+// block is truncated before the failing transaction, so that the error is reported exactly
+// where it would have occurred without pipelining.
+func (p *pipelinedProvider[T]) prepare(block *pipelinedBlock[T]) error {
+	for i := range block.txs {
+		data, err := p.prefetch(block.txs[i])
+		if err != nil {
+			tx := block.txs[i]
+			block.txs = block.txs[:i]
+			return fmt.Errorf("cannot prefetch transaction %d of block %d; %w", tx.Transaction, tx.Block, err)
+		}
+		block.txs[i].Data = data
+	}
+	return nil
+}
+
+// prefetchTxContext constructs the message and the input state of the transaction ahead of
+// its execution. A malformed payload is reported as an error instead of failing the execution.
+func prefetchTxContext(tx TransactionInfo[txcontext.TxContext]) (data txcontext.TxContext, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed transaction payload; %v", r)
+		}
+	}()
+	if tx.Data == nil {
+		return nil, errors.New("missing transaction payload")
+	}
+	msg := tx.Data.GetMessage()
+	if msg == nil {
+		return nil, errors.New("missing transaction message")
+	}
+	return &prefetchedTxContext{
+		TxContext:  tx.Data,
+		message:    msg,
+		inputState: tx.Data.GetInputState(),
+	}, nil
+}
+
+// prefetchedTxContext serves the message and the input state prepared by the prefetch.
+type prefetchedTxContext struct {
+	txcontext.TxContext
+	message    *core.Message
+	inputState txcontext.WorldState
+}
+
+func (t *prefetchedTxContext) GetMessage() *core.Message {
+	return t.message
+}
+
+func (t *prefetchedTxContext) GetInputState() txcontext.WorldState {
+	return t.inputState
+}
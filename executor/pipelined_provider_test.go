@@ -0,0 +1,230 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// runBlocksOf returns a provider run forwarding txsPerBlock transactions for each block in
+// the range. The payload of each transaction is its position in the range. If failAt is
+// reached, the run is aborted with given error.
+func runBlocksOf(txsPerBlock int, failAt int, failure error) func(int, int, Consumer[int]) error {
+	return func(from int, to int, consumer Consumer[int]) error {
+		pos := 0
+		for block := from; block < to; block++ {
+			for tx := 0; tx < txsPerBlock; tx++ {
+				if pos == failAt {
+					return failure
+				}
+				if err := consumer(TransactionInfo[int]{Block: block, Transaction: tx, Data: pos}); err != nil {
+					return err
+				}
+				pos++
+			}
+		}
+		return nil
+	}
+}
+
+func TestMakePipelinedProvider_ReturnsProviderUnchangedIfDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[txcontext.TxContext](ctrl)
+	res, err := MakePipelinedProvider(provider, &utils.Config{})
+	require.NoError(t, err)
+	assert.Equal(t, provider, res)
+}
+
+func TestMakePipelinedProvider_RejectsNegativeDepth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[txcontext.TxContext](ctrl)
+	_, err := MakePipelinedProvider(provider, &utils.Config{PipelineDepth: -1})
+	require.ErrorContains(t, err, "invalid pipeline depth")
+}
+
+func TestPipelinedProvider_PreservesTransactionOrder(t *testing.T) {
+	for _, depth := range []int{1, 2, 8} {
+		ctrl := gomock.NewController(t)
+		inner := NewMockProvider[int](ctrl)
+		inner.EXPECT().Run(10, 60, gomock.Any()).DoAndReturn(runBlocksOf(3, -1, nil))
+
+		// prefetches finish in random order
+		provider := makePipelinedProvider[int](inner, depth, func(tx TransactionInfo[int]) (int, error) {
+			time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+			return tx.Data * 2, nil
+		})
+
+		var consumed []TransactionInfo[int]
+		err := provider.Run(10, 60, func(tx TransactionInfo[int]) error {
+			consumed = append(consumed, tx)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, consumed, 150)
+		for i, tx := range consumed {
+			assert.Equal(t, 10+i/3, tx.Block)
+			assert.Equal(t, i%3, tx.Transaction)
+			assert.Equal(t, 2*i, tx.Data, "payload was not prefetched")
+		}
+	}
+}
+
+func TestPipelinedProvider_PrefetchErrorAbortsAtFailingBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inner := NewMockProvider[int](ctrl)
+	inner.EXPECT().Run(0, 100, gomock.Any()).DoAndReturn(runBlocksOf(2, -1, nil))
+
+	injected := errors.New("injected")
+	provider := makePipelinedProvider[int](inner, 4, func(tx TransactionInfo[int]) (int, error) {
+		if tx.Block == 7 && tx.Transaction == 1 {
+			return 0, injected
+		}
+		return tx.Data, nil
+	})
+
+	var last TransactionInfo[int]
+	count := 0
+	err := provider.Run(0, 100, func(tx TransactionInfo[int]) error {
+		last = tx
+		count++
+		return nil
+	})
+	require.ErrorIs(t, err, injected)
+	require.ErrorContains(t, err, "cannot prefetch transaction 1 of block 7")
+	// all transactions preceding the failing one have been consumed, none after it
+	assert.Equal(t, 15, count)
+	assert.Equal(t, TransactionInfo[int]{Block: 7, Transaction: 0, Data: 14}, last)
+}
+
+func TestPipelinedProvider_ProviderErrorIsReportedAfterPrecedingTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inner := NewMockProvider[int](ctrl)
+	injected := errors.New("injected")
+	inner.EXPECT().Run(0, 100, gomock.Any()).DoAndReturn(runBlocksOf(2, 11, injected))
+
+	provider := makePipelinedProvider[int](inner, 4, func(tx TransactionInfo[int]) (int, error) {
+		return tx.Data, nil
+	})
+
+	count := 0
+	err := provider.Run(0, 100, func(tx TransactionInfo[int]) error {
+		count++
+		return nil
+	})
+	require.ErrorIs(t, err, injected)
+	assert.Equal(t, 11, count)
+}
+
+func TestPipelinedProvider_ConsumerErrorStopsProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inner := NewMockProvider[int](ctrl)
+	inner.EXPECT().Run(0, 1000, gomock.Any()).DoAndReturn(runBlocksOf(1, -1, nil))
+
+	provider := makePipelinedProvider[int](inner, 2, func(tx TransactionInfo[int]) (int, error) {
+		return tx.Data, nil
+	})
+
+	injected := errors.New("injected")
+	err := provider.Run(0, 1000, func(tx TransactionInfo[int]) error {
+		if tx.Block == 5 {
+			return injected
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, injected)
+}
+
+func TestPipelinedProvider_NumberOfBlocksPreparedAheadIsBounded(t *testing.T) {
+	const depth = 3
+	ctrl := gomock.NewController(t)
+	inner := NewMockProvider[int](ctrl)
+	inner.EXPECT().Run(0, 50, gomock.Any()).DoAndReturn(runBlocksOf(2, -1, nil))
+
+	var prepared atomic.Int64
+	provider := makePipelinedProvider[int](inner, depth, func(tx TransactionInfo[int]) (int, error) {
+		if tx.Transaction == 0 {
+			prepared.Add(1)
+		}
+		return tx.Data, nil
+	})
+
+	consumed := 0
+	err := provider.Run(0, 50, func(tx TransactionInfo[int]) error {
+		// a slow consumer gives the pipeline the chance to run ahead
+		time.Sleep(100 * time.Microsecond)
+		if ahead := prepared.Load() - int64(consumed); ahead > depth {
+			t.Errorf("%d blocks prepared ahead of block %d, limit is %d", ahead, tx.Block, depth)
+		}
+		if tx.Transaction == 1 {
+			consumed++
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 50, consumed)
+}
+
+func TestPrefetchTxContext_CachesMessageAndInputState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	data := txcontext.NewMockTxContext(ctrl)
+	input := txcontext.NewMockWorldState(ctrl)
+	msg := &core.Message{Nonce: 1}
+	data.EXPECT().GetMessage().Return(msg)
+	data.EXPECT().GetInputState().Return(input)
+
+	res, err := prefetchTxContext(TransactionInfo[txcontext.TxContext]{Data: data})
+	require.NoError(t, err)
+
+	// further calls are served without consulting the original payload
+	assert.Same(t, msg, res.GetMessage())
+	assert.Same(t, msg, res.GetMessage())
+	assert.Equal(t, input, res.GetInputState())
+}
+
+func TestPrefetchTxContext_ReportsMalformedPayload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	missingMsg := txcontext.NewMockTxContext(ctrl)
+	missingMsg.EXPECT().GetMessage().Return(nil)
+	panicking := txcontext.NewMockTxContext(ctrl)
+	panicking.EXPECT().GetMessage().DoAndReturn(func() *core.Message { panic("nil message") })
+
+	tests := map[string]struct {
+		data     txcontext.TxContext
+		expected string
+	}{
+		"missing payload": {nil, "missing transaction payload"},
+		"missing message": {missingMsg, "missing transaction message"},
+		"panic":           {panicking, "malformed transaction payload; nil message"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := prefetchTxContext(TransactionInfo[txcontext.TxContext]{Data: test.data})
+			require.ErrorContains(t, err, test.expected)
+		})
+	}
+}
@@ -29,6 +29,15 @@ type Provider[T any] interface {
 	Close()
 }
 
+// LazyPayload is implemented by payloads forwarded by a Provider in encoded form.
+// The executor decodes them in the worker right before their execution, hence
+// the number of decoded payloads is bounded by the workers, not by the buffers
+// between the Provider and the workers.
+type LazyPayload interface {
+	// Decode decodes the payload unless it has been decoded already.
+	Decode() error
+}
+
 // Consumer is a type alias for the type of function to which payload information
 // can be forwarded by a Provider.
 type Consumer[T any] func(TransactionInfo[T]) error
@@ -0,0 +1,151 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/0xsoniclabs/aida/ethtest"
+	"github.com/0xsoniclabs/aida/rpc"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/urfave/cli/v2"
+)
+
+// Names of providers shipped with Aida.
+const (
+	SubstateProviderName    = "substate"
+	TxGeneratorProviderName = "tx-generator"
+	EthTestProviderName     = "ethtest"
+	RpcProviderName         = "rpc"
+)
+
+// TxProviders is the registry of providers of transactions executed by the aida-vm tools.
+// Projects embedding Aida may register their own providers and select them by --provider.
+var TxProviders = NewProviderRegistry[txcontext.TxContext]()
+
+// RpcProviders is the registry of providers of RPC requests replayed by aida-rpc.
+var RpcProviders = NewProviderRegistry[*rpc.RequestAndResults]()
+
+func init() {
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+	must(TxProviders.Register(SubstateProviderName, func(cfg *utils.Config, env ProviderEnvironment) (Provider[txcontext.TxContext], error) {
+		if env.AidaDb == nil {
+			return nil, errors.New("substate provider requires an aida-db")
+		}
+		return OpenSubstateProvider(cfg, env.Cli, env.AidaDb)
+	}))
+	must(TxProviders.Register(TxGeneratorProviderName, func(cfg *utils.Config, env ProviderEnvironment) (Provider[txcontext.TxContext], error) {
+		if env.StateDb == nil {
+			return nil, errors.New("tx-generator provider requires a StateDb")
+		}
+		return NewNormaTxProvider(cfg, env.StateDb), nil
+	}))
+	must(TxProviders.Register(EthTestProviderName, func(cfg *utils.Config, env ProviderEnvironment) (Provider[txcontext.TxContext], error) {
+		return NewEthStateTestProviderWithResults(cfg, env.EthTestResults), nil
+	}))
+	must(RpcProviders.Register(RpcProviderName, func(cfg *utils.Config, env ProviderEnvironment) (Provider[*rpc.RequestAndResults], error) {
+		if env.Cli == nil {
+			return nil, errors.New("rpc provider requires a command line context")
+		}
+		return OpenRpcRecording(cfg, env.Cli)
+	}))
+}
+
+// ProviderEnvironment holds resources opened by the running command, which providers may use.
+// Fields not used by the command are nil.
+type ProviderEnvironment struct {
+	Cli            *cli.Context          // command line context of the run
+	AidaDb         db.BaseDB             // opened aida-db
+	StateDb        state.StateDB         // StateDb the payload is executed on
+	EthTestResults *ethtest.ResultMatrix // results of ethereum tests to which skipped tests are recorded
+}
+
+// ProviderFactory creates a provider for the given configuration.
+type ProviderFactory[T any] func(cfg *utils.Config, env ProviderEnvironment) (Provider[T], error)
+
+// ProviderRegistry maps provider names to their factories. It is safe for concurrent use.
+type ProviderRegistry[T any] struct {
+	lock      sync.Mutex
+	factories map[string]ProviderFactory[T]
+}
+
+// NewProviderRegistry creates an empty registry.
+func NewProviderRegistry[T any]() *ProviderRegistry[T] {
+	return &ProviderRegistry[T]{
+		factories: make(map[string]ProviderFactory[T]),
+	}
+}
+
+// Register adds a factory under the given name. Names must be unique.
+func (r *ProviderRegistry[T]) Register(name string, factory ProviderFactory[T]) error {
+	if name == "" || factory == nil {
+		return errors.New("provider must have a name and a factory")
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, found := r.factories[name]; found {
+		return fmt.Errorf("provider %q is already registered", name)
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+// Names returns the sorted names of all registered providers.
+func (r *ProviderRegistry[T]) Names() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Open creates the provider registered under the given name.
+func (r *ProviderRegistry[T]) Open(name string, cfg *utils.Config, env ProviderEnvironment) (Provider[T], error) {
+	r.lock.Lock()
+	factory, found := r.factories[name]
+	r.lock.Unlock()
+	if !found {
+		return nil, fmt.Errorf("unknown provider %q; registered providers: %v", name, r.Names())
+	}
+	provider, err := factory(cfg, env)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open provider %q; %w", name, err)
+	}
+	return provider, nil
+}
+
+// OpenSelected creates the provider selected by --provider, or the default provider of the command if not set.
+func (r *ProviderRegistry[T]) OpenSelected(cfg *utils.Config, defaultName string, env ProviderEnvironment) (Provider[T], error) {
+	name := cfg.Provider
+	if name == "" {
+		name = defaultName
+	}
+	return r.Open(name, cfg, env)
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProviderRegistry_RegisterRejectsInvalidEntries(t *testing.T) {
+	registry := NewProviderRegistry[txcontext.TxContext]()
+	factory := func(*utils.Config, ProviderEnvironment) (Provider[txcontext.TxContext], error) {
+		return nil, nil
+	}
+
+	require.ErrorContains(t, registry.Register("", factory), "must have a name")
+	require.ErrorContains(t, registry.Register("a", nil), "must have a name")
+	require.NoError(t, registry.Register("a", factory))
+	require.ErrorContains(t, registry.Register("a", factory), `provider "a" is already registered`)
+}
+
+func TestProviderRegistry_NamesAreSorted(t *testing.T) {
+	registry := NewProviderRegistry[txcontext.TxContext]()
+	factory := func(*utils.Config, ProviderEnvironment) (Provider[txcontext.TxContext], error) {
+		return nil, nil
+	}
+	for _, name := range []string{"c", "a", "b"} {
+		require.NoError(t, registry.Register(name, factory))
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, registry.Names())
+}
+
+func TestProviderRegistry_OpenPassesConfigAndEnvironment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[txcontext.TxContext](ctrl)
+	cfg := &utils.Config{First: 5}
+
+	registry := NewProviderRegistry[txcontext.TxContext]()
+	require.NoError(t, registry.Register("mock", func(c *utils.Config, env ProviderEnvironment) (Provider[txcontext.TxContext], error) {
+		assert.Same(t, cfg, c)
+		assert.Nil(t, env.AidaDb)
+		return provider, nil
+	}))
+
+	got, err := registry.Open("mock", cfg, ProviderEnvironment{})
+	require.NoError(t, err)
+	assert.Same(t, provider, got)
+}
+
+func TestProviderRegistry_OpenFailsForUnknownProvider(t *testing.T) {
+	registry := NewProviderRegistry[txcontext.TxContext]()
+	require.NoError(t, registry.Register("known", func(*utils.Config, ProviderEnvironment) (Provider[txcontext.TxContext], error) {
+		return nil, nil
+	}))
+
+	_, err := registry.Open("unknown", &utils.Config{}, ProviderEnvironment{})
+	require.ErrorContains(t, err, `unknown provider "unknown"; registered providers: [known]`)
+}
+
+func TestProviderRegistry_OpenWrapsFactoryError(t *testing.T) {
+	injectedErr := errors.New("injected error")
+	registry := NewProviderRegistry[txcontext.TxContext]()
+	require.NoError(t, registry.Register("failing", func(*utils.Config, ProviderEnvironment) (Provider[txcontext.TxContext], error) {
+		return nil, injectedErr
+	}))
+
+	_, err := registry.Open("failing", &utils.Config{}, ProviderEnvironment{})
+	require.ErrorIs(t, err, injectedErr)
+	require.ErrorContains(t, err, `cannot open provider "failing"`)
+}
+
+func TestProviderRegistry_OpenSelectedPrefersConfiguredProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defaultProvider := NewMockProvider[txcontext.TxContext](ctrl)
+	customProvider := NewMockProvider[txcontext.TxContext](ctrl)
+
+	registry := NewProviderRegistry[txcontext.TxContext]()
+	require.NoError(t, registry.Register("default", func(*utils.Config, ProviderEnvironment) (Provider[txcontext.TxContext], error) {
+		return defaultProvider, nil
+	}))
+	require.NoError(t, registry.Register("custom", func(*utils.Config, ProviderEnvironment) (Provider[txcontext.TxContext], error) {
+		return customProvider, nil
+	}))
+
+	got, err := registry.OpenSelected(&utils.Config{}, "default", ProviderEnvironment{})
+	require.NoError(t, err)
+	assert.Same(t, defaultProvider, got)
+
+	got, err = registry.OpenSelected(&utils.Config{Provider: "custom"}, "default", ProviderEnvironment{})
+	require.NoError(t, err)
+	assert.Same(t, customProvider, got)
+}
+
+func TestProviderRegistry_BuiltinProvidersAreRegistered(t *testing.T) {
+	assert.Subset(t, TxProviders.Names(), []string{SubstateProviderName, TxGeneratorProviderName, EthTestProviderName})
+	assert.Equal(t, []string{RpcProviderName}, RpcProviders.Names())
+}
+
+func TestProviderRegistry_BuiltinProvidersCheckEnvironment(t *testing.T) {
+	cfg := &utils.Config{}
+
+	_, err := TxProviders.Open(SubstateProviderName, cfg, ProviderEnvironment{})
+	require.ErrorContains(t, err, "requires an aida-db")
+
+	_, err = TxProviders.Open(TxGeneratorProviderName, cfg, ProviderEnvironment{})
+	require.ErrorContains(t, err, "requires a StateDb")
+
+	_, err = RpcProviders.Open(RpcProviderName, cfg, ProviderEnvironment{})
+	require.ErrorContains(t, err, "requires a command line context")
+}
@@ -28,12 +28,22 @@ import (
 )
 
 func OpenRpcRecording(cfg *utils.Config, ctx *cli.Context) (Provider[*rpc.RequestAndResults], error) {
+	log := logger.NewLogger(cfg.LogLevel, "rpc-provider")
+
+	// recording streamed live over a websocket
+	if rpc.IsWebSocketUrl(cfg.RpcRecordingPath) {
+		iter, err := rpc.NewWebSocketReader(ctx.Context, cfg.RpcRecordingPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open rpc recording stream; %w", err)
+		}
+		return openRpcRecording(iter, cfg, log, ctx, []string{cfg.RpcRecordingPath}), nil
+	}
+
 	fileInfo, err := os.Stat(cfg.RpcRecordingPath)
 	if err != nil {
 		return nil, fmt.Errorf("cannot stat the rpc path; %w", err)
 	}
 
-	log := logger.NewLogger(cfg.LogLevel, "rpc-provider")
 	if !fileInfo.IsDir() {
 		iter, err := rpc.NewFileReader(ctx.Context, cfg.RpcRecordingPath)
 		if err != nil {
@@ -98,11 +108,6 @@ func (r *rpcRequestProvider) Run(from int, to int, consumer Consumer[*rpc.Reques
 			return errors.New("iterator returned nil request")
 		}
 
-		// get logs is not yet implemented, skip these for now
-		if req.Query.MethodBase == "getLogs" {
-			continue
-		}
-
 		req.DecodeInfo()
 		// are we skipping requests?
 		if req.RecordedBlock < from {
@@ -157,11 +162,6 @@ func (r *rpcRequestProvider) processFirst(from int, consumer Consumer[*rpc.Reque
 			return nil
 		}
 
-		// get logs is not yet implemented, skip these for now
-		if req.Query.MethodBase == "getLogs" {
-			return nil
-		}
-
 		if err := consumer(TransactionInfo[*rpc.RequestAndResults]{req.RecordedBlock, 0, req}); err != nil {
 			return err
 		}
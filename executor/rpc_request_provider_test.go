@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"sort"
@@ -143,7 +145,7 @@ func TestRPCRequestProvider_ErrorReturnedByIteratorEndsTheApp(t *testing.T) {
 	}
 }
 
-func TestRPCRequestProvider_GetLogMethodDoesNotEndIteration(t *testing.T) {
+func TestRPCRequestProvider_GetLogsRequestsAreForwarded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	consumer := NewMockRPCReqConsumer(ctrl)
 	i := rpc.NewMockIterator(ctrl)
@@ -158,9 +160,11 @@ func TestRPCRequestProvider_GetLogMethodDoesNotEndIteration(t *testing.T) {
 		i.EXPECT().Next().Return(true),
 		i.EXPECT().Error().Return(nil),
 		i.EXPECT().Value().Return(logResp),
+		consumer.EXPECT().Consume(10, 0, logResp),
 		i.EXPECT().Next().Return(true),
 		i.EXPECT().Error().Return(nil),
 		i.EXPECT().Value().Return(logResp),
+		consumer.EXPECT().Consume(10, 0, logResp),
 		i.EXPECT().Next().Return(false),
 		i.EXPECT().Close(),
 	)
@@ -354,6 +358,19 @@ func TestOpenRpcRecording(t *testing.T) {
 		assert.Contains(t, err.Error(), "cannot stat the rpc path")
 	})
 
+	t.Run("Error_WebSocketUnreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := "ws" + strings.TrimPrefix(server.URL, "http")
+		server.Close()
+
+		cfg := &utils.Config{RpcRecordingPath: url}
+		provider, err := OpenRpcRecording(cfg, cliCtx)
+
+		assert.Error(t, err)
+		assert.Nil(t, provider)
+		assert.Contains(t, err.Error(), "cannot open rpc recording stream")
+	})
+
 	t.Run("Error_RpcNewFileReader_SingleFile_Unreadable", func(t *testing.T) {
 		tmpFile, err := os.CreateTemp(baseDir, "unreadable*.rpc")
 		require.NoError(t, err)
@@ -611,7 +628,7 @@ func TestRpcRequestProvider_Run_IteratorReturnsNilValueInLoop(t *testing.T) {
 	assert.EqualError(t, err, "iterator returned nil request")
 }
 
-func TestRpcRequestProvider_Run_GetLogsSkippedInLoop(t *testing.T) {
+func TestRpcRequestProvider_Run_GetLogsForwardedInLoop(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockIter := rpc.NewMockIterator(ctrl)
@@ -633,6 +650,7 @@ func TestRpcRequestProvider_Run_GetLogsSkippedInLoop(t *testing.T) {
 	mockIter.EXPECT().Next().Return(true)
 	mockIter.EXPECT().Error().Return(nil)
 	mockIter.EXPECT().Value().Return(getLogsReq)
+	mockConsumer.EXPECT().Consume(5, 0, getLogsReq).Return(nil)
 
 	mockIter.EXPECT().Next().Return(true)
 	mockIter.EXPECT().Error().Return(nil)
@@ -0,0 +1,143 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+// MakeSkipListProvider wraps the given provider so that transactions listed in the skip list
+// configured by --skip-list are not executed. Skipped transactions are still forwarded to the
+// consumer to preserve the block structure, but processors apply their recorded output alloc
+// instead of running them. If no skip list is configured, the provider is returned unchanged.
+func MakeSkipListProvider(provider Provider[txcontext.TxContext], cfg *utils.Config) (Provider[txcontext.TxContext], error) {
+	if cfg.SkipList == "" {
+		return provider, nil
+	}
+	list, err := readSkipList(cfg.SkipList)
+	if err != nil {
+		return nil, err
+	}
+	return makeSkipListProvider(provider, list, logger.NewLogger(cfg.LogLevel, "Skip-List")), nil
+}
+
+func makeSkipListProvider(provider Provider[txcontext.TxContext], list map[skipListKey]string, log logger.Logger) *skipListProvider {
+	return &skipListProvider{
+		Provider: provider,
+		list:     list,
+		log:      log,
+	}
+}
+
+// skipListKey identifies a listed transaction.
+type skipListKey struct {
+	block int
+	tx    int
+}
+
+type skipListProvider struct {
+	Provider[txcontext.TxContext]
+	list map[skipListKey]string // reasons of listed transactions
+	log  logger.Logger
+}
+
+func (p *skipListProvider) Run(from int, to int, consumer Consumer[txcontext.TxContext]) error {
+	skipped := make(map[skipListKey]struct{})
+	err := p.Provider.Run(from, to, func(info TransactionInfo[txcontext.TxContext]) error {
+		key := skipListKey{info.Block, info.Transaction}
+		if reason, found := p.list[key]; found {
+			p.log.Infof("Transaction %v/%v skipped by policy; %v", info.Block, info.Transaction, reason)
+			skipped[key] = struct{}{}
+			info.Data = skippedTx{info.Data}
+		}
+		return consumer(info)
+	})
+
+	p.log.Noticef("%v transactions skipped by policy", len(skipped))
+	if err != nil {
+		return err
+	}
+
+	// the list is stale if it contains transactions which do not exist in the range
+	for key := range p.list {
+		if _, found := skipped[key]; !found && key.block >= from && key.block < to {
+			p.log.Warningf("Transaction %v/%v of the skip list was not found; the skip list may be stale", key.block, key.tx)
+		}
+	}
+	return nil
+}
+
+// skippedTx marks a transaction which is not executed because it is listed in the skip list.
+type skippedTx struct {
+	txcontext.TxContext
+}
+
+// IsSkippedByPolicy returns true if the transaction is listed in the skip list,
+// hence it is not executed and its recorded output alloc is applied instead.
+func IsSkippedByPolicy(data txcontext.TxContext) bool {
+	_, skipped := data.(skippedTx)
+	return skipped
+}
+
+// readSkipList parses the skip list file. Each line contains a block number,
+// a transaction number and a reason separated by whitespaces. Empty lines and
+// lines starting with # are ignored.
+func readSkipList(path string) (map[skipListKey]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open skip list; %w", err)
+	}
+	defer file.Close()
+
+	list := make(map[skipListKey]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid skip list entry at line %d; expected <block> <tx> <reason>", line)
+		}
+		block, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid block number at line %d of skip list; %w", line, err)
+		}
+		tx, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction number at line %d of skip list; %w", line, err)
+		}
+		key := skipListKey{block, tx}
+		if _, found := list[key]; found {
+			return nil, fmt.Errorf("duplicate skip list entry %v/%v at line %d", block, tx, line)
+		}
+		list[key] = strings.Join(fields[2:], " ")
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read skip list; %w", err)
+	}
+	return list, nil
+}
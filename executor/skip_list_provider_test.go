@@ -0,0 +1,155 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReadSkipList_ParsesEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "skip-list")
+	content := "# known non-replayable transactions\n\n10 2 depends on consensus data\n  12 0 epoch sealing  \n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	list, err := readSkipList(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[skipListKey]string{
+		{10, 2}: "depends on consensus data",
+		{12, 0}: "epoch sealing",
+	}, list)
+}
+
+func TestReadSkipList_RejectsInvalidEntries(t *testing.T) {
+	tests := map[string]struct {
+		content  string
+		expected string
+	}{
+		"missing reason": {"10 2\n", "invalid skip list entry at line 1"},
+		"invalid block":  {"# comment\nx 2 reason\n", "invalid block number at line 2"},
+		"invalid tx":     {"10 y reason\n", "invalid transaction number at line 1"},
+		"duplicate":      {"10 2 reason\n10 2 other\n", "duplicate skip list entry 10/2 at line 2"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "skip-list")
+			require.NoError(t, os.WriteFile(path, []byte(test.content), 0644))
+			_, err := readSkipList(path)
+			require.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestMakeSkipListProvider_ReturnsProviderUnchangedWithoutSkipList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[txcontext.TxContext](ctrl)
+	res, err := MakeSkipListProvider(provider, &utils.Config{})
+	require.NoError(t, err)
+	assert.Equal(t, provider, res)
+}
+
+func TestMakeSkipListProvider_MissingFileCausesError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[txcontext.TxContext](ctrl)
+	_, err := MakeSkipListProvider(provider, &utils.Config{SkipList: filepath.Join(t.TempDir(), "missing")})
+	require.ErrorContains(t, err, "cannot open skip list")
+}
+
+func TestSkipListProvider_MarksListedTransactionsAndPreservesBlockStructure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inner := NewMockProvider[txcontext.TxContext](ctrl)
+	consumer := NewMockTxConsumer(ctrl)
+	log := logger.NewMockLogger(ctrl)
+	tx := txcontext.NewMockTxContext(ctrl)
+
+	list := map[skipListKey]string{{10, 1}: "reason"}
+	provider := makeSkipListProvider(inner, list, log)
+
+	inner.EXPECT().Run(10, 12, gomock.Any()).DoAndReturn(func(_ int, _ int, consumer Consumer[txcontext.TxContext]) error {
+		for _, info := range []TransactionInfo[txcontext.TxContext]{{10, 0, tx}, {10, 1, tx}, {11, 0, tx}} {
+			if err := consumer(info); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	gomock.InOrder(
+		consumer.EXPECT().Consume(10, 0, gomock.Any()).DoAndReturn(func(_ int, _ int, data txcontext.TxContext) error {
+			assert.False(t, IsSkippedByPolicy(data))
+			return nil
+		}),
+		log.EXPECT().Infof(gomock.Any(), 10, 1, "reason"),
+		consumer.EXPECT().Consume(10, 1, gomock.Any()).DoAndReturn(func(_ int, _ int, data txcontext.TxContext) error {
+			assert.True(t, IsSkippedByPolicy(data))
+			return nil
+		}),
+		consumer.EXPECT().Consume(11, 0, gomock.Any()),
+		log.EXPECT().Noticef("%v transactions skipped by policy", 1),
+	)
+
+	require.NoError(t, provider.Run(10, 12, toSubstateConsumer(consumer)))
+}
+
+func TestSkipListProvider_WarnsAboutStaleEntriesInRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inner := NewMockProvider[txcontext.TxContext](ctrl)
+	log := logger.NewMockLogger(ctrl)
+
+	list := map[skipListKey]string{{10, 1}: "in range", {20, 0}: "out of range"}
+	provider := makeSkipListProvider(inner, list, log)
+
+	inner.EXPECT().Run(10, 12, gomock.Any()).Return(nil)
+	log.EXPECT().Noticef("%v transactions skipped by policy", 0)
+	log.EXPECT().Warningf(gomock.Any(), 10, 1)
+
+	require.NoError(t, provider.Run(10, 12, toSubstateConsumer(NewMockTxConsumer(ctrl))))
+}
+
+func TestTxProcessor_SkippedTransactionAppliesOutputAlloc(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockVmStateDB(ctrl)
+	tx := txcontext.NewMockTxContext(ctrl)
+
+	addr := common.Address{1}
+	tx.EXPECT().GetOutputState().Return(txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		addr: txcontext.NewAccount(nil, map[common.Hash]common.Hash{{1}: {2}}, big.NewInt(5), 3),
+	}))
+	db.EXPECT().GetBalance(addr).Return(uint256.NewInt(1))
+	db.EXPECT().SubBalance(addr, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+	db.EXPECT().AddBalance(addr, uint256.NewInt(5), tracing.BalanceChangeUnspecified)
+	db.EXPECT().SetNonce(addr, uint64(3), tracing.NonceChangeUnspecified)
+	db.EXPECT().SetCode(addr, gomock.Any(), tracing.CodeChangeUnspecified)
+	db.EXPECT().SetState(addr, common.Hash{1}, common.Hash{2})
+
+	processor, err := MakeTxProcessor(&utils.Config{})
+	require.NoError(t, err)
+	_, err = processor.ProcessTransaction(db, 10, 1, skippedTx{tx})
+	require.NoError(t, err)
+}
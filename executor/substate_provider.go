@@ -19,11 +19,18 @@ package executor
 //go:generate mockgen -source substate_provider.go -destination substate_provider_mocks.go -package executor
 
 import (
+	"bytes"
+	"fmt"
+
 	"github.com/0xsoniclabs/aida/txcontext"
 	substatecontext "github.com/0xsoniclabs/aida/txcontext/substate"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/0xsoniclabs/substate/db"
+	pb "github.com/0xsoniclabs/substate/protobuf"
+	"github.com/0xsoniclabs/substate/rlp"
+	"github.com/0xsoniclabs/substate/substate"
 	"github.com/urfave/cli/v2"
+	"google.golang.org/protobuf/proto"
 )
 
 // ----------------------------------------------------------------------------
@@ -36,11 +43,26 @@ func OpenSubstateProvider(cfg *utils.Config, ctxt *cli.Context, aidaDb db.BaseDB
 	if err != nil {
 		return nil, err
 	}
-	return &substateProvider{
+	source := &substateProvider{
 		db:                  substateDb,
 		ctxt:                ctxt,
 		numParallelDecoders: cfg.Workers,
-	}, nil
+	}
+	if cfg.LazySubstateDecoding {
+		// prefetching would decode the substates ahead of their execution again
+		if cfg.PipelineDepth > 0 {
+			return nil, fmt.Errorf("--%v cannot be combined with --%v", utils.LazySubstateDecodingFlag.Name, utils.PipelineDepthFlag.Name)
+		}
+		source.decode, err = makeSubstateDecoder(substateDb)
+		if err != nil {
+			return nil, err
+		}
+	}
+	provider, err := MakePipelinedProvider(source, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return MakeSkipListProvider(provider, cfg)
 }
 
 // substateProvider is an adapter of Aida's SubstateProvider interface defined above to the
@@ -49,9 +71,13 @@ type substateProvider struct {
 	db                  db.SubstateDB
 	ctxt                *cli.Context
 	numParallelDecoders int
+	decode              substatecontext.DecodeFunc // non-nil if substates are forwarded encoded, see LazyPayload
 }
 
 func (s substateProvider) Run(from int, to int, consumer Consumer[txcontext.TxContext]) error {
+	if s.decode != nil {
+		return s.runLazy(from, to, consumer)
+	}
 	iter := s.db.NewSubstateIterator(from, s.numParallelDecoders)
 	for iter.Next() {
 		tx := iter.Value()
@@ -70,6 +96,51 @@ func (s substateProvider) Run(from int, to int, consumer Consumer[txcontext.TxCo
 	return iter.Error()
 }
 
+// runLazy forwards the substates in their encoded form, leaving the decoding to the workers.
+func (s substateProvider) runLazy(from int, to int, consumer Consumer[txcontext.TxContext]) error {
+	iter := s.db.NewIterator([]byte(db.SubstateDBPrefix), db.BlockToBytes(uint64(from)))
+	defer iter.Release()
+	for iter.Next() {
+		block, tx, err := db.DecodeSubstateDBKey(iter.Key())
+		if err != nil {
+			return fmt.Errorf("cannot decode substate key; %w", err)
+		}
+		if block >= uint64(to) {
+			return nil
+		}
+		// the iterator reuses the buffer of the value
+		encoded := bytes.Clone(iter.Value())
+		if err = consumer(TransactionInfo[txcontext.TxContext]{int(block), tx, substatecontext.NewLazyTxContext(encoded, block, tx, s.decode)}); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// makeSubstateDecoder creates a decoder of substates stored in the encoding of given database.
+func makeSubstateDecoder(substateDb db.SubstateDB) (substatecontext.DecodeFunc, error) {
+	switch schema := substateDb.GetSubstateEncoding(); schema {
+	case db.RLPEncodingSchema:
+		return func(encoded []byte, block uint64, tx int) (*substate.Substate, error) {
+			decoded, err := rlp.Decode(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("cannot decode substate data from rlp block: %v, tx %v; %w", block, tx, err)
+			}
+			return decoded.ToSubstate(substateDb.GetCode, block, tx)
+		}, nil
+	case db.ProtobufEncodingSchema, db.LegacyProtobufEncodingAlias, db.DefaultEncodingSchema, "":
+		return func(encoded []byte, block uint64, tx int) (*substate.Substate, error) {
+			decoded := &pb.Substate{}
+			if err := proto.Unmarshal(encoded, decoded); err != nil {
+				return nil, fmt.Errorf("cannot decode substate data from protobuf block: %v, tx %v; %w", block, tx, err)
+			}
+			return decoded.Decode(substateDb.GetCode, block, tx)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported substate encoding %v", schema)
+	}
+}
+
 func (s substateProvider) Close() {
 	// ignored, database is opened it top-most level
 }
@@ -269,3 +269,103 @@ func TestSubstateProvider_Close(t *testing.T) {
 		provider.Close()
 	})
 }
+
+func TestSubstateProvider_LazyDecodingProvidesSameSubstatesAsEagerDecoding(t *testing.T) {
+	path := t.TempDir()
+	if err := createSubstateDb(t, path); err != nil {
+		t.Fatalf("failed to setup test DB: %v", err)
+	}
+	aidaDb, err := db.NewReadOnlySubstateDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aidaDb.Close()
+
+	collect := func(lazy bool) []TransactionInfo[txcontext.TxContext] {
+		cfg := &utils.Config{Workers: 1, LazySubstateDecoding: lazy}
+		provider, err := OpenSubstateProvider(cfg, nil, aidaDb)
+		if err != nil {
+			t.Fatalf("cannot open provider: %v", err)
+		}
+		defer provider.Close()
+
+		var infos []TransactionInfo[txcontext.TxContext]
+		err = provider.Run(0, 20, func(info TransactionInfo[txcontext.TxContext]) error {
+			infos = append(infos, info)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("cannot run provider: %v", err)
+		}
+		return infos
+	}
+
+	eager := collect(false)
+	lazy := collect(true)
+	if !assert.Equal(t, len(eager), len(lazy)) {
+		return
+	}
+	for i := range eager {
+		assert.Equal(t, eager[i].Block, lazy[i].Block)
+		assert.Equal(t, eager[i].Transaction, lazy[i].Transaction)
+
+		payload, ok := lazy[i].Data.(LazyPayload)
+		if !assert.True(t, ok, "lazy provider must forward encoded payloads") {
+			continue
+		}
+		assert.NoError(t, payload.Decode())
+		assert.Equal(t, eager[i].Data.GetMessage(), lazy[i].Data.GetMessage())
+		assert.Equal(t, eager[i].Data.GetBlockEnvironment(), lazy[i].Data.GetBlockEnvironment())
+		assert.Equal(t, eager[i].Data.GetInputState(), lazy[i].Data.GetInputState())
+		assert.Equal(t, eager[i].Data.GetOutputState(), lazy[i].Data.GetOutputState())
+		assert.Equal(t, eager[i].Data.GetResult(), lazy[i].Data.GetResult())
+	}
+}
+
+func TestSubstateProvider_LazyDecodingUpperBoundIsExclusive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	consumer := NewMockTxConsumer(ctrl)
+
+	path := t.TempDir()
+	if err := createSubstateDb(t, path); err != nil {
+		t.Fatalf("failed to setup test DB: %v", err)
+	}
+	aidaDb, err := db.NewReadOnlySubstateDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aidaDb.Close()
+
+	provider, err := OpenSubstateProvider(&utils.Config{Workers: 1, LazySubstateDecoding: true}, nil, aidaDb)
+	assert.NoError(t, err)
+	defer provider.Close()
+
+	gomock.InOrder(
+		consumer.EXPECT().Consume(10, 7, gomock.Any()),
+		consumer.EXPECT().Consume(10, 9, gomock.Any()),
+	)
+
+	if err := provider.Run(10, 12, toSubstateConsumer(consumer)); err != nil {
+		t.Fatalf("failed to iterate through states: %v", err)
+	}
+}
+
+func TestSubstateProvider_LazyDecodingCannotBeCombinedWithPipelining(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBaseDb := db.NewMockBaseDB(ctrl)
+	mockBaseDb.EXPECT().GetBackend().Return(db.NewMockDbAdapter(ctrl))
+	mockBaseDb.EXPECT().GetSubstateEncoding().Return(db.DefaultEncodingSchema)
+
+	cfg := &utils.Config{LazySubstateDecoding: true, PipelineDepth: 4}
+	_, err := OpenSubstateProvider(cfg, nil, mockBaseDb)
+	assert.ErrorContains(t, err, "--lazy-substate-decoding cannot be combined with --pipeline-depth")
+}
+
+func TestSubstateProvider_MakeSubstateDecoderRejectsUnsupportedEncoding(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDb := db.NewMockSubstateDB(ctrl)
+	mockDb.EXPECT().GetSubstateEncoding().Return(db.SubstateEncodingSchema("unknown"))
+
+	_, err := makeSubstateDecoder(mockDb)
+	assert.ErrorContains(t, err, "unsupported substate encoding unknown")
+}
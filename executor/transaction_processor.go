@@ -22,10 +22,12 @@ import (
 	"math/big"
 	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/0xsoniclabs/aida/ethtest"
 	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/profile/opcodeprofile"
 	"github.com/0xsoniclabs/aida/state"
 	"github.com/0xsoniclabs/aida/txcontext"
 	"github.com/0xsoniclabs/aida/utils"
@@ -69,7 +71,7 @@ func (p *LiveDbTxProcessor) Process(state State[txcontext.TxContext], ctx *Conte
 	}
 
 	if !p.isErrFatal() {
-		ctx.ErrorInput <- fmt.Errorf("live-db processor failed; %v", err)
+		ctx.ErrorInput <- NewTxError(ProcessorComponent, state.Block, state.Transaction, ErrTxProcessing, fmt.Errorf("live-db processor failed; %v", err))
 		return nil
 	}
 
@@ -99,7 +101,7 @@ func (p *ArchiveDbTxProcessor) Process(state State[txcontext.TxContext], ctx *Co
 	}
 
 	if !p.isErrFatal() {
-		ctx.ErrorInput <- fmt.Errorf("archive-db processor failed; %v", err)
+		ctx.ErrorInput <- NewTxError(ProcessorComponent, state.Block, state.Transaction, ErrTxProcessing, fmt.Errorf("archive-db processor failed; %v", err))
 		return nil
 	}
 
@@ -193,6 +195,23 @@ func (p *ethTestProcessor) Process(state State[txcontext.TxContext], ctx *Contex
 	return nil
 }
 
+func init() {
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+	for _, info := range []utils.VmImplementationInfo{
+		{Name: "opera", Description: "Aida's processor based on go-ethereum's state transition with Sonic rules, the default"},
+		{Name: "ethereum", Description: "Aida's processor based on go-ethereum's state transition with Ethereum rules"},
+		{Name: "floria", Description: "Tosca's processor, the interpreter is selected by --vm-impl", FirstFork: "Istanbul", LastFork: "Osaka", Tosca: true},
+		{Name: "geth", Description: "go-ethereum's processor wrapped by Tosca, the interpreter is selected by --vm-impl", FirstFork: "Istanbul", LastFork: "Osaka", Tosca: true},
+	} {
+		info.Kind = utils.ProcessorImplementation
+		must(utils.RegisterVmImplementation(info))
+	}
+}
+
 type TxProcessor struct {
 	cfg       *utils.Config
 	numErrors *atomic.Int32 // transactions can be processed in parallel, so this needs to be thread safe
@@ -206,17 +225,22 @@ func MakeTxProcessor(cfg *utils.Config) (*TxProcessor, error) {
 	case "", "opera", "ethereum":
 		processor = makeAidaProcessor(cfg)
 	default:
+		if cfg.MicroProfiling {
+			return nil, fmt.Errorf("micro-profiling is not supported by the %v processor; use opera or ethereum", cfg.EvmImpl)
+		}
 		interpreter, err := tosca.NewInterpreter(cfg.VmImpl)
 		if err != nil {
 			available := maps.Keys(tosca.GetAllRegisteredInterpreters())
-			return nil, fmt.Errorf("failed to create interpreter %s, error %v, supported: %v", cfg.VmImpl, err, available)
+			return nil, fmt.Errorf("failed to create interpreter %s, error %v, supported: %v%v", cfg.VmImpl, err, available,
+				utils.FormatVmImplementationSuggestions(utils.InterpreterImplementation, cfg.VmImpl))
 		}
 		evm := tosca.GetProcessor(cfg.EvmImpl, interpreter)
 		if evm == nil {
 			available := maps.Keys(tosca.GetAllRegisteredProcessorFactories())
 			available = append(available, "opera", "ethereum")
 			slices.Sort(available)
-			return nil, fmt.Errorf("unknown EVM implementation: %s, supported: %v", cfg.EvmImpl, available)
+			return nil, fmt.Errorf("unknown EVM implementation: %s, supported: %v%v", cfg.EvmImpl, available,
+				utils.FormatVmImplementationSuggestions(utils.ProcessorImplementation, cfg.EvmImpl))
 		}
 
 		processor = &toscaProcessor{
@@ -253,12 +277,22 @@ func (s *TxProcessor) isErrFatal() bool {
 }
 
 func (s *TxProcessor) ProcessTransaction(db state.VmStateDB, block int, tx int, st txcontext.TxContext) (txcontext.Result, error) {
-	if tx >= utils.PseudoTx {
+	// transactions skipped by policy are not executed, their recorded effects are applied instead
+	if tx >= utils.PseudoTx || IsSkippedByPolicy(st) {
 		return s.processPseudoTx(st.GetOutputState(), db), nil
 	}
 	return s.processor.processRegularTx(db, block, tx, st)
 }
 
+// OpcodeProfile returns the per-opcode statistics of all processed transactions
+// or nil if micro-profiling is disabled.
+func (s *TxProcessor) OpcodeProfile() *opcodeprofile.Profile {
+	if p, ok := s.processor.(*aidaProcessor); ok {
+		return p.opcodes
+	}
+	return nil
+}
+
 type processor interface {
 	processRegularTx(db state.VmStateDB, block int, tx int, st txcontext.TxContext) (transactionResult, error)
 }
@@ -266,15 +300,24 @@ type processor interface {
 type aidaProcessor struct {
 	cfg *utils.Config
 	log logger.Logger
+
+	// deepCompareUnsupported makes sure the missing post-alloc support is reported only once.
+	deepCompareUnsupported sync.Once
+
+	opcodes *opcodeprofile.Profile // nil if micro-profiling is disabled
 }
 
 // for testing purposes
 func makeAidaProcessor(cfg *utils.Config) *aidaProcessor {
 	evmImpl := strings.ToLower(cfg.EvmImpl)
-	return &aidaProcessor{
+	p := &aidaProcessor{
 		cfg: cfg,
 		log: logger.NewLogger(cfg.LogLevel, fmt.Sprintf("AidaProcessor(%s)", evmImpl)),
 	}
+	if cfg.MicroProfiling {
+		p.opcodes = opcodeprofile.NewProfile()
+	}
+	return p
 }
 
 // executionResult is a wrapper around ExecutionResult so both types from core and evmcore can be used.
@@ -325,14 +368,36 @@ func (s *aidaProcessor) processRegularTx(db state.VmStateDB, block int, tx int,
 		return res, fmt.Errorf("cannot get chain config: %w", err)
 	}
 
+	// the write journal has to outlive the execution so that
+	// divergent slots can be attributed to the writing call frame
+	var journal *writeJournal
+	vmCfg := s.cfg.VmCfg
+	if s.cfg.DeepOutputCompare {
+		journal = newWriteJournal(db)
+		db = journal
+		vmCfg.Tracer = journal.hooks()
+	}
+	if s.opcodes != nil {
+		collector := s.opcodes.NewCollector()
+		defer collector.Flush()
+		vmCfg.Tracer = collector.Hooks(vmCfg.Tracer)
+	}
+
 	db.SetTxContext(txHash, tx)
 	snapshot := db.Snapshot()
 	blockCtx := utils.PrepareBlockCtx(inputEnv, &hashError)
-	evm := vm.NewEVM(*blockCtx, db, chainCfg, s.cfg.VmCfg)
+	evm := vm.NewEVM(*blockCtx, db, chainCfg, vmCfg)
 
-	var msgResult messageResult
+	var (
+		msgResult       messageResult
+		executionResult *core.ExecutionResult
+	)
 	gasPool := core.NewGasPool(inputEnv.GetGasLimit())
-	executionResult, err := core.ApplyMessage(evm, msg, gasPool)
+	// a gas limit below the intrinsic gas can only come from a broken recording
+	err = utils.CheckIntrinsicGas(msg, utils.GetRules(chainCfg, inputEnv))
+	if err == nil {
+		executionResult, err = core.ApplyMessage(evm, msg, gasPool)
+	}
 	if err != nil {
 		// if transaction fails, revert to the first snapshot.
 		db.RevertToSnapshot(snapshot)
@@ -345,6 +410,11 @@ func (s *aidaProcessor) processRegularTx(db state.VmStateDB, block int, tx int,
 			gasUsed:    executionResult.UsedGas,
 			err:        executionResult.Err,
 		}
+		if journal != nil {
+			if err = s.deepOutputCompare(journal, st.GetOutputState()); err != nil {
+				finalError = fmt.Errorf("block: %v transaction: %v; deep output compare failed; %w", block, tx, err)
+			}
+		}
 	}
 
 	// inform about failing transaction
@@ -363,6 +433,20 @@ func (s *aidaProcessor) processRegularTx(db state.VmStateDB, block int, tx int,
 	return
 }
 
+// deepOutputCompare compares the post-alloc of the executed transaction against
+// the recorded output alloc. StateDb implementations not able to provide
+// a post-alloc are skipped.
+func (s *aidaProcessor) deepOutputCompare(journal *writeJournal, want txcontext.WorldState) error {
+	have := journal.GetSubstatePostAlloc()
+	if have == nil {
+		s.deepCompareUnsupported.Do(func() {
+			s.log.Warning("Used StateDb does not provide post-alloc; deep output compare is skipped")
+		})
+		return nil
+	}
+	return journal.compare(want, have)
+}
+
 // processPseudoTx processes pseudo transactions in Lachesis by applying the change in db state.
 // The pseudo transactions includes Lachesis SFC, lachesis genesis and lachesis-opera transition.
 func (s *TxProcessor) processPseudoTx(ws txcontext.WorldState, db state.VmStateDB) txcontext.Result {
@@ -453,6 +537,10 @@ func (t *toscaProcessor) processRegularTx(db state.VmStateDB, int, tx int, st tx
 		db:               db,
 	}
 
+	if err = utils.CheckIntrinsicGas(message, utils.GetRules(chainCfg, blockEnvironment)); err != nil {
+		return transactionResult{err: err}, err
+	}
+
 	receipt, err := processor.Run(blockParams, transaction, context)
 	if err != nil {
 		return transactionResult{err: err}, err
@@ -473,10 +561,7 @@ func (t *toscaProcessor) processRegularTx(db state.VmStateDB, int, tx int, st tx
 	msg := st.GetMessage()
 
 	if !receipt.Success {
-		// The actual error is not relevant. Anything
-		// that is not equal to nil will be considered
-		// as a failed execution that got rolled back.
-		err = fmt.Errorf("transaction failed")
+		err = toscaFailureError(receipt, transaction.GasLimit)
 	}
 
 	result := &messageResult{
@@ -489,6 +574,19 @@ func (t *toscaProcessor) processRegularTx(db state.VmStateDB, int, tx int, st tx
 	return newTransactionResult(log, msg, result, finalError, msg.From), nil
 }
 
+// toscaFailureError derives the vm error of a failed execution from the tosca receipt.
+// Tosca does not report why the execution failed, however, only a revert returns data
+// and leaves gas unused, whereas any other failure consumes all the gas.
+func toscaFailureError(receipt tosca.Receipt, gasLimit tosca.Gas) error {
+	if len(receipt.Output) > 0 || receipt.GasUsed < gasLimit {
+		return txcontext.NewRevertError(receipt.Output)
+	}
+	// The actual error is not relevant. Anything
+	// that is not equal to nil will be considered
+	// as a failed execution that got rolled back.
+	return fmt.Errorf("transaction failed")
+}
+
 func messageToTransaction(message *core.Message) tosca.Transaction {
 	gasFeeCap := message.GasFeeCap
 	gasTipCap := message.GasTipCap
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 
@@ -31,11 +32,16 @@ import (
 	substatecontext "github.com/0xsoniclabs/aida/txcontext/substate"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/0xsoniclabs/substate/substate"
+	substatetypes "github.com/0xsoniclabs/substate/types"
 	"github.com/0xsoniclabs/tosca/go/tosca"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -131,6 +137,92 @@ func TestMakeTxProcessor_InvalidEvmImplCausesError(t *testing.T) {
 	require.Contains(t, err.Error(), "unknown EVM implementation: invalid")
 }
 
+func TestMakeTxProcessor_MisspelledImplSuggestsCloseMatches(t *testing.T) {
+	cfg := &utils.Config{
+		ChainID: utils.OperaMainnetChainID,
+		EvmImpl: "flora",
+		VmImpl:  "lfvm",
+	}
+	_, err := MakeTxProcessor(cfg)
+	require.ErrorContains(t, err, "did you mean floria?")
+
+	cfg.EvmImpl = "floria"
+	cfg.VmImpl = "lfmv"
+	_, err = MakeTxProcessor(cfg)
+	require.ErrorContains(t, err, "did you mean lfvm")
+}
+
+func TestMakeTxProcessor_MicroProfilingIsNotSupportedByToscaProcessors(t *testing.T) {
+	cfg := &utils.Config{
+		ChainID:        utils.OperaMainnetChainID,
+		EvmImpl:        "floria",
+		VmImpl:         "lfvm",
+		MicroProfiling: true,
+	}
+	_, err := MakeTxProcessor(cfg)
+	require.ErrorContains(t, err, "micro-profiling is not supported by the floria processor")
+}
+
+func TestTxProcessor_OpcodeProfileIsNilWithoutMicroProfiling(t *testing.T) {
+	cfg := utils.NewTestConfig(t, utils.SonicMainnetChainID, 1, 1, false, "")
+	p, err := MakeTxProcessor(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, p.OpcodeProfile())
+}
+
+func TestTxProcessor_MicroProfilingCountsOpcodesOfAllWorkers(t *testing.T) {
+	const numTxs = 8
+	cfg := utils.NewTestConfig(t, utils.SonicMainnetChainID, 1, 1, false, "")
+	cfg.MicroProfiling = true
+	p, err := MakeTxProcessor(cfg)
+	require.NoError(t, err)
+	profile := p.OpcodeProfile()
+	require.NotNil(t, profile)
+
+	// stores the first calldata word into slot 0
+	code := []byte{byte(vm.PUSH1), 0, byte(vm.CALLDATALOAD), byte(vm.PUSH1), 0, byte(vm.SSTORE), byte(vm.STOP)}
+	sender, contract := common.Address{1}, common.Address{2}
+	random := substatetypes.Hash{1}
+	env := substate.NewEnv(substatetypes.Address{3}, big.NewInt(0), 100_000_000, 1, 1_700_000_000,
+		big.NewInt(1_000_000_000), big.NewInt(1), nil, &random)
+
+	// transactions are processed in parallel as with --workers > 1
+	var wg sync.WaitGroup
+	errs := make([]error, numTxs)
+	for tx := range numTxs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db := state.MakeInMemoryStateDB(txcontext.NewWorldState(map[common.Address]txcontext.Account{
+				sender:   txcontext.NewAccount(nil, map[common.Hash]common.Hash{}, big.NewInt(1e18), 0),
+				contract: txcontext.NewAccount(code, map[common.Hash]common.Hash{}, big.NewInt(0), 1),
+			}), 1)
+			gasPrice := big.NewInt(2_000_000_000)
+			to := substatetypes.Address(contract)
+			txType := int32(substate.LegacyTxType)
+			msg := substate.NewMessage(0, true, gasPrice, 100_000, substatetypes.Address(sender), &to, big.NewInt(0),
+				common.BigToHash(big.NewInt(int64(tx+1))).Bytes(), nil, &txType, substatetypes.AccessList{}, gasPrice, gasPrice, nil, nil, nil)
+			ss := &substate.Substate{Env: env, Message: msg, Block: 1, Transaction: tx}
+
+			res, err := p.ProcessTransaction(db, 1, tx, substatecontext.NewTxContext(ss))
+			if err == nil && res.GetReceipt().GetStatus() != types.ReceiptStatusSuccessful {
+				err = fmt.Errorf("transaction %v failed", tx)
+			}
+			errs[tx] = err
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, errors.Join(errs...))
+
+	assert.Equal(t, uint64(2*numTxs), profile.Get(vm.PUSH1).Count)
+	assert.Equal(t, uint64(numTxs), profile.Get(vm.CALLDATALOAD).Count)
+	assert.Equal(t, uint64(numTxs), profile.Get(vm.SSTORE).Count)
+	assert.Equal(t, uint64(numTxs), profile.Get(vm.STOP).Count)
+	assert.Equal(t, uint64(2*numTxs*3), profile.Get(vm.PUSH1).Gas)
+	assert.NotZero(t, profile.Get(vm.SSTORE).Gas)
+	assert.Zero(t, profile.Get(vm.ADD).Count)
+}
+
 func TestEthTestProcessor_DoesNotExecuteTransactionWhenBlobGasCouldExceed(t *testing.T) {
 	p, err := MakeEthTestProcessor(&utils.Config{})
 	if err != nil {
@@ -1340,6 +1432,134 @@ func TestToscaProcessor_processRegularTx(t *testing.T) {
 	})
 }
 
+func TestToscaProcessor_processRegularTx_RejectsGasLimitBelowIntrinsicGas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStateDB := state.NewMockVmStateDB(ctrl)
+	mockTxContext := txcontext.NewMockTxContext(ctrl)
+	mockBlockEnv := txcontext.NewMockBlockEnvironment(ctrl)
+	mockToscaProcessor := tosca.NewMockProcessor(ctrl)
+
+	recipient := common.Address{1}
+	message := &core.Message{
+		To:       &recipient,
+		Value:    big.NewInt(0),
+		GasLimit: 21_047,
+		GasPrice: big.NewInt(50),
+		Data:     []byte{1, 2, 3},
+	}
+
+	mockTxContext.EXPECT().GetBlockEnvironment().Return(mockBlockEnv).AnyTimes()
+	mockTxContext.EXPECT().GetMessage().Return(message).AnyTimes()
+	mockBlockEnv.EXPECT().GetFork().Return("cancun").AnyTimes()
+	mockBlockEnv.EXPECT().GetNumber().Return(uint64(12345)).AnyTimes()
+	mockBlockEnv.EXPECT().GetTimestamp().Return(uint64(1700000000)).AnyTimes()
+	mockBlockEnv.EXPECT().GetBaseFee().Return(big.NewInt(5)).AnyTimes()
+	mockBlockEnv.EXPECT().GetBlobBaseFee().Return(big.NewInt(10)).AnyTimes()
+	mockBlockEnv.EXPECT().GetGasLimit().Return(uint64(30000000)).AnyTimes()
+	mockBlockEnv.EXPECT().GetCoinbase().Return(common.Address{}).AnyTimes()
+	mockBlockEnv.EXPECT().GetDifficulty().Return(big.NewInt(2)).AnyTimes()
+	mockBlockEnv.EXPECT().GetRandom().Return(&common.Hash{}).AnyTimes()
+	// the transaction must not reach the processor
+	mockToscaProcessor.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	processor := &toscaProcessor{
+		processor: mockToscaProcessor,
+		cfg:       &utils.Config{ChainID: utils.OperaMainnetChainID},
+		log:       logger.NewLogger("info", "dummy logger"),
+	}
+
+	result, err := processor.processRegularTx(mockStateDB, 12345, 1, mockTxContext)
+	require.ErrorContains(t, err, "gas limit 21047 is below intrinsic gas 21048")
+	assert.Equal(t, err, result.err)
+}
+
+func TestToscaProcessor_processRegularTx_AttachesRevertReasonToFailedTransaction(t *testing.T) {
+	errorData := append(common.FromHex("0x08c379a0"), common.LeftPadBytes([]byte{0x20}, 32)...)
+	errorData = append(errorData, common.LeftPadBytes([]byte{4}, 32)...)
+	errorData = append(errorData, common.RightPadBytes([]byte("boom"), 32)...)
+	panicData := append(common.FromHex("0x4e487b71"), common.LeftPadBytes([]byte{0x11}, 32)...)
+
+	tests := []struct {
+		name       string
+		receipt    tosca.Receipt
+		wantRevert bool
+		wantReason string
+	}{
+		{
+			name:       "plain revert",
+			receipt:    tosca.Receipt{GasUsed: 25_000},
+			wantRevert: true,
+		},
+		{
+			name:       "revert with string",
+			receipt:    tosca.Receipt{GasUsed: 25_000, Output: errorData},
+			wantRevert: true,
+			wantReason: "boom",
+		},
+		{
+			name:       "panic code",
+			receipt:    tosca.Receipt{GasUsed: 25_000, Output: panicData},
+			wantRevert: true,
+			wantReason: "arithmetic underflow or overflow",
+		},
+		{
+			name:    "non-revert failure",
+			receipt: tosca.Receipt{GasUsed: 50_000},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStateDB := state.NewMockVmStateDB(ctrl)
+			mockTxContext := txcontext.NewMockTxContext(ctrl)
+			mockBlockEnv := txcontext.NewMockBlockEnvironment(ctrl)
+			mockToscaProcessor := tosca.NewMockProcessor(ctrl)
+
+			recipient := common.Address{1}
+			message := &core.Message{
+				To:       &recipient,
+				Value:    big.NewInt(0),
+				GasLimit: 50_000,
+				GasPrice: big.NewInt(50),
+			}
+
+			mockTxContext.EXPECT().GetBlockEnvironment().Return(mockBlockEnv).AnyTimes()
+			mockTxContext.EXPECT().GetMessage().Return(message).AnyTimes()
+			mockBlockEnv.EXPECT().GetFork().Return("cancun").AnyTimes()
+			mockBlockEnv.EXPECT().GetNumber().Return(uint64(12345)).AnyTimes()
+			mockBlockEnv.EXPECT().GetTimestamp().Return(uint64(1700000000)).AnyTimes()
+			mockBlockEnv.EXPECT().GetBaseFee().Return(big.NewInt(5)).AnyTimes()
+			mockBlockEnv.EXPECT().GetBlobBaseFee().Return(big.NewInt(10)).AnyTimes()
+			mockBlockEnv.EXPECT().GetGasLimit().Return(uint64(30000000)).AnyTimes()
+			mockBlockEnv.EXPECT().GetCoinbase().Return(common.Address{}).AnyTimes()
+			mockBlockEnv.EXPECT().GetDifficulty().Return(big.NewInt(2)).AnyTimes()
+			mockBlockEnv.EXPECT().GetRandom().Return(&common.Hash{}).AnyTimes()
+			mockToscaProcessor.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).Return(test.receipt, nil)
+
+			processor := &toscaProcessor{
+				processor: mockToscaProcessor,
+				cfg:       &utils.Config{ChainID: utils.OperaMainnetChainID},
+				log:       logger.NewLogger("info", "dummy logger"),
+			}
+
+			result, err := processor.processRegularTx(mockStateDB, 12345, 1, mockTxContext)
+			require.NoError(t, err)
+			assert.Nil(t, result.err)
+			assert.Equal(t, types.ReceiptStatusFailed, result.status)
+
+			var revertErr *txcontext.RevertError
+			require.Equal(t, test.wantRevert, errors.As(result.GetVmError(), &revertErr))
+			if !test.wantRevert {
+				require.Error(t, result.GetVmError())
+				return
+			}
+			assert.ErrorIs(t, result.GetVmError(), vm.ErrExecutionReverted)
+			assert.Equal(t, test.wantReason, revertErr.Reason)
+		})
+	}
+}
+
 // TestMessageResult_Failed tests the Failed method of messageResult
 func TestMessageResult_Failed(t *testing.T) {
 	testCases := []struct {
@@ -1830,6 +2050,144 @@ func TestAidaProcessor_processRegularTx(t *testing.T) {
 	})
 }
 
+func TestAidaProcessor_processRegularTx_RejectsGasLimitBelowIntrinsicGas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStateDB := state.NewMockVmStateDB(ctrl)
+	mockBlockEnv := txcontext.NewMockBlockEnvironment(ctrl)
+	mockTxContext := txcontext.NewMockTxContext(ctrl)
+
+	block, tx := 12345, 1
+	txHash := common.HexToHash(fmt.Sprintf("0x%016d%016d", block, tx))
+	blockHash := common.HexToHash(fmt.Sprintf("0x%016d", block))
+	recipient := common.Address{1}
+	message := &core.Message{
+		To:       &recipient,
+		Value:    big.NewInt(0),
+		GasLimit: 21_047,
+		GasPrice: big.NewInt(50),
+		Data:     []byte{1, 2, 3},
+	}
+
+	mockTxContext.EXPECT().GetBlockEnvironment().Return(mockBlockEnv).AnyTimes()
+	mockTxContext.EXPECT().GetMessage().Return(message).AnyTimes()
+	mockBlockEnv.EXPECT().GetFork().Return("cancun").AnyTimes()
+	mockBlockEnv.EXPECT().GetGasLimit().Return(uint64(30000000)).AnyTimes()
+	mockBlockEnv.EXPECT().GetNumber().Return(uint64(block)).AnyTimes()
+	mockBlockEnv.EXPECT().GetTimestamp().Return(uint64(1700000000)).AnyTimes()
+	mockBlockEnv.EXPECT().GetBaseFee().Return(big.NewInt(5)).AnyTimes()
+	mockBlockEnv.EXPECT().GetBlobBaseFee().Return(big.NewInt(10)).AnyTimes()
+	mockBlockEnv.EXPECT().GetCoinbase().Return(common.Address{}).AnyTimes()
+	mockBlockEnv.EXPECT().GetDifficulty().Return(big.NewInt(2)).AnyTimes()
+	mockBlockEnv.EXPECT().GetRandom().Return(&common.Hash{}).AnyTimes()
+
+	gomock.InOrder(
+		mockStateDB.EXPECT().SetTxContext(txHash, tx),
+		mockStateDB.EXPECT().Snapshot().Return(7),
+		mockStateDB.EXPECT().RevertToSnapshot(7),
+		mockStateDB.EXPECT().GetLogs(txHash, uint64(block), blockHash, uint64(1700000000)).Return(nil),
+	)
+
+	processor := &aidaProcessor{
+		cfg: &utils.Config{ChainID: utils.OperaMainnetChainID},
+		log: logger.NewLogger("info", "test"),
+	}
+
+	result, err := processor.processRegularTx(mockStateDB, block, tx, mockTxContext)
+	require.ErrorContains(t, err, "gas limit 21047 is below intrinsic gas 21048")
+	assert.Equal(t, err, result.err)
+	assert.Zero(t, result.gasUsed)
+}
+
+func TestAidaProcessor_processRegularTx_AppliesSetCodeAuthorizations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStateDB := state.NewMockVmStateDB(ctrl)
+
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	require.NoError(t, err)
+	authority := crypto.PubkeyToAddress(key.PublicKey)
+	delegate := common.HexToAddress("0x7702770277027702770277027702770277027702")
+	auth, err := types.SignSetCode(key, types.SetCodeAuthorization{
+		ChainID: *uint256.NewInt(uint64(utils.EthereumChainID)),
+		Address: delegate,
+		Nonce:   3,
+	})
+	require.NoError(t, err)
+
+	// a post-Prague mainnet block carrying a type-4 transaction
+	block, tx := 22_500_000, 4
+	timestamp := uint64(1_750_000_000)
+	txHash := common.HexToHash(fmt.Sprintf("0x%016d%016d", block, tx))
+	blockHash := common.HexToHash(fmt.Sprintf("0x%016d", block))
+	sender := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	recipient := common.HexToAddress("0x0987654321098765432109876543210987654321")
+
+	st := substatecontext.NewTxContext(&substate.Substate{
+		Env: &substate.Env{
+			Coinbase:    substatetypes.Address{0xc0},
+			GasLimit:    30_000_000,
+			Number:      uint64(block),
+			Timestamp:   timestamp,
+			BaseFee:     big.NewInt(5),
+			BlobBaseFee: big.NewInt(1),
+			Difficulty:  big.NewInt(0),
+			Random:      &substatetypes.Hash{},
+		},
+		Message: &substate.Message{
+			From:       substatetypes.Address(sender),
+			To:         (*substatetypes.Address)(&recipient),
+			Nonce:      10,
+			Value:      big.NewInt(0),
+			Gas:        50_000,
+			GasPrice:   big.NewInt(6),
+			GasFeeCap:  big.NewInt(6),
+			GasTipCap:  big.NewInt(1),
+			CheckNonce: true,
+			SetCodeAuthorizations: []substatetypes.SetCodeAuthorization{{
+				ChainID: auth.ChainID,
+				Address: substatetypes.Address(auth.Address),
+				Nonce:   auth.Nonce,
+				V:       auth.V,
+				R:       auth.R,
+				S:       auth.S,
+			}},
+		},
+	})
+
+	// incidental accesses of the state transition
+	mockStateDB.EXPECT().Snapshot().Return(1).AnyTimes()
+	mockStateDB.EXPECT().GetBalance(gomock.Any()).Return(uint256.NewInt(1_000_000_000)).AnyTimes()
+	mockStateDB.EXPECT().SubBalance(gomock.Any(), gomock.Any(), gomock.Any()).Return(*uint256.NewInt(0)).AnyTimes()
+	mockStateDB.EXPECT().AddBalance(gomock.Any(), gomock.Any(), gomock.Any()).Return(*uint256.NewInt(0)).AnyTimes()
+	mockStateDB.EXPECT().GetCode(gomock.Any()).Return(nil).AnyTimes()
+	mockStateDB.EXPECT().Exist(gomock.Any()).Return(true).AnyTimes()
+	mockStateDB.EXPECT().AddAddressToAccessList(gomock.Any()).AnyTimes()
+	mockStateDB.EXPECT().Prepare(gomock.Any(), sender, gomock.Any(), &recipient, gomock.Any(), gomock.Any())
+	mockStateDB.EXPECT().GetNonce(sender).Return(uint64(10)).AnyTimes()
+	mockStateDB.EXPECT().SetTxContext(txHash, tx)
+	mockStateDB.EXPECT().SetNonce(sender, uint64(11), tracing.NonceChangeEoACall)
+	mockStateDB.EXPECT().GetLogs(txHash, uint64(block), blockHash, timestamp).Return(nil)
+
+	// the delegation designation is installed for the authority of the signed tuple
+	mockStateDB.EXPECT().GetNonce(authority).Return(uint64(3)).AnyTimes()
+	mockStateDB.EXPECT().SetNonce(authority, uint64(4), tracing.NonceChangeAuthorization)
+	mockStateDB.EXPECT().SetCode(authority, types.AddressToDelegation(delegate), tracing.CodeChangeAuthorization)
+
+	// the existing authority is refunded the new account cost, capped at a fifth of the gas used
+	mockStateDB.EXPECT().AddRefund(params.CallNewAccountGas - params.TxAuthTupleGas)
+	mockStateDB.EXPECT().GetRefund().Return(params.CallNewAccountGas - params.TxAuthTupleGas).AnyTimes()
+
+	processor := &aidaProcessor{
+		cfg: &utils.Config{ChainID: utils.EthereumChainID},
+		log: logger.NewLogger("info", "test"),
+	}
+
+	result, err := processor.processRegularTx(mockStateDB, block, tx, st)
+	require.NoError(t, err)
+	require.NoError(t, result.err)
+	intrinsicGas := params.TxGas + params.CallNewAccountGas
+	assert.Equal(t, intrinsicGas-intrinsicGas/params.RefundQuotientEIP3529, result.gasUsed)
+}
+
 func TestEthTestProcessor_Process(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -17,12 +17,14 @@
 package executor
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/0xsoniclabs/aida/txcontext"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -34,6 +36,7 @@ type transactionResult struct {
 	logs            []*types.Log
 	contractAddress common.Address
 	gasUsed         uint64
+	vmErr           error
 }
 
 func (r transactionResult) GetReceipt() txcontext.Receipt {
@@ -65,6 +68,12 @@ func (r transactionResult) GetContractAddress() common.Address {
 	return r.contractAddress
 }
 
+// GetVmError returns the reason of a failed execution. Reverted executions
+// are reported as *txcontext.RevertError.
+func (r transactionResult) GetVmError() error {
+	return r.vmErr
+}
+
 func (r transactionResult) Equal(y txcontext.Receipt) bool {
 	return txcontext.ReceiptEqual(r, y)
 }
@@ -78,6 +87,7 @@ func newTransactionResult(logs []*types.Log, msg *core.Message, msgResult execut
 		contract common.Address
 		gasUsed  uint64
 		status   uint64
+		vmErr    error
 	)
 
 	if msg.To == nil {
@@ -90,6 +100,11 @@ func newTransactionResult(logs []*types.Log, msg *core.Message, msgResult execut
 		gasUsed = msgResult.GetGasUsed()
 		if msgResult.Failed() {
 			status = types.ReceiptStatusFailed
+			vmErr = msgResult.GetError()
+			var revertErr *txcontext.RevertError
+			if errors.Is(vmErr, vm.ErrExecutionReverted) && !errors.As(vmErr, &revertErr) {
+				vmErr = txcontext.NewRevertError(returnData)
+			}
 		} else {
 			status = types.ReceiptStatusSuccessful
 		}
@@ -105,6 +120,7 @@ func newTransactionResult(logs []*types.Log, msg *core.Message, msgResult execut
 		bloom:           bloom,
 		status:          status,
 		gasUsed:         gasUsed,
+		vmErr:           vmErr,
 	}
 }
 
@@ -20,10 +20,13 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/0xsoniclabs/aida/txcontext"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTransactionResult_GetReceipt(t *testing.T) {
@@ -159,6 +162,32 @@ func TestTransactionResult_newTransactionResult(t *testing.T) {
 	assert.Equal(t, err, result.err)
 }
 
+func TestTransactionResult_newTransactionResult_DecodesRevertReason(t *testing.T) {
+	data := append(common.FromHex("0x4e487b71"), common.LeftPadBytes([]byte{0x12}, 32)...)
+	msgResult := &messageResult{
+		failed:     true,
+		returnData: data,
+		err:        vm.ErrExecutionReverted,
+	}
+
+	result := newTransactionResult(nil, &core.Message{}, msgResult, nil, common.Address{})
+
+	var revertErr *txcontext.RevertError
+	require.ErrorAs(t, result.GetVmError(), &revertErr)
+	assert.Equal(t, "division or modulo by zero", revertErr.Reason)
+	assert.NoError(t, result.err)
+}
+
+func TestTransactionResult_newTransactionResult_KeepsNonRevertVmError(t *testing.T) {
+	msgResult := &messageResult{
+		failed: true,
+		err:    vm.ErrOutOfGas,
+	}
+
+	result := newTransactionResult(nil, &core.Message{}, msgResult, nil, common.Address{})
+	assert.Equal(t, vm.ErrOutOfGas, result.GetVmError())
+}
+
 func TestTransactionResult_newPseudoExecutionResult(t *testing.T) {
 	result := newPseudoExecutionResult()
 
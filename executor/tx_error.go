@@ -0,0 +1,74 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import "errors"
+
+// ErrorComponent names the part of a run in which an error reported to Context.ErrorInput occurred.
+type ErrorComponent string
+
+const (
+	ProcessorComponent ErrorComponent = "processor"
+	ValidatorComponent ErrorComponent = "validator"
+	StateDbComponent   ErrorComponent = "statedb"
+)
+
+var (
+	// ErrTxProcessing marks a transaction which could not be processed.
+	ErrTxProcessing = errors.New("transaction processing failed")
+	// ErrStateMismatch marks a world state differing from the recorded one.
+	ErrStateMismatch = errors.New("world-state mismatch")
+	// ErrReceiptMismatch marks a receipt differing from the recorded one.
+	ErrReceiptMismatch = errors.New("receipt mismatch")
+	// ErrEvmDivergence marks a transaction executed differently by the cross-checked EVM implementations.
+	ErrEvmDivergence = errors.New("evm divergence")
+	// ErrArchiveQuery marks a failed access to the archive of a StateDb.
+	ErrArchiveQuery = errors.New("archive query failed")
+)
+
+// TxError attaches the transaction and the component in which an error occurred to the error.
+// Its message is the message of the wrapped error, hence wrapping does not change logged errors.
+type TxError struct {
+	Block       int
+	Transaction int
+	Component   ErrorComponent
+	Kind        error // sentinel error classifying the error; may be nil
+	Err         error
+}
+
+// NewTxError wraps err into a TxError. The kind is one of the sentinel errors of this package or nil.
+func NewTxError(component ErrorComponent, block int, tx int, kind error, err error) error {
+	return &TxError{
+		Block:       block,
+		Transaction: tx,
+		Component:   component,
+		Kind:        kind,
+		Err:         err,
+	}
+}
+
+func (e *TxError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap makes both the wrapped error and the kind of the error visible to errors.Is and errors.As.
+func (e *TxError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.Kind}
+}
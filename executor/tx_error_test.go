@@ -0,0 +1,53 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxError_KeepsMessageOfWrappedError(t *testing.T) {
+	err := NewTxError(ValidatorComponent, 1, 2, ErrStateMismatch, errors.New("mismatch"))
+	assert.Equal(t, "mismatch", err.Error())
+}
+
+func TestTxError_ExposesKindAndWrappedError(t *testing.T) {
+	cause := errors.New("cause")
+	err := fmt.Errorf("wrapped; %w", NewTxError(ProcessorComponent, 1, 2, ErrTxProcessing, cause))
+
+	assert.ErrorIs(t, err, cause)
+	assert.ErrorIs(t, err, ErrTxProcessing)
+	assert.NotErrorIs(t, err, ErrStateMismatch)
+
+	var txErr *TxError
+	if assert.ErrorAs(t, err, &txErr) {
+		assert.Equal(t, 1, txErr.Block)
+		assert.Equal(t, 2, txErr.Transaction)
+		assert.Equal(t, ProcessorComponent, txErr.Component)
+	}
+}
+
+func TestTxError_KindIsOptional(t *testing.T) {
+	cause := errors.New("cause")
+	err := NewTxError(StateDbComponent, 1, 2, nil, cause)
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, ErrArchiveQuery)
+}
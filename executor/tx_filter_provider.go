@@ -0,0 +1,165 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MakeTxFilterProvider wraps the given provider so that only transactions selected by
+// --tx-filter are forwarded to the consumer, all others are dropped. This is meant for
+// processors preparing a temporary StateDb from the input alloc of each transaction,
+// such as the one of aida-vm, for which dropping transactions does not affect the state
+// of the selected ones. If no filter is configured, the provider is returned unchanged.
+func MakeTxFilterProvider(provider Provider[txcontext.TxContext], cfg *utils.Config) (Provider[txcontext.TxContext], error) {
+	if cfg.TxFilter == "" {
+		return provider, nil
+	}
+	filter, err := parseTxFilter(cfg.TxFilter)
+	if err != nil {
+		return nil, err
+	}
+	return makeTxFilterProvider(provider, filter, logger.NewLogger(cfg.LogLevel, "Tx-Filter")), nil
+}
+
+func makeTxFilterProvider(provider Provider[txcontext.TxContext], filter txFilter, log logger.Logger) *txFilterProvider {
+	return &txFilterProvider{
+		Provider: provider,
+		filter:   filter,
+		log:      log,
+	}
+}
+
+// txFilterKey identifies a transaction by its position.
+type txFilterKey struct {
+	block int
+	tx    int
+}
+
+// txFilter selects transactions either by their hash or by their position.
+type txFilter struct {
+	hashes    map[common.Hash]struct{}
+	positions map[txFilterKey]struct{}
+}
+
+// match returns the filter entry selecting the given transaction, if there is one.
+// Substates do not record transaction hashes, hence a hash is matched against the
+// transaction hash of the recorded logs, which are only present if the transaction
+// emitted any.
+func (f txFilter) match(info TransactionInfo[txcontext.TxContext]) (any, bool) {
+	key := txFilterKey{info.Block, info.Transaction}
+	if _, found := f.positions[key]; found {
+		return key, true
+	}
+	if len(f.hashes) == 0 || info.Data == nil {
+		return nil, false
+	}
+	res := info.Data.GetResult()
+	if res == nil || res.GetReceipt() == nil {
+		return nil, false
+	}
+	for _, log := range res.GetReceipt().GetLogs() {
+		if _, found := f.hashes[log.TxHash]; found {
+			return log.TxHash, true
+		}
+	}
+	return nil, false
+}
+
+type txFilterProvider struct {
+	Provider[txcontext.TxContext]
+	filter txFilter
+	log    logger.Logger
+}
+
+func (p *txFilterProvider) Run(from int, to int, consumer Consumer[txcontext.TxContext]) error {
+	matched := make(map[any]struct{})
+	numMatched := 0
+	err := p.Provider.Run(from, to, func(info TransactionInfo[txcontext.TxContext]) error {
+		entry, found := p.filter.match(info)
+		if !found {
+			return nil
+		}
+		p.log.Infof("Transaction %v/%v matched the tx filter", info.Block, info.Transaction)
+		matched[entry] = struct{}{}
+		numMatched++
+		return consumer(info)
+	})
+	if err != nil {
+		return err
+	}
+
+	p.log.Noticef("%v of %v candidates of the tx filter matched", numMatched, len(p.filter.hashes)+len(p.filter.positions))
+	for key := range p.filter.positions {
+		if _, found := matched[key]; !found {
+			p.log.Warningf("Transaction %v/%v of the tx filter was not found in block range [%v, %v)", key.block, key.tx, from, to)
+		}
+	}
+	for hash := range p.filter.hashes {
+		if _, found := matched[hash]; !found {
+			p.log.Warningf("Transaction %v of the tx filter was not found; only transactions emitting logs can be selected by hash, use <block>:<tx> otherwise", hash.Hex())
+		}
+	}
+
+	if numMatched == 0 {
+		return fmt.Errorf("no transaction in block range [%v, %v) matched the tx filter", from, to)
+	}
+	return nil
+}
+
+// parseTxFilter parses a comma separated list of transaction hashes and <block>:<tx> pairs.
+func parseTxFilter(value string) (txFilter, error) {
+	filter := txFilter{
+		hashes:    make(map[common.Hash]struct{}),
+		positions: make(map[txFilterKey]struct{}),
+	}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if blockStr, txStr, found := strings.Cut(entry, ":"); found {
+			block, err := strconv.Atoi(blockStr)
+			if err != nil || block < 0 {
+				return txFilter{}, fmt.Errorf("invalid block number in tx filter entry %q", entry)
+			}
+			tx, err := strconv.Atoi(txStr)
+			if err != nil || tx < 0 {
+				return txFilter{}, fmt.Errorf("invalid transaction number in tx filter entry %q", entry)
+			}
+			filter.positions[txFilterKey{block, tx}] = struct{}{}
+			continue
+		}
+		hash, err := hex.DecodeString(strings.TrimPrefix(entry, "0x"))
+		if err != nil || len(hash) != common.HashLength {
+			return txFilter{}, fmt.Errorf("invalid tx filter entry %q; expected a transaction hash or <block>:<tx>", entry)
+		}
+		filter.hashes[common.BytesToHash(hash)] = struct{}{}
+	}
+	if len(filter.hashes)+len(filter.positions) == 0 {
+		return txFilter{}, fmt.Errorf("tx filter %q does not select any transaction", value)
+	}
+	return filter, nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseTxFilter_ParsesHashesAndPositions(t *testing.T) {
+	hash := common.HexToHash("0x0102")
+	filter, err := parseTxFilter(" 10:2," + hash.Hex() + ", 12:0,," + hash.Hex()[2:])
+	require.NoError(t, err)
+	assert.Equal(t, map[common.Hash]struct{}{hash: {}}, filter.hashes)
+	assert.Equal(t, map[txFilterKey]struct{}{{10, 2}: {}, {12, 0}: {}}, filter.positions)
+}
+
+func TestParseTxFilter_RejectsInvalidEntries(t *testing.T) {
+	tests := map[string]struct {
+		value    string
+		expected string
+	}{
+		"invalid block": {"x:2", `invalid block number in tx filter entry "x:2"`},
+		"invalid tx":    {"10:-1", `invalid transaction number in tx filter entry "10:-1"`},
+		"short hash":    {"0x0102", `invalid tx filter entry "0x0102"`},
+		"no hex":        {"0x" + strings.Repeat("z", 64), "expected a transaction hash or <block>:<tx>"},
+		"empty":         {" , ", "does not select any transaction"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseTxFilter(test.value)
+			require.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestMakeTxFilterProvider_ReturnsProviderUnchangedWithoutFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[txcontext.TxContext](ctrl)
+	res, err := MakeTxFilterProvider(provider, &utils.Config{})
+	require.NoError(t, err)
+	assert.Equal(t, provider, res)
+}
+
+func TestMakeTxFilterProvider_InvalidFilterCausesError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[txcontext.TxContext](ctrl)
+	_, err := MakeTxFilterProvider(provider, &utils.Config{TxFilter: "10:x"})
+	require.ErrorContains(t, err, "invalid transaction number")
+}
+
+// newTxWithLogs creates a transaction whose recorded result contains logs emitted by the given transaction.
+func newTxWithLogs(ctrl *gomock.Controller, txHash common.Hash) txcontext.TxContext {
+	receipt := txcontext.NewMockReceipt(ctrl)
+	receipt.EXPECT().GetLogs().Return([]*types.Log{{TxHash: txHash}}).AnyTimes()
+	res := txcontext.NewMockResult(ctrl)
+	res.EXPECT().GetReceipt().Return(receipt).AnyTimes()
+	tx := txcontext.NewMockTxContext(ctrl)
+	tx.EXPECT().GetResult().Return(res).AnyTimes()
+	return tx
+}
+
+func TestTxFilterProvider_ForwardsOnlySelectedTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inner := NewMockProvider[txcontext.TxContext](ctrl)
+	consumer := NewMockTxConsumer(ctrl)
+	log := logger.NewMockLogger(ctrl)
+
+	selected, other := common.Hash{1}, common.Hash{2}
+	filter, err := parseTxFilter("10:1," + selected.Hex())
+	require.NoError(t, err)
+	provider := makeTxFilterProvider(inner, filter, log)
+
+	txs := []TransactionInfo[txcontext.TxContext]{
+		{10, 0, newTxWithLogs(ctrl, other)},
+		{10, 1, newTxWithLogs(ctrl, other)},
+		{11, 0, newTxWithLogs(ctrl, selected)},
+		{11, 1, newTxWithLogs(ctrl, other)},
+		{12, 0, newTxWithLogs(ctrl, other)},
+	}
+	inner.EXPECT().Run(10, 13, gomock.Any()).DoAndReturn(func(_ int, _ int, consumer Consumer[txcontext.TxContext]) error {
+		for _, info := range txs {
+			if err := consumer(info); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	log.EXPECT().Infof(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+	gomock.InOrder(
+		consumer.EXPECT().Consume(10, 1, gomock.Any()),
+		consumer.EXPECT().Consume(11, 0, gomock.Any()),
+		log.EXPECT().Noticef("%v of %v candidates of the tx filter matched", 2, 2),
+	)
+
+	require.NoError(t, provider.Run(10, 13, toSubstateConsumer(consumer)))
+}
+
+func TestTxFilterProvider_WarnsAboutMissingCandidates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inner := NewMockProvider[txcontext.TxContext](ctrl)
+	consumer := NewMockTxConsumer(ctrl)
+	log := logger.NewMockLogger(ctrl)
+
+	filter, err := parseTxFilter("10:0,10:5," + common.Hash{3}.Hex())
+	require.NoError(t, err)
+	provider := makeTxFilterProvider(inner, filter, log)
+
+	inner.EXPECT().Run(10, 11, gomock.Any()).DoAndReturn(func(_ int, _ int, consumer Consumer[txcontext.TxContext]) error {
+		return consumer(TransactionInfo[txcontext.TxContext]{Block: 10, Transaction: 0, Data: newTxWithLogs(ctrl, common.Hash{4})})
+	})
+	consumer.EXPECT().Consume(10, 0, gomock.Any())
+	log.EXPECT().Infof(gomock.Any(), 10, 0)
+	log.EXPECT().Noticef("%v of %v candidates of the tx filter matched", 1, 3)
+	log.EXPECT().Warningf(gomock.Any(), 10, 5, 10, 11)
+	log.EXPECT().Warningf(gomock.Any(), common.Hash{3}.Hex())
+
+	require.NoError(t, provider.Run(10, 11, toSubstateConsumer(consumer)))
+}
+
+func TestTxFilterProvider_FailsIfNoTransactionMatched(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inner := NewMockProvider[txcontext.TxContext](ctrl)
+	log := logger.NewMockLogger(ctrl)
+
+	filter, err := parseTxFilter("20:0")
+	require.NoError(t, err)
+	provider := makeTxFilterProvider(inner, filter, log)
+
+	inner.EXPECT().Run(10, 12, gomock.Any()).DoAndReturn(func(_ int, _ int, consumer Consumer[txcontext.TxContext]) error {
+		return consumer(TransactionInfo[txcontext.TxContext]{Block: 10, Transaction: 0, Data: newTxWithLogs(ctrl, common.Hash{1})})
+	})
+	log.EXPECT().Noticef(gomock.Any(), 0, 1)
+	log.EXPECT().Warningf(gomock.Any(), 20, 0, 10, 12)
+
+	err = provider.Run(10, 12, toSubstateConsumer(NewMockTxConsumer(ctrl)))
+	require.ErrorContains(t, err, "no transaction in block range [10, 12) matched the tx filter")
+}
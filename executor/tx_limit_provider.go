@@ -0,0 +1,74 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"errors"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+// errTxLimitReached stops the wrapped provider once the transaction limit is reached.
+var errTxLimitReached = errors.New("transaction limit reached")
+
+// MakeTxLimitProvider wraps the given provider so that it stops cleanly once --max-tx
+// transactions were forwarded to the consumer. Pseudo transactions are not counted and
+// are forwarded until the transaction following the limit is reached. The executor
+// processes all forwarded transactions before the run ends, hence exactly --max-tx
+// transactions are executed and the last block may be incomplete. If no limit is
+// configured, the provider is returned unchanged.
+func MakeTxLimitProvider[T any](provider Provider[T], cfg *utils.Config) Provider[T] {
+	if cfg.MaxNumTransactions <= 0 {
+		return provider
+	}
+	return makeTxLimitProvider(provider, cfg.MaxNumTransactions, logger.NewLogger(cfg.LogLevel, "Tx-Limit"))
+}
+
+func makeTxLimitProvider[T any](provider Provider[T], limit int, log logger.Logger) *txLimitProvider[T] {
+	return &txLimitProvider[T]{
+		Provider: provider,
+		limit:    limit,
+		log:      log,
+	}
+}
+
+type txLimitProvider[T any] struct {
+	Provider[T]
+	limit int
+	log   logger.Logger
+}
+
+func (p *txLimitProvider[T]) Run(from int, to int, consumer Consumer[T]) error {
+	count := 0
+	last := -1
+	err := p.Provider.Run(from, to, func(info TransactionInfo[T]) error {
+		if info.Transaction < utils.PseudoTx {
+			if count >= p.limit {
+				return errTxLimitReached
+			}
+			count++
+		}
+		last = info.Block
+		return consumer(info)
+	})
+	if errors.Is(err, errTxLimitReached) {
+		p.log.Noticef("Transaction limit of %d transactions (--%v) reached in block %d; the block may be incomplete", p.limit, utils.MaxNumTransactionsFlag.Name, last)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMakeTxLimitProvider_ReturnsProviderUnchangedWithoutLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[any](ctrl)
+	assert.Equal(t, Provider[any](provider), MakeTxLimitProvider[any](provider, &utils.Config{}))
+}
+
+func TestTxLimitProvider_ForwardsOnlyGivenNumberOfTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[any](ctrl)
+	log := logger.NewMockLogger(ctrl)
+
+	provider.EXPECT().Run(1, 4, gomock.Any()).DoAndReturn(func(from int, to int, consume Consumer[any]) error {
+		for _, tx := range []struct{ block, tx int }{{1, utils.PseudoTx}, {1, 0}, {1, 1}, {2, 0}, {2, 1}, {3, 0}} {
+			if err := consume(TransactionInfo[any]{tx.block, tx.tx, nil}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	log.EXPECT().Noticef(gomock.Any(), 3, utils.MaxNumTransactionsFlag.Name, 2)
+
+	var got []TransactionInfo[any]
+	err := makeTxLimitProvider[any](provider, 3, log).Run(1, 4, func(info TransactionInfo[any]) error {
+		got = append(got, info)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []TransactionInfo[any]{{1, utils.PseudoTx, nil}, {1, 0, nil}, {1, 1, nil}, {2, 0, nil}}, got)
+}
+
+func TestTxLimitProvider_ErrorsOfConsumerArePropagated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockProvider[any](ctrl)
+	log := logger.NewMockLogger(ctrl)
+	injectedErr := errors.New("injected error")
+
+	provider.EXPECT().Run(1, 2, gomock.Any()).DoAndReturn(func(from int, to int, consume Consumer[any]) error {
+		return consume(TransactionInfo[any]{1, 0, nil})
+	})
+
+	err := makeTxLimitProvider[any](provider, 3, log).Run(1, 2, func(TransactionInfo[any]) error {
+		return injectedErr
+	})
+	require.ErrorIs(t, err, injectedErr)
+}
+
+func TestTxLimitProvider_ExecutorStopsAfterExactlyGivenNumberOfTransactions(t *testing.T) {
+	tests := map[string]ParallelismGranularity{
+		"block-level":       BlockLevel,
+		"transaction-level": TransactionLevel,
+	}
+	for name, granularity := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			provider := NewMockProvider[any](ctrl)
+			processor := NewMockProcessor[any](ctrl)
+			extension := NewMockExtension[any](ctrl)
+			log := logger.NewMockLogger(ctrl)
+
+			provider.EXPECT().Run(10, 20, gomock.Any()).DoAndReturn(func(from int, to int, consume Consumer[any]) error {
+				for block := from; block < to; block++ {
+					for tx := 0; tx < 4; tx++ {
+						if err := consume(TransactionInfo[any]{block, tx, nil}); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			})
+			log.EXPECT().Noticef(gomock.Any(), 6, utils.MaxNumTransactionsFlag.Name, 11)
+
+			processor.EXPECT().Process(gomock.Any(), gomock.Any()).Times(6)
+			extension.EXPECT().PreRun(gomock.Any(), gomock.Any())
+			extension.EXPECT().PreTransaction(gomock.Any(), gomock.Any()).Times(6)
+			extension.EXPECT().PostTransaction(gomock.Any(), gomock.Any()).Times(6)
+			if granularity == BlockLevel {
+				// the partially executed block is still closed
+				extension.EXPECT().PreBlock(AtBlock[any](10), gomock.Any())
+				extension.EXPECT().PreBlock(AtBlock[any](11), gomock.Any())
+				extension.EXPECT().PostBlock(AtTransaction[any](10, 3), gomock.Any())
+				extension.EXPECT().PostBlock(AtTransaction[any](11, 1), gomock.Any())
+			}
+			extension.EXPECT().PostRun(gomock.Any(), gomock.Any(), nil)
+
+			err := NewExecutor[any](makeTxLimitProvider[any](provider, 6, log), "CRITICAL").Run(
+				Params{From: 10, To: 20, ParallelismGranularity: granularity, NumWorkers: 2},
+				processor,
+				[]Extension[any]{extension},
+				nil,
+			)
+			require.NoError(t, err)
+		})
+	}
+}
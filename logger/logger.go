@@ -19,7 +19,10 @@ package logger
 //go:generate mockgen -source logger.go -destination logger_mock.go -package logger
 
 import (
+	"fmt"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/op/go-logging"
@@ -33,6 +36,11 @@ var LogLevelFlag = cli.StringFlag{
 	Value:   "info",
 }
 
+var LogLevelOverrideFlag = cli.StringFlag{
+	Name:  "log-level-override",
+	Usage: "comma separated list of per-component log levels overriding --log, e.g. \"StateDbLogger=debug,ProgressTracker=warning\"; component names are case-insensitive",
+}
+
 // defaultLogFormat defines the format used for log output.
 const (
 	defaultLogFormat = "%{time:2006/01/02 15:04:05} %{color}%{level}%{color:reset}: %{message}"
@@ -89,8 +97,53 @@ type Logger interface {
 	IsEnabledFor(level logging.Level) bool
 }
 
+var (
+	overridesMutex sync.Mutex
+	levelOverrides = map[string]logging.Level{} // lower-cased module name -> level
+	modules        = map[string]struct{}{}      // names of all modules created by NewLogger
+)
+
+// ParseLevelOverrides parses a comma separated list of module=level pairs. Module
+// names are matched case-insensitively, hence they are lower-cased in the result.
+func ParseLevelOverrides(value string) (map[string]logging.Level, error) {
+	res := make(map[string]logging.Level)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		module, level, found := strings.Cut(entry, "=")
+		module, level = strings.TrimSpace(module), strings.TrimSpace(level)
+		if !found || module == "" {
+			return nil, fmt.Errorf("invalid log level override %q; expected <module>=<level>", entry)
+		}
+		lvl, err := logging.LogLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level %q for module %q; options: \"critical\", \"error\", \"warning\", \"notice\", \"info\", \"debug\"", level, module)
+		}
+		res[strings.ToLower(module)] = lvl
+	}
+	return res, nil
+}
+
+// SetLevelOverrides replaces the per-module log levels consulted by NewLogger.
+// Loggers created beforehand are affected once the next logger is created.
+func SetLevelOverrides(overrides map[string]logging.Level) {
+	overridesMutex.Lock()
+	defer overridesMutex.Unlock()
+
+	levelOverrides = make(map[string]logging.Level, len(overrides))
+	for module, lvl := range overrides {
+		levelOverrides[strings.ToLower(module)] = lvl
+	}
+}
+
 // NewLogger provides a new instance of the Logger based on context flags.
+// The level is replaced by the override of the module, if there is one.
 func NewLogger(level string, module string) Logger {
+	overridesMutex.Lock()
+	defer overridesMutex.Unlock()
+
 	backend := logging.NewLogBackend(os.Stdout, "", 0)
 
 	fm := logging.MustStringFormatter(defaultLogFormat)
@@ -103,6 +156,14 @@ func NewLogger(level string, module string) Logger {
 	lvlBackend := logging.AddModuleLevel(fmtBackend)
 	lvlBackend.SetLevel(lvl, "")
 
+	// the backend is shared by all loggers, hence it has to carry the overrides of all modules
+	modules[module] = struct{}{}
+	for name := range modules {
+		if override, found := levelOverrides[strings.ToLower(name)]; found {
+			lvlBackend.SetLevel(override, name)
+		}
+	}
+
 	logging.SetBackend(lvlBackend)
 	return logging.MustGetLogger(module)
 }
@@ -22,6 +22,7 @@ import (
 
 	"github.com/op/go-logging"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLogger_NewLogger(t *testing.T) {
@@ -38,6 +39,68 @@ func TestLogger_NewLogger(t *testing.T) {
 	})
 }
 
+func TestLogger_NewLoggerAppliesLevelOverrides(t *testing.T) {
+	overrides, err := ParseLevelOverrides("StateDbLogger=debug, progresstracker=WARNING")
+	require.NoError(t, err)
+	SetLevelOverrides(overrides)
+	t.Cleanup(func() { SetLevelOverrides(nil) })
+
+	stateDb := NewLogger("info", "StateDbLogger")
+	tracker := NewLogger("info", "ProgressTracker")
+	other := NewLogger("info", "Other")
+	mixedCase := NewLogger("info", "STATEDBLOGGER")
+
+	tests := []struct {
+		logger  Logger
+		enabled logging.Level
+		disable logging.Level
+	}{
+		{stateDb, logging.DEBUG, -1},
+		{tracker, logging.WARNING, logging.NOTICE},
+		{other, logging.INFO, logging.DEBUG},
+		{mixedCase, logging.DEBUG, -1},
+	}
+	for i, test := range tests {
+		assert.True(t, test.logger.IsEnabledFor(test.enabled), "logger %d", i)
+		if test.disable >= 0 {
+			assert.False(t, test.logger.IsEnabledFor(test.disable), "logger %d", i)
+		}
+	}
+}
+
+func TestLogger_NewLoggerWithoutOverridesUsesGivenLevel(t *testing.T) {
+	SetLevelOverrides(nil)
+	log := NewLogger("warning", "StateDbLogger")
+	assert.True(t, log.IsEnabledFor(logging.WARNING))
+	assert.False(t, log.IsEnabledFor(logging.NOTICE))
+}
+
+func TestLogger_ParseLevelOverrides(t *testing.T) {
+	tests := map[string]struct {
+		value    string
+		expected map[string]logging.Level
+		err      string
+	}{
+		"empty":          {value: "", expected: map[string]logging.Level{}},
+		"single":         {value: "Tracker=debug", expected: map[string]logging.Level{"tracker": logging.DEBUG}},
+		"multiple":       {value: "A=info, b=ERROR,", expected: map[string]logging.Level{"a": logging.INFO, "b": logging.ERROR}},
+		"unknown level":  {value: "A=verbose", err: `invalid log level "verbose" for module "A"`},
+		"missing level":  {value: "A", err: `invalid log level override "A"`},
+		"missing module": {value: "=info", err: `invalid log level override "=info"`},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			overrides, err := ParseLevelOverrides(test.value)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, overrides)
+		})
+	}
+}
+
 func TestLogger_ParseTime(t *testing.T) {
 	elapsed := 3661 * time.Second // 1 hour, 1 minute, and 1 second
 	hours, minutes, seconds := ParseTime(elapsed)
@@ -0,0 +1,220 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// OverflowPolicy defines how a WriteBehind queue treats records pushed while it is full.
+type OverflowPolicy string
+
+const (
+	// BlockOnOverflow makes the producer wait until the writer frees space in the queue.
+	BlockOnOverflow OverflowPolicy = "block"
+	// DropOnOverflow discards the record and counts it as dropped.
+	DropOnOverflow OverflowPolicy = "drop"
+)
+
+// ParseOverflowPolicy converts given name into an OverflowPolicy.
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	switch policy := OverflowPolicy(name); policy {
+	case BlockOnOverflow, DropOnOverflow:
+		return policy, nil
+	case "":
+		return BlockOnOverflow, nil
+	default:
+		return "", fmt.Errorf("unknown overflow policy %q; options: %q, %q", name, BlockOnOverflow, DropOnOverflow)
+	}
+}
+
+// WriteBehind is a bounded queue of records serviced by a dedicated writer goroutine,
+// taking slow writes off the path of the producers. Records are written in the order
+// they were pushed. The flush function is called whenever the queue runs empty, so that
+// written records reach their destination without waiting for more records.
+type WriteBehind[T any] struct {
+	queue  chan writeRequest[T]
+	policy OverflowPolicy
+	write  func(T) error
+	flush  func() error
+
+	written atomic.Uint64
+	dropped atomic.Uint64
+
+	mutex  sync.RWMutex // guards closed against concurrent pushes
+	closed bool
+	done   chan struct{}
+	errs   []error // owned by the writer goroutine until done is closed
+}
+
+// writeRequest is either a record or, if flushed is set, a request to flush all records pushed before.
+type writeRequest[T any] struct {
+	record  T
+	flushed chan error
+}
+
+// NewWriteBehind creates a queue of given capacity and starts its writer goroutine.
+// The flush function may be nil.
+func NewWriteBehind[T any](capacity int, policy OverflowPolicy, write func(T) error, flush func() error) *WriteBehind[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	if flush == nil {
+		flush = func() error { return nil }
+	}
+	w := &WriteBehind[T]{
+		queue:  make(chan writeRequest[T], capacity),
+		policy: policy,
+		write:  write,
+		flush:  flush,
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Push enqueues given record. It returns false if the record was dropped, either due to a full
+// queue with the DropOnOverflow policy or because the queue was already closed.
+func (w *WriteBehind[T]) Push(record T) bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if w.closed {
+		w.dropped.Add(1)
+		return false
+	}
+	request := writeRequest[T]{record: record}
+	if w.policy == DropOnOverflow {
+		select {
+		case w.queue <- request:
+			return true
+		default:
+			w.dropped.Add(1)
+			return false
+		}
+	}
+	w.queue <- request
+	return true
+}
+
+// Flush waits until all records pushed before are written and flushed.
+func (w *WriteBehind[T]) Flush() error {
+	w.mutex.RLock()
+	if w.closed {
+		w.mutex.RUnlock()
+		return nil
+	}
+	flushed := make(chan error, 1)
+	// flush requests are never dropped
+	w.queue <- writeRequest[T]{flushed: flushed}
+	w.mutex.RUnlock()
+	return <-flushed
+}
+
+// Close writes and flushes all remaining records and stops the writer goroutine. It returns
+// all errors of writing and flushing not yet reported by Flush. Records pushed after Close are dropped
+// and repeated calls of Close have no effect.
+func (w *WriteBehind[T]) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	<-w.done
+	return errors.Join(w.errs...)
+}
+
+// Written returns the number of records written so far.
+func (w *WriteBehind[T]) Written() uint64 {
+	return w.written.Load()
+}
+
+// Dropped returns the number of records dropped so far.
+func (w *WriteBehind[T]) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+func (w *WriteBehind[T]) run() {
+	defer close(w.done)
+	for request := range w.queue {
+		if request.flushed != nil {
+			err := errors.Join(append(w.errs, w.flush())...)
+			w.errs = nil
+			request.flushed <- err
+			continue
+		}
+		if err := w.write(request.record); err != nil {
+			w.errs = append(w.errs, err)
+		}
+		w.written.Add(1)
+		if len(w.queue) == 0 {
+			if err := w.flush(); err != nil {
+				w.errs = append(w.errs, err)
+			}
+		}
+	}
+	if err := w.flush(); err != nil {
+		w.errs = append(w.errs, err)
+	}
+}
+
+// FlushOnSignal calls given flush function once the process receives an interrupt or
+// termination signal. The signal is re-raised afterward, so the process terminates
+// as it would have without the handler. The returned function stops the handler.
+func FlushOnSignal(log Logger, flush func() error) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	stopHandler := flushOnSignal(signals, log, flush, func(sig os.Signal) {
+		signal.Stop(signals)
+		if process, err := os.FindProcess(os.Getpid()); err == nil {
+			_ = process.Signal(sig)
+		}
+	})
+	return func() {
+		stopHandler()
+		signal.Stop(signals)
+	}
+}
+
+func flushOnSignal(signals <-chan os.Signal, log Logger, flush func() error, raise func(os.Signal)) (stop func()) {
+	quit := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case sig := <-signals:
+			log.Warningf("Received %v, flushing pending records", sig)
+			if err := flush(); err != nil {
+				log.Errorf("cannot flush pending records; %v", err)
+			}
+			raise(sig)
+		case <-quit:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(quit) })
+		<-finished
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseOverflowPolicy(t *testing.T) {
+	tests := map[string]struct {
+		want    OverflowPolicy
+		wantErr bool
+	}{
+		"":      {want: BlockOnOverflow},
+		"block": {want: BlockOnOverflow},
+		"drop":  {want: DropOnOverflow},
+		"spill": {wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseOverflowPolicy(name)
+			if test.wantErr {
+				require.ErrorContains(t, err, "unknown overflow policy")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestWriteBehind_WritesRecordsInOrder(t *testing.T) {
+	var written []int
+	flushes := 0
+	w := NewWriteBehind(4, BlockOnOverflow, func(i int) error {
+		written = append(written, i)
+		return nil
+	}, func() error {
+		flushes++
+		return nil
+	})
+	for i := 0; i < 100; i++ {
+		require.True(t, w.Push(i))
+	}
+	require.NoError(t, w.Close())
+
+	require.Len(t, written, 100)
+	for i, v := range written {
+		require.Equal(t, i, v)
+	}
+	assert.Equal(t, uint64(100), w.Written())
+	assert.Zero(t, w.Dropped())
+	assert.Positive(t, flushes)
+}
+
+func TestWriteBehind_BlockingPolicyWaitsForWriter(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	w := NewWriteBehind(1, BlockOnOverflow, func(i int) error {
+		if i == 1 {
+			started <- struct{}{}
+			<-release
+		}
+		return nil
+	}, nil)
+
+	require.True(t, w.Push(1))
+	<-started // the writer holds the first record
+	require.True(t, w.Push(2))
+
+	pushed := make(chan bool)
+	go func() { pushed <- w.Push(3) }()
+	select {
+	case <-pushed:
+		t.Fatal("push into a full queue must block")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	assert.True(t, <-pushed)
+	require.NoError(t, w.Close())
+	assert.Equal(t, uint64(3), w.Written())
+	assert.Zero(t, w.Dropped())
+}
+
+func TestWriteBehind_DropPolicyCountsDroppedRecords(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	var written []int
+	w := NewWriteBehind(2, DropOnOverflow, func(i int) error {
+		if i == 0 {
+			started <- struct{}{}
+			<-release
+		}
+		written = append(written, i)
+		return nil
+	}, nil)
+
+	require.True(t, w.Push(0))
+	<-started
+	require.True(t, w.Push(1))
+	require.True(t, w.Push(2))
+	for i := 3; i < 10; i++ {
+		require.False(t, w.Push(i))
+	}
+	close(release)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []int{0, 1, 2}, written)
+	assert.Equal(t, uint64(3), w.Written())
+	assert.Equal(t, uint64(7), w.Dropped())
+}
+
+func TestWriteBehind_FlushWaitsForPendingRecordsAndReportsErrors(t *testing.T) {
+	var mutex sync.Mutex
+	var written []int
+	w := NewWriteBehind(8, BlockOnOverflow, func(i int) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		written = append(written, i)
+		if i == 2 {
+			return errors.New("write failed")
+		}
+		return nil
+	}, nil)
+
+	for i := 0; i < 3; i++ {
+		w.Push(i)
+	}
+	require.ErrorContains(t, w.Flush(), "write failed")
+	mutex.Lock()
+	assert.Equal(t, []int{0, 1, 2}, written)
+	mutex.Unlock()
+
+	// errors reported by Flush are not reported again
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Flush())
+}
+
+func TestWriteBehind_CloseReportsFlushErrorAndDropsLateRecords(t *testing.T) {
+	w := NewWriteBehind(1, BlockOnOverflow, func(int) error { return nil }, func() error {
+		return errors.New("flush failed")
+	})
+	require.ErrorContains(t, w.Close(), "flush failed")
+	require.False(t, w.Push(1))
+	assert.Equal(t, uint64(1), w.Dropped())
+	require.NoError(t, w.Close())
+}
+
+func TestFlushOnSignal_FlushesAndReraisesSignal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := NewMockLogger(ctrl)
+	log.EXPECT().Warningf("Received %v, flushing pending records", syscall.SIGTERM)
+
+	signals := make(chan os.Signal, 1)
+	flushed := false
+	raised := make(chan os.Signal, 1)
+	stop := flushOnSignal(signals, log, func() error {
+		flushed = true
+		return nil
+	}, func(sig os.Signal) { raised <- sig })
+
+	signals <- syscall.SIGTERM
+	assert.Equal(t, syscall.SIGTERM, <-raised)
+	stop()
+	assert.True(t, flushed)
+}
+
+func TestFlushOnSignal_StopWithoutSignalDoesNotFlush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	stop := flushOnSignal(make(chan os.Signal), NewMockLogger(ctrl), func() error {
+		t.Fatal("flush must not be called")
+		return nil
+	}, func(os.Signal) {
+		t.Fatal("signal must not be raised")
+	})
+	stop()
+	stop()
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package prime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// cachedFileSuffix marks the files owned by a fileCache.
+const cachedFileSuffix = ".cached"
+
+// fileCache keeps downloaded files in a directory, addressed by their checksum.
+// Once the cached files exceed the capacity, the least recently used ones are
+// removed. Since the cache lives on disk, it is shared by subsequent runs.
+type fileCache struct {
+	dir      string
+	capacity uint64 // maximum total size of the cached files in bytes; unlimited if 0
+}
+
+func newFileCache(dir string, capacity uint64) (*fileCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("cannot create cache directory %s; %w", dir, err)
+	}
+	return &fileCache{dir: dir, capacity: capacity}, nil
+}
+
+// get returns the content of the file with the given checksum if it is cached and intact.
+func (c *fileCache) get(checksum string) ([]byte, bool) {
+	path := c.path(checksum)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	if verifyChecksum(data, checksum) != nil {
+		// drop the corrupted file so that it is fetched again
+		_ = os.Remove(path)
+		return nil, false
+	}
+	// the modification time records the last use
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data, true
+}
+
+// put stores the file with the given checksum and evicts the least recently used
+// files if the capacity is exceeded.
+func (c *fileCache) put(checksum string, data []byte) error {
+	tmp, err := os.CreateTemp(c.dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// rename atomically so that concurrent runs never read a partial file
+		err = os.Rename(tmp.Name(), c.path(checksum))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return c.evict(c.path(checksum))
+}
+
+// evict removes the least recently used files, except keep, until the cached files fit the capacity.
+func (c *fileCache) evict(keep string) error {
+	if c.capacity == 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	type cachedFile struct {
+		path    string
+		size    uint64
+		lastUse time.Time
+	}
+	var (
+		files []cachedFile
+		total uint64
+	)
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), cachedFileSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// removed by a concurrent run
+			continue
+		}
+		files = append(files, cachedFile{filepath.Join(c.dir, entry.Name()), uint64(info.Size()), info.ModTime()})
+		total += uint64(info.Size())
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].lastUse.Before(files[j].lastUse) })
+	for _, file := range files {
+		if total <= c.capacity {
+			break
+		}
+		if file.path == keep {
+			continue
+		}
+		if err = os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		total -= file.size
+	}
+	return nil
+}
+
+func (c *fileCache) path(checksum string) string {
+	return filepath.Join(c.dir, checksum+cachedFileSuffix)
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package prime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCache_GetReturnsStoredFile(t *testing.T) {
+	cache, err := newFileCache(filepath.Join(t.TempDir(), "cache"), 0)
+	require.NoError(t, err)
+	data := []byte("update set")
+	checksum := testChecksum(data)
+
+	_, found := cache.get(checksum)
+	assert.False(t, found)
+
+	require.NoError(t, cache.put(checksum, data))
+	got, found := cache.get(checksum)
+	assert.True(t, found)
+	assert.Equal(t, data, got)
+}
+
+func TestFileCache_GetDropsCorruptedFile(t *testing.T) {
+	cache, err := newFileCache(t.TempDir(), 0)
+	require.NoError(t, err)
+	data := []byte("update set")
+	checksum := testChecksum(data)
+	require.NoError(t, cache.put(checksum, data))
+	require.NoError(t, os.WriteFile(cache.path(checksum), []byte("corrupted"), 0644))
+
+	_, found := cache.get(checksum)
+	assert.False(t, found)
+	assert.NoFileExists(t, cache.path(checksum))
+}
+
+func TestFileCache_EvictsLeastRecentlyUsedFiles(t *testing.T) {
+	cache, err := newFileCache(t.TempDir(), 20)
+	require.NoError(t, err)
+	a, b, c := []byte("aaaaaaaaaa"), []byte("bbbbbbbbbb"), []byte("cccccccccc")
+
+	// a is stored before b, but used after it
+	require.NoError(t, cache.put(testChecksum(a), a))
+	require.NoError(t, cache.put(testChecksum(b), b))
+	now := time.Now()
+	require.NoError(t, os.Chtimes(cache.path(testChecksum(a)), now.Add(-2*time.Hour), now.Add(-2*time.Hour)))
+	require.NoError(t, os.Chtimes(cache.path(testChecksum(b)), now.Add(-time.Hour), now.Add(-time.Hour)))
+	_, found := cache.get(testChecksum(a))
+	require.True(t, found)
+
+	require.NoError(t, cache.put(testChecksum(c), c))
+
+	assert.FileExists(t, cache.path(testChecksum(a)))
+	assert.NoFileExists(t, cache.path(testChecksum(b)))
+	assert.FileExists(t, cache.path(testChecksum(c)))
+}
+
+func TestFileCache_KeepsFileLargerThanCapacity(t *testing.T) {
+	cache, err := newFileCache(t.TempDir(), 5)
+	require.NoError(t, err)
+	a, b := []byte("aaaaaaaaaa"), []byte("bbbbbbbbbb")
+
+	require.NoError(t, cache.put(testChecksum(a), a))
+	require.NoError(t, cache.put(testChecksum(b), b))
+
+	assert.NoFileExists(t, cache.path(testChecksum(a)))
+	assert.FileExists(t, cache.path(testChecksum(b)))
+}
+
+func TestFileCache_UnlimitedCapacityKeepsAllFiles(t *testing.T) {
+	cache, err := newFileCache(t.TempDir(), 0)
+	require.NoError(t, err)
+	files := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	for _, data := range files {
+		require.NoError(t, cache.put(testChecksum(data), data))
+	}
+	for _, data := range files {
+		assert.FileExists(t, cache.path(testChecksum(data)))
+	}
+}
+
+func TestFileCache_EvictionIgnoresForeignFiles(t *testing.T) {
+	dir := t.TempDir()
+	foreign := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(foreign, make([]byte, 100), 0644))
+	cache, err := newFileCache(dir, 10)
+	require.NoError(t, err)
+
+	data := []byte("aaaaaaaaaa")
+	require.NoError(t, cache.put(testChecksum(data), data))
+
+	assert.FileExists(t, foreign)
+	assert.FileExists(t, cache.path(testChecksum(data)))
+}
+
+func testChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
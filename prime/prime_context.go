@@ -17,9 +17,12 @@
 package prime
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"math"
 	"math/rand"
-	"sort"
+	"slices"
 
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/state"
@@ -212,21 +215,11 @@ func (pc *context) primeOneAccount(addr common.Address, acc txcontext.Account, p
 }
 
 // primeStateDBRandom primes database with accounts from the world state in random order.
+// Accounts are shuffled within windows of at most cfg.PrimeShuffleWindow accounts (see
+// forEachShuffleWindow), hence the order is deterministic for a fixed seed and window size.
 func (pc *context) primeStateDBRandom(ws txcontext.WorldState, pt *utils.ProgressTracker) error {
 	var err error
 
-	contracts := make([]string, 0, ws.Len())
-	ws.ForEachAccount(func(addr common.Address, _ txcontext.Account) {
-		contracts = append(contracts, addr.Hex())
-	})
-
-	sort.Strings(contracts)
-	// shuffle contract order
-	rand.NewSource(pc.cfg.RandomSeed)
-	rand.Shuffle(len(contracts), func(i, j int) {
-		contracts[i], contracts[j] = contracts[j], contracts[i]
-	})
-
 	if pc.cfg.IsExistingStateDb {
 		err := pc.loadExistingAccountsIntoCache(ws)
 		if err != nil {
@@ -239,23 +232,67 @@ func (pc *context) primeStateDBRandom(ws txcontext.WorldState, pt *utils.Progres
 		return err
 	}
 
-	for _, c := range contracts {
-		addr := common.HexToAddress(c)
-		account := ws.Get(addr)
-		if err := pc.primeOneAccount(addr, account, pt); err != nil {
-			return err
-		}
-		// commit to stateDB after process n accounts and start a new buck load
-		if err := pc.mayApplyBulkLoad(); err != nil {
-			return err
+	rng := rand.New(rand.NewSource(pc.cfg.RandomSeed))
+	err = forEachShuffleWindow(ws, pc.cfg.PrimeShuffleWindow, rng, func(addresses []common.Address) error {
+		for _, addr := range addresses {
+			if err := pc.primeOneAccount(addr, ws.Get(addr), pt); err != nil {
+				return err
+			}
+			// commit to stateDB after process n accounts and start a new buck load
+			if err := pc.mayApplyBulkLoad(); err != nil {
+				return err
+			}
 		}
-
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+
 	err = pc.load.Close()
 	pc.block++
 	return err
 }
 
+// forEachShuffleWindow splits the accounts of the world state into windows of roughly
+// windowSize accounts and passes each window to the handler in a random order. Windows
+// are ranges of the address space of equal width, so only the addresses of a single
+// window are held in memory at a time. If windowSize is not positive or the world state
+// fits into a single window, all accounts are shuffled at once.
+func forEachShuffleWindow(ws txcontext.WorldState, windowSize int, rng *rand.Rand, handler func([]common.Address) error) error {
+	numWindows := uint64(1)
+	if windowSize > 0 && ws.Len() > windowSize {
+		numWindows = uint64((ws.Len() + windowSize - 1) / windowSize)
+	}
+	// width of a window in the space of 8-byte address prefixes
+	width, capacity := uint64(math.MaxUint64), ws.Len()
+	if numWindows > 1 {
+		width, capacity = math.MaxUint64/numWindows+1, windowSize
+	}
+
+	for window := uint64(0); window < numWindows; window++ {
+		addresses := make([]common.Address, 0, capacity)
+		ws.ForEachAccount(func(addr common.Address, _ txcontext.Account) {
+			if numWindows == 1 || binary.BigEndian.Uint64(addr[:8])/width == window {
+				addresses = append(addresses, addr)
+			}
+		})
+
+		// sort first, the iteration order of the world state is not deterministic
+		slices.SortFunc(addresses, func(a, b common.Address) int {
+			return bytes.Compare(a[:], b[:])
+		})
+		rng.Shuffle(len(addresses), func(i, j int) {
+			addresses[i], addresses[j] = addresses[j], addresses[i]
+		})
+
+		if err := handler(addresses); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // selfDestructAccounts clears storage of all input accounts.
 func (pc *context) selfDestructAccounts(accounts []substatetypes.Address) error {
 	// short-circuit if no accounts to self-destruct
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"math/rand"
 	"testing"
 
 	"github.com/0xsoniclabs/aida/logger"
@@ -29,6 +30,8 @@ import (
 	"github.com/0xsoniclabs/aida/utils"
 	substatetypes "github.com/0xsoniclabs/substate/types"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -281,6 +284,133 @@ func TestPrimeContext_PrimeStateDBRandom(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// primedContent is the content written into a bulk load by priming.
+type primedContent struct {
+	order    []common.Address
+	balances map[common.Address]*uint256.Int
+	nonces   map[common.Address]uint64
+	codes    map[common.Address][]byte
+	storage  map[common.Address]map[common.Hash]common.Hash
+}
+
+// primeRandomly primes the world state in random order with given shuffle window
+// and returns the content written into the StateDB.
+func primeRandomly(t *testing.T, ws txcontext.WorldState, seed int64, window int) primedContent {
+	ctrl := gomock.NewController(t)
+	mockStateDb := state.NewMockStateDB(ctrl)
+	mockBulk := state.NewMockBulkLoad(ctrl)
+
+	content := primedContent{
+		balances: make(map[common.Address]*uint256.Int),
+		nonces:   make(map[common.Address]uint64),
+		codes:    make(map[common.Address][]byte),
+		storage:  make(map[common.Address]map[common.Hash]common.Hash),
+	}
+	mockStateDb.EXPECT().StartBulkLoad(gomock.Any()).Return(mockBulk, nil).AnyTimes()
+	mockBulk.EXPECT().CreateAccount(gomock.Any()).Do(func(addr common.Address) {
+		content.order = append(content.order, addr)
+		content.storage[addr] = make(map[common.Hash]common.Hash)
+	}).AnyTimes()
+	mockBulk.EXPECT().SetBalance(gomock.Any(), gomock.Any()).Do(func(addr common.Address, value *uint256.Int) {
+		content.balances[addr] = value
+	}).AnyTimes()
+	mockBulk.EXPECT().SetNonce(gomock.Any(), gomock.Any()).Do(func(addr common.Address, nonce uint64) {
+		content.nonces[addr] = nonce
+	}).AnyTimes()
+	mockBulk.EXPECT().SetCode(gomock.Any(), gomock.Any()).Do(func(addr common.Address, code []byte) {
+		content.codes[addr] = code
+	}).AnyTimes()
+	mockBulk.EXPECT().SetState(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(addr common.Address, key common.Hash, value common.Hash) {
+		content.storage[addr][key] = value
+	}).AnyTimes()
+	mockBulk.EXPECT().Close().Return(nil).AnyTimes()
+
+	cfg := &utils.Config{PrimeRandom: true, RandomSeed: seed, PrimeShuffleWindow: window}
+	pc := newContext(cfg, mockStateDb, logger.NewLogger("ERROR", "Test"))
+	require.NoError(t, pc.PrimeStateDB(ws))
+	return content
+}
+
+func makeSyntheticWorldState(numAccounts int) txcontext.WorldState {
+	accounts := make(map[common.Address]txcontext.Account, numAccounts)
+	for i := 1; i <= numAccounts; i++ {
+		addr := common.BytesToAddress(crypto.Keccak256(big.NewInt(int64(i)).Bytes()))
+		storage := map[common.Hash]common.Hash{
+			common.BigToHash(big.NewInt(int64(i))): common.BigToHash(big.NewInt(int64(2 * i))),
+		}
+		accounts[addr] = txcontext.NewAccount([]byte{byte(i)}, storage, big.NewInt(int64(i)), uint64(i))
+	}
+	return txcontext.NewWorldState(accounts)
+}
+
+func TestPrimeContext_PrimeStateDBRandom_ContentDoesNotDependOnShuffleWindow(t *testing.T) {
+	ws := makeSyntheticWorldState(200)
+
+	want := primeRandomly(t, ws, 42, 0)
+	require.Len(t, want.order, ws.Len())
+	for _, window := range []int{1, 7, 50, 199, 200, 1000} {
+		t.Run(fmt.Sprintf("window %d", window), func(t *testing.T) {
+			got := primeRandomly(t, ws, 42, window)
+			assert.ElementsMatch(t, want.order, got.order)
+			assert.Equal(t, want.balances, got.balances)
+			assert.Equal(t, want.nonces, got.nonces)
+			assert.Equal(t, want.codes, got.codes)
+			assert.Equal(t, want.storage, got.storage)
+		})
+	}
+}
+
+func TestPrimeContext_PrimeStateDBRandom_OrderIsDeterministicForFixedWindow(t *testing.T) {
+	ws := makeSyntheticWorldState(200)
+	for _, window := range []int{0, 7, 50} {
+		t.Run(fmt.Sprintf("window %d", window), func(t *testing.T) {
+			first := primeRandomly(t, ws, 42, window)
+			second := primeRandomly(t, ws, 42, window)
+			assert.Equal(t, first.order, second.order)
+		})
+	}
+}
+
+func TestPrimeContext_PrimeStateDBRandom_WholeStateFitsIntoWindow(t *testing.T) {
+	ws := makeSyntheticWorldState(50)
+	// a window covering the whole state shuffles all accounts at once
+	assert.Equal(t, primeRandomly(t, ws, 7, 0).order, primeRandomly(t, ws, 7, 50).order)
+	assert.Equal(t, primeRandomly(t, ws, 7, 0).order, primeRandomly(t, ws, 7, 1000).order)
+}
+
+func TestPrimeContext_forEachShuffleWindow_SplitsAccountsIntoWindows(t *testing.T) {
+	ws := makeSyntheticWorldState(1000)
+
+	seen := make(map[common.Address]int)
+	numWindows := 0
+	err := forEachShuffleWindow(ws, 100, rand.New(rand.NewSource(0)), func(addresses []common.Address) error {
+		numWindows++
+		for _, addr := range addresses {
+			seen[addr]++
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 10, numWindows)
+	assert.Len(t, seen, ws.Len())
+	for addr, count := range seen {
+		assert.Equal(t, 1, count, "account %v visited %d times", addr, count)
+	}
+}
+
+func TestPrimeContext_forEachShuffleWindow_ReturnsHandlerError(t *testing.T) {
+	ws := makeSyntheticWorldState(10)
+	injectedErr := errors.New("injected error")
+
+	calls := 0
+	err := forEachShuffleWindow(ws, 2, rand.New(rand.NewSource(0)), func([]common.Address) error {
+		calls++
+		return injectedErr
+	})
+	require.ErrorIs(t, err, injectedErr)
+	assert.Equal(t, 1, calls)
+}
+
 func TestPrimeContext_SelfDestructAccountsSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
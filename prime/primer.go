@@ -51,15 +51,22 @@ func newPrimer(cfg *utils.Config, state state.StateDB, aidaDb db.BaseDB, log log
 		if err != nil {
 			return nil, err
 		}
-		p.udb, err = db.MakeDefaultUpdateDBFromBaseDB(aidaDb)
+		udb, err := db.MakeDefaultUpdateDBFromBaseDB(aidaDb)
 		if err != nil {
 			return nil, err
 		}
+		p.udb = NewFileUpdateSetSource(udb)
 		p.ddb, err = db.MakeDefaultDestroyedAccountDBFromBaseDB(aidaDb)
 		if err != nil {
 			return nil, err
 		}
 	}
+	if IsRemoteUpdateDb(cfg.UpdateDb) {
+		p.udb, err = NewHttpUpdateSetSource(cfg.UpdateDb, cfg.UpdateCacheDir, uint64(cfg.UpdateCacheSize)*1_000_000, log)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open remote update-set database; %w", err)
+		}
+	}
 	p.trySetBlocks()
 	return p, nil
 }
@@ -70,7 +77,7 @@ type primer struct {
 	ctx    *context              // prime context
 	aidadb db.BaseDB             // Aida database
 	sdb    db.SubstateDB         // substate database
-	udb    db.UpdateDB           // update-set database
+	udb    UpdateSetSource       // update-set database
 	ddb    db.DestroyedAccountDB // deleted accounts database
 	block  uint64                // current block number used for priming
 	target uint64                // end of priming block
@@ -111,10 +118,7 @@ func (p *primer) trySetBlocks() {
 
 // mayPrimeFromUpdateSet primes the stateDb from the update-set database if data is available.
 func (p *primer) mayPrimeFromUpdateSet() error {
-	var (
-		totalSize uint64 // total size of unprimed update set
-		hasPrimed bool   // if true, db has been primed
-	)
+	var hasPrimed bool // if true, db has been primed
 
 	// Primable block is already ahead of the first target block. No priming is needed.
 	if p.block >= p.target {
@@ -123,7 +127,12 @@ func (p *primer) mayPrimeFromUpdateSet() error {
 	// create iterator starting from the first primable block.
 	updateIter := p.udb.NewUpdateSetIterator(p.block, p.target-1)
 	defer updateIter.Release()
-	update := make(substate.WorldState)
+	buffer := newUpdateBuffer(p.cfg.UpdateBufferSize, p.cfg.UpdateBufferStrict, p.log, func(update substate.WorldState) error {
+		if err := p.ctx.PrimeStateDB(substatecontext.NewWorldState(update)); err != nil {
+			return fmt.Errorf("cannot prime state-db; %v", err)
+		}
+		return nil
+	})
 
 	for updateIter.Next() {
 		newSet := updateIter.Value()
@@ -131,24 +140,18 @@ func (p *primer) mayPrimeFromUpdateSet() error {
 			break
 		}
 		p.block = newSet.Block
-		incrementalSize := update.EstimateIncrementalSize(newSet.WorldState)
 
 		// Prime StateDB
-		if totalSize+incrementalSize > p.cfg.UpdateBufferSize {
-			p.log.Infof("\tPriming...")
-			if err := p.ctx.PrimeStateDB(substatecontext.NewWorldState(update)); err != nil {
-				return fmt.Errorf("cannot prime state-db; %v", err)
-			}
-
-			totalSize = 0
-			update = make(substate.WorldState)
-			hasPrimed = true
+		flushes := buffer.flushes
+		if err := buffer.makeRoom(newSet.WorldState); err != nil {
+			return err
 		}
+		hasPrimed = hasPrimed || buffer.flushes > flushes
 
 		// Reset accessed storage locations of suicided accounts prior to update-set block.
 		// The known accessed storage locations in the update-set range has already been
 		// reset when generating the update set database.
-		ClearAccountStorage(update, newSet.DeletedAccounts)
+		ClearAccountStorage(buffer.update, newSet.DeletedAccounts)
 		// if exists in DB, suicide
 		if hasPrimed {
 			if err := p.ctx.selfDestructAccounts(newSet.DeletedAccounts); err != nil {
@@ -157,20 +160,28 @@ func (p *primer) mayPrimeFromUpdateSet() error {
 			hasPrimed = false
 		}
 
-		update.Merge(newSet.WorldState)
-		totalSize += incrementalSize
-		p.log.Infof("\tMerge update set at block %v. New total size %v MB (+%v MB)",
-			newSet.Block, totalSize/1_000_000,
-			incrementalSize/1_000_000)
+		// in strict mode, the buffer may be flushed while merging
+		flushes = buffer.flushes
+		if err := buffer.merge(newSet.WorldState); err != nil {
+			return err
+		}
+		hasPrimed = hasPrimed || buffer.flushes > flushes
+		p.log.Infof("\tMerge update set at block %v. New total size %v MB", newSet.Block, buffer.size/1_000_000)
 		// advance next primable block after merge update set
 		p.block++
 	}
+	if err := updateIter.Error(); err != nil {
+		return fmt.Errorf("cannot read update sets; %w", err)
+	}
 
-	if len(update) > 0 {
-		if err := p.ctx.PrimeStateDB(substatecontext.NewWorldState(update)); err != nil {
-			return fmt.Errorf("cannot prime state-db; %v", err)
+	if len(buffer.update) > 0 {
+		if err := buffer.flush(); err != nil {
+			return err
 		}
 	}
+	if buffer.flushes > 0 {
+		p.log.Infof("Primed update sets in %v flushes; high-water mark %v MB", buffer.flushes, buffer.highWater/1_000_000)
+	}
 
 	return nil
 }
@@ -102,6 +102,7 @@ func TestPrime_Prime(t *testing.T) {
 			mockStateDb.EXPECT().StartBulkLoad(gomock.Any()).Return(mockBulk, nil),
 			mockBulk.EXPECT().Close().Return(nil),
 			mockUpdateIter.EXPECT().Next().Return(false),
+			mockUpdateIter.EXPECT().Error().Return(nil),
 			mockStateDb.EXPECT().StartBulkLoad(gomock.Any()).Return(mockBulk, nil),
 			mockBulk.EXPECT().CreateAccount(gomock.Any()),
 			mockBulk.EXPECT().SetBalance(gomock.Any(), gomock.Any()),
@@ -161,6 +162,7 @@ func TestPrime_Prime(t *testing.T) {
 		gomock.InOrder(
 			mockUpdateDb.EXPECT().NewUpdateSetIterator(gomock.Any(), gomock.Any()).Return(mockUpdateIter),
 			mockUpdateIter.EXPECT().Next().Return(false),
+			mockUpdateIter.EXPECT().Error().Return(nil),
 			mockUpdateIter.EXPECT().Release(),
 			mockSubstateDb.EXPECT().NewSubstateIterator(gomock.Any(), gomock.Any()).Return(mockSubstateIter).AnyTimes(),
 			mockSubstateIter.EXPECT().Next().Return(true),
@@ -186,6 +188,7 @@ func TestPrime_Prime(t *testing.T) {
 		gomock.InOrder(
 			mockUpdateDb.EXPECT().NewUpdateSetIterator(gomock.Any(), gomock.Any()).Return(mockUpdateIter),
 			mockUpdateIter.EXPECT().Next().Return(false),
+			mockUpdateIter.EXPECT().Error().Return(nil),
 			mockUpdateIter.EXPECT().Release(),
 
 			mockSubstateDb.EXPECT().NewSubstateIterator(gomock.Any(), gomock.Any()).Return(mockSubstateIter).AnyTimes(),
@@ -229,6 +232,7 @@ func TestPrime_MayPrimeFromUpdateSet_EdgeCases(t *testing.T) {
 			mockUpdateDb.EXPECT().NewUpdateSetIterator(gomock.Any(), gomock.Any()).Return(mockUpdateIter),
 			mockUpdateIter.EXPECT().Next().Return(true),
 			mockUpdateIter.EXPECT().Value().Return(updateBlk15),
+			mockUpdateIter.EXPECT().Error().Return(nil),
 			mockUpdateIter.EXPECT().Release(),
 		)
 		err := p.mayPrimeFromUpdateSet()
@@ -243,12 +247,29 @@ func TestPrime_MayPrimeFromUpdateSet_EdgeCases(t *testing.T) {
 		gomock.InOrder(
 			mockUpdateDb.EXPECT().NewUpdateSetIterator(gomock.Any(), gomock.Any()).Return(mockUpdateIter),
 			mockUpdateIter.EXPECT().Next().Return(false),
+			mockUpdateIter.EXPECT().Error().Return(nil),
 			mockUpdateIter.EXPECT().Release(),
 		)
 		err := p.mayPrimeFromUpdateSet()
 		assert.NoError(t, err)
 	})
 
+	t.Run("iteration fails", func(t *testing.T) {
+		mockStateDb := state.NewMockStateDB(ctrl)
+		mockUpdateDb := db.NewMockUpdateDB(ctrl)
+		mockUpdateIter := db.NewMockIIterator[*updateset.UpdateSet](ctrl)
+		p := newTestPrimer(primeBlock, primeFirst, cfg, mockStateDb, mockUpdateDb, nil, nil, log)
+		gomock.InOrder(
+			mockUpdateDb.EXPECT().NewUpdateSetIterator(gomock.Any(), gomock.Any()).Return(mockUpdateIter),
+			mockUpdateIter.EXPECT().Next().Return(false),
+			mockUpdateIter.EXPECT().Error().Return(retError),
+			mockUpdateIter.EXPECT().Release(),
+		)
+		err := p.mayPrimeFromUpdateSet()
+		assert.ErrorIs(t, err, retError)
+		assert.ErrorContains(t, err, "cannot read update sets")
+	})
+
 	t.Run("PrimeStateDB fails", func(t *testing.T) {
 		mockStateDb := state.NewMockStateDB(ctrl)
 		mockUpdateDb := db.NewMockUpdateDB(ctrl)
@@ -626,7 +647,7 @@ func newTestPrimer(
 		cfg:    cfg,
 		log:    log,
 		ctx:    newContext(cfg, stateDb, log),
-		udb:    updateDb,
+		udb:    NewFileUpdateSetSource(updateDb),
 		sdb:    substateDb,
 		ddb:    deletionDb,
 		block:  block,
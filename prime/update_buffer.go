@@ -0,0 +1,193 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package prime
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/substate/substate"
+	"github.com/0xsoniclabs/substate/types"
+)
+
+// Estimates of the memory retained by buffered update sets, measured with runtime.MemStats on amd64.
+const (
+	// accountBytes is retained by an account without storage and code, including its
+	// world-state map entry, the account struct, its balance and its empty storage map.
+	accountBytes = 180
+	// storageSlotBytes is the average size of a storage map entry; depending on how
+	// full the map is since it last grew, an entry retains between 92 and 165 bytes.
+	storageSlotBytes = 128
+	// strictStorageSlotBytes is the largest measured size of a storage map entry.
+	strictStorageSlotBytes = 168
+)
+
+// updateBuffer collects update sets in memory until their estimated size reaches the
+// budget, then it is flushed into the StateDb. By default, the buffer is flushed before
+// an update set which does not fit into it, hence a single large update set exceeds the
+// budget. In strict mode, update sets are merged account by account and the storage of
+// large accounts is split, so the buffer never exceeds the budget by more than the size
+// of a single account without storage.
+type updateBuffer struct {
+	update    substate.WorldState
+	budget    uint64                          // budget of the buffer in bytes
+	strict    bool                            // flush eagerly to stay within the budget
+	slotBytes uint64                          // estimated size of a storage slot
+	size      uint64                          // estimated size of the buffered update
+	highWater uint64                          // largest estimated size of the buffer
+	flushes   int                             // number of flushes of the buffer
+	prime     func(substate.WorldState) error // primes the StateDb with the buffered update
+	log       logger.Logger
+}
+
+func newUpdateBuffer(budget uint64, strict bool, log logger.Logger, prime func(substate.WorldState) error) *updateBuffer {
+	b := &updateBuffer{
+		update:    make(substate.WorldState),
+		budget:    budget,
+		strict:    strict,
+		slotBytes: storageSlotBytes,
+		prime:     prime,
+		log:       log,
+	}
+	if strict {
+		b.slotBytes = strictStorageSlotBytes
+	}
+	return b
+}
+
+// makeRoom flushes the buffer unless given world state fits into it. In strict mode,
+// room is made while merging instead.
+func (b *updateBuffer) makeRoom(ws substate.WorldState) error {
+	if b.strict || b.size+b.estimateIncrementalSize(ws) <= b.budget {
+		return nil
+	}
+	return b.flush()
+}
+
+// merge merges given world state into the buffer.
+func (b *updateBuffer) merge(ws substate.WorldState) error {
+	if !b.strict {
+		b.grow(b.estimateIncrementalSize(ws))
+		b.update.Merge(ws)
+		return nil
+	}
+
+	// sorted, so the flushes do not depend on the map iteration order
+	addresses := make([]types.Address, 0, len(ws))
+	for addr := range ws {
+		addresses = append(addresses, addr)
+	}
+	slices.SortFunc(addresses, func(a, b types.Address) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	for _, addr := range addresses {
+		if err := b.mergeAccount(addr, ws[addr]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeAccount merges given account into the buffer, the buffer is flushed before if the
+// account does not fit. The storage of an account exceeding the budget on its own is
+// split into chunks filling the buffer, each but the last one is flushed immediately.
+func (b *updateBuffer) mergeAccount(addr types.Address, acc *substate.Account) error {
+	increment := b.estimateAccountIncrement(addr, acc)
+	if b.size+increment > b.budget && len(b.update) > 0 {
+		if err := b.flush(); err != nil {
+			return err
+		}
+		increment = b.estimateAccountIncrement(addr, acc)
+	}
+	if increment <= b.budget || len(acc.Storage) == 0 {
+		b.grow(increment)
+		b.update.Merge(substate.WorldState{addr: acc})
+		return nil
+	}
+
+	base := uint64(accountBytes + len(acc.Code))
+	slotsPerChunk := uint64(1)
+	if b.budget > base+b.slotBytes {
+		slotsPerChunk = (b.budget - base) / b.slotBytes
+	}
+	chunk := substate.NewAccount(acc.Nonce, acc.Balance, acc.Code)
+	for key, value := range acc.Storage {
+		chunk.Storage[key] = value
+		if uint64(len(chunk.Storage)) < slotsPerChunk {
+			continue
+		}
+		b.grow(b.estimateAccountIncrement(addr, chunk))
+		b.update[addr] = chunk
+		if err := b.flush(); err != nil {
+			return err
+		}
+		chunk = substate.NewAccount(acc.Nonce, acc.Balance, acc.Code)
+	}
+	if len(chunk.Storage) > 0 {
+		b.grow(b.estimateAccountIncrement(addr, chunk))
+		b.update[addr] = chunk
+	}
+	return nil
+}
+
+// flush primes the StateDb with the buffered update and empties the buffer.
+func (b *updateBuffer) flush() error {
+	b.flushes++
+	b.log.Infof("\tPriming %v accounts of %v MB; high-water mark %v MB", len(b.update), b.size/1_000_000, b.highWater/1_000_000)
+	if err := b.prime(b.update); err != nil {
+		return err
+	}
+	b.update = make(substate.WorldState)
+	b.size = 0
+	return nil
+}
+
+// grow increases the estimated size of the buffer.
+func (b *updateBuffer) grow(increment uint64) {
+	b.size += increment
+	b.highWater = max(b.highWater, b.size)
+}
+
+// estimateIncrementalSize returns the estimated growth of the buffer after merging given world state.
+func (b *updateBuffer) estimateIncrementalSize(ws substate.WorldState) uint64 {
+	var size uint64
+	for addr, acc := range ws {
+		size += b.estimateAccountIncrement(addr, acc)
+	}
+	return size
+}
+
+// estimateAccountIncrement returns the estimated growth of the buffer after merging given account.
+func (b *updateBuffer) estimateAccountIncrement(addr types.Address, acc *substate.Account) uint64 {
+	buffered, found := b.update[addr]
+	if !found {
+		return accountBytes + uint64(len(acc.Code)) + uint64(len(acc.Storage))*b.slotBytes
+	}
+
+	var size uint64
+	if len(acc.Code) > len(buffered.Code) {
+		size += uint64(len(acc.Code) - len(buffered.Code))
+	}
+	for key := range acc.Storage {
+		if _, found := buffered.Storage[key]; !found {
+			size += b.slotBytes
+		}
+	}
+	return size
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package prime
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/0xsoniclabs/substate/substate"
+	"github.com/0xsoniclabs/substate/types"
+	"github.com/0xsoniclabs/substate/updateset"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// makeBufferTestWorldState creates a world state of given number of accounts with given number of storage slots each.
+func makeBufferTestWorldState(accounts int, slots int) substate.WorldState {
+	ws := make(substate.WorldState)
+	for i := 0; i < accounts; i++ {
+		var addr types.Address
+		binary.BigEndian.PutUint32(addr[:], uint32(i+1))
+		acc := substate.NewAccount(1, uint256.NewInt(1), nil)
+		for j := 0; j < slots; j++ {
+			var key types.Hash
+			binary.BigEndian.PutUint32(key[:], uint32(j))
+			acc.Storage[key] = types.Hash{1}
+		}
+		ws[addr] = acc
+	}
+	return ws
+}
+
+// makeTestUpdateBuffer creates an update buffer counting the primed accounts and storage slots.
+func makeTestUpdateBuffer(budget uint64, strict bool, accounts *int, slots *int) *updateBuffer {
+	return newUpdateBuffer(budget, strict, logger.NewLogger("critical", "test"), func(ws substate.WorldState) error {
+		for _, acc := range ws {
+			*accounts++
+			*slots += len(acc.Storage)
+		}
+		return nil
+	})
+}
+
+func TestUpdateBuffer_EstimateIncrementalSize(t *testing.T) {
+	b := makeTestUpdateBuffer(0, false, new(int), new(int))
+	ws := makeBufferTestWorldState(2, 3)
+	ws[types.Address{1}] = substate.NewAccount(1, uint256.NewInt(1), make([]byte, 100))
+	assert.Equal(t, uint64(3*accountBytes+100+2*3*storageSlotBytes), b.estimateIncrementalSize(ws))
+
+	b.update.Merge(ws)
+	update := makeBufferTestWorldState(2, 5)
+	update[types.Address{1}] = substate.NewAccount(1, uint256.NewInt(1), make([]byte, 150))
+	// only the new storage slots and the code growth are added to buffered accounts
+	assert.Equal(t, uint64(50+2*2*storageSlotBytes), b.estimateIncrementalSize(update))
+
+	strict := makeTestUpdateBuffer(0, true, new(int), new(int))
+	assert.Equal(t, uint64(2*accountBytes+2*3*strictStorageSlotBytes), strict.estimateIncrementalSize(makeBufferTestWorldState(2, 3)))
+}
+
+func TestUpdateBuffer_FlushesUnderBudget(t *testing.T) {
+	const budget = 50_000
+	tests := []struct {
+		name        string
+		strict      bool
+		ws          substate.WorldState
+		wantFlushes int
+	}{
+		// 1000 accounts of 180 bytes; the loose buffer flushes only before the update set
+		{name: "many small accounts", ws: makeBufferTestWorldState(1000, 0), wantFlushes: 2},
+		// 277 accounts fit into the budget
+		{name: "many small accounts strict", strict: true, ws: makeBufferTestWorldState(1000, 0), wantFlushes: 4},
+		{name: "few huge accounts", ws: makeBufferTestWorldState(2, 1000), wantFlushes: 2},
+		// chunks of 296 slots fit into the budget, the remaining 112 slots of each account are merged
+		// into the buffer which is flushed before the next account does not fit in
+		{name: "few huge accounts strict", strict: true, ws: makeBufferTestWorldState(2, 1000), wantFlushes: 8},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var accounts, slots int
+			b := makeTestUpdateBuffer(budget, test.strict, &accounts, &slots)
+			require.NoError(t, b.makeRoom(test.ws))
+			require.NoError(t, b.merge(test.ws))
+			require.NoError(t, b.flush())
+
+			assert.Equal(t, test.wantFlushes, b.flushes)
+			if test.strict {
+				assert.LessOrEqual(t, b.highWater, uint64(budget))
+			} else {
+				assert.Greater(t, b.highWater, uint64(budget))
+			}
+
+			// all accounts and slots are primed, the storage of huge accounts possibly in several chunks
+			var wantSlots int
+			for _, acc := range test.ws {
+				wantSlots += len(acc.Storage)
+			}
+			assert.Equal(t, wantSlots, slots)
+			assert.GreaterOrEqual(t, accounts, len(test.ws))
+			assert.Zero(t, b.size)
+			assert.Empty(t, b.update)
+		})
+	}
+}
+
+func TestUpdateBuffer_StrictMergesAccountExceedingBudgetWithoutStorage(t *testing.T) {
+	var accounts, slots int
+	b := makeTestUpdateBuffer(100, true, &accounts, &slots)
+	ws := substate.WorldState{types.Address{1}: substate.NewAccount(1, uint256.NewInt(1), make([]byte, 1000))}
+
+	require.NoError(t, b.merge(ws))
+	assert.Equal(t, uint64(accountBytes+1000), b.size)
+	require.NoError(t, b.flush())
+	assert.Equal(t, 1, accounts)
+}
+
+func TestUpdateBuffer_FlushErrorIsReturned(t *testing.T) {
+	want := errors.New("prime failed")
+	b := newUpdateBuffer(1000, true, logger.NewLogger("critical", "test"), func(substate.WorldState) error {
+		return want
+	})
+	err := b.merge(makeBufferTestWorldState(10, 0))
+	require.ErrorIs(t, err, want)
+}
+
+func TestPrime_MayPrimeFromUpdateSet_StrictBufferFlushesEagerly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStateDb := state.NewMockStateDB(ctrl)
+	mockUpdateDb := db.NewMockUpdateDB(ctrl)
+	mockUpdateIter := db.NewMockIIterator[*updateset.UpdateSet](ctrl)
+	mockBulk := state.NewMockBulkLoad(ctrl)
+
+	cfg := &utils.Config{UpdateBufferSize: 50_000, UpdateBufferStrict: true}
+	p := newTestPrimer(5, 10, cfg, mockStateDb, mockUpdateDb, nil, nil, logger.NewLogger("critical", "test"))
+
+	// two update sets of 500 small accounts each, 277 accounts fit into the budget
+	updates := []*updateset.UpdateSet{
+		{Block: 5, WorldState: makeBufferTestWorldState(500, 0)},
+		{Block: 6, WorldState: makeBufferTestWorldState(1000, 0)},
+	}
+	for addr := range updates[1].WorldState {
+		if _, found := updates[0].WorldState[addr]; found {
+			delete(updates[1].WorldState, addr)
+		}
+	}
+
+	bulkLoads := 0
+	mockUpdateDb.EXPECT().NewUpdateSetIterator(uint64(5), uint64(9)).Return(mockUpdateIter)
+	gomock.InOrder(
+		mockUpdateIter.EXPECT().Next().Return(true),
+		mockUpdateIter.EXPECT().Value().Return(updates[0]),
+		mockUpdateIter.EXPECT().Next().Return(true),
+		mockUpdateIter.EXPECT().Value().Return(updates[1]),
+		mockUpdateIter.EXPECT().Next().Return(false),
+	)
+	mockUpdateIter.EXPECT().Error().Return(nil)
+	mockUpdateIter.EXPECT().Release()
+	mockStateDb.EXPECT().StartBulkLoad(gomock.Any()).DoAndReturn(func(uint64) (state.BulkLoad, error) {
+		bulkLoads++
+		return mockBulk, nil
+	}).AnyTimes()
+	mockBulk.EXPECT().CreateAccount(gomock.Any()).Times(1000)
+	mockBulk.EXPECT().SetBalance(gomock.Any(), gomock.Any()).Times(1000)
+	mockBulk.EXPECT().SetNonce(gomock.Any(), gomock.Any()).Times(1000)
+	mockBulk.EXPECT().SetCode(gomock.Any(), gomock.Any()).Times(1000)
+	mockBulk.EXPECT().Close().Return(nil).AnyTimes()
+
+	require.NoError(t, p.mayPrimeFromUpdateSet())
+	assert.Equal(t, 4, bulkLoads)
+}
@@ -0,0 +1,329 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package prime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/substate/rlp"
+	"github.com/0xsoniclabs/substate/types"
+	"github.com/0xsoniclabs/substate/types/hash"
+	trlp "github.com/0xsoniclabs/substate/types/rlp"
+	"github.com/0xsoniclabs/substate/updateset"
+)
+
+const (
+	// UpdateSetIndexName is the name of the index of an exported update-set layout.
+	UpdateSetIndexName = "index.json"
+
+	httpFetchAttempts = 3 // number of attempts to fetch a file before giving up
+)
+
+// httpRetryDelay is the delay before the second attempt to fetch a file; it doubles with every further attempt.
+var httpRetryDelay = 2 * time.Second
+
+// UpdateSetIndex lists the files of an exported update-set layout. Each file holds
+// the update set of a single block and is listed in block order.
+type UpdateSetIndex struct {
+	UpdateSets []UpdateSetFile `json:"updateSets"`
+}
+
+// UpdateSetFile describes the file holding the update set of a block.
+type UpdateSetFile struct {
+	Block  uint64 `json:"block"`
+	File   string `json:"file"`   // path relative to the index
+	Sha256 string `json:"sha256"` // hex encoded checksum of the file
+}
+
+// updateSetFileRLP is the content of an update-set file. Unlike the update-set
+// database, which keeps contract codes in a separate table, the file carries
+// all codes of its accounts.
+type updateSetFileRLP struct {
+	Block     uint64
+	UpdateSet *rlp.UpdateSetRLP
+	Codes     [][]byte
+}
+
+// NewHttpUpdateSetSource creates a source fetching update sets from an update-set layout
+// exported by `util-updateset export` and served at the given http(s) URL. Fetched files
+// are kept in cacheDir, which holds at most cacheSize bytes (unlimited if 0) by evicting
+// the least recently used files. If cacheDir is empty, the user cache directory is used.
+func NewHttpUpdateSetSource(baseUrl string, cacheDir string, cacheSize uint64, log logger.Logger) (UpdateSetSource, error) {
+	if cacheDir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			dir = os.TempDir()
+		}
+		cacheDir = filepath.Join(dir, "aida", "update-sets")
+	}
+	cache, err := newFileCache(cacheDir, cacheSize)
+	if err != nil {
+		return nil, err
+	}
+	s := &httpUpdateSetSource{
+		baseUrl: baseUrl,
+		client:  &http.Client{},
+		cache:   cache,
+		log:     log,
+	}
+	data, err := s.fetch(UpdateSetIndexName, "")
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &s.index); err != nil {
+		return nil, fmt.Errorf("cannot parse update-set index; %w", err)
+	}
+	for i, file := range s.index.UpdateSets {
+		if i > 0 && file.Block <= s.index.UpdateSets[i-1].Block {
+			return nil, fmt.Errorf("update-set index is not in block order; block %d listed after block %d", file.Block, s.index.UpdateSets[i-1].Block)
+		}
+		if file.File == "" {
+			return nil, fmt.Errorf("update-set index lacks file of block %d", file.Block)
+		}
+		// checksums name the cached files, hence they must not be arbitrary strings
+		if sum, err := hex.DecodeString(file.Sha256); err != nil || len(sum) != sha256.Size {
+			return nil, fmt.Errorf("update-set index has invalid checksum %q of block %d", file.Sha256, file.Block)
+		}
+	}
+	return s, nil
+}
+
+type httpUpdateSetSource struct {
+	baseUrl string
+	client  *http.Client
+	index   UpdateSetIndex
+	cache   *fileCache
+	log     logger.Logger
+}
+
+func (s *httpUpdateSetSource) GetFirstKey() (uint64, error) {
+	if len(s.index.UpdateSets) == 0 {
+		return 0, errors.New("remote update-set database is empty")
+	}
+	return s.index.UpdateSets[0].Block, nil
+}
+
+func (s *httpUpdateSetSource) NewUpdateSetIterator(first, last uint64) UpdateSetIterator {
+	var files []UpdateSetFile
+	for _, file := range s.index.UpdateSets {
+		if file.Block >= first && file.Block <= last {
+			files = append(files, file)
+		}
+	}
+	return &httpUpdateSetIterator{source: s, files: files}
+}
+
+// load returns the update set of the given file, preferably from the cache.
+func (s *httpUpdateSetSource) load(file UpdateSetFile) (*updateset.UpdateSet, error) {
+	data, found := s.cache.get(file.Sha256)
+	if !found {
+		var err error
+		data, err = s.fetch(file.File, file.Sha256)
+		if err != nil {
+			return nil, err
+		}
+		if err = s.cache.put(file.Sha256, data); err != nil {
+			s.log.Warningf("cannot cache update set of block %d; %v", file.Block, err)
+		}
+	}
+	set, err := decodeUpdateSetFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode update set of block %d; %w", file.Block, err)
+	}
+	if set.Block != file.Block {
+		return nil, fmt.Errorf("file %s holds update set of block %d instead of %d", file.File, set.Block, file.Block)
+	}
+	return set, nil
+}
+
+// fetch downloads the file at the given path relative to the base URL and verifies
+// its checksum unless it is empty. Failed downloads are retried unless the server
+// rejects the request.
+func (s *httpUpdateSetSource) fetch(path string, checksum string) ([]byte, error) {
+	fileUrl, err := url.JoinPath(s.baseUrl, path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid update-set url; %w", err)
+	}
+	delay := httpRetryDelay
+	for attempt := 1; ; attempt++ {
+		data, err := s.get(fileUrl)
+		if err == nil && checksum != "" {
+			err = verifyChecksum(data, checksum)
+		}
+		if err == nil {
+			return data, nil
+		}
+		var status httpStatusError
+		if attempt == httpFetchAttempts || (errors.As(err, &status) && !status.retryable()) {
+			return nil, fmt.Errorf("cannot fetch %s; %w", fileUrl, err)
+		}
+		s.log.Warningf("Attempt %d of %d to fetch %s failed; %v", attempt, httpFetchAttempts, fileUrl, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func (s *httpUpdateSetSource) get(fileUrl string) ([]byte, error) {
+	resp, err := s.client.Get(fileUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpStatusError{resp.StatusCode}
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// httpStatusError is returned if the server does not respond with 200 OK.
+type httpStatusError struct {
+	code int
+}
+
+func (e httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected http status %d %s", e.code, http.StatusText(e.code))
+}
+
+// retryable returns false if the server rejected the request itself.
+func (e httpStatusError) retryable() bool {
+	return e.code >= 500 || e.code == http.StatusRequestTimeout || e.code == http.StatusTooManyRequests
+}
+
+// verifyChecksum returns an error if the sha256 of data does not match the hex encoded checksum.
+func verifyChecksum(data []byte, checksum string) error {
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != checksum {
+		return fmt.Errorf("checksum mismatch; expected %s, got %s", checksum, got)
+	}
+	return nil
+}
+
+// httpUpdateSetIterator loads the update sets of the listed files one after another.
+type httpUpdateSetIterator struct {
+	source *httpUpdateSetSource
+	files  []UpdateSetFile
+	cur    *updateset.UpdateSet
+	err    error
+}
+
+func (i *httpUpdateSetIterator) Next() bool {
+	i.cur = nil
+	if i.err != nil || len(i.files) == 0 {
+		return false
+	}
+	i.cur, i.err = i.source.load(i.files[0])
+	i.files = i.files[1:]
+	return i.err == nil
+}
+
+func (i *httpUpdateSetIterator) Value() *updateset.UpdateSet {
+	return i.cur
+}
+
+func (i *httpUpdateSetIterator) Error() error {
+	return i.err
+}
+
+func (i *httpUpdateSetIterator) Release() {
+	i.files = nil
+	i.cur = nil
+}
+
+// WriteUpdateSetFiles exports all update sets of the iterator into dir, one file
+// per update set, and writes the index listing them. The directory can then be
+// served over http(s) and used as --update-db.
+func WriteUpdateSetFiles(iter UpdateSetIterator, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot create directory %s; %w", dir, err)
+	}
+	var index UpdateSetIndex
+	for iter.Next() {
+		set := iter.Value()
+		data, err := encodeUpdateSetFile(set)
+		if err != nil {
+			return fmt.Errorf("cannot encode update set of block %d; %w", set.Block, err)
+		}
+		name := fmt.Sprintf("updateset-%d.rlp", set.Block)
+		if err = os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		index.UpdateSets = append(index.UpdateSets, UpdateSetFile{
+			Block:  set.Block,
+			File:   name,
+			Sha256: hex.EncodeToString(sum[:]),
+		})
+	}
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("cannot iterate update sets; %w", err)
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, UpdateSetIndexName), data, 0644)
+}
+
+func encodeUpdateSetFile(set *updateset.UpdateSet) ([]byte, error) {
+	up, err := rlp.NewUpdateSetRLP(set.WorldState, set.DeletedAccounts)
+	if err != nil {
+		return nil, err
+	}
+	var codes [][]byte
+	for _, account := range set.WorldState {
+		if len(account.Code) > 0 {
+			codes = append(codes, account.Code)
+		}
+	}
+	return trlp.EncodeToBytes(updateSetFileRLP{Block: set.Block, UpdateSet: up, Codes: codes})
+}
+
+func decodeUpdateSetFile(data []byte) (*updateset.UpdateSet, error) {
+	var file updateSetFileRLP
+	if err := trlp.DecodeBytes(data, &file); err != nil {
+		return nil, err
+	}
+	codes := make(map[types.Hash][]byte, len(file.Codes))
+	for _, code := range file.Codes {
+		codes[hash.Keccak256Hash(code)] = code
+	}
+	emptyCodeHash := hash.Keccak256Hash(nil)
+	ws, err := file.UpdateSet.ToWorldState(func(codeHash types.Hash) ([]byte, error) {
+		code, found := codes[codeHash]
+		if !found && codeHash != emptyCodeHash {
+			return nil, fmt.Errorf("missing code with hash %v", codeHash)
+		}
+		return code, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	set := updateset.NewUpdateSet(*ws, file.Block)
+	set.DeletedAccounts = file.UpdateSet.DeletedAccounts
+	return set, nil
+}
@@ -0,0 +1,353 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package prime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/substate/substate"
+	"github.com/0xsoniclabs/substate/types"
+	"github.com/0xsoniclabs/substate/updateset"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRemoteUpdateDb(t *testing.T) {
+	tests := map[string]bool{
+		"":                             false,
+		"/path/to/aida-db":             false,
+		"httpdir/aida-db":              false,
+		"http://localhost:8080/update": true,
+		"https://example.com/update":   true,
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, IsRemoteUpdateDb(path), path)
+	}
+}
+
+func TestHttpUpdateSetSource_ReadsExportedUpdateSets(t *testing.T) {
+	sets := makeTestUpdateSets()
+	server := serveUpdateSets(t, sets)
+
+	source, err := NewHttpUpdateSetSource(server.URL, t.TempDir(), 0, logger.NewLogger("Info", "Test"))
+	require.NoError(t, err)
+
+	first, err := source.GetFirstKey()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), first)
+
+	assertUpdateSets(t, sets, source.NewUpdateSetIterator(0, 100))
+	assertUpdateSets(t, sets[1:2], source.NewUpdateSetIterator(15, 25))
+	assertUpdateSets(t, nil, source.NewUpdateSetIterator(31, 100))
+}
+
+func TestHttpUpdateSetSource_SecondAccessIsServedFromCache(t *testing.T) {
+	sets := makeTestUpdateSets()
+	server := serveUpdateSets(t, sets)
+	cacheDir := t.TempDir()
+
+	// every priming run creates its own source sharing the cache directory
+	for run := 0; run < 2; run++ {
+		source, err := NewHttpUpdateSetSource(server.URL, cacheDir, 0, logger.NewLogger("Info", "Test"))
+		require.NoError(t, err)
+		assertUpdateSets(t, sets, source.NewUpdateSetIterator(0, 100))
+	}
+
+	assert.Equal(t, 2, server.requests("/"+UpdateSetIndexName))
+	for _, set := range sets {
+		assert.Equal(t, 1, server.requests(updateSetPath(set.Block)), "update set of block %d", set.Block)
+	}
+}
+
+func TestHttpUpdateSetSource_RefetchesCorruptedCacheEntries(t *testing.T) {
+	sets := makeTestUpdateSets()
+	server := serveUpdateSets(t, sets)
+	cacheDir := t.TempDir()
+
+	source, err := NewHttpUpdateSetSource(server.URL, cacheDir, 0, logger.NewLogger("Info", "Test"))
+	require.NoError(t, err)
+	assertUpdateSets(t, sets, source.NewUpdateSetIterator(0, 100))
+
+	cached, err := filepath.Glob(filepath.Join(cacheDir, "*"+cachedFileSuffix))
+	require.NoError(t, err)
+	require.Len(t, cached, len(sets))
+	for _, path := range cached {
+		require.NoError(t, os.WriteFile(path, []byte("corrupted"), 0644))
+	}
+
+	source, err = NewHttpUpdateSetSource(server.URL, cacheDir, 0, logger.NewLogger("Info", "Test"))
+	require.NoError(t, err)
+	assertUpdateSets(t, sets, source.NewUpdateSetIterator(0, 100))
+	for _, set := range sets {
+		assert.Equal(t, 2, server.requests(updateSetPath(set.Block)), "update set of block %d", set.Block)
+	}
+}
+
+func TestHttpUpdateSetSource_RetriesFailedFetches(t *testing.T) {
+	setFastRetries(t)
+	sets := makeTestUpdateSets()
+	server := serveUpdateSets(t, sets)
+	server.failures[updateSetPath(20)] = httpFetchAttempts - 1
+
+	source, err := NewHttpUpdateSetSource(server.URL, t.TempDir(), 0, logger.NewLogger("Info", "Test"))
+	require.NoError(t, err)
+	assertUpdateSets(t, sets, source.NewUpdateSetIterator(0, 100))
+	assert.Equal(t, httpFetchAttempts, server.requests(updateSetPath(20)))
+}
+
+func TestHttpUpdateSetSource_GivesUpAfterLastAttempt(t *testing.T) {
+	setFastRetries(t)
+	sets := makeTestUpdateSets()
+	server := serveUpdateSets(t, sets)
+	server.failures[updateSetPath(20)] = httpFetchAttempts
+
+	source, err := NewHttpUpdateSetSource(server.URL, t.TempDir(), 0, logger.NewLogger("Info", "Test"))
+	require.NoError(t, err)
+	iter := source.NewUpdateSetIterator(0, 100)
+	defer iter.Release()
+	require.True(t, iter.Next())
+	assert.False(t, iter.Next())
+	assert.ErrorContains(t, iter.Error(), "unexpected http status 503")
+	assert.False(t, iter.Next())
+	assert.Equal(t, httpFetchAttempts, server.requests(updateSetPath(20)))
+}
+
+func TestHttpUpdateSetSource_DoesNotRetryRejectedRequests(t *testing.T) {
+	setFastRetries(t)
+	sets := makeTestUpdateSets()
+	server := serveUpdateSets(t, sets)
+	require.NoError(t, os.Remove(filepath.Join(server.dir, updateSetPath(20))))
+
+	source, err := NewHttpUpdateSetSource(server.URL, t.TempDir(), 0, logger.NewLogger("Info", "Test"))
+	require.NoError(t, err)
+	iter := source.NewUpdateSetIterator(20, 20)
+	defer iter.Release()
+	assert.False(t, iter.Next())
+	assert.ErrorContains(t, iter.Error(), "unexpected http status 404")
+	assert.Equal(t, 1, server.requests(updateSetPath(20)))
+}
+
+func TestHttpUpdateSetSource_RejectsFilesWithWrongChecksum(t *testing.T) {
+	setFastRetries(t)
+	sets := makeTestUpdateSets()
+	server := serveUpdateSets(t, sets)
+	require.NoError(t, os.WriteFile(filepath.Join(server.dir, updateSetPath(10)), []byte("tampered"), 0644))
+	cacheDir := t.TempDir()
+
+	source, err := NewHttpUpdateSetSource(server.URL, cacheDir, 0, logger.NewLogger("Info", "Test"))
+	require.NoError(t, err)
+	iter := source.NewUpdateSetIterator(0, 100)
+	defer iter.Release()
+	assert.False(t, iter.Next())
+	assert.ErrorContains(t, iter.Error(), "checksum mismatch")
+	assert.Equal(t, httpFetchAttempts, server.requests(updateSetPath(10)))
+
+	cached, err := filepath.Glob(filepath.Join(cacheDir, "*"))
+	require.NoError(t, err)
+	assert.Empty(t, cached, "rejected files must not be cached")
+}
+
+func TestNewHttpUpdateSetSource_RejectsInvalidIndex(t *testing.T) {
+	checksum := hex.EncodeToString(make([]byte, sha256.Size))
+	tests := map[string]struct {
+		index string
+		want  string
+	}{
+		"malformed": {
+			index: "{",
+			want:  "cannot parse update-set index",
+		},
+		"unordered": {
+			index: `{"updateSets":[{"block":2,"file":"a","sha256":"` + checksum + `"},{"block":1,"file":"b","sha256":"` + checksum + `"}]}`,
+			want:  "not in block order",
+		},
+		"missing-file": {
+			index: `{"updateSets":[{"block":1,"sha256":"` + checksum + `"}]}`,
+			want:  "lacks file of block 1",
+		},
+		"invalid-checksum": {
+			index: `{"updateSets":[{"block":1,"file":"a","sha256":"../../escape"}]}`,
+			want:  "invalid checksum",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(test.index))
+			}))
+			defer server.Close()
+
+			_, err := NewHttpUpdateSetSource(server.URL, t.TempDir(), 0, logger.NewLogger("Info", "Test"))
+			assert.ErrorContains(t, err, test.want)
+		})
+	}
+}
+
+func TestNewHttpUpdateSetSource_FailsWithoutIndex(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := NewHttpUpdateSetSource(server.URL, t.TempDir(), 0, logger.NewLogger("Info", "Test"))
+	assert.ErrorContains(t, err, "unexpected http status 404")
+}
+
+func TestHttpUpdateSetSource_EmptyIndexHasNoFirstKey(t *testing.T) {
+	server := serveUpdateSets(t, nil)
+
+	source, err := NewHttpUpdateSetSource(server.URL, t.TempDir(), 0, logger.NewLogger("Info", "Test"))
+	require.NoError(t, err)
+	_, err = source.GetFirstKey()
+	assert.ErrorContains(t, err, "empty")
+}
+
+func TestWriteUpdateSetFiles_WritesIndex(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, WriteUpdateSetFiles(&testUpdateSetIterator{sets: makeTestUpdateSets()}, dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, UpdateSetIndexName))
+	require.NoError(t, err)
+	var index UpdateSetIndex
+	require.NoError(t, json.Unmarshal(data, &index))
+	require.Len(t, index.UpdateSets, 3)
+	for i, block := range []uint64{10, 20, 30} {
+		file := index.UpdateSets[i]
+		assert.Equal(t, block, file.Block)
+		content, err := os.ReadFile(filepath.Join(dir, file.File))
+		require.NoError(t, err)
+		assert.NoError(t, verifyChecksum(content, file.Sha256))
+	}
+}
+
+// makeTestUpdateSets returns update sets covering codes, storage and deleted accounts.
+func makeTestUpdateSets() []*updateset.UpdateSet {
+	withCode := substate.NewWorldState().
+		Add(types.Address{1}, 1, uint256.NewInt(100), []byte{0x60, 0x00, 0x60, 0x00}).
+		Add(types.Address{2}, 2, uint256.NewInt(200), nil)
+	withStorage := substate.NewWorldState().
+		Add(types.Address{3}, 3, uint256.NewInt(300), []byte{0x5f})
+	withStorage[types.Address{3}].Storage[types.Hash{1}] = types.Hash{2}
+	return []*updateset.UpdateSet{
+		{Block: 10, WorldState: withCode, DeletedAccounts: []types.Address{}},
+		{Block: 20, WorldState: withStorage, DeletedAccounts: []types.Address{{9}}},
+		{Block: 30, WorldState: substate.NewWorldState(), DeletedAccounts: []types.Address{{7}, {8}}},
+	}
+}
+
+func assertUpdateSets(t *testing.T, want []*updateset.UpdateSet, iter UpdateSetIterator) {
+	t.Helper()
+	defer iter.Release()
+	var got []*updateset.UpdateSet
+	for iter.Next() {
+		got = append(got, iter.Value())
+	}
+	require.NoError(t, iter.Error())
+	require.Len(t, got, len(want))
+	for i := range want {
+		assert.True(t, want[i].Equal(got[i]), "update set of block %d differs", want[i].Block)
+		assert.Equal(t, want[i].DeletedAccounts, got[i].DeletedAccounts)
+	}
+}
+
+// updateSetPath returns the url path of the exported update set of the block.
+func updateSetPath(block uint64) string {
+	return fmt.Sprintf("/updateset-%d.rlp", block)
+}
+
+// setFastRetries shortens the delay between fetch attempts for the duration of the test.
+func setFastRetries(t *testing.T) {
+	delay := httpRetryDelay
+	httpRetryDelay = time.Millisecond
+	t.Cleanup(func() { httpRetryDelay = delay })
+}
+
+// testUpdateSetServer serves exported update sets and counts the requests per path.
+type testUpdateSetServer struct {
+	*httptest.Server
+	dir      string
+	mu       sync.Mutex
+	counts   map[string]int
+	failures map[string]int // number of requests of a path answered with 503 before serving it
+}
+
+func serveUpdateSets(t *testing.T, sets []*updateset.UpdateSet) *testUpdateSetServer {
+	t.Helper()
+	s := &testUpdateSetServer{
+		dir:      t.TempDir(),
+		counts:   make(map[string]int),
+		failures: make(map[string]int),
+	}
+	require.NoError(t, WriteUpdateSetFiles(&testUpdateSetIterator{sets: sets}, s.dir))
+	files := http.FileServer(http.Dir(s.dir))
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.counts[r.URL.Path]++
+		fail := s.failures[r.URL.Path] > 0
+		if fail {
+			s.failures[r.URL.Path]--
+		}
+		s.mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testUpdateSetServer) requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counts[path]
+}
+
+// testUpdateSetIterator iterates over a slice of update sets.
+type testUpdateSetIterator struct {
+	sets []*updateset.UpdateSet
+	cur  *updateset.UpdateSet
+}
+
+func (i *testUpdateSetIterator) Next() bool {
+	if len(i.sets) == 0 {
+		return false
+	}
+	i.cur, i.sets = i.sets[0], i.sets[1:]
+	return true
+}
+
+func (i *testUpdateSetIterator) Value() *updateset.UpdateSet {
+	return i.cur
+}
+
+func (i *testUpdateSetIterator) Error() error {
+	return nil
+}
+
+func (i *testUpdateSetIterator) Release() {}
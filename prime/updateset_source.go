@@ -0,0 +1,66 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package prime
+
+import (
+	"strings"
+
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/0xsoniclabs/substate/updateset"
+)
+
+// UpdateSetSource supplies the update sets used for priming.
+type UpdateSetSource interface {
+	// GetFirstKey returns the first block holding an update set.
+	GetFirstKey() (uint64, error)
+	// NewUpdateSetIterator iterates in block order over the update sets of blocks in [first, last].
+	NewUpdateSetIterator(first, last uint64) UpdateSetIterator
+}
+
+// UpdateSetIterator iterates over update sets in block order.
+type UpdateSetIterator interface {
+	// Next moves to the next update set and reports whether there is one.
+	Next() bool
+	// Value returns the current update set.
+	Value() *updateset.UpdateSet
+	// Error returns the error which stopped the iteration, if any.
+	Error() error
+	// Release releases the resources of the iterator.
+	Release()
+}
+
+// NewFileUpdateSetSource creates a source reading update sets from an update-set database.
+func NewFileUpdateSetSource(udb db.UpdateDB) UpdateSetSource {
+	return fileUpdateSetSource{udb}
+}
+
+type fileUpdateSetSource struct {
+	udb db.UpdateDB
+}
+
+func (s fileUpdateSetSource) GetFirstKey() (uint64, error) {
+	return s.udb.GetFirstKey()
+}
+
+func (s fileUpdateSetSource) NewUpdateSetIterator(first, last uint64) UpdateSetIterator {
+	return s.udb.NewUpdateSetIterator(first, last)
+}
+
+// IsRemoteUpdateDb returns true if the update-set database is served over http(s).
+func IsRemoteUpdateDb(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
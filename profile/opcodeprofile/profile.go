@@ -0,0 +1,206 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+// Package opcodeprofile provides per-opcode execution statistics of the EVM.
+package opcodeprofile
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/vm"
+	// Your main or test packages require this import so the sql package is properly initialized.
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// SQL statement for creating the opcode profiling table
+	createSQL = `
+PRAGMA journal_mode = MEMORY;
+CREATE TABLE IF NOT EXISTS opcodeProfile (
+	vm TEXT,
+	opcode TEXT,
+	count INTEGER,
+	gas INTEGER
+);
+`
+	// SQL statement for inserting statistics of a single opcode
+	insertSQL = `
+INSERT INTO opcodeProfile (
+	vm, opcode, count, gas
+) VALUES (
+	?, ?, ?, ?
+)
+`
+)
+
+// OpcodeStats contains aggregated statistics of a single opcode.
+type OpcodeStats struct {
+	Count uint64 // number of executions
+	Gas   uint64 // accumulated gas charged by the executions
+}
+
+// Profile aggregates opcode statistics of transactions executed by
+// any number of workers in parallel.
+type Profile struct {
+	mutex sync.Mutex
+	stats [256]OpcodeStats
+}
+
+// NewProfile creates an empty opcode profile.
+func NewProfile() *Profile {
+	return &Profile{}
+}
+
+// Get returns the statistics of the given opcode.
+func (p *Profile) Get(op vm.OpCode) OpcodeStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.stats[op]
+}
+
+// IsEmpty returns true if no opcode was recorded.
+func (p *Profile) IsEmpty() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, s := range p.stats {
+		if s.Count > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// opcodeEntry is the statistics of an opcode used for reporting.
+type opcodeEntry struct {
+	op vm.OpCode
+	OpcodeStats
+}
+
+// entries returns statistics of all executed opcodes ordered by their frequency.
+func (p *Profile) entries() []opcodeEntry {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var res []opcodeEntry
+	for op, s := range p.stats {
+		if s.Count > 0 {
+			res = append(res, opcodeEntry{vm.OpCode(op), s})
+		}
+	}
+	slices.SortFunc(res, func(a, b opcodeEntry) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.op, b.op)
+	})
+	return res
+}
+
+// WriteTable writes the profile as a table ordered by opcode frequency.
+func (p *Profile) WriteTable(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	if _, err := fmt.Fprintln(w, "opcode\tcount\tgas\tgas/op\t"); err != nil {
+		return err
+	}
+	for _, e := range p.entries() {
+		if _, err := fmt.Fprintf(w, "%v\t%d\t%d\t%.2f\t\n", e.op, e.Count, e.Gas, float64(e.Gas)/float64(e.Count)); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// WriteToDb stores the profile in the sqlite database at given path. The rows
+// are labeled by the name of the profiled vm, so profiles of different vms can
+// be compared within the same database.
+func (p *Profile) WriteToDb(dbFile string, vmName string) (err error) {
+	sqlDB, err := sql.Open("sqlite3", dbFile)
+	if err != nil {
+		return fmt.Errorf("failed to open database %v; %w", dbFile, err)
+	}
+	defer func() {
+		if closeErr := sqlDB.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	if _, err = sqlDB.Exec(createSQL); err != nil {
+		return fmt.Errorf("failed to create opcode profile table; %w", err)
+	}
+
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		return err
+	}
+	for _, e := range p.entries() {
+		if _, err = tx.Exec(insertSQL, vmName, e.op.String(), e.Count, e.Gas); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to insert statistics of %v; %w", e.op, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Collector records opcodes executed by a single transaction. Recorded
+// statistics are added to the profile once the transaction is finished,
+// hence the profile lock is not contended for each executed opcode.
+type Collector struct {
+	profile *Profile
+	stats   [256]OpcodeStats
+}
+
+// NewCollector creates a collector adding its statistics to the profile.
+func (p *Profile) NewCollector() *Collector {
+	return &Collector{profile: p}
+}
+
+// Hooks returns a copy of the given tracer hooks, which additionally records
+// every executed opcode. Inner hooks may be nil.
+func (c *Collector) Hooks(inner *tracing.Hooks) *tracing.Hooks {
+	hooks := &tracing.Hooks{}
+	if inner != nil {
+		*hooks = *inner
+	}
+	next := hooks.OnOpcode
+	hooks.OnOpcode = func(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+		c.onOpcode(op, cost)
+		if next != nil {
+			next(pc, op, gas, cost, scope, rData, depth, err)
+		}
+	}
+	return hooks
+}
+
+func (c *Collector) onOpcode(op byte, cost uint64) {
+	c.stats[op].Count++
+	c.stats[op].Gas += cost
+}
+
+// Flush adds the recorded statistics to the profile and resets the collector.
+func (c *Collector) Flush() {
+	c.profile.mutex.Lock()
+	defer c.profile.mutex.Unlock()
+	for op, s := range c.stats {
+		c.profile.stats[op].Count += s.Count
+		c.profile.stats[op].Gas += s.Gas
+	}
+	c.stats = [256]OpcodeStats{}
+}
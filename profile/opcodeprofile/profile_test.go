@@ -0,0 +1,145 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package opcodeprofile
+
+import (
+	"bytes"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func record(c *Collector, op vm.OpCode, cost uint64) {
+	c.Hooks(nil).OnOpcode(0, byte(op), 0, cost, nil, nil, 0, nil)
+}
+
+func TestCollector_StatisticsAreAddedOnFlush(t *testing.T) {
+	profile := NewProfile()
+	collector := profile.NewCollector()
+	record(collector, vm.PUSH1, 3)
+	record(collector, vm.PUSH1, 3)
+	record(collector, vm.SSTORE, 20_000)
+
+	assert.True(t, profile.IsEmpty())
+	collector.Flush()
+	assert.False(t, profile.IsEmpty())
+	assert.Equal(t, OpcodeStats{Count: 2, Gas: 6}, profile.Get(vm.PUSH1))
+	assert.Equal(t, OpcodeStats{Count: 1, Gas: 20_000}, profile.Get(vm.SSTORE))
+
+	// flushed statistics are not added twice
+	collector.Flush()
+	assert.Equal(t, OpcodeStats{Count: 2, Gas: 6}, profile.Get(vm.PUSH1))
+}
+
+func TestCollector_InnerHooksAreCalled(t *testing.T) {
+	var ops []vm.OpCode
+	inner := &tracing.Hooks{
+		OnOpcode: func(_ uint64, op byte, _, _ uint64, _ tracing.OpContext, _ []byte, _ int, _ error) {
+			ops = append(ops, vm.OpCode(op))
+		},
+		OnExit: func(int, []byte, uint64, error, bool) {},
+	}
+
+	collector := NewProfile().NewCollector()
+	hooks := collector.Hooks(inner)
+	hooks.OnOpcode(0, byte(vm.ADD), 0, 3, nil, nil, 0, nil)
+
+	assert.Equal(t, []vm.OpCode{vm.ADD}, ops)
+	assert.NotNil(t, hooks.OnExit)
+	assert.Equal(t, uint64(1), collector.stats[vm.ADD].Count)
+}
+
+func TestProfile_CollectorsCanBeFlushedInParallel(t *testing.T) {
+	const numWorkers = 8
+	profile := NewProfile()
+
+	var wg sync.WaitGroup
+	for range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				collector := profile.NewCollector()
+				record(collector, vm.ADD, 3)
+				collector.Flush()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, OpcodeStats{Count: numWorkers * 100, Gas: numWorkers * 300}, profile.Get(vm.ADD))
+}
+
+func TestProfile_WriteTableOrdersOpcodesByFrequency(t *testing.T) {
+	profile := NewProfile()
+	collector := profile.NewCollector()
+	record(collector, vm.SSTORE, 20_000)
+	record(collector, vm.PUSH1, 3)
+	record(collector, vm.PUSH1, 3)
+	collector.Flush()
+
+	var out bytes.Buffer
+	require.NoError(t, profile.WriteTable(&out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "opcode")
+	assert.Equal(t, []string{"PUSH1", "2", "6", "3.00"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"SSTORE", "1", "20000", "20000.00"}, strings.Fields(lines[2]))
+}
+
+func TestProfile_WriteToDbLabelsRowsByVm(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "profiling.db")
+	for _, name := range []string{"geth", "lfvm"} {
+		profile := NewProfile()
+		collector := profile.NewCollector()
+		record(collector, vm.PUSH1, 3)
+		collector.Flush()
+		require.NoError(t, profile.WriteToDb(dbFile, name))
+	}
+
+	db, err := sql.Open("sqlite3", dbFile)
+	require.NoError(t, err)
+	defer db.Close()
+	rows, err := db.Query("SELECT vm, opcode, count, gas FROM opcodeProfile ORDER BY vm")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var got []string
+	for rows.Next() {
+		var vmName, opcode string
+		var count, gas uint64
+		require.NoError(t, rows.Scan(&vmName, &opcode, &count, &gas))
+		assert.Equal(t, uint64(1), count)
+		assert.Equal(t, uint64(3), gas)
+		got = append(got, vmName+":"+opcode)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"geth:PUSH1", "lfvm:PUSH1"}, got)
+}
+
+func TestProfile_WriteToDbFailsForInvalidPath(t *testing.T) {
+	profile := NewProfile()
+	err := profile.WriteToDb(filepath.Join(t.TempDir(), "missing", "profiling.db"), "geth")
+	require.Error(t, err)
+}
@@ -55,6 +55,14 @@ const (
 	bashCmdIpAddress     = "curl -s api.ipify.org"
 )
 
+// csvColumns lists the metadata exported by WriteCsv in order of the columns.
+var csvColumns = []string{
+	"RunId", "AppName", "CommandName", "First", "Last",
+	"DbImpl", "DbVariant", "CarmenSchema", "VmImpl", "ArchiveMode",
+	"AidaGitHash", "CarmenGitHash", "ToscaGitHash",
+	"Timestamp", "EndTimestamp", "Runtime", "RunSucceed", "RunError",
+}
+
 type RunMetadata struct {
 	Meta map[string]string
 	Ps   *utils.Printers
@@ -112,6 +120,22 @@ func (rm *RunMetadata) Close() error {
 	return rm.Ps.Close()
 }
 
+// WriteCsv writes a selection of the metadata as a single row into a csv file
+// at the given path. Metadata which were not collected are left empty.
+func (rm *RunMetadata) WriteCsv(path string) error {
+	p, err := utils.NewPrinterToCsv(path, csvColumns, func() [][]any {
+		row := make([]any, len(csvColumns))
+		for i, key := range csvColumns {
+			row[i] = rm.Meta[key]
+		}
+		return [][]any{row}
+	})
+	if err != nil {
+		return err
+	}
+	return errors.Join(p.Print(), p.Close())
+}
+
 // fetchEnvInfo fetches environment info by executing a number of linux commands.
 // Any errors are collected and returned.
 func FetchUnixInfo() (map[string]string, error) {
@@ -17,10 +17,14 @@
 package register
 
 import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -72,6 +76,39 @@ func TestRunMetadata_Close(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRunMetadata_WriteCsv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run_metadata.csv")
+	rm := &RunMetadata{
+		Meta: map[string]string{
+			"RunId":       "run",
+			"CommandName": "substate",
+			"First":       "1",
+			"Last":        "10",
+			"Unexported":  "value",
+		},
+		Ps: utils.NewPrinters(),
+	}
+	require.NoError(t, rm.WriteCsv(path))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, csvColumns, records[0])
+	row := make(map[string]string)
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	assert.Equal(t, "run", row["RunId"])
+	assert.Equal(t, "substate", row["CommandName"])
+	assert.Equal(t, "1", row["First"])
+	assert.Equal(t, "10", row["Last"])
+	assert.Equal(t, "", row["DbImpl"])
+	assert.NotContains(t, row, "Unexported")
+}
+
 func TestRunMetadata_sqlite3(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -18,6 +18,8 @@ package rpc
 
 import (
 	"encoding/binary"
+	"encoding/json"
+	"fmt"
 	"strings"
 	"unsafe"
 
@@ -25,12 +27,27 @@ import (
 	"github.com/0xsoniclabs/aida/txcontext"
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // TODO FIX!
 const falsyContract = "0xe0c38b2a8d09aad53f1c67734b9a95e43d5981c0"
 
+// requiredParams is the number of parameters each executed method expects.
+var requiredParams = map[string]int{
+	"getBalance":          1,
+	"getTransactionCount": 1,
+	"call":                1,
+	"getCode":             1,
+	"getStorageAt":        2,
+}
+
 func Execute(block uint64, rec *RequestAndResults, archive state.NonCommittableStateDB, cfg *utils.Config) (txcontext.Result, error) {
+	if n := requiredParams[rec.Query.MethodBase]; len(rec.Query.Params) < n {
+		return nil, fmt.Errorf("%v requires %d parameters, got %d", rec.Query.Method, n, len(rec.Query.Params))
+	}
+
 	switch rec.Query.MethodBase {
 	case "getBalance":
 		return executeGetBalance(rec.Query.Params[0], archive), nil
@@ -58,6 +75,8 @@ func Execute(block uint64, rec *RequestAndResults, archive state.NonCommittableS
 		return executeGetCode(rec.Query.Params[0], archive), nil
 	case "getStorageAt":
 		return executeGetStorageAt(rec.Query.Params, archive), nil
+	case "getLogs":
+		return executeGetLogs(block, rec.Query.Params, archive), nil
 	default:
 		break
 	}
@@ -131,3 +150,94 @@ func executeGetStorageAt(params []interface{}, archive state.VmStateDB) *result
 		result: archive.GetState(address, hash).Bytes(),
 	}
 }
+
+// executeGetLogs queries logs matching the filter of the request from given archive and
+// sends them JSON encoded, as returned by the RPC-API, to comparator
+func executeGetLogs(block uint64, params []interface{}, archive state.VmStateDB) *result {
+	var filter map[string]interface{}
+	if len(params) > 0 {
+		filter, _ = params[0].(map[string]interface{})
+	}
+
+	logs := make([]*types.Log, 0)
+	for _, log := range archive.GetLogs(common.Hash{}, block, common.Hash{}, 0) {
+		l := *log
+		// not all StateDB implementations are aware of the block of the log
+		if l.BlockNumber == 0 {
+			l.BlockNumber = block
+		}
+		if matchesLogFilter(filter, &l) {
+			logs = append(logs, &l)
+		}
+	}
+
+	res, err := json.Marshal(logs)
+	return &result{
+		result: res,
+		err:    err,
+	}
+}
+
+// matchesLogFilter returns true if the log satisfies the block range, the addresses and
+// the topics of given eth_getLogs filter. Missing filter criteria match any log.
+func matchesLogFilter(filter map[string]interface{}, log *types.Log) bool {
+	if from, ok := filterBlock(filter["fromBlock"]); ok && log.BlockNumber < from {
+		return false
+	}
+	if to, ok := filterBlock(filter["toBlock"]); ok && log.BlockNumber > to {
+		return false
+	}
+
+	if addresses := filterValues(filter["address"]); len(addresses) > 0 && !containsHex(addresses, log.Address.Hex()) {
+		return false
+	}
+
+	topics, _ := filter["topics"].([]interface{})
+	if len(topics) > len(log.Topics) {
+		return false
+	}
+	for i, topic := range topics {
+		// a nil position matches any topic
+		if wanted := filterValues(topic); len(wanted) > 0 && !containsHex(wanted, log.Topics[i].Hex()) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterBlock decodes a block number of the filter; block tags such as latest are not limiting.
+func filterBlock(value interface{}) (uint64, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	block, err := hexutil.DecodeUint64(str)
+	return block, err == nil
+}
+
+// filterValues returns the values of a filter criterion which is either a single value or a list of values.
+func filterValues(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		res := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				res = append(res, str)
+			}
+		}
+		return res
+	default:
+		return nil
+	}
+}
+
+func containsHex(values []string, hex string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, hex) {
+			return true
+		}
+	}
+	return false
+}
@@ -17,6 +17,7 @@
 package rpc
 
 import (
+	"encoding/json"
 	"math/big"
 	"testing"
 
@@ -24,9 +25,11 @@ import (
 	"github.com/0xsoniclabs/aida/utils"
 	"github.com/0xsoniclabs/sonic/opera"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -217,3 +220,50 @@ func TestRpc_executeGetStorageAt(t *testing.T) {
 	out := executeGetStorageAt([]interface{}{"0x1234567890abcdef", "0x0"}, mockArchive)
 	assert.NotNil(t, out)
 }
+
+func TestRpc_executeGetLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addrA := common.HexToAddress("0xa")
+	addrB := common.HexToAddress("0xb")
+	topicA := common.HexToHash("0x1")
+	topicB := common.HexToHash("0x2")
+	logs := []*types.Log{
+		{Address: addrA, Topics: []common.Hash{topicA, topicB}, Data: []byte{1}},
+		{Address: addrA, Topics: []common.Hash{topicB}, Data: []byte{2}, Index: 1},
+		{Address: addrB, Topics: []common.Hash{topicA}, Data: []byte{3}, Index: 2},
+	}
+
+	tests := map[string]struct {
+		filter  map[string]interface{}
+		indices []uint
+	}{
+		"no filter":          {map[string]interface{}{}, []uint{0, 1, 2}},
+		"single address":     {map[string]interface{}{"address": addrB.Hex()}, []uint{2}},
+		"address list":       {map[string]interface{}{"address": []interface{}{addrA.Hex()}}, []uint{0, 1}},
+		"first topic":        {map[string]interface{}{"topics": []interface{}{topicA.Hex()}}, []uint{0, 2}},
+		"any first topic":    {map[string]interface{}{"topics": []interface{}{nil, topicB.Hex()}}, []uint{0}},
+		"topic alternatives": {map[string]interface{}{"topics": []interface{}{[]interface{}{topicA.Hex(), topicB.Hex()}}}, []uint{0, 1, 2}},
+		"block in range":     {map[string]interface{}{"fromBlock": "0x5", "toBlock": "latest"}, []uint{0, 1, 2}},
+		"block out of range": {map[string]interface{}{"fromBlock": "0x6"}, []uint{}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockArchive := state.NewMockVmStateDB(ctrl)
+			mockArchive.EXPECT().GetLogs(common.Hash{}, uint64(5), common.Hash{}, uint64(0)).Return(logs)
+
+			out := executeGetLogs(5, []interface{}{test.filter}, mockArchive)
+			require.NoError(t, out.err)
+
+			var got []*types.Log
+			require.NoError(t, json.Unmarshal(out.result, &got))
+			indices := make([]uint, 0, len(got))
+			for _, log := range got {
+				assert.Equal(t, uint64(5), log.BlockNumber)
+				indices = append(indices, log.Index)
+			}
+			assert.Equal(t, test.indices, indices)
+		})
+	}
+}
@@ -21,11 +21,12 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"time"
 
 	"github.com/sigurn/crc8"
 )
 
-// Record Header Structure (min 18 bytes, max 21 bytes per data):
+// Record Header Structure (min 18 bytes, max 25 bytes per data):
 // +-----+-----+-----+-----+-----+-----+-----+-----+
 // | ERR | HiQ | HiR |  Version  |    Namespace    |
 // +-----+-----+-----+-----+-----+-----+-----+-----+
@@ -55,6 +56,11 @@ import (
 // |                                               |
 // |                                               |
 // +-----+-----+-----+-----+-----+-----+-----+-----+
+// |                                               |
+// |       Upstream Latency in Microseconds        |
+// |       (32 bits; present in Version 2 only)    |
+// |                                               |
+// +-----+-----+-----+-----+-----+-----+-----+-----+
 // |                 CRC8 Checksum                 |
 // +-----+-----+-----+-----+-----+-----+-----+-----+
 
@@ -67,7 +73,10 @@ const maxShortQuery = 0xFFF
 // maxShortResponse represents the longest response payload still considered as short (16 bits uint).
 const maxShortResponse = 0xFFFF
 
-const headerSize = 21
+const headerSize = 25
+
+// maxLatencyMicros represents the longest upstream latency the header can carry (32 bits of microseconds).
+const maxLatencyMicros = 0xFFFFFFFF
 
 // Header represents a single data header on a virtual recording tape represented by a Reader/Writer.
 type Header struct {
@@ -81,6 +90,7 @@ type Header struct {
 	resultCodeSize int32 // also used for error code; see ERR flag
 	blockID        uint64
 	blockTimestamp uint64
+	latencyMicros  uint32
 }
 
 // namespaceDictionary represents a dictionary of call namespace for encoding.
@@ -202,6 +212,25 @@ func (h *Header) BlockTimestamp() uint64 {
 	return h.blockTimestamp
 }
 
+// SetLatency configures the time the upstream node needed to answer the query.
+// The latency is stored with microsecond precision; longer latencies are capped.
+// Zero means the latency is unknown and keeps the header in the version 1 format.
+func (h *Header) SetLatency(d time.Duration) {
+	micros := d.Microseconds()
+	switch {
+	case micros < 0:
+		micros = 0
+	case micros > maxLatencyMicros:
+		micros = maxLatencyMicros
+	}
+	h.latencyMicros = uint32(micros)
+}
+
+// Latency returns the recorded upstream latency of the query, or zero if it was not recorded.
+func (h *Header) Latency() time.Duration {
+	return time.Duration(h.latencyMicros) * time.Microsecond
+}
+
 // SetQueryLength configures the query length.
 func (h *Header) SetQueryLength(ql int) error {
 	// we have to skip queries too big to be stored
@@ -274,17 +303,29 @@ func (h *Header) WriteTo(out io.Writer) (int64, error) {
 	// append the block timestamp
 	binary.BigEndian.PutUint64(hdr[offset+4:offset+12], h.blockTimestamp)
 
+	offset += 12
+
+	// append the upstream latency, if known
+	if h.latencyMicros > 0 {
+		binary.BigEndian.PutUint32(hdr[offset:offset+4], h.latencyMicros)
+		offset += 4
+	}
+
 	// add the CRC8/CDMA2000 checksum
-	hdr[offset+12] = crc8.Checksum(hdr[:offset+12], checksumTable)
+	hdr[offset] = crc8.Checksum(hdr[:offset], checksumTable)
 
-	n, e := out.Write(hdr[:offset+13])
+	n, e := out.Write(hdr[:offset+1])
 	return int64(n), e
 }
 
 // codeQuery encodes query part of the header into the given buffer returning the number of bytes used.
 func (h *Header) codeQuery(hdr []byte) int {
-	// namespace (3 bits) + current version = #1
-	hdr[0] = (h.namespace & 0x7) | 1<<3
+	// namespace (3 bits) + version; #2 carries the upstream latency, #1 otherwise
+	var version byte = 1
+	if h.latencyMicros > 0 {
+		version = 2
+	}
+	hdr[0] = (h.namespace & 0x7) | version<<3
 
 	// add query size; 12 bits (4kB) for short, or 20 bits (~1MB) for long signaled by HiQ flag
 	if !h.isLongQuery {
@@ -336,7 +377,7 @@ func (h *Header) ReadFrom(r io.Reader) (int64, error) {
 
 // readFrom reads the header from Reader and pre-decodes internal flags.
 func (h *Header) readFrom(r io.Reader) ([]byte, error) {
-	hdr := make([]byte, 21)
+	hdr := make([]byte, headerSize)
 	var err error
 
 	// read the first byte to get the idea of how long the header is
@@ -355,6 +396,8 @@ func (h *Header) readFrom(r io.Reader) ([]byte, error) {
 	switch h.version {
 	case 1:
 		size = 18
+	case 2:
+		size = 22
 	default:
 		size = 10
 	}
@@ -415,8 +458,12 @@ func (h *Header) decodeFields(hdr []byte) {
 
 	h.blockID = uint64(binary.BigEndian.Uint32(hdr[offset : offset+4]))
 
+	h.latencyMicros = 0
 	switch h.version {
 	case 1:
 		h.blockTimestamp = binary.BigEndian.Uint64(hdr[offset+4 : offset+12])
+	case 2:
+		h.blockTimestamp = binary.BigEndian.Uint64(hdr[offset+4 : offset+12])
+		h.latencyMicros = binary.BigEndian.Uint32(hdr[offset+12 : offset+16])
 	}
 }
@@ -19,6 +19,7 @@ package rpc
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -312,6 +313,78 @@ func TestHeader_BlockTimestamp(t *testing.T) {
 	assert.Equal(t, uint64(1640995200), h.BlockTimestamp())
 }
 
+func TestHeader_SetLatency(t *testing.T) {
+	tests := []struct {
+		name     string
+		latency  time.Duration
+		expected time.Duration
+	}{
+		{name: "unknown", latency: 0, expected: 0},
+		{name: "truncated to microseconds", latency: 1500*time.Microsecond + 300, expected: 1500 * time.Microsecond},
+		{name: "negative", latency: -time.Second, expected: 0},
+		{name: "capped", latency: 2 * time.Hour, expected: maxLatencyMicros * time.Microsecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Header{}
+			h.SetLatency(tt.latency)
+			assert.Equal(t, tt.expected, h.Latency())
+		})
+	}
+}
+
+func TestHeader_LatencyRoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		latency     time.Duration
+		longQuery   bool
+		longResult  bool
+		wantVersion byte
+		wantSize    int64
+	}{
+		{name: "without latency", latency: 0, wantVersion: 1, wantSize: 18},
+		{name: "with latency", latency: 42 * time.Millisecond, wantVersion: 2, wantSize: 22},
+		{name: "with latency and long sizes", latency: time.Second, longQuery: true, longResult: true, wantVersion: 2, wantSize: headerSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &Header{}
+			assert.NoError(t, original.SetMethod("eth", "call"))
+			if tt.longQuery {
+				assert.NoError(t, original.SetQueryLength(maxShortQuery+1))
+			} else {
+				assert.NoError(t, original.SetQueryLength(100))
+			}
+			if tt.longResult {
+				original.SetResponseLength(maxShortResponse + 1)
+			} else {
+				original.SetResponseLength(200)
+			}
+			original.SetBlockID(12345)
+			original.SetBlockTimestamp(1640995200)
+			original.SetLatency(tt.latency)
+
+			var buf bytes.Buffer
+			n, err := original.WriteTo(&buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSize, n)
+
+			restored := &Header{}
+			n, err = restored.ReadFrom(&buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSize, n)
+			assert.Equal(t, tt.wantVersion, restored.version)
+			assert.Equal(t, tt.latency, restored.Latency())
+			assert.Equal(t, original.BlockID(), restored.BlockID())
+			assert.Equal(t, original.BlockTimestamp(), restored.BlockTimestamp())
+			assert.Equal(t, original.QueryLength(), restored.QueryLength())
+			assert.Equal(t, original.ResponseLength(), restored.ResponseLength())
+		})
+	}
+}
+
 func TestHeader_SetQueryLength(t *testing.T) {
 	tests := []struct {
 		name        string
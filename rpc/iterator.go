@@ -156,8 +156,9 @@ func (i *iterator) decode(hdr *Header, namespace, method string) (*RequestAndRes
 			MethodBase: method,
 			Method:     fmt.Sprintf("%s_%s", namespace, method),
 		},
-		ParamsRaw:   make([]byte, hdr.QueryLength()),
-		ResponseRaw: make([]byte, hdr.ResponseLength()),
+		ParamsRaw:       make([]byte, hdr.QueryLength()),
+		ResponseRaw:     make([]byte, hdr.ResponseLength()),
+		RecordedLatency: hdr.Latency(),
 	}
 
 	err := i.loadPayload(req.ParamsRaw)
@@ -21,6 +21,7 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -208,6 +209,24 @@ func TestIterator_decode(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("success with recorded latency", func(t *testing.T) {
+		h := &Header{}
+		paramBytes := []byte(`["0x1234567890abcdef", "latest"]`)
+		h.namespace = namespaceDictionary["eth"]
+		h.method = methodDictionary[h.namespace]["call"]
+		h.querySize = int32(len(paramBytes))
+		h.SetError(-32000)
+		h.SetLatency(1500 * time.Microsecond)
+
+		reader := io.NopCloser(bytes.NewReader(paramBytes))
+		iter := &iterator{
+			in: reader,
+		}
+		out, err := iter.decode(h, "eth", "call")
+		assert.NoError(t, err)
+		assert.Equal(t, 1500*time.Microsecond, out.RecordedLatency)
+	})
+
 	t.Run("success with error response", func(t *testing.T) {
 		// Create original header
 		h := &Header{}
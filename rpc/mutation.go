@@ -0,0 +1,215 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Mutation is a kind of small change applied to a recorded request
+// to test the robustness of the StateDB against unusual input.
+type Mutation int
+
+const (
+	// BlockNumberMutation moves the requested block by one.
+	BlockNumberMutation Mutation = iota
+	// AddressMutation truncates an address and pads it with zeros.
+	AddressMutation
+	// MaxGasMutation sets the gas of a call to the maximal uint64 value.
+	MaxGasMutation
+	// EmptyParamsMutation removes all parameters of the request.
+	EmptyParamsMutation
+)
+
+// AllMutations lists every mutation the Mutator may apply.
+var AllMutations = []Mutation{BlockNumberMutation, AddressMutation, MaxGasMutation, EmptyParamsMutation}
+
+func (m Mutation) String() string {
+	switch m {
+	case BlockNumberMutation:
+		return "block-number"
+	case AddressMutation:
+		return "address"
+	case MaxGasMutation:
+		return "max-gas"
+	case EmptyParamsMutation:
+		return "empty-params"
+	default:
+		return "unknown"
+	}
+}
+
+// Mutant is a mutated copy of a recorded request.
+type Mutant struct {
+	Mutation Mutation
+	Request  *RequestAndResults
+}
+
+// Mutator derives mutated variants of recorded requests. Mutations only depend on
+// the seed and the request itself, hence the same requests are always mutated
+// the same way regardless of the order in which they are processed.
+type Mutator struct {
+	seed     int64
+	variants int
+}
+
+// NewMutator creates a Mutator producing given number of variants per request.
+func NewMutator(seed int64, variants int) *Mutator {
+	return &Mutator{seed: seed, variants: variants}
+}
+
+// Mutate returns mutated copies of the request, each carrying exactly one mutation
+// chosen among those applicable to the request. The request itself is not modified.
+func (m *Mutator) Mutate(req *RequestAndResults) []Mutant {
+	var applicable []Mutation
+	for _, mutation := range AllMutations {
+		if mutation.appliesTo(req) {
+			applicable = append(applicable, mutation)
+		}
+	}
+	if len(applicable) == 0 {
+		return nil
+	}
+
+	rnd := rand.New(rand.NewSource(m.seed ^ requestHash(req)))
+	mutants := make([]Mutant, 0, m.variants)
+	for i := 0; i < m.variants; i++ {
+		mutation := applicable[rnd.Intn(len(applicable))]
+		mutant := cloneRequest(req)
+		mutation.apply(mutant, rnd)
+		mutants = append(mutants, Mutant{Mutation: mutation, Request: mutant})
+	}
+	return mutants
+}
+
+// appliesTo returns true if the request has the parameters changed by the mutation.
+func (m Mutation) appliesTo(req *RequestAndResults) bool {
+	params := req.Query.Params
+	switch m {
+	case BlockNumberMutation:
+		return true
+	case AddressMutation:
+		return len(addressParams(params)) > 0
+	case MaxGasMutation:
+		if len(params) == 0 {
+			return false
+		}
+		_, ok := params[0].(map[string]interface{})
+		return ok
+	case EmptyParamsMutation:
+		return len(params) > 0
+	default:
+		return false
+	}
+}
+
+// apply applies the mutation to the request, which must be applicable.
+func (m Mutation) apply(req *RequestAndResults, rnd *rand.Rand) {
+	params := req.Query.Params
+	switch m {
+	case BlockNumberMutation:
+		block := req.RequestedBlock + 1
+		if block > 1 && rnd.Intn(2) == 0 {
+			block = req.RequestedBlock - 1
+		}
+		req.RequestedBlock = block
+		if l := len(params); l >= 2 {
+			if str, ok := params[l-1].(string); ok && strings.HasPrefix(str, "0x") {
+				params[l-1] = hexutil.EncodeUint64(uint64(block))
+			}
+		}
+	case AddressMutation:
+		setters := addressParams(params)
+		keep := rnd.Intn(addressHexLength)
+		setters[rnd.Intn(len(setters))](func(address string) string {
+			return truncateAddress(address, keep)
+		})
+	case MaxGasMutation:
+		params[0].(map[string]interface{})["gas"] = hexutil.EncodeUint64(math.MaxUint64)
+	case EmptyParamsMutation:
+		req.Query.Params = []interface{}{}
+	}
+}
+
+// addressParams returns setters of all parameters holding an address,
+// these are the first parameter itself or the addresses of a call.
+func addressParams(params []interface{}) []func(func(string) string) {
+	if len(params) == 0 {
+		return nil
+	}
+	var setters []func(func(string) string)
+	switch p := params[0].(type) {
+	case string:
+		if isAddress(p) {
+			setters = append(setters, func(f func(string) string) { params[0] = f(p) })
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"from", "to"} {
+			if str, ok := p[key].(string); ok && isAddress(str) {
+				setters = append(setters, func(f func(string) string) { p[key] = f(str) })
+			}
+		}
+	}
+	return setters
+}
+
+const addressHexLength = 40
+
+func isAddress(str string) bool {
+	return len(str) == addressHexLength+2 && strings.HasPrefix(str, "0x")
+}
+
+// truncateAddress keeps the first given number of hex digits of the address and pads it with zeros.
+func truncateAddress(address string, keep int) string {
+	return address[:2+keep] + strings.Repeat("0", addressHexLength-keep)
+}
+
+// cloneRequest copies the request deep enough for its parameters to be mutated.
+func cloneRequest(req *RequestAndResults) *RequestAndResults {
+	clone := *req
+	query := *req.Query
+	query.Params = make([]interface{}, len(req.Query.Params))
+	for i, param := range req.Query.Params {
+		if m, ok := param.(map[string]interface{}); ok {
+			c := make(map[string]interface{}, len(m))
+			for k, v := range m {
+				c[k] = v
+			}
+			param = c
+		}
+		query.Params[i] = param
+	}
+	clone.Query = &query
+	return &clone
+}
+
+// requestHash identifies the request for seeding its mutations.
+func requestHash(req *RequestAndResults) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(req.Query.Method))
+	params, _ := json.Marshal(req.Query.Params)
+	h.Write(params)
+	fmt.Fprintf(h, "%d", req.RecordedBlock)
+	return int64(h.Sum64())
+}
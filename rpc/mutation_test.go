@@ -0,0 +1,177 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mutatedAddress = "0x1234567890123456789012345678901234567891"
+
+func makeMutationRequests() map[string]*RequestAndResults {
+	return map[string]*RequestAndResults{
+		"getBalance": {
+			Query: &Body{
+				Method:     "eth_getBalance",
+				MethodBase: "getBalance",
+				Params:     []interface{}{mutatedAddress, "0x10"},
+			},
+			RecordedBlock:  16,
+			RequestedBlock: 16,
+		},
+		"call": {
+			Query: &Body{
+				Method:     "eth_call",
+				MethodBase: "call",
+				Params: []interface{}{
+					map[string]interface{}{"to": mutatedAddress, "gas": "0x5208"},
+					"latest",
+				},
+			},
+			RecordedBlock:  16,
+			RequestedBlock: 16,
+		},
+	}
+}
+
+func TestMutation_BlockNumber(t *testing.T) {
+	for name, req := range makeMutationRequests() {
+		t.Run(name, func(t *testing.T) {
+			require.True(t, BlockNumberMutation.appliesTo(req))
+			mutant := cloneRequest(req)
+			BlockNumberMutation.apply(mutant, newTestRand())
+
+			assert.Contains(t, []int{15, 17}, mutant.RequestedBlock)
+			last := mutant.Query.Params[len(mutant.Query.Params)-1]
+			if req.Query.Params[1] == "latest" {
+				// block tags are kept
+				assert.Equal(t, "latest", last)
+			} else {
+				assert.Equal(t, hexutil.EncodeUint64(uint64(mutant.RequestedBlock)), last)
+			}
+		})
+	}
+}
+
+func TestMutation_BlockNumber_DoesNotGoBelowZero(t *testing.T) {
+	req := &RequestAndResults{Query: &Body{}}
+	for i := 0; i < 10; i++ {
+		mutant := cloneRequest(req)
+		BlockNumberMutation.apply(mutant, newTestRand())
+		assert.Equal(t, 1, mutant.RequestedBlock)
+	}
+}
+
+func TestMutation_Address(t *testing.T) {
+	requests := makeMutationRequests()
+	tests := map[string]func(*RequestAndResults) string{
+		"getBalance": func(r *RequestAndResults) string { return r.Query.Params[0].(string) },
+		"call":       func(r *RequestAndResults) string { return r.Query.Params[0].(map[string]interface{})["to"].(string) },
+	}
+	for name, get := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := requests[name]
+			require.True(t, AddressMutation.appliesTo(req))
+			mutant := cloneRequest(req)
+			AddressMutation.apply(mutant, newTestRand())
+
+			got := get(mutant)
+			require.Len(t, got, len(mutatedAddress))
+			assert.NotEqual(t, mutatedAddress, got)
+			assert.Regexp(t, "^0x[0-9]*0+$", got)
+			assert.Equal(t, mutatedAddress, get(req), "original request must not be changed")
+		})
+	}
+}
+
+func TestMutation_Address_NotApplicableWithoutAddress(t *testing.T) {
+	req := &RequestAndResults{Query: &Body{Params: []interface{}{map[string]interface{}{"fromBlock": "0x1"}}}}
+	assert.False(t, AddressMutation.appliesTo(req))
+}
+
+func TestMutation_TruncateAddress(t *testing.T) {
+	assert.Equal(t, "0x0000000000000000000000000000000000000000", truncateAddress(mutatedAddress, 0))
+	assert.Equal(t, "0x1234000000000000000000000000000000000000", truncateAddress(mutatedAddress, 4))
+}
+
+func TestMutation_MaxGas(t *testing.T) {
+	requests := makeMutationRequests()
+	assert.False(t, MaxGasMutation.appliesTo(requests["getBalance"]))
+
+	req := requests["call"]
+	require.True(t, MaxGasMutation.appliesTo(req))
+	mutant := cloneRequest(req)
+	MaxGasMutation.apply(mutant, newTestRand())
+
+	assert.Equal(t, hexutil.EncodeUint64(math.MaxUint64), mutant.Query.Params[0].(map[string]interface{})["gas"])
+	assert.Equal(t, "0x5208", req.Query.Params[0].(map[string]interface{})["gas"], "original request must not be changed")
+}
+
+func TestMutation_EmptyParams(t *testing.T) {
+	for name, req := range makeMutationRequests() {
+		t.Run(name, func(t *testing.T) {
+			require.True(t, EmptyParamsMutation.appliesTo(req))
+			mutant := cloneRequest(req)
+			EmptyParamsMutation.apply(mutant, newTestRand())
+
+			assert.Empty(t, mutant.Query.Params)
+			assert.Len(t, req.Query.Params, 2, "original request must not be changed")
+		})
+	}
+	assert.False(t, EmptyParamsMutation.appliesTo(&RequestAndResults{Query: &Body{}}))
+}
+
+func TestMutation_String(t *testing.T) {
+	for _, mutation := range AllMutations {
+		assert.NotEqual(t, "unknown", mutation.String())
+	}
+	assert.Equal(t, "unknown", Mutation(-1).String())
+}
+
+func TestMutator_MutateProducesConfiguredNumberOfVariants(t *testing.T) {
+	req := makeMutationRequests()["call"]
+	mutants := NewMutator(42, 7).Mutate(req)
+	require.Len(t, mutants, 7)
+	for _, mutant := range mutants {
+		assert.NotSame(t, req, mutant.Request)
+		assert.True(t, mutant.Mutation.appliesTo(req))
+	}
+}
+
+func TestMutator_MutateIsDeterministic(t *testing.T) {
+	req := makeMutationRequests()["call"]
+	first := NewMutator(42, 16).Mutate(req)
+	second := NewMutator(42, 16).Mutate(req)
+	assert.Equal(t, first, second)
+}
+
+func TestMutator_MutateDependsOnSeed(t *testing.T) {
+	req := makeMutationRequests()["call"]
+	first := NewMutator(1, 16).Mutate(req)
+	second := NewMutator(2, 16).Mutate(req)
+	assert.NotEqual(t, first, second)
+}
+
+func newTestRand() *rand.Rand {
+	return rand.New(rand.NewSource(1))
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/0xsoniclabs/aida/txcontext"
+	substatecontext "github.com/0xsoniclabs/aida/txcontext/substate"
+	"github.com/0xsoniclabs/substate/substate"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrUnverifiable marks a request whose result cannot be reconstructed from the replayed data.
+var ErrUnverifiable = errors.New("request is unverifiable")
+
+// SubstateReader provides the recorded transactions of an AidaDb.
+type SubstateReader interface {
+	HasSubstate(block uint64, tx int) (bool, error)
+	GetSubstate(block uint64, tx int) (*substate.Substate, error)
+}
+
+// TransactionReceipt is a receipt as returned by the eth_getTransactionReceipt method. Only fields
+// which can be reconstructed from the substate of the transaction are included.
+type TransactionReceipt struct {
+	TxHash           common.Hash     `json:"transactionHash"`
+	BlockNumber      hexutil.Uint64  `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64  `json:"transactionIndex"`
+	Status           hexutil.Uint64  `json:"status"`
+	GasUsed          hexutil.Uint64  `json:"gasUsed"`
+	ContractAddress  *common.Address `json:"contractAddress"`
+	Bloom            types.Bloom     `json:"logsBloom"`
+	Logs             []*types.Log    `json:"logs"`
+}
+
+// ExecuteGetTransactionReceipt reconstructs the receipt of the transaction requested by given
+// eth_getTransactionReceipt request from its substate and sends it JSON encoded to comparator.
+// Substates do not record transaction hashes, hence the transaction is located by the block and
+// the index of the recorded receipt. Transactions outside the replayed block range [first, last],
+// transactions missing in the AidaDb and requests without a recorded receipt are unverifiable.
+func ExecuteGetTransactionReceipt(rec *RequestAndResults, substates SubstateReader, first, last uint64) txcontext.Result {
+	if len(rec.Query.Params) < 1 {
+		return &result{err: fmt.Errorf("%v requires 1 parameter, got 0", rec.Query.Method)}
+	}
+	hash, ok := rec.Query.Params[0].(string)
+	if !ok {
+		return &result{err: fmt.Errorf("invalid transaction hash %v", rec.Query.Params[0])}
+	}
+
+	if substates == nil || rec.Response == nil {
+		return &result{err: ErrUnverifiable}
+	}
+	var recorded *TransactionReceipt
+	if err := json.Unmarshal(rec.Response.Result, &recorded); err != nil || recorded == nil {
+		// unknown transactions are answered by null
+		return &result{err: ErrUnverifiable}
+	}
+
+	block, tx := uint64(recorded.BlockNumber), int(recorded.TransactionIndex)
+	if block < first || block > last {
+		return &result{err: ErrUnverifiable}
+	}
+	found, err := substates.HasSubstate(block, tx)
+	if err != nil {
+		return &result{err: err}
+	}
+	if !found {
+		return &result{err: ErrUnverifiable}
+	}
+	ss, err := substates.GetSubstate(block, tx)
+	if err != nil {
+		return &result{err: err}
+	}
+
+	res, err := json.Marshal(makeTransactionReceipt(common.HexToHash(hash), ss))
+	return &result{
+		result:  res,
+		err:     err,
+		gasUsed: ss.Result.GasUsed,
+	}
+}
+
+// makeTransactionReceipt builds the receipt of the transaction recorded by given substate. Log indices
+// are counted from zero within the transaction and the bloom is recomputed from the logs.
+func makeTransactionReceipt(hash common.Hash, ss *substate.Substate) *TransactionReceipt {
+	receipt := substatecontext.NewTxContext(ss).GetResult().GetReceipt()
+
+	r := &TransactionReceipt{
+		TxHash:           hash,
+		BlockNumber:      hexutil.Uint64(ss.Block),
+		TransactionIndex: hexutil.Uint64(ss.Transaction),
+		Status:           hexutil.Uint64(receipt.GetStatus()),
+		GasUsed:          hexutil.Uint64(receipt.GetGasUsed()),
+		Logs:             make([]*types.Log, 0, len(receipt.GetLogs())),
+	}
+	if ss.Message != nil && ss.Message.To == nil {
+		address := receipt.GetContractAddress()
+		r.ContractAddress = &address
+	}
+
+	for i, log := range receipt.GetLogs() {
+		l := *log
+		// hashes recorded with the logs reveal a substate of another transaction
+		if l.TxHash == (common.Hash{}) {
+			l.TxHash = hash
+		}
+		l.BlockNumber = ss.Block
+		l.TxIndex = uint(ss.Transaction)
+		l.Index = uint(i)
+		r.Logs = append(r.Logs, &l)
+
+		r.Bloom.Add(l.Address.Bytes())
+		for _, topic := range l.Topics {
+			r.Bloom.Add(topic.Bytes())
+		}
+	}
+	return r
+}
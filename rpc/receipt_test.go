@@ -0,0 +1,178 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/0xsoniclabs/substate/substate"
+	substatetypes "github.com/0xsoniclabs/substate/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testReceiptHash is the hash of the transaction recorded by makeReceiptTestSubstate.
+var testReceiptHash = common.HexToHash("0xabcd")
+
+func TestExecuteGetTransactionReceipt_ReconstructsReceiptFromSubstate(t *testing.T) {
+	substates := testSubstates{makeReceiptTestSubstate(20, 3)}
+	rec := makeReceiptRequest(`{"transactionHash":"0x000000000000000000000000000000000000000000000000000000000000abcd","blockNumber":"0x14","transactionIndex":"0x3"}`)
+
+	res := ExecuteGetTransactionReceipt(rec, substates, 10, 30)
+	raw, err := res.GetRawResult()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(21500), res.GetGasUsed())
+
+	var receipt TransactionReceipt
+	require.NoError(t, json.Unmarshal(raw, &receipt))
+	assert.Equal(t, testReceiptHash, receipt.TxHash)
+	assert.Equal(t, hexutil.Uint64(20), receipt.BlockNumber)
+	assert.Equal(t, hexutil.Uint64(3), receipt.TransactionIndex)
+	assert.Equal(t, hexutil.Uint64(1), receipt.Status)
+	assert.Equal(t, hexutil.Uint64(21500), receipt.GasUsed)
+	assert.Nil(t, receipt.ContractAddress, "no contract was created")
+
+	require.Len(t, receipt.Logs, 2)
+	for i, log := range receipt.Logs {
+		assert.Equal(t, uint(i), log.Index)
+		assert.Equal(t, uint64(20), log.BlockNumber)
+		assert.Equal(t, uint(3), log.TxIndex)
+		assert.Equal(t, testReceiptHash, log.TxHash)
+		assert.True(t, receipt.Bloom.Test(log.Address.Bytes()))
+		for _, topic := range log.Topics {
+			assert.True(t, receipt.Bloom.Test(topic.Bytes()))
+		}
+	}
+	assert.False(t, receipt.Bloom.Test(common.Address{0xff}.Bytes()))
+}
+
+func TestExecuteGetTransactionReceipt_ContractCreationHasContractAddress(t *testing.T) {
+	ss := makeReceiptTestSubstate(20, 3)
+	ss.Message.To = nil
+	ss.Result.ContractAddress = substatetypes.Address{0xcc}
+	rec := makeReceiptRequest(`{"blockNumber":"0x14","transactionIndex":"0x3"}`)
+
+	raw, err := ExecuteGetTransactionReceipt(rec, testSubstates{ss}, 10, 30).GetRawResult()
+	require.NoError(t, err)
+
+	var receipt TransactionReceipt
+	require.NoError(t, json.Unmarshal(raw, &receipt))
+	if assert.NotNil(t, receipt.ContractAddress) {
+		assert.Equal(t, common.Address{0xcc}, *receipt.ContractAddress)
+	}
+}
+
+func TestExecuteGetTransactionReceipt_RequestsOutsideReplayedDataAreUnverifiable(t *testing.T) {
+	substates := testSubstates{makeReceiptTestSubstate(20, 3)}
+	recorded := `{"blockNumber":"0x14","transactionIndex":"0x3"}`
+
+	tests := map[string]struct {
+		rec       *RequestAndResults
+		substates SubstateReader
+		first     uint64
+		last      uint64
+	}{
+		"no aida-db":              {rec: makeReceiptRequest(recorded), first: 10, last: 30},
+		"unknown transaction":     {rec: makeReceiptRequest(`null`), substates: substates, first: 10, last: 30},
+		"before replayed range":   {rec: makeReceiptRequest(recorded), substates: substates, first: 21, last: 30},
+		"after replayed range":    {rec: makeReceiptRequest(recorded), substates: substates, first: 10, last: 19},
+		"missing in aida-db":      {rec: makeReceiptRequest(`{"blockNumber":"0x14","transactionIndex":"0x4"}`), substates: substates, first: 10, last: 30},
+		"recorded error response": {rec: &RequestAndResults{Query: makeReceiptRequest(recorded).Query, Error: &ErrorResponse{}}, substates: substates, first: 10, last: 30},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ExecuteGetTransactionReceipt(test.rec, test.substates, test.first, test.last).GetRawResult()
+			assert.ErrorIs(t, err, ErrUnverifiable)
+		})
+	}
+}
+
+func TestExecuteGetTransactionReceipt_AidaDbErrorIsReported(t *testing.T) {
+	injected := errors.New("injected")
+	rec := makeReceiptRequest(`{"blockNumber":"0x14","transactionIndex":"0x3"}`)
+
+	_, err := ExecuteGetTransactionReceipt(rec, failingSubstates{injected}, 10, 30).GetRawResult()
+	assert.ErrorIs(t, err, injected)
+	assert.NotErrorIs(t, err, ErrUnverifiable)
+}
+
+func TestExecuteGetTransactionReceipt_MissingHashIsRejected(t *testing.T) {
+	rec := makeReceiptRequest(`{"blockNumber":"0x14","transactionIndex":"0x3"}`)
+	rec.Query.Params = nil
+
+	_, err := ExecuteGetTransactionReceipt(rec, testSubstates{makeReceiptTestSubstate(20, 3)}, 10, 30).GetRawResult()
+	assert.ErrorContains(t, err, "eth_getTransactionReceipt requires 1 parameter")
+}
+
+// makeReceiptTestSubstate creates a substate of a transfer emitting two logs.
+func makeReceiptTestSubstate(block uint64, tx int) *substate.Substate {
+	hash := substatetypes.Hash(testReceiptHash)
+	return &substate.Substate{
+		Block:       block,
+		Transaction: tx,
+		Message:     &substate.Message{To: &substatetypes.Address{0x2}},
+		Result: substate.NewResult(1, substatetypes.Bloom{}, []*substatetypes.Log{
+			{Address: substatetypes.Address{0xa}, Topics: []substatetypes.Hash{{0x1}}, Data: []byte{0x1}, TxHash: hash},
+			{Address: substatetypes.Address{0xb}, Topics: []substatetypes.Hash{}, Data: []byte{}, TxHash: hash},
+		}, substatetypes.Address{}, 21500),
+	}
+}
+
+func makeReceiptRequest(recorded string) *RequestAndResults {
+	return &RequestAndResults{
+		Query: &Body{
+			Method:     "eth_getTransactionReceipt",
+			MethodBase: "getTransactionReceipt",
+			Params:     []interface{}{testReceiptHash.Hex()},
+		},
+		Response: &Response{Result: json.RawMessage(recorded)},
+	}
+}
+
+// testSubstates is a SubstateReader serving given substates.
+type testSubstates []*substate.Substate
+
+func (s testSubstates) HasSubstate(block uint64, tx int) (bool, error) {
+	_, err := s.GetSubstate(block, tx)
+	return err == nil, nil
+}
+
+func (s testSubstates) GetSubstate(block uint64, tx int) (*substate.Substate, error) {
+	for _, ss := range s {
+		if ss.Block == block && ss.Transaction == tx {
+			return ss, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+// failingSubstates is a SubstateReader failing with given error.
+type failingSubstates struct {
+	err error
+}
+
+func (s failingSubstates) HasSubstate(uint64, int) (bool, error) {
+	return false, s.err
+}
+
+func (s failingSubstates) GetSubstate(uint64, int) (*substate.Substate, error) {
+	return nil, s.err
+}
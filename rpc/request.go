@@ -34,6 +34,7 @@ type RequestAndResults struct {
 	IsRecovered                   bool
 	RecordedBlock, RequestedBlock int
 	Timestamp                     uint64
+	RecordedLatency               time.Duration // time the upstream node needed to answer; zero if not recorded
 }
 
 // Body represents a decoded payload of a balancer.
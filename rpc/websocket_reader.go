@@ -0,0 +1,154 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	webSocketMinBackoff  = 100 * time.Millisecond // delay before the first reconnection attempt
+	webSocketMaxBackoff  = 10 * time.Second       // upper bound of the delay between reconnection attempts
+	webSocketMaxAttempts = 8                      // number of reconnection attempts before giving up
+)
+
+// IsWebSocketUrl returns true if given recording path is a ws:// or wss:// URL.
+func IsWebSocketUrl(path string) bool {
+	return strings.HasPrefix(path, "ws://") || strings.HasPrefix(path, "wss://")
+}
+
+// NewWebSocketReader creates new instance of a reader of a recording streamed over a websocket
+// and starts reading. Each binary message of the stream carries complete recorded frames in the
+// same format as a recording file. Lost connections are re-established with exponential backoff,
+// the stream ends once the server closes the connection normally.
+func NewWebSocketReader(ctx context.Context, url string) (Iterator, error) {
+	stream, err := newWebSocketStream(ctx, url, webSocketMinBackoff, webSocketMaxBackoff, webSocketMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	return newIterator(ctx, stream, 10), nil
+}
+
+// webSocketStream exposes binary messages of a websocket as a continuous byte stream.
+// Messages are read only when the previous one was consumed, hence a slow consumer
+// applies backpressure on the sender.
+type webSocketStream struct {
+	ctx         context.Context
+	url         string
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	maxAttempts int
+
+	mutex   sync.Mutex
+	conn    *websocket.Conn
+	closed  bool
+	pending []byte // unread remainder of the current message
+}
+
+func newWebSocketStream(ctx context.Context, url string, minBackoff, maxBackoff time.Duration, maxAttempts int) (*webSocketStream, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to %v; %w", url, err)
+	}
+	return &webSocketStream{
+		ctx:         ctx,
+		url:         url,
+		minBackoff:  minBackoff,
+		maxBackoff:  maxBackoff,
+		maxAttempts: maxAttempts,
+		conn:        conn,
+	}, nil
+}
+
+// Read reads the next bytes of the stream. A message is never split across connections,
+// a message interrupted by a lost connection is dropped as a whole.
+func (s *webSocketStream) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		conn, closed := s.current()
+		if closed {
+			return 0, io.EOF
+		}
+		typ, msg, err := conn.ReadMessage()
+		if err == nil {
+			if typ == websocket.BinaryMessage {
+				s.pending = msg
+			}
+			continue
+		}
+		// a connection closed by the reader or normally by the server ends the stream
+		if _, closed = s.current(); closed || websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+			return 0, io.EOF
+		}
+		if err = s.reconnect(err); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Close closes the connection, a blocked Read returns io.EOF.
+func (s *webSocketStream) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.conn.Close()
+}
+
+func (s *webSocketStream) current() (*websocket.Conn, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.conn, s.closed
+}
+
+// reconnect re-establishes a lost connection with exponential backoff.
+func (s *webSocketStream) reconnect(cause error) error {
+	delay := s.minBackoff
+	for attempt := 1; attempt <= s.maxAttempts; attempt++ {
+		select {
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		case <-time.After(delay):
+		}
+
+		conn, _, err := websocket.DefaultDialer.DialContext(s.ctx, s.url, nil)
+		if err == nil {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+			if s.closed {
+				_ = conn.Close()
+				return io.EOF
+			}
+			_ = s.conn.Close()
+			s.conn = conn
+			return nil
+		}
+		delay = min(2*delay, s.maxBackoff)
+	}
+	return fmt.Errorf("cannot reconnect to %v after %v attempts; %w", s.url, s.maxAttempts, cause)
+}
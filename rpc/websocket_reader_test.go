@@ -0,0 +1,187 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordedFrame returns a single recorded eth_call request in the recording file format.
+// The given id is recorded as the only parameter of the request.
+func recordedFrame(t *testing.T, id int) []byte {
+	paramBytes := []byte(fmt.Sprintf("[%d]", id))
+	h := &Header{}
+	h.version = 1
+	h.namespace = namespaceDictionary["eth"]
+	h.method = methodDictionary[h.namespace]["call"]
+	h.querySize = int32(len(paramBytes))
+	h.blockID = 12345
+	h.blockTimestamp = 1640995200
+
+	var buf bytes.Buffer
+	_, err := h.WriteTo(&buf)
+	require.NoError(t, err)
+	return append(buf.Bytes(), paramBytes...)
+}
+
+// newRecordingServer starts a websocket server calling serve for every accepted connection.
+// The index of the connection, starting at 0, is passed to serve.
+func newRecordingServer(t *testing.T, serve func(conn *websocket.Conn, index int)) (*httptest.Server, string) {
+	var connections atomic.Int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		serve(conn, int(connections.Add(1)-1))
+	}))
+	t.Cleanup(server.Close)
+	return server, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func sendFrames(t *testing.T, conn *websocket.Conn, frames ...[]byte) {
+	for _, frame := range frames {
+		assert.NoError(t, conn.WriteMessage(websocket.BinaryMessage, frame))
+	}
+}
+
+func closeNormally(conn *websocket.Conn) {
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	// wait for the client to acknowledge the close
+	_, _, _ = conn.ReadMessage()
+}
+
+// collectIds returns the ids of all requests yielded by the iterator.
+func collectIds(t *testing.T, iter Iterator) []string {
+	var ids []string
+	for iter.Next() {
+		require.NotNil(t, iter.Value())
+		ids = append(ids, string(iter.Value().ParamsRaw))
+	}
+	return ids
+}
+
+func TestIsWebSocketUrl(t *testing.T) {
+	tests := map[string]bool{
+		"ws://localhost:8080/recording": true,
+		"wss://example.com/recording":   true,
+		"/path/to/recording":            false,
+		"http://localhost:8080":         false,
+		"":                              false,
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, IsWebSocketUrl(path), path)
+	}
+}
+
+func TestWebSocketReader_ReadsStreamedRecording(t *testing.T) {
+	_, url := newRecordingServer(t, func(conn *websocket.Conn, _ int) {
+		// two frames in one message and one frame in another one
+		sendFrames(t, conn, append(recordedFrame(t, 1), recordedFrame(t, 2)...), recordedFrame(t, 3))
+		closeNormally(conn)
+	})
+
+	iter, err := NewWebSocketReader(context.Background(), url)
+	require.NoError(t, err)
+	defer iter.Close()
+
+	assert.Equal(t, []string{"[1]", "[2]", "[3]"}, collectIds(t, iter))
+	assert.NoError(t, iter.Error())
+}
+
+func TestWebSocketReader_FailsIfServerIsUnreachable(t *testing.T) {
+	server, url := newRecordingServer(t, func(*websocket.Conn, int) {})
+	server.Close()
+
+	_, err := NewWebSocketReader(context.Background(), url)
+	require.ErrorContains(t, err, "cannot connect to")
+}
+
+func TestWebSocketStream_ReconnectsAfterLostConnection(t *testing.T) {
+	_, url := newRecordingServer(t, func(conn *websocket.Conn, index int) {
+		if index == 0 {
+			// connection is dropped without a close message
+			sendFrames(t, conn, recordedFrame(t, 1))
+			return
+		}
+		sendFrames(t, conn, recordedFrame(t, 2))
+		closeNormally(conn)
+	})
+
+	stream, err := newWebSocketStream(context.Background(), url, time.Millisecond, 10*time.Millisecond, 3)
+	require.NoError(t, err)
+	iter := newIterator(context.Background(), stream, 10)
+	defer iter.Close()
+
+	assert.Equal(t, []string{"[1]", "[2]"}, collectIds(t, iter))
+	assert.NoError(t, iter.Error())
+}
+
+func TestWebSocketStream_GivesUpReconnecting(t *testing.T) {
+	server, url := newRecordingServer(t, func(conn *websocket.Conn, _ int) {})
+
+	stream, err := newWebSocketStream(context.Background(), url, time.Millisecond, 10*time.Millisecond, 3)
+	require.NoError(t, err)
+	server.Close()
+
+	iter := newIterator(context.Background(), stream, 10)
+	defer iter.Close()
+
+	assert.False(t, iter.Next())
+	require.ErrorContains(t, iter.Error(), "cannot reconnect to")
+}
+
+func TestWebSocketStream_CloseEndsBlockedRead(t *testing.T) {
+	done := make(chan struct{})
+	_, url := newRecordingServer(t, func(conn *websocket.Conn, _ int) {
+		<-done
+	})
+	defer close(done)
+
+	stream, err := newWebSocketStream(context.Background(), url, time.Millisecond, 10*time.Millisecond, 3)
+	require.NoError(t, err)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := stream.Read(make([]byte, 1))
+		result <- err
+	}()
+	require.NoError(t, stream.Close())
+
+	select {
+	case err := <-result:
+		assert.Equal(t, io.EOF, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("read was not unblocked by close")
+	}
+}
@@ -19,10 +19,12 @@ package state
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/0xsoniclabs/aida/txcontext"
 	"github.com/0xsoniclabs/carmen/go/carmen"
+	"github.com/0xsoniclabs/carmen/go/database/vt/utils"
 	_ "github.com/0xsoniclabs/carmen/go/experimental"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -102,15 +104,22 @@ func MakeCarmenStateDB(
 
 	return &carmenHeadState{
 		carmenStateDB: carmenStateDB{
-			db: db,
+			db:         db,
+			pointCache: utils.NewPointCache(pointCacheSize),
 		},
 	}, nil
 }
 
+// pointCacheSize is the number of tree key points cached per database.
+const pointCacheSize = 4096
+
 type carmenStateDB struct {
 	db           carmen.Database
 	txCtx        carmen.TransactionContext
-	accessEvents *state.AccessEvents
+	pointCache   *utils.PointCache   // shared by the head state and all archive states of the database
+	accessEvents *state.AccessEvents // accesses of the current transaction
+	touched      touchedAccounts
+	postAlloc    txcontext.WorldState // post-alloc of the last ended transaction
 }
 
 type carmenHeadState struct {
@@ -125,10 +134,14 @@ type carmenHistoricState struct {
 }
 
 func (s *carmenStateDB) CreateAccount(addr common.Address) {
+	s.touched.touch(addr)
+	s.recordAccountAccess(addr)
 	s.txCtx.CreateAccount(carmen.Address(addr))
 }
 
 func (s *carmenStateDB) CreateContract(addr common.Address) {
+	s.touched.touch(addr)
+	s.recordAccountAccess(addr)
 	s.txCtx.CreateContract(carmen.Address(addr))
 }
 
@@ -145,6 +158,8 @@ func (s *carmenStateDB) Empty(addr common.Address) bool {
 }
 
 func (s *carmenStateDB) SelfDestruct(addr common.Address) {
+	s.touched.touch(addr)
+	s.recordBasicDataAccess(addr, true)
 	s.txCtx.SelfDestruct(carmen.Address(addr))
 }
 
@@ -153,31 +168,40 @@ func (s *carmenStateDB) HasSelfDestructed(addr common.Address) bool {
 }
 
 func (s *carmenStateDB) GetBalance(addr common.Address) *uint256.Int {
+	s.recordBasicDataAccess(addr, false)
 	value := s.txCtx.GetBalance(carmen.Address(addr)).Uint256()
 	return &value
 }
 
 func (s *carmenStateDB) AddBalance(addr common.Address, value *uint256.Int, _ tracing.BalanceChangeReason) uint256.Int {
+	s.touched.touch(addr)
+	s.recordBasicDataAccess(addr, true)
 	before := s.txCtx.GetBalance(carmen.Address(addr)).Uint256()
 	s.txCtx.AddBalance(carmen.Address(addr), carmen.NewAmountFromUint256(value))
 	return before
 }
 
 func (s *carmenStateDB) SubBalance(addr common.Address, value *uint256.Int, _ tracing.BalanceChangeReason) uint256.Int {
+	s.touched.touch(addr)
+	s.recordBasicDataAccess(addr, true)
 	before := s.txCtx.GetBalance(carmen.Address(addr)).Uint256()
 	s.txCtx.SubBalance(carmen.Address(addr), carmen.NewAmountFromUint256(value))
 	return before
 }
 
 func (s *carmenStateDB) GetNonce(addr common.Address) uint64 {
+	s.recordBasicDataAccess(addr, false)
 	return s.txCtx.GetNonce(carmen.Address(addr))
 }
 
 func (s *carmenStateDB) SetNonce(addr common.Address, value uint64, reason tracing.NonceChangeReason) {
+	s.touched.touch(addr)
+	s.recordBasicDataAccess(addr, true)
 	s.txCtx.SetNonce(carmen.Address(addr), value)
 }
 
 func (s *carmenStateDB) GetCommittedState(addr common.Address, key common.Hash) common.Hash {
+	s.recordSlotAccess(addr, key, false)
 	return common.Hash(s.txCtx.GetCommittedState(carmen.Address(addr), carmen.Key(key)))
 }
 
@@ -188,10 +212,13 @@ func (s *carmenStateDB) GetStateAndCommittedState(addr common.Address, key commo
 }
 
 func (s *carmenStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	s.recordSlotAccess(addr, key, false)
 	return common.Hash(s.txCtx.GetState(carmen.Address(addr), carmen.Key(key)))
 }
 
 func (s *carmenStateDB) SetState(addr common.Address, key common.Hash, value common.Hash) common.Hash {
+	s.touched.touchSlot(addr, key)
+	s.recordSlotAccess(addr, key, true)
 	before := s.txCtx.GetState(carmen.Address(addr), carmen.Key(key))
 	s.txCtx.SetState(carmen.Address(addr), carmen.Key(key), carmen.Value(value))
 	return common.Hash(before)
@@ -220,32 +247,52 @@ func (s *carmenStateDB) GetTransientState(address common.Address, key common.Has
 }
 
 func (s *carmenStateDB) GetCode(addr common.Address) []byte {
+	s.recordBasicDataAccess(addr, false)
 	return s.txCtx.GetCode(carmen.Address(addr))
 }
 
 func (s *carmenStateDB) GetCodeSize(addr common.Address) int {
+	s.recordBasicDataAccess(addr, false)
 	return s.txCtx.GetCodeSize(carmen.Address(addr))
 }
 
 func (s *carmenStateDB) GetCodeHash(addr common.Address) common.Hash {
+	s.recordCodeHashAccess(addr, false)
 	return common.Hash(s.txCtx.GetCodeHash(carmen.Address(addr)))
 }
 
 func (s *carmenStateDB) SetCode(addr common.Address, code []byte, _ tracing.CodeChangeReason) []byte {
-	before := bytes.Clone(s.GetCode(addr))
+	s.touched.touch(addr)
+	s.recordBasicDataAccess(addr, true)
+	s.recordCodeHashAccess(addr, true)
+	before := bytes.Clone(s.txCtx.GetCode(carmen.Address(addr)))
 	s.txCtx.SetCode(carmen.Address(addr), code)
 	return before
 }
 
 func (s *carmenStateDB) Snapshot() int {
-	return s.txCtx.Snapshot()
+	id := s.txCtx.Snapshot()
+	s.touched.snapshot(id)
+	return id
 }
 
 func (s *carmenStateDB) RevertToSnapshot(id int) {
 	s.txCtx.RevertToSnapshot(id)
+	s.touched.revert(id)
+}
+
+// beginTransaction resets the post-alloc tracking and the access events of the previous transaction.
+func (s *carmenStateDB) beginTransaction() {
+	s.touched.reset()
+	s.postAlloc = nil
+	s.accessEvents = state.NewAccessEvents()
 }
 
 func (s *carmenStateDB) EndTransaction() error {
+	// the post-alloc needs to be collected before the changes are committed,
+	// since the transaction context cannot be queried afterward
+	s.postAlloc = s.collectPostAlloc()
+	s.touched.reset()
 	return s.txCtx.Commit()
 }
 
@@ -371,9 +418,39 @@ func (s *carmenStateDB) PrepareSubstate(txcontext.WorldState, uint64) {
 	// ignored
 }
 
+// GetSubstatePostAlloc returns the state of all accounts touched by the current
+// transaction. Once the transaction has ended, the state collected at its end is
+// returned until the next transaction begins.
 func (s *carmenStateDB) GetSubstatePostAlloc() txcontext.WorldState {
-	// ignored
-	return nil
+	if s.postAlloc != nil {
+		return s.postAlloc
+	}
+	return s.collectPostAlloc()
+}
+
+// collectPostAlloc reads the current state of all touched accounts from the
+// transaction context. Accounts which have been self-destructed or do not exist
+// are not part of the post-alloc.
+func (s *carmenStateDB) collectPostAlloc() txcontext.WorldState {
+	res := make(map[common.Address]txcontext.Account)
+	for addr, keys := range s.touched.accounts() {
+		carmenAddr := carmen.Address(addr)
+		if s.txCtx.HasSelfDestructed(carmenAddr) || !s.txCtx.Exist(carmenAddr) {
+			continue
+		}
+		storage := make(map[common.Hash]common.Hash, len(keys))
+		for _, key := range keys {
+			storage[key] = common.Hash(s.txCtx.GetState(carmenAddr, carmen.Key(key)))
+		}
+		balance := s.txCtx.GetBalance(carmenAddr).Uint256()
+		res[addr] = txcontext.NewAccount(
+			s.txCtx.GetCode(carmenAddr),
+			storage,
+			balance.ToBig(),
+			s.txCtx.GetNonce(carmenAddr),
+		)
+	}
+	return txcontext.NewWorldState(res)
 }
 
 func (s *carmenStateDB) AddPreimage(common.Hash, []byte) {
@@ -381,10 +458,17 @@ func (s *carmenStateDB) AddPreimage(common.Hash, []byte) {
 	panic("AddPreimage not implemented")
 }
 
+// AccessEvents returns the account and storage accesses of the current transaction.
+// Nil is returned if no transaction has been started yet.
 func (s *carmenStateDB) AccessEvents() *state.AccessEvents {
 	return s.accessEvents
 }
 
+// PointCache returns the cache of tree key points of the database.
+func (s *carmenStateDB) PointCache() *utils.PointCache {
+	return s.pointCache
+}
+
 func (s *carmenStateDB) Error() error {
 	// ignored
 	return nil
@@ -409,6 +493,16 @@ func (s *carmenStateDB) GetMemoryUsage() *MemoryUsage {
 	return &MemoryUsage{uint64(usage.Total()), usage}
 }
 
+// CreateCheckpoint makes Carmen create a checkpoint in addition to those created every
+// --carmen-checkpoint-interval blocks or --carmen-checkpoint-period minutes.
+func (s *carmenHeadState) CreateCheckpoint() error {
+	checkpointer, ok := s.db.(interface{ CreateCheckpoint() error })
+	if !ok {
+		return fmt.Errorf("carmen database does not support checkpoints on demand")
+	}
+	return checkpointer.CreateCheckpoint()
+}
+
 func (s *carmenStateDB) GetShadowDB() StateDB {
 	return nil
 }
@@ -420,6 +514,7 @@ func (s *carmenHeadState) BeginBlock(block uint64) error {
 }
 
 func (s *carmenHeadState) BeginTransaction(uint32) error {
+	s.beginTransaction()
 	var err error
 	s.txCtx, err = s.blkCtx.BeginTransaction()
 	return err
@@ -445,7 +540,8 @@ func (s *carmenHeadState) GetArchiveState(block uint64) (NonCommittableStateDB,
 
 	return &carmenHistoricState{
 		carmenStateDB: carmenStateDB{
-			db: s.db,
+			db:         s.db,
+			pointCache: s.pointCache,
 		},
 		blkCtx:    historicBlkCtx,
 		blkNumber: block,
@@ -464,6 +560,7 @@ func (s *carmenHeadState) GetArchiveBlockHeight() (uint64, bool, error) {
 }
 
 func (s *carmenHistoricState) BeginTransaction(uint32) error {
+	s.beginTransaction()
 	var err error
 	s.txCtx, err = s.blkCtx.BeginTransaction()
 	return err
@@ -478,6 +575,107 @@ func (s *carmenHistoricState) Release() error {
 	return s.blkCtx.Close()
 }
 
+// ----------------------------------------------------------------------------
+//                               Touched Accounts
+// ----------------------------------------------------------------------------
+
+// touchedAccounts records the accounts and storage slots modified by a
+// transaction. Modifications are kept in order of occurrence, so reverting
+// to a snapshot drops all modifications made after the snapshot was taken.
+type touchedAccounts struct {
+	entries   []touchedEntry
+	snapshots map[int]int // snapshot id -> number of entries at the time of the snapshot
+}
+
+type touchedEntry struct {
+	addr   common.Address
+	key    common.Hash
+	isSlot bool
+}
+
+func (t *touchedAccounts) touch(addr common.Address) {
+	t.entries = append(t.entries, touchedEntry{addr: addr})
+}
+
+func (t *touchedAccounts) touchSlot(addr common.Address, key common.Hash) {
+	t.entries = append(t.entries, touchedEntry{addr: addr, key: key, isSlot: true})
+}
+
+func (t *touchedAccounts) snapshot(id int) {
+	if t.snapshots == nil {
+		t.snapshots = make(map[int]int)
+	}
+	t.snapshots[id] = len(t.entries)
+}
+
+func (t *touchedAccounts) revert(id int) {
+	size, found := t.snapshots[id]
+	if !found {
+		return
+	}
+	t.entries = t.entries[:size]
+	// snapshots taken after the reverted one are invalidated
+	for other, otherSize := range t.snapshots {
+		if otherSize > size || other > id {
+			delete(t.snapshots, other)
+		}
+	}
+}
+
+func (t *touchedAccounts) reset() {
+	t.entries = t.entries[:0]
+	clear(t.snapshots)
+}
+
+// accounts returns touched accounts along with their modified storage keys.
+func (t *touchedAccounts) accounts() map[common.Address][]common.Hash {
+	res := make(map[common.Address][]common.Hash)
+	seen := make(map[touchedEntry]struct{})
+	for _, entry := range t.entries {
+		if _, found := seen[entry]; found {
+			continue
+		}
+		seen[entry] = struct{}{}
+		keys := res[entry.addr]
+		if entry.isSlot {
+			keys = append(keys, entry.key)
+		}
+		res[entry.addr] = keys
+	}
+	return res
+}
+
+// ----------------------------------------------------------------------------
+//                                Access Events
+// ----------------------------------------------------------------------------
+
+// The access events are collected for EIP-4762 gas accounting experiments. Only
+// the accessed locations are of interest, hence no gas limit is applied.
+
+func (s *carmenStateDB) recordAccountAccess(addr common.Address) {
+	if s.accessEvents != nil {
+		s.accessEvents.AddAccount(addr, true, math.MaxUint64)
+	}
+}
+
+func (s *carmenStateDB) recordBasicDataAccess(addr common.Address, isWrite bool) {
+	if s.accessEvents != nil {
+		s.accessEvents.BasicDataGas(addr, isWrite, math.MaxUint64, false)
+	}
+}
+
+func (s *carmenStateDB) recordCodeHashAccess(addr common.Address, isWrite bool) {
+	if s.accessEvents != nil {
+		s.accessEvents.CodeHashGas(addr, isWrite, math.MaxUint64, false)
+	}
+}
+
+func (s *carmenStateDB) recordSlotAccess(addr common.Address, key common.Hash, isWrite bool) {
+	if s.accessEvents != nil {
+		s.accessEvents.SlotGas(addr, key, isWrite, math.MaxUint64, false)
+	}
+}
+
 // ----------------------------------------------------------------------------
 //                                  BulkLoad
 // ----------------------------------------------------------------------------
@@ -19,15 +19,22 @@ package state
 import (
 	"bytes"
 	"errors"
+	"math"
+	"math/big"
+	"slices"
 	"testing"
 
+	"github.com/0xsoniclabs/aida/txcontext"
 	"github.com/0xsoniclabs/carmen/go/carmen"
+	"github.com/0xsoniclabs/carmen/go/database/vt/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -1502,16 +1509,142 @@ func TestCarmenStateDB_PrepareSubstate(t *testing.T) {
 }
 
 func TestCarmenStateDB_GetSubstatePostAlloc(t *testing.T) {
+	addr1 := common.Address{1}
+	addr2 := common.Address{2}
+	key1 := common.Hash{1}
+	key2 := common.Hash{2}
+
+	tests := map[string]struct {
+		run       func(c *carmenStateDB, mockTxCtx *carmen.MockTransactionContext)
+		destroyed []common.Address
+		want      map[common.Address]txcontext.Account
+	}{
+		"no modifications": {
+			run:  func(*carmenStateDB, *carmen.MockTransactionContext) {},
+			want: map[common.Address]txcontext.Account{},
+		},
+		"modified accounts and slots": {
+			run: func(c *carmenStateDB, mockTxCtx *carmen.MockTransactionContext) {
+				c.CreateAccount(addr1)
+				c.SetNonce(addr1, 1, tracing.NonceChangeUnspecified)
+				c.SetState(addr1, key1, common.Hash{3})
+				c.AddBalance(addr2, uint256.NewInt(5), tracing.BalanceChangeUnspecified)
+			},
+			want: map[common.Address]txcontext.Account{
+				addr1: txcontext.NewAccount([]byte{1}, map[common.Hash]common.Hash{key1: {0x11}}, big.NewInt(7), 3),
+				addr2: txcontext.NewAccount([]byte{2}, map[common.Hash]common.Hash{}, big.NewInt(7), 3),
+			},
+		},
+		"reverted modifications": {
+			run: func(c *carmenStateDB, mockTxCtx *carmen.MockTransactionContext) {
+				c.SetState(addr1, key1, common.Hash{3})
+				mockTxCtx.EXPECT().Snapshot().Return(1)
+				snapshot := c.Snapshot()
+				c.SetState(addr1, key2, common.Hash{3})
+				c.SetCode(addr2, []byte{1}, tracing.CodeChangeUnspecified)
+				mockTxCtx.EXPECT().RevertToSnapshot(snapshot)
+				c.RevertToSnapshot(snapshot)
+			},
+			want: map[common.Address]txcontext.Account{
+				addr1: txcontext.NewAccount([]byte{1}, map[common.Hash]common.Hash{key1: {0x11}}, big.NewInt(7), 3),
+			},
+		},
+		"nested snapshots": {
+			run: func(c *carmenStateDB, mockTxCtx *carmen.MockTransactionContext) {
+				mockTxCtx.EXPECT().Snapshot().Return(1)
+				outer := c.Snapshot()
+				c.SetState(addr1, key1, common.Hash{3})
+				mockTxCtx.EXPECT().Snapshot().Return(2)
+				inner := c.Snapshot()
+				c.SetState(addr2, key2, common.Hash{3})
+				mockTxCtx.EXPECT().RevertToSnapshot(inner)
+				c.RevertToSnapshot(inner)
+				mockTxCtx.EXPECT().RevertToSnapshot(outer)
+				c.RevertToSnapshot(outer)
+			},
+			want: map[common.Address]txcontext.Account{},
+		},
+		"self-destructed account": {
+			run: func(c *carmenStateDB, mockTxCtx *carmen.MockTransactionContext) {
+				c.AddBalance(addr1, uint256.NewInt(5), tracing.BalanceChangeUnspecified)
+				c.SelfDestruct(addr2)
+			},
+			destroyed: []common.Address{addr2},
+			want: map[common.Address]txcontext.Account{
+				addr1: txcontext.NewAccount([]byte{1}, map[common.Hash]common.Hash{}, big.NewInt(7), 3),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockTxCtx := carmen.NewMockTransactionContext(ctrl)
+			c := &carmenStateDB{txCtx: mockTxCtx}
+
+			// modifications
+			mockTxCtx.EXPECT().CreateAccount(gomock.Any()).AnyTimes()
+			mockTxCtx.EXPECT().SetNonce(gomock.Any(), gomock.Any()).AnyTimes()
+			mockTxCtx.EXPECT().SetState(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			mockTxCtx.EXPECT().SetCode(gomock.Any(), gomock.Any()).AnyTimes()
+			mockTxCtx.EXPECT().AddBalance(gomock.Any(), gomock.Any()).AnyTimes()
+			mockTxCtx.EXPECT().SelfDestruct(gomock.Any()).AnyTimes()
+
+			// state of touched accounts
+			mockTxCtx.EXPECT().Exist(gomock.Any()).Return(true).AnyTimes()
+			for _, addr := range []common.Address{addr1, addr2} {
+				mockTxCtx.EXPECT().HasSelfDestructed(carmen.Address(addr)).Return(slices.Contains(test.destroyed, addr)).AnyTimes()
+				mockTxCtx.EXPECT().GetCode(carmen.Address(addr)).Return([]byte{addr[0]}).AnyTimes()
+			}
+			mockTxCtx.EXPECT().GetState(gomock.Any(), gomock.Any()).Return(carmen.Value{0x11}).AnyTimes()
+			mockTxCtx.EXPECT().GetBalance(gomock.Any()).Return(carmen.NewAmountFromUint256(uint256.NewInt(7))).AnyTimes()
+			mockTxCtx.EXPECT().GetNonce(gomock.Any()).Return(uint64(3)).AnyTimes()
+
+			test.run(c, mockTxCtx)
+			want := txcontext.NewWorldState(test.want)
+			assert.True(t, want.Equal(c.GetSubstatePostAlloc()), "unexpected post-alloc during transaction")
+
+			mockTxCtx.EXPECT().Commit().Return(nil)
+			require.NoError(t, c.EndTransaction())
+			got := c.GetSubstatePostAlloc()
+			assert.True(t, want.Equal(got), "unexpected post-alloc\nwant: %v\ngot: %v", want, got)
+		})
+	}
+}
+
+func TestCarmenStateDB_GetSubstatePostAllocIsResetPerTransaction(t *testing.T) {
 	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
 	mockDb := carmen.NewMockDatabase(ctrl)
+	mockBlkCtx := carmen.NewMockHeadBlockContext(ctrl)
 	mockTxCtx := carmen.NewMockTransactionContext(ctrl)
-	c := &carmenStateDB{
-		db:    mockDb,
-		txCtx: mockTxCtx,
-	}
-	out := c.GetSubstatePostAlloc()
-	assert.Nil(t, out)
+	c := &carmenHeadState{
+		carmenStateDB: carmenStateDB{db: mockDb},
+		blkCtx:        mockBlkCtx,
+	}
+	addr := common.Address{1}
+
+	gomock.InOrder(
+		mockBlkCtx.EXPECT().BeginTransaction().Return(mockTxCtx, nil),
+		mockTxCtx.EXPECT().SetNonce(carmen.Address(addr), uint64(1)),
+		mockTxCtx.EXPECT().HasSelfDestructed(carmen.Address(addr)).Return(false),
+		mockTxCtx.EXPECT().Exist(carmen.Address(addr)).Return(true),
+		mockTxCtx.EXPECT().GetBalance(carmen.Address(addr)).Return(carmen.NewAmountFromUint256(uint256.NewInt(0))),
+		mockTxCtx.EXPECT().GetCode(carmen.Address(addr)).Return(nil),
+		mockTxCtx.EXPECT().GetNonce(carmen.Address(addr)).Return(uint64(1)),
+		mockTxCtx.EXPECT().Commit().Return(nil),
+		mockBlkCtx.EXPECT().BeginTransaction().Return(mockTxCtx, nil),
+		mockTxCtx.EXPECT().Commit().Return(nil),
+	)
+
+	require.NoError(t, c.BeginTransaction(0))
+	c.SetNonce(addr, 1, tracing.NonceChangeUnspecified)
+	require.NoError(t, c.EndTransaction())
+	assert.Equal(t, 1, c.GetSubstatePostAlloc().Len())
+
+	require.NoError(t, c.BeginTransaction(1))
+	assert.Equal(t, 0, c.GetSubstatePostAlloc().Len())
+	require.NoError(t, c.EndTransaction())
+	assert.Equal(t, 0, c.GetSubstatePostAlloc().Len())
 }
 
 func TestCarmenStateDB_AddPreimage(t *testing.T) {
@@ -1541,6 +1674,113 @@ func TestCarmenStateDB_AccessEvents(t *testing.T) {
 	assert.Nil(t, out)
 }
 
+func TestCarmenStateDB_AccessEventsAreRecordedByAdapterMethods(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBlkCtx := carmen.NewMockHeadBlockContext(ctrl)
+	mockTxCtx := carmen.NewMockTransactionContext(ctrl)
+	c := &carmenHeadState{blkCtx: mockBlkCtx}
+
+	addr := common.Address{1}
+	other := common.Address{2}
+	key := common.Hash{3}
+	value := common.Hash{4}
+
+	mockBlkCtx.EXPECT().BeginTransaction().Return(mockTxCtx, nil)
+	mockTxCtx.EXPECT().GetState(carmen.Address(addr), carmen.Key(key)).Return(carmen.Value{}).Times(2)
+	mockTxCtx.EXPECT().SetState(carmen.Address(addr), carmen.Key(key), carmen.Value(value))
+	mockTxCtx.EXPECT().GetBalance(carmen.Address(other)).Return(carmen.NewAmount(uint64(1)))
+
+	require.NoError(t, c.BeginTransaction(0))
+	c.GetState(addr, key)
+	c.SetState(addr, key, value)
+	c.GetBalance(other)
+
+	want := state.NewAccessEvents()
+	want.SlotGas(addr, key, true, math.MaxUint64, false)
+	want.BasicDataGas(other, false, math.MaxUint64, false)
+
+	got := c.AccessEvents()
+	require.NotNil(t, got)
+	assert.ElementsMatch(t, want.Keys(), got.Keys())
+
+	// the write access to the slot has been recorded
+	assert.Zero(t, got.SlotGas(addr, key, true, math.MaxUint64, false))
+	// the balance has only been read
+	assert.NotZero(t, got.BasicDataGas(other, true, math.MaxUint64, false))
+}
+
+func TestCarmenStateDB_AccessEventsAreResetPerTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBlkCtx := carmen.NewMockHeadBlockContext(ctrl)
+	mockTxCtx := carmen.NewMockTransactionContext(ctrl)
+	c := &carmenHeadState{blkCtx: mockBlkCtx}
+	addr := common.Address{1}
+
+	mockBlkCtx.EXPECT().BeginTransaction().Return(mockTxCtx, nil).Times(2)
+	mockTxCtx.EXPECT().GetNonce(carmen.Address(addr)).Return(uint64(1))
+	mockTxCtx.EXPECT().Commit().Return(nil)
+
+	require.NoError(t, c.BeginTransaction(0))
+	c.GetNonce(addr)
+	require.NoError(t, c.EndTransaction())
+	assert.Len(t, c.AccessEvents().Keys(), 1)
+
+	require.NoError(t, c.BeginTransaction(1))
+	assert.Empty(t, c.AccessEvents().Keys())
+}
+
+func TestCarmenHistoricState_AccessEventsAreRecordedPerTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBlkCtx := carmen.NewMockHistoricBlockContext(ctrl)
+	mockTxCtx := carmen.NewMockTransactionContext(ctrl)
+	c := &carmenHistoricState{blkCtx: mockBlkCtx}
+	addr := common.Address{1}
+	key := common.Hash{2}
+
+	mockBlkCtx.EXPECT().BeginTransaction().Return(mockTxCtx, nil).Times(2)
+	mockTxCtx.EXPECT().GetState(carmen.Address(addr), carmen.Key(key)).Return(carmen.Value{})
+
+	require.NoError(t, c.BeginTransaction(0))
+	c.GetState(addr, key)
+	assert.Len(t, c.AccessEvents().Keys(), 1)
+
+	require.NoError(t, c.BeginTransaction(1))
+	assert.Empty(t, c.AccessEvents().Keys())
+}
+
+func TestCarmenStateDB_PointCacheIsStable(t *testing.T) {
+	db, err := MakeCarmenStateDB(t.TempDir(), "go-file", 5, "", 0, 0, 0, 0)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	head, ok := db.(*carmenHeadState)
+	require.True(t, ok)
+	cache := head.PointCache()
+	assert.NotNil(t, cache)
+	assert.Same(t, cache, head.PointCache())
+}
+
+func TestCarmenHeadState_PointCacheIsSharedWithArchiveStates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDb := carmen.NewMockDatabase(ctrl)
+	mockHistoricCtx := carmen.NewMockHistoricBlockContext(ctrl)
+	c := &carmenHeadState{
+		carmenStateDB: carmenStateDB{
+			db:         mockDb,
+			pointCache: utils.NewPointCache(pointCacheSize),
+		},
+	}
+	mockDb.EXPECT().GetHistoricContext(uint64(1)).Return(mockHistoricCtx, nil)
+
+	archive, err := c.GetArchiveState(1)
+	require.NoError(t, err)
+	historic, ok := archive.(*carmenHistoricState)
+	require.True(t, ok)
+	assert.Same(t, c.PointCache(), historic.PointCache())
+}
+
 func TestCarmenStateDB_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -416,6 +416,10 @@ func (s *gethStateDB) GetMemoryUsage() *MemoryUsage {
 	return &MemoryUsage{uint64(0), nil}
 }
 
+func (s *gethStateDB) CreateCheckpoint() error {
+	return fmt.Errorf("checkpoints are not supported by this DB implementation")
+}
+
 type gethBulkLoad struct {
 	db    *gethStateDB
 	block uint64
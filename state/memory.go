@@ -587,6 +587,10 @@ func (db *inMemoryStateDB) GetMemoryUsage() *MemoryUsage {
 	return &MemoryUsage{uint64(0), nil}
 }
 
+func (db *inMemoryStateDB) CreateCheckpoint() error {
+	return fmt.Errorf("checkpoints are not supported by this DB implementation")
+}
+
 func (db *inMemoryStateDB) GetArchiveState(block uint64) (NonCommittableStateDB, error) {
 	return nil, fmt.Errorf("archive states are not (yet) supported by this DB implementation")
 }
@@ -336,6 +336,10 @@ func (r *DeletionProxy) GetMemoryUsage() *state.MemoryUsage {
 	return r.db.GetMemoryUsage()
 }
 
+func (r *DeletionProxy) CreateCheckpoint() error {
+	return r.db.CreateCheckpoint()
+}
+
 func (r *DeletionProxy) GetShadowDB() state.StateDB {
 	return r.db.GetShadowDB()
 }
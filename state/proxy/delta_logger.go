@@ -40,10 +40,12 @@ import (
 
 // DeltaLogSink writes textual operations to disk in the format expected by the delta debugger.
 type DeltaLogSink struct {
-	mu     sync.Mutex
-	writer *bufio.Writer
-	closer io.Closer
-	log    logger.Logger
+	mu      sync.Mutex
+	writer  *bufio.Writer
+	closer  io.Closer
+	log     logger.Logger
+	queue   *logger.WriteBehind[string] // nil if lines are written synchronously
+	results bool                        // values returned by read operations are logged
 }
 
 // NewDeltaLogSink creates a sink that logs to the provided writer and logger.
@@ -55,8 +57,17 @@ func NewDeltaLogSink(log logger.Logger, writer *bufio.Writer, closer io.Closer)
 	}
 }
 
-// Logf writes the formatted message to the sink and flushes immediately so the last operation
-// is present even if the process crashes.
+// NewAsyncDeltaLogSink creates a sink handing lines over to a write-behind queue of given capacity,
+// so that the writing does not slow down the logged operations. Lines are flushed whenever the
+// queue runs empty.
+func NewAsyncDeltaLogSink(log logger.Logger, writer *bufio.Writer, closer io.Closer, capacity int, policy logger.OverflowPolicy) *DeltaLogSink {
+	s := NewDeltaLogSink(log, writer, closer)
+	s.queue = logger.NewWriteBehind(capacity, policy, s.writeLine, s.flushWriter)
+	return s
+}
+
+// Logf writes the formatted message to the sink. A synchronous sink flushes immediately so the
+// last operation is present even if the process crashes.
 func (s *DeltaLogSink) Logf(format string, args ...any) {
 	if s == nil {
 		return
@@ -65,28 +76,68 @@ func (s *DeltaLogSink) Logf(format string, args ...any) {
 	line := fmt.Sprintf(format, args...)
 	line = strings.TrimSuffix(line, "\n")
 
-	s.mu.Lock()
-	if s.writer != nil {
-		if _, err := s.writer.WriteString(line + "\n"); err != nil && s.log != nil {
+	if s.queue != nil {
+		s.queue.Push(line)
+	} else {
+		if err := s.writeLine(line); err != nil && s.log != nil {
 			s.log.Errorf("delta logger: write failed: %v", err)
 		}
-		if err := s.writer.Flush(); err != nil && s.log != nil {
+		if err := s.flushWriter(); err != nil && s.log != nil {
 			s.log.Errorf("delta logger: flush failed: %v", err)
 		}
 	}
-	s.mu.Unlock()
 
 	if s.log != nil {
 		s.log.Debug(line)
 	}
 }
 
+// RecordResults makes proxies using the sink log the values returned by read operations
+// in a Result line following the operation. Traces without Result lines remain valid,
+// the values are only needed for a strict replay verifying them.
+func (s *DeltaLogSink) RecordResults() {
+	s.results = true
+}
+
+// Dropped returns the number of lines dropped by an asynchronous sink due to a full queue.
+func (s *DeltaLogSink) Dropped() uint64 {
+	if s == nil || s.queue == nil {
+		return 0
+	}
+	return s.queue.Dropped()
+}
+
+func (s *DeltaLogSink) writeLine(line string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writer == nil {
+		return nil
+	}
+	_, err := s.writer.WriteString(line + "\n")
+	return err
+}
+
+func (s *DeltaLogSink) flushWriter() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writer == nil {
+		return nil
+	}
+	return s.writer.Flush()
+}
+
 // Flush flushes buffered data and fsyncs when supported by the closer.
 func (s *DeltaLogSink) Flush() error {
 	if s == nil {
 		return nil
 	}
 
+	if s.queue != nil {
+		if err := s.queue.Flush(); err != nil {
+			return err
+		}
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -108,10 +159,15 @@ func (s *DeltaLogSink) Close() error {
 		return nil
 	}
 
+	// pending lines have to be written before the writer is closed
+	var err error
+	if s.queue != nil {
+		err = s.queue.Close()
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	var err error
 	if s.writer != nil {
 		err = errors.Join(err, s.writer.Flush())
 	}
@@ -217,6 +273,11 @@ func (s *DeltaLoggingStateDB) GetMemoryUsage() *state.MemoryUsage {
 	return s.state.GetMemoryUsage()
 }
 
+// CreateCheckpoint is not logged since it does not modify the state.
+func (s *DeltaLoggingStateDB) CreateCheckpoint() error {
+	return s.state.CreateCheckpoint()
+}
+
 func (s *DeltaLoggingStateDB) GetShadowDB() state.StateDB {
 	return s.state.GetShadowDB()
 }
@@ -252,6 +313,15 @@ func (s *deltaLoggingVmStateDb) logf(format string, args ...any) {
 	}
 }
 
+// logResult logs the value returned by a read operation if the sink records results.
+// The operation and its arguments are repeated so that the result can be matched with
+// the operation even if the trace is reduced by the delta debugger.
+func (s *deltaLoggingVmStateDb) logResult(format string, args ...any) {
+	if s.sink != nil && s.sink.results {
+		s.sink.Logf("Result, "+format, args...)
+	}
+}
+
 func (s *deltaLoggingVmStateDb) CreateAccount(addr common.Address) {
 	s.logf("CreateAccount, %s", addr.Hex())
 	s.db.CreateAccount(addr)
@@ -269,7 +339,9 @@ func (s *deltaLoggingVmStateDb) IsNewContract(addr common.Address) bool {
 
 func (s *deltaLoggingVmStateDb) Exist(addr common.Address) bool {
 	s.logf("Exist, %s", addr.Hex())
-	return s.db.Exist(addr)
+	res := s.db.Exist(addr)
+	s.logResult("Exist, %s, %s", addr.Hex(), formatBool(res))
+	return res
 }
 
 func (s *deltaLoggingVmStateDb) Empty(addr common.Address) bool {
@@ -289,7 +361,9 @@ func (s *deltaLoggingVmStateDb) HasSelfDestructed(addr common.Address) bool {
 
 func (s *deltaLoggingVmStateDb) GetBalance(addr common.Address) *uint256.Int {
 	s.logf("GetBalance, %s", addr.Hex())
-	return s.db.GetBalance(addr)
+	res := s.db.GetBalance(addr)
+	s.logResult("GetBalance, %s, %s", addr.Hex(), formatUint256(res))
+	return res
 }
 
 func (s *deltaLoggingVmStateDb) AddBalance(addr common.Address, value *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
@@ -306,7 +380,9 @@ func (s *deltaLoggingVmStateDb) SubBalance(addr common.Address, value *uint256.I
 
 func (s *deltaLoggingVmStateDb) GetNonce(addr common.Address) uint64 {
 	s.logf("GetNonce, %s", addr.Hex())
-	return s.db.GetNonce(addr)
+	res := s.db.GetNonce(addr)
+	s.logResult("GetNonce, %s, %s", addr.Hex(), formatUint64(res))
+	return res
 }
 
 func (s *deltaLoggingVmStateDb) SetNonce(addr common.Address, nonce uint64, reason tracing.NonceChangeReason) {
@@ -326,7 +402,9 @@ func (s *deltaLoggingVmStateDb) GetStateAndCommittedState(addr common.Address, k
 
 func (s *deltaLoggingVmStateDb) GetState(addr common.Address, key common.Hash) common.Hash {
 	s.logf("GetState, %s, %s", addr.Hex(), key.Hex())
-	return s.db.GetState(addr, key)
+	res := s.db.GetState(addr, key)
+	s.logResult("GetState, %s, %s, %s", addr.Hex(), key.Hex(), res.Hex())
+	return res
 }
 
 func (s *deltaLoggingVmStateDb) SetState(addr common.Address, key common.Hash, value common.Hash) common.Hash {
@@ -346,7 +424,9 @@ func (s *deltaLoggingVmStateDb) GetTransientState(addr common.Address, key commo
 
 func (s *deltaLoggingVmStateDb) GetCodeHash(addr common.Address) common.Hash {
 	s.logf("GetCodeHash, %s", addr.Hex())
-	return s.db.GetCodeHash(addr)
+	res := s.db.GetCodeHash(addr)
+	s.logResult("GetCodeHash, %s, %s", addr.Hex(), res.Hex())
+	return res
 }
 
 func (s *deltaLoggingVmStateDb) GetCode(addr common.Address) []byte {
@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"bytes"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -340,3 +341,92 @@ func TestDeltaLoggingStateDB_DelegatesMethods(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, proxyDB.Close())
 }
+
+func TestDeltaLogSink_AsyncSinkWritesLinesInOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockLog := logger.NewMockLogger(ctrl)
+	mockLog.EXPECT().Debug(gomock.Any()).Times(100)
+
+	syncCloser := &fakeSyncCloser{}
+	sink := NewAsyncDeltaLogSink(mockLog, bufio.NewWriter(syncCloser), syncCloser, 8, logger.BlockOnOverflow)
+	var want []string
+	for i := 0; i < 100; i++ {
+		line := fmt.Sprintf("SetNonce, %d", i)
+		want = append(want, line)
+		sink.Logf(line)
+	}
+	require.NoError(t, sink.Flush())
+	require.Equal(t, strings.Join(want, "\n")+"\n", syncCloser.String())
+	require.True(t, syncCloser.syncCalled)
+
+	require.NoError(t, sink.Close())
+	require.True(t, syncCloser.closeCalled)
+	require.Zero(t, sink.Dropped())
+
+	// lines logged after closing are dropped
+	sink.Logf("late")
+	require.Equal(t, uint64(1), sink.Dropped())
+}
+
+func TestDeltaLogSink_AsyncSinkReportsWriteErrorsOnClose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockLog := logger.NewMockLogger(ctrl)
+	mockLog.EXPECT().Debug("content").Times(1)
+
+	syncCloser := &fakeSyncCloser{failWrite: true}
+	sink := NewAsyncDeltaLogSink(mockLog, bufio.NewWriterSize(syncCloser, 1), syncCloser, 8, logger.DropOnOverflow)
+	sink.Logf("content")
+	require.Error(t, sink.Close())
+}
+
+func TestDeltaLogger_RecordsReadResultsOnlyIfEnabled(t *testing.T) {
+	addr := common.HexToAddress("0x1")
+	key := common.HexToHash("0x2")
+	value := common.HexToHash("0x3")
+	codeHash := common.HexToHash("0x4")
+
+	for _, record := range []bool{false, true} {
+		t.Run(fmt.Sprintf("record=%t", record), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := state.NewMockStateDB(ctrl)
+
+			buf := new(bytes.Buffer)
+			sink := NewDeltaLogSink(nil, bufio.NewWriter(buf), nil)
+			if record {
+				sink.RecordResults()
+			}
+			proxyDB := NewDeltaLoggerProxy(mockDB, sink)
+
+			mockDB.EXPECT().Exist(addr).Return(true)
+			mockDB.EXPECT().GetBalance(addr).Return(uint256.NewInt(42))
+			mockDB.EXPECT().GetNonce(addr).Return(uint64(7))
+			mockDB.EXPECT().GetState(addr, key).Return(value)
+			mockDB.EXPECT().GetCodeHash(addr).Return(codeHash)
+
+			require.True(t, proxyDB.Exist(addr))
+			require.Equal(t, uint256.NewInt(42), proxyDB.GetBalance(addr))
+			require.Equal(t, uint64(7), proxyDB.GetNonce(addr))
+			require.Equal(t, value, proxyDB.GetState(addr, key))
+			require.Equal(t, codeHash, proxyDB.GetCodeHash(addr))
+			require.NoError(t, sink.Flush())
+
+			expected := []string{
+				"Exist, " + addr.Hex(),
+				"GetBalance, " + addr.Hex(),
+				"GetNonce, " + addr.Hex(),
+				"GetState, " + addr.Hex() + ", " + key.Hex(),
+				"GetCodeHash, " + addr.Hex(),
+			}
+			if record {
+				expected = []string{
+					expected[0], "Result, Exist, " + addr.Hex() + ", true",
+					expected[1], "Result, GetBalance, " + addr.Hex() + ", 42",
+					expected[2], "Result, GetNonce, " + addr.Hex() + ", 7",
+					expected[3], "Result, GetState, " + addr.Hex() + ", " + key.Hex() + ", " + value.Hex(),
+					expected[4], "Result, GetCodeHash, " + addr.Hex() + ", " + codeHash.Hex(),
+				}
+			}
+			require.Equal(t, strings.Join(expected, "\n"), strings.TrimSpace(buf.String()))
+		})
+	}
+}
@@ -18,8 +18,11 @@ package proxy
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/state"
@@ -35,8 +38,8 @@ import (
 
 // NewLoggerProxy wraps the given StateDB instance into a logging wrapper causing
 // every StateDB operation (except BulkLoading) to be logged for debugging.
-func NewLoggerProxy(db state.StateDB, log logger.Logger, output chan string, wg *sync.WaitGroup) state.StateDB {
-	return &LoggingStateDb{
+func NewLoggerProxy(db state.StateDB, log logger.Logger, output chan string, wg *sync.WaitGroup, opts ...LoggerOption) state.StateDB {
+	res := &LoggingStateDb{
 		loggingVmStateDb: loggingVmStateDb{
 			db:     db,
 			log:    log,
@@ -46,6 +49,69 @@ func NewLoggerProxy(db state.StateDB, log logger.Logger, output chan string, wg
 
 		state: db,
 	}
+	for _, opt := range opts {
+		opt(&res.loggingVmStateDb)
+	}
+	return res
+}
+
+// LoggingFormat is the format of operations written by the logging proxy.
+type LoggingFormat string
+
+const (
+	// TextLoggingFormat writes each operation as a comma-separated line.
+	TextLoggingFormat LoggingFormat = "text"
+	// JsonLoggingFormat writes each operation as a single-line JSON object.
+	JsonLoggingFormat LoggingFormat = "json"
+)
+
+// ParseLoggingFormat converts given name into a LoggingFormat.
+func ParseLoggingFormat(name string) (LoggingFormat, error) {
+	switch format := LoggingFormat(name); format {
+	case TextLoggingFormat, JsonLoggingFormat:
+		return format, nil
+	case "":
+		return TextLoggingFormat, nil
+	default:
+		return "", fmt.Errorf("unknown db-logging format %q; options: %q, %q", name, TextLoggingFormat, JsonLoggingFormat)
+	}
+}
+
+// ParseOperationFilter splits a comma-separated list of operation names.
+func ParseOperationFilter(list string) []string {
+	var ops []string
+	for _, op := range strings.Split(list, ",") {
+		if op = strings.TrimSpace(op); op != "" {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// LoggerOption configures a logging proxy created by NewLoggerProxy.
+type LoggerOption func(*loggingVmStateDb)
+
+// WithLoggingFormat sets the format of logged operations; text is used by default.
+func WithLoggingFormat(format LoggingFormat) LoggerOption {
+	return func(s *loggingVmStateDb) {
+		s.json = format == JsonLoggingFormat
+	}
+}
+
+// WithOperationFilter restricts logging to operations of given names, e.g. GetState.
+// Filtered operations are dropped before being formatted. All operations are logged
+// if no name is given.
+func WithOperationFilter(ops ...string) LoggerOption {
+	return func(s *loggingVmStateDb) {
+		if len(ops) == 0 {
+			s.filter = nil
+			return
+		}
+		s.filter = make(map[string]struct{}, len(ops))
+		for _, op := range ops {
+			s.filter[op] = struct{}{}
+		}
+	}
 }
 
 type loggingVmStateDb struct {
@@ -53,6 +119,22 @@ type loggingVmStateDb struct {
 	log    logger.Logger
 	output chan string
 	wg     *sync.WaitGroup
+
+	json   bool                // operations are written as JSON objects instead of text lines
+	filter map[string]struct{} // names of logged operations, all operations are logged if nil
+	block  uint64              // current block, reported in the JSON format
+	tx     uint32              // current transaction, reported in the JSON format
+}
+
+// jsonLogEntry is a single operation written in the JSON format.
+type jsonLogEntry struct {
+	Op      string          `json:"op"`
+	Addr    *common.Address `json:"addr,omitempty"`
+	Key     *common.Hash    `json:"key,omitempty"`
+	Value   string          `json:"value"`
+	Block   uint64          `json:"block"`
+	Tx      uint32          `json:"tx"`
+	Elapsed int64           `json:"elapsed"` // in nanoseconds
 }
 
 type loggingNonCommittableStateDb struct {
@@ -66,45 +148,55 @@ type LoggingStateDb struct {
 }
 
 type loggingBulkLoad struct {
-	nested   state.BulkLoad
-	writeLog func(format string, a ...any)
+	nested state.BulkLoad
+	db     *loggingVmStateDb
 }
 
 func (s *LoggingStateDb) Error() error {
+	start := time.Now()
 	err := s.state.Error()
-	s.writeLog("Error, %v", err)
+	s.writeLog("Error", start, err)
 	return err
 }
 
 func (s *LoggingStateDb) BeginBlock(blk uint64) error {
-	s.writeLog("BeginBlock, %v", blk)
-	return s.state.BeginBlock(blk)
+	s.block, s.tx = blk, 0
+	start := time.Now()
+	err := s.state.BeginBlock(blk)
+	s.writeLog("BeginBlock", start, blk)
+	return err
 }
 
 func (s *LoggingStateDb) EndBlock() error {
-	s.writeLog("EndBlock")
-	return s.state.EndBlock()
+	start := time.Now()
+	err := s.state.EndBlock()
+	s.writeLog("EndBlock", start)
+	return err
 }
 
 func (s *LoggingStateDb) BeginSyncPeriod(number uint64) {
-	s.writeLog("BeginSyncPeriod, %v", number)
+	start := time.Now()
 	s.state.BeginSyncPeriod(number)
+	s.writeLog("BeginSyncPeriod", start, number)
 }
 
 func (s *LoggingStateDb) EndSyncPeriod() {
-	s.writeLog("EndSyncPeriod")
+	start := time.Now()
 	s.state.EndSyncPeriod()
+	s.writeLog("EndSyncPeriod", start)
 }
 
 func (s *LoggingStateDb) GetHash() (common.Hash, error) {
+	start := time.Now()
 	hash, err := s.state.GetHash()
-	s.writeLog("GetHash, %v", hash)
+	s.writeLog("GetHash", start, hash)
 	return hash, err
 }
 
 func (s *LoggingStateDb) Close() error {
+	start := time.Now()
 	res := s.state.Close()
-	s.writeLog("Close")
+	s.writeLog("Close", start)
 	// signal and await the close
 	close(s.output)
 	s.wg.Wait()
@@ -117,8 +209,8 @@ func (s *LoggingStateDb) StartBulkLoad(block uint64) (state.BulkLoad, error) {
 		return nil, fmt.Errorf("cannot start bulkload; %w", err)
 	}
 	return &loggingBulkLoad{
-		nested:   bl,
-		writeLog: s.writeLog,
+		nested: bl,
+		db:     &s.loggingVmStateDb,
 	}, nil
 }
 
@@ -132,14 +224,18 @@ func (s *LoggingStateDb) GetArchiveState(block uint64) (state.NonCommittableStat
 			db:     archive,
 			log:    s.log,
 			output: s.output,
+			json:   s.json,
+			filter: s.filter,
+			block:  block,
 		},
 		nonCommittableStateDB: archive,
 	}, nil
 }
 
 func (s *LoggingStateDb) GetArchiveBlockHeight() (uint64, bool, error) {
+	start := time.Now()
 	res, empty, err := s.state.GetArchiveBlockHeight()
-	s.writeLog("GetArchiveBlockHeight, %v, %t, %v", res, empty, err)
+	s.writeLog("GetArchiveBlockHeight", start, res, empty, err)
 	return res, empty, err
 }
 
@@ -148,327 +244,482 @@ func (s *LoggingStateDb) GetMemoryUsage() *state.MemoryUsage {
 	return s.state.GetMemoryUsage()
 }
 
+func (s *LoggingStateDb) CreateCheckpoint() error {
+	start := time.Now()
+	err := s.state.CreateCheckpoint()
+	s.writeLog("CreateCheckpoint", start, err)
+	return err
+}
+
 func (s *LoggingStateDb) GetShadowDB() state.StateDB {
 	return s.state.GetShadowDB()
 }
 
 func (s *LoggingStateDb) Finalise(deleteEmptyObjects bool) {
-	s.writeLog("Finalise, %v", deleteEmptyObjects)
+	start := time.Now()
 	s.state.Finalise(deleteEmptyObjects)
+	s.writeLog("Finalise", start, deleteEmptyObjects)
 }
 
 func (s *LoggingStateDb) IntermediateRoot(deleteEmptyObjects bool) common.Hash {
+	start := time.Now()
 	res := s.state.IntermediateRoot(deleteEmptyObjects)
-	s.writeLog("IntermediateRoot, %v, %v", deleteEmptyObjects, res)
+	s.writeLog("IntermediateRoot", start, deleteEmptyObjects, res)
 	return res
 }
 
 func (s *LoggingStateDb) Commit(block uint64, deleteEmptyObjects bool) (common.Hash, error) {
+	start := time.Now()
 	hash, err := s.state.Commit(block, deleteEmptyObjects)
-	s.writeLog("Commit, %v, %v, %v", deleteEmptyObjects, hash, err)
+	s.writeLog("Commit", start, deleteEmptyObjects, hash, err)
 	return hash, err
 }
 
 func (s *LoggingStateDb) PrepareSubstate(substate txcontext.WorldState, block uint64) {
+	start := time.Now()
 	s.state.PrepareSubstate(substate, block)
-	s.writeLog("PrepareSubstate, %v", substate.String())
+	s.writeLog("PrepareSubstate", start, substate)
 }
 
 func (s *loggingVmStateDb) CreateAccount(addr common.Address) {
+	start := time.Now()
 	s.db.CreateAccount(addr)
-	s.writeLog("CreateAccount, %v", addr)
+	s.writeAccountLog("CreateAccount", start, addr)
 }
 
 func (s *loggingVmStateDb) IsNewContract(addr common.Address) bool {
+	start := time.Now()
 	res := s.db.IsNewContract(addr)
-	s.writeLog("IsNewContract, %v, %v", addr, res)
+	s.writeAccountLog("IsNewContract", start, addr, res)
 	return res
 }
 
 func (s *loggingVmStateDb) Exist(addr common.Address) bool {
+	start := time.Now()
 	res := s.db.Exist(addr)
-	s.writeLog("Exist, %v, %v", addr, res)
+	s.writeAccountLog("Exist", start, addr, res)
 	return res
 }
 
 func (s *loggingVmStateDb) Empty(addr common.Address) bool {
+	start := time.Now()
 	res := s.db.Empty(addr)
-	s.writeLog("Empty, %v, %v", addr, res)
+	s.writeAccountLog("Empty", start, addr, res)
 	return res
 }
 
 func (s *loggingVmStateDb) SelfDestruct(addr common.Address) {
+	start := time.Now()
 	s.db.SelfDestruct(addr)
-	s.writeLog("SelfDestruct, %v", addr)
+	s.writeAccountLog("SelfDestruct", start, addr)
 }
 
 func (s *loggingVmStateDb) HasSelfDestructed(addr common.Address) bool {
+	start := time.Now()
 	res := s.db.HasSelfDestructed(addr)
-	s.writeLog("HasSelfDestructed, %v, %v", addr, res)
+	s.writeAccountLog("HasSelfDestructed", start, addr, res)
 	return res
 }
 
 func (s *loggingVmStateDb) GetBalance(addr common.Address) *uint256.Int {
+	start := time.Now()
 	res := s.db.GetBalance(addr)
-	s.writeLog("GetBalance, %v, %v", addr, res)
+	s.writeAccountLog("GetBalance", start, addr, res)
 	return res
 }
 
 func (s *loggingVmStateDb) AddBalance(addr common.Address, value *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	start := time.Now()
 	res := s.db.AddBalance(addr, value, reason)
-	s.writeLog("AddBalance, %v, %v, %v, %v, %v", addr, value, s.db.GetBalance(addr), reason, res)
+	if s.isLogged("AddBalance") {
+		s.writeAccountLog("AddBalance", start, addr, value, s.db.GetBalance(addr), reason, res)
+	}
 	return res
 }
 
 func (s *loggingVmStateDb) SubBalance(addr common.Address, value *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	start := time.Now()
 	res := s.db.SubBalance(addr, value, reason)
-	s.writeLog("SubBalance, %v, %v, %v, %v, %v", addr, value, s.db.GetBalance(addr), reason, res)
+	if s.isLogged("SubBalance") {
+		s.writeAccountLog("SubBalance", start, addr, value, s.db.GetBalance(addr), reason, res)
+	}
 	return res
 }
 
 func (s *loggingVmStateDb) GetNonce(addr common.Address) uint64 {
+	start := time.Now()
 	res := s.db.GetNonce(addr)
-	s.writeLog("GetNonce, %v, %v", addr, res)
+	s.writeAccountLog("GetNonce", start, addr, res)
 	return res
 }
 
 func (s *loggingVmStateDb) SetNonce(addr common.Address, value uint64, reason tracing.NonceChangeReason) {
+	start := time.Now()
 	s.db.SetNonce(addr, value, reason)
-	s.writeLog("SetNonce, %v, %v, %v", addr, value, reason)
+	s.writeAccountLog("SetNonce", start, addr, value, reason)
 }
 
 func (s *loggingVmStateDb) GetCommittedState(addr common.Address, key common.Hash) common.Hash {
+	start := time.Now()
 	res := s.db.GetCommittedState(addr, key)
-	s.writeLog("GetCommittedState, %v, %v, %v", addr, key, res)
+	s.writeSlotLog("GetCommittedState", start, addr, key, res)
 	return res
 }
 
 func (s *loggingVmStateDb) GetStateAndCommittedState(addr common.Address, key common.Hash) (common.Hash, common.Hash) {
+	start := time.Now()
 	val, origin := s.db.GetStateAndCommittedState(addr, key)
-	s.writeLog("GetStateAndCommittedState, %s, %s, %s, %s", addr, key, val, origin)
+	s.writeSlotLog("GetStateAndCommittedState", start, addr, key, val, origin)
 	return val, origin
 }
 
 func (s *loggingVmStateDb) GetState(addr common.Address, key common.Hash) common.Hash {
+	start := time.Now()
 	res := s.db.GetState(addr, key)
-	s.writeLog("GetState, %v, %v, %v", addr, key, res)
+	s.writeSlotLog("GetState", start, addr, key, res)
 	return res
 }
 
 func (s *loggingVmStateDb) SetState(addr common.Address, key common.Hash, value common.Hash) common.Hash {
+	start := time.Now()
 	res := s.db.SetState(addr, key, value)
-	s.writeLog("SetState, %v, %v, %v, %v", addr, key, value, res)
+	s.writeSlotLog("SetState", start, addr, key, value, res)
 	return res
 }
 
 func (s *loggingVmStateDb) SetTransientState(addr common.Address, key common.Hash, value common.Hash) {
-	s.writeLog("SetTransientState, %v, %v, %v", addr, key, value)
+	start := time.Now()
 	s.db.SetTransientState(addr, key, value)
+	s.writeSlotLog("SetTransientState", start, addr, key, value)
 }
 
 func (s *loggingVmStateDb) GetTransientState(addr common.Address, key common.Hash) common.Hash {
+	start := time.Now()
 	value := s.db.GetTransientState(addr, key)
-	s.writeLog("GetTransientState, %v, %v, %v", addr, key, value)
+	s.writeSlotLog("GetTransientState", start, addr, key, value)
 	return value
 }
 
 func (s *loggingVmStateDb) GetCode(addr common.Address) []byte {
+	start := time.Now()
 	res := s.db.GetCode(addr)
-	s.writeLog("GetCode, %v, %v", addr, hex.EncodeToString(res))
+	if s.isLogged("GetCode") {
+		s.writeAccountLog("GetCode", start, addr, hex.EncodeToString(res))
+	}
 	return res
 }
 
 func (s *loggingVmStateDb) GetCodeSize(addr common.Address) int {
+	start := time.Now()
 	res := s.db.GetCodeSize(addr)
-	s.writeLog("GetCodeSize, %v, %v", addr, res)
+	s.writeAccountLog("GetCodeSize", start, addr, res)
 	return res
 }
 
 func (s *loggingVmStateDb) GetCodeHash(addr common.Address) common.Hash {
+	start := time.Now()
 	res := s.db.GetCodeHash(addr)
-	s.writeLog("GetCodeHash, %v, %v", addr, res)
+	s.writeAccountLog("GetCodeHash", start, addr, res)
 	return res
 }
 
 func (s *loggingVmStateDb) SetCode(addr common.Address, code []byte, reason tracing.CodeChangeReason) []byte {
+	start := time.Now()
 	res := s.db.SetCode(addr, code, reason)
-	s.writeLog("SetCode, %v, %v, %v, %v", addr, code, res, reason)
+	s.writeAccountLog("SetCode", start, addr, code, res, reason)
 	return res
 }
 
 func (s *loggingVmStateDb) Snapshot() int {
+	start := time.Now()
 	res := s.db.Snapshot()
-	s.writeLog("Snapshot, %v", res)
+	s.writeLog("Snapshot", start, res)
 	return res
 }
 
 func (s *loggingVmStateDb) RevertToSnapshot(id int) {
+	start := time.Now()
 	s.db.RevertToSnapshot(id)
-	s.writeLog("RevertToSnapshot, %v", id)
+	s.writeLog("RevertToSnapshot", start, id)
 }
 
 func (s *loggingVmStateDb) BeginTransaction(tx uint32) error {
-	s.writeLog("BeginTransaction, %v", tx)
-	return s.db.BeginTransaction(tx)
+	s.tx = tx
+	start := time.Now()
+	err := s.db.BeginTransaction(tx)
+	s.writeLog("BeginTransaction", start, tx)
+	return err
 }
 
 func (s *loggingVmStateDb) EndTransaction() error {
-	s.writeLog("EndTransaction")
-	return s.db.EndTransaction()
+	start := time.Now()
+	err := s.db.EndTransaction()
+	s.writeLog("EndTransaction", start)
+	return err
 }
 
 func (s *loggingVmStateDb) Finalise(deleteEmptyObjects bool) {
-	s.writeLog("Finalise, %v", deleteEmptyObjects)
+	start := time.Now()
 	s.db.Finalise(deleteEmptyObjects)
+	s.writeLog("Finalise", start, deleteEmptyObjects)
 }
 
 func (s *loggingVmStateDb) AddRefund(amount uint64) {
+	start := time.Now()
 	s.db.AddRefund(amount)
-	s.writeLog("AddRefund, %v, %v", amount, s.db.GetRefund())
+	if s.isLogged("AddRefund") {
+		s.writeLog("AddRefund", start, amount, s.db.GetRefund())
+	}
 }
 
 func (s *loggingVmStateDb) SubRefund(amount uint64) {
+	start := time.Now()
 	s.db.SubRefund(amount)
-	s.writeLog("SubRefund, %v, %v", amount, s.db.GetRefund())
+	if s.isLogged("SubRefund") {
+		s.writeLog("SubRefund", start, amount, s.db.GetRefund())
+	}
 }
 
 func (s *loggingVmStateDb) GetRefund() uint64 {
+	start := time.Now()
 	res := s.db.GetRefund()
-	s.writeLog("GetRefund, %v", res)
+	s.writeLog("GetRefund", start, res)
 	return res
 }
 
 func (s *loggingVmStateDb) Prepare(rules params.Rules, sender, coinbase common.Address, dest *common.Address, precompiles []common.Address, txAccesses types.AccessList) {
-	s.writeLog("Prepare, %v, %v, %v, %v", sender, dest, precompiles, txAccesses)
+	start := time.Now()
 	s.db.Prepare(rules, sender, coinbase, dest, precompiles, txAccesses)
+	s.writeLog("Prepare", start, sender, dest, precompiles, txAccesses)
 }
 
 func (s *loggingVmStateDb) AddressInAccessList(addr common.Address) bool {
+	start := time.Now()
 	res := s.db.AddressInAccessList(addr)
-	s.writeLog("AddressInAccessList, %v, %v", addr, res)
+	s.writeAccountLog("AddressInAccessList", start, addr, res)
 	return res
 }
 
 func (s *loggingVmStateDb) SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool) {
+	start := time.Now()
 	a, b := s.db.SlotInAccessList(addr, slot)
-	s.writeLog("SlotInAccessList, %v, %v, %v, %v", addr, slot, a, b)
+	s.writeSlotLog("SlotInAccessList", start, addr, slot, a, b)
 	return a, b
 }
 
 func (s *loggingVmStateDb) AddAddressToAccessList(addr common.Address) {
-	s.writeLog("AddAddressToAccessList, %v", addr)
+	start := time.Now()
 	s.db.AddAddressToAccessList(addr)
+	s.writeAccountLog("AddAddressToAccessList", start, addr)
 }
 
 func (s *loggingVmStateDb) AddSlotToAccessList(addr common.Address, slot common.Hash) {
-	s.writeLog("AddSlotToAccessList, %v, %v", addr, slot)
+	start := time.Now()
 	s.db.AddSlotToAccessList(addr, slot)
+	s.writeSlotLog("AddSlotToAccessList", start, addr, slot)
 }
 
 func (s *loggingVmStateDb) AddLog(entry *types.Log) {
-	s.writeLog("AddLog, %v", entry)
+	start := time.Now()
 	s.db.AddLog(entry)
+	s.writeLog("AddLog", start, entry)
 }
 
 func (s *loggingVmStateDb) GetLogs(hash common.Hash, block uint64, blockHash common.Hash, blkTimestamp uint64) []*types.Log {
+	start := time.Now()
 	res := s.db.GetLogs(hash, block, blockHash, blkTimestamp)
-	s.writeLog("GetLogs, %v, %v, %v, %v, %v", hash, block, blockHash, blkTimestamp, res)
+	s.writeLog("GetLogs", start, hash, block, blockHash, blkTimestamp, res)
 	return res
 }
 
 func (s *loggingVmStateDb) EmitLogsForBurnAccounts() {
-	s.writeLog("EmitLogsForBurnAccounts")
+	start := time.Now()
 	s.db.EmitLogsForBurnAccounts()
+	s.writeLog("EmitLogsForBurnAccounts", start)
 }
 
 // Witness retrieves the current state witness.
 func (s *loggingVmStateDb) Witness() *stateless.Witness {
+	start := time.Now()
 	res := s.db.Witness()
-	s.writeLog("Witness, %v", res)
+	s.writeLog("Witness", start, res)
 	return res
 }
 
 func (s *loggingVmStateDb) SetTxContext(thash common.Hash, ti int) {
+	start := time.Now()
 	s.db.SetTxContext(thash, ti)
-	s.writeLog("SetTxContext, %v, %v", thash, ti)
+	s.writeLog("SetTxContext", start, thash, ti)
 }
 
 func (s *loggingVmStateDb) GetSubstatePostAlloc() txcontext.WorldState {
+	start := time.Now()
 	res := s.db.GetSubstatePostAlloc()
-	s.writeLog("GetSubstatePostAlloc, %v", res.String())
+	s.writeLog("GetSubstatePostAlloc", start, res)
 	return res
 }
 
 func (s *loggingVmStateDb) AddPreimage(hash common.Hash, data []byte) {
+	start := time.Now()
 	s.db.AddPreimage(hash, data)
-	s.writeLog("AddPreimage, %v, %v", hash, data)
+	s.writeLog("AddPreimage", start, hash, data)
 }
 
 func (s *loggingVmStateDb) AccessEvents() *geth.AccessEvents {
+	start := time.Now()
 	res := s.db.AccessEvents()
-	s.writeLog("AccessEvents, %v", res)
+	s.writeLog("AccessEvents", start, res)
 	return res
 }
 
 func (s *loggingVmStateDb) CreateContract(addr common.Address) {
-	s.writeLog("CreateContract, %v", addr)
+	start := time.Now()
 	s.db.CreateContract(addr)
+	s.writeAccountLog("CreateContract", start, addr)
 }
 
 func (s *loggingVmStateDb) GetStorageRoot(addr common.Address) common.Hash {
+	start := time.Now()
 	res := s.db.GetStorageRoot(addr)
-	s.writeLog("GetStorageRoot, %v, %v", res, addr)
+	s.writeAccountLog("GetStorageRoot", start, addr, res)
 	return res
 }
 
-func (s *loggingVmStateDb) writeLog(format string, a ...any) {
-	str := fmt.Sprintf(format, a...)
+// isLogged returns true if the operation passes the operation filter.
+func (s *loggingVmStateDb) isLogged(op string) bool {
+	if s.filter == nil {
+		return true
+	}
+	_, ok := s.filter[op]
+	return ok
+}
+
+// writeLog logs an operation not related to a particular account.
+// Args are the arguments and results of the operation.
+func (s *loggingVmStateDb) writeLog(op string, start time.Time, args ...any) {
+	if s.isLogged(op) {
+		s.write(op, start, nil, nil, args)
+	}
+}
+
+// writeAccountLog logs an operation accessing given account.
+func (s *loggingVmStateDb) writeAccountLog(op string, start time.Time, addr common.Address, args ...any) {
+	if s.isLogged(op) {
+		s.write(op, start, &addr, nil, args)
+	}
+}
+
+// writeSlotLog logs an operation accessing given storage slot.
+func (s *loggingVmStateDb) writeSlotLog(op string, start time.Time, addr common.Address, key common.Hash, args ...any) {
+	if s.isLogged(op) {
+		s.write(op, start, &addr, &key, args)
+	}
+}
+
+func (s *loggingVmStateDb) write(op string, start time.Time, addr *common.Address, key *common.Hash, args []any) {
+	elapsed := time.Since(start)
+	var str string
+	if s.json {
+		str = s.formatJson(op, elapsed, addr, key, args)
+	} else {
+		str = formatText(op, addr, key, args)
+	}
 	s.output <- str
 	s.log.Debug(str)
 }
 
+// formatText formats the operation as a comma-separated line, e.g. "GetState, <addr>, <key>, <value>".
+func formatText(op string, addr *common.Address, key *common.Hash, args []any) string {
+	var b strings.Builder
+	b.WriteString(op)
+	if addr != nil {
+		fmt.Fprintf(&b, ", %v", *addr)
+	}
+	if key != nil {
+		fmt.Fprintf(&b, ", %v", *key)
+	}
+	for _, arg := range args {
+		fmt.Fprintf(&b, ", %v", arg)
+	}
+	return b.String()
+}
+
+// formatJson formats the operation as a single-line JSON object. All arguments
+// besides the account and the storage key are joined into the value field.
+func (s *loggingVmStateDb) formatJson(op string, elapsed time.Duration, addr *common.Address, key *common.Hash, args []any) string {
+	values := make([]string, len(args))
+	for i, arg := range args {
+		values[i] = fmt.Sprintf("%v", arg)
+	}
+	entry := jsonLogEntry{
+		Op:      op,
+		Addr:    addr,
+		Key:     key,
+		Value:   strings.Join(values, ", "),
+		Block:   s.block,
+		Tx:      s.tx,
+		Elapsed: elapsed.Nanoseconds(),
+	}
+	res, err := json.Marshal(entry)
+	if err != nil {
+		// entries consist of strings and numbers only
+		panic(fmt.Sprintf("cannot encode db-logging entry; %v", err))
+	}
+	return string(res)
+}
+
 func (s *loggingNonCommittableStateDb) GetHash() (common.Hash, error) {
+	start := time.Now()
 	hash, err := s.nonCommittableStateDB.GetHash()
 	if err != nil {
-		s.writeLog("GetHash, %v", err)
+		s.writeLog("GetHash", start, err)
 		return common.Hash{}, err
 	} else {
-		s.writeLog("GetHash, %v", hash)
+		s.writeLog("GetHash", start, hash)
 	}
 	return hash, nil
 }
 
 func (s *loggingNonCommittableStateDb) Release() error {
-	s.writeLog("Release")
-	return s.nonCommittableStateDB.Release()
+	start := time.Now()
+	err := s.nonCommittableStateDB.Release()
+	s.writeLog("Release", start)
+	return err
 }
 
 func (l *loggingBulkLoad) CreateAccount(addr common.Address) {
+	start := time.Now()
 	l.nested.CreateAccount(addr)
-	l.writeLog("Bulk, CreateAccount, %v", addr)
+	l.db.writeAccountLog("BulkCreateAccount", start, addr)
 }
+
 func (l *loggingBulkLoad) SetBalance(addr common.Address, balance *uint256.Int) {
+	start := time.Now()
 	l.nested.SetBalance(addr, balance)
-	l.writeLog("Bulk, SetBalance, %v, %v", addr, balance)
+	l.db.writeAccountLog("BulkSetBalance", start, addr, balance)
 }
 
 func (l *loggingBulkLoad) SetNonce(addr common.Address, nonce uint64) {
+	start := time.Now()
 	l.nested.SetNonce(addr, nonce)
-	l.writeLog("Bulk, SetNonce, %v, %v", addr, nonce)
+	l.db.writeAccountLog("BulkSetNonce", start, addr, nonce)
 }
 
 func (l *loggingBulkLoad) SetState(addr common.Address, key common.Hash, value common.Hash) {
+	start := time.Now()
 	l.nested.SetState(addr, key, value)
-	l.writeLog("Bulk, SetState, %v, %v, %v", addr, key, value)
+	l.db.writeSlotLog("BulkSetState", start, addr, key, value)
 }
 
 func (l *loggingBulkLoad) SetCode(addr common.Address, code []byte) {
+	start := time.Now()
 	l.nested.SetCode(addr, code)
-	l.writeLog("Bulk, SetCode, %v, %v", addr, code)
+	l.db.writeAccountLog("BulkSetCode", start, addr, code)
 }
 
 func (l *loggingBulkLoad) Close() error {
+	start := time.Now()
 	res := l.nested.Close()
-	l.writeLog("Bulk, Close, %v", res)
+	l.db.writeLog("BulkClose", start, res)
 	return res
 }
@@ -17,7 +17,9 @@
 package proxy
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"sync"
 	"testing"
 
@@ -32,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -469,9 +472,7 @@ func TestLoggingBulkLoad_CreateAccount(t *testing.T) {
 	mockDb := state.NewMockBulkLoad(ctrl)
 	proxy := &loggingBulkLoad{
 		nested: mockDb,
-		writeLog: func(format string, a ...any) {
-
-		},
+		db:     &loggingVmStateDb{log: logger.NewLogger("critical", "test"), output: make(chan string, 1)},
 	}
 	addr := common.HexToAddress("0x1234")
 	mockDb.EXPECT().CreateAccount(addr)
@@ -485,9 +486,7 @@ func TestLoggingBulkLoad_SetBalance(t *testing.T) {
 	mockDb := state.NewMockBulkLoad(ctrl)
 	proxy := &loggingBulkLoad{
 		nested: mockDb,
-		writeLog: func(format string, a ...any) {
-
-		},
+		db:     &loggingVmStateDb{log: logger.NewLogger("critical", "test"), output: make(chan string, 1)},
 	}
 	addr := common.HexToAddress("0x1234")
 	value := uint256.NewInt(100)
@@ -502,9 +501,7 @@ func TestLoggingBulkLoad_SetNonce(t *testing.T) {
 	mockDb := state.NewMockBulkLoad(ctrl)
 	proxy := &loggingBulkLoad{
 		nested: mockDb,
-		writeLog: func(format string, a ...any) {
-
-		},
+		db:     &loggingVmStateDb{log: logger.NewLogger("critical", "test"), output: make(chan string, 1)},
 	}
 	addr := common.HexToAddress("0x1234")
 	nonce := uint64(42)
@@ -519,9 +516,7 @@ func TestLoggingBulkLoad_SetState(t *testing.T) {
 	mockDb := state.NewMockBulkLoad(ctrl)
 	proxy := &loggingBulkLoad{
 		nested: mockDb,
-		writeLog: func(format string, a ...any) {
-
-		},
+		db:     &loggingVmStateDb{log: logger.NewLogger("critical", "test"), output: make(chan string, 1)},
 	}
 	addr := common.HexToAddress("0x1234")
 	key := common.HexToHash("0x5678")
@@ -537,9 +532,7 @@ func TestLoggingBulkLoad_SetCode(t *testing.T) {
 	mockDb := state.NewMockBulkLoad(ctrl)
 	proxy := &loggingBulkLoad{
 		nested: mockDb,
-		writeLog: func(format string, a ...any) {
-
-		},
+		db:     &loggingVmStateDb{log: logger.NewLogger("critical", "test"), output: make(chan string, 1)},
 	}
 	addr := common.HexToAddress("0x1234")
 	code := []byte{0x01, 0x02}
@@ -554,9 +547,7 @@ func TestLoggingBulkLoad_Close(t *testing.T) {
 	mockDb := state.NewMockBulkLoad(ctrl)
 	proxy := &loggingBulkLoad{
 		nested: mockDb,
-		writeLog: func(format string, a ...any) {
-
-		},
+		db:     &loggingVmStateDb{log: logger.NewLogger("critical", "test"), output: make(chan string, 1)},
 	}
 	mockDb.EXPECT().Close().Return(nil)
 
@@ -1480,3 +1471,140 @@ func TestLoggingVmStateDb_GetStorageRoot(t *testing.T) {
 	res := proxy.GetStorageRoot(addr)
 	assert.Equal(t, expected, res)
 }
+
+// runLoggedOperations issues a known sequence of operations on a logging proxy
+// created with given options and returns the logged lines.
+func runLoggedOperations(t *testing.T, opts ...LoggerOption) []string {
+	ctrl := gomock.NewController(t)
+	mockDb := state.NewMockStateDB(ctrl)
+	output := make(chan string, 100)
+	proxy := NewLoggerProxy(mockDb, logger.NewLogger("critical", "test"), output, &sync.WaitGroup{}, opts...)
+
+	addr := common.Address{1}
+	key := common.Hash{2}
+	value := common.Hash{3}
+	gomock.InOrder(
+		mockDb.EXPECT().BeginBlock(uint64(7)),
+		mockDb.EXPECT().BeginTransaction(uint32(2)),
+		mockDb.EXPECT().GetNonce(addr).Return(uint64(5)),
+		mockDb.EXPECT().GetState(addr, key).Return(common.Hash{}),
+		mockDb.EXPECT().SetState(addr, key, value).Return(common.Hash{}),
+		mockDb.EXPECT().EndTransaction(),
+	)
+	require.NoError(t, proxy.BeginBlock(7))
+	require.NoError(t, proxy.BeginTransaction(2))
+	proxy.GetNonce(addr)
+	proxy.GetState(addr, key)
+	proxy.SetState(addr, key, value)
+	require.NoError(t, proxy.EndTransaction())
+
+	close(output)
+	var lines []string
+	for line := range output {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestLoggingStateDb_TextFormatIsUsedByDefault(t *testing.T) {
+	lines := runLoggedOperations(t)
+	require.Len(t, lines, 6)
+	assert.Equal(t, "BeginBlock, 7", lines[0])
+	assert.Equal(t, fmt.Sprintf("GetNonce, %v, 5", common.Address{1}), lines[2])
+	assert.Equal(t, fmt.Sprintf("SetState, %v, %v, %v, %v", common.Address{1}, common.Hash{2}, common.Hash{3}, common.Hash{}), lines[4])
+	assert.Equal(t, "EndTransaction", lines[5])
+}
+
+func TestLoggingStateDb_JsonFormatWritesOneObjectPerOperation(t *testing.T) {
+	lines := runLoggedOperations(t, WithLoggingFormat(JsonLoggingFormat))
+	require.Len(t, lines, 6)
+
+	wantOps := []string{"BeginBlock", "BeginTransaction", "GetNonce", "GetState", "SetState", "EndTransaction"}
+	for i, line := range lines {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		assert.Equal(t, wantOps[i], entry["op"])
+		for _, field := range []string{"value", "block", "tx", "elapsed"} {
+			assert.Contains(t, entry, field, line)
+		}
+		assert.GreaterOrEqual(t, entry["elapsed"], float64(0))
+	}
+
+	var setState jsonLogEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[4]), &setState))
+	require.NotNil(t, setState.Addr)
+	require.NotNil(t, setState.Key)
+	assert.Equal(t, common.Address{1}, *setState.Addr)
+	assert.Equal(t, common.Hash{2}, *setState.Key)
+	assert.Equal(t, fmt.Sprintf("%v, %v", common.Hash{3}, common.Hash{}), setState.Value)
+	assert.Equal(t, uint64(7), setState.Block)
+	assert.Equal(t, uint32(2), setState.Tx)
+
+	var getNonce map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &getNonce))
+	assert.Equal(t, "5", getNonce["value"])
+	assert.NotContains(t, getNonce, "key")
+
+	var endTx map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[5]), &endTx))
+	assert.NotContains(t, endTx, "addr")
+	assert.Equal(t, "", endTx["value"])
+}
+
+func TestLoggingStateDb_OperationFilterRestrictsLoggedOperations(t *testing.T) {
+	lines := runLoggedOperations(t, WithLoggingFormat(JsonLoggingFormat), WithOperationFilter("GetState", "SetState"))
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"op":"GetState"`)
+	assert.Contains(t, lines[1], `"op":"SetState"`)
+
+	// block and transaction are tracked even if their operations are filtered
+	var entry jsonLogEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, uint64(7), entry.Block)
+	assert.Equal(t, uint32(2), entry.Tx)
+}
+
+func TestLoggingStateDb_EmptyOperationFilterLogsAllOperations(t *testing.T) {
+	lines := runLoggedOperations(t, WithOperationFilter(ParseOperationFilter("")...))
+	assert.Len(t, lines, 6)
+}
+
+func TestLoggingStateDb_FilteredOperationsAreNotFormatted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDb := state.NewMockStateDB(ctrl)
+	output := make(chan string, 1)
+	proxy := NewLoggerProxy(mockDb, logger.NewLogger("critical", "test"), output, &sync.WaitGroup{}, WithOperationFilter("GetState"))
+
+	// the balance is only read to format a logged AddBalance
+	mockDb.EXPECT().AddBalance(common.Address{1}, uint256.NewInt(1), tracing.BalanceChangeUnspecified).Return(uint256.Int{})
+	proxy.AddBalance(common.Address{1}, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+	assert.Empty(t, output)
+}
+
+func TestParseLoggingFormat(t *testing.T) {
+	tests := map[string]struct {
+		want    LoggingFormat
+		wantErr bool
+	}{
+		"":     {want: TextLoggingFormat},
+		"text": {want: TextLoggingFormat},
+		"json": {want: JsonLoggingFormat},
+		"xml":  {wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseLoggingFormat(name)
+			if test.wantErr {
+				require.ErrorContains(t, err, "unknown db-logging format")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestParseOperationFilter(t *testing.T) {
+	assert.Nil(t, ParseOperationFilter(""))
+	assert.Equal(t, []string{"GetState", "SetState"}, ParseOperationFilter("GetState, SetState,"))
+}
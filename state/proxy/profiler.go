@@ -480,6 +480,10 @@ func (p *ProfilerProxy) GetMemoryUsage() *state.MemoryUsage {
 	return p.db.GetMemoryUsage()
 }
 
+func (p *ProfilerProxy) CreateCheckpoint() error {
+	return p.db.CreateCheckpoint()
+}
+
 func (p *ProfilerProxy) GetShadowDB() state.StateDB {
 	return p.db.GetShadowDB()
 }
@@ -0,0 +1,484 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package proxy
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/ethereum/go-ethereum/common"
+	geth "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// remapRounds is the number of Feistel rounds of the keyed permutation.
+const remapRounds = 4
+
+// Remapper is a keyed permutation of addresses and, optionally, storage keys. It is
+// implemented as a balanced Feistel network using AES as round function, hence it is
+// a bijection which distributes clustered inputs uniformly over the output space and
+// can be inverted given the same key.
+type Remapper struct {
+	rounds      [remapRounds]cipher.Block
+	storageKeys bool
+}
+
+// NewRemapper creates a permutation derived from given key. Storage keys are only
+// remapped if storageKeys is set.
+func NewRemapper(key string, storageKeys bool) *Remapper {
+	r := &Remapper{storageKeys: storageKeys}
+	for i := range r.rounds {
+		roundKey := sha256.Sum256(binary.BigEndian.AppendUint32([]byte(key), uint32(i)))
+		// the key has a valid length, hence the cipher cannot fail
+		r.rounds[i], _ = aes.NewCipher(roundKey[:])
+	}
+	return r
+}
+
+// Address returns the remapped address.
+func (r *Remapper) Address(addr common.Address) common.Address {
+	var res common.Address
+	r.permute(res[:], addr[:], false)
+	return res
+}
+
+// InverseAddress returns the original address of a remapped address.
+func (r *Remapper) InverseAddress(addr common.Address) common.Address {
+	var res common.Address
+	r.permute(res[:], addr[:], true)
+	return res
+}
+
+// Key returns the remapped storage key.
+func (r *Remapper) Key(key common.Hash) common.Hash {
+	if !r.storageKeys {
+		return key
+	}
+	var res common.Hash
+	r.permute(res[:], key[:], false)
+	return res
+}
+
+// InverseKey returns the original storage key of a remapped storage key.
+func (r *Remapper) InverseKey(key common.Hash) common.Hash {
+	if !r.storageKeys {
+		return key
+	}
+	var res common.Hash
+	r.permute(res[:], key[:], true)
+	return res
+}
+
+// permute runs the Feistel network on src, which has an even length of at most 32 bytes.
+func (r *Remapper) permute(dst, src []byte, inverse bool) {
+	half := len(src) / 2
+	left, right := make([]byte, half), make([]byte, half)
+	copy(left, src[:half])
+	copy(right, src[half:])
+
+	var in, out [aes.BlockSize]byte
+	round := func(i int, from, to []byte) {
+		in = [aes.BlockSize]byte{}
+		copy(in[:], from)
+		r.rounds[i].Encrypt(out[:], in[:])
+		for j := range to {
+			to[j] ^= out[j]
+		}
+	}
+	if inverse {
+		for i := remapRounds - 1; i >= 0; i-- {
+			left, right = right, left
+			round(i, right, left)
+		}
+	} else {
+		for i := 0; i < remapRounds; i++ {
+			round(i, right, left)
+			left, right = right, left
+		}
+	}
+	copy(dst[:half], left)
+	copy(dst[half:], right)
+}
+
+// worldState applies given address and key mapping to all accounts of ws.
+func (r *Remapper) worldState(ws txcontext.WorldState, addr func(common.Address) common.Address, key func(common.Hash) common.Hash) txcontext.WorldState {
+	if ws == nil {
+		return nil
+	}
+	res := make(map[common.Address]txcontext.Account, ws.Len())
+	ws.ForEachAccount(func(a common.Address, acc txcontext.Account) {
+		storage := make(map[common.Hash]common.Hash)
+		acc.ForEachStorage(func(k common.Hash, v common.Hash) {
+			storage[key(k)] = v
+		})
+		res[addr(a)] = txcontext.NewAccount(acc.GetCode(), storage, acc.GetBalance().ToBig(), acc.GetNonce())
+	})
+	return txcontext.NewWorldState(res)
+}
+
+// NewRemapProxy creates a StateDB proxy which remaps every address, and optionally
+// storage key, passed to the underlying StateDB with given remapper. Addresses and
+// keys returned by the StateDB are mapped back, hence the execution semantics are
+// unchanged while the underlying StateDB observes a different state layout. Logs are
+// passed through unchanged since they are not part of the state.
+func NewRemapProxy(db state.StateDB, remapper *Remapper) *RemapProxy {
+	return &RemapProxy{
+		remappingVmStateDb: remappingVmStateDb{db: db, r: remapper},
+		db:                 db,
+	}
+}
+
+// RemapProxy is a StateDB proxy applying a keyed permutation to the state layout.
+type RemapProxy struct {
+	remappingVmStateDb
+	db state.StateDB
+}
+
+func (p *RemapProxy) BeginBlock(number uint64) error {
+	return p.db.BeginBlock(number)
+}
+
+func (p *RemapProxy) EndBlock() error {
+	return p.db.EndBlock()
+}
+
+func (p *RemapProxy) BeginSyncPeriod(number uint64) {
+	p.db.BeginSyncPeriod(number)
+}
+
+func (p *RemapProxy) EndSyncPeriod() {
+	p.db.EndSyncPeriod()
+}
+
+func (p *RemapProxy) GetHash() (common.Hash, error) {
+	return p.db.GetHash()
+}
+
+func (p *RemapProxy) Error() error {
+	return p.db.Error()
+}
+
+func (p *RemapProxy) Close() error {
+	return p.db.Close()
+}
+
+// StartBulkLoad creates a bulk load remapping all inserted addresses and storage keys.
+func (p *RemapProxy) StartBulkLoad(block uint64) (state.BulkLoad, error) {
+	bulk, err := p.db.StartBulkLoad(block)
+	if err != nil {
+		return nil, err
+	}
+	return &remappingBulkLoad{bulk: bulk, r: p.r}, nil
+}
+
+// GetArchiveState returns a view of the archive applying the same remapping.
+func (p *RemapProxy) GetArchiveState(block uint64) (state.NonCommittableStateDB, error) {
+	archive, err := p.db.GetArchiveState(block)
+	if err != nil {
+		return nil, err
+	}
+	return &remappingArchive{remappingVmStateDb: remappingVmStateDb{db: archive, r: p.r}, archive: archive}, nil
+}
+
+func (p *RemapProxy) GetArchiveBlockHeight() (uint64, bool, error) {
+	return p.db.GetArchiveBlockHeight()
+}
+
+func (p *RemapProxy) GetMemoryUsage() *state.MemoryUsage {
+	return p.db.GetMemoryUsage()
+}
+
+func (p *RemapProxy) CreateCheckpoint() error {
+	return p.db.CreateCheckpoint()
+}
+
+func (p *RemapProxy) IntermediateRoot(deleteEmptyObjects bool) common.Hash {
+	return p.db.IntermediateRoot(deleteEmptyObjects)
+}
+
+func (p *RemapProxy) Commit(block uint64, deleteEmptyObjects bool) (common.Hash, error) {
+	return p.db.Commit(block, deleteEmptyObjects)
+}
+
+// PrepareSubstate remaps the given world state before passing it to the StateDB.
+func (p *RemapProxy) PrepareSubstate(substate txcontext.WorldState, block uint64) {
+	p.db.PrepareSubstate(p.r.worldState(substate, p.r.Address, p.r.Key), block)
+}
+
+// GetShadowDB returns the shadow DB applying the same remapping, if there is one.
+func (p *RemapProxy) GetShadowDB() state.StateDB {
+	shadow := p.db.GetShadowDB()
+	if shadow == nil {
+		return nil
+	}
+	return NewRemapProxy(shadow, p.r)
+}
+
+// remappingArchive is an archive view applying the remapping of its RemapProxy.
+type remappingArchive struct {
+	remappingVmStateDb
+	archive state.NonCommittableStateDB
+}
+
+func (a *remappingArchive) GetHash() (common.Hash, error) {
+	return a.archive.GetHash()
+}
+
+func (a *remappingArchive) Release() error {
+	return a.archive.Release()
+}
+
+// remappingBulkLoad is a bulk load applying the remapping of its RemapProxy.
+type remappingBulkLoad struct {
+	bulk state.BulkLoad
+	r    *Remapper
+}
+
+func (b *remappingBulkLoad) CreateAccount(addr common.Address) {
+	b.bulk.CreateAccount(b.r.Address(addr))
+}
+
+func (b *remappingBulkLoad) SetBalance(addr common.Address, value *uint256.Int) {
+	b.bulk.SetBalance(b.r.Address(addr), value)
+}
+
+func (b *remappingBulkLoad) SetNonce(addr common.Address, nonce uint64) {
+	b.bulk.SetNonce(b.r.Address(addr), nonce)
+}
+
+func (b *remappingBulkLoad) SetState(addr common.Address, key common.Hash, value common.Hash) {
+	b.bulk.SetState(b.r.Address(addr), b.r.Key(key), value)
+}
+
+func (b *remappingBulkLoad) SetCode(addr common.Address, code []byte) {
+	b.bulk.SetCode(b.r.Address(addr), code)
+}
+
+func (b *remappingBulkLoad) Close() error {
+	return b.bulk.Close()
+}
+
+// remappingVmStateDb implements the VmStateDB interface on top of a remapped StateDB.
+type remappingVmStateDb struct {
+	db state.VmStateDB
+	r  *Remapper
+}
+
+func (p *remappingVmStateDb) CreateAccount(addr common.Address) {
+	p.db.CreateAccount(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) CreateContract(addr common.Address) {
+	p.db.CreateContract(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) IsNewContract(addr common.Address) bool {
+	return p.db.IsNewContract(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) Exist(addr common.Address) bool {
+	return p.db.Exist(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) Empty(addr common.Address) bool {
+	return p.db.Empty(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) SelfDestruct(addr common.Address) {
+	p.db.SelfDestruct(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) HasSelfDestructed(addr common.Address) bool {
+	return p.db.HasSelfDestructed(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) GetBalance(addr common.Address) *uint256.Int {
+	return p.db.GetBalance(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) AddBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	return p.db.AddBalance(p.r.Address(addr), amount, reason)
+}
+
+func (p *remappingVmStateDb) SubBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	return p.db.SubBalance(p.r.Address(addr), amount, reason)
+}
+
+func (p *remappingVmStateDb) GetNonce(addr common.Address) uint64 {
+	return p.db.GetNonce(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) SetNonce(addr common.Address, nonce uint64, reason tracing.NonceChangeReason) {
+	p.db.SetNonce(p.r.Address(addr), nonce, reason)
+}
+
+func (p *remappingVmStateDb) GetCommittedState(addr common.Address, key common.Hash) common.Hash {
+	return p.db.GetCommittedState(p.r.Address(addr), p.r.Key(key))
+}
+
+func (p *remappingVmStateDb) GetState(addr common.Address, key common.Hash) common.Hash {
+	return p.db.GetState(p.r.Address(addr), p.r.Key(key))
+}
+
+func (p *remappingVmStateDb) SetState(addr common.Address, key common.Hash, value common.Hash) common.Hash {
+	return p.db.SetState(p.r.Address(addr), p.r.Key(key), value)
+}
+
+func (p *remappingVmStateDb) GetStorageRoot(addr common.Address) common.Hash {
+	return p.db.GetStorageRoot(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) GetStateAndCommittedState(addr common.Address, key common.Hash) (common.Hash, common.Hash) {
+	return p.db.GetStateAndCommittedState(p.r.Address(addr), p.r.Key(key))
+}
+
+func (p *remappingVmStateDb) SetTransientState(addr common.Address, key common.Hash, value common.Hash) {
+	p.db.SetTransientState(p.r.Address(addr), p.r.Key(key), value)
+}
+
+func (p *remappingVmStateDb) GetTransientState(addr common.Address, key common.Hash) common.Hash {
+	return p.db.GetTransientState(p.r.Address(addr), p.r.Key(key))
+}
+
+func (p *remappingVmStateDb) GetCodeHash(addr common.Address) common.Hash {
+	return p.db.GetCodeHash(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) GetCode(addr common.Address) []byte {
+	return p.db.GetCode(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) SetCode(addr common.Address, code []byte, reason tracing.CodeChangeReason) []byte {
+	return p.db.SetCode(p.r.Address(addr), code, reason)
+}
+
+func (p *remappingVmStateDb) GetCodeSize(addr common.Address) int {
+	return p.db.GetCodeSize(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) AddRefund(gas uint64) {
+	p.db.AddRefund(gas)
+}
+
+func (p *remappingVmStateDb) SubRefund(gas uint64) {
+	p.db.SubRefund(gas)
+}
+
+func (p *remappingVmStateDb) GetRefund() uint64 {
+	return p.db.GetRefund()
+}
+
+// Prepare remaps all addresses and storage keys of the access list.
+func (p *remappingVmStateDb) Prepare(rules params.Rules, sender, coinbase common.Address, dest *common.Address, precompiles []common.Address, txAccesses types.AccessList) {
+	if dest != nil {
+		remapped := p.r.Address(*dest)
+		dest = &remapped
+	}
+	remappedPrecompiles := make([]common.Address, len(precompiles))
+	for i, addr := range precompiles {
+		remappedPrecompiles[i] = p.r.Address(addr)
+	}
+	var remappedAccesses types.AccessList
+	if txAccesses != nil {
+		remappedAccesses = make(types.AccessList, len(txAccesses))
+		for i, tuple := range txAccesses {
+			keys := make([]common.Hash, len(tuple.StorageKeys))
+			for j, key := range tuple.StorageKeys {
+				keys[j] = p.r.Key(key)
+			}
+			remappedAccesses[i] = types.AccessTuple{Address: p.r.Address(tuple.Address), StorageKeys: keys}
+		}
+	}
+	p.db.Prepare(rules, p.r.Address(sender), p.r.Address(coinbase), dest, remappedPrecompiles, remappedAccesses)
+}
+
+func (p *remappingVmStateDb) AddressInAccessList(addr common.Address) bool {
+	return p.db.AddressInAccessList(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) SlotInAccessList(addr common.Address, slot common.Hash) (bool, bool) {
+	return p.db.SlotInAccessList(p.r.Address(addr), p.r.Key(slot))
+}
+
+func (p *remappingVmStateDb) AddAddressToAccessList(addr common.Address) {
+	p.db.AddAddressToAccessList(p.r.Address(addr))
+}
+
+func (p *remappingVmStateDb) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	p.db.AddSlotToAccessList(p.r.Address(addr), p.r.Key(slot))
+}
+
+func (p *remappingVmStateDb) AddLog(log *types.Log) {
+	p.db.AddLog(log)
+}
+
+func (p *remappingVmStateDb) GetLogs(hash common.Hash, block uint64, blockHash common.Hash, blkTimestamp uint64) []*types.Log {
+	return p.db.GetLogs(hash, block, blockHash, blkTimestamp)
+}
+
+func (p *remappingVmStateDb) EmitLogsForBurnAccounts() {
+	p.db.EmitLogsForBurnAccounts()
+}
+
+func (p *remappingVmStateDb) Witness() *stateless.Witness {
+	return p.db.Witness()
+}
+
+func (p *remappingVmStateDb) SetTxContext(thash common.Hash, ti int) {
+	p.db.SetTxContext(thash, ti)
+}
+
+func (p *remappingVmStateDb) Snapshot() int {
+	return p.db.Snapshot()
+}
+
+func (p *remappingVmStateDb) RevertToSnapshot(snapshot int) {
+	p.db.RevertToSnapshot(snapshot)
+}
+
+func (p *remappingVmStateDb) BeginTransaction(number uint32) error {
+	return p.db.BeginTransaction(number)
+}
+
+func (p *remappingVmStateDb) EndTransaction() error {
+	return p.db.EndTransaction()
+}
+
+func (p *remappingVmStateDb) Finalise(deleteEmptyObjects bool) {
+	p.db.Finalise(deleteEmptyObjects)
+}
+
+func (p *remappingVmStateDb) AddPreimage(hash common.Hash, image []byte) {
+	p.db.AddPreimage(hash, image)
+}
+
+func (p *remappingVmStateDb) AccessEvents() *geth.AccessEvents {
+	return p.db.AccessEvents()
+}
+
+// GetSubstatePostAlloc maps the post-alloc of the StateDB back to the original addresses.
+func (p *remappingVmStateDb) GetSubstatePostAlloc() txcontext.WorldState {
+	return p.r.worldState(p.db.GetSubstatePostAlloc(), p.r.InverseAddress, p.r.InverseKey)
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package proxy
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRemapper_IsInvertiblePermutation(t *testing.T) {
+	r := NewRemapper("key", true)
+	seen := make(map[common.Address]struct{})
+	for i := 0; i < 1000; i++ {
+		addr := common.BigToAddress(big.NewInt(int64(i)))
+		remapped := r.Address(addr)
+		assert.NotEqual(t, addr, remapped)
+		assert.Equal(t, addr, r.InverseAddress(remapped))
+		seen[remapped] = struct{}{}
+
+		key := common.BigToHash(big.NewInt(int64(i)))
+		assert.NotEqual(t, key, r.Key(key))
+		assert.Equal(t, key, r.InverseKey(r.Key(key)))
+	}
+	assert.Len(t, seen, 1000)
+}
+
+func TestRemapper_DependsOnKey(t *testing.T) {
+	addr := common.Address{1}
+	assert.Equal(t, NewRemapper("a", false).Address(addr), NewRemapper("a", false).Address(addr))
+	assert.NotEqual(t, NewRemapper("a", false).Address(addr), NewRemapper("b", false).Address(addr))
+}
+
+func TestRemapper_KeepsStorageKeysIfNotEnabled(t *testing.T) {
+	r := NewRemapper("key", false)
+	key := common.Hash{1}
+	assert.Equal(t, key, r.Key(key))
+	assert.Equal(t, key, r.InverseKey(key))
+}
+
+func TestRemapProxy_RemapsAccountAndStorageAccesses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	r := NewRemapper("key", true)
+	p := NewRemapProxy(db, r)
+
+	addr, key, value := common.Address{1}, common.Hash{2}, common.Hash{3}
+	gomock.InOrder(
+		db.EXPECT().CreateAccount(r.Address(addr)),
+		db.EXPECT().AddBalance(r.Address(addr), uint256.NewInt(5), tracing.BalanceChangeUnspecified),
+		db.EXPECT().GetBalance(r.Address(addr)).Return(uint256.NewInt(5)),
+		db.EXPECT().SetState(r.Address(addr), r.Key(key), value),
+		db.EXPECT().GetState(r.Address(addr), r.Key(key)).Return(value),
+		db.EXPECT().SlotInAccessList(r.Address(addr), r.Key(key)).Return(true, true),
+	)
+
+	p.CreateAccount(addr)
+	p.AddBalance(addr, uint256.NewInt(5), tracing.BalanceChangeUnspecified)
+	assert.Equal(t, uint256.NewInt(5), p.GetBalance(addr))
+	p.SetState(addr, key, value)
+	assert.Equal(t, value, p.GetState(addr, key))
+	addressOk, slotOk := p.SlotInAccessList(addr, key)
+	assert.True(t, addressOk)
+	assert.True(t, slotOk)
+}
+
+func TestRemapProxy_PrepareRemapsAccessList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	r := NewRemapper("key", true)
+	p := NewRemapProxy(db, r)
+
+	sender, coinbase, dest, precompile := common.Address{1}, common.Address{2}, common.Address{3}, common.Address{4}
+	remappedDest := r.Address(dest)
+	db.EXPECT().Prepare(params.Rules{}, r.Address(sender), r.Address(coinbase), &remappedDest, []common.Address{r.Address(precompile)},
+		types.AccessList{{Address: r.Address(dest), StorageKeys: []common.Hash{r.Key(common.Hash{5})}}})
+
+	p.Prepare(params.Rules{}, sender, coinbase, &dest, []common.Address{precompile},
+		types.AccessList{{Address: dest, StorageKeys: []common.Hash{{5}}}})
+}
+
+func TestRemapProxy_BulkLoadIsRemapped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	bulk := state.NewMockBulkLoad(ctrl)
+	r := NewRemapper("key", true)
+	p := NewRemapProxy(db, r)
+
+	addr, key, value := common.Address{1}, common.Hash{2}, common.Hash{3}
+	gomock.InOrder(
+		db.EXPECT().StartBulkLoad(uint64(7)).Return(bulk, nil),
+		bulk.EXPECT().CreateAccount(r.Address(addr)),
+		bulk.EXPECT().SetBalance(r.Address(addr), uint256.NewInt(1)),
+		bulk.EXPECT().SetNonce(r.Address(addr), uint64(2)),
+		bulk.EXPECT().SetState(r.Address(addr), r.Key(key), value),
+		bulk.EXPECT().SetCode(r.Address(addr), []byte{1}),
+		bulk.EXPECT().Close(),
+	)
+
+	load, err := p.StartBulkLoad(7)
+	require.NoError(t, err)
+	load.CreateAccount(addr)
+	load.SetBalance(addr, uint256.NewInt(1))
+	load.SetNonce(addr, 2)
+	load.SetState(addr, key, value)
+	load.SetCode(addr, []byte{1})
+	require.NoError(t, load.Close())
+}
+
+func TestRemapProxy_WorldStatesAreMappedBothWays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	r := NewRemapper("key", true)
+	p := NewRemapProxy(db, r)
+
+	addr, key, value := common.Address{1}, common.Hash{2}, common.Hash{3}
+	original := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		addr: txcontext.NewAccount([]byte{1}, map[common.Hash]common.Hash{key: value}, big.NewInt(4), 5),
+	})
+	remapped := txcontext.NewWorldState(map[common.Address]txcontext.Account{
+		r.Address(addr): txcontext.NewAccount([]byte{1}, map[common.Hash]common.Hash{r.Key(key): value}, big.NewInt(4), 5),
+	})
+
+	db.EXPECT().PrepareSubstate(gomock.Any(), uint64(1)).Do(func(ws txcontext.WorldState, _ uint64) {
+		assert.True(t, remapped.Equal(ws))
+	})
+	db.EXPECT().GetSubstatePostAlloc().Return(remapped)
+
+	p.PrepareSubstate(original, 1)
+	assert.True(t, original.Equal(p.GetSubstatePostAlloc()))
+}
+
+func TestRemapProxy_ArchiveStateIsRemapped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+	r := NewRemapper("key", false)
+	p := NewRemapProxy(db, r)
+
+	addr := common.Address{1}
+	db.EXPECT().GetArchiveState(uint64(3)).Return(archive, nil)
+	archive.EXPECT().GetNonce(r.Address(addr)).Return(uint64(9))
+	archive.EXPECT().Release()
+
+	view, err := p.GetArchiveState(3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), view.GetNonce(addr))
+	require.NoError(t, view.Release())
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package proxy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xsoniclabs/aida/state"
+)
+
+// ErrArchiveBlockPruned is returned for archive queries of blocks dropped by an archive retention window.
+var ErrArchiveBlockPruned = errors.New("archive block not found")
+
+// NewRetentionProxy creates a StateDB proxy which rejects archive queries of blocks
+// before the given first retained block. A shrunk archive still physically holds the
+// primed state below its retention boundary, which does not represent any real block.
+func NewRetentionProxy(db state.StateDB, firstBlock uint64) *RetentionProxy {
+	return &RetentionProxy{
+		StateDB:    db,
+		firstBlock: firstBlock,
+	}
+}
+
+// RetentionProxy hides archive blocks below the retention boundary of a shrunk archive.
+type RetentionProxy struct {
+	state.StateDB
+	firstBlock uint64 // first block retained in the archive
+}
+
+// GetArchiveState returns the archive state of given block, blocks before the retention boundary are not found.
+func (p *RetentionProxy) GetArchiveState(block uint64) (state.NonCommittableStateDB, error) {
+	if block < p.firstBlock {
+		return nil, fmt.Errorf("%w; block %d was pruned, the archive retains blocks from %d", ErrArchiveBlockPruned, block, p.firstBlock)
+	}
+	return p.StateDB.GetArchiveState(block)
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package proxy
+
+import (
+	"testing"
+
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRetentionProxy_PrunedBlocksAreNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	p := NewRetentionProxy(db, 10)
+
+	for _, block := range []uint64{0, 1, 9} {
+		_, err := p.GetArchiveState(block)
+		require.ErrorIs(t, err, ErrArchiveBlockPruned)
+	}
+}
+
+func TestRetentionProxy_RetainedBlocksAreDelegated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	archive := state.NewMockNonCommittableStateDB(ctrl)
+	p := NewRetentionProxy(db, 10)
+
+	gomock.InOrder(
+		db.EXPECT().GetArchiveState(uint64(10)).Return(archive, nil),
+		db.EXPECT().GetArchiveState(uint64(11)).Return(archive, nil),
+		db.EXPECT().GetArchiveBlockHeight().Return(uint64(11), false, nil),
+	)
+
+	for _, block := range []uint64{10, 11} {
+		view, err := p.GetArchiveState(block)
+		require.NoError(t, err)
+		assert.Equal(t, archive, view)
+	}
+	height, empty, err := p.GetArchiveBlockHeight()
+	require.NoError(t, err)
+	assert.False(t, empty)
+	assert.Equal(t, uint64(11), height)
+}
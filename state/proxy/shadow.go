@@ -34,11 +34,52 @@ import (
 	"github.com/holiman/uint256"
 )
 
+// ShadowOption configures optional features of the shadow proxy.
+type ShadowOption func(*shadowVmStateDb)
+
+// WithOperationHistory keeps the last size operations performed on the shadow proxy. Once
+// a divergence is detected, the history is logged and attached to the reported error
+// together with the current block and transaction. A size of 0 disables the recording.
+func WithOperationHistory(size int) ShadowOption {
+	return func(s *shadowVmStateDb) {
+		s.history = newOperationHistory(size)
+	}
+}
+
+// WithConcurrentReads runs side-effect-free read operations, e.g. GetBalance or GetState, on the
+// prime and the shadow instance concurrently, which reduces the wall-clock time of read-heavy
+// workloads. Mutating operations as well as transaction and block boundaries are still run
+// sequentially, prime first.
+func WithConcurrentReads() ShadowOption {
+	return func(s *shadowVmStateDb) {
+		s.concurrentReads = true
+	}
+}
+
+// ComparisonLevel defines which results of the shadow proxy are cross checked.
+type ComparisonLevel int
+
+const (
+	// CompareValues compares the results of all getters except of storage roots and witnesses.
+	CompareValues ComparisonLevel = iota
+	// CompareTrieStructures additionally compares storage roots and witness contents. Different
+	// implementations, e.g. Carmen schemas and geth, may produce different storage roots and
+	// witnesses for the same logical content, hence this level is opt-in.
+	CompareTrieStructures
+)
+
+// WithComparisonLevel sets the level of cross checks of the shadow proxy; CompareValues by default.
+func WithComparisonLevel(level ComparisonLevel) ShadowOption {
+	return func(s *shadowVmStateDb) {
+		s.level = level
+	}
+}
+
 // NewShadowProxy creates a StateDB instance bundling two other instances and running each
 // operation on both of them, cross checking results. If the results are not equal, an error
 // is logged and the result of the primary instance is returned.
-func NewShadowProxy(prime, shadow state.StateDB, compareStateHash bool) state.StateDB {
-	return &shadowStateDb{
+func NewShadowProxy(prime, shadow state.StateDB, compareStateHash bool, opts ...ShadowOption) state.StateDB {
+	db := &shadowStateDb{
 		shadowVmStateDb: shadowVmStateDb{
 			prime:            prime,
 			shadow:           shadow,
@@ -50,6 +91,10 @@ func NewShadowProxy(prime, shadow state.StateDB, compareStateHash bool) state.St
 		prime:  prime,
 		shadow: shadow,
 	}
+	for _, opt := range opts {
+		opt(&db.shadowVmStateDb)
+	}
+	return db
 }
 
 // sameVmStateDBInstance reports whether both handles point to the exact same state implementation.
@@ -69,6 +114,11 @@ type shadowVmStateDb struct {
 	err              error
 	log              logger.Logger
 	compareStateHash bool
+	level            ComparisonLevel
+	history          *operationHistory // nil if recording of operations is disabled
+	block            uint64            // current block, only reported with a divergence
+	tx               uint32            // current transaction, only reported with a divergence
+	concurrentReads  bool              // whether read operations are run on both instances concurrently
 }
 
 type shadowNonCommittableStateDb struct {
@@ -87,6 +137,37 @@ type snapshotPair struct {
 	prime, shadow int
 }
 
+// operationHistory is a ring buffer of the most recent operations performed on the shadow proxy.
+type operationHistory struct {
+	ops  []string
+	next int
+	full bool
+}
+
+func newOperationHistory(size int) *operationHistory {
+	if size <= 0 {
+		return nil
+	}
+	return &operationHistory{ops: make([]string, size)}
+}
+
+func (h *operationHistory) add(op string) {
+	h.ops[h.next] = op
+	h.next++
+	if h.next == len(h.ops) {
+		h.next = 0
+		h.full = true
+	}
+}
+
+// entries returns the recorded operations starting with the oldest one.
+func (h *operationHistory) entries() []string {
+	if !h.full {
+		return append([]string(nil), h.ops[:h.next]...)
+	}
+	return append(append([]string(nil), h.ops[h.next:]...), h.ops[:h.next]...)
+}
+
 type vmStateHasher interface {
 	GetHash() (common.Hash, error)
 }
@@ -95,7 +176,7 @@ func (s *shadowVmStateDb) CreateAccount(addr common.Address) {
 	err := s.run("CreateAccount", func(s state.VmStateDB) error {
 		s.CreateAccount(addr)
 		return nil
-	})
+	}, addr)
 	if err != nil {
 		s.log.Errorf("failed: %v", err)
 	}
@@ -119,7 +200,7 @@ func (s *shadowVmStateDb) SelfDestruct(addr common.Address) {
 	err := s.run("SelfDestruct", func(s state.VmStateDB) error {
 		s.SelfDestruct(addr)
 		return nil
-	})
+	}, addr)
 	if err != nil {
 		s.log.Errorf("failed: %v", err)
 	}
@@ -136,13 +217,13 @@ func (s *shadowVmStateDb) GetBalance(addr common.Address) *uint256.Int {
 func (s *shadowVmStateDb) AddBalance(addr common.Address, value *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
 	return s.getUint256("AddBalance", func(s state.VmStateDB) uint256.Int {
 		return s.AddBalance(addr, value, reason)
-	})
+	}, addr, value)
 }
 
 func (s *shadowVmStateDb) SubBalance(addr common.Address, value *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
 	return s.getUint256("SubBalance", func(s state.VmStateDB) uint256.Int {
 		return s.SubBalance(addr, value, reason)
-	})
+	}, addr, value)
 }
 
 func (s *shadowVmStateDb) GetNonce(addr common.Address) uint64 {
@@ -153,7 +234,7 @@ func (s *shadowVmStateDb) SetNonce(addr common.Address, value uint64, reason tra
 	err := s.run("SetNonce", func(s state.VmStateDB) error {
 		s.SetNonce(addr, value, reason)
 		return nil
-	})
+	}, addr, value)
 	if err != nil {
 		s.log.Errorf("failed: %v", err)
 	}
@@ -177,14 +258,14 @@ func (s *shadowVmStateDb) GetState(addr common.Address, key common.Hash) common.
 func (s *shadowVmStateDb) SetState(addr common.Address, key common.Hash, value common.Hash) common.Hash {
 	return s.getHash("SetState", func(s state.VmStateDB) common.Hash {
 		return s.SetState(addr, key, value)
-	})
+	}, addr, key, value)
 }
 
 func (s *shadowVmStateDb) SetTransientState(addr common.Address, key common.Hash, value common.Hash) {
 	s.err = errors.Join(s.err, s.run("SetTransientState", func(s state.VmStateDB) error {
 		s.SetTransientState(addr, key, value)
 		return nil
-	}))
+	}, addr, key, value))
 }
 
 func (s *shadowVmStateDb) GetTransientState(addr common.Address, key common.Hash) common.Hash {
@@ -206,10 +287,11 @@ func (s *shadowVmStateDb) GetCodeHash(addr common.Address) common.Hash {
 func (s *shadowVmStateDb) SetCode(addr common.Address, code []byte, reason tracing.CodeChangeReason) []byte {
 	return s.getBytes("SetCode", func(s state.VmStateDB) []byte {
 		return s.SetCode(addr, code, reason)
-	})
+	}, addr)
 }
 
 func (s *shadowVmStateDb) Snapshot() int {
+	s.record("Snapshot")
 	pair := snapshotPair{
 		s.prime.Snapshot(),
 		s.shadow.Snapshot(),
@@ -223,15 +305,19 @@ func (s *shadowVmStateDb) RevertToSnapshot(id int) {
 	if id < 0 || len(s.snapshots) <= id {
 		panic(fmt.Sprintf("invalid snapshot id: %v, max: %v", id, len(s.snapshots)))
 	}
+	s.record("RevertToSnapshot", id)
 	s.verifyStateHash("RevertToSnapshot.Before")
 	s.prime.RevertToSnapshot(s.snapshots[id].prime)
 	s.shadow.RevertToSnapshot(s.snapshots[id].shadow)
+	// snapshots taken after id are invalidated by the revert, hence their pairs are dropped
+	s.snapshots = s.snapshots[:id+1]
 	s.verifyStateHash("RevertToSnapshot.After")
 }
 
 func (s *shadowVmStateDb) BeginTransaction(tx uint32) error {
 	s.snapshots = s.snapshots[0:0]
-	if err := s.run("BeginTransaction", func(s state.VmStateDB) error { return s.BeginTransaction(tx) }); err != nil {
+	s.tx = tx
+	if err := s.run("BeginTransaction", func(s state.VmStateDB) error { return s.BeginTransaction(tx) }, tx); err != nil {
 		return err
 	}
 	s.verifyStateHash("BeginTransaction")
@@ -239,6 +325,7 @@ func (s *shadowVmStateDb) BeginTransaction(tx uint32) error {
 }
 
 func (s *shadowVmStateDb) EndTransaction() error {
+	s.snapshots = s.snapshots[0:0]
 	if err := s.run("EndTransaction", func(s state.VmStateDB) error { return s.EndTransaction() }); err != nil {
 		return err
 	}
@@ -257,7 +344,8 @@ func (s *shadowVmStateDb) Finalise(deleteEmptyObjects bool) {
 }
 
 func (s *shadowStateDb) BeginBlock(blk uint64) error {
-	if err := s.run("BeginBlock", func(s state.StateDB) error { return s.BeginBlock(blk) }); err != nil {
+	s.block = blk
+	if err := s.run("BeginBlock", func(s state.StateDB) error { return s.BeginBlock(blk) }, blk); err != nil {
 		return err
 	}
 	s.verifyStateHash("BeginBlock")
@@ -343,7 +431,7 @@ func (s *shadowVmStateDb) AddRefund(amount uint64) {
 	err := s.run("AddRefund", func(s state.VmStateDB) error {
 		s.AddRefund(amount)
 		return nil
-	})
+	}, amount)
 	if err != nil {
 		s.log.Errorf("failed: %v", err)
 	}
@@ -355,7 +443,7 @@ func (s *shadowVmStateDb) SubRefund(amount uint64) {
 	err := s.run("SubRefund", func(s state.VmStateDB) error {
 		s.SubRefund(amount)
 		return nil
-	})
+	}, amount)
 	if err != nil {
 		s.log.Errorf("failed: %v", err)
 	}
@@ -389,7 +477,7 @@ func (s *shadowVmStateDb) AddAddressToAccessList(addr common.Address) {
 	err := s.run("AddAddressToAccessList", func(s state.VmStateDB) error {
 		s.AddAddressToAccessList(addr)
 		return nil
-	})
+	}, addr)
 	if err != nil {
 		s.log.Errorf("failed: %v", err)
 	}
@@ -399,7 +487,7 @@ func (s *shadowVmStateDb) AddSlotToAccessList(addr common.Address, slot common.H
 	err := s.run("AddSlotToAccessList", func(s state.VmStateDB) error {
 		s.AddSlotToAccessList(addr, slot)
 		return nil
-	})
+	}, addr, slot)
 	if err != nil {
 		s.log.Errorf("failed: %v", err)
 	}
@@ -416,6 +504,7 @@ func (s *shadowVmStateDb) AddLog(log *types.Log) {
 }
 
 func (s *shadowVmStateDb) GetLogs(hash common.Hash, block uint64, blockHash common.Hash, blkTimestamp uint64) []*types.Log {
+	s.record("GetLogs", hash, blockHash, blkTimestamp)
 	logsP := s.prime.GetLogs(hash, block, blockHash, blkTimestamp)
 	logsS := s.shadow.GetLogs(hash, block, blockHash, blkTimestamp)
 
@@ -430,7 +519,7 @@ func (s *shadowVmStateDb) GetLogs(hash common.Hash, block uint64, blockHash comm
 	}
 	if !equal {
 		s.logIssue("GetLogs", logsP, logsS, hash, blockHash, blkTimestamp)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString("GetLogs", hash, blockHash, blkTimestamp))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString("GetLogs", hash, blockHash, blkTimestamp)))
 	}
 	return logsP
 }
@@ -446,24 +535,71 @@ func (s *shadowVmStateDb) EmitLogsForBurnAccounts() {
 }
 
 func (s *shadowVmStateDb) GetStorageRoot(addr common.Address) common.Hash {
-	// call must be done onto both databases but result must not be compared
-	_ = s.shadow.GetStorageRoot(addr)
+	s.record("GetStorageRoot", addr)
+	// call must be done onto both databases but result is only compared if requested
+	resS := s.shadow.GetStorageRoot(addr)
 	// prime must be returned
-	return s.prime.GetStorageRoot(addr)
+	resP := s.prime.GetStorageRoot(addr)
+	if s.level >= CompareTrieStructures && resP != resS {
+		s.logIssue("GetStorageRoot", resP, resS, addr)
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB (%v)", getOpcodeString("GetStorageRoot", addr), trieComparisonNote))
+	}
+	return resP
 }
 
 func (s *shadowVmStateDb) CreateContract(addr common.Address) {
 	err := s.run("CreateContract", func(s state.VmStateDB) error {
 		s.CreateContract(addr)
 		return nil
-	})
+	}, addr)
 	if err != nil {
 		s.log.Errorf("failed: %v", err)
 	}
 }
 
 func (s *shadowVmStateDb) Witness() *stateless.Witness {
-	return s.prime.Witness()
+	if s.level < CompareTrieStructures {
+		return s.prime.Witness()
+	}
+	s.record("Witness")
+	resP := s.prime.Witness()
+	resS := s.shadow.Witness()
+	if diff := compareWitnesses(resP, resS); diff != "" {
+		s.log.Errorf("Diff for %v\n\t%v", getOpcodeString("Witness"), diff)
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB; %v (%v)", getOpcodeString("Witness"), diff, trieComparisonNote))
+	}
+	return resP
+}
+
+// trieComparisonNote labels divergences found by the opt-in CompareTrieStructures level.
+const trieComparisonNote = "trie structure comparison enabled; implementations may legitimately differ"
+
+// compareWitnesses describes the first difference of the code and state node sets of
+// given witnesses; it returns an empty string if both witnesses are equal. Headers are
+// not compared, as they are provided by the chain rather than the StateDB.
+func compareWitnesses(prime, shadow *stateless.Witness) string {
+	if prime == nil || shadow == nil {
+		if prime != shadow {
+			return fmt.Sprintf("witness is nil in primary: %v, in shadow: %v", prime == nil, shadow == nil)
+		}
+		return ""
+	}
+	if diff := compareSets("code", prime.Codes, shadow.Codes); diff != "" {
+		return diff
+	}
+	return compareSets("state node", prime.State, shadow.State)
+}
+
+func compareSets(name string, prime, shadow map[string]struct{}) string {
+	if len(prime) != len(shadow) {
+		return fmt.Sprintf("different number of %v entries; primary: %d, shadow: %d", name, len(prime), len(shadow))
+	}
+	for entry := range prime {
+		if _, found := shadow[entry]; !found {
+			return fmt.Sprintf("%v entry %x is missing in shadow", name, entry)
+		}
+	}
+	return ""
 }
 
 func (s *shadowStateDb) Finalise(deleteEmptyObjects bool) {
@@ -562,11 +698,14 @@ func (s *shadowStateDb) GetArchiveState(block uint64) (state.NonCommittableState
 	}
 	return &shadowNonCommittableStateDb{
 		shadowVmStateDb: shadowVmStateDb{
-			prime:     prime,
-			shadow:    shadow,
-			snapshots: []snapshotPair{},
-			err:       nil,
-			log:       s.log,
+			prime:           prime,
+			shadow:          shadow,
+			snapshots:       []snapshotPair{},
+			err:             nil,
+			log:             s.log,
+			history:         newOperationHistory(s.historySize()),
+			block:           block,
+			concurrentReads: s.concurrentReads,
 		},
 		prime:  prime,
 		shadow: shadow,
@@ -634,6 +773,17 @@ func (s *shadowStateDb) GetShadowDB() state.StateDB {
 	return s.shadow
 }
 
+func (s *shadowStateDb) CreateCheckpoint() error {
+	var errs []error
+	if err := s.prime.CreateCheckpoint(); err != nil {
+		errs = append(errs, fmt.Errorf("prime: %w", err))
+	}
+	if err := s.shadow.CreateCheckpoint(); err != nil {
+		errs = append(errs, fmt.Errorf("shadow: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
 type shadowBulkLoad struct {
 	prime  state.BulkLoad
 	shadow state.BulkLoad
@@ -671,7 +821,8 @@ func (l *shadowBulkLoad) Close() error {
 	)
 }
 
-func (s *shadowVmStateDb) run(opName string, op func(s state.VmStateDB) error) error {
+func (s *shadowVmStateDb) run(opName string, op func(s state.VmStateDB) error, args ...any) error {
+	s.record(opName, args...)
 	if err := op(s.prime); err != nil {
 		return fmt.Errorf("prime: %w", err)
 	}
@@ -683,11 +834,13 @@ func (s *shadowVmStateDb) run(opName string, op func(s state.VmStateDB) error) e
 }
 
 func (s *shadowNonCommittableStateDb) run(opName string, op func(s state.NonCommittableStateDB)) {
+	s.record(opName)
 	op(s.prime)
 	op(s.shadow)
 }
 
-func (s *shadowStateDb) run(opName string, op func(s state.StateDB) error) error {
+func (s *shadowStateDb) run(opName string, op func(s state.StateDB) error, args ...any) error {
+	s.record(opName, args...)
 	if err := op(s.prime); err != nil {
 		return fmt.Errorf("prime: %w", err)
 	}
@@ -699,46 +852,51 @@ func (s *shadowStateDb) run(opName string, op func(s state.StateDB) error) error
 }
 
 func (s *shadowVmStateDb) getBool(opName string, op func(s state.VmStateDB) bool, args ...any) bool {
-	resP := op(s.prime)
-	resS := op(s.shadow)
+	s.record(opName, args...)
+	resP, resS := both(s, opName, op)
 	if resP != resS {
 		s.logIssue(opName, resP, resS, args)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args)))
 	}
 	return resP
 }
 
 func (s *shadowVmStateDb) getBoolBool(opName string, op func(s state.VmStateDB) (bool, bool), args ...any) (bool, bool) {
-	resP1, resP2 := op(s.prime)
-	resS1, resS2 := op(s.shadow)
+	s.record(opName, args...)
+	resP, resS := both(s, opName, func(s state.VmStateDB) [2]bool {
+		res1, res2 := op(s)
+		return [2]bool{res1, res2}
+	})
+	resP1, resP2, resS1, resS2 := resP[0], resP[1], resS[0], resS[1]
 	if resP1 != resS1 || resP2 != resS2 {
 		s.logIssue(opName, fmt.Sprintf("(%v,%v)", resP1, resP2), fmt.Sprintf("(%v,%v)", resS1, resS2), args)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args)))
 	}
 	return resP1, resP2
 }
 
 func (s *shadowVmStateDb) getInt(opName string, op func(s state.VmStateDB) int, args ...any) int {
-	resP := op(s.prime)
-	resS := op(s.shadow)
+	s.record(opName, args...)
+	resP, resS := both(s, opName, op)
 	if resP != resS {
 		s.logIssue(opName, resP, resS, args)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args)))
 	}
 	return resP
 }
 
 func (s *shadowVmStateDb) getUint64(opName string, op func(s state.VmStateDB) uint64, args ...any) uint64 {
-	resP := op(s.prime)
-	resS := op(s.shadow)
+	s.record(opName, args...)
+	resP, resS := both(s, opName, op)
 	if resP != resS {
 		s.logIssue(opName, resP, resS, args)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args)))
 	}
 	return resP
 }
 
 func (s *shadowStateDb) getHash(opName string, op func(s state.StateDB) (common.Hash, error), args ...any) (common.Hash, error) {
+	s.record(opName, args...)
 	resP, err := op(s.prime)
 	if err != nil {
 		return common.Hash{}, err
@@ -749,13 +907,14 @@ func (s *shadowStateDb) getHash(opName string, op func(s state.StateDB) (common.
 	}
 	if resP != resS {
 		s.logIssue(opName, fmt.Sprintf("%x", resP), fmt.Sprintf("%x", resS), args)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args)))
 		return common.Hash{}, s.err
 	}
 	return resP, nil
 }
 
 func (s *shadowNonCommittableStateDb) getHash(opName string, op func(s state.NonCommittableStateDB) (common.Hash, error), args ...any) (common.Hash, error) {
+	s.record(opName, args...)
 	resP, err := op(s.prime)
 	if err != nil {
 		return common.Hash{}, err
@@ -766,13 +925,14 @@ func (s *shadowNonCommittableStateDb) getHash(opName string, op func(s state.Non
 	}
 	if resP != resS {
 		s.logIssue(opName, resP, resS, args)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args)))
 		return common.Hash{}, s.err
 	}
 	return resP, fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
 }
 
 func (s *shadowVmStateDb) getStateHash(opName string, op func(s state.VmStateDB) (common.Hash, error), args ...any) (common.Hash, error) {
+	s.record(opName, args...)
 	resP, err := op(s.prime)
 	if err != nil {
 		return common.Hash{}, err
@@ -783,75 +943,173 @@ func (s *shadowVmStateDb) getStateHash(opName string, op func(s state.VmStateDB)
 	}
 	if resP != resS {
 		s.logIssue(opName, resP, resS, args)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args)))
 	}
 	return resP, nil
 }
 
 func (s *shadowVmStateDb) getHash(opName string, op func(s state.VmStateDB) common.Hash, args ...any) common.Hash {
-	resP := op(s.prime)
-	resS := op(s.shadow)
+	s.record(opName, args...)
+	resP, resS := both(s, opName, op)
 	if resP != resS {
 		s.logIssue(opName, resP, resS, args)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args)))
 	}
 	return resP
 }
 
 func (s *shadowVmStateDb) getHashPair(opName string, op func(s state.VmStateDB) (common.Hash, common.Hash), args ...any) (common.Hash, common.Hash) {
-	res1P, res2P := op(s.prime)
-	res1S, res2S := op(s.shadow)
+	s.record(opName, args...)
+	resP, resS := both(s, opName, func(s state.VmStateDB) [2]common.Hash {
+		res1, res2 := op(s)
+		return [2]common.Hash{res1, res2}
+	})
+	res1P, res2P, res1S, res2S := resP[0], resP[1], resS[0], resS[1]
 	if res1P != res1S {
 		s.logIssue(opName, res1P, res1S, args)
-		s.err = fmt.Errorf("%v (first hash) diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v (first hash) diverged from shadow DB", getOpcodeString(opName, args)))
 	}
 	if res2P != res2S {
 		s.logIssue(opName, res2P, res2S, args)
-		s.err = fmt.Errorf("%v (second hash) diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v (second hash) diverged from shadow DB", getOpcodeString(opName, args)))
 	}
 	return res1P, res2P
 }
 
 func (s *shadowVmStateDb) getUint256Ptr(opName string, op func(s state.VmStateDB) *uint256.Int, args ...any) *uint256.Int {
-	resP := op(s.prime)
-	resS := op(s.shadow)
+	s.record(opName, args...)
+	resP, resS := both(s, opName, op)
 	if resP.Cmp(resS) != 0 {
 		s.logIssue(opName, resP, resS, args)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args)))
 	}
 	return resP
 }
 
 func (s *shadowVmStateDb) getUint256(opName string, op func(s state.VmStateDB) uint256.Int, args ...any) uint256.Int {
-	resP := op(s.prime)
-	resS := op(s.shadow)
+	s.record(opName, args...)
+	resP, resS := both(s, opName, op)
 	if resP.Cmp(&resS) != 0 {
 		s.logIssue(opName, resP, resS, args)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args)))
 	}
 	return resP
 }
 
 func (s *shadowVmStateDb) getBytes(opName string, op func(s state.VmStateDB) []byte, args ...any) []byte {
-	resP := op(s.prime)
-	resS := op(s.shadow)
+	s.record(opName, args...)
+	resP, resS := both(s, opName, op)
 	if !bytes.Equal(resP, resS) {
 		s.logIssue(opName, resP, resS, args)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args)))
 	}
 	return resP
 }
 
 func (s *shadowStateDb) getError(opName string, op func(s state.StateDB) error, args ...any) error {
+	s.record(opName, args...)
 	resP := op(s.prime)
 	resS := op(s.shadow)
 	if resP != resS {
 		s.logIssue(opName, resP, resS, args)
-		s.err = fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args))
+		s.reportDivergence(fmt.Errorf("%v diverged from shadow DB", getOpcodeString(opName, args)))
 	}
 	return resP
 }
 
+// readOperations are the side-effect-free operations which may be run on both instances concurrently.
+var readOperations = map[string]struct{}{
+	"AddressInAccessList": {},
+	"Empty":               {},
+	"Exist":               {},
+	"GetBalance":          {},
+	"GetCode":             {},
+	"GetCodeHash":         {},
+	"GetCodeSize":         {},
+	"GetCommittedState":   {},
+	"GetNonce":            {},
+	"GetRefund":           {},
+	"GetState":            {},
+	"GetTransientState":   {},
+	"HasSelfDestructed":   {},
+	"IsNewContract":       {},
+	"SlotInAccessList":    {},
+}
+
+// both runs the operation on the prime and the shadow instance and returns both results. Read
+// operations are run concurrently if enabled, all others sequentially starting with the prime.
+func both[T any](s *shadowVmStateDb, opName string, op func(s state.VmStateDB) T) (prime T, shadow T) {
+	if !s.runsConcurrently(opName) {
+		prime = op(s.prime)
+		shadow = op(s.shadow)
+		return prime, shadow
+	}
+
+	done := make(chan any, 1)
+	go func() {
+		defer func() { done <- recover() }()
+		shadow = op(s.shadow)
+	}()
+	// the shadow operation is always awaited, a panic of it is re-raised by the caller's goroutine
+	defer func() {
+		if r := <-done; r != nil {
+			panic(r)
+		}
+	}()
+	prime = op(s.prime)
+	return prime, shadow
+}
+
+// runsConcurrently reports whether the operation is run on both instances concurrently. Instances
+// shared by prime and shadow are never accessed concurrently.
+func (s *shadowVmStateDb) runsConcurrently(opName string) bool {
+	if !s.concurrentReads {
+		return false
+	}
+	if _, ok := readOperations[opName]; !ok {
+		return false
+	}
+	return !sameVmStateDBInstance(s.prime, s.shadow)
+}
+
+// record adds the operation to the history if the recording is enabled.
+func (s *shadowVmStateDb) record(opName string, args ...any) {
+	if s.history == nil {
+		return
+	}
+	s.history.add(getOpcodeString(opName, args...))
+}
+
+// historySize returns the capacity of the operation history, 0 if the recording is disabled.
+func (s *shadowVmStateDb) historySize() int {
+	if s.history == nil {
+		return 0
+	}
+	return len(s.history.ops)
+}
+
+// reportDivergence stores the divergence in err. If the operation history is enabled, the
+// context of the divergence is logged and attached to the error.
+func (s *shadowVmStateDb) reportDivergence(err error) {
+	if s.history != nil {
+		desc := s.divergenceContext()
+		s.log.Errorf("Divergence context:\n%v", desc)
+		err = fmt.Errorf("%w\n%v", err, desc)
+	}
+	s.err = err
+}
+
+// divergenceContext describes the current block, transaction and the recorded operations.
+func (s *shadowVmStateDb) divergenceContext() string {
+	ops := s.history.entries()
+	var desc strings.Builder
+	fmt.Fprintf(&desc, "block %v tx %v; last %v operations (oldest first):", s.block, s.tx, len(ops))
+	for i, op := range ops {
+		fmt.Fprintf(&desc, "\n\t%d: %v", i+1, op)
+	}
+	return desc.String()
+}
+
 func getOpcodeString(opName string, args ...any) string {
 	var opcode strings.Builder
 	opcode.WriteString(fmt.Sprintf("%v(", opName))
@@ -19,13 +19,17 @@ package proxy
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/aida/state"
 	"github.com/0xsoniclabs/aida/txcontext"
 	"github.com/0xsoniclabs/carmen/go/carmen"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
@@ -990,6 +994,98 @@ func TestShadowState_GetStorageRoot_CallsBothMethods_And_ReturnsPrimaryResult(t
 	}
 }
 
+func TestShadowState_GetStorageRoot_IsComparedOnlyWithTrieStructureLevel(t *testing.T) {
+	tests := map[string]struct {
+		level       ComparisonLevel
+		shadowHash  common.Hash
+		expectError bool
+	}{
+		"disabledDiverging":  {level: CompareValues, shadowHash: common.Hash{2}},
+		"enabledAgreeing":    {level: CompareTrieStructures, shadowHash: common.Hash{1}},
+		"enabledDisagreeing": {level: CompareTrieStructures, shadowHash: common.Hash{2}, expectError: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			pdb := state.NewMockStateDB(ctrl)
+			sdb := state.NewMockStateDB(ctrl)
+			db := NewShadowProxy(pdb, sdb, false, WithComparisonLevel(test.level))
+
+			addr := common.Address{1}
+			pdb.EXPECT().GetStorageRoot(addr).Return(common.Hash{1})
+			sdb.EXPECT().GetStorageRoot(addr).Return(test.shadowHash)
+
+			assert.Equal(t, common.Hash{1}, db.GetStorageRoot(addr))
+			if !test.expectError {
+				assert.NoError(t, db.Error())
+				return
+			}
+			err := db.Error()
+			assert.ErrorContains(t, err, "GetStorageRoot")
+			assert.ErrorContains(t, err, trieComparisonNote)
+		})
+	}
+}
+
+func TestShadowState_Witness_IsOnlyTakenFromPrimaryByDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	pdb := state.NewMockStateDB(ctrl)
+	sdb := state.NewMockStateDB(ctrl)
+	db := NewShadowProxy(pdb, sdb, false)
+
+	witness := &stateless.Witness{Codes: map[string]struct{}{"a": {}}}
+	pdb.EXPECT().Witness().Return(witness)
+
+	assert.Same(t, witness, db.Witness())
+	assert.NoError(t, db.Error())
+}
+
+func TestShadowState_Witness_IsComparedWithTrieStructureLevel(t *testing.T) {
+	witness := func(codes, nodes []string) *stateless.Witness {
+		w := &stateless.Witness{Codes: map[string]struct{}{}, State: map[string]struct{}{}}
+		for _, c := range codes {
+			w.Codes[c] = struct{}{}
+		}
+		for _, n := range nodes {
+			w.State[n] = struct{}{}
+		}
+		return w
+	}
+	tests := map[string]struct {
+		prime, shadow *stateless.Witness
+		wantErr       string
+	}{
+		"bothNil":      {},
+		"equal":        {prime: witness([]string{"a"}, []string{"n"}), shadow: witness([]string{"a"}, []string{"n"})},
+		"shadowNil":    {prime: witness([]string{"a"}, nil), wantErr: "witness is nil in primary: false, in shadow: true"},
+		"primeNil":     {shadow: witness([]string{"a"}, nil), wantErr: "witness is nil in primary: true, in shadow: false"},
+		"codeCount":    {prime: witness([]string{"a"}, nil), shadow: witness([]string{"a", "b"}, nil), wantErr: "different number of code entries"},
+		"codeContent":  {prime: witness([]string{"a"}, nil), shadow: witness([]string{"b"}, nil), wantErr: "code entry 61 is missing in shadow"},
+		"stateContent": {prime: witness(nil, []string{"n"}), shadow: witness(nil, []string{"m"}), wantErr: "state node entry 6e is missing in shadow"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			pdb := state.NewMockStateDB(ctrl)
+			sdb := state.NewMockStateDB(ctrl)
+			db := NewShadowProxy(pdb, sdb, false, WithComparisonLevel(CompareTrieStructures))
+
+			pdb.EXPECT().Witness().Return(test.prime)
+			sdb.EXPECT().Witness().Return(test.shadow)
+
+			assert.Same(t, test.prime, db.Witness())
+			if test.wantErr == "" {
+				assert.NoError(t, db.Error())
+				return
+			}
+			err := db.Error()
+			assert.ErrorContains(t, err, test.wantErr)
+			assert.ErrorContains(t, err, trieComparisonNote)
+		})
+	}
+}
+
 func TestProxy_NewShadowProxy(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1422,6 +1518,79 @@ func TestShadowVmStateDb_RevertToSnapshot(t *testing.T) {
 	mockDb.EXPECT().RevertToSnapshot(snapshot).Times(2)
 	shadow.RevertToSnapshot(snapshot)
 }
+func TestShadowVmStateDb_RevertToSnapshotDiscardsLaterSnapshots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prime := state.NewMockStateDB(ctrl)
+	shadow := state.NewMockStateDB(ctrl)
+	db := &shadowVmStateDb{
+		prime:     prime,
+		shadow:    shadow,
+		snapshots: []snapshotPair{},
+		log:       logger.NewLogger("info", "test"),
+	}
+
+	gomock.InOrder(
+		prime.EXPECT().Snapshot().Return(10),
+		shadow.EXPECT().Snapshot().Return(20),
+		prime.EXPECT().Snapshot().Return(11),
+		shadow.EXPECT().Snapshot().Return(21),
+		prime.EXPECT().Snapshot().Return(12),
+		shadow.EXPECT().Snapshot().Return(22),
+		prime.EXPECT().RevertToSnapshot(11),
+		shadow.EXPECT().RevertToSnapshot(21),
+		prime.EXPECT().Snapshot().Return(13),
+		shadow.EXPECT().Snapshot().Return(23),
+		prime.EXPECT().RevertToSnapshot(13),
+		shadow.EXPECT().RevertToSnapshot(23),
+		prime.EXPECT().RevertToSnapshot(10),
+		shadow.EXPECT().RevertToSnapshot(20),
+	)
+
+	assert.Equal(t, 0, db.Snapshot())
+	assert.Equal(t, 1, db.Snapshot())
+	assert.Equal(t, 2, db.Snapshot())
+
+	db.RevertToSnapshot(1)
+	assert.Equal(t, []snapshotPair{{10, 20}, {11, 21}}, db.snapshots)
+
+	// the id of the discarded snapshot is reused by the next snapshot
+	assert.Equal(t, 2, db.Snapshot())
+	db.RevertToSnapshot(2)
+	db.RevertToSnapshot(0)
+	assert.Equal(t, []snapshotPair{{10, 20}}, db.snapshots)
+
+	assert.Panics(t, func() { db.RevertToSnapshot(1) })
+}
+
+func TestShadowVmStateDb_EndTransactionResetsSnapshots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prime := state.NewMockStateDB(ctrl)
+	shadow := state.NewMockStateDB(ctrl)
+	db := &shadowVmStateDb{
+		prime:     prime,
+		shadow:    shadow,
+		snapshots: []snapshotPair{{1, 2}, {3, 4}},
+		log:       logger.NewLogger("info", "test"),
+	}
+
+	prime.EXPECT().EndTransaction().Return(nil)
+	shadow.EXPECT().EndTransaction().Return(nil)
+	assert.NoError(t, db.EndTransaction())
+	assert.Empty(t, db.snapshots)
+
+	prime.EXPECT().Snapshot().Return(5)
+	shadow.EXPECT().Snapshot().Return(6)
+	assert.Equal(t, 0, db.Snapshot())
+
+	prime.EXPECT().RevertToSnapshot(5)
+	shadow.EXPECT().RevertToSnapshot(6)
+	db.RevertToSnapshot(0)
+}
+
 func TestShadowVmStateDb_BeginTransaction(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -2710,3 +2879,354 @@ func TestShadowStateDb_GetArchiveBlockHeightVariants(t *testing.T) {
 		assert.Equal(t, uint64(7), height)
 	})
 }
+
+func TestShadowProxy_OperationHistoryCapturesStateOperations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	prime := state.NewMockStateDB(ctrl)
+	shadow := state.NewMockStateDB(ctrl)
+	db := NewShadowProxy(prime, shadow, false, WithOperationHistory(8))
+
+	addr := common.Address{0x12}
+	key := common.Hash{0x34}
+	for _, mock := range []*state.MockStateDB{prime, shadow} {
+		mock.EXPECT().BeginBlock(uint64(5)).Return(nil)
+		mock.EXPECT().BeginTransaction(uint32(2)).Return(nil)
+		mock.EXPECT().SetState(addr, key, common.Hash{1}).Return(common.Hash{})
+	}
+	prime.EXPECT().GetState(addr, key).Return(common.Hash{1})
+	shadow.EXPECT().GetState(addr, key).Return(common.Hash{2})
+
+	assert.NoError(t, db.BeginBlock(5))
+	assert.NoError(t, db.BeginTransaction(2))
+	db.SetState(addr, key, common.Hash{1})
+	assert.Equal(t, common.Hash{1}, db.GetState(addr, key))
+
+	err := db.Error()
+	assert.ErrorContains(t, err, "diverged from shadow DB")
+	assert.ErrorContains(t, err, "block 5 tx 2; last 4 operations (oldest first):")
+	assert.ErrorContains(t, err, "\t3: "+getOpcodeString("SetState", addr, key, common.Hash{1}))
+	assert.ErrorContains(t, err, "\t4: "+getOpcodeString("GetState", addr, key))
+}
+
+func TestShadowProxy_OperationHistoryKeepsOnlyLatestOperations(t *testing.T) {
+	history := newOperationHistory(2)
+	history.add("a")
+	assert.Equal(t, []string{"a"}, history.entries())
+	history.add("b")
+	history.add("c")
+	assert.Equal(t, []string{"b", "c"}, history.entries())
+}
+
+func TestShadowProxy_DisabledOperationHistoryDoesNotChangeBehavior(t *testing.T) {
+	addr := common.Address{0x12}
+	key := common.Hash{0x34}
+	run := func(opts ...ShadowOption) error {
+		ctrl := gomock.NewController(t)
+		prime := state.NewMockStateDB(ctrl)
+		shadow := state.NewMockStateDB(ctrl)
+		prime.EXPECT().GetState(addr, key).Return(common.Hash{1})
+		shadow.EXPECT().GetState(addr, key).Return(common.Hash{2})
+
+		db := NewShadowProxy(prime, shadow, false, opts...)
+		assert.Equal(t, common.Hash{1}, db.GetState(addr, key))
+		assert.Nil(t, db.(*shadowStateDb).history)
+		return db.Error()
+	}
+
+	want := run()
+	assert.EqualError(t, run(WithOperationHistory(0)), want.Error())
+	assert.NotContains(t, want.Error(), "operations")
+}
+
+func newConcurrentShadowVmStateDb(prime, shadow state.VmStateDB) *shadowVmStateDb {
+	return &shadowVmStateDb{
+		prime:           prime,
+		shadow:          shadow,
+		snapshots:       []snapshotPair{},
+		log:             logger.NewLogger("critical", "test"),
+		concurrentReads: true,
+	}
+}
+
+func TestShadowProxy_WithConcurrentReads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := NewShadowProxy(state.NewMockStateDB(ctrl), state.NewMockStateDB(ctrl), false, WithConcurrentReads())
+	assert.True(t, db.(*shadowStateDb).concurrentReads)
+
+	db = NewShadowProxy(state.NewMockStateDB(ctrl), state.NewMockStateDB(ctrl), false)
+	assert.False(t, db.(*shadowStateDb).concurrentReads)
+}
+
+func TestShadowVmStateDb_RunsConcurrently_OnlyReadsOfDistinctInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	prime := state.NewMockStateDB(ctrl)
+	shadow := newConcurrentShadowVmStateDb(prime, state.NewMockStateDB(ctrl))
+
+	for op := range readOperations {
+		assert.True(t, shadow.runsConcurrently(op), op)
+	}
+	for _, op := range []string{"SetState", "AddBalance", "SetCode", "Snapshot", "RevertToSnapshot", "SubRefund", "BeginTransaction"} {
+		assert.False(t, shadow.runsConcurrently(op), op)
+	}
+
+	shadow.concurrentReads = false
+	assert.False(t, shadow.runsConcurrently("GetBalance"))
+
+	same := newConcurrentShadowVmStateDb(prime, prime)
+	assert.False(t, same.runsConcurrently("GetBalance"), "a shared instance must not be accessed concurrently")
+}
+
+func TestShadowVmStateDb_ConcurrentReads_AreDispatchedToBothInstancesInParallel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	prime := state.NewMockStateDB(ctrl)
+	shadow := state.NewMockStateDB(ctrl)
+	db := newConcurrentShadowVmStateDb(prime, shadow)
+
+	addr := common.Address{0x1}
+	key := common.Hash{0x2}
+	// each instance waits for the other one, which only terminates if both run in parallel
+	primeStarted, shadowStarted := make(chan struct{}), make(chan struct{})
+	await := func(c chan struct{}) {
+		select {
+		case <-c:
+		case <-time.After(5 * time.Second):
+			t.Error("operations are not run concurrently")
+		}
+	}
+	prime.EXPECT().GetState(addr, key).DoAndReturn(func(common.Address, common.Hash) common.Hash {
+		close(primeStarted)
+		await(shadowStarted)
+		return common.Hash{0x3}
+	})
+	shadow.EXPECT().GetState(addr, key).DoAndReturn(func(common.Address, common.Hash) common.Hash {
+		close(shadowStarted)
+		await(primeStarted)
+		return common.Hash{0x3}
+	})
+
+	assert.Equal(t, common.Hash{0x3}, db.GetState(addr, key))
+	assert.NoError(t, db.err)
+}
+
+func TestShadowVmStateDb_ConcurrentReads_DetectDivergences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	prime := state.NewMockStateDB(ctrl)
+	shadow := state.NewMockStateDB(ctrl)
+	db := newConcurrentShadowVmStateDb(prime, shadow)
+	addr := common.Address{0x1}
+
+	prime.EXPECT().GetBalance(addr).Return(uint256.NewInt(1))
+	shadow.EXPECT().GetBalance(addr).Return(uint256.NewInt(2))
+	prime.EXPECT().SlotInAccessList(addr, common.Hash{}).Return(true, true)
+	shadow.EXPECT().SlotInAccessList(addr, common.Hash{}).Return(true, false)
+	prime.EXPECT().GetStateAndCommittedState(addr, common.Hash{}).Return(common.Hash{1}, common.Hash{2})
+	shadow.EXPECT().GetStateAndCommittedState(addr, common.Hash{}).Return(common.Hash{1}, common.Hash{2})
+
+	assert.Equal(t, uint256.NewInt(1), db.GetBalance(addr))
+	assert.ErrorContains(t, db.err, "GetBalance")
+	db.err = nil
+
+	addrOk, slotOk := db.SlotInAccessList(addr, common.Hash{})
+	assert.True(t, addrOk)
+	assert.True(t, slotOk)
+	assert.ErrorContains(t, db.err, "SlotInAccessList")
+	db.err = nil
+
+	value, committed := db.GetStateAndCommittedState(addr, common.Hash{})
+	assert.Equal(t, common.Hash{1}, value)
+	assert.Equal(t, common.Hash{2}, committed)
+	assert.NoError(t, db.err)
+}
+
+func TestShadowVmStateDb_ConcurrentReads_MutationsStaySequential(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	prime := state.NewMockStateDB(ctrl)
+	shadow := state.NewMockStateDB(ctrl)
+	db := newConcurrentShadowVmStateDb(prime, shadow)
+	addr := common.Address{0x1}
+
+	var primeDone atomic.Bool
+	checkPrimeDone := func() {
+		assert.True(t, primeDone.Load(), "shadow started before prime finished")
+	}
+	gomock.InOrder(
+		prime.EXPECT().SetState(addr, common.Hash{1}, common.Hash{2}).DoAndReturn(func(common.Address, common.Hash, common.Hash) common.Hash {
+			primeDone.Store(true)
+			return common.Hash{}
+		}),
+		shadow.EXPECT().SetState(addr, common.Hash{1}, common.Hash{2}).DoAndReturn(func(common.Address, common.Hash, common.Hash) common.Hash {
+			checkPrimeDone()
+			primeDone.Store(false)
+			return common.Hash{}
+		}),
+		prime.EXPECT().AddBalance(addr, uint256.NewInt(1), tracing.BalanceChangeUnspecified).DoAndReturn(func(common.Address, *uint256.Int, tracing.BalanceChangeReason) uint256.Int {
+			primeDone.Store(true)
+			return uint256.Int{}
+		}),
+		shadow.EXPECT().AddBalance(addr, uint256.NewInt(1), tracing.BalanceChangeUnspecified).DoAndReturn(func(common.Address, *uint256.Int, tracing.BalanceChangeReason) uint256.Int {
+			checkPrimeDone()
+			return uint256.Int{}
+		}),
+	)
+
+	db.SetState(addr, common.Hash{1}, common.Hash{2})
+	db.AddBalance(addr, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+	assert.NoError(t, db.err)
+}
+
+func TestShadowVmStateDb_ConcurrentReads_KeepSnapshotsConsistent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	prime := state.NewMockStateDB(ctrl)
+	shadow := state.NewMockStateDB(ctrl)
+	db := newConcurrentShadowVmStateDb(prime, shadow)
+	addr := common.Address{0x1}
+
+	gomock.InOrder(
+		prime.EXPECT().Snapshot().Return(10),
+		shadow.EXPECT().Snapshot().Return(20),
+	)
+	prime.EXPECT().GetNonce(addr).Return(uint64(1))
+	shadow.EXPECT().GetNonce(addr).Return(uint64(1))
+	gomock.InOrder(
+		prime.EXPECT().Snapshot().Return(11),
+		shadow.EXPECT().Snapshot().Return(21),
+	)
+	gomock.InOrder(
+		prime.EXPECT().RevertToSnapshot(10),
+		shadow.EXPECT().RevertToSnapshot(20),
+	)
+	prime.EXPECT().GetNonce(addr).Return(uint64(0))
+	shadow.EXPECT().GetNonce(addr).Return(uint64(0))
+
+	first := db.Snapshot()
+	assert.Equal(t, uint64(1), db.GetNonce(addr))
+	assert.Equal(t, 1, db.Snapshot())
+	db.RevertToSnapshot(first)
+	assert.Equal(t, uint64(0), db.GetNonce(addr))
+	assert.NoError(t, db.err)
+}
+
+func TestShadowVmStateDb_ConcurrentReads_PanicOfShadowIsRaisedByCaller(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	prime := state.NewMockStateDB(ctrl)
+	shadow := state.NewMockStateDB(ctrl)
+	db := newConcurrentShadowVmStateDb(prime, shadow)
+	addr := common.Address{0x1}
+
+	prime.EXPECT().GetCode(addr).Return([]byte{1})
+	shadow.EXPECT().GetCode(addr).DoAndReturn(func(common.Address) []byte {
+		panic("shadow failed")
+	})
+
+	assert.PanicsWithValue(t, "shadow failed", func() { db.GetCode(addr) })
+}
+
+func TestShadowStateDb_GetArchiveState_InheritsConcurrentReads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	prime := state.NewMockStateDB(ctrl)
+	shadow := state.NewMockStateDB(ctrl)
+	prime.EXPECT().GetArchiveState(uint64(5)).Return(state.NewMockNonCommittableStateDB(ctrl), nil)
+	shadow.EXPECT().GetArchiveState(uint64(5)).Return(state.NewMockNonCommittableStateDB(ctrl), nil)
+
+	db := NewShadowProxy(prime, shadow, false, WithConcurrentReads())
+	archive, err := db.GetArchiveState(5)
+	assert.NoError(t, err)
+	assert.True(t, archive.(*shadowNonCommittableStateDb).concurrentReads)
+}
+
+func TestShadowStateDb_ConcurrentReads_AreRaceFree(t *testing.T) {
+	db := newInMemoryShadowProxy(t, 0, WithConcurrentReads())
+	addrs := populateShadowProxy(t, db, 16)
+
+	for i := 0; i < 1000; i++ {
+		addr := addrs[i%len(addrs)]
+		db.AddBalance(addr, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+		db.SetState(addr, common.Hash{byte(i)}, common.Hash{byte(i + 1)})
+		assert.Equal(t, common.Hash{byte(i + 1)}, db.GetState(addr, common.Hash{byte(i)}))
+		assert.True(t, db.Exist(addr))
+		db.GetBalance(addr)
+		db.GetNonce(addr)
+		db.GetCodeHash(addr)
+	}
+	assert.NoError(t, db.Error())
+}
+
+// slowReadStateDB delays reads to model a StateDb serving reads from disk.
+type slowReadStateDB struct {
+	state.StateDB
+	latency time.Duration
+}
+
+func (db slowReadStateDB) GetBalance(addr common.Address) *uint256.Int {
+	time.Sleep(db.latency)
+	return db.StateDB.GetBalance(addr)
+}
+
+func (db slowReadStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	time.Sleep(db.latency)
+	return db.StateDB.GetState(addr, key)
+}
+
+func newInMemoryShadowProxy(t testing.TB, latency time.Duration, opts ...ShadowOption) state.StateDB {
+	prime, err := state.MakeEmptyGethInMemoryStateDB("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	shadow, err := state.MakeEmptyGethInMemoryStateDB("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latency > 0 {
+		prime, shadow = slowReadStateDB{prime, latency}, slowReadStateDB{shadow, latency}
+	}
+	return NewShadowProxy(prime, shadow, false, opts...)
+}
+
+func populateShadowProxy(t testing.TB, db state.StateDB, accounts int) []common.Address {
+	if err := db.BeginBlock(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.BeginTransaction(0); err != nil {
+		t.Fatal(err)
+	}
+	addrs := make([]common.Address, accounts)
+	for i := range addrs {
+		addrs[i] = common.Address{byte(i + 1)}
+		db.CreateAccount(addrs[i])
+		db.AddBalance(addrs[i], uint256.NewInt(100), tracing.BalanceChangeUnspecified)
+		db.SetState(addrs[i], common.Hash{1}, common.Hash{2})
+	}
+	return addrs
+}
+
+// BenchmarkShadowProxy_Reads compares sequential and concurrent reads. Without latency, dispatching
+// the shadow read to a goroutine makes reads of in-memory StateDbs about four times slower. With a
+// read latency modelling a StateDb served from disk, concurrent reads halve the wall-clock time,
+// even on a single core. Measured on a single core machine, where a 50µs sleep takes about 1ms:
+//
+//	BenchmarkShadowProxy_Reads/latency=0s/sequential      	    447 ns/op
+//	BenchmarkShadowProxy_Reads/latency=0s/concurrent      	   1786 ns/op
+//	BenchmarkShadowProxy_Reads/latency=50µs/sequential    	4311858 ns/op
+//	BenchmarkShadowProxy_Reads/latency=50µs/concurrent    	2174822 ns/op
+func BenchmarkShadowProxy_Reads(b *testing.B) {
+	for _, latency := range []time.Duration{0, 50 * time.Microsecond} {
+		for _, mode := range []struct {
+			name string
+			opts []ShadowOption
+		}{
+			{"sequential", nil},
+			{"concurrent", []ShadowOption{WithConcurrentReads()}},
+		} {
+			b.Run(fmt.Sprintf("latency=%v/%v", latency, mode.name), func(b *testing.B) {
+				db := newInMemoryShadowProxy(b, latency, mode.opts...)
+				addrs := populateShadowProxy(b, db, 64)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					addr := addrs[i%len(addrs)]
+					db.GetBalance(addr)
+					db.GetState(addr, common.Hash{1})
+				}
+			})
+		}
+	}
+}
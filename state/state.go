@@ -193,6 +193,11 @@ type StateDB interface {
 	// not supporting this may return nil.
 	GetMemoryUsage() *MemoryUsage
 
+	// CreateCheckpoint requests the StateDB to create a checkpoint it can be recovered to
+	// after a crash. It may only be called between blocks. Implementations not supporting
+	// checkpoints return an error.
+	CreateCheckpoint() error
+
 	// ---- Artifacts from Geth dependency ----
 
 	// The following functions may be used by StateDB implementations for backward-compatibility
@@ -1491,6 +1491,20 @@ func (mr *MockStateDBMockRecorder) CreateContract(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContract", reflect.TypeOf((*MockStateDB)(nil).CreateContract), arg0)
 }
 
+// CreateCheckpoint mocks base method.
+func (m *MockStateDB) CreateCheckpoint() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCheckpoint")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCheckpoint indicates an expected call of CreateCheckpoint.
+func (mr *MockStateDBMockRecorder) CreateCheckpoint() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCheckpoint", reflect.TypeOf((*MockStateDB)(nil).CreateCheckpoint))
+}
+
 // EmitLogsForBurnAccounts mocks base method.
 func (m *MockStateDB) EmitLogsForBurnAccounts() {
 	m.ctrl.T.Helper()
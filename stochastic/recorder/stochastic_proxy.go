@@ -430,6 +430,10 @@ func (p *StochasticProxy) GetMemoryUsage() *state.MemoryUsage {
 	return p.db.GetMemoryUsage()
 }
 
+func (p *StochasticProxy) CreateCheckpoint() error {
+	return p.db.CreateCheckpoint()
+}
+
 func (p *StochasticProxy) GetArchiveState(block uint64) (state.NonCommittableStateDB, error) {
 	return p.db.GetArchiveState(block)
 }
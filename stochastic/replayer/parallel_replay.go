@@ -0,0 +1,317 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package replayer
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/stochastic/operations"
+	"github.com/0xsoniclabs/aida/stochastic/recorder"
+	"github.com/0xsoniclabs/aida/stochastic/statistics/markov"
+	"github.com/0xsoniclabs/aida/utils"
+)
+
+// blockAssignment describes the blocks simulated by a single replay worker. The worker
+// simulates count blocks starting with first, each block being stride blocks after the
+// previous one.
+type blockAssignment struct {
+	first  uint64
+	stride uint64
+	count  int
+}
+
+// assignBlocks distributes nBlocks blocks starting with first among the given number
+// of workers. The blocks are assigned round-robin, hence the blocks of the workers are
+// disjoint and a worker never waits for more than one block of each other worker.
+func assignBlocks(first uint64, nBlocks int, workers int) []blockAssignment {
+	res := make([]blockAssignment, workers)
+	for i := range res {
+		res[i] = blockAssignment{
+			first:  first + uint64(i),
+			stride: uint64(workers),
+			count:  nBlocks / workers,
+		}
+		if i < nBlocks%workers {
+			res[i].count++
+		}
+	}
+	return res
+}
+
+// blockTurn grants the shared StateDB to one replay worker at a time. The turn passes
+// block by block in ascending order of block numbers, hence the StateDB observes the
+// same sequence of operations as in a single-worker run with the same blocks, and the
+// simulation is reproducible for a fixed seed.
+type blockTurn struct {
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	next    uint64 // block whose worker holds the turn
+	stopped bool
+}
+
+func newBlockTurn(first uint64) *blockTurn {
+	t := &blockTurn{next: first}
+	t.cond = sync.NewCond(&t.mutex)
+	return t
+}
+
+// wait blocks until the turn reaches the given block. It returns false if the replay was stopped.
+func (t *blockTurn) wait(block uint64) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for t.next != block && !t.stopped {
+		t.cond.Wait()
+	}
+	return !t.stopped
+}
+
+// advance passes the turn to the worker of the next block.
+func (t *blockTurn) advance() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.next++
+	t.cond.Broadcast()
+}
+
+// stop releases all waiting workers and ends the replay.
+func (t *blockTurn) stop() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.stopped = true
+	t.cond.Broadcast()
+}
+
+// parallelReplay is the state shared by all workers of a parallel stochastic replay.
+// Except for the turn itself, all fields are only accessed by the worker holding the turn.
+type parallelReplay struct {
+	cfg      *utils.Config
+	log      logger.Logger
+	mc       *markov.Chain
+	initial  int // initial state of the markov chain
+	turn     *blockTurn
+	start    time.Time
+	lastLog  time.Time
+	interval time.Duration
+	numOps   uint64  // operations executed by all workers
+	errList  []error // StateDB errors observed by all workers
+}
+
+// replayWorker simulates its assigned blocks using its own replay context and random generator.
+type replayWorker struct {
+	index       int
+	ss          *replayContext
+	rg          *rand.Rand
+	blocks      blockAssignment
+	simulated   int                       // number of simulated blocks
+	numOps      uint64                    // number of executed operations
+	opFrequency [operations.NumOps]uint64 // operation frequency
+}
+
+// runParallelStochasticReplay runs the stochastic simulation with cfg.ReplayWorkers workers.
+// Each worker samples its own markov chain walk with a random generator seeded by
+// RandomSeed+worker index. The workers share the StateDB, which is handed to them block
+// by block in ascending order, see blockTurn.
+func runParallelStochasticReplay(db state.StateDB, e *recorder.StatsJSON, nBlocks int, cfg *utils.Config, log logger.Logger, balanceRange int64, nonceRange int) error {
+	if cfg.EnableCoverage {
+		return fmt.Errorf("RunStochasticReplay: coverage-guided fuzzing requires a single replay worker")
+	}
+
+	numWorkers := min(cfg.ReplayWorkers, nBlocks)
+	log.Noticef("using %d replay workers with random seeds %d-%d", numWorkers, cfg.RandomSeed, cfg.RandomSeed+int64(numWorkers-1))
+
+	mc, initial, err := getStochasticMatrix(e)
+	if err != nil {
+		return fmt.Errorf("RunStochasticReplay: expected a markov chain: %w", err)
+	}
+
+	// create the replay contexts, the StateDB is primed by the first worker
+	const firstBlock = 1
+	workers := make([]*replayWorker, numWorkers)
+	for i, blocks := range assignBlocks(firstBlock, nBlocks, numWorkers) {
+		rg := rand.New(rand.NewSource(cfg.RandomSeed + int64(i)))
+		ss, err := buildReplayContext(e, db, rg, log, balanceRange, nonceRange)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			if err := ss.prime(); err != nil {
+				return err
+			}
+			log.Noticef("balance range %d", ss.balanceRange)
+			log.Noticef("nonce range %d", ss.nonceRange)
+		}
+		ss.blockNum = blocks.first
+		workers[i] = &replayWorker{index: i, ss: ss, rg: rg, blocks: blocks}
+	}
+
+	start := time.Now()
+	p := &parallelReplay{
+		cfg:      cfg,
+		log:      log,
+		mc:       mc,
+		initial:  initial,
+		turn:     newBlockTurn(firstBlock),
+		start:    start,
+		lastLog:  start,
+		interval: time.Duration(progressLogIntervalSec) * time.Second,
+	}
+
+	log.Noticef("Simulation block range: first %v, last %v", firstBlock, firstBlock+uint64(nBlocks-1))
+	var wg sync.WaitGroup
+	errs := make([]error, numWorkers)
+	for i, w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if errs[i] = w.run(p); errs[i] != nil {
+				p.turn.stop()
+			}
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	// print progress summary and statistics
+	var (
+		opFrequency [operations.NumOps]uint64
+		blocks      int
+		totalTx     uint64
+	)
+	for _, w := range workers {
+		blocks += w.simulated
+		totalTx += w.ss.totalTx
+		for op := range operations.NumOps {
+			opFrequency[op] += w.opFrequency[op]
+		}
+	}
+	elapsed := time.Since(start)
+	log.Noticef("Total elapsed time: %.3f s, processed %v blocks, %.2f ops/s", elapsed.Seconds(), blocks, float64(p.numOps)/elapsed.Seconds())
+	if len(p.errList) > 0 {
+		log.Warningf("%v errors were found", len(p.errList))
+	}
+	log.Noticef("Workers: %v", numWorkers)
+	log.Noticef("Blocks: %v", blocks)
+	log.Noticef("Transactions: %v", totalTx)
+	log.Noticef("Operations: %v", p.numOps)
+	log.Noticef("Operation Frequencies:")
+	for op := range operations.NumOps {
+		log.Noticef("\t%v: %v", operations.OpText[op], opFrequency[op])
+	}
+
+	if len(p.errList) == 0 {
+		return nil
+	}
+	return fmt.Errorf("stochastic replay failed: %w", errors.Join(p.errList...))
+}
+
+// run simulates the blocks of the worker. Operations are only executed while the
+// worker holds the turn, which it releases after the end of each of its blocks.
+func (w *replayWorker) run(p *parallelReplay) error {
+	if w.blocks.count == 0 {
+		return nil
+	}
+	if p.cfg.Debug && w.ss.blockNum >= p.cfg.DebugFrom {
+		w.ss.enableDebug()
+	}
+
+	state := p.initial
+	holding := false
+	for {
+		if !holding {
+			if !p.turn.wait(w.ss.blockNum) {
+				return nil
+			}
+			holding = true
+		}
+
+		label, err := mcLabel(p.mc, state)
+		if err != nil {
+			return fmt.Errorf("RunStochasticReplay: worker %d cannot retrieve state label: %w", w.index, err)
+		}
+
+		// decode opcode
+		op, addrCl, keyCl, valueCl, err := operations.DecodeOpcode(label)
+		if err != nil {
+			return fmt.Errorf("RunStochasticReplay: worker %d cannot decode opcode: %w", w.index, err)
+		}
+
+		// keep track of stats
+		w.numOps++
+		w.opFrequency[op]++
+		p.numOps++
+
+		// execute operation with its argument classes
+		if err := w.ss.execute(op, addrCl, keyCl, valueCl); err != nil {
+			return fmt.Errorf("RunStochasticReplay: worker %d: %w", w.index, err)
+		}
+
+		endOfBlock := op == operations.EndBlockID
+		if endOfBlock {
+			w.simulated++
+			// execute moved to the following block, skip the blocks of the other workers
+			w.ss.blockNum += w.blocks.stride - 1
+			if w.simulated >= w.blocks.count {
+				p.turn.advance()
+				return nil
+			}
+			if p.cfg.Debug && !w.ss.traceDebug && w.ss.blockNum >= p.cfg.DebugFrom {
+				w.ss.enableDebug()
+			}
+		}
+
+		p.reportProgress(w.ss.blockNum)
+
+		// check for errors
+		if err := w.ss.db.Error(); err != nil {
+			p.errList = append(p.errList, fmt.Errorf("block %v tx %v: %w", w.ss.blockNum, w.ss.txNum, err))
+			if !p.cfg.ContinueOnFailure {
+				p.turn.stop()
+				return nil
+			}
+		}
+
+		if endOfBlock {
+			holding = false
+			p.turn.advance()
+		}
+
+		// transit to next state in Markovian process
+		state, err = mcSample(p.mc, state, w.rg.Float64())
+		if err != nil {
+			return fmt.Errorf("RunStochasticReplay: worker %d failed sampling the next state: %w", w.index, err)
+		}
+	}
+}
+
+// reportProgress logs the combined throughput of all workers. It must only be called by
+// the worker holding the turn.
+func (p *parallelReplay) reportProgress(block uint64) {
+	if p.interval > 0 && time.Since(p.lastLog) < p.interval {
+		return
+	}
+	elapsed := time.Since(p.start)
+	p.log.Debugf("Elapsed time: %.0f s, at block %v, %.2f ops/s", elapsed.Seconds(), block, float64(p.numOps)/elapsed.Seconds())
+	p.lastLog = time.Now()
+}
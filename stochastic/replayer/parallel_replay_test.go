@@ -0,0 +1,214 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package replayer
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/stochastic"
+	"github.com/0xsoniclabs/aida/stochastic/operations"
+	"github.com/0xsoniclabs/aida/stochastic/recorder"
+	recArgs "github.com/0xsoniclabs/aida/stochastic/recorder/arguments"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestAssignBlocks_BlocksAreDisjointAndComplete(t *testing.T) {
+	for _, nBlocks := range []int{1, 7, 10, 100} {
+		for _, workers := range []int{1, 2, 3, 7} {
+			if workers > nBlocks {
+				continue
+			}
+			t.Run(fmt.Sprintf("%d blocks %d workers", nBlocks, workers), func(t *testing.T) {
+				seen := make(map[uint64]int)
+				for i, a := range assignBlocks(5, nBlocks, workers) {
+					assert.Equal(t, uint64(workers), a.stride)
+					for k := 0; k < a.count; k++ {
+						block := a.first + uint64(k)*a.stride
+						owner, found := seen[block]
+						require.False(t, found, "block %d assigned to workers %d and %d", block, owner, i)
+						seen[block] = i
+					}
+				}
+				require.Len(t, seen, nBlocks)
+				for block := uint64(5); block < uint64(5+nBlocks); block++ {
+					assert.Contains(t, seen, block)
+				}
+			})
+		}
+	}
+}
+
+// newParallelReplayTestStats returns stats of a markov chain with randomized
+// transactions per block and blocks per sync-period.
+func newParallelReplayTestStats(t *testing.T) *recorder.StatsJSON {
+	labels := newLabels(t,
+		operations.BeginSyncPeriodID,
+		operations.BeginBlockID,
+		operations.BeginTransactionID,
+		operations.EndTransactionID,
+		operations.EndBlockID,
+		operations.EndSyncPeriodID,
+	)
+	A := [][]float64{
+		{0, 1, 0, 0, 0, 0},     // BS -> BB
+		{0, 0, 1, 0, 0, 0},     // BB -> BT
+		{0, 0, 0, 1, 0, 0},     // BT -> ET
+		{0, 0, 0.6, 0, 0.4, 0}, // ET -> BT | EB
+		{0, 0.7, 0, 0, 0, 0.3}, // EB -> BB | ES
+		{1, 0, 0, 0, 0, 0},     // ES -> BS
+	}
+	qpdf := make([]float64, stochastic.QueueLen)
+	qpdf[0] = 0.3
+	for i := 1; i < len(qpdf); i++ {
+		qpdf[i] = 0.7 / float64(stochastic.QueueLen-1)
+	}
+	cls := recArgs.ClassifierJSON{Counting: recArgs.ArgStatsJSON{N: 400, ECDF: [][2]float64{{0, 0}, {1, 1}}}, Queuing: recArgs.QueueStatsJSON{Distribution: qpdf}}
+	return &recorder.StatsJSON{
+		Operations:       labels,
+		StochasticMatrix: A,
+		Contracts:        cls,
+		Keys:             cls,
+		Values:           cls,
+		SnapshotECDF:     [][2]float64{{0, 0}, {1, 1}},
+	}
+}
+
+// newRecordingStateDB returns a StateDB mock recording the sequence of block and transaction
+// operations. Priming accounts and errors are not recorded.
+func newRecordingStateDB(ctrl *gomock.Controller, dbErr error) (*state.MockStateDB, *[]string) {
+	var (
+		mutex sync.Mutex
+		trace []string
+	)
+	record := func(format string, args ...any) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		trace = append(trace, fmt.Sprintf(format, args...))
+	}
+
+	db := state.NewMockStateDB(ctrl)
+	db.EXPECT().GetShadowDB().Return(nil).AnyTimes()
+	db.EXPECT().CreateAccount(gomock.Any()).AnyTimes()
+	db.EXPECT().AddBalance(gomock.Any(), gomock.Any(), gomock.Any()).Return(*uint256.NewInt(0)).AnyTimes()
+	db.EXPECT().BeginSyncPeriod(gomock.Any()).Do(func(n uint64) { record("BS %d", n) }).AnyTimes()
+	db.EXPECT().EndSyncPeriod().Do(func() { record("ES") }).AnyTimes()
+	db.EXPECT().BeginBlock(gomock.Any()).DoAndReturn(func(n uint64) error { record("BB %d", n); return nil }).AnyTimes()
+	db.EXPECT().EndBlock().DoAndReturn(func() error { record("EB"); return nil }).AnyTimes()
+	db.EXPECT().BeginTransaction(gomock.Any()).DoAndReturn(func(n uint32) error { record("BT %d", n); return nil }).AnyTimes()
+	db.EXPECT().EndTransaction().DoAndReturn(func() error { record("ET"); return nil }).AnyTimes()
+	if dbErr != nil {
+		db.EXPECT().Error().Return(dbErr)
+	}
+	db.EXPECT().Error().Return(nil).AnyTimes()
+	return db, &trace
+}
+
+// beginBlocks returns the block numbers of all BeginBlock operations of the trace.
+func beginBlocks(trace []string) []uint64 {
+	var res []uint64
+	for _, op := range trace {
+		var block uint64
+		if _, err := fmt.Sscanf(op, "BB %d", &block); err == nil {
+			res = append(res, block)
+		}
+	}
+	return res
+}
+
+func TestRunStochasticReplay_ParallelWorkersSimulateDisjointBlocksInOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db, trace := newRecordingStateDB(ctrl, nil)
+
+	cfg := &utils.Config{BalanceRange: 100, NonceRange: 100, RandomSeed: 3, ReplayWorkers: 3}
+	require.NoError(t, RunStochasticReplay(db, newParallelReplayTestStats(t), 10, cfg, logger.NewLogger("critical", "test")))
+
+	// block 0 is used for priming
+	want := []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, want, beginBlocks(*trace))
+}
+
+func TestRunStochasticReplay_ParallelWorkersAreDeterministic(t *testing.T) {
+	run := func(seed int64) []string {
+		ctrl := gomock.NewController(t)
+		db, trace := newRecordingStateDB(ctrl, nil)
+		cfg := &utils.Config{BalanceRange: 100, NonceRange: 100, RandomSeed: seed, ReplayWorkers: 4}
+		require.NoError(t, RunStochasticReplay(db, newParallelReplayTestStats(t), 50, cfg, logger.NewLogger("critical", "test")))
+		return *trace
+	}
+	count := func(trace []string, prefix string) int {
+		res := 0
+		for _, op := range trace {
+			if op[:2] == prefix {
+				res++
+			}
+		}
+		return res
+	}
+
+	first := run(7)
+	for i := 0; i < 3; i++ {
+		again := run(7)
+		require.Equal(t, first, again)
+	}
+	assert.Equal(t, 51, count(first, "BB"))
+	assert.Equal(t, count(first, "BT"), count(first, "ET"))
+	assert.Greater(t, count(first, "BT"), 50)
+}
+
+func TestRunStochasticReplay_ParallelWorkersAggregateErrors(t *testing.T) {
+	tests := map[string]struct {
+		continueOnFailure bool
+		blocks            int
+	}{
+		"continue on failure": {continueOnFailure: true, blocks: 21},
+		"stop on failure":     {continueOnFailure: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db, trace := newRecordingStateDB(ctrl, assert.AnError)
+
+			cfg := &utils.Config{BalanceRange: 100, NonceRange: 100, RandomSeed: 3, ReplayWorkers: 2, ContinueOnFailure: test.continueOnFailure}
+			err := RunStochasticReplay(db, newParallelReplayTestStats(t), 20, cfg, logger.NewLogger("critical", "test"))
+			require.ErrorIs(t, err, assert.AnError)
+			require.ErrorContains(t, err, "stochastic replay failed")
+			if test.continueOnFailure {
+				assert.Len(t, beginBlocks(*trace), test.blocks)
+			} else {
+				assert.Less(t, len(beginBlocks(*trace)), 21)
+			}
+		})
+	}
+}
+
+func TestRunStochasticReplay_ParallelWorkersRejectCoverage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := state.NewMockStateDB(ctrl)
+	db.EXPECT().GetShadowDB().Return(nil)
+
+	cfg := &utils.Config{BalanceRange: 100, NonceRange: 100, ReplayWorkers: 2, EnableCoverage: true}
+	err := RunStochasticReplay(db, newParallelReplayTestStats(t), 10, cfg, logger.NewLogger("critical", "test"))
+	require.ErrorContains(t, err, "requires a single replay worker")
+}
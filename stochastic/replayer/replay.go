@@ -120,6 +120,29 @@ func populateReplayContext(
 	log logger.Logger,
 	balanceRange int64,
 	nonceRange int,
+) (*replayContext, error) {
+	ss, err := buildReplayContext(e, db, rg, log, balanceRange, nonceRange)
+	if err != nil {
+		return nil, err
+	}
+
+	// create accounts in StateDB before starting the simulation
+	err = ss.prime()
+	if err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+// buildReplayContext creates a stochastic state without priming the StateDB.
+func buildReplayContext(
+	e *recorder.StatsJSON,
+	db state.StateDB,
+	rg *rand.Rand,
+	log logger.Logger,
+	balanceRange int64,
+	nonceRange int,
 ) (*replayContext, error) {
 	// produce random variables for contract addresses,
 	// storage-keys, storage addresses, and snapshot ids.
@@ -173,12 +196,6 @@ func populateReplayContext(
 	ss.nonceSampler = arguments.NewScalarSampler(rg, e.Nonce.ECDF)
 	ss.codeSampler = arguments.NewScalarSampler(rg, e.CodeSize.ECDF)
 
-	// create accounts in StateDB before starting the simulation
-	err = ss.prime()
-	if err != nil {
-		return nil, err
-	}
-
 	return &ss, nil
 }
 
@@ -230,6 +247,10 @@ func RunStochasticReplay(db state.StateDB, e *recorder.StatsJSON, nBlocks int, c
 		nonceRange = 1
 	}
 
+	if cfg.ReplayWorkers > 1 {
+		return runParallelStochasticReplay(db, e, nBlocks, cfg, log, balanceRange, nonceRange)
+	}
+
 	// random arguments
 	rg := rand.New(rand.NewSource(cfg.RandomSeed))
 	log.Noticef("using random seed %d", cfg.RandomSeed)
@@ -20,9 +20,13 @@ package txcontext
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 )
 
 // Result is a transaction result.
@@ -57,6 +61,41 @@ type Receipt interface {
 	Equal(y Receipt) bool
 }
 
+// VmErrorReporter is implemented by receipts which know why the execution of
+// the transaction failed. Recorded receipts usually do not carry this information.
+type VmErrorReporter interface {
+	// GetVmError returns the error reported by the vm for a failed execution or nil.
+	GetVmError() error
+}
+
+// RevertError is the vm error of a reverted execution. It wraps
+// vm.ErrExecutionReverted and carries the reason decoded from the
+// returned data, if the data is a standard Error(string) or Panic(uint256).
+type RevertError struct {
+	Reason string
+}
+
+// NewRevertError decodes the revert reason from the data returned by a reverted execution.
+// Data which does not follow the Error(string) or Panic(uint256) encoding yields an empty reason.
+func NewRevertError(output []byte) *RevertError {
+	reason, err := abi.UnpackRevert(output)
+	if err != nil {
+		return &RevertError{}
+	}
+	return &RevertError{Reason: reason}
+}
+
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return vm.ErrExecutionReverted.Error()
+	}
+	return fmt.Sprintf("%v: %v", vm.ErrExecutionReverted, e.Reason)
+}
+
+func (e *RevertError) Unwrap() error {
+	return vm.ErrExecutionReverted
+}
+
 func NewResult(status uint64, bloom types.Bloom, logs []*types.Log, contractAddress common.Address, gasUsed uint64) Receipt {
 	return &result{
 		status:          status,
@@ -115,7 +154,8 @@ func ReceiptEqual(x, y Receipt) bool {
 		x.GetBloom() == y.GetBloom() &&
 		(len(rLogs)) == len(yLogs) &&
 		x.GetContractAddress() == y.GetContractAddress() &&
-		x.GetGasUsed() == y.GetGasUsed()
+		x.GetGasUsed() == y.GetGasUsed() &&
+		vmErrorEqual(x, y)
 	if !equal {
 		return false
 	}
@@ -140,3 +180,24 @@ func ReceiptEqual(x, y Receipt) bool {
 
 	return true
 }
+
+// vmErrorEqual compares revert reasons of both receipts. Receipts which
+// do not report their vm error are considered equal to any other receipt.
+func vmErrorEqual(x, y Receipt) bool {
+	xr, ok := x.(VmErrorReporter)
+	if !ok {
+		return true
+	}
+	yr, ok := y.(VmErrorReporter)
+	if !ok {
+		return true
+	}
+
+	var xRevert, yRevert *RevertError
+	xIsRevert := errors.As(xr.GetVmError(), &xRevert)
+	yIsRevert := errors.As(yr.GetVmError(), &yRevert)
+	if xIsRevert != yIsRevert {
+		return false
+	}
+	return !xIsRevert || xRevert.Reason == yRevert.Reason
+}
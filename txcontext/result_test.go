@@ -17,10 +17,12 @@
 package txcontext
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -168,3 +170,60 @@ func TestResult_ReceiptEqual2(t *testing.T) {
 	differentReceipt := NewResult(0, bloom, logs, contractAddress, gasUsed)
 	assert.False(t, receipt.Equal(differentReceipt))
 }
+
+func TestRevertError_NewRevertError(t *testing.T) {
+	errorData := append(common.FromHex("0x08c379a0"), common.LeftPadBytes([]byte{0x20}, 32)...)
+	errorData = append(errorData, common.LeftPadBytes([]byte{4}, 32)...)
+	errorData = append(errorData, common.RightPadBytes([]byte("boom"), 32)...)
+
+	tests := map[string]struct {
+		output  []byte
+		wantMsg string
+	}{
+		"empty":   {output: nil, wantMsg: "execution reverted"},
+		"string":  {output: errorData, wantMsg: "execution reverted: boom"},
+		"panic":   {output: append(common.FromHex("0x4e487b71"), common.LeftPadBytes([]byte{0x01}, 32)...), wantMsg: "execution reverted: assert(false)"},
+		"unknown": {output: []byte{1, 2, 3, 4, 5}, wantMsg: "execution reverted"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := NewRevertError(test.output)
+			assert.EqualError(t, err, test.wantMsg)
+			assert.ErrorIs(t, err, vm.ErrExecutionReverted)
+		})
+	}
+}
+
+type vmErrorReceipt struct {
+	result
+	vmErr error
+}
+
+func (r vmErrorReceipt) GetVmError() error {
+	return r.vmErr
+}
+
+func TestResult_ReceiptEqual_ComparesRevertReasons(t *testing.T) {
+	tests := map[string]struct {
+		x, y  error
+		equal bool
+	}{
+		"no errors":             {equal: true},
+		"equal reasons":         {x: &RevertError{Reason: "boom"}, y: &RevertError{Reason: "boom"}, equal: true},
+		"different reasons":     {x: &RevertError{Reason: "boom"}, y: &RevertError{Reason: "bang"}},
+		"revert and failure":    {x: &RevertError{}, y: errors.New("transaction failed")},
+		"different failures":    {x: vm.ErrOutOfGas, y: errors.New("transaction failed"), equal: true},
+		"revert without reason": {x: &RevertError{}, y: &RevertError{}, equal: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			x := vmErrorReceipt{vmErr: test.x}
+			y := vmErrorReceipt{vmErr: test.y}
+			assert.Equal(t, test.equal, ReceiptEqual(x, y))
+			// receipts not reporting vm errors are compared without them
+			assert.True(t, ReceiptEqual(x, result{}))
+		})
+	}
+}
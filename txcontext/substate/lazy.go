@@ -0,0 +1,104 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package substate
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/0xsoniclabs/substate/substate"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+)
+
+// DecodeFunc decodes the substate of given transaction from its encoded form.
+type DecodeFunc func(encoded []byte, block uint64, tx int) (*substate.Substate, error)
+
+// NewLazyTxContext creates a TxContext holding the encoded substate of given transaction.
+// The substate is decoded by Decode, or transparently by the first access to its content.
+func NewLazyTxContext(encoded []byte, block uint64, tx int, decode DecodeFunc) *LazyTxContext {
+	return &LazyTxContext{
+		encoded: encoded,
+		block:   block,
+		tx:      tx,
+		decode:  decode,
+	}
+}
+
+// LazyTxContext defers decoding of a substate until it is needed, so that
+// substates waiting for their execution are held in the compact encoded form.
+type LazyTxContext struct {
+	encoded []byte
+	block   uint64
+	tx      int
+	decode  DecodeFunc
+
+	once sync.Once
+	data txcontext.TxContext
+	err  error
+}
+
+// Decode decodes the substate unless it has been decoded already.
+func (t *LazyTxContext) Decode() error {
+	t.once.Do(func() {
+		var ss *substate.Substate
+		ss, t.err = t.decode(t.encoded, t.block, t.tx)
+		if t.err == nil {
+			t.data = NewTxContext(ss)
+		}
+		t.encoded = nil
+	})
+	return t.err
+}
+
+// decoded returns the decoded substate. Since the TxContext interface cannot report errors,
+// a failed decoding panics; callers are expected to use Decode to handle the error first.
+func (t *LazyTxContext) decoded() txcontext.TxContext {
+	if err := t.Decode(); err != nil {
+		panic(fmt.Sprintf("cannot decode substate of block %v tx %v; %v", t.block, t.tx, err))
+	}
+	return t.data
+}
+
+func (t *LazyTxContext) GetLogsHash() common.Hash {
+	return t.decoded().GetLogsHash()
+}
+
+func (t *LazyTxContext) GetStateHash() common.Hash {
+	return t.decoded().GetStateHash()
+}
+
+func (t *LazyTxContext) GetInputState() txcontext.WorldState {
+	return t.decoded().GetInputState()
+}
+
+func (t *LazyTxContext) GetOutputState() txcontext.WorldState {
+	return t.decoded().GetOutputState()
+}
+
+func (t *LazyTxContext) GetBlockEnvironment() txcontext.BlockEnvironment {
+	return t.decoded().GetBlockEnvironment()
+}
+
+func (t *LazyTxContext) GetMessage() *core.Message {
+	return t.decoded().GetMessage()
+}
+
+func (t *LazyTxContext) GetResult() txcontext.Result {
+	return t.decoded().GetResult()
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package substate
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/substate/substate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyTxContext_DecodesOnlyOnce(t *testing.T) {
+	calls := 0
+	ss := &substate.Substate{
+		Env:     &substate.Env{Number: 10, Difficulty: big.NewInt(1)},
+		Message: &substate.Message{Value: big.NewInt(12), GasPrice: big.NewInt(14)},
+		Result:  &substate.Result{GasUsed: 21_000},
+	}
+	ctx := NewLazyTxContext([]byte{1, 2, 3}, 10, 7, func(encoded []byte, block uint64, tx int) (*substate.Substate, error) {
+		calls++
+		assert.Equal(t, []byte{1, 2, 3}, encoded)
+		assert.Equal(t, uint64(10), block)
+		assert.Equal(t, 7, tx)
+		return ss, nil
+	})
+
+	assert.NoError(t, ctx.Decode())
+	assert.NoError(t, ctx.Decode())
+	assert.Equal(t, uint64(10), ctx.GetBlockEnvironment().GetNumber())
+	assert.Equal(t, uint64(21_000), ctx.GetResult().GetGasUsed())
+	assert.Equal(t, 1, calls)
+	assert.Nil(t, ctx.encoded, "encoded substate should be released after decoding")
+}
+
+func TestLazyTxContext_AccessDecodesTransparently(t *testing.T) {
+	ctx := NewLazyTxContext(nil, 10, 7, func([]byte, uint64, int) (*substate.Substate, error) {
+		return &substate.Substate{Message: &substate.Message{Value: big.NewInt(12), GasPrice: big.NewInt(14)}}, nil
+	})
+	assert.Equal(t, big.NewInt(12), ctx.GetMessage().Value)
+}
+
+func TestLazyTxContext_DecodingErrorIsReported(t *testing.T) {
+	injectedErr := errors.New("injected error")
+	ctx := NewLazyTxContext(nil, 10, 7, func([]byte, uint64, int) (*substate.Substate, error) {
+		return nil, injectedErr
+	})
+
+	assert.ErrorIs(t, ctx.Decode(), injectedErr)
+	assert.ErrorIs(t, ctx.Decode(), injectedErr)
+	assert.PanicsWithValue(t, "cannot decode substate of block 10 tx 7; injected error", func() {
+		ctx.GetMessage()
+	})
+}
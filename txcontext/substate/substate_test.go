@@ -199,7 +199,8 @@ func TestSubstateData_GetMessage(t *testing.T) {
 	assert.Nil(t, message2.BlobHashes)
 	assert.Nil(t, message2.BlobGasFeeCap)
 	assert.True(t, message2.SkipNonceChecks) // Should be true when CheckNonce is false
-	assert.Empty(t, message2.SetCodeAuthorizations)
+	// records predating Prague must not be executed as set-code transactions
+	assert.Nil(t, message2.SetCodeAuthorizations)
 
 	// Test with empty arrays
 	ss3 := &substateData{
@@ -218,7 +219,7 @@ func TestSubstateData_GetMessage(t *testing.T) {
 	assert.Equal(t, from, message3.From)
 	assert.Nil(t, message3.AccessList)
 	assert.Nil(t, message3.BlobHashes)
-	assert.Empty(t, message3.SetCodeAuthorizations)
+	assert.Nil(t, message3.SetCodeAuthorizations)
 }
 
 func TestSubstateData_NewTxContext(t *testing.T) {
@@ -0,0 +1,249 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package txgenerator
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// NonceSource returns the current nonce of an account in the StateDB the
+// generated transactions are executed on.
+type NonceSource func(common.Address) (uint64, error)
+
+// BalanceSource returns the current balance of an account in the StateDB the
+// generated transactions are executed on.
+type BalanceSource func(common.Address) (*big.Int, error)
+
+// FunderAddress is the account sending funding transactions to accounts whose balance
+// does not cover the cost of their next transaction. It has to be funded by the provider
+// before any transaction is generated.
+var FunderAddress = common.BytesToAddress(crypto.Keccak256([]byte("aida-tx-generator-funder")))
+
+// FundingAmount is the amount transferred to an account by a single funding transaction.
+var FundingAmount = new(big.Int).Mul(big.NewInt(params.Ether), big.NewInt(1_000))
+
+// generatedTxGasPrice is the gas price of generated transactions. Generated transactions
+// are free, hence their senders only need to be funded if they transfer value.
+var generatedTxGasPrice = big.NewInt(0)
+
+// AccountRegistry tracks nonces and balances of all accounts sending generated
+// transactions. A single registry is shared by all generators of a run and is safe
+// for concurrent use, so every transaction of an account receives the next nonce
+// regardless of the generator or goroutine creating it.
+//
+// Nonces and balances are read from the StateDB on the first use of an account only;
+// afterward the registry is the authority. Thus, they do not depend on how far the
+// execution of already generated transactions has progressed.
+type AccountRegistry struct {
+	lock      sync.Mutex
+	nonces    map[common.Address]uint64
+	balances  map[common.Address]*big.Int
+	nonceAt   NonceSource
+	balanceAt BalanceSource
+}
+
+// NewAccountRegistry creates a registry reading initial nonces and balances of accounts
+// from the given sources. Accounts start with zero nonce and balance if a source is nil.
+func NewAccountRegistry(nonceAt NonceSource, balanceAt BalanceSource) *AccountRegistry {
+	return &AccountRegistry{
+		nonces:    make(map[common.Address]uint64),
+		balances:  make(map[common.Address]*big.Int),
+		nonceAt:   nonceAt,
+		balanceAt: balanceAt,
+	}
+}
+
+// reserve assigns the next nonce of sender to a transaction costing cost and charges
+// the cost to the balance of sender. If the balance does not cover the cost, a funding
+// transaction from FunderAddress is reserved as well and returned; it has to be
+// executed before the transaction of sender. Otherwise, the returned funding is nil.
+func (r *AccountRegistry) reserve(sender common.Address, cost *big.Int) (uint64, *types.Transaction, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var funding *types.Transaction
+	if cost.Sign() > 0 {
+		balance, err := r.balance(sender)
+		if err != nil {
+			return 0, nil, err
+		}
+		if balance.Cmp(cost) < 0 {
+			funding, err = r.fund(sender, balance, cost)
+			if err != nil {
+				return 0, nil, err
+			}
+		}
+		balance.Sub(balance, cost)
+	}
+	nonce, err := r.nextNonce(sender)
+	if err != nil {
+		return 0, nil, err
+	}
+	return nonce, funding, nil
+}
+
+// fund creates a transfer from the funder to addr covering the cost; must be called with the lock held.
+func (r *AccountRegistry) fund(addr common.Address, balance, cost *big.Int) (*types.Transaction, error) {
+	amount := new(big.Int).Set(FundingAmount)
+	if missing := new(big.Int).Sub(cost, balance); amount.Cmp(missing) < 0 {
+		amount = missing
+	}
+	funderBalance, err := r.balance(FunderAddress)
+	if err != nil {
+		return nil, err
+	}
+	if funderBalance.Cmp(amount) < 0 {
+		return nil, fmt.Errorf("funder %v cannot fund %v with %v, its balance is %v", FunderAddress, addr, amount, funderBalance)
+	}
+	nonce, err := r.nextNonce(FunderAddress)
+	if err != nil {
+		return nil, err
+	}
+	funderBalance.Sub(funderBalance, amount)
+	balance.Add(balance, amount)
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       &addr,
+		Gas:      params.TxGas,
+		GasPrice: generatedTxGasPrice,
+		Value:    amount,
+	}), nil
+}
+
+// nextNonce returns the nonce of the next transaction sent by addr; must be called with the lock held.
+func (r *AccountRegistry) nextNonce(addr common.Address) (uint64, error) {
+	nonce, found := r.nonces[addr]
+	if !found && r.nonceAt != nil {
+		var err error
+		nonce, err = r.nonceAt(addr)
+		if err != nil {
+			return 0, fmt.Errorf("cannot get nonce of %v; %w", addr, err)
+		}
+	}
+	r.nonces[addr] = nonce + 1
+	return nonce, nil
+}
+
+// balance returns the tracked balance of addr, which is updated in place; must be called with the lock held.
+func (r *AccountRegistry) balance(addr common.Address) (*big.Int, error) {
+	if balance, found := r.balances[addr]; found {
+		return balance, nil
+	}
+	balance := new(big.Int)
+	if r.balanceAt != nil {
+		current, err := r.balanceAt(addr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get balance of %v; %w", addr, err)
+		}
+		balance.Set(current)
+	}
+	r.balances[addr] = balance
+	return balance, nil
+}
+
+// accountSet is a set of deterministically derived accounts sending generated
+// transactions of a single generator. Generated transactions are not signed.
+type accountSet struct {
+	addresses []common.Address
+	registry  *AccountRegistry
+	gasPrice  *big.Int
+	funding   *types.Transaction // funding transaction reserved by the last call of nextNonce
+	delayed   *types.Transaction // transaction waiting for the execution of its funding transaction
+	delayedBy common.Address     // sender of the delayed transaction
+}
+
+// newAccountSet derives numAccounts addresses unique for the given generator name.
+// Nonces and balances of the accounts are tracked by the registry; a private
+// registry starting all accounts with nonce 0 is used if registry is nil.
+func newAccountSet(name string, numAccounts int, registry *AccountRegistry) (*accountSet, error) {
+	if numAccounts <= 0 {
+		return nil, fmt.Errorf("number of accounts of the %v generator must be positive, got %d", name, numAccounts)
+	}
+	if registry == nil {
+		registry = NewAccountRegistry(nil, nil)
+	}
+	addresses := make([]common.Address, numAccounts)
+	for i := range addresses {
+		addresses[i] = common.BytesToAddress(crypto.Keccak256([]byte(fmt.Sprintf("aida-tx-generator-%v-%d", name, i))))
+	}
+	return &accountSet{
+		addresses: addresses,
+		registry:  registry,
+		gasPrice:  generatedTxGasPrice,
+	}, nil
+}
+
+// random returns a randomly chosen account of the set.
+func (s *accountSet) random(rnd *rand.Rand) common.Address {
+	return s.addresses[rnd.Intn(len(s.addresses))]
+}
+
+// nextNonce returns the nonce of the next transaction sent by addr limited to gas.
+// A funding transaction required by the transaction is emitted by generate first.
+func (s *accountSet) nextNonce(addr common.Address, gas uint64) (uint64, error) {
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(gas), s.gasPrice)
+	nonce, funding, err := s.registry.reserve(addr, cost)
+	if err != nil {
+		return 0, err
+	}
+	s.funding = funding
+	return nonce, nil
+}
+
+// generate returns the next transaction to be executed along with its sender. This is
+// the transaction delayed by a funding transaction, if any. Otherwise, a new transaction
+// is created by next; if its sender needs to be funded, the funding transaction is
+// returned instead and the new transaction is delayed until the following call.
+func (s *accountSet) generate(next func() (*types.Transaction, common.Address, error)) (*types.Transaction, common.Address, error) {
+	if s.delayed != nil {
+		tx, sender := s.delayed, s.delayedBy
+		s.delayed, s.delayedBy = nil, common.Address{}
+		return tx, sender, nil
+	}
+	tx, sender, err := next()
+	if err != nil {
+		s.funding = nil
+		return nil, common.Address{}, err
+	}
+	if s.funding == nil {
+		return tx, sender, nil
+	}
+	funding := s.funding
+	s.funding = nil
+	s.delayed, s.delayedBy = tx, sender
+	return funding, FunderAddress, nil
+}
+
+// newTx creates an unsigned transaction without value. Contracts are deployed if to is nil.
+func (s *accountSet) newTx(nonce uint64, to *common.Address, gas uint64, data []byte) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       to,
+		Gas:      gas,
+		GasPrice: s.gasPrice,
+		Value:    big.NewInt(0),
+		Data:     data,
+	})
+}
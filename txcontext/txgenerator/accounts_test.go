@@ -0,0 +1,187 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package txgenerator
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountRegistry_ConcurrentGeneratorsProduceUniqueNonces(t *testing.T) {
+	const (
+		numWorkers     = 8
+		numBlocks      = 20
+		txsPerBlock    = 25
+		numAccounts    = 5
+		startingNonces = 3
+	)
+	registry := NewAccountRegistry(func(common.Address) (uint64, error) {
+		return startingNonces, nil
+	}, nil)
+
+	type key struct {
+		sender common.Address
+		nonce  uint64
+	}
+	var (
+		lock sync.Mutex
+		seen = make(map[key]int)
+		wg   sync.WaitGroup
+	)
+	for worker := range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// all generators share the same accounts, since they have the same type
+			gen, err := NewCreateGenerator(numAccounts, 0.5, int64(worker), registry)
+			if !assert.NoError(t, err) {
+				return
+			}
+			for range numBlocks {
+				block := make([]key, 0, txsPerBlock)
+				for range txsPerBlock {
+					tx, err := gen.GenerateTx()
+					if !assert.NoError(t, err) {
+						return
+					}
+					block = append(block, key{gen.SenderAddress(), tx.Nonce()})
+				}
+				lock.Lock()
+				for _, k := range block {
+					seen[k]++
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, seen, numWorkers*numBlocks*txsPerBlock, "generated (sender, nonce) pairs must be unique")
+	perSender := make(map[common.Address][]uint64)
+	for k, count := range seen {
+		assert.Equal(t, 1, count, "nonce %d of %v was generated %d times", k.nonce, k.sender, count)
+		perSender[k.sender] = append(perSender[k.sender], k.nonce)
+	}
+	// nonces of each account are consecutive, starting from the nonce of the source
+	for sender, nonces := range perSender {
+		slices.Sort(nonces)
+		for i, nonce := range nonces {
+			require.Equal(t, uint64(startingNonces+i), nonce, "nonce gap of %v", sender)
+		}
+	}
+}
+
+func TestAccountRegistry_NoncesAreSharedBetweenGenerators(t *testing.T) {
+	registry := NewAccountRegistry(nil, nil)
+	a, err := NewErc20Generator(1, 1, registry)
+	require.NoError(t, err)
+	b, err := NewErc20Generator(1, 2, registry)
+	require.NoError(t, err)
+
+	for i := range 6 {
+		gen := a
+		if i%2 == 1 {
+			gen = b
+		}
+		tx, err := gen.GenerateTx()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(i), tx.Nonce())
+	}
+	assert.NotEqual(t, a.Token(), b.Token())
+}
+
+func TestAccountRegistry_FundsAccountsWhoseBalanceDoesNotCoverCost(t *testing.T) {
+	funderBalance := new(big.Int).Mul(big.NewInt(params.Ether), big.NewInt(1_000_000))
+	registry := NewAccountRegistry(nil, func(addr common.Address) (*big.Int, error) {
+		if addr == FunderAddress {
+			return funderBalance, nil
+		}
+		return big.NewInt(0), nil
+	})
+	gen, err := NewCreateGenerator(1, 1, 1, registry)
+	require.NoError(t, err)
+	gen.accounts.gasPrice = big.NewInt(1)
+	sender := gen.accounts.addresses[0]
+
+	// the funding transaction precedes the first transaction of the account
+	funding, err := gen.GenerateTx()
+	require.NoError(t, err)
+	assert.Equal(t, FunderAddress, gen.SenderAddress())
+	assert.Equal(t, &sender, funding.To())
+	assert.Equal(t, FundingAmount, funding.Value())
+	assert.Equal(t, uint64(0), funding.Nonce())
+
+	tx, err := gen.GenerateTx()
+	require.NoError(t, err)
+	assert.Equal(t, sender, gen.SenderAddress())
+	assert.Nil(t, tx.To())
+	assert.Equal(t, uint64(0), tx.Nonce())
+
+	// the funded amount covers many further transactions
+	for range 10 {
+		_, err = gen.GenerateTx()
+		require.NoError(t, err)
+		assert.Equal(t, sender, gen.SenderAddress())
+	}
+	assert.Equal(t, uint64(12), gen.GetSentTransactions())
+
+	cost := new(big.Int).Mul(big.NewInt(createDeployGas), big.NewInt(11))
+	assert.Equal(t, new(big.Int).Sub(FundingAmount, cost), registry.balances[sender])
+	assert.Equal(t, new(big.Int).Sub(funderBalance, FundingAmount), registry.balances[FunderAddress])
+}
+
+func TestAccountRegistry_FreeTransactionsDoNotReadBalances(t *testing.T) {
+	registry := NewAccountRegistry(nil, func(common.Address) (*big.Int, error) {
+		t.Fatal("balance must not be read")
+		return nil, nil
+	})
+	gen, err := NewCreateGenerator(2, 0.5, 1, registry)
+	require.NoError(t, err)
+	txs, senders := generateTxs(t, gen, 10)
+	for i := range txs {
+		assert.NotEqual(t, FunderAddress, senders[i])
+	}
+}
+
+func TestAccountRegistry_ReportsFunderWithoutBalance(t *testing.T) {
+	gen, err := NewCreateGenerator(1, 1, 1, NewAccountRegistry(nil, nil))
+	require.NoError(t, err)
+	gen.accounts.gasPrice = big.NewInt(1)
+
+	_, err = gen.GenerateTx()
+	require.ErrorContains(t, err, "cannot fund")
+}
+
+func TestAccountRegistry_ReportsBalanceSourceError(t *testing.T) {
+	injectedErr := errors.New("injected error")
+	gen, err := NewCreateGenerator(1, 1, 1, NewAccountRegistry(nil, func(common.Address) (*big.Int, error) {
+		return nil, injectedErr
+	}))
+	require.NoError(t, err)
+	gen.accounts.gasPrice = big.NewInt(1)
+
+	_, err = gen.GenerateTx()
+	require.ErrorIs(t, err, injectedErr)
+}
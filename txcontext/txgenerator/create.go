@@ -0,0 +1,140 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package txgenerator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CreateGeneratorType is the tx generator type of the CreateGenerator.
+const CreateGeneratorType = "create"
+
+const (
+	createDeployGas = 100_000
+	createCallGas   = 50_000
+)
+
+// CreateGenerator generates transactions deploying unique small contracts. The
+// fraction of deploying transactions is set by the create rate; all other
+// transactions call one of the deployed contracts, which stores the first word
+// of the call data in its storage slot 0.
+type CreateGenerator struct {
+	accounts  *accountSet
+	rnd       *rand.Rand
+	rate      float64
+	contracts []common.Address
+	sender    common.Address
+	sentTxs   uint64
+}
+
+// NewCreateGenerator creates a generator using numAccounts accounts for deploying
+// and calling contracts. The rate must be within [0, 1]; the first transaction is
+// always a deployment. The generated sequence of transactions is determined by the seed.
+// Nonces of the accounts are tracked by the registry, see newAccountSet.
+func NewCreateGenerator(numAccounts int, rate float64, seed int64, registry *AccountRegistry) (*CreateGenerator, error) {
+	if rate < 0 || rate > 1 {
+		return nil, fmt.Errorf("create rate must be within [0, 1], got %v", rate)
+	}
+	accounts, err := newAccountSet(CreateGeneratorType, numAccounts, registry)
+	if err != nil {
+		return nil, err
+	}
+	return &CreateGenerator{
+		accounts: accounts,
+		rnd:      rand.New(rand.NewSource(seed)),
+		rate:     rate,
+	}, nil
+}
+
+// GenerateTx returns the next transaction of the sequence.
+func (g *CreateGenerator) GenerateTx() (*types.Transaction, error) {
+	tx, sender, err := g.accounts.generate(g.nextTx)
+	if err != nil {
+		return nil, err
+	}
+	g.sender = sender
+	g.sentTxs++
+	return tx, nil
+}
+
+// nextTx creates the next deployment or call along with its sender.
+func (g *CreateGenerator) nextTx() (*types.Transaction, common.Address, error) {
+	sender := g.accounts.random(g.rnd)
+	if len(g.contracts) == 0 || g.rnd.Float64() < g.rate {
+		nonce, err := g.accounts.nextNonce(sender, createDeployGas)
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+		code := createInitCode(uint64(len(g.contracts)))
+		g.contracts = append(g.contracts, crypto.CreateAddress(sender, nonce))
+		return g.accounts.newTx(nonce, nil, createDeployGas, code), sender, nil
+	}
+
+	to := g.contracts[g.rnd.Intn(len(g.contracts))]
+	var value common.Hash
+	g.rnd.Read(value[:])
+	nonce, err := g.accounts.nextNonce(sender, createCallGas)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	return g.accounts.newTx(nonce, &to, createCallGas, value[:]), sender, nil
+}
+
+// GetSentTransactions returns the number of generated transactions.
+func (g *CreateGenerator) GetSentTransactions() uint64 {
+	return g.sentTxs
+}
+
+// SenderAddress returns the sender of the last generated transaction.
+func (g *CreateGenerator) SenderAddress() common.Address {
+	return g.sender
+}
+
+// Contracts returns the addresses of contracts deployed so far.
+func (g *CreateGenerator) Contracts() []common.Address {
+	return g.contracts
+}
+
+// createInitCode returns the init code of a contract storing the first word of
+// its call data in slot 0. The id is embedded in the code to make the code of
+// each contract unique.
+func createInitCode(id uint64) []byte {
+	runtime := []byte{
+		byte(vm.PUSH1), 0, byte(vm.CALLDATALOAD),
+		byte(vm.PUSH1), 0, byte(vm.SSTORE),
+		byte(vm.PUSH8),
+	}
+	runtime = binary.BigEndian.AppendUint64(runtime, id)
+	runtime = append(runtime, byte(vm.POP), byte(vm.STOP))
+
+	// copies the runtime code following the init code to memory and returns it
+	init := []byte{
+		byte(vm.PUSH1), byte(len(runtime)), byte(vm.DUP1),
+		byte(vm.PUSH1), 0, // offset of the runtime code, set below
+		byte(vm.PUSH1), 0, byte(vm.CODECOPY),
+		byte(vm.PUSH1), 0, byte(vm.RETURN),
+	}
+	init[4] = byte(len(init))
+	return append(init, runtime...)
+}
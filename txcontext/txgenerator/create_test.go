@@ -0,0 +1,111 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package txgenerator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateGenerator_DeploymentsFollowCreateRate(t *testing.T) {
+	tests := map[string]struct {
+		rate        float64
+		numTxs      int
+		deployments int
+	}{
+		"only deployments": {rate: 1, numTxs: 10, deployments: 10},
+		"only calls":       {rate: 0, numTxs: 10, deployments: 1},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			gen, err := NewCreateGenerator(3, test.rate, 1, nil)
+			require.NoError(t, err)
+			txs, _ := generateTxs(t, gen, test.numTxs)
+			deployments := 0
+			for _, tx := range txs {
+				if tx.To() == nil {
+					deployments++
+				}
+			}
+			assert.Equal(t, test.deployments, deployments)
+			assert.Len(t, gen.Contracts(), test.deployments)
+			assert.Equal(t, uint64(test.numTxs), gen.GetSentTransactions())
+		})
+	}
+}
+
+func TestCreateGenerator_DeploysUniqueContracts(t *testing.T) {
+	gen, err := NewCreateGenerator(2, 1, 1, nil)
+	require.NoError(t, err)
+	txs, senders := generateTxs(t, gen, 20)
+	db := executeGeneratedTxs(t, txs, senders)
+
+	codeHashes := make(map[common.Hash]struct{})
+	for i, addr := range gen.Contracts() {
+		assert.Equal(t, crypto.CreateAddress(senders[i], txs[i].Nonce()), addr)
+		code := db.GetCode(addr)
+		require.NotEmpty(t, code, "contract %v was not deployed", addr)
+		codeHashes[crypto.Keccak256Hash(code)] = struct{}{}
+	}
+	assert.Len(t, codeHashes, len(gen.Contracts()))
+}
+
+func TestCreateGenerator_CallsWriteStorageOfDeployedContracts(t *testing.T) {
+	gen, err := NewCreateGenerator(4, 0.3, 5, nil)
+	require.NoError(t, err)
+	txs, senders := generateTxs(t, gen, 50)
+	db := executeGeneratedTxs(t, txs, senders)
+
+	written := make(map[common.Address]common.Hash)
+	for _, tx := range txs {
+		if tx.To() != nil {
+			require.Contains(t, gen.Contracts(), *tx.To())
+			written[*tx.To()] = common.BytesToHash(tx.Data())
+		}
+	}
+	require.NotEmpty(t, written)
+	for _, addr := range gen.Contracts() {
+		assert.Equal(t, written[addr], db.GetState(addr, common.Hash{}), "slot 0 of %v", addr)
+	}
+}
+
+func TestCreateGenerator_IsDeterministic(t *testing.T) {
+	data := func(seed int64) [][]byte {
+		gen, err := NewCreateGenerator(3, 0.5, seed, nil)
+		require.NoError(t, err)
+		txs, _ := generateTxs(t, gen, 30)
+		res := make([][]byte, len(txs))
+		for i, tx := range txs {
+			res[i] = bytes.Clone(tx.Data())
+		}
+		return res
+	}
+	assert.Equal(t, data(3), data(3))
+	assert.NotEqual(t, data(3), data(4))
+}
+
+func TestNewCreateGenerator_RejectsInvalidParameters(t *testing.T) {
+	_, err := NewCreateGenerator(1, 1.5, 1, nil)
+	require.ErrorContains(t, err, "create rate")
+	_, err = NewCreateGenerator(0, 0.5, 1, nil)
+	require.ErrorContains(t, err, "must be positive")
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package txgenerator
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+
+	contract "github.com/Fantom-foundation/Norma/load/contracts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Erc20GeneratorType is the tx generator type of the Erc20Generator.
+const Erc20GeneratorType = "erc20"
+
+const (
+	erc20DeployGas = 1_500_000
+	erc20CallGas   = 100_000
+	// erc20MintedAmount is the amount of tokens minted for each account.
+	erc20MintedAmount = 1_000_000_000_000_000_000
+	// erc20MaxAmount is the maximum amount of tokens transferred or approved by a single transaction.
+	erc20MaxAmount = 100
+)
+
+// Erc20Generator generates transactions of an ERC-20 token shared by a set of
+// accounts. The first transaction deploys the token contract and is followed by
+// minting tokens for each of the accounts. All further transactions are random
+// transfer and approve calls between the accounts.
+type Erc20Generator struct {
+	abi      *abi.ABI
+	accounts *accountSet
+	rnd      *rand.Rand
+	deployer common.Address
+	token    common.Address
+	deployed bool
+	minted   int // number of accounts which received tokens
+	sender   common.Address
+	sentTxs  uint64
+}
+
+// NewErc20Generator creates a generator using numAccounts accounts. The generated
+// sequence of transactions is determined by the seed. Nonces of the accounts are
+// tracked by the registry, see newAccountSet.
+func NewErc20Generator(numAccounts int, seed int64, registry *AccountRegistry) (*Erc20Generator, error) {
+	accounts, err := newAccountSet(Erc20GeneratorType, numAccounts, registry)
+	if err != nil {
+		return nil, err
+	}
+	parsedAbi, err := contract.ERC20MetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse ERC-20 abi; %w", err)
+	}
+	return &Erc20Generator{
+		abi:      parsedAbi,
+		accounts: accounts,
+		rnd:      rand.New(rand.NewSource(seed)),
+		deployer: accounts.addresses[0],
+	}, nil
+}
+
+// GenerateTx returns the next transaction of the sequence.
+func (g *Erc20Generator) GenerateTx() (*types.Transaction, error) {
+	tx, sender, err := g.accounts.generate(g.nextTx)
+	if err != nil {
+		return nil, err
+	}
+	g.sender = sender
+	g.sentTxs++
+	return tx, nil
+}
+
+// nextTx creates the next deployment, mint or call along with its sender.
+func (g *Erc20Generator) nextTx() (*types.Transaction, common.Address, error) {
+	switch {
+	case !g.deployed:
+		return g.deploy()
+	case g.minted < len(g.accounts.addresses):
+		recipient := g.accounts.addresses[g.minted]
+		g.minted++
+		return g.mint(recipient)
+	default:
+		return g.call()
+	}
+}
+
+func (g *Erc20Generator) deploy() (*types.Transaction, common.Address, error) {
+	args, err := g.abi.Pack("", "Aida Token", "AIDA")
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("cannot pack ERC-20 constructor arguments; %w", err)
+	}
+	nonce, err := g.accounts.nextNonce(g.deployer, erc20DeployGas)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	g.deployed = true
+	g.token = crypto.CreateAddress(g.deployer, nonce)
+	data := append(common.FromHex(contract.ERC20MetaData.Bin), args...)
+	return g.accounts.newTx(nonce, nil, erc20DeployGas, data), g.deployer, nil
+}
+
+func (g *Erc20Generator) mint(recipient common.Address) (*types.Transaction, common.Address, error) {
+	return g.newCall(g.deployer, "mint", recipient, new(big.Int).SetUint64(erc20MintedAmount))
+}
+
+// call issues a transfer or an approval of a random amount between two random accounts.
+func (g *Erc20Generator) call() (*types.Transaction, common.Address, error) {
+	method := "transfer"
+	if g.rnd.Intn(2) == 1 {
+		method = "approve"
+	}
+	sender := g.accounts.random(g.rnd)
+	counterpart := g.accounts.random(g.rnd)
+	amount := big.NewInt(g.rnd.Int63n(erc20MaxAmount) + 1)
+	return g.newCall(sender, method, counterpart, amount)
+}
+
+func (g *Erc20Generator) newCall(sender common.Address, method string, args ...any) (*types.Transaction, common.Address, error) {
+	data, err := g.abi.Pack(method, args...)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("cannot pack ERC-20 %v call; %w", method, err)
+	}
+	nonce, err := g.accounts.nextNonce(sender, erc20CallGas)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	return g.accounts.newTx(nonce, &g.token, erc20CallGas, data), sender, nil
+}
+
+// GetSentTransactions returns the number of generated transactions.
+func (g *Erc20Generator) GetSentTransactions() uint64 {
+	return g.sentTxs
+}
+
+// SenderAddress returns the sender of the last generated transaction.
+func (g *Erc20Generator) SenderAddress() common.Address {
+	return g.sender
+}
+
+// Token returns the address of the token contract, which is known once the
+// deploying transaction was generated.
+func (g *Erc20Generator) Token() common.Address {
+	return g.token
+}
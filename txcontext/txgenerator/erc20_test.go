@@ -0,0 +1,199 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package txgenerator
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	contract "github.com/Fantom-foundation/Norma/load/contracts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	gethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErc20Generator_DeploysTokenAndMintsForEachAccount(t *testing.T) {
+	const numAccounts = 3
+	gen, err := NewErc20Generator(numAccounts, 1, nil)
+	require.NoError(t, err)
+	txs, senders := generateTxs(t, gen, 1+numAccounts)
+
+	assert.Nil(t, txs[0].To())
+	assert.NotEqual(t, common.Address{}, gen.Token())
+	for i, tx := range txs[1:] {
+		require.Equal(t, gen.Token(), *tx.To())
+		method, args := unpackErc20Call(t, tx)
+		assert.Equal(t, "mint", method)
+		assert.Equal(t, gen.accounts.addresses[i], args[0])
+		assert.Equal(t, senders[0], senders[i+1], "all mints must be sent by the deployer")
+	}
+	assert.Equal(t, uint64(1+numAccounts), gen.GetSentTransactions())
+}
+
+func TestErc20Generator_TransfersAndApprovalsAreExecutable(t *testing.T) {
+	const (
+		numAccounts = 4
+		numCalls    = 50
+	)
+	gen, err := NewErc20Generator(numAccounts, 42, nil)
+	require.NoError(t, err)
+	txs, senders := generateTxs(t, gen, 1+numAccounts+numCalls)
+	db := executeGeneratedTxs(t, txs, senders)
+
+	// replay the calls to derive the expected token state
+	minted := new(big.Int).SetUint64(erc20MintedAmount)
+	balances := make(map[common.Address]*big.Int)
+	for _, addr := range gen.accounts.addresses {
+		balances[addr] = new(big.Int).Set(minted)
+	}
+	allowances := make(map[[2]common.Address]*big.Int)
+	methods := make(map[string]int)
+	for i, tx := range txs[1+numAccounts:] {
+		sender := senders[1+numAccounts+i]
+		method, args := unpackErc20Call(t, tx)
+		methods[method]++
+		counterpart, amount := args[0].(common.Address), args[1].(*big.Int)
+		assert.Contains(t, gen.accounts.addresses, counterpart)
+		switch method {
+		case "transfer":
+			balances[sender].Sub(balances[sender], amount)
+			balances[counterpart].Add(balances[counterpart], amount)
+		case "approve":
+			allowances[[2]common.Address{sender, counterpart}] = amount
+		default:
+			t.Fatalf("unexpected method %v", method)
+		}
+	}
+	assert.Equal(t, numCalls, methods["transfer"]+methods["approve"])
+	assert.NotZero(t, methods["transfer"])
+	assert.NotZero(t, methods["approve"])
+
+	totalSupply := callErc20(t, db, gen.Token(), "totalSupply")
+	assert.Equal(t, new(big.Int).Mul(minted, big.NewInt(numAccounts)), totalSupply)
+	for addr, want := range balances {
+		assert.Equal(t, want, callErc20(t, db, gen.Token(), "balanceOf", addr), "balance of %v", addr)
+	}
+	for pair, want := range allowances {
+		assert.Equal(t, want, callErc20(t, db, gen.Token(), "allowance", pair[0], pair[1]), "allowance of %v", pair)
+	}
+}
+
+func TestErc20Generator_IsDeterministic(t *testing.T) {
+	hashes := func(seed int64) []common.Hash {
+		gen, err := NewErc20Generator(5, seed, nil)
+		require.NoError(t, err)
+		txs, _ := generateTxs(t, gen, 30)
+		res := make([]common.Hash, len(txs))
+		for i, tx := range txs {
+			res[i] = tx.Hash()
+		}
+		return res
+	}
+	assert.Equal(t, hashes(7), hashes(7))
+	assert.NotEqual(t, hashes(7), hashes(8))
+}
+
+func TestErc20Generator_ContinuesFromNoncesOfNonceSource(t *testing.T) {
+	gen, err := NewErc20Generator(1, 1, NewAccountRegistry(func(common.Address) (uint64, error) {
+		return 5, nil
+	}, nil))
+	require.NoError(t, err)
+	txs, _ := generateTxs(t, gen, 3)
+	for i, tx := range txs {
+		assert.Equal(t, uint64(5+i), tx.Nonce())
+	}
+}
+
+func TestErc20Generator_ReportsNonceSourceError(t *testing.T) {
+	injectedErr := errors.New("injected error")
+	gen, err := NewErc20Generator(1, 1, NewAccountRegistry(func(common.Address) (uint64, error) {
+		return 0, injectedErr
+	}, nil))
+	require.NoError(t, err)
+	_, err = gen.GenerateTx()
+	require.ErrorIs(t, err, injectedErr)
+}
+
+func TestNewErc20Generator_RejectsEmptyAccountSet(t *testing.T) {
+	_, err := NewErc20Generator(0, 1, nil)
+	require.ErrorContains(t, err, "must be positive")
+}
+
+// txSource is implemented by all generators.
+type txSource interface {
+	GenerateTx() (*types.Transaction, error)
+	SenderAddress() common.Address
+}
+
+// generateTxs generates num transactions and returns them along with their senders.
+func generateTxs(t *testing.T, gen txSource, num int) ([]*types.Transaction, []common.Address) {
+	t.Helper()
+	txs := make([]*types.Transaction, num)
+	senders := make([]common.Address, num)
+	for i := range num {
+		tx, err := gen.GenerateTx()
+		require.NoError(t, err)
+		txs[i], senders[i] = tx, gen.SenderAddress()
+	}
+	return txs, senders
+}
+
+// executeGeneratedTxs executes the transactions in order on an empty geth state.
+func executeGeneratedTxs(t *testing.T, txs []*types.Transaction, senders []common.Address) *gethstate.StateDB {
+	t.Helper()
+	db, err := gethstate.New(types.EmptyRootHash, gethstate.NewDatabaseForTesting())
+	require.NoError(t, err)
+	for i, tx := range txs {
+		cfg := &runtime.Config{Origin: senders[i], State: db, GasLimit: tx.Gas()}
+		if tx.To() == nil {
+			// the address of a deployed contract is derived from the nonce of the sender
+			db.SetNonce(senders[i], tx.Nonce(), tracing.NonceChangeUnspecified)
+			_, _, _, err = runtime.Create(tx.Data(), cfg)
+		} else {
+			_, _, err = runtime.Call(*tx.To(), tx.Data(), cfg)
+		}
+		require.NoError(t, err, "transaction %d failed", i)
+	}
+	return db
+}
+
+func unpackErc20Call(t *testing.T, tx *types.Transaction) (string, []any) {
+	t.Helper()
+	parsedAbi, err := contract.ERC20MetaData.GetAbi()
+	require.NoError(t, err)
+	method, err := parsedAbi.MethodById(tx.Data())
+	require.NoError(t, err)
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
+	require.NoError(t, err)
+	return method.Name, args
+}
+
+func callErc20(t *testing.T, db *gethstate.StateDB, token common.Address, method string, args ...any) *big.Int {
+	t.Helper()
+	parsedAbi, err := contract.ERC20MetaData.GetAbi()
+	require.NoError(t, err)
+	input, err := parsedAbi.Pack(method, args...)
+	require.NoError(t, err)
+	output, _, err := runtime.Call(token, input, &runtime.Config{State: db})
+	require.NoError(t, err)
+	return new(big.Int).SetBytes(output)
+}
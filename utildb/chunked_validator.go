@@ -0,0 +1,273 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utildb
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/substate/db"
+)
+
+// DefaultDbHashChunkSize is the number of blocks covered by a single chunk of a chunked db-hash calculation.
+const DefaultDbHashChunkSize = 1_000_000
+
+// ChunkedDbHashConfig configures GenerateDbHashInChunks.
+type ChunkedDbHashConfig struct {
+	LastBlock  uint64 // last block of the db, determines the number of chunks
+	ChunkSize  uint64 // number of blocks covered by a chunk
+	Workers    int    // number of chunks read in parallel
+	Checkpoint string // path of the file recording completed chunks; empty disables checkpoints
+	Force      bool   // ignores an existing checkpoint and hashes all chunks again
+}
+
+// hashChunk is a contiguous key range of a prefix. A nil bound is unlimited.
+type hashChunk struct {
+	prefix     string
+	start, end []byte // start is relative to the prefix, end is an absolute key
+}
+
+// dbHashCheckpoint records the progress of an interrupted chunked db-hash calculation.
+type dbHashCheckpoint struct {
+	ChunkSize uint64   `json:"chunkSize"`
+	LastBlock uint64   `json:"lastBlock"`
+	State     []byte   `json:"state"`   // md5 state after hashing the completed chunks
+	Digests   []string `json:"digests"` // md5 digest of each completed chunk
+}
+
+// chunkStream hands key/value pairs of a chunk from its reader to the hasher.
+type chunkStream struct {
+	data   chan []byte
+	digest []byte // md5 digest of the chunk, valid once data is closed
+	err    error  // iteration error, valid once data is closed
+}
+
+// GenerateDbHashInChunks calculates the same hash as GenerateDbHash. The db is split into
+// chunks of cfg.ChunkSize blocks which are read by cfg.Workers workers in parallel, while the
+// hash itself is folded over the chunks in order, since an md5 sum cannot be combined from
+// partial sums. After each chunk, the md5 state is persisted in cfg.Checkpoint so that an
+// interrupted calculation resumes with the first chunk not hashed yet. The checkpoint is
+// removed once the hash is complete.
+func GenerateDbHashInChunks(ctx context.Context, base db.BaseDB, cfg ChunkedDbHashConfig, logLevel string) ([]byte, error) {
+	return generateDbHashInChunks(ctx, base, cfg, logger.NewLogger(logLevel, "Db-Validator"), nil)
+}
+
+// generateDbHashInChunks implements GenerateDbHashInChunks; onChunk is called after each hashed chunk.
+func generateDbHashInChunks(ctx context.Context, base db.BaseDB, cfg ChunkedDbHashConfig, log logger.Logger, onChunk func(idx int)) ([]byte, error) {
+	if cfg.ChunkSize == 0 {
+		cfg.ChunkSize = DefaultDbHashChunkSize
+	}
+	workers := max(1, cfg.Workers)
+
+	chunks := makeHashChunks(cfg.LastBlock, cfg.ChunkSize)
+	h := md5.New()
+	checkpoint := dbHashCheckpoint{ChunkSize: cfg.ChunkSize, LastBlock: cfg.LastBlock}
+	if cfg.Checkpoint != "" && !cfg.Force {
+		restored, err := restoreDbHashCheckpoint(cfg.Checkpoint, checkpoint, h)
+		if err != nil {
+			return nil, err
+		}
+		if restored != nil {
+			checkpoint = *restored
+			log.Noticef("Resuming db-hash calculation from checkpoint %v; %v of %v chunks are already hashed", cfg.Checkpoint, len(checkpoint.Digests), len(chunks))
+		}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		abort   = make(chan any)
+		slots   = make(chan any, workers)
+		pending = chunks[len(checkpoint.Digests):]
+		streams = make([]*chunkStream, len(pending))
+	)
+	for i := range streams {
+		streams[i] = &chunkStream{data: make(chan []byte, standardInputBufferSize)}
+	}
+	defer func() {
+		close(abort)
+		wg.Wait()
+	}()
+
+	// chunks are started in order and at most workers of them are read ahead of the hasher
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, chunk := range pending {
+			select {
+			case <-abort:
+				return
+			case slots <- nil:
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				readHashChunk(base, chunk, streams[i], abort)
+			}()
+		}
+	}()
+
+	interrupted := func(err error) error {
+		return fmt.Errorf("db-hash calculation interrupted after %v of %v chunks; %w", len(checkpoint.Digests), len(chunks), err)
+	}
+
+	start := time.Now()
+	for i, stream := range streams {
+		for data := range stream.data {
+			if err := ctx.Err(); err != nil {
+				return nil, interrupted(err)
+			}
+			h.Write(data)
+		}
+		if stream.err != nil {
+			return nil, fmt.Errorf("cannot iterate chunk of prefix %v; %w", pending[i].prefix, stream.err)
+		}
+		<-slots
+
+		idx := len(checkpoint.Digests)
+		checkpoint.Digests = append(checkpoint.Digests, hex.EncodeToString(stream.digest))
+		log.Infof("Chunk %v/%v (prefix %v) hashed; md5 %x; elapsed %v", idx+1, len(chunks), pending[i].prefix, stream.digest, time.Since(start).Round(time.Second))
+		if cfg.Checkpoint != "" && idx+1 < len(chunks) {
+			if err := writeDbHashCheckpoint(cfg.Checkpoint, checkpoint, h); err != nil {
+				return nil, err
+			}
+		}
+		if onChunk != nil {
+			onChunk(idx)
+		}
+		if err := ctx.Err(); err != nil && idx+1 < len(chunks) {
+			return nil, interrupted(err)
+		}
+	}
+
+	if cfg.Checkpoint != "" {
+		if err := os.Remove(cfg.Checkpoint); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("cannot remove checkpoint %v; %w", cfg.Checkpoint, err)
+		}
+	}
+
+	sum := h.Sum(nil)
+	log.Notice("DbHash Generation complete!")
+	log.Noticef("AidaDb MD5 sum: %v", hex.EncodeToString(sum))
+	return sum, nil
+}
+
+// makeHashChunks splits the prefixes hashed by GenerateDbHash into chunks in the order of
+// hashing. Keys of block-indexed prefixes start with the big-endian block number, hence a
+// block range is a contiguous key range. State-root hashes are keyed by a hex string and
+// are hashed as a single chunk.
+func makeHashChunks(lastBlock, chunkSize uint64) []hashChunk {
+	var chunks []hashChunk
+	for _, prefix := range []string{db.SubstateDBPrefix, db.UpdateDBPrefix, db.DestroyedAccountPrefix, db.StateRootHashPrefix, db.BlockHashPrefix} {
+		if prefix == db.StateRootHashPrefix {
+			chunks = append(chunks, hashChunk{prefix: prefix})
+			continue
+		}
+		var start []byte
+		for from := chunkSize; from <= lastBlock && from >= chunkSize; from += chunkSize {
+			end := append([]byte(prefix), db.BlockToBytes(from)...)
+			chunks = append(chunks, hashChunk{prefix: prefix, start: start, end: end})
+			start = db.BlockToBytes(from)
+		}
+		chunks = append(chunks, hashChunk{prefix: prefix, start: start})
+	}
+	return chunks
+}
+
+// readHashChunk streams keys and values of the chunk in the order hashed by GenerateDbHash.
+func readHashChunk(base db.BaseDB, chunk hashChunk, stream *chunkStream, abort <-chan any) {
+	h := md5.New()
+	defer func() {
+		stream.digest = h.Sum(nil)
+		close(stream.data)
+	}()
+
+	iter := base.NewIterator([]byte(chunk.prefix), chunk.start)
+	defer iter.Release()
+
+	for iter.Next() {
+		if chunk.end != nil && bytes.Compare(iter.Key(), chunk.end) >= 0 {
+			break
+		}
+		for _, b := range [][]byte{iter.Key(), iter.Value()} {
+			data := bytes.Clone(b)
+			h.Write(data)
+			select {
+			case <-abort:
+				return
+			case stream.data <- data:
+			}
+		}
+	}
+	stream.err = iter.Error()
+}
+
+// restoreDbHashCheckpoint loads the checkpoint at path into h. It returns nil if there is no
+// checkpoint or if it was recorded for a different chunk layout.
+func restoreDbHashCheckpoint(path string, layout dbHashCheckpoint, h hash.Hash) (*dbHashCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read checkpoint %v; %w", path, err)
+	}
+
+	var checkpoint dbHashCheckpoint
+	if err = json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("cannot parse checkpoint %v; %w", path, err)
+	}
+	if checkpoint.ChunkSize != layout.ChunkSize || checkpoint.LastBlock != layout.LastBlock {
+		return nil, nil
+	}
+	if err = h.(encoding.BinaryUnmarshaler).UnmarshalBinary(checkpoint.State); err != nil {
+		return nil, fmt.Errorf("cannot restore hash state from checkpoint %v; %w", path, err)
+	}
+	return &checkpoint, nil
+}
+
+// writeDbHashCheckpoint atomically replaces the checkpoint at path.
+func writeDbHashCheckpoint(path string, checkpoint dbHashCheckpoint, h hash.Hash) error {
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("cannot save hash state; %w", err)
+	}
+	checkpoint.State = state
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("cannot encode checkpoint; %w", err)
+	}
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("cannot write checkpoint %v; %w", tmp, err)
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("cannot replace checkpoint %v; %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utildb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDbHashInChunks_MatchesSerialHash(t *testing.T) {
+	base := makeChunkedHashTestDb(t, 95)
+	want, err := GenerateDbHash(base, "critical")
+	require.NoError(t, err)
+
+	for _, chunkSize := range []uint64{1, 10, 96, 1000} {
+		for _, workers := range []int{1, 4} {
+			t.Run(fmt.Sprintf("chunk=%d/workers=%d", chunkSize, workers), func(t *testing.T) {
+				got, err := generateDbHashInChunks(context.Background(), base, ChunkedDbHashConfig{
+					LastBlock: 95,
+					ChunkSize: chunkSize,
+					Workers:   workers,
+				}, logger.NewLogger("critical", "test"), nil)
+				require.NoError(t, err)
+				require.Equal(t, want, got)
+			})
+		}
+	}
+}
+
+func TestGenerateDbHashInChunks_EmptyDb(t *testing.T) {
+	base, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = base.Close() })
+
+	got, err := GenerateDbHashInChunks(context.Background(), base, ChunkedDbHashConfig{Workers: 2}, "critical")
+	require.NoError(t, err)
+	require.Equal(t, emptyDBHash, fmt.Sprintf("%x", got))
+}
+
+func TestGenerateDbHashInChunks_ResumesAfterInterrupt(t *testing.T) {
+	base := makeChunkedHashTestDb(t, 95)
+	want, err := GenerateDbHash(base, "critical")
+	require.NoError(t, err)
+
+	cfg := ChunkedDbHashConfig{
+		LastBlock:  95,
+		ChunkSize:  10,
+		Workers:    3,
+		Checkpoint: filepath.Join(t.TempDir(), "aida-db.validate-checkpoint"),
+	}
+	total := len(makeHashChunks(cfg.LastBlock, cfg.ChunkSize))
+	half := total / 2
+
+	// the first run is stopped once half of the chunks is hashed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err = generateDbHashInChunks(ctx, base, cfg, logger.NewLogger("critical", "test"), func(idx int) {
+		if idx+1 == half {
+			cancel()
+		}
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, half, len(readDbHashCheckpoint(t, cfg.Checkpoint).Digests))
+
+	// the second run hashes only the remaining chunks
+	var resumed []int
+	got, err := generateDbHashInChunks(context.Background(), base, cfg, logger.NewLogger("critical", "test"), func(idx int) {
+		resumed = append(resumed, idx)
+	})
+	require.NoError(t, err)
+	require.Len(t, resumed, total-half)
+	require.Equal(t, half, resumed[0])
+	require.Equal(t, want, got)
+	require.NoFileExists(t, cfg.Checkpoint)
+}
+
+func TestGenerateDbHashInChunks_CheckpointIsIgnored(t *testing.T) {
+	base := makeChunkedHashTestDb(t, 95)
+	want, err := GenerateDbHash(base, "critical")
+	require.NoError(t, err)
+
+	tests := map[string]func(cfg *ChunkedDbHashConfig){
+		"Force":           func(cfg *ChunkedDbHashConfig) { cfg.Force = true },
+		"DifferentLayout": func(cfg *ChunkedDbHashConfig) { cfg.ChunkSize = 20 },
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := ChunkedDbHashConfig{
+				LastBlock:  95,
+				ChunkSize:  10,
+				Workers:    2,
+				Checkpoint: filepath.Join(t.TempDir(), "aida-db.validate-checkpoint"),
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			_, err := generateDbHashInChunks(ctx, base, cfg, logger.NewLogger("critical", "test"), func(idx int) {
+				if idx == 2 {
+					cancel()
+				}
+			})
+			require.ErrorIs(t, err, context.Canceled)
+			require.FileExists(t, cfg.Checkpoint)
+
+			modify(&cfg)
+			var hashed []int
+			got, err := generateDbHashInChunks(context.Background(), base, cfg, logger.NewLogger("critical", "test"), func(idx int) {
+				hashed = append(hashed, idx)
+			})
+			require.NoError(t, err)
+			require.Len(t, hashed, len(makeHashChunks(cfg.LastBlock, cfg.ChunkSize)))
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestGenerateDbHashInChunks_CorruptedCheckpoint(t *testing.T) {
+	base := makeChunkedHashTestDb(t, 5)
+	checkpoint := filepath.Join(t.TempDir(), "aida-db.validate-checkpoint")
+	require.NoError(t, os.WriteFile(checkpoint, []byte("{"), 0644))
+
+	_, err := GenerateDbHashInChunks(context.Background(), base, ChunkedDbHashConfig{
+		LastBlock:  5,
+		ChunkSize:  2,
+		Checkpoint: checkpoint,
+	}, "critical")
+	require.ErrorContains(t, err, "cannot parse checkpoint")
+}
+
+func TestMakeHashChunks_CoverBlockRanges(t *testing.T) {
+	chunks := makeHashChunks(25, 10)
+	// three chunks for each of the four block-indexed prefixes and one for state-root hashes
+	require.Len(t, chunks, 13)
+
+	substate := chunks[:3]
+	require.Nil(t, substate[0].start)
+	require.Equal(t, append([]byte(db.SubstateDBPrefix), db.BlockToBytes(10)...), substate[0].end)
+	require.Equal(t, db.BlockToBytes(10), substate[1].start)
+	require.Equal(t, append([]byte(db.SubstateDBPrefix), db.BlockToBytes(20)...), substate[1].end)
+	require.Equal(t, db.BlockToBytes(20), substate[2].start)
+	require.Nil(t, substate[2].end)
+
+	require.Equal(t, hashChunk{prefix: db.StateRootHashPrefix}, chunks[9])
+}
+
+// makeChunkedHashTestDb creates a db with entries of all hashed prefixes for blocks [0, lastBlock].
+func makeChunkedHashTestDb(t *testing.T, lastBlock uint64) db.BaseDB {
+	t.Helper()
+	base, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = base.Close() })
+
+	put := func(key []byte, value string) {
+		require.NoError(t, base.Put(key, []byte(value)))
+	}
+	for block := uint64(0); block <= lastBlock; block++ {
+		for tx := 0; tx < 3; tx++ {
+			put(db.SubstateDBKey(block, tx), fmt.Sprintf("substate-%d-%d", block, tx))
+		}
+		if block%4 == 0 {
+			put(db.UpdateDBKey(block), fmt.Sprintf("update-%d", block))
+		}
+		if block%7 == 0 {
+			put(db.EncodeDestroyedAccountKey(block, 1), fmt.Sprintf("destroyed-%d", block))
+		}
+		put([]byte(fmt.Sprintf("%s0x%x", db.StateRootHashPrefix, block)), fmt.Sprintf("state-root-%d", block))
+		put(db.BlockHashDBKey(block), fmt.Sprintf("block-hash-%d", block))
+	}
+	// keys not following the block encoding are hashed as well
+	put([]byte(db.SubstateDBPrefix+"key"), "irregular")
+	return base
+}
+
+func readDbHashCheckpoint(t *testing.T, path string) dbHashCheckpoint {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var checkpoint dbHashCheckpoint
+	require.NoError(t, json.Unmarshal(data, &checkpoint))
+	return checkpoint
+}
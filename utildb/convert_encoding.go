@@ -0,0 +1,308 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utildb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// ConvertStagingSuffix is appended to the path of the AidaDb to get the staging database
+// of an in-place encoding conversion.
+const ConvertStagingSuffix = "-encoding-staging"
+
+// convertProgressKey records the progress of an unfinished conversion in the target database.
+// Its value is the next block to convert followed by the encoding of the source substates.
+var convertProgressKey = []byte(db.MetadataPrefix + "cv")
+
+// convertLogInterval is the minimal time between two progress reports of a conversion.
+const convertLogInterval = 15 * time.Second
+
+// ConvertSubstateEncoding re-encodes the substates of blocks first-last from the source
+// database into the target database. If from is empty, the source encoding is detected
+// from the stored substates. With copyOtherKeys, all keys except substates are copied
+// to the target database as well. Finally, the target encoding is recorded in the
+// metadata of the target database.
+//
+// The target database keeps track of the converted blocks, hence an interrupted
+// conversion continues where it stopped once started again with the same databases.
+func ConvertSubstateEncoding(src, dst db.BaseDB, from, to db.SubstateEncodingSchema, first, last uint64, workers int, copyOtherKeys bool, logLevel string) error {
+	log := logger.NewLogger(logLevel, "aida-db-convert")
+	to, err := utils.NormalizeSubstateEncoding(to)
+	if err != nil {
+		return err
+	}
+	if to == "" {
+		return errors.New("target encoding is not set")
+	}
+
+	md := utils.NewAidaDbMetadata(dst, logLevel)
+	next, stored, found, err := readConvertProgress(dst)
+	if err != nil {
+		return err
+	}
+	switch {
+	case found:
+		if from != "" && !sameSubstateEncoding(from, stored) {
+			return fmt.Errorf("unfinished conversion reads %v encoded substates, but source encoding is %v", stored, from)
+		}
+		from = stored
+		log.Noticef("Resuming conversion at block %d", next)
+	case md.GetSubstateEncoding() == to:
+		log.Noticef("Substates are already converted to %v", to)
+		return nil
+	default:
+		if from, err = resolveSourceEncoding(src, from); err != nil {
+			return err
+		}
+		if copyOtherKeys {
+			copied, err := copyKeys(src, dst, nil, func(key []byte) bool {
+				return !bytes.HasPrefix(key, []byte(db.SubstateDBPrefix))
+			})
+			if err != nil {
+				return fmt.Errorf("cannot copy keys; %w", err)
+			}
+			log.Noticef("Copied %d keys other than substates", copied)
+		}
+		next = first
+		if err = writeConvertProgress(dst, next, from); err != nil {
+			return err
+		}
+	}
+	if from == to {
+		return fmt.Errorf("substates are already %v encoded", to)
+	}
+
+	srcSdb, err := db.MakeDefaultSubstateDBFromBaseDBWithEncoding(src, from)
+	if err != nil {
+		return fmt.Errorf("cannot set source encoding; %w", err)
+	}
+	dstSdb, err := db.MakeDefaultSubstateDBFromBaseDBWithEncoding(dst, to)
+	if err != nil {
+		return fmt.Errorf("cannot set target encoding; %w", err)
+	}
+
+	log.Noticef("Converting substates of blocks %d-%d from %v to %v", next, last, from, to)
+	iter := srcSdb.NewSubstateIterator(int(next), workers)
+	defer iter.Release()
+
+	var (
+		start     = time.Now()
+		lastLog   = start
+		block     = next
+		converted uint64
+	)
+	for iter.Next() {
+		ss := iter.Value()
+		if ss.Block > last {
+			break
+		}
+		if ss.Block != block {
+			if err = writeConvertProgress(dst, ss.Block, from); err != nil {
+				return err
+			}
+			block = ss.Block
+			if time.Since(lastLog) >= convertLogInterval {
+				log.Infof("Converted %d substates; reached block %d; elapsed %v", converted, block, time.Since(start).Round(time.Second))
+				lastLog = time.Now()
+			}
+		}
+		if err = dstSdb.PutSubstate(ss); err != nil {
+			return fmt.Errorf("cannot put substate of block %d, tx %d; %w", ss.Block, ss.Transaction, err)
+		}
+		converted++
+	}
+	if err = iter.Error(); err != nil {
+		return fmt.Errorf("cannot read substates; %w", err)
+	}
+
+	if err = md.SetSubstateEncoding(to); err != nil {
+		return err
+	}
+	if err = dst.Delete(convertProgressKey); err != nil {
+		return fmt.Errorf("cannot delete conversion progress; %w", err)
+	}
+	log.Noticef("Converted %d substates in %v", converted, time.Since(start).Round(time.Second))
+	return nil
+}
+
+// ConvertSubstateEncodingInPlace re-encodes all substates of the AidaDb at given path.
+// The substates are converted into a staging database next to the AidaDb first and
+// copied over the original substates afterwards, hence the AidaDb is not modified
+// before the conversion is complete. Blocks first-last have to cover all substates.
+//
+// The staging database is removed once the conversion finished. If it exists, an
+// interrupted conversion is resumed, including the copying of converted substates.
+func ConvertSubstateEncodingInPlace(path string, from, to db.SubstateEncodingSchema, first, last uint64, workers int, logLevel string) (finalErr error) {
+	log := logger.NewLogger(logLevel, "aida-db-convert")
+	to, err := utils.NormalizeSubstateEncoding(to)
+	if err != nil {
+		return err
+	}
+
+	aidaDb, err := db.NewDefaultSubstateDB(path)
+	if err != nil {
+		return fmt.Errorf("cannot open aida-db; %w", err)
+	}
+	defer func() {
+		finalErr = errors.Join(finalErr, aidaDb.Close())
+	}()
+	md := utils.NewAidaDbMetadata(aidaDb, logLevel)
+
+	stagingPath := path + ConvertStagingSuffix
+	if _, err = os.Stat(stagingPath); errors.Is(err, os.ErrNotExist) {
+		if md.GetSubstateEncoding() == to {
+			log.Noticef("Substates are already converted to %v", to)
+			return nil
+		}
+		if err = checkSubstatesInRange(aidaDb, first, last); err != nil {
+			return err
+		}
+	} else {
+		log.Noticef("Resuming conversion using staging database %v", stagingPath)
+	}
+
+	staging, err := db.NewDefaultSubstateDB(stagingPath)
+	if err != nil {
+		return fmt.Errorf("cannot open staging database; %w", err)
+	}
+	err = ConvertSubstateEncoding(aidaDb, staging, from, to, first, last, workers, false, logLevel)
+	if err != nil {
+		return errors.Join(err, staging.Close())
+	}
+
+	// copying is idempotent, hence an interrupted copy is repeated from the start
+	copied, err := copyKeys(staging, aidaDb, []byte(db.SubstateDBPrefix), nil)
+	if err = errors.Join(err, staging.Close()); err != nil {
+		return fmt.Errorf("cannot copy converted substates; %w", err)
+	}
+	if err = md.SetSubstateEncoding(to); err != nil {
+		return err
+	}
+	log.Noticef("Replaced %d substates of aida-db", copied)
+	return os.RemoveAll(stagingPath)
+}
+
+// resolveSourceEncoding returns the normalized requested encoding, or the encoding
+// detected from the substates of given database if none is requested.
+func resolveSourceEncoding(src db.BaseDB, requested db.SubstateEncodingSchema) (db.SubstateEncodingSchema, error) {
+	if requested != "" {
+		return utils.NormalizeSubstateEncoding(requested)
+	}
+	detected, key, err := utils.DetectSubstateEncoding(src)
+	if err != nil {
+		return "", err
+	}
+	if key == nil {
+		return "", errors.New("source database contains no substates")
+	}
+	if len(detected) == 0 {
+		return "", fmt.Errorf("cannot detect encoding of first substate %#x; use --%v", key, utils.SubstateEncodingFlag.Name)
+	}
+	return detected[0], nil
+}
+
+// sameSubstateEncoding reports whether both encodings are the same after resolving aliases.
+func sameSubstateEncoding(a, b db.SubstateEncodingSchema) bool {
+	a, errA := utils.NormalizeSubstateEncoding(a)
+	b, errB := utils.NormalizeSubstateEncoding(b)
+	return errA == nil && errB == nil && a == b
+}
+
+// checkSubstatesInRange checks that the database contains no substates outside of blocks first-last.
+func checkSubstatesInRange(database db.BaseDB, first, last uint64) error {
+	iter := database.NewIterator([]byte(db.SubstateDBPrefix), nil)
+	below := iter.Next() && bytes.Compare(iter.Key(), db.SubstateDBBlockPrefix(first)) < 0
+	iter.Release()
+
+	iter = database.NewIterator([]byte(db.SubstateDBPrefix), db.BlockToBytes(last+1))
+	above := iter.Next()
+	iter.Release()
+
+	if below || above {
+		return fmt.Errorf("block range %d-%d does not cover all substates of the aida-db; an in-place conversion has to convert all substates", first, last)
+	}
+	return nil
+}
+
+// readConvertProgress returns the next block and the source encoding of an unfinished conversion.
+func readConvertProgress(database db.BaseDB) (uint64, db.SubstateEncodingSchema, bool, error) {
+	value, err := database.Get(convertProgressKey)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return 0, "", false, nil
+		}
+		return 0, "", false, fmt.Errorf("cannot read conversion progress; %w", err)
+	}
+	if len(value) < 8 {
+		return 0, "", false, fmt.Errorf("invalid conversion progress %#x", value)
+	}
+	return bigendian.BytesToUint64(value[:8]), db.SubstateEncodingSchema(value[8:]), true, nil
+}
+
+// writeConvertProgress records that all blocks below next are converted.
+func writeConvertProgress(database db.BaseDB, next uint64, from db.SubstateEncodingSchema) error {
+	value := append(bigendian.Uint64ToBytes(next), from...)
+	if err := database.Put(convertProgressKey, value); err != nil {
+		return fmt.Errorf("cannot write conversion progress; %w", err)
+	}
+	return nil
+}
+
+// copyKeys copies keys with given prefix accepted by filter from src to dst in batches.
+// A nil filter accepts all keys.
+func copyKeys(src, dst db.BaseDB, prefix []byte, filter func(key []byte) bool) (uint64, error) {
+	iter := src.NewIterator(prefix, nil)
+	defer iter.Release()
+
+	batch := dst.NewBatch()
+	var copied uint64
+	for iter.Next() {
+		if filter != nil && !filter(iter.Key()) {
+			continue
+		}
+		if err := batch.Put(iter.Key(), iter.Value()); err != nil {
+			return copied, err
+		}
+		copied++
+		if batch.ValueSize() > kvdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return copied, fmt.Errorf("cannot write batch; %w", err)
+			}
+			batch.Reset()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return copied, err
+	}
+	if batch.ValueSize() > 0 {
+		if err := batch.Write(); err != nil {
+			return copied, fmt.Errorf("cannot write batch; %w", err)
+		}
+	}
+	return copied, nil
+}
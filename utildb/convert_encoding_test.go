@@ -0,0 +1,245 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utildb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/0xsoniclabs/substate/substate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// convertedTestBlocks is the number of blocks of the synthetic AidaDb of conversion tests.
+const convertedTestBlocks = 5
+
+// createConvertTestDb creates an AidaDb at given path with two substates of given encoding
+// for blocks 1-5, an update set and metadata. It returns the stored substates, which are
+// limited to fields supported by all encodings.
+func createConvertTestDb(t *testing.T, path string, encoding db.SubstateEncodingSchema) []*substate.Substate {
+	sdb, err := db.NewDefaultSubstateDB(path)
+	require.NoError(t, err)
+	require.NoError(t, sdb.SetSubstateEncoding(encoding))
+
+	var substates []*substate.Substate
+	for block := uint64(1); block <= convertedTestBlocks; block++ {
+		for tx := 0; tx < 2; tx++ {
+			ss := utils.GetTestSubstate(string(db.RLPEncodingSchema))
+			ss.Block, ss.Transaction, ss.Env.Number = block, tx, block
+			require.NoError(t, sdb.PutSubstate(ss))
+			substates = append(substates, ss)
+		}
+	}
+	require.NoError(t, sdb.Put(db.UpdateDBKey(3), []byte{1}))
+	md := utils.NewAidaDbMetadata(sdb, "ERROR")
+	require.NoError(t, md.SetFirstBlock(1))
+	require.NoError(t, md.SetLastBlock(convertedTestBlocks))
+	require.NoError(t, sdb.Close())
+	return substates
+}
+
+// requireConverted checks that the database at given path contains given substates
+// encoded with given encoding, the update set and the metadata of the original AidaDb.
+func requireConverted(t *testing.T, path string, encoding db.SubstateEncodingSchema, substates []*substate.Substate) {
+	base, err := db.NewDefaultSubstateDB(path)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, base.Close())
+	}()
+
+	md := utils.NewAidaDbMetadata(base, "ERROR")
+	assert.Equal(t, encoding, md.GetSubstateEncoding())
+	assert.Equal(t, uint64(convertedTestBlocks), md.GetLastBlock())
+	has, err := base.Has(db.UpdateDBKey(3))
+	require.NoError(t, err)
+	assert.True(t, has)
+	has, err = base.Has(convertProgressKey)
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	detected, _, err := utils.DetectSubstateEncoding(base)
+	require.NoError(t, err)
+	assert.Equal(t, []db.SubstateEncodingSchema{encoding}, detected)
+
+	sdb, err := db.MakeDefaultSubstateDBFromBaseDBWithEncoding(base, encoding)
+	require.NoError(t, err)
+	for _, want := range substates {
+		got, err := sdb.GetSubstate(want.Block, want.Transaction)
+		require.NoError(t, err)
+		assert.NoError(t, want.Equal(got), "substate of block %d, tx %d", want.Block, want.Transaction)
+	}
+}
+
+var convertDirections = []struct {
+	from, to db.SubstateEncodingSchema
+}{
+	{db.RLPEncodingSchema, db.ProtobufEncodingSchema},
+	{db.ProtobufEncodingSchema, db.RLPEncodingSchema},
+}
+
+func TestConvertSubstateEncoding_ConvertsIntoTargetDb(t *testing.T) {
+	for _, d := range convertDirections {
+		t.Run(string(d.from)+"-to-"+string(d.to), func(t *testing.T) {
+			srcPath, dstPath := filepath.Join(t.TempDir(), "src"), filepath.Join(t.TempDir(), "dst")
+			substates := createConvertTestDb(t, srcPath, d.from)
+
+			src, err := db.NewDefaultSubstateDB(srcPath)
+			require.NoError(t, err)
+			dst, err := db.NewDefaultSubstateDB(dstPath)
+			require.NoError(t, err)
+			err = ConvertSubstateEncoding(src, dst, "", d.to, 1, convertedTestBlocks, 2, true, "ERROR")
+			require.NoError(t, err)
+			require.NoError(t, src.Close())
+			require.NoError(t, dst.Close())
+
+			requireConverted(t, dstPath, d.to, substates)
+		})
+	}
+}
+
+func TestConvertSubstateEncoding_ResumesInterruptedConversion(t *testing.T) {
+	srcPath, dstPath := filepath.Join(t.TempDir(), "src"), filepath.Join(t.TempDir(), "dst")
+	createConvertTestDb(t, srcPath, db.RLPEncodingSchema)
+
+	src, err := db.NewDefaultSubstateDB(srcPath)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, src.Close())
+	}()
+	dst, err := db.NewDefaultSubstateDB(dstPath)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, dst.Close())
+	}()
+	require.NoError(t, writeConvertProgress(dst, 3, db.RLPEncodingSchema))
+
+	// the source encoding is taken from the progress, hence the requested alias is accepted
+	err = ConvertSubstateEncoding(src, dst, db.LegacyProtobufEncodingAlias, db.ProtobufEncodingSchema, 1, convertedTestBlocks, 1, true, "ERROR")
+	require.ErrorContains(t, err, "unfinished conversion reads rlp encoded substates")
+
+	err = ConvertSubstateEncoding(src, dst, "", db.ProtobufEncodingSchema, 1, convertedTestBlocks, 1, true, "ERROR")
+	require.NoError(t, err)
+	for block := uint64(1); block <= convertedTestBlocks; block++ {
+		has, err := dst.Has(db.SubstateDBKey(block, 0))
+		require.NoError(t, err)
+		assert.Equal(t, block >= 3, has, "substate of block %d", block)
+	}
+	_, _, found, err := readConvertProgress(dst)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestConvertSubstateEncoding_ConvertsOnlyGivenRange(t *testing.T) {
+	srcPath, dstPath := filepath.Join(t.TempDir(), "src"), filepath.Join(t.TempDir(), "dst")
+	createConvertTestDb(t, srcPath, db.ProtobufEncodingSchema)
+
+	src, err := db.NewDefaultSubstateDB(srcPath)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, src.Close())
+	}()
+	dst, err := db.NewDefaultSubstateDB(dstPath)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, dst.Close())
+	}()
+
+	err = ConvertSubstateEncoding(src, dst, "", db.RLPEncodingSchema, 2, 3, 1, false, "ERROR")
+	require.NoError(t, err)
+	for block := uint64(1); block <= convertedTestBlocks; block++ {
+		has, err := dst.Has(db.SubstateDBKey(block, 1))
+		require.NoError(t, err)
+		assert.Equal(t, block >= 2 && block <= 3, has, "substate of block %d", block)
+	}
+	has, err := dst.Has(db.UpdateDBKey(3))
+	require.NoError(t, err)
+	assert.False(t, has)
+}
+
+func TestConvertSubstateEncoding_RejectsSameEncoding(t *testing.T) {
+	srcPath := filepath.Join(t.TempDir(), "src")
+	createConvertTestDb(t, srcPath, db.ProtobufEncodingSchema)
+
+	src, err := db.NewDefaultSubstateDB(srcPath)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, src.Close())
+	}()
+	dst, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, dst.Close())
+	}()
+
+	err = ConvertSubstateEncoding(src, dst, "", db.DefaultEncodingSchema, 1, convertedTestBlocks, 1, false, "ERROR")
+	require.ErrorContains(t, err, "substates are already protobuf encoded")
+}
+
+func TestConvertSubstateEncodingInPlace_ConvertsAidaDb(t *testing.T) {
+	for _, d := range convertDirections {
+		t.Run(string(d.from)+"-to-"+string(d.to), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "aida-db")
+			substates := createConvertTestDb(t, path, d.from)
+
+			err := ConvertSubstateEncodingInPlace(path, d.from, d.to, 1, convertedTestBlocks, 2, "ERROR")
+			require.NoError(t, err)
+			requireConverted(t, path, d.to, substates)
+			_, err = os.Stat(path + ConvertStagingSuffix)
+			assert.ErrorIs(t, err, os.ErrNotExist)
+
+			// a second run finds the substates converted already
+			err = ConvertSubstateEncodingInPlace(path, "", d.to, 1, convertedTestBlocks, 2, "ERROR")
+			require.NoError(t, err)
+			requireConverted(t, path, d.to, substates)
+		})
+	}
+}
+
+func TestConvertSubstateEncodingInPlace_ResumesFromStagingDb(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aida-db")
+	substates := createConvertTestDb(t, path, db.ProtobufEncodingSchema)
+
+	// simulate an interruption after the substates were converted into the staging database
+	src, err := db.NewDefaultSubstateDB(path)
+	require.NoError(t, err)
+	staging, err := db.NewDefaultSubstateDB(path + ConvertStagingSuffix)
+	require.NoError(t, err)
+	err = ConvertSubstateEncoding(src, staging, "", db.RLPEncodingSchema, 1, convertedTestBlocks, 1, false, "ERROR")
+	require.NoError(t, err)
+	require.NoError(t, src.Close())
+	require.NoError(t, staging.Close())
+
+	err = ConvertSubstateEncodingInPlace(path, "", db.RLPEncodingSchema, 1, convertedTestBlocks, 1, "ERROR")
+	require.NoError(t, err)
+	requireConverted(t, path, db.RLPEncodingSchema, substates)
+	_, err = os.Stat(path + ConvertStagingSuffix)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestConvertSubstateEncodingInPlace_RequiresAllSubstates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aida-db")
+	createConvertTestDb(t, path, db.RLPEncodingSchema)
+
+	err := ConvertSubstateEncodingInPlace(path, "", db.ProtobufEncodingSchema, 2, convertedTestBlocks, 1, "ERROR")
+	require.ErrorContains(t, err, "does not cover all substates")
+	err = ConvertSubstateEncodingInPlace(path, "", db.ProtobufEncodingSchema, 1, convertedTestBlocks-1, 1, "ERROR")
+	require.ErrorContains(t, err, "does not cover all substates")
+}
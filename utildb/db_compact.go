@@ -0,0 +1,147 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utildb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// sstablesProperty lists the tables of every level of the LevelDB together with their size.
+const sstablesProperty = "leveldb.sstables"
+
+// ParseCompactionComponents parses a comma-separated list of components of the AidaDb
+// compacted by CompactDb. An empty list selects all components.
+func ParseCompactionComponents(s string) ([]string, error) {
+	var res []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, found := findSizeComponent(name); !found {
+			return nil, fmt.Errorf("unknown component %q; expected one of %v", name, strings.Join(sizeComponentNames(), ", "))
+		}
+		res = append(res, name)
+	}
+	return res, nil
+}
+
+// CompactDb compacts the key ranges of given components of the AidaDb one after another, which,
+// unlike a single compaction of the whole database, allows to report the progress. The change of
+// the on-disk size is logged after each component. All components are compacted if none are given.
+func CompactDb(database db.BaseDB, components []string, log logger.Logger) error {
+	if len(components) == 0 {
+		components = sizeComponentNames()
+	}
+
+	total, err := diskSize(database)
+	if err != nil {
+		return err
+	}
+	log.Noticef("Compacting %v; on-disk size %v", strings.Join(components, ", "), formatMiB(total))
+
+	start := time.Now()
+	for i, name := range components {
+		c, found := findSizeComponent(name)
+		if !found {
+			return fmt.Errorf("unknown component %q", name)
+		}
+
+		before, err := diskSize(database)
+		if err != nil {
+			return err
+		}
+		componentStart := time.Now()
+		for _, prefix := range c.prefixes {
+			r := util.BytesPrefix([]byte(prefix))
+			if err = database.Compact(r.Start, r.Limit); err != nil {
+				return fmt.Errorf("cannot compact %v; %w", name, err)
+			}
+		}
+		after, err := diskSize(database)
+		if err != nil {
+			return err
+		}
+		log.Noticef("Compacted %v (%d/%d) in %v; size delta %v; elapsed time %v",
+			name, i+1, len(components), time.Since(componentStart).Round(time.Millisecond),
+			formatMiBDelta(before, after), time.Since(start).Round(time.Second))
+	}
+
+	after, err := diskSize(database)
+	if err != nil {
+		return err
+	}
+	log.Noticef("Compaction finished; on-disk size %v before, %v after", formatMiB(total), formatMiB(after))
+	return nil
+}
+
+// diskSize sums up the size of all tables of the database.
+func diskSize(database db.BaseDB) (uint64, error) {
+	tables, err := database.Stat(sstablesProperty)
+	if err != nil {
+		return 0, fmt.Errorf("cannot query %v; %w", sstablesProperty, err)
+	}
+	return parseTablesSize(tables)
+}
+
+// parseTablesSize parses the sstables property consisting of a "--- level N ---" header
+// per level, followed by a "number:size[smallest .. largest]" line per table.
+func parseTablesSize(tables string) (uint64, error) {
+	var total uint64
+	for _, line := range strings.Split(tables, "\n") {
+		if line == "" || strings.HasPrefix(line, "---") {
+			continue
+		}
+		var num, size uint64
+		if _, err := fmt.Sscanf(line, "%d:%d[", &num, &size); err != nil {
+			return 0, fmt.Errorf("unexpected table description %q; %w", line, err)
+		}
+		total += size
+	}
+	return total, nil
+}
+
+func findSizeComponent(name string) (sizeComponent, bool) {
+	for _, c := range sizeComponents {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return sizeComponent{}, false
+}
+
+func sizeComponentNames() []string {
+	names := make([]string, len(sizeComponents))
+	for i, c := range sizeComponents {
+		names[i] = c.name
+	}
+	return names
+}
+
+func formatMiB(size uint64) string {
+	return fmt.Sprintf("%.2f MiB", float64(size)/(1024*1024))
+}
+
+func formatMiBDelta(before, after uint64) string {
+	return fmt.Sprintf("%+.2f MiB", (float64(after)-float64(before))/(1024*1024))
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utildb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseCompactionComponents(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    []string
+		wantErr string
+	}{
+		"empty":   {input: "", want: nil},
+		"single":  {input: "code", want: []string{"code"}},
+		"several": {input: "substate, block-hash,code", want: []string{"substate", "block-hash", "code"}},
+		"unknown": {input: "substate,hashes", wantErr: `unknown component "hashes"`},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseCompactionComponents(test.input)
+			if test.wantErr != "" {
+				require.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestParseTablesSize(t *testing.T) {
+	tables := "--- level 0 ---\n" +
+		"5:1200[\"1s\\x00\" .. \"1s\\xff\"]\n" +
+		"--- level 1 ---\n" +
+		"3:300[\"bh\\x00\" .. \"da\\x01\"]\n" +
+		"4:45[\"md\" .. \"us\"]\n" +
+		"--- level 2 ---\n"
+	size, err := parseTablesSize(tables)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1545), size)
+
+	_, err = parseTablesSize("--- level 0 ---\nbroken\n")
+	require.ErrorContains(t, err, "unexpected table description")
+}
+
+func TestCompactDb_CompactsOnlySelectedComponents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	database := db.NewMockBaseDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+
+	sizes := []string{"1:300[", "1:300[", "1:200[", "1:200[", "1:150[", "1:150["}
+	for _, size := range sizes {
+		database.EXPECT().Stat(sstablesProperty).Return("--- level 0 ---\n"+size+"\"a\" .. \"b\"]\n", nil)
+	}
+	gomock.InOrder(
+		database.EXPECT().Compact([]byte(db.SubstateDBPrefix), []byte("1t")),
+		database.EXPECT().Compact([]byte(db.MetadataPrefix), []byte("me")),
+		database.EXPECT().Compact([]byte(db.UpdatesetPrefix), []byte("ut")),
+	)
+	log.EXPECT().Noticef("Compacting %v; on-disk size %v", "substate, metadata", "0.00 MiB")
+	log.EXPECT().Noticef(gomock.Any(), "substate", 1, 2, gomock.Any(), "-0.00 MiB", gomock.Any())
+	log.EXPECT().Noticef(gomock.Any(), "metadata", 2, 2, gomock.Any(), "-0.00 MiB", gomock.Any())
+	log.EXPECT().Noticef("Compaction finished; on-disk size %v before, %v after", "0.00 MiB", "0.00 MiB")
+
+	require.NoError(t, CompactDb(database, []string{"substate", "metadata"}, log))
+}
+
+func TestCompactDb_ReportsCompactionError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	database := db.NewMockBaseDB(ctrl)
+	log := logger.NewMockLogger(ctrl)
+
+	database.EXPECT().Stat(sstablesProperty).Return("", nil).Times(2)
+	database.EXPECT().Compact(gomock.Any(), gomock.Any()).Return(errors.New("injected error"))
+	log.EXPECT().Noticef(gomock.Any(), gomock.Any(), gomock.Any())
+
+	err := CompactDb(database, []string{"code"}, log)
+	require.ErrorContains(t, err, "cannot compact code; injected error")
+}
+
+func TestCompactDb_CompactsTemporaryLevelDb(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	database := createSizeTestDb(t)
+	log := logger.NewMockLogger(ctrl)
+
+	// the test db is small enough to be kept in the memory table until the first compaction
+	before, err := diskSize(database)
+	require.NoError(t, err)
+	require.Zero(t, before)
+
+	log.EXPECT().Noticef(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+	log.EXPECT().Noticef(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+	require.NoError(t, CompactDb(database, []string{"substate", "code"}, log))
+
+	after, err := diskSize(database)
+	require.NoError(t, err)
+	assert.NotZero(t, after)
+
+	// compaction must not lose any data
+	size, err := GetDbSize(database, 0, 100, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), size[0].Keys)
+}
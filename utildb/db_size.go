@@ -0,0 +1,232 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utildb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/0xsoniclabs/aida/utildb/dbcomponent"
+	"github.com/0xsoniclabs/substate/db"
+)
+
+// sizeSampleWindows is the number of evenly spaced key ranges scanned in sampling mode.
+const sizeSampleWindows = 100
+
+// DbComponentSize describes the space occupied by a single component of an AidaDb.
+type DbComponentSize struct {
+	Component  string `json:"component"`
+	Keys       uint64 `json:"keys"`
+	KeyBytes   uint64 `json:"keyBytes"`
+	ValueBytes uint64 `json:"valueBytes"`
+	Estimated  bool   `json:"estimated"` // true if the size was extrapolated from samples
+}
+
+func (s *DbComponentSize) add(o DbComponentSize) {
+	s.Keys += o.Keys
+	s.KeyBytes += o.KeyBytes
+	s.ValueBytes += o.ValueBytes
+	s.Estimated = s.Estimated || o.Estimated
+}
+
+// keyLayout describes how keys following the prefix of a component are ordered.
+type keyLayout int
+
+const (
+	blockKeys     keyLayout = iota // 8-byte big-endian block number
+	hashKeys                       // uniformly distributed hash, not related to blocks
+	stateHashKeys                  // textual hex block number, not ordered by block
+	plainKeys                      // arbitrary keys, not related to blocks
+)
+
+type sizeComponent struct {
+	name     string
+	prefixes []string
+	layout   keyLayout
+}
+
+// sizeComponents lists all components of an AidaDb in the order they are reported.
+var sizeComponents = []sizeComponent{
+	{string(dbcomponent.Substate), []string{db.SubstateDBPrefix}, blockKeys},
+	{string(dbcomponent.Update), []string{db.UpdateDBPrefix}, blockKeys},
+	{string(dbcomponent.Delete), []string{db.DestroyedAccountPrefix}, blockKeys},
+	{string(dbcomponent.StateHash), []string{db.StateRootHashPrefix}, stateHashKeys},
+	{string(dbcomponent.BlockHash), []string{db.BlockHashPrefix}, blockKeys},
+	{string(dbcomponent.Exception), []string{db.ExceptionDBPrefix}, blockKeys},
+	{"code", []string{db.CodeDBPrefix}, hashKeys},
+	{"metadata", []string{db.MetadataPrefix, db.UpdatesetPrefix}, plainKeys},
+}
+
+// GetDbSize reports the number of keys and the bytes occupied by keys and values of each
+// component of the AidaDb followed by the overall total. Block keyed components only count
+// the keys of blocks first to last; code and metadata are not related to blocks and are
+// always counted in full.
+//
+// If sampleRate is within (0, 1), block and hash keyed components are not iterated in full.
+// Instead, the given fraction of their key range is scanned in evenly spaced windows and
+// the result is extrapolated. State hashes and metadata are always counted exactly.
+func GetDbSize(database db.BaseDB, first, last uint64, sampleRate float64) ([]DbComponentSize, error) {
+	if sampleRate < 0 || sampleRate > 1 {
+		return nil, fmt.Errorf("sample rate must be within [0, 1], got %v", sampleRate)
+	}
+	sampling := sampleRate > 0 && sampleRate < 1
+
+	total := DbComponentSize{Component: "total"}
+	res := make([]DbComponentSize, 0, len(sizeComponents)+1)
+	for _, c := range sizeComponents {
+		size := DbComponentSize{Component: c.name}
+		for _, prefix := range c.prefixes {
+			var (
+				s   DbComponentSize
+				err error
+			)
+			switch {
+			case c.layout == blockKeys && sampling:
+				s, err = sampleKeyRange(database, []byte(prefix), first, last, sampleRate, true)
+			case c.layout == blockKeys:
+				s, err = scanKeyRange(database, []byte(prefix), first, last)
+			case c.layout == hashKeys && sampling:
+				s, err = sampleKeyRange(database, []byte(prefix), 0, math.MaxUint64, sampleRate, false)
+			case c.layout == stateHashKeys:
+				s, err = scanStateHashes(database, []byte(prefix), first, last)
+			default:
+				s, err = scanKeyRange(database, []byte(prefix), 0, math.MaxUint64)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("cannot measure %v; %w", c.name, err)
+			}
+			size.add(s)
+		}
+		total.add(size)
+		res = append(res, size)
+	}
+	return append(res, total), nil
+}
+
+// keyOrdinal returns the 8-byte big-endian number following the prefix of given key.
+func keyOrdinal(key []byte, prefixLen int) uint64 {
+	var buf [8]byte
+	if len(key) > prefixLen {
+		copy(buf[:], key[prefixLen:])
+	}
+	return binary.BigEndian.Uint64(buf[:])
+}
+
+// scanKeyRange measures all keys of given prefix whose ordinal is within [from, to].
+func scanKeyRange(database db.BaseDB, prefix []byte, from, to uint64) (DbComponentSize, error) {
+	var size DbComponentSize
+	start := make([]byte, 8)
+	binary.BigEndian.PutUint64(start, from)
+
+	iter := database.NewIterator(prefix, start)
+	defer iter.Release()
+	for iter.Next() {
+		if keyOrdinal(iter.Key(), len(prefix)) > to {
+			break
+		}
+		size.Keys++
+		size.KeyBytes += uint64(len(iter.Key()))
+		size.ValueBytes += uint64(len(iter.Value()))
+	}
+	return size, iter.Error()
+}
+
+// sampleKeyRange estimates the size of keys of given prefix whose ordinal is within [from, to]
+// by scanning sampleRate of the range in sizeSampleWindows evenly spaced windows. If shrink is
+// set, the range is first narrowed to the ordinals actually present in the database, which
+// avoids sampling empty parts of the block range.
+func sampleKeyRange(database db.BaseDB, prefix []byte, from, to uint64, sampleRate float64, shrink bool) (DbComponentSize, error) {
+	if shrink {
+		lo, hi, found, err := ordinalRange(database, prefix)
+		if err != nil || !found {
+			return DbComponentSize{}, err
+		}
+		from, to = max(from, lo), min(to, hi)
+		if from > to {
+			return DbComponentSize{}, nil
+		}
+	}
+
+	span := float64(to-from) + 1
+	segment := span / sizeSampleWindows
+	width := max(uint64(segment*sampleRate), 1)
+
+	var (
+		sampled DbComponentSize
+		scanned float64
+	)
+	for i := 0; i < sizeSampleWindows; i++ {
+		start := from + uint64(float64(i)*segment)
+		if i > 0 && start <= from+uint64(float64(i-1)*segment) {
+			continue // the range is narrower than the number of windows
+		}
+		end := start + width - 1
+		if end < start || end > to {
+			end = to
+		}
+		s, err := scanKeyRange(database, prefix, start, end)
+		if err != nil {
+			return DbComponentSize{}, err
+		}
+		sampled.add(s)
+		scanned += float64(end-start) + 1
+	}
+
+	scale := span / scanned
+	return DbComponentSize{
+		Keys:       uint64(math.Round(float64(sampled.Keys) * scale)),
+		KeyBytes:   uint64(math.Round(float64(sampled.KeyBytes) * scale)),
+		ValueBytes: uint64(math.Round(float64(sampled.ValueBytes) * scale)),
+		Estimated:  scale > 1,
+	}, nil
+}
+
+// ordinalRange returns the lowest and highest ordinal of keys with given prefix.
+func ordinalRange(database db.BaseDB, prefix []byte) (uint64, uint64, bool, error) {
+	iter := database.NewIterator(prefix, nil)
+	defer iter.Release()
+	if !iter.Next() {
+		return 0, 0, false, iter.Error()
+	}
+	lo := keyOrdinal(iter.Key(), len(prefix))
+	if !iter.Last() {
+		return 0, 0, false, iter.Error()
+	}
+	return lo, keyOrdinal(iter.Key(), len(prefix)), true, iter.Error()
+}
+
+// scanStateHashes measures state hashes of blocks first to last. State hash keys contain
+// the block number as a hex string, hence all keys need to be visited.
+func scanStateHashes(database db.BaseDB, prefix []byte, first, last uint64) (DbComponentSize, error) {
+	var size DbComponentSize
+	iter := database.NewIterator(prefix, nil)
+	defer iter.Release()
+	for iter.Next() {
+		block, err := db.StateHashKeyToUint64(iter.Key())
+		if err != nil {
+			return DbComponentSize{}, err
+		}
+		if block < first || block > last {
+			continue
+		}
+		size.Keys++
+		size.KeyBytes += uint64(len(iter.Key()))
+		size.ValueBytes += uint64(len(iter.Value()))
+	}
+	return size, iter.Error()
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utildb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/0xsoniclabs/substate/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createSizeTestDb creates an AidaDb with a known number and size of keys of every component.
+func createSizeTestDb(t *testing.T) db.BaseDB {
+	database, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, database.Close()) })
+
+	put := func(key []byte, valueLen int) {
+		require.NoError(t, database.Put(key, bytes.Repeat([]byte{1}, valueLen)))
+	}
+	blockKey := func(prefix string, block uint64) []byte {
+		return binary.BigEndian.AppendUint64([]byte(prefix), block)
+	}
+	for b := uint64(10); b < 20; b++ {
+		put(db.SubstateDBKey(b, 0), 100)
+		put(db.SubstateDBKey(b, 1), 100)
+		put(db.EncodeDestroyedAccountKey(b, 0), 10)
+		put(blockKey(db.BlockHashPrefix, b), 32)
+		put([]byte(fmt.Sprintf("%v0x%x", db.StateRootHashPrefix, b)), 32)
+	}
+	put(db.UpdateDBKey(10), 1000)
+	put(db.UpdateDBKey(15), 1000)
+	put(blockKey(db.ExceptionDBPrefix, 12), 50)
+	for i := 0; i < 3; i++ {
+		put(db.CodeDBKey(types.Hash{byte(i)}), 20)
+	}
+	put([]byte(db.MetadataPrefix+"fb"), 8)
+	return database
+}
+
+func TestDbSize_GetDbSize_CountsAllKeysExactly(t *testing.T) {
+	database := createSizeTestDb(t)
+
+	sizes, err := GetDbSize(database, 0, math.MaxUint64, 0)
+	require.NoError(t, err)
+
+	want := []DbComponentSize{
+		{Component: "substate", Keys: 20, KeyBytes: 20 * 18, ValueBytes: 20 * 100},
+		{Component: "update", Keys: 2, KeyBytes: 2 * 10, ValueBytes: 2 * 1000},
+		{Component: "delete", Keys: 10, KeyBytes: 10 * 14, ValueBytes: 10 * 10},
+		{Component: "state-hash", Keys: 10, KeyBytes: 6*6 + 4*7, ValueBytes: 10 * 32},
+		{Component: "block-hash", Keys: 10, KeyBytes: 10 * 10, ValueBytes: 10 * 32},
+		{Component: "exception", Keys: 1, KeyBytes: 10, ValueBytes: 50},
+		{Component: "code", Keys: 3, KeyBytes: 3 * 34, ValueBytes: 3 * 20},
+		{Component: "metadata", Keys: 1, KeyBytes: 4, ValueBytes: 8},
+	}
+	var total DbComponentSize
+	for _, s := range want {
+		total.add(s)
+	}
+	total.Component = "total"
+	assert.Equal(t, append(want, total), sizes)
+}
+
+func TestDbSize_GetDbSize_RestrictsBlockRange(t *testing.T) {
+	database := createSizeTestDb(t)
+
+	sizes, err := GetDbSize(database, 12, 14, 0)
+	require.NoError(t, err)
+
+	keys := make(map[string]uint64)
+	for _, s := range sizes {
+		keys[s.Component] = s.Keys
+		assert.False(t, s.Estimated)
+	}
+	assert.Equal(t, map[string]uint64{
+		"substate":   6,
+		"update":     0,
+		"delete":     3,
+		"state-hash": 3,
+		"block-hash": 3,
+		"exception":  1,
+		"code":       3, // not related to blocks
+		"metadata":   1, // not related to blocks
+		"total":      20,
+	}, keys)
+}
+
+func TestDbSize_GetDbSize_SamplingEstimatesSizes(t *testing.T) {
+	database, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	defer database.Close()
+	for b := uint64(0); b < 10_000; b++ {
+		require.NoError(t, database.Put(db.SubstateDBKey(b, 0), []byte{1, 2, 3, 4}))
+	}
+
+	sizes, err := GetDbSize(database, 0, math.MaxUint64, 0.1)
+	require.NoError(t, err)
+	substates := sizes[0]
+	require.Equal(t, "substate", substates.Component)
+	assert.True(t, substates.Estimated)
+	assert.InDelta(t, 10_000, substates.Keys, 100)
+	assert.InDelta(t, 40_000, substates.ValueBytes, 400)
+	assert.True(t, sizes[len(sizes)-1].Estimated)
+}
+
+func TestDbSize_GetDbSize_SamplingSmallRangeIsExact(t *testing.T) {
+	database := createSizeTestDb(t)
+
+	exact, err := GetDbSize(database, 0, math.MaxUint64, 0)
+	require.NoError(t, err)
+	sampled, err := GetDbSize(database, 0, math.MaxUint64, 0.5)
+	require.NoError(t, err)
+	// block keyed components span fewer blocks than there are sampling windows
+	for i, s := range sampled {
+		if s.Component == "code" || s.Component == "total" {
+			continue
+		}
+		assert.Equal(t, exact[i], s, "component %v", s.Component)
+	}
+}
+
+func TestDbSize_GetDbSize_RejectsInvalidSampleRate(t *testing.T) {
+	database := createSizeTestDb(t)
+	for _, rate := range []float64{-0.1, 1.5} {
+		_, err := GetDbSize(database, 0, 10, rate)
+		assert.ErrorContains(t, err, "sample rate must be within [0, 1]")
+	}
+}
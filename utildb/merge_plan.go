@@ -0,0 +1,238 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utildb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+)
+
+// MergeConflictKind classifies incompatibilities between source databases of a merge.
+type MergeConflictKind string
+
+const (
+	ChainIDMismatch          MergeConflictKind = "chain id mismatch"
+	SubstateEncodingMismatch MergeConflictKind = "substate encoding mismatch"
+	BlockRangeOverlap        MergeConflictKind = "overlapping block range"
+)
+
+// BlockInterval is an inclusive range of blocks.
+type BlockInterval struct {
+	First, Last uint64
+}
+
+func (i BlockInterval) String() string {
+	return fmt.Sprintf("%v-%v", i.First, i.Last)
+}
+
+// MergeSource describes a single source database of a merge.
+type MergeSource struct {
+	Path             string
+	ChainId          utils.ChainID
+	SubstateEncoding db.SubstateEncodingSchema // empty if the source contains no substates
+	Blocks           BlockInterval
+	HasBlockRange    bool   // false if neither metadata nor substates define the block range
+	KeyCount         uint64 // only set by EstimateSizes
+	Size             int64  // size of the database directory in bytes; only set by EstimateSizes
+}
+
+// MergeConflict is an incompatibility between two source databases.
+type MergeConflict struct {
+	Kind     MergeConflictKind
+	Paths    [2]string
+	Interval BlockInterval // overlapping blocks; only set for BlockRangeOverlap
+	Detail   string
+}
+
+func (c MergeConflict) String() string {
+	if c.Kind == BlockRangeOverlap {
+		return fmt.Sprintf("%v: blocks %v are contained in both %v and %v", c.Kind, c.Interval, c.Paths[0], c.Paths[1])
+	}
+	return fmt.Sprintf("%v: %v (%v vs %v)", c.Kind, c.Detail, c.Paths[0], c.Paths[1])
+}
+
+// MergePlan describes what a merge of given source databases is going to write.
+type MergePlan struct {
+	Sources   []MergeSource
+	Conflicts []MergeConflict
+	Gaps      []BlockInterval // blocks not covered by any source between the first and the last block
+}
+
+// PlanMerge inspects metadata of given source databases and detects incompatibilities between them.
+// Source databases without block range in metadata are inspected for their first and last substate.
+func PlanMerge(cfg *utils.Config, sourceDbs []db.BaseDB, sourceDbPaths []string) (*MergePlan, error) {
+	if len(sourceDbs) != len(sourceDbPaths) {
+		return nil, fmt.Errorf("got %d source databases but %d paths", len(sourceDbs), len(sourceDbPaths))
+	}
+
+	sources := make([]MergeSource, len(sourceDbs))
+	for i, database := range sourceDbs {
+		md := utils.NewAidaDbMetadata(database, cfg.LogLevel)
+		md.GetMetadata()
+
+		sdb, err := db.MakeDefaultSubstateDBFromBaseDB(database)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open substates of %v; %w", sourceDbPaths[i], err)
+		}
+
+		source := MergeSource{
+			Path:          sourceDbPaths[i],
+			ChainId:       md.ChainId,
+			Blocks:        BlockInterval{md.FirstBlock, md.LastBlock},
+			HasBlockRange: md.FirstBlock != 0 || md.LastBlock != 0,
+		}
+		if sdb.GetFirstSubstate() != nil {
+			source.SubstateEncoding = sdb.GetSubstateEncoding()
+		}
+		if !source.HasBlockRange {
+			source.Blocks.First, source.Blocks.Last, source.HasBlockRange = utils.FindBlockRangeInSubstate(sdb)
+		}
+		sources[i] = source
+	}
+
+	return newMergePlan(sources), nil
+}
+
+// newMergePlan detects conflicts and gaps between given sources.
+func newMergePlan(sources []MergeSource) *MergePlan {
+	plan := &MergePlan{Sources: sources}
+
+	var chainIdRef, encodingRef *MergeSource
+	for i := range sources {
+		source := &sources[i]
+		if source.ChainId != 0 {
+			if chainIdRef == nil {
+				chainIdRef = source
+			} else if source.ChainId != chainIdRef.ChainId {
+				plan.Conflicts = append(plan.Conflicts, MergeConflict{
+					Kind:   ChainIDMismatch,
+					Paths:  [2]string{chainIdRef.Path, source.Path},
+					Detail: fmt.Sprintf("%v vs %v", chainIdRef.ChainId, source.ChainId),
+				})
+			}
+		}
+		if source.SubstateEncoding != "" {
+			if encodingRef == nil {
+				encodingRef = source
+			} else if source.SubstateEncoding != encodingRef.SubstateEncoding {
+				plan.Conflicts = append(plan.Conflicts, MergeConflict{
+					Kind:   SubstateEncodingMismatch,
+					Paths:  [2]string{encodingRef.Path, source.Path},
+					Detail: fmt.Sprintf("%v vs %v", encodingRef.SubstateEncoding, source.SubstateEncoding),
+				})
+			}
+		}
+	}
+
+	// visit sources in order of their first block, so that overlaps and gaps are reported in block order
+	var ranged []MergeSource
+	for _, source := range sources {
+		if source.HasBlockRange {
+			ranged = append(ranged, source)
+		}
+	}
+	slices.SortStableFunc(ranged, func(a, b MergeSource) int {
+		switch {
+		case a.Blocks.First < b.Blocks.First:
+			return -1
+		case a.Blocks.First > b.Blocks.First:
+			return 1
+		}
+		return 0
+	})
+
+	for i, a := range ranged {
+		for _, b := range ranged[i+1:] {
+			if b.Blocks.First > a.Blocks.Last {
+				break
+			}
+			plan.Conflicts = append(plan.Conflicts, MergeConflict{
+				Kind:     BlockRangeOverlap,
+				Paths:    [2]string{a.Path, b.Path},
+				Interval: BlockInterval{b.Blocks.First, min(a.Blocks.Last, b.Blocks.Last)},
+			})
+		}
+	}
+
+	for i := 1; i < len(ranged); i++ {
+		if covered := ranged[i-1].Blocks.Last; ranged[i].Blocks.First > covered+1 {
+			plan.Gaps = append(plan.Gaps, BlockInterval{covered + 1, ranged[i].Blocks.First - 1})
+		}
+		// a source contained in its predecessor must not hide the end of the predecessor
+		ranged[i].Blocks.Last = max(ranged[i].Blocks.Last, ranged[i-1].Blocks.Last)
+	}
+
+	return plan
+}
+
+// EstimateSizes counts keys and measures the size on disk of every source database of the plan.
+func (p *MergePlan) EstimateSizes(sourceDbs []db.BaseDB) error {
+	for i := range p.Sources {
+		size, err := utils.GetDirectorySize(p.Sources[i].Path)
+		if err != nil {
+			return fmt.Errorf("cannot get size of %v; %w", p.Sources[i].Path, err)
+		}
+		p.Sources[i].Size = size
+		p.Sources[i].KeyCount = GetDbSize(sourceDbs[i])
+	}
+	return nil
+}
+
+// Print logs the sources, the conflicts and the gaps of the plan.
+func (p *MergePlan) Print(log logger.Logger) {
+	var keys uint64
+	var size int64
+	for _, source := range p.Sources {
+		blocks := "unknown"
+		if source.HasBlockRange {
+			blocks = source.Blocks.String()
+		}
+		encoding := string(source.SubstateEncoding)
+		if encoding == "" {
+			encoding = "none"
+		}
+		log.Noticef("Source %v: chain id %v; blocks %v; substate encoding %v; %v keys; %v MB",
+			source.Path, source.ChainId, blocks, encoding, source.KeyCount, source.Size/1_000_000)
+		keys += source.KeyCount
+		size += source.Size
+	}
+	log.Noticef("Merge would write %v keys from %v sources; %v MB in total", keys, len(p.Sources), size/1_000_000)
+
+	for _, gap := range p.Gaps {
+		log.Warningf("Blocks %v are not covered by any source", gap)
+	}
+	for _, conflict := range p.Conflicts {
+		log.Warningf("Conflict - %v", conflict)
+	}
+}
+
+// Err returns an error listing all conflicts of the plan, or nil if there are none.
+func (p *MergePlan) Err() error {
+	if len(p.Conflicts) == 0 {
+		return nil
+	}
+	conflicts := make([]string, len(p.Conflicts))
+	for i, conflict := range p.Conflicts {
+		conflicts[i] = conflict.String()
+	}
+	return fmt.Errorf("merge plan has %d conflicts:\n%v", len(p.Conflicts), strings.Join(conflicts, "\n"))
+}
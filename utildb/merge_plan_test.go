@@ -0,0 +1,199 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utildb
+
+import (
+	"testing"
+
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePlan_DetectsConflicts(t *testing.T) {
+	source := func(path string, chainId utils.ChainID, encoding db.SubstateEncodingSchema, first, last uint64) MergeSource {
+		return MergeSource{
+			Path:             path,
+			ChainId:          chainId,
+			SubstateEncoding: encoding,
+			Blocks:           BlockInterval{first, last},
+			HasBlockRange:    true,
+		}
+	}
+	tests := []struct {
+		name      string
+		sources   []MergeSource
+		conflicts []MergeConflict
+		gaps      []BlockInterval
+	}{
+		{
+			name: "Contiguous",
+			sources: []MergeSource{
+				source("a", 250, db.ProtobufEncodingSchema, 0, 99),
+				source("b", 250, db.ProtobufEncodingSchema, 100, 199),
+			},
+		},
+		{
+			name: "UnorderedContiguous",
+			sources: []MergeSource{
+				source("b", 250, db.ProtobufEncodingSchema, 100, 199),
+				source("a", 250, db.ProtobufEncodingSchema, 0, 99),
+			},
+		},
+		{
+			name: "Gap",
+			sources: []MergeSource{
+				source("a", 250, db.ProtobufEncodingSchema, 0, 99),
+				source("b", 250, db.ProtobufEncodingSchema, 150, 199),
+			},
+			gaps: []BlockInterval{{100, 149}},
+		},
+		{
+			name: "Overlap",
+			sources: []MergeSource{
+				source("a", 250, db.ProtobufEncodingSchema, 0, 120),
+				source("b", 250, db.ProtobufEncodingSchema, 100, 199),
+			},
+			conflicts: []MergeConflict{
+				{Kind: BlockRangeOverlap, Paths: [2]string{"a", "b"}, Interval: BlockInterval{100, 120}},
+			},
+		},
+		{
+			name: "ContainedRange",
+			sources: []MergeSource{
+				source("a", 250, db.ProtobufEncodingSchema, 0, 199),
+				source("b", 250, db.ProtobufEncodingSchema, 50, 60),
+				source("c", 250, db.ProtobufEncodingSchema, 200, 299),
+			},
+			conflicts: []MergeConflict{
+				{Kind: BlockRangeOverlap, Paths: [2]string{"a", "b"}, Interval: BlockInterval{50, 60}},
+			},
+		},
+		{
+			name: "OverlapOfMultipleSources",
+			sources: []MergeSource{
+				source("a", 250, db.ProtobufEncodingSchema, 0, 100),
+				source("b", 250, db.ProtobufEncodingSchema, 50, 150),
+				source("c", 250, db.ProtobufEncodingSchema, 90, 200),
+			},
+			conflicts: []MergeConflict{
+				{Kind: BlockRangeOverlap, Paths: [2]string{"a", "b"}, Interval: BlockInterval{50, 100}},
+				{Kind: BlockRangeOverlap, Paths: [2]string{"a", "c"}, Interval: BlockInterval{90, 100}},
+				{Kind: BlockRangeOverlap, Paths: [2]string{"b", "c"}, Interval: BlockInterval{90, 150}},
+			},
+		},
+		{
+			name: "ChainIdMismatch",
+			sources: []MergeSource{
+				source("a", 250, db.ProtobufEncodingSchema, 0, 99),
+				source("b", 4002, db.ProtobufEncodingSchema, 100, 199),
+			},
+			conflicts: []MergeConflict{
+				{Kind: ChainIDMismatch, Paths: [2]string{"a", "b"}, Detail: "250 vs 4002"},
+			},
+		},
+		{
+			name: "UnknownChainIdIsCompatible",
+			sources: []MergeSource{
+				source("a", 0, db.ProtobufEncodingSchema, 0, 99),
+				source("b", 250, db.ProtobufEncodingSchema, 100, 199),
+			},
+		},
+		{
+			name: "SubstateEncodingMismatch",
+			sources: []MergeSource{
+				source("a", 250, db.ProtobufEncodingSchema, 0, 99),
+				source("b", 250, db.RLPEncodingSchema, 100, 199),
+				source("c", 250, "", 200, 299),
+			},
+			conflicts: []MergeConflict{
+				{Kind: SubstateEncodingMismatch, Paths: [2]string{"a", "b"}, Detail: "protobuf vs rlp"},
+			},
+		},
+		{
+			name: "SourceWithoutBlockRangeIsIgnored",
+			sources: []MergeSource{
+				source("a", 250, db.ProtobufEncodingSchema, 0, 99),
+				{Path: "b", ChainId: 250},
+				source("c", 250, db.ProtobufEncodingSchema, 100, 199),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plan := newMergePlan(test.sources)
+			assert.Equal(t, test.sources, plan.Sources)
+			assert.Equal(t, test.conflicts, plan.Conflicts)
+			assert.Equal(t, test.gaps, plan.Gaps)
+			if test.conflicts == nil {
+				assert.NoError(t, plan.Err())
+			} else {
+				assert.ErrorContains(t, plan.Err(), test.conflicts[0].String())
+			}
+		})
+	}
+}
+
+func TestMergeConflict_StringContainsOverlappingInterval(t *testing.T) {
+	conflict := MergeConflict{Kind: BlockRangeOverlap, Paths: [2]string{"/path/a", "/path/b"}, Interval: BlockInterval{5, 10}}
+	assert.Equal(t, "overlapping block range: blocks 5-10 are contained in both /path/a and /path/b", conflict.String())
+}
+
+func TestPlanMerge_ReadsMetadataAndSubstates(t *testing.T) {
+
+	pathA := t.TempDir() + "/a"
+	dbA, err := db.NewDefaultSubstateDB(pathA)
+	require.NoError(t, err)
+	md := utils.NewAidaDbMetadata(dbA, "CRITICAL")
+	require.NoError(t, md.SetChainID(utils.OperaMainnetChainID))
+	require.NoError(t, md.SetFirstBlock(0))
+	require.NoError(t, md.SetLastBlock(99))
+
+	pathB := t.TempDir() + "/b"
+	dbB, err := db.NewDefaultSubstateDB(pathB)
+	require.NoError(t, err)
+	require.NoError(t, dbB.SetSubstateEncoding(db.ProtobufEncodingSchema))
+	ss := utils.GetTestSubstate("pb")
+	ss.Block = 50
+	ss.Env.Number = 50
+	require.NoError(t, dbB.PutSubstate(ss))
+
+	plan, err := PlanMerge(&utils.Config{LogLevel: "CRITICAL"}, []db.BaseDB{dbA, dbB}, []string{pathA, pathB})
+	require.NoError(t, err)
+	require.Len(t, plan.Sources, 2)
+
+	assert.Equal(t, utils.OperaMainnetChainID, plan.Sources[0].ChainId)
+	assert.Equal(t, BlockInterval{0, 99}, plan.Sources[0].Blocks)
+	assert.Empty(t, plan.Sources[0].SubstateEncoding)
+
+	assert.True(t, plan.Sources[1].HasBlockRange)
+	assert.Equal(t, BlockInterval{50, 50}, plan.Sources[1].Blocks)
+	assert.Equal(t, db.ProtobufEncodingSchema, plan.Sources[1].SubstateEncoding)
+	assert.Equal(t, []MergeConflict{{
+		Kind:     BlockRangeOverlap,
+		Paths:    [2]string{pathA, pathB},
+		Interval: BlockInterval{50, 50},
+	}}, plan.Conflicts)
+
+	require.NoError(t, plan.EstimateSizes([]db.BaseDB{dbA, dbB}))
+	assert.NotZero(t, plan.Sources[1].KeyCount)
+	assert.NotZero(t, plan.Sources[1].Size)
+
+	require.NoError(t, dbA.Close())
+	require.NoError(t, dbB.Close())
+}
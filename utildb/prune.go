@@ -0,0 +1,173 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utildb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utildb/dbcomponent"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+)
+
+// PrunedComponents lists the components of the AidaDb from which PruneDb removes old blocks.
+var PrunedComponents = []string{
+	string(dbcomponent.Substate),
+	string(dbcomponent.Update),
+	string(dbcomponent.Delete),
+	string(dbcomponent.StateHash),
+}
+
+// PruneDb removes substates, update sets, destroyed accounts and state hashes of all blocks
+// below keepFrom from the AidaDb and moves the first block in its metadata to keepFrom.
+//
+// Update sets span the range of blocks following the previous update set, hence keepFrom
+// has to directly follow an update set. Otherwise, the pruning is refused unless force is set.
+//
+// The metadata is updated before any key is deleted, and keys are deleted in the order of
+// their blocks. Thus, an interrupted pruning is completed by running it again with the same
+// keepFrom; the update-set check is skipped once the metadata starts at keepFrom or later.
+func PruneDb(md *utils.AidaDbMetadata, keepFrom uint64, force bool, log logger.Logger) error {
+	database := md.Db
+	first, last := md.GetFirstBlock(), md.GetLastBlock()
+	if last != 0 && keepFrom > last {
+		return fmt.Errorf("keep-from block %d is beyond the last block %d of the AidaDb", keepFrom, last)
+	}
+
+	if first < keepFrom {
+		if !force {
+			if err := checkUpdateSetBoundary(database, keepFrom); err != nil {
+				return err
+			}
+		}
+		if err := md.SetFirstBlock(keepFrom); err != nil {
+			return fmt.Errorf("cannot update first block; %w", err)
+		}
+		log.Noticef("First block of AidaDb moved from %d to %d", first, keepFrom)
+	} else {
+		log.Noticef("First block of AidaDb is already %d; removing remaining blocks below %d", first, keepFrom)
+	}
+
+	limits := []struct {
+		component string
+		prefix    string
+		limit     []byte
+	}{
+		{string(dbcomponent.Substate), db.SubstateDBPrefix, db.SubstateDBKey(keepFrom, 0)},
+		{string(dbcomponent.Update), db.UpdateDBPrefix, db.UpdateDBKey(keepFrom)},
+		{string(dbcomponent.Delete), db.DestroyedAccountPrefix, db.EncodeDestroyedAccountKey(keepFrom, 0)},
+	}
+	for _, l := range limits {
+		deleted, err := deleteKeys(database, []byte(l.prefix), func(key []byte) (bool, bool, error) {
+			below := bytes.Compare(key, l.limit) < 0
+			return below, below, nil
+		})
+		if err != nil {
+			return fmt.Errorf("cannot prune %v; %w", l.component, err)
+		}
+		log.Noticef("Pruned %d %v keys", deleted, l.component)
+	}
+
+	// state hash keys contain the block as a hex string, hence they are not ordered by block
+	deleted, err := deleteKeys(database, []byte(db.StateRootHashPrefix), func(key []byte) (bool, bool, error) {
+		block, err := db.StateHashKeyToUint64(key)
+		if err != nil {
+			return false, false, err
+		}
+		return block < keepFrom, true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot prune %v; %w", dbcomponent.StateHash, err)
+	}
+	log.Noticef("Pruned %d %v keys", deleted, dbcomponent.StateHash)
+	return nil
+}
+
+// checkUpdateSetBoundary checks that no update set spans blocks both below and starting at keepFrom.
+func checkUpdateSetBoundary(database db.BaseDB, keepFrom uint64) error {
+	iter := database.NewIterator([]byte(db.UpdateDBPrefix), nil)
+	defer iter.Release()
+
+	var (
+		previous uint64
+		found    bool
+	)
+	for iter.Next() {
+		block, err := db.DecodeUpdateSetKey(iter.Key())
+		if err != nil {
+			return err
+		}
+		if block >= keepFrom {
+			if found && previous+1 != keepFrom {
+				return fmt.Errorf("keep-from block %d is in the middle of the update-set interval %d-%d; use --force to prune anyway", keepFrom, previous+1, block)
+			}
+			return nil
+		}
+		previous, found = block, true
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if found && previous+1 != keepFrom {
+		return fmt.Errorf("keep-from block %d is in the middle of the update-set interval starting at %d; use --force to prune anyway", keepFrom, previous+1)
+	}
+	return nil
+}
+
+// deleteKeys deletes keys with given prefix in batches. For each key, match reports whether
+// the key is deleted and whether the iteration continues.
+func deleteKeys(database db.BaseDB, prefix []byte, match func(key []byte) (bool, bool, error)) (uint64, error) {
+	iter := database.NewIterator(prefix, nil)
+	defer iter.Release()
+
+	batch := database.NewBatch()
+	var deleted uint64
+	for iter.Next() {
+		remove, next, err := match(iter.Key())
+		if err != nil {
+			return deleted, err
+		}
+		if !next {
+			break
+		}
+		if !remove {
+			continue
+		}
+		if err = batch.Delete(iter.Key()); err != nil {
+			return deleted, err
+		}
+		deleted++
+		if batch.ValueSize() > kvdb.IdealBatchSize {
+			if err = batch.Write(); err != nil {
+				return deleted, fmt.Errorf("cannot write batch; %w", err)
+			}
+			batch.Reset()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return deleted, err
+	}
+	if batch.ValueSize() > 0 {
+		if err := batch.Write(); err != nil {
+			return deleted, fmt.Errorf("cannot write batch; %w", err)
+		}
+	}
+	return deleted, nil
+}
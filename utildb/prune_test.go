@@ -0,0 +1,188 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utildb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/utils"
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// prunedTestBlocks is the number of blocks of the synthetic AidaDb of pruning tests.
+const prunedTestBlocks = 20
+
+// codeKey is a key of the synthetic AidaDb not related to blocks.
+var codeKey = string(append([]byte(db.CodeDBPrefix), make([]byte, 32)...))
+
+// createPruneTestDb creates an AidaDb of blocks 1-20 with two substates per block, update
+// sets at blocks 4, 9, 14 and 19, a destroyed account at every odd block, a state hash
+// for every block, a contract code and metadata.
+func createPruneTestDb(t *testing.T) *utils.AidaDbMetadata {
+	database, err := db.NewDefaultSubstateDB(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, database.Close()) })
+
+	for block := uint64(1); block <= prunedTestBlocks; block++ {
+		keys := [][]byte{
+			db.SubstateDBKey(block, 0),
+			db.SubstateDBKey(block, 1),
+			[]byte(db.StateRootHashPrefix + hexutil.EncodeUint64(block)),
+		}
+		if block%5 == 4 {
+			keys = append(keys, db.UpdateDBKey(block))
+		}
+		if block%2 == 1 {
+			keys = append(keys, db.EncodeDestroyedAccountKey(block, 0))
+		}
+		for _, key := range keys {
+			require.NoError(t, database.Put(key, []byte{1}))
+		}
+	}
+	require.NoError(t, database.Put([]byte(codeKey), []byte{1}))
+
+	md := utils.NewAidaDbMetadata(database, "ERROR")
+	require.NoError(t, md.SetFirstBlock(1))
+	require.NoError(t, md.SetLastBlock(prunedTestBlocks))
+	return md
+}
+
+// expectedKeysAfterPruning lists all keys of the synthetic AidaDb retained when pruning blocks below keepFrom.
+func expectedKeysAfterPruning(keepFrom uint64) map[string]struct{} {
+	keys := map[string]struct{}{
+		codeKey:                {},
+		utils.FirstBlockPrefix: {},
+		utils.LastBlockPrefix:  {},
+	}
+	for block := keepFrom; block <= prunedTestBlocks; block++ {
+		keys[string(db.SubstateDBKey(block, 0))] = struct{}{}
+		keys[string(db.SubstateDBKey(block, 1))] = struct{}{}
+		keys[db.StateRootHashPrefix+hexutil.EncodeUint64(block)] = struct{}{}
+		if block%5 == 4 {
+			keys[string(db.UpdateDBKey(block))] = struct{}{}
+		}
+		if block%2 == 1 {
+			keys[string(db.EncodeDestroyedAccountKey(block, 0))] = struct{}{}
+		}
+	}
+	return keys
+}
+
+func allKeys(t *testing.T, database db.BaseDB) map[string]struct{} {
+	keys := make(map[string]struct{})
+	iter := database.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		keys[string(iter.Key())] = struct{}{}
+	}
+	require.NoError(t, iter.Error())
+	return keys
+}
+
+func newPruneTestLogger(ctrl *gomock.Controller) logger.Logger {
+	log := logger.NewMockLogger(ctrl)
+	log.EXPECT().Noticef(gomock.Any(), gomock.Any()).AnyTimes()
+	return log
+}
+
+func TestPruneDb_RemovesAllBlocksBelowKeepFrom(t *testing.T) {
+	for _, keepFrom := range []uint64{1, 5, 10, 20} {
+		t.Run(fmt.Sprint(keepFrom), func(t *testing.T) {
+			md := createPruneTestDb(t)
+			require.NoError(t, PruneDb(md, keepFrom, false, newPruneTestLogger(gomock.NewController(t))))
+
+			assert.Equal(t, expectedKeysAfterPruning(keepFrom), allKeys(t, md.Db))
+			assert.Equal(t, keepFrom, md.GetFirstBlock())
+			assert.Equal(t, uint64(prunedTestBlocks), md.GetLastBlock())
+		})
+	}
+}
+
+func TestPruneDb_RefusesToSplitUpdateSetInterval(t *testing.T) {
+	md := createPruneTestDb(t)
+	before := allKeys(t, md.Db)
+
+	err := PruneDb(md, 12, false, newPruneTestLogger(gomock.NewController(t)))
+	require.ErrorContains(t, err, "keep-from block 12 is in the middle of the update-set interval 10-14")
+	assert.Equal(t, before, allKeys(t, md.Db))
+	assert.Equal(t, uint64(1), md.GetFirstBlock())
+}
+
+func TestPruneDb_RefusesToSplitLastUpdateSetInterval(t *testing.T) {
+	md := createPruneTestDb(t)
+	err := PruneDb(md, 20, false, newPruneTestLogger(gomock.NewController(t)))
+	require.NoError(t, err)
+
+	md = createPruneTestDb(t)
+	require.NoError(t, md.SetLastBlock(30))
+	err = PruneDb(md, 25, false, newPruneTestLogger(gomock.NewController(t)))
+	require.ErrorContains(t, err, "update-set interval starting at 20")
+}
+
+func TestPruneDb_ForcePrunesInTheMiddleOfUpdateSetInterval(t *testing.T) {
+	md := createPruneTestDb(t)
+	require.NoError(t, PruneDb(md, 12, true, newPruneTestLogger(gomock.NewController(t))))
+	assert.Equal(t, expectedKeysAfterPruning(12), allKeys(t, md.Db))
+	assert.Equal(t, uint64(12), md.GetFirstBlock())
+}
+
+func TestPruneDb_RefusesKeepFromBeyondLastBlock(t *testing.T) {
+	md := createPruneTestDb(t)
+	err := PruneDb(md, prunedTestBlocks+1, true, newPruneTestLogger(gomock.NewController(t)))
+	require.ErrorContains(t, err, "beyond the last block")
+	assert.Equal(t, uint64(1), md.GetFirstBlock())
+}
+
+func TestPruneDb_InterruptedPruningIsCompletedByRestart(t *testing.T) {
+	md := createPruneTestDb(t)
+
+	// an interrupted pruning updated the metadata and removed some of the keys, including
+	// the update set preceding keep-from, which makes the update-set check fail
+	require.NoError(t, md.SetFirstBlock(10))
+	for block := uint64(1); block < 10; block++ {
+		require.NoError(t, md.Db.Delete(db.SubstateDBKey(block, 0)))
+	}
+	require.NoError(t, md.Db.Delete(db.UpdateDBKey(4)))
+	require.NoError(t, md.Db.Delete(db.UpdateDBKey(9)))
+
+	require.NoError(t, PruneDb(md, 10, false, newPruneTestLogger(gomock.NewController(t))))
+	assert.Equal(t, expectedKeysAfterPruning(10), allKeys(t, md.Db))
+	assert.Equal(t, uint64(10), md.GetFirstBlock())
+
+	// pruning again has no effect
+	require.NoError(t, PruneDb(md, 10, false, newPruneTestLogger(gomock.NewController(t))))
+	assert.Equal(t, expectedKeysAfterPruning(10), allKeys(t, md.Db))
+}
+
+func TestPruneDb_StateHashesAreNotOrderedByBlock(t *testing.T) {
+	// 0x10 is ordered before 0x9, hence state hashes cannot be pruned by a key range
+	md := createPruneTestDb(t)
+	require.NoError(t, PruneDb(md, 10, false, newPruneTestLogger(gomock.NewController(t))))
+
+	has, err := md.Db.Has([]byte(db.StateRootHashPrefix + "0x9"))
+	require.NoError(t, err)
+	assert.False(t, has)
+	has, err = md.Db.Has([]byte(db.StateRootHashPrefix + "0x10"))
+	require.NoError(t, err)
+	assert.True(t, has)
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+package utildb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+)
+
+// ScanCachePolicy determines how the page cache is treated while an AidaDb is scanned sequentially.
+type ScanCachePolicy string
+
+const (
+	KeepScanCachePolicy       ScanCachePolicy = "keep"        // page cache is left to the OS
+	DropBehindScanCachePolicy ScanCachePolicy = "drop-behind" // pages of scanned table files are dropped using POSIX_FADV_DONTNEED
+	DirectScanCachePolicy     ScanCachePolicy = "direct"      // page cache is bypassed if supported by the platform
+)
+
+// scanCacheDropInterval defines how often pages of the scanned db are dropped from page cache.
+const scanCacheDropInterval = 10 * time.Second
+
+// ParseScanCachePolicy converts given string to ScanCachePolicy.
+func ParseScanCachePolicy(policy string) (ScanCachePolicy, error) {
+	switch p := ScanCachePolicy(strings.ToLower(policy)); p {
+	case "", KeepScanCachePolicy:
+		return KeepScanCachePolicy, nil
+	case DropBehindScanCachePolicy, DirectScanCachePolicy:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown scan cache policy %q; allowed: %v, %v, %v", policy, KeepScanCachePolicy, DropBehindScanCachePolicy, DirectScanCachePolicy)
+	}
+}
+
+// StartScanCachePolicy applies given policy to the LevelDB located at dbPath for the duration of
+// a sequential scan. Returned function must be called once the scan is finished. Unsupported
+// policies fall back to the closest supported one, so the scan itself is never affected.
+func StartScanCachePolicy(policy string, dbPath string, log logger.Logger) (func(), error) {
+	p, err := ParseScanCachePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	if p == KeepScanCachePolicy {
+		return func() {}, nil
+	}
+
+	if p == DirectScanCachePolicy {
+		// LevelDB opens its table files internally hence O_DIRECT cannot be requested
+		log.Warningf("Direct I/O is not supported for LevelDB table files; falling back to %v", DropBehindScanCachePolicy)
+	}
+
+	if !fadviseSupported {
+		log.Warningf("Scan cache policy %v is not supported on this platform; page cache is left to the OS", p)
+		return func() {}, nil
+	}
+
+	d := &scanCacheDropper{
+		path:   dbPath,
+		log:    log,
+		ticker: time.NewTicker(scanCacheDropInterval),
+		stop:   make(chan struct{}),
+	}
+	d.wg.Add(1)
+	go d.run()
+
+	log.Noticef("Scan cache policy %v enabled for %v", DropBehindScanCachePolicy, dbPath)
+	return d.close, nil
+}
+
+// scanCacheDropper periodically evicts pages of LevelDB table files from page cache, so that
+// a long sequential scan does not push out pages of other processes running on the same host.
+type scanCacheDropper struct {
+	path   string
+	log    logger.Logger
+	ticker *time.Ticker
+	stop   chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+}
+
+func (d *scanCacheDropper) run() {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-d.ticker.C:
+			d.drop()
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// close stops the dropper and evicts the rest of consumed pages.
+func (d *scanCacheDropper) close() {
+	d.once.Do(func() {
+		d.ticker.Stop()
+		close(d.stop)
+		d.wg.Wait()
+		d.drop()
+	})
+}
+
+// drop evicts pages of all table files of the db.
+func (d *scanCacheDropper) drop() {
+	if _, err := dropTableCaches(d.path); err != nil {
+		d.log.Warningf("Cannot drop page cache of %v; %v", d.path, err)
+	}
+}
+
+// dropTableCaches advises the kernel to drop cached pages of all LevelDB table
+// files in given directory and returns the number of processed files.
+func dropTableCaches(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("cannot read db directory; %w", err)
+	}
+
+	count := 0
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".ldb" && ext != ".sst") {
+			continue
+		}
+		if err = dropFileCache(filepath.Join(dir, entry.Name())); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+package utildb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanCachePolicy_ParseScanCachePolicy(t *testing.T) {
+	tests := map[string]ScanCachePolicy{
+		"":            KeepScanCachePolicy,
+		"keep":        KeepScanCachePolicy,
+		"Drop-Behind": DropBehindScanCachePolicy,
+		"direct":      DirectScanCachePolicy,
+	}
+	for input, want := range tests {
+		got, err := ParseScanCachePolicy(input)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+
+	_, err := ParseScanCachePolicy("unknown")
+	require.ErrorContains(t, err, "unknown scan cache policy")
+}
+
+func TestScanCachePolicy_StartScanCachePolicy_UnknownPolicy(t *testing.T) {
+	_, err := StartScanCachePolicy("unknown", t.TempDir(), logger.NewLogger("critical", "test"))
+	require.Error(t, err)
+}
+
+func TestScanCachePolicy_StartScanCachePolicy_StopCanBeCalledRepeatedly(t *testing.T) {
+	for _, policy := range []string{"keep", "drop-behind", "direct"} {
+		t.Run(policy, func(t *testing.T) {
+			stop, err := StartScanCachePolicy(policy, t.TempDir(), logger.NewLogger("critical", "test"))
+			require.NoError(t, err)
+			stop()
+			stop()
+		})
+	}
+}
+
+func TestScanCachePolicy_DropTableCaches_OnlyTableFilesAreProcessed(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"000001.ldb", "000002.sst", "MANIFEST-000003", "LOG"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("data"), 0600))
+	}
+
+	count, err := dropTableCaches(dir)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func TestScanCachePolicy_DropTableCaches_MissingDirectory(t *testing.T) {
+	_, err := dropTableCaches(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorContains(t, err, "cannot read db directory")
+}
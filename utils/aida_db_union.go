@@ -0,0 +1,306 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/comparer"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// ErrAidaDbUnionReadOnly is returned by all write operations on a union of aida-db slices.
+var ErrAidaDbUnionReadOnly = errors.New("aida-db union is read-only")
+
+// blockKeyedPrefixes lists the record prefixes whose keys start with a big-endian block number.
+var blockKeyedPrefixes = []string{
+	db.SubstateDBPrefix,
+	db.UpdateDBPrefix,
+	db.DestroyedAccountPrefix,
+	db.ExceptionDBPrefix,
+	db.BlockHashPrefix,
+}
+
+// SplitAidaDbPaths splits the value of --aida-db into the paths of individual aida-db slices.
+func SplitAidaDbPaths(value string) []string {
+	var paths []string
+	for _, path := range strings.Split(value, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// OpenAidaDb opens the aida-db given by --aida-db in read-only mode. If the flag lists
+// several comma-separated paths, the slices are combined to a read-only union view
+// which has to cover the configured block range without gaps. The substate encoding
+// is detected from the data and has to match --substate-encoding if it is set.
+func OpenAidaDb(cfg *Config) (db.SubstateDB, error) {
+	sdb, err := openAidaDbInRange(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = ApplySubstateEncoding(sdb, cfg.SubstateEncoding); err != nil {
+		return nil, errors.Join(err, sdb.Close())
+	}
+	return sdb, nil
+}
+
+func openAidaDbInRange(cfg *Config) (db.SubstateDB, error) {
+	paths := SplitAidaDbPaths(cfg.AidaDb)
+	if len(paths) <= 1 {
+		return db.NewReadOnlySubstateDB(cfg.AidaDb)
+	}
+	union, err := openAidaDbUnion(paths, cfg.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	if err = union.checkCoverage(cfg.First, cfg.Last); err != nil {
+		return nil, errors.Join(err, union.Close())
+	}
+	return union.substateDB()
+}
+
+// openAidaDb opens given aida-db path(s) in read-only mode without checking the block coverage.
+func openAidaDb(value string, logLevel string) (db.SubstateDB, error) {
+	paths := SplitAidaDbPaths(value)
+	if len(paths) <= 1 {
+		return db.NewReadOnlySubstateDB(value)
+	}
+	union, err := openAidaDbUnion(paths, logLevel)
+	if err != nil {
+		return nil, err
+	}
+	return union.substateDB()
+}
+
+// aidaDbExists returns true if all aida-db slices given by value exist.
+func aidaDbExists(value string) bool {
+	paths := SplitAidaDbPaths(value)
+	if len(paths) == 0 {
+		return false
+	}
+	for _, path := range paths {
+		if !directoryExists(path) {
+			return false
+		}
+	}
+	return true
+}
+
+// aidaDbSlice is one of the aida-db databases forming a union.
+type aidaDbSlice struct {
+	path        string
+	db          db.SubstateDB
+	first, last uint64 // block range according to the metadata of the slice
+	chainId     ChainID
+}
+
+// aidaDbUnion is a read-only database adapter routing reads to aida-db slices holding
+// adjacent block ranges. Records stored by block, such as substates, update-sets,
+// state and block hashes, are read from the slice covering the block, while other
+// records, for instance contract codes, are looked up in all slices.
+type aidaDbUnion struct {
+	slices []aidaDbSlice // sorted by block range
+}
+
+func openAidaDbUnion(paths []string, logLevel string) (*aidaDbUnion, error) {
+	u := &aidaDbUnion{}
+	for _, path := range paths {
+		sdb, err := db.NewReadOnlySubstateDB(path)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("cannot open aida-db slice %v; %w", path, err), u.Close())
+		}
+		md := NewAidaDbMetadata(sdb, logLevel)
+		u.slices = append(u.slices, aidaDbSlice{
+			path:    path,
+			db:      sdb,
+			first:   md.GetFirstBlock(),
+			last:    md.GetLastBlock(),
+			chainId: md.GetChainID(),
+		})
+		if md.GetLastBlock() == 0 {
+			return nil, errors.Join(fmt.Errorf("aida-db slice %v does not contain metadata; please generate them using util-db metadata generate", path), u.Close())
+		}
+	}
+
+	sort.Slice(u.slices, func(i, j int) bool { return u.slices[i].first < u.slices[j].first })
+	for i := 1; i < len(u.slices); i++ {
+		prev, cur := u.slices[i-1], u.slices[i]
+		if prev.last >= cur.first {
+			return nil, errors.Join(fmt.Errorf("aida-db slices %v (%v-%v) and %v (%v-%v) overlap",
+				prev.path, prev.first, prev.last, cur.path, cur.first, cur.last), u.Close())
+		}
+		if prev.chainId != cur.chainId {
+			return nil, errors.Join(fmt.Errorf("aida-db slices %v and %v belong to different chains (%v, %v)",
+				prev.path, cur.path, prev.chainId, cur.chainId), u.Close())
+		}
+		if prev.db.GetSubstateEncoding() != cur.db.GetSubstateEncoding() {
+			return nil, errors.Join(fmt.Errorf("aida-db slices %v and %v use different substate encodings (%v, %v)",
+				prev.path, cur.path, prev.db.GetSubstateEncoding(), cur.db.GetSubstateEncoding()), u.Close())
+		}
+	}
+	return u, nil
+}
+
+// checkCoverage makes sure that every block of given range is held by a slice.
+func (u *aidaDbUnion) checkCoverage(first, last uint64) error {
+	head, tail := u.slices[0], u.slices[len(u.slices)-1]
+	if first < head.first {
+		return fmt.Errorf("first block %v is not covered by aida-db; first slice %v starts at block %v", first, head.path, head.first)
+	}
+	if last > tail.last {
+		return fmt.Errorf("last block %v is not covered by aida-db; last slice %v ends at block %v", last, tail.path, tail.last)
+	}
+	for i := 1; i < len(u.slices); i++ {
+		prev, cur := u.slices[i-1], u.slices[i]
+		if prev.last+1 == cur.first || prev.last >= last || cur.first <= first {
+			continue
+		}
+		return fmt.Errorf("aida-db slices %v and %v are not contiguous; blocks %v-%v are missing", prev.path, cur.path, prev.last+1, cur.first-1)
+	}
+	return nil
+}
+
+// substateDB returns a substate database reading through the union.
+func (u *aidaDbUnion) substateDB() (db.SubstateDB, error) {
+	base := unionBaseDB{BaseDB: u.slices[0].db, backend: u}
+	return db.MakeDefaultSubstateDBFromBaseDBWithEncoding(base, u.slices[0].db.GetSubstateEncoding())
+}
+
+// unionBaseDB hands the union as backend to the constructors of the substate library,
+// which build their databases on top of the backend of a given BaseDB.
+type unionBaseDB struct {
+	db.BaseDB
+	backend db.DbAdapter
+}
+
+func (b unionBaseDB) GetBackend() db.DbAdapter {
+	return b.backend
+}
+
+// candidates returns the slices which may hold the record of given key in lookup order.
+func (u *aidaDbUnion) candidates(key []byte) []aidaDbSlice {
+	if block, ok := blockOfKey(key); ok {
+		for _, slice := range u.slices {
+			if slice.first <= block && block <= slice.last {
+				return []aidaDbSlice{slice}
+			}
+		}
+		return nil
+	}
+	switch string(key) {
+	case LastBlockPrefix, LastEpochPrefix:
+		// the end of the union is recorded by its last slice
+		reversed := make([]aidaDbSlice, len(u.slices))
+		for i, slice := range u.slices {
+			reversed[len(u.slices)-1-i] = slice
+		}
+		return reversed
+	}
+	return u.slices
+}
+
+// blockOfKey returns the block of a record stored by block.
+func blockOfKey(key []byte) (uint64, bool) {
+	for _, prefix := range blockKeyedPrefixes {
+		if bytes.HasPrefix(key, []byte(prefix)) && len(key) >= len(prefix)+8 {
+			return binary.BigEndian.Uint64(key[len(prefix):]), true
+		}
+	}
+	if bytes.HasPrefix(key, []byte(db.StateRootHashPrefix)) {
+		if block, err := db.StateHashKeyToUint64(key); err == nil {
+			return block, true
+		}
+	}
+	return 0, false
+}
+
+func (u *aidaDbUnion) Get(key []byte, ro *opt.ReadOptions) ([]byte, error) {
+	for _, slice := range u.candidates(key) {
+		value, err := slice.db.GetBackend().Get(key, ro)
+		if errors.Is(err, leveldb.ErrNotFound) {
+			continue
+		}
+		return value, err
+	}
+	return nil, leveldb.ErrNotFound
+}
+
+func (u *aidaDbUnion) Has(key []byte, ro *opt.ReadOptions) (bool, error) {
+	for _, slice := range u.candidates(key) {
+		has, err := slice.db.GetBackend().Has(key, ro)
+		if err != nil || has {
+			return has, err
+		}
+	}
+	return false, nil
+}
+
+// NewIterator merges the iterators of all slices in key order.
+func (u *aidaDbUnion) NewIterator(r *util.Range, ro *opt.ReadOptions) iterator.Iterator {
+	iters := make([]iterator.Iterator, 0, len(u.slices))
+	for _, slice := range u.slices {
+		iters = append(iters, slice.db.GetBackend().NewIterator(r, ro))
+	}
+	return iterator.NewMergedIterator(iters, comparer.DefaultComparer, true)
+}
+
+// GetProperty returns the property of the first slice.
+func (u *aidaDbUnion) GetProperty(property string) (string, error) {
+	return u.slices[0].db.GetBackend().GetProperty(property)
+}
+
+// Stats returns the statistics of the first slice.
+func (u *aidaDbUnion) Stats(s *leveldb.DBStats) error {
+	return u.slices[0].db.GetBackend().Stats(s)
+}
+
+func (u *aidaDbUnion) Close() error {
+	var err error
+	for _, slice := range u.slices {
+		err = errors.Join(err, slice.db.Close())
+	}
+	u.slices = nil
+	return err
+}
+
+func (u *aidaDbUnion) Put([]byte, []byte, *opt.WriteOptions) error {
+	return ErrAidaDbUnionReadOnly
+}
+
+func (u *aidaDbUnion) Delete([]byte, *opt.WriteOptions) error {
+	return ErrAidaDbUnionReadOnly
+}
+
+func (u *aidaDbUnion) Write(*leveldb.Batch, *opt.WriteOptions) error {
+	return ErrAidaDbUnionReadOnly
+}
+
+func (u *aidaDbUnion) CompactRange(util.Range) error {
+	return ErrAidaDbUnionReadOnly
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/0xsoniclabs/substate/types"
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// createAidaDbSlice creates an aida-db slice holding a block hash and a state root for
+// every block of given range and returns its path.
+func createAidaDbSlice(t *testing.T, first, last uint64, chainId ChainID, extra map[string][]byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "aida-db")
+	sdb, err := db.NewDefaultSubstateDB(path)
+	require.NoError(t, err)
+
+	md := NewAidaDbMetadata(sdb, "CRITICAL")
+	require.NoError(t, md.SetFirstBlock(first))
+	require.NoError(t, md.SetLastBlock(last))
+	require.NoError(t, md.SetChainID(chainId))
+	for block := first; block <= last; block++ {
+		require.NoError(t, sdb.Put(db.BlockHashDBKey(block), []byte{byte(block)}))
+		require.NoError(t, sdb.Put([]byte(db.StateRootHashPrefix+"0x"+strconv.FormatUint(block, 16)), make([]byte, 32)))
+	}
+	for key, value := range extra {
+		require.NoError(t, sdb.Put([]byte(key), value))
+	}
+	require.NoError(t, sdb.Close())
+	return path
+}
+
+func TestSplitAidaDbPaths(t *testing.T) {
+	require.Equal(t, []string{"a"}, SplitAidaDbPaths("a"))
+	require.Equal(t, []string{"a", "b"}, SplitAidaDbPaths("a, b,"))
+	require.Empty(t, SplitAidaDbPaths(""))
+}
+
+func TestAidaDbUnion_RoutesReadsByBlock(t *testing.T) {
+	code := string(db.CodeDBKey(types.Hash{1}))
+	lower := createAidaDbSlice(t, 0, 9, SonicMainnetChainID, nil)
+	upper := createAidaDbSlice(t, 10, 19, SonicMainnetChainID, map[string][]byte{code: {0xc0, 0xde}})
+
+	union, err := OpenAidaDb(&Config{AidaDb: upper + "," + lower, First: 5, Last: 15, LogLevel: "CRITICAL"})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, union.Close())
+	}()
+
+	for _, block := range []uint64{0, 9, 10, 19} {
+		value, err := union.Get(db.BlockHashDBKey(block))
+		require.NoError(t, err)
+		require.Equal(t, []byte{byte(block)}, value)
+	}
+	_, err = union.Get(db.BlockHashDBKey(20))
+	require.ErrorIs(t, err, leveldb.ErrNotFound)
+
+	hashes := db.MakeHashProvider(union)
+	for _, block := range []int{9, 10} {
+		_, err = hashes.GetStateRootHash(block)
+		require.NoError(t, err)
+	}
+
+	value, err := db.MakeDefaultCodeDBFromBaseDB(union).GetCode(types.Hash{1})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xc0, 0xde}, value)
+
+	md := NewAidaDbMetadata(union, "CRITICAL")
+	require.Equal(t, uint64(0), md.GetFirstBlock())
+	require.Equal(t, uint64(19), md.GetLastBlock())
+}
+
+func TestAidaDbUnion_IteratesAllSlicesInOrder(t *testing.T) {
+	lower := createAidaDbSlice(t, 0, 2, SonicMainnetChainID, nil)
+	upper := createAidaDbSlice(t, 3, 5, SonicMainnetChainID, nil)
+
+	union, err := openAidaDb(upper+","+lower, "CRITICAL")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, union.Close())
+	}()
+
+	iter := union.NewIterator([]byte(db.BlockHashPrefix), nil)
+	defer iter.Release()
+	var blocks []uint64
+	for iter.Next() {
+		block, err := db.DecodeBlockHashDBKey(iter.Key())
+		require.NoError(t, err)
+		blocks = append(blocks, block)
+	}
+	require.NoError(t, iter.Error())
+	require.Equal(t, []uint64{0, 1, 2, 3, 4, 5}, blocks)
+}
+
+func TestAidaDbUnion_RejectsWrites(t *testing.T) {
+	lower := createAidaDbSlice(t, 0, 2, SonicMainnetChainID, nil)
+	upper := createAidaDbSlice(t, 3, 5, SonicMainnetChainID, nil)
+
+	union, err := openAidaDb(lower+","+upper, "CRITICAL")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, union.Close())
+	}()
+
+	require.ErrorIs(t, union.Put(db.BlockHashDBKey(1), []byte{1}), ErrAidaDbUnionReadOnly)
+	require.ErrorIs(t, union.Delete(db.BlockHashDBKey(1)), ErrAidaDbUnionReadOnly)
+	batch := union.NewBatch()
+	require.NoError(t, batch.Put(db.BlockHashDBKey(1), []byte{1}))
+	require.ErrorIs(t, batch.Write(), ErrAidaDbUnionReadOnly)
+	require.ErrorIs(t, union.Compact(nil, nil), ErrAidaDbUnionReadOnly)
+}
+
+func TestAidaDbUnion_ValidatesSlices(t *testing.T) {
+	tests := map[string]struct {
+		slices      [][2]uint64
+		chainIds    []ChainID
+		first, last uint64
+		wantErr     string
+	}{
+		"contiguous": {
+			slices: [][2]uint64{{0, 9}, {10, 19}},
+			first:  0, last: 19,
+		},
+		"gap outside of range": {
+			slices: [][2]uint64{{0, 9}, {12, 19}, {20, 29}},
+			first:  12, last: 29,
+		},
+		"overlap": {
+			slices: [][2]uint64{{0, 10}, {10, 19}},
+			first:  0, last: 19,
+			wantErr: "overlap",
+		},
+		"gap": {
+			slices: [][2]uint64{{0, 9}, {12, 19}},
+			first:  5, last: 15,
+			wantErr: "blocks 10-11 are missing",
+		},
+		"range starts before first slice": {
+			slices: [][2]uint64{{5, 9}, {10, 19}},
+			first:  0, last: 15,
+			wantErr: "first block 0 is not covered",
+		},
+		"range ends after last slice": {
+			slices: [][2]uint64{{0, 9}, {10, 19}},
+			first:  0, last: 25,
+			wantErr: "last block 25 is not covered",
+		},
+		"different chains": {
+			slices:   [][2]uint64{{0, 9}, {10, 19}},
+			chainIds: []ChainID{SonicMainnetChainID, EthereumChainID},
+			first:    0, last: 19,
+			wantErr: "different chains",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var paths []string
+			for i, slice := range test.slices {
+				chainId := SonicMainnetChainID
+				if test.chainIds != nil {
+					chainId = test.chainIds[i]
+				}
+				paths = append(paths, createAidaDbSlice(t, slice[0], slice[1], chainId, nil))
+			}
+			cfg := &Config{First: test.first, Last: test.last, LogLevel: "CRITICAL"}
+			for i, path := range paths {
+				if i > 0 {
+					cfg.AidaDb += ","
+				}
+				cfg.AidaDb += path
+			}
+
+			union, err := OpenAidaDb(cfg)
+			if test.wantErr != "" {
+				require.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, union.Close())
+		})
+	}
+}
+
+func TestAidaDbUnion_RejectsSliceWithoutMetadata(t *testing.T) {
+	lower := createAidaDbSlice(t, 0, 9, SonicMainnetChainID, nil)
+	path := filepath.Join(t.TempDir(), "aida-db")
+	sdb, err := db.NewDefaultSubstateDB(path)
+	require.NoError(t, err)
+	require.NoError(t, sdb.Close())
+
+	_, err = openAidaDb(lower+","+path, "CRITICAL")
+	require.ErrorContains(t, err, "does not contain metadata")
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+// Package bundle packs all artifacts of a run into a single tar.zst file
+// so that runs of A/B experiments can be archived and compared.
+package bundle
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// ManifestName is the name of the manifest within a bundle.
+	ManifestName = "manifest.json"
+	// SummaryName is the name of the run summary within a bundle.
+	SummaryName = "summary.json"
+	// ConfigName is the name of the effective configuration within a bundle.
+	ConfigName = "config.json"
+
+	manifestVersion = 1
+)
+
+// Manifest describes the run a bundle was created from and the files it contains.
+type Manifest struct {
+	Version   int               `json:"version"`
+	CreatedAt string            `json:"createdAt"`
+	Tool      string            `json:"tool"`   // application and command which produced the run
+	First     uint64            `json:"first"`  // first block of the run
+	Last      uint64            `json:"last"`   // last block of the run
+	Inputs    map[string]string `json:"inputs"` // data inputs of the run which must match for bundles to be comparable
+	Files     []string          `json:"files"`  // names of all files within the bundle except the manifest
+}
+
+// Bundle is a run artifact bundle loaded into memory.
+type Bundle struct {
+	Manifest Manifest
+	Files    map[string][]byte
+}
+
+// NewBundle creates an empty bundle for the given run.
+func NewBundle(tool string, first, last uint64, inputs map[string]string) *Bundle {
+	return &Bundle{
+		Manifest: Manifest{
+			Version:   manifestVersion,
+			CreatedAt: time.Now().UTC().Format(time.RFC3339),
+			Tool:      tool,
+			First:     first,
+			Last:      last,
+			Inputs:    inputs,
+		},
+		Files: make(map[string][]byte),
+	}
+}
+
+// Add adds a file with given content into the bundle.
+func (b *Bundle) Add(name string, data []byte) {
+	b.Files[name] = data
+}
+
+// AddJson adds the given value encoded as JSON into the bundle.
+func (b *Bundle) AddJson(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode %v; %w", name, err)
+	}
+	b.Add(name, data)
+	return nil
+}
+
+// AddFile adds an existing file into the bundle under the name of the file.
+// Missing files are skipped and reported by the returned boolean.
+func (b *Bundle) AddFile(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("cannot read %v; %w", path, err)
+	}
+	b.Add(filepath.Base(path), data)
+	return true, nil
+}
+
+// Write writes the bundle into a tar.zst file at given path.
+func (b *Bundle) Write(path string) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("cannot create bundle %v; %w", path, err)
+	}
+	defer func() {
+		err = errors.Join(err, file.Close())
+	}()
+
+	enc, err := zstd.NewWriter(file)
+	if err != nil {
+		return fmt.Errorf("cannot create zstd writer; %w", err)
+	}
+	tw := tar.NewWriter(enc)
+
+	names := make([]string, 0, len(b.Files))
+	for name := range b.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	b.Manifest.Files = names
+
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode manifest; %w", err)
+	}
+	if err = writeEntry(tw, ManifestName, manifest); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err = writeEntry(tw, name, b.Files[name]); err != nil {
+			return err
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return fmt.Errorf("cannot close tar writer; %w", err)
+	}
+	if err = enc.Close(); err != nil {
+		return fmt.Errorf("cannot close zstd writer; %w", err)
+	}
+	return nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("cannot write header of %v; %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("cannot write %v; %w", name, err)
+	}
+	return nil
+}
+
+// Read loads a bundle from a tar.zst file at given path.
+func Read(path string) (*Bundle, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open bundle %v; %w", path, err)
+	}
+	defer file.Close()
+
+	dec, err := zstd.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create zstd reader; %w", err)
+	}
+	defer dec.Close()
+
+	b := &Bundle{Files: make(map[string][]byte)}
+	hasManifest := false
+	tr := tar.NewReader(dec)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read bundle %v; %w", path, err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %v from bundle %v; %w", header.Name, path, err)
+		}
+		if header.Name == ManifestName {
+			if err = json.Unmarshal(data, &b.Manifest); err != nil {
+				return nil, fmt.Errorf("cannot decode manifest of bundle %v; %w", path, err)
+			}
+			hasManifest = true
+			continue
+		}
+		b.Files[header.Name] = data
+	}
+
+	if !hasManifest {
+		return nil, fmt.Errorf("bundle %v has no manifest", path)
+	}
+	if b.Manifest.Version != manifestVersion {
+		return nil, fmt.Errorf("unsupported version %v of bundle %v", b.Manifest.Version, path)
+	}
+	return b, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_WriteAndReadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	report := filepath.Join(dir, "profile.csv")
+	require.NoError(t, os.WriteFile(report, []byte("op,n\nGetState,5\n"), 0644))
+
+	b := NewBundle("aida-vm-sdb substate", 10, 20, map[string]string{"aida-db": "/db"})
+	require.NoError(t, b.AddJson(SummaryName, map[string]any{"transactions": 5}))
+	added, err := b.AddFile(report)
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = b.AddFile(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	path := filepath.Join(dir, "run.tar.zst")
+	require.NoError(t, b.Write(path))
+
+	got, err := Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, "aida-vm-sdb substate", got.Manifest.Tool)
+	assert.Equal(t, uint64(10), got.Manifest.First)
+	assert.Equal(t, uint64(20), got.Manifest.Last)
+	assert.Equal(t, map[string]string{"aida-db": "/db"}, got.Manifest.Inputs)
+	assert.Equal(t, []string{"profile.csv", SummaryName}, got.Manifest.Files)
+	assert.Equal(t, []byte("op,n\nGetState,5\n"), got.Files["profile.csv"])
+	assert.Contains(t, string(got.Files[SummaryName]), `"transactions": 5`)
+}
+
+func TestBundle_ReadFailsWithoutManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.tar.zst")
+	require.NoError(t, os.WriteFile(path, []byte("not a bundle"), 0644))
+	_, err := Read(path)
+	require.Error(t, err)
+}
@@ -0,0 +1,281 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package bundle
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MetricDiff is a difference of a single metric present in both compared bundles.
+type MetricDiff struct {
+	Name string
+	A, B float64
+}
+
+// Delta returns the absolute change of the metric.
+func (d MetricDiff) Delta() float64 {
+	return d.B - d.A
+}
+
+// RelativeDelta returns the relative change of the metric in percent.
+func (d MetricDiff) RelativeDelta() float64 {
+	if d.A == 0 {
+		if d.B == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return (d.B - d.A) / math.Abs(d.A) * 100
+}
+
+// Comparison is the result of comparing two bundles.
+type Comparison struct {
+	Metrics     []MetricDiff // metrics present in both bundles, sorted by name
+	OnlyInA     []string     // metrics present only in the first bundle
+	OnlyInB     []string     // metrics present only in the second bundle
+	ConfigDiffs []string     // configuration values differing between the runs
+}
+
+// CheckComparable returns an error if the bundles were not produced from the same block range and data inputs.
+func CheckComparable(a, b Manifest) error {
+	if a.First != b.First || a.Last != b.Last {
+		return fmt.Errorf("different block ranges: %v-%v vs %v-%v", a.First, a.Last, b.First, b.Last)
+	}
+	for _, key := range slices.Sorted(maps.Keys(a.Inputs)) {
+		if a.Inputs[key] != b.Inputs[key] {
+			return fmt.Errorf("different input %v: %q vs %q", key, a.Inputs[key], b.Inputs[key])
+		}
+	}
+	for key := range b.Inputs {
+		if _, found := a.Inputs[key]; !found {
+			return fmt.Errorf("different input %v: %q vs %q", key, "", b.Inputs[key])
+		}
+	}
+	return nil
+}
+
+// Compare compares all common metrics and the configuration of two bundles.
+func Compare(a, b *Bundle) (*Comparison, error) {
+	metricsA, err := Metrics(a)
+	if err != nil {
+		return nil, fmt.Errorf("cannot collect metrics of first bundle; %w", err)
+	}
+	metricsB, err := Metrics(b)
+	if err != nil {
+		return nil, fmt.Errorf("cannot collect metrics of second bundle; %w", err)
+	}
+
+	res := &Comparison{}
+	for _, name := range slices.Sorted(maps.Keys(metricsA)) {
+		valueB, found := metricsB[name]
+		if !found {
+			res.OnlyInA = append(res.OnlyInA, name)
+			continue
+		}
+		res.Metrics = append(res.Metrics, MetricDiff{Name: name, A: metricsA[name], B: valueB})
+	}
+	for _, name := range slices.Sorted(maps.Keys(metricsB)) {
+		if _, found := metricsA[name]; !found {
+			res.OnlyInB = append(res.OnlyInB, name)
+		}
+	}
+
+	res.ConfigDiffs, err = compareConfigs(a.Files[ConfigName], b.Files[ConfigName])
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Metrics collects all numeric metrics of a bundle. Metrics are taken from the
+// run summary and from all CSV files, in which the first column identifies a row.
+func Metrics(b *Bundle) (map[string]float64, error) {
+	res := make(map[string]float64)
+	if data, found := b.Files[SummaryName]; found {
+		values, err := flattenJson(data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode %v; %w", SummaryName, err)
+		}
+		for key, value := range values {
+			if f, ok := value.(float64); ok {
+				res["summary:"+key] = f
+			}
+		}
+	}
+	for name, data := range b.Files {
+		if filepath.Ext(name) != ".csv" {
+			continue
+		}
+		if err := csvMetrics(name, data, res); err != nil {
+			return nil, fmt.Errorf("cannot parse %v; %w", name, err)
+		}
+	}
+	return res, nil
+}
+
+// csvMetrics adds numeric cells of a CSV file as metrics named <file>:<row>:<column>.
+func csvMetrics(name string, data []byte, res map[string]float64) error {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]int)
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(row) == 0 {
+			continue
+		}
+		// repeated rows, e.g. of subsequent intervals, are numbered
+		key := row[0]
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key = fmt.Sprintf("%v#%d", key, n)
+		}
+		for i := 1; i < len(row) && i < len(header); i++ {
+			value, err := strconv.ParseFloat(strings.TrimSpace(row[i]), 64)
+			if err != nil {
+				continue
+			}
+			res[fmt.Sprintf("%v:%v:%v", name, key, header[i])] = value
+		}
+	}
+}
+
+// compareConfigs reports configuration values differing between two runs.
+func compareConfigs(a, b []byte) ([]string, error) {
+	if a == nil || b == nil {
+		return nil, nil
+	}
+	valuesA, err := flattenJson(a)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %v; %w", ConfigName, err)
+	}
+	valuesB, err := flattenJson(b)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %v; %w", ConfigName, err)
+	}
+
+	keys := make(map[string]struct{})
+	for key := range valuesA {
+		keys[key] = struct{}{}
+	}
+	for key := range valuesB {
+		keys[key] = struct{}{}
+	}
+
+	var res []string
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		valueA, valueB := fmt.Sprint(valuesA[key]), fmt.Sprint(valuesB[key])
+		if valueA != valueB {
+			res = append(res, fmt.Sprintf("%v: %v -> %v", key, valueA, valueB))
+		}
+	}
+	return res, nil
+}
+
+// flattenJson decodes a JSON object into a flat map with dot separated keys.
+func flattenJson(data []byte) (map[string]any, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	res := make(map[string]any)
+	flatten("", value, res)
+	return res, nil
+}
+
+func flatten(prefix string, value any, res map[string]any) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			flatten(join(key), inner, res)
+		}
+	case []any:
+		for i, inner := range v {
+			flatten(join(strconv.Itoa(i)), inner, res)
+		}
+	default:
+		res[prefix] = v
+	}
+}
+
+// WriteText writes a human-readable report of the comparison.
+func (c *Comparison) WriteText(w io.Writer) error {
+	var buf bytes.Buffer
+	if len(c.ConfigDiffs) > 0 {
+		fmt.Fprintf(&buf, "Configuration differences:\n")
+		for _, diff := range c.ConfigDiffs {
+			fmt.Fprintf(&buf, "  %v\n", diff)
+		}
+		fmt.Fprintln(&buf)
+	}
+	fmt.Fprintf(&buf, "%-60s %16s %16s %16s %10s\n", "metric", "a", "b", "delta", "delta(%)")
+	for _, m := range c.Metrics {
+		fmt.Fprintf(&buf, "%-60s %16.4f %16.4f %16.4f %10.2f\n", m.Name, m.A, m.B, m.Delta(), m.RelativeDelta())
+	}
+	if len(c.OnlyInA) > 0 || len(c.OnlyInB) > 0 {
+		fmt.Fprintf(&buf, "\n%d metrics only in a, %d metrics only in b\n", len(c.OnlyInA), len(c.OnlyInB))
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteCsv writes all common metrics of the comparison in CSV format.
+func (c *Comparison) WriteCsv(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"metric", "a", "b", "delta", "delta_percent"}); err != nil {
+		return err
+	}
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	for _, m := range c.Metrics {
+		if err := writer.Write([]string{m.Name, format(m.A), format(m.B), format(m.Delta()), format(m.RelativeDelta())}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package bundle
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff_CheckComparable(t *testing.T) {
+	base := Manifest{First: 1, Last: 10, Inputs: map[string]string{"aida-db": "/db"}}
+	tests := map[string]struct {
+		other    Manifest
+		expected string
+	}{
+		"same":            {Manifest{First: 1, Last: 10, Inputs: map[string]string{"aida-db": "/db"}}, ""},
+		"different range": {Manifest{First: 1, Last: 11, Inputs: map[string]string{"aida-db": "/db"}}, "different block ranges"},
+		"different input": {Manifest{First: 1, Last: 10, Inputs: map[string]string{"aida-db": "/other"}}, "different input aida-db"},
+		"extra input":     {Manifest{First: 1, Last: 10, Inputs: map[string]string{"aida-db": "/db", "x": "y"}}, "different input x"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckComparable(base, test.other)
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestDiff_CompareCollectsCommonMetrics(t *testing.T) {
+	a := NewBundle("tool", 1, 10, nil)
+	a.Add(SummaryName, []byte(`{"tx_rate": 100, "failed": false}`))
+	a.Add("profile.csv", []byte("op,first,n,mean(us)\nGetState,1,10,2.5\nSetState,1,4,1\n"))
+	a.Add(ConfigName, []byte(`{"CarmenNodeCacheSize": 1, "DbImpl": "carmen"}`))
+
+	b := NewBundle("tool", 1, 10, nil)
+	b.Add(SummaryName, []byte(`{"tx_rate": 150, "failed": false}`))
+	b.Add("profile.csv", []byte("op,first,n,mean(us)\nGetState,1,10,2\n"))
+	b.Add(ConfigName, []byte(`{"CarmenNodeCacheSize": 2, "DbImpl": "carmen"}`))
+
+	c, err := Compare(a, b)
+	require.NoError(t, err)
+
+	byName := make(map[string]MetricDiff)
+	for _, m := range c.Metrics {
+		byName[m.Name] = m
+	}
+	require.Contains(t, byName, "summary:tx_rate")
+	assert.Equal(t, 50.0, byName["summary:tx_rate"].Delta())
+	assert.Equal(t, 50.0, byName["summary:tx_rate"].RelativeDelta())
+	require.Contains(t, byName, "profile.csv:GetState:mean(us)")
+	assert.Equal(t, -0.5, byName["profile.csv:GetState:mean(us)"].Delta())
+	assert.Contains(t, c.OnlyInA, "profile.csv:SetState:n")
+	assert.Empty(t, c.OnlyInB)
+	assert.Equal(t, []string{"CarmenNodeCacheSize: 1 -> 2"}, c.ConfigDiffs)
+
+	var text, csv bytes.Buffer
+	require.NoError(t, c.WriteText(&text))
+	assert.Contains(t, text.String(), "CarmenNodeCacheSize: 1 -> 2")
+	assert.Contains(t, text.String(), "summary:tx_rate")
+	require.NoError(t, c.WriteCsv(&csv))
+	assert.Contains(t, csv.String(), "summary:tx_rate,100,150,50,50\n")
+}
+
+func TestDiff_RepeatedCsvRowsAreNumbered(t *testing.T) {
+	res := make(map[string]float64)
+	require.NoError(t, csvMetrics("f.csv", []byte("op,n\nGetState,1\nGetState,2\n"), res))
+	assert.Equal(t, map[string]float64{"f.csv:GetState:n": 1, "f.csv:GetState#2:n": 2}, res)
+}
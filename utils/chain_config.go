@@ -0,0 +1,85 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// LoadChainConfig reads a chain configuration from the given JSON file. The file
+// may either hold a go-ethereum genesis, in which case its "config" section is
+// used, or a bare chain configuration.
+func LoadChainConfig(path string) (*params.ChainConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read chain config %v; %w", path, err)
+	}
+
+	var genesis struct {
+		Config *params.ChainConfig `json:"config"`
+	}
+	if err = json.Unmarshal(data, &genesis); err != nil {
+		return nil, fmt.Errorf("cannot parse chain config %v; %w", path, err)
+	}
+	chainCfg := genesis.Config
+	if chainCfg == nil {
+		chainCfg = new(params.ChainConfig)
+		if err = json.Unmarshal(data, chainCfg); err != nil {
+			return nil, fmt.Errorf("cannot parse chain config %v; %w", path, err)
+		}
+	}
+
+	if err = validateChainConfig(chainCfg); err != nil {
+		return nil, fmt.Errorf("invalid chain config %v; %w", path, err)
+	}
+	chainCfg.DAOForkSupport = false
+	return chainCfg, nil
+}
+
+// validateChainConfig makes sure that all forks up to London are scheduled, since
+// the fork rules of replayed blocks cannot be derived otherwise, and that the
+// schedule is consistent.
+func validateChainConfig(chainCfg *params.ChainConfig) error {
+	if chainCfg.ChainID == nil || chainCfg.ChainID.Sign() <= 0 {
+		return fmt.Errorf("missing chainId")
+	}
+	for _, fork := range []struct {
+		name  string
+		block *big.Int
+	}{
+		{"homesteadBlock", chainCfg.HomesteadBlock},
+		{"eip150Block", chainCfg.EIP150Block},
+		{"eip155Block", chainCfg.EIP155Block},
+		{"eip158Block", chainCfg.EIP158Block},
+		{"byzantiumBlock", chainCfg.ByzantiumBlock},
+		{"constantinopleBlock", chainCfg.ConstantinopleBlock},
+		{"petersburgBlock", chainCfg.PetersburgBlock},
+		{"istanbulBlock", chainCfg.IstanbulBlock},
+		{"berlinBlock", chainCfg.BerlinBlock},
+		{"londonBlock", chainCfg.LondonBlock},
+	} {
+		if fork.block == nil {
+			return fmt.Errorf("missing %v", fork.name)
+		}
+	}
+	return chainCfg.CheckConfigForkOrder()
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// devnetGenesis is a genesis of a private devnet with a fork schedule differing
+// from all predefined chains.
+const devnetGenesis = `{
+	"config": {
+		"chainId": 4242,
+		"homesteadBlock": 0,
+		"eip150Block": 0,
+		"eip155Block": 0,
+		"eip158Block": 0,
+		"byzantiumBlock": 0,
+		"constantinopleBlock": 0,
+		"petersburgBlock": 0,
+		"istanbulBlock": 0,
+		"berlinBlock": 100,
+		"londonBlock": 200,
+		"mergeNetsplitBlock": 300,
+		"shanghaiTime": 1000,
+		"cancunTime": 2000,
+		"blobSchedule": {
+			"cancun": {"target": 3, "max": 6, "baseFeeUpdateFraction": 3338477}
+		}
+	},
+	"nonce": "0x0",
+	"gasLimit": "0x1c9c380",
+	"difficulty": "0x1",
+	"alloc": {}
+}`
+
+func writeChainConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadChainConfig_SelectsForksOfFile(t *testing.T) {
+	chainCfg, err := LoadChainConfig(writeChainConfig(t, devnetGenesis))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(4242), chainCfg.ChainID)
+
+	tests := map[string]struct {
+		block     uint64
+		timestamp uint64
+		want      string
+	}{
+		"istanbul":      {block: 99, timestamp: 0, want: "istanbul"},
+		"berlin":        {block: 100, timestamp: 0, want: "berlin"},
+		"london":        {block: 250, timestamp: 500, want: "london"},
+		"merge":         {block: 300, timestamp: 999, want: "merge"},
+		"shanghai":      {block: 400, timestamp: 1000, want: "shanghai"},
+		"cancun":        {block: 500, timestamp: 2500, want: "cancun"},
+		"time too late": {block: 150, timestamp: 5000, want: "berlin"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			block := new(big.Int).SetUint64(test.block)
+			isMerge := chainCfg.MergeNetsplitBlock.Cmp(block) <= 0
+			rules := chainCfg.Rules(block, isMerge, test.timestamp)
+
+			got := "istanbul"
+			switch {
+			case rules.IsCancun:
+				got = "cancun"
+			case rules.IsShanghai:
+				got = "shanghai"
+			case rules.IsMerge:
+				got = "merge"
+			case rules.IsLondon:
+				got = "london"
+			case rules.IsBerlin:
+				got = "berlin"
+			}
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestLoadChainConfig_AcceptsBareChainConfig(t *testing.T) {
+	chainCfg, err := LoadChainConfig(writeChainConfig(t, `{
+		"chainId": 7, "homesteadBlock": 0, "eip150Block": 0, "eip155Block": 0, "eip158Block": 0,
+		"byzantiumBlock": 0, "constantinopleBlock": 0, "petersburgBlock": 0, "istanbulBlock": 0,
+		"berlinBlock": 10, "londonBlock": 20, "daoForkSupport": true
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(7), chainCfg.ChainID)
+	require.Equal(t, big.NewInt(20), chainCfg.LondonBlock)
+	require.False(t, chainCfg.DAOForkSupport)
+}
+
+func TestLoadChainConfig_RejectsInvalidConfigs(t *testing.T) {
+	forks := `"homesteadBlock": 0, "eip150Block": 0, "eip155Block": 0, "eip158Block": 0, "byzantiumBlock": 0,
+		"constantinopleBlock": 0, "petersburgBlock": 0, "istanbulBlock": 0, "berlinBlock": 10`
+	tests := map[string]struct {
+		content string
+		want    string
+	}{
+		"invalid json":      {content: `{"config":`, want: "cannot parse chain config"},
+		"missing chain id":  {content: `{` + forks + `, "londonBlock": 20}`, want: "missing chainId"},
+		"missing fork":      {content: `{"chainId": 7, ` + forks + `}`, want: "missing londonBlock"},
+		"wrong fork order":  {content: `{"chainId": 7, ` + forks + `, "londonBlock": 5}`, want: "unsupported fork ordering"},
+		"missing blob conf": {content: `{"chainId": 7, ` + forks + `, "londonBlock": 20, "shanghaiTime": 0, "cancunTime": 0}`, want: "cancun"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadChainConfig(writeChainConfig(t, test.content))
+			require.ErrorContains(t, err, test.want)
+		})
+	}
+}
+
+func TestLoadChainConfig_MissingFile(t *testing.T) {
+	_, err := LoadChainConfig(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorContains(t, err, "cannot read chain config")
+}
+
+func TestConfigContext_SetChainConfig_UsesChainConfigFile(t *testing.T) {
+	cfg := &Config{ChainID: SonicMainnetChainID, ChainConfigFile: writeChainConfig(t, devnetGenesis)}
+	cc := NewConfigContext(cfg, nil)
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cc.log = log
+
+	log.EXPECT().Warningf(gomock.Any(), big.NewInt(4242), cfg.ChainConfigFile, SonicMainnetChainID, ChainIDFlag.Name)
+	log.EXPECT().Noticef(gomock.Any(), cfg.ChainConfigFile)
+	require.NoError(t, cc.setChainConfig())
+	require.Equal(t, big.NewInt(4242), cfg.ChainCfg.ChainID)
+
+	// the fork schedule of the file applies independently of the requested fork
+	for _, fork := range []string{"", "Cancun", "Istanbul"} {
+		chainCfg, err := cfg.GetChainConfig(fork)
+		require.NoError(t, err)
+		require.Same(t, cfg.ChainCfg, chainCfg)
+	}
+}
+
+func TestConfigContext_SetChainConfig_MatchingChainIdDoesNotWarn(t *testing.T) {
+	cfg := &Config{ChainID: 4242, ChainConfigFile: writeChainConfig(t, devnetGenesis)}
+	cc := NewConfigContext(cfg, nil)
+	ctrl := gomock.NewController(t)
+	log := logger.NewMockLogger(ctrl)
+	cc.log = log
+
+	log.EXPECT().Noticef(gomock.Any(), cfg.ChainConfigFile)
+	require.NoError(t, cc.setChainConfig())
+}
+
+func TestConfigContext_SetChainConfig_RejectsChainConfigFileForEthTests(t *testing.T) {
+	cfg := &Config{ChainID: EthTestsChainID, ChainConfigFile: writeChainConfig(t, devnetGenesis)}
+	err := NewConfigContext(cfg, nil).setChainConfig()
+	require.ErrorContains(t, err, "cannot be used with ethereum tests")
+}
+
+func TestConfigContext_SetChainConfig_InvalidChainConfigFile(t *testing.T) {
+	cfg := &Config{ChainID: SonicMainnetChainID, ChainConfigFile: writeChainConfig(t, `{"chainId": 1}`)}
+	err := NewConfigContext(cfg, nil).setChainConfig()
+	require.ErrorContains(t, err, "missing homesteadBlock")
+}
+
+func TestConfig_GetChainConfig_LoadsChainConfigFile(t *testing.T) {
+	cfg := &Config{ChainID: SonicMainnetChainID, ChainConfigFile: writeChainConfig(t, devnetGenesis)}
+	chainCfg, err := cfg.GetChainConfig("")
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(300), chainCfg.MergeNetsplitBlock)
+}
@@ -0,0 +1,301 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/state/proxy"
+	"github.com/0xsoniclabs/aida/tracer/operation"
+	"github.com/0xsoniclabs/aida/utils/analytics"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PathToSchemaComparison is the name of the --compare-schemas report placed into a run directory.
+const PathToSchemaComparison = "schema_comparison.json"
+
+// componentDepth is the number of path elements identifying a component within a Carmen directory.
+const componentDepth = 2
+
+// SchemaComparison holds the two Carmen DBs of a --compare-schemas run. Each DB is wrapped
+// in its own timing proxy, hence the time spent in the prime and shadow DB can be told apart.
+type SchemaComparison struct {
+	PrimeSchema  int
+	ShadowSchema int
+	PrimeDir     string
+	ShadowDir    string
+
+	prime       state.StateDB
+	primeTime   *analytics.IncrementalAnalytics
+	shadowTime  *analytics.IncrementalAnalytics
+	divergences []string
+}
+
+// SchemaReport describes the differences of a single schema observed by the comparison.
+type SchemaReport struct {
+	Schema     int              `json:"schema"`
+	Directory  string           `json:"directory"`
+	Size       int64            `json:"size"`       // total size of the DB directory in bytes
+	Components map[string]int64 `json:"components"` // component path -> size in bytes
+	Time       time.Duration    `json:"time"`       // total time spent in StateDb operations
+}
+
+// SchemaComparisonReport is the outcome of a --compare-schemas run.
+type SchemaComparisonReport struct {
+	Prime       SchemaReport                `json:"prime"`
+	Shadow      SchemaReport                `json:"shadow"`
+	Operations  map[string][2]time.Duration `json:"operations"` // operation -> time spent in prime and shadow DB
+	Divergences []string                    `json:"divergences"`
+}
+
+// setSchemaComparison configures the prime and shadow DB for --compare-schemas.
+// Both DBs are Carmen DBs of the same variant, which differ only in their schema.
+func (cc *configContext) setSchemaComparison() error {
+	cfg := cc.cfg
+	if cfg.CompareSchemas == "" {
+		return nil
+	}
+
+	prime, shadow, err := ParseSchemaPair(cfg.CompareSchemas)
+	if err != nil {
+		return fmt.Errorf("invalid --%v; %w", CompareSchemasFlag.Name, err)
+	}
+	if cfg.StateDbSrc != "" {
+		return fmt.Errorf("--%v cannot be used with an existing StateDb", CompareSchemasFlag.Name)
+	}
+
+	cfg.DbImpl = "carmen"
+	cfg.ShadowImpl = "carmen"
+	if cfg.DbVariant == "" {
+		cfg.DbVariant = "go-file"
+	}
+	cfg.ShadowVariant = cfg.DbVariant
+	cfg.ShadowDb = true
+	cfg.CarmenSchema = prime
+	cfg.ValidateStateHashes = true
+
+	cc.log.Warningf("Comparing Carmen schemas %d and %d; StateDb disk and memory requirements are doubled", prime, shadow)
+	return nil
+}
+
+// ParseSchemaPair parses a comma separated pair of Carmen schemas.
+func ParseSchemaPair(value string) (int, int, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expected two comma separated schemas, got %q", value)
+	}
+	var schemas [2]int
+	for i, part := range parts {
+		schema, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || schema <= 0 {
+			return 0, 0, fmt.Errorf("invalid schema %q", part)
+		}
+		schemas[i] = schema
+	}
+	if schemas[0] == schemas[1] {
+		return 0, 0, fmt.Errorf("schemas must differ, got %d twice", schemas[0])
+	}
+	return schemas[0], schemas[1], nil
+}
+
+// PrepareSchemaComparison creates the prime and shadow Carmen DB in separate directories
+// of a new temporary directory and combines them by a shadow proxy comparing state hashes.
+func PrepareSchemaComparison(cfg *Config) (state.StateDB, string, *SchemaComparison, error) {
+	primeSchema, shadowSchema, err := ParseSchemaPair(cfg.CompareSchemas)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp(cfg.DbTmp, "state_db_tmp_*")
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to create a temporary directory; %v", err)
+	}
+
+	c := &SchemaComparison{
+		PrimeSchema:  primeSchema,
+		ShadowSchema: shadowSchema,
+		PrimeDir:     filepath.Join(tmpDir, PathToPrimaryStateDb),
+		ShadowDir:    filepath.Join(tmpDir, PathToShadowStateDb),
+		primeTime:    analytics.NewIncrementalAnalytics(int(operation.NumOperations)),
+		shadowTime:   analytics.NewIncrementalAnalytics(int(operation.NumOperations)),
+	}
+
+	primeDb, err := makeStateDBVariant(c.PrimeDir, "carmen", cfg.DbVariant, cfg.ArchiveVariant, primeSchema, common.Hash{}, cfg)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("cannot make stateDb with schema %d; %v", primeSchema, err)
+	}
+	shadowDb, err := makeStateDBVariant(c.ShadowDir, "carmen", cfg.DbVariant, cfg.ArchiveVariant, shadowSchema, common.Hash{}, cfg)
+	if err != nil {
+		return nil, "", nil, errors.Join(
+			fmt.Errorf("cannot make shadowDb with schema %d; %v", shadowSchema, err),
+			primeDb.Close(),
+		)
+	}
+
+	c.prime = proxy.NewProfilerProxy(primeDb, c.primeTime, cfg.LogLevel)
+	shadow := proxy.NewProfilerProxy(shadowDb, c.shadowTime, cfg.LogLevel)
+	return proxy.NewShadowProxy(c.prime, shadow, true), tmpDir, c, nil
+}
+
+// RecordDivergence adds a divergence of the compared DBs to the report. Nil errors are ignored.
+func (c *SchemaComparison) RecordDivergence(err error) {
+	if err != nil {
+		c.divergences = append(c.divergences, err.Error())
+	}
+}
+
+// GetPrimeHash returns the state hash of the prime DB.
+func (c *SchemaComparison) GetPrimeHash() (common.Hash, error) {
+	return c.prime.GetHash()
+}
+
+// Report measures the DB directories and summarizes the time spent in both DBs.
+// The DBs must be closed to measure their final size.
+func (c *SchemaComparison) Report() (SchemaComparisonReport, error) {
+	report := SchemaComparisonReport{
+		Prime:       SchemaReport{Schema: c.PrimeSchema, Directory: c.PrimeDir},
+		Shadow:      SchemaReport{Schema: c.ShadowSchema, Directory: c.ShadowDir},
+		Operations:  make(map[string][2]time.Duration),
+		Divergences: c.divergences,
+	}
+
+	var err error
+	for _, r := range []*SchemaReport{&report.Prime, &report.Shadow} {
+		r.Components, err = getComponentSizes(r.Directory, componentDepth)
+		if err != nil {
+			return report, fmt.Errorf("cannot measure schema %d; %w", r.Schema, err)
+		}
+		for _, size := range r.Components {
+			r.Size += size
+		}
+	}
+
+	for id := byte(0); id < operation.NumOperations; id++ {
+		if c.primeTime.GetCount(id) == 0 && c.shadowTime.GetCount(id) == 0 {
+			continue
+		}
+		prime := time.Duration(c.primeTime.GetSum(id))
+		shadow := time.Duration(c.shadowTime.GetSum(id))
+		report.Operations[operation.GetLabel(id)] = [2]time.Duration{prime, shadow}
+		report.Prime.Time += prime
+		report.Shadow.Time += shadow
+	}
+	return report, nil
+}
+
+// Log writes the report into the given log.
+func (r SchemaComparisonReport) Log(log logger.Logger) {
+	log.Noticef("Schema comparison: schema %d vs schema %d", r.Prime.Schema, r.Shadow.Schema)
+	log.Noticef("  DB size: %.2f MB vs %.2f MB", toMB(r.Prime.Size), toMB(r.Shadow.Size))
+
+	components := make(map[string]struct{})
+	for component := range r.Prime.Components {
+		components[component] = struct{}{}
+	}
+	for component := range r.Shadow.Components {
+		components[component] = struct{}{}
+	}
+	for _, component := range sortedKeys(components) {
+		log.Infof("    %v: %.2f MB vs %.2f MB", component, toMB(r.Prime.Components[component]), toMB(r.Shadow.Components[component]))
+	}
+
+	log.Noticef("  StateDb time: %v vs %v", r.Prime.Time.Round(time.Millisecond), r.Shadow.Time.Round(time.Millisecond))
+	for _, op := range sortedKeys(r.Operations) {
+		log.Infof("    %v: %v vs %v", op, r.Operations[op][0].Round(time.Microsecond), r.Operations[op][1].Round(time.Microsecond))
+	}
+
+	if len(r.Divergences) == 0 {
+		log.Noticef("  No divergences found")
+		return
+	}
+	log.Errorf("  %d divergences found", len(r.Divergences))
+	for _, divergence := range r.Divergences {
+		log.Errorf("    %v", divergence)
+	}
+}
+
+// WriteSchemaComparisonReport writes the report into given json file.
+func WriteSchemaComparisonReport(path string, report SchemaComparisonReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal schema comparison report; %w", err)
+	}
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("cannot write schema comparison report; %w", err)
+	}
+	return nil
+}
+
+// getComponentSizes sums up sizes of files within root by their component, which is
+// identified by the first depth elements of their relative path.
+func getComponentSizes(root string, depth int) (map[string]int64, error) {
+	sizes := make(map[string]int64)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// see GetDirectorySize
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		elements := strings.Split(filepath.ToSlash(rel), "/")
+		if len(elements) > depth {
+			elements = elements[:depth]
+		}
+		sizes[strings.Join(elements, "/")] += info.Size()
+		return nil
+	})
+	return sizes, err
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toMB(size int64) float64 {
+	return float64(size) / float64(1_000_000)
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/aida/tracer/operation"
+	"github.com/0xsoniclabs/aida/utils/analytics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareSchemas_ParseSchemaPair(t *testing.T) {
+	tests := map[string]struct {
+		value          string
+		prime, shadow  int
+		expectedErrMsg string
+	}{
+		"valid":       {value: "4,5", prime: 4, shadow: 5},
+		"spaces":      {value: " 5 , 3", prime: 5, shadow: 3},
+		"single":      {value: "5", expectedErrMsg: "expected two comma separated schemas"},
+		"triple":      {value: "3,4,5", expectedErrMsg: "expected two comma separated schemas"},
+		"notANumber":  {value: "4,x", expectedErrMsg: "invalid schema"},
+		"notPositive": {value: "0,5", expectedErrMsg: "invalid schema"},
+		"same":        {value: "5,5", expectedErrMsg: "schemas must differ"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			prime, shadow, err := ParseSchemaPair(test.value)
+			if test.expectedErrMsg != "" {
+				require.ErrorContains(t, err, test.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.prime, prime)
+			assert.Equal(t, test.shadow, shadow)
+		})
+	}
+}
+
+func TestCompareSchemas_ConfiguresCarmenShadowDb(t *testing.T) {
+	cfg := &Config{CompareSchemas: "4,5", DbImpl: "geth", CarmenSchema: 5, LogLevel: "critical"}
+	cc := NewConfigContext(cfg, nil)
+	require.NoError(t, cc.setSchemaComparison())
+
+	assert.Equal(t, "carmen", cfg.DbImpl)
+	assert.Equal(t, "carmen", cfg.ShadowImpl)
+	assert.Equal(t, "go-file", cfg.DbVariant)
+	assert.Equal(t, cfg.DbVariant, cfg.ShadowVariant)
+	assert.True(t, cfg.ShadowDb)
+	assert.True(t, cfg.ValidateStateHashes)
+	assert.Equal(t, 4, cfg.CarmenSchema)
+}
+
+func TestCompareSchemas_RejectsExistingStateDb(t *testing.T) {
+	cfg := &Config{CompareSchemas: "4,5", StateDbSrc: t.TempDir(), LogLevel: "critical"}
+	cc := NewConfigContext(cfg, nil)
+	require.ErrorContains(t, cc.setSchemaComparison(), "cannot be used with an existing StateDb")
+}
+
+func TestCompareSchemas_DisabledWithoutFlag(t *testing.T) {
+	cfg := &Config{DbImpl: "geth", LogLevel: "critical"}
+	cc := NewConfigContext(cfg, nil)
+	require.NoError(t, cc.setSchemaComparison())
+
+	assert.Equal(t, "geth", cfg.DbImpl)
+	assert.False(t, cfg.ShadowDb)
+}
+
+func TestCompareSchemas_ReportsSizesTimesAndDivergences(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile := func(path string, size int) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+	}
+	c := &SchemaComparison{
+		PrimeSchema:  4,
+		ShadowSchema: 5,
+		PrimeDir:     filepath.Join(tmpDir, "prime"),
+		ShadowDir:    filepath.Join(tmpDir, "shadow"),
+		primeTime:    analytics.NewIncrementalAnalytics(int(operation.NumOperations)),
+		shadowTime:   analytics.NewIncrementalAnalytics(int(operation.NumOperations)),
+	}
+	writeFile(filepath.Join(c.PrimeDir, "live", "accounts", "data"), 10)
+	writeFile(filepath.Join(c.PrimeDir, "live", "accounts", "hashes"), 5)
+	writeFile(filepath.Join(c.PrimeDir, "live", "values"), 7)
+	writeFile(filepath.Join(c.ShadowDir, "live", "nodes", "deep", "data"), 20)
+	writeFile(filepath.Join(c.ShadowDir, "statedb_info.json"), 1)
+
+	c.primeTime.Update(operation.GetBalanceID, float64(2*time.Millisecond))
+	c.shadowTime.Update(operation.GetBalanceID, float64(3*time.Millisecond))
+	c.RecordDivergence(nil)
+	c.RecordDivergence(os.ErrInvalid)
+
+	report, err := c.Report()
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(22), report.Prime.Size)
+	assert.Equal(t, map[string]int64{"live/accounts": 15, "live/values": 7}, report.Prime.Components)
+	assert.Equal(t, int64(21), report.Shadow.Size)
+	assert.Equal(t, map[string]int64{"live/nodes": 20, "statedb_info.json": 1}, report.Shadow.Components)
+
+	assert.Equal(t, 2*time.Millisecond, report.Prime.Time)
+	assert.Equal(t, 3*time.Millisecond, report.Shadow.Time)
+	assert.Equal(t, map[string][2]time.Duration{
+		operation.GetLabel(operation.GetBalanceID): {2 * time.Millisecond, 3 * time.Millisecond},
+	}, report.Operations)
+	assert.Equal(t, []string{os.ErrInvalid.Error()}, report.Divergences)
+
+	path := filepath.Join(tmpDir, PathToSchemaComparison)
+	require.NoError(t, WriteSchemaComparisonReport(path, report))
+	assert.FileExists(t, path)
+}
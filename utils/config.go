@@ -19,14 +19,17 @@ package utils
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/sonic/opera"
@@ -45,9 +48,46 @@ import (
 )
 
 func init() {
+	// interpreters registered by the following call are experimental
+	stable := maps.Clone(tosca.GetAllRegisteredInterpreters())
 	if err := lfvm.RegisterExperimentalInterpreterConfigurations(); err != nil {
 		panic(fmt.Sprintf("failed to register experimental LFVM interpreter configurations: %v", err))
 	}
+
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+	must(RegisterVmImplementation(VmImplementationInfo{
+		Kind:        InterpreterImplementation,
+		Name:        "geth",
+		Description: "interpreter of go-ethereum, used if no Tosca interpreter is selected",
+	}))
+	for _, info := range []VmImplementationInfo{
+		{Name: "lfvm", Description: "Tosca's long-form VM, the interpreter used by Sonic"},
+		{Name: "evmzero", Description: "Tosca's C++ interpreter"},
+		{Name: "evmone", Description: "evmone C++ interpreter integrated through Tosca"},
+	} {
+		info.Kind = InterpreterImplementation
+		info.FirstFork, info.LastFork = "Istanbul", "Osaka"
+		info.Tosca = true
+		must(RegisterVmImplementation(info))
+	}
+	for name := range tosca.GetAllRegisteredInterpreters() {
+		if _, found := stable[name]; found {
+			continue
+		}
+		must(RegisterVmImplementation(VmImplementationInfo{
+			Kind:         InterpreterImplementation,
+			Name:         name,
+			Description:  "experimental LFVM configuration",
+			FirstFork:    "Istanbul",
+			LastFork:     "Osaka",
+			Experimental: true,
+			Tosca:        true,
+		}))
+	}
 }
 
 type ArgumentMode int
@@ -138,6 +178,12 @@ const (
 	EqualityCheck                       // confirms whether a substate and StateDB are identical.
 )
 
+// Comparison of world-states performed by the transaction validation.
+const (
+	StrictTxStateValidation  = "strict"  // all accounts must match exactly
+	RelaxedTxStateValidation = "relaxed" // the balance of the fee recipient is compared by its delta only
+)
+
 // A map of key blocks on Fantom chain
 var KeywordBlocks = map[ChainID]map[string]uint64{
 	SonicMainnetChainID: {
@@ -282,89 +328,161 @@ type Config struct {
 	Last  uint64 // last block
 
 	// global configs
+	AccessListStats          string                    // path to csv file collecting access-list effectiveness of each transaction
 	AidaDb                   string                    // directory to profiling database containing substate, update, delete accounts data
+	ArchiveCacheSize         int                       // the number of archive states kept open for repeated queries
+	ArchiveFirstBlock        uint64                    // the first block retained in the archive of a shrunk StateDb
 	ArchiveMaxQueryAge       int                       // the maximum age for archive queries (in blocks)
 	ArchiveMode              bool                      // enable archive mode
+	ArchiveQueryBurst        int                       // the maximum number of archive queries sent at once after idle periods (0 = unbounded)
 	ArchiveQueryRate         int                       // the queries per second send to the archive
+	ArchiveQueryWarmUp       time.Duration             // the period over which the archive query rate ramps up to its target
 	ArchiveVariant           string                    // selects the implementation variant of the archive
 	ArgPath                  string                    // path to file or directory given as argument
 	BalanceRange             int64                     // balance range for stochastic simulation/replay
 	BasicBlockProfiling      bool                      // enable profiling of basic block
+	Bisect                   bool                      // locate the first divergent transaction once a run fails
+	BisectA                  string                    // flags of the first configuration compared by bisection
+	BisectB                  string                    // flags of the second configuration compared by bisection
+	BisectReport             string                    // output file of the first divergent block differences
 	BlockLength              uint64                    // length of a block in number of transactions
 	CPUProfile               string                    // pprof cpu profile output file name
 	CPUProfilePerInterval    bool                      // a different CPU profile is taken per 100k block interval
 	Cache                    int                       // Cache for StateDb or Priming
 	CarmenCheckpointInterval int                       // how often (in blocks) will Carmen create checkpoints
 	CarmenCheckpointPeriod   int                       // how often (in minutes) will Carmen create checkpoints
+	CarmenCheckpointTrigger  string                    // path of a file triggering a Carmen checkpoint when created
 	CarmenNodeCacheSize      int                       // the size of the in-memory cache to be used by a Carmen LiveDB in byte (0 for default value)
 	CarmenSchema             int                       // the current DB schema ID to use in Carmen
 	CarmenStateCacheSize     int                       // the number of values cached in the Carmen StateDB (0 for default value)
+	ChainConfigFile          string                    // JSON file with a custom chain config replacing the predefined one
 	ChainID                  ChainID                   // Blockchain ID (mainnet: 250/testnet: 4002)
 	ChannelBufferSize        int                       // set a buffer size for profiling channel
+	CoinbaseTolerance        string                    // tolerated difference of the fee recipient's balance delta in wei
+	CloneWorkers             int                       // number of workers copying block ranges in parallel when cloning aida-db
 	CompactDb                bool                      // compact database after merging
+	CompareSchemas           string                    // pair of Carmen schemas run side by side as prime and shadow DB
+	CompareSchemasReport     string                    // path to json file with the report of compared schemas
 	ContinueOnFailure        bool                      // continue validation when an error detected
 	ContractNumber           int64                     // number of contracts to create
+	CrossCheckDir            string                    // directory receiving the summaries of transactions diverging in cross-check mode
+	CrossCheckEvm            string                    // EVM implementation (<evm-impl>[:<vm-impl>]) cross-checked against EvmImpl
 	CustomDbName             string                    // name of state-db directory
 	DbComponent              string                    // options for util-db info are 'all', 'substate', 'delete', 'update', 'state-hash', 'exception'
 	DbImpl                   string                    // storage implementation
 	DbLogging                string                    // set to true if all DB operations should be logged
+	DbLoggingFilter          string                    // comma-separated list of operations logged by the db-logging, all if empty
+	DbLoggingFormat          string                    // format of the db-logging output (text/json)
 	DeltaLogging             string                    // path to delta-debugger formatted DB log file
+	DeltaLoggingResults      bool                      // record values returned by read operations into the delta-log
 	DbTmp                    string                    // path to temporary database
 	DbVariant                string                    // database variant
 	Debug                    bool                      // enable trace debug flag
 	DebugFrom                uint64                    // the first block to print trace debug
+	DeepOutputCompare        bool                      // compare post-alloc against recorded output alloc with slot-level granularity
 	DeleteSourceDbs          bool                      // delete source databases
 	DeletionDb               string                    // directory of deleted account database
+	DryRun                   bool                      // only plan the action without writing any data
 	DiagnosticServer         int64                     // if not zero, the port used for hosting a HTTP server for performance diagnostics
 	ErrorLogging             string                    // if defined, error logging to file is enabled
+	ErrorLoggingFormat       string                    // format of the error-log-file; selected by its extension if empty
 	EthTestType              EthTestType               // which geth test are we running
 	EvmImpl                  string                    // processor implementation
+	ExcludePrecompileCalls   string                    // handling of transactions sent to precompiled contracts by the contract gas profiler
+	ExportGenesis            string                    // path to genesis json file exported from the final state
+	ForceChainID             bool                      // proceed even if the chain id differs from the one of AidaDb
 	Fork                     string                    // Which forks are going to get executed byz
+	Forks                    []string                  // list of forks executed by the eth-tests; Fork is used if empty
 	Genesis                  string                    // genesis file
+	HeartbeatFields          string                    // comma-separated list of fields appended to heartbeat logs, none if empty
 	IncludeStorage           bool                      // represents a flag for contract storage inclusion in an operation
 	IsExistingStateDb        bool                      // this is true if we are using an existing StateDb
 	KeepDb                   bool                      // set to true if db is kept after run
 	KeysNumber               int64                     // number of keys to generate
+	KillCount                int                       // number of times the run is killed by the kill and resume test
+	KillReport               string                    // path to the JSON report of the kill and resume test
+	KillResumeArgs           string                    // flags of the run killed and resumed by the kill and resume test
+	LazySubstateDecoding     bool                      // substates are decoded by the workers right before execution
 	LogLevel                 string                    // level of the logging of the app action
+	LogLevelOverride         string                    // per-component log levels overriding LogLevel
+	LogOverflow              string                    // behavior of the asynchronous log writers once their queue is full
+	LogQueueSize             int                       // number of records buffered for the asynchronous log writers
 	MaxNumErrors             int                       // maximum number of errors when ContinueOnFailure is enabled
-	MaxNumTransactions       int                       // the maximum number of processed transactions
+	MaxNumTransactions       int                       // the maximum number of executed transactions, 0 = unlimited
 	MemoryBreakdown          bool                      // enable printing of memory breakdown
 	MemoryProfile            string                    // capture the memory heap profile into the file
 	MicroProfiling           bool                      // enable micro-profiling of EVM
+	MinFreeDiskGib           float64                   // minimum free space of the temporary directory in GiB; the run is aborted below it
 	NoHeartbeatLogging       bool                      // disables heartbeat logging
 	NonceRange               int                       // nonce range for stochastic simulation/replay
 	OnlySuccessful           bool                      // only runs transactions that have been successful
 	OperaBinary              string                    // path to opera binary
+	OtlpBlockSampling        int                       // every n-th block is traced as a span (0 disables block spans)
+	OtlpEndpoint             string                    // OTLP/HTTP endpoint receiving traces of the run; tracing is disabled if empty
 	ClientDb                 string                    // path to client database
 	Output                   string                    // output directory for aida-db patches or path to events.json file in stochastic generation
+	OutputDir                string                    // parent directory of all artifacts of the run which were not set explicitly
 	OverwriteRunId           string                    // when registering runs, use provided id instead of the autogenerated run id
 	PathToStateDb            string                    // Path to a working state-db directory
+	PauseOnFailure           bool                      // open an inspection console on the StateDb if the run fails
+	PipelineDepth            int                       // number of blocks prefetched ahead of the execution; 0 disables pipelining
 	PrimeRandom              bool                      // enable randomized priming
+	PrimeShuffleWindow       int                       // maximum number of accounts shuffled together in randomized priming; 0 shuffles all
 	PrimeThreshold           int                       // set account threshold before commit
 	Profile                  bool                      // enable micro profiling
 	ProfileBlocks            bool                      // enables block profiler extension
+	ProfileContracts         bool                      // enables attribution of gas used to recipient contracts
+	ProfileContractsTop      int                       // number of contracts reported by the contract gas profiler
 	ProfileDB                string                    // profile db for parallel transaction execution
 	ProfileDepth             int                       // 0 = Interval, 1 = Interval+Block, 2 = Interval+Block+Tx
 	ProfileEVMCall           bool                      // enable profiling for EVM call
 	ProfileFile              string                    // output file containing profiling result
 	ProfileInterval          uint64                    // interval of printing profile result
 	ProfileSqlite3           string                    // output profiling results to sqlite3 DB
+	ProfileTxTiming          bool                      // enables recording of the execution time of each transaction
 	ProfilingDbName          string                    // set a database name for storing micro-profiling results
+	Provider                 string                    // name of the registered provider supplying the payload
+	QueryWorkloadFile        string                    // file of recorded archive queries replayed during the run; disabled if empty
+	QueryWorkloadSpeedup     float64                   // factor by which the schedule of the recorded archive queries is compressed
 	RandomSeed               int64                     // set random seed for stochastic testing
 	EnableCoverage           bool                      // enable coverage-guided fuzzing
 	CoverageSnapshotInterval int                       // number of operations between coverage snapshots
+	RegisterExportCsv        string                    // directory to which the registered run is additionally exported as csv files
+	RegisterGasBuckets       string                    // upper bounds of the registered gas-per-block histogram buckets; defaults if empty
 	RegisterRun              string                    // register run to the provided connection string
-	RpcRecordingPath         string                    // path to source file (or dir with files) with recorded RPC requests
+	RegisterTxBuckets        string                    // upper bounds of the registered transactions-per-block histogram buckets; defaults if empty
+	RemapKey                 string                    // key of the permutation remapping all addresses during replay; disabled if empty
+	RemapStorageKeys         bool                      // remap storage keys as well as addresses
+	ReplayWorkers            int                       // number of workers of the stochastic replay
+	ReportFile               string                    // if defined, the results of the eth-tests are written to this JSON file
+	Resume                   bool                      // continue an interrupted run on the existing StateDb
+	RunBundle                string                    // path to the bundle collecting all artifacts of the run
+	RunId                    string                    // id of the run scoping its artifacts under OutputDir
+	RunManifest              *RunManifest              // fingerprints of the inputs of the run, computed at its start
+	RpcCompareLatency        bool                      // compare recorded upstream latency to local execution time of requests
+	RpcFuzz                  bool                      // execute mutated recorded requests instead of comparing results
+	RpcFuzzVariants          int                       // number of mutated variants per recorded request in fuzz mode
+	RpcLatencyReport         string                    // if defined, the latency comparison is written to this JSON file
+	RpcRecordingPath         string                    // path to source file (or dir with files, or websocket URL) with recorded RPC requests
+	ScanCachePolicy          string                    // page cache policy used when scanning source db sequentially
 	ShadowDb                 bool                      // defines we want to open an existing db as shadow
 	ShadowImpl               string                    // implementation of the shadow DB to use, empty if disabled
+	ShadowRetry              int                       // number of block re-executions attempted after a shadow DB divergence
 	ShadowVariant            string                    // database variant of the shadow DB to be used
+	SkipFailedTx             bool                      // skip transactions failing the transaction validation and report them at the end
+	SkipList                 string                    // file listing non-replayable transactions which are not executed
 	SkipMetadata             bool                      // skip metadata insert/getting into AidaDb
 	SkipPriming              bool                      // skip priming of the state DB
+	SkipSanityChecks         bool                      // skip checks of sender nonces and balances of replayed transactions
 	SkipStateHashScrapping   bool                      // if enabled, then state-hashes are not loaded from rpc
 	SnapshotDepth            int                       // depth of snapshot history
+	StallAction              string                    // what to do once a stalled run is detected (log/abort)
+	StallTimeout             time.Duration             // duration without a completed transaction considered a stall (0 disables detection)
 	StateDbSrc               string                    // directory to load an existing State DB data
 	StateDbSrcDirectAccess   bool                      // if true, read and write directly from the source database
 	StateDbSrcReadOnly       bool                      // if true, source database is not modified
+	StateHashInterval        int                       // only the state hashes of blocks divisible by the interval are validated
+	StateHashSkipFirst       int                       // number of blocks after priming whose state hashes are not validated
 	StateValidationMode      ValidationMode            // state validation mode
 	SubstateDb               string                    // substate directory
 	SubstateEncoding         db.SubstateEncodingSchema // rlp (default) or protobuf - when reading from disk
@@ -374,20 +492,34 @@ type Config struct {
 	Trace                    bool                      // trace flag
 	TraceDirectory           string                    // name of trace directory
 	TraceFile                string                    // name of trace file
+	TrackIo                  bool                      // enables i/o statistics in track progress logging
 	TrackProgress            bool                      // enables track progress logging
+	TrackerEtaWindow         int                       // number of recent progress reports used to estimate the remaining time
 	TrackerGranularity       int                       // defines how often will tracker report achieved block
 	TransactionLength        uint64                    // determines indirectly the length of a transaction
+	TxFilter                 string                    // comma separated list of transaction hashes and <block>:<tx> pairs selecting the executed transactions
+	TxGeneratorAccounts      int                       // number of accounts sending transactions of the erc20 and create generators
+	TxGeneratorCreateRate    float64                   // fraction of transactions of the create generator deploying a new contract
 	TxGeneratorType          []string                  // type of the application used for transaction generation
+	TxStateValidation        string                    // world-state comparison of transaction validation (strict/relaxed)
 	UpdateBufferSize         uint64                    // cache size in Bytes
+	UpdateBufferStrict       bool                      // flush the update buffer eagerly to stay within its size
+	UpdateCacheDir           string                    // directory caching update sets fetched from a remote update-set database
+	UpdateCacheSize          int                       // maximum size of the cached update sets in MB
 	UpdateDb                 string                    // update-set directory
 	OverwritePreWorldState   bool                      // instead of validation of StateDb we overwrite it with the provided data
 	UpdateType               string                    // download datatype
 	Validate                 bool                      // validate validate aida-db
+	ValidateAccounting       bool                      // validate net balance change of each block against burned fees
+	ValidateDeletions        bool                      // validate self-destructed accounts of each block against the deletion db
 	ValidateStateHashes      bool                      // if this is true state hash validation is enabled in Executor
 	ValidateTxState          bool                      // validate stateDB before and after transaction
+	ValidateWitness          bool                      // validate that the state witness covers the input alloc of each transaction
 	ValuesNumber             int64                     // number of values to generate
 	VmImpl                   string                    // vm implementation (geth/lfvm)
+	VmTrace                  bool                      // write structured logs of transactions diverging in cross-check mode
 	Workers                  int                       // number of worker threads
+	WorkerPartitioning       string                    // distribution of blocks among workers (interleaved/contiguous)
 
 	// -- cached results --
 	ChainCfg           *params.ChainConfig   // cached chain configuration
@@ -400,6 +532,7 @@ type configContext struct {
 	log         logger.Logger // logger for printing logs in config functions
 	hasMetadata bool          // if true, Aida-db has a valid metadata table
 	ctx         *cli.Context  // command line context for accessing flags and command line arguments
+	runSummary  *RunSummary   // layout of the run if artifacts are scoped by --output-dir
 }
 
 func NewConfigContext(cfg *Config, ctx *cli.Context) *configContext {
@@ -423,15 +556,16 @@ func NewTestConfig(t *testing.T, chainId ChainID, first, last uint64, validate b
 	vmCfg.Tracer = nil
 	vmCfg.Interpreter = nil
 	return &Config{
-		ChainID:         chainId,
-		First:           first,
-		Last:            last,
-		ChainCfg:        chainCfg,
-		LogLevel:        "Critical",
-		SkipPriming:     true,
-		Validate:        validate,
-		ValidateTxState: validate,
-		VmCfg:           vmCfg,
+		ChainID:          chainId,
+		First:            first,
+		Last:             last,
+		ChainCfg:         chainCfg,
+		LogLevel:         "Critical",
+		SkipPriming:      true,
+		SkipSanityChecks: true, // mocked StateDbs only expect calls of the replayed transactions
+		Validate:         validate,
+		ValidateTxState:  validate,
+		VmCfg:            vmCfg,
 	}
 }
 
@@ -442,6 +576,13 @@ func NewConfig(ctx *cli.Context, mode ArgumentMode) (*Config, error) {
 	// create config with user flag values, if not set default values are used
 	cfg := createConfigFromFlags(ctx)
 
+	// log level overrides have to be known before any logger is created
+	overrides, err := logger.ParseLevelOverrides(cfg.LogLevelOverride)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse --%v; %w", logger.LogLevelOverrideFlag.Name, err)
+	}
+	logger.SetLevelOverrides(overrides)
+
 	// create config context for sharing common arguments
 	cc := NewConfigContext(cfg, ctx)
 
@@ -456,6 +597,11 @@ func NewConfig(ctx *cli.Context, mode ArgumentMode) (*Config, error) {
 		return nil, fmt.Errorf("cannot set chain id: %w", err)
 	}
 
+	err = cc.checkAidaDbChainId()
+	if err != nil {
+		return nil, err
+	}
+
 	err = cc.setVmConfig()
 	if err != nil {
 		return nil, fmt.Errorf("cannot set vm config: %w", err)
@@ -479,21 +625,42 @@ func NewConfig(ctx *cli.Context, mode ArgumentMode) (*Config, error) {
 		return cfg, fmt.Errorf("unable to parse cli arguments; %v", err)
 	}
 
+	err = cc.setOutputDir()
+	if err != nil {
+		return nil, fmt.Errorf("cannot set output directory; %v", err)
+	}
+
+	err = cc.setSchemaComparison()
+	if err != nil {
+		return nil, err
+	}
+
 	err = cc.adjustMissingConfigValues()
 	if err != nil {
 		return nil, fmt.Errorf("cannot adjust missing config values; %v", err)
 	}
 
+	err = cc.checkStateDbSrcCompatibility()
+	if err != nil {
+		return nil, err
+	}
+
 	cc.cfg.Fork = ToTitleCase(cc.cfg.Fork)
 	cc.reportNewConfig()
 
 	return cfg, nil
 }
 
+// GetChainConfig returns the chain config of the given fork. A custom chain config
+// given by --chain-config-file defines the fork schedule itself and is returned
+// for every fork.
 func (cfg *Config) GetChainConfig(fork string) (*params.ChainConfig, error) {
-	if cfg.ChainCfg != nil && fork == "" {
+	if cfg.ChainCfg != nil && (fork == "" || cfg.ChainConfigFile != "") {
 		return cfg.ChainCfg, nil
 	}
+	if cfg.ChainConfigFile != "" {
+		return LoadChainConfig(cfg.ChainConfigFile)
+	}
 	return getChainConfig(cfg.ChainID, fork)
 }
 
@@ -513,7 +680,7 @@ func (cfg *Config) GetInterpreterFactory() (vm.InterpreterFactory, error) {
 	// try to get the factory from Tosca's interpreter registry
 	interpreter, err := tosca.NewInterpreter(name)
 	if err != nil {
-		return nil, fmt.Errorf("cannot get interpreter for %q: %v", cfg.VmImpl, err)
+		return nil, fmt.Errorf("cannot get interpreter for %q: %v%v", cfg.VmImpl, err, FormatVmImplementationSuggestions(InterpreterImplementation, name))
 	}
 
 	cfg.interpreterFactory = geth_adapter.NewGethInterpreterFactory(interpreter)
@@ -620,6 +787,10 @@ func directoryExists(path string) bool {
 
 }
 
+// ErrEmptyBlockRange is returned if the first block of a block range is larger than its last block.
+// A range with equal first and last block is valid and executes exactly that block.
+var ErrEmptyBlockRange = errors.New("empty block range")
+
 // SetBlockRange checks the validity of a block range and return the first and last block as numbers.
 func SetBlockRange(firstArg string, lastArg string, chainId ChainID) (uint64, uint64, error) {
 	var err error = nil
@@ -641,7 +812,7 @@ func SetBlockRange(firstArg string, lastArg string, chainId ChainID) (uint64, ui
 	}
 
 	if first > last {
-		return 0, 0, fmt.Errorf("first block %v has larger number than last block %v", first, last)
+		return 0, 0, fmt.Errorf("%w; first block %v has larger number than last block %v", ErrEmptyBlockRange, first, last)
 	}
 
 	return first, last, err
@@ -675,7 +846,10 @@ func setBlockNumber(arg string, chainId ChainID) (uint64, error) {
 
 	// shift base block number by the offset
 	if hasOffset {
-		blkNum = offsetBlockNum(blkNum, symbol, offset)
+		var err error
+		if blkNum, err = offsetBlockNum(blkNum, symbol, offset); err != nil {
+			return 0, fmt.Errorf("invalid block number %v; %w", arg, err)
+		}
 	}
 
 	return blkNum, nil
@@ -684,50 +858,63 @@ func setBlockNumber(arg string, chainId ChainID) (uint64, error) {
 // parseOffset parse the hardfork keyword, offset value and a direction of the offset
 func parseOffset(arg string) (string, string, uint64, error) {
 	if strings.Contains(arg, "+") {
-		if keyword, offset, ok := splitKeywordOffset(arg, "+"); ok {
-			return strings.ToLower(keyword), "+", offset, nil
-		}
-
-		return "", "", 0, fmt.Errorf("block number not a valid keyword with offset")
+		return splitKeywordOffset(arg, "+")
 	} else if strings.Contains(arg, "-") {
-		if keyword, offset, ok := splitKeywordOffset(arg, "-"); ok {
-			return strings.ToLower(keyword), "-", offset, nil
-		}
-
-		return "", "", 0, fmt.Errorf("block number not a valid keyword with offset")
+		return splitKeywordOffset(arg, "-")
 	}
 
 	return "", "", 0, fmt.Errorf("block number has invalid arithmetical sign")
 }
 
 // splitKeywordOffset split the hardfork keyword and the arithmetical sign determining the direction of the offset
-func splitKeywordOffset(arg string, symbol string) (string, uint64, bool) {
+func splitKeywordOffset(arg string, symbol string) (string, string, uint64, error) {
 	res := strings.Split(arg, symbol)
+	keyword := strings.ToLower(res[0])
 
 	// if the keyword doesn't exist, return.
-	if _, ok := KeywordBlocks[OperaMainnetChainID][strings.ToLower(res[0])]; !ok {
-		return "", 0, false
+	if _, ok := KeywordBlocks[OperaMainnetChainID][keyword]; !ok {
+		return "", "", 0, fmt.Errorf("block number not a valid keyword with offset")
 	}
 
 	offset, err := strconv.ParseUint(res[1], 10, 64)
 	if err != nil {
-		return "", 0, false
+		return "", "", 0, fmt.Errorf("invalid offset %v of block number %v; %w", res[1], arg, err)
 	}
 
-	return res[0], offset, true
+	return keyword, symbol, offset, nil
 }
 
 // offsetBlockNum adds/subtracts the offset to/from block number
-func offsetBlockNum(blkNum uint64, symbol string, offset uint64) uint64 {
-	res := uint64(0)
+// and fails if the result leaves the range of valid block numbers
+func offsetBlockNum(blkNum uint64, symbol string, offset uint64) (uint64, error) {
 	switch symbol {
 	case "+":
-		res = blkNum + offset
+		if blkNum > maxLastBlock || offset > maxLastBlock-blkNum {
+			return 0, fmt.Errorf("block %v plus offset %v exceeds the largest block number %v", blkNum, offset, uint64(maxLastBlock))
+		}
+		return blkNum + offset, nil
 	case "-":
-		res = blkNum - offset
+		if offset > blkNum {
+			return 0, fmt.Errorf("block %v minus offset %v is below block 0", blkNum, offset)
+		}
+		return blkNum - offset, nil
 	}
 
-	return res
+	return 0, fmt.Errorf("unknown offset direction %q", symbol)
+}
+
+// isMetadataKeyword returns true if the block number argument refers to a block which
+// is resolved from the AidaDb metadata (first, last or lastpatch), with or without an offset
+func isMetadataKeyword(arg string) (bool, bool) {
+	name, _, hasOffset := strings.Cut(strings.ToLower(arg), "+")
+	if !hasOffset {
+		name, _, hasOffset = strings.Cut(name, "-")
+	}
+	switch name {
+	case "first", "last", "lastpatch":
+		return true, hasOffset
+	}
+	return false, false
 }
 
 // getMdBlockRange gets block range from aidaDB metadata
@@ -736,13 +923,13 @@ func (cc *configContext) getMdBlockRange() (uint64, uint64, uint64, error) {
 	defaultLast := KeywordBlocks[cc.cfg.ChainID]["last"]
 	defaultLastPatch := KeywordBlocks[cc.cfg.ChainID]["lastpatch"]
 
-	if !directoryExists(cc.cfg.AidaDb) {
+	if !aidaDbExists(cc.cfg.AidaDb) {
 		cc.log.Warningf("Unable to open Aida-db in %s", cc.cfg.AidaDb)
 		return defaultFirst, defaultLast, defaultLastPatch, nil
 	}
 
 	// read meta data
-	aidaDb, err := db.NewReadOnlySubstateDB(cc.cfg.AidaDb)
+	aidaDb, err := openAidaDb(cc.cfg.AidaDb, cc.cfg.LogLevel)
 	if err != nil {
 		cc.log.Warningf("Cannot open AidaDB; %v", err)
 		return defaultFirst, defaultLast, defaultLastPatch, nil
@@ -752,11 +939,17 @@ func (cc *configContext) getMdBlockRange() (uint64, uint64, uint64, error) {
 			cc.log.Warningf("Cannot close AidaDB; %v", err)
 		}
 	}()
-	err = aidaDb.SetSubstateEncoding(cc.cfg.SubstateEncoding)
+	encoding, err := ApplySubstateEncoding(aidaDb, cc.cfg.SubstateEncoding)
+	if errors.Is(err, ErrSubstateEncodingMismatch) {
+		return 0, 0, 0, err
+	}
 	if err != nil {
 		cc.log.Warningf("Cannot set substate encoding; %v", err)
 		return defaultFirst, defaultLast, defaultLastPatch, nil
 	}
+	if cc.cfg.SubstateEncoding == "" {
+		cc.cfg.SubstateEncoding = encoding
+	}
 
 	md := NewAidaDbMetadata(aidaDb, cc.cfg.LogLevel)
 	err = md.getBlockRange()
@@ -802,6 +995,22 @@ func (cc *configContext) adjustBlockRange(firstArg, lastArg uint64) (uint64, uin
 	}
 }
 
+// checkMetadataKeywords rejects block numbers relative to first, last or lastpatch if there is no AidaDb
+// metadata to resolve them from. Plain first and last are accepted and keep selecting the widest block range.
+func (cc *configContext) checkMetadataKeywords(args ...string) error {
+	for _, arg := range args {
+		keyword, withOffset := isMetadataKeyword(arg)
+		if !keyword || (!withOffset && !strings.EqualFold(arg, "lastpatch")) {
+			continue
+		}
+		if cc.cfg.AidaDb == "" {
+			return fmt.Errorf("block number %v is resolved from AidaDb metadata; please specify --%v", arg, AidaDbFlag.Name)
+		}
+		return fmt.Errorf("block number %v is resolved from AidaDb metadata, but AidaDb (%v) has no block range metadata", arg, cc.cfg.AidaDb)
+	}
+	return nil
+}
+
 // setChainId set config chainID to the default (mainnet) or user specified chainID
 // if the chainID is unknown type, it'll be loaded from aidaDB
 func (cc *configContext) setChainId() error {
@@ -810,7 +1019,7 @@ func (cc *configContext) setChainId() error {
 		cc.log.Warningf("ChainID (--%v) was not set; looking for it in AidaDb", ChainIDFlag.Name)
 
 		// we check if AidaDb was set with err == nil
-		if aidaDb, err := db.NewDefaultSubstateDB(cc.cfg.AidaDb); err == nil {
+		if aidaDb, err := openAidaDb(cc.cfg.AidaDb, cc.cfg.LogLevel); err == nil {
 			md := NewAidaDbMetadata(aidaDb, cc.cfg.LogLevel)
 
 			cc.cfg.ChainID = md.GetChainID()
@@ -830,6 +1039,35 @@ func (cc *configContext) setChainId() error {
 	return nil
 }
 
+// checkAidaDbChainId makes sure that an explicitly set chain id matches the chain id recorded
+// in the AidaDb metadata, since replaying with the chain config of another chain fails with
+// confusing errors. A mismatch is only accepted with --force-chain-id.
+func (cc *configContext) checkAidaDbChainId() error {
+	if !aidaDbExists(cc.cfg.AidaDb) {
+		return nil
+	}
+
+	aidaDb, err := openAidaDb(cc.cfg.AidaDb, cc.cfg.LogLevel)
+	if err != nil {
+		cc.log.Warningf("Cannot check chain id against AidaDb (%v); %v", cc.cfg.AidaDb, err)
+		return nil
+	}
+	mdChainId := NewAidaDbMetadata(aidaDb, cc.cfg.LogLevel).GetChainID()
+	if err = aidaDb.Close(); err != nil {
+		return fmt.Errorf("cannot close db; %v", err)
+	}
+
+	if mdChainId == UnknownChainID || mdChainId == cc.cfg.ChainID {
+		return nil
+	}
+	if cc.cfg.ForceChainID {
+		cc.log.Warningf("ChainID %v differs from chainId %v found in AidaDb (%v); proceeding since --%v is set", cc.cfg.ChainID, mdChainId, cc.cfg.AidaDb, ForceChainIDFlag.Name)
+		return nil
+	}
+	return fmt.Errorf("chain id %v (--%v) differs from chain id %v found in AidaDb (%v); omit --%v to use the chain id of AidaDb or set --%v to proceed anyway",
+		cc.cfg.ChainID, ChainIDFlag.Name, mdChainId, cc.cfg.AidaDb, ChainIDFlag.Name, ForceChainIDFlag.Name)
+}
+
 // updateConfigBlockRange parse the command line arguments according to the mode in which selected tool runs
 // and store them into the config
 func (cc *configContext) updateConfigBlockRange(args []string, mode ArgumentMode) error {
@@ -850,6 +1088,11 @@ func (cc *configContext) updateConfigBlockRange(args []string, mode ArgumentMode
 			KeywordBlocks[cc.cfg.ChainID]["first"] = firstMd
 			KeywordBlocks[cc.cfg.ChainID]["last"] = lastMd
 			KeywordBlocks[cc.cfg.ChainID]["lastpatch"] = lastPatchMd
+			if !cc.hasMetadata {
+				if err = cc.checkMetadataKeywords(args[0], args[1]); err != nil {
+					return err
+				}
+			}
 
 			// try to parse and check block range
 			firstArg, lastArg, argErr := SetBlockRange(args[0], args[1], cc.cfg.ChainID)
@@ -923,10 +1166,21 @@ func (cc *configContext) adjustMissingConfigValues() error {
 		log.Warning("Enable continue-on-failure mode because error logging is used.")
 	}
 
-	// --continue-on-failure implicitly enables transaction validation
-	cfg.ValidateTxState = cfg.Validate || cfg.ValidateTxState || cfg.ContinueOnFailure
+	// skipping failed transactions would hide the genuine errors reported with --continue-on-failure
+	if cfg.SkipFailedTx && cfg.ContinueOnFailure {
+		return fmt.Errorf("--%v cannot be combined with --%v (also enabled by --%v)", SkipFailedTxFlag.Name, ContinueOnFailureFlag.Name, ErrorLoggingFlag.Name)
+	}
+
+	// --continue-on-failure and --skip-failed-tx implicitly enable transaction validation
+	cfg.ValidateTxState = cfg.Validate || cfg.ValidateTxState || cfg.ContinueOnFailure || cfg.SkipFailedTx || cfg.TxStateValidation == RelaxedTxStateValidation
 	cfg.ValidateStateHashes = cfg.Validate || cfg.ValidateStateHashes
 
+	// remapped addresses change the state roots, hence they cannot be compared to the recorded ones
+	if cfg.RemapKey != "" && cfg.ValidateStateHashes {
+		cfg.ValidateStateHashes = false
+		log.Warning("State hash validation is disabled because addresses are remapped.")
+	}
+
 	if cfg.RandomSeed < 0 {
 		cfg.RandomSeed = int64(rand.Uint32())
 	}
@@ -956,6 +1210,33 @@ func (cc *configContext) adjustMissingConfigValues() error {
 	return nil
 }
 
+// checkStateDbSrcCompatibility refuses to use an existing StateDb if its recorded configuration
+// contradicts the StateDb flags given by the user. StateDbs without an info file are only reported.
+func (cc *configContext) checkStateDbSrcCompatibility() error {
+	if cc.cfg.StateDbSrc == "" {
+		return nil
+	}
+
+	path := cc.cfg.StateDbSrc
+	if cc.cfg.ShadowDb {
+		path = filepath.Join(path, PathToPrimaryStateDb)
+	}
+
+	info, err := ReadStateDbInfo(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			cc.log.Warningf("StateDb %v has no %v file (legacy StateDb?); its configuration cannot be verified against the requested one", path, PathToDbInfo)
+			return nil
+		}
+		return fmt.Errorf("cannot read StateDb info; %w", err)
+	}
+
+	if err = checkStateDbInfoCompatibility(info, cc.cfg, cc.isSet); err != nil {
+		return fmt.Errorf("cannot use StateDb %v; %w", path, err)
+	}
+	return nil
+}
+
 // reportNewConfig logs out the state of config in current run
 func (cc *configContext) reportNewConfig() {
 	cfg := cc.cfg
@@ -963,7 +1244,7 @@ func (cc *configContext) reportNewConfig() {
 
 	log.Noticef("Run config:")
 	log.Infof("Block range: %v to %v", cfg.First, cfg.Last)
-	if cfg.MaxNumTransactions >= 0 {
+	if cfg.MaxNumTransactions > 0 {
 		log.Noticef("Transaction limit: %d", cfg.MaxNumTransactions)
 	}
 	log.Infof("Chain id: %v (record & run-vm only)", cfg.ChainID)
@@ -971,7 +1252,11 @@ func (cc *configContext) reportNewConfig() {
 	log.Noticef("Used EVM implementation: %v", cfg.EvmImpl)
 	log.Noticef("Used VM implementation: %v", cfg.VmImpl)
 	log.Infof("Aida DB directory: %v", cfg.AidaDb)
-	log.Infof("Fork: %v", cfg.Fork)
+	if len(cfg.Forks) > 0 {
+		log.Infof("Forks: %v", strings.Join(cfg.Forks, ","))
+	} else {
+		log.Infof("Fork: %v", cfg.Fork)
+	}
 
 	// todo move to tx validator once finished
 	log.Infof("validate tx state: %v", cfg.ValidateTxState)
@@ -998,9 +1283,13 @@ func (cc *configContext) reportNewConfig() {
 	if cfg.DeltaLogging != "" {
 		log.Warning("Delta logging enabled, reducing Tx throughput")
 	}
+	cc.reportRunLayout()
 }
 
 func (cc *configContext) setChainConfig() (err error) {
+	if cc.cfg.ChainConfigFile != "" {
+		return cc.loadChainConfigFile()
+	}
 	// Each test will have its own chainConfig - no need to set here
 	if cc.cfg.ChainID == EthTestsChainID {
 		return nil
@@ -1009,6 +1298,24 @@ func (cc *configContext) setChainConfig() (err error) {
 	return err
 }
 
+// loadChainConfigFile replaces the predefined chain config with the one given by --chain-config-file.
+func (cc *configContext) loadChainConfigFile() error {
+	if cc.cfg.ChainID == EthTestsChainID {
+		return fmt.Errorf("--%v cannot be used with ethereum tests, each test defines its own fork", ChainConfigFileFlag.Name)
+	}
+	chainCfg, err := LoadChainConfig(cc.cfg.ChainConfigFile)
+	if err != nil {
+		return err
+	}
+	if chainCfg.ChainID.Cmp(new(big.Int).SetUint64(uint64(cc.cfg.ChainID))) != 0 {
+		cc.log.Warningf("ChainID %v from %v differs from chain id %v (--%v); transactions are signed and executed with the chain id of the file",
+			chainCfg.ChainID, cc.cfg.ChainConfigFile, cc.cfg.ChainID, ChainIDFlag.Name)
+	}
+	cc.log.Noticef("Using chain config from %v", cc.cfg.ChainConfigFile)
+	cc.cfg.ChainCfg = chainCfg
+	return nil
+}
+
 func (cc *configContext) setVmConfig() (err error) {
 	if !IsEthereumNetwork(cc.cfg.ChainID) {
 		// The default VM config is sufficient for all Sonic blocks that have
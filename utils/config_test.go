@@ -22,6 +22,7 @@ import (
 	"math"
 	"math/big"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"testing"
@@ -44,6 +45,7 @@ func prepareMockCliContext() *cli.Context {
 	flagSet.Bool(ContinueOnFailureFlag.Name, true, "continue execute after validation failure detected")
 	flagSet.String(AidaDbFlag.Name, "./test.db", "set substate, updateset and deleted accounts directory")
 	flagSet.String(logger.LogLevelFlag.Name, "info", "Level of the logging of the app action (\"critical\", \"error\", \"warning\", \"notice\", \"info\", \"debug\"; default: NOTICE)")
+	flagSet.String(logger.LogLevelOverrideFlag.Name, "", "per-component log levels")
 
 	ctx := cli.NewContext(cli.NewApp(), flagSet, nil)
 
@@ -94,6 +96,14 @@ func TestUtilsConfig_NewConfig(t *testing.T) {
 	}
 }
 
+func TestUtilsConfig_NewConfigRejectsUnknownLogLevelOverride(t *testing.T) {
+	ctx := prepareMockCliContext()
+	require.NoError(t, ctx.Set(logger.LogLevelOverrideFlag.Name, "StateDbLogger=verbose"))
+
+	_, err := NewConfig(ctx, NoArgs)
+	require.ErrorContains(t, err, `invalid log level "verbose" for module "StateDbLogger"`)
+}
+
 func TestUtilsConfig_SetBlockRange(t *testing.T) {
 	first, last, err := SetBlockRange("0", "40000000", 0)
 	if err != nil {
@@ -230,6 +240,100 @@ func TestUtilsConfig_SetBlockRangeLastSmallerThanFirst(t *testing.T) {
 	}
 }
 
+func TestUtilsConfig_SetBlockRangeMetadataKeywords(t *testing.T) {
+	chainId := OperaMainnetChainID
+	defer func(first, last, lastpatch uint64) {
+		KeywordBlocks[chainId]["first"] = first
+		KeywordBlocks[chainId]["last"] = last
+		KeywordBlocks[chainId]["lastpatch"] = lastpatch
+	}(KeywordBlocks[chainId]["first"], KeywordBlocks[chainId]["last"], KeywordBlocks[chainId]["lastpatch"])
+	KeywordBlocks[chainId]["first"] = 1_000_000
+	KeywordBlocks[chainId]["last"] = 5_000_000
+	KeywordBlocks[chainId]["lastpatch"] = 4_000_000
+
+	tests := []struct {
+		firstArg, lastArg string
+		first, last       uint64
+	}{
+		{"first", "last", 1_000_000, 5_000_000},
+		{"FIRST", "Last", 1_000_000, 5_000_000},
+		{"first+500000", "last", 1_500_000, 5_000_000},
+		{"first", "last-1000000", 1_000_000, 4_000_000},
+		{"last-1000000", "last", 4_000_000, 5_000_000},
+		{"first", "first+0", 1_000_000, 1_000_000},
+		{"first-1000000", "last+10", 0, 5_000_010},
+		{"lastpatch", "last", 4_000_000, 5_000_000},
+		{"lastpatch-10", "lastpatch+10", 3_999_990, 4_000_010},
+		{"2000000", "last", 2_000_000, 5_000_000},
+	}
+	for _, test := range tests {
+		t.Run(test.firstArg+"_"+test.lastArg, func(t *testing.T) {
+			first, last, err := SetBlockRange(test.firstArg, test.lastArg, chainId)
+			require.NoError(t, err)
+			assert.Equal(t, test.first, first)
+			assert.Equal(t, test.last, last)
+		})
+	}
+}
+
+func TestUtilsConfig_SetBlockRangeInvalidOffsets(t *testing.T) {
+	chainId := OperaMainnetChainID
+	defer func(first, last uint64) {
+		KeywordBlocks[chainId]["first"] = first
+		KeywordBlocks[chainId]["last"] = last
+	}(KeywordBlocks[chainId]["first"], KeywordBlocks[chainId]["last"])
+	KeywordBlocks[chainId]["first"] = 1_000_000
+	KeywordBlocks[chainId]["last"] = 5_000_000
+
+	tests := []struct {
+		firstArg, lastArg string
+		wantErr           string
+	}{
+		{"first-1000001", "last", "block 1000000 minus offset 1000001 is below block 0"},
+		{"first", "last-5000001", "block 5000000 minus offset 5000001 is below block 0"},
+		{"first", "last+18446744073709551615", "exceeds the largest block number"},
+		{"first", "last+18446744073709551616", "invalid offset 18446744073709551616"},
+		{"first+10", "first+5", "empty block range"},
+		{"last-10", "first", "empty block range"},
+		{"latest-10", "last", "not a valid keyword with offset"},
+		{"first", "last*2", "not a valid keyword or integer"},
+		{"first", "last-", "not a valid keyword or integer"},
+		{"first", "last-10-5", "not a valid keyword or integer"},
+	}
+	for _, test := range tests {
+		t.Run(test.firstArg+"_"+test.lastArg, func(t *testing.T) {
+			_, _, err := SetBlockRange(test.firstArg, test.lastArg, chainId)
+			require.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestUtilsConfig_offsetBlockNum(t *testing.T) {
+	res, err := offsetBlockNum(10, "+", 5)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(15), res)
+
+	res, err = offsetBlockNum(10, "-", 10)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), res)
+
+	res, err = offsetBlockNum(1, "+", maxLastBlock-1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(maxLastBlock), res)
+
+	_, err = offsetBlockNum(2, "+", maxLastBlock-1)
+	require.ErrorContains(t, err, "exceeds the largest block number")
+
+	_, err = offsetBlockNum(math.MaxUint64, "+", 0)
+	require.ErrorContains(t, err, "exceeds the largest block number")
+
+	_, err = offsetBlockNum(10, "-", 11)
+	require.ErrorContains(t, err, "is below block 0")
+
+	_, err = offsetBlockNum(10, "*", 1)
+	require.ErrorContains(t, err, "unknown offset direction")
+}
+
 func TestUtilsConfig_adjustBlockRange(t *testing.T) {
 	var (
 		chainId           ChainID
@@ -479,6 +583,62 @@ func TestUtilsConfig_setDefaultChainId(t *testing.T) {
 
 }
 
+func TestUtilsConfig_checkAidaDbChainId(t *testing.T) {
+	aidaDbPath := t.TempDir()
+	require.NoError(t, createFakeAidaDb(&Config{AidaDb: aidaDbPath, ChainID: SonicMainnetChainID, LogLevel: "CRITICAL"}))
+
+	tests := []struct {
+		name       string
+		aidaDbPath string
+		chainID    ChainID
+		force      bool
+		wantErr    string
+	}{
+		{
+			name:       "Matching ChainID",
+			aidaDbPath: aidaDbPath,
+			chainID:    SonicMainnetChainID,
+		},
+		{
+			name:       "Mismatching ChainID",
+			aidaDbPath: aidaDbPath,
+			chainID:    OperaMainnetChainID,
+			wantErr:    fmt.Sprintf("chain id 250 (--chainid) differs from chain id 146 found in AidaDb (%v)", aidaDbPath),
+		},
+		{
+			name:       "Mismatching ChainID Forced",
+			aidaDbPath: aidaDbPath,
+			chainID:    OperaMainnetChainID,
+			force:      true,
+		},
+		{
+			name:       "Missing AidaDb",
+			aidaDbPath: filepath.Join(t.TempDir(), "missing"),
+			chainID:    OperaMainnetChainID,
+		},
+		{
+			name:    "No AidaDb",
+			chainID: EthTestsChainID,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Config{AidaDb: test.aidaDbPath, LogLevel: "Critical", ChainID: test.chainID, ForceChainID: test.force}
+			cc := NewConfigContext(cfg, &cli.Context{Command: &cli.Command{Name: "fake-name"}})
+
+			err := cc.checkAidaDbChainId()
+			if test.wantErr != "" {
+				require.ErrorContains(t, err, test.wantErr)
+				require.ErrorContains(t, err, "--force-chain-id")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.chainID, cfg.ChainID)
+		})
+	}
+}
+
 // TestUtilsConfig_updateConfigBlockRangeBlockRange tests correct parsing of cli arguments for block range
 func TestUtilsConfig_updateConfigBlockRangeBlockRange(t *testing.T) {
 	// prepare components
@@ -545,6 +705,56 @@ func TestUtilsConfig_updateConfigBlockRangeBlockRangeInvalid(t *testing.T) {
 	}
 }
 
+// TestUtilsConfig_updateConfigBlockRangeMetadataKeywords tests resolving of keywords relative to the AidaDb metadata
+func TestUtilsConfig_updateConfigBlockRangeMetadataKeywords(t *testing.T) {
+	defer func(first, last, lastpatch uint64) {
+		KeywordBlocks[OperaMainnetChainID]["first"] = first
+		KeywordBlocks[OperaMainnetChainID]["last"] = last
+		KeywordBlocks[OperaMainnetChainID]["lastpatch"] = lastpatch
+	}(KeywordBlocks[OperaMainnetChainID]["first"], KeywordBlocks[OperaMainnetChainID]["last"], KeywordBlocks[OperaMainnetChainID]["lastpatch"])
+	cfg := &Config{AidaDb: filepath.Join(t.TempDir(), "test.db"), LogLevel: "NOTICE", ChainID: OperaMainnetChainID}
+	require.NoError(t, createFakeAidaDb(cfg))
+
+	cc := NewConfigContext(cfg, nil)
+	require.NoError(t, cc.updateConfigBlockRange([]string{"first+500000", "last-1000000"}, BlockRangeArgs))
+	assert.Equal(t, KeywordBlocks[OperaMainnetChainID]["opera"]+500_000, cfg.First)
+	assert.Equal(t, uint64(20_001_704-1_000_000), cfg.Last)
+
+	cc = NewConfigContext(cfg, nil)
+	err := cc.updateConfigBlockRange([]string{"first-4564027", "last"}, BlockRangeArgs)
+	require.ErrorContains(t, err, "is below block 0")
+}
+
+// TestUtilsConfig_updateConfigBlockRangeMetadataKeywordsWithoutAidaDb tests that keywords relative
+// to the AidaDb metadata are rejected if there is no AidaDb
+func TestUtilsConfig_updateConfigBlockRangeMetadataKeywordsWithoutAidaDb(t *testing.T) {
+	tests := []struct {
+		aidaDb, firstArg, lastArg string
+		wantErr                   string
+	}{
+		{"", "first+10", "last", "please specify --aida-db"},
+		{"", "first", "last-1000000", "please specify --aida-db"},
+		{"", "first-10", "last", "please specify --aida-db"},
+		{"", "lastpatch", "last", "please specify --aida-db"},
+		{"./missing.db", "last-100", "last", "AidaDb (./missing.db) has no block range metadata"},
+	}
+	for _, test := range tests {
+		t.Run(test.firstArg+"_"+test.lastArg, func(t *testing.T) {
+			cfg := &Config{AidaDb: test.aidaDb, LogLevel: "NOTICE", ChainID: OperaMainnetChainID}
+			cc := NewConfigContext(cfg, nil)
+			err := cc.updateConfigBlockRange([]string{test.firstArg, test.lastArg}, BlockRangeArgs)
+			require.ErrorContains(t, err, test.wantErr)
+		})
+	}
+
+	// plain first and last keep selecting the widest block range
+	cfg := &Config{LogLevel: "NOTICE", ChainID: OperaMainnetChainID}
+	cc := NewConfigContext(cfg, nil)
+	require.NoError(t, cc.updateConfigBlockRange([]string{"first", "last"}, BlockRangeArgs))
+	assert.Equal(t, uint64(0), cfg.First)
+	assert.Equal(t, uint64(maxLastBlock), cfg.Last)
+}
+
 // TestUtilsConfig_updateConfigBlockRangeLastBlock tests correct parsing of cli argument for last block number
 func TestUtilsConfig_updateConfigBlockRangeLastBlock(t *testing.T) {
 	// prepare components
@@ -728,6 +938,64 @@ func TestUtilsConfig_adjustMissingConfigValuesValidationOn(t *testing.T) {
 	}
 }
 
+// TestUtilsConfig_adjustMissingConfigValuesRelaxedTxStateValidation tests that the relaxed validation enables the validation
+func TestUtilsConfig_adjustMissingConfigValuesRelaxedTxStateValidation(t *testing.T) {
+	cfg := &Config{
+		TxStateValidation: RelaxedTxStateValidation,
+		LogLevel:          "NOTICE",
+	}
+	require.NoError(t, NewConfigContext(cfg, nil).adjustMissingConfigValues())
+	assert.True(t, cfg.ValidateTxState)
+
+	cfg = &Config{
+		TxStateValidation: StrictTxStateValidation,
+		LogLevel:          "NOTICE",
+	}
+	require.NoError(t, NewConfigContext(cfg, nil).adjustMissingConfigValues())
+	assert.False(t, cfg.ValidateTxState)
+}
+
+// TestUtilsConfig_adjustMissingConfigValuesRemapDisablesStateHashes tests that state hash validation is disabled for remapped addresses
+func TestUtilsConfig_adjustMissingConfigValuesRemapDisablesStateHashes(t *testing.T) {
+	cfg := &Config{
+		Validate: true,
+		RemapKey: "key",
+		LogLevel: "NOTICE",
+	}
+
+	cc := NewConfigContext(cfg, nil)
+	require.NoError(t, cc.adjustMissingConfigValues())
+	assert.False(t, cfg.ValidateStateHashes)
+	assert.True(t, cfg.ValidateTxState)
+}
+
+// TestUtilsConfig_adjustMissingConfigValuesSkipFailedTx tests that skipping failed transactions enables the validation
+// and cannot be combined with continue-on-failure
+func TestUtilsConfig_adjustMissingConfigValuesSkipFailedTx(t *testing.T) {
+	cfg := &Config{
+		SkipFailedTx: true,
+		LogLevel:     "NOTICE",
+	}
+	require.NoError(t, NewConfigContext(cfg, nil).adjustMissingConfigValues())
+	assert.True(t, cfg.ValidateTxState)
+
+	cfg = &Config{
+		SkipFailedTx:      true,
+		ContinueOnFailure: true,
+		LogLevel:          "NOTICE",
+	}
+	err := NewConfigContext(cfg, nil).adjustMissingConfigValues()
+	require.ErrorContains(t, err, "--skip-failed-tx cannot be combined with --continue-on-failure")
+
+	cfg = &Config{
+		SkipFailedTx: true,
+		ErrorLogging: "errors.log",
+		LogLevel:     "NOTICE",
+	}
+	err = NewConfigContext(cfg, nil).adjustMissingConfigValues()
+	require.ErrorContains(t, err, "--skip-failed-tx cannot be combined with --continue-on-failure")
+}
+
 // TestUtilsConfig_adjustMissingConfigValuesValidationOff tests if missing config validation values are set correctly
 func TestUtilsConfig_adjustMissingConfigValuesValidationOff(t *testing.T) {
 	// prepare mock config
@@ -17,6 +17,8 @@
 package utils
 
 import (
+	"time"
+
 	"github.com/0xsoniclabs/aida/cmd/util-db/flags"
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/0xsoniclabs/substate/db"
@@ -29,84 +31,152 @@ func createConfigFromFlags(ctx *cli.Context) *Config {
 		AppName:     ctx.App.HelpName,
 		CommandName: ctx.Command.Name,
 
+		AccessListStats:          getFlagValue(ctx, AccessListStatsFlag).(string),
 		AidaDb:                   getFlagValue(ctx, AidaDbFlag).(string),
+		ArchiveCacheSize:         getFlagValue(ctx, ArchiveCacheSizeFlag).(int),
 		ArchiveMaxQueryAge:       getFlagValue(ctx, ArchiveMaxQueryAgeFlag).(int),
 		ArchiveMode:              getFlagValue(ctx, ArchiveModeFlag).(bool),
+		ArchiveQueryBurst:        getFlagValue(ctx, ArchiveQueryBurstFlag).(int),
 		ArchiveQueryRate:         getFlagValue(ctx, ArchiveQueryRateFlag).(int),
+		ArchiveQueryWarmUp:       getFlagValue(ctx, ArchiveQueryWarmUpFlag).(time.Duration),
 		ArchiveVariant:           getFlagValue(ctx, ArchiveVariantFlag).(string),
 		BalanceRange:             getFlagValue(ctx, BalanceRangeFlag).(int64),
 		BasicBlockProfiling:      getFlagValue(ctx, BasicBlockProfilingFlag).(bool),
+		Bisect:                   getFlagValue(ctx, BisectFlag).(bool),
+		BisectA:                  getFlagValue(ctx, BisectAFlag).(string),
+		BisectB:                  getFlagValue(ctx, BisectBFlag).(string),
+		BisectReport:             getFlagValue(ctx, BisectReportFlag).(string),
 		BlockLength:              getFlagValue(ctx, BlockLengthFlag).(uint64),
 		CPUProfile:               getFlagValue(ctx, CpuProfileFlag).(string),
 		CPUProfilePerInterval:    getFlagValue(ctx, CpuProfilePerIntervalFlag).(bool),
 		Cache:                    getFlagValue(ctx, CacheFlag).(int),
 		CarmenCheckpointInterval: getFlagValue(ctx, CarmenCheckpointInterval).(int),
 		CarmenCheckpointPeriod:   getFlagValue(ctx, CarmenCheckpointPeriod).(int),
+		CarmenCheckpointTrigger:  getFlagValue(ctx, CarmenCheckpointTriggerFlag).(string),
 		CarmenSchema:             getFlagValue(ctx, CarmenSchemaFlag).(int),
+		ChainConfigFile:          getFlagValue(ctx, ChainConfigFileFlag).(string),
 		ChainID:                  ChainID(getFlagValue(ctx, ChainIDFlag).(int)),
 		ChannelBufferSize:        getFlagValue(ctx, ChannelBufferSizeFlag).(int),
+		CloneWorkers:             getFlagValue(ctx, CloneWorkersFlag).(int),
 		CompactDb:                getFlagValue(ctx, CompactDbFlag).(bool),
+		CompareSchemas:           getFlagValue(ctx, CompareSchemasFlag).(string),
+		CompareSchemasReport:     getFlagValue(ctx, CompareSchemasReportFlag).(string),
 		ContinueOnFailure:        getFlagValue(ctx, ContinueOnFailureFlag).(bool),
 		ContractNumber:           getFlagValue(ctx, ContractNumberFlag).(int64),
+		CrossCheckDir:            getFlagValue(ctx, CrossCheckDirFlag).(string),
+		CrossCheckEvm:            getFlagValue(ctx, CrossCheckEvmFlag).(string),
 		CustomDbName:             getFlagValue(ctx, CustomDbNameFlag).(string),
 		DbComponent:              getFlagValue(ctx, DbComponentFlag).(string),
 		DbImpl:                   getFlagValue(ctx, StateDbImplementationFlag).(string),
 		DbLogging:                getFlagValue(ctx, StateDbLoggingFlag).(string),
+		DbLoggingFilter:          getFlagValue(ctx, StateDbLoggingFilterFlag).(string),
+		DbLoggingFormat:          getFlagValue(ctx, StateDbLoggingFormatFlag).(string),
 		DeltaLogging:             getFlagValue(ctx, DeltaLoggingFlag).(string),
+		DeltaLoggingResults:      getFlagValue(ctx, DeltaLoggingResultsFlag).(bool),
 		DbTmp:                    getFlagValue(ctx, DbTmpFlag).(string),
 		DbVariant:                getFlagValue(ctx, StateDbVariantFlag).(string),
 		Debug:                    getFlagValue(ctx, TraceDebugFlag).(bool),
 		DebugFrom:                getFlagValue(ctx, DebugFromFlag).(uint64),
+		DeepOutputCompare:        getFlagValue(ctx, DeepOutputCompareFlag).(bool),
 		DeleteSourceDbs:          getFlagValue(ctx, DeleteSourceDbsFlag).(bool),
 		DeletionDb:               getFlagValue(ctx, DeletionDbFlag).(string),
+		DryRun:                   getFlagValue(ctx, flags.DryRun).(bool),
 		DiagnosticServer:         getFlagValue(ctx, DiagnosticServerFlag).(int64),
 		ErrorLogging:             getFlagValue(ctx, ErrorLoggingFlag).(string),
+		ErrorLoggingFormat:       getFlagValue(ctx, ErrorLoggingFormatFlag).(string),
 		EvmImpl:                  getFlagValue(ctx, EvmImplementation).(string),
+		ExcludePrecompileCalls:   getFlagValue(ctx, ExcludePrecompileCallsFlag).(string),
+		ExportGenesis:            getFlagValue(ctx, ExportGenesisFlag).(string),
+		ForceChainID:             getFlagValue(ctx, ForceChainIDFlag).(bool),
 		Fork:                     getFlagValue(ctx, ForkFlag).(string),
+		Forks:                    getFlagValue(ctx, ForksFlag).([]string),
 		Genesis:                  getFlagValue(ctx, GenesisFlag).(string),
+		HeartbeatFields:          getFlagValue(ctx, HeartbeatFieldsFlag).(string),
 		EthTestType:              EthTestType(getFlagValue(ctx, EthTestTypeFlag).(int)),
 		IncludeStorage:           getFlagValue(ctx, IncludeStorageFlag).(bool),
 		KeepDb:                   getFlagValue(ctx, KeepDbFlag).(bool),
+		KillCount:                getFlagValue(ctx, KillCountFlag).(int),
+		KillReport:               getFlagValue(ctx, KillReportFlag).(string),
+		KillResumeArgs:           getFlagValue(ctx, KillResumeArgsFlag).(string),
 		KeysNumber:               getFlagValue(ctx, KeysNumberFlag).(int64),
+		LazySubstateDecoding:     getFlagValue(ctx, LazySubstateDecodingFlag).(bool),
 		LogLevel:                 getFlagValue(ctx, logger.LogLevelFlag).(string),
+		LogLevelOverride:         getFlagValue(ctx, logger.LogLevelOverrideFlag).(string),
+		LogOverflow:              getFlagValue(ctx, LogOverflowFlag).(string),
+		LogQueueSize:             getFlagValue(ctx, LogQueueSizeFlag).(int),
 		MaxNumErrors:             getFlagValue(ctx, MaxNumErrorsFlag).(int),
 		MaxNumTransactions:       getFlagValue(ctx, MaxNumTransactionsFlag).(int),
 		MemoryBreakdown:          getFlagValue(ctx, MemoryBreakdownFlag).(bool),
 		MemoryProfile:            getFlagValue(ctx, MemoryProfileFlag).(string),
 		MicroProfiling:           getFlagValue(ctx, MicroProfilingFlag).(bool),
+		MinFreeDiskGib:           getFlagValue(ctx, MinFreeDiskGibFlag).(float64),
 		NoHeartbeatLogging:       getFlagValue(ctx, NoHeartbeatLoggingFlag).(bool),
 		NonceRange:               getFlagValue(ctx, NonceRangeFlag).(int),
 		OnlySuccessful:           getFlagValue(ctx, OnlySuccessfulFlag).(bool),
 		OperaBinary:              getFlagValue(ctx, OperaBinaryFlag).(string),
+		OtlpBlockSampling:        getFlagValue(ctx, OtlpBlockSamplingFlag).(int),
+		OtlpEndpoint:             getFlagValue(ctx, OtlpEndpointFlag).(string),
 		ClientDb:                 getFlagValue(ctx, ClientDbFlag).(string),
 		Output:                   getFlagValue(ctx, OutputFlag).(string),
+		OutputDir:                getFlagValue(ctx, OutputDirFlag).(string),
 		OverwriteRunId:           getFlagValue(ctx, OverwriteRunIdFlag).(string),
+		PauseOnFailure:           getFlagValue(ctx, PauseOnFailureFlag).(bool),
+		PipelineDepth:            getFlagValue(ctx, PipelineDepthFlag).(int),
 		PrimeRandom:              getFlagValue(ctx, RandomizePrimingFlag).(bool),
+		PrimeShuffleWindow:       getFlagValue(ctx, PrimeShuffleWindowFlag).(int),
 		PrimeThreshold:           getFlagValue(ctx, PrimeThresholdFlag).(int),
 		Profile:                  getFlagValue(ctx, ProfileFlag).(bool),
 		ProfileBlocks:            getFlagValue(ctx, ProfileBlocksFlag).(bool),
+		ProfileContracts:         getFlagValue(ctx, ProfileContractsFlag).(bool),
+		ProfileContractsTop:      getFlagValue(ctx, ProfileContractsTopFlag).(int),
 		ProfileDB:                getFlagValue(ctx, ProfileDBFlag).(string),
 		ProfileDepth:             getFlagValue(ctx, ProfileDepthFlag).(int),
 		ProfileEVMCall:           getFlagValue(ctx, ProfileEVMCallFlag).(bool),
 		ProfileFile:              getFlagValue(ctx, ProfileFileFlag).(string),
 		ProfileInterval:          getFlagValue(ctx, ProfileIntervalFlag).(uint64),
 		ProfileSqlite3:           getFlagValue(ctx, ProfileSqlite3Flag).(string),
+		ProfileTxTiming:          getFlagValue(ctx, ProfileTxTimingFlag).(bool),
 		ProfilingDbName:          getFlagValue(ctx, ProfilingDbNameFlag).(string),
+		Provider:                 getFlagValue(ctx, ProviderFlag).(string),
+		QueryWorkloadFile:        getFlagValue(ctx, QueryWorkloadFileFlag).(string),
+		QueryWorkloadSpeedup:     getFlagValue(ctx, QueryWorkloadSpeedupFlag).(float64),
 		RandomSeed:               getFlagValue(ctx, RandomSeedFlag).(int64),
 		EnableCoverage:           getFlagValue(ctx, EnableCoverageFlag).(bool),
 		CoverageSnapshotInterval: getFlagValue(ctx, CoverageSnapshotIntervalFlag).(int),
+		RegisterExportCsv:        getFlagValue(ctx, RegisterExportCsvFlag).(string),
+		RegisterGasBuckets:       getFlagValue(ctx, RegisterGasBucketsFlag).(string),
 		RegisterRun:              getFlagValue(ctx, RegisterRunFlag).(string),
+		RegisterTxBuckets:        getFlagValue(ctx, RegisterTxBucketsFlag).(string),
+		RemapKey:                 getFlagValue(ctx, RemapKeyFlag).(string),
+		RemapStorageKeys:         getFlagValue(ctx, RemapStorageKeysFlag).(bool),
+		ReplayWorkers:            getFlagValue(ctx, ReplayWorkersFlag).(int),
+		ReportFile:               getFlagValue(ctx, ReportFileFlag).(string),
+		Resume:                   getFlagValue(ctx, ResumeFlag).(bool),
+		RunBundle:                getFlagValue(ctx, RunBundleFlag).(string),
+		RpcCompareLatency:        getFlagValue(ctx, RpcCompareLatencyFlag).(bool),
+		RpcFuzz:                  getFlagValue(ctx, RpcFuzzFlag).(bool),
+		RpcFuzzVariants:          getFlagValue(ctx, RpcFuzzVariantsFlag).(int),
+		RpcLatencyReport:         getFlagValue(ctx, RpcLatencyReportFlag).(string),
 		RpcRecordingPath:         getFlagValue(ctx, RpcRecordingFileFlag).(string),
+		ScanCachePolicy:          getFlagValue(ctx, ScanCachePolicyFlag).(string),
 		ShadowDb:                 getFlagValue(ctx, ShadowDb).(bool),
 		ShadowImpl:               getFlagValue(ctx, ShadowDbImplementationFlag).(string),
+		ShadowRetry:              getFlagValue(ctx, ShadowRetryFlag).(int),
 		ShadowVariant:            getFlagValue(ctx, ShadowDbVariantFlag).(string),
+		SkipFailedTx:             getFlagValue(ctx, SkipFailedTxFlag).(bool),
+		SkipList:                 getFlagValue(ctx, SkipListFlag).(string),
 		SkipMetadata:             getFlagValue(ctx, flags.SkipMetadata).(bool),
 		SkipPriming:              getFlagValue(ctx, SkipPrimingFlag).(bool),
+		SkipSanityChecks:         getFlagValue(ctx, SkipSanityChecksFlag).(bool),
 		SkipStateHashScrapping:   getFlagValue(ctx, SkipStateHashScrappingFlag).(bool),
 		SnapshotDepth:            getFlagValue(ctx, SnapshotDepthFlag).(int),
+		StallAction:              getFlagValue(ctx, StallActionFlag).(string),
+		StallTimeout:             getFlagValue(ctx, StallTimeoutFlag).(time.Duration),
 		StateDbSrc:               getFlagValue(ctx, StateDbSrcFlag).(string),
 		StateDbSrcDirectAccess:   getFlagValue(ctx, StateDbSrcOverwriteFlag).(bool),
 		StateDbSrcReadOnly:       false,
+		StateHashInterval:        getFlagValue(ctx, ValidateStateHashesIntervalFlag).(int),
+		StateHashSkipFirst:       getFlagValue(ctx, ValidateStateHashesSkipFirstFlag).(int),
 		// TODO re-enable equality check once supported in Carmen
 		StateValidationMode:    SubsetCheck,
 		SubstateDb:             getFlagValue(ctx, AidaDbFlag).(string),
@@ -117,20 +187,35 @@ func createConfigFromFlags(ctx *cli.Context) *Config {
 		Trace:                  getFlagValue(ctx, TraceFlag).(bool),
 		TraceDirectory:         getFlagValue(ctx, TraceDirectoryFlag).(string),
 		TraceFile:              getFlagValue(ctx, TraceFileFlag).(string),
+		TrackIo:                getFlagValue(ctx, TrackIoFlag).(bool),
 		TrackProgress:          getFlagValue(ctx, TrackProgressFlag).(bool),
+		TrackerEtaWindow:       getFlagValue(ctx, TrackerEtaWindowFlag).(int),
 		TrackerGranularity:     getFlagValue(ctx, TrackerGranularityFlag).(int),
 		TransactionLength:      getFlagValue(ctx, TransactionLengthFlag).(uint64),
 		UpdateBufferSize:       getFlagValue(ctx, UpdateBufferSizeFlag).(uint64),
+		UpdateBufferStrict:     getFlagValue(ctx, UpdateBufferStrictFlag).(bool),
+		UpdateCacheDir:         getFlagValue(ctx, UpdateCacheDirFlag).(string),
+		UpdateCacheSize:        getFlagValue(ctx, UpdateCacheSizeFlag).(int),
 		UpdateDb:               getFlagValue(ctx, UpdateDbFlag).(string),
 		OverwritePreWorldState: getFlagValue(ctx, OverwritePreWorldStateFlag).(bool),
 		UpdateType:             getFlagValue(ctx, UpdateTypeFlag).(string),
 		Validate:               getFlagValue(ctx, ValidateFlag).(bool),
 		ValidateStateHashes:    getFlagValue(ctx, ValidateStateHashesFlag).(bool),
 		ValidateTxState:        getFlagValue(ctx, ValidateTxStateFlag).(bool),
+		TxStateValidation:      getFlagValue(ctx, TxStateValidationFlag).(string),
+		CoinbaseTolerance:      getFlagValue(ctx, CoinbaseToleranceFlag).(string),
+		ValidateAccounting:     getFlagValue(ctx, ValidateBalanceAccountingFlag).(bool),
+		ValidateDeletions:      getFlagValue(ctx, ValidateDeletionsFlag).(bool),
+		ValidateWitness:        getFlagValue(ctx, ValidateWitnessFlag).(bool),
 		ValuesNumber:           getFlagValue(ctx, ValuesNumberFlag).(int64),
 		VmImpl:                 getFlagValue(ctx, VmImplementation).(string),
+		VmTrace:                getFlagValue(ctx, VmTraceFlag).(bool),
 		Workers:                getFlagValue(ctx, WorkersFlag).(int),
+		WorkerPartitioning:     getFlagValue(ctx, WorkerPartitioningFlag).(string),
 		TxGeneratorType:        getFlagValue(ctx, TxGeneratorTypeFlag).([]string),
+		TxFilter:               getFlagValue(ctx, TxFilterFlag).(string),
+		TxGeneratorAccounts:    getFlagValue(ctx, TxGeneratorAccountsFlag).(int),
+		TxGeneratorCreateRate:  getFlagValue(ctx, TxGeneratorCreateRateFlag).(float64),
 	}
 
 	return cfg
@@ -172,6 +257,14 @@ func getFlagValue(ctx *cli.Context, flag interface{}) interface{} {
 			if cmdFlag.Names()[0] == f.Name {
 				return ctx.Bool(f.Name)
 			}
+		case cli.DurationFlag:
+			if cmdFlag.Names()[0] == f.Name {
+				return ctx.Duration(f.Name)
+			}
+		case cli.Float64Flag:
+			if cmdFlag.Names()[0] == f.Name {
+				return ctx.Float64(f.Name)
+			}
 		case cli.StringSliceFlag:
 			if cmdFlag.Names()[0] == f.Name {
 				return ctx.StringSlice(f.Name)
@@ -193,6 +286,10 @@ func getFlagValue(ctx *cli.Context, flag interface{}) interface{} {
 		return f.Value
 	case cli.BoolFlag:
 		return f.Value
+	case cli.DurationFlag:
+		return f.Value
+	case cli.Float64Flag:
+		return f.Value
 	case cli.StringSliceFlag:
 		if f.Value == nil {
 			return []string{}
@@ -52,6 +52,9 @@ func TestGetFlagValue(t *testing.T) {
 				&cli.StringSliceFlag{
 					Name: "stringsliceflag",
 				},
+				&cli.Float64Flag{
+					Name: "float64flag",
+				},
 			},
 		},
 	}
@@ -147,6 +150,18 @@ func TestGetFlagValue(t *testing.T) {
 			flagToTest:    cli.StringSliceFlag{Name: "stringsliceflag"},
 			expectedValue: []string{"value1", "value2"},
 		},
+		{
+			name: "Float64Flag value",
+			setupFlags: func() (*cli.Context, error) {
+				set := flag.NewFlagSet("test", 0)
+				set.Float64("float64flag", 0.25, "")
+				ctx := cli.NewContext(app, set, nil)
+				ctx.Command = app.Commands[0]
+				return ctx, nil
+			},
+			flagToTest:    cli.Float64Flag{Name: "float64flag"},
+			expectedValue: 0.25,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -24,9 +24,26 @@ import (
 var (
 	RpcRecordingFileFlag = cli.PathFlag{
 		Name:    "rpc-recording",
-		Usage:   "Path to source file with recorded API data",
+		Usage:   "Path to source file with recorded API data, or a ws:// or wss:// URL streaming the recording",
 		Aliases: []string{"r"},
 	}
+	RpcFuzzFlag = cli.BoolFlag{
+		Name:  "fuzz",
+		Usage: "execute mutated variants of recorded requests and fail on panics or StateDB errors instead of comparing results; mutations are seeded by --random-seed",
+	}
+	RpcFuzzVariantsFlag = cli.IntFlag{
+		Name:  "fuzz-variants",
+		Usage: "number of mutated variants executed per recorded request in fuzz mode",
+		Value: 4,
+	}
+	RpcCompareLatencyFlag = cli.BoolFlag{
+		Name:  "compare-latency",
+		Usage: "compares the upstream latency recorded with each request to the time of its local execution and reports per-method statistics",
+	}
+	RpcLatencyReportFlag = cli.PathFlag{
+		Name:  "latency-report",
+		Usage: "writes the per-method statistics of --compare-latency into given json file",
+	}
 	ArchiveModeFlag = cli.BoolFlag{
 		Name:  "archive",
 		Usage: "set node type to archival mode. If set, the node keep all the EVM state history; otherwise the state history will be pruned.",
@@ -35,15 +52,43 @@ var (
 		Name:  "archive-query-rate",
 		Usage: "sets the number of queries send to the archive per second, disabled if 0 or negative",
 	}
+	ArchiveQueryBurstFlag = cli.IntFlag{
+		Name:  "archive-query-burst",
+		Usage: "sets the maximum number of archive queries sent at once after idle periods, unbounded if 0",
+	}
+	ArchiveQueryWarmUpFlag = cli.DurationFlag{
+		Name:  "archive-query-warm-up",
+		Usage: "linearly increases the archive query rate from 0 to --archive-query-rate over the given period",
+	}
 	ArchiveMaxQueryAgeFlag = cli.IntFlag{
 		Name:  "archive-max-query-age",
 		Usage: "sets an upper limit for the number of blocks an archive query may be lagging behind the head block",
 		Value: 100_000,
 	}
+	ArchiveCacheSizeFlag = cli.IntFlag{
+		Name:  "archive-cache-size",
+		Usage: "sets the number of archive states kept open for repeated queries of the same block (0 disables the cache)",
+	}
 	ArchiveVariantFlag = cli.StringFlag{
 		Name:  "archive-variant",
 		Usage: "set the archive implementation variant for the selected DB implementation, ignored if not running in archive mode",
 	}
+	BisectFlag = cli.BoolFlag{
+		Name:  "bisect",
+		Usage: "on a failure, re-runs the failing blocks from the last known-good block with transaction validation to locate the first transaction diverging from the recorded post-state",
+	}
+	BisectAFlag = cli.StringFlag{
+		Name:  "bisect-a",
+		Usage: "flags of the first configuration compared by bisection, e.g. \"--evm-impl opera\"",
+	}
+	BisectBFlag = cli.StringFlag{
+		Name:  "bisect-b",
+		Usage: "flags of the second configuration compared by bisection, e.g. \"--evm-impl ethereum\"",
+	}
+	BisectReportFlag = cli.PathFlag{
+		Name:  "bisect-report",
+		Usage: "writes the state differences of the first divergent block or transaction into given json file",
+	}
 	BlockLengthFlag = cli.Uint64Flag{
 		Name:  "block-length",
 		Usage: "defines the number of transactions per block",
@@ -64,6 +109,10 @@ var (
 		Usage: "defines how often (in minutes) will Carmen create checkpoints",
 		Value: 0,
 	}
+	CarmenCheckpointTriggerFlag = cli.PathFlag{
+		Name:  "carmen-checkpoint-trigger",
+		Usage: "path of a file whose creation makes Carmen create a checkpoint after the current block; the file is removed once the checkpoint is created",
+	}
 	CarmenSchemaFlag = cli.IntFlag{
 		Name:  "carmen-schema",
 		Usage: "select the DB schema used by Carmen's current state DB",
@@ -73,6 +122,14 @@ var (
 		Name:  "chainid",
 		Usage: "ChainID for replayer",
 	}
+	ChainConfigFileFlag = cli.PathFlag{
+		Name:  "chain-config-file",
+		Usage: "go-ethereum genesis-style JSON file providing the chain config used instead of the predefined one of --chainid",
+	}
+	ForceChainIDFlag = cli.BoolFlag{
+		Name:  "force-chain-id",
+		Usage: "proceeds even if the chain id set by --chainid differs from the chain id recorded in AidaDb",
+	}
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
 		Usage: "Cache limit for StateDb or Priming",
@@ -104,10 +161,32 @@ var (
 		Usage: "enable hosting of a realtime diagnostic server by providing a port",
 		Value: 0,
 	}
+	OtlpEndpointFlag = cli.StringFlag{
+		Name:  "otlp-endpoint",
+		Usage: "exports traces of the run and its phases to given OTLP/HTTP endpoint (URL or host:port)",
+	}
+	OtlpBlockSamplingFlag = cli.IntFlag{
+		Name:  "otlp-block-sampling",
+		Usage: "records a trace span for every n-th block when --otlp-endpoint is set (0 disables block spans)",
+		Value: 100,
+	}
 	KeepDbFlag = cli.BoolFlag{
 		Name:  "keep-db",
 		Usage: "if set, state-db is not deleted after run",
 	}
+	KillResumeArgsFlag = cli.StringFlag{
+		Name:  "kill-resume-args",
+		Usage: "flags of the substate run which is repeatedly killed and resumed, e.g. \"--aida-db path --db-impl carmen --archive\"",
+	}
+	KillCountFlag = cli.IntFlag{
+		Name:  "kill-count",
+		Usage: "number of times the run is killed before it is allowed to finish",
+		Value: 5,
+	}
+	KillReportFlag = cli.PathFlag{
+		Name:  "kill-report",
+		Usage: "writes the report of the kill and resume test to the given JSON file",
+	}
 	CustomDbNameFlag = cli.StringFlag{
 		Name:  "custom-db-name",
 		Usage: "sets the name of state-db direcotry when --keep-db is enabled",
@@ -130,10 +209,41 @@ var (
 		Usage: "sets nonce range for stochastic simulation",
 		Value: 1000000,
 	}
+	ProviderFlag = cli.StringFlag{
+		Name:  "provider",
+		Usage: "selects the registered provider supplying the executed payload; the default provider of the command is used if not set",
+	}
+	QueryWorkloadFileFlag = cli.PathFlag{
+		Name:  "query-workload-file",
+		Usage: "replays the archive queries of given CSV or JSONL (.jsonl) file, each at its recorded offset from the start of the run",
+	}
+	QueryWorkloadSpeedupFlag = cli.Float64Flag{
+		Name:  "speedup",
+		Usage: "divides the recorded offsets of --query-workload-file by given factor; 2 replays the workload twice as fast",
+		Value: 1,
+	}
 	ProfileFlag = cli.BoolFlag{
 		Name:  "profile",
 		Usage: "enable profiling",
 	}
+	ProfileContractsFlag = cli.BoolFlag{
+		Name:  "profile-contracts",
+		Usage: "attributes the gas used by transactions to their recipient contracts and reports the top consumers at the end of the run",
+	}
+	ProfileContractsTopFlag = cli.IntFlag{
+		Name:  "profile-contracts-top",
+		Usage: "number of contracts reported by --profile-contracts",
+		Value: 20,
+	}
+	ExcludePrecompileCallsFlag = cli.StringFlag{
+		Name:  "exclude-precompile-calls",
+		Usage: "handling of transactions sent to precompiled contracts by --profile-contracts: include, aggregate (into a single precompiles entry) or exclude",
+		Value: "include",
+	}
+	ProfileTxTimingFlag = cli.BoolFlag{
+		Name:  "profile-tx-timing",
+		Usage: "records the execution time of each transaction into table tx_timing of the sqlite3 db set by --profile-sqlite3",
+	}
 	ProfileDepthFlag = cli.IntFlag{
 		Name:  "profile-depth",
 		Usage: "0=interval, 1=interval+block, 2=interval+block+transaction",
@@ -156,6 +266,18 @@ var (
 		Name:  "register-run",
 		Usage: "When enabled, register results/metadata to an external service.",
 	}
+	RegisterExportCsvFlag = cli.StringFlag{
+		Name:  "register-export-csv",
+		Usage: "Additionally export registered metadata and metrics as csv files to given directory (requires --register-run)",
+	}
+	RegisterGasBucketsFlag = cli.StringFlag{
+		Name:  "register-gas-buckets",
+		Usage: "Comma-separated, increasing upper bounds of the registered gas-per-block histogram buckets (empty for defaults)",
+	}
+	RegisterTxBucketsFlag = cli.StringFlag{
+		Name:  "register-tx-buckets",
+		Usage: "Comma-separated, increasing upper bounds of the registered transactions-per-block histogram buckets (empty for defaults)",
+	}
 	OverwriteRunIdFlag = cli.StringFlag{
 		Name:  "overwrite-run-id",
 		Usage: "Use provided run id instead of auto-generating run id",
@@ -164,6 +286,20 @@ var (
 		Name:  "prime-random",
 		Usage: "randomize order of accounts in StateDB priming",
 	}
+	PrimeShuffleWindowFlag = cli.IntFlag{
+		Name:  "priming-shuffle-window",
+		Usage: "maximum number of accounts shuffled together in randomized priming; 0 shuffles all accounts at once",
+		Value: 0,
+	}
+	PipelineDepthFlag = cli.IntFlag{
+		Name:  "pipeline-depth",
+		Usage: "number of blocks prefetched and prepared ahead of the execution; 0 disables pipelining",
+		Value: 0,
+	}
+	LazySubstateDecodingFlag = cli.BoolFlag{
+		Name:  "lazy-substate-decoding",
+		Usage: "forward substates encoded and decode them in the workers right before execution; reduces memory held by buffered substates",
+	}
 	PrimeThresholdFlag = cli.IntFlag{
 		Name:  "prime-threshold",
 		Usage: "set number of accounts written to stateDB before applying pending state updates",
@@ -174,6 +310,11 @@ var (
 		Usage: "Set random seed",
 		Value: -1,
 	}
+	ReplayWorkersFlag = cli.IntFlag{
+		Name:  "replay-workers",
+		Usage: "number of workers of the stochastic replay, each simulating its own blocks with a random generator seeded by random-seed+worker index",
+		Value: 1,
+	}
 	EnableCoverageFlag = cli.BoolFlag{
 		Name:  "enable-coverage",
 		Usage: "Enable coverage-guided fuzzing (requires binary built with -cover)",
@@ -187,9 +328,25 @@ var (
 		Name:  "skip-priming",
 		Usage: "if set, DB priming should be skipped; most useful with the 'memory' DB implementation",
 	}
+	RemapKeyFlag = cli.StringFlag{
+		Name:  "remap-key",
+		Usage: "replays with all addresses remapped by a keyed permutation derived from given key; disables state hash validation",
+	}
+	RemapStorageKeysFlag = cli.BoolFlag{
+		Name:  "remap-storage-keys",
+		Usage: "remaps storage keys as well when --remap-key is set",
+	}
+	SkipFailedTxFlag = cli.BoolFlag{
+		Name:  "skip-failed-tx",
+		Usage: "skips transactions failing the transaction validation and fails the run with a summary of them at the end; cannot be combined with --continue-on-failure",
+	}
+	SkipSanityChecksFlag = cli.BoolFlag{
+		Name:  "skip-sanity-checks",
+		Usage: "disables the always-on checks of sender nonces and balances of replayed transactions",
+	}
 	DeltaTraceFileFlag = cli.StringSliceFlag{
 		Name:    "trace-file",
-		Usage:   "path to a trace file (repeatable)",
+		Usage:   "path to a trace file (repeatable); zstd-compressed files are decompressed on the fly",
 		Aliases: []string{"f"},
 	}
 	DeltaOutputFlag = cli.StringFlag{
@@ -197,6 +354,10 @@ var (
 		Aliases: []string{"o"},
 		Usage:   "write the minimized trace to the given path",
 	}
+	DeltaOutputDirFlag = cli.PathFlag{
+		Name:  "output-dir",
+		Usage: "minimizes each --trace-file independently and writes the minimized traces, their summaries and an index.json into the given directory",
+	}
 	AddressSampleRunsFlag = cli.IntFlag{
 		Name:  "address-sample-runs",
 		Usage: "number of attempts per sampling factor when reducing contracts",
@@ -204,12 +365,29 @@ var (
 	}
 	DeltaTimeoutFlag = cli.DurationFlag{
 		Name:  "timeout",
-		Usage: "overall timeout for the minimization run",
+		Usage: "overall timeout for the minimization run; applies to each trace with --output-dir",
 	}
 	MaxFactorFlag = cli.IntFlag{
 		Name:  "max-factor",
 		Usage: "maximum sampling factor when reducing addresses",
 	}
+	DeltaStrategyFlag = cli.StringFlag{
+		Name:  "strategy",
+		Usage: "comma-separated order of the reduction phases run in each minimization round (addresses, transactions)",
+		Value: "addresses,transactions",
+	}
+	StrictReplayFlag = cli.BoolFlag{
+		Name:  "strict-replay",
+		Usage: "verifies the values returned by read operations against the results recorded with --delta-log-results; traces without recorded results are replayed as usual",
+	}
+	MaxReplayMismatchesFlag = cli.IntFlag{
+		Name:  "max-replay-mismatches",
+		Usage: "number of mismatching read results tolerated by --strict-replay before the replay fails",
+	}
+	CutPointFlag = cli.StringFlag{
+		Name:  "cut-point",
+		Usage: "operation after which the failure is known to occur, given as <block>:<tx>:<op> (use '-' as tx for operations outside any transaction)",
+	}
 	StateDbImplementationFlag = cli.StringFlag{
 		Name:  "db-impl",
 		Usage: "select state DB implementation",
@@ -228,18 +406,49 @@ var (
 		Name:  "db-src-overwrite",
 		Usage: "Modify source db directly",
 	}
+	ResumeFlag = cli.BoolFlag{
+		Name:  "resume",
+		Usage: "continues an interrupted run on the StateDb given by --db-src after its last durable block; requires --archive",
+	}
+	OutputDirFlag = cli.PathFlag{
+		Name:  "output-dir",
+		Usage: "places all artifacts of the run, whose paths are not set explicitly, into <output-dir>/<run-id>",
+	}
 	DbTmpFlag = cli.PathFlag{
 		Name:  "db-tmp",
 		Usage: "sets the temporary directory where to place DB data; uses system default if empty",
 	}
 	StateDbLoggingFlag = cli.PathFlag{
 		Name:  "db-logging",
-		Usage: "sets path to file for db-logging output",
+		Usage: "sets path to file for db-logging output; the output is compressed with zstd if the path ends in .zst",
+	}
+	StateDbLoggingFormatFlag = cli.StringFlag{
+		Name:  "db-logging-format",
+		Usage: "format of the db-logging output (\"text\", \"json\")",
+		Value: "text",
+	}
+	StateDbLoggingFilterFlag = cli.StringFlag{
+		Name:  "db-logging-filter",
+		Usage: "comma-separated list of operations written by db-logging, e.g. GetState,SetState; all operations are written if empty",
 	}
 	DeltaLoggingFlag = cli.PathFlag{
 		Name:  "delta-log",
 		Usage: "sets path to file for delta-debugger compatible DB logs",
 	}
+	DeltaLoggingResultsFlag = cli.BoolFlag{
+		Name:  "delta-log-results",
+		Usage: "additionally records the values returned by Exist, GetBalance, GetNonce, GetState and GetCodeHash into the delta-log, so that they can be verified with --strict-replay",
+	}
+	LogQueueSizeFlag = cli.IntFlag{
+		Name:  "log-queue-size",
+		Usage: "number of records buffered for the asynchronous writers of the error-log and the delta-log",
+		Value: 10_000,
+	}
+	LogOverflowFlag = cli.StringFlag{
+		Name:  "log-overflow",
+		Usage: "behavior once the queue of the error-log or the delta-log is full; options: \"block\" (wait for the writer), \"drop\" (discard and count the record)",
+		Value: "block",
+	}
 	ShadowDb = cli.BoolFlag{
 		Name:  "shadow-db",
 		Usage: "use this flag when using an existing ShadowDb",
@@ -255,10 +464,22 @@ var (
 		Usage: "select a state DB variant to shadow the prime DB implementation",
 		Value: "",
 	}
+	ShadowRetryFlag = cli.IntFlag{
+		Name:  "shadow-retry",
+		Usage: "rolls back and re-executes a block up to N times if prime and shadow DB diverge; fails only if the divergence reproduces",
+	}
+	ScanCachePolicyFlag = cli.StringFlag{
+		Name:  "scan-cache-policy",
+		Usage: "page cache policy for sequential scans of the source db (\"keep\", \"drop-behind\", \"direct\")",
+		Value: "keep",
+	}
+	SkipListFlag = cli.PathFlag{
+		Name:  "skip-list",
+		Usage: "skips non-replayable transactions listed in given file (<block> <tx> <reason> per line) and applies their recorded output alloc instead",
+	}
 	SubstateEncodingFlag = cli.StringFlag{
 		Name:  "substate-encoding",
-		Usage: "select encoding when reading substate from disk: rlp (default) or protobuf",
-		Value: "protobuf",
+		Usage: "select encoding when reading substate from disk: rlp or protobuf; detected from the stored substates if not set",
 	}
 	TraceFlag = cli.BoolFlag{
 		Name:  "trace",
@@ -279,7 +500,16 @@ var (
 	}
 	UpdateDbFlag = cli.PathFlag{
 		Name:  "update-db",
-		Usage: "set update-set database directory",
+		Usage: "set update-set database directory or http(s) URL of an update-set layout exported by util-updateset export",
+	}
+	UpdateCacheDirFlag = cli.PathFlag{
+		Name:  "update-cache-dir",
+		Usage: "directory caching update sets fetched from a remote --update-db (default: user cache directory)",
+	}
+	UpdateCacheSizeFlag = cli.IntFlag{
+		Name:  "update-cache-size",
+		Usage: "maximum size of the cached update sets in MB; unlimited if 0",
+		Value: 50_000,
 	}
 	UpdateTypeFlag = cli.StringFlag{
 		Name:  "update-type",
@@ -298,6 +528,32 @@ var (
 		Name:  "validate-tx",
 		Usage: "enables validation after transaction processing",
 	}
+	TxStateValidationFlag = cli.StringFlag{
+		Name:  "validate-tx-state",
+		Usage: "world-state comparison of transaction validation: strict compares all accounts exactly, relaxed compares only the balance delta of the fee recipient",
+		Value: "strict",
+	}
+	CoinbaseToleranceFlag = cli.StringFlag{
+		Name:  "validate-tx-coinbase-tolerance",
+		Usage: "tolerated difference of the fee recipient's balance delta in wei with --validate-tx-state=relaxed",
+		Value: "0",
+	}
+	ValidateBalanceAccountingFlag = cli.BoolFlag{
+		Name:  "validate-balance-accounting",
+		Usage: "enables validation that the net balance change of each block matches the burned fees",
+	}
+	ValidateWitnessFlag = cli.BoolFlag{
+		Name:  "validate-witness",
+		Usage: "enables validation that the state witness covers all accounts and storage slots of the input alloc of each transaction",
+	}
+	ValidateDeletionsFlag = cli.BoolFlag{
+		Name:  "validate-deletions",
+		Usage: "enables validation that the accounts self-destructed in each block match the recorded deleted accounts",
+	}
+	DeepOutputCompareFlag = cli.BoolFlag{
+		Name:  "deep-output-compare",
+		Usage: "compares the post-alloc of each transaction with the recorded output alloc slot by slot and reports the first divergence",
+	}
 	EvmImplementation = cli.StringFlag{
 		Name:  "evm-impl",
 		Usage: "select EVM implementation",
@@ -308,10 +564,27 @@ var (
 		Usage: "select VM implementation",
 		Value: "geth",
 	}
+	CrossCheckEvmFlag = cli.StringFlag{
+		Name:  "cross-check-evm",
+		Usage: "executes each transaction with --evm-impl and the given EVM implementation (<evm-impl>[:<vm-impl>]) on in-memory copies of its input alloc and reports diverging results",
+	}
+	CrossCheckDirFlag = cli.PathFlag{
+		Name:  "cross-check-dir",
+		Usage: "directory receiving the execution summaries of transactions diverging in --cross-check-evm mode",
+		Value: "cross-check",
+	}
+	VmTraceFlag = cli.BoolFlag{
+		Name:  "vm-trace",
+		Usage: "writes the structured logs of both executions of transactions diverging in --cross-check-evm mode",
+	}
+	ListVmsFlag = cli.BoolFlag{
+		Name:  "list-vms",
+		Usage: "lists the implementations accepted by --evm-impl and --vm-impl in this build and exits",
+	}
 	MaxNumTransactionsFlag = cli.IntFlag{
 		Name:  "max-tx",
-		Usage: "limit the maximum number of processed transactions, default: unlimited",
-		Value: -1,
+		Usage: "stops the run after the given number of executed transactions, 0 = unlimited",
+		Value: 0,
 	}
 	OutputFlag = cli.PathFlag{
 		Name:  "output",
@@ -328,6 +601,11 @@ var (
 		Usage: "delete source databases while merging into one database",
 		Value: false,
 	}
+	CloneWorkersFlag = cli.IntFlag{
+		Name:  "clone-workers",
+		Usage: "number of workers copying disjoint block ranges of substates, update-sets and deleted accounts in parallel",
+		Value: 1,
+	}
 	CompactDbFlag = cli.BoolFlag{
 		Name:  "compact",
 		Usage: "compact target database",
@@ -335,7 +613,7 @@ var (
 	}
 	AidaDbFlag = cli.PathFlag{
 		Name:     "aida-db",
-		Usage:    "set substate, updateset and deleted accounts directory",
+		Usage:    "set substate, updateset and deleted accounts directory; replay tools accept a comma-separated list of aida-db slices holding adjacent block ranges",
 		Required: true,
 	}
 	ContractNumberFlag = cli.Int64Flag{
@@ -403,8 +681,7 @@ var (
 	}
 	ProfilingDbNameFlag = cli.StringFlag{
 		Name:  "profiling-db-name",
-		Usage: "set a database name for storing micro-profiling results",
-		Value: "./profiling.db",
+		Usage: "set a database name for storing micro-profiling results, printed to stdout if not set",
 	}
 	ChannelBufferSizeFlag = cli.IntFlag{
 		Name:  "buffer-size",
@@ -416,6 +693,10 @@ var (
 		Usage: "buffer size for holding update set in MB",
 		Value: 1_000_000,
 	}
+	UpdateBufferStrictFlag = cli.BoolFlag{
+		Name:  "update-buffer-strict",
+		Usage: "flushes the update set buffer eagerly while priming, so it does not exceed --update-buffer-size by more than a single account",
+	}
 	TargetEpochFlag = cli.Uint64Flag{
 		Name:    "target-epoch",
 		Aliases: []string{"epoch"},
@@ -441,37 +722,110 @@ var (
 		Name:  "no-heartbeat-logging",
 		Usage: "disables heartbeat logging",
 	}
+	HeartbeatFieldsFlag = cli.StringFlag{
+		Name:  "heartbeat-fields",
+		Usage: "comma-separated list of key=value fields appended to heartbeat logs (statedb_bytes, statedb_breakdown, goroutines, heap_alloc, num_gc); statedb_breakdown requires --memory-breakdown",
+		Value: "statedb_bytes,statedb_breakdown,goroutines,heap_alloc,num_gc",
+	}
 	TrackProgressFlag = cli.BoolFlag{
 		Name:  "track-progress",
 		Usage: "enables track progress logging",
 	}
+	TrackIoFlag = cli.BoolFlag{
+		Name:  "track-io",
+		Usage: "reports read/write rates of the process and IOPS of the state DB device with each progress report (linux only)",
+	}
 	TrackerGranularityFlag = cli.IntFlag{
 		Name:  "tracker-granularity",
 		Usage: "chooses how often will tracker report achieved block",
 		Value: 100_000,
 	}
+	TrackerEtaWindowFlag = cli.IntFlag{
+		Name:  "tracker-eta-window",
+		Usage: "number of recent progress reports used to compute the recent rates and the estimated time of arrival",
+		Value: 10,
+	}
+	MinFreeDiskGibFlag = cli.Float64Flag{
+		Name:  "min-free-disk-gib",
+		Usage: "aborts the run once the free space of the temporary directory drops below given GiB; checked every tracker-granularity blocks, disabled if 0",
+	}
+	StallTimeoutFlag = cli.DurationFlag{
+		Name:  "stall-timeout",
+		Usage: "dumps diagnostics if no transaction completes within given duration (e.g. 30m); disabled if 0",
+	}
+	StallActionFlag = cli.StringFlag{
+		Name:  "stall-action",
+		Usage: "action taken once a stall is detected; options: \"log\" (continue watching), \"abort\"",
+		Value: "log",
+	}
 	ValidateStateHashesFlag = cli.BoolFlag{
 		Name:  "validate-state-hash",
 		Usage: "enables state hash validation",
 	}
+	ValidateStateHashesSkipFirstFlag = cli.IntFlag{
+		Name:  "validate-state-hash-skip-first",
+		Usage: "number of blocks after priming whose state hashes are not validated, since priming may produce a different but logically equal state representation",
+		Value: 1,
+	}
+	ValidateStateHashesIntervalFlag = cli.IntFlag{
+		Name:  "validate-state-hash-interval",
+		Usage: "validates only the state hashes of blocks divisible by the given interval",
+		Value: 1,
+	}
 	ProfileBlocksFlag = cli.BoolFlag{
 		Name:  "profile-blocks",
 		Usage: "enables block profiling",
 	}
+	RunBundleFlag = cli.PathFlag{
+		Name:  "run-bundle",
+		Usage: "writes summary, configuration and reports of the run into given tar.zst bundle",
+	}
+	AccessListStatsFlag = cli.PathFlag{
+		Name:  "access-list-stats",
+		Usage: "writes per-transaction access-list coverage into given csv file and reports it per profiling interval",
+	}
+	CompareSchemasFlag = cli.StringFlag{
+		Name:  "compare-schemas",
+		Usage: "runs two Carmen schemas side by side (e.g. 4,5) and reports their size and time differences",
+	}
+	CompareSchemasReportFlag = cli.PathFlag{
+		Name:  "compare-schemas-report",
+		Usage: "writes the report of --compare-schemas into given json file",
+	}
 	ProfileDBFlag = cli.PathFlag{
 		Name:  "profile-db",
 		Usage: "defines path to profile-db",
 		Value: "/var/opera/Aida/profile.db",
 	}
+	ExportGenesisFlag = cli.PathFlag{
+		Name:  "export-genesis",
+		Usage: "exports the final state of the run into given genesis json file accepted by the Sonic client",
+	}
 	ErrorLoggingFlag = cli.PathFlag{
 		Name:  "err-logging",
 		Usage: "defines path to error-log-file where any PROCESSING error is recorded",
 	}
+	ErrorLoggingFormatFlag = cli.StringFlag{
+		Name:  "err-logging-format",
+		Usage: "format of the error-log-file; \"plain\" or \"jsonl\" (one JSON record per error); selected by the file extension .jsonl if not set",
+	}
+	PauseOnFailureFlag = cli.BoolFlag{
+		Name:  "pause-on-failure",
+		Usage: "opens an inspection console on the StateDb of the failing block before the run terminates",
+	}
 	ForkFlag = cli.StringFlag{
 		Name:  "fork",
 		Usage: "defines a fork to get executed by the eth-tests (\"all\", \"osaka\", \"prague\", \"cancun\", \"shanghai\", \"paris\", \"bellatrix\", \"grayglacier\", \"arrowglacier\", \"altair\", \"london\", \"berlin\", \"istanbul\", \"muirglacier\")",
 		Value: "All",
 	}
+	ForksFlag = cli.StringSliceFlag{
+		Name:  "forks",
+		Usage: "comma separated list of forks to get executed by the eth-tests (e.g. \"cancun,prague\"); overrides --fork if set",
+	}
+	ReportFileFlag = cli.PathFlag{
+		Name:  "report-file",
+		Usage: "writes the per-fork and per-suite results of the eth-tests to the given JSON file",
+	}
 	DbComponentFlag = cli.StringFlag{
 		Name:     "db-component",
 		Usage:    "db component to be used (\"all\", \"substate\", \"delete\", \"update\", \"state-hash\", \"block-hash\", \"exception\")",
@@ -479,13 +833,32 @@ var (
 	}
 	TxGeneratorTypeFlag = cli.StringSliceFlag{
 		Name:  "tx-type",
-		Usage: "list of tx generator application type (\"all\" | <\"erc20\", \"counter\", \"store\", \"uniswap\">)",
+		Usage: "list of tx generator application types; \"all\" or any of \"erc20\" (transfers and approvals of an ERC-20 token), \"create\" (deployments of small contracts), \"counter\", \"store\", \"uniswap\"",
 		Value: cli.NewStringSlice("all"),
 	}
+	TxFilterFlag = cli.StringFlag{
+		Name:  "tx-filter",
+		Usage: "executes only the transactions selected by given comma separated list of transaction hashes and <block>:<tx> pairs",
+	}
+	TxGeneratorAccountsFlag = cli.IntFlag{
+		Name:  "tx-accounts",
+		Usage: "number of accounts sending transactions of the \"erc20\" and \"create\" tx generators",
+		Value: 10,
+	}
+	TxGeneratorCreateRateFlag = cli.Float64Flag{
+		Name:  "tx-create-rate",
+		Usage: "fraction of transactions of the \"create\" tx generator deploying a new contract, the others call a deployed one; within [0, 1]",
+		Value: 0.5,
+	}
 	WorkersFlag = cli.IntFlag{
 		Name:    "workers",
 		Aliases: []string{"w"},
 		Usage:   "determines number of workers",
 		Value:   4,
 	}
+	WorkerPartitioningFlag = cli.StringFlag{
+		Name:  "worker-partitioning",
+		Usage: "distribution of blocks among workers; options: \"interleaved\" (next idle worker), \"contiguous\" (one contiguous sub-range per worker)",
+		Value: "interleaved",
+	}
 )
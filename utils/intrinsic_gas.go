@@ -0,0 +1,137 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ErrIntrinsicGasOverflow is returned if the intrinsic gas of a message does not fit into uint64.
+var ErrIntrinsicGasOverflow = errors.New("intrinsic gas overflows uint64")
+
+// IntrinsicGas is the gas a message costs regardless of the code it executes.
+type IntrinsicGas struct {
+	Gas   uint64 // charged before the execution starts
+	Floor uint64 // minimal gas used by the message according to EIP-7623 since Prague, 0 before
+}
+
+// Required returns the lowest gas limit of a valid message.
+func (g IntrinsicGas) Required() uint64 {
+	return max(g.Gas, g.Floor)
+}
+
+// GetRules returns the fork rules in effect in the given block environment.
+func GetRules(chainCfg *params.ChainConfig, env txcontext.BlockEnvironment) params.Rules {
+	return chainCfg.Rules(new(big.Int).SetUint64(env.GetNumber()), env.GetRandom() != nil, env.GetTimestamp())
+}
+
+// ComputeIntrinsicGas computes the intrinsic gas of the message under the given fork rules.
+// It covers the base cost of calls and contract creations, the calldata cost, the init code
+// word cost (EIP-3860), access lists (EIP-2930), set-code authorizations (EIP-7702) and the
+// calldata floor (EIP-7623).
+func ComputeIntrinsicGas(msg *core.Message, rules params.Rules) (IntrinsicGas, error) {
+	var (
+		creation = msg.To == nil
+		res      IntrinsicGas
+	)
+
+	res.Gas = params.TxGas
+	if creation && rules.IsHomestead {
+		res.Gas = params.TxGasContractCreation
+	}
+
+	var zeros, nonZeros uint64
+	for _, b := range msg.Data {
+		if b == 0 {
+			zeros++
+		} else {
+			nonZeros++
+		}
+	}
+	nonZeroGas := params.TxDataNonZeroGasFrontier
+	if rules.IsIstanbul {
+		nonZeroGas = params.TxDataNonZeroGasEIP2028
+	}
+	var err error
+	if res.Gas, err = addProduct(res.Gas, nonZeros, nonZeroGas); err != nil {
+		return IntrinsicGas{}, err
+	}
+	if res.Gas, err = addProduct(res.Gas, zeros, params.TxDataZeroGas); err != nil {
+		return IntrinsicGas{}, err
+	}
+	if creation && rules.IsShanghai {
+		words := (uint64(len(msg.Data)) + 31) / 32
+		if res.Gas, err = addProduct(res.Gas, words, params.InitCodeWordGas); err != nil {
+			return IntrinsicGas{}, err
+		}
+	}
+
+	var keys uint64
+	for _, tuple := range msg.AccessList {
+		keys += uint64(len(tuple.StorageKeys))
+	}
+	if res.Gas, err = addProduct(res.Gas, uint64(len(msg.AccessList)), params.TxAccessListAddressGas); err != nil {
+		return IntrinsicGas{}, err
+	}
+	if res.Gas, err = addProduct(res.Gas, keys, params.TxAccessListStorageKeyGas); err != nil {
+		return IntrinsicGas{}, err
+	}
+	if res.Gas, err = addProduct(res.Gas, uint64(len(msg.SetCodeAuthorizations)), params.CallNewAccountGas); err != nil {
+		return IntrinsicGas{}, err
+	}
+
+	if rules.IsPrague {
+		tokens, err := addProduct(zeros, nonZeros, params.TxTokenPerNonZeroByte)
+		if err != nil {
+			return IntrinsicGas{}, err
+		}
+		if res.Floor, err = addProduct(params.TxGas, tokens, params.TxCostFloorPerToken); err != nil {
+			return IntrinsicGas{}, err
+		}
+	}
+	return res, nil
+}
+
+// CheckIntrinsicGas returns an error if the gas limit of the message is below its intrinsic gas.
+func CheckIntrinsicGas(msg *core.Message, rules params.Rules) error {
+	gas, err := ComputeIntrinsicGas(msg, rules)
+	if err != nil {
+		return err
+	}
+	if msg.GasLimit < gas.Gas {
+		return fmt.Errorf("gas limit %d is below intrinsic gas %d", msg.GasLimit, gas.Gas)
+	}
+	if msg.GasLimit < gas.Floor {
+		return fmt.Errorf("gas limit %d is below calldata floor gas %d", msg.GasLimit, gas.Floor)
+	}
+	return nil
+}
+
+// addProduct returns sum + a*b or an error if the result overflows.
+func addProduct(sum, a, b uint64) (uint64, error) {
+	if b != 0 && a > (math.MaxUint64-sum)/b {
+		return 0, ErrIntrinsicGasOverflow
+	}
+	return sum + a*b, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/aida/txcontext"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestComputeIntrinsicGas_CoversAllCostComponents(t *testing.T) {
+	var (
+		frontier  = params.Rules{}
+		homestead = params.Rules{IsHomestead: true}
+		istanbul  = params.Rules{IsHomestead: true, IsIstanbul: true}
+		shanghai  = params.Rules{IsHomestead: true, IsIstanbul: true, IsBerlin: true, IsShanghai: true}
+		prague    = params.Rules{IsHomestead: true, IsIstanbul: true, IsBerlin: true, IsShanghai: true, IsPrague: true}
+		recipient = &common.Address{1}
+	)
+	accessList := types.AccessList{
+		{Address: common.Address{1}, StorageKeys: []common.Hash{{1}, {2}}},
+		{Address: common.Address{2}, StorageKeys: []common.Hash{{3}}},
+	}
+	nonZeroCode := make([]byte, 33)
+	for i := range nonZeroCode {
+		nonZeroCode[i] = 1
+	}
+
+	tests := map[string]struct {
+		msg   core.Message
+		rules params.Rules
+		want  IntrinsicGas
+	}{
+		"transfer":                      {core.Message{To: recipient}, frontier, IntrinsicGas{Gas: 21_000}},
+		"transfer in prague":            {core.Message{To: recipient}, prague, IntrinsicGas{Gas: 21_000, Floor: 21_000}},
+		"calldata in frontier":          {core.Message{To: recipient, Data: []byte{0, 1}}, frontier, IntrinsicGas{Gas: 21_000 + 4 + 68}},
+		"calldata in istanbul":          {core.Message{To: recipient, Data: []byte{0, 1}}, istanbul, IntrinsicGas{Gas: 21_000 + 4 + 16}},
+		"calldata floor in prague":      {core.Message{To: recipient, Data: []byte{0, 1}}, prague, IntrinsicGas{Gas: 21_000 + 4 + 16, Floor: 21_000 + (1+4)*10}},
+		"creation in frontier":          {core.Message{}, frontier, IntrinsicGas{Gas: 21_000}},
+		"creation in homestead":         {core.Message{}, homestead, IntrinsicGas{Gas: 53_000}},
+		"init code before shanghai":     {core.Message{Data: nonZeroCode}, istanbul, IntrinsicGas{Gas: 53_000 + 33*16}},
+		"init code words in shanghai":   {core.Message{Data: nonZeroCode}, shanghai, IntrinsicGas{Gas: 53_000 + 33*16 + 2*2}},
+		"init code words not for calls": {core.Message{To: recipient, Data: nonZeroCode}, shanghai, IntrinsicGas{Gas: 21_000 + 33*16}},
+		"access list":                   {core.Message{To: recipient, AccessList: accessList}, shanghai, IntrinsicGas{Gas: 21_000 + 2*2400 + 3*1900}},
+		"authorizations":                {core.Message{To: recipient, SetCodeAuthorizations: make([]types.SetCodeAuthorization, 2)}, prague, IntrinsicGas{Gas: 21_000 + 2*25_000, Floor: 21_000}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ComputeIntrinsicGas(&test.msg, test.rules)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestIntrinsicGas_RequiredIsMaximumOfGasAndFloor(t *testing.T) {
+	assert.Equal(t, uint64(30), IntrinsicGas{Gas: 30, Floor: 20}.Required())
+	assert.Equal(t, uint64(40), IntrinsicGas{Gas: 30, Floor: 40}.Required())
+}
+
+func TestCheckIntrinsicGas_FlagsGasLimitBelowIntrinsicGas(t *testing.T) {
+	prague := params.Rules{IsHomestead: true, IsIstanbul: true, IsPrague: true}
+	data := []byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1} // gas 21160, floor 21400
+
+	tests := map[string]struct {
+		gasLimit uint64
+		want     string
+	}{
+		"below intrinsic gas": {21_159, "gas limit 21159 is below intrinsic gas 21160"},
+		"below floor":         {21_399, "gas limit 21399 is below calldata floor gas 21400"},
+		"sufficient":          {21_400, ""},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			msg := &core.Message{To: &common.Address{1}, Data: data, GasLimit: test.gasLimit}
+			err := CheckIntrinsicGas(msg, prague)
+			if test.want == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, test.want)
+		})
+	}
+}
+
+func TestAddProduct_DetectsOverflow(t *testing.T) {
+	sum, err := addProduct(1, 2, 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), sum)
+
+	_, err = addProduct(math.MaxUint64-5, 2, 3)
+	require.ErrorIs(t, err, ErrIntrinsicGasOverflow)
+}
+
+func TestGetRules_UsesBlockAndTimeOfEnvironment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	env := txcontext.NewMockBlockEnvironment(ctrl)
+	env.EXPECT().GetNumber().Return(uint64(params.MainnetChainConfig.IstanbulBlock.Uint64()))
+	env.EXPECT().GetRandom().Return(nil)
+	env.EXPECT().GetTimestamp().Return(uint64(0))
+
+	rules := GetRules(params.MainnetChainConfig, env)
+	assert.True(t, rules.IsIstanbul)
+	assert.False(t, rules.IsBerlin)
+	assert.Zero(t, rules.ChainID.Cmp(big.NewInt(1)))
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IoCounters are cumulative I/O counters of the process and of the block device holding a directory.
+type IoCounters struct {
+	ReadBytes    uint64 // bytes fetched from the storage by the process
+	WrittenBytes uint64 // bytes sent to the storage by the process
+	HasDevice    bool   // false if the directory is not placed on a block device (e.g. tmpfs)
+	DeviceReads  uint64 // completed read operations of the device
+	DeviceWrites uint64 // completed write operations of the device
+}
+
+// Sub returns the difference of the counters since the given older counters.
+func (c IoCounters) Sub(older IoCounters) IoCounters {
+	return IoCounters{
+		ReadBytes:    c.ReadBytes - older.ReadBytes,
+		WrittenBytes: c.WrittenBytes - older.WrittenBytes,
+		HasDevice:    c.HasDevice && older.HasDevice,
+		DeviceReads:  c.DeviceReads - older.DeviceReads,
+		DeviceWrites: c.DeviceWrites - older.DeviceWrites,
+	}
+}
+
+// parseProcessIo parses the content of /proc/<pid>/io.
+func parseProcessIo(data []byte, counters *IoCounters) error {
+	found := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		var target *uint64
+		switch key {
+		case "read_bytes":
+			target = &counters.ReadBytes
+		case "write_bytes":
+			target = &counters.WrittenBytes
+		default:
+			continue
+		}
+		v, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value of %v; %w", key, err)
+		}
+		*target = v
+		found++
+	}
+	if found != 2 {
+		return fmt.Errorf("read_bytes or write_bytes is missing")
+	}
+	return nil
+}
+
+// parseBlockDeviceStat parses the content of /sys/dev/block/<major>:<minor>/stat.
+func parseBlockDeviceStat(data []byte, counters *IoCounters) error {
+	fields := strings.Fields(string(data))
+	if len(fields) < 7 {
+		return fmt.Errorf("expected at least 7 fields, got %d", len(fields))
+	}
+	values := make([]uint64, 7)
+	for i := range values {
+		v, err := strconv.ParseUint(fields[i], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid field %d; %w", i, err)
+		}
+		values[i] = v
+	}
+	// fields 0 and 4 are the numbers of completed reads and writes
+	counters.DeviceReads = values[0]
+	counters.DeviceWrites = values[4]
+	counters.HasDevice = true
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build linux
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// IoStatsSupported is true if I/O counters can be read on this platform.
+const IoStatsSupported = true
+
+// ReadIoCounters returns the I/O counters of the process and of the block device holding
+// given directory. Device counters are omitted if dir is empty or is not placed on a block device.
+func ReadIoCounters(dir string) (IoCounters, error) {
+	var counters IoCounters
+	data, err := os.ReadFile("/proc/self/io")
+	if err != nil {
+		return counters, fmt.Errorf("cannot read process i/o counters; %w", err)
+	}
+	if err = parseProcessIo(data, &counters); err != nil {
+		return counters, fmt.Errorf("cannot parse process i/o counters; %w", err)
+	}
+	if dir == "" {
+		return counters, nil
+	}
+
+	var stat unix.Stat_t
+	if err = unix.Stat(dir, &stat); err != nil {
+		return counters, fmt.Errorf("cannot stat %v; %w", dir, err)
+	}
+	dev := uint64(stat.Dev)
+	path := fmt.Sprintf("/sys/dev/block/%d:%d/stat", unix.Major(dev), unix.Minor(dev))
+	data, err = os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// virtual file systems are not backed by a block device
+		return counters, nil
+	}
+	if err != nil {
+		return counters, fmt.Errorf("cannot read device i/o counters; %w", err)
+	}
+	if err = parseBlockDeviceStat(data, &counters); err != nil {
+		return counters, fmt.Errorf("cannot parse device i/o counters of %v; %w", path, err)
+	}
+	return counters, nil
+}
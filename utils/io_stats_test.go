@@ -0,0 +1,71 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcessIo_ReadsStorageBytes(t *testing.T) {
+	data := []byte("rchar: 100\nwchar: 200\nsyscr: 3\nsyscw: 4\nread_bytes: 4096\nwrite_bytes: 8192\ncancelled_write_bytes: 0\n")
+	var counters IoCounters
+	require.NoError(t, parseProcessIo(data, &counters))
+	assert.Equal(t, uint64(4096), counters.ReadBytes)
+	assert.Equal(t, uint64(8192), counters.WrittenBytes)
+}
+
+func TestParseProcessIo_RejectsIncompleteContent(t *testing.T) {
+	var counters IoCounters
+	require.ErrorContains(t, parseProcessIo([]byte("read_bytes: 1\n"), &counters), "missing")
+	require.ErrorContains(t, parseProcessIo([]byte("read_bytes: x\nwrite_bytes: 1\n"), &counters), "invalid value of read_bytes")
+}
+
+func TestParseBlockDeviceStat_ReadsCompletedOperations(t *testing.T) {
+	data := []byte("    1200      10    20000     300      450      20    9000     400        0     500     700\n")
+	var counters IoCounters
+	require.NoError(t, parseBlockDeviceStat(data, &counters))
+	assert.True(t, counters.HasDevice)
+	assert.Equal(t, uint64(1200), counters.DeviceReads)
+	assert.Equal(t, uint64(450), counters.DeviceWrites)
+}
+
+func TestParseBlockDeviceStat_RejectsInvalidContent(t *testing.T) {
+	var counters IoCounters
+	require.ErrorContains(t, parseBlockDeviceStat([]byte("1 2 3"), &counters), "expected at least 7 fields")
+	require.ErrorContains(t, parseBlockDeviceStat([]byte("1 2 3 4 x 6 7"), &counters), "invalid field 4")
+	assert.False(t, counters.HasDevice)
+}
+
+func TestIoCounters_Sub(t *testing.T) {
+	older := IoCounters{ReadBytes: 1, WrittenBytes: 2, HasDevice: true, DeviceReads: 3, DeviceWrites: 4}
+	newer := IoCounters{ReadBytes: 11, WrittenBytes: 22, HasDevice: true, DeviceReads: 33, DeviceWrites: 44}
+	assert.Equal(t, IoCounters{ReadBytes: 10, WrittenBytes: 20, HasDevice: true, DeviceReads: 30, DeviceWrites: 40}, newer.Sub(older))
+
+	older.HasDevice = false
+	assert.False(t, newer.Sub(older).HasDevice)
+}
+
+func TestReadIoCounters_ReadsProcessCounters(t *testing.T) {
+	if !IoStatsSupported {
+		t.Skip("i/o counters are not supported on this platform")
+	}
+	_, err := ReadIoCounters(t.TempDir())
+	require.NoError(t, err)
+}
@@ -53,6 +53,7 @@ const (
 	TimestampPrefix         = db.MetadataPrefix + "ti"
 	DbHashPrefix            = db.MetadataPrefix + "md"
 	HasStateHashPatchPrefix = db.MetadataPrefix + "sh"
+	SubstateEncodingPrefix  = db.MetadataPrefix + "se"
 )
 
 // merge is determined by what are we merging
@@ -1135,6 +1136,29 @@ func (md *AidaDbMetadata) SetHasHashPatch() error {
 	return md.Db.Put([]byte(HasStateHashPatchPrefix), []byte{1})
 }
 
+// SetSubstateEncoding records the encoding of all substates stored in the AidaDb.
+func (md *AidaDbMetadata) SetSubstateEncoding(encoding db.SubstateEncodingSchema) error {
+	if err := md.Db.Put([]byte(SubstateEncodingPrefix), []byte(encoding)); err != nil {
+		return fmt.Errorf("cannot put substate encoding; %w", err)
+	}
+	md.log.Info("METADATA: Substate encoding saved successfully")
+	return nil
+}
+
+// GetSubstateEncoding returns the recorded encoding of substates, or an empty
+// string if the AidaDb does not record it.
+func (md *AidaDbMetadata) GetSubstateEncoding() db.SubstateEncodingSchema {
+	encoding, err := md.Db.Get([]byte(SubstateEncodingPrefix))
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return ""
+		}
+		md.log.Criticalf("cannot get substate encoding from metadata; %v", err)
+		return ""
+	}
+	return db.SubstateEncodingSchema(encoding)
+}
+
 func (md *AidaDbMetadata) SetUpdatesetInterval(val uint64) error {
 	byteInterval := make([]byte, 8)
 	binary.BigEndian.PutUint64(byteInterval, val)
@@ -921,6 +921,36 @@ func TestAidaDbMetadata_SetUpdatesetInterval(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestAidaDbMetadata_SetSubstateEncoding(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDb := db.NewMockBaseDB(ctrl)
+	md := NewAidaDbMetadata(mockDb, "ERROR")
+
+	mockDb.EXPECT().Put([]byte(SubstateEncodingPrefix), []byte(db.ProtobufEncodingSchema)).Return(nil)
+	err := md.SetSubstateEncoding(db.ProtobufEncodingSchema)
+	assert.NoError(t, err)
+
+	mockDb.EXPECT().Put(gomock.Any(), gomock.Any()).Return(errors.New("mock error"))
+	err = md.SetSubstateEncoding(db.ProtobufEncodingSchema)
+	assert.Error(t, err)
+}
+
+func TestAidaDbMetadata_GetSubstateEncoding(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDb := db.NewMockBaseDB(ctrl)
+	md := NewAidaDbMetadata(mockDb, "ERROR")
+
+	mockDb.EXPECT().Get([]byte(SubstateEncodingPrefix)).Return([]byte(db.RLPEncodingSchema), nil)
+	assert.Equal(t, db.RLPEncodingSchema, md.GetSubstateEncoding())
+
+	mockDb.EXPECT().Get([]byte(SubstateEncodingPrefix)).Return(nil, leveldb.ErrNotFound)
+	assert.Equal(t, db.SubstateEncodingSchema(""), md.GetSubstateEncoding())
+}
+
 func TestAidaDbMetadata_SetUpdatesetSize(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
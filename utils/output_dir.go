@@ -0,0 +1,165 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// PathToRunSummary is the name of the file describing a run scoped by --output-dir.
+const PathToRunSummary = "run_summary.json"
+
+// RunSummary records the identity of a run and the effective location of its artifacts.
+type RunSummary struct {
+	RunId     string            `json:"runId"`              // id of the run, shared with the registered run if any
+	RunDir    string            `json:"runDir"`             // directory containing all scoped artifacts
+	StartTime string            `json:"startTime"`          // time when the run was configured
+	Artifacts map[string]string `json:"artifacts"`          // flag name -> effective path of the artifact
+	Manifest  *RunManifest      `json:"manifest,omitempty"` // fingerprints of the inputs, added at the start of the run
+}
+
+// setOutputDir scopes all artifact paths, which were not set explicitly, under
+// <output-dir>/<run-id>. The run id is taken from --overwrite-run-id if provided,
+// otherwise a new one is generated and, if the run is registered, shared with it.
+func (cc *configContext) setOutputDir() error {
+	cfg := cc.cfg
+	if cfg.OutputDir == "" {
+		return nil
+	}
+
+	if cfg.RunId == "" {
+		cfg.RunId = cfg.OverwriteRunId
+	}
+	if cfg.RunId == "" {
+		cfg.RunId = makeRunId(time.Now())
+	}
+	// registered run must be identifiable by the same id as its artifacts
+	if cfg.RegisterRun != "" && cfg.OverwriteRunId == "" {
+		cfg.OverwriteRunId = cfg.RunId
+	}
+
+	runDir := filepath.Join(cfg.OutputDir, cfg.RunId)
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		return fmt.Errorf("cannot create run directory %v; %w", runDir, err)
+	}
+
+	summary := RunSummary{
+		RunId:     cfg.RunId,
+		RunDir:    runDir,
+		StartTime: time.Now().UTC().Format(time.UnixDate),
+		Artifacts: make(map[string]string),
+	}
+
+	// scope sets the path of an artifact unless its flag was set explicitly
+	scope := func(flag string, value *string, name string) {
+		if !cc.isSet(flag) {
+			*value = filepath.Join(runDir, name)
+		}
+		summary.Artifacts[flag] = *value
+	}
+
+	scope(DbTmpFlag.Name, &cfg.DbTmp, "state-db")
+	if err := os.MkdirAll(cfg.DbTmp, 0755); err != nil {
+		return fmt.Errorf("cannot create state-db directory %v; %w", cfg.DbTmp, err)
+	}
+	scope(ProfileDBFlag.Name, &cfg.ProfileDB, "profile.db")
+	if cfg.MicroProfiling {
+		scope(ProfilingDbNameFlag.Name, &cfg.ProfilingDbName, "profiling.db")
+	}
+	scope(TraceFileFlag.Name, &cfg.TraceFile, "trace")
+	if cfg.Profile {
+		scope(ProfileFileFlag.Name, &cfg.ProfileFile, "profile.csv")
+	}
+	if cfg.CompareSchemas != "" {
+		scope(CompareSchemasReportFlag.Name, &cfg.CompareSchemasReport, PathToSchemaComparison)
+	}
+	// errors are only collected if the run is expected to continue on failure
+	if cfg.ContinueOnFailure {
+		scope(ErrorLoggingFlag.Name, &cfg.ErrorLogging, "errors.log")
+	}
+
+	// artifacts enabled by their path are only reported
+	for flag, value := range map[string]string{
+		CpuProfileFlag.Name:     cfg.CPUProfile,
+		MemoryProfileFlag.Name:  cfg.MemoryProfile,
+		StateDbLoggingFlag.Name: cfg.DbLogging,
+		RegisterRunFlag.Name:    cfg.RegisterRun,
+	} {
+		if value != "" {
+			summary.Artifacts[flag] = value
+		}
+	}
+
+	cc.runSummary = &summary
+	return WriteRunSummary(summary)
+}
+
+// isSet returns true if the given flag was set explicitly on the command line.
+func (cc *configContext) isSet(flag string) bool {
+	return cc.ctx != nil && cc.ctx.IsSet(flag)
+}
+
+// reportRunLayout logs out the effective layout of a run scoped by --output-dir.
+func (cc *configContext) reportRunLayout() {
+	if cc.runSummary == nil {
+		return
+	}
+	cc.log.Noticef("Run %v artifacts are placed in %v", cc.runSummary.RunId, cc.runSummary.RunDir)
+	flags := make([]string, 0, len(cc.runSummary.Artifacts))
+	for flag := range cc.runSummary.Artifacts {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+	for _, flag := range flags {
+		cc.log.Infof("  --%v: %v", flag, cc.runSummary.Artifacts[flag])
+	}
+}
+
+// makeRunId generates a run id which is unique for runs started in parallel on one host.
+func makeRunId(t time.Time) string {
+	return fmt.Sprintf("%v_%d", t.UTC().Format("20060102_150405"), os.Getpid())
+}
+
+// WriteRunSummary writes the summary into the run directory.
+func WriteRunSummary(summary RunSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal run summary; %w", err)
+	}
+	filename := filepath.Join(summary.RunDir, PathToRunSummary)
+	if err = os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("cannot write run summary %v; %w", filename, err)
+	}
+	return nil
+}
+
+// ReadRunSummary reads the summary of a run from the given run directory.
+func ReadRunSummary(runDir string) (RunSummary, error) {
+	var summary RunSummary
+	filename := filepath.Join(runDir, PathToRunSummary)
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return summary, fmt.Errorf("failed to read %v; %w", filename, err)
+	}
+	err = json.Unmarshal(data, &summary)
+	return summary, err
+}
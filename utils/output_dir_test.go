@@ -0,0 +1,89 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestOutputDir_ScopesArtifactsNotSetExplicitly(t *testing.T) {
+	outputDir := t.TempDir()
+	flagSet := flag.NewFlagSet("output_dir_test", 0)
+	flagSet.String(ProfileDBFlag.Name, "", "")
+	require.NoError(t, flagSet.Set(ProfileDBFlag.Name, "/explicit/profile.db"))
+	ctx := cli.NewContext(cli.NewApp(), flagSet, nil)
+
+	cfg := &Config{
+		OutputDir:         outputDir,
+		OverwriteRunId:    "my-run",
+		ProfileDB:         "/explicit/profile.db",
+		ContinueOnFailure: true,
+		LogLevel:          "critical",
+	}
+	cc := NewConfigContext(cfg, ctx)
+	require.NoError(t, cc.setOutputDir())
+
+	runDir := filepath.Join(outputDir, "my-run")
+	assert.Equal(t, "my-run", cfg.RunId)
+	assert.Equal(t, filepath.Join(runDir, "state-db"), cfg.DbTmp)
+	assert.DirExists(t, cfg.DbTmp)
+	assert.Equal(t, filepath.Join(runDir, "errors.log"), cfg.ErrorLogging)
+	assert.Equal(t, "/explicit/profile.db", cfg.ProfileDB)
+
+	summary, err := ReadRunSummary(runDir)
+	require.NoError(t, err)
+	assert.Equal(t, "my-run", summary.RunId)
+	assert.Equal(t, runDir, summary.RunDir)
+	assert.Equal(t, cfg.DbTmp, summary.Artifacts[DbTmpFlag.Name])
+	assert.Equal(t, "/explicit/profile.db", summary.Artifacts[ProfileDBFlag.Name])
+}
+
+func TestOutputDir_GeneratedRunIdIsSharedWithRegisteredRun(t *testing.T) {
+	cfg := &Config{
+		OutputDir:   t.TempDir(),
+		RegisterRun: t.TempDir(),
+		LogLevel:    "critical",
+	}
+	cc := NewConfigContext(cfg, nil)
+	require.NoError(t, cc.setOutputDir())
+
+	assert.NotEmpty(t, cfg.RunId)
+	assert.Equal(t, cfg.RunId, cfg.OverwriteRunId)
+	assert.DirExists(t, filepath.Join(cfg.OutputDir, cfg.RunId))
+}
+
+func TestOutputDir_DisabledWithoutOutputDir(t *testing.T) {
+	cfg := &Config{DbTmp: "/tmp", LogLevel: "critical"}
+	cc := NewConfigContext(cfg, nil)
+	require.NoError(t, cc.setOutputDir())
+
+	assert.Empty(t, cfg.RunId)
+	assert.Equal(t, "/tmp", cfg.DbTmp)
+	assert.Nil(t, cc.runSummary)
+}
+
+func TestOutputDir_MakeRunIdContainsTimeAndProcess(t *testing.T) {
+	id := makeRunId(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	assert.Regexp(t, `^20250102_030405_\d+$`, id)
+}
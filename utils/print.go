@@ -18,6 +18,7 @@ package utils
 
 import (
 	"database/sql"
+	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
@@ -147,6 +148,51 @@ func (ps *Printers) AddPrinterToFile(filepath string, f func() string) *Printers
 	return ps
 }
 
+// PrinterToCsv writes rows to a csv file
+// Wrap f, returns an array of rows to be written
+// Rows are flushed on every print, so that the file is complete up to the last print.
+type PrinterToCsv struct {
+	file *os.File
+	w    *csv.Writer
+	f    func() [][]any
+}
+
+func (p *PrinterToCsv) Print() error {
+	for _, row := range p.f() {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = fmt.Sprint(value)
+		}
+		if err := p.w.Write(record); err != nil {
+			return fmt.Errorf("unable to write to csv file %s; %v", p.file.Name(), err)
+		}
+	}
+	p.w.Flush()
+	return p.w.Error()
+}
+
+func (p *PrinterToCsv) Close() error {
+	p.w.Flush()
+	return errors.Join(p.w.Error(), p.file.Close())
+}
+
+// NewPrinterToCsv creates the csv file at filepath, replacing any existing one, and writes the header.
+func NewPrinterToCsv(filepath string, header []string, f func() [][]any) (*PrinterToCsv, error) {
+	file, err := os.Create(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create csv file %s; %v", filepath, err)
+	}
+	p := &PrinterToCsv{file, csv.NewWriter(file), f}
+	if err = p.w.Write(header); err != nil {
+		return nil, errors.Join(err, file.Close())
+	}
+	p.w.Flush()
+	if err = p.w.Error(); err != nil {
+		return nil, errors.Join(err, file.Close())
+	}
+	return p, nil
+}
+
 // PrinterToDb writes by inserting rows into DB
 // Wrap f, returns an array of values to be inserted
 type PrinterToDb struct {
@@ -18,6 +18,7 @@ package utils
 
 import (
 	"database/sql"
+	"encoding/csv"
 	"errors"
 	"os"
 	"reflect"
@@ -227,6 +228,38 @@ func TestPrinterToFile_NewPrinterToFile(t *testing.T) {
 	assert.Equal(t, filePath, p.filepath)
 }
 
+func TestPrinterToCsv_WritesHeaderAndFlushesEachPrint(t *testing.T) {
+	filePath := t.TempDir() + "/test.csv"
+	row := 0
+	p, err := NewPrinterToCsv(filePath, []string{"row", "name"}, func() [][]any {
+		row++
+		return [][]any{{row, "a,b"}}
+	})
+	assert.NoError(t, err)
+
+	readRecords := func() [][]string {
+		file, err := os.Open(filePath)
+		assert.NoError(t, err)
+		defer file.Close()
+		records, err := csv.NewReader(file).ReadAll()
+		assert.NoError(t, err)
+		return records
+	}
+	assert.Equal(t, [][]string{{"row", "name"}}, readRecords())
+
+	assert.NoError(t, p.Print())
+	assert.Equal(t, [][]string{{"row", "name"}, {"1", "a,b"}}, readRecords())
+
+	assert.NoError(t, p.Print())
+	assert.NoError(t, p.Close())
+	assert.Equal(t, [][]string{{"row", "name"}, {"1", "a,b"}, {"2", "a,b"}}, readRecords())
+}
+
+func TestPrinterToCsv_NewPrinterToCsvFailsOnInvalidPath(t *testing.T) {
+	_, err := NewPrinterToCsv(t.TempDir()+"/does/not/exist.csv", []string{"a"}, nil)
+	assert.ErrorContains(t, err, "unable to create csv file")
+}
+
 func TestPrinterToDb_Print(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -61,3 +61,12 @@ func (pt *ProgressTracker) PrintProgress() {
 		pt.log.Infof("\t\tLoading state ... %8.1f slots/s, %5.1f%%, time: %d:%02d, ETA: %d:%02d", currentRate, progress*100, time/60, time%60, eta/60, eta%60)
 	}
 }
+
+// Rate returns the amount per second processed within the given duration. A run
+// without any measurable duration, e.g. an empty block range, yields a zero rate.
+func Rate(amount float64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return amount / d.Seconds()
+}
@@ -18,6 +18,7 @@ package utils
 
 import (
 	"testing"
+	"time"
 
 	"github.com/0xsoniclabs/aida/logger"
 	"github.com/stretchr/testify/assert"
@@ -45,3 +46,9 @@ func TestProgressTracker_PrintProgress(t *testing.T) {
 		tracker.PrintProgress()
 	}
 }
+
+func TestRate_ZeroDurationYieldsZeroRate(t *testing.T) {
+	assert.Equal(t, 0.0, Rate(100, 0))
+	assert.Equal(t, 0.0, Rate(0, 0))
+	assert.Equal(t, 50.0, Rate(100, 2*time.Second))
+}
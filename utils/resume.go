@@ -0,0 +1,103 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/0xsoniclabs/aida/logger"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ResumeReportFormat is logged once the block at which an interrupted run continues is known.
+const ResumeReportFormat = "Resuming run at block %d"
+
+// ErrNothingToResume is returned by PrepareResume if the interrupted run has already processed all its blocks.
+var ErrNothingToResume = errors.New("all blocks of the run have already been processed")
+
+// PrepareResume adjusts the configuration so that the run continues an interrupted run on the
+// StateDb in cfg.StateDbSrc, which is modified in place. Blocks are only known to be durable
+// once they are part of the archive; after a crash Carmen restores its last checkpoint, hence
+// the run continues after the archive block height. If the StateDb does not exist yet, it is
+// created by the run starting at cfg.First.
+func PrepareResume(cfg *Config) error {
+	if cfg.StateDbSrc == "" {
+		return fmt.Errorf("--%v requires --%v", ResumeFlag.Name, StateDbSrcFlag.Name)
+	}
+	if !cfg.ArchiveMode {
+		return fmt.Errorf("--%v requires --%v; the archive block height is the block at which the run continues", ResumeFlag.Name, ArchiveModeFlag.Name)
+	}
+	if cfg.ShadowDb {
+		return fmt.Errorf("--%v cannot be used with --%v", ResumeFlag.Name, ShadowDb.Name)
+	}
+	cfg.StateDbSrcDirectAccess = true
+	log := logger.NewLogger(cfg.LogLevel, "Resume")
+
+	if _, err := os.Stat(filepath.Join(cfg.StateDbSrc, PathToDbInfo)); errors.Is(err, os.ErrNotExist) {
+		if err = os.MkdirAll(cfg.StateDbSrc, 0755); err != nil {
+			return fmt.Errorf("cannot create StateDb directory; %w", err)
+		}
+		if err = WriteStateDbInfo(cfg.StateDbSrc, cfg, 0, common.Hash{}, false); err != nil {
+			return fmt.Errorf("cannot create state-db info file; %w", err)
+		}
+		log.Noticef(ResumeReportFormat, cfg.First)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("cannot read state-db info file; %w", err)
+	}
+
+	// opening the StateDb restores its last checkpoint if it was not closed properly
+	db, _, err := PrepareStateDB(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot restore StateDb; %w", err)
+	}
+	height, empty, err := db.GetArchiveBlockHeight()
+	if err != nil {
+		return errors.Join(fmt.Errorf("cannot get archive block height; %w", err), db.Close())
+	}
+	if empty {
+		log.Noticef(ResumeReportFormat, cfg.First)
+		return db.Close()
+	}
+	hash, err := db.GetHash()
+	if err != nil {
+		return errors.Join(fmt.Errorf("cannot get state hash; %w", err), db.Close())
+	}
+	if err = db.Close(); err != nil {
+		return fmt.Errorf("cannot close StateDb; %w", err)
+	}
+
+	// the info file records the restored block, so that the StateDb is neither primed nor processed again up to it
+	if height >= cfg.Last {
+		// the run was interrupted after its last block
+		if err = WriteStateDbInfo(cfg.StateDbSrc, cfg, height, hash, true); err != nil {
+			return fmt.Errorf("cannot write state-db info file; %w", err)
+		}
+		log.Noticef("StateDb already contains block %d", height)
+		return ErrNothingToResume
+	}
+	if err = WriteStateDbInfo(cfg.StateDbSrc, cfg, height, hash, false); err != nil {
+		return fmt.Errorf("cannot write state-db info file; %w", err)
+	}
+	// an archive ending before the first block was interrupted while priming
+	cfg.First = max(cfg.First, height+1)
+	log.Noticef(ResumeReportFormat, cfg.First)
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeResumeTestConfig(t *testing.T) *Config {
+	return &Config{
+		DbImpl:         "carmen",
+		DbVariant:      "go-file",
+		CarmenSchema:   5,
+		ArchiveMode:    true,
+		ArchiveVariant: "s5",
+		StateDbSrc:     filepath.Join(t.TempDir(), "state_db"),
+		First:          1,
+		Last:           10,
+		LogLevel:       "critical",
+	}
+}
+
+// processTestBlocks processes blocks [1, last] on the StateDb of the configuration and closes it.
+func processTestBlocks(t *testing.T, cfg *Config, last uint64) {
+	db, _, err := PrepareStateDB(cfg)
+	require.NoError(t, err)
+	for block := uint64(1); block <= last; block++ {
+		require.NoError(t, db.BeginBlock(block))
+		require.NoError(t, db.BeginTransaction(0))
+		db.SetNonce(common.Address{1}, block, tracing.NonceChangeUnspecified)
+		require.NoError(t, db.EndTransaction())
+		require.NoError(t, db.EndBlock())
+	}
+	require.NoError(t, db.Close())
+}
+
+func TestPrepareResume_RejectsInvalidConfiguration(t *testing.T) {
+	tests := map[string]struct {
+		modify func(cfg *Config)
+		want   string
+	}{
+		"no db-src":  {func(cfg *Config) { cfg.StateDbSrc = "" }, "requires --db-src"},
+		"no archive": {func(cfg *Config) { cfg.ArchiveMode = false }, "requires --archive"},
+		"shadow db":  {func(cfg *Config) { cfg.ShadowDb = true }, "cannot be used with --shadow-db"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := makeResumeTestConfig(t)
+			test.modify(cfg)
+			require.ErrorContains(t, PrepareResume(cfg), test.want)
+		})
+	}
+}
+
+func TestPrepareResume_NewStateDbStartsAtFirstBlock(t *testing.T) {
+	cfg := makeResumeTestConfig(t)
+	require.NoError(t, PrepareResume(cfg))
+
+	assert.True(t, cfg.StateDbSrcDirectAccess)
+	assert.Equal(t, uint64(1), cfg.First)
+	info, err := ReadStateDbInfo(cfg.StateDbSrc)
+	require.NoError(t, err)
+	assert.False(t, info.HasFinished)
+	assert.Equal(t, "carmen", info.Impl)
+}
+
+func TestPrepareResume_ContinuesAfterArchiveBlockHeight(t *testing.T) {
+	cfg := makeResumeTestConfig(t)
+	path := cfg.StateDbSrc
+	require.NoError(t, PrepareResume(cfg))
+	processTestBlocks(t, cfg, 3)
+
+	cfg = makeResumeTestConfig(t)
+	cfg.StateDbSrc = path
+	require.NoError(t, PrepareResume(cfg))
+
+	assert.Equal(t, uint64(4), cfg.First)
+	info, err := ReadStateDbInfo(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), info.Block)
+	assert.False(t, info.HasFinished)
+}
+
+func TestPrepareResume_FinishedRunHasNothingToResume(t *testing.T) {
+	cfg := makeResumeTestConfig(t)
+	path := cfg.StateDbSrc
+	require.NoError(t, PrepareResume(cfg))
+	processTestBlocks(t, cfg, 3)
+
+	cfg = makeResumeTestConfig(t)
+	cfg.StateDbSrc = path
+	cfg.Last = 3
+	require.ErrorIs(t, PrepareResume(cfg), ErrNothingToResume)
+
+	info, err := ReadStateDbInfo(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), info.Block)
+	assert.True(t, info.HasFinished)
+	assert.NotEqual(t, common.Hash{}, info.RootHash)
+}
@@ -0,0 +1,373 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/0xsoniclabs/substate/db"
+)
+
+// Schemes of input fingerprints. A fingerprint can only be compared to another one
+// computed by the same scheme; sampled schemes record their parameters alongside.
+const (
+	FileFingerprintScheme     = "sha256/file"
+	MetadataFingerprintScheme = "sha256/aida-db-metadata"
+	SampledFingerprintScheme  = "sha256/aida-db-sampled-v1"
+)
+
+// Names of inputs fingerprinted by a run manifest.
+const (
+	AidaDbMetadataInput = "aida-db-metadata"
+	AidaDbContentInput  = "aida-db-content"
+	StateDbInfoInput    = "state-db-info"
+	SkipListInput       = "skip-list"
+	GenesisInput        = "genesis"
+)
+
+const (
+	defaultManifestSamples          = 64
+	defaultManifestEntriesPerSample = 16
+)
+
+// modulesOfInterest are the dependencies whose versions decide the outcome of a run.
+var modulesOfInterest = []string{
+	"github.com/0xsoniclabs/carmen/go",
+	"github.com/0xsoniclabs/sonic",
+	"github.com/0xsoniclabs/substate",
+	"github.com/0xsoniclabs/tosca",
+	"github.com/ethereum/go-ethereum",
+}
+
+// RunManifest records fingerprints of the binary and of all data inputs of a run, so
+// that two runs claiming the same inputs can be proven to have used identical data.
+type RunManifest struct {
+	Build  BuildFingerprint   `json:"build"`
+	Inputs []InputFingerprint `json:"inputs"`
+}
+
+// BuildFingerprint identifies the binary which executed a run.
+type BuildFingerprint struct {
+	GitCommit string            `json:"gitCommit"`          // commit injected at build time
+	GoVersion string            `json:"goVersion"`          // version of the go toolchain
+	Revision  string            `json:"revision,omitempty"` // vcs revision recorded by the go toolchain
+	Modified  bool              `json:"modified,omitempty"` // true if the binary was built from a dirty tree
+	Modules   map[string]string `json:"modules,omitempty"`  // versions of dependencies deciding the outcome of a run
+}
+
+// InputFingerprint is the checksum of a single data input of a run.
+type InputFingerprint struct {
+	Name     string    `json:"name"`
+	Path     string    `json:"path"`
+	Scheme   string    `json:"scheme"`
+	Hash     string    `json:"hash"`
+	Sampling *Sampling `json:"sampling,omitempty"` // parameters of sampled schemes
+}
+
+// Sampling describes which parts of a huge database are covered by a sampled checksum.
+// The checksum covers the first EntriesPerSample records of each block-keyed component
+// starting at Samples blocks evenly spread over First-Last.
+type Sampling struct {
+	First            uint64   `json:"first"`
+	Last             uint64   `json:"last"`
+	Samples          int      `json:"samples"`
+	EntriesPerSample int      `json:"entriesPerSample"`
+	Prefixes         []string `json:"prefixes"`
+}
+
+// ManifestDrift is an input whose current fingerprint differs from the recorded one.
+type ManifestDrift struct {
+	Name string
+	Path string
+	Want string // recorded fingerprint
+	Have string // current fingerprint, empty if it could not be computed
+	Err  error  // reason why the fingerprint could not be computed
+}
+
+func (d ManifestDrift) String() string {
+	if d.Err != nil {
+		return fmt.Sprintf("%v (%v): cannot recompute fingerprint; %v", d.Name, d.Path, d.Err)
+	}
+	return fmt.Sprintf("%v (%v): want %v, have %v", d.Name, d.Path, d.Want, d.Have)
+}
+
+// MakeRunManifest computes the fingerprints of all inputs of the run configured by cfg.
+// The aida-db is read through the given, already opened, database; it may be nil if the
+// run does not use an aida-db.
+func MakeRunManifest(cfg *Config, aidaDb db.BaseDB) (*RunManifest, error) {
+	m := &RunManifest{Build: MakeBuildFingerprint()}
+
+	if aidaDb != nil {
+		metadata, err := FingerprintAidaDbMetadata(cfg.AidaDb, aidaDb)
+		if err != nil {
+			return nil, err
+		}
+		content, err := FingerprintAidaDbContent(cfg.AidaDb, aidaDb, Sampling{
+			First:            cfg.First,
+			Last:             cfg.Last,
+			Samples:          defaultManifestSamples,
+			EntriesPerSample: defaultManifestEntriesPerSample,
+			Prefixes:         blockKeyedPrefixes,
+		})
+		if err != nil {
+			return nil, err
+		}
+		m.Inputs = append(m.Inputs, metadata, content)
+	}
+
+	files := []struct{ name, path string }{
+		{StateDbInfoInput, ""},
+		{SkipListInput, cfg.SkipList},
+		{GenesisInput, cfg.Genesis},
+	}
+	if cfg.StateDbSrc != "" {
+		files[0].path = filepath.Join(cfg.StateDbSrc, PathToDbInfo)
+	}
+	for _, file := range files {
+		if file.path == "" {
+			continue
+		}
+		fingerprint, err := FingerprintFile(file.name, file.path)
+		if err != nil {
+			return nil, err
+		}
+		m.Inputs = append(m.Inputs, fingerprint)
+	}
+	return m, nil
+}
+
+// MakeBuildFingerprint identifies the running binary.
+func MakeBuildFingerprint() BuildFingerprint {
+	res := BuildFingerprint{
+		GitCommit: GitCommit,
+		GoVersion: runtime.Version(),
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return res
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			res.Revision = setting.Value
+		case "vcs.modified":
+			res.Modified = setting.Value == "true"
+		}
+	}
+	for _, dep := range info.Deps {
+		for _, path := range modulesOfInterest {
+			if dep.Path != path {
+				continue
+			}
+			if res.Modules == nil {
+				res.Modules = make(map[string]string)
+			}
+			version := dep.Version
+			if dep.Replace != nil {
+				version = fmt.Sprintf("%v => %v %v", version, dep.Replace.Path, dep.Replace.Version)
+			}
+			res.Modules[path] = strings.TrimSpace(version)
+		}
+	}
+	return res
+}
+
+// Input returns the fingerprint of the input with given name.
+func (m *RunManifest) Input(name string) (InputFingerprint, bool) {
+	for _, input := range m.Inputs {
+		if input.Name == name {
+			return input, true
+		}
+	}
+	return InputFingerprint{}, false
+}
+
+// Metadata flattens the manifest into key-value pairs for the run registration.
+func (m *RunManifest) Metadata() map[string]string {
+	res := map[string]string{
+		"Manifest.GitCommit": m.Build.GitCommit,
+		"Manifest.GoVersion": m.Build.GoVersion,
+		"Manifest.Revision":  m.Build.Revision,
+		"Manifest.Modified":  fmt.Sprint(m.Build.Modified),
+	}
+	for path, version := range m.Build.Modules {
+		res["Manifest.Module."+path] = version
+	}
+	for _, input := range m.Inputs {
+		res["Manifest.Input."+input.Name] = fmt.Sprintf("%v:%v", input.Scheme, input.Hash)
+		res["Manifest.Input."+input.Name+".Path"] = input.Path
+		if input.Sampling != nil {
+			s := input.Sampling
+			res["Manifest.Input."+input.Name+".Sampling"] = fmt.Sprintf("blocks=%v-%v;samples=%v;entries=%v;prefixes=%v",
+				s.First, s.Last, s.Samples, s.EntriesPerSample, strings.Join(s.Prefixes, ","))
+		}
+	}
+	return res
+}
+
+// FingerprintFile hashes the whole content of the given file.
+func FingerprintFile(name, path string) (InputFingerprint, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return InputFingerprint{}, fmt.Errorf("cannot open %v %v; %w", name, path, err)
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, file); err != nil {
+		return InputFingerprint{}, fmt.Errorf("cannot read %v %v; %w", name, path, err)
+	}
+	return InputFingerprint{Name: name, Path: path, Scheme: FileFingerprintScheme, Hash: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// FingerprintAidaDbMetadata hashes all metadata records of the aida-db.
+func FingerprintAidaDbMetadata(path string, aidaDb db.BaseDB) (InputFingerprint, error) {
+	h := sha256.New()
+	if _, err := hashRecords(h, aidaDb, []byte(db.MetadataPrefix), nil, -1); err != nil {
+		return InputFingerprint{}, fmt.Errorf("cannot hash aida-db metadata; %w", err)
+	}
+	return InputFingerprint{Name: AidaDbMetadataInput, Path: path, Scheme: MetadataFingerprintScheme, Hash: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// FingerprintAidaDbContent computes a sampled checksum of the block-keyed records of
+// the aida-db. Its cost is bounded by the sampling parameters, not by the database size.
+func FingerprintAidaDbContent(path string, aidaDb db.BaseDB, sampling Sampling) (InputFingerprint, error) {
+	if sampling.Samples <= 0 || sampling.EntriesPerSample <= 0 || sampling.Last < sampling.First {
+		return InputFingerprint{}, fmt.Errorf("invalid sampling %+v", sampling)
+	}
+	h := sha256.New()
+	for _, prefix := range sampling.Prefixes {
+		for _, block := range sampleBlocks(sampling.First, sampling.Last, sampling.Samples) {
+			if _, err := hashRecords(h, aidaDb, []byte(prefix), db.BlockToBytes(block), sampling.EntriesPerSample); err != nil {
+				return InputFingerprint{}, fmt.Errorf("cannot hash aida-db records with prefix %q at block %v; %w", prefix, block, err)
+			}
+		}
+	}
+	return InputFingerprint{
+		Name:     AidaDbContentInput,
+		Path:     path,
+		Scheme:   SampledFingerprintScheme,
+		Hash:     hex.EncodeToString(h.Sum(nil)),
+		Sampling: &sampling,
+	}, nil
+}
+
+// sampleBlocks returns up to n distinct blocks evenly spread over first-last, including both ends.
+func sampleBlocks(first, last uint64, n int) []uint64 {
+	if n == 1 || first == last {
+		return []uint64{first}
+	}
+	span := last - first
+	res := make([]uint64, 0, n)
+	for i := 0; i < n; i++ {
+		// split the multiplication to avoid an overflow for huge ranges
+		step := uint64(i)
+		block := first + span/uint64(n-1)*step + span%uint64(n-1)*step/uint64(n-1)
+		if len(res) == 0 || res[len(res)-1] != block {
+			res = append(res, block)
+		}
+	}
+	return res
+}
+
+// hashRecords feeds at most limit records with given prefix, starting at start, into h.
+// All records are hashed if limit is negative. It returns the number of hashed records.
+func hashRecords(h hash.Hash, aidaDb db.BaseDB, prefix []byte, start []byte, limit int) (int, error) {
+	iter := aidaDb.NewIterator(prefix, start)
+	defer iter.Release()
+	count := 0
+	for (limit < 0 || count < limit) && iter.Next() {
+		writeLengthPrefixed(h, iter.Key())
+		writeLengthPrefixed(h, iter.Value())
+		count++
+	}
+	return count, iter.Error()
+}
+
+func writeLengthPrefixed(h hash.Hash, data []byte) {
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(data)))
+	h.Write(length[:])
+	h.Write(data)
+}
+
+// VerifyRunManifest recomputes all fingerprints of the manifest using the same schemes
+// and reports the inputs which drifted. Paths of inputs may be overridden by name, for
+// instance if the data was moved since the run. The aida-db is opened in read-only mode.
+func VerifyRunManifest(m *RunManifest, paths map[string]string, logLevel string) ([]ManifestDrift, error) {
+	var drifts []ManifestDrift
+	openedDbs := make(map[string]db.SubstateDB)
+	defer func() {
+		for _, sdb := range openedDbs {
+			sdb.Close()
+		}
+	}()
+
+	for _, want := range m.Inputs {
+		path := want.Path
+		if override, found := paths[want.Name]; found && override != "" {
+			path = override
+		}
+
+		var (
+			have InputFingerprint
+			err  error
+		)
+		switch want.Scheme {
+		case FileFingerprintScheme:
+			have, err = FingerprintFile(want.Name, path)
+		case MetadataFingerprintScheme, SampledFingerprintScheme:
+			sdb, found := openedDbs[path]
+			if !found {
+				if sdb, err = openAidaDb(path, logLevel); err == nil {
+					openedDbs[path] = sdb
+				}
+			}
+			if err != nil {
+				break
+			}
+			if want.Scheme == MetadataFingerprintScheme {
+				have, err = FingerprintAidaDbMetadata(path, sdb)
+			} else if want.Sampling == nil {
+				err = errors.New("sampled fingerprint without sampling parameters")
+			} else {
+				have, err = FingerprintAidaDbContent(path, sdb, *want.Sampling)
+			}
+		default:
+			return nil, fmt.Errorf("unknown fingerprint scheme %q of input %v", want.Scheme, want.Name)
+		}
+
+		if err != nil {
+			drifts = append(drifts, ManifestDrift{Name: want.Name, Path: path, Want: want.Hash, Err: err})
+			continue
+		}
+		if have.Hash != want.Hash {
+			drifts = append(drifts, ManifestDrift{Name: want.Name, Path: path, Want: want.Hash, Have: have.Hash})
+		}
+	}
+	return drifts, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunManifest_SampleBlocksAreEvenlySpreadAndDistinct(t *testing.T) {
+	tests := map[string]struct {
+		first, last uint64
+		n           int
+		want        []uint64
+	}{
+		"single block":     {5, 5, 4, []uint64{5}},
+		"single sample":    {5, 10, 1, []uint64{5}},
+		"both ends":        {0, 10, 2, []uint64{0, 10}},
+		"even spread":      {0, 100, 5, []uint64{0, 25, 50, 75, 100}},
+		"range too narrow": {0, 2, 5, []uint64{0, 1, 2}},
+		"huge range":       {0, ^uint64(0), 3, []uint64{0, ^uint64(0) / 2, ^uint64(0)}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, sampleBlocks(test.first, test.last, test.n))
+		})
+	}
+}
+
+func TestRunManifest_FingerprintFileDependsOnContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(path, []byte("content"), 0644))
+
+	a, err := FingerprintFile(SkipListInput, path)
+	require.NoError(t, err)
+	b, err := FingerprintFile(SkipListInput, path)
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+	assert.Equal(t, FileFingerprintScheme, a.Scheme)
+
+	require.NoError(t, os.WriteFile(path, []byte("changed"), 0644))
+	c, err := FingerprintFile(SkipListInput, path)
+	require.NoError(t, err)
+	assert.NotEqual(t, a.Hash, c.Hash)
+
+	_, err = FingerprintFile(SkipListInput, filepath.Join(t.TempDir(), "missing"))
+	require.ErrorContains(t, err, "cannot open skip-list")
+}
+
+func TestRunManifest_MakeRunManifestFingerprintsAllInputs(t *testing.T) {
+	ss, aidaDbPath := CreateTestSubstateDb(t, db.ProtobufEncodingSchema)
+	aidaDb, err := db.NewReadOnlySubstateDB(aidaDbPath)
+	require.NoError(t, err)
+	defer aidaDb.Close()
+
+	skipList := filepath.Join(t.TempDir(), "skip-list")
+	require.NoError(t, os.WriteFile(skipList, []byte("1:2\n"), 0644))
+	stateDbSrc := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(stateDbSrc, PathToDbInfo), []byte("{}"), 0644))
+
+	cfg := &Config{AidaDb: aidaDbPath, First: ss.Block - 1, Last: ss.Block + 1, SkipList: skipList, StateDbSrc: stateDbSrc}
+	m, err := MakeRunManifest(cfg, aidaDb)
+	require.NoError(t, err)
+
+	assert.Equal(t, GitCommit, m.Build.GitCommit)
+	assert.NotEmpty(t, m.Build.GoVersion)
+	for _, name := range []string{AidaDbMetadataInput, AidaDbContentInput, StateDbInfoInput, SkipListInput} {
+		input, found := m.Input(name)
+		require.True(t, found, name)
+		assert.Len(t, input.Hash, 64)
+	}
+	_, found := m.Input(GenesisInput)
+	assert.False(t, found)
+
+	content, _ := m.Input(AidaDbContentInput)
+	require.NotNil(t, content.Sampling)
+	assert.Equal(t, cfg.First, content.Sampling.First)
+	assert.Equal(t, cfg.Last, content.Sampling.Last)
+	assert.Equal(t, blockKeyedPrefixes, content.Sampling.Prefixes)
+
+	metadata := m.Metadata()
+	assert.Equal(t, SampledFingerprintScheme+":"+content.Hash, metadata["Manifest.Input.aida-db-content"])
+	assert.Contains(t, metadata["Manifest.Input.aida-db-content.Sampling"], "samples=64")
+	assert.Equal(t, skipList, metadata["Manifest.Input.skip-list.Path"])
+}
+
+func TestRunManifest_VerifyRunManifestReportsDrift(t *testing.T) {
+	ss, aidaDbPath := CreateTestSubstateDb(t, db.ProtobufEncodingSchema)
+	skipList := filepath.Join(t.TempDir(), "skip-list")
+	require.NoError(t, os.WriteFile(skipList, []byte("1:2\n"), 0644))
+	cfg := &Config{AidaDb: aidaDbPath, First: ss.Block - 1, Last: ss.Block + 1, SkipList: skipList}
+
+	aidaDb, err := db.NewReadOnlySubstateDB(aidaDbPath)
+	require.NoError(t, err)
+	m, err := MakeRunManifest(cfg, aidaDb)
+	require.NoError(t, err)
+	require.NoError(t, aidaDb.Close())
+
+	drifts, err := VerifyRunManifest(m, nil, "critical")
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	// a record within the sampled range changes the content fingerprint
+	sdb, err := db.NewDefaultSubstateDB(aidaDbPath)
+	require.NoError(t, err)
+	require.NoError(t, sdb.Put(db.SubstateDBKey(ss.Block, 1), []byte{1}))
+	require.NoError(t, sdb.Close())
+	require.NoError(t, os.WriteFile(skipList, []byte("1:3\n"), 0644))
+
+	drifts, err = VerifyRunManifest(m, nil, "critical")
+	require.NoError(t, err)
+	require.Len(t, drifts, 2)
+	assert.Equal(t, AidaDbContentInput, drifts[0].Name)
+	assert.Equal(t, SkipListInput, drifts[1].Name)
+
+	// inputs which cannot be read anymore are reported as well
+	drifts, err = VerifyRunManifest(m, map[string]string{SkipListInput: filepath.Join(t.TempDir(), "missing")}, "critical")
+	require.NoError(t, err)
+	require.Len(t, drifts, 2)
+	assert.Error(t, drifts[1].Err)
+	assert.Contains(t, drifts[1].String(), "cannot recompute fingerprint")
+}
+
+func TestRunManifest_VerifyRunManifestRejectsUnknownScheme(t *testing.T) {
+	m := &RunManifest{Inputs: []InputFingerprint{{Name: "x", Scheme: "md5"}}}
+	_, err := VerifyRunManifest(m, nil, "critical")
+	require.ErrorContains(t, err, "unknown fingerprint scheme")
+}
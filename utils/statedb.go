@@ -18,6 +18,7 @@ package utils
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -103,7 +104,17 @@ func useExistingStateDB(cfg *Config) (state.StateDB, string, error) {
 	}
 
 	stateDbInfo, err = ReadStateDbInfo(cfg.PathToStateDb)
-	if err != nil {
+	if errors.Is(err, os.ErrNotExist) {
+		// legacy StateDbs were created without an info file, the requested configuration is trusted
+		log.Warningf("StateDb in '%v' has no %v file; opening it with the requested configuration", cfg.PathToStateDb, PathToDbInfo)
+		stateDbInfo = StateDbInfo{
+			Impl:           cfg.DbImpl,
+			Variant:        cfg.DbVariant,
+			ArchiveMode:    cfg.ArchiveMode,
+			ArchiveVariant: cfg.ArchiveVariant,
+			Schema:         cfg.CarmenSchema,
+		}
+	} else if err != nil {
 		return nil, "", fmt.Errorf("cannot read StateDb cfg file in '%v'; %v", cfg.PathToStateDb, err)
 	}
 
@@ -125,12 +136,17 @@ func useExistingStateDB(cfg *Config) (state.StateDB, string, error) {
 	cfg.DbImpl = stateDbInfo.Impl
 	cfg.DbVariant = stateDbInfo.Variant
 	cfg.CarmenSchema = stateDbInfo.Schema
+	cfg.ArchiveFirstBlock = stateDbInfo.ArchiveFirst
 
 	// open primary db
 	stateDb, err = makeStateDBVariant(cfg.PathToStateDb, stateDbInfo.Impl, stateDbInfo.Variant, stateDbInfo.ArchiveVariant, stateDbInfo.Schema, stateDbInfo.RootHash, cfg)
 	if err != nil {
 		return nil, "", fmt.Errorf("cannot create StateDb; %v", err)
 	}
+	// archive blocks of a shrunk StateDb before its retention boundary must not be queried
+	if stateDbInfo.ArchiveFirst > 0 {
+		stateDb = proxy.NewRetentionProxy(stateDb, stateDbInfo.ArchiveFirst)
+	}
 
 	if !cfg.ShadowDb {
 		return stateDb, cfg.PathToStateDb, nil
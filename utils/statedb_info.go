@@ -25,6 +25,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -38,6 +39,7 @@ type StateDbInfo struct {
 	Variant        string      `json:"dbVariant"`      // type of db variant
 	ArchiveMode    bool        `json:"archiveMode"`    // archive mode
 	ArchiveVariant string      `json:"archiveVariant"` // archive variant
+	ArchiveFirst   uint64      `json:"archiveFirst"`   // first block retained in the archive, older blocks were pruned
 	Schema         int         `json:"schema"`         // DB schema version used
 	Block          uint64      `json:"block"`          // last block height
 	RootHash       common.Hash `json:"rootHash"`       // root hash of the last block height
@@ -115,6 +117,7 @@ func WriteStateDbInfo(directory string, cfg *Config, block uint64, root common.H
 		Variant:        cfg.DbVariant,
 		ArchiveMode:    cfg.ArchiveMode,
 		ArchiveVariant: cfg.ArchiveVariant,
+		ArchiveFirst:   cfg.ArchiveFirstBlock,
 		Schema:         cfg.CarmenSchema,
 		Block:          block,
 		RootHash:       root,
@@ -139,12 +142,62 @@ func ReadStateDbInfo(dbpath string) (StateDbInfo, error) {
 	filename := filepath.Join(dbpath, PathToDbInfo)
 	file, err := os.ReadFile(filename)
 	if err != nil {
-		return dbinfo, fmt.Errorf("failed to read %v; %v", filename, err)
+		return dbinfo, fmt.Errorf("failed to read %v; %w", filename, err)
 	}
 	err = json.Unmarshal(file, &dbinfo)
 	return dbinfo, err
 }
 
+// String returns the StateDb configuration recorded in the info in the form of command line flags.
+func (info StateDbInfo) String() string {
+	return fmt.Sprintf("--%v %v --%v %q --%v %v --%v=%v --%v %q",
+		StateDbImplementationFlag.Name, info.Impl,
+		StateDbVariantFlag.Name, info.Variant,
+		CarmenSchemaFlag.Name, info.Schema,
+		ArchiveModeFlag.Name, info.ArchiveMode,
+		ArchiveVariantFlag.Name, info.ArchiveVariant,
+	)
+}
+
+// checkStateDbInfoCompatibility compares the StateDb configuration recorded in the info file
+// against the requested one. Only flags reported as explicitly set by isSet are compared,
+// flags left on their default values are taken over from the recorded configuration.
+func checkStateDbInfoCompatibility(recorded StateDbInfo, cfg *Config, isSet func(name string) bool) error {
+	var mismatches []string
+	check := func(flag string, want, have any) {
+		if isSet(flag) && want != have {
+			mismatches = append(mismatches, fmt.Sprintf("--%v (recorded: %v, requested: %v)", flag, want, have))
+		}
+	}
+
+	check(StateDbImplementationFlag.Name, recorded.Impl, cfg.DbImpl)
+	check(StateDbVariantFlag.Name, recorded.Variant, cfg.DbVariant)
+	if recorded.Impl == "carmen" {
+		check(CarmenSchemaFlag.Name, recorded.Schema, cfg.CarmenSchema)
+	}
+	// an archive variant of an existing archive cannot be changed
+	if recorded.ArchiveMode && cfg.ArchiveMode {
+		check(ArchiveVariantFlag.Name, recorded.ArchiveVariant, cfg.ArchiveVariant)
+	}
+
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	requested := StateDbInfo{
+		Impl:           cfg.DbImpl,
+		Variant:        cfg.DbVariant,
+		ArchiveMode:    cfg.ArchiveMode,
+		ArchiveVariant: cfg.ArchiveVariant,
+		Schema:         cfg.CarmenSchema,
+	}
+	return fmt.Errorf("existing StateDb was created with a different configuration than requested: %v\n"+
+		"\trecorded:  %v\n"+
+		"\trequested: %v\n"+
+		"run with the recorded flags or omit them to use the recorded configuration",
+		strings.Join(mismatches, ", "), recorded, requested)
+}
+
 // RenameTempStateDbDirectory renames a temp directory to a meaningful name
 func RenameTempStateDbDirectory(cfg *Config, oldDirectory string, block uint64) string {
 	var newDirectory string
@@ -17,6 +17,7 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -35,6 +36,7 @@ func TestStatedbInfo_WriteReadStateDbInfo(t *testing.T) {
 			// Update config for state DB preparation by providing additional information
 			cfg.DbTmp = t.TempDir()
 			cfg.StateDbSrc = t.TempDir()
+			cfg.ArchiveFirstBlock = 1
 
 			// Call for json creation and writing into it
 			err := WriteStateDbInfo(cfg.StateDbSrc, cfg, 2, common.Hash{}, true)
@@ -64,6 +66,9 @@ func TestStatedbInfo_WriteReadStateDbInfo(t *testing.T) {
 			if dbInfo.Schema != cfg.CarmenSchema {
 				t.Fatalf("failed to write CarmenSchema into DB info json file correctly; Is: %d; Should be: %d", dbInfo.Schema, cfg.CarmenSchema)
 			}
+			if dbInfo.ArchiveFirst != cfg.ArchiveFirstBlock {
+				t.Fatalf("failed to write ArchiveFirstBlock into DB info json file correctly; Is: %d; Should be: %d", dbInfo.ArchiveFirst, cfg.ArchiveFirstBlock)
+			}
 			if dbInfo.Block != 2 {
 				t.Fatalf("failed to write Block into DB info json file correctly; Is: %d; Should be: %d", dbInfo.Block, 2)
 			}
@@ -227,6 +232,62 @@ func TestStateDBInfo_ReadStateDbInfoError(t *testing.T) {
 	dbInfo, err := ReadStateDbInfo(tempDir)
 	assert.Equal(t, StateDbInfo{}, dbInfo)
 	assert.Error(t, err)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestStateDBInfo_CheckStateDbInfoCompatibility(t *testing.T) {
+	recorded := StateDbInfo{Impl: "carmen", Variant: "go-file", Schema: 5, ArchiveMode: true, ArchiveVariant: "s5"}
+	tests := map[string]struct {
+		cfg      Config
+		set      []string
+		expected string
+	}{
+		"matching": {
+			cfg: Config{DbImpl: "carmen", DbVariant: "go-file", CarmenSchema: 5, ArchiveMode: true, ArchiveVariant: "s5"},
+			set: []string{StateDbImplementationFlag.Name, StateDbVariantFlag.Name, CarmenSchemaFlag.Name, ArchiveVariantFlag.Name},
+		},
+		"defaults are not compared": {
+			cfg: Config{DbImpl: "geth", CarmenSchema: 3},
+		},
+		"different schema": {
+			cfg:      Config{DbImpl: "carmen", DbVariant: "go-file", CarmenSchema: 3},
+			set:      []string{CarmenSchemaFlag.Name},
+			expected: "--carmen-schema (recorded: 5, requested: 3)",
+		},
+		"different variant": {
+			cfg:      Config{DbImpl: "carmen", DbVariant: "go-memory", CarmenSchema: 5},
+			set:      []string{StateDbVariantFlag.Name},
+			expected: "--db-variant (recorded: go-file, requested: go-memory)",
+		},
+		"different archive variant": {
+			cfg:      Config{DbImpl: "carmen", DbVariant: "go-file", CarmenSchema: 5, ArchiveMode: true, ArchiveVariant: "ldb"},
+			set:      []string{ArchiveVariantFlag.Name},
+			expected: "--archive-variant (recorded: s5, requested: ldb)",
+		},
+		"archive variant ignored in live mode": {
+			cfg: Config{DbImpl: "carmen", DbVariant: "go-file", CarmenSchema: 5, ArchiveVariant: "ldb"},
+			set: []string{ArchiveVariantFlag.Name},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			isSet := func(name string) bool {
+				for _, flag := range test.set {
+					if flag == name {
+						return true
+					}
+				}
+				return false
+			}
+			err := checkStateDbInfoCompatibility(recorded, &test.cfg, isSet)
+			if test.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.expected)
+			assert.ErrorContains(t, err, "recorded:  "+recorded.String())
+		})
+	}
 }
 
 func TestStateDBInfo_RenameTempStateDbDirectoryError(t *testing.T) {
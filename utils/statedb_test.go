@@ -25,12 +25,15 @@ import (
 	"time"
 
 	"github.com/0xsoniclabs/aida/state"
+	"github.com/0xsoniclabs/aida/state/proxy"
 	substatecontext "github.com/0xsoniclabs/aida/txcontext/substate"
 	"github.com/0xsoniclabs/substate/substate"
 	substatetypes "github.com/0xsoniclabs/substate/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -249,6 +252,28 @@ func TestStateDB_useExistingStateDB(t *testing.T) {
 	}
 }
 
+func TestStateDB_useExistingStateDB_ShrunkArchiveRejectsPrunedBlocks(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &Config{
+		DbImpl:                 "memory",
+		StateDbSrc:             tempDir,
+		StateDbSrcDirectAccess: true,
+		ChainID:                OperaMainnetChainID,
+	}
+	require.NoError(t, WriteStateDbInfo(tempDir, &Config{DbImpl: "memory", ArchiveMode: true, ArchiveFirstBlock: 5}, 10, common.Hash{}, true))
+
+	db, _, err := useExistingStateDB(cfg)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	assert.Equal(t, uint64(5), cfg.ArchiveFirstBlock)
+	require.IsType(t, &proxy.RetentionProxy{}, db)
+	_, err = db.GetArchiveState(4)
+	require.ErrorIs(t, err, proxy.ErrArchiveBlockPruned)
+}
+
 func TestWorldstateUpdate_OverwriteStateDb(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
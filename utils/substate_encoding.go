@@ -0,0 +1,116 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/0xsoniclabs/substate/db"
+)
+
+// ErrSubstateEncodingMismatch is returned if --substate-encoding contradicts the encoding of stored substates.
+var ErrSubstateEncodingMismatch = errors.New("substate encoding mismatch")
+
+// probedSubstateEncodings lists the encodings tried when detecting the encoding of stored substates.
+var probedSubstateEncodings = []db.SubstateEncodingSchema{db.ProtobufEncodingSchema, db.RLPEncodingSchema}
+
+// DetectSubstateEncoding probes the first substate record of given database with all
+// supported decoders. It returns the encodings able to decode the record together with
+// the probed key. Both are nil if the database contains no substates. The probing only
+// reads from the database.
+func DetectSubstateEncoding(base db.BaseDB) ([]db.SubstateEncodingSchema, []byte, error) {
+	iter := base.NewIterator([]byte(db.SubstateDBPrefix), nil)
+	defer iter.Release()
+	if !iter.Next() {
+		if err := iter.Error(); err != nil {
+			return nil, nil, fmt.Errorf("cannot read first substate; %w", err)
+		}
+		return nil, nil, nil
+	}
+	key := bytes.Clone(iter.Key())
+	block, tx, err := db.DecodeSubstateDBKey(key)
+	if err != nil {
+		return nil, key, fmt.Errorf("cannot decode substate key %#x; %w", key, err)
+	}
+
+	var detected []db.SubstateEncodingSchema
+	for _, encoding := range probedSubstateEncodings {
+		sdb, err := db.MakeDefaultSubstateDBFromBaseDBWithEncoding(base, encoding)
+		if err != nil {
+			return nil, key, err
+		}
+		if _, err = sdb.GetSubstate(block, tx); err == nil {
+			detected = append(detected, encoding)
+		}
+	}
+	return detected, key, nil
+}
+
+// ApplySubstateEncoding sets the substate encoding of given database and returns it.
+// If no encoding is requested, the encoding detected from the first substate record
+// is used. A requested encoding which cannot decode the stored substates is rejected
+// with ErrSubstateEncodingMismatch before any further substate is read. Databases
+// without substates keep the requested encoding, or the current one if none is requested.
+func ApplySubstateEncoding(sdb db.SubstateDB, requested db.SubstateEncodingSchema) (db.SubstateEncodingSchema, error) {
+	want, err := NormalizeSubstateEncoding(requested)
+	if err != nil {
+		return "", err
+	}
+
+	detected, key, err := DetectSubstateEncoding(sdb)
+	if err != nil {
+		return "", err
+	}
+
+	encoding := requested
+	switch {
+	case key == nil:
+		if encoding == "" {
+			return sdb.GetSubstateEncoding(), nil
+		}
+	case len(detected) == 0:
+		return "", fmt.Errorf("first substate %#x cannot be decoded with any of the supported encodings %v", key, probedSubstateEncodings)
+	case want == "":
+		encoding = detected[0]
+	case !slices.Contains(detected, want):
+		return "", fmt.Errorf("%w; --substate-encoding is %v but aida-db contains %v encoded substates (first key probed: %#x)",
+			ErrSubstateEncodingMismatch, requested, detected[0], key)
+	}
+
+	if err = sdb.SetSubstateEncoding(encoding); err != nil {
+		return "", fmt.Errorf("cannot set substate encoding; %w", err)
+	}
+	return sdb.GetSubstateEncoding(), nil
+}
+
+// NormalizeSubstateEncoding maps aliases of given encoding to the probed encodings.
+// An empty encoding is returned unchanged.
+func NormalizeSubstateEncoding(encoding db.SubstateEncodingSchema) (db.SubstateEncodingSchema, error) {
+	switch encoding {
+	case "":
+		return "", nil
+	case db.DefaultEncodingSchema, db.ProtobufEncodingSchema, db.LegacyProtobufEncodingAlias:
+		return db.ProtobufEncodingSchema, nil
+	case db.RLPEncodingSchema:
+		return db.RLPEncodingSchema, nil
+	default:
+		return "", fmt.Errorf("encoding not supported: %s", encoding)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/substate/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSubstateEncoding_FindsEncodingOfFirstSubstate(t *testing.T) {
+	for _, encoding := range probedSubstateEncodings {
+		t.Run(string(encoding), func(t *testing.T) {
+			ss, path := CreateTestSubstateDb(t, encoding)
+			sdb, err := db.NewReadOnlySubstateDB(path)
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, sdb.Close())
+			}()
+
+			detected, key, err := DetectSubstateEncoding(sdb)
+			require.NoError(t, err)
+			assert.Contains(t, detected, encoding)
+			assert.Equal(t, db.SubstateDBKey(ss.Block, ss.Transaction), key)
+		})
+	}
+}
+
+func TestDetectSubstateEncoding_EmptyDb(t *testing.T) {
+	sdb, err := db.NewDefaultSubstateDB(filepath.Join(t.TempDir(), "aida-db"))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, sdb.Close())
+	}()
+
+	detected, key, err := DetectSubstateEncoding(sdb)
+	require.NoError(t, err)
+	assert.Nil(t, detected)
+	assert.Nil(t, key)
+}
+
+func TestApplySubstateEncoding_UsesDetectedEncodingIfNotRequested(t *testing.T) {
+	_, path := CreateTestSubstateDb(t, db.RLPEncodingSchema)
+	sdb, err := db.NewReadOnlySubstateDB(path)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, sdb.Close())
+	}()
+
+	encoding, err := ApplySubstateEncoding(sdb, "")
+	require.NoError(t, err)
+	assert.Equal(t, db.RLPEncodingSchema, encoding)
+	assert.Equal(t, db.RLPEncodingSchema, sdb.GetSubstateEncoding())
+}
+
+func TestApplySubstateEncoding_AcceptsMatchingEncoding(t *testing.T) {
+	_, path := CreateTestSubstateDb(t, db.ProtobufEncodingSchema)
+	sdb, err := db.NewReadOnlySubstateDB(path)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, sdb.Close())
+	}()
+
+	for _, requested := range []db.SubstateEncodingSchema{db.ProtobufEncodingSchema, db.LegacyProtobufEncodingAlias, db.DefaultEncodingSchema} {
+		encoding, err := ApplySubstateEncoding(sdb, requested)
+		require.NoError(t, err)
+		assert.Equal(t, db.ProtobufEncodingSchema, encoding)
+	}
+}
+
+func TestApplySubstateEncoding_RejectsContradictingEncoding(t *testing.T) {
+	ss, path := CreateTestSubstateDb(t, db.ProtobufEncodingSchema)
+	sdb, err := db.NewReadOnlySubstateDB(path)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, sdb.Close())
+	}()
+
+	_, err = ApplySubstateEncoding(sdb, db.RLPEncodingSchema)
+	require.ErrorIs(t, err, ErrSubstateEncodingMismatch)
+	assert.ErrorContains(t, err, "--substate-encoding is rlp but aida-db contains protobuf encoded substates")
+	assert.ErrorContains(t, err, fmt.Sprintf("%#x", db.SubstateDBKey(ss.Block, ss.Transaction)))
+}
+
+func TestApplySubstateEncoding_EmptyDbKeepsEncoding(t *testing.T) {
+	sdb, err := db.NewDefaultSubstateDB(filepath.Join(t.TempDir(), "aida-db"))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, sdb.Close())
+	}()
+	current := sdb.GetSubstateEncoding()
+
+	encoding, err := ApplySubstateEncoding(sdb, "")
+	require.NoError(t, err)
+	assert.Equal(t, current, encoding)
+
+	encoding, err = ApplySubstateEncoding(sdb, db.RLPEncodingSchema)
+	require.NoError(t, err)
+	assert.Equal(t, db.RLPEncodingSchema, encoding)
+}
+
+func TestApplySubstateEncoding_RejectsUnknownEncoding(t *testing.T) {
+	sdb, err := db.NewDefaultSubstateDB(filepath.Join(t.TempDir(), "aida-db"))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, sdb.Close())
+	}()
+
+	_, err = ApplySubstateEncoding(sdb, "invalid")
+	require.ErrorContains(t, err, "encoding not supported: invalid")
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+// Package tracefile reads and writes trace files of StateDb operations. Trace files
+// whose path ends in .zst are transparently stream-compressed using zstd.
+package tracefile
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Extension is the file extension which enables compression of written trace files.
+const Extension = ".zst"
+
+// zstdMagic are the leading bytes of every zstd frame.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// IsCompressed returns true if a trace file written to the given path is compressed.
+func IsCompressed(path string) bool {
+	return strings.HasSuffix(path, Extension)
+}
+
+// Create creates or truncates the trace file at the given path. If the path ends
+// in .zst, all data written is compressed on the fly. Closing the returned writer
+// flushes pending data and closes the file.
+func Create(path string) (io.WriteCloser, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if !IsCompressed(path) {
+		return file, nil
+	}
+	encoder, err := zstd.NewWriter(file)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("cannot create zstd encoder; %w", err), file.Close())
+	}
+	return &compressedWriter{Encoder: encoder, file: file}, nil
+}
+
+// Open opens the trace file at the given path for sequential reading. Compressed
+// files are detected by the .zst extension or by their leading magic bytes and
+// are decompressed on the fly. Reading a truncated compressed file fails with
+// an error instead of silently ending the stream.
+func Open(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReader(file)
+	magic, _ := reader.Peek(len(zstdMagic))
+	if !IsCompressed(path) && !bytes.Equal(magic, zstdMagic) {
+		return &plainReader{Reader: reader, file: file}, nil
+	}
+	decoder, err := zstd.NewReader(reader)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("cannot create zstd decoder; %w", err), file.Close())
+	}
+	return &compressedReader{decoder: decoder, file: file, path: path}, nil
+}
+
+type compressedWriter struct {
+	*zstd.Encoder
+	file *os.File
+}
+
+func (w *compressedWriter) Close() error {
+	return errors.Join(w.Encoder.Close(), w.file.Close())
+}
+
+type plainReader struct {
+	*bufio.Reader
+	file *os.File
+}
+
+func (r *plainReader) Close() error {
+	return r.file.Close()
+}
+
+type compressedReader struct {
+	decoder *zstd.Decoder
+	file    *os.File
+	path    string
+}
+
+func (r *compressedReader) Read(p []byte) (int, error) {
+	n, err := r.decoder.Read(p)
+	switch {
+	case err == nil, err == io.EOF:
+		return n, err
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return n, fmt.Errorf("trace file %v is truncated; %w", r.path, err)
+	default:
+		return n, fmt.Errorf("cannot decompress trace file %v; %w", r.path, err)
+	}
+}
+
+func (r *compressedReader) Close() error {
+	r.decoder.Close()
+	return r.file.Close()
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package tracefile
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTrace = "BeginBlock, 1\nBeginTransaction, 0\nGetBalance, 0x0000000000000000000000000000000000000001, 10\nEndTransaction\nEndBlock\n"
+
+func writeTrace(t *testing.T, path string, content string) {
+	t.Helper()
+	w, err := Create(path)
+	require.NoError(t, err)
+	_, err = io.WriteString(w, content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+}
+
+func readTrace(path string) (string, error) {
+	r, err := Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	return string(data), err
+}
+
+func TestTraceFile_RoundTrip(t *testing.T) {
+	for _, name := range []string{"trace.log", "trace.log.zst"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			writeTrace(t, path, testTrace)
+
+			raw, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, IsCompressed(path), string(raw) != testTrace)
+
+			content, err := readTrace(path)
+			require.NoError(t, err)
+			assert.Equal(t, testTrace, content)
+		})
+	}
+}
+
+func TestTraceFile_CompressedFileIsDetectedByMagicBytes(t *testing.T) {
+	dir := t.TempDir()
+	compressed := filepath.Join(dir, "trace.zst")
+	writeTrace(t, compressed, testTrace)
+
+	renamed := filepath.Join(dir, "trace.log")
+	require.NoError(t, os.Rename(compressed, renamed))
+
+	content, err := readTrace(renamed)
+	require.NoError(t, err)
+	assert.Equal(t, testTrace, content)
+}
+
+func TestTraceFile_TruncatedFileIsReported(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.zst")
+	writeTrace(t, path, testTrace)
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, raw[:len(raw)-3], 0644))
+
+	_, err = readTrace(path)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.ErrorContains(t, err, "is truncated")
+}
+
+func TestTraceFile_UncompressedFileWithZstExtensionIsReported(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.zst")
+	require.NoError(t, os.WriteFile(path, []byte(testTrace), 0644))
+
+	_, err := readTrace(path)
+	require.ErrorContains(t, err, "cannot decompress trace file")
+}
+
+func TestTraceFile_OpenMissingFileFails(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "missing.zst"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+// VmImplementationKind distinguishes values of --vm-impl from values of --evm-impl.
+type VmImplementationKind int
+
+const (
+	InterpreterImplementation VmImplementationKind = iota // selected by --vm-impl
+	ProcessorImplementation                               // selected by --evm-impl
+)
+
+func (k VmImplementationKind) String() string {
+	switch k {
+	case InterpreterImplementation:
+		return "vm-impl"
+	case ProcessorImplementation:
+		return "evm-impl"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(k))
+	}
+}
+
+// VmImplementationInfo describes an EVM implementation or an interpreter which
+// can be selected on the command line.
+type VmImplementationInfo struct {
+	Kind         VmImplementationKind
+	Name         string
+	Description  string
+	FirstFork    string // first supported fork, empty if unbounded
+	LastFork     string // last supported fork, empty if unbounded
+	Experimental bool   // true if the implementation is not meant for production runs
+	Tosca        bool   // true if the implementation is looked up in Tosca's registry
+}
+
+// Forks returns the supported fork range in human-readable form.
+func (i VmImplementationInfo) Forks() string {
+	if i.FirstFork == "" && i.LastFork == "" {
+		return "all"
+	}
+	first, last := i.FirstFork, i.LastFork
+	if first == "" {
+		first = "..."
+	}
+	if last == "" {
+		last = "..."
+	}
+	return first + "-" + last
+}
+
+var vmRegistry = struct {
+	mutex sync.Mutex
+	infos map[VmImplementationKind]map[string]VmImplementationInfo
+}{infos: make(map[VmImplementationKind]map[string]VmImplementationInfo)}
+
+// RegisterVmImplementation adds the description of an implementation to the registry
+// listed by --list-vms. It is meant to be called at init time by each integration.
+// Names are case-insensitive and must be unique per kind.
+func RegisterVmImplementation(info VmImplementationInfo) error {
+	if info.Name == "" {
+		return errors.New("implementation must have a name")
+	}
+	info.Name = strings.ToLower(info.Name)
+	vmRegistry.mutex.Lock()
+	defer vmRegistry.mutex.Unlock()
+	infos, ok := vmRegistry.infos[info.Kind]
+	if !ok {
+		infos = make(map[string]VmImplementationInfo)
+		vmRegistry.infos[info.Kind] = infos
+	}
+	if _, found := infos[info.Name]; found {
+		return fmt.Errorf("%v implementation %q is already registered", info.Kind, info.Name)
+	}
+	infos[info.Name] = info
+	return nil
+}
+
+// GetVmImplementations returns all implementations of given kind available in this
+// build, sorted by name. Implementations registered in Tosca without a description
+// are included as well, registered Tosca-backed implementations missing in Tosca's
+// registry are omitted.
+func GetVmImplementations(kind VmImplementationKind) []VmImplementationInfo {
+	available := make(map[string]struct{})
+	switch kind {
+	case InterpreterImplementation:
+		for name := range tosca.GetAllRegisteredInterpreters() {
+			available[name] = struct{}{}
+		}
+	case ProcessorImplementation:
+		for name := range tosca.GetAllRegisteredProcessorFactories() {
+			available[name] = struct{}{}
+		}
+	}
+
+	vmRegistry.mutex.Lock()
+	defer vmRegistry.mutex.Unlock()
+	res := make(map[string]VmImplementationInfo)
+	for name, info := range vmRegistry.infos[kind] {
+		if _, found := available[name]; info.Tosca && !found {
+			continue
+		}
+		res[name] = info
+	}
+	for name := range available {
+		if _, found := res[name]; !found {
+			res[name] = VmImplementationInfo{Kind: kind, Name: name, Description: "registered by Tosca, no description available", Tosca: true}
+		}
+	}
+	return slices.SortedFunc(maps.Values(res), func(a, b VmImplementationInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// SuggestVmImplementations returns the names of available implementations of given
+// kind which are close to the given, unknown, name.
+func SuggestVmImplementations(kind VmImplementationKind, name string) []string {
+	name = strings.ToLower(name)
+	var res []string
+	for _, info := range GetVmImplementations(kind) {
+		if strings.HasPrefix(info.Name, name) || strings.HasPrefix(name, info.Name) ||
+			levenshtein(name, info.Name) <= max(2, len(name)/3) {
+			res = append(res, info.Name)
+		}
+	}
+	return res
+}
+
+// FormatVmImplementationSuggestions returns a hint listing the close matches of an
+// unknown implementation name, or an empty string if there are none.
+func FormatVmImplementationSuggestions(kind VmImplementationKind, name string) string {
+	suggestions := SuggestVmImplementations(kind, name)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("; did you mean %v?", strings.Join(suggestions, ", "))
+}
+
+// PrintVmImplementations writes a table of all implementations available in this build.
+func PrintVmImplementations(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, kind := range []VmImplementationKind{ProcessorImplementation, InterpreterImplementation} {
+		if _, err := fmt.Fprintf(w, "--%v\tFORKS\tEXPERIMENTAL\tDESCRIPTION\n", kind); err != nil {
+			return err
+		}
+		for _, info := range GetVmImplementations(kind) {
+			if _, err := fmt.Fprintf(w, "  %v\t%v\t%v\t%v\n", info.Name, info.Forks(), info.Experimental, info.Description); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, "\t\t\t"); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// levenshtein returns the edit distance of given strings.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Sonic Labs
+// This file is part of Aida Testing Infrastructure for Sonic
+//
+// Aida is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Aida is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Aida. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVmRegistry_RegisterRejectsDuplicatesAndEmptyNames(t *testing.T) {
+	info := VmImplementationInfo{Kind: ProcessorImplementation, Name: "Test-Registry-Duplicate"}
+	require.NoError(t, RegisterVmImplementation(info))
+	require.ErrorContains(t, RegisterVmImplementation(VmImplementationInfo{Kind: ProcessorImplementation, Name: "test-registry-duplicate"}), "already registered")
+	require.ErrorContains(t, RegisterVmImplementation(VmImplementationInfo{Kind: ProcessorImplementation}), "must have a name")
+
+	// the same name may be used by an implementation of a different kind
+	require.NoError(t, RegisterVmImplementation(VmImplementationInfo{Kind: InterpreterImplementation, Name: "test-registry-duplicate"}))
+}
+
+func TestVmRegistry_GetVmImplementationsListsBuildContent(t *testing.T) {
+	require.NoError(t, tosca.RegisterInterpreterFactory("test-registry-undescribed", func(any) (tosca.Interpreter, error) {
+		return nil, nil
+	}))
+	require.NoError(t, RegisterVmImplementation(VmImplementationInfo{Kind: InterpreterImplementation, Name: "test-registry-missing", Tosca: true}))
+
+	byName := make(map[string]VmImplementationInfo)
+	for _, info := range GetVmImplementations(InterpreterImplementation) {
+		byName[info.Name] = info
+	}
+
+	assert.Contains(t, byName, "geth")
+	assert.False(t, byName["geth"].Tosca)
+	assert.Contains(t, byName, "lfvm")
+	assert.Equal(t, "Istanbul-Osaka", byName["lfvm"].Forks())
+	assert.False(t, byName["lfvm"].Experimental)
+	assert.Contains(t, byName, "test-registry-undescribed")
+	assert.NotContains(t, byName, "test-registry-missing")
+	for name := range tosca.GetAllRegisteredInterpreters() {
+		assert.Contains(t, byName, name)
+	}
+}
+
+func TestVmRegistry_SuggestVmImplementationsFindsCloseMatches(t *testing.T) {
+	assert.Contains(t, SuggestVmImplementations(InterpreterImplementation, "lfmv"), "lfvm")
+	assert.Contains(t, SuggestVmImplementations(InterpreterImplementation, "LFVM-"), "lfvm")
+	assert.Contains(t, SuggestVmImplementations(InterpreterImplementation, "gethh"), "geth")
+	assert.Empty(t, SuggestVmImplementations(InterpreterImplementation, "completely-different"))
+
+	assert.Equal(t, "", FormatVmImplementationSuggestions(InterpreterImplementation, "completely-different"))
+	assert.Contains(t, FormatVmImplementationSuggestions(InterpreterImplementation, "gteh"), "did you mean geth")
+}
+
+func TestVmRegistry_PrintVmImplementations(t *testing.T) {
+	require.NoError(t, RegisterVmImplementation(VmImplementationInfo{
+		Kind:         ProcessorImplementation,
+		Name:         "test-registry-print",
+		Description:  "printed description",
+		FirstFork:    "Cancun",
+		Experimental: true,
+	}))
+
+	var out bytes.Buffer
+	require.NoError(t, PrintVmImplementations(&out))
+	assert.Contains(t, out.String(), "--evm-impl")
+	assert.Contains(t, out.String(), "--vm-impl")
+	assert.Regexp(t, `test-registry-print\s+Cancun-\.\.\.\s+true\s+printed description`, out.String())
+	assert.Regexp(t, `geth\s+all\s+false`, out.String())
+}
+
+func TestVmRegistry_Levenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"lfvm", "lfvm", 0},
+		{"lfvm", "", 4},
+		{"lfvm", "lfmv", 2},
+		{"geth", "gethh", 1},
+		{"evmone", "evmzero", 4},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, levenshtein(test.a, test.b), "%q vs %q", test.a, test.b)
+	}
+}